@@ -4,6 +4,7 @@ go 1.24.7
 
 require (
 	github.com/google/go-github/v65 v65.0.0
+	github.com/klauspost/compress v1.18.0
 	github.com/tucnak/climax v0.0.0-20200905070204-9f87fd172d1c
 	golang.org/x/oauth2 v0.31.0
 	gopkg.in/yaml.v3 v3.0.1
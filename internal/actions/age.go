@@ -0,0 +1,126 @@
+package actions
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/cache"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// releaseDateTTL is how long release dates are cached; published versions rarely change date
+const releaseDateTTL = 24 * time.Hour
+
+// ReleaseDateClient looks up when a version of an action was published
+type ReleaseDateClient interface {
+	GetReleaseDate(owner, repo, ref string) (time.Time, error)
+}
+
+// AgeAnnotator enriches outdated issues with release dates so they can be prioritized by staleness
+type AgeAnnotator struct {
+	client  ReleaseDateClient
+	cache   cache.Cache
+	verbose bool
+	now     func() time.Time
+
+	// Lookups that failed are remembered for the run so they aren't retried per issue
+	mutex  sync.Mutex
+	failed map[string]bool
+}
+
+// NewAgeAnnotator creates an annotator backed by the given client and optional shared cache
+func NewAgeAnnotator(client ReleaseDateClient, sharedCache cache.Cache, config *Config) *AgeAnnotator {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	return &AgeAnnotator{
+		client:  client,
+		cache:   sharedCache,
+		verbose: config.Verbose,
+		now:     time.Now,
+		failed:  make(map[string]bool),
+	}
+}
+
+// Annotate adds release dates, pin age, and days behind to outdated issues in place
+func (a *AgeAnnotator) Annotate(issues []output.ActionIssue) {
+	for i := range issues {
+		issue := &issues[i]
+		if issue.IssueType != "outdated" || issue.SuggestedVersion == "" {
+			continue
+		}
+
+		current, ok := a.releaseDate(issue.Repository, issue.CurrentVersion)
+		if !ok {
+			continue
+		}
+		issue.CurrentVersionDate = &current
+		issue.PinAgeDays = daysBetween(current, a.now())
+
+		suggested, ok := a.releaseDate(issue.Repository, issue.SuggestedVersion)
+		if !ok {
+			continue
+		}
+		issue.SuggestedVersionDate = &suggested
+		issue.DaysBehind = daysBetween(current, suggested)
+	}
+}
+
+// releaseDate returns the release date for an action version, using the cache when available
+func (a *AgeAnnotator) releaseDate(repository, version string) (time.Time, bool) {
+	parts := strings.Split(repository, "/")
+	if len(parts) < 2 || version == "" {
+		return time.Time{}, false
+	}
+	owner, repo := parts[0], parts[1]
+	key := owner + "/" + repo + "@" + version
+
+	// Concurrent lookups of the same version wait for the first and reuse its result
+	if a.cache != nil {
+		unlock := a.cache.Lock(cache.RefDateKey(owner, repo, version))
+		defer unlock()
+	}
+
+	a.mutex.Lock()
+	failed := a.failed[key]
+	a.mutex.Unlock()
+	if failed {
+		return time.Time{}, false
+	}
+
+	if a.cache != nil {
+		if date, found, err := a.cache.GetRefDate(owner, repo, version); err == nil && found {
+			return date, true
+		}
+	}
+
+	date, err := a.client.GetReleaseDate(owner, repo, version)
+	if err != nil {
+		if a.verbose {
+			log.Printf("Unable to determine release date for %s: %v", key, err)
+		}
+		a.mutex.Lock()
+		a.failed[key] = true
+		a.mutex.Unlock()
+		return time.Time{}, false
+	}
+
+	if a.cache != nil {
+		if err := a.cache.SetRefDate(owner, repo, version, date, releaseDateTTL); err != nil && a.verbose {
+			log.Printf("Failed to cache release date for %s: %v", key, err)
+		}
+	}
+
+	return date, true
+}
+
+// daysBetween returns the whole number of days from start to end, or 0 if end is not after start
+func daysBetween(start, end time.Time) int {
+	if !end.After(start) {
+		return 0
+	}
+	return int(end.Sub(start).Hours() / 24)
+}
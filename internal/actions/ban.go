@@ -0,0 +1,86 @@
+package actions
+
+import (
+	"fmt"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// How a banned action is remediated
+const (
+	BanRemove  = "remove"  // Delete the step using the action
+	BanReplace = "replace" // Swap the step to another action, mapping its inputs
+)
+
+// IssueTypeBanned is raised for every use of a banned action
+const IssueTypeBanned = "banned-action"
+
+// Ban bans an action at every version, with an optional remediation applied by create-pr
+type Ban struct {
+	Remediation    string            `json:"remediation,omitempty"`     // BanRemove, BanReplace, or empty to only report
+	ReplaceWith    string            `json:"replace_with,omitempty"`    // Replacement action, e.g. "my-org/checkout" or "org/repo/path"
+	ReplaceVersion string            `json:"replace_version,omitempty"` // Version of the replacement
+	WithMapping    map[string]string `json:"with_mapping,omitempty"`    // Input renames for the replacement; an empty name drops the input
+	With           map[string]string `json:"with,omitempty"`            // Inputs set on the replacement
+}
+
+// Validate checks that the remediation is known and a replacement names its action and version
+func (b *Ban) Validate() error {
+	if b == nil {
+		return nil
+	}
+	switch b.Remediation {
+	case "", BanRemove:
+		if b.ReplaceWith != "" || b.ReplaceVersion != "" || len(b.WithMapping) > 0 || len(b.With) > 0 {
+			return fmt.Errorf("ban replacement fields require remediation %q", BanReplace)
+		}
+	case BanReplace:
+		if b.ReplaceWith == "" || b.ReplaceVersion == "" {
+			return fmt.Errorf("ban remediation %q requires replace_with and replace_version", BanReplace)
+		}
+	default:
+		return fmt.Errorf("invalid ban remediation %q: must be %q or %q", b.Remediation, BanRemove, BanReplace)
+	}
+	return nil
+}
+
+// bannedIssue reports a use of a banned action with the rule's remediation
+// Removing a reusable workflow call would remove a whole job that others may need, so it is only reported.
+func (m *Manager) bannedIssue(action workflow.ActionReference, rule *Rule) output.ActionIssue {
+	description := fmt.Sprintf("Action %s is banned", action.Repository)
+	if rule.Recommendation != "" {
+		description = rule.Recommendation
+	}
+
+	issue := output.ActionIssue{
+		Repository:     action.Repository,
+		WorkflowPath:   action.WorkflowPath,
+		CurrentVersion: action.Version,
+		IssueType:      IssueTypeBanned,
+		Severity:       "high",
+		Description:    description,
+		Context:        action.Context,
+		FilePath:       action.FilePath,
+		PinComment:     action.PinComment,
+	}
+
+	ban := rule.Ban
+	switch {
+	case ban.Remediation == BanReplace:
+		issue.SuggestedVersion = ban.ReplaceVersion
+		issue.Remediation = &output.BanRemediation{
+			Action:      BanReplace,
+			Replacement: ban.ReplaceWith + "@" + ban.ReplaceVersion,
+			WithMapping: ban.WithMapping,
+			With:        ban.With,
+		}
+	case ban.Remediation == BanRemove && !action.IsReusable:
+		issue.Remediation = &output.BanRemediation{Action: BanRemove}
+	}
+
+	if m.verbose {
+		m.logf("Rule evaluation: Action %s@%s is banned (remediation: %q)", action.Repository, action.Version, ban.Remediation)
+	}
+	return issue
+}
@@ -0,0 +1,73 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+func TestAnalyzeActions_BannedAction(t *testing.T) {
+	manager := NewManagerWithResolverConfigAndRules(nil, nil, []Rule{
+		{
+			Repository:     "acme/upload",
+			Ban:            &Ban{Remediation: BanReplace, ReplaceWith: "my-org/upload", ReplaceVersion: "v2", WithMapping: map[string]string{"path": "files"}},
+			Recommendation: "acme/upload sends artifacts to a third party",
+			Owners:         []string{"my-org/security"},
+		},
+		{Repository: "sketchy/*", Ban: &Ban{Remediation: BanRemove}},
+		{Repository: "old/notify", Ban: &Ban{}},
+	})
+
+	issues := manager.AnalyzeActions([]workflow.ActionReference{
+		{Repository: "acme/upload", Version: "v1", FilePath: "ci.yml", Context: "job:build"},
+		{Repository: "sketchy/cache", Version: "main", FilePath: "ci.yml"},
+		{Repository: "sketchy/workflows", WorkflowPath: ".github/workflows/build.yml", Version: "v1", IsReusable: true, FilePath: "ci.yml"},
+		{Repository: "old/notify", Version: "v3", FilePath: "ci.yml"},
+	})
+	if len(issues) != 4 {
+		t.Fatalf("Expected 4 issues, got %d: %+v", len(issues), issues)
+	}
+	for _, issue := range issues {
+		if issue.IssueType != IssueTypeBanned || issue.Severity != "high" {
+			t.Errorf("Expected a high severity banned-action issue, got %+v", issue)
+		}
+	}
+
+	replaced := issues[0]
+	if replaced.Remediation == nil || replaced.Remediation.Action != BanReplace || replaced.Remediation.Replacement != "my-org/upload@v2" {
+		t.Errorf("Expected a replacement with my-org/upload@v2, got %+v", replaced.Remediation)
+	}
+	if replaced.SuggestedVersion != "v2" || replaced.Description != "acme/upload sends artifacts to a third party" || len(replaced.Owners) != 1 {
+		t.Errorf("Expected suggested version, recommendation, and owners, got %+v", replaced)
+	}
+	if removed := issues[1]; removed.Remediation == nil || removed.Remediation.Action != BanRemove || removed.SuggestedVersion != "" {
+		t.Errorf("Expected a removal without a suggested version, got %+v", removed)
+	}
+
+	// Reusable workflow jobs are never removed, and bans without a remediation only report
+	if issues[2].Remediation != nil || issues[3].Remediation != nil {
+		t.Errorf("Expected no remediation, got %+v and %+v", issues[2].Remediation, issues[3].Remediation)
+	}
+}
+
+func TestBanValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		ban         *Ban
+		expectError bool
+	}{
+		{"nil", nil, false},
+		{"report only", &Ban{}, false},
+		{"remove", &Ban{Remediation: BanRemove}, false},
+		{"replace", &Ban{Remediation: BanReplace, ReplaceWith: "my-org/upload", ReplaceVersion: "v2"}, false},
+		{"replace without version", &Ban{Remediation: BanReplace, ReplaceWith: "my-org/upload"}, true},
+		{"remove with replacement", &Ban{Remediation: BanRemove, ReplaceWith: "my-org/upload"}, true},
+		{"unknown remediation", &Ban{Remediation: "disable"}, true},
+	}
+
+	for _, tt := range tests {
+		if err := tt.ban.Validate(); (err != nil) != tt.expectError {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.expectError, err)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package actions
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// generateActionReferences builds references spread across a set of repositories and versions
+func generateActionReferences(count int) []workflow.ActionReference {
+	repositories := []string{"actions/checkout", "actions/setup-node", "actions/cache", "actions/upload-artifact", "my-org/custom"}
+	versions := []string{"v2", "v3", "v4"}
+
+	refs := make([]workflow.ActionReference, count)
+	for i := range refs {
+		refs[i] = workflow.ActionReference{
+			Repository: repositories[i%len(repositories)],
+			Version:    versions[i%len(versions)],
+			Context:    fmt.Sprintf("job:build/step:step-%d", i),
+			FilePath:   fmt.Sprintf(".github/workflows/wf-%d.yml", i%20),
+		}
+	}
+	return refs
+}
+
+func benchmarkAnalyzeActions(b *testing.B, count int) {
+	rules := []Rule{
+		{Repository: "actions/checkout", LatestVersion: "v4", DeprecatedVersions: []string{"v2"}},
+		{Repository: "actions/setup-node", LatestVersion: "v4", DeprecatedVersions: []string{"v2"}},
+		{Repository: "actions/cache", LatestVersion: "v4"},
+		{Repository: "actions/upload-artifact", LatestVersion: "v4", DeprecatedVersions: []string{"v2", "v3"}},
+	}
+	manager := NewManagerWithResolverConfigAndRules(NewMockVersionResolver(), &Config{}, rules)
+	refs := generateActionReferences(count)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		manager.AnalyzeActions(refs)
+	}
+}
+
+func BenchmarkAnalyzeActions_100(b *testing.B)   { benchmarkAnalyzeActions(b, 100) }
+func BenchmarkAnalyzeActions_10000(b *testing.B) { benchmarkAnalyzeActions(b, 10000) }
+
+// benchmarkAnalyzeRepositories analyzes a scan-sized set of repositories with the given worker count
+func benchmarkAnalyzeRepositories(b *testing.B, workers int) {
+	rules := []Rule{
+		{Repository: "actions/checkout", LatestVersion: "v4", DeprecatedVersions: []string{"v2"}},
+		{Repository: "actions/setup-node", LatestVersion: "v4", DeprecatedVersions: []string{"v2"}},
+		{Repository: "actions/cache", LatestVersion: "v4"},
+		{Repository: "actions/upload-artifact", LatestVersion: "v4", DeprecatedVersions: []string{"v2", "v3"}},
+		{Repository: "my-org/*", LatestVersion: "v2", Conditions: &RuleConditions{RepositoryPattern: "^svc-"}},
+	}
+	manager := NewManagerWithResolverConfigAndRules(NewMockVersionResolver(), &Config{Workers: workers}, rules)
+
+	repos := make([]output.RepositoryResult, 200)
+	for i := range repos {
+		repos[i] = output.RepositoryResult{
+			Name:     fmt.Sprintf("svc-%d", i),
+			FullName: fmt.Sprintf("my-org/svc-%d", i),
+			Actions:  generateActionReferences(100),
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		manager.AnalyzeRepositories(repos)
+	}
+}
+
+func BenchmarkAnalyzeRepositories_Serial(b *testing.B)   { benchmarkAnalyzeRepositories(b, 1) }
+func BenchmarkAnalyzeRepositories_Parallel(b *testing.B) { benchmarkAnalyzeRepositories(b, 0) }
@@ -0,0 +1,98 @@
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// brokenReferenceCheck reports actions and reusable workflows whose repository or ref does not exist
+// It applies to every reference, with or without a rule, and needs a resolver that resolves refs.
+type brokenReferenceCheck struct{}
+
+func (brokenReferenceCheck) Name() string { return CheckBrokenReference }
+
+func (brokenReferenceCheck) CheckAction(m *Manager, action workflow.ActionReference, rule *Rule) []output.ActionIssue {
+	if issue := m.checkBrokenReference(action); issue != nil {
+		return []output.ActionIssue{*issue}
+	}
+	return nil
+}
+
+// CheckRepository reports local reusable workflow calls ("./.github/workflows/build.yml") to workflow files
+// the repository does not have. They are resolved against the repository's scanned workflow files rather
+// than through GitHub, so calls into directories the scan did not read, or to files its workflow filter
+// skipped, are not checked.
+func (brokenReferenceCheck) CheckRepository(m *Manager, repo output.RepositoryResult, outlines []workflow.WorkflowOutline) []output.ActionIssue {
+	files := make(map[string]bool, len(repo.WorkflowFiles))
+	dirs := make(map[string]bool)
+	for _, file := range repo.WorkflowFiles {
+		files[file.Path] = true
+		dirs[path.Dir(file.Path)] = true
+	}
+
+	var issues []output.ActionIssue
+	for _, action := range repo.Actions {
+		if !action.Local || files[action.WorkflowPath] || !dirs[path.Dir(action.WorkflowPath)] || !m.workflowFilter.Allows(action.WorkflowPath) {
+			continue
+		}
+		if m.verbose {
+			m.logf("Rule evaluation: Local reusable workflow %s called from %s does not exist", action.WorkflowPath, action.FilePath)
+		}
+		issues = append(issues, output.ActionIssue{
+			Repository:   repo.FullName,
+			WorkflowPath: action.WorkflowPath,
+			IssueType:    CheckBrokenReference,
+			Severity:     "critical",
+			Description:  fmt.Sprintf("Local reusable workflow ./%s does not exist in %s; the workflow will fail at runtime", action.WorkflowPath, repo.FullName),
+			Context:      action.Context,
+			FilePath:     action.FilePath,
+		})
+	}
+	return issues
+}
+
+// checkBrokenReference resolves an action's ref and reports it when GitHub has no such repository or ref
+// Other resolution failures, such as rate limits, are not reported: the reference may well work.
+func (m *Manager) checkBrokenReference(action workflow.ActionReference) *output.ActionIssue {
+	if m.resolver == nil || m.skipResolution || action.Version == "" || strings.Contains(action.Version, "${{") {
+		return nil
+	}
+	parts := strings.Split(action.Repository, "/")
+	if len(parts) != 2 {
+		return nil
+	}
+
+	_, err := m.resolver.ResolveRefWithCache(parts[0], parts[1], action.Version)
+	if err == nil || !errors.Is(err, github.ErrRefNotFound) {
+		if err != nil && m.verbose {
+			m.logf("Rule evaluation: Unable to resolve %s@%s: %v", action.Repository, action.Version, err)
+		}
+		return nil
+	}
+
+	target := action.Repository
+	if action.WorkflowPath != "" {
+		target += "/" + action.WorkflowPath
+	}
+	if m.verbose {
+		m.logf("Rule evaluation: %s@%s does not exist", target, action.Version)
+	}
+
+	return &output.ActionIssue{
+		Repository:     action.Repository,
+		WorkflowPath:   action.WorkflowPath,
+		CurrentVersion: action.Version,
+		IssueType:      CheckBrokenReference,
+		Severity:       "critical",
+		Description:    fmt.Sprintf("%s@%s does not exist or is not visible to the token; the workflow will fail at runtime", target, action.Version),
+		Context:        action.Context,
+		FilePath:       action.FilePath,
+		PinComment:     action.PinComment,
+	}
+}
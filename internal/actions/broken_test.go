@@ -0,0 +1,105 @@
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// missingRefResolver fails to resolve the refs it lists, as not found or with another error
+type missingRefResolver struct {
+	*MockVersionResolver
+	errors map[string]error // maps "owner/repo@ref" to the resolution error
+}
+
+func (r missingRefResolver) ResolveRefWithCache(owner, repo, ref string) (string, error) {
+	if err, ok := r.errors[owner+"/"+repo+"@"+ref]; ok {
+		return "", err
+	}
+	return r.MockVersionResolver.ResolveRefWithCache(owner, repo, ref)
+}
+
+func TestBrokenReferenceCheck(t *testing.T) {
+	resolver := missingRefResolver{
+		MockVersionResolver: NewMockVersionResolver(),
+		errors: map[string]error{
+			"my-org/deploy@v9":       fmt.Errorf("could not resolve reference v9 in my-org/deploy: %w", github.ErrRefNotFound),
+			"my-org/workflows@v1":    fmt.Errorf("could not resolve reference v1 in my-org/workflows: %w", github.ErrRefNotFound),
+			"actions/checkout@v4":    errors.New("rate limit exceeded"),
+			"actions/cache@${{ x }}": fmt.Errorf("could not resolve reference: %w", github.ErrRefNotFound),
+		},
+	}
+
+	manager := NewManagerWithResolverConfigAndRules(resolver, &Config{Checks: []string{CheckBrokenReference}}, nil)
+	issues := manager.AnalyzeActions([]workflow.ActionReference{
+		{Repository: "my-org/deploy", Version: "v9", FilePath: ".github/workflows/deploy.yml"},
+		{Repository: "my-org/workflows", WorkflowPath: ".github/workflows/build.yml", Version: "v1", IsReusable: true, FilePath: ".github/workflows/ci.yml"},
+		{Repository: "actions/checkout", Version: "v4", FilePath: ".github/workflows/ci.yml"},
+		{Repository: "actions/setup-go", Version: "v5", FilePath: ".github/workflows/ci.yml"},
+		{Repository: "actions/cache", Version: "${{ x }}", FilePath: ".github/workflows/ci.yml"},
+	})
+
+	if len(issues) != 2 {
+		t.Fatalf("Expected broken-reference issues for the two missing refs only, got %+v", issues)
+	}
+	issue := issues[0]
+	if issue.IssueType != CheckBrokenReference || issue.Severity != "critical" {
+		t.Errorf("Expected a critical broken-reference issue, got %s %s", issue.Severity, issue.IssueType)
+	}
+	if !strings.Contains(issue.Description, "my-org/deploy@v9 does not exist") || !strings.Contains(issue.Description, "fail at runtime") {
+		t.Errorf("Expected the description to name the reference and the runtime failure, got %q", issue.Description)
+	}
+	if issues[1].WorkflowPath != ".github/workflows/build.yml" || !strings.Contains(issues[1].Description, "my-org/workflows/.github/workflows/build.yml@v1") {
+		t.Errorf("Expected the reusable workflow path in the issue, got %+v", issues[1])
+	}
+
+	// Without resolution, references are never resolved
+	skipping := NewManagerWithResolverConfigAndRules(resolver, &Config{Checks: []string{CheckBrokenReference}, SkipResolution: true}, nil)
+	if issues := skipping.AnalyzeActions([]workflow.ActionReference{{Repository: "my-org/deploy", Version: "v9"}}); len(issues) != 0 {
+		t.Errorf("Expected no issues with resolution skipped, got %+v", issues)
+	}
+}
+
+func TestBrokenReferenceCheck_LocalReusableWorkflows(t *testing.T) {
+	local := func(path string) workflow.ActionReference {
+		return workflow.ActionReference{Repository: "my-org/api", WorkflowPath: path, IsReusable: true, Local: true, FilePath: ".github/workflows/ci.yml", Context: "job:build"}
+	}
+	repo := output.RepositoryResult{
+		Name:     "api",
+		FullName: "my-org/api",
+		WorkflowFiles: []output.WorkflowFileResult{
+			{Path: ".github/workflows/ci.yml"},
+			{Path: ".github/workflows/build.yml"},
+		},
+		Actions: []workflow.ActionReference{
+			local(".github/workflows/build.yml"),
+			local(".github/workflows/release.yml"),
+			local(".github/workflows/experimental.yml"), // Skipped by the workflow filter
+			local("ci/workflows/lint.yml"),              // Not a scanned directory
+		},
+	}
+
+	// A resolver that fails every lookup shows local calls never reach GitHub
+	resolver := missingRefResolver{MockVersionResolver: NewMockVersionResolver(), errors: map[string]error{"my-org/api@": github.ErrRefNotFound}}
+	filter := &github.WorkflowFilter{Exclude: []string{"experimental.yml"}}
+	manager := NewManagerWithResolverConfigAndRules(resolver, &Config{Checks: []string{CheckBrokenReference, CheckOutdated}, WorkflowFilter: filter}, []Rule{
+		{Repository: "my-org/api", LatestVersion: "v2"},
+	})
+
+	if issues := manager.AnalyzeRepository(repo); len(issues) != 0 {
+		t.Errorf("Expected local calls to be left out of version checks and resolution, got %+v", issues)
+	}
+
+	issues := manager.CheckRequiredActions(repo, nil)
+	if len(issues) != 1 || issues[0].WorkflowPath != ".github/workflows/release.yml" || issues[0].IssueType != CheckBrokenReference {
+		t.Fatalf("Expected the missing release workflow to be a broken reference, got %+v", issues)
+	}
+	if !strings.Contains(issues[0].Description, "./.github/workflows/release.yml does not exist in my-org/api") {
+		t.Errorf("Unexpected description %q", issues[0].Description)
+	}
+}
@@ -0,0 +1,216 @@
+package actions
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// brownoutUrgentDays is how close a removal must be for its issues to be critical
+const brownoutUrgentDays = 30
+
+// Brownout turns a rule into a calendar entry: the versions of its action that stop working on a date
+// A rule with a brownout replaces the built-in entries for its repository; it is not also a version rule.
+type Brownout struct {
+	Versions      []string `json:"versions"`                 // Affected major versions, e.g. ["v1", "v2"]; empty disables the built-in entries
+	Date          string   `json:"date,omitempty"`           // Removal date, YYYY-MM-DD
+	BrownoutDates []string `json:"brownout_dates,omitempty"` // Temporary outages announced ahead of the removal, YYYY-MM-DD
+	Replacement   string   `json:"replacement,omitempty"`    // Version to move to, suggested as the fix
+	Announcement  string   `json:"announcement,omitempty"`   // Changelog post announcing the removal
+}
+
+// Validate checks the dates of a brownout
+func (b *Brownout) Validate() error {
+	if b == nil {
+		return nil
+	}
+	if len(b.Versions) > 0 && b.Date == "" {
+		return fmt.Errorf("brownout date field is required")
+	}
+	for _, date := range append([]string{b.Date}, b.BrownoutDates...) {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse(time.DateOnly, date); err != nil {
+			return fmt.Errorf("invalid brownout date %q: use YYYY-MM-DD", date)
+		}
+	}
+	return nil
+}
+
+// DefaultBrownouts is the built-in calendar of removals GitHub has announced for its own actions
+var DefaultBrownouts = []Rule{
+	{
+		Repository: "actions/upload-artifact",
+		Brownout: &Brownout{
+			Versions:     []string{"v1", "v2"},
+			Date:         "2024-06-30",
+			Replacement:  "v4",
+			Announcement: "https://github.blog/changelog/2024-02-13-deprecation-notice-v1-and-v2-of-the-artifact-actions/",
+		},
+	},
+	{
+		Repository: "actions/upload-artifact",
+		Brownout: &Brownout{
+			Versions:     []string{"v3"},
+			Date:         "2025-01-30",
+			Replacement:  "v4",
+			Announcement: "https://github.blog/changelog/2024-04-16-deprecation-notice-v3-of-the-artifact-actions/",
+		},
+	},
+	{
+		Repository: "actions/download-artifact",
+		Brownout: &Brownout{
+			Versions:     []string{"v1", "v2"},
+			Date:         "2024-06-30",
+			Replacement:  "v4",
+			Announcement: "https://github.blog/changelog/2024-02-13-deprecation-notice-v1-and-v2-of-the-artifact-actions/",
+		},
+	},
+	{
+		Repository: "actions/download-artifact",
+		Brownout: &Brownout{
+			Versions:     []string{"v3"},
+			Date:         "2025-01-30",
+			Replacement:  "v4",
+			Announcement: "https://github.blog/changelog/2024-04-16-deprecation-notice-v3-of-the-artifact-actions/",
+		},
+	},
+	{
+		Repository: "actions/cache",
+		Brownout: &Brownout{
+			Versions:     []string{"v1", "v2"},
+			Date:         "2025-03-01",
+			Replacement:  "v4",
+			Announcement: "https://github.blog/changelog/2024-12-05-notice-of-upcoming-releases-and-breaking-changes-for-github-actions/",
+		},
+	},
+}
+
+// brownoutCalendar holds the calendar entries of each action repository
+type brownoutCalendar map[string][]Brownout
+
+// newBrownoutCalendar builds a calendar from the built-in entries, replacing those of any repository a
+// rule with a brownout names
+func newBrownoutCalendar(defaults, rules []Rule) brownoutCalendar {
+	calendar := make(brownoutCalendar)
+	for _, rule := range defaults {
+		calendar[rule.Repository] = append(calendar[rule.Repository], *rule.Brownout)
+	}
+	overridden := make(map[string]bool)
+	for _, rule := range rules {
+		if rule.Brownout == nil {
+			continue
+		}
+		if !overridden[rule.Repository] {
+			overridden[rule.Repository] = true
+			calendar[rule.Repository] = nil
+		}
+		if len(rule.Brownout.Versions) > 0 {
+			calendar[rule.Repository] = append(calendar[rule.Repository], *rule.Brownout)
+		}
+	}
+	return calendar
+}
+
+// defaultBrownoutCalendar is used by managers created without rules
+var defaultBrownoutCalendar = newBrownoutCalendar(DefaultBrownouts, nil)
+
+// find returns the calendar entry covering a version of an action, or nil
+func (c brownoutCalendar) find(repository, version string) *Brownout {
+	major := "v" + extractMajorVersion(version)
+	for i, brownout := range c[repository] {
+		for _, affected := range brownout.Versions {
+			if strings.EqualFold(affected, major) {
+				return &c[repository][i]
+			}
+		}
+	}
+	return nil
+}
+
+// brownoutCheck reports actions pinned to versions with an announced brownout or removal
+type brownoutCheck struct{}
+
+func (brownoutCheck) Name() string { return CheckBrownout }
+
+func (brownoutCheck) CheckAction(m *Manager, action workflow.ActionReference, rule *Rule) []output.ActionIssue {
+	// SHA pins are matched on the tag in their pin comment
+	version := action.Version
+	if m.detectVersionFormat(version) == VersionFormatSHA {
+		version = workflow.PinCommentVersion(action.PinComment)
+	}
+	if version == "" {
+		return nil
+	}
+	calendar := m.brownouts
+	if calendar == nil {
+		calendar = defaultBrownoutCalendar
+	}
+	brownout := calendar.find(action.Repository, version)
+	if brownout == nil {
+		return nil
+	}
+	deadline, err := time.Parse(time.DateOnly, brownout.Date)
+	if err != nil {
+		return nil
+	}
+
+	today := m.clock().UTC().Truncate(24 * time.Hour)
+	days := int(deadline.Sub(today).Hours() / 24)
+	severity := "high"
+	if days <= brownoutUrgentDays {
+		severity = "critical"
+	}
+
+	var description string
+	if days < 0 {
+		description = fmt.Sprintf("Action %s %s stopped working on %s", action.Repository, version, brownout.Date)
+	} else {
+		description = fmt.Sprintf("Action %s %s stops working on %s (in %d days)", action.Repository, version, brownout.Date, days)
+		var upcoming []string
+		for _, date := range brownout.BrownoutDates {
+			if brownoutDate, err := time.Parse(time.DateOnly, date); err == nil && !brownoutDate.Before(today) {
+				upcoming = append(upcoming, date)
+			}
+		}
+		if len(upcoming) > 0 {
+			description += fmt.Sprintf(", with brownouts on %s", strings.Join(upcoming, ", "))
+		}
+	}
+	if brownout.Replacement != "" {
+		description += fmt.Sprintf("; update to %s", brownout.Replacement)
+	}
+
+	issue := output.ActionIssue{
+		Repository:     action.Repository,
+		CurrentVersion: action.Version,
+		IssueType:      CheckBrownout,
+		Severity:       severity,
+		Description:    description,
+		Context:        action.Context,
+		FilePath:       action.FilePath,
+		PinComment:     action.PinComment,
+		Deadline:       &deadline,
+		Announcement:   brownout.Announcement,
+	}
+
+	// Version rules suggest their own fix; without one, the calendar's replacement is the fix
+	if versionRule(rule) == nil && brownout.Replacement != "" {
+		issue.SuggestedVersion = m.suggestLikeForLikeVersion(action.Repository, action.Version, brownout.Replacement)
+		issue.SuggestedPinComment = m.suggestPinComment(issue.SuggestedVersion, brownout.Replacement)
+		m.addTransformations(&issue, action, brownout.Replacement, action.Repository)
+	}
+
+	if m.verbose {
+		m.logf("Rule evaluation: %s %s is scheduled for removal on %s", action.Repository, version, brownout.Date)
+	}
+	issues := []output.ActionIssue{issue}
+	if rule != nil {
+		annotateRuleIssues(issues, rule)
+	}
+	return issues
+}
@@ -0,0 +1,103 @@
+package actions
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+func brownoutManager(rules []Rule, today time.Time) *Manager {
+	manager := NewManagerWithResolverConfigAndRules(nil, &Config{Checks: []string{CheckBrownout}}, rules)
+	manager.now = func() time.Time { return today }
+	return manager
+}
+
+func TestBrownoutCheck_BuiltIn(t *testing.T) {
+	manager := brownoutManager(nil, time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC))
+	issues := manager.AnalyzeActions([]workflow.ActionReference{
+		{Repository: "actions/upload-artifact", Version: "v3", FilePath: ".github/workflows/ci.yml"},
+		{Repository: "actions/upload-artifact", Version: "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2", PinComment: "v3.1.3", FilePath: ".github/workflows/ci.yml"},
+		{Repository: "actions/upload-artifact", Version: "v4", FilePath: ".github/workflows/ci.yml"},
+		{Repository: "actions/checkout", Version: "v3", FilePath: ".github/workflows/ci.yml"},
+	})
+
+	if len(issues) != 2 {
+		t.Fatalf("Expected brownout issues for both v3 references, got %+v", issues)
+	}
+	issue := issues[0]
+	if issue.IssueType != CheckBrownout || issue.Severity != "critical" {
+		t.Errorf("Expected a critical brownout issue 20 days ahead, got %s %s", issue.Severity, issue.IssueType)
+	}
+	if issue.Deadline == nil || issue.Deadline.Format(time.DateOnly) != "2025-01-30" {
+		t.Errorf("Expected the 2025-01-30 deadline, got %v", issue.Deadline)
+	}
+	if !strings.Contains(issue.Description, "stops working on 2025-01-30 (in 20 days)") {
+		t.Errorf("Expected the days left in the description, got %q", issue.Description)
+	}
+	if issue.SuggestedVersion != "v4" || issue.Announcement == "" {
+		t.Errorf("Expected the v4 replacement and the announcement, got %q, %q", issue.SuggestedVersion, issue.Announcement)
+	}
+}
+
+func TestBrownoutCheck_RuleOverride(t *testing.T) {
+	rules := []Rule{
+		{Repository: "my-org/deploy", Brownout: &Brownout{Versions: []string{"v1"}, Date: "2025-09-30", BrownoutDates: []string{"2025-06-01", "2025-09-09"}, Replacement: "v2"}},
+		{Repository: "my-org/deploy", LatestVersion: "v3"},
+		{Repository: "actions/cache", Brownout: &Brownout{}},
+	}
+	manager := brownoutManager(rules, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC))
+	issues := manager.AnalyzeActions([]workflow.ActionReference{
+		{Repository: "my-org/deploy", Version: "v1.4.0", FilePath: ".github/workflows/deploy.yml"},
+		{Repository: "actions/cache", Version: "v2", FilePath: ".github/workflows/ci.yml"},
+	})
+
+	// The cache rule turns the built-in entry off
+	if len(issues) != 1 {
+		t.Fatalf("Expected one brownout issue for my-org/deploy, got %+v", issues)
+	}
+	issue := issues[0]
+	if issue.Severity != "high" {
+		t.Errorf("Expected high severity 91 days ahead, got %s", issue.Severity)
+	}
+	if !strings.Contains(issue.Description, "with brownouts on 2025-09-09") || strings.Contains(issue.Description, "2025-06-01") {
+		t.Errorf("Expected only the upcoming brownout in the description, got %q", issue.Description)
+	}
+	// The version rule suggests its own fix
+	if issue.SuggestedVersion != "" {
+		t.Errorf("Expected no suggestion when a version rule covers the action, got %q", issue.SuggestedVersion)
+	}
+}
+
+func TestBrownoutValidate(t *testing.T) {
+	if err := (&Brownout{Versions: []string{"v1"}}).Validate(); err == nil {
+		t.Error("Expected an error for a brownout without a date")
+	}
+	if err := (&Brownout{Versions: []string{"v1"}, Date: "30/09/2025"}).Validate(); err == nil {
+		t.Error("Expected an error for a date not in YYYY-MM-DD")
+	}
+	if err := (&Brownout{}).Validate(); err != nil {
+		t.Errorf("Expected a brownout disabling built-in entries to be valid, got %v", err)
+	}
+}
+
+func TestBrownoutCheck_DisableDefaultRules(t *testing.T) {
+	disabled, err := ParseDisabledDefaultRules(" Actions/Upload-Artifact ,")
+	if err != nil || len(disabled) != 1 {
+		t.Fatalf("Expected actions/upload-artifact to be accepted, got %v (%v)", disabled, err)
+	}
+	if _, err := ParseDisabledDefaultRules("actions/setup-java"); err == nil || !strings.Contains(err.Error(), "actions/cache") {
+		t.Errorf("Expected an action without built-in rules to be rejected with the known ones, got %v", err)
+	}
+
+	manager := NewManagerWithResolverConfigAndRules(nil, &Config{Checks: []string{CheckBrownout}, DisableDefaultRules: disabled}, nil)
+	manager.now = func() time.Time { return time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC) }
+	issues := manager.AnalyzeActions([]workflow.ActionReference{
+		{Repository: "actions/upload-artifact", Version: "v3", FilePath: ".github/workflows/ci.yml"},
+		{Repository: "actions/download-artifact", Version: "v3", FilePath: ".github/workflows/ci.yml"},
+	})
+	if len(issues) != 1 || issues[0].Repository != "actions/download-artifact" {
+		t.Errorf("Expected only the download-artifact entry to stay on, got %+v", issues)
+	}
+}
@@ -0,0 +1,325 @@
+package actions
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// Names of the built-in checks, which are also the issue types they raise
+const (
+	CheckCommentDrift    = "comment-drift"                // Pin comments disagreeing with the pinned ref, and moved tags
+	CheckBannedAction    = IssueTypeBanned                // Every use of a banned action
+	CheckOutdated        = "outdated"                     // Versions older than the rule's latest version
+	CheckDeprecated      = "deprecated"                   // Versions the rule lists as deprecated
+	CheckMigration       = "migration"                    // Actions and reusable workflows that have moved
+	CheckRequiredActions = IssueTypeMissingRequiredAction // Workflows or jobs missing a required action
+	CheckBrownout        = "brownout"                     // Versions with an announced brownout or removal
+	CheckBrokenReference = "broken-reference"             // Repositories or refs that do not exist
+)
+
+// Check is an analysis run by the manager, registered by name with RegisterCheck
+// A check implements ActionCheck, RepositoryCheck, or both.
+type Check interface {
+	Name() string
+}
+
+// ActionCheck analyzes every action reference of a repository
+type ActionCheck interface {
+	Check
+	// CheckAction returns the issues of an action reference; rule is the rule matching it, or nil
+	CheckAction(m *Manager, action workflow.ActionReference, rule *Rule) []output.ActionIssue
+}
+
+// RepositoryCheck analyzes the workflows of a repository as a whole
+type RepositoryCheck interface {
+	Check
+	// CheckRepository returns the issues of a repository from the job layout of its workflows
+	CheckRepository(m *Manager, repo output.RepositoryResult, outlines []workflow.WorkflowOutline) []output.ActionIssue
+}
+
+var (
+	checksMu sync.RWMutex
+	checks   []Check
+)
+
+func init() {
+	for _, check := range []Check{
+		commentDriftCheck{}, bannedActionCheck{}, outdatedCheck{}, deprecatedCheck{}, migrationCheck{}, requiredActionsCheck{}, brownoutCheck{},
+		brokenReferenceCheck{},
+	} {
+		RegisterCheck(check)
+	}
+}
+
+// RegisterCheck adds a check run by every manager enabling it, typically from an init function
+// Checks run in registration order. Registering an unnamed check or a name twice panics.
+func RegisterCheck(check Check) {
+	checksMu.Lock()
+	defer checksMu.Unlock()
+
+	name := check.Name()
+	if name == "" {
+		panic("actions: check has no name")
+	}
+	for _, existing := range checks {
+		if existing.Name() == name {
+			panic(fmt.Sprintf("actions: check %q registered twice", name))
+		}
+	}
+	checks = append(checks, check)
+}
+
+// RegisteredChecks returns the names of the registered checks, in registration order
+func RegisteredChecks() []string {
+	checksMu.RLock()
+	defer checksMu.RUnlock()
+
+	names := make([]string, len(checks))
+	for i, check := range checks {
+		names[i] = check.Name()
+	}
+	return names
+}
+
+// ParseChecks parses a comma-separated list of check names, where "all" enables every registered check
+func ParseChecks(value string) ([]string, error) {
+	registered := RegisteredChecks()
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "":
+			continue
+		case name == "all":
+			return registered, nil
+		case !containsString(registered, name):
+			return nil, fmt.Errorf("unknown check %q: use %s, or all", name, strings.Join(registered, ", "))
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// enabledChecks returns the registered checks with the given names, or every check when none are given
+func enabledChecks(names []string) []Check {
+	checksMu.RLock()
+	defer checksMu.RUnlock()
+
+	var enabled []Check
+	for _, check := range checks {
+		if len(names) == 0 || containsString(names, check.Name()) {
+			enabled = append(enabled, check)
+		}
+	}
+	return enabled
+}
+
+// containsString reports whether a list contains a value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// commentDriftCheck flags pinned actions whose trailing version comment no longer matches the pinned ref
+// It applies to every pinned action, with or without a rule.
+type commentDriftCheck struct{}
+
+func (commentDriftCheck) Name() string { return CheckCommentDrift }
+
+func (commentDriftCheck) CheckAction(m *Manager, action workflow.ActionReference, rule *Rule) []output.ActionIssue {
+	if issue := m.checkCommentDrift(action); issue != nil {
+		return []output.ActionIssue{*issue}
+	}
+	return nil
+}
+
+// bannedActionCheck reports every use of an action banned by its rule
+type bannedActionCheck struct{}
+
+func (bannedActionCheck) Name() string { return CheckBannedAction }
+
+func (bannedActionCheck) CheckAction(m *Manager, action workflow.ActionReference, rule *Rule) []output.ActionIssue {
+	if rule == nil || rule.Ban == nil {
+		return nil
+	}
+	issues := []output.ActionIssue{m.bannedIssue(action, rule)}
+	annotateRuleIssues(issues, rule)
+	return issues
+}
+
+// versionRule returns the rule when its version checks apply: banned actions are reported at any
+// version, so version checks do not apply to them
+func versionRule(rule *Rule) *Rule {
+	if rule == nil || rule.Ban != nil {
+		return nil
+	}
+	return rule
+}
+
+// outdatedCheck reports versions older than the rule's latest version
+type outdatedCheck struct{}
+
+func (outdatedCheck) Name() string { return CheckOutdated }
+
+func (outdatedCheck) CheckAction(m *Manager, action workflow.ActionReference, rule *Rule) []output.ActionIssue {
+	rule = versionRule(rule)
+	if rule == nil || !m.isOutdatedForRepository(action.Repository, action.Version, rule.LatestVersion) {
+		return nil
+	}
+	if m.verbose {
+		m.logf("Rule evaluation: Version %s is outdated for %s (latest: %s)", action.Version, action.Repository, rule.LatestVersion)
+	}
+
+	// Suggest version in the same format as current version (like for like)
+	suggestedVersion := m.suggestLikeForLikeVersion(action.Repository, action.Version, rule.LatestVersion)
+
+	if m.verbose {
+		m.logf("Rule evaluation: Suggested version for %s: %s -> %s", action.Repository, action.Version, suggestedVersion)
+	}
+
+	issue := output.ActionIssue{
+		Repository:       action.Repository,
+		CurrentVersion:   action.Version,
+		SuggestedVersion: suggestedVersion,
+		IssueType:        CheckOutdated,
+		Severity:         m.determineSeverity(action.Version, rule),
+		Description:      fmt.Sprintf("Action %s is using version %s, latest is %s", action.Repository, action.Version, rule.LatestVersion),
+		Context:          action.Context,
+		FilePath:         action.FilePath,
+		PinComment:       action.PinComment,
+	}
+	issue.SuggestedPinComment = m.suggestPinComment(suggestedVersion, rule.LatestVersion)
+
+	if m.verbose {
+		m.logf("Rule evaluation: Created outdated issue for %s with severity %s", action.Repository, issue.Severity)
+	}
+
+	// Check if there are schema transformations for this version upgrade
+	m.addTransformations(&issue, action, rule.LatestVersion, action.Repository)
+
+	issues := []output.ActionIssue{issue}
+	annotateRuleIssues(issues, rule)
+	return issues
+}
+
+// deprecatedCheck reports versions the rule lists as deprecated
+type deprecatedCheck struct{}
+
+func (deprecatedCheck) Name() string { return CheckDeprecated }
+
+func (deprecatedCheck) CheckAction(m *Manager, action workflow.ActionReference, rule *Rule) []output.ActionIssue {
+	rule = versionRule(rule)
+	if rule == nil {
+		return nil
+	}
+
+	var issues []output.ActionIssue
+	for _, deprecatedVersion := range rule.DeprecatedVersions {
+		if action.Version != deprecatedVersion {
+			continue
+		}
+		if m.verbose {
+			m.logf("Rule evaluation: Version %s is deprecated for %s", action.Version, action.Repository)
+		}
+
+		// Suggest version in the same format as current version (like for like)
+		suggestedVersion := m.suggestLikeForLikeVersion(action.Repository, action.Version, rule.LatestVersion)
+
+		issue := output.ActionIssue{
+			Repository:       action.Repository,
+			CurrentVersion:   action.Version,
+			SuggestedVersion: suggestedVersion,
+			IssueType:        CheckDeprecated,
+			Severity:         "high",
+			Description:      fmt.Sprintf("Action %s version %s is deprecated", action.Repository, action.Version),
+			Context:          action.Context,
+			FilePath:         action.FilePath,
+			PinComment:       action.PinComment,
+		}
+		issue.SuggestedPinComment = m.suggestPinComment(suggestedVersion, rule.LatestVersion)
+
+		// Check if there are schema transformations for this version upgrade
+		m.addTransformations(&issue, action, rule.LatestVersion, action.Repository)
+
+		issues = append(issues, issue)
+	}
+
+	annotateRuleIssues(issues, rule)
+	return issues
+}
+
+// migrationCheck reports actions and reusable workflows that have moved to another repository or path
+type migrationCheck struct{}
+
+func (migrationCheck) Name() string { return CheckMigration }
+
+func (migrationCheck) CheckAction(m *Manager, action workflow.ActionReference, rule *Rule) []output.ActionIssue {
+	rule = versionRule(rule)
+	if rule == nil || (rule.MigrateToRepository == "" && rule.MigrateToPath == "") || rule.MigrateToVersion == "" {
+		return nil
+	}
+
+	// Path-only migrations rename a reusable workflow (or nested action) within the same repository
+	targetRepository := rule.MigrateToRepository
+	if targetRepository == "" {
+		targetRepository = action.Repository
+	}
+	targetPath := rule.MigrateToPath
+	if targetPath == "" {
+		targetPath = action.WorkflowPath
+	}
+
+	if m.verbose {
+		pathInfo := ""
+		if action.WorkflowPath != "" {
+			pathInfo = fmt.Sprintf(" (path: %s)", action.WorkflowPath)
+		}
+		m.logf("Rule evaluation: Repository %s%s should migrate to %s@%s", action.Repository, pathInfo, targetRepository, rule.MigrateToVersion)
+	}
+
+	// Build migration target with path if specified
+	migrationTarget := targetRepository
+	if targetPath != "" {
+		migrationTarget += "/" + targetPath
+	}
+	migrationTarget += "@" + rule.MigrateToVersion
+
+	description := fmt.Sprintf("Action %s has migrated to %s", action.Repository, targetRepository)
+	if rule.MigrateToRepository == "" {
+		description = fmt.Sprintf("Workflow %s/%s has moved to %s", action.Repository, action.WorkflowPath, targetPath)
+	}
+	if rule.Recommendation != "" {
+		description = rule.Recommendation
+	}
+
+	issue := output.ActionIssue{
+		Repository:      action.Repository,
+		WorkflowPath:    action.WorkflowPath,
+		CurrentVersion:  action.Version,
+		MigrationTarget: migrationTarget,
+		IssueType:       CheckMigration,
+		Severity:        "medium",
+		Description:     description,
+		Context:         action.Context,
+		FilePath:        action.FilePath,
+	}
+
+	// Check if there are schema transformations for this migration
+	m.addTransformations(&issue, action, rule.MigrateToVersion, targetRepository)
+
+	if m.verbose {
+		m.logf("Rule evaluation: Created migration issue for %s -> %s with severity %s", action.Repository, migrationTarget, issue.Severity)
+	}
+
+	issues := []output.ActionIssue{issue}
+	annotateRuleIssues(issues, rule)
+	return issues
+}
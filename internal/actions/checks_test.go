@@ -0,0 +1,96 @@
+package actions
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// registeredTestCheck flags a single test repository, so registering it leaves other tests unaffected
+type registeredTestCheck struct{}
+
+func (registeredTestCheck) Name() string { return "test-registered" }
+
+func (registeredTestCheck) CheckAction(m *Manager, action workflow.ActionReference, rule *Rule) []output.ActionIssue {
+	if action.Repository != "test/registered" {
+		return nil
+	}
+	return []output.ActionIssue{{Repository: action.Repository, IssueType: "test-registered", Severity: "low"}}
+}
+
+func TestRegisteredChecks(t *testing.T) {
+	expected := []string{CheckCommentDrift, CheckBannedAction, CheckOutdated, CheckDeprecated, CheckMigration, CheckRequiredActions}
+	if names := RegisteredChecks(); !reflect.DeepEqual(names[:len(expected)], expected) {
+		t.Errorf("Expected built-in checks %v first, got %v", expected, names)
+	}
+}
+
+func TestParseChecks(t *testing.T) {
+	checks, err := ParseChecks("outdated, deprecated")
+	if err != nil || !reflect.DeepEqual(checks, []string{CheckOutdated, CheckDeprecated}) {
+		t.Errorf("Unexpected checks %v, error %v", checks, err)
+	}
+	if checks, _ := ParseChecks("all"); !reflect.DeepEqual(checks, RegisteredChecks()) {
+		t.Errorf("Expected all checks, got %v", checks)
+	}
+	if checks, err := ParseChecks(""); err != nil || len(checks) != 0 {
+		t.Errorf("Expected no checks for an empty list, got %v, %v", checks, err)
+	}
+	if _, err := ParseChecks("outdated,unpinned-typo"); err == nil {
+		t.Errorf("Expected an error for an unknown check")
+	}
+}
+
+func TestManager_SelectedChecks(t *testing.T) {
+	rules := []Rule{
+		{Repository: "actions/checkout", LatestVersion: "v4", DeprecatedVersions: []string{"v2"}},
+		{Repository: "old-org/setup", LatestVersion: "v1", MigrateToRepository: "new-org/setup", MigrateToVersion: "v2"},
+	}
+	actions := []workflow.ActionReference{
+		{Repository: "actions/checkout", Version: "v2", FilePath: "ci.yml"},
+		{Repository: "old-org/setup", Version: "v1", FilePath: "ci.yml"},
+	}
+
+	all := NewManagerWithResolverConfigAndRules(nil, nil, rules).AnalyzeActions(actions)
+	var types []string
+	for _, issue := range all {
+		types = append(types, issue.IssueType)
+	}
+	if !reflect.DeepEqual(types, []string{"outdated", "deprecated", "migration"}) {
+		t.Errorf("Expected every check to run by default, got %v", types)
+	}
+
+	selected := NewManagerWithResolverConfigAndRules(nil, &Config{Checks: []string{CheckDeprecated}}, rules).AnalyzeActions(actions)
+	if len(selected) != 1 || selected[0].IssueType != "deprecated" {
+		t.Errorf("Expected only the deprecated check to run, got %+v", selected)
+	}
+
+	required := NewManagerWithResolverConfigAndRules(nil, &Config{Checks: []string{CheckOutdated}}, []Rule{
+		{Repository: "my-org/scan", Required: &Requirement{Scope: RequireInWorkflow, Version: "v1"}},
+	})
+	outlines := []workflow.WorkflowOutline{{FilePath: ".github/workflows/ci.yml", Jobs: []workflow.JobOutline{{Name: "test"}}}}
+	if issues := required.CheckRequiredActions(output.RepositoryResult{Name: "api"}, outlines); len(issues) != 0 {
+		t.Errorf("Expected required actions not to be checked when disabled, got %+v", issues)
+	}
+}
+
+func TestRegisterCheck(t *testing.T) {
+	// The registry is global, so repeated runs (-count) find the check already registered
+	if !containsString(RegisteredChecks(), "test-registered") {
+		RegisterCheck(registeredTestCheck{})
+	}
+
+	issues := NewManager().AnalyzeActions([]workflow.ActionReference{{Repository: "test/registered", Version: "v1"}})
+	if len(issues) != 1 || issues[0].IssueType != "test-registered" {
+		t.Errorf("Expected the registered check to run, got %+v", issues)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected registering a check twice to panic")
+		}
+	}()
+	RegisterCheck(registeredTestCheck{})
+}
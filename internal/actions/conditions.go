@@ -0,0 +1,123 @@
+package actions
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// RuleConditions restrict a rule to repositories matching all of the given criteria
+type RuleConditions struct {
+	RepositoryPattern string            `json:"repository_pattern,omitempty"` // Regex matched against the repository name
+	CustomProperties  map[string]string `json:"custom_properties,omitempty"`  // Custom property values that must all be equal
+	Topic             string            `json:"topic,omitempty"`              // Topic the repository must have
+}
+
+// RepositoryContext describes the repository whose actions are being analyzed
+type RepositoryContext struct {
+	Name             string
+	FullName         string
+	DefaultBranch    string
+	CustomProperties map[string]string
+	Topics           []string
+}
+
+// Validate checks that the repository pattern is a valid regex
+func (c *RuleConditions) Validate() error {
+	if c == nil || c.RepositoryPattern == "" {
+		return nil
+	}
+	if _, err := regexp.Compile(c.RepositoryPattern); err != nil {
+		return fmt.Errorf("invalid repository_pattern %q: %w", c.RepositoryPattern, err)
+	}
+	return nil
+}
+
+// Matches reports whether the repository satisfies every condition; nil conditions match any repository
+func (c *RuleConditions) Matches(repo RepositoryContext) bool {
+	if c == nil {
+		return true
+	}
+
+	var pattern *regexp.Regexp
+	if c.RepositoryPattern != "" {
+		compiled, err := regexp.Compile(c.RepositoryPattern)
+		if err != nil {
+			return false
+		}
+		pattern = compiled
+	}
+	return c.matches(repo, pattern)
+}
+
+// matches evaluates the conditions with an already compiled repository pattern
+func (c *RuleConditions) matches(repo RepositoryContext, pattern *regexp.Regexp) bool {
+	if pattern != nil && !pattern.MatchString(repo.Name) {
+		return false
+	}
+
+	for name, value := range c.CustomProperties {
+		if repo.CustomProperties[name] != value {
+			return false
+		}
+	}
+
+	if c.Topic != "" {
+		found := false
+		for _, topic := range repo.Topics {
+			if topic == c.Topic {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String summarizes the conditions, e.g. "repository_pattern=^api-, ProductId=legacy, topic=beta"
+func (c *RuleConditions) String() string {
+	if c == nil {
+		return ""
+	}
+
+	var parts []string
+	if c.RepositoryPattern != "" {
+		parts = append(parts, "repository_pattern="+c.RepositoryPattern)
+	}
+	names := make([]string, 0, len(c.CustomProperties))
+	for name := range c.CustomProperties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		parts = append(parts, name+"="+c.CustomProperties[name])
+	}
+	if c.Topic != "" {
+		parts = append(parts, "topic="+c.Topic)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ConditionProperties returns the custom property names referenced by rule conditions, sorted
+func ConditionProperties(rules []Rule) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, rule := range rules {
+		if rule.Conditions == nil {
+			continue
+		}
+		for name := range rule.Conditions.CustomProperties {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,64 @@
+package actions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultRuleRepositories returns the action repositories with built-in rules, in sorted order
+// Version and patch rules have no built-in defaults; the built-in rules are the brownout calendar.
+func DefaultRuleRepositories() []string {
+	seen := make(map[string]bool)
+	var repositories []string
+	for _, rule := range DefaultBrownouts {
+		if !seen[rule.Repository] {
+			seen[rule.Repository] = true
+			repositories = append(repositories, rule.Repository)
+		}
+	}
+	sort.Strings(repositories)
+	return repositories
+}
+
+// ParseDisabledDefaultRules parses a comma-separated list of action repositories whose built-in rules
+// are turned off, rejecting repositories without built-in rules
+func ParseDisabledDefaultRules(value string) ([]string, error) {
+	known := DefaultRuleRepositories()
+	var repositories []string
+	for _, repository := range strings.Split(value, ",") {
+		repository = strings.TrimSpace(repository)
+		if repository == "" {
+			continue
+		}
+		if !containsFold(known, repository) {
+			return nil, fmt.Errorf("%s has no built-in rules: use %s", repository, strings.Join(known, ", "))
+		}
+		repositories = append(repositories, repository)
+	}
+	return repositories, nil
+}
+
+// enabledDefaults returns the built-in rules whose repository is not disabled
+func enabledDefaults(defaults []Rule, disabled []string) []Rule {
+	if len(disabled) == 0 {
+		return defaults
+	}
+	var enabled []Rule
+	for _, rule := range defaults {
+		if !containsFold(disabled, rule.Repository) {
+			enabled = append(enabled, rule)
+		}
+	}
+	return enabled
+}
+
+// containsFold reports whether a list contains a value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,211 @@
+package actions
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// readmeNoticeLines is how much of a README is searched; deprecation notices sit at the top
+const readmeNoticeLines = 40
+
+// maxNoticeLength caps the notice text quoted in issue descriptions
+const maxNoticeLength = 160
+
+// readmeDeprecationPatterns match deprecation notices and status badges in a README
+var readmeDeprecationPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(this|the) (action|repository|repo|project) (is|has been) (now )?(deprecated|archived|unmaintained|no longer (actively )?maintained)\b`),
+	regexp.MustCompile(`(?i)\bno longer (being |actively )?maintained\b`),
+	// A heading, bold text, or alert that is only "Deprecated", e.g. "> [!WARNING]" followed by "**Deprecated:** use ..."
+	regexp.MustCompile(`(?i)^\s*(>\s*)?(#+\s*|\*\*|__)?\s*(⚠️\s*)?(deprecated|deprecation notice)\s*(\*\*|__)?\s*([:!.—-]|$)`),
+	regexp.MustCompile(`(?i)img\.shields\.io/badge/[^)\s]*deprecated`),
+	regexp.MustCompile(`(?i)repostatus\.org/badges/[^)\s]*/(unsupported|abandoned|moved)\b`),
+}
+
+// descriptionDeprecationPattern matches repository descriptions announcing deprecation
+var descriptionDeprecationPattern = regexp.MustCompile(`(?i)^\W*deprecated\b|\b(is|now) deprecated\b|\bno longer (actively )?maintained\b|\bunmaintained\b`)
+
+// RepositoryStatusClient looks up whether an action repository announces its deprecation
+type RepositoryStatusClient interface {
+	GetRepositoryStatus(owner, repo string) (*github.RepositoryStatus, error)
+}
+
+// DeprecationNotice is a deprecation announced by an action's repository
+type DeprecationNotice struct {
+	Archived bool
+	Reason   string // e.g. "repository is archived" or the README line announcing the deprecation
+}
+
+// DeprecationAnnotator adds deprecation notices from action repositories to issues, so deprecated
+// actions are flagged without listing every version in a rule's deprecated_versions
+type DeprecationAnnotator struct {
+	client  RepositoryStatusClient
+	verbose bool
+
+	// Each repository is checked once per run; nil records repositories without a notice
+	mutex   sync.Mutex
+	notices map[string]*DeprecationNotice
+}
+
+// NewDeprecationAnnotator creates an annotator backed by the given client
+func NewDeprecationAnnotator(client RepositoryStatusClient, config *Config) *DeprecationAnnotator {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	return &DeprecationAnnotator{
+		client:  client,
+		verbose: config.Verbose,
+		notices: make(map[string]*DeprecationNotice),
+	}
+}
+
+// Annotate appends repository deprecation notices to the descriptions of issues for deprecated actions,
+// and raises a "deprecated" issue for each reference to a deprecated action no rule already flagged
+func (d *DeprecationAnnotator) Annotate(references []workflow.ActionReference, issues []output.ActionIssue) []output.ActionIssue {
+	for i := range issues {
+		if notice := d.notice(issues[i].Repository); notice != nil {
+			issues[i].Description += fmt.Sprintf(" (upstream notice: %s)", notice.Reason)
+		}
+	}
+
+	for _, action := range references {
+		notice := d.notice(action.Repository)
+		if notice == nil || flagged(issues, action) {
+			continue
+		}
+
+		severity := "medium"
+		if notice.Archived {
+			severity = "high" // Archived repositories receive no further fixes
+		}
+		issues = append(issues, output.ActionIssue{
+			Repository:     action.Repository,
+			WorkflowPath:   action.WorkflowPath,
+			CurrentVersion: action.Version,
+			IssueType:      "deprecated",
+			Severity:       severity,
+			Description:    fmt.Sprintf("Action %s is deprecated upstream: %s", action.Repository, notice.Reason),
+			Context:        action.Context,
+			FilePath:       action.FilePath,
+			PinComment:     action.PinComment,
+		})
+	}
+
+	return issues
+}
+
+// flagged reports whether a rule already raised a deprecated or migration issue for a reference
+func flagged(issues []output.ActionIssue, action workflow.ActionReference) bool {
+	for _, issue := range issues {
+		if (issue.IssueType == "deprecated" || issue.IssueType == "migration") &&
+			strings.EqualFold(issue.Repository, action.Repository) &&
+			issue.CurrentVersion == action.Version && issue.FilePath == action.FilePath {
+			return true
+		}
+	}
+	return false
+}
+
+// notice returns the deprecation notice of an action repository, or nil if it has none
+func (d *DeprecationAnnotator) notice(repository string) *DeprecationNotice {
+	parts := strings.Split(repository, "/")
+	if len(parts) < 2 || strings.HasPrefix(repository, ".") || strings.Contains(parts[0], ":") {
+		return nil // Local and docker references have no repository to check
+	}
+	owner, repo := parts[0], parts[1]
+	key := strings.ToLower(owner + "/" + repo)
+
+	d.mutex.Lock()
+	notice, checked := d.notices[key]
+	d.mutex.Unlock()
+	if checked {
+		return notice
+	}
+
+	status, err := d.client.GetRepositoryStatus(owner, repo)
+	if err != nil {
+		if d.verbose {
+			log.Printf("Unable to check %s for a deprecation notice: %v", key, err)
+		}
+	} else {
+		notice = DetectDeprecation(status)
+		if notice != nil && d.verbose {
+			log.Printf("Found deprecation notice for %s: %s", key, notice.Reason)
+		}
+	}
+
+	d.mutex.Lock()
+	d.notices[key] = notice
+	d.mutex.Unlock()
+	return notice
+}
+
+// DetectDeprecation returns the deprecation a repository announces, or nil if it announces none
+// An archived repository takes precedence; otherwise the description and the top of the README are searched.
+func DetectDeprecation(status *github.RepositoryStatus) *DeprecationNotice {
+	if status == nil {
+		return nil
+	}
+	if status.Archived {
+		return &DeprecationNotice{Archived: true, Reason: "repository is archived"}
+	}
+
+	if descriptionDeprecationPattern.MatchString(status.Description) {
+		return &DeprecationNotice{Reason: "description: " + noticeText(status.Description)}
+	}
+
+	lines := strings.Split(status.Readme, "\n")
+	if len(lines) > readmeNoticeLines {
+		lines = lines[:readmeNoticeLines]
+	}
+	for i, line := range lines {
+		if !matchesAnyPattern(readmeDeprecationPatterns, line) {
+			continue
+		}
+		if strings.Contains(line, "](") {
+			return &DeprecationNotice{Reason: "README status badge marks it deprecated"}
+		}
+		text := noticeText(line)
+		// A bare "Deprecated" heading is explained by the line after it
+		if bare := strings.ToLower(strings.TrimRight(text, ":!.—- ")); bare == "deprecated" || bare == "deprecation notice" {
+			for _, next := range lines[i+1:] {
+				if next = noticeText(next); next != "" {
+					text = next
+					break
+				}
+			}
+		}
+		return &DeprecationNotice{Reason: "README: " + text}
+	}
+
+	return nil
+}
+
+// matchesAnyPattern reports whether any pattern matches the text
+func matchesAnyPattern(patterns []*regexp.Regexp, text string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// noticeText strips Markdown decoration from a notice line and shortens it
+func noticeText(line string) string {
+	text := strings.TrimSpace(line)
+	text = strings.TrimLeft(text, "#>*_ ")
+	text = strings.TrimPrefix(text, "⚠️")
+	text = strings.TrimSpace(strings.NewReplacer("**", "", "__", "", "`", "").Replace(text))
+	if runes := []rune(text); len(runes) > maxNoticeLength {
+		text = strings.TrimSpace(string(runes[:maxNoticeLength])) + "..."
+	}
+	return text
+}
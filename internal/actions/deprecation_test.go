@@ -0,0 +1,94 @@
+package actions
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// fakeStatusClient returns canned repository statuses and counts lookups
+type fakeStatusClient struct {
+	statuses map[string]*github.RepositoryStatus
+	calls    int
+}
+
+func (f *fakeStatusClient) GetRepositoryStatus(owner, repo string) (*github.RepositoryStatus, error) {
+	f.calls++
+	status, exists := f.statuses[owner+"/"+repo]
+	if !exists {
+		return nil, fmt.Errorf("not found")
+	}
+	return status, nil
+}
+
+func TestDetectDeprecation(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   *github.RepositoryStatus
+		expected string // empty for no notice
+	}{
+		{"archived", &github.RepositoryStatus{Archived: true, Readme: "# Action"}, "repository is archived"},
+		{"description", &github.RepositoryStatus{Description: "DEPRECATED: use actions/cache instead"}, "description: DEPRECATED: use actions/cache instead"},
+		{"readme sentence", &github.RepositoryStatus{Readme: "# Setup\n\n> **Note** This action is deprecated in favor of `my-org/setup@v2`.\n"}, "README: Note This action is deprecated in favor of my-org/setup@v2."},
+		{"bare heading", &github.RepositoryStatus{Readme: "# Setup\n\n## ⚠️ Deprecated\n\nUse my-org/setup instead.\n"}, "README: Use my-org/setup instead."},
+		{"badge", &github.RepositoryStatus{Readme: "[![status](https://img.shields.io/badge/status-deprecated-red)](#)\n"}, "README status badge marks it deprecated"},
+		{"deprecated inputs", &github.RepositoryStatus{Readme: "# Setup\n\n## Deprecated inputs\n\n`version` is deprecated, use `go-version`.\n"}, ""},
+		{"notice below the top", &github.RepositoryStatus{Readme: strings.Repeat("line\n", readmeNoticeLines) + "This project is no longer maintained.\n"}, ""},
+		{"active", &github.RepositoryStatus{Description: "Check out a repository", Readme: "# Checkout\n"}, ""},
+	}
+
+	for _, tt := range tests {
+		notice := DetectDeprecation(tt.status)
+		switch {
+		case tt.expected == "" && notice != nil:
+			t.Errorf("%s: expected no notice, got %q", tt.name, notice.Reason)
+		case tt.expected != "" && notice == nil:
+			t.Errorf("%s: expected %q, got no notice", tt.name, tt.expected)
+		case notice != nil && notice.Reason != tt.expected:
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, notice.Reason)
+		}
+	}
+}
+
+func TestDeprecationAnnotator_Annotate(t *testing.T) {
+	client := &fakeStatusClient{statuses: map[string]*github.RepositoryStatus{
+		"old-org/setup":    {Archived: true},
+		"actions/checkout": {Description: "Action for checking out a repo"},
+	}}
+	annotator := NewDeprecationAnnotator(client, nil)
+
+	references := []workflow.ActionReference{
+		{Repository: "old-org/setup", Version: "v1", FilePath: ".github/workflows/ci.yml", Context: "build"},
+		{Repository: "old-org/setup", Version: "v2", FilePath: ".github/workflows/ci.yml", Context: "test"},
+		{Repository: "actions/checkout", Version: "v4", FilePath: ".github/workflows/ci.yml"},
+		{Repository: "./.github/actions/local", FilePath: ".github/workflows/ci.yml"},
+	}
+	issues := []output.ActionIssue{
+		{Repository: "old-org/setup", CurrentVersion: "v1", IssueType: "deprecated", Severity: "high", Description: "Action old-org/setup version v1 is deprecated", FilePath: ".github/workflows/ci.yml"},
+	}
+
+	issues = annotator.Annotate(references, issues)
+	if len(issues) != 2 {
+		t.Fatalf("Expected the rule issue plus 1 upstream issue, got %d: %+v", len(issues), issues)
+	}
+	if issues[0].Description != "Action old-org/setup version v1 is deprecated (upstream notice: repository is archived)" {
+		t.Errorf("Unexpected annotated description: %s", issues[0].Description)
+	}
+
+	added := issues[1]
+	if added.IssueType != "deprecated" || added.Severity != "high" || added.CurrentVersion != "v2" || added.Context != "test" {
+		t.Errorf("Unexpected upstream issue: %+v", added)
+	}
+	if added.Description != "Action old-org/setup is deprecated upstream: repository is archived" {
+		t.Errorf("Unexpected upstream description: %s", added.Description)
+	}
+
+	// Each repository is looked up once; local actions are never looked up
+	if client.calls != 2 {
+		t.Errorf("Expected 2 status lookups, got %d", client.calls)
+	}
+}
@@ -0,0 +1,121 @@
+package actions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// ExplainRepository records, for each action reference of a repository, which rule applies and why the
+// enabled version checks did or did not raise an issue (scan --explain-all). Complete the decisions with
+// output.RecordReported once the issues have been suppressed.
+func (m *Manager) ExplainRepository(repo output.RepositoryResult) []output.Decision {
+	context := repositoryContextOf(repo)
+	decisions := make([]output.Decision, 0, len(repo.Actions))
+	for _, action := range repo.Actions {
+		decisions = append(decisions, m.explainAction(context, action))
+	}
+	return decisions
+}
+
+// explainAction records the rule selection and version check outcomes of an action reference
+func (m *Manager) explainAction(repo RepositoryContext, action workflow.ActionReference) output.Decision {
+	decision := output.NewDecision(action)
+	if action.Local {
+		decision.AddStep(output.DecisionStageRule, output.DecisionUnmatched, "local reusable workflows are versioned with the repository, so only their workflow file is checked")
+		return decision
+	}
+
+	rule := m.findRuleForAction(repo, action)
+	if rule == nil {
+		decision.AddStep(output.DecisionStageRule, output.DecisionUnmatched, m.explainNoRule(repo, action))
+		return decision
+	}
+	decision.Rule = ruleName(rule)
+	decision.AddStep(output.DecisionStageRule, output.DecisionMatched, fmt.Sprintf("rule %s applies", decision.Rule))
+
+	for _, check := range m.checks {
+		switch check.Name() {
+		case CheckBannedAction:
+			if rule.Ban != nil {
+				decision.AddStep(CheckBannedAction, output.DecisionRaised, "the rule bans the action")
+			}
+		case CheckOutdated:
+			if rule.Ban != nil {
+				decision.AddStep(CheckOutdated, output.DecisionNotRaised, "version checks do not apply to banned actions")
+				continue
+			}
+			outdated, reason := m.explainOutdated(action, rule)
+			outcome := output.DecisionNotRaised
+			if outdated {
+				outcome = output.DecisionRaised
+			}
+			decision.AddStep(CheckOutdated, outcome, reason)
+		case CheckDeprecated:
+			if rule.Ban != nil || len(rule.DeprecatedVersions) == 0 {
+				continue
+			}
+			if containsString(rule.DeprecatedVersions, action.Version) {
+				decision.AddStep(CheckDeprecated, output.DecisionRaised, fmt.Sprintf("the rule lists %s as deprecated", action.Version))
+			} else {
+				decision.AddStep(CheckDeprecated, output.DecisionNotRaised, fmt.Sprintf("the rule does not list %s as deprecated", action.Version))
+			}
+		case CheckMigration:
+			if rule.Ban != nil || (rule.MigrateToRepository == "" && rule.MigrateToPath == "") {
+				continue
+			}
+			if rule.MigrateToVersion == "" {
+				decision.AddStep(CheckMigration, output.DecisionNotRaised, "the rule names a migration target but no version")
+			} else {
+				decision.AddStep(CheckMigration, output.DecisionRaised, "the rule migrates the action")
+			}
+		}
+	}
+	return decision
+}
+
+// explainOutdated decides whether an action is outdated the way the outdated check does. The resolver
+// answers for branches and equivalent versions too, so those reasons are named apart from a plain "not
+// older" answer.
+func (m *Manager) explainOutdated(action workflow.ActionReference, rule *Rule) (bool, string) {
+	outdated, reason := m.compareToLatest(action.Repository, action.Version, rule.LatestVersion)
+	if outdated || m.resolver == nil || action.Version == rule.LatestVersion {
+		return outdated, reason
+	}
+	if action.Version == "main" || action.Version == "master" {
+		return false, fmt.Sprintf("branch %s is exempt from version checks", action.Version)
+	}
+	if equivalent, err := m.resolver.AreVersionsEquivalent(action.Repository, action.Version, rule.LatestVersion); err == nil && equivalent {
+		return false, fmt.Sprintf("resolver found %s equivalent to %s (same SHA)", action.Version, rule.LatestVersion)
+	}
+	return false, reason
+}
+
+// explainNoRule says why no rule applies to an action: rules for its repository can still exist for
+// other workflow paths, or have conditions the scanned repository does not meet
+func (m *Manager) explainNoRule(repo RepositoryContext, action workflow.ActionReference) string {
+	var skipped []string
+	for _, i := range m.index.candidates(action.Repository) {
+		rule := &m.rules[i]
+		switch {
+		case rule.WorkflowPath != "" && rule.WorkflowPath != action.WorkflowPath:
+			skipped = append(skipped, fmt.Sprintf("rule %s is for another workflow path", ruleName(rule)))
+		case !m.index.conditionsMatch(i, rule.Conditions, repo):
+			skipped = append(skipped, fmt.Sprintf("the conditions of rule %s do not match %s", ruleName(rule), repo.FullName))
+		}
+	}
+	if len(skipped) == 0 {
+		return fmt.Sprintf("no rule for %s, so only rule-independent checks ran", action.Repository)
+	}
+	return strings.Join(skipped, "; ")
+}
+
+// ruleName identifies a rule by its repository and, for path-specific rules, its workflow path
+func ruleName(rule *Rule) string {
+	if rule.WorkflowPath != "" {
+		return rule.Repository + "/" + rule.WorkflowPath
+	}
+	return rule.Repository
+}
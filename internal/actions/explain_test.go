@@ -0,0 +1,84 @@
+package actions
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+func TestExplainRepository(t *testing.T) {
+	resolver := NewMockVersionResolver()
+	resolver.outdatedVersions["actions/setup-go:v4.2.0:v5"] = false
+	resolver.equivalentVersions["actions/setup-go:v4.2.0:v5"] = true
+
+	manager := NewManagerWithResolverConfigAndRules(resolver, nil, []Rule{
+		{Repository: "actions/checkout", LatestVersion: "v4", DeprecatedVersions: []string{"v2"}},
+		{Repository: "actions/setup-go", LatestVersion: "v5"},
+		{Repository: "my-org/shared", WorkflowPath: ".github/workflows/build.yml", LatestVersion: "v2"},
+		{Repository: "my-org/deploy", LatestVersion: "v3", Conditions: &RuleConditions{Topic: "production"}},
+		{Repository: "old/notify", Ban: &Ban{}},
+	})
+
+	repo := output.RepositoryResult{Name: "api", FullName: "my-org/api", Actions: []workflow.ActionReference{
+		{Repository: "actions/checkout", Version: "v2", FilePath: "ci.yml", Context: "job:build"},
+		{Repository: "actions/setup-go", Version: "v4.2.0", FilePath: "ci.yml"},
+		{Repository: "actions/checkout", Version: "main", FilePath: "ci.yml"},
+		{Repository: "my-org/shared", WorkflowPath: ".github/workflows/test.yml", Version: "v1", IsReusable: true, FilePath: "ci.yml"},
+		{Repository: "my-org/deploy", Version: "v1", FilePath: "ci.yml"},
+		{Repository: "old/notify", Version: "v3", FilePath: "ci.yml"},
+		{Repository: "acme/lint", Version: "v1", FilePath: "ci.yml"},
+	}}
+
+	decisions := manager.ExplainRepository(repo)
+	if len(decisions) != len(repo.Actions) {
+		t.Fatalf("Expected a decision per action reference, got %+v", decisions)
+	}
+
+	step := func(decision output.Decision, stage string) output.DecisionStep {
+		for _, s := range decision.Steps {
+			if s.Stage == stage {
+				return s
+			}
+		}
+		t.Fatalf("Expected a %s step in %+v", stage, decision)
+		return output.DecisionStep{}
+	}
+
+	checkout := decisions[0]
+	if checkout.Action != "actions/checkout@v2" || checkout.Rule != "actions/checkout" || step(checkout, output.DecisionStageRule).Outcome != output.DecisionMatched {
+		t.Errorf("Expected the checkout rule to match, got %+v", checkout)
+	}
+	if s := step(checkout, CheckOutdated); s.Outcome != output.DecisionRaised {
+		t.Errorf("Expected checkout v2 to be outdated, got %+v", s)
+	}
+	if s := step(checkout, CheckDeprecated); s.Outcome != output.DecisionRaised {
+		t.Errorf("Expected checkout v2 to be deprecated, got %+v", s)
+	}
+
+	if s := step(decisions[1], CheckOutdated); s.Outcome != output.DecisionNotRaised || !strings.Contains(s.Reason, "equivalent to v5") {
+		t.Errorf("Expected setup-go to be equivalent to the latest version, got %+v", s)
+	}
+	if s := step(decisions[2], CheckOutdated); s.Outcome != output.DecisionNotRaised || !strings.Contains(s.Reason, "branch main is exempt") {
+		t.Errorf("Expected the main branch to be exempt, got %+v", s)
+	}
+
+	if s := step(decisions[3], output.DecisionStageRule); s.Outcome != output.DecisionUnmatched || !strings.Contains(s.Reason, "another workflow path") {
+		t.Errorf("Expected the path-specific rule to be skipped, got %+v", s)
+	}
+	if s := step(decisions[4], output.DecisionStageRule); s.Outcome != output.DecisionUnmatched || !strings.Contains(s.Reason, "do not match my-org/api") {
+		t.Errorf("Expected the conditional rule to be skipped, got %+v", s)
+	}
+
+	if s := step(decisions[5], CheckOutdated); s.Outcome != output.DecisionNotRaised || !strings.Contains(s.Reason, "banned") {
+		t.Errorf("Expected version checks to skip the banned action, got %+v", s)
+	}
+	if s := step(decisions[5], CheckBannedAction); s.Outcome != output.DecisionRaised {
+		t.Errorf("Expected the ban to be raised, got %+v", s)
+	}
+
+	if s := step(decisions[6], output.DecisionStageRule); s.Outcome != output.DecisionUnmatched || !strings.Contains(s.Reason, "no rule for acme/lint") {
+		t.Errorf("Expected no rule for acme/lint, got %+v", s)
+	}
+}
@@ -0,0 +1,97 @@
+package actions
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ruleIndex finds the rules for an action repository without scanning every rule
+// Patterns are compiled once when the index is built rather than per action.
+type ruleIndex struct {
+	exact    map[string][]int       // action repository -> rule positions
+	globs    []int                  // positions of rules whose repository is a glob (e.g. "my-org/*")
+	repoGlob map[int]*regexp.Regexp // compiled repository glob per rule position
+	patterns map[int]*regexp.Regexp // compiled condition repository_pattern per rule position
+	invalid  map[int]bool           // rules with an uncompilable pattern, which never match
+}
+
+// isGlobPattern reports whether a rule repository uses glob wildcards
+func isGlobPattern(repository string) bool {
+	return strings.ContainsAny(repository, "*?")
+}
+
+// globToRegexp converts a repository glob to a regex; "*" and "?" do not cross "/"
+func globToRegexp(glob string) (*regexp.Regexp, error) {
+	pattern := regexp.QuoteMeta(glob)
+	pattern = strings.ReplaceAll(pattern, `\*`, `[^/]*`)
+	pattern = strings.ReplaceAll(pattern, `\?`, `[^/]`)
+	return regexp.Compile("^" + pattern + "$")
+}
+
+// newRuleIndex buckets rules by repository and precompiles their patterns
+func newRuleIndex(rules []Rule) *ruleIndex {
+	index := &ruleIndex{
+		exact:    make(map[string][]int),
+		repoGlob: make(map[int]*regexp.Regexp),
+		patterns: make(map[int]*regexp.Regexp),
+		invalid:  make(map[int]bool),
+	}
+
+	for i, rule := range rules {
+		if isGlobPattern(rule.Repository) {
+			re, err := globToRegexp(rule.Repository)
+			if err != nil {
+				index.invalid[i] = true
+				continue
+			}
+			index.repoGlob[i] = re
+			index.globs = append(index.globs, i)
+		} else {
+			index.exact[rule.Repository] = append(index.exact[rule.Repository], i)
+		}
+
+		if rule.Conditions != nil && rule.Conditions.RepositoryPattern != "" {
+			re, err := regexp.Compile(rule.Conditions.RepositoryPattern)
+			if err != nil {
+				index.invalid[i] = true
+				continue
+			}
+			index.patterns[i] = re
+		}
+	}
+
+	return index
+}
+
+// candidates returns the positions of rules whose repository matches, in rule order
+func (idx *ruleIndex) candidates(repository string) []int {
+	positions := idx.exact[repository]
+
+	// Only copy the exact bucket when a glob also matches
+	var merged []int
+	for _, i := range idx.globs {
+		if idx.repoGlob[i].MatchString(repository) {
+			if merged == nil {
+				merged = append([]int(nil), positions...)
+			}
+			merged = append(merged, i)
+		}
+	}
+	if merged == nil {
+		return positions
+	}
+	sort.Ints(merged)
+	return merged
+}
+
+// conditionsMatch evaluates a rule's conditions using its precompiled repository pattern
+func (idx *ruleIndex) conditionsMatch(i int, conditions *RuleConditions, repo RepositoryContext) bool {
+	if conditions == nil {
+		return true
+	}
+	if idx.invalid[i] {
+		return false
+	}
+	return conditions.matches(repo, idx.patterns[i])
+}
@@ -0,0 +1,197 @@
+package actions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version sources of a rule's latest version, looked up by scan --resolve-latest
+const (
+	VersionSourceAuto      = ""           // The latest release, or the highest tag without releases, as its major tag when that tag has moved to it
+	VersionSourceTags      = "tags"       // The highest version tag, e.g. v4.2.1, for actions that tag without publishing releases
+	VersionSourceReleases  = "releases"   // The latest GitHub release, for actions that release without moving major tags
+	VersionSourceMajorTags = "major-tags" // The major tag of the highest version tag, e.g. v4, for actions pinned by major version
+)
+
+// ReleaseLister looks up the latest release of an action repository
+type ReleaseLister interface {
+	GetLatestRelease(owner, repo string) (string, error)
+}
+
+// ValidateVersionSource checks that a rule's version_source is known
+func ValidateVersionSource(source string) error {
+	switch source {
+	case VersionSourceAuto, VersionSourceTags, VersionSourceReleases, VersionSourceMajorTags:
+		return nil
+	}
+	return fmt.Errorf("unknown version_source %q: use %s, %s, or %s", source, VersionSourceTags, VersionSourceReleases, VersionSourceMajorTags)
+}
+
+// LatestResolution is the outcome of looking up the latest version of a rule
+type LatestResolution struct {
+	Repository string
+	Source     string // Where the version came from: tags, releases, or major-tags
+	Previous   string // latest_version of the rules file
+	Version    string // Looked up latest version; empty when the lookup failed
+	Err        error
+}
+
+// ResolveLatestVersions replaces the latest_version of each version rule with the latest version of its
+// action, looked up as its version_source says. Required, ban, brownout, migration, and glob rules keep
+// theirs, as do rules whose lookup fails, which are returned with the error.
+func ResolveLatestVersions(rules []Rule, tags VersionResolver, releases ReleaseLister) []LatestResolution {
+	var resolutions []LatestResolution
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Required != nil || rule.Ban != nil || rule.Brownout != nil || isGlobPattern(rule.Repository) ||
+			rule.MigrateToRepository != "" || rule.MigrateToPath != "" || rule.MigrateToVersion != "" {
+			continue
+		}
+
+		resolution := LatestResolution{Repository: rule.Repository, Previous: rule.LatestVersion}
+		resolution.Version, resolution.Source, resolution.Err = lookupLatestVersion(rule.Repository, rule.VersionSource, tags, releases)
+		if resolution.Err == nil {
+			rule.LatestVersion = resolution.Version
+		}
+		resolutions = append(resolutions, resolution)
+	}
+	return resolutions
+}
+
+// lookupLatestVersion fetches the tags, and the latest release when the source needs it, of an action
+// repository ("owner/name", optionally followed by the path of an action within it)
+func lookupLatestVersion(repository, source string, tags VersionResolver, releases ReleaseLister) (string, string, error) {
+	parts := strings.SplitN(repository, "/", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid repository %q", repository)
+	}
+	owner, name := parts[0], parts[1]
+
+	tagCommits, err := tags.GetTagsWithCache(owner, name)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	release := ""
+	if source == VersionSourceAuto || source == VersionSourceReleases {
+		if release, err = releases.GetLatestRelease(owner, name); err != nil {
+			return "", "", fmt.Errorf("failed to get the latest release: %w", err)
+		}
+	}
+	return PickLatestVersion(source, tagCommits, release)
+}
+
+// PickLatestVersion picks the latest version from the tags of an action, mapped to their commits, and
+// its latest release tag ("" without releases), returning the version and the source it came from
+func PickLatestVersion(source string, tagCommits map[string]string, release string) (string, string, error) {
+	names := make([]string, 0, len(tagCommits))
+	for tag := range tagCommits {
+		names = append(names, tag)
+	}
+	highest := HighestVersion(names)
+
+	switch source {
+	case VersionSourceTags:
+		if highest == "" {
+			return "", "", fmt.Errorf("no version tags")
+		}
+		return highest, VersionSourceTags, nil
+	case VersionSourceReleases:
+		if release == "" {
+			return "", "", fmt.Errorf("no releases")
+		}
+		return release, VersionSourceReleases, nil
+	case VersionSourceMajorTags:
+		if highest == "" {
+			return "", "", fmt.Errorf("no version tags")
+		}
+		major := MajorTag(highest)
+		if _, ok := tagCommits[major]; !ok {
+			return "", "", fmt.Errorf("no major tag %s for %s", major, highest)
+		}
+		return major, VersionSourceMajorTags, nil
+	}
+
+	// Releases are deliberate, so they win over tags, but workflows pin major tags where they are maintained
+	latest, used := release, VersionSourceReleases
+	if latest == "" {
+		latest, used = highest, VersionSourceTags
+	}
+	if latest == "" {
+		return "", "", fmt.Errorf("no releases or version tags")
+	}
+	major := MajorTag(latest)
+	if commit, ok := tagCommits[major]; ok && major != latest && commit != "" && commit == tagCommits[latest] {
+		return major, VersionSourceMajorTags, nil
+	}
+	return latest, used, nil
+}
+
+// HighestVersion returns the highest version among tags such as v4, v4.2, or 4.2.1, preferring the more
+// specific tag of equal versions. Branches, SHAs, and pre-releases are ignored, and "" is returned when
+// no tag is a version.
+func HighestVersion(tags []string) string {
+	var highest string
+	var highestParts []int
+	for _, tag := range tags {
+		parts, ok := versionParts(tag)
+		if !ok {
+			continue
+		}
+		if highestParts == nil || compareParts(parts, highestParts) > 0 || compareParts(parts, highestParts) == 0 && len(tag) > len(highest) {
+			highest, highestParts = tag, parts
+		}
+	}
+	return highest
+}
+
+// MajorTag returns the major version tag of a version, e.g. v4 for v4.2.1, or "" for non-versions
+func MajorTag(version string) string {
+	parts, ok := versionParts(version)
+	if !ok {
+		return ""
+	}
+	major := strconv.Itoa(parts[0])
+	if strings.HasPrefix(version, "v") {
+		major = "v" + major
+	}
+	return major
+}
+
+// versionParts parses a version tag such as v4, v4.2, or 4.2.1 into its numbers
+func versionParts(tag string) ([]int, bool) {
+	fields := strings.Split(strings.TrimPrefix(tag, "v"), ".")
+	if len(fields) > 3 {
+		return nil, false
+	}
+	parts := make([]int, 0, len(fields))
+	for _, field := range fields {
+		number, err := strconv.Atoi(field)
+		if err != nil || number < 0 {
+			return nil, false
+		}
+		parts = append(parts, number)
+	}
+	return parts, true
+}
+
+// compareParts compares version numbers, treating missing numbers as zero
+func compareParts(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x > y {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,105 @@
+package actions
+
+import (
+	"fmt"
+	"testing"
+)
+
+// mockReleaseLister implements ReleaseLister for testing
+type mockReleaseLister map[string]string // maps "owner/repo" to its latest release tag
+
+func (m mockReleaseLister) GetLatestRelease(owner, repo string) (string, error) {
+	release, ok := m[owner+"/"+repo]
+	if !ok {
+		return "", fmt.Errorf("no releases for %s/%s", owner, repo)
+	}
+	return release, nil
+}
+
+func TestPickLatestVersion(t *testing.T) {
+	moved := map[string]string{"v3": "c3", "v3.9.0": "c3", "v4": "c4", "v4.1.0": "c40", "v4.2.1": "c4", "main": "c5"}
+	stale := map[string]string{"v1": "c1", "v1.0.0": "c1", "v2.0.0": "c2", "v2.1.0": "c21"}
+
+	tests := []struct {
+		name       string
+		source     string
+		tags       map[string]string
+		release    string
+		wantVer    string
+		wantSource string
+		wantErr    bool
+	}{
+		{"tags takes the highest version tag", VersionSourceTags, moved, "v4.1.0", "v4.2.1", VersionSourceTags, false},
+		{"releases takes the latest release", VersionSourceReleases, moved, "v4.1.0", "v4.1.0", VersionSourceReleases, false},
+		{"releases fails without releases", VersionSourceReleases, moved, "", "", "", true},
+		{"major-tags takes the major tag", VersionSourceMajorTags, moved, "", "v4", VersionSourceMajorTags, false},
+		{"major-tags fails without the major tag", VersionSourceMajorTags, stale, "", "", "", true},
+		{"auto prefers a moved major tag", VersionSourceAuto, moved, "v4.2.1", "v4", VersionSourceMajorTags, false},
+		{"auto keeps a release the major tag lags", VersionSourceAuto, stale, "v2.1.0", "v2.1.0", VersionSourceReleases, false},
+		{"auto falls back to tags without releases", VersionSourceAuto, stale, "", "v2.1.0", VersionSourceTags, false},
+		{"auto fails without versions", VersionSourceAuto, map[string]string{"main": "c1"}, "", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, source, err := PickLatestVersion(tt.source, tt.tags, tt.release)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expected an error, got version %s", version)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if version != tt.wantVer || source != tt.wantSource {
+				t.Errorf("Expected %s from %s, got %s from %s", tt.wantVer, tt.wantSource, version, source)
+			}
+		})
+	}
+}
+
+func TestResolveLatestVersions(t *testing.T) {
+	resolver := NewMockVersionResolver()
+	resolver.SetTags("actions", "checkout", map[string]string{"v4": "c4", "v4.2.2": "c4"})
+	resolver.SetTags("my-org", "deploy", map[string]string{"v1.0.0": "c1", "v1.1.0": "c2"})
+	releases := mockReleaseLister{"actions/checkout": "v4.2.2", "my-org/deploy": ""}
+
+	rules := []Rule{
+		{Repository: "actions/checkout", LatestVersion: "v3"},
+		{Repository: "my-org/deploy/.github/workflows/deploy.yml", LatestVersion: "v1.0.0", VersionSource: VersionSourceTags},
+		{Repository: "my-org/missing", LatestVersion: "v2"},
+		{Repository: "my-org/*", LatestVersion: "v1"},
+		{Repository: "old-org/action", LatestVersion: "v1", MigrateToRepository: "new-org/action"},
+		{Repository: "bad/action", Ban: &Ban{}},
+	}
+
+	resolutions := ResolveLatestVersions(rules, resolver, releases)
+	if len(resolutions) != 3 {
+		t.Fatalf("Expected 3 resolutions, got %d: %+v", len(resolutions), resolutions)
+	}
+
+	if rules[0].LatestVersion != "v4" || resolutions[0].Source != VersionSourceMajorTags || resolutions[0].Previous != "v3" {
+		t.Errorf("Expected actions/checkout to resolve to v4 from major-tags, got %s from %s", rules[0].LatestVersion, resolutions[0].Source)
+	}
+	if rules[1].LatestVersion != "v1.1.0" {
+		t.Errorf("Expected the reusable workflow to resolve to v1.1.0, got %s", rules[1].LatestVersion)
+	}
+	if resolutions[2].Err == nil || rules[2].LatestVersion != "v2" {
+		t.Errorf("Expected the failed lookup to keep v2 and report an error, got %s and %v", rules[2].LatestVersion, resolutions[2].Err)
+	}
+	if rules[3].LatestVersion != "v1" || rules[4].LatestVersion != "v1" {
+		t.Errorf("Expected glob and migration rules to be left alone, got %s and %s", rules[3].LatestVersion, rules[4].LatestVersion)
+	}
+}
+
+func TestValidateVersionSource(t *testing.T) {
+	for _, source := range []string{"", "tags", "releases", "major-tags"} {
+		if err := ValidateVersionSource(source); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", source, err)
+		}
+	}
+	if err := ValidateVersionSource("marketplace"); err == nil {
+		t.Error("Expected an unknown version source to be rejected")
+	}
+}
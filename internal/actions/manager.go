@@ -2,9 +2,15 @@ package actions
 
 import (
 	"fmt"
+	"io"
 	"log"
+	"runtime"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/logcapture"
 	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
 	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/patcher"
 	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
@@ -13,14 +19,45 @@ import (
 // Config holds configuration options for the actions manager
 type Config struct {
 	Verbose bool
+	Workers int // Repositories analyzed concurrently by AnalyzeRepositories (default: GOMAXPROCS)
+
+	// PatchPreview embeds the concrete patch of each transformed upgrade in its issue
+	PatchPreview bool
+
+	// Checks names the registered checks to run (see RegisteredChecks); empty runs every check
+	Checks []string
+
+	// SkipResolution compares versions as strings only, so refs are not resolved to find broken references
+	SkipResolution bool
+
+	// WorkflowFilter is the scan's workflow file filter: local reusable workflow calls to files it skips
+	// are not checked, since those files are not in the results (nil = every file was scanned)
+	WorkflowFilter *github.WorkflowFilter
+
+	// DisableDefaultRules names action repositories whose built-in rules are turned off (see
+	// DefaultRuleRepositories), keeping the rest
+	DisableDefaultRules []string
 }
 
 // Manager handles action version management and issue detection
 type Manager struct {
-	rules    []Rule
-	patcher  *patcher.WorkflowPatcher
-	resolver VersionResolver // Interface for version resolution
-	verbose  bool
+	rules     []Rule
+	index     *ruleIndex
+	required  []requiredRule   // Rules requiring an action to be present, checked by CheckRequiredActions
+	brownouts brownoutCalendar // Announced removals by action repository; nil uses DefaultBrownouts
+	checks    []Check          // Enabled checks, in registration order
+	patcher   *patcher.WorkflowPatcher
+	resolver  VersionResolver // Interface for version resolution
+	verbose   bool
+	workers   int
+
+	patchPreview   bool // Embed concrete patches in issues (Config.PatchPreview)
+	skipResolution bool // Refs are not resolved (Config.SkipResolution)
+	workflowFilter *github.WorkflowFilter
+
+	logger *log.Logger // Destination of rule evaluation logs; nil for the standard logger
+
+	now func() time.Time // Clock for brownout deadlines; nil for time.Now
 }
 
 // VersionResolver interface for resolving version aliases
@@ -28,6 +65,7 @@ type VersionResolver interface {
 	AreVersionsEquivalent(repository, version1, version2 string) (bool, error)
 	IsVersionOutdated(repository, currentVersion, latestVersion string) (bool, error)
 	ResolveRefWithCache(owner, repo, ref string) (string, error)
+	GetTagsWithCache(owner, repo string) (map[string]string, error)
 }
 
 // Rule defines a version enforcement rule for actions
@@ -44,13 +82,42 @@ type Rule struct {
 	// Migration support: for actions that have moved to a new repository
 	MigrateToRepository string `json:"migrate_to_repository,omitempty"`
 	MigrateToVersion    string `json:"migrate_to_version,omitempty"`
-	MigrateToPath       string `json:"migrate_to_path,omitempty"` // Target path for migrations
+	MigrateToPath       string `json:"migrate_to_path,omitempty"` // Target path for migrations; alone, renames the path within the repository
+
+	// Conditions restrict the rule to matching repositories (nil applies everywhere)
+	Conditions *RuleConditions `json:"conditions,omitempty"`
+
+	// Owners are asked to review pull requests updating the action: GitHub users, or teams as "org/team"
+	Owners []string `json:"owners,omitempty"`
+
+	// Required rules report workflows or jobs missing the action instead of checking its version
+	Required *Requirement `json:"required,omitempty"`
+
+	// Ban rules report every use of the action, optionally removing or replacing it
+	Ban *Ban `json:"ban,omitempty"`
+
+	// Brownout rules add to or replace the built-in calendar of announced removals
+	Brownout *Brownout `json:"brownout,omitempty"`
+
+	// Files outside .github/workflows edited in the same pull request as the action's updates
+	Files []output.FileEdit `json:"files,omitempty"`
+
+	// CommitMessage is a Go template or preset ("conventional", "conventional-ci", "title") for the commit of the action's updates
+	CommitMessage string `json:"commit_message,omitempty"`
+
+	// VersionSource is where scan --resolve-latest looks up latest_version: "tags", "releases", or "major-tags" (empty picks by heuristics)
+	VersionSource string `json:"version_source,omitempty"`
+
+	// ReportOnly rules are reported but never turned into pull request updates, e.g. migrations needing human judgement
+	ReportOnly bool `json:"report_only,omitempty"`
 }
 
 // NewManager creates a new actions manager with no default rules
 func NewManager() *Manager {
 	return &Manager{
 		rules:   []Rule{},
+		index:   newRuleIndex(nil),
+		checks:  enabledChecks(nil),
 		patcher: patcher.NewWorkflowPatcher(),
 		verbose: false,
 	}
@@ -60,6 +127,8 @@ func NewManager() *Manager {
 func NewManagerWithResolver(resolver VersionResolver) *Manager {
 	return &Manager{
 		rules:    []Rule{},
+		index:    newRuleIndex(nil),
+		checks:   enabledChecks(nil),
 		patcher:  patcher.NewWorkflowPatcher(),
 		resolver: resolver,
 		verbose:  false,
@@ -77,9 +146,16 @@ func NewManagerWithConfig(config *Config) *Manager {
 	}
 
 	return &Manager{
-		rules:   []Rule{},
-		patcher: patcher.NewWorkflowPatcher(),
-		verbose: config.Verbose,
+		rules:          []Rule{},
+		index:          newRuleIndex(nil),
+		brownouts:      newBrownoutCalendar(enabledDefaults(DefaultBrownouts, config.DisableDefaultRules), nil),
+		checks:         enabledChecks(config.Checks),
+		patcher:        patcher.NewWorkflowPatcher(),
+		verbose:        config.Verbose,
+		workers:        config.Workers,
+		patchPreview:   config.PatchPreview,
+		skipResolution: config.SkipResolution,
+		workflowFilter: config.WorkflowFilter,
 	}
 }
 
@@ -94,10 +170,17 @@ func NewManagerWithResolverAndConfig(resolver VersionResolver, config *Config) *
 	}
 
 	return &Manager{
-		rules:    []Rule{},
-		patcher:  patcher.NewWorkflowPatcher(),
-		resolver: resolver,
-		verbose:  config.Verbose,
+		rules:          []Rule{},
+		index:          newRuleIndex(nil),
+		brownouts:      newBrownoutCalendar(enabledDefaults(DefaultBrownouts, config.DisableDefaultRules), nil),
+		checks:         enabledChecks(config.Checks),
+		patcher:        patcher.NewWorkflowPatcher(),
+		resolver:       resolver,
+		verbose:        config.Verbose,
+		workers:        config.Workers,
+		patchPreview:   config.PatchPreview,
+		skipResolution: config.SkipResolution,
+		workflowFilter: config.WorkflowFilter,
 	}
 }
 
@@ -107,253 +190,449 @@ func NewManagerWithResolverConfigAndRules(resolver VersionResolver, config *Conf
 		config = &Config{Verbose: false}
 	}
 
-	// Use only custom rules - no default rules
-	rules := customRules
-	if rules == nil {
-		rules = []Rule{}
+	// Use only custom rules - no default rules; required and brownout rules are kept apart from version rules
+	rules := []Rule{}
+	for _, rule := range customRules {
+		if rule.Required == nil && rule.Brownout == nil {
+			rules = append(rules, rule)
+		}
 	}
+	required := newRequiredRules(customRules)
 
 	if config.Verbose {
 		log.Printf("Actions manager initialized with version resolver, custom rules, and verbose logging enabled")
-		log.Printf("Using %d custom rules (no default rules)", len(rules))
+		log.Printf("Using %d custom rules (no default rules)", len(rules)+len(required))
 	}
 
 	return &Manager{
-		rules:    rules,
-		patcher:  patcher.NewWorkflowPatcher(),
-		resolver: resolver,
-		verbose:  config.Verbose,
+		rules:          rules,
+		index:          newRuleIndex(rules),
+		required:       required,
+		brownouts:      newBrownoutCalendar(enabledDefaults(DefaultBrownouts, config.DisableDefaultRules), customRules),
+		checks:         enabledChecks(config.Checks),
+		patcher:        patcher.NewWorkflowPatcher(),
+		resolver:       resolver,
+		verbose:        config.Verbose,
+		workers:        config.Workers,
+		patchPreview:   config.PatchPreview,
+		skipResolution: config.SkipResolution,
+		workflowFilter: config.WorkflowFilter,
+	}
+}
+
+// AnalyzeRepository analyzes the actions of a scanned repository, evaluating rule conditions against its metadata
+func (m *Manager) AnalyzeRepository(repo output.RepositoryResult) []output.ActionIssue {
+	return m.AnalyzeActionsForRepository(repositoryContextOf(repo), repo.Actions)
+}
+
+// RuleFor returns the rule matching an action of a scanned repository, or nil when no rule matches
+func (m *Manager) RuleFor(repo output.RepositoryResult, action workflow.ActionReference) *Rule {
+	return m.findRuleForAction(repositoryContextOf(repo), action)
+}
+
+// repositoryContextOf returns the metadata of a scanned repository that rule conditions are evaluated against
+func repositoryContextOf(repo output.RepositoryResult) RepositoryContext {
+	return RepositoryContext{
+		Name:             repo.Name,
+		FullName:         repo.FullName,
+		DefaultBranch:    repo.DefaultBranch,
+		CustomProperties: repo.CustomProperties,
+		Topics:           repo.Topics,
+	}
+}
+
+// AnalyzeRepositories analyzes several repositories concurrently, returning their issues in input order
+func (m *Manager) AnalyzeRepositories(repos []output.RepositoryResult) [][]output.ActionIssue {
+	results := make([][]output.ActionIssue, len(repos))
+	m.analyzeConcurrently(len(repos), func(i int) {
+		results[i] = m.AnalyzeRepository(repos[i])
+	})
+	return results
+}
+
+// AnalyzeRepositoriesWithLogs analyzes repositories concurrently as AnalyzeRepositories does, also returning
+// the lines logged while analyzing each one. Every repository gets its own logger, so lines from repositories
+// analyzed at the same time are never mixed up; they are also passed through to out (which may be nil).
+func (m *Manager) AnalyzeRepositoriesWithLogs(repos []output.RepositoryResult, out io.Writer) ([][]output.ActionIssue, [][]string) {
+	results := make([][]output.ActionIssue, len(repos))
+	logs := make([][]string, len(repos))
+	m.analyzeConcurrently(len(repos), func(i int) {
+		recorder := logcapture.NewRecorder(out)
+		recorder.Start()
+		results[i] = m.WithLogger(log.New(recorder, log.Prefix(), log.Flags())).AnalyzeRepository(repos[i])
+		logs[i] = recorder.Stop()
+	})
+	return results, logs
+}
+
+// analyzeConcurrently calls analyze for each of n repository positions on the configured number of workers
+func (m *Manager) analyzeConcurrently(n int, analyze func(i int)) {
+	workers := m.workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > n {
+		workers = n
+	}
+
+	if m.verbose {
+		m.logf("Rule evaluation: Analyzing %d repositories with %d workers", n, workers)
+	}
+
+	positions := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range positions {
+				analyze(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		positions <- i
 	}
+	close(positions)
+	wg.Wait()
+}
+
+// WithLogger returns a manager sharing this one's rules and resolver that writes its logs to logger
+func (m *Manager) WithLogger(logger *log.Logger) *Manager {
+	copied := *m
+	copied.logger = logger
+	return &copied
+}
+
+// clock returns the current time
+func (m *Manager) clock() time.Time {
+	if m.now == nil {
+		return time.Now()
+	}
+	return m.now()
+}
+
+// logf logs a rule evaluation message to the manager's logger
+func (m *Manager) logf(format string, args ...interface{}) {
+	if m.logger == nil {
+		log.Printf(format, args...)
+		return
+	}
+	m.logger.Printf(format, args...)
 }
 
 // AnalyzeActions analyzes action references and identifies issues
+// Rules with conditions never apply, as there is no repository to evaluate them against.
 func (m *Manager) AnalyzeActions(actions []workflow.ActionReference) []output.ActionIssue {
+	return m.AnalyzeActionsForRepository(RepositoryContext{}, actions)
+}
+
+// AnalyzeActionsForRepository analyzes the action references of one repository, applying rules whose conditions it matches
+func (m *Manager) AnalyzeActionsForRepository(repo RepositoryContext, actions []workflow.ActionReference) []output.ActionIssue {
 	if m.verbose {
-		log.Printf("Rule evaluation: Starting analysis of %d action references", len(actions))
+		m.logf("Rule evaluation: Starting analysis of %d action references", len(actions))
 	}
 
 	var issues []output.ActionIssue
 
 	for i, action := range actions {
+		// Local reusable workflows are versioned with the repository calling them
+		if action.Local {
+			if m.verbose {
+				m.logf("Rule evaluation: Skipping local reusable workflow %s (context: %s)", action.WorkflowPath, action.Context)
+			}
+			continue
+		}
 		if m.verbose {
 			actionType := "action"
 			if action.IsReusable {
 				actionType = "reusable workflow"
 			}
-			log.Printf("Rule evaluation: Analyzing %s %d/%d - %s@%s (context: %s)", actionType, i+1, len(actions), action.Repository, action.Version, action.Context)
+			m.logf("Rule evaluation: Analyzing %s %d/%d - %s@%s (context: %s)", actionType, i+1, len(actions), action.Repository, action.Version, action.Context)
 		}
 
-		actionIssues := m.analyzeAction(action)
+		actionIssues := m.analyzeAction(repo, action)
 		issues = append(issues, actionIssues...)
 
 		if m.verbose {
-			log.Printf("Rule evaluation: Found %d issues for %s@%s", len(actionIssues), action.Repository, action.Version)
+			m.logf("Rule evaluation: Found %d issues for %s@%s", len(actionIssues), action.Repository, action.Version)
 		}
 	}
 
 	if m.verbose {
-		log.Printf("Rule evaluation: Completed analysis, found %d total issues", len(issues))
+		m.logf("Rule evaluation: Completed analysis, found %d total issues", len(issues))
 	}
 
 	return issues
 }
 
-// analyzeAction analyzes a single action reference for issues
-func (m *Manager) analyzeAction(action workflow.ActionReference) []output.ActionIssue {
-	var issues []output.ActionIssue
-
-	rule := m.findRuleForAction(action)
-	if rule == nil {
-		if m.verbose {
+// analyzeAction runs the enabled action checks on a single action reference
+func (m *Manager) analyzeAction(repo RepositoryContext, action workflow.ActionReference) []output.ActionIssue {
+	rule := m.findRuleForAction(repo, action)
+	if m.verbose {
+		if rule == nil {
 			pathInfo := ""
 			if action.WorkflowPath != "" {
 				pathInfo = fmt.Sprintf(" (path: %s)", action.WorkflowPath)
 			}
-			log.Printf("Rule evaluation: No rules found for repository %s%s, skipping analysis", action.Repository, pathInfo)
+			m.logf("Rule evaluation: No rules found for repository %s%s, running rule-independent checks only", action.Repository, pathInfo)
+		} else {
+			pathInfo := ""
+			if rule.WorkflowPath != "" {
+				pathInfo = fmt.Sprintf(" (path: %s)", rule.WorkflowPath)
+			}
+			m.logf("Rule evaluation: Found rule for %s%s - latest: %s, minimum: %s, deprecated: %v", action.Repository, pathInfo, rule.LatestVersion, rule.MinimumVersion, rule.DeprecatedVersions)
 		}
-		return issues // No rules for this action
 	}
 
-	if m.verbose {
-		pathInfo := ""
-		if rule.WorkflowPath != "" {
-			pathInfo = fmt.Sprintf(" (path: %s)", rule.WorkflowPath)
+	var issues []output.ActionIssue
+	for _, check := range m.checks {
+		if actionCheck, ok := check.(ActionCheck); ok {
+			issues = append(issues, actionCheck.CheckAction(m, action, rule)...)
 		}
-		log.Printf("Rule evaluation: Found rule for %s%s - latest: %s, minimum: %s, deprecated: %v", action.Repository, pathInfo, rule.LatestVersion, rule.MinimumVersion, rule.DeprecatedVersions)
 	}
+	return issues
+}
 
-	// Check for outdated versions
-	if m.isOutdatedForRepository(action.Repository, action.Version, rule.LatestVersion) {
-		if m.verbose {
-			log.Printf("Rule evaluation: Version %s is outdated for %s (latest: %s)", action.Version, action.Repository, rule.LatestVersion)
-		}
+// addTransformations records the schema changes of upgrading an action to a version, with a patch preview
+func (m *Manager) addTransformations(issue *output.ActionIssue, action workflow.ActionReference, targetVersion, targetRepository string) {
+	patchInfo, hasPatches := m.GetTransformationInfo(action.Repository, action.Version, targetVersion)
+	if !hasPatches {
+		return
+	}
+	issue.HasTransformations = true
+	issue.SchemaChanges = []string{patchInfo.Description}
+	issue.PatchPreview = m.previewPatch(action, targetVersion, targetRepository)
 
-		// Suggest version in the same format as current version (like for like)
-		suggestedVersion := m.suggestLikeForLikeVersion(action.Repository, action.Version, rule.LatestVersion)
+	if m.verbose {
+		m.logf("Rule evaluation: Found schema transformations for %s (%s -> %s)", action.Repository, action.Version, targetVersion)
+	}
 
-		if m.verbose {
-			log.Printf("Rule evaluation: Suggested version for %s: %s -> %s", action.Repository, action.Version, suggestedVersion)
-		}
+	// Add details about specific field changes
+	for _, patch := range patchInfo.Patches {
+		change := fmt.Sprintf("%s: %s", patch.Operation, patch.Reason)
+		issue.SchemaChanges = append(issue.SchemaChanges, change)
+	}
+}
 
-		issue := output.ActionIssue{
-			Repository:       action.Repository,
-			CurrentVersion:   action.Version,
-			SuggestedVersion: suggestedVersion,
-			IssueType:        "outdated",
-			Severity:         m.determineSeverity(action.Version, rule),
-			Description:      fmt.Sprintf("Action %s is using version %s, latest is %s", action.Repository, action.Version, rule.LatestVersion),
-			Context:          action.Context,
-			FilePath:         action.FilePath,
+// annotateRuleIssues records the rule's conditions, owners, file edits, and report-only flag on the issues it raised
+func annotateRuleIssues(issues []output.ActionIssue, rule *Rule) {
+	// Record why this repository got a repository-specific policy
+	if rule.Conditions != nil {
+		for i := range issues {
+			issues[i].RuleConditions = rule.Conditions.String()
 		}
+	}
 
-		if m.verbose {
-			log.Printf("Rule evaluation: Created outdated issue for %s with severity %s", action.Repository, issue.Severity)
+	// Carry the action's owners through to pull request reviewer assignment
+	if len(rule.Owners) > 0 {
+		for i := range issues {
+			issues[i].Owners = rule.Owners
 		}
+	}
 
-		// Check if there are schema transformations for this version upgrade
-		if patchInfo, hasPatches := m.GetTransformationInfo(action.Repository, action.Version, rule.LatestVersion); hasPatches {
-			issue.HasTransformations = true
-			issue.SchemaChanges = []string{patchInfo.Description}
+	// Commit message templates follow the issue to create-pr, which renders them per pull request
+	if rule.CommitMessage != "" {
+		for i := range issues {
+			issues[i].CommitMessage = rule.CommitMessage
+		}
+	}
 
-			if m.verbose {
-				log.Printf("Rule evaluation: Found schema transformations for %s (%s -> %s)", action.Repository, action.Version, rule.LatestVersion)
-			}
+	// Coordinated edits follow the issue to create-pr, which makes them once per pull request
+	if len(rule.Files) > 0 {
+		for i := range issues {
+			issues[i].FileEdits = rule.Files
+		}
+	}
 
-			// Add details about specific field changes
-			for _, patch := range patchInfo.Patches {
-				change := fmt.Sprintf("%s: %s", patch.Operation, patch.Reason)
-				issue.SchemaChanges = append(issue.SchemaChanges, change)
-			}
+	// Report-only issues stay in reports but are left out of create-pr's update plans
+	if rule.ReportOnly {
+		for i := range issues {
+			issues[i].ReportOnly = true
 		}
+	}
+}
 
-		issues = append(issues, issue)
+// checkCommentDrift flags pinned actions whose trailing version comment no longer matches the pinned ref
+// For SHA pins the comment's tag is resolved and compared with the SHA; for tag pins the two tags are compared.
+func (m *Manager) checkCommentDrift(action workflow.ActionReference) *output.ActionIssue {
+	commentVersion := workflow.PinCommentVersion(action.PinComment)
+	if commentVersion == "" {
+		return nil
 	}
 
-	// Check for deprecated versions
-	for _, deprecatedVersion := range rule.DeprecatedVersions {
-		if action.Version == deprecatedVersion {
+	var description string
+	switch m.detectVersionFormat(action.Version) {
+	case VersionFormatSHA:
+		if m.resolver == nil {
+			return nil
+		}
+		parts := strings.Split(action.Repository, "/")
+		if len(parts) != 2 {
+			return nil
+		}
+		sha, err := m.resolver.ResolveRefWithCache(parts[0], parts[1], commentVersion)
+		if err != nil {
 			if m.verbose {
-				log.Printf("Rule evaluation: Version %s is deprecated for %s", action.Version, action.Repository)
-			}
-
-			// Suggest version in the same format as current version (like for like)
-			suggestedVersion := m.suggestLikeForLikeVersion(action.Repository, action.Version, rule.LatestVersion)
-
-			issue := output.ActionIssue{
-				Repository:       action.Repository,
-				CurrentVersion:   action.Version,
-				SuggestedVersion: suggestedVersion,
-				IssueType:        "deprecated",
-				Severity:         "high",
-				Description:      fmt.Sprintf("Action %s version %s is deprecated", action.Repository, action.Version),
-				Context:          action.Context,
-				FilePath:         action.FilePath,
+				m.logf("Rule evaluation: Unable to resolve pin comment %s for %s: %v", commentVersion, action.Repository, err)
 			}
+			return nil
+		}
+		if shaMatches(action.Version, sha) {
+			return nil
+		}
+		if m.isTagMoved(parts[0], parts[1], action.Version, commentVersion) {
+			return m.tagMovedIssue(action, commentVersion, sha)
+		}
+		description = fmt.Sprintf("Action %s is pinned to %s but its comment says %s, which resolves to %s", action.Repository, action.Version, commentVersion, sha)
+	case VersionFormatTag:
+		if tagsConsistent(action.Version, commentVersion) {
+			return nil
+		}
+		description = fmt.Sprintf("Action %s is pinned to %s but its comment says %s", action.Repository, action.Version, commentVersion)
+	default:
+		return nil
+	}
 
-			// Check if there are schema transformations for this version upgrade
-			if patchInfo, hasPatches := m.GetTransformationInfo(action.Repository, action.Version, rule.LatestVersion); hasPatches {
-				issue.HasTransformations = true
-				issue.SchemaChanges = []string{patchInfo.Description}
+	if m.verbose {
+		m.logf("Rule evaluation: Comment drift detected for %s@%s (comment: %s)", action.Repository, action.Version, action.PinComment)
+	}
 
-				// Add details about specific field changes
-				for _, patch := range patchInfo.Patches {
-					change := fmt.Sprintf("%s: %s", patch.Operation, patch.Reason)
-					issue.SchemaChanges = append(issue.SchemaChanges, change)
-				}
-			}
+	return &output.ActionIssue{
+		Repository:     action.Repository,
+		CurrentVersion: action.Version,
+		IssueType:      "comment-drift",
+		Severity:       "medium",
+		Description:    description,
+		Context:        action.Context,
+		FilePath:       action.FilePath,
+		PinComment:     action.PinComment,
+	}
+}
 
-			issues = append(issues, issue)
-		}
+// isTagMoved reports whether an exact release tag was force-moved away from the pinned SHA
+// A SHA still carried by another tag means the comment is merely stale, so only SHAs no
+// tag points at any more are treated as moved. Floating tags like v4 move by design.
+func (m *Manager) isTagMoved(owner, repo, pinnedSHA, tag string) bool {
+	if !isExactReleaseTag(tag) {
+		return false
 	}
 
-	// Check for repository migrations
-	if rule.MigrateToRepository != "" && rule.MigrateToVersion != "" {
+	tags, err := m.resolver.GetTagsWithCache(owner, repo)
+	if err != nil {
 		if m.verbose {
-			pathInfo := ""
-			if action.WorkflowPath != "" {
-				pathInfo = fmt.Sprintf(" (path: %s)", action.WorkflowPath)
-			}
-			log.Printf("Rule evaluation: Repository %s%s should migrate to %s@%s", action.Repository, pathInfo, rule.MigrateToRepository, rule.MigrateToVersion)
-		}
-
-		// Build migration target with path if specified
-		migrationTarget := rule.MigrateToRepository
-		if rule.MigrateToPath != "" {
-			migrationTarget += "/" + rule.MigrateToPath
+			m.logf("Rule evaluation: Unable to list tags for %s/%s: %v", owner, repo, err)
 		}
-		migrationTarget += "@" + rule.MigrateToVersion
+		return false
+	}
+	if _, exists := tags[tag]; !exists {
+		return false
+	}
 
-		description := fmt.Sprintf("Action %s has migrated to %s", action.Repository, rule.MigrateToRepository)
-		if rule.Recommendation != "" {
-			description = rule.Recommendation
+	for _, sha := range tags {
+		if shaMatches(pinnedSHA, sha) {
+			return false
 		}
+	}
+	return true
+}
 
-		issue := output.ActionIssue{
-			Repository:      action.Repository,
-			CurrentVersion:  action.Version,
-			MigrationTarget: migrationTarget,
-			IssueType:       "migration",
-			Severity:        "medium",
-			Description:     description,
-			Context:         action.Context,
-			FilePath:        action.FilePath,
-		}
+// tagMovedIssue reports a SHA pin whose recorded tag now points at a different commit
+func (m *Manager) tagMovedIssue(action workflow.ActionReference, tag, currentSHA string) *output.ActionIssue {
+	if m.verbose {
+		m.logf("Rule evaluation: Tag %s of %s was moved from %s to %s", tag, action.Repository, action.Version, currentSHA)
+	}
 
-		// Check if there are schema transformations for this migration
-		if patchInfo, hasPatches := m.GetTransformationInfo(action.Repository, action.Version, rule.MigrateToVersion); hasPatches {
-			issue.HasTransformations = true
-			issue.SchemaChanges = []string{patchInfo.Description}
+	return &output.ActionIssue{
+		Repository:     action.Repository,
+		CurrentVersion: action.Version,
+		IssueType:      "tag-moved",
+		Severity:       "high",
+		Description:    fmt.Sprintf("Tag %s of %s was force-moved upstream: it was pinned at %s but now points to %s; review the new commit before updating", tag, action.Repository, action.Version, currentSHA),
+		Context:        action.Context,
+		FilePath:       action.FilePath,
+		PinComment:     action.PinComment,
+	}
+}
 
-			// Add details about specific field changes
-			for _, patch := range patchInfo.Patches {
-				change := fmt.Sprintf("%s: %s", patch.Operation, patch.Reason)
-				issue.SchemaChanges = append(issue.SchemaChanges, change)
-			}
+// isExactReleaseTag reports whether a tag names a full version such as v4.1.1 rather than a floating major or minor tag
+func isExactReleaseTag(tag string) bool {
+	parts := strings.Split(strings.TrimPrefix(tag, "v"), ".")
+	if len(parts) != 3 {
+		return false
+	}
+	for _, part := range parts {
+		if part == "" || strings.Trim(part, "0123456789") != "" {
+			return false
 		}
+	}
+	return true
+}
 
-		issues = append(issues, issue)
+// suggestPinComment returns the tag to record in a pin comment when the suggested version is a SHA
+func (m *Manager) suggestPinComment(suggestedVersion, latestTagVersion string) string {
+	if suggestedVersion != latestTagVersion && m.detectVersionFormat(suggestedVersion) == VersionFormatSHA {
+		return latestTagVersion
+	}
+	return ""
+}
 
-		if m.verbose {
-			log.Printf("Rule evaluation: Created migration issue for %s -> %s with severity %s", action.Repository, migrationTarget, issue.Severity)
-		}
+// shaMatches reports whether two SHAs refer to the same commit, allowing for abbreviated SHAs
+func shaMatches(a, b string) bool {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	if len(a) > len(b) {
+		a, b = b, a
 	}
+	return a != "" && strings.HasPrefix(b, a)
+}
 
-	return issues
+// tagsConsistent reports whether two tags agree, treating "v4" and "v4.1.1" as consistent
+func tagsConsistent(a, b string) bool {
+	a, b = strings.TrimPrefix(a, "v"), strings.TrimPrefix(b, "v")
+	return a == b || strings.HasPrefix(a, b+".") || strings.HasPrefix(b, a+".")
 }
 
-// findRuleForAction finds a rule for the given action, considering both repository and workflow path
-func (m *Manager) findRuleForAction(action workflow.ActionReference) *Rule {
-	var matchingRule *Rule
+// findRuleForAction finds a rule for the given action, considering the workflow path and the repository's conditions
+// An exact path match beats a generic rule, an exact repository beats a glob, and a conditional rule beats an
+// unconditional one; the first rule wins ties.
+func (m *Manager) findRuleForAction(repo RepositoryContext, action workflow.ActionReference) *Rule {
 	var bestMatch *Rule
+	bestScore := -1
 
-	for _, rule := range m.rules {
-		if rule.Repository == action.Repository {
-			// If rule has no path specified, it matches any path for the repository
-			if rule.WorkflowPath == "" {
-				if matchingRule == nil {
-					matchingRule = &rule // Generic rule as fallback
-				}
-			} else if rule.WorkflowPath == action.WorkflowPath {
-				// Exact path match takes priority
-				bestMatch = &rule
-				break
-			}
+	for _, i := range m.index.candidates(action.Repository) {
+		rule := &m.rules[i]
+		// If rule has no path specified, it matches any path for the repository
+		if rule.WorkflowPath != "" && rule.WorkflowPath != action.WorkflowPath {
+			continue
+		}
+		if !m.index.conditionsMatch(i, rule.Conditions, repo) {
+			continue
 		}
-	}
 
-	// Return exact path match if found, otherwise return generic rule
-	if bestMatch != nil {
-		return bestMatch
+		score := 0
+		if rule.WorkflowPath != "" {
+			score += 4
+		}
+		if !isGlobPattern(rule.Repository) {
+			score += 2
+		}
+		if rule.Conditions != nil {
+			score++
+		}
+		if score > bestScore {
+			bestMatch = rule
+			bestScore = score
+		}
 	}
-	return matchingRule
+
+	return bestMatch
 }
 
 // findRule finds a rule for the given repository (legacy method for backward compatibility)
 func (m *Manager) findRule(repository string) *Rule {
-	for _, rule := range m.rules {
-		if rule.Repository == repository {
-			return &rule
-		}
+	if positions := m.index.exact[repository]; len(positions) > 0 {
+		return &m.rules[positions[0]]
 	}
 	return nil
 }
@@ -381,28 +660,37 @@ func (m *Manager) isOutdated(current, latest string) bool {
 // 2. Fall back to traditional string-based major version comparison
 // 3. Fall back to simple string inequality check
 func (m *Manager) isOutdatedForRepository(repository, current, latest string) bool {
+	outdated, _ := m.compareToLatest(repository, current, latest)
+	return outdated
+}
+
+// compareToLatest decides whether a version is outdated, with the reason for the decision
+func (m *Manager) compareToLatest(repository, current, latest string) (bool, string) {
 	if current == latest {
-		return false
+		return false, fmt.Sprintf("%s is the latest version", current)
 	}
 
 	// Use cache-first version resolver if available and repository is provided
 	if m.resolver != nil && repository != "" {
 		// First try the new cache-first outdated check method
 		if outdated, err := m.resolver.IsVersionOutdated(repository, current, latest); err == nil {
-			return outdated
+			if outdated {
+				return true, fmt.Sprintf("resolver found %s older than %s", current, latest)
+			}
+			return false, fmt.Sprintf("resolver found %s not older than %s", current, latest)
 		}
 
 		// Fall back to equivalence check if IsVersionOutdated fails
 		equivalent, err := m.resolver.AreVersionsEquivalent(repository, current, latest)
 		if err == nil && equivalent {
-			return false // Versions are equivalent (same SHA)
+			return false, fmt.Sprintf("resolver found %s equivalent to %s (same SHA)", current, latest)
 		}
 		// Continue with fallback logic if resolver fails or versions are not equivalent
 	}
 
 	// Don't flag branch references as outdated
 	if current == "main" || current == "master" {
-		return false
+		return false, fmt.Sprintf("branch %s is exempt from version checks", current)
 	}
 
 	// Simple version comparison (in practice, use proper semver)
@@ -410,10 +698,13 @@ func (m *Manager) isOutdatedForRepository(repository, current, latest string) bo
 	latestMajor := extractMajorVersion(latest)
 
 	if currentMajor != "" && latestMajor != "" {
-		return currentMajor < latestMajor
+		if currentMajor < latestMajor {
+			return true, fmt.Sprintf("major version %s is behind %s", currentMajor, latestMajor)
+		}
+		return false, fmt.Sprintf("major version %s is not behind %s", currentMajor, latestMajor)
 	}
 
-	return current != latest
+	return true, fmt.Sprintf("%s differs from %s", current, latest)
 }
 
 // determineSeverity determines the severity of an outdated version
@@ -609,6 +900,20 @@ func (m *Manager) GetTransformationInfo(repository, currentVersion, targetVersio
 	return m.patcher.GetPatchInfo(repository, currentVersion, targetVersion)
 }
 
+// previewPatch builds the patch an upgrade would apply to the action's with: block when patch previews
+// are enabled, or nil; a patch that cannot be built is logged and left out of the issue
+func (m *Manager) previewPatch(action workflow.ActionReference, targetVersion, targetRepository string) *patcher.Patch {
+	if !m.patchPreview {
+		return nil
+	}
+	patch, err := m.patcher.PreviewChangesWithLocation(action.Repository, action.Version, targetVersion, targetRepository, action.With)
+	if err != nil {
+		m.logf("Warning: Failed to preview patch for %s@%s in %s: %v", action.Repository, action.Version, action.FilePath, err)
+		return nil
+	}
+	return patch
+}
+
 // PreviewTransformation shows what changes would be made to an action's with block
 // without actually applying them
 func (m *Manager) PreviewTransformation(repository, currentVersion, targetVersion string, withBlock interface{}) (*patcher.Patch, error) {
@@ -1,17 +1,24 @@
 package actions
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/cache"
 	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/patcher"
 	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
 )
 
 // MockVersionResolver implements VersionResolver for testing
 type MockVersionResolver struct {
-	equivalentVersions map[string]bool   // maps "repo:v1:v2" to bool
-	outdatedVersions   map[string]bool   // maps "repo:current:latest" to bool
-	refResolutions     map[string]string // maps "owner/repo:ref" to SHA
+	equivalentVersions map[string]bool              // maps "repo:v1:v2" to bool
+	outdatedVersions   map[string]bool              // maps "repo:current:latest" to bool
+	refResolutions     map[string]string            // maps "owner/repo:ref" to SHA
+	tags               map[string]map[string]string // maps "owner/repo" to tag->SHA
 }
 
 func NewMockVersionResolver() *MockVersionResolver {
@@ -19,6 +26,7 @@ func NewMockVersionResolver() *MockVersionResolver {
 		equivalentVersions: make(map[string]bool),
 		outdatedVersions:   make(map[string]bool),
 		refResolutions:     make(map[string]string),
+		tags:               make(map[string]map[string]string),
 	}
 }
 
@@ -60,6 +68,18 @@ func (m *MockVersionResolver) ResolveRefWithCache(owner, repo, ref string) (stri
 	return "abc123def456ghi789jkl012mno345pqr678stu901", nil
 }
 
+func (m *MockVersionResolver) GetTagsWithCache(owner, repo string) (map[string]string, error) {
+	if tags, exists := m.tags[owner+"/"+repo]; exists {
+		return tags, nil
+	}
+	return nil, fmt.Errorf("no tags for %s/%s", owner, repo)
+}
+
+// SetTags sets the mock tag->SHA map for a repository
+func (m *MockVersionResolver) SetTags(owner, repo string, tags map[string]string) {
+	m.tags[owner+"/"+repo] = tags
+}
+
 // SetRefResolution sets a mock resolution for a ref to SHA
 func (m *MockVersionResolver) SetRefResolution(owner, repo, ref, sha string) {
 	key := owner + "/" + repo + ":" + ref
@@ -879,3 +899,585 @@ func findIssuesByRepositoryAndContext(issues []output.ActionIssue, repository, c
 	}
 	return found
 }
+
+func TestAnalyzeActions_CommentDrift(t *testing.T) {
+	resolver := NewMockVersionResolver()
+	resolver.SetRefResolution("actions", "checkout", "v4.1.1", "b4ffde65f46336ab88eb53be808477a3936bae11")
+	manager := NewManagerWithResolver(resolver)
+
+	tests := []struct {
+		name        string
+		version     string
+		comment     string
+		expectDrift bool
+	}{
+		{"SHA matches comment", "b4ffde65f46336ab88eb53be808477a3936bae11", "v4.1.1", false},
+		{"abbreviated SHA matches comment", "b4ffde6", "v4.1.1", false},
+		{"SHA does not match comment", "f43a0e5ff2bd294095638e18286ca9a3d1956744", "v4.1.1", true},
+		{"tag consistent with comment", "v4", "v4.1.1", false},
+		{"tag mismatches comment", "v3", "v4.1.1", true},
+		{"comment without version", "f43a0e5ff2bd294095638e18286ca9a3d1956744", "pinned by security team", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := manager.AnalyzeActions([]workflow.ActionReference{
+				{Repository: "actions/checkout", Version: tt.version, PinComment: tt.comment, FilePath: ".github/workflows/ci.yml"},
+			})
+
+			found := false
+			for _, issue := range issues {
+				if issue.IssueType == "comment-drift" {
+					found = true
+				}
+			}
+			if found != tt.expectDrift {
+				t.Errorf("Expected comment drift %v, got %v (issues: %+v)", tt.expectDrift, found, issues)
+			}
+		})
+	}
+}
+
+func TestAnalyzeActions_TagMoved(t *testing.T) {
+	resolver := NewMockVersionResolver()
+	resolver.SetRefResolution("actions", "checkout", "v4.1.1", "b4ffde65f46336ab88eb53be808477a3936bae11")
+	resolver.SetRefResolution("actions", "checkout", "v4", "b4ffde65f46336ab88eb53be808477a3936bae11")
+	resolver.SetTags("actions", "checkout", map[string]string{
+		"v4":     "b4ffde65f46336ab88eb53be808477a3936bae11",
+		"v4.1.1": "b4ffde65f46336ab88eb53be808477a3936bae11",
+		"v4.1.0": "8ade135a41bc03ea155e62e844d188df1ea18608",
+	})
+	manager := NewManagerWithResolver(resolver)
+
+	tests := []struct {
+		name         string
+		version      string
+		comment      string
+		expectedType string
+	}{
+		{"pinned SHA no longer tagged", "f43a0e5ff2bd294095638e18286ca9a3d1956744", "v4.1.1", "tag-moved"},
+		{"pinned SHA carried by another tag", "8ade135a41bc03ea155e62e844d188df1ea18608", "v4.1.1", "comment-drift"},
+		{"floating tag moved by design", "f43a0e5ff2bd294095638e18286ca9a3d1956744", "v4", "comment-drift"},
+		{"SHA matches tag", "b4ffde65f46336ab88eb53be808477a3936bae11", "v4.1.1", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := manager.AnalyzeActions([]workflow.ActionReference{
+				{Repository: "actions/checkout", Version: tt.version, PinComment: tt.comment, FilePath: ".github/workflows/ci.yml"},
+			})
+
+			if tt.expectedType == "" {
+				if len(issues) != 0 {
+					t.Errorf("Expected no issues, got %+v", issues)
+				}
+				return
+			}
+			if len(issues) != 1 || issues[0].IssueType != tt.expectedType {
+				t.Fatalf("Expected one %s issue, got %+v", tt.expectedType, issues)
+			}
+			if tt.expectedType == "tag-moved" && issues[0].Severity != "high" {
+				t.Errorf("Expected high severity, got %s", issues[0].Severity)
+			}
+		})
+	}
+}
+
+func TestTimedResolver_RecordsCalls(t *testing.T) {
+	resolver := NewTimedResolver(NewMockVersionResolver())
+	manager := NewManagerWithResolverConfigAndRules(resolver, &Config{}, []Rule{
+		{Repository: "actions/checkout", LatestVersion: "v4"},
+	})
+
+	manager.AnalyzeActions([]workflow.ActionReference{
+		{Repository: "actions/checkout", Version: "v3"},
+		{Repository: "actions/checkout", Version: "b4ffde65f46336ab88eb53be808477a3936bae11"},
+	})
+
+	if _, calls := resolver.Elapsed(); calls == 0 {
+		t.Errorf("Expected resolver calls to be recorded")
+	}
+}
+
+// mockReleaseDateClient returns fixed release dates and counts lookups
+type mockReleaseDateClient struct {
+	dates map[string]time.Time
+	calls int
+}
+
+func (m *mockReleaseDateClient) GetReleaseDate(owner, repo, ref string) (time.Time, error) {
+	m.calls++
+	date, ok := m.dates[owner+"/"+repo+"@"+ref]
+	if !ok {
+		return time.Time{}, fmt.Errorf("unknown ref %s", ref)
+	}
+	return date, nil
+}
+
+func TestAgeAnnotator_AnnotatesOutdatedIssues(t *testing.T) {
+	client := &mockReleaseDateClient{dates: map[string]time.Time{
+		"actions/checkout@v3": time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC),
+		"actions/checkout@v4": time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC),
+	}}
+	annotator := NewAgeAnnotator(client, cache.NewMemoryCache(), nil)
+	annotator.now = func() time.Time { return time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC) }
+
+	issues := []output.ActionIssue{
+		{Repository: "actions/checkout", CurrentVersion: "v3", SuggestedVersion: "v4", IssueType: "outdated"},
+		{Repository: "actions/checkout", CurrentVersion: "v3", SuggestedVersion: "v4", IssueType: "outdated"},
+		{Repository: "actions/checkout", CurrentVersion: "v3", IssueType: "comment-drift"},
+		{Repository: "actions/setup-node", CurrentVersion: "v1", SuggestedVersion: "v4", IssueType: "outdated"},
+	}
+	annotator.Annotate(issues)
+
+	if issues[0].DaysBehind != 552 {
+		t.Errorf("Expected 552 days behind, got %d", issues[0].DaysBehind)
+	}
+	if issues[0].PinAgeDays != 731 {
+		t.Errorf("Expected pin age of 731 days, got %d", issues[0].PinAgeDays)
+	}
+	if issues[0].CurrentVersionDate == nil || issues[0].SuggestedVersionDate == nil {
+		t.Errorf("Expected release dates to be set")
+	}
+	if issues[2].CurrentVersionDate != nil {
+		t.Errorf("Expected non-outdated issues to be left unchanged")
+	}
+	if issues[3].CurrentVersionDate != nil || issues[3].DaysBehind != 0 {
+		t.Errorf("Expected issue with unknown release dates to be left unchanged")
+	}
+
+	// Second checkout issue is served from cache; the unknown setup-node ref is looked up once
+	if client.calls != 3 {
+		t.Errorf("Expected 3 release date lookups, got %d", client.calls)
+	}
+}
+
+// TestAnalyzeActions_WithPathRenameRules tests renaming a reusable workflow path within its repository
+func TestAnalyzeActions_WithPathRenameRules(t *testing.T) {
+	customRules := []Rule{
+		{
+			Repository:       "org/workflows",
+			WorkflowPath:     ".github/workflows/old.yml",
+			MigrateToPath:    ".github/workflows/new.yml",
+			MigrateToVersion: "v3",
+		},
+	}
+
+	manager := NewManagerWithResolverConfigAndRules(nil, &Config{Verbose: false}, customRules)
+
+	issues := manager.AnalyzeActions([]workflow.ActionReference{
+		{
+			Repository:   "org/workflows",
+			WorkflowPath: ".github/workflows/old.yml",
+			Version:      "v2",
+			IsReusable:   true,
+			Context:      "job:build",
+			FilePath:     ".github/workflows/ci.yml",
+		},
+		{
+			Repository:   "org/workflows",
+			WorkflowPath: ".github/workflows/other.yml",
+			Version:      "v2",
+			IsReusable:   true,
+			Context:      "job:test",
+			FilePath:     ".github/workflows/ci.yml",
+		},
+	})
+
+	migration := findIssueByVersionAndType(issues, "v2", "migration")
+	if migration == nil {
+		t.Fatal("Expected a migration issue for the renamed workflow")
+	}
+	if migration.MigrationTarget != "org/workflows/.github/workflows/new.yml@v3" {
+		t.Errorf("Expected migration target 'org/workflows/.github/workflows/new.yml@v3', got '%s'", migration.MigrationTarget)
+	}
+	if migration.WorkflowPath != ".github/workflows/old.yml" {
+		t.Errorf("Expected workflow path '.github/workflows/old.yml', got '%s'", migration.WorkflowPath)
+	}
+	if !strings.Contains(migration.Description, "has moved to .github/workflows/new.yml") {
+		t.Errorf("Unexpected description: %s", migration.Description)
+	}
+
+	for _, issue := range issues {
+		if issue.Context == "job:test" {
+			t.Errorf("Expected no issue for workflows the rule does not name, got %+v", issue)
+		}
+	}
+}
+
+// TestAnalyzeActionsForRepository_WithConditions tests that conditional rules apply only to matching repositories
+func TestAnalyzeActionsForRepository_WithConditions(t *testing.T) {
+	customRules := []Rule{
+		{Repository: "my-org/deploy", LatestVersion: "v5"},
+		{
+			Repository:    "my-org/deploy",
+			LatestVersion: "v3",
+			Conditions:    &RuleConditions{CustomProperties: map[string]string{"ProductId": "legacy"}},
+		},
+		{
+			Repository:    "my-org/deploy",
+			LatestVersion: "v4",
+			Conditions:    &RuleConditions{RepositoryPattern: "^api-", Topic: "beta"},
+		},
+	}
+
+	manager := NewManagerWithResolverConfigAndRules(nil, &Config{Verbose: false}, customRules)
+	deploy := []workflow.ActionReference{{Repository: "my-org/deploy", Version: "v2", FilePath: ".github/workflows/ci.yml"}}
+
+	tests := []struct {
+		name     string
+		repo     RepositoryContext
+		expected string
+	}{
+		{"no conditions match", RepositoryContext{Name: "web"}, "v5"},
+		{"custom property matches", RepositoryContext{Name: "web", CustomProperties: map[string]string{"ProductId": "legacy"}}, "v3"},
+		{"custom property differs", RepositoryContext{Name: "web", CustomProperties: map[string]string{"ProductId": "modern"}}, "v5"},
+		{"pattern and topic match", RepositoryContext{Name: "api-orders", Topics: []string{"go", "beta"}}, "v4"},
+		{"pattern matches without topic", RepositoryContext{Name: "api-orders", Topics: []string{"go"}}, "v5"},
+	}
+
+	for _, test := range tests {
+		issues := manager.AnalyzeActionsForRepository(test.repo, deploy)
+		outdated := findIssueByVersionAndType(issues, "v2", "outdated")
+		if outdated == nil {
+			t.Errorf("%s: expected an outdated issue", test.name)
+			continue
+		}
+		if outdated.SuggestedVersion != test.expected {
+			t.Errorf("%s: expected suggested version %s, got %s", test.name, test.expected, outdated.SuggestedVersion)
+		}
+	}
+
+	// Without a repository context only the unconditional rule applies
+	if outdated := findIssueByVersionAndType(manager.AnalyzeActions(deploy), "v2", "outdated"); outdated == nil || outdated.SuggestedVersion != "v5" {
+		t.Errorf("Expected AnalyzeActions to use the unconditional rule, got %+v", outdated)
+	}
+}
+
+// TestRuleConditions_Validate tests repository pattern validation
+func TestRuleConditions_Validate(t *testing.T) {
+	var none *RuleConditions
+	if err := none.Validate(); err != nil {
+		t.Errorf("Expected nil conditions to be valid, got %v", err)
+	}
+	if err := (&RuleConditions{RepositoryPattern: "^api-"}).Validate(); err != nil {
+		t.Errorf("Expected valid pattern, got %v", err)
+	}
+	if err := (&RuleConditions{RepositoryPattern: "(["}).Validate(); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+}
+
+// TestConditionProperties tests collecting custom property names from rule conditions
+func TestConditionProperties(t *testing.T) {
+	rules := []Rule{
+		{Repository: "a/b", Conditions: &RuleConditions{CustomProperties: map[string]string{"Team": "x", "ProductId": "legacy"}}},
+		{Repository: "a/c", Conditions: &RuleConditions{CustomProperties: map[string]string{"ProductId": "new"}}},
+		{Repository: "a/d"},
+	}
+
+	names := ConditionProperties(rules)
+	if strings.Join(names, ",") != "ProductId,Team" {
+		t.Errorf("Expected [ProductId Team], got %v", names)
+	}
+}
+
+// TestAnalyzeRepository tests that repository metadata reaches rule evaluation and issue construction
+func TestAnalyzeRepository(t *testing.T) {
+	customRules := []Rule{
+		{Repository: "my-org/deploy", LatestVersion: "v5"},
+		{
+			Repository:    "my-org/deploy",
+			LatestVersion: "v3",
+			Conditions:    &RuleConditions{CustomProperties: map[string]string{"ProductId": "legacy"}, Topic: "payments"},
+		},
+	}
+
+	manager := NewManagerWithResolverConfigAndRules(nil, &Config{Verbose: false}, customRules)
+
+	repo := output.RepositoryResult{
+		Name:             "billing",
+		FullName:         "my-org/billing",
+		DefaultBranch:    "main",
+		Actions:          []workflow.ActionReference{{Repository: "my-org/deploy", Version: "v2", FilePath: ".github/workflows/ci.yml"}},
+		CustomProperties: map[string]string{"ProductId": "legacy"},
+		Topics:           []string{"payments"},
+	}
+
+	outdated := findIssueByVersionAndType(manager.AnalyzeRepository(repo), "v2", "outdated")
+	if outdated == nil {
+		t.Fatal("Expected an outdated issue")
+	}
+	if outdated.SuggestedVersion != "v3" {
+		t.Errorf("Expected the legacy rule's v3, got %s", outdated.SuggestedVersion)
+	}
+	if outdated.RuleConditions != "ProductId=legacy, topic=payments" {
+		t.Errorf("Expected rule conditions to be recorded, got %q", outdated.RuleConditions)
+	}
+
+	// Issues from unconditional rules carry no conditions
+	repo.Topics = nil
+	outdated = findIssueByVersionAndType(manager.AnalyzeRepository(repo), "v2", "outdated")
+	if outdated == nil || outdated.SuggestedVersion != "v5" || outdated.RuleConditions != "" {
+		t.Errorf("Expected the unconditional rule without conditions, got %+v", outdated)
+	}
+}
+
+// TestFindRuleForAction_GlobRules tests glob repository rules and their precedence
+func TestFindRuleForAction_GlobRules(t *testing.T) {
+	customRules := []Rule{
+		{Repository: "my-org/*", LatestVersion: "v1"},
+		{Repository: "my-org/deploy", LatestVersion: "v2"},
+		{Repository: "my-org/*", LatestVersion: "v3", Conditions: &RuleConditions{Topic: "beta"}},
+		{Repository: "other-org/?ache", LatestVersion: "v4"},
+		{Repository: "bad/*", LatestVersion: "v5", Conditions: &RuleConditions{RepositoryPattern: "(["}},
+	}
+
+	manager := NewManagerWithResolverConfigAndRules(nil, &Config{Verbose: false}, customRules)
+
+	tests := []struct {
+		name       string
+		repository string
+		repo       RepositoryContext
+		expected   string
+	}{
+		{"glob matches", "my-org/build", RepositoryContext{}, "v1"},
+		{"exact repository beats glob", "my-org/deploy", RepositoryContext{}, "v2"},
+		{"exact repository beats conditional glob", "my-org/deploy", RepositoryContext{Topics: []string{"beta"}}, "v2"},
+		{"conditional glob beats glob", "my-org/build", RepositoryContext{Topics: []string{"beta"}}, "v3"},
+		{"question mark matches one character", "other-org/cache", RepositoryContext{}, "v4"},
+		{"glob does not cross slashes", "my-org/build/sub", RepositoryContext{}, ""},
+		{"invalid pattern never matches", "bad/action", RepositoryContext{Name: "x"}, ""},
+		{"no rule", "actions/checkout", RepositoryContext{}, ""},
+	}
+
+	for _, test := range tests {
+		rule := manager.findRuleForAction(test.repo, workflow.ActionReference{Repository: test.repository})
+		got := ""
+		if rule != nil {
+			got = rule.LatestVersion
+		}
+		if got != test.expected {
+			t.Errorf("%s: expected rule %q, got %q", test.name, test.expected, got)
+		}
+	}
+}
+
+// TestAnalyzeRepositories tests that parallel analysis returns issues in repository order
+func TestAnalyzeRepositories(t *testing.T) {
+	customRules := []Rule{
+		{Repository: "actions/checkout", LatestVersion: "v4"},
+		{Repository: "actions/checkout", LatestVersion: "v3", Conditions: &RuleConditions{RepositoryPattern: "^legacy-"}},
+	}
+
+	var repos []output.RepositoryResult
+	for i := 0; i < 50; i++ {
+		name := fmt.Sprintf("service-%d", i)
+		if i%2 == 0 {
+			name = fmt.Sprintf("legacy-%d", i)
+		}
+		repos = append(repos, output.RepositoryResult{
+			Name:     name,
+			FullName: "my-org/" + name,
+			Actions:  []workflow.ActionReference{{Repository: "actions/checkout", Version: "v2", FilePath: ".github/workflows/ci.yml"}},
+		})
+	}
+
+	for _, workers := range []int{0, 1, 4} {
+		manager := NewManagerWithResolverConfigAndRules(nil, &Config{Workers: workers}, customRules)
+		results := manager.AnalyzeRepositories(repos)
+		if len(results) != len(repos) {
+			t.Fatalf("workers=%d: expected %d results, got %d", workers, len(repos), len(results))
+		}
+
+		for i, issues := range results {
+			expected := "v4"
+			if i%2 == 0 {
+				expected = "v3"
+			}
+			outdated := findIssueByVersionAndType(issues, "v2", "outdated")
+			if outdated == nil || outdated.SuggestedVersion != expected {
+				t.Errorf("workers=%d: %s expected suggestion %s, got %+v", workers, repos[i].Name, expected, outdated)
+			}
+		}
+	}
+
+	// No repositories, no work
+	if results := NewManager().AnalyzeRepositories(nil); len(results) != 0 {
+		t.Errorf("Expected no results, got %d", len(results))
+	}
+}
+
+// TestAnalyzeRepositoriesWithLogs tests that logs of repositories analyzed concurrently are kept apart
+func TestAnalyzeRepositoriesWithLogs(t *testing.T) {
+	var customRules []Rule
+	var repos []output.RepositoryResult
+	for i := 0; i < 40; i++ {
+		action := fmt.Sprintf("my-org/tool-%02d", i)
+		customRules = append(customRules, Rule{Repository: action, LatestVersion: "v2"})
+		repos = append(repos, output.RepositoryResult{
+			Name:     fmt.Sprintf("service-%02d", i),
+			FullName: fmt.Sprintf("my-org/service-%02d", i),
+			Actions: []workflow.ActionReference{
+				{Repository: action, Version: "v1", FilePath: ".github/workflows/ci.yml"},
+				{Repository: action, Version: "v1", FilePath: ".github/workflows/release.yml"},
+			},
+		})
+	}
+
+	manager := NewManagerWithResolverConfigAndRules(nil, &Config{Verbose: true, Workers: 8}, customRules)
+	results, logs := manager.AnalyzeRepositoriesWithLogs(repos, nil)
+	if len(results) != len(repos) || len(logs) != len(repos) {
+		t.Fatalf("Expected %d results and logs, got %d and %d", len(repos), len(results), len(logs))
+	}
+
+	tool := regexp.MustCompile(`my-org/tool-\d+`)
+	for i, lines := range logs {
+		own := fmt.Sprintf("my-org/tool-%02d", i)
+		mentions := 0
+		for _, line := range lines {
+			for _, name := range tool.FindAllString(line, -1) {
+				if name != own {
+					t.Errorf("%s: recorded a line from another repository: %s", repos[i].Name, line)
+				}
+				mentions++
+			}
+		}
+		if mentions == 0 {
+			t.Errorf("%s: expected its rule evaluation to be recorded, got %v", repos[i].Name, lines)
+		}
+		if len(results[i]) == 0 {
+			t.Errorf("%s: expected issues, got none", repos[i].Name)
+		}
+	}
+}
+
+// TestRuleOwners tests that rule owners are recorded on the issues the rule raises
+func TestRuleOwners(t *testing.T) {
+	customRules := []Rule{
+		{Repository: "my-org/deploy", LatestVersion: "v5", DeprecatedVersions: []string{"v1"}, Owners: []string{"my-org/platform", "octocat"}},
+		{Repository: "actions/checkout", LatestVersion: "v4"},
+	}
+
+	manager := NewManagerWithResolverConfigAndRules(nil, &Config{Verbose: false}, customRules)
+	issues := manager.AnalyzeActions([]workflow.ActionReference{
+		{Repository: "my-org/deploy", Version: "v1", FilePath: ".github/workflows/ci.yml"},
+		{Repository: "actions/checkout", Version: "v3", FilePath: ".github/workflows/ci.yml"},
+	})
+	if len(issues) < 2 {
+		t.Fatalf("Expected issues for both actions, got %d", len(issues))
+	}
+
+	for _, issue := range issues {
+		switch issue.Repository {
+		case "my-org/deploy":
+			if strings.Join(issue.Owners, ",") != "my-org/platform,octocat" {
+				t.Errorf("Expected %s issue owners [my-org/platform octocat], got %v", issue.IssueType, issue.Owners)
+			}
+		case "actions/checkout":
+			if issue.Owners != nil {
+				t.Errorf("Expected no owners for actions/checkout, got %v", issue.Owners)
+			}
+		}
+	}
+}
+
+// TestRuleReportOnly tests that report-only rules mark the issues they raise
+func TestRuleReportOnly(t *testing.T) {
+	manager := NewManagerWithResolverConfigAndRules(nil, nil, []Rule{
+		{Repository: "old-org/deploy", LatestVersion: "v2", MigrateToRepository: "new-org/deploy", MigrateToVersion: "v1", ReportOnly: true},
+		{Repository: "actions/checkout", LatestVersion: "v4"},
+	})
+	issues := manager.AnalyzeActions([]workflow.ActionReference{
+		{Repository: "old-org/deploy", Version: "v2", FilePath: ".github/workflows/ci.yml"},
+		{Repository: "actions/checkout", Version: "v3", FilePath: ".github/workflows/ci.yml"},
+	})
+	if len(issues) < 2 {
+		t.Fatalf("Expected issues for both actions, got %d", len(issues))
+	}
+
+	for _, issue := range issues {
+		if want := issue.Repository == "old-org/deploy"; issue.ReportOnly != want {
+			t.Errorf("Expected %s %s issue report_only %v, got %v", issue.Repository, issue.IssueType, want, issue.ReportOnly)
+		}
+	}
+}
+
+// TestRuleFiles tests that rule file edits are recorded on the issues the rule raises
+func TestRuleFiles(t *testing.T) {
+	edit := output.FileEdit{Path: ".github/dependabot.yml", Find: "cache@v2", Replace: "cache@{target}"}
+	manager := NewManagerWithResolverConfigAndRules(nil, nil, []Rule{
+		{Repository: "actions/cache", LatestVersion: "v4", Files: []output.FileEdit{edit}},
+		{Repository: "actions/checkout", LatestVersion: "v4"},
+	})
+	issues := manager.AnalyzeActions([]workflow.ActionReference{
+		{Repository: "actions/cache", Version: "v2", FilePath: ".github/workflows/ci.yml"},
+		{Repository: "actions/checkout", Version: "v3", FilePath: ".github/workflows/ci.yml"},
+	})
+	if len(issues) < 2 {
+		t.Fatalf("Expected issues for both actions, got %d", len(issues))
+	}
+
+	for _, issue := range issues {
+		switch issue.Repository {
+		case "actions/cache":
+			if len(issue.FileEdits) != 1 || issue.FileEdits[0] != edit {
+				t.Errorf("Expected %s issue file edits [%+v], got %+v", issue.IssueType, edit, issue.FileEdits)
+			}
+		case "actions/checkout":
+			if issue.FileEdits != nil {
+				t.Errorf("Expected no file edits for actions/checkout, got %+v", issue.FileEdits)
+			}
+		}
+	}
+}
+
+// TestPatchPreview tests that the concrete patch is embedded in issues only when previews are enabled
+func TestPatchPreview(t *testing.T) {
+	rules := []Rule{{Repository: "actions/checkout", LatestVersion: "v4"}}
+	checkoutPatch := patcher.ActionPatchRule{
+		Repository: "actions/checkout",
+		VersionPatches: []patcher.VersionPatch{{
+			FromVersion: "v1",
+			ToVersion:   "v4",
+			Description: "Token handling changed",
+			Patches: []patcher.FieldPatch{
+				{Operation: patcher.OperationRemove, Field: "token", Reason: "Uses GITHUB_TOKEN"},
+				{Operation: patcher.OperationAdd, Field: "fetch-depth", Value: 1, Reason: "Shallow clone"},
+			},
+		}},
+	}
+	action := workflow.ActionReference{
+		Repository: "actions/checkout",
+		Version:    "v1",
+		FilePath:   ".github/workflows/ci.yml",
+		With:       map[string]interface{}{"token": "${{ secrets.PAT }}", "path": "src"},
+	}
+
+	for _, enabled := range []bool{false, true} {
+		manager := NewManagerWithResolverConfigAndRules(nil, &Config{PatchPreview: enabled}, rules)
+		manager.patcher.AddPatchRule(checkoutPatch)
+
+		issues := manager.AnalyzeActions([]workflow.ActionReference{action})
+		if len(issues) != 1 || !issues[0].HasTransformations {
+			t.Fatalf("Expected one transformed issue, got %+v", issues)
+		}
+		preview := issues[0].PatchPreview
+		if !enabled {
+			if preview != nil {
+				t.Errorf("Expected no preview when disabled, got %+v", preview)
+			}
+			continue
+		}
+		if preview == nil || len(preview.Removals) != 1 || len(preview.Additions) != 1 {
+			t.Fatalf("Expected a preview removing token and adding fetch-depth, got %+v", preview)
+		}
+		updated, _ := preview.UpdatedWith.(map[string]interface{})
+		if _, hasToken := updated["token"]; hasToken || updated["fetch-depth"] != 1 || updated["path"] != "src" {
+			t.Errorf("Expected the updated with block to drop token and add fetch-depth, got %v", preview.UpdatedWith)
+		}
+		if _, hasToken := action.With.(map[string]interface{})["token"]; !hasToken {
+			t.Error("Expected the previewed with block not to be modified")
+		}
+	}
+}
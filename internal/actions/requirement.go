@@ -0,0 +1,229 @@
+package actions
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// Where a required action must be present
+const (
+	RequireInWorkflow = "workflow" // Called by a job of every matching workflow
+	RequireInJob      = "job"      // Used by a step of every matching job
+)
+
+// IssueTypeMissingRequiredAction is raised when a required action is absent or not first
+const IssueTypeMissingRequiredAction = "missing-required-action"
+
+// Requirement turns a rule into a requirement that its action is present in matching workflows or jobs
+// The rule's repository and workflow_path name the required action or reusable workflow.
+type Requirement struct {
+	Scope     string            `json:"scope,omitempty"`     // RequireInWorkflow (default) or RequireInJob
+	Version   string            `json:"version"`             // Version to call, e.g. "v2"
+	First     bool              `json:"first,omitempty"`     // Must be the first job of the workflow, or the first step of the job
+	Workflows string            `json:"workflows,omitempty"` // Regex matched against workflow file paths (default: all)
+	Jobs      string            `json:"jobs,omitempty"`      // Regex matched against job names, for job scope (default: all)
+	With      map[string]string `json:"with,omitempty"`      // Inputs of an inserted call
+	Insert    bool              `json:"insert,omitempty"`    // Let create-pr insert the action where it is missing
+}
+
+// Validate checks the scope, version, and patterns of a requirement
+func (r *Requirement) Validate() error {
+	if r == nil {
+		return nil
+	}
+	if r.Scope != "" && r.Scope != RequireInWorkflow && r.Scope != RequireInJob {
+		return fmt.Errorf("invalid required scope %q: must be %q or %q", r.Scope, RequireInWorkflow, RequireInJob)
+	}
+	if r.Version == "" {
+		return fmt.Errorf("required version field is required")
+	}
+	if _, err := regexp.Compile(r.Workflows); err != nil {
+		return fmt.Errorf("invalid required workflows pattern %q: %w", r.Workflows, err)
+	}
+	if _, err := regexp.Compile(r.Jobs); err != nil {
+		return fmt.Errorf("invalid required jobs pattern %q: %w", r.Jobs, err)
+	}
+	return nil
+}
+
+// requiredRule is a rule with a requirement and its compiled patterns
+type requiredRule struct {
+	rule      Rule
+	workflows *regexp.Regexp
+	jobs      *regexp.Regexp
+}
+
+// newRequiredRules compiles the requirements of rules; rules with invalid patterns never match
+func newRequiredRules(rules []Rule) []requiredRule {
+	var required []requiredRule
+	for _, rule := range rules {
+		if rule.Required == nil {
+			continue
+		}
+		workflows, err := regexp.Compile(rule.Required.Workflows)
+		if err != nil {
+			continue
+		}
+		jobs, err := regexp.Compile(rule.Required.Jobs)
+		if err != nil {
+			continue
+		}
+		required = append(required, requiredRule{rule: rule, workflows: workflows, jobs: jobs})
+	}
+	return required
+}
+
+// HasRequirements reports whether any rule requires an action, so workflow outlines need to be parsed
+func (m *Manager) HasRequirements() bool {
+	return len(m.required) > 0
+}
+
+// CheckRequiredActions runs the enabled repository checks, such as required action rules, on the workflows of a repository
+func (m *Manager) CheckRequiredActions(repo output.RepositoryResult, outlines []workflow.WorkflowOutline) []output.ActionIssue {
+	var issues []output.ActionIssue
+	for _, check := range m.checks {
+		if repositoryCheck, ok := check.(RepositoryCheck); ok {
+			issues = append(issues, repositoryCheck.CheckRepository(m, repo, outlines)...)
+		}
+	}
+	return issues
+}
+
+// requiredActionsCheck checks the workflows of a repository against required action rules whose conditions it matches
+type requiredActionsCheck struct{}
+
+func (requiredActionsCheck) Name() string { return CheckRequiredActions }
+
+func (requiredActionsCheck) CheckRepository(m *Manager, repo output.RepositoryResult, outlines []workflow.WorkflowOutline) []output.ActionIssue {
+	context := repositoryContextOf(repo)
+
+	var issues []output.ActionIssue
+	for _, required := range m.required {
+		if !required.rule.Conditions.Matches(context) {
+			continue
+		}
+		for _, outline := range outlines {
+			if !required.workflows.MatchString(outline.FilePath) {
+				continue
+			}
+			if required.rule.Required.Scope == RequireInJob {
+				issues = append(issues, m.checkRequiredInJobs(required, outline)...)
+			} else if issue := m.checkRequiredInWorkflow(required, outline); issue != nil {
+				issues = append(issues, *issue)
+			}
+		}
+	}
+	return issues
+}
+
+// checkRequiredInWorkflow checks that a job of the workflow calls the required action, first when required
+func (m *Manager) checkRequiredInWorkflow(required requiredRule, outline workflow.WorkflowOutline) *output.ActionIssue {
+	rule := required.rule
+	position := -1
+	for i, job := range outline.Jobs {
+		if jobUsesRule(job, rule) {
+			position = i
+			break
+		}
+	}
+
+	switch {
+	case position < 0:
+		if m.verbose {
+			m.logf("Rule evaluation: Required action %s missing from %s", requiredName(rule), outline.FilePath)
+		}
+		issue := requiredIssue(rule, outline.FilePath, "workflow",
+			fmt.Sprintf("Required action %s is not called by any job of this workflow", requiredName(rule)))
+		if rule.Required.Insert {
+			issue.SuggestedVersion = rule.Required.Version
+			issue.Insertion = &output.RequiredInsertion{Scope: RequireInWorkflow, With: rule.Required.With}
+		}
+		return &issue
+	case position > 0 && rule.Required.First:
+		issue := requiredIssue(rule, outline.FilePath, "workflow",
+			fmt.Sprintf("Required action %s must be called by the first job of this workflow, not job %s", requiredName(rule), outline.Jobs[position].Name))
+		return &issue
+	}
+	return nil
+}
+
+// checkRequiredInJobs checks that every matching job has a step using the required action, first when required
+// Jobs calling a reusable workflow have no steps of their own and are skipped.
+func (m *Manager) checkRequiredInJobs(required requiredRule, outline workflow.WorkflowOutline) []output.ActionIssue {
+	rule := required.rule
+	var issues []output.ActionIssue
+	for _, job := range outline.Jobs {
+		if job.Uses != "" || !required.jobs.MatchString(job.Name) {
+			continue
+		}
+
+		position := -1
+		for i, uses := range job.Steps {
+			if workflow.UsesAction(uses, rule.Repository, rule.WorkflowPath) {
+				position = i
+				break
+			}
+		}
+
+		context := "job:" + job.Name
+		switch {
+		case position < 0:
+			issue := requiredIssue(rule, outline.FilePath, context,
+				fmt.Sprintf("Required action %s is not used by any step of job %s", requiredName(rule), job.Name))
+			if rule.Required.Insert {
+				issue.SuggestedVersion = rule.Required.Version
+				issue.Insertion = &output.RequiredInsertion{Scope: RequireInJob, Job: job.Name, With: rule.Required.With}
+			}
+			issues = append(issues, issue)
+		case position > 0 && rule.Required.First:
+			issues = append(issues, requiredIssue(rule, outline.FilePath, context,
+				fmt.Sprintf("Required action %s must be the first step of job %s", requiredName(rule), job.Name)))
+		}
+	}
+	return issues
+}
+
+// jobUsesRule reports whether a job calls the rule's action, as a reusable workflow or in a step
+func jobUsesRule(job workflow.JobOutline, rule Rule) bool {
+	if job.Uses != "" {
+		return workflow.UsesAction(job.Uses, rule.Repository, rule.WorkflowPath)
+	}
+	for _, uses := range job.Steps {
+		if workflow.UsesAction(uses, rule.Repository, rule.WorkflowPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// requiredIssue builds a missing-required-action issue; the rule's recommendation replaces the description
+func requiredIssue(rule Rule, filePath, context, description string) output.ActionIssue {
+	if rule.Recommendation != "" {
+		description = rule.Recommendation
+	}
+	return output.ActionIssue{
+		Repository:     rule.Repository,
+		WorkflowPath:   rule.WorkflowPath,
+		IssueType:      IssueTypeMissingRequiredAction,
+		Severity:       "medium",
+		Description:    description,
+		Context:        context,
+		FilePath:       filePath,
+		RuleConditions: rule.Conditions.String(),
+		Owners:         rule.Owners,
+		FileEdits:      rule.Files,
+	}
+}
+
+// requiredName names the required action with its version, e.g. "org/security-scan@v2"
+func requiredName(rule Rule) string {
+	name := rule.Repository
+	if rule.WorkflowPath != "" {
+		name += "/" + strings.TrimPrefix(rule.WorkflowPath, "/")
+	}
+	return name + "@" + rule.Required.Version
+}
@@ -0,0 +1,133 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+func TestCheckRequiredActions_WorkflowScope(t *testing.T) {
+	manager := NewManagerWithResolverConfigAndRules(nil, nil, []Rule{{
+		Repository:   "my-org/security",
+		WorkflowPath: ".github/workflows/scan.yml",
+		Required:     &Requirement{Version: "v2", First: true, Insert: true, Workflows: `ci\.yml$|release\.yml$`},
+		Owners:       []string{"my-org/security"},
+	}})
+	if !manager.HasRequirements() {
+		t.Fatal("Expected manager to have requirements")
+	}
+
+	outlines := []workflow.WorkflowOutline{
+		{FilePath: ".github/workflows/ci.yml", Jobs: []workflow.JobOutline{
+			{Name: "test", Steps: []string{"actions/checkout@v4"}},
+		}},
+		{FilePath: ".github/workflows/release.yml", Jobs: []workflow.JobOutline{
+			{Name: "build", Steps: []string{"actions/checkout@v4"}},
+			{Name: "scan", Uses: "my-org/security/.github/workflows/scan.yml@v1"},
+		}},
+		{FilePath: ".github/workflows/docs.yml", Jobs: []workflow.JobOutline{
+			{Name: "docs", Steps: []string{""}},
+		}},
+	}
+	issues := manager.CheckRequiredActions(output.RepositoryResult{Name: "api", FullName: "my-org/api"}, outlines)
+	if len(issues) != 2 {
+		t.Fatalf("Expected 2 issues, got %d: %+v", len(issues), issues)
+	}
+
+	missing := issues[0]
+	if missing.IssueType != IssueTypeMissingRequiredAction || missing.FilePath != ".github/workflows/ci.yml" || missing.Context != "workflow" {
+		t.Errorf("Expected missing-required-action in ci.yml, got %+v", missing)
+	}
+	if missing.SuggestedVersion != "v2" || missing.Insertion == nil || missing.Insertion.Scope != RequireInWorkflow {
+		t.Errorf("Expected an insertable issue at v2, got %+v", missing)
+	}
+	if missing.Repository != "my-org/security" || missing.WorkflowPath != ".github/workflows/scan.yml" || len(missing.Owners) != 1 {
+		t.Errorf("Expected the rule's action and owners to be recorded, got %+v", missing)
+	}
+
+	// Present at another version but not first: reported without a fix
+	misplaced := issues[1]
+	if misplaced.FilePath != ".github/workflows/release.yml" || misplaced.SuggestedVersion != "" || misplaced.Insertion != nil {
+		t.Errorf("Expected a misplaced issue without a fix in release.yml, got %+v", misplaced)
+	}
+}
+
+func TestCheckRequiredActions_JobScope(t *testing.T) {
+	manager := NewManagerWithResolverConfigAndRules(nil, nil, []Rule{{
+		Repository:     "step-security/harden-runner",
+		Required:       &Requirement{Scope: RequireInJob, Version: "v2", First: true, Jobs: "^(build|deploy)$", With: map[string]string{"egress-policy": "audit"}, Insert: true},
+		Recommendation: "Harden every build runner",
+	}})
+
+	outlines := []workflow.WorkflowOutline{{FilePath: "ci.yml", Jobs: []workflow.JobOutline{
+		{Name: "build", Steps: []string{"actions/checkout@v4"}},
+		{Name: "deploy", Steps: []string{"actions/checkout@v4", "step-security/harden-runner@v2"}},
+		{Name: "lint", Steps: []string{"actions/checkout@v4"}},
+	}}}
+	issues := manager.CheckRequiredActions(output.RepositoryResult{Name: "api"}, outlines)
+	if len(issues) != 2 {
+		t.Fatalf("Expected 2 issues, got %d: %+v", len(issues), issues)
+	}
+
+	if issues[0].Context != "job:build" || issues[0].Insertion == nil || issues[0].Insertion.Job != "build" || issues[0].Insertion.With["egress-policy"] != "audit" {
+		t.Errorf("Expected an insertion into job build, got %+v", issues[0])
+	}
+	if issues[0].Description != "Harden every build runner" {
+		t.Errorf("Expected the recommendation as description, got %q", issues[0].Description)
+	}
+	if issues[1].Context != "job:deploy" || issues[1].Insertion != nil {
+		t.Errorf("Expected a misplaced step in job deploy without a fix, got %+v", issues[1])
+	}
+}
+
+func TestCheckRequiredActions_Conditions(t *testing.T) {
+	manager := NewManagerWithResolverConfigAndRules(nil, nil, []Rule{{
+		Repository: "my-org/security-scan",
+		Required:   &Requirement{Version: "v2"},
+		Conditions: &RuleConditions{Topic: "production"},
+	}})
+	outlines := []workflow.WorkflowOutline{{FilePath: "ci.yml", Jobs: []workflow.JobOutline{{Name: "test"}}}}
+
+	if issues := manager.CheckRequiredActions(output.RepositoryResult{Name: "tool"}, outlines); len(issues) != 0 {
+		t.Errorf("Expected no issues outside the condition, got %+v", issues)
+	}
+	issues := manager.CheckRequiredActions(output.RepositoryResult{Name: "api", Topics: []string{"production"}}, outlines)
+	if len(issues) != 1 || issues[0].RuleConditions != "topic=production" || issues[0].Insertion != nil {
+		t.Errorf("Expected one issue recording its conditions and no insertion, got %+v", issues)
+	}
+}
+
+func TestRequiredRulesAreNotVersionRules(t *testing.T) {
+	manager := NewManagerWithResolverConfigAndRules(nil, nil, []Rule{{
+		Repository: "actions/checkout",
+		Required:   &Requirement{Version: "v4"},
+	}})
+
+	issues := manager.AnalyzeActions([]workflow.ActionReference{{Repository: "actions/checkout", Version: "v2", FilePath: "ci.yml"}})
+	if len(issues) != 0 {
+		t.Errorf("Expected required rules not to check versions, got %+v", issues)
+	}
+}
+
+func TestRequirementValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		requirement *Requirement
+		expectError bool
+	}{
+		{"nil", nil, false},
+		{"workflow scope", &Requirement{Version: "v2"}, false},
+		{"job scope", &Requirement{Scope: RequireInJob, Version: "v2", Jobs: "^build"}, false},
+		{"unknown scope", &Requirement{Scope: "step", Version: "v2"}, true},
+		{"missing version", &Requirement{}, true},
+		{"invalid workflows", &Requirement{Version: "v2", Workflows: "("}, true},
+		{"invalid jobs", &Requirement{Version: "v2", Jobs: "["}, true},
+	}
+
+	for _, tt := range tests {
+		if err := tt.requirement.Validate(); (err != nil) != tt.expectError {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.expectError, err)
+		}
+	}
+}
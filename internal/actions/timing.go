@@ -0,0 +1,59 @@
+package actions
+
+import (
+	"sync"
+	"time"
+)
+
+// TimedResolver wraps a VersionResolver and records the time spent resolving versions
+type TimedResolver struct {
+	resolver VersionResolver
+	mutex    sync.Mutex
+	elapsed  time.Duration
+	calls    int
+}
+
+// NewTimedResolver creates a resolver that times every call to the wrapped resolver
+func NewTimedResolver(resolver VersionResolver) *TimedResolver {
+	return &TimedResolver{resolver: resolver}
+}
+
+// AreVersionsEquivalent delegates to the wrapped resolver and records its duration
+func (t *TimedResolver) AreVersionsEquivalent(repository, version1, version2 string) (bool, error) {
+	defer t.track(time.Now())
+	return t.resolver.AreVersionsEquivalent(repository, version1, version2)
+}
+
+// IsVersionOutdated delegates to the wrapped resolver and records its duration
+func (t *TimedResolver) IsVersionOutdated(repository, currentVersion, latestVersion string) (bool, error) {
+	defer t.track(time.Now())
+	return t.resolver.IsVersionOutdated(repository, currentVersion, latestVersion)
+}
+
+// ResolveRefWithCache delegates to the wrapped resolver and records its duration
+func (t *TimedResolver) ResolveRefWithCache(owner, repo, ref string) (string, error) {
+	defer t.track(time.Now())
+	return t.resolver.ResolveRefWithCache(owner, repo, ref)
+}
+
+// GetTagsWithCache delegates to the wrapped resolver and records its duration
+func (t *TimedResolver) GetTagsWithCache(owner, repo string) (map[string]string, error) {
+	defer t.track(time.Now())
+	return t.resolver.GetTagsWithCache(owner, repo)
+}
+
+// Elapsed returns the total time spent in the wrapped resolver and the number of calls made
+func (t *TimedResolver) Elapsed() (time.Duration, int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.elapsed, t.calls
+}
+
+// track records a completed call that started at the given time
+func (t *TimedResolver) track(start time.Time) {
+	elapsed := time.Since(start)
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.elapsed += elapsed
+	t.calls++
+}
@@ -0,0 +1,205 @@
+package apply
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/patcher"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/pr"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// Config holds configuration options for the applier
+type Config struct {
+	Verbose bool
+	DryRun  bool // Report changes without writing files
+
+	// Simulate validates rewritten files with the dry run checks of the job graph and expressions
+	Simulate bool
+}
+
+// Applier rewrites workflow files in repositories already checked out on disk
+type Applier struct {
+	workdir    string
+	patcher    *patcher.WorkflowPatcher
+	verbose    bool
+	dryRun     bool
+	validation *workflow.ValidationConfig
+}
+
+// Result describes the outcome of applying a plan to one local checkout
+type Result struct {
+	Repository   string   // Repository full name
+	Directory    string   // Local checkout directory (empty if not found)
+	FilesChanged []string // Workflow files that were (or would be) rewritten
+	InvalidFiles []string // Workflow files left unchanged because the update would make them invalid, with the problems
+	Changes      []string // Schema transformations applied by the patcher
+	Err          error    // Set when the repository could not be updated
+}
+
+// NewApplier creates a new applier rooted at the given working directory
+func NewApplier(workdir string) *Applier {
+	return NewApplierWithConfig(workdir, &Config{Verbose: false})
+}
+
+// NewApplierWithConfig creates a new applier with configuration
+func NewApplierWithConfig(workdir string, config *Config) *Applier {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	return &Applier{
+		workdir:    workdir,
+		patcher:    patcher.NewWorkflowPatcher(),
+		verbose:    config.Verbose,
+		dryRun:     config.DryRun,
+		validation: &workflow.ValidationConfig{Simulate: config.Simulate},
+	}
+}
+
+// ApplyPlans applies each update plan to its local checkout, returning one result per plan
+func (a *Applier) ApplyPlans(plans []pr.UpdatePlan) []Result {
+	var results []Result
+
+	for _, plan := range plans {
+		results = append(results, a.applyPlan(plan))
+	}
+
+	return results
+}
+
+// RepositoryDir locates the checkout for a repository under the working directory
+// Supported layouts are <workdir>/<owner>/<name> and <workdir>/<name>; a working
+// directory that is itself the checkout of a repository with the same name also matches.
+func (a *Applier) RepositoryDir(owner, name string) (string, error) {
+	candidates := []string{
+		filepath.Join(a.workdir, owner, name),
+		filepath.Join(a.workdir, name),
+	}
+	if filepath.Base(filepath.Clean(a.workdir)) == name {
+		candidates = append(candidates, a.workdir)
+	}
+
+	for _, candidate := range candidates {
+		info, err := os.Stat(candidate)
+		if err == nil && info.IsDir() {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("no local checkout found for %s/%s under %s", owner, name, a.workdir)
+}
+
+// applyPlan rewrites all workflow files referenced by a single plan
+func (a *Applier) applyPlan(plan pr.UpdatePlan) Result {
+	result := Result{Repository: plan.Repository.FullName}
+
+	repoDir, err := a.RepositoryDir(plan.Repository.Owner, plan.Repository.Name)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Directory = repoDir
+
+	// Group updates by workflow file so each file is read and written once
+	updatesByFile := make(map[string][]pr.ActionUpdate)
+	for _, update := range plan.Updates {
+		updatesByFile[update.FilePath] = append(updatesByFile[update.FilePath], update)
+	}
+
+	filePaths := make([]string, 0, len(updatesByFile))
+	for filePath := range updatesByFile {
+		filePaths = append(filePaths, filePath)
+	}
+	sort.Strings(filePaths)
+
+	for _, filePath := range filePaths {
+		changed, changes, err := a.applyFile(repoDir, filePath, updatesByFile[filePath])
+		if errors.Is(err, workflow.ErrInvalidWorkflow) {
+			// Other files in the repository can still be updated safely
+			log.Printf("Warning: Not updating %s in %s: %v", filePath, plan.Repository.FullName, err)
+			result.InvalidFiles = append(result.InvalidFiles, fmt.Sprintf("%s: %v", filePath, err))
+			continue
+		}
+		if err != nil {
+			result.Err = fmt.Errorf("failed to update %s: %w", filePath, err)
+			return result
+		}
+		if changed {
+			result.FilesChanged = append(result.FilesChanged, filePath)
+		}
+		result.Changes = append(result.Changes, changes...)
+	}
+
+	return result
+}
+
+// applyFile updates a single workflow file, reporting whether its content changed
+func (a *Applier) applyFile(repoDir, filePath string, updates []pr.ActionUpdate) (bool, []string, error) {
+	fullPath, err := resolveWithin(repoDir, filePath)
+	if err != nil {
+		return false, nil, err
+	}
+
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return false, nil, fmt.Errorf("unable to stat workflow file: %w", err)
+	}
+	if info.Size() > workflow.DefaultMaxFileSize {
+		return false, nil, fmt.Errorf("%w: %d bytes (limit %d)", workflow.ErrWorkflowTooLarge, info.Size(), workflow.DefaultMaxFileSize)
+	}
+
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return false, nil, fmt.Errorf("unable to read workflow file: %w", err)
+	}
+
+	updated, changes, err := pr.PatchWorkflowContentWithValidation(a.patcher, string(content), updates, a.validation)
+	if err != nil {
+		return false, nil, err
+	}
+
+	if updated == string(content) {
+		if a.verbose {
+			log.Printf("No changes needed for %s", fullPath)
+		}
+		return false, changes, nil
+	}
+
+	if a.dryRun {
+		if a.verbose {
+			log.Printf("Dry run: would rewrite %s", fullPath)
+		}
+		return true, changes, nil
+	}
+
+	if err := os.WriteFile(fullPath, []byte(updated), info.Mode().Perm()); err != nil {
+		return false, nil, fmt.Errorf("unable to write workflow file: %w", err)
+	}
+
+	if a.verbose {
+		log.Printf("Rewrote %s with %d updates", fullPath, len(updates))
+	}
+
+	return true, changes, nil
+}
+
+// resolveWithin joins a slash-separated relative path to root, rejecting paths that escape it
+func resolveWithin(root, relPath string) (string, error) {
+	if relPath == "" || filepath.IsAbs(filepath.FromSlash(relPath)) {
+		return "", fmt.Errorf("invalid workflow file path %q", relPath)
+	}
+
+	fullPath := filepath.Join(root, filepath.FromSlash(relPath))
+	rel, err := filepath.Rel(root, fullPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("workflow file path %q escapes repository directory", relPath)
+	}
+
+	return fullPath, nil
+}
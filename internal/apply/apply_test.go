@@ -0,0 +1,177 @@
+package apply
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/pr"
+)
+
+const testWorkflow = `name: CI
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: example-org/custom-action@v1
+`
+
+func writeWorkflow(t *testing.T, repoDir string) string {
+	t.Helper()
+	workflowDir := filepath.Join(repoDir, ".github", "workflows")
+	if err := os.MkdirAll(workflowDir, 0o755); err != nil {
+		t.Fatalf("Failed to create workflow dir: %v", err)
+	}
+	path := filepath.Join(workflowDir, "ci.yml")
+	if err := os.WriteFile(path, []byte(testWorkflow), 0o644); err != nil {
+		t.Fatalf("Failed to write workflow: %v", err)
+	}
+	return path
+}
+
+func testPlan() pr.UpdatePlan {
+	return pr.UpdatePlan{
+		Repository: github.Repository{Owner: "my-org", Name: "api", FullName: "my-org/api"},
+		Updates: []pr.ActionUpdate{
+			{
+				FilePath:       ".github/workflows/ci.yml",
+				ActionRepo:     "example-org/custom-action",
+				CurrentVersion: "v1",
+				TargetVersion:  "v2",
+			},
+		},
+	}
+}
+
+func TestApplyPlans_RewritesWorkflow(t *testing.T) {
+	workdir := t.TempDir()
+	path := writeWorkflow(t, filepath.Join(workdir, "my-org", "api"))
+
+	results := NewApplier(workdir).ApplyPlans([]pr.UpdatePlan{testPlan()})
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	if results[0].Err != nil {
+		t.Fatalf("Unexpected error: %v", results[0].Err)
+	}
+	if len(results[0].FilesChanged) != 1 {
+		t.Errorf("Expected 1 changed file, got %d", len(results[0].FilesChanged))
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read workflow: %v", err)
+	}
+	if !strings.Contains(string(content), "example-org/custom-action@v2") {
+		t.Errorf("Expected workflow to reference v2, got:\n%s", content)
+	}
+}
+
+func TestApplyPlans_DryRunLeavesFileUntouched(t *testing.T) {
+	workdir := t.TempDir()
+	path := writeWorkflow(t, filepath.Join(workdir, "api"))
+
+	applier := NewApplierWithConfig(workdir, &Config{DryRun: true})
+	results := applier.ApplyPlans([]pr.UpdatePlan{testPlan()})
+	if results[0].Err != nil {
+		t.Fatalf("Unexpected error: %v", results[0].Err)
+	}
+	if len(results[0].FilesChanged) != 1 {
+		t.Errorf("Expected dry run to report 1 changed file, got %d", len(results[0].FilesChanged))
+	}
+
+	content, _ := os.ReadFile(path)
+	if string(content) != testWorkflow {
+		t.Errorf("Expected dry run not to modify the workflow file")
+	}
+}
+
+func TestApplyPlans_MissingCheckout(t *testing.T) {
+	results := NewApplier(t.TempDir()).ApplyPlans([]pr.UpdatePlan{testPlan()})
+	if results[0].Err == nil {
+		t.Errorf("Expected error when checkout is missing")
+	}
+}
+
+func TestResolveWithin_RejectsEscapingPaths(t *testing.T) {
+	root := t.TempDir()
+
+	for _, path := range []string{"../outside.yml", ".github/../../outside.yml", ""} {
+		if _, err := resolveWithin(root, path); err == nil {
+			t.Errorf("Expected error for path %q", path)
+		}
+	}
+
+	if _, err := resolveWithin(root, ".github/workflows/ci.yml"); err != nil {
+		t.Errorf("Unexpected error for valid path: %v", err)
+	}
+}
+
+func TestApplyPlans_PreservesCRLFLineEndings(t *testing.T) {
+	workdir := t.TempDir()
+	path := writeWorkflow(t, filepath.Join(workdir, "api"))
+	crlf := strings.ReplaceAll(testWorkflow, "\n", "\r\n")
+	if err := os.WriteFile(path, []byte(crlf), 0o644); err != nil {
+		t.Fatalf("Failed to write workflow: %v", err)
+	}
+
+	results := NewApplier(workdir).ApplyPlans([]pr.UpdatePlan{testPlan()})
+	if results[0].Err != nil {
+		t.Fatalf("Unexpected error: %v", results[0].Err)
+	}
+
+	content, _ := os.ReadFile(path)
+	if !strings.Contains(string(content), "example-org/custom-action@v2\r\n") {
+		t.Errorf("Expected updated reference with CRLF ending, got %q", content)
+	}
+	if strings.Count(string(content), "\n") != strings.Count(string(content), "\r\n") {
+		t.Errorf("Expected all line endings to remain CRLF, got %q", content)
+	}
+}
+
+func TestApplyPlans_LeavesInvalidResultUnchanged(t *testing.T) {
+	workdir := t.TempDir()
+	path := writeWorkflow(t, filepath.Join(workdir, "api"))
+
+	// A target version with a space would leave a uses line GitHub cannot resolve
+	plan := testPlan()
+	plan.Updates[0].TargetVersion = "v2 beta"
+
+	results := NewApplier(workdir).ApplyPlans([]pr.UpdatePlan{plan})
+	if results[0].Err != nil {
+		t.Fatalf("Unexpected error: %v", results[0].Err)
+	}
+	if len(results[0].FilesChanged) != 0 {
+		t.Errorf("Expected no changed files, got %v", results[0].FilesChanged)
+	}
+	if len(results[0].InvalidFiles) != 1 || !strings.HasPrefix(results[0].InvalidFiles[0], ".github/workflows/ci.yml: ") {
+		t.Errorf("Expected ci.yml to be flagged as invalid, got %v", results[0].InvalidFiles)
+	}
+
+	content, _ := os.ReadFile(path)
+	if string(content) != testWorkflow {
+		t.Errorf("Expected the invalid update not to be written, got:\n%s", content)
+	}
+}
+
+func TestApplyPlans_SimulateOnlyBlamesNewProblems(t *testing.T) {
+	workdir := t.TempDir()
+	path := writeWorkflow(t, filepath.Join(workdir, "api"))
+
+	// The matrix reference without a strategy predates the update, so it does not block it
+	existing := strings.Replace(testWorkflow, "ubuntu-latest", "${{ matrix.os }}", 1)
+	if err := os.WriteFile(path, []byte(existing), 0o644); err != nil {
+		t.Fatalf("Failed to write workflow: %v", err)
+	}
+
+	results := NewApplierWithConfig(workdir, &Config{Simulate: true}).ApplyPlans([]pr.UpdatePlan{testPlan()})
+	if results[0].Err != nil {
+		t.Fatalf("Unexpected error: %v", results[0].Err)
+	}
+	if len(results[0].InvalidFiles) != 0 || len(results[0].FilesChanged) != 1 {
+		t.Errorf("Expected the update to be applied, got changed %v and invalid %v", results[0].FilesChanged, results[0].InvalidFiles)
+	}
+}
@@ -0,0 +1,153 @@
+package approvals
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/pr"
+)
+
+// header explains the approvals file to the people reviewing it
+const header = `# Planned updates awaiting approval, written by report --emit-approvals.
+# Set approved: true on each update to make, then run create-pr --approvals <this file>.
+# Updates that are not approved, or not listed, get no pull request.
+`
+
+// Entry is a planned update awaiting approval
+type Entry struct {
+	Repository     string `yaml:"repository"`
+	File           string `yaml:"file"`
+	Action         string `yaml:"action"` // Action repository, with the path of a reusable workflow or nested action
+	CurrentVersion string `yaml:"current_version,omitempty"`
+	Target         string `yaml:"target,omitempty"` // Suggested version, or the migration target as "repo[/path]@version"
+	IssueType      string `yaml:"issue_type"`
+	Description    string `yaml:"description,omitempty"`
+	Approved       bool   `yaml:"approved"`
+}
+
+// File is a list of planned updates and whether each is approved
+type File struct {
+	GeneratedAt time.Time `yaml:"generated_at"`
+	Updates     []Entry   `yaml:"updates"`
+}
+
+// key identifies the update an entry approves; the description is informational only
+func (e Entry) key() string {
+	return strings.Join([]string{e.Repository, e.File, e.Action, e.CurrentVersion, e.Target, e.IssueType}, "|")
+}
+
+// entryFor returns the entry approving the update that fixes an issue of a repository
+func entryFor(repository string, issue output.ActionIssue) Entry {
+	action := issue.Repository
+	if issue.WorkflowPath != "" {
+		action += "/" + issue.WorkflowPath
+	}
+	target := issue.SuggestedVersion
+	if issue.MigrationTarget != "" {
+		target = issue.MigrationTarget
+	}
+	return Entry{
+		Repository:     repository,
+		File:           issue.FilePath,
+		Action:         action,
+		CurrentVersion: issue.CurrentVersion,
+		Target:         target,
+		IssueType:      issue.IssueType,
+		Description:    issue.Description,
+	}
+}
+
+// Build lists the updates create-pr would make for the repositories, none of them approved
+func Build(repositories []output.RepositoryResult) *File {
+	file := &File{GeneratedAt: time.Now().UTC(), Updates: []Entry{}}
+	for _, plan := range pr.PlanUpdates(repositories) {
+		for _, update := range plan.Updates {
+			file.Updates = append(file.Updates, entryFor(plan.Repository.FullName, update.Issue))
+		}
+	}
+	return file
+}
+
+// KeepApprovals approves the entries of the file that a previous file already approved, so
+// regenerating the file after a rescan does not discard earlier decisions
+func (f *File) KeepApprovals(previous *File) {
+	approved := previous.approved()
+	for i := range f.Updates {
+		if approved[f.Updates[i].key()] {
+			f.Updates[i].Approved = true
+		}
+	}
+}
+
+// Approved returns the number of approved entries
+func (f *File) Approved() int {
+	return len(f.approved())
+}
+
+// approved returns the keys of the approved entries
+func (f *File) approved() map[string]bool {
+	keys := make(map[string]bool)
+	for _, entry := range f.Updates {
+		if entry.Approved {
+			keys[entry.key()] = true
+		}
+	}
+	return keys
+}
+
+// ExcludeUnapproved removes the issues whose updates are not approved from each repository in place
+// It returns the number of issues removed.
+func (f *File) ExcludeUnapproved(repositories []output.RepositoryResult) int {
+	approved := f.approved()
+	removed := 0
+	for i := range repositories {
+		var issues []output.ActionIssue
+		for _, issue := range repositories[i].Issues {
+			if !approved[entryFor(repositories[i].FullName, issue).key()] {
+				removed++
+				continue
+			}
+			issues = append(issues, issue)
+		}
+		repositories[i].Issues = issues
+	}
+	return removed
+}
+
+// Load reads an approvals file
+func Load(filename string) (*File, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read approvals file: %w", err)
+	}
+
+	file := &File{}
+	if err := yaml.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("unable to parse approvals file as YAML: %w", err)
+	}
+	return file, nil
+}
+
+// Save writes the approvals file with a header explaining how to approve updates
+func (f *File) Save(filename string) error {
+	var buf bytes.Buffer
+	buf.WriteString(header)
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(f); err != nil {
+		return fmt.Errorf("failed to encode approvals file: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to encode approvals file: %w", err)
+	}
+	if err := os.WriteFile(filename, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write approvals file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package approvals
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+func approvalRepositories() []output.RepositoryResult {
+	return []output.RepositoryResult{
+		{
+			Name:     "app",
+			FullName: "my-org/app",
+			Issues: []output.ActionIssue{
+				{Repository: "actions/checkout", CurrentVersion: "v3", SuggestedVersion: "v4", IssueType: "outdated", FilePath: ".github/workflows/ci.yml"},
+				{Repository: "actions/setup-node", CurrentVersion: "v3", SuggestedVersion: "v4", IssueType: "outdated", FilePath: ".github/workflows/ci.yml"},
+				{Repository: "actions/cache", CurrentVersion: "v3", IssueType: "comment-drift", FilePath: ".github/workflows/ci.yml"},
+			},
+		},
+		{
+			Name:     "api",
+			FullName: "my-org/api",
+			Issues: []output.ActionIssue{
+				{Repository: "old-org/deploy", CurrentVersion: "v1", MigrationTarget: "new-org/deploy@v2", IssueType: "migration", FilePath: ".github/workflows/deploy.yml"},
+			},
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	file := Build(approvalRepositories())
+
+	// The comment drift issue has no fix, so it is not planned
+	if len(file.Updates) != 3 {
+		t.Fatalf("Expected 3 planned updates, got %+v", file.Updates)
+	}
+	for _, entry := range file.Updates {
+		if entry.Approved {
+			t.Errorf("Expected new entries to be unapproved, got %+v", entry)
+		}
+	}
+	migration := file.Updates[2]
+	if migration.Repository != "my-org/api" || migration.Target != "new-org/deploy@v2" {
+		t.Errorf("Expected the migration target in the api entry, got %+v", migration)
+	}
+}
+
+func TestExcludeUnapproved(t *testing.T) {
+	file := Build(approvalRepositories())
+	file.Updates[0].Approved = true
+
+	repositories := approvalRepositories()
+	removed := file.ExcludeUnapproved(repositories)
+	if removed != 3 {
+		t.Errorf("Expected 3 issues removed, got %d", removed)
+	}
+	if len(repositories[0].Issues) != 1 || repositories[0].Issues[0].Repository != "actions/checkout" {
+		t.Errorf("Expected only the approved checkout update in app, got %+v", repositories[0].Issues)
+	}
+	if len(repositories[1].Issues) != 0 {
+		t.Errorf("Expected no approved updates in api, got %+v", repositories[1].Issues)
+	}
+}
+
+func TestExcludeUnapproved_ChangedTarget(t *testing.T) {
+	file := Build(approvalRepositories())
+	for i := range file.Updates {
+		file.Updates[i].Approved = true
+	}
+
+	// A rescan suggesting a different version needs a new approval
+	repositories := approvalRepositories()
+	repositories[0].Issues[0].SuggestedVersion = "v5"
+	file.ExcludeUnapproved(repositories)
+	for _, issue := range repositories[0].Issues {
+		if issue.Repository == "actions/checkout" {
+			t.Errorf("Expected the checkout update to v5 to be unapproved, got %+v", issue)
+		}
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "approvals.yaml")
+	file := Build(approvalRepositories())
+	if err := file.Save(path); err != nil {
+		t.Fatalf("Expected no error saving, got %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected no error reading, got %v", err)
+	}
+	if !strings.HasPrefix(string(data), "# Planned updates") || !strings.Contains(string(data), "approved: false") {
+		t.Errorf("Expected a header and unapproved entries, got:\n%s", data)
+	}
+
+	// Approve an entry by hand, as a reviewer would
+	edited := strings.Replace(string(data), "approved: false", "approved: true", 1)
+	if err := os.WriteFile(path, []byte(edited), 0o644); err != nil {
+		t.Fatalf("Expected no error writing, got %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Expected no error loading, got %v", err)
+	}
+	if loaded.Approved() != 1 || !loaded.Updates[0].Approved {
+		t.Errorf("Expected the first entry approved, got %+v", loaded.Updates)
+	}
+
+	regenerated := Build(approvalRepositories())
+	regenerated.KeepApprovals(loaded)
+	if regenerated.Approved() != 1 || !regenerated.Updates[0].Approved {
+		t.Errorf("Expected the approval kept when regenerating, got %+v", regenerated.Updates)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "approvals.yaml")
+	if err := os.WriteFile(path, []byte("updates: [unclosed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Expected an error for invalid YAML")
+	}
+}
@@ -0,0 +1,203 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout bounds a single webhook delivery
+const DefaultTimeout = 30 * time.Second
+
+// Mutating operations recorded in the audit log
+const (
+	ActionBranchCreated   = "branch_created"
+	ActionFileModified    = "file_modified"
+	ActionPROpened        = "pr_opened"
+	ActionPRUpdated       = "pr_updated"
+	ActionBranchDeleted   = "branch_deleted"
+	ActionIssueOpened     = "issue_opened"
+	ActionReviewPosted    = "review_posted"
+	ActionAdvisoryDrafted = "advisory_drafted"
+)
+
+// Event is one audit log entry, written as a single JSON line (file) or request body (webhook)
+type Event struct {
+	Time       time.Time `json:"time"`
+	Action     string    `json:"action"`
+	Actor      string    `json:"actor"`                 // Login of the token owner performing the operation
+	Command    string    `json:"command,omitempty"`     // Subcommand that performed the operation
+	Repository string    `json:"repository"`            // Target repository full name
+	Branch     string    `json:"branch,omitempty"`      // Branch created, committed to, or deleted
+	Path       string    `json:"path,omitempty"`        // File modified
+	URL        string    `json:"url,omitempty"`         // Pull request or issue opened or updated
+	PRNumber   int       `json:"pr_number,omitempty"`   // Pull request opened or updated
+	BaseBranch string    `json:"base_branch,omitempty"` // Branch a pull request targets
+}
+
+// Config holds configuration options for the audit log
+type Config struct {
+	Target     string        // File appended to, or an http(s) URL each event is POSTed to
+	Actor      string        // Identity recorded on every event
+	Command    string        // Subcommand recorded on every event
+	Timeout    time.Duration // Zero uses DefaultTimeout
+	HTTPClient *http.Client  // Nil uses the default client
+}
+
+// Logger appends events to the audit log
+// The first delivery failure is kept and reported by Close, so a run with an incomplete audit trail fails.
+type Logger struct {
+	file       *os.File
+	webhookURL string
+	actor      string
+	command    string
+	timeout    time.Duration
+	httpClient *http.Client
+	now        func() time.Time
+
+	mu  sync.Mutex
+	err error
+}
+
+// Open opens the audit log, or returns nil when no target is configured
+// Files are opened for appending only and created when missing; existing entries are never rewritten.
+func Open(config *Config) (*Logger, error) {
+	if config == nil || config.Target == "" {
+		return nil, nil
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	logger := &Logger{
+		actor:      config.Actor,
+		command:    config.Command,
+		timeout:    timeout,
+		httpClient: httpClient,
+		now:        time.Now,
+	}
+
+	if isWebhook(config.Target) {
+		logger.webhookURL = config.Target
+		return logger, nil
+	}
+
+	file, err := os.OpenFile(config.Target, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	logger.file = file
+	return logger, nil
+}
+
+// isWebhook reports whether an audit log target is a URL rather than a file
+func isWebhook(target string) bool {
+	return strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://")
+}
+
+// Record appends an event, stamping it with the time, actor, and command
+func (l *Logger) Record(event Event) error {
+	if l == nil {
+		return nil
+	}
+
+	if event.Time.IsZero() {
+		event.Time = l.now().UTC()
+	}
+	if event.Actor == "" {
+		event.Actor = l.actor
+	}
+	if event.Command == "" {
+		event.Command = l.command
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return l.fail(fmt.Errorf("failed to encode audit event: %w", err))
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch {
+	case l.webhookURL != "":
+		err = l.post(payload)
+	case l.file == nil:
+		err = fmt.Errorf("audit log is closed")
+	default:
+		// One write per line keeps entries whole when several runs append to the same file
+		_, err = l.file.Write(append(payload, '\n'))
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to record %s for %s: %w", event.Action, event.Repository, err)
+		if l.err == nil {
+			l.err = err
+		}
+	}
+	return err
+}
+
+// fail keeps the first error for Close and returns it
+func (l *Logger) fail(err error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err == nil {
+		l.err = err
+	}
+	return err
+}
+
+// post sends one event to the webhook
+func (l *Logger) post(payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Close closes the audit log file and returns the first error recorded while writing to it
+func (l *Logger) Close() error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file != nil {
+		if err := l.file.Close(); err != nil && l.err == nil {
+			l.err = fmt.Errorf("failed to close audit log: %w", err)
+		}
+		l.file = nil
+	}
+	return l.err
+}
@@ -0,0 +1,126 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOpen_Unconfigured(t *testing.T) {
+	logger, err := Open(&Config{})
+	if err != nil || logger != nil {
+		t.Fatalf("Expected nil logger when no target is configured, got %v, %v", logger, err)
+	}
+	if err := logger.Record(Event{Action: ActionPROpened}); err != nil {
+		t.Errorf("Expected nil logger to accept events, got %v", err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Errorf("Expected nil logger to close cleanly, got %v", err)
+	}
+}
+
+func TestRecord_AppendsToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	if err := os.WriteFile(path, []byte(`{"action":"earlier"}`+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	logger, err := Open(&Config{Target: path, Actor: "octocat", Command: "create-pr"})
+	if err != nil {
+		t.Fatalf("Open() returned error: %v", err)
+	}
+	logger.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+
+	if err := logger.Record(Event{Action: ActionBranchCreated, Repository: "my-org/api", Branch: "actions-maintainer/update"}); err != nil {
+		t.Fatalf("Record() returned error: %v", err)
+	}
+	if err := logger.Record(Event{Action: ActionFileModified, Repository: "my-org/api", Path: ".github/workflows/ci.yml"}); err != nil {
+		t.Fatalf("Record() returned error: %v", err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() returned error: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) != 3 || lines[0] != `{"action":"earlier"}` {
+		t.Fatalf("Expected existing entries kept and 2 appended, got %v", lines)
+	}
+
+	var event Event
+	if err := json.Unmarshal([]byte(lines[1]), &event); err != nil {
+		t.Fatalf("Expected a JSON line, got %q: %v", lines[1], err)
+	}
+	if event.Action != ActionBranchCreated || event.Actor != "octocat" || event.Command != "create-pr" ||
+		event.Repository != "my-org/api" || event.Branch != "actions-maintainer/update" {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+	if !event.Time.Equal(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected event timestamp, got %v", event.Time)
+	}
+}
+
+func TestRecord_Webhook(t *testing.T) {
+	var received []Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("Expected JSON body: %v", err)
+		}
+		received = append(received, event)
+	}))
+	defer server.Close()
+
+	logger, err := Open(&Config{Target: server.URL, Actor: "octocat"})
+	if err != nil {
+		t.Fatalf("Open() returned error: %v", err)
+	}
+	if err := logger.Record(Event{Action: ActionPROpened, Repository: "my-org/api", PRNumber: 7}); err != nil {
+		t.Fatalf("Record() returned error: %v", err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() returned error: %v", err)
+	}
+
+	if len(received) != 1 || received[0].Action != ActionPROpened || received[0].PRNumber != 7 || received[0].Actor != "octocat" {
+		t.Errorf("Unexpected webhook events: %+v", received)
+	}
+}
+
+func TestClose_ReportsFailedDelivery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	logger, err := Open(&Config{Target: server.URL})
+	if err != nil {
+		t.Fatalf("Open() returned error: %v", err)
+	}
+	if err := logger.Record(Event{Action: ActionBranchDeleted, Repository: "my-org/api"}); err == nil {
+		t.Fatalf("Expected a rejected event to fail")
+	}
+	if err := logger.Record(Event{Action: ActionBranchDeleted, Repository: "my-org/web"}); err == nil {
+		t.Fatalf("Expected a rejected event to fail")
+	}
+
+	err = logger.Close()
+	if err == nil || !strings.Contains(err.Error(), "my-org/api") {
+		t.Errorf("Expected Close to report the first failure, got %v", err)
+	}
+}
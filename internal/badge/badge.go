@@ -0,0 +1,223 @@
+package badge
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// Label is the left-hand text of every badge
+const Label = "actions"
+
+// severityPenalty is the points an issue of each severity takes off a repository's score of 100
+var severityPenalty = map[string]int{
+	"critical": 25,
+	"high":     10,
+	"medium":   4,
+	"low":      1,
+}
+
+// grades are the lowest scores of each grade, best first, with the badge color of the grade
+var grades = []struct {
+	grade    string
+	minScore int
+	color    string
+}{
+	{"A", 90, "brightgreen"},
+	{"B", 75, "green"},
+	{"C", 60, "yellow"},
+	{"D", 40, "orange"},
+	{"F", 0, "red"},
+}
+
+// colors are the fill colors of shields.io named colors
+var colors = map[string]string{
+	"brightgreen": "#4c1",
+	"green":       "#97ca00",
+	"yellow":      "#dfb317",
+	"orange":      "#fe7d37",
+	"red":         "#e05d44",
+}
+
+// Grade is the actions hygiene grade of a repository
+type Grade struct {
+	Repository string
+	Score      int    // 100 less the penalties of the repository's issues, at least 0
+	Grade      string // A to F
+	Color      string // shields.io named color of the grade
+	Issues     int
+}
+
+// Message is the right-hand text of the repository's badge, e.g. "B (82)"
+func (g Grade) Message() string {
+	return fmt.Sprintf("%s (%d)", g.Grade, g.Score)
+}
+
+// Score grades a scanned repository from its issues; suppressed issues do not count
+func Score(repo output.RepositoryResult) Grade {
+	score := 100
+	for _, issue := range repo.Issues {
+		score -= severityPenalty[issue.Severity]
+	}
+	if score < 0 {
+		score = 0
+	}
+
+	grade := Grade{Repository: repo.FullName, Score: score, Issues: len(repo.Issues)}
+	for _, g := range grades {
+		if score >= g.minScore {
+			grade.Grade, grade.Color = g.grade, g.color
+			break
+		}
+	}
+	return grade
+}
+
+// ScoreAll grades every scanned repository of a scan, by repository name; repositories whose
+// workflows were not scanned get no grade
+func ScoreAll(result *output.ScanResult) []Grade {
+	var grades []Grade
+	for _, repo := range result.Repositories {
+		if repo.Scanned() {
+			grades = append(grades, Score(repo))
+		}
+	}
+	sort.Slice(grades, func(i, j int) bool {
+		return grades[i].Repository < grades[j].Repository
+	})
+	return grades
+}
+
+// Endpoint returns the shields.io endpoint JSON of a grade, for badges rendered by shields.io
+func Endpoint(grade Grade) []byte {
+	data, _ := json.MarshalIndent(struct {
+		SchemaVersion int    `json:"schemaVersion"`
+		Label         string `json:"label"`
+		Message       string `json:"message"`
+		Color         string `json:"color"`
+	}{1, Label, grade.Message(), grade.Color}, "", "  ")
+	return append(data, '\n')
+}
+
+// SVG returns a flat badge of a grade in the style of shields.io
+func SVG(grade Grade) []byte {
+	label, message := Label, grade.Message()
+	labelWidth, messageWidth := textWidth(label), textWidth(message)
+	width := labelWidth + messageWidth
+	title := html.EscapeString(label + ": " + message)
+
+	svg := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s">`+
+		`<title>%s</title>`+
+		`<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`+
+		`<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`+
+		`<g clip-path="url(#r)"><rect width="%d" height="20" fill="#555"/><rect x="%d" width="%d" height="20" fill="%s"/><rect width="%d" height="20" fill="url(#s)"/></g>`+
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`+
+		`<text x="%d" y="14">%s</text><text x="%d" y="14">%s</text></g></svg>`+"\n",
+		width, title, title,
+		width,
+		labelWidth, labelWidth, messageWidth, colors[grade.Color], width,
+		labelWidth/2, html.EscapeString(label), labelWidth+messageWidth/2, html.EscapeString(message))
+	return []byte(svg)
+}
+
+// textWidth approximates the width of badge text in 11px Verdana, with padding on both sides
+func textWidth(text string) int {
+	return 7*len(text) + 10
+}
+
+// Formats of badge files
+const (
+	FormatSVG      = "svg"      // <owner>/<name>.svg
+	FormatEndpoint = "endpoint" // <owner>/<name>.json, for https://img.shields.io/endpoint
+	FormatBoth     = "both"
+)
+
+// ParseFormat validates a badge format; "" is FormatSVG
+func ParseFormat(value string) (string, error) {
+	switch value {
+	case "":
+		return FormatSVG, nil
+	case FormatSVG, FormatEndpoint, FormatBoth:
+		return value, nil
+	}
+	return "", fmt.Errorf("unknown badge format %q (expected %s, %s, or %s)", value, FormatSVG, FormatEndpoint, FormatBoth)
+}
+
+// Files returns the badge files of a grade in a format, keyed by slash-separated path
+func Files(grade Grade, format string) map[string][]byte {
+	files := make(map[string][]byte)
+	if format == FormatSVG || format == FormatBoth {
+		files[grade.Repository+".svg"] = SVG(grade)
+	}
+	if format == FormatEndpoint || format == FormatBoth {
+		files[grade.Repository+".json"] = Endpoint(grade)
+	}
+	return files
+}
+
+// WriteDir writes the badge files of every grade under dir, returning the paths written
+func WriteDir(dir string, grades []Grade, format string) ([]string, error) {
+	var written []string
+	for _, grade := range grades {
+		files := Files(grade, format)
+		for _, name := range sortedNames(files) {
+			path := filepath.Join(dir, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return written, fmt.Errorf("failed to create badge directory: %w", err)
+			}
+			if err := os.WriteFile(path, files[name], 0o644); err != nil {
+				return written, fmt.Errorf("failed to write badge: %w", err)
+			}
+			written = append(written, path)
+		}
+	}
+	return written, nil
+}
+
+// FileWriter commits files to a branch of a repository; *github.Client implements it
+type FileWriter interface {
+	PutFile(owner, repo, branch, filePath string, content []byte, message string) (bool, error)
+}
+
+// Publish commits the badge files of every grade under dir on a branch of target ("owner/name"),
+// such as a gh-pages branch, returning the number of files changed; unchanged badges are not committed
+func Publish(writer FileWriter, target, branch, dir string, grades []Grade, format string) (int, error) {
+	owner, repo, ok := strings.Cut(target, "/")
+	if !ok || owner == "" || repo == "" {
+		return 0, fmt.Errorf("invalid repository %q (expected owner/name)", target)
+	}
+
+	changed := 0
+	for _, grade := range grades {
+		files := Files(grade, format)
+		for _, name := range sortedNames(files) {
+			filePath := path.Join(dir, name)
+			message := fmt.Sprintf("Update actions badge of %s: %s", grade.Repository, grade.Message())
+			committed, err := writer.PutFile(owner, repo, branch, filePath, files[name], message)
+			if err != nil {
+				return changed, err
+			}
+			if committed {
+				changed++
+			}
+		}
+	}
+	return changed, nil
+}
+
+// sortedNames returns the paths of badge files in order
+func sortedNames(files map[string][]byte) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,142 @@
+package badge
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+func issues(severities ...string) []output.ActionIssue {
+	var list []output.ActionIssue
+	for _, severity := range severities {
+		list = append(list, output.ActionIssue{Repository: "actions/checkout", Severity: severity})
+	}
+	return list
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		issues   []output.ActionIssue
+		expected string
+		color    string
+	}{
+		{"no issues", nil, "A (100)", "brightgreen"},
+		{"low issues", issues("low", "low"), "A (98)", "brightgreen"},
+		{"one high", issues("high", "medium"), "B (86)", "green"},
+		{"one critical", issues("critical", "high"), "C (65)", "yellow"},
+		{"two critical", issues("critical", "critical", "medium"), "D (46)", "orange"},
+		{"clamped", issues("critical", "critical", "critical", "critical", "critical"), "F (0)", "red"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grade := Score(output.RepositoryResult{FullName: "my-org/api", Issues: tt.issues})
+			if grade.Message() != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, grade.Message())
+			}
+			if grade.Color != tt.color {
+				t.Errorf("Expected color %s, got %s", tt.color, grade.Color)
+			}
+		})
+	}
+}
+
+func TestScoreAll(t *testing.T) {
+	result := &output.ScanResult{Repositories: []output.RepositoryResult{
+		{FullName: "my-org/web", Issues: issues("high")},
+		{FullName: "my-org/archived", Status: "skipped"},
+		{FullName: "my-org/api"},
+	}}
+
+	grades := ScoreAll(result)
+	if len(grades) != 2 {
+		t.Fatalf("Expected 2 grades, got %+v", grades)
+	}
+	if grades[0].Repository != "my-org/api" || grades[1].Repository != "my-org/web" {
+		t.Errorf("Expected grades sorted by repository, got %s and %s", grades[0].Repository, grades[1].Repository)
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	var endpoint map[string]interface{}
+	if err := json.Unmarshal(Endpoint(Grade{Score: 82, Grade: "B", Color: "green"}), &endpoint); err != nil {
+		t.Fatalf("Expected JSON, got %v", err)
+	}
+	if endpoint["schemaVersion"] != float64(1) || endpoint["label"] != "actions" || endpoint["message"] != "B (82)" || endpoint["color"] != "green" {
+		t.Errorf("Expected a shields.io endpoint, got %v", endpoint)
+	}
+}
+
+func TestSVG(t *testing.T) {
+	svg := string(SVG(Grade{Score: 30, Grade: "F", Color: "red"}))
+	for _, expected := range []string{`<svg xmlns="http://www.w3.org/2000/svg"`, `aria-label="actions: F (30)"`, `fill="#e05d44"`, `>F (30)</text>`} {
+		if !strings.Contains(svg, expected) {
+			t.Errorf("Expected the badge to contain %q, got %s", expected, svg)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if format, err := ParseFormat(""); err != nil || format != FormatSVG {
+		t.Errorf("Expected the default format to be svg, got %q, %v", format, err)
+	}
+	if _, err := ParseFormat("png"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestWriteDir(t *testing.T) {
+	dir := t.TempDir()
+	grades := []Grade{Score(output.RepositoryResult{FullName: "my-org/api"})}
+
+	written, err := WriteDir(dir, grades, FormatBoth)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(written) != 2 {
+		t.Fatalf("Expected 2 files, got %v", written)
+	}
+	for _, name := range []string{"api.json", "api.svg"} {
+		if _, err := os.Stat(filepath.Join(dir, "my-org", name)); err != nil {
+			t.Errorf("Expected my-org/%s to be written, got %v", name, err)
+		}
+	}
+}
+
+type recordingWriter struct {
+	paths []string
+}
+
+func (w *recordingWriter) PutFile(owner, repo, branch, filePath string, content []byte, message string) (bool, error) {
+	w.paths = append(w.paths, owner+"/"+repo+"@"+branch+":"+filePath)
+	return filePath != "badges/my-org/web.svg", nil
+}
+
+func TestPublish(t *testing.T) {
+	writer := &recordingWriter{}
+	grades := []Grade{
+		Score(output.RepositoryResult{FullName: "my-org/api"}),
+		Score(output.RepositoryResult{FullName: "my-org/web"}),
+	}
+
+	changed, err := Publish(writer, "my-org/status", "gh-pages", "badges", grades, FormatSVG)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if changed != 1 {
+		t.Errorf("Expected 1 changed badge, got %d", changed)
+	}
+	expected := []string{"my-org/status@gh-pages:badges/my-org/api.svg", "my-org/status@gh-pages:badges/my-org/web.svg"}
+	if strings.Join(writer.paths, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, writer.paths)
+	}
+
+	if _, err := Publish(writer, "status", "gh-pages", "badges", grades, FormatSVG); err == nil {
+		t.Error("Expected an error for a repository without an owner")
+	}
+}
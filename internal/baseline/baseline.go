@@ -0,0 +1,140 @@
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// Baseline holds the issues recorded by a previous scan
+//
+// Issues are matched on the scanned repository, workflow file, action, issue type, and
+// pinned version, so an issue stops matching once its action version changes.
+type Baseline struct {
+	keys    map[string]bool
+	counts  map[string]int // Open issues per repository
+	history []output.SeveritySnapshot
+	created []output.CreatedPR // Pull requests recorded in the baseline scan
+}
+
+// LoadFile loads a baseline from a previous scan's JSON results file
+func LoadFile(filename string) (*Baseline, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open baseline file: %w", err)
+	}
+	defer file.Close()
+
+	return Load(file)
+}
+
+// Load parses a baseline from a previous scan's JSON results
+func Load(reader io.Reader) (*Baseline, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read baseline: %w", err)
+	}
+
+	var result output.ScanResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("unable to parse baseline as scan results JSON: %w", err)
+	}
+
+	return FromScanResult(&result), nil
+}
+
+// FromScanResult builds a baseline from scan results, including suppressed issues
+func FromScanResult(result *output.ScanResult) *Baseline {
+	b := &Baseline{
+		keys:    make(map[string]bool),
+		counts:  make(map[string]int),
+		history: output.SeverityHistoryFrom(result),
+		created: result.CreatedPRs,
+	}
+	for _, repo := range result.Repositories {
+		b.counts[repo.FullName] = len(repo.Issues)
+		for _, issue := range repo.Issues {
+			b.keys[issueKey(repo.FullName, issue)] = true
+		}
+		for _, suppressed := range repo.SuppressedIssues {
+			b.keys[issueKey(repo.FullName, suppressed.ActionIssue)] = true
+		}
+	}
+	return b
+}
+
+// Len returns the number of distinct issues in the baseline
+func (b *Baseline) Len() int {
+	if b == nil {
+		return 0
+	}
+	return len(b.keys)
+}
+
+// IssueCount returns the number of open issues the baseline scan found in a repository
+func (b *Baseline) IssueCount(repoFullName string) int {
+	if b == nil {
+		return 0
+	}
+	return b.counts[repoFullName]
+}
+
+// History returns the severity counts of the baseline scan and the scans before it, oldest first
+func (b *Baseline) History() []output.SeveritySnapshot {
+	if b == nil {
+		return nil
+	}
+	return b.history
+}
+
+// CreatedPRs returns the pull requests the baseline scan records, as run pipelines add them
+func (b *Baseline) CreatedPRs() []output.CreatedPR {
+	if b == nil {
+		return nil
+	}
+	return b.created
+}
+
+// Mark flags issues for a repository that are present in the baseline as existing
+// It returns the number of issues marked.
+func (b *Baseline) Mark(repoFullName string, issues []output.ActionIssue) int {
+	if b == nil {
+		return 0
+	}
+
+	marked := 0
+	for i := range issues {
+		if b.keys[issueKey(repoFullName, issues[i])] {
+			issues[i].Existing = true
+			marked++
+		}
+	}
+	return marked
+}
+
+// issueKey identifies an issue across scans
+func issueKey(repoFullName string, issue output.ActionIssue) string {
+	return strings.Join([]string{repoFullName, issue.FilePath, issue.Repository, issue.IssueType, issue.CurrentVersion}, "\x00")
+}
+
+// ExcludeExisting removes issues marked as existing from each repository in place
+// It returns the number of issues removed.
+func ExcludeExisting(repositories []output.RepositoryResult) int {
+	removed := 0
+	for i := range repositories {
+		var issues []output.ActionIssue
+		for _, issue := range repositories[i].Issues {
+			if issue.Existing {
+				removed++
+				continue
+			}
+			issues = append(issues, issue)
+		}
+		repositories[i].Issues = issues
+	}
+	return removed
+}
@@ -0,0 +1,128 @@
+package baseline
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+const previousScan = `{
+  "owner": "my-org",
+  "repositories": [
+    {
+      "full_name": "my-org/api",
+      "issues": [
+        {"repository": "actions/checkout", "current_version": "v3", "issue_type": "outdated", "severity": "medium", "file_path": ".github/workflows/ci.yml"}
+      ],
+      "suppressed_issues": [
+        {"repository": "actions/setup-node", "current_version": "v2", "issue_type": "deprecated", "severity": "high", "file_path": ".github/workflows/ci.yml"}
+      ]
+    }
+  ]
+}`
+
+func TestMark(t *testing.T) {
+	b, err := Load(strings.NewReader(previousScan))
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if b.Len() != 2 {
+		t.Errorf("Expected 2 baseline issues, got %d", b.Len())
+	}
+
+	issues := []output.ActionIssue{
+		{Repository: "actions/checkout", CurrentVersion: "v3", IssueType: "outdated", FilePath: ".github/workflows/ci.yml"},
+		{Repository: "actions/setup-node", CurrentVersion: "v2", IssueType: "deprecated", FilePath: ".github/workflows/ci.yml"},
+		{Repository: "actions/checkout", CurrentVersion: "v3", IssueType: "outdated", FilePath: ".github/workflows/release.yml"},
+		{Repository: "actions/cache", CurrentVersion: "v2", IssueType: "outdated", FilePath: ".github/workflows/ci.yml"},
+	}
+
+	if marked := b.Mark("my-org/api", issues); marked != 2 {
+		t.Errorf("Expected 2 issues marked existing, got %d", marked)
+	}
+	expected := []bool{true, true, false, false}
+	for i, issue := range issues {
+		if issue.Existing != expected[i] {
+			t.Errorf("Issue %d: expected existing=%v, got %v", i, expected[i], issue.Existing)
+		}
+	}
+
+	other := []output.ActionIssue{issues[0]}
+	other[0].Existing = false
+	if marked := b.Mark("my-org/web", other); marked != 0 {
+		t.Errorf("Expected issues in other repositories not to match, got %d", marked)
+	}
+}
+
+func TestMark_NilBaseline(t *testing.T) {
+	var b *Baseline
+	issues := []output.ActionIssue{{Repository: "actions/checkout", CurrentVersion: "v3"}}
+	if marked := b.Mark("my-org/api", issues); marked != 0 || issues[0].Existing {
+		t.Errorf("Expected nil baseline to mark nothing")
+	}
+}
+
+func TestIssueCount(t *testing.T) {
+	b, err := Load(strings.NewReader(previousScan))
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	// Suppressed issues are not counted
+	if count := b.IssueCount("my-org/api"); count != 1 {
+		t.Errorf("Expected 1 issue in my-org/api, got %d", count)
+	}
+	if count := b.IssueCount("my-org/web"); count != 0 {
+		t.Errorf("Expected no issues in an unknown repository, got %d", count)
+	}
+}
+
+func TestLoad_InvalidJSON(t *testing.T) {
+	if _, err := Load(strings.NewReader("not json")); err == nil {
+		t.Errorf("Expected error for invalid baseline JSON")
+	}
+}
+
+func TestExcludeExisting(t *testing.T) {
+	repositories := []output.RepositoryResult{
+		{FullName: "my-org/api", Issues: []output.ActionIssue{
+			{Repository: "actions/checkout", Existing: true},
+			{Repository: "actions/cache"},
+		}},
+	}
+
+	if removed := ExcludeExisting(repositories); removed != 1 {
+		t.Errorf("Expected 1 issue removed, got %d", removed)
+	}
+	if len(repositories[0].Issues) != 1 || repositories[0].Issues[0].Repository != "actions/cache" {
+		t.Errorf("Expected only new issues to remain, got %+v", repositories[0].Issues)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	previous := `{
+  "scan_time": "2026-02-01T00:00:00Z",
+  "summary": {
+    "issues_by_severity": {"high": 2},
+    "severity_history": [{"scan_time": "2026-01-01T00:00:00Z", "issues_by_severity": {"high": 4}}]
+  }
+}`
+
+	b, err := Load(strings.NewReader(previous))
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+
+	history := b.History()
+	if len(history) != 2 {
+		t.Fatalf("Expected 2 history entries, got %d", len(history))
+	}
+	if history[0].IssuesBySeverity["high"] != 4 || history[1].IssuesBySeverity["high"] != 2 {
+		t.Errorf("Expected history oldest first, got %+v", history)
+	}
+
+	var none *Baseline
+	if none.History() != nil {
+		t.Errorf("Expected nil baseline to have no history")
+	}
+}
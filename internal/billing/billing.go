@@ -0,0 +1,154 @@
+package billing
+
+import (
+	"log"
+	"math"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// DefaultWindowDays is how much run history is inspected when no window is configured
+const DefaultWindowDays = 30
+
+// DefaultSampleRuns is how many of a workflow's most recent runs have their billable time fetched
+const DefaultSampleRuns = 20
+
+// daysPerMonth normalizes estimates to a 30-day month
+const daysPerMonth = 30
+
+// runnerMultipliers weight billable minutes by runner environment, as GitHub does when consuming
+// included minutes; other environments count once
+var runnerMultipliers = map[string]int{
+	"UBUNTU":  1,
+	"WINDOWS": 2,
+	"MACOS":   10,
+}
+
+// RunTimingClient lists the runs of a workflow file and fetches their billable time
+type RunTimingClient interface {
+	ListWorkflowRuns(owner, repo, filePath string, since time.Time) ([]github.WorkflowRun, error)
+	GetWorkflowRunBillableMS(owner, repo string, runID int64) (map[string]int64, error)
+}
+
+// Config holds configuration options for Actions minutes estimation
+type Config struct {
+	Verbose    bool
+	WindowDays int // Days of run history to count; zero uses DefaultWindowDays
+	SampleRuns int // Recent runs whose billable time is fetched per workflow; zero uses DefaultSampleRuns
+}
+
+// Estimator estimates the monthly Actions minutes of each workflow file from its recent runs
+type Estimator struct {
+	client     RunTimingClient
+	windowDays int
+	sampleRuns int
+	verbose    bool
+	now        func() time.Time
+}
+
+// NewEstimator creates an estimator counting windowDays of run history
+func NewEstimator(client RunTimingClient, windowDays int) *Estimator {
+	return NewEstimatorWithConfig(client, &Config{Verbose: false, WindowDays: windowDays})
+}
+
+// NewEstimatorWithConfig creates an estimator with configuration
+func NewEstimatorWithConfig(client RunTimingClient, config *Config) *Estimator {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	windowDays := config.WindowDays
+	if windowDays <= 0 {
+		windowDays = DefaultWindowDays
+	}
+	sampleRuns := config.SampleRuns
+	if sampleRuns <= 0 {
+		sampleRuns = DefaultSampleRuns
+	}
+
+	return &Estimator{
+		client:     client,
+		windowDays: windowDays,
+		sampleRuns: sampleRuns,
+		verbose:    config.Verbose,
+		now:        time.Now,
+	}
+}
+
+// Estimate sets the minutes estimate of each analyzed workflow file in place
+// The runs within the window are counted, the billable time of the most recent ones is averaged,
+// and the total is scaled to a 30-day month. Only files in .github/workflows have runs.
+func (e *Estimator) Estimate(repoFullName string, files []output.WorkflowFileResult) {
+	parts := strings.SplitN(repoFullName, "/", 2)
+	if len(parts) != 2 {
+		return
+	}
+	owner, repo := parts[0], parts[1]
+	since := e.now().AddDate(0, 0, -e.windowDays)
+
+	for i := range files {
+		file := &files[i]
+		if file.Status != "" || path.Dir(file.Path) != ".github/workflows" {
+			continue
+		}
+
+		runs, err := e.client.ListWorkflowRuns(owner, repo, file.Path, since)
+		if err != nil {
+			if e.verbose {
+				log.Printf("Unable to list runs of %s in %s: %v", file.Path, repoFullName, err)
+			}
+			continue
+		}
+
+		estimate := &output.MinutesEstimate{WindowDays: e.windowDays, Runs: len(runs)}
+		billableMS := make(map[string]int64)
+		for _, run := range runs {
+			if estimate.SampledRuns == e.sampleRuns {
+				break
+			}
+			billable, err := e.client.GetWorkflowRunBillableMS(owner, repo, run.ID)
+			if err != nil {
+				if e.verbose {
+					log.Printf("Unable to get timing of run %d of %s in %s: %v", run.ID, file.Path, repoFullName, err)
+				}
+				continue
+			}
+			estimate.SampledRuns++
+			for runner, ms := range billable {
+				billableMS[runner] += ms
+			}
+		}
+
+		if estimate.SampledRuns > 0 {
+			e.extrapolate(estimate, billableMS)
+		}
+		file.Minutes = estimate
+	}
+}
+
+// extrapolate scales the billable time of the sampled runs to every run in the window, per month
+func (e *Estimator) extrapolate(estimate *output.MinutesEstimate, billableMS map[string]int64) {
+	scale := float64(estimate.Runs) / float64(estimate.SampledRuns) * daysPerMonth / float64(e.windowDays)
+
+	for runner, ms := range billableMS {
+		minutes := int(math.Round(float64(ms) / float64(time.Minute/time.Millisecond) * scale))
+		if minutes == 0 {
+			continue
+		}
+		if estimate.ByRunner == nil {
+			estimate.ByRunner = make(map[string]int)
+		}
+		estimate.ByRunner[runner] = minutes
+		estimate.MonthlyMinutes += minutes
+
+		multiplier, ok := runnerMultipliers[strings.ToUpper(runner)]
+		if !ok {
+			multiplier = 1
+		}
+		estimate.WeightedMinutes += minutes * multiplier
+	}
+}
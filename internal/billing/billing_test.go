@@ -0,0 +1,97 @@
+package billing
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// mockRunTiming returns fixed runs keyed by workflow path and billable time keyed by run ID
+type mockRunTiming struct {
+	runs    map[string][]github.WorkflowRun
+	billing map[int64]map[string]int64
+	fetched []int64
+}
+
+func (m *mockRunTiming) ListWorkflowRuns(owner, repo, filePath string, since time.Time) ([]github.WorkflowRun, error) {
+	if filePath == ".github/workflows/broken.yml" {
+		return nil, fmt.Errorf("not found")
+	}
+	return m.runs[filePath], nil
+}
+
+func (m *mockRunTiming) GetWorkflowRunBillableMS(owner, repo string, runID int64) (map[string]int64, error) {
+	m.fetched = append(m.fetched, runID)
+	billable, ok := m.billing[runID]
+	if !ok {
+		return nil, fmt.Errorf("run %d not found", runID)
+	}
+	return billable, nil
+}
+
+func TestEstimate_ExtrapolatesSampledRuns(t *testing.T) {
+	client := &mockRunTiming{
+		runs: map[string][]github.WorkflowRun{
+			".github/workflows/ci.yml":   {{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}},
+			".github/workflows/idle.yml": nil,
+		},
+		billing: map[int64]map[string]int64{
+			1: {"UBUNTU": 3 * 60000, "MACOS": 60000},
+			3: {"UBUNTU": 60000, "WINDOWS": 0},
+			4: {"UBUNTU": 600000},
+		},
+	}
+	estimator := NewEstimatorWithConfig(client, &Config{WindowDays: 15, SampleRuns: 2})
+
+	files := []output.WorkflowFileResult{
+		{Path: ".github/workflows/ci.yml"},
+		{Path: ".github/workflows/idle.yml"},
+		{Path: ".github/workflows/broken.yml"},
+		{Path: ".github/workflows/huge.yml", Status: output.WorkflowStatusSkippedTooLarge},
+		{Path: "ci/templates/build.yml"},
+	}
+	estimator.Estimate("my-org/api", files)
+
+	// Run 2 has no timing, so runs 1 and 3 are sampled and run 4 is not fetched
+	if len(client.fetched) != 3 || client.fetched[2] != 3 {
+		t.Errorf("Expected runs 1, 2, and 3 to be fetched, got %v", client.fetched)
+	}
+
+	// 4 sampled minutes on Ubuntu and 1 on macOS, scaled by 4 runs / 2 sampled and 30 / 15 days
+	ci := files[0].Minutes
+	if ci == nil {
+		t.Fatalf("Expected an estimate for ci.yml")
+	}
+	if ci.Runs != 4 || ci.SampledRuns != 2 || ci.WindowDays != 15 {
+		t.Errorf("Unexpected run counts: %+v", ci)
+	}
+	if ci.ByRunner["UBUNTU"] != 16 || ci.ByRunner["MACOS"] != 4 || len(ci.ByRunner) != 2 {
+		t.Errorf("Unexpected minutes by runner: %v", ci.ByRunner)
+	}
+	if ci.MonthlyMinutes != 20 || ci.WeightedMinutes != 56 {
+		t.Errorf("Expected 20 monthly and 56 weighted minutes, got %d and %d", ci.MonthlyMinutes, ci.WeightedMinutes)
+	}
+
+	if idle := files[1].Minutes; idle == nil || idle.Runs != 0 || idle.MonthlyMinutes != 0 {
+		t.Errorf("Expected an empty estimate for idle.yml, got %+v", idle)
+	}
+	for _, file := range files[2:] {
+		if file.Minutes != nil {
+			t.Errorf("Expected no estimate for %s, got %+v", file.Path, file.Minutes)
+		}
+	}
+}
+
+func TestNewEstimatorWithConfig_Defaults(t *testing.T) {
+	estimator := NewEstimatorWithConfig(&mockRunTiming{}, nil)
+	if estimator.windowDays != DefaultWindowDays || estimator.sampleRuns != DefaultSampleRuns {
+		t.Errorf("Expected default window and sample size, got %d and %d", estimator.windowDays, estimator.sampleRuns)
+	}
+
+	if estimator := NewEstimator(&mockRunTiming{}, 7); estimator.windowDays != 7 {
+		t.Errorf("Expected a 7 day window, got %d", estimator.windowDays)
+	}
+}
@@ -0,0 +1,41 @@
+package budget
+
+import (
+	"fmt"
+	"time"
+)
+
+// Budget limits how long a scan runs and how many API calls it makes, so scheduled jobs finish
+// within their window. A zero limit is unlimited.
+type Budget struct {
+	MaxDuration time.Duration
+	MaxAPICalls int
+	start       time.Time
+	now         func() time.Time
+}
+
+// New starts a budget with the given limits
+func New(maxDuration time.Duration, maxAPICalls int) *Budget {
+	return &Budget{MaxDuration: maxDuration, MaxAPICalls: maxAPICalls, start: time.Now(), now: time.Now}
+}
+
+// Limited reports whether the budget sets any limit
+func (b *Budget) Limited() bool {
+	return b != nil && (b.MaxDuration > 0 || b.MaxAPICalls > 0)
+}
+
+// Exhausted returns why the budget is used up given the API calls made so far, or "" while it lasts
+func (b *Budget) Exhausted(apiCalls int) string {
+	if !b.Limited() {
+		return ""
+	}
+	if b.MaxDuration > 0 {
+		if elapsed := b.now().Sub(b.start); elapsed >= b.MaxDuration {
+			return fmt.Sprintf("--max-duration %s reached after %s", b.MaxDuration, elapsed.Round(time.Second))
+		}
+	}
+	if b.MaxAPICalls > 0 && apiCalls >= b.MaxAPICalls {
+		return fmt.Sprintf("--max-api-calls %d reached after %d API calls", b.MaxAPICalls, apiCalls)
+	}
+	return ""
+}
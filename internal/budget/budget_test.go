@@ -0,0 +1,41 @@
+package budget
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExhausted_Duration(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start.Add(10 * time.Minute)
+	b := &Budget{MaxDuration: 30 * time.Minute, start: start, now: func() time.Time { return now }}
+
+	if reason := b.Exhausted(1000); reason != "" {
+		t.Errorf("Expected the budget to last after 10 minutes, got %q", reason)
+	}
+	now = start.Add(30 * time.Minute)
+	if reason := b.Exhausted(0); !strings.Contains(reason, "--max-duration 30m0s") {
+		t.Errorf("Expected the duration budget exhausted, got %q", reason)
+	}
+}
+
+func TestExhausted_APICalls(t *testing.T) {
+	b := New(0, 500)
+	if reason := b.Exhausted(499); reason != "" {
+		t.Errorf("Expected the budget to last after 499 calls, got %q", reason)
+	}
+	if reason := b.Exhausted(500); !strings.Contains(reason, "--max-api-calls 500") {
+		t.Errorf("Expected the API call budget exhausted, got %q", reason)
+	}
+}
+
+func TestExhausted_Unlimited(t *testing.T) {
+	var nilBudget *Budget
+	if nilBudget.Limited() || nilBudget.Exhausted(1_000_000) != "" {
+		t.Error("Expected a nil budget to be unlimited")
+	}
+	if New(0, 0).Limited() {
+		t.Error("Expected zero limits to be unlimited")
+	}
+}
@@ -0,0 +1,193 @@
+package budget
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// Heuristics for runs whose repositories have not been read yet
+const (
+	DefaultWorkflowsPerRepository = 4                      // Workflow files in a typical repository
+	DefaultActionsPerRepository   = 3                      // Distinct actions a repository adds to an owner's total, before reuse
+	DefaultCallLatency            = 300 * time.Millisecond // Average GitHub API round trip
+)
+
+// EstimateStep is one part of a run and the API calls it is expected to make
+type EstimateStep struct {
+	Name  string
+	Calls int
+	Note  string
+}
+
+// Estimate predicts the API calls and duration of a run before it does any real work
+type Estimate struct {
+	Steps       []EstimateStep
+	CallLatency time.Duration // Zero uses DefaultCallLatency
+}
+
+// Add records the expected calls of a step; steps without calls are listed with their note only
+func (e *Estimate) Add(name string, calls int, note string) {
+	e.Steps = append(e.Steps, EstimateStep{Name: name, Calls: calls, Note: note})
+}
+
+// Calls returns the total expected API calls
+func (e *Estimate) Calls() int {
+	total := 0
+	for _, step := range e.Steps {
+		total += step.Calls
+	}
+	return total
+}
+
+// Duration returns the expected time spent on API calls, made one at a time
+func (e *Estimate) Duration() time.Duration {
+	latency := e.CallLatency
+	if latency <= 0 {
+		latency = DefaultCallLatency
+	}
+	return time.Duration(e.Calls()) * latency
+}
+
+// ScanProfile describes a scan for EstimateScan
+type ScanProfile struct {
+	Repositories         int  // Repositories to scan, after --filter
+	ListCalls            int  // Calls already made to list repositories; zero when a fresh snapshot was used
+	SnapshotUsed         bool // The repository list came from --repos-snapshot
+	WorkflowDirs         int  // Workflow directory patterns listed per repository
+	WorkflowsPerRepo     int  // Zero uses DefaultWorkflowsPerRepository
+	ActionsPerRepo       int  // Zero uses DefaultActionsPerRepository
+	CustomProperties     bool // --custom-property
+	SkipResolution       bool // --skip-resolution
+	PinAge               bool // --pin-age
+	DeprecationNotices   bool // --check-deprecation-notices
+	WorkflowUsage        bool // --workflow-usage
+	TagProtectionActions bool // --check-tag-protection
+	MajorTagActions      bool // --check-major-tags
+	ReusableCalls        bool // --check-calls
+	InternalActions      bool // --internal-actions
+}
+
+// EstimateScan predicts the API calls of a scan from its repository count and options
+// Version resolution starts with an empty in-memory cache, so every distinct action costs a tag
+// listing. Distinct actions grow slower than repositories, as most repositories reuse common actions.
+func EstimateScan(profile ScanProfile) *Estimate {
+	workflows := profile.WorkflowsPerRepo
+	if workflows <= 0 {
+		workflows = DefaultWorkflowsPerRepository
+	}
+	actionsPerRepo := profile.ActionsPerRepo
+	if actionsPerRepo <= 0 {
+		actionsPerRepo = DefaultActionsPerRepository
+	}
+	dirs := profile.WorkflowDirs
+	if dirs <= 0 {
+		dirs = 1
+	}
+	repos := profile.Repositories
+	files := repos * workflows
+	actionRepos := distinctActions(repos, actionsPerRepo)
+
+	estimate := &Estimate{}
+	if profile.SnapshotUsed {
+		estimate.Add("List repositories", 0, "fresh snapshot reused")
+	} else {
+		estimate.Add("List repositories", profile.ListCalls, "already made")
+	}
+	if profile.CustomProperties {
+		estimate.Add("Custom properties", repos, "one call per repository")
+	}
+	estimate.Add("Workflow files", repos*(1+dirs)+files,
+		fmt.Sprintf("branch head and %d directory listing(s) per repository, ~%d files each", dirs, workflows))
+	if profile.SkipResolution {
+		estimate.Add("Version resolution", 0, "--skip-resolution")
+	} else {
+		estimate.Add("Version resolution", actionRepos, fmt.Sprintf("~%d distinct actions, cold cache", actionRepos))
+	}
+	if profile.PinAge {
+		estimate.Add("Pin age", actionRepos, "one release lookup per action")
+	}
+	if profile.DeprecationNotices {
+		estimate.Add("Deprecation notices", 2*actionRepos, "repository and README per action")
+	}
+	if profile.WorkflowUsage {
+		estimate.Add("Workflow usage", files, "one run listing per workflow file")
+	}
+	if profile.TagProtectionActions {
+		estimate.Add("Tag protection", actionRepos, "rulesets per internal action, at most")
+	}
+	if profile.MajorTagActions {
+		estimate.Add("Major tags", actionRepos, "tag listing per internal action, at most; shared with version resolution")
+	}
+	if profile.ReusableCalls {
+		estimate.Add("Reusable workflow calls", actionRepos, "one read per called workflow, at most")
+	}
+	if profile.InternalActions {
+		estimate.Add("Internal actions", 3*actionRepos, "repository, pull requests, and release per internal action, at most")
+	}
+	return estimate
+}
+
+// distinctActions estimates the distinct actions used by repos repositories
+func distinctActions(repos, perRepo int) int {
+	if repos <= 0 {
+		return 0
+	}
+	return int(math.Ceil(float64(perRepo) * math.Sqrt(float64(repos)) * 2))
+}
+
+// PRProfile describes a create-pr run for EstimatePRs
+type PRProfile struct {
+	PullRequests int // Pull requests to open
+	Files        int // Workflow and other files changed across the pull requests
+	Workflows    int // Workflow files changed across the pull requests
+	Reviewers    int // Pull requests that request reviewers
+	Coexist      bool
+	DriftCheck   bool
+	CodeOwners   bool
+}
+
+// EstimatePRs predicts the API calls of opening pull requests for planned updates
+func EstimatePRs(profile PRProfile) *Estimate {
+	estimate := &Estimate{}
+	estimate.Add("Token preflight", 1, "")
+	if profile.Coexist {
+		estimate.Add("Bot pull requests", profile.PullRequests, "open Dependabot and Renovate pull requests")
+	}
+	if profile.DriftCheck {
+		estimate.Add("Drift check", profile.Files, "one blob SHA per changed file")
+	}
+	estimate.Add("Required checks", profile.PullRequests, "branch protection per pull request")
+	estimate.Add("Script coupling", profile.Workflows, "one read per updated workflow file")
+	if profile.CodeOwners {
+		estimate.Add("Code owners", profile.PullRequests, "CODEOWNERS per pull request")
+	}
+	estimate.Add("Branches", 2*profile.PullRequests, "base ref and new branch")
+	estimate.Add("Commits", profile.Files, "one per changed file")
+	estimate.Add("Pull requests", profile.PullRequests+profile.Reviewers, "open pull requests and request reviewers")
+	return estimate
+}
+
+// Write prints the estimate with the token's current rate limit; a zero limit is not shown
+func (e *Estimate) Write(w io.Writer, remaining, limit int, reset time.Time) {
+	fmt.Fprintf(w, "Estimated GitHub API usage:\n")
+	for _, step := range e.Steps {
+		if step.Note != "" {
+			fmt.Fprintf(w, "  %-22s %6d  (%s)\n", step.Name, step.Calls, step.Note)
+		} else {
+			fmt.Fprintf(w, "  %-22s %6d\n", step.Name, step.Calls)
+		}
+	}
+	fmt.Fprintf(w, "  %-22s %6d\n", "Total", e.Calls())
+	fmt.Fprintf(w, "Estimated duration: %s of API calls\n", e.Duration().Round(time.Second))
+
+	if limit <= 0 {
+		return
+	}
+	fmt.Fprintf(w, "Rate limit: %d/%d requests remaining, resets at %s\n", remaining, limit, reset.Local().Format("15:04:05"))
+	if calls := e.Calls(); calls > remaining {
+		resets := int(math.Ceil(float64(calls-remaining) / float64(limit)))
+		fmt.Fprintf(w, "Warning: the run needs %d more requests than remain; expect to wait for %d rate limit reset(s)\n", calls-remaining, resets)
+	}
+}
@@ -0,0 +1,57 @@
+package budget
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEstimateScan(t *testing.T) {
+	estimate := EstimateScan(ScanProfile{Repositories: 100, ListCalls: 2, WorkflowDirs: 1})
+
+	// 2 listing calls, 100 × (head + 1 directory + 4 files), 60 distinct actions
+	if calls := estimate.Calls(); calls != 2+600+60 {
+		t.Errorf("Expected 662 calls, got %d", calls)
+	}
+	if duration := estimate.Duration(); duration != 662*DefaultCallLatency {
+		t.Errorf("Expected %s, got %s", 662*DefaultCallLatency, duration)
+	}
+
+	skipped := EstimateScan(ScanProfile{Repositories: 100, SnapshotUsed: true, SkipResolution: true})
+	if calls := skipped.Calls(); calls != 600 {
+		t.Errorf("Expected a fresh snapshot and --skip-resolution to cost only workflow reads, got %d", calls)
+	}
+
+	extras := EstimateScan(ScanProfile{Repositories: 100, SkipResolution: true, CustomProperties: true, WorkflowUsage: true})
+	if calls := extras.Calls(); calls != 600+100+400 {
+		t.Errorf("Expected custom properties per repository and usage per workflow file, got %d", calls)
+	}
+}
+
+func TestEstimatePRs(t *testing.T) {
+	estimate := EstimatePRs(PRProfile{PullRequests: 10, Files: 15, Workflows: 12, Reviewers: 4, Coexist: true, DriftCheck: true, CodeOwners: true})
+	// preflight + coexist + drift + required checks + script coupling + code owners + branches + commits + PRs and reviewers
+	if calls := estimate.Calls(); calls != 1+10+15+10+12+10+20+15+14 {
+		t.Errorf("Expected 107 calls, got %d", calls)
+	}
+}
+
+func TestEstimateWrite(t *testing.T) {
+	estimate := &Estimate{CallLatency: time.Second}
+	estimate.Add("Workflow files", 7000, "")
+
+	var out strings.Builder
+	estimate.Write(&out, 1000, 5000, time.Now())
+	if !strings.Contains(out.String(), "Total                    7000") || !strings.Contains(out.String(), "1h56m40s") {
+		t.Errorf("Expected the total and duration, got:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "6000 more requests than remain; expect to wait for 2 rate limit reset(s)") {
+		t.Errorf("Expected a rate limit warning, got:\n%s", out.String())
+	}
+
+	out.Reset()
+	estimate.Write(&out, 0, 0, time.Time{})
+	if strings.Contains(out.String(), "Rate limit") {
+		t.Errorf("Expected no rate limit without one, got:\n%s", out.String())
+	}
+}
@@ -0,0 +1,43 @@
+package budget
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FailureBudget limits how many repositories may fail to fetch or parse before a scan is aborted,
+// either as a count ("5") or as a percentage of the repositories scanned ("2%")
+type FailureBudget struct {
+	Max     int  // Failures allowed: a count, or a percentage when Percent is set
+	Percent bool // Max is a percentage of the repositories scanned
+}
+
+// ParseFailureBudget parses a --max-failures value, "N" or "N%"
+func ParseFailureBudget(value string) (*FailureBudget, error) {
+	number, percent := strings.CutSuffix(strings.TrimSpace(value), "%")
+	max, err := strconv.Atoi(number)
+	if err != nil || max < 0 || (percent && max > 100) {
+		return nil, fmt.Errorf("invalid failure budget %q: expected a count such as 5 or a percentage such as 2%%", value)
+	}
+	return &FailureBudget{Max: max, Percent: percent}, nil
+}
+
+// Exceeded reports whether more repositories failed than the budget allows out of total
+func (b *FailureBudget) Exceeded(failures, total int) bool {
+	if b == nil {
+		return false
+	}
+	if b.Percent {
+		return failures*100 > b.Max*total
+	}
+	return failures > b.Max
+}
+
+// String returns the budget as given on the command line
+func (b *FailureBudget) String() string {
+	if b.Percent {
+		return fmt.Sprintf("%d%%", b.Max)
+	}
+	return strconv.Itoa(b.Max)
+}
@@ -0,0 +1,45 @@
+package budget
+
+import "testing"
+
+func TestParseFailureBudget(t *testing.T) {
+	for _, value := range []string{"", "five", "-1", "101%", "2.5%"} {
+		if _, err := ParseFailureBudget(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+
+	count, err := ParseFailureBudget("5")
+	if err != nil || count.Max != 5 || count.Percent || count.String() != "5" {
+		t.Errorf("Expected a count of 5, got %+v, %v", count, err)
+	}
+	percent, err := ParseFailureBudget("2%")
+	if err != nil || percent.Max != 2 || !percent.Percent || percent.String() != "2%" {
+		t.Errorf("Expected 2 percent, got %+v, %v", percent, err)
+	}
+}
+
+func TestFailureBudget_Exceeded(t *testing.T) {
+	count := &FailureBudget{Max: 2}
+	if count.Exceeded(2, 10) || !count.Exceeded(3, 10) {
+		t.Error("Expected a count budget of 2 to allow 2 failures and not 3")
+	}
+
+	percent := &FailureBudget{Max: 10, Percent: true}
+	if percent.Exceeded(10, 100) || !percent.Exceeded(11, 100) {
+		t.Error("Expected 10% of 100 repositories to allow 10 failures and not 11")
+	}
+	if percent.Exceeded(0, 0) {
+		t.Error("Expected no failures out of no repositories to be within budget")
+	}
+
+	zero := &FailureBudget{Max: 0}
+	if !zero.Exceeded(1, 1000) {
+		t.Error("Expected a budget of 0 to abort on the first failure")
+	}
+
+	var unlimited *FailureBudget
+	if unlimited.Exceeded(1000, 1000) {
+		t.Error("Expected a nil budget to be unlimited")
+	}
+}
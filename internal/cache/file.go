@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// fileContents is the JSON document of a file cache
+type fileContents struct {
+	Entries []*CachedVersionInfo `json:"entries"`
+}
+
+// FileCache is a memory cache persisted to a JSON file, so version resolutions are reused by later runs
+// Entries are loaded when the cache is created and written back by Save and Close.
+type FileCache struct {
+	*MemoryCache
+	path string
+}
+
+// DefaultFilePath returns the per-user file of the file cache provider
+func DefaultFilePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "actions-maintainer", "versions.json")
+}
+
+// NewFileCacheWithConfig creates a file cache from the entries stored at path; a missing file is an
+// empty cache
+func NewFileCacheWithConfig(path string, config *Config) (*FileCache, error) {
+	if path == "" {
+		return nil, fmt.Errorf("no cache file path (the user cache directory is unavailable)")
+	}
+	memory := NewMemoryCacheWithConfig(config).(*MemoryCache)
+	cache := &FileCache{MemoryCache: memory, path: path}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read cache file: %w", err)
+	}
+
+	var contents fileContents
+	if err := json.Unmarshal(data, &contents); err != nil {
+		return nil, fmt.Errorf("unable to parse cache file %s: %w", path, err)
+	}
+	for _, entry := range contents.Entries {
+		if entry != nil && entry.Key != "" {
+			memory.data[entry.Key] = entry
+		}
+	}
+	if memory.verbose {
+		log.Printf("Cache: Loaded %d entries from %s", len(memory.data), path)
+	}
+	return cache, nil
+}
+
+// Path returns the file the cache is stored in
+func (c *FileCache) Path() string {
+	return c.path
+}
+
+// Save writes the unexpired entries to the cache file, replacing it whole
+func (c *FileCache) Save() error {
+	now := time.Now()
+	c.mutex.RLock()
+	contents := fileContents{Entries: make([]*CachedVersionInfo, 0, len(c.data))}
+	for _, entry := range c.data {
+		if !now.After(entry.ExpiresAt) {
+			contents.Entries = append(contents.Entries, entry)
+		}
+	}
+	c.mutex.RUnlock()
+	sort.Slice(contents.Entries, func(i, j int) bool {
+		return contents.Entries[i].Key < contents.Entries[j].Key
+	})
+
+	data, err := json.MarshalIndent(contents, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("unable to create cache directory: %w", err)
+	}
+
+	// Write a temporary file and rename it, so a failed write never leaves a truncated cache
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("unable to write cache file: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("unable to write cache file: %w", err)
+	}
+
+	if c.verbose {
+		log.Printf("Cache: Saved %d entries to %s", len(contents.Entries), c.path)
+	}
+	return nil
+}
+
+// Close saves the cache to its file and empties it
+func (c *FileCache) Close() error {
+	err := c.Save()
+	c.MemoryCache.Close()
+	return err
+}
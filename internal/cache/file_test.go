@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileCache_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "versions.json")
+
+	fileCache, err := NewFileCacheWithConfig(path, nil)
+	if err != nil {
+		t.Fatalf("Expected a missing file to be an empty cache, got %v", err)
+	}
+	fileCache.SetRef("actions", "checkout", "v4", "abc123", time.Hour)
+	fileCache.SetTags("actions", "checkout", map[string]string{"v4": "abc123"}, time.Hour)
+	fileCache.SetRef("actions", "setup-go", "v5", "def456", -time.Minute) // already expired
+	if err := fileCache.Close(); err != nil {
+		t.Fatalf("Expected no error saving the cache, got %v", err)
+	}
+
+	reloaded, err := NewFileCacheWithConfig(path, nil)
+	if err != nil {
+		t.Fatalf("Expected no error loading the cache, got %v", err)
+	}
+	if sha, found, _ := reloaded.GetRef("actions", "checkout", "v4"); !found || sha != "abc123" {
+		t.Errorf("Expected the ref to be reloaded, got %q (found %v)", sha, found)
+	}
+	if tags, found, _ := reloaded.GetTags("actions", "checkout"); !found || tags["v4"] != "abc123" {
+		t.Errorf("Expected the tags to be reloaded, got %v (found %v)", tags, found)
+	}
+	if _, found, _ := reloaded.GetRef("actions", "setup-go", "v5"); found {
+		t.Error("Expected expired entries not to be saved")
+	}
+}
+
+func TestMemoryCache_Purge(t *testing.T) {
+	memory := NewMemoryCache().(*MemoryCache)
+	memory.SetRef("actions", "checkout", "v4", "abc123", time.Hour)
+	memory.SetTags("actions", "checkout", map[string]string{"v4": "abc123"}, time.Hour)
+	memory.SetTags("actions", "setup-go", map[string]string{"v5": "def456"}, time.Hour)
+	memory.SetTags("my-org", "deploy", map[string]string{"v1": "fed789"}, time.Hour)
+	memory.data[TagsKey("my-org", "deploy")].CacheTime = time.Now().Add(-48 * time.Hour)
+
+	removed, err := memory.Purge(PurgeFilter{Repositories: []string{"Actions/Checkout"}})
+	if err != nil || removed != 2 {
+		t.Errorf("Expected 2 entries of actions/checkout to be purged, got %d, %v", removed, err)
+	}
+	if _, found, _ := memory.GetTags("actions", "setup-go"); !found {
+		t.Error("Expected other repositories to be kept")
+	}
+
+	removed, _ = memory.Purge(PurgeFilter{OlderThan: 24 * time.Hour})
+	if removed != 1 {
+		t.Errorf("Expected 1 entry older than 24h to be purged, got %d", removed)
+	}
+	if _, found, _ := memory.GetTags("my-org", "deploy"); found {
+		t.Error("Expected my-org/deploy to be purged")
+	}
+
+	removed, _ = memory.Purge(PurgeFilter{})
+	if removed != 1 {
+		t.Errorf("Expected the empty filter to purge the remaining entry, got %d", removed)
+	}
+}
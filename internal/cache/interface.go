@@ -1,6 +1,8 @@
 package cache
 
 import (
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -18,12 +20,24 @@ type Cache interface {
 	// SetTags stores tag mappings for a repository in the cache with TTL
 	SetTags(owner, repo string, tags map[string]string, ttl time.Duration) error
 
+	// GetMatchingTags retrieves cached tag mappings for the tags of a repository starting with prefix
+	GetMatchingTags(owner, repo, prefix string) (map[string]string, bool, error)
+
+	// SetMatchingTags stores tag mappings for the tags of a repository starting with prefix with TTL
+	SetMatchingTags(owner, repo, prefix string, tags map[string]string, ttl time.Duration) error
+
 	// GetComprehensiveVersionInfo retrieves comprehensive version information from cache
 	GetComprehensiveVersionInfo(owner, repo string) (map[string]string, map[string][]string, bool, error)
 
 	// SetComprehensiveVersionInfo stores comprehensive version information in the cache
 	SetComprehensiveVersionInfo(owner, repo string, versions map[string]string, aliases map[string][]string, ttl time.Duration) error
 
+	// GetRefDate retrieves a cached release date for a ref if it exists and hasn't expired
+	GetRefDate(owner, repo, ref string) (time.Time, bool, error)
+
+	// SetRefDate stores the release date of a ref in the cache with TTL
+	SetRefDate(owner, repo, ref string, date time.Time, ttl time.Duration) error
+
 	// CleanExpired removes expired entries from the cache
 	CleanExpired() error
 
@@ -32,6 +46,63 @@ type Cache interface {
 
 	// GetStats returns cache statistics
 	GetStats() (map[string]interface{}, error)
+
+	// Purge removes the entries selected by a filter and returns how many were removed
+	Purge(filter PurgeFilter) (int, error)
+
+	// Lock serializes lookups of a key, such as RefKey(owner, repo, ref), by everyone sharing the
+	// cache, so a value missing from the cache is fetched once however many callers need it at the
+	// same time. Callers check the cache, fetch, and store while holding the lock, then call the
+	// returned function to release it. Different keys are not serialized.
+	Lock(key string) func()
+}
+
+// PurgeFilter selects cache entries to remove; the zero filter selects every entry
+type PurgeFilter struct {
+	Repositories []string      // Repositories ("owner/name") whose entries are removed; empty selects every repository
+	OlderThan    time.Duration // Only entries cached at least this long ago; zero selects entries of any age
+}
+
+// Matches reports whether a filter selects an entry
+func (f PurgeFilter) Matches(entry *CachedVersionInfo, now time.Time) bool {
+	if f.OlderThan > 0 && now.Sub(entry.CacheTime) < f.OlderThan {
+		return false
+	}
+	if len(f.Repositories) == 0 {
+		return true
+	}
+	repository, _, _ := strings.Cut(entry.Key, ":")
+	for _, selected := range f.Repositories {
+		if strings.EqualFold(repository, selected) {
+			return true
+		}
+	}
+	return false
+}
+
+// RefKey is the key of a ref resolution
+func RefKey(owner, repo, ref string) string {
+	return fmt.Sprintf("%s/%s:%s", owner, repo, ref)
+}
+
+// RefDateKey is the key of a ref's release date
+func RefDateKey(owner, repo, ref string) string {
+	return fmt.Sprintf("%s/%s:%s:date", owner, repo, ref)
+}
+
+// TagsKey is the key of a repository's tag mappings
+func TagsKey(owner, repo string) string {
+	return fmt.Sprintf("%s/%s:tags", owner, repo)
+}
+
+// MatchingTagsKey is the key of the tag mappings of a repository's tags starting with prefix
+func MatchingTagsKey(owner, repo, prefix string) string {
+	return fmt.Sprintf("%s/%s:tags/%s", owner, repo, prefix)
+}
+
+// ComprehensiveKey is the key of a repository's comprehensive version information
+func ComprehensiveKey(owner, repo string) string {
+	return fmt.Sprintf("%s/%s:comprehensive", owner, repo)
 }
 
 // CachedVersionInfo represents cached version resolution data
@@ -39,11 +110,14 @@ type CachedVersionInfo struct {
 	Key       string    `json:"key"`        // Cache key
 	CacheTime time.Time `json:"cache_time"` // When this was cached
 	ExpiresAt time.Time `json:"expires_at"` // When this expires
-	DataType  string    `json:"data_type"`  // "ref", "tags", or "comprehensive"
+	DataType  string    `json:"data_type"`  // "ref", "date", "tags", or "comprehensive"
 
 	// For ref resolution
 	SHA string `json:"sha,omitempty"`
 
+	// For ref release dates
+	Date time.Time `json:"date,omitempty"`
+
 	// For tag mappings
 	Tags map[string]string `json:"tags,omitempty"`
 
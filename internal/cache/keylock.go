@@ -0,0 +1,44 @@
+package cache
+
+import "sync"
+
+// keyLocks hands out one mutex per key, dropping it once no caller holds or waits for it
+type keyLocks struct {
+	mutex sync.Mutex
+	locks map[string]*keyLock
+}
+
+// keyLock is the mutex of one key and the number of callers holding or waiting for it
+type keyLock struct {
+	sync.Mutex
+	refs int
+}
+
+// lock blocks until the key is free and returns the function that frees it
+func (k *keyLocks) lock(key string) func() {
+	k.mutex.Lock()
+	if k.locks == nil {
+		k.locks = make(map[string]*keyLock)
+	}
+	lock, ok := k.locks[key]
+	if !ok {
+		lock = &keyLock{}
+		k.locks[key] = lock
+	}
+	lock.refs++
+	k.mutex.Unlock()
+
+	lock.Lock()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			lock.Unlock()
+			k.mutex.Lock()
+			lock.refs--
+			if lock.refs == 0 {
+				delete(k.locks, key)
+			}
+			k.mutex.Unlock()
+		})
+	}
+}
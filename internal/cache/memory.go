@@ -13,9 +13,11 @@ type Config struct {
 }
 
 // MemoryCache provides TTL-based caching using in-memory storage for version resolution data
+// It is safe for concurrent use. Entries are never modified once stored, only replaced.
 type MemoryCache struct {
 	data    map[string]*CachedVersionInfo
 	mutex   sync.RWMutex
+	locks   keyLocks
 	verbose bool
 }
 
@@ -43,16 +45,13 @@ func NewMemoryCacheWithConfig(config *Config) Cache {
 
 // GetRef retrieves a cached ref resolution if it exists and hasn't expired
 func (c *MemoryCache) GetRef(owner, repo, ref string) (string, bool, error) {
-	key := fmt.Sprintf("%s/%s:%s", owner, repo, ref)
+	key := RefKey(owner, repo, ref)
 
 	if c.verbose {
 		log.Printf("Cache: Checking for cached ref resolution '%s'", key)
 	}
 
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
-
-	entry, exists := c.data[key]
+	entry, exists := c.entry(key)
 	if !exists {
 		if c.verbose {
 			log.Printf("Cache: MISS - No cached ref resolution found for '%s'", key)
@@ -65,12 +64,7 @@ func (c *MemoryCache) GetRef(owner, repo, ref string) (string, bool, error) {
 		if c.verbose {
 			log.Printf("Cache: MISS - Cached ref resolution for '%s' has expired (was valid until %s)", key, entry.ExpiresAt.Format(time.RFC3339))
 		}
-		// Remove expired entry
-		c.mutex.RUnlock()
-		c.mutex.Lock()
-		delete(c.data, key)
-		c.mutex.Unlock()
-		c.mutex.RLock()
+		c.removeExpired(key, entry)
 		return "", false, nil
 	}
 
@@ -90,7 +84,7 @@ func (c *MemoryCache) GetRef(owner, repo, ref string) (string, bool, error) {
 
 // SetRef stores a ref resolution in the cache with TTL
 func (c *MemoryCache) SetRef(owner, repo, ref, sha string, ttl time.Duration) error {
-	key := fmt.Sprintf("%s/%s:%s", owner, repo, ref)
+	key := RefKey(owner, repo, ref)
 
 	if c.verbose {
 		log.Printf("Cache: Storing ref resolution '%s' -> %s with TTL %s", key, sha, ttl)
@@ -118,18 +112,77 @@ func (c *MemoryCache) SetRef(owner, repo, ref, sha string, ttl time.Duration) er
 	return nil
 }
 
+// GetRefDate retrieves a cached release date for a ref if it exists and hasn't expired
+func (c *MemoryCache) GetRefDate(owner, repo, ref string) (time.Time, bool, error) {
+	key := RefDateKey(owner, repo, ref)
+
+	if c.verbose {
+		log.Printf("Cache: Checking for cached release date '%s'", key)
+	}
+
+	entry, exists := c.entry(key)
+	if !exists || entry.DataType != "date" {
+		if c.verbose {
+			log.Printf("Cache: MISS - No cached release date found for '%s'", key)
+		}
+		return time.Time{}, false, nil
+	}
+
+	if time.Now().After(entry.ExpiresAt) {
+		if c.verbose {
+			log.Printf("Cache: MISS - Cached release date for '%s' has expired (was valid until %s)", key, entry.ExpiresAt.Format(time.RFC3339))
+		}
+		c.removeExpired(key, entry)
+		return time.Time{}, false, nil
+	}
+
+	if c.verbose {
+		log.Printf("Cache: HIT - Found valid cached release date for '%s' -> %s", key, entry.Date.Format(time.RFC3339))
+	}
+
+	return entry.Date, true, nil
+}
+
+// SetRefDate stores the release date of a ref in the cache with TTL
+func (c *MemoryCache) SetRefDate(owner, repo, ref string, date time.Time, ttl time.Duration) error {
+	key := RefDateKey(owner, repo, ref)
+
+	if c.verbose {
+		log.Printf("Cache: Storing release date '%s' -> %s with TTL %s", key, date.Format(time.RFC3339), ttl)
+	}
+
+	now := time.Now()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.data[key] = &CachedVersionInfo{
+		Key:       key,
+		CacheTime: now,
+		ExpiresAt: now.Add(ttl),
+		DataType:  "date",
+		Date:      date,
+	}
+
+	return nil
+}
+
 // GetTags retrieves cached tag mappings for a repository if they exist and haven't expired
 func (c *MemoryCache) GetTags(owner, repo string) (map[string]string, bool, error) {
-	key := fmt.Sprintf("%s/%s:tags", owner, repo)
+	return c.getTags(TagsKey(owner, repo))
+}
+
+// GetMatchingTags retrieves cached tag mappings for the tags of a repository starting with prefix
+func (c *MemoryCache) GetMatchingTags(owner, repo, prefix string) (map[string]string, bool, error) {
+	return c.getTags(MatchingTagsKey(owner, repo, prefix))
+}
 
+// getTags retrieves the cached tag mappings stored under a key
+func (c *MemoryCache) getTags(key string) (map[string]string, bool, error) {
 	if c.verbose {
 		log.Printf("Cache: Checking for cached tags '%s'", key)
 	}
 
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
-
-	entry, exists := c.data[key]
+	entry, exists := c.entry(key)
 	if !exists {
 		if c.verbose {
 			log.Printf("Cache: MISS - No cached tags found for '%s'", key)
@@ -142,12 +195,7 @@ func (c *MemoryCache) GetTags(owner, repo string) (map[string]string, bool, erro
 		if c.verbose {
 			log.Printf("Cache: MISS - Cached tags for '%s' has expired (was valid until %s)", key, entry.ExpiresAt.Format(time.RFC3339))
 		}
-		// Remove expired entry
-		c.mutex.RUnlock()
-		c.mutex.Lock()
-		delete(c.data, key)
-		c.mutex.Unlock()
-		c.mutex.RLock()
+		c.removeExpired(key, entry)
 		return nil, false, nil
 	}
 
@@ -167,8 +215,16 @@ func (c *MemoryCache) GetTags(owner, repo string) (map[string]string, bool, erro
 
 // SetTags stores tag mappings for a repository in the cache with TTL
 func (c *MemoryCache) SetTags(owner, repo string, tags map[string]string, ttl time.Duration) error {
-	key := fmt.Sprintf("%s/%s:tags", owner, repo)
+	return c.setTags(TagsKey(owner, repo), tags, ttl)
+}
 
+// SetMatchingTags stores tag mappings for the tags of a repository starting with prefix with TTL
+func (c *MemoryCache) SetMatchingTags(owner, repo, prefix string, tags map[string]string, ttl time.Duration) error {
+	return c.setTags(MatchingTagsKey(owner, repo, prefix), tags, ttl)
+}
+
+// setTags stores tag mappings under a key
+func (c *MemoryCache) setTags(key string, tags map[string]string, ttl time.Duration) error {
 	if c.verbose {
 		log.Printf("Cache: Storing tags for '%s' (%d tags) with TTL %s", key, len(tags), ttl)
 	}
@@ -197,16 +253,13 @@ func (c *MemoryCache) SetTags(owner, repo string, tags map[string]string, ttl ti
 
 // GetComprehensiveVersionInfo retrieves comprehensive version information from cache
 func (c *MemoryCache) GetComprehensiveVersionInfo(owner, repo string) (map[string]string, map[string][]string, bool, error) {
-	key := fmt.Sprintf("%s/%s:comprehensive", owner, repo)
+	key := ComprehensiveKey(owner, repo)
 
 	if c.verbose {
 		log.Printf("Cache: Checking for cached comprehensive version info '%s'", key)
 	}
 
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
-
-	entry, exists := c.data[key]
+	entry, exists := c.entry(key)
 	if !exists {
 		if c.verbose {
 			log.Printf("Cache: MISS - No cached comprehensive version info found for '%s'", key)
@@ -219,12 +272,7 @@ func (c *MemoryCache) GetComprehensiveVersionInfo(owner, repo string) (map[strin
 		if c.verbose {
 			log.Printf("Cache: MISS - Cached comprehensive version info for '%s' has expired (was valid until %s)", key, entry.ExpiresAt.Format(time.RFC3339))
 		}
-		// Remove expired entry
-		c.mutex.RUnlock()
-		c.mutex.Lock()
-		delete(c.data, key)
-		c.mutex.Unlock()
-		c.mutex.RLock()
+		c.removeExpired(key, entry)
 		return nil, nil, false, nil
 	}
 
@@ -244,7 +292,7 @@ func (c *MemoryCache) GetComprehensiveVersionInfo(owner, repo string) (map[strin
 
 // SetComprehensiveVersionInfo stores comprehensive version information in the cache
 func (c *MemoryCache) SetComprehensiveVersionInfo(owner, repo string, versions map[string]string, aliases map[string][]string, ttl time.Duration) error {
-	key := fmt.Sprintf("%s/%s:comprehensive", owner, repo)
+	key := ComprehensiveKey(owner, repo)
 
 	if c.verbose {
 		log.Printf("Cache: Storing comprehensive version info for '%s' (%d versions) with TTL %s", key, len(versions), ttl)
@@ -273,6 +321,28 @@ func (c *MemoryCache) SetComprehensiveVersionInfo(owner, repo string, versions m
 	return nil
 }
 
+// entry returns the entry stored under a key, expired or not
+func (c *MemoryCache) entry(key string) (*CachedVersionInfo, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	entry, exists := c.data[key]
+	return entry, exists
+}
+
+// removeExpired removes an expired entry, unless it was replaced since it was read
+func (c *MemoryCache) removeExpired(key string, entry *CachedVersionInfo) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.data[key] == entry {
+		delete(c.data, key)
+	}
+}
+
+// Lock serializes lookups of a key by everyone sharing the cache
+func (c *MemoryCache) Lock(key string) func() {
+	return c.locks.lock(key)
+}
+
 // CleanExpired removes expired entries from the cache
 func (c *MemoryCache) CleanExpired() error {
 	if c.verbose {
@@ -307,6 +377,25 @@ func (c *MemoryCache) CleanExpired() error {
 	return nil
 }
 
+// Purge removes the entries selected by a filter, expired or not
+func (c *MemoryCache) Purge(filter PurgeFilter) (int, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	removed := 0
+	for key, entry := range c.data {
+		if filter.Matches(entry, now) {
+			delete(c.data, key)
+			removed++
+			if c.verbose {
+				log.Printf("Cache: Purged entry for key '%s'", key)
+			}
+		}
+	}
+	return removed, nil
+}
+
 // Close is a no-op for memory cache but implements the interface
 func (c *MemoryCache) Close() error {
 	c.mutex.Lock()
@@ -326,6 +415,7 @@ func (c *MemoryCache) GetStats() (map[string]interface{}, error) {
 	totalEntries := len(c.data)
 	expiredEntries := 0
 	refEntries := 0
+	dateEntries := 0
 	tagEntries := 0
 	comprehensiveEntries := 0
 
@@ -337,6 +427,8 @@ func (c *MemoryCache) GetStats() (map[string]interface{}, error) {
 		switch entry.DataType {
 		case "ref":
 			refEntries++
+		case "date":
+			dateEntries++
 		case "tags":
 			tagEntries++
 		case "comprehensive":
@@ -348,6 +440,7 @@ func (c *MemoryCache) GetStats() (map[string]interface{}, error) {
 	stats["expired_entries"] = expiredEntries
 	stats["valid_entries"] = totalEntries - expiredEntries
 	stats["ref_entries"] = refEntries
+	stats["date_entries"] = dateEntries
 	stats["tag_entries"] = tagEntries
 	stats["comprehensive_entries"] = comprehensiveEntries
 
@@ -0,0 +1,206 @@
+package calls
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// IssueTypeBrokenCall is the issue type for reusable workflow calls that fail when the job starts
+const IssueTypeBrokenCall = "broken-call"
+
+// ContentClient reads files from repositories
+type ContentClient interface {
+	GetFileContent(owner, repo, filePath, ref string) (string, error)
+}
+
+// Config holds configuration options for reusable workflow call checks
+type Config struct {
+	Verbose     bool
+	MaxFileSize int // Largest called workflow parsed, in bytes; zero uses workflow.DefaultMaxFileSize
+}
+
+// callee is what a reusable workflow declares for its callers
+type callee struct {
+	secrets  []workflow.CallSecret
+	callable bool
+}
+
+// Checker verifies that callers of reusable workflows pass the secrets the workflows declare
+// Called workflows are read once per reference, so workflows shared across the organization cost one
+// read per scan. Calls to workflows that cannot be read are left to the broken-reference check.
+type Checker struct {
+	client      ContentClient
+	verbose     bool
+	maxFileSize int
+	callees     map[string]*callee // Keyed by owner/repo/path@ref; nil when unreadable
+}
+
+// NewChecker creates a reusable workflow call checker
+func NewChecker(client ContentClient) *Checker {
+	return NewCheckerWithConfig(client, &Config{Verbose: false})
+}
+
+// NewCheckerWithConfig creates a reusable workflow call checker with configuration
+func NewCheckerWithConfig(client ContentClient, config *Config) *Checker {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	return &Checker{
+		client:      client,
+		verbose:     config.Verbose,
+		maxFileSize: config.MaxFileSize,
+		callees:     make(map[string]*callee),
+	}
+}
+
+// Check returns broken-call issues for the reusable workflow calls of a repository
+// A call is broken when it leaves out a required secret without "secrets: inherit", passes a secret
+// the workflow does not declare, or calls a workflow without a workflow_call trigger. Local calls
+// are read from the repository at its scanned commit.
+func (c *Checker) Check(repo output.RepositoryResult, calls []workflow.ReusableCall) []output.ActionIssue {
+	var issues []output.ActionIssue
+	for _, call := range calls {
+		owner, name, path, ref, ok := c.target(repo, call.Uses)
+		if !ok {
+			continue
+		}
+		called := c.callee(owner, name, path, ref)
+		if called == nil {
+			continue
+		}
+
+		issue := output.ActionIssue{
+			Repository:     owner + "/" + name,
+			WorkflowPath:   path,
+			CurrentVersion: ref,
+			IssueType:      IssueTypeBrokenCall,
+			Severity:       "high",
+			Context:        call.Context,
+			FilePath:       call.FilePath,
+		}
+		if strings.HasPrefix(call.Uses, "./") {
+			issue.Repository, issue.WorkflowPath, issue.CurrentVersion = call.Uses, "", ""
+		}
+
+		if !called.callable {
+			issue.Description = fmt.Sprintf("Job %s calls %s, which has no workflow_call trigger and cannot be called", call.Job, call.Uses)
+			issues = append(issues, issue)
+			continue
+		}
+
+		if missing := missingSecrets(called.secrets, call); len(missing) > 0 {
+			missingIssue := issue
+			missingIssue.Description = fmt.Sprintf("Job %s does not pass required secrets %s to %s; pass them under secrets: or use secrets: inherit",
+				call.Job, strings.Join(missing, ", "), call.Uses)
+			issues = append(issues, missingIssue)
+		}
+		if undeclared := undeclaredSecrets(called.secrets, call); len(undeclared) > 0 {
+			undeclaredIssue := issue
+			undeclaredIssue.Description = fmt.Sprintf("Job %s passes secrets %s that %s does not declare",
+				call.Job, strings.Join(undeclared, ", "), call.Uses)
+			issues = append(issues, undeclaredIssue)
+		}
+	}
+	return issues
+}
+
+// target returns the repository, path, and ref of a called workflow; local calls resolve to the
+// calling repository at the commit its workflows were read from
+func (c *Checker) target(repo output.RepositoryResult, uses string) (owner, name, path, ref string, ok bool) {
+	if strings.HasPrefix(uses, "./") {
+		owner, name, found := strings.Cut(repo.FullName, "/")
+		if !found {
+			return "", "", "", "", false
+		}
+		ref := repo.DefaultBranch
+		for _, file := range repo.WorkflowFiles {
+			if file.CommitSHA != "" {
+				ref = file.CommitSHA
+				break
+			}
+		}
+		return owner, name, strings.TrimPrefix(uses, "./"), ref, true
+	}
+
+	target, ref, found := strings.Cut(uses, "@")
+	if !found {
+		return "", "", "", "", false
+	}
+	parts := strings.SplitN(target, "/", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", "", false
+	}
+	return parts[0], parts[1], parts[2], ref, true
+}
+
+// callee reads and parses a called workflow, once per reference
+func (c *Checker) callee(owner, name, path, ref string) *callee {
+	key := fmt.Sprintf("%s/%s/%s@%s", owner, name, path, ref)
+	if called, ok := c.callees[key]; ok {
+		return called
+	}
+
+	var called *callee
+	content, err := c.client.GetFileContent(owner, name, path, ref)
+	if err != nil {
+		if c.verbose {
+			log.Printf("Unable to read called workflow %s: %v", key, err)
+		}
+	} else {
+		secrets, callable, err := workflow.ParseCallSecrets(content, &workflow.Config{Verbose: c.verbose, MaxFileSize: c.maxFileSize})
+		if err != nil {
+			if c.verbose {
+				log.Printf("Unable to parse called workflow %s: %v", key, err)
+			}
+		} else {
+			called = &callee{secrets: secrets, callable: callable}
+		}
+	}
+	c.callees[key] = called
+	return called
+}
+
+// missingSecrets returns the required secrets a call does not pass
+// Secret names are case-insensitive, as they are on GitHub.
+func missingSecrets(declared []workflow.CallSecret, call workflow.ReusableCall) []string {
+	if call.InheritSecrets {
+		return nil
+	}
+	var missing []string
+	for _, secret := range declared {
+		if secret.Required && !containsFold(call.Secrets, secret.Name) {
+			missing = append(missing, secret.Name)
+		}
+	}
+	return missing
+}
+
+// undeclaredSecrets returns the secrets a call passes that the called workflow does not declare
+func undeclaredSecrets(declared []workflow.CallSecret, call workflow.ReusableCall) []string {
+	names := make([]string, 0, len(declared))
+	for _, secret := range declared {
+		names = append(names, secret.Name)
+	}
+	var undeclared []string
+	for _, name := range call.Secrets {
+		if !containsFold(names, name) {
+			undeclared = append(undeclared, name)
+		}
+	}
+	return undeclared
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
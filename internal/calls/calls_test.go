@@ -0,0 +1,86 @@
+package calls
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+const deployWorkflow = `on:
+  workflow_call:
+    secrets:
+      DEPLOY_KEY:
+        required: true
+      SLACK_WEBHOOK:
+        required: false
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - run: ./deploy.sh
+`
+
+// fakeContentClient serves files keyed by "owner/repo/path@ref" and counts reads
+type fakeContentClient struct {
+	files map[string]string
+	reads int
+}
+
+func (f *fakeContentClient) GetFileContent(owner, repo, filePath, ref string) (string, error) {
+	f.reads++
+	key := fmt.Sprintf("%s/%s/%s@%s", owner, repo, filePath, ref)
+	content, ok := f.files[key]
+	if !ok {
+		return "", fmt.Errorf("%s not found", key)
+	}
+	return content, nil
+}
+
+func TestCheck(t *testing.T) {
+	client := &fakeContentClient{files: map[string]string{
+		"my-org/workflows/.github/workflows/deploy.yml@v1": deployWorkflow,
+		"my-org/workflows/.github/workflows/build.yml@v1":  "on: push\njobs: {}\n",
+		"my-org/api/.github/workflows/release.yml@head1":   deployWorkflow,
+	}}
+	repo := output.RepositoryResult{
+		FullName:      "my-org/api",
+		DefaultBranch: "main",
+		WorkflowFiles: []output.WorkflowFileResult{{Path: ".github/workflows/ci.yml", CommitSHA: "head1"}},
+	}
+	calls := []workflow.ReusableCall{
+		{FilePath: ".github/workflows/ci.yml", Job: "passes", Context: "job:passes", Uses: "my-org/workflows/.github/workflows/deploy.yml@v1", Secrets: []string{"deploy_key"}},
+		{FilePath: ".github/workflows/ci.yml", Job: "inherits", Context: "job:inherits", Uses: "my-org/workflows/.github/workflows/deploy.yml@v1", InheritSecrets: true},
+		{FilePath: ".github/workflows/ci.yml", Job: "missing", Context: "job:missing", Uses: "my-org/workflows/.github/workflows/deploy.yml@v1", Secrets: []string{"SLACK_WEBHOOK", "NPM_TOKEN"}},
+		{FilePath: ".github/workflows/ci.yml", Job: "local", Context: "job:local", Uses: "./.github/workflows/release.yml"},
+		{FilePath: ".github/workflows/ci.yml", Job: "build", Context: "job:build", Uses: "my-org/workflows/.github/workflows/build.yml@v1"},
+		{FilePath: ".github/workflows/ci.yml", Job: "gone", Context: "job:gone", Uses: "my-org/workflows/.github/workflows/gone.yml@v1"},
+	}
+
+	issues := NewChecker(client).Check(repo, calls)
+	if len(issues) != 4 {
+		t.Fatalf("Expected 4 issues, got %d: %+v", len(issues), issues)
+	}
+	if issues[0].Context != "job:missing" || !strings.Contains(issues[0].Description, "required secrets DEPLOY_KEY") {
+		t.Errorf("Expected the missing DEPLOY_KEY reported, got %+v", issues[0])
+	}
+	if issues[0].Repository != "my-org/workflows" || issues[0].WorkflowPath != ".github/workflows/deploy.yml" || issues[0].CurrentVersion != "v1" {
+		t.Errorf("Expected the called workflow identified, got %+v", issues[0])
+	}
+	if issues[1].Context != "job:missing" || !strings.Contains(issues[1].Description, "passes secrets NPM_TOKEN") {
+		t.Errorf("Expected the undeclared NPM_TOKEN reported, got %+v", issues[1])
+	}
+	if issues[2].Repository != "./.github/workflows/release.yml" || issues[2].IssueType != IssueTypeBrokenCall || issues[2].Severity != "high" {
+		t.Errorf("Expected the local call read at the scanned commit, got %+v", issues[2])
+	}
+	if issues[3].Context != "job:build" || !strings.Contains(issues[3].Description, "no workflow_call trigger") {
+		t.Errorf("Expected the uncallable workflow reported, got %+v", issues[3])
+	}
+
+	// deploy.yml is read once for its three calls
+	if client.reads != 4 {
+		t.Errorf("Expected 4 reads, got %d", client.reads)
+	}
+}
@@ -0,0 +1,266 @@
+package canary
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/pr"
+)
+
+// DefaultStateFile is where the canary cohort is recorded between create-pr runs
+const DefaultStateFile = ".actions-maintainer-canary.json"
+
+// Size is the number or percentage of affected repositories in a canary cohort
+type Size struct {
+	Count   int // Fixed number of repositories
+	Percent int // Percentage of affected repositories, rounded up; used when Count is zero
+}
+
+// ParseSize parses a cohort size such as "5" or "10%"
+func ParseSize(value string) (Size, error) {
+	value = strings.TrimSpace(value)
+	if percent, found := strings.CutSuffix(value, "%"); found {
+		n, err := strconv.Atoi(strings.TrimSpace(percent))
+		if err != nil || n < 1 || n > 100 {
+			return Size{}, fmt.Errorf("invalid canary size %q: percentage must be between 1%% and 100%%", value)
+		}
+		return Size{Percent: n}, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return Size{}, fmt.Errorf("invalid canary size %q: use a number of repositories (e.g. 5) or a percentage (e.g. 10%%)", value)
+	}
+	return Size{Count: n}, nil
+}
+
+// String formats the size as it is written on the command line
+func (s Size) String() string {
+	if s.Count == 0 {
+		return fmt.Sprintf("%d%%", s.Percent)
+	}
+	return strconv.Itoa(s.Count)
+}
+
+// Of returns the cohort size for a number of repositories; a nonzero percentage always picks at least one
+func (s Size) Of(total int) int {
+	n := s.Count
+	if n == 0 {
+		n = (total*s.Percent + 99) / 100
+	}
+	if n > total {
+		return total
+	}
+	return n
+}
+
+// Property is a custom property value whose repositories join the cohort before any others
+type Property struct {
+	Name  string
+	Value string
+}
+
+// ParseProperty parses a "name=value" custom property selector
+func ParseProperty(value string) (*Property, error) {
+	name, propertyValue, found := strings.Cut(value, "=")
+	if !found || strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("invalid canary property %q: use <name>=<value>, e.g. rollout-ring=canary", value)
+	}
+	return &Property{Name: strings.TrimSpace(name), Value: strings.TrimSpace(propertyValue)}, nil
+}
+
+// Split divides update plans into the canary cohort and the remainder
+// Repositories are the unit of selection, so every plan of a repository (one per base branch) lands on
+// the same side. Repositories matching property come first, then the rest ordered by a hash of their
+// name, so reruns against the same scan choose the same cohort.
+func Split(plans []pr.UpdatePlan, size Size, property *Property) (cohort, remainder []pr.UpdatePlan) {
+	names := RepositoryNames(plans)
+	preferred := make(map[string]bool)
+	if property != nil {
+		for _, plan := range plans {
+			if value, ok := plan.Repository.CustomProperties[property.Name]; ok && value == property.Value {
+				preferred[plan.Repository.FullName] = true
+			}
+		}
+	}
+
+	sort.SliceStable(names, func(i, j int) bool {
+		if preferred[names[i]] != preferred[names[j]] {
+			return preferred[names[i]]
+		}
+		if hi, hj := nameHash(names[i]), nameHash(names[j]); hi != hj {
+			return hi < hj
+		}
+		return names[i] < names[j]
+	})
+
+	selected := make(map[string]bool)
+	for _, name := range names[:size.Of(len(names))] {
+		selected[name] = true
+	}
+	for _, plan := range plans {
+		if selected[plan.Repository.FullName] {
+			cohort = append(cohort, plan)
+		} else {
+			remainder = append(remainder, plan)
+		}
+	}
+	return cohort, remainder
+}
+
+// RepositoryNames returns the distinct repositories of update plans in sorted order
+func RepositoryNames(plans []pr.UpdatePlan) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, plan := range plans {
+		if !seen[plan.Repository.FullName] {
+			seen[plan.Repository.FullName] = true
+			names = append(names, plan.Repository.FullName)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// nameHash spreads repositories evenly, so cohorts are not biased toward alphabetically early names
+func nameHash(name string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(strings.ToLower(name)))
+	return h.Sum32()
+}
+
+// Member is a canary pull request
+type Member struct {
+	Repository string `json:"repository"`
+	BaseBranch string `json:"base_branch,omitempty"`
+	Branch     string `json:"branch"`
+	Number     int    `json:"number"`
+	URL        string `json:"url"`
+}
+
+// State records a canary rollout between the create-pr run that opened the cohort and its promotion
+type State struct {
+	StartedAt  time.Time  `json:"started_at"`
+	Size       string     `json:"size"`
+	Cohort     []Member   `json:"cohort"`
+	Remainder  []string   `json:"remainder"` // Repositories held back until promotion
+	PromotedAt *time.Time `json:"promoted_at,omitempty"`
+}
+
+// NewState records the pull requests opened for a cohort and the repositories held back
+func NewState(size Size, created []output.CreatedPR, remainder []pr.UpdatePlan, now time.Time) *State {
+	state := &State{
+		StartedAt: now,
+		Size:      size.String(),
+		Remainder: RepositoryNames(remainder),
+	}
+	for _, createdPR := range created {
+		state.Cohort = append(state.Cohort, Member{
+			Repository: createdPR.Repository,
+			BaseBranch: createdPR.BaseBranch,
+			Branch:     createdPR.Branch,
+			Number:     createdPR.Number,
+			URL:        createdPR.URL,
+		})
+	}
+	return state
+}
+
+// LoadState loads the canary state file; a missing file is an empty state
+func LoadState(filename string) (*State, error) {
+	state := &State{}
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read canary state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("unable to parse canary state as JSON: %w", err)
+	}
+	return state, nil
+}
+
+// Save writes the canary state file
+func (s *State) Save(filename string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode canary state: %w", err)
+	}
+	if err := os.WriteFile(filename, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write canary state: %w", err)
+	}
+	return nil
+}
+
+// Active reports whether a cohort was opened and not yet promoted
+func (s *State) Active() bool {
+	return len(s.Cohort) > 0 && s.PromotedAt == nil
+}
+
+// Exclude removes the plans of cohort repositories, leaving the remainder to promote
+func (s *State) Exclude(plans []pr.UpdatePlan) []pr.UpdatePlan {
+	inCohort := make(map[string]bool)
+	for _, member := range s.Cohort {
+		inCohort[member.Repository] = true
+	}
+
+	var remaining []pr.UpdatePlan
+	for _, plan := range plans {
+		if !inCohort[plan.Repository.FullName] {
+			remaining = append(remaining, plan)
+		}
+	}
+	return remaining
+}
+
+// PullRequestClient lists the pull requests opened from a branch
+type PullRequestClient interface {
+	ListBranchPullRequests(owner, repo, branch string) ([]github.PullRequestInfo, error)
+}
+
+// Pending checks the cohort's pull requests, returning why each one that has not merged blocks promotion
+func (s *State) Pending(client PullRequestClient) ([]string, error) {
+	var pending []string
+	for _, member := range s.Cohort {
+		owner, repo, found := strings.Cut(member.Repository, "/")
+		if !found {
+			return nil, fmt.Errorf("invalid repository %q in canary state", member.Repository)
+		}
+
+		pulls, err := client.ListBranchPullRequests(owner, repo, member.Branch)
+		if err != nil {
+			return nil, fmt.Errorf("unable to check canary pull request for %s: %w", member.Repository, err)
+		}
+
+		var pull *github.PullRequestInfo
+		for i := range pulls {
+			if pulls[i].Number == member.Number {
+				pull = &pulls[i]
+				break
+			}
+		}
+
+		switch {
+		case pull == nil:
+			pending = append(pending, fmt.Sprintf("%s: pull request #%d from %s not found", member.Repository, member.Number, member.Branch))
+		case pull.Merged:
+			continue
+		case pull.State == "open":
+			pending = append(pending, fmt.Sprintf("%s: pull request #%d is still open", member.Repository, member.Number))
+		default:
+			pending = append(pending, fmt.Sprintf("%s: pull request #%d was closed without merging", member.Repository, member.Number))
+		}
+	}
+	return pending, nil
+}
@@ -0,0 +1,211 @@
+package canary
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/pr"
+)
+
+func testPlans(names ...string) []pr.UpdatePlan {
+	var plans []pr.UpdatePlan
+	for _, name := range names {
+		plans = append(plans, pr.UpdatePlan{Repository: github.Repository{FullName: name}})
+	}
+	return plans
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected Size
+		err      bool
+	}{
+		{"5", Size{Count: 5}, false},
+		{" 10% ", Size{Percent: 10}, false},
+		{"100%", Size{Percent: 100}, false},
+		{"0", Size{}, true},
+		{"-1", Size{}, true},
+		{"0%", Size{}, true},
+		{"150%", Size{}, true},
+		{"ten", Size{}, true},
+	}
+
+	for _, tt := range tests {
+		size, err := ParseSize(tt.value)
+		if tt.err {
+			if err == nil {
+				t.Errorf("%q: expected an error", tt.value)
+			}
+			continue
+		}
+		if err != nil || size != tt.expected {
+			t.Errorf("%q: expected %+v, got %+v (%v)", tt.value, tt.expected, size, err)
+		}
+	}
+}
+
+func TestSizeOf(t *testing.T) {
+	tests := []struct {
+		size     Size
+		total    int
+		expected int
+	}{
+		{Size{Count: 3}, 10, 3},
+		{Size{Count: 30}, 10, 10},
+		{Size{Percent: 10}, 25, 3},
+		{Size{Percent: 1}, 5, 1},
+		{Size{Percent: 50}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := tt.size.Of(tt.total); got != tt.expected {
+			t.Errorf("%s of %d: expected %d, got %d", tt.size, tt.total, tt.expected, got)
+		}
+	}
+}
+
+func TestParseProperty(t *testing.T) {
+	property, err := ParseProperty("rollout-ring = canary")
+	if err != nil || *property != (Property{Name: "rollout-ring", Value: "canary"}) {
+		t.Errorf("Unexpected property %+v (%v)", property, err)
+	}
+
+	for _, value := range []string{"rollout-ring", "=canary"} {
+		if _, err := ParseProperty(value); err == nil {
+			t.Errorf("%q: expected an error", value)
+		}
+	}
+}
+
+func TestSplit_IsDeterministicAndGroupsRepositories(t *testing.T) {
+	plans := testPlans("org/a", "org/b", "org/c", "org/d", "org/e")
+	// A second base branch of org/c must follow it into whichever group it lands in
+	plans = append(plans, pr.UpdatePlan{Repository: github.Repository{FullName: "org/c"}, BaseBranch: "release/1.x"})
+
+	cohort, remainder := Split(plans, Size{Count: 2}, nil)
+	if names := RepositoryNames(cohort); len(names) != 2 {
+		t.Fatalf("Expected 2 cohort repositories, got %v", names)
+	}
+	if len(RepositoryNames(remainder)) != 3 {
+		t.Errorf("Expected 3 remaining repositories, got %v", RepositoryNames(remainder))
+	}
+
+	groups := make(map[string]int)
+	for i, group := range [][]pr.UpdatePlan{cohort, remainder} {
+		for _, plan := range group {
+			if previous, ok := groups[plan.Repository.FullName]; ok && previous != i {
+				t.Errorf("Plans of %s were split between the cohort and the remainder", plan.Repository.FullName)
+			}
+			groups[plan.Repository.FullName] = i
+		}
+	}
+
+	// The input order must not change the cohort
+	reversed := make([]pr.UpdatePlan, len(plans))
+	for i, plan := range plans {
+		reversed[len(plans)-1-i] = plan
+	}
+	again, _ := Split(reversed, Size{Count: 2}, nil)
+	if !reflect.DeepEqual(RepositoryNames(again), RepositoryNames(cohort)) {
+		t.Errorf("Expected the same cohort, got %v and %v", RepositoryNames(cohort), RepositoryNames(again))
+	}
+}
+
+func TestSplit_PrefersProperty(t *testing.T) {
+	plans := testPlans("org/a", "org/b", "org/c", "org/d")
+	plans[3].Repository.CustomProperties = map[string]string{"rollout-ring": "canary"}
+	plans[1].Repository.CustomProperties = map[string]string{"rollout-ring": "general"}
+
+	cohort, _ := Split(plans, Size{Count: 1}, &Property{Name: "rollout-ring", Value: "canary"})
+	if names := RepositoryNames(cohort); !reflect.DeepEqual(names, []string{"org/d"}) {
+		t.Errorf("Expected the canary ring repository, got %v", names)
+	}
+}
+
+func TestState_SaveLoadAndExclude(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "canary.json")
+
+	state, err := LoadState(filename)
+	if err != nil || state.Active() {
+		t.Fatalf("Expected an empty state for a missing file, got %+v (%v)", state, err)
+	}
+
+	created := []output.CreatedPR{{Repository: "org/a", Branch: "actions-maintainer/update-actions-1", Number: 7, URL: "https://github.com/org/a/pull/7"}}
+	state = NewState(Size{Percent: 20}, created, testPlans("org/b", "org/c", "org/b"), time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	if err := state.Save(filename); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	loaded, err := LoadState(filename)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !loaded.Active() || loaded.Size != "20%" || len(loaded.Cohort) != 1 || loaded.Cohort[0].Number != 7 {
+		t.Errorf("Unexpected state: %+v", loaded)
+	}
+	if !reflect.DeepEqual(loaded.Remainder, []string{"org/b", "org/c"}) {
+		t.Errorf("Expected remainder org/b and org/c, got %v", loaded.Remainder)
+	}
+
+	remaining := loaded.Exclude(testPlans("org/a", "org/b", "org/d"))
+	if names := RepositoryNames(remaining); !reflect.DeepEqual(names, []string{"org/b", "org/d"}) {
+		t.Errorf("Expected the cohort to be excluded, got %v", names)
+	}
+
+	now := time.Now()
+	loaded.PromotedAt = &now
+	if loaded.Active() {
+		t.Errorf("Expected a promoted rollout to be inactive")
+	}
+}
+
+// mockPullRequests returns pull requests keyed by "repo branch"
+type mockPullRequests struct {
+	pulls map[string][]github.PullRequestInfo
+}
+
+func (m *mockPullRequests) ListBranchPullRequests(owner, repo, branch string) ([]github.PullRequestInfo, error) {
+	if repo == "broken" {
+		return nil, fmt.Errorf("not found")
+	}
+	return m.pulls[repo+" "+branch], nil
+}
+
+func TestState_Pending(t *testing.T) {
+	state := &State{Cohort: []Member{
+		{Repository: "org/merged", Branch: "b", Number: 1},
+		{Repository: "org/open", Branch: "b", Number: 2},
+		{Repository: "org/closed", Branch: "b", Number: 3},
+		{Repository: "org/missing", Branch: "b", Number: 4},
+	}}
+	client := &mockPullRequests{pulls: map[string][]github.PullRequestInfo{
+		"merged b": {{Number: 9, State: "closed"}, {Number: 1, State: "closed", Merged: true}},
+		"open b":   {{Number: 2, State: "open"}},
+		"closed b": {{Number: 3, State: "closed"}},
+	}}
+
+	pending, err := state.Pending(client)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{
+		"org/open: pull request #2 is still open",
+		"org/closed: pull request #3 was closed without merging",
+		"org/missing: pull request #4 from b not found",
+	}
+	if !reflect.DeepEqual(pending, expected) {
+		t.Errorf("Expected %q, got %q", expected, pending)
+	}
+
+	state.Cohort = append(state.Cohort, Member{Repository: "org/broken", Branch: "b", Number: 5})
+	if _, err := state.Pending(client); err == nil || !strings.Contains(err.Error(), "org/broken") {
+		t.Errorf("Expected an error naming org/broken, got %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package cleanup
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/audit"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+)
+
+// Branch cleanup outcomes
+const (
+	ActionDeleted     = "deleted"
+	ActionWouldDelete = "would-delete" // Dry run
+	ActionKept        = "kept"
+	ActionFailed      = "failed"
+)
+
+// BranchClient lists and deletes branches and the pull requests opened from them
+type BranchClient interface {
+	ListBranches(owner, repo, prefix string) ([]string, error)
+	ListBranchPullRequests(owner, repo, branch string) ([]github.PullRequestInfo, error)
+	DeleteBranch(owner, repo, branch string) error
+}
+
+// Config holds configuration options for branch cleanup
+type Config struct {
+	Verbose bool
+	Prefix  string        // Only branches starting with this prefix are considered
+	DryRun  bool          // Report branches that would be deleted without deleting them
+	Audit   *audit.Logger // Records each deleted branch; nil disables auditing
+}
+
+// Result records what happened to one branch
+type Result struct {
+	Repository string
+	Branch     string
+	Action     string
+	Reason     string
+}
+
+// Cleaner deletes tool-created branches whose pull requests are merged or closed
+type Cleaner struct {
+	client  BranchClient
+	prefix  string
+	dryRun  bool
+	verbose bool
+	audit   *audit.Logger
+}
+
+// NewCleaner creates a cleaner for branches starting with prefix
+func NewCleaner(client BranchClient, prefix string) *Cleaner {
+	return NewCleanerWithConfig(client, &Config{Verbose: false, Prefix: prefix})
+}
+
+// NewCleanerWithConfig creates a cleaner with configuration
+func NewCleanerWithConfig(client BranchClient, config *Config) *Cleaner {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	return &Cleaner{
+		client:  client,
+		prefix:  config.Prefix,
+		dryRun:  config.DryRun,
+		verbose: config.Verbose,
+		audit:   config.Audit,
+	}
+}
+
+// CleanRepository deletes a repository's matching branches once none of their pull requests are open
+// Branches without any pull request are kept, since they may belong to a rollout still in progress.
+func (c *Cleaner) CleanRepository(owner, repo string) ([]Result, error) {
+	fullName := owner + "/" + repo
+
+	branches, err := c.client.ListBranches(owner, repo, c.prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.verbose {
+		log.Printf("Found %d branches matching '%s' in %s", len(branches), c.prefix, fullName)
+	}
+
+	var results []Result
+	for _, branch := range branches {
+		result := Result{Repository: fullName, Branch: branch}
+
+		pulls, err := c.client.ListBranchPullRequests(owner, repo, branch)
+		if err != nil {
+			result.Action = ActionFailed
+			result.Reason = err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		result.Action, result.Reason = decide(pulls)
+		if result.Action == ActionDeleted {
+			if c.dryRun {
+				result.Action = ActionWouldDelete
+			} else if err := c.client.DeleteBranch(owner, repo, branch); err != nil {
+				result.Action = ActionFailed
+				result.Reason = err.Error()
+			} else if err := c.audit.Record(audit.Event{Action: audit.ActionBranchDeleted, Repository: fullName, Branch: branch}); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: audit log: %v\n", err)
+			}
+		}
+
+		if c.verbose {
+			log.Printf("Branch %s in %s: %s (%s)", branch, fullName, result.Action, result.Reason)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// decide returns whether a branch can be deleted given the pull requests opened from it
+func decide(pulls []github.PullRequestInfo) (string, string) {
+	if len(pulls) == 0 {
+		return ActionKept, "no pull request"
+	}
+
+	for _, pull := range pulls {
+		if pull.State == "open" {
+			return ActionKept, fmt.Sprintf("pull request #%d is open", pull.Number)
+		}
+	}
+
+	// Pull requests are listed newest first
+	latest := pulls[0]
+	if latest.Merged {
+		return ActionDeleted, fmt.Sprintf("pull request #%d was merged", latest.Number)
+	}
+	return ActionDeleted, fmt.Sprintf("pull request #%d was closed", latest.Number)
+}
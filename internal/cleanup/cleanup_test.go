@@ -0,0 +1,144 @@
+package cleanup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/audit"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+)
+
+// mockBranchClient serves fixed branches and pull requests and records deletions
+type mockBranchClient struct {
+	branches []string
+	pulls    map[string][]github.PullRequestInfo
+	deleted  []string
+}
+
+func (m *mockBranchClient) ListBranches(owner, repo, prefix string) ([]string, error) {
+	return m.branches, nil
+}
+
+func (m *mockBranchClient) ListBranchPullRequests(owner, repo, branch string) ([]github.PullRequestInfo, error) {
+	if branch == "actions-maintainer/broken" {
+		return nil, fmt.Errorf("server error")
+	}
+	return m.pulls[branch], nil
+}
+
+func (m *mockBranchClient) DeleteBranch(owner, repo, branch string) error {
+	m.deleted = append(m.deleted, branch)
+	return nil
+}
+
+func newMockClient() *mockBranchClient {
+	return &mockBranchClient{
+		branches: []string{
+			"actions-maintainer/merged",
+			"actions-maintainer/closed",
+			"actions-maintainer/open",
+			"actions-maintainer/no-pr",
+			"actions-maintainer/broken",
+		},
+		pulls: map[string][]github.PullRequestInfo{
+			"actions-maintainer/merged": {{Number: 3, State: "closed", Merged: true}},
+			"actions-maintainer/closed": {{Number: 4, State: "closed"}},
+			"actions-maintainer/open":   {{Number: 6, State: "open"}, {Number: 5, State: "closed"}},
+		},
+	}
+}
+
+func TestCleanRepository(t *testing.T) {
+	client := newMockClient()
+	cleaner := NewCleaner(client, "actions-maintainer/")
+
+	results, err := cleaner.CleanRepository("my-org", "api")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"actions-maintainer/merged": ActionDeleted,
+		"actions-maintainer/closed": ActionDeleted,
+		"actions-maintainer/open":   ActionKept,
+		"actions-maintainer/no-pr":  ActionKept,
+		"actions-maintainer/broken": ActionFailed,
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("Expected %d results, got %d", len(expected), len(results))
+	}
+	for _, result := range results {
+		if result.Action != expected[result.Branch] {
+			t.Errorf("Expected %s for %s, got %s (%s)", expected[result.Branch], result.Branch, result.Action, result.Reason)
+		}
+		if result.Repository != "my-org/api" {
+			t.Errorf("Unexpected repository %s", result.Repository)
+		}
+	}
+
+	if len(client.deleted) != 2 || client.deleted[0] != "actions-maintainer/merged" || client.deleted[1] != "actions-maintainer/closed" {
+		t.Errorf("Unexpected deletions: %v", client.deleted)
+	}
+}
+
+func TestCleanRepository_DryRun(t *testing.T) {
+	client := newMockClient()
+	cleaner := NewCleanerWithConfig(client, &Config{Prefix: "actions-maintainer/", DryRun: true})
+
+	results, err := cleaner.CleanRepository("my-org", "api")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	wouldDelete := 0
+	for _, result := range results {
+		if result.Action == ActionWouldDelete {
+			wouldDelete++
+		}
+	}
+	if wouldDelete != 2 {
+		t.Errorf("Expected 2 branches to be reported for deletion, got %d", wouldDelete)
+	}
+	if len(client.deleted) != 0 {
+		t.Errorf("Expected no deletions in a dry run, got %v", client.deleted)
+	}
+}
+
+func TestCleanRepository_AuditsDeletions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger, err := audit.Open(&audit.Config{Target: path, Actor: "octocat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cleaner := NewCleanerWithConfig(newMockClient(), &Config{Prefix: "actions-maintainer/", Audit: logger})
+
+	if _, err := cleaner.CleanRepository("my-org", "api"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var branches []string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var event audit.Event
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("Expected a JSON line, got %q", line)
+		}
+		if event.Action != audit.ActionBranchDeleted || event.Repository != "my-org/api" || event.Actor != "octocat" {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+		branches = append(branches, event.Branch)
+	}
+	if len(branches) != 2 || branches[0] != "actions-maintainer/merged" || branches[1] != "actions-maintainer/closed" {
+		t.Errorf("Expected only deleted branches to be audited, got %v", branches)
+	}
+}
@@ -0,0 +1,264 @@
+package completion
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Shells lists the shells completion scripts can be generated for
+var Shells = []string{"bash", "zsh", "fish", "powershell"}
+
+// Command describes a CLI command for completion purposes
+type Command struct {
+	Name  string
+	Brief string
+	Flags []Flag
+}
+
+// Flag describes a command flag for completion purposes
+type Flag struct {
+	Name     string // Long name, completed as --name
+	Short    string // Optional single-letter alias, completed as -s
+	Help     string
+	Variable bool // Whether the flag takes a value
+}
+
+// Generate writes a completion script for the given shell
+func Generate(w io.Writer, shell, program string, commands []Command) error {
+	sorted := make([]Command, len(commands))
+	copy(sorted, commands)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	switch strings.ToLower(shell) {
+	case "bash":
+		return writeBash(w, program, sorted)
+	case "zsh":
+		return writeZsh(w, program, sorted)
+	case "fish":
+		return writeFish(w, program, sorted)
+	case "powershell", "pwsh":
+		return writePowerShell(w, program, sorted)
+	default:
+		return fmt.Errorf("unsupported shell %q (supported: %s)", shell, strings.Join(Shells, ", "))
+	}
+}
+
+// flagWords returns the long and short spellings of a flag
+func flagWords(flag Flag) []string {
+	words := []string{"--" + flag.Name}
+	if flag.Short != "" {
+		words = append(words, "-"+flag.Short)
+	}
+	return words
+}
+
+// shortHelp trims flag help to its first sentence for use as a completion description
+func shortHelp(help string) string {
+	help = strings.Join(strings.Fields(help), " ")
+	if i := strings.Index(help, ". "); i >= 0 {
+		help = help[:i]
+	}
+	return strings.TrimSuffix(help, ".")
+}
+
+// functionName converts a program name into a valid shell function identifier
+func functionName(program string) string {
+	return "_" + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, program)
+}
+
+func writeBash(w io.Writer, program string, commands []Command) error {
+	fn := functionName(program)
+	var names []string
+	for _, cmd := range commands {
+		names = append(names, cmd.Name)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# bash completion for %s\n", program)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("    local cur prev\n")
+	b.WriteString("    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	b.WriteString("    prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n\n")
+	b.WriteString("    if [[ ${COMP_CWORD} -eq 1 ]]; then\n")
+	fmt.Fprintf(&b, "        COMPREPLY=( $(compgen -W \"%s\" -- \"${cur}\") )\n", strings.Join(names, " "))
+	b.WriteString("        return\n")
+	b.WriteString("    fi\n\n")
+	b.WriteString("    case \"${COMP_WORDS[1]}\" in\n")
+	for _, cmd := range commands {
+		if len(cmd.Flags) == 0 {
+			continue
+		}
+		var all, valued []string
+		for _, flag := range cmd.Flags {
+			all = append(all, flagWords(flag)...)
+			if flag.Variable {
+				valued = append(valued, flagWords(flag)...)
+			}
+		}
+		fmt.Fprintf(&b, "        %s)\n", cmd.Name)
+		if len(valued) > 0 {
+			// Fall back to default (file) completion for flag values
+			fmt.Fprintf(&b, "            case \"${prev}\" in\n                %s) return ;;\n            esac\n", strings.Join(valued, "|"))
+		}
+		fmt.Fprintf(&b, "            COMPREPLY=( $(compgen -W \"%s\" -- \"${cur}\") )\n", strings.Join(all, " "))
+		b.WriteString("            ;;\n")
+	}
+	b.WriteString("    esac\n")
+	b.WriteString("}\n")
+	fmt.Fprintf(&b, "complete -o default -F %s %s\n", fn, program)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// zshQuote escapes text for a single-quoted zsh _arguments description
+func zshQuote(text string) string {
+	text = strings.ReplaceAll(text, `'`, `'\''`)
+	return strings.NewReplacer(`[`, `\[`, `]`, `\]`, `:`, `\:`).Replace(text)
+}
+
+func writeZsh(w io.Writer, program string, commands []Command) error {
+	fn := functionName(program)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "#compdef %s\n\n", program)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("    local -a commands\n")
+	b.WriteString("    commands=(\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(&b, "        '%s:%s'\n", cmd.Name, strings.ReplaceAll(cmd.Brief, `'`, `'\''`))
+	}
+	b.WriteString("    )\n\n")
+	b.WriteString("    if (( CURRENT == 2 )); then\n")
+	b.WriteString("        _describe -t commands 'command' commands\n")
+	b.WriteString("        return\n")
+	b.WriteString("    fi\n\n")
+	b.WriteString("    words=(${words[2,-1]})\n")
+	b.WriteString("    (( CURRENT-- ))\n\n")
+	b.WriteString("    case \"${words[1]}\" in\n")
+	for _, cmd := range commands {
+		if len(cmd.Flags) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "        %s)\n", cmd.Name)
+		b.WriteString("            _arguments")
+		for _, flag := range cmd.Flags {
+			spec := "'[" + zshQuote(shortHelp(flag.Help)) + "]"
+			if flag.Variable {
+				spec += ":" + flag.Name + ":_files"
+			}
+			spec += "'"
+			if flag.Short != "" {
+				fmt.Fprintf(&b, " \\\n                '(--%s -%s)'{--%s,-%s}%s", flag.Name, flag.Short, flag.Name, flag.Short, spec)
+			} else {
+				fmt.Fprintf(&b, " \\\n                '--%s%s", flag.Name, spec[1:])
+			}
+		}
+		b.WriteString("\n            ;;\n")
+	}
+	b.WriteString("    esac\n")
+	b.WriteString("}\n\n")
+	// Run directly when autoloaded from $fpath, otherwise register when sourced
+	fmt.Fprintf(&b, "if [ \"$funcstack[1]\" = \"%s\" ]; then\n    %s \"$@\"\nelse\n    compdef %s %s\nfi\n", fn, fn, fn, program)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// fishQuote escapes text for a single-quoted fish string
+func fishQuote(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(text)
+}
+
+func writeFish(w io.Writer, program string, commands []Command) error {
+	var names []string
+	for _, cmd := range commands {
+		names = append(names, cmd.Name)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# fish completion for %s\n", program)
+	fmt.Fprintf(&b, "complete -c %s -f\n", program)
+	for _, cmd := range commands {
+		fmt.Fprintf(&b, "complete -c %s -n 'not __fish_seen_subcommand_from %s' -a %s -d '%s'\n",
+			program, strings.Join(names, " "), cmd.Name, fishQuote(cmd.Brief))
+	}
+	for _, cmd := range commands {
+		for _, flag := range cmd.Flags {
+			fmt.Fprintf(&b, "complete -c %s -n '__fish_seen_subcommand_from %s' -l %s", program, cmd.Name, flag.Name)
+			if flag.Short != "" {
+				fmt.Fprintf(&b, " -s %s", flag.Short)
+			}
+			if flag.Variable {
+				b.WriteString(" -r -F")
+			}
+			fmt.Fprintf(&b, " -d '%s'\n", fishQuote(shortHelp(flag.Help)))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// powerShellQuote escapes text for a single-quoted PowerShell string
+func powerShellQuote(text string) string {
+	return strings.ReplaceAll(text, `'`, `''`)
+}
+
+func writePowerShell(w io.Writer, program string, commands []Command) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# PowerShell completion for %s\n", program)
+	// Register both names so completion works for the .exe on Windows
+	fmt.Fprintf(&b, "Register-ArgumentCompleter -Native -CommandName '%s', '%s.exe' -ScriptBlock {\n",
+		powerShellQuote(program), powerShellQuote(program))
+	b.WriteString("    param($wordToComplete, $commandAst, $cursorPosition)\n\n")
+	b.WriteString("    $commands = [ordered]@{\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(&b, "        '%s' = '%s'\n", cmd.Name, powerShellQuote(cmd.Brief))
+	}
+	b.WriteString("    }\n")
+	b.WriteString("    $flags = @{\n")
+	for _, cmd := range commands {
+		var entries []string
+		for _, flag := range cmd.Flags {
+			for _, word := range flagWords(flag) {
+				entries = append(entries, fmt.Sprintf("@('%s', '%s')", word, powerShellQuote(shortHelp(flag.Help))))
+			}
+		}
+		list := strings.Join(entries, ", ")
+		if len(entries) == 1 {
+			// A leading comma stops PowerShell from flattening a single pair into the outer array
+			list = "," + list
+		}
+		fmt.Fprintf(&b, "        '%s' = @(%s)\n", cmd.Name, list)
+	}
+	b.WriteString("    }\n\n")
+	b.WriteString("    $elements = @($commandAst.CommandElements | ForEach-Object { $_.ToString() })\n")
+	b.WriteString("    if ($elements.Count -lt 2 -or ($elements.Count -eq 2 -and $wordToComplete -ne '')) {\n")
+	b.WriteString("        foreach ($name in $commands.Keys) {\n")
+	b.WriteString("            if ($name -like \"$wordToComplete*\") {\n")
+	b.WriteString("                [System.Management.Automation.CompletionResult]::new($name, $name, 'ParameterValue', $commands[$name])\n")
+	b.WriteString("            }\n")
+	b.WriteString("        }\n")
+	b.WriteString("        return\n")
+	b.WriteString("    }\n\n")
+	b.WriteString("    if (-not $wordToComplete.StartsWith('-')) {\n")
+	b.WriteString("        return\n")
+	b.WriteString("    }\n")
+	b.WriteString("    foreach ($flag in $flags[$elements[1]]) {\n")
+	b.WriteString("        if ($flag[0] -like \"$wordToComplete*\") {\n")
+	b.WriteString("            [System.Management.Automation.CompletionResult]::new($flag[0], $flag[0], 'ParameterName', $flag[1])\n")
+	b.WriteString("        }\n")
+	b.WriteString("    }\n")
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
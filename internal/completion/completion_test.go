@@ -0,0 +1,66 @@
+package completion
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+var testCommands = []Command{
+	{
+		Name:  "scan",
+		Brief: "Scan repositories",
+		Flags: []Flag{
+			{Name: "owner", Short: "o", Help: "GitHub owner to scan", Variable: true},
+			{Name: "verbose", Short: "v", Help: "Enable verbose logging. Shows API calls.", Variable: false},
+			{Name: "timeout", Help: "Request timeout", Variable: true},
+		},
+	},
+	{Name: "version", Brief: "Show the program's version"},
+}
+
+func TestGenerate_AllShells(t *testing.T) {
+	expected := map[string][]string{
+		"bash": {
+			`compgen -W "scan version"`,
+			`--owner|-o|--timeout) return ;;`,
+			"complete -o default -F _actions_maintainer actions-maintainer",
+		},
+		"zsh": {
+			"#compdef actions-maintainer",
+			`'(--owner -o)'{--owner,-o}'[GitHub owner to scan]:owner:_files'`,
+			`'--timeout[Request timeout]:timeout:_files'`,
+			`'(--verbose -v)'{--verbose,-v}'[Enable verbose logging]'`,
+		},
+		"fish": {
+			"-a scan -d 'Scan repositories'",
+			"-l owner -s o -r -F -d 'GitHub owner to scan'",
+			"-l verbose -s v -d 'Enable verbose logging'",
+			`-d 'Show the program\'s version'`,
+		},
+		"powershell": {
+			"-CommandName 'actions-maintainer', 'actions-maintainer.exe'",
+			"'version' = 'Show the program''s version'",
+			"@('--owner', 'GitHub owner to scan'), @('-o', 'GitHub owner to scan')",
+		},
+	}
+
+	for _, shell := range Shells {
+		var buf bytes.Buffer
+		if err := Generate(&buf, shell, "actions-maintainer", testCommands); err != nil {
+			t.Fatalf("Generate(%s) returned error: %v", shell, err)
+		}
+		for _, fragment := range expected[shell] {
+			if !strings.Contains(buf.String(), fragment) {
+				t.Errorf("Expected %s completion to contain %q, got:\n%s", shell, fragment, buf.String())
+			}
+		}
+	}
+}
+
+func TestGenerate_UnsupportedShell(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Generate(&buf, "tcsh", "actions-maintainer", testCommands); err == nil {
+		t.Errorf("Expected error for unsupported shell")
+	}
+}
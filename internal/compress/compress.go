@@ -0,0 +1,126 @@
+// Package compress reads and writes gzip or zstd compressed scan results, so large scans stored as
+// CI artifacts stay small. Output is compressed by file extension and input is detected by content.
+package compress
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression formats
+const (
+	None = ""
+	Gzip = "gzip" // .gz
+	Zstd = "zstd" // .zst
+)
+
+// extensions map the file extensions of compressed files to their format
+var extensions = map[string]string{
+	".gz":  Gzip,
+	".zst": Zstd,
+}
+
+// Magic numbers at the start of compressed data
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// FormatForPath returns the compression format selected by a file's extension, or None
+func FormatForPath(path string) string {
+	return extensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// TrimExtension splits the compression extension off a path, e.g. "scan.json.gz" into "scan.json"
+// and ".gz"; paths of uncompressed files are returned whole with an empty extension
+func TrimExtension(path string) (string, string) {
+	ext := filepath.Ext(path)
+	if _, ok := extensions[strings.ToLower(ext)]; !ok {
+		return path, ""
+	}
+	return strings.TrimSuffix(path, ext), ext
+}
+
+// Detect returns the compression format of data from its first bytes, or None
+func Detect(header []byte) string {
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		return Gzip
+	case bytes.HasPrefix(header, zstdMagic):
+		return Zstd
+	}
+	return None
+}
+
+// NewWriter compresses everything written to the returned writer into w; None writes through
+// unchanged. Close must be called to flush the compressed output, and does not close w.
+func NewWriter(w io.Writer, format string) (io.WriteCloser, error) {
+	switch format {
+	case None:
+		return nopWriteCloser{w}, nil
+	case Gzip:
+		return gzip.NewWriter(w), nil
+	case Zstd:
+		writer, err := zstd.NewWriter(w)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start zstd compression: %w", err)
+		}
+		return writer, nil
+	}
+	return nil, fmt.Errorf("unknown compression format %q", format)
+}
+
+// NewReader decompresses r in the format detected from its first bytes; uncompressed data is read
+// through unchanged
+func NewReader(r io.Reader) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(r)
+	header, _ := buffered.Peek(len(zstdMagic))
+
+	switch Detect(header) {
+	case Gzip:
+		reader, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gzip input: %w", err)
+		}
+		return reader, nil
+	case Zstd:
+		reader, err := zstd.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read zstd input: %w", err)
+		}
+		return reader.IOReadCloser(), nil
+	}
+	return io.NopCloser(buffered), nil
+}
+
+// Decompress returns the decompressed form of data; uncompressed data is returned unchanged
+func Decompress(data []byte) ([]byte, error) {
+	if Detect(data) == None {
+		return data, nil
+	}
+	reader, err := NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	decompressed, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress input: %w", err)
+	}
+	return decompressed, nil
+}
+
+// nopWriteCloser writes through to w without compressing
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
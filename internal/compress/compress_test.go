@@ -0,0 +1,86 @@
+package compress
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestFormatForPath(t *testing.T) {
+	tests := map[string]string{
+		"scan.json":        None,
+		"scan.json.gz":     Gzip,
+		"scan.json.GZ":     Gzip,
+		"reports/scan.zst": Zstd,
+		"scan.sarif.zst":   Zstd,
+	}
+	for path, expected := range tests {
+		if format := FormatForPath(path); format != expected {
+			t.Errorf("%s: Expected %q, got %q", path, expected, format)
+		}
+	}
+}
+
+func TestTrimExtension(t *testing.T) {
+	if base, ext := TrimExtension("scan.json.zst"); base != "scan.json" || ext != ".zst" {
+		t.Errorf("Expected scan.json and .zst, got %q and %q", base, ext)
+	}
+	if base, ext := TrimExtension("scan.json"); base != "scan.json" || ext != "" {
+		t.Errorf("Expected scan.json unchanged, got %q and %q", base, ext)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	content := strings.Repeat(`{"repositories": []}`+"\n", 100)
+
+	for _, format := range []string{None, Gzip, Zstd} {
+		t.Run("format "+format, func(t *testing.T) {
+			var buf bytes.Buffer
+			writer, err := NewWriter(&buf, format)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if _, err := io.WriteString(writer, content); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if detected := Detect(buf.Bytes()); detected != format {
+				t.Errorf("Expected format %q to be detected, got %q", format, detected)
+			}
+			if format != None && buf.Len() >= len(content) {
+				t.Errorf("Expected compressed output smaller than %d bytes, got %d", len(content), buf.Len())
+			}
+
+			reader, err := NewReader(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			defer reader.Close()
+			read, err := io.ReadAll(reader)
+			if err != nil || string(read) != content {
+				t.Errorf("Expected the content back, got %d bytes, %v", len(read), err)
+			}
+
+			decompressed, err := Decompress(buf.Bytes())
+			if err != nil || string(decompressed) != content {
+				t.Errorf("Expected Decompress to return the content, got %d bytes, %v", len(decompressed), err)
+			}
+		})
+	}
+}
+
+func TestDecompressCorrupt(t *testing.T) {
+	if _, err := Decompress([]byte{0x1f, 0x8b, 0x00, 0x01}); err == nil {
+		t.Error("Expected an error for corrupt gzip data")
+	}
+}
+
+func TestNewWriterUnknownFormat(t *testing.T) {
+	if _, err := NewWriter(io.Discard, "brotli"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,200 @@
+package consumers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// Advisory collects the deprecated or vulnerable versions of an internal action still in use, to be
+// drafted as a repository security advisory so GitHub notifies the action's owners
+type Advisory struct {
+	Repository     string              // Internal action, "owner/name"
+	Severity       string              // Highest severity of the findings: low, medium, high, or critical
+	Deprecated     bool                // Some consumers use deprecated versions
+	Vulnerable     bool                // Some consumers use versions with known vulnerabilities
+	CVEs           []string            // Advisory ids of the vulnerabilities, e.g. "CVE-2025-30066"
+	PatchedVersion string              // Version the findings suggest moving to, when they agree on one
+	Findings       []string            // Distinct finding descriptions
+	Consumers      map[string][]string // Affected version -> consumer repositories
+}
+
+// advisorySeverities orders severities, higher is more severe
+var advisorySeverities = map[string]int{"low": 1, "medium": 2, "high": 3, "critical": 4}
+
+// FindAdvisories returns an advisory for each internal action with deprecated or vulnerable versions in
+// use, most severe first. Findings are vulnerable when their issue type is "security" or they name a CVE.
+//
+// Actions are internal when their owner also owns a scanned repository. A repository using its own
+// action is not a consumer.
+func FindAdvisories(repositories []output.RepositoryResult) []Advisory {
+	owners := make(map[string]bool)
+	for _, repo := range repositories {
+		owners[strings.ToLower(ownerOf(repo.FullName))] = true
+	}
+
+	type collected struct {
+		advisory  Advisory
+		cves      map[string]bool
+		findings  map[string]bool
+		patched   map[string]bool
+		consumers map[string]map[string]bool
+	}
+	byAction := make(map[string]*collected)
+
+	for _, repo := range repositories {
+		for _, issue := range repo.Issues {
+			if !owners[strings.ToLower(ownerOf(issue.Repository))] || strings.EqualFold(issue.Repository, repo.FullName) {
+				continue
+			}
+			cve := issue.Metadata[output.MetadataCVE]
+			vulnerable := issue.IssueType == "security" || cve != ""
+			if !vulnerable && issue.IssueType != "deprecated" {
+				continue
+			}
+
+			entry, exists := byAction[issue.Repository]
+			if !exists {
+				entry = &collected{
+					advisory:  Advisory{Repository: issue.Repository},
+					cves:      make(map[string]bool),
+					findings:  make(map[string]bool),
+					patched:   make(map[string]bool),
+					consumers: make(map[string]map[string]bool),
+				}
+				byAction[issue.Repository] = entry
+			}
+
+			if vulnerable {
+				entry.advisory.Vulnerable = true
+			} else {
+				entry.advisory.Deprecated = true
+			}
+			if advisorySeverities[issue.Severity] > advisorySeverities[entry.advisory.Severity] {
+				entry.advisory.Severity = issue.Severity
+			}
+			if cve != "" {
+				entry.cves[cve] = true
+			}
+			if issue.Description != "" {
+				entry.findings[issue.Description] = true
+			}
+			if issue.SuggestedVersion != "" {
+				entry.patched[issue.SuggestedVersion] = true
+			}
+			if entry.consumers[issue.CurrentVersion] == nil {
+				entry.consumers[issue.CurrentVersion] = make(map[string]bool)
+			}
+			entry.consumers[issue.CurrentVersion][repo.FullName] = true
+		}
+	}
+
+	advisories := make([]Advisory, 0, len(byAction))
+	for _, entry := range byAction {
+		advisory := entry.advisory
+		if advisory.Severity == "" {
+			advisory.Severity = "medium"
+		}
+		advisory.CVEs = sortedKeys(entry.cves)
+		advisory.Findings = sortedKeys(entry.findings)
+		if len(entry.patched) == 1 {
+			advisory.PatchedVersion = sortedKeys(entry.patched)[0]
+		}
+		advisory.Consumers = make(map[string][]string)
+		for version, consumers := range entry.consumers {
+			advisory.Consumers[version] = sortedKeys(consumers)
+		}
+		advisories = append(advisories, advisory)
+	}
+
+	sort.Slice(advisories, func(i, j int) bool {
+		a, b := advisorySeverities[advisories[i].Severity], advisorySeverities[advisories[j].Severity]
+		if a != b {
+			return a > b
+		}
+		return advisories[i].Repository < advisories[j].Repository
+	})
+	return advisories
+}
+
+// AffectedConsumers returns every consumer repository using an affected version, in sorted order
+func (a Advisory) AffectedConsumers() []string {
+	all := make(map[string]bool)
+	for _, consumers := range a.Consumers {
+		for _, consumer := range consumers {
+			all[consumer] = true
+		}
+	}
+	return sortedKeys(all)
+}
+
+// AdvisorySummary returns the summary of an action's advisory
+// The summary does not change between scans, so an existing draft can be found again.
+func AdvisorySummary(advisory Advisory) string {
+	var kind string
+	switch {
+	case advisory.Vulnerable && advisory.Deprecated:
+		kind = "Vulnerable and deprecated"
+	case advisory.Vulnerable:
+		kind = "Vulnerable"
+	default:
+		kind = "Deprecated"
+	}
+	return fmt.Sprintf("%s versions of %s are still in use", kind, advisory.Repository)
+}
+
+// AdvisoryDescription describes the findings of an advisory and lists the consumers of each affected version
+func AdvisoryDescription(advisory Advisory) string {
+	var body strings.Builder
+
+	consumers := advisory.AffectedConsumers()
+	body.WriteString(fmt.Sprintf("%d repositories use versions of `%s` that a scan found to be ", len(consumers), advisory.Repository))
+	switch {
+	case advisory.Vulnerable && advisory.Deprecated:
+		body.WriteString("vulnerable or deprecated.")
+	case advisory.Vulnerable:
+		body.WriteString("vulnerable.")
+	default:
+		body.WriteString("deprecated.")
+	}
+	if advisory.PatchedVersion != "" {
+		body.WriteString(fmt.Sprintf(" Consumers should move to `%s`.", advisory.PatchedVersion))
+	}
+	body.WriteString("\n\n")
+
+	if len(advisory.CVEs) > 0 {
+		body.WriteString("## Vulnerabilities\n\n")
+		for _, cve := range advisory.CVEs {
+			body.WriteString(fmt.Sprintf("- %s\n", cve))
+		}
+		body.WriteString("\n")
+	}
+
+	if len(advisory.Findings) > 0 {
+		body.WriteString("## Findings\n\n")
+		for _, finding := range advisory.Findings {
+			body.WriteString(fmt.Sprintf("- %s\n", finding))
+		}
+		body.WriteString("\n")
+	}
+
+	versions := make([]string, 0, len(advisory.Consumers))
+	for version := range advisory.Consumers {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+
+	body.WriteString("## Affected Consumers\n")
+	for _, version := range versions {
+		body.WriteString(fmt.Sprintf("\n### `%s`\n\n", version))
+		for _, consumer := range advisory.Consumers[version] {
+			body.WriteString(fmt.Sprintf("- %s\n", consumer))
+		}
+	}
+
+	body.WriteString("\n---\n*This advisory was drafted by actions-maintainer.*\n")
+
+	return body.String()
+}
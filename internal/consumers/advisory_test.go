@@ -0,0 +1,61 @@
+package consumers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+func TestFindAdvisories(t *testing.T) {
+	vulnerable := output.ActionIssue{Repository: "my-org/deploy", CurrentVersion: "v1", SuggestedVersion: "v2.1.0", IssueType: "outdated", Severity: "high", Description: "Leaks the deploy token to logs"}
+	vulnerable.SetMetadata(output.MetadataCVE, "CVE-2025-0001")
+
+	repositories := []output.RepositoryResult{
+		{FullName: "my-org/api", Issues: []output.ActionIssue{
+			vulnerable,
+			{Repository: "my-org/lint", CurrentVersion: "v1", SuggestedVersion: "v3", IssueType: "deprecated", Severity: "medium", Description: "Uses the node16 runtime"},
+			{Repository: "my-org/build", CurrentVersion: "v1", IssueType: "outdated", Severity: "low"},
+			{Repository: "actions/checkout", CurrentVersion: "v2", IssueType: "deprecated", Severity: "medium"},
+		}},
+		{FullName: "my-org/web", Issues: []output.ActionIssue{
+			{Repository: "my-org/deploy", CurrentVersion: "v2", SuggestedVersion: "v2.1.0", IssueType: "deprecated", Severity: "low", Description: "Uses the node16 runtime"},
+		}},
+		{FullName: "my-org/deploy", Issues: []output.ActionIssue{
+			{Repository: "my-org/deploy", CurrentVersion: "v1", IssueType: "security", Severity: "critical"}, // Its own action
+		}},
+	}
+
+	advisories := FindAdvisories(repositories)
+	if len(advisories) != 2 || advisories[0].Repository != "my-org/deploy" || advisories[1].Repository != "my-org/lint" {
+		t.Fatalf("Expected advisories for deploy and lint, most severe first, got %+v", advisories)
+	}
+
+	deploy := advisories[0]
+	if !deploy.Vulnerable || !deploy.Deprecated || deploy.Severity != "high" || deploy.PatchedVersion != "v2.1.0" {
+		t.Errorf("Unexpected deploy advisory: %+v", deploy)
+	}
+	if consumers := deploy.AffectedConsumers(); strings.Join(consumers, ",") != "my-org/api,my-org/web" {
+		t.Errorf("Expected api and web to be affected, got %v", consumers)
+	}
+
+	if summary := AdvisorySummary(deploy); summary != "Vulnerable and deprecated versions of my-org/deploy are still in use" {
+		t.Errorf("Expected a stable summary, got %q", summary)
+	}
+	description := AdvisoryDescription(deploy)
+	for _, expected := range []string{
+		"2 repositories use versions of `my-org/deploy`",
+		"Consumers should move to `v2.1.0`.",
+		"- CVE-2025-0001",
+		"- Leaks the deploy token to logs",
+		"### `v2`\n\n- my-org/web",
+	} {
+		if !strings.Contains(description, expected) {
+			t.Errorf("Expected the description to contain %q, got:\n%s", expected, description)
+		}
+	}
+
+	if summary := AdvisorySummary(advisories[1]); summary != "Deprecated versions of my-org/lint are still in use" {
+		t.Errorf("Unexpected lint summary %q", summary)
+	}
+}
@@ -0,0 +1,107 @@
+package consumers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// DefaultMinVersions is how many versions of an internal action must be in use before it gets a release checklist
+const DefaultMinVersions = 3
+
+// Fragmented returns the internal actions whose consumers use at least minVersions versions, most
+// versions first. Archived actions are skipped, as their repositories cannot take new issues.
+func Fragmented(actions []output.InternalAction, minVersions int) []output.InternalAction {
+	if minVersions <= 0 {
+		minVersions = DefaultMinVersions
+	}
+
+	var fragmented []output.InternalAction
+	for _, action := range actions {
+		if action.Archived || len(action.Versions) < minVersions {
+			continue
+		}
+		fragmented = append(fragmented, action)
+	}
+	sort.SliceStable(fragmented, func(i, j int) bool {
+		return len(fragmented[i].Versions) > len(fragmented[j].Versions)
+	})
+	return fragmented
+}
+
+// ChecklistTitle returns the title of the release checklist issue of an internal action
+// The title does not change between scans, so an open checklist can be found again.
+func ChecklistTitle(action output.InternalAction) string {
+	return fmt.Sprintf("Release checklist: converge consumers of %s", action.Repository)
+}
+
+// ChecklistBody lists the consumers of each version of an internal action in use, most used first,
+// with a migration checklist for every consumer not yet on the latest release
+func ChecklistBody(action output.InternalAction) string {
+	var body strings.Builder
+
+	target := "the latest release"
+	if action.LatestRelease != "" {
+		target = fmt.Sprintf("`%s`", action.LatestRelease)
+	}
+	body.WriteString(fmt.Sprintf("The %d consumers of `%s` use %d different versions. ", len(action.Consumers), action.Repository, len(action.Versions)))
+	body.WriteString(fmt.Sprintf("This checklist tracks moving every consumer to %s.\n\n", target))
+
+	versions := make([]string, 0, len(action.Versions))
+	for version := range action.Versions {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		if action.Versions[versions[i]] != action.Versions[versions[j]] {
+			return action.Versions[versions[i]] > action.Versions[versions[j]]
+		}
+		return versions[i] < versions[j]
+	})
+
+	body.WriteString("## Versions in Use\n\n")
+	body.WriteString("| Version | Consumers |\n")
+	body.WriteString("|---------|-----------|\n")
+	for _, version := range versions {
+		body.WriteString(fmt.Sprintf("| `%s` | %d |\n", version, action.Versions[version]))
+	}
+
+	body.WriteString("\n## Migration Checklist\n")
+	pending := 0
+	for _, version := range versions {
+		if onRelease(version, action.LatestRelease) {
+			continue
+		}
+		body.WriteString(fmt.Sprintf("\n### From `%s`\n\n", version))
+		consumers := action.VersionConsumers[version]
+		if len(consumers) == 0 {
+			body.WriteString(fmt.Sprintf("- [ ] %d consumers (not listed in this scan)\n", action.Versions[version]))
+			pending++
+			continue
+		}
+		for _, consumer := range consumers {
+			body.WriteString(fmt.Sprintf("- [ ] %s\n", consumer))
+			pending++
+		}
+	}
+	if pending == 0 {
+		body.WriteString("\nEvery consumer is on the latest release.\n")
+	}
+
+	body.WriteString("\n---\n*This issue was created by actions-maintainer.*\n")
+
+	return body.String()
+}
+
+// onRelease reports whether a consumed version is the latest release or its major tag, such as v2 for v2.1.0
+func onRelease(version, latestRelease string) bool {
+	if latestRelease == "" {
+		return false
+	}
+	if version == latestRelease {
+		return true
+	}
+	major, ok := majorVersion(latestRelease)
+	return ok && strings.TrimPrefix(version, "v") == fmt.Sprint(major)
+}
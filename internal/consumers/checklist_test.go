@@ -0,0 +1,51 @@
+package consumers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+func TestFragmented(t *testing.T) {
+	actions := []output.InternalAction{
+		{Repository: "my-org/lint", Versions: map[string]int{"v1": 2, "v2": 1}},
+		{Repository: "my-org/deploy", Versions: map[string]int{"v1": 2, "v2": 1, "v2.1.0": 1, "main": 1}},
+		{Repository: "my-org/old", Versions: map[string]int{"v1": 1, "v2": 1, "v3": 1}, Archived: true},
+		{Repository: "my-org/build", Versions: map[string]int{"v1": 1, "v2": 1, "v3": 1}},
+	}
+
+	fragmented := Fragmented(actions, 3)
+	if len(fragmented) != 2 || fragmented[0].Repository != "my-org/deploy" || fragmented[1].Repository != "my-org/build" {
+		t.Errorf("Expected deploy and build, most versions first, without the archived action, got %+v", fragmented)
+	}
+}
+
+func TestChecklistBody(t *testing.T) {
+	action := output.InternalAction{
+		Repository:    "my-org/deploy",
+		Consumers:     []string{"my-org/api", "my-org/db", "my-org/web"},
+		Versions:      map[string]int{"v1": 2, "v2": 1, "v2.1.0": 1},
+		LatestRelease: "v2.1.0",
+		VersionConsumers: map[string][]string{
+			"v1":     {"my-org/api", "my-org/db"},
+			"v2":     {"my-org/web"},
+			"v2.1.0": {"my-org/api"},
+		},
+	}
+
+	if title := ChecklistTitle(action); title != "Release checklist: converge consumers of my-org/deploy" {
+		t.Errorf("Expected a stable title, got %q", title)
+	}
+
+	body := ChecklistBody(action)
+	if !strings.Contains(body, "| `v1` | 2 |") {
+		t.Errorf("Expected the versions table, got:\n%s", body)
+	}
+	if !strings.Contains(body, "### From `v1`\n\n- [ ] my-org/api\n- [ ] my-org/db\n") {
+		t.Errorf("Expected a checklist of v1 consumers, got:\n%s", body)
+	}
+	if strings.Contains(body, "From `v2`") || strings.Contains(body, "From `v2.1.0`") {
+		t.Errorf("Expected consumers of the latest release and its major tag not to be listed, got:\n%s", body)
+	}
+}
@@ -0,0 +1,201 @@
+package consumers
+
+import (
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// DefaultMinConsumers is how many scanned repositories must use an internal action before it is reported
+const DefaultMinConsumers = 1
+
+// ActivityClient reads the maintenance state of an action repository
+type ActivityClient interface {
+	GetRepositoryActivity(owner, repo string) (*github.RepositoryActivity, error)
+}
+
+// Config holds configuration options for internal action reports
+type Config struct {
+	Verbose      bool
+	MinConsumers int // Scanned repositories that must use an action; zero uses DefaultMinConsumers
+}
+
+// Reporter builds the maintainer-facing view of internal actions
+type Reporter struct {
+	client       ActivityClient
+	minConsumers int
+	verbose      bool
+}
+
+// NewReporter creates a reporter for actions used by at least minConsumers scanned repositories
+func NewReporter(client ActivityClient, minConsumers int) *Reporter {
+	return NewReporterWithConfig(client, &Config{Verbose: false, MinConsumers: minConsumers})
+}
+
+// NewReporterWithConfig creates a reporter with configuration
+func NewReporterWithConfig(client ActivityClient, config *Config) *Reporter {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	minConsumers := config.MinConsumers
+	if minConsumers <= 0 {
+		minConsumers = DefaultMinConsumers
+	}
+
+	return &Reporter{
+		client:       client,
+		minConsumers: minConsumers,
+		verbose:      config.Verbose,
+	}
+}
+
+// internalAction collects how an internal action is consumed
+type internalAction struct {
+	consumers map[string]bool
+	uses      int
+	versions  map[string]map[string]bool // version -> consumers
+}
+
+// Report returns the consumers, version spread, and repository state of each internal action used by
+// enough repositories, most widely used first
+//
+// Actions are internal when their owner also owns a scanned repository. A repository using its own
+// action is not a consumer. Actions whose repository cannot be read are reported without its state.
+func (r *Reporter) Report(repositories []output.RepositoryResult) []output.InternalAction {
+	owners := make(map[string]bool)
+	for _, repo := range repositories {
+		owners[strings.ToLower(ownerOf(repo.FullName))] = true
+	}
+
+	usage := make(map[string]*internalAction)
+	for _, repo := range repositories {
+		for _, action := range repo.Actions {
+			if !owners[strings.ToLower(ownerOf(action.Repository))] || strings.EqualFold(action.Repository, repo.FullName) {
+				continue
+			}
+			entry, exists := usage[action.Repository]
+			if !exists {
+				entry = &internalAction{consumers: make(map[string]bool), versions: make(map[string]map[string]bool)}
+				usage[action.Repository] = entry
+			}
+			entry.consumers[repo.FullName] = true
+			entry.uses++
+			version := consumedVersion(action)
+			if entry.versions[version] == nil {
+				entry.versions[version] = make(map[string]bool)
+			}
+			entry.versions[version][repo.FullName] = true
+		}
+	}
+
+	var reports []output.InternalAction
+	for actionRepo, entry := range usage {
+		if len(entry.consumers) < r.minConsumers {
+			continue
+		}
+
+		report := output.InternalAction{
+			Repository:       actionRepo,
+			Consumers:        sortedKeys(entry.consumers),
+			Uses:             entry.uses,
+			Versions:         make(map[string]int),
+			VersionConsumers: make(map[string][]string),
+		}
+		for version, consumers := range entry.versions {
+			report.Versions[version] = len(consumers)
+			report.VersionConsumers[version] = sortedKeys(consumers)
+		}
+
+		owner, name, _ := strings.Cut(actionRepo, "/")
+		activity, err := r.client.GetRepositoryActivity(owner, name)
+		if err != nil {
+			log.Printf("Warning: Failed to read the repository of internal action %s: %v", actionRepo, err)
+			reports = append(reports, report)
+			continue
+		}
+		report.Archived = activity.Archived
+		report.OpenIssues = activity.OpenIssues
+		report.OpenPullRequests = activity.OpenPullRequests
+		if activity.LatestRelease != "" {
+			date := activity.LatestReleaseDate
+			report.LatestRelease = activity.LatestRelease
+			report.LatestReleaseDate = &date
+			report.StaleConsumers = staleConsumers(entry.versions, activity.LatestRelease)
+		}
+		if r.verbose {
+			log.Printf("Internal action %s: %d consumers, %d on an older major version than %s",
+				actionRepo, len(report.Consumers), len(report.StaleConsumers), activity.LatestRelease)
+		}
+		reports = append(reports, report)
+	}
+
+	// Most widely used actions first
+	sort.Slice(reports, func(i, j int) bool {
+		if len(reports[i].Consumers) != len(reports[j].Consumers) {
+			return len(reports[i].Consumers) > len(reports[j].Consumers)
+		}
+		return reports[i].Repository < reports[j].Repository
+	})
+
+	return reports
+}
+
+// consumedVersion returns the version an action reference uses, reading SHA pins by their pin comment
+func consumedVersion(action workflow.ActionReference) string {
+	if output.PinStyle(action.Version) == output.PinStyleSHA {
+		if version := workflow.PinCommentVersion(action.PinComment); version != "" {
+			return version
+		}
+	}
+	return action.Version
+}
+
+// staleConsumers returns the consumers using a version of an older major version than the latest release
+// Versions without a major version, such as branches and unannotated SHA pins, are not judged.
+func staleConsumers(versions map[string]map[string]bool, latestRelease string) []string {
+	latest, ok := majorVersion(latestRelease)
+	if !ok {
+		return nil
+	}
+	stale := make(map[string]bool)
+	for version, consumers := range versions {
+		if major, ok := majorVersion(version); ok && major < latest {
+			for consumer := range consumers {
+				stale[consumer] = true
+			}
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	return sortedKeys(stale)
+}
+
+// majorVersion returns the major version number of a version such as v4 or v4.1.2
+func majorVersion(version string) (int, bool) {
+	major, _, _ := strings.Cut(strings.TrimPrefix(version, "v"), ".")
+	number, err := strconv.Atoi(major)
+	return number, err == nil
+}
+
+// ownerOf returns the owner part of an "owner/name" reference
+func ownerOf(repository string) string {
+	owner, _, _ := strings.Cut(repository, "/")
+	return owner
+}
+
+// sortedKeys returns the keys of a set in sorted order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,75 @@
+package consumers
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+type testActivityClient map[string]*github.RepositoryActivity
+
+func (c testActivityClient) GetRepositoryActivity(owner, repo string) (*github.RepositoryActivity, error) {
+	activity, ok := c[owner+"/"+repo]
+	if !ok {
+		return nil, fmt.Errorf("not found")
+	}
+	return activity, nil
+}
+
+func consumerRepositories() []output.RepositoryResult {
+	return []output.RepositoryResult{
+		{FullName: "my-org/api", Actions: []workflow.ActionReference{
+			{Repository: "my-org/deploy", Version: "v1"},
+			{Repository: "my-org/deploy", Version: "v2"},
+			{Repository: "actions/checkout", Version: "v4"},
+		}},
+		{FullName: "my-org/web", Actions: []workflow.ActionReference{
+			{Repository: "my-org/deploy", Version: "0123456789abcdef0123456789abcdef01234567", PinComment: "v2.1.0"},
+			{Repository: "my-org/lint", Version: "main"},
+		}},
+		{FullName: "my-org/deploy", Actions: []workflow.ActionReference{
+			{Repository: "my-org/deploy", Version: "v2"},
+		}},
+	}
+}
+
+func TestReport(t *testing.T) {
+	released := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	client := testActivityClient{
+		"my-org/deploy": {OpenIssues: 4, OpenPullRequests: 2, LatestRelease: "v2.1.0", LatestReleaseDate: released},
+	}
+
+	reports := NewReporter(client, 1).Report(consumerRepositories())
+	if len(reports) != 2 {
+		t.Fatalf("Expected 2 internal actions, got %+v", reports)
+	}
+
+	deploy := reports[0]
+	if deploy.Repository != "my-org/deploy" || !reflect.DeepEqual(deploy.Consumers, []string{"my-org/api", "my-org/web"}) || deploy.Uses != 3 {
+		t.Errorf("Expected deploy consumed by api and web but not by itself, got %+v", deploy)
+	}
+	if !reflect.DeepEqual(deploy.Versions, map[string]int{"v1": 1, "v2": 1, "v2.1.0": 1}) {
+		t.Errorf("Expected consumers by version, with the SHA pin read by its comment, got %v", deploy.Versions)
+	}
+	if !reflect.DeepEqual(deploy.StaleConsumers, []string{"my-org/api"}) {
+		t.Errorf("Expected api to be behind the v2 release, got %v", deploy.StaleConsumers)
+	}
+	if deploy.OpenIssues != 4 || deploy.OpenPullRequests != 2 || deploy.LatestReleaseDate == nil || !deploy.LatestReleaseDate.Equal(released) {
+		t.Errorf("Expected the action repository's activity, got %+v", deploy)
+	}
+
+	// The lint repository cannot be read, so it is reported with its consumers only
+	lint := reports[1]
+	if lint.Repository != "my-org/lint" || len(lint.Consumers) != 1 || lint.LatestRelease != "" || lint.StaleConsumers != nil {
+		t.Errorf("Expected lint without repository state, got %+v", lint)
+	}
+
+	if reports := NewReporter(client, 2).Report(consumerRepositories()); len(reports) != 1 {
+		t.Errorf("Expected only deploy with 2 consumers, got %+v", reports)
+	}
+}
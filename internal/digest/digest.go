@@ -0,0 +1,217 @@
+package digest
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/verify"
+)
+
+// DefaultStalledDays is how long a pull request stays open before the digest lists it as stalled
+const DefaultStalledDays = 7
+
+// DefaultDeadlineDays is how far ahead the digest looks for deprecation deadlines
+const DefaultDeadlineDays = 30
+
+// severities orders the severity counts of the trend, most severe first
+var severities = []string{"critical", "high", "medium", "low"}
+
+// Options tune what the digest counts as stalled and upcoming
+type Options struct {
+	Now          time.Time
+	StalledDays  int // Open pull requests older than this are stalled
+	DeadlineDays int // Deadlines within this many days are upcoming; passed deadlines are always listed
+}
+
+// ParseOptions validates the --stalled-days and --deadline-days values of the digest command; an
+// empty value keeps the default
+func ParseOptions(stalledDays, deadlineDays string, now time.Time) (Options, error) {
+	options := Options{Now: now}
+	for _, flag := range []struct {
+		name  string
+		value string
+		days  *int
+	}{
+		{"stalled-days", stalledDays, &options.StalledDays},
+		{"deadline-days", deadlineDays, &options.DeadlineDays},
+	} {
+		if flag.value == "" {
+			continue
+		}
+		days, err := strconv.Atoi(flag.value)
+		if err != nil || days <= 0 {
+			return Options{}, fmt.Errorf("--%s must be a positive number of days", flag.name)
+		}
+		*flag.days = days
+	}
+	return options, nil
+}
+
+// Finding is an issue of a scanned repository
+type Finding struct {
+	Repository string             `json:"repository"`
+	Issue      output.ActionIssue `json:"issue"`
+}
+
+// StalledPR is a pull request opened by actions-maintainer that is still open
+type StalledPR struct {
+	Repository  string             `json:"repository"`
+	PullRequest verify.PullRequest `json:"pull_request"`
+	DaysOpen    int                `json:"days_open"`
+}
+
+// Digest is the weekly summary of a scan: what changed since the previous scan, what is stuck, and what
+// is about to break
+type Digest struct {
+	Owner       string    `json:"owner"`
+	ScanTime    time.Time `json:"scan_time"`
+	GeneratedAt time.Time `json:"generated_at"`
+
+	NewIssues   []Finding `json:"new_issues"`
+	FixedIssues []Finding `json:"fixed_issues"`
+	Compared    bool      `json:"compared"` // False when there was no previous scan, so every issue counts as new and none as fixed
+
+	StalledPRs      []StalledPR `json:"stalled_prs"`
+	CheckedPRs      int         `json:"checked_prs"` // Pull requests whose state was read
+	PRStatusSkipped bool        `json:"pr_status_skipped,omitempty"`
+
+	Deadlines []output.Deadline         `json:"deadlines"`
+	Trend     []output.SeveritySnapshot `json:"trend"` // Severity counts of previous scans and this one, oldest first
+
+	stalledDays  int
+	deadlineDays int
+}
+
+// Build assembles the digest of a scan. New and fixed issues are found by comparing with the previous
+// scan; without one, the issues a scan with --baseline marked as existing are not new, and fixed issues
+// are unknown. pulls holds the state of the pull requests actions-maintainer opened, or nil when it could
+// not be read.
+func Build(current, previous *output.ScanResult, pulls map[string][]verify.PullRequest, options Options) *Digest {
+	if options.StalledDays <= 0 {
+		options.StalledDays = DefaultStalledDays
+	}
+	if options.DeadlineDays <= 0 {
+		options.DeadlineDays = DefaultDeadlineDays
+	}
+
+	digest := &Digest{
+		Owner:           current.Owner,
+		ScanTime:        current.ScanTime,
+		GeneratedAt:     options.Now,
+		NewIssues:       []Finding{},
+		FixedIssues:     []Finding{},
+		StalledPRs:      []StalledPR{},
+		PRStatusSkipped: pulls == nil,
+		Deadlines:       []output.Deadline{},
+		stalledDays:     options.StalledDays,
+		deadlineDays:    options.DeadlineDays,
+	}
+
+	digest.compareIssues(current, previous)
+	digest.findStalled(pulls, options.Now)
+
+	for _, deadline := range current.Summary.Deadlines {
+		if deadline.DaysFrom(options.Now) <= options.DeadlineDays {
+			digest.Deadlines = append(digest.Deadlines, deadline)
+		}
+	}
+
+	history := current.Summary.SeverityHistory
+	if previous != nil {
+		history = output.SeverityHistoryFrom(previous)
+	}
+	digest.Trend = append(append([]output.SeveritySnapshot{}, history...), snapshotOf(current))
+	return digest
+}
+
+// compareIssues finds the issues that are new in the current scan and those the previous scan had that
+// are gone
+func (d *Digest) compareIssues(current, previous *output.ScanResult) {
+	if previous == nil {
+		d.Compared = len(current.Summary.SeverityHistory) > 0
+		for _, repo := range current.Repositories {
+			for _, issue := range repo.Issues {
+				if !issue.Existing {
+					d.NewIssues = append(d.NewIssues, Finding{Repository: repo.FullName, Issue: issue})
+				}
+			}
+		}
+		return
+	}
+
+	d.Compared = true
+	currentKeys := issueKeys(current)
+	previousKeys := issueKeys(previous)
+	for _, repo := range current.Repositories {
+		for _, issue := range repo.Issues {
+			if !previousKeys[issueKey(repo.FullName, issue)] {
+				d.NewIssues = append(d.NewIssues, Finding{Repository: repo.FullName, Issue: issue})
+			}
+		}
+	}
+	for _, repo := range previous.Repositories {
+		for _, issue := range repo.Issues {
+			if !currentKeys[issueKey(repo.FullName, issue)] {
+				d.FixedIssues = append(d.FixedIssues, Finding{Repository: repo.FullName, Issue: issue})
+			}
+		}
+	}
+}
+
+// findStalled lists the open pull requests older than the stalled threshold, longest open first
+func (d *Digest) findStalled(pulls map[string][]verify.PullRequest, now time.Time) {
+	for repository, repoPulls := range pulls {
+		for _, pull := range repoPulls {
+			if pull.State != verify.StateUnknown {
+				d.CheckedPRs++
+			}
+			if pull.State != verify.StateOpen || pull.CreatedAt == nil {
+				continue
+			}
+			days := int(now.Sub(*pull.CreatedAt).Hours() / 24)
+			if days >= d.stalledDays {
+				d.StalledPRs = append(d.StalledPRs, StalledPR{Repository: repository, PullRequest: pull, DaysOpen: days})
+			}
+		}
+	}
+	sort.Slice(d.StalledPRs, func(i, j int) bool {
+		a, b := d.StalledPRs[i], d.StalledPRs[j]
+		if a.DaysOpen != b.DaysOpen {
+			return a.DaysOpen > b.DaysOpen
+		}
+		if a.Repository != b.Repository {
+			return a.Repository < b.Repository
+		}
+		return a.PullRequest.Number < b.PullRequest.Number
+	})
+}
+
+// snapshotOf records the severity counts of a scan for the trend
+func snapshotOf(result *output.ScanResult) output.SeveritySnapshot {
+	snapshot := output.SeveritySnapshot{ScanTime: result.ScanTime, IssuesBySeverity: result.Summary.IssuesBySeverity}
+	if result.Summary.Debt != nil {
+		snapshot.DebtScore = result.Summary.Debt.Score
+	}
+	return snapshot
+}
+
+// issueKeys returns the keys of every issue of a scan
+func issueKeys(result *output.ScanResult) map[string]bool {
+	keys := make(map[string]bool)
+	for _, repo := range result.Repositories {
+		for _, issue := range repo.Issues {
+			keys[issueKey(repo.FullName, issue)] = true
+		}
+	}
+	return keys
+}
+
+// issueKey identifies an issue across scans the way scan --baseline does, so an issue whose version
+// changed is fixed and new again
+func issueKey(repoFullName string, issue output.ActionIssue) string {
+	return strings.Join([]string{strings.ToLower(repoFullName), issue.FilePath, strings.ToLower(issue.Repository), issue.IssueType, issue.CurrentVersion}, "\x00")
+}
@@ -0,0 +1,184 @@
+package digest
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/verify"
+)
+
+func issue(action, version, issueType string) output.ActionIssue {
+	return output.ActionIssue{Repository: action, CurrentVersion: version, IssueType: issueType, Severity: "medium", FilePath: ".github/workflows/ci.yml", Description: issueType + " " + action}
+}
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+	previous := &output.ScanResult{
+		Owner:    "my-org",
+		ScanTime: now.AddDate(0, 0, -7),
+		Repositories: []output.RepositoryResult{
+			{FullName: "my-org/api", Issues: []output.ActionIssue{issue("actions/checkout", "v3", "outdated"), issue("actions/cache", "v2", "deprecated")}},
+		},
+		Summary: output.Summary{IssuesBySeverity: map[string]int{"medium": 2}},
+	}
+	current := &output.ScanResult{
+		Owner:    "my-org",
+		ScanTime: now,
+		Repositories: []output.RepositoryResult{
+			{FullName: "my-org/api", Issues: []output.ActionIssue{issue("actions/checkout", "v3", "outdated")}},
+			{FullName: "my-org/web", Issues: []output.ActionIssue{issue("actions/setup-node", "v3", "outdated")}},
+		},
+		Summary: output.Summary{
+			IssuesBySeverity: map[string]int{"medium": 2},
+			Deadlines: []output.Deadline{
+				{Action: "actions/upload-artifact", Versions: []string{"v3"}, Date: now.AddDate(0, 0, 10), Repositories: 2},
+				{Action: "actions/download-artifact", Versions: []string{"v3"}, Date: now.AddDate(0, 0, 90), Repositories: 1},
+			},
+		},
+	}
+
+	opened := now.AddDate(0, 0, -10)
+	recent := now.AddDate(0, 0, -2)
+	pulls := map[string][]verify.PullRequest{
+		"my-org/api": {
+			{Number: 1, URL: "https://github.com/my-org/api/pull/1", Title: "Update actions", State: verify.StateOpen, CreatedAt: &opened},
+			{Number: 2, State: verify.StateMerged, CreatedAt: &opened},
+		},
+		"my-org/web": {{Number: 3, State: verify.StateOpen, CreatedAt: &recent}, {Number: 4, State: verify.StateUnknown}},
+	}
+
+	digest := Build(current, previous, pulls, Options{Now: now})
+	if !digest.Compared || len(digest.NewIssues) != 1 || digest.NewIssues[0].Repository != "my-org/web" {
+		t.Errorf("Expected the setup-node issue of web to be new, got %+v", digest.NewIssues)
+	}
+	if len(digest.FixedIssues) != 1 || digest.FixedIssues[0].Issue.Repository != "actions/cache" {
+		t.Errorf("Expected the cache issue to be fixed, got %+v", digest.FixedIssues)
+	}
+	if len(digest.StalledPRs) != 1 || digest.StalledPRs[0].PullRequest.Number != 1 || digest.StalledPRs[0].DaysOpen != 10 {
+		t.Errorf("Expected pull request 1 to be stalled for 10 days, got %+v", digest.StalledPRs)
+	}
+	if digest.CheckedPRs != 3 {
+		t.Errorf("Expected 3 pull requests with a known state, got %d", digest.CheckedPRs)
+	}
+	if len(digest.Deadlines) != 1 || digest.Deadlines[0].Action != "actions/upload-artifact" {
+		t.Errorf("Expected only the upload-artifact deadline within 30 days, got %+v", digest.Deadlines)
+	}
+	if len(digest.Trend) != 2 || !digest.Trend[0].ScanTime.Equal(previous.ScanTime) || !digest.Trend[1].ScanTime.Equal(now) {
+		t.Errorf("Expected a trend of the previous and current scans, got %+v", digest.Trend)
+	}
+}
+
+func TestBuild_WithoutPrevious(t *testing.T) {
+	existing := issue("actions/checkout", "v3", "outdated")
+	existing.Existing = true
+	current := &output.ScanResult{
+		Owner: "my-org",
+		Repositories: []output.RepositoryResult{
+			{FullName: "my-org/api", Issues: []output.ActionIssue{existing, issue("actions/cache", "v2", "deprecated")}},
+		},
+		Summary: output.Summary{SeverityHistory: []output.SeveritySnapshot{{IssuesBySeverity: map[string]int{"medium": 1}}}},
+	}
+
+	digest := Build(current, nil, nil, Options{Now: time.Now()})
+	if !digest.Compared || len(digest.NewIssues) != 1 || digest.NewIssues[0].Issue.Repository != "actions/cache" {
+		t.Errorf("Expected issues the baseline scan marked as existing not to be new, got %+v", digest.NewIssues)
+	}
+	if !digest.PRStatusSkipped {
+		t.Error("Expected pull request status to be skipped without pull request states")
+	}
+
+	current.Summary.SeverityHistory = nil
+	if digest := Build(current, nil, nil, Options{Now: time.Now()}); digest.Compared {
+		t.Error("Expected a scan without a baseline not to be compared")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	now := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+	opened := now.AddDate(0, 0, -9)
+	current := &output.ScanResult{
+		Owner:    "my-org",
+		ScanTime: now,
+		Repositories: []output.RepositoryResult{
+			{FullName: "my-org/web", Issues: []output.ActionIssue{issue("actions/setup-node", "v3", "outdated")}},
+		},
+		Summary: output.Summary{
+			Deadlines: []output.Deadline{{Action: "actions/upload-artifact", Versions: []string{"v3"}, Date: time.Date(2026, 10, 9, 0, 0, 0, 0, time.UTC), Repositories: 2}},
+		},
+	}
+	pulls := map[string][]verify.PullRequest{
+		"my-org/api": {{Number: 1, URL: "https://github.com/my-org/api/pull/1", Title: "Update actions", State: verify.StateOpen, CreatedAt: &opened}},
+	}
+	digest := Build(current, &output.ScanResult{ScanTime: now.AddDate(0, 0, -7)}, pulls, Options{Now: now})
+
+	var markdown bytes.Buffer
+	if err := Write(&markdown, digest, FormatMarkdown, EmailHeaders{}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	for _, expected := range []string{
+		"# Weekly Actions Digest: my-org",
+		"- **1** new issues",
+		"| Scan | Critical | High | Medium | Low |",
+		"- **outdated** `actions/setup-node@v3` in my-org/web/.github/workflows/ci.yml (MEDIUM)",
+		"## Fixed Issues\n\nNone.",
+		"- my-org/api [#1](https://github.com/my-org/api/pull/1): Update actions (open 9 days)",
+		"- **actions/upload-artifact** `v3` on 2026-10-09 (passed 3 days ago): 2 repositories",
+	} {
+		if !strings.Contains(markdown.String(), expected) {
+			t.Errorf("Expected the Markdown digest to contain %q, got:\n%s", expected, markdown.String())
+		}
+	}
+
+	var slack bytes.Buffer
+	if err := Write(&slack, digest, FormatSlack, EmailHeaders{}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	var payload struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(slack.Bytes(), &payload); err != nil {
+		t.Fatalf("Expected a webhook payload, got %q: %v", slack.String(), err)
+	}
+	if !strings.Contains(payload.Text, "<https://github.com/my-org/api/pull/1|#1>") || strings.Contains(payload.Text, "| Scan |") {
+		t.Errorf("Expected Slack mrkdwn links and no tables, got:\n%s", payload.Text)
+	}
+
+	var email bytes.Buffer
+	if err := Write(&email, digest, FormatEmail, EmailHeaders{From: "bot@example.com", To: []string{"platform@example.com", "sre@example.com"}}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	for _, expected := range []string{
+		"From: bot@example.com\r\n",
+		"To: platform@example.com, sre@example.com\r\n",
+		"Subject: Actions digest for my-org: 1 new, 0 fixed, 1 stalled PRs, 1 deadlines\r\n",
+		"\r\n\r\n# Weekly Actions Digest: my-org\r\n",
+	} {
+		if !strings.Contains(email.String(), expected) {
+			t.Errorf("Expected the email digest to contain %q, got:\n%s", expected, email.String())
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if format, err := ParseFormat(""); err != nil || format != FormatMarkdown {
+		t.Errorf("Expected the default to be markdown, got %q, %v", format, err)
+	}
+	if _, err := ParseFormat("html"); err == nil {
+		t.Error("Expected an unknown format to be rejected")
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	now := time.Now()
+	options, err := ParseOptions("14", "", now)
+	if err != nil || options.StalledDays != 14 || options.DeadlineDays != 0 || !options.Now.Equal(now) {
+		t.Errorf("Expected 14 stalled days and the default deadline window, got %+v, %v", options, err)
+	}
+	if _, err := ParseOptions("", "0", now); err == nil || !strings.Contains(err.Error(), "--deadline-days") {
+		t.Errorf("Expected a non-positive deadline window to be rejected, got %v", err)
+	}
+}
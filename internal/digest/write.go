@@ -0,0 +1,219 @@
+package digest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// Digest formats
+const (
+	FormatMarkdown = "markdown"
+	FormatEmail    = "email" // RFC 5322 message for sendmail or an SMTP relay, with the Markdown as plain text body
+	FormatSlack    = "slack" // Payload for a Slack incoming webhook
+	FormatJSON     = "json"
+)
+
+// maxListed caps the entries listed per section, keeping Slack messages within their size limit
+const maxListed = 50
+
+// ParseFormat validates a digest format; "" is FormatMarkdown
+func ParseFormat(value string) (string, error) {
+	switch value {
+	case "":
+		return FormatMarkdown, nil
+	case FormatMarkdown, FormatEmail, FormatSlack, FormatJSON:
+		return value, nil
+	}
+	return "", fmt.Errorf("unknown digest format %q (expected %s, %s, %s, or %s)", value, FormatMarkdown, FormatEmail, FormatSlack, FormatJSON)
+}
+
+// EmailHeaders address a digest written as email
+type EmailHeaders struct {
+	From string
+	To   []string
+}
+
+// Subject returns the subject line of a digest
+func Subject(digest *Digest) string {
+	return fmt.Sprintf("Actions digest for %s: %d new, %d fixed, %d stalled PRs, %d deadlines",
+		digest.Owner, len(digest.NewIssues), len(digest.FixedIssues), len(digest.StalledPRs), len(digest.Deadlines))
+}
+
+// Write writes a digest in a format
+func Write(w io.Writer, digest *Digest, format string, headers EmailHeaders) error {
+	var err error
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(digest)
+	case FormatSlack:
+		err = json.NewEncoder(w).Encode(map[string]string{"text": render(digest, slackStyle)})
+	case FormatEmail:
+		_, err = io.WriteString(w, email(digest, headers))
+	default:
+		_, err = io.WriteString(w, render(digest, markdownStyle))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write digest: %w", err)
+	}
+	return nil
+}
+
+// email returns the digest as a plain text message
+func email(digest *Digest, headers EmailHeaders) string {
+	var b strings.Builder
+	if headers.From != "" {
+		fmt.Fprintf(&b, "From: %s\r\n", headers.From)
+	}
+	if len(headers.To) > 0 {
+		fmt.Fprintf(&b, "To: %s\r\n", strings.Join(headers.To, ", "))
+	}
+	fmt.Fprintf(&b, "Subject: %s\r\n", Subject(digest))
+	fmt.Fprintf(&b, "Date: %s\r\n", digest.GeneratedAt.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(render(digest, markdownStyle), "\n", "\r\n"))
+	return b.String()
+}
+
+// style renders the markup of a text format
+type style struct {
+	heading func(level int, text string) string
+	bold    func(text string) string
+	link    func(text, url string) string
+	table   bool // Whether the trend is a table
+}
+
+var markdownStyle = style{
+	heading: func(level int, text string) string { return strings.Repeat("#", level) + " " + text },
+	bold:    func(text string) string { return "**" + text + "**" },
+	link:    func(text, url string) string { return "[" + text + "](" + url + ")" },
+	table:   true,
+}
+
+// slackStyle renders Slack mrkdwn, which has no headings or tables
+var slackStyle = style{
+	heading: func(level int, text string) string { return "*" + text + "*" },
+	bold:    func(text string) string { return "*" + text + "*" },
+	link:    func(text, url string) string { return "<" + url + "|" + text + ">" },
+}
+
+// render writes the sections of a digest in a style
+func render(digest *Digest, s style) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n", s.heading(1, "Weekly Actions Digest: "+digest.Owner))
+	fmt.Fprintf(&b, "Scan: %s\n\n", digest.ScanTime.UTC().Format(time.RFC3339))
+
+	fmt.Fprintf(&b, "- %s new issues\n", s.bold(fmt.Sprint(len(digest.NewIssues))))
+	if digest.Compared {
+		fmt.Fprintf(&b, "- %s fixed issues\n", s.bold(fmt.Sprint(len(digest.FixedIssues))))
+	}
+	if !digest.PRStatusSkipped {
+		fmt.Fprintf(&b, "- %s stalled pull requests (open %d+ days)\n", s.bold(fmt.Sprint(len(digest.StalledPRs))), digest.stalledDays)
+	}
+	fmt.Fprintf(&b, "- %s deprecation deadlines within %d days\n", s.bold(fmt.Sprint(len(digest.Deadlines))), digest.deadlineDays)
+
+	writeTrend(&b, digest.Trend, s)
+
+	fmt.Fprintf(&b, "\n%s\n\n", s.heading(2, "New Issues"))
+	if !digest.Compared {
+		b.WriteString("No previous scan to compare with, so every issue is listed as new.\n\n")
+	}
+	writeFindings(&b, digest.NewIssues, s)
+
+	if digest.Compared {
+		fmt.Fprintf(&b, "\n%s\n\n", s.heading(2, "Fixed Issues"))
+		writeFindings(&b, digest.FixedIssues, s)
+	}
+
+	fmt.Fprintf(&b, "\n%s\n\n", s.heading(2, "Stalled Pull Requests"))
+	switch {
+	case digest.PRStatusSkipped:
+		b.WriteString("Pull request status was not checked.\n")
+	case len(digest.StalledPRs) == 0:
+		b.WriteString("None.\n")
+	}
+	for i, stalled := range digest.StalledPRs {
+		if i == maxListed {
+			fmt.Fprintf(&b, "- ...and %d more\n", len(digest.StalledPRs)-maxListed)
+			break
+		}
+		pull := stalled.PullRequest
+		fmt.Fprintf(&b, "- %s %s: %s (open %d days)\n", stalled.Repository, s.link(fmt.Sprintf("#%d", pull.Number), pull.URL), pull.Title, stalled.DaysOpen)
+	}
+
+	fmt.Fprintf(&b, "\n%s\n\n", s.heading(2, "Upcoming Deadlines"))
+	if len(digest.Deadlines) == 0 {
+		b.WriteString("None.\n")
+	}
+	for _, deadline := range digest.Deadlines {
+		days := deadline.DaysFrom(digest.GeneratedAt)
+		when := fmt.Sprintf("in %d days", days)
+		if days < 0 {
+			when = fmt.Sprintf("passed %d days ago", -days)
+		}
+		fmt.Fprintf(&b, "- %s `%s` on %s (%s): %d repositories\n", s.bold(deadline.Action), strings.Join(deadline.Versions, "`, `"),
+			deadline.Date.Format(time.DateOnly), when, deadline.Repositories)
+	}
+
+	return b.String()
+}
+
+// writeTrend lists the severity counts of the scans in the trend
+func writeTrend(b *strings.Builder, trend []output.SeveritySnapshot, s style) {
+	if len(trend) < 2 {
+		return
+	}
+	fmt.Fprintf(b, "\n%s\n\n", s.heading(2, "Trend"))
+	if s.table {
+		b.WriteString("| Scan | Critical | High | Medium | Low |\n")
+		b.WriteString("|------|----------|------|--------|-----|\n")
+	}
+	for _, snapshot := range trend {
+		date := snapshot.ScanTime.UTC().Format(time.DateOnly)
+		if s.table {
+			fmt.Fprintf(b, "| %s |", date)
+			for _, severity := range severities {
+				fmt.Fprintf(b, " %d |", snapshot.IssuesBySeverity[severity])
+			}
+			b.WriteString("\n")
+			continue
+		}
+		counts := make([]string, 0, len(severities))
+		for _, severity := range severities {
+			counts = append(counts, fmt.Sprintf("%d %s", snapshot.IssuesBySeverity[severity], severity))
+		}
+		fmt.Fprintf(b, "- %s: %s\n", date, strings.Join(counts, ", "))
+	}
+}
+
+// writeFindings lists issues with their repository, at most maxListed of them
+func writeFindings(b *strings.Builder, findings []Finding, s style) {
+	if len(findings) == 0 {
+		b.WriteString("None.\n")
+		return
+	}
+	for i, finding := range findings {
+		if i == maxListed {
+			fmt.Fprintf(b, "- ...and %d more\n", len(findings)-maxListed)
+			return
+		}
+		issue := finding.Issue
+		subject := issue.Repository
+		if issue.CurrentVersion != "" {
+			subject += "@" + issue.CurrentVersion
+		}
+		location := finding.Repository
+		if issue.FilePath != "" {
+			location += "/" + issue.FilePath
+		}
+		fmt.Fprintf(b, "- %s `%s` in %s (%s): %s\n", s.bold(issue.IssueType), subject, location, strings.ToUpper(issue.Severity), issue.Description)
+	}
+}
@@ -0,0 +1,153 @@
+package dispatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// IssueTypeRiskyDefault is the issue type for workflow_dispatch inputs with a risky default
+const IssueTypeRiskyDefault = "risky-dispatch-default"
+
+// Rule flags workflow_dispatch inputs whose default is one of a set of risky values
+type Rule struct {
+	Inputs   []string `json:"inputs"`             // Input name patterns, matched case-insensitively with path.Match wildcards, e.g. "*env*"
+	Types    []string `json:"types,omitempty"`    // Input types the rule applies to, e.g. ["boolean"]; empty applies to all
+	Defaults []string `json:"defaults"`           // Risky default values, compared case-insensitively
+	Severity string   `json:"severity,omitempty"` // Severity of issues; "medium" when empty
+	Reason   string   `json:"reason,omitempty"`   // Why the default is risky, added to issue descriptions
+}
+
+// Policy is the set of rules checked against workflow_dispatch input defaults
+type Policy struct {
+	Rules []Rule `json:"rules"`
+}
+
+// DefaultPolicy flags inputs that default to production, and boolean inputs that default to a
+// destructive mode
+var DefaultPolicy = Policy{Rules: []Rule{
+	{
+		Inputs:   []string{"*env*", "*stage*", "*target*"},
+		Defaults: []string{"production", "prod", "prd", "live"},
+		Reason:   "a run that keeps the defaults deploys to production",
+	},
+	{
+		Inputs:   []string{"*force*", "*delete*", "*destroy*", "*drop*", "*purge*", "*wipe*", "*reset*", "*overwrite*", "*skip*"},
+		Types:    []string{"boolean"},
+		Defaults: []string{"true"},
+		Reason:   "a run that keeps the defaults takes the destructive path",
+	},
+	{
+		Inputs:   []string{"*dry*run*", "*dryrun*", "*plan*only*"},
+		Types:    []string{"boolean"},
+		Defaults: []string{"false"},
+		Reason:   "a run that keeps the defaults makes real changes",
+	},
+}}
+
+// LoadPolicy reads a JSON policy file
+func LoadPolicy(filename string) (*Policy, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dispatch policy file: %w", err)
+	}
+
+	var policy Policy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse dispatch policy file: %w", err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Validate checks that every rule names inputs and defaults with valid patterns and severities
+func (p *Policy) Validate() error {
+	if len(p.Rules) == 0 {
+		return fmt.Errorf("dispatch policy has no rules")
+	}
+	for i, rule := range p.Rules {
+		if len(rule.Inputs) == 0 || len(rule.Defaults) == 0 {
+			return fmt.Errorf("dispatch policy rule %d: inputs and defaults are required", i+1)
+		}
+		for _, pattern := range rule.Inputs {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("dispatch policy rule %d: invalid input pattern %q: %w", i+1, pattern, err)
+			}
+		}
+		switch rule.Severity {
+		case "", "low", "medium", "high", "critical":
+		default:
+			return fmt.Errorf("dispatch policy rule %d: invalid severity %q", i+1, rule.Severity)
+		}
+	}
+	return nil
+}
+
+// Check returns an issue for each input whose default a rule flags; each input is reported by the
+// first rule matching it
+func (p *Policy) Check(inputs []workflow.DispatchInput) []output.ActionIssue {
+	var issues []output.ActionIssue
+	for _, input := range inputs {
+		if !input.HasDefault {
+			continue
+		}
+		for _, rule := range p.Rules {
+			if !rule.matches(input) {
+				continue
+			}
+			severity := rule.Severity
+			if severity == "" {
+				severity = "medium"
+			}
+			description := fmt.Sprintf("workflow_dispatch input '%s' defaults to '%s'", input.Name, input.Default)
+			if rule.Reason != "" {
+				description += "; " + rule.Reason
+			}
+			issues = append(issues, output.ActionIssue{
+				Repository:     "workflow_dispatch",
+				CurrentVersion: input.Default,
+				IssueType:      IssueTypeRiskyDefault,
+				Severity:       severity,
+				Description:    description,
+				Context:        "input:" + input.Name,
+				FilePath:       input.FilePath,
+			})
+			break
+		}
+	}
+	return issues
+}
+
+// matches reports whether a rule flags an input's type, name, and default
+func (r Rule) matches(input workflow.DispatchInput) bool {
+	if len(r.Types) > 0 && !containsFold(r.Types, input.Type) {
+		return false
+	}
+	if !containsFold(r.Defaults, input.Default) {
+		return false
+	}
+	name := strings.ToLower(input.Name)
+	for _, pattern := range r.Inputs {
+		if matched, _ := path.Match(strings.ToLower(pattern), name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
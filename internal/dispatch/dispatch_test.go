@@ -0,0 +1,61 @@
+package dispatch
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+func TestDefaultPolicy(t *testing.T) {
+	inputs := []workflow.DispatchInput{
+		{FilePath: "deploy.yml", Name: "environment", Type: "environment", Default: "Production", HasDefault: true},
+		{FilePath: "deploy.yml", Name: "target_env", Type: "choice", Default: "staging", HasDefault: true},
+		{FilePath: "deploy.yml", Name: "force_push", Type: "boolean", Default: "true", HasDefault: true},
+		{FilePath: "deploy.yml", Name: "force_push_note", Type: "string", Default: "true", HasDefault: true},
+		{FilePath: "deploy.yml", Name: "dry-run", Type: "boolean", Default: "false", HasDefault: true},
+		{FilePath: "deploy.yml", Name: "env", Type: "string"},
+	}
+
+	issues := DefaultPolicy.Check(inputs)
+	if len(issues) != 3 {
+		t.Fatalf("Expected 3 issues, got %d: %+v", len(issues), issues)
+	}
+	if issues[0].Context != "input:environment" || !strings.Contains(issues[0].Description, "deploys to production") {
+		t.Errorf("Expected the production default reported, got %+v", issues[0])
+	}
+	if issues[1].Context != "input:force_push" || issues[1].Severity != "medium" || issues[1].IssueType != IssueTypeRiskyDefault {
+		t.Errorf("Expected the destructive boolean reported, got %+v", issues[1])
+	}
+	if issues[2].Context != "input:dry-run" || !strings.Contains(issues[2].Description, "makes real changes") {
+		t.Errorf("Expected the disabled dry run reported, got %+v", issues[2])
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "policy.json")
+	if err := os.WriteFile(file, []byte(`{"rules": [{"inputs": ["region"], "defaults": ["us-east-1"], "severity": "high"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err := LoadPolicy(file)
+	if err != nil {
+		t.Fatalf("Failed to load policy: %v", err)
+	}
+	issues := policy.Check([]workflow.DispatchInput{{Name: "Region", Type: "choice", Default: "us-east-1", HasDefault: true}})
+	if len(issues) != 1 || issues[0].Severity != "high" {
+		t.Errorf("Expected a high severity issue from the custom rule, got %+v", issues)
+	}
+
+	for _, invalid := range []string{`{"rules": []}`, `{"rules": [{"inputs": ["env"]}]}`, `{"rules": [{"inputs": ["["], "defaults": ["x"]}]}`, `{"rules": [{"inputs": ["env"], "defaults": ["prod"], "severity": "urgent"}]}`} {
+		if err := os.WriteFile(file, []byte(invalid), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadPolicy(file); err == nil {
+			t.Errorf("Expected an error for policy %s", invalid)
+		}
+	}
+}
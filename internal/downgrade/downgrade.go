@@ -0,0 +1,180 @@
+package downgrade
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/actions"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// severities in ascending order
+var severities = []string{"low", "medium", "high", "critical"}
+
+// Rule relaxes the issues of repositories matching its conditions, such as development and test
+// repositories, so reports and --fail-on gates focus on production-critical ones
+type Rule struct {
+	Name        string                  `json:"name"`                   // Names the repositories in suppression reasons, e.g. "development"
+	Conditions  *actions.RuleConditions `json:"conditions"`             // Repositories the rule applies to
+	Downgrade   int                     `json:"downgrade,omitempty"`    // Severity levels issues are lowered by, never below low
+	MaxSeverity string                  `json:"max_severity,omitempty"` // Severity issues are capped at
+	Exclude     []string                `json:"exclude,omitempty"`      // Issue types left out of the results, e.g. ["stale-image"]
+	Keep        []string                `json:"keep,omitempty"`         // Issue types never downgraded, e.g. ["security"]
+}
+
+// Policy is the set of rules relaxing issues of non-production repositories
+// A repository is relaxed by the first rule whose conditions it matches.
+type Policy struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Load reads a JSON severity policy file
+func Load(filename string) (*Policy, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read severity policy file: %w", err)
+	}
+
+	var policy Policy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse severity policy file: %w", err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Validate checks that every rule is named, selects repositories, and relaxes something
+func (p *Policy) Validate() error {
+	if len(p.Rules) == 0 {
+		return fmt.Errorf("severity policy has no rules")
+	}
+	for _, rule := range p.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("severity policy rule without a name")
+		}
+		if rule.Conditions == nil {
+			return fmt.Errorf("severity policy rule %q: conditions are required", rule.Name)
+		}
+		if err := rule.Conditions.Validate(); err != nil {
+			return fmt.Errorf("severity policy rule %q: %w", rule.Name, err)
+		}
+		if rule.Downgrade < 0 {
+			return fmt.Errorf("severity policy rule %q: downgrade must not be negative", rule.Name)
+		}
+		if rule.MaxSeverity != "" && !output.IsValidSeverity(rule.MaxSeverity) {
+			return fmt.Errorf("severity policy rule %q: invalid max_severity %q", rule.Name, rule.MaxSeverity)
+		}
+		if rule.Downgrade == 0 && rule.MaxSeverity == "" && len(rule.Exclude) == 0 {
+			return fmt.Errorf("severity policy rule %q: set downgrade, max_severity, or exclude", rule.Name)
+		}
+	}
+	return nil
+}
+
+// Properties returns the custom properties the rules' conditions need, sorted
+func (p *Policy) Properties() []string {
+	seen := make(map[string]bool)
+	var properties []string
+	for _, rule := range p.Rules {
+		if rule.Conditions == nil {
+			continue
+		}
+		for name := range rule.Conditions.CustomProperties {
+			if !seen[name] {
+				seen[name] = true
+				properties = append(properties, name)
+			}
+		}
+	}
+	sort.Strings(properties)
+	return properties
+}
+
+// RuleFor returns the rule relaxing a repository's issues, or nil for repositories no rule matches
+func (p *Policy) RuleFor(repo output.RepositoryResult) *Rule {
+	if p == nil {
+		return nil
+	}
+	context := actions.RepositoryContext{
+		Name:             repo.Name,
+		FullName:         repo.FullName,
+		DefaultBranch:    repo.DefaultBranch,
+		CustomProperties: repo.CustomProperties,
+		Topics:           repo.Topics,
+	}
+	for i := range p.Rules {
+		if p.Rules[i].Conditions.Matches(context) {
+			return &p.Rules[i]
+		}
+	}
+	return nil
+}
+
+// Apply relaxes the issues of a repository matching a rule: excluded issue types are returned as
+// suppressed, and the severity of the others is lowered, recording the original severity in the
+// issue metadata. Issues of other repositories are returned unchanged.
+func (p *Policy) Apply(repo output.RepositoryResult, issues []output.ActionIssue) ([]output.ActionIssue, []output.SuppressedIssue) {
+	rule := p.RuleFor(repo)
+	if rule == nil {
+		return issues, nil
+	}
+
+	var active []output.ActionIssue
+	var excluded []output.SuppressedIssue
+	for _, issue := range issues {
+		if containsFold(rule.Exclude, issue.IssueType) {
+			excluded = append(excluded, output.SuppressedIssue{
+				ActionIssue: issue,
+				Reason:      fmt.Sprintf("%s is excluded for %s repositories by the severity policy", issue.IssueType, rule.Name),
+			})
+			continue
+		}
+		if severity := rule.severity(issue); severity != issue.Severity {
+			issue.SetMetadata(output.MetadataOriginalSeverity, issue.Severity)
+			issue.Severity = severity
+		}
+		active = append(active, issue)
+	}
+	return active, excluded
+}
+
+// severity returns the severity of an issue after the rule lowers it
+func (r *Rule) severity(issue output.ActionIssue) string {
+	level := severityLevel(issue.Severity)
+	if level < 0 || containsFold(r.Keep, issue.IssueType) {
+		return issue.Severity
+	}
+	level -= r.Downgrade
+	if r.MaxSeverity != "" && level > severityLevel(r.MaxSeverity) {
+		level = severityLevel(r.MaxSeverity)
+	}
+	if level < 0 {
+		level = 0
+	}
+	return severities[level]
+}
+
+// severityLevel returns the position of a severity in ascending order, or -1 for unknown severities
+func severityLevel(severity string) int {
+	for i, s := range severities {
+		if s == severity {
+			return i
+		}
+	}
+	return -1
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package downgrade
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/actions"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+func testPolicy() *Policy {
+	return &Policy{Rules: []Rule{
+		{
+			Name:       "development",
+			Conditions: &actions.RuleConditions{CustomProperties: map[string]string{"Environment": "development"}},
+			Downgrade:  1,
+			Exclude:    []string{"stale-image"},
+			Keep:       []string{"security"},
+		},
+		{
+			Name:        "sandbox",
+			Conditions:  &actions.RuleConditions{RepositoryPattern: "^sandbox-"},
+			MaxSeverity: "low",
+		},
+	}}
+}
+
+func TestApply(t *testing.T) {
+	policy := testPolicy()
+	issues := []output.ActionIssue{
+		{Repository: "actions/checkout", IssueType: "outdated", Severity: "high"},
+		{Repository: "actions/cache", IssueType: "outdated", Severity: "low"},
+		{Repository: "tj-actions/changed-files", IssueType: "security", Severity: "critical"},
+		{Repository: "postgres", IssueType: "stale-image", Severity: "low"},
+	}
+
+	dev := output.RepositoryResult{Name: "api", FullName: "my-org/api", CustomProperties: map[string]string{"Environment": "development"}}
+	active, excluded := policy.Apply(dev, issues)
+	var severities []string
+	for _, issue := range active {
+		severities = append(severities, issue.Severity)
+	}
+	if expected := []string{"medium", "low", "critical"}; !reflect.DeepEqual(severities, expected) {
+		t.Errorf("Expected severities %v, got %v", expected, severities)
+	}
+	if active[0].Metadata[output.MetadataOriginalSeverity] != "high" || active[1].Metadata != nil {
+		t.Errorf("Expected the original severity recorded only for lowered issues, got %v and %v", active[0].Metadata, active[1].Metadata)
+	}
+	if len(excluded) != 1 || excluded[0].IssueType != "stale-image" || excluded[0].Reason == "" {
+		t.Errorf("Expected the stale image excluded with a reason, got %+v", excluded)
+	}
+	if issues[0].Severity != "high" {
+		t.Errorf("Expected the input issues unchanged, got %s", issues[0].Severity)
+	}
+
+	sandbox := output.RepositoryResult{Name: "sandbox-demo", FullName: "my-org/sandbox-demo"}
+	active, _ = policy.Apply(sandbox, issues)
+	for _, issue := range active {
+		if issue.Severity != "low" {
+			t.Errorf("Expected sandbox issues capped at low, got %s for %s", issue.Severity, issue.Repository)
+		}
+	}
+
+	production := output.RepositoryResult{Name: "billing", FullName: "my-org/billing", CustomProperties: map[string]string{"Environment": "production"}}
+	if active, excluded := policy.Apply(production, issues); !reflect.DeepEqual(active, issues) || excluded != nil {
+		t.Errorf("Expected production issues unchanged, got %+v and %+v", active, excluded)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(file, []byte(`{"rules": [{"name": "test", "conditions": {"topic": "test"}, "downgrade": 2}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := Load(file)
+	if err != nil {
+		t.Fatalf("Failed to load policy: %v", err)
+	}
+	if rule := policy.RuleFor(output.RepositoryResult{Topics: []string{"test"}}); rule == nil || rule.Name != "test" {
+		t.Errorf("Expected the test rule for a repository with the test topic, got %+v", rule)
+	}
+
+	for _, invalid := range []string{
+		`{"rules": []}`,
+		`{"rules": [{"conditions": {"topic": "test"}, "downgrade": 1}]}`,
+		`{"rules": [{"name": "test", "downgrade": 1}]}`,
+		`{"rules": [{"name": "test", "conditions": {"topic": "test"}}]}`,
+		`{"rules": [{"name": "test", "conditions": {"topic": "test"}, "max_severity": "none"}]}`,
+		`{"rules": [{"name": "test", "conditions": {"repository_pattern": "("}, "downgrade": 1}]}`,
+	} {
+		if err := os.WriteFile(file, []byte(invalid), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(file); err == nil {
+			t.Errorf("Expected an error for policy %s", invalid)
+		}
+	}
+}
+
+func TestProperties(t *testing.T) {
+	if properties := testPolicy().Properties(); !reflect.DeepEqual(properties, []string{"Environment"}) {
+		t.Errorf("Expected [Environment], got %v", properties)
+	}
+}
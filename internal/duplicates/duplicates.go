@@ -0,0 +1,153 @@
+package duplicates
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// Default thresholds for recommending a reusable workflow
+const (
+	DefaultMinRepositories = 3 // Distinct repositories that must share a step sequence
+	DefaultMinSteps        = 3 // Shorter sequences are too trivial to extract
+)
+
+// Config holds configuration options for duplicate detection
+// Zero thresholds use the defaults.
+type Config struct {
+	Verbose         bool
+	MinRepositories int
+	MinSteps        int
+}
+
+// Detector groups jobs by a hash of their normalized steps across an organization
+type Detector struct {
+	minRepositories int
+	minSteps        int
+	verbose         bool
+	groups          map[string]*group
+}
+
+// group collects every job sharing one normalized step sequence
+type group struct {
+	steps        []string
+	occurrences  []output.StepOccurrence
+	repositories map[string]bool
+}
+
+// NewDetector creates a duplicate detector with default thresholds
+func NewDetector() *Detector {
+	return NewDetectorWithConfig(&Config{Verbose: false})
+}
+
+// NewDetectorWithConfig creates a duplicate detector with configuration
+func NewDetectorWithConfig(config *Config) *Detector {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	minRepositories := config.MinRepositories
+	if minRepositories <= 0 {
+		minRepositories = DefaultMinRepositories
+	}
+	minSteps := config.MinSteps
+	if minSteps <= 0 {
+		minSteps = DefaultMinSteps
+	}
+
+	return &Detector{
+		minRepositories: minRepositories,
+		minSteps:        minSteps,
+		verbose:         config.Verbose,
+		groups:          make(map[string]*group),
+	}
+}
+
+// AddJobs records the normalized steps of jobs from one workflow file
+func (d *Detector) AddJobs(jobs []workflow.JobSteps) {
+	for _, job := range jobs {
+		if len(job.Steps) < d.minSteps {
+			continue
+		}
+
+		fingerprint := Fingerprint(job.Steps)
+		g, exists := d.groups[fingerprint]
+		if !exists {
+			g = &group{steps: job.Steps, repositories: make(map[string]bool)}
+			d.groups[fingerprint] = g
+		}
+
+		g.occurrences = append(g.occurrences, output.StepOccurrence{
+			Repository: job.RepoFullName,
+			FilePath:   job.FilePath,
+			Job:        job.Job,
+		})
+		g.repositories[job.RepoFullName] = true
+	}
+}
+
+// Clusters returns step sequences shared by enough repositories to recommend a reusable workflow
+// Clusters are ordered by the number of affected repositories, then by sequence length.
+func (d *Detector) Clusters() []output.DuplicateStepCluster {
+	var clusters []output.DuplicateStepCluster
+
+	for fingerprint, g := range d.groups {
+		if len(g.repositories) < d.minRepositories {
+			continue
+		}
+
+		repositories := make([]string, 0, len(g.repositories))
+		for repo := range g.repositories {
+			repositories = append(repositories, repo)
+		}
+		sort.Strings(repositories)
+
+		occurrences := append([]output.StepOccurrence(nil), g.occurrences...)
+		sort.Slice(occurrences, func(i, j int) bool {
+			a, b := occurrences[i], occurrences[j]
+			if a.Repository != b.Repository {
+				return a.Repository < b.Repository
+			}
+			if a.FilePath != b.FilePath {
+				return a.FilePath < b.FilePath
+			}
+			return a.Job < b.Job
+		})
+
+		clusters = append(clusters, output.DuplicateStepCluster{
+			Fingerprint:  fingerprint,
+			Steps:        g.steps,
+			Repositories: repositories,
+			Occurrences:  occurrences,
+		})
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		a, b := clusters[i], clusters[j]
+		if len(a.Repositories) != len(b.Repositories) {
+			return len(a.Repositories) > len(b.Repositories)
+		}
+		if len(a.Steps) != len(b.Steps) {
+			return len(a.Steps) > len(b.Steps)
+		}
+		return a.Fingerprint < b.Fingerprint
+	})
+
+	if d.verbose {
+		log.Printf("Duplicate detection: %d distinct step sequences, %d shared by at least %d repositories",
+			len(d.groups), len(clusters), d.minRepositories)
+	}
+
+	return clusters
+}
+
+// Fingerprint returns a short stable hash of a normalized step sequence
+func Fingerprint(steps []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(steps, "\n")))
+	return hex.EncodeToString(sum[:])[:12]
+}
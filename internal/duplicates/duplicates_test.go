@@ -0,0 +1,66 @@
+package duplicates
+
+import (
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+var buildSteps = []string{
+	"uses: actions/checkout",
+	"uses: actions/setup-go with: go-version=1.22",
+	"run: go build ./...",
+	"run: go test ./...",
+}
+
+func job(repo, file, name string, steps []string) workflow.JobSteps {
+	return workflow.JobSteps{RepoFullName: repo, FilePath: file, Job: name, Steps: steps}
+}
+
+func TestDetector_ClustersSharedSequences(t *testing.T) {
+	detector := NewDetector()
+	detector.AddJobs([]workflow.JobSteps{
+		job("my-org/api", ".github/workflows/ci.yml", "build", buildSteps),
+		job("my-org/api", ".github/workflows/release.yml", "build", buildSteps),
+	})
+	detector.AddJobs([]workflow.JobSteps{job("my-org/web", ".github/workflows/ci.yml", "test", buildSteps)})
+	detector.AddJobs([]workflow.JobSteps{job("my-org/cli", ".github/workflows/ci.yml", "ci", buildSteps)})
+
+	// Shared by only two repositories
+	detector.AddJobs([]workflow.JobSteps{
+		job("my-org/api", ".github/workflows/lint.yml", "lint", []string{"uses: actions/checkout", "run: make lint", "run: make vet"}),
+		job("my-org/web", ".github/workflows/lint.yml", "lint", []string{"uses: actions/checkout", "run: make lint", "run: make vet"}),
+	})
+
+	clusters := detector.Clusters()
+	if len(clusters) != 1 {
+		t.Fatalf("Expected 1 cluster, got %d", len(clusters))
+	}
+
+	cluster := clusters[0]
+	if cluster.Fingerprint != Fingerprint(buildSteps) {
+		t.Errorf("Expected fingerprint %s, got %s", Fingerprint(buildSteps), cluster.Fingerprint)
+	}
+	expectedRepos := []string{"my-org/api", "my-org/cli", "my-org/web"}
+	for i, repo := range expectedRepos {
+		if cluster.Repositories[i] != repo {
+			t.Errorf("Expected repository %d to be %s, got %s", i, repo, cluster.Repositories[i])
+		}
+	}
+	if len(cluster.Occurrences) != 4 {
+		t.Errorf("Expected 4 occurrences, got %d", len(cluster.Occurrences))
+	}
+}
+
+func TestDetector_IgnoresShortSequences(t *testing.T) {
+	detector := NewDetectorWithConfig(&Config{MinRepositories: 2})
+	short := []string{"uses: actions/checkout", "run: make"}
+	detector.AddJobs([]workflow.JobSteps{
+		job("my-org/api", "ci.yml", "build", short),
+		job("my-org/web", "ci.yml", "build", short),
+	})
+
+	if clusters := detector.Clusters(); len(clusters) != 0 {
+		t.Errorf("Expected sequences shorter than the minimum to be ignored, got %d clusters", len(clusters))
+	}
+}
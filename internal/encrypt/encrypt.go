@@ -0,0 +1,171 @@
+// Package encrypt protects scan results at rest by piping them through the age command-line tool
+// (https://age-encryption.org), so any age recipient works, including SSH keys and plugin recipients
+// backed by hardware tokens or a cloud KMS.
+package encrypt
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// ageCommand is the age binary used for encryption and decryption
+var ageCommand = "age"
+
+// Headers that identify age-encrypted data
+const (
+	binaryHeader = "age-encryption.org/v1\n"
+	armorHeader  = "-----BEGIN AGE ENCRYPTED FILE-----"
+)
+
+// IsEncrypted reports whether data is age-encrypted, in binary or ASCII-armored form
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(binaryHeader)) ||
+		bytes.HasPrefix(bytes.TrimLeft(data, " \t\r\n"), []byte(armorHeader))
+}
+
+// ParseRecipients splits a comma-separated list of recipients, dropping empty entries
+func ParseRecipients(value string) []string {
+	var recipients []string
+	for _, recipient := range strings.Split(value, ",") {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			recipients = append(recipients, recipient)
+		}
+	}
+	return recipients
+}
+
+// recipientArgs converts recipients to age arguments
+// Public keys ("age1...", "ssh-...") are passed with -r; anything else is a recipients file passed with -R.
+func recipientArgs(recipients []string) []string {
+	var args []string
+	for _, recipient := range recipients {
+		if strings.HasPrefix(recipient, "age1") || strings.HasPrefix(recipient, "ssh-") {
+			args = append(args, "-r", recipient)
+		} else {
+			args = append(args, "-R", recipient)
+		}
+	}
+	return args
+}
+
+// Writer encrypts everything written to it to the given recipients
+// Close must be called to flush the encrypted output.
+type Writer struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stderr bytes.Buffer
+}
+
+// NewWriter starts age to encrypt data written to the returned Writer into w
+func NewWriter(w io.Writer, recipients []string) (*Writer, error) {
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("at least one recipient is required")
+	}
+
+	writer := &Writer{}
+	writer.cmd = exec.Command(ageCommand, append([]string{"--encrypt"}, recipientArgs(recipients)...)...)
+	writer.cmd.Stdout = w
+	writer.cmd.Stderr = &writer.stderr
+
+	stdin, err := writer.cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open age input: %w", err)
+	}
+	writer.stdin = stdin
+
+	if err := writer.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to run %s (install it from https://age-encryption.org): %w", ageCommand, err)
+	}
+	return writer, nil
+}
+
+// Write sends plaintext to age
+func (w *Writer) Write(p []byte) (int, error) {
+	return w.stdin.Write(p)
+}
+
+// Close finishes encryption and waits for age to exit
+func (w *Writer) Close() error {
+	if err := w.stdin.Close(); err != nil {
+		return fmt.Errorf("failed to close age input: %w", err)
+	}
+	if err := w.cmd.Wait(); err != nil {
+		return commandError("encryption", err, w.stderr.String())
+	}
+	return nil
+}
+
+// Decrypt decrypts age-encrypted data with the identities in identityFile
+func Decrypt(ciphertext []byte, identityFile string) ([]byte, error) {
+	if identityFile == "" {
+		return nil, fmt.Errorf("an identity file is required to decrypt")
+	}
+
+	cmd := exec.Command(ageCommand, "--decrypt", "-i", identityFile)
+	cmd.Stdin = bytes.NewReader(ciphertext)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	plaintext, err := cmd.Output()
+	if err != nil {
+		return nil, commandError("decryption", err, stderr.String())
+	}
+	return plaintext, nil
+}
+
+// Reader decrypts age-encrypted data as it is read
+// Close must be called to wait for age and report decryption failures.
+type Reader struct {
+	cmd    *exec.Cmd
+	stdout io.ReadCloser
+	stderr bytes.Buffer
+}
+
+// NewReader starts age to decrypt r with the identities in identityFile
+func NewReader(r io.Reader, identityFile string) (*Reader, error) {
+	if identityFile == "" {
+		return nil, fmt.Errorf("an identity file is required to decrypt")
+	}
+
+	reader := &Reader{}
+	reader.cmd = exec.Command(ageCommand, "--decrypt", "-i", identityFile)
+	reader.cmd.Stdin = r
+	reader.cmd.Stderr = &reader.stderr
+
+	stdout, err := reader.cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open age output: %w", err)
+	}
+	reader.stdout = stdout
+
+	if err := reader.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to run %s (install it from https://age-encryption.org): %w", ageCommand, err)
+	}
+	return reader, nil
+}
+
+// Read returns decrypted data
+func (r *Reader) Read(p []byte) (int, error) {
+	return r.stdout.Read(p)
+}
+
+// Close stops reading and waits for age to exit, returning its error if decryption failed
+func (r *Reader) Close() error {
+	// Drain unread output so age is not blocked writing when the caller stops early
+	io.Copy(io.Discard, r.stdout)
+	if err := r.cmd.Wait(); err != nil {
+		return commandError("decryption", err, r.stderr.String())
+	}
+	return nil
+}
+
+// commandError wraps an age failure with its error output
+func commandError(operation string, err error, stderr string) error {
+	if message := strings.TrimSpace(stderr); message != "" {
+		return fmt.Errorf("age %s failed: %w: %s", operation, err, message)
+	}
+	return fmt.Errorf("age %s failed: %w", operation, err)
+}
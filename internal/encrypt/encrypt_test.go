@@ -0,0 +1,156 @@
+package encrypt
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeAge installs a stand-in for age that records its arguments and prefixes or strips the age header
+func fakeAge(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell script")
+	}
+
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "args")
+	script := `#!/bin/sh
+echo "$@" > "` + argsFile + `"
+case "$1" in
+--encrypt) printf 'age-encryption.org/v1\n'; cat ;;
+--decrypt) test -f "$3" || { echo "no identity" >&2; exit 1; }; tail -n +2 ;;
+esac
+`
+	path := filepath.Join(dir, "age")
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatalf("Failed to write fake age: %v", err)
+	}
+
+	previous := ageCommand
+	ageCommand = path
+	t.Cleanup(func() { ageCommand = previous })
+	return argsFile
+}
+
+func TestIsEncrypted(t *testing.T) {
+	tests := map[string]bool{
+		"age-encryption.org/v1\n-> X25519 abc\n":             true,
+		"\n-----BEGIN AGE ENCRYPTED FILE-----\nYWdlLWVuY3J5": true,
+		`{"owner": "my-org"}`:                                false,
+		"":                                                   false,
+	}
+
+	for data, expected := range tests {
+		if encrypted := IsEncrypted([]byte(data)); encrypted != expected {
+			t.Errorf("IsEncrypted(%q) = %v, expected %v", data, encrypted, expected)
+		}
+	}
+}
+
+func TestParseRecipients(t *testing.T) {
+	recipients := ParseRecipients(" age1abc, ,ssh-ed25519 AAAA,recipients.txt")
+	expected := []string{"age1abc", "ssh-ed25519 AAAA", "recipients.txt"}
+	if !reflect.DeepEqual(recipients, expected) {
+		t.Errorf("Expected %v, got %v", expected, recipients)
+	}
+
+	args := recipientArgs(recipients)
+	expectedArgs := []string{"-r", "age1abc", "-r", "ssh-ed25519 AAAA", "-R", "recipients.txt"}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("Expected %v, got %v", expectedArgs, args)
+	}
+}
+
+func TestWriterAndDecrypt(t *testing.T) {
+	argsFile := fakeAge(t)
+
+	var encrypted bytes.Buffer
+	writer, err := NewWriter(&encrypted, []string{"age1abc"})
+	if err != nil {
+		t.Fatalf("NewWriter() returned error: %v", err)
+	}
+	if _, err := writer.Write([]byte(`{"owner": "my-org"}`)); err != nil {
+		t.Fatalf("Write() returned error: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() returned error: %v", err)
+	}
+
+	if !IsEncrypted(encrypted.Bytes()) {
+		t.Fatalf("Expected encrypted output, got %q", encrypted.String())
+	}
+	if args, _ := os.ReadFile(argsFile); strings.TrimSpace(string(args)) != "--encrypt -r age1abc" {
+		t.Errorf("Unexpected age arguments: %q", args)
+	}
+
+	identity := filepath.Join(t.TempDir(), "key.txt")
+	if err := os.WriteFile(identity, []byte("AGE-SECRET-KEY-1"), 0o600); err != nil {
+		t.Fatalf("Failed to write identity: %v", err)
+	}
+	plaintext, err := Decrypt(encrypted.Bytes(), identity)
+	if err != nil {
+		t.Fatalf("Decrypt() returned error: %v", err)
+	}
+	if string(plaintext) != `{"owner": "my-org"}` {
+		t.Errorf("Expected the original plaintext, got %q", plaintext)
+	}
+}
+
+func TestDecrypt_Errors(t *testing.T) {
+	fakeAge(t)
+
+	if _, err := Decrypt([]byte(binaryHeader), ""); err == nil {
+		t.Errorf("Expected an error without an identity file")
+	}
+
+	_, err := Decrypt([]byte(binaryHeader), filepath.Join(t.TempDir(), "missing.txt"))
+	if err == nil || !strings.Contains(err.Error(), "no identity") {
+		t.Errorf("Expected age's error output in the error, got %v", err)
+	}
+}
+
+func TestNewWriter_NoRecipients(t *testing.T) {
+	if _, err := NewWriter(&bytes.Buffer{}, nil); err == nil {
+		t.Errorf("Expected an error without recipients")
+	}
+}
+
+func TestReader(t *testing.T) {
+	fakeAge(t)
+
+	identity := filepath.Join(t.TempDir(), "key.txt")
+	if err := os.WriteFile(identity, []byte("AGE-SECRET-KEY-1"), 0o600); err != nil {
+		t.Fatalf("Failed to write identity: %v", err)
+	}
+
+	reader, err := NewReader(strings.NewReader(binaryHeader+`{"owner": "my-org"}`), identity)
+	if err != nil {
+		t.Fatalf("NewReader() returned error: %v", err)
+	}
+	plaintext, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("ReadAll returned error: %v", err)
+	}
+	if err := reader.Close(); err != nil {
+		t.Fatalf("Close() returned error: %v", err)
+	}
+	if string(plaintext) != `{"owner": "my-org"}` {
+		t.Errorf("Expected the original plaintext, got %q", plaintext)
+	}
+
+	// Decryption failures are reported by Close
+	reader, err = NewReader(strings.NewReader(binaryHeader), filepath.Join(t.TempDir(), "missing.txt"))
+	if err != nil {
+		t.Fatalf("NewReader() returned error: %v", err)
+	}
+	io.ReadAll(reader)
+	if err := reader.Close(); err == nil || !strings.Contains(err.Error(), "no identity") {
+		t.Errorf("Expected age's error output from Close, got %v", err)
+	}
+}
@@ -0,0 +1,362 @@
+package explain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/actions"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/patcher"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/patchtest"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/pr"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// diffContext is the number of unchanged lines shown around each change of the pull request diff
+const diffContext = 2
+
+// Query selects the finding to explain: the uses of one action in one workflow file of a repository
+type Query struct {
+	Repository string // Full name or name of the scanned repository
+	FilePath   string // Workflow file using the action
+	Action     string // Action as written in uses:, with or without a version (e.g., "actions/checkout@v3")
+}
+
+// Resolver resolves refs of action repositories to commit SHAs, as the scan's version resolver does
+type Resolver interface {
+	ResolveRefWithCache(owner, repo, ref string) (string, error)
+	GetTagsWithCache(owner, repo string) (map[string]string, error)
+}
+
+// ContentFetcher reads a file of a repository at a ref
+type ContentFetcher interface {
+	GetFileContent(owner, repo, filePath, ref string) (string, error)
+}
+
+// Explainer gathers what is known about a finding
+// Rules and Patcher are required. Without a Resolver refs are not resolved, and without a Content
+// fetcher the with: blocks and the pull request change are unknown; the explanation notes what is missing.
+type Explainer struct {
+	Rules    *actions.Manager
+	Patcher  *patcher.WorkflowPatcher
+	Resolver Resolver
+	Content  ContentFetcher
+}
+
+// Explanation is everything known about the uses of one action in one workflow file
+type Explanation struct {
+	Repository      string
+	FilePath        string
+	Action          string                     // Action repository, with the path of a reusable workflow or nested action
+	Version         string                     // Version from the query; empty for every version
+	References      []workflow.ActionReference // Uses of the action in the file
+	Findings        []output.ActionIssue
+	Suppressed      []output.SuppressedIssue
+	Rule            *actions.Rule // Rule matching the action, or nil
+	Resolutions     []Resolution
+	Transformations []Transformation
+	Change          *Change  // Pull request change fixing the findings, or nil
+	Notes           []string // Why parts of the explanation are missing
+}
+
+// Resolution is the commit a ref of an action repository points at
+type Resolution struct {
+	Repository string
+	Ref        string
+	SHA        string
+	Aliases    []string // Other tags pointing at the same commit, sorted
+	Err        error
+}
+
+// Transformation is a schema transformation applied by a finding's upgrade
+type Transformation struct {
+	FromRepository string
+	FromVersion    string
+	ToRepository   string
+	ToVersion      string
+	Rule           *patcher.VersionPatch
+	Preview        *patcher.Patch // The transformation of the step's with: block, or nil when the block is unknown
+}
+
+// Change is the edit create-pr would make to the workflow file to fix the findings
+type Change struct {
+	Ref     string   // Commit or branch the workflow was read at
+	Changes []string // Descriptions of the schema and step changes
+	Diff    string   // Changed lines with their context: "- " removed, "+ " added, "  " unchanged
+	Err     error    // Why the file could not be patched
+}
+
+// Explain explains the finding a query selects from scan results
+func (e *Explainer) Explain(result *output.ScanResult, query Query) (*Explanation, error) {
+	repo := findRepository(result, query.Repository)
+	if repo == nil {
+		return nil, fmt.Errorf("repository %s is not in the scan results", query.Repository)
+	}
+
+	name, version, _ := strings.Cut(query.Action, "@")
+	actionRepo, actionPath := splitAction(name)
+	explanation := &Explanation{
+		Repository: repo.FullName,
+		FilePath:   query.FilePath,
+		Action:     name,
+		Version:    version,
+	}
+
+	for _, action := range repo.Actions {
+		if action.FilePath == query.FilePath && action.Repository == actionRepo && action.WorkflowPath == actionPath &&
+			(version == "" || action.Version == version) {
+			explanation.References = append(explanation.References, action)
+		}
+	}
+	matches := func(issue output.ActionIssue) bool {
+		// Only migrations record the path of nested actions and reusable workflows
+		return issue.FilePath == query.FilePath && issue.Repository == actionRepo &&
+			(issue.WorkflowPath == "" || issue.WorkflowPath == actionPath) &&
+			(version == "" || issue.CurrentVersion == version)
+	}
+	for _, issue := range repo.Issues {
+		if matches(issue) {
+			explanation.Findings = append(explanation.Findings, issue)
+		}
+	}
+	for _, suppressed := range repo.SuppressedIssues {
+		if matches(suppressed.ActionIssue) {
+			explanation.Suppressed = append(explanation.Suppressed, suppressed)
+		}
+	}
+	if len(explanation.References) == 0 && len(explanation.Findings) == 0 && len(explanation.Suppressed) == 0 {
+		return nil, fmt.Errorf("%s does not use %s in %s", repo.FullName, query.Action, query.FilePath)
+	}
+
+	if len(explanation.References) > 0 {
+		explanation.Rule = e.Rules.RuleFor(*repo, explanation.References[0])
+	}
+
+	e.resolve(explanation)
+
+	content, ref := e.fetchWorkflow(explanation, *repo)
+	withBlocks := withBlocksOf(content, query.FilePath, repo.FullName)
+	e.transform(explanation, withBlocks)
+	if content != "" {
+		explanation.Change = e.change(*repo, explanation.Findings, content, ref)
+		if explanation.Change == nil {
+			explanation.Notes = append(explanation.Notes, "No pull request change: none of the findings has a suggested fix")
+		}
+	}
+
+	return explanation, nil
+}
+
+// findRepository returns the repository of scan results with a full name or name, or nil
+func findRepository(result *output.ScanResult, name string) *output.RepositoryResult {
+	for i := range result.Repositories {
+		if result.Repositories[i].FullName == name {
+			return &result.Repositories[i]
+		}
+	}
+	for i := range result.Repositories {
+		if result.Repositories[i].Name == name {
+			return &result.Repositories[i]
+		}
+	}
+	return nil
+}
+
+// splitAction splits an action name into its repository and the path within it
+func splitAction(name string) (string, string) {
+	parts := strings.SplitN(name, "/", 3)
+	if len(parts) < 3 {
+		return name, ""
+	}
+	return parts[0] + "/" + parts[1], parts[2]
+}
+
+// resolve resolves the used and suggested versions of the action to commits
+func (e *Explainer) resolve(explanation *Explanation) {
+	if e.Resolver == nil {
+		explanation.Notes = append(explanation.Notes, "Refs not resolved: no GitHub access")
+		return
+	}
+
+	seen := make(map[string]bool)
+	add := func(repository, ref string) {
+		key := repository + "@" + ref
+		if repository == "" || ref == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		explanation.Resolutions = append(explanation.Resolutions, e.resolveRef(repository, ref))
+	}
+
+	actionRepo, _ := splitAction(explanation.Action)
+	for _, action := range explanation.References {
+		add(action.Repository, action.Version)
+	}
+	for _, issue := range explanation.Findings {
+		add(issue.Repository, issue.CurrentVersion)
+		add(issue.Repository, issue.SuggestedVersion)
+		if issue.MigrationTarget != "" {
+			target, targetVersion, _ := strings.Cut(issue.MigrationTarget, "@")
+			targetRepo, _ := splitAction(target)
+			add(targetRepo, targetVersion)
+		}
+	}
+	if len(seen) == 0 && explanation.Version != "" {
+		add(actionRepo, explanation.Version)
+	}
+}
+
+// resolveRef resolves a ref of a repository and finds the other tags pointing at the same commit
+func (e *Explainer) resolveRef(repository, ref string) Resolution {
+	resolution := Resolution{Repository: repository, Ref: ref}
+	owner, name, _ := strings.Cut(repository, "/")
+
+	sha, err := e.Resolver.ResolveRefWithCache(owner, name, ref)
+	if err != nil {
+		resolution.Err = err
+		return resolution
+	}
+	resolution.SHA = sha
+
+	tags, err := e.Resolver.GetTagsWithCache(owner, name)
+	if err != nil {
+		resolution.Err = fmt.Errorf("failed to list tags: %w", err)
+		return resolution
+	}
+	for tag, tagSHA := range tags {
+		if tag != ref && strings.EqualFold(tagSHA, sha) {
+			resolution.Aliases = append(resolution.Aliases, tag)
+		}
+	}
+	sort.Strings(resolution.Aliases)
+	return resolution
+}
+
+// fetchWorkflow reads the workflow file at the commit it was scanned at, or the default branch
+func (e *Explainer) fetchWorkflow(explanation *Explanation, repo output.RepositoryResult) (string, string) {
+	if e.Content == nil {
+		explanation.Notes = append(explanation.Notes, "Workflow not read: no GitHub access, so with: blocks and the pull request change are unknown")
+		return "", ""
+	}
+
+	ref := repo.DefaultBranch
+	for _, file := range repo.WorkflowFiles {
+		if file.Path == explanation.FilePath && file.CommitSHA != "" {
+			ref = file.CommitSHA
+		}
+	}
+	owner, name, _ := strings.Cut(repo.FullName, "/")
+	content, err := e.Content.GetFileContent(owner, name, explanation.FilePath, ref)
+	if err != nil {
+		explanation.Notes = append(explanation.Notes, fmt.Sprintf("Workflow not read: %v", err))
+		return "", ""
+	}
+	return content, ref
+}
+
+// withBlocksOf returns the with: blocks of a workflow's action references, keyed by withKey
+func withBlocksOf(content, filePath, repoFullName string) map[string]interface{} {
+	if content == "" {
+		return nil
+	}
+	references, err := workflow.ParseWorkflow(content, filePath, repoFullName)
+	if err != nil {
+		return nil
+	}
+	blocks := make(map[string]interface{})
+	for _, reference := range references {
+		key := withKey(reference.Context, reference.Repository, reference.Version)
+		if _, ok := blocks[key]; !ok && reference.With != nil {
+			blocks[key] = reference.With
+		}
+	}
+	return blocks
+}
+
+// withKey identifies the with: block of a use of an action
+func withKey(context, repository, version string) string {
+	return context + "|" + repository + "@" + version
+}
+
+// transform previews the schema transformations the findings' upgrades apply
+func (e *Explainer) transform(explanation *Explanation, withBlocks map[string]interface{}) {
+	for _, issue := range explanation.Findings {
+		toRepo, toVersion := issue.Repository, issue.SuggestedVersion
+		if issue.MigrationTarget != "" {
+			target, targetVersion, _ := strings.Cut(issue.MigrationTarget, "@")
+			toRepo, _ = splitAction(target)
+			toVersion = targetVersion
+		}
+		if toVersion == "" {
+			continue
+		}
+
+		info, ok := e.Patcher.GetPatchInfo(issue.Repository, issue.CurrentVersion, toVersion)
+		if !ok {
+			continue
+		}
+		transformation := Transformation{
+			FromRepository: issue.Repository,
+			FromVersion:    issue.CurrentVersion,
+			ToRepository:   toRepo,
+			ToVersion:      toVersion,
+			Rule:           info,
+			Preview:        issue.PatchPreview,
+		}
+		if with, ok := withBlocks[withKey(issue.Context, issue.Repository, issue.CurrentVersion)]; ok {
+			if preview, err := e.Patcher.PreviewChangesWithLocation(issue.Repository, issue.CurrentVersion, toVersion, toRepo, with); err == nil {
+				transformation.Preview = preview
+			}
+		}
+		explanation.Transformations = append(explanation.Transformations, transformation)
+	}
+}
+
+// change patches the workflow as create-pr would for the findings, or returns nil when none has a fix
+func (e *Explainer) change(repo output.RepositoryResult, findings []output.ActionIssue, content, ref string) *Change {
+	repo.Issues = findings
+	plans := pr.PlanUpdates([]output.RepositoryResult{repo})
+	if len(plans) == 0 {
+		return nil
+	}
+
+	change := &Change{Ref: ref}
+	patched, changes, err := pr.PatchWorkflowContent(e.Patcher, content, plans[0].Updates)
+	if err != nil {
+		change.Err = err
+		return change
+	}
+	change.Changes = changes
+	change.Diff = hunks(patchtest.Diff(content, patched), diffContext)
+	return change
+}
+
+// hunks trims a whole-file diff to its changed lines and the given number of unchanged lines around them,
+// separating hunks with "  ..."
+func hunks(diff string, context int) string {
+	lines := strings.Split(strings.TrimSuffix(diff, "\n"), "\n")
+	keep := make([]bool, len(lines))
+	for i, line := range lines {
+		if strings.HasPrefix(line, "  ") {
+			continue
+		}
+		for j := max(0, i-context); j <= min(len(lines)-1, i+context); j++ {
+			keep[j] = true
+		}
+	}
+
+	var b strings.Builder
+	last := -1
+	for i, line := range lines {
+		if !keep[i] {
+			continue
+		}
+		if last >= 0 && i > last+1 {
+			b.WriteString("  ...\n")
+		}
+		b.WriteString(line + "\n")
+		last = i
+	}
+	return b.String()
+}
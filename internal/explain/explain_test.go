@@ -0,0 +1,200 @@
+package explain
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/actions"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/patcher"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+const explainWorkflow = `name: Deploy
+on: push
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - name: Deploy
+        uses: my-org/deploy@v1
+        with:
+          env: production
+          dry_run: false
+      - run: echo done
+`
+
+type fakeResolver struct {
+	tags map[string]map[string]string // repository -> tag -> sha
+}
+
+func (f fakeResolver) ResolveRefWithCache(owner, repo, ref string) (string, error) {
+	if sha, ok := f.tags[owner+"/"+repo][ref]; ok {
+		return sha, nil
+	}
+	return "", fmt.Errorf("ref %s not found", ref)
+}
+
+func (f fakeResolver) GetTagsWithCache(owner, repo string) (map[string]string, error) {
+	return f.tags[owner+"/"+repo], nil
+}
+
+type fakeContent map[string]string
+
+func (f fakeContent) GetFileContent(owner, repo, filePath, ref string) (string, error) {
+	if content, ok := f[owner+"/"+repo+"/"+filePath+"@"+ref]; ok {
+		return content, nil
+	}
+	return "", fmt.Errorf("%s not found at %s", filePath, ref)
+}
+
+func explainScanResult() *output.ScanResult {
+	deploy := workflow.ActionReference{
+		Repository: "my-org/deploy",
+		Version:    "v1",
+		Context:    "job:deploy/step:Deploy",
+		FilePath:   ".github/workflows/deploy.yml",
+	}
+	return &output.ScanResult{Repositories: []output.RepositoryResult{{
+		Name:          "app",
+		FullName:      "my-org/app",
+		DefaultBranch: "main",
+		WorkflowFiles: []output.WorkflowFileResult{{Path: ".github/workflows/deploy.yml", CommitSHA: "abc123"}},
+		Actions:       []workflow.ActionReference{deploy},
+		Issues: []output.ActionIssue{{
+			Repository:       "my-org/deploy",
+			CurrentVersion:   "v1",
+			SuggestedVersion: "v2",
+			IssueType:        "outdated",
+			Severity:         "medium",
+			Description:      "Action my-org/deploy is using version v1, latest is v2",
+			Context:          "job:deploy/step:Deploy",
+			FilePath:         ".github/workflows/deploy.yml",
+		}},
+	}}}
+}
+
+func newExplainer(online bool) *Explainer {
+	wp := patcher.NewWorkflowPatcher()
+	wp.AddPatchRule(patcher.ActionPatchRule{
+		Repository: "my-org/deploy",
+		VersionPatches: []patcher.VersionPatch{{
+			FromVersion: "v1",
+			ToVersion:   "v2",
+			Description: "v2 renames env to environment",
+			Patches: []patcher.FieldPatch{
+				{Operation: patcher.OperationRename, Field: "env", NewField: "environment", Reason: "Input renamed in v2"},
+			},
+		}},
+	})
+	explainer := &Explainer{
+		Rules:   actions.NewManagerWithResolverConfigAndRules(nil, nil, []actions.Rule{{Repository: "my-org/deploy", LatestVersion: "v2", Owners: []string{"my-org/platform"}}}),
+		Patcher: wp,
+	}
+	if online {
+		explainer.Resolver = fakeResolver{tags: map[string]map[string]string{
+			"my-org/deploy": {"v1": "1111111", "v1.4.0": "1111111", "v2": "2222222"},
+		}}
+		explainer.Content = fakeContent{"my-org/app/.github/workflows/deploy.yml@abc123": explainWorkflow}
+	}
+	return explainer
+}
+
+func TestExplain(t *testing.T) {
+	explanation, err := newExplainer(true).Explain(explainScanResult(), Query{
+		Repository: "app",
+		FilePath:   ".github/workflows/deploy.yml",
+		Action:     "my-org/deploy@v1",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if explanation.Repository != "my-org/app" || len(explanation.References) != 1 || len(explanation.Findings) != 1 {
+		t.Fatalf("Expected one use and one finding in my-org/app, got %+v", explanation)
+	}
+	if explanation.Rule == nil || explanation.Rule.LatestVersion != "v2" {
+		t.Errorf("Expected the my-org/deploy rule, got %+v", explanation.Rule)
+	}
+	if len(explanation.Resolutions) != 2 || explanation.Resolutions[0].SHA != "1111111" ||
+		strings.Join(explanation.Resolutions[0].Aliases, ",") != "v1.4.0" {
+		t.Errorf("Expected v1 and v2 resolved with v1.4.0 as an alias of v1, got %+v", explanation.Resolutions)
+	}
+	if len(explanation.Transformations) != 1 || explanation.Transformations[0].Preview == nil {
+		t.Fatalf("Expected a transformation with a preview, got %+v", explanation.Transformations)
+	}
+	if updated, ok := explanation.Transformations[0].Preview.UpdatedWith.(map[string]interface{}); !ok || updated["environment"] != "production" {
+		t.Errorf("Expected env renamed to environment in the preview, got %v", explanation.Transformations[0].Preview.UpdatedWith)
+	}
+
+	change := explanation.Change
+	if change == nil || change.Err != nil || change.Ref != "abc123" {
+		t.Fatalf("Expected a pull request change read at abc123, got %+v", change)
+	}
+	if !strings.Contains(change.Diff, "-         uses: my-org/deploy@v1\n") || !strings.Contains(change.Diff, "+         uses: my-org/deploy@v2\n") {
+		t.Errorf("Expected the diff to update the uses line, got:\n%s", change.Diff)
+	}
+	if strings.Contains(change.Diff, "runs-on") {
+		t.Errorf("Expected the diff to leave out distant unchanged lines, got:\n%s", change.Diff)
+	}
+
+	var out strings.Builder
+	if err := Write(&out, explanation); err != nil {
+		t.Fatalf("Expected no error writing, got %v", err)
+	}
+	for _, expected := range []string{
+		"[AM001 outdated, medium]",
+		"Owners: my-org/platform",
+		"my-org/deploy@v1 -> 1111111 (also v1.4.0)",
+		"rename env -> environment: Input renamed in v2",
+		"environment: production",
+		"Pull request change (.github/workflows/deploy.yml at abc123):",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out.String())
+		}
+	}
+}
+
+func TestExplain_Offline(t *testing.T) {
+	explanation, err := newExplainer(false).Explain(explainScanResult(), Query{
+		Repository: "my-org/app",
+		FilePath:   ".github/workflows/deploy.yml",
+		Action:     "my-org/deploy",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if explanation.Change != nil || len(explanation.Resolutions) != 0 {
+		t.Errorf("Expected no resolution or pull request change offline, got %+v", explanation)
+	}
+	if len(explanation.Transformations) != 1 || explanation.Transformations[0].Preview != nil {
+		t.Errorf("Expected a transformation without a preview, got %+v", explanation.Transformations)
+	}
+	if len(explanation.Notes) != 2 {
+		t.Errorf("Expected notes on the skipped resolution and workflow, got %v", explanation.Notes)
+	}
+}
+
+func TestExplain_NotFound(t *testing.T) {
+	explainer := newExplainer(false)
+	if _, err := explainer.Explain(explainScanResult(), Query{Repository: "my-org/other", FilePath: ".github/workflows/deploy.yml", Action: "my-org/deploy"}); err == nil {
+		t.Error("Expected an error for a repository missing from the scan")
+	}
+	if _, err := explainer.Explain(explainScanResult(), Query{Repository: "my-org/app", FilePath: ".github/workflows/deploy.yml", Action: "my-org/deploy@v9"}); err == nil {
+		t.Error("Expected an error for a version the workflow does not use")
+	}
+}
+
+func TestHunks(t *testing.T) {
+	diff := "  a\n  b\n  c\n  d\n- e\n+ E\n  f\n  g\n  h\n  i\n  j\n  k\n- l\n"
+	expected := "  c\n  d\n- e\n+ E\n  f\n  g\n  ...\n  j\n  k\n- l\n"
+	if got := hunks(diff, 2); got != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+	}
+	if got := hunks("  a\n  b\n", 2); got != "" {
+		t.Errorf("Expected no hunks for an unchanged file, got %q", got)
+	}
+}
@@ -0,0 +1,219 @@
+package explain
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// Write prints an explanation as plain text, one section at a time
+func Write(w io.Writer, explanation *Explanation) error {
+	var b strings.Builder
+
+	action := explanation.Action
+	if explanation.Version != "" {
+		action += "@" + explanation.Version
+	}
+	fmt.Fprintf(&b, "%s in %s of %s\n", action, explanation.FilePath, explanation.Repository)
+
+	fmt.Fprintf(&b, "\nUses (%d):\n", len(explanation.References))
+	for _, reference := range explanation.References {
+		uses := reference.Repository
+		if reference.WorkflowPath != "" {
+			uses += "/" + reference.WorkflowPath
+		}
+		fmt.Fprintf(&b, "  %s@%s", uses, reference.Version)
+		if reference.PinComment != "" {
+			fmt.Fprintf(&b, " # %s", reference.PinComment)
+		}
+		fmt.Fprintf(&b, " (%s)\n", reference.Context)
+	}
+
+	fmt.Fprintf(&b, "\nFindings (%d):\n", len(explanation.Findings))
+	if len(explanation.Findings) == 0 {
+		b.WriteString("  None\n")
+	}
+	for _, issue := range explanation.Findings {
+		writeFinding(&b, issue)
+	}
+	for _, suppressed := range explanation.Suppressed {
+		writeFinding(&b, suppressed.ActionIssue)
+		fmt.Fprintf(&b, "    Suppressed: %s", suppressed.Reason)
+		if suppressed.Until != "" {
+			fmt.Fprintf(&b, " (until %s)", suppressed.Until)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\nRule:\n")
+	writeRule(&b, explanation)
+
+	if len(explanation.Resolutions) > 0 {
+		b.WriteString("\nResolution:\n")
+		for _, resolution := range explanation.Resolutions {
+			fmt.Fprintf(&b, "  %s@%s", resolution.Repository, resolution.Ref)
+			switch {
+			case resolution.Err != nil:
+				fmt.Fprintf(&b, ": %v\n", resolution.Err)
+			case len(resolution.Aliases) > 0:
+				fmt.Fprintf(&b, " -> %s (also %s)\n", resolution.SHA, strings.Join(resolution.Aliases, ", "))
+			default:
+				fmt.Fprintf(&b, " -> %s\n", resolution.SHA)
+			}
+		}
+	}
+
+	b.WriteString("\nTransformations:\n")
+	if len(explanation.Transformations) == 0 {
+		b.WriteString("  None: the upgrade leaves the step's inputs unchanged\n")
+	}
+	for _, transformation := range explanation.Transformations {
+		writeTransformation(&b, transformation)
+	}
+
+	if change := explanation.Change; change != nil {
+		fmt.Fprintf(&b, "\nPull request change (%s at %s):\n", explanation.FilePath, change.Ref)
+		switch {
+		case change.Err != nil:
+			fmt.Fprintf(&b, "  Not updated: %v\n", change.Err)
+		case change.Diff == "":
+			b.WriteString("  None: the file is already up to date\n")
+		default:
+			for _, description := range change.Changes {
+				fmt.Fprintf(&b, "  %s\n", description)
+			}
+			for _, line := range strings.Split(strings.TrimSuffix(change.Diff, "\n"), "\n") {
+				fmt.Fprintf(&b, "  %s\n", line)
+			}
+		}
+	}
+
+	if len(explanation.Notes) > 0 {
+		b.WriteString("\nNotes:\n")
+		for _, note := range explanation.Notes {
+			fmt.Fprintf(&b, "  %s\n", note)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeFinding prints a finding with its rule id, versions, and documentation
+func writeFinding(b *strings.Builder, issue output.ActionIssue) {
+	fmt.Fprintf(b, "  [%s %s, %s] %s\n", output.RuleID(issue.IssueType), issue.IssueType, issue.Severity, issue.Description)
+	fmt.Fprintf(b, "    Context: %s\n", issue.Context)
+	switch {
+	case issue.MigrationTarget != "":
+		fmt.Fprintf(b, "    Version: %s, migrate to %s\n", issue.CurrentVersion, issue.MigrationTarget)
+	case issue.SuggestedVersion != "":
+		fmt.Fprintf(b, "    Version: %s, suggested %s\n", issue.CurrentVersion, issue.SuggestedVersion)
+	case issue.CurrentVersion != "":
+		fmt.Fprintf(b, "    Version: %s\n", issue.CurrentVersion)
+	}
+	if issue.Existing {
+		b.WriteString("    Existing: present in the baseline\n")
+	}
+	if len(issue.Metadata) > 0 {
+		keys := make([]string, 0, len(issue.Metadata))
+		for key := range issue.Metadata {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(b, "    %s: %s\n", key, issue.Metadata[key])
+		}
+	}
+	fmt.Fprintf(b, "    Docs: %s\n", issue.DocsURL())
+}
+
+// writeRule prints the rule matching the action
+func writeRule(b *strings.Builder, explanation *Explanation) {
+	rule := explanation.Rule
+	if rule == nil {
+		b.WriteString("  None: no rule in the rules file matches this action\n")
+		return
+	}
+
+	fmt.Fprintf(b, "  Repository: %s\n", rule.Repository)
+	if rule.WorkflowPath != "" {
+		fmt.Fprintf(b, "  Workflow path: %s\n", rule.WorkflowPath)
+	}
+	if rule.Conditions != nil {
+		fmt.Fprintf(b, "  Conditions: %s\n", rule.Conditions.String())
+	}
+	if rule.LatestVersion != "" {
+		fmt.Fprintf(b, "  Latest version: %s\n", rule.LatestVersion)
+	}
+	if rule.MinimumVersion != "" {
+		fmt.Fprintf(b, "  Minimum version: %s\n", rule.MinimumVersion)
+	}
+	if len(rule.DeprecatedVersions) > 0 {
+		fmt.Fprintf(b, "  Deprecated versions: %s\n", strings.Join(rule.DeprecatedVersions, ", "))
+	}
+	if rule.MigrateToRepository != "" || rule.MigrateToPath != "" {
+		target := rule.MigrateToRepository
+		if rule.MigrateToPath != "" {
+			target = strings.TrimPrefix(target+"/"+rule.MigrateToPath, "/")
+		}
+		fmt.Fprintf(b, "  Migrate to: %s@%s\n", target, rule.MigrateToVersion)
+	}
+	if rule.Ban != nil {
+		b.WriteString("  Banned\n")
+	}
+	if rule.Recommendation != "" {
+		fmt.Fprintf(b, "  Recommendation: %s\n", rule.Recommendation)
+	}
+	if len(rule.Owners) > 0 {
+		fmt.Fprintf(b, "  Owners: %s\n", strings.Join(rule.Owners, ", "))
+	}
+}
+
+// writeTransformation prints the field patches of a transformation and its before and after with: blocks
+func writeTransformation(b *strings.Builder, transformation Transformation) {
+	to := transformation.ToVersion
+	if transformation.ToRepository != transformation.FromRepository {
+		to = transformation.ToRepository + "@" + to
+	}
+	fmt.Fprintf(b, "  %s %s -> %s: %s\n", transformation.FromRepository, transformation.FromVersion, to, transformation.Rule.Description)
+	for _, patch := range transformation.Rule.Patches {
+		field := patch.Field
+		if patch.NewField != "" {
+			field += " -> " + patch.NewField
+		}
+		fmt.Fprintf(b, "    %s %s: %s\n", patch.Operation, field, patch.Reason)
+	}
+
+	preview := transformation.Preview
+	if preview == nil {
+		b.WriteString("    Preview unavailable: the step's with: block is unknown\n")
+		return
+	}
+	writeWith(b, "Before", preview.OriginalWith)
+	writeWith(b, "After", preview.UpdatedWith)
+	for _, warning := range preview.Warnings {
+		fmt.Fprintf(b, "    Warning: %s\n", warning)
+	}
+}
+
+// writeWith prints a with: block as indented YAML
+func writeWith(b *strings.Builder, label string, with interface{}) {
+	fmt.Fprintf(b, "    %s:\n", label)
+	if with == nil {
+		b.WriteString("      (no with: block)\n")
+		return
+	}
+	data, err := yaml.Marshal(with)
+	if err != nil {
+		fmt.Fprintf(b, "      (unprintable: %v)\n", err)
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		fmt.Fprintf(b, "      %s\n", line)
+	}
+}
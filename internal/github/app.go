@@ -0,0 +1,261 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v65/github"
+)
+
+// appJWTLifetime is how long an app JWT is valid; GitHub accepts at most 10 minutes
+const appJWTLifetime = 9 * time.Minute
+
+// appTokenRefresh is how long before expiry an installation token is replaced, so requests in flight
+// never carry an expired token
+const appTokenRefresh = 5 * time.Minute
+
+// AppCredentials authenticate as a GitHub App instead of with a token
+type AppCredentials struct {
+	AppID      int64
+	PrivateKey *rsa.PrivateKey
+}
+
+// LoadAppCredentials parses an app ID and reads the app's PEM private key file
+func LoadAppCredentials(appID, keyFile string) (*AppCredentials, error) {
+	id, err := strconv.ParseInt(strings.TrimSpace(appID), 10, 64)
+	if err != nil || id <= 0 {
+		return nil, fmt.Errorf("app ID must be a positive number, got %q", appID)
+	}
+
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read app private key: %w", err)
+	}
+	key, err := ParseAppPrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+	return &AppCredentials{AppID: id, PrivateKey: key}, nil
+}
+
+// ParseAppPrivateKey parses a PEM RSA private key, as downloaded from the app's settings (PKCS #1) or converted to PKCS #8
+func ParseAppPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("app private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse app private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("app private key must be an RSA key")
+	}
+	return key, nil
+}
+
+// signJWT returns an RS256 JWT identifying the app, backdated a minute to allow for clock drift
+func (a *AppCredentials) signJWT(now time.Time) (string, error) {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": strconv.FormatInt(a.AppID, 10),
+	})
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.PrivateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign app JWT: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// errNotInstalled marks repositories the app is not installed on
+var errNotInstalled = errors.New("app is not installed on the repository")
+
+// repoToken is an installation token scoped to a single repository
+type repoToken struct {
+	token     string
+	expiresAt time.Time
+	installed bool
+}
+
+// appAuth mints installation tokens for a GitHub App, each scoped to the one repository a request
+// targets, so a token leaked from one repository's requests cannot touch any other repository
+type appAuth struct {
+	credentials *AppCredentials
+	client      *github.Client // Authenticated with the app JWT
+	verbose     bool
+	now         func() time.Time
+
+	mu        sync.Mutex
+	jwt       string
+	jwtExpiry time.Time
+	tokens    map[string]repoToken // Keyed by lowercase "owner/name"
+}
+
+// newAppAuth creates the app authenticator, sending its own requests through base
+func newAppAuth(credentials *AppCredentials, base http.RoundTripper, apiURL string, verbose bool) *appAuth {
+	auth := &appAuth{credentials: credentials, verbose: verbose, now: time.Now, tokens: make(map[string]repoToken)}
+	client := github.NewClient(&http.Client{Transport: &jwtTransport{base: base, auth: auth}})
+	if parsed, err := ParseAPIURL(apiURL); apiURL != "" && err == nil {
+		if enterprise, err := client.WithEnterpriseURLs(parsed, parsed); err == nil {
+			client = enterprise
+		}
+	}
+	auth.client = client
+	return auth
+}
+
+// appJWT returns the app JWT, signing a new one when the current one is close to expiry
+func (a *appAuth) appJWT() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	if a.jwt != "" && now.Before(a.jwtExpiry.Add(-time.Minute)) {
+		return a.jwt, nil
+	}
+	jwt, err := a.credentials.signJWT(now)
+	if err != nil {
+		return "", err
+	}
+	a.jwt, a.jwtExpiry = jwt, now.Add(appJWTLifetime)
+	return jwt, nil
+}
+
+// repositoryToken returns an installation token scoped to owner/repo, minting one when none is cached
+// or the cached one is close to expiry. Repositories the app is not installed on return errNotInstalled.
+func (a *appAuth) repositoryToken(ctx context.Context, owner, repo string) (string, error) {
+	key := strings.ToLower(owner + "/" + repo)
+
+	a.mu.Lock()
+	cached, ok := a.tokens[key]
+	a.mu.Unlock()
+	if ok && !cached.installed {
+		return "", errNotInstalled
+	}
+	if ok && a.now().Before(cached.expiresAt.Add(-appTokenRefresh)) {
+		return cached.token, nil
+	}
+
+	installation, resp, err := a.client.Apps.FindRepositoryInstallation(ctx, owner, repo)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			a.mu.Lock()
+			a.tokens[key] = repoToken{}
+			a.mu.Unlock()
+			return "", errNotInstalled
+		}
+		return "", fmt.Errorf("failed to find the app installation for %s/%s: %w", owner, repo, err)
+	}
+
+	minted, _, err := a.client.Apps.CreateInstallationToken(ctx, installation.GetID(), &github.InstallationTokenOptions{
+		Repositories: []string{repo},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to mint an installation token for %s/%s: %w", owner, repo, err)
+	}
+	if a.verbose {
+		log.Printf("GitHub API: Minted an installation token scoped to %s/%s (expires %s)", owner, repo, minted.GetExpiresAt().Format(time.RFC3339))
+	}
+
+	a.mu.Lock()
+	a.tokens[key] = repoToken{token: minted.GetToken(), expiresAt: minted.GetExpiresAt().Time, installed: true}
+	a.mu.Unlock()
+	return minted.GetToken(), nil
+}
+
+// login returns the app's bot login, e.g. "my-app[bot]", the author of its commits and pull requests
+func (a *appAuth) login(ctx context.Context) (string, error) {
+	app, _, err := a.client.Apps.Get(ctx, "")
+	if err != nil {
+		return "", classifyTokenError(err)
+	}
+	return app.GetSlug() + "[bot]", nil
+}
+
+// jwtTransport authenticates requests as the app itself
+type jwtTransport struct {
+	base http.RoundTripper
+	auth *appAuth
+}
+
+// RoundTrip sends a copy of the request carrying the app JWT
+func (t *jwtTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	jwt, err := t.auth.appJWT()
+	if err != nil {
+		return nil, err
+	}
+	authenticated := req.Clone(req.Context())
+	authenticated.Header.Set("Authorization", "Bearer "+jwt)
+	return t.base.RoundTrip(authenticated)
+}
+
+// appTransport authenticates each repository request with an installation token scoped to that
+// repository. Repositories the app is not installed on, such as public action repositories, are read
+// anonymously, and requests outside a repository carry the app JWT.
+type appTransport struct {
+	base http.RoundTripper
+	auth *appAuth
+}
+
+// RoundTrip sends a copy of the request carrying the token for the repository it targets
+func (t *appTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	authenticated := req.Clone(req.Context())
+	owner, repo, ok := requestRepository(req.URL.Path)
+	if !ok {
+		jwt, err := t.auth.appJWT()
+		if err != nil {
+			return nil, err
+		}
+		authenticated.Header.Set("Authorization", "Bearer "+jwt)
+		return t.base.RoundTrip(authenticated)
+	}
+
+	token, err := t.auth.repositoryToken(req.Context(), owner, repo)
+	switch {
+	case errors.Is(err, errNotInstalled):
+		authenticated.Header.Del("Authorization")
+	case err != nil:
+		return nil, err
+	default:
+		authenticated.Header.Set("Authorization", "token "+token)
+	}
+	return t.base.RoundTrip(authenticated)
+}
+
+// requestRepository returns the repository of a /repos/{owner}/{repo}/... API path, including paths
+// under a GitHub Enterprise Server API or upload prefix
+func requestRepository(urlPath string) (owner, repo string, ok bool) {
+	for _, prefix := range []string{"/api/v3", "/api/uploads"} {
+		urlPath = strings.TrimPrefix(urlPath, prefix)
+	}
+	parts := strings.Split(strings.TrimPrefix(urlPath, "/"), "/")
+	if len(parts) < 3 || parts[0] != "repos" || parts[1] == "" || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
@@ -0,0 +1,173 @@
+package github
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testAppCredentials(t *testing.T) *AppCredentials {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate a key: %v", err)
+	}
+	return &AppCredentials{AppID: 12345, PrivateKey: key}
+}
+
+// verifyJWT checks an "Authorization: Bearer" JWT is signed by the app's key and returns its claims
+func verifyJWT(t *testing.T, credentials *AppCredentials, authorization string) map[string]interface{} {
+	jwt, ok := strings.CutPrefix(authorization, "Bearer ")
+	parts := strings.Split(jwt, ".")
+	if !ok || len(parts) != 3 {
+		t.Errorf("Expected a bearer JWT, got %q", authorization)
+		return nil
+	}
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&credentials.PrivateKey.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+		t.Errorf("Expected the JWT to be signed with the app's key: %v", err)
+	}
+	payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	var claims map[string]interface{}
+	json.Unmarshal(payload, &claims)
+	return claims
+}
+
+func TestAppTransport_ScopesTokensPerRepository(t *testing.T) {
+	credentials := testAppCredentials(t)
+
+	var mu sync.Mutex
+	minted := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := strings.TrimPrefix(r.URL.Path, "/api/v3")
+		switch {
+		case path == "/app":
+			verifyJWT(t, credentials, r.Header.Get("Authorization"))
+			w.Write([]byte(`{"id": 12345, "slug": "maintainer"}`))
+		case path == "/repos/my-org/api/installation" || path == "/repos/my-org/web/installation":
+			claims := verifyJWT(t, credentials, r.Header.Get("Authorization"))
+			if claims["iss"] != "12345" {
+				t.Errorf("Expected the JWT to be issued by the app, got %v", claims["iss"])
+			}
+			w.Write([]byte(`{"id": 42}`))
+		case path == "/app/installations/42/access_tokens" && r.Method == http.MethodPost:
+			verifyJWT(t, credentials, r.Header.Get("Authorization"))
+			var body struct {
+				Repositories []string `json:"repositories"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			if len(body.Repositories) != 1 {
+				t.Errorf("Expected the token to be scoped to one repository, got %v", body.Repositories)
+			}
+			mu.Lock()
+			minted[body.Repositories[0]]++
+			mu.Unlock()
+			fmt.Fprintf(w, `{"token": "ghs_%sToken0000000000000000", "expires_at": %q}`, body.Repositories[0], time.Now().Add(time.Hour).Format(time.RFC3339))
+		case path == "/repos/actions/checkout/installation":
+			http.NotFound(w, r)
+		case strings.HasPrefix(path, "/repos/actions/checkout/"):
+			if r.Header.Get("Authorization") != "" {
+				t.Errorf("Expected repositories without the app to be read anonymously, got %q", r.Header.Get("Authorization"))
+			}
+			w.Write([]byte(`{"tag_name": "v4.1.0"}`))
+		case strings.HasPrefix(path, "/repos/my-org/"):
+			repo := strings.Split(path, "/")[3]
+			if expected := "token ghs_" + repo + "Token0000000000000000"; r.Header.Get("Authorization") != expected {
+				t.Errorf("Expected %s to be requested with its own token, got %q", path, r.Header.Get("Authorization"))
+			}
+			if strings.HasSuffix(path, "/readme") {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte(`{"name": "` + repo + `", "full_name": "my-org/` + repo + `", "default_branch": "main"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithConfig("", &Config{App: credentials, APIURL: server.URL + "/api/v3"})
+	if client.IsAnonymous() {
+		t.Error("Expected an app client not to be anonymous")
+	}
+
+	info, err := client.GetTokenInfo()
+	if err != nil || info.Login != "maintainer[bot]" {
+		t.Fatalf("Expected the app's bot login, got %+v (%v)", info, err)
+	}
+
+	for _, repo := range []string{"api", "web", "api"} {
+		if _, err := client.GetRepositoryStatus("my-org", repo); err != nil {
+			t.Fatalf("Failed to get my-org/%s: %v", repo, err)
+		}
+	}
+	if minted["api"] != 1 || minted["web"] != 1 {
+		t.Errorf("Expected one cached token per repository, got %v", minted)
+	}
+
+	if release, err := client.GetLatestRelease("actions", "checkout"); err != nil || release != "v4.1.0" {
+		t.Errorf("Expected the public repository to be read anonymously, got %q (%v)", release, err)
+	}
+}
+
+func TestRequestRepository(t *testing.T) {
+	for path, expected := range map[string]string{
+		"/repos/my-org/api/contents/.github":       "my-org/api",
+		"/api/v3/repos/my-org/api/git/refs":        "my-org/api",
+		"/api/uploads/repos/my-org/api/releases/1": "my-org/api",
+		"/repos/my-org/api":                        "my-org/api",
+		"/user":                                    "",
+		"/orgs/my-org/repos":                       "",
+		"/repos/my-org":                            "",
+		"/installation/repositories":               "",
+	} {
+		owner, repo, ok := requestRepository(path)
+		if got := owner + "/" + repo; (ok && got != expected) || (!ok && expected != "") {
+			t.Errorf("%s: expected %q, got %q (%t)", path, expected, got, ok)
+		}
+	}
+}
+
+func TestLoadAppCredentials(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate a key: %v", err)
+	}
+	dir := t.TempDir()
+	pkcs1 := filepath.Join(dir, "pkcs1.pem")
+	os.WriteFile(pkcs1, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600)
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	pkcs8 := filepath.Join(dir, "pkcs8.pem")
+	os.WriteFile(pkcs8, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600)
+
+	for _, file := range []string{pkcs1, pkcs8} {
+		credentials, err := LoadAppCredentials("12345", file)
+		if err != nil || credentials.AppID != 12345 || !credentials.PrivateKey.Equal(key) {
+			t.Errorf("%s: expected the app credentials, got %v", filepath.Base(file), err)
+		}
+	}
+
+	if _, err := LoadAppCredentials("my-app", pkcs1); err == nil {
+		t.Error("Expected a non-numeric app ID to be rejected")
+	}
+	notPEM := filepath.Join(dir, "key.txt")
+	os.WriteFile(notPEM, []byte("not a key"), 0600)
+	if _, err := LoadAppCredentials("12345", notPEM); err == nil || !strings.Contains(err.Error(), "PEM") {
+		t.Errorf("Expected a non-PEM key to be rejected, got %v", err)
+	}
+}
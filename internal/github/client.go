@@ -2,9 +2,15 @@ package github
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"path"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/google/go-github/v65/github"
 	"golang.org/x/oauth2"
@@ -12,14 +18,47 @@ import (
 
 // Config holds configuration options for the GitHub client
 type Config struct {
-	Verbose bool
+	Verbose     bool
+	MaxFileSize int               // Workflow files larger than this many bytes are not downloaded (0 = no limit)
+	Transport   http.RoundTripper // Base HTTP transport (nil = default, honoring proxy environment variables)
+	Timeout     time.Duration     // Overall timeout per API request (0 = DefaultRequestTimeout)
+	FileFilter  *WorkflowFilter   // Workflow files outside the filter are not downloaded (nil = every file)
+	Tags        RequestTags       // User-Agent and headers identifying the tool on every request
+	MaxTagPages int               // Pages of 100 tags listed per repository (0 = every page)
+	APIURL      string            // GitHub Enterprise Server API URL checked with ParseAPIURL, e.g. "https://ghe.example.com/api/v3" (empty = github.com)
+
+	// Filtered is called with each workflow file FileFilter excludes, e.g. to explain why it was not scanned
+	Filtered func(repo Repository, path string)
+
+	// SkipListingCheck keeps repository listings that come up short of the owner's reported totals,
+	// e.g. for tokens limited to some repositories
+	SkipListingCheck bool
+
+	// TokenRepositories lists the owner's repositories the token can access instead of every repository
+	// of the owner, for fine-grained tokens and GitHub App installations granted selected repositories
+	TokenRepositories bool
+
+	// App authenticates as a GitHub App instead of with a token: each repository's requests carry an
+	// installation token scoped to only that repository
+	App *AppCredentials
 }
 
 // Client wraps the GitHub API client with our specific functionality
 type Client struct {
-	client  *github.Client
-	ctx     context.Context
-	verbose bool
+	client      *github.Client
+	ctx         context.Context
+	verbose     bool
+	stats       *requestStats
+	maxFileSize int
+	fileFilter  *WorkflowFilter
+	filtered    func(repo Repository, path string)
+	anonymous   bool
+	maxTagPages int
+
+	skipListingCheck  bool
+	tokenRepositories bool
+
+	app *appAuth // Set when authenticating as a GitHub App
 }
 
 // Repository represents a GitHub repository with relevant metadata
@@ -29,6 +68,77 @@ type Repository struct {
 	DefaultBranch    string            `json:"default_branch"`
 	FullName         string            `json:"full_name"`
 	CustomProperties map[string]string `json:"custom_properties,omitempty"`
+	Topics           []string          `json:"topics,omitempty"`
+	Language         string            `json:"language,omitempty"`  // Primary language detected by GitHub
+	PushedAt         time.Time         `json:"pushed_at,omitempty"` // Last push to any branch
+}
+
+// PullRequestInfo is the state of a pull request opened from a branch
+type PullRequestInfo struct {
+	Number     int       `json:"number"`
+	State      string    `json:"state"` // "open" or "closed"
+	Merged     bool      `json:"merged"`
+	URL        string    `json:"url"`
+	Title      string    `json:"title"`
+	Author     string    `json:"author"` // Login of the user or app that opened the pull request, e.g. "dependabot[bot]"
+	HeadBranch string    `json:"head_branch"`
+	HeadSHA    string    `json:"head_sha"` // Commit at the tip of the head branch
+	CreatedAt  time.Time `json:"created_at"`
+	MergedAt   time.Time `json:"merged_at"` // Zero unless merged
+}
+
+// PullRequestFile is a file changed by a pull request
+type PullRequestFile struct {
+	Path  string
+	Patch string // Unified diff of the file; empty for binary or very large files
+}
+
+// RepositoryActivity is the maintenance state of an action repository
+type RepositoryActivity struct {
+	Archived          bool
+	OpenIssues        int       // Open issues, not counting pull requests
+	OpenPullRequests  int       // Open pull requests
+	LatestRelease     string    // Tag of the latest release; empty when the repository has none
+	LatestReleaseDate time.Time // When the latest release was published
+}
+
+// RepositoryStatus holds the parts of an action repository that can announce its deprecation
+type RepositoryStatus struct {
+	Archived    bool
+	Description string
+	Readme      string // Empty when the repository has no README
+}
+
+// TagRuleset is an active ruleset targeting tags, including rulesets inherited from the organization
+type TagRuleset struct {
+	Name    string
+	Include []string // Ref name patterns, e.g. "refs/tags/v*" or "~ALL"
+	Exclude []string
+	Rules   []string // Rule types, e.g. "update", "deletion"
+}
+
+// WorkflowRun identifies a run of a workflow file
+type WorkflowRun struct {
+	ID        int64
+	CreatedAt time.Time
+}
+
+// DispatchRun is a workflow_dispatch run of a workflow file
+type DispatchRun struct {
+	ID         int64
+	FilePath   string // Workflow file, e.g. ".github/workflows/ci.yml"
+	Status     string // queued, in_progress, or completed
+	Conclusion string // success, failure, cancelled, ... once completed
+	URL        string
+	CreatedAt  time.Time
+}
+
+// SecretInventory lists the names of the secrets and variables available to a repository's workflows
+type SecretInventory struct {
+	RepoSecrets   []string
+	OrgSecrets    []string // Organization secrets shared with the repository
+	RepoVariables []string
+	OrgVariables  []string // Organization variables shared with the repository
 }
 
 // WorkflowFile represents a workflow file found in a repository
@@ -36,6 +146,17 @@ type WorkflowFile struct {
 	Repository Repository
 	Path       string
 	Content    string
+	Size       int    // Size in bytes as reported by the API
+	SHA        string // Git blob SHA of the file on the default branch
+	CommitSHA  string // Default branch commit the file was read at; empty when it could not be resolved
+	TooLarge   bool   // Content was not downloaded because Size exceeds the client's limit
+}
+
+// workflowEntry is a workflow file path discovered in a repository along with its size and blob SHA
+type workflowEntry struct {
+	path string
+	size int
+	sha  string
 }
 
 // NewClient creates a new GitHub API client with authentication
@@ -46,24 +167,94 @@ func NewClient(token string) *Client {
 // NewClientWithConfig creates a new GitHub API client with authentication and configuration
 func NewClientWithConfig(token string, config *Config) *Client {
 	ctx := context.Background()
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
-	tc := oauth2.NewClient(ctx, ts)
+
+	// Route OAuth requests through the configured base transport (proxy, CA, TLS settings)
+	if config.Transport != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: config.Transport})
+	}
+
+	// Without a token requests are unauthenticated: public data only, at a much lower rate limit
+	var tc *http.Client
+	var app *appAuth
+	switch {
+	case config.App != nil:
+		base := config.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		app = newAppAuth(config.App, newTagTransport(base, config.Tags), config.APIURL, config.Verbose)
+		tc = &http.Client{Transport: &appTransport{base: base, auth: app}}
+	case token == "":
+		tc = &http.Client{Transport: config.Transport}
+	default:
+		ts := oauth2.StaticTokenSource(
+			&oauth2.Token{AccessToken: token},
+		)
+		tc = oauth2.NewClient(ctx, ts)
+	}
+
+	tc.Timeout = config.Timeout
+	if tc.Timeout == 0 {
+		tc.Timeout = DefaultRequestTimeout
+	}
+
+	// Record request counts and latency so scans can report time spent in the API
+	stats := &requestStats{}
+	base := tc.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	tc.Transport = &statsTransport{base: newTagTransport(base, config.Tags), stats: stats}
 
 	client := github.NewClient(tc)
 
+	// GitHub Enterprise Server serves the API, and uploads, under its own host; callers check the URL
+	// with ParseAPIURL first
+	if apiURL, err := ParseAPIURL(config.APIURL); config.APIURL != "" && err == nil {
+		if enterprise, err := client.WithEnterpriseURLs(apiURL, apiURL); err == nil {
+			client = enterprise
+		}
+	}
+
 	if config.Verbose {
 		log.Printf("GitHub client initialized with verbose logging enabled")
 	}
 
 	return &Client{
-		client:  client,
-		ctx:     ctx,
-		verbose: config.Verbose,
+		client:      client,
+		ctx:         ctx,
+		verbose:     config.Verbose,
+		stats:       stats,
+		maxFileSize: config.MaxFileSize,
+		fileFilter:  config.FileFilter,
+		filtered:    config.Filtered,
+		anonymous:   token == "" && app == nil,
+		app:         app,
+		maxTagPages: config.MaxTagPages,
+
+		skipListingCheck:  config.SkipListingCheck,
+		tokenRepositories: config.TokenRepositories,
 	}
 }
 
+// IsAnonymous reports whether the client makes unauthenticated requests
+func (c *Client) IsAnonymous() bool {
+	return c.anonymous
+}
+
+// GetRateLimit returns the core REST API rate limit for the client's credentials
+// Checking the rate limit does not count against it.
+func (c *Client) GetRateLimit() (remaining, limit int, reset time.Time, err error) {
+	limits, _, err := c.client.RateLimit.Get(c.ctx)
+	if err != nil {
+		return 0, 0, time.Time{}, fmt.Errorf("failed to get rate limit: %w", err)
+	}
+	if limits == nil || limits.Core == nil {
+		return 0, 0, time.Time{}, fmt.Errorf("rate limit response has no core limit")
+	}
+	return limits.Core.Remaining, limits.Core.Limit, limits.Core.Reset.Time, nil
+}
+
 // ListRepositories gets all repositories for a given owner (user or org)
 func (c *Client) ListRepositories(owner string) ([]Repository, error) {
 	return c.ListRepositoriesWithCustomProperties(owner, nil)
@@ -78,6 +269,14 @@ func (c *Client) ListRepositoriesWithCustomProperties(owner string, customProper
 		}
 	}
 
+	if c.tokenRepositories {
+		repos, err := c.listAccessibleRepositories(owner)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repositories the token can access: %w", err)
+		}
+		return c.toRepositories(owner, repos, customProperties), nil
+	}
+
 	// First, determine if owner is a user or organization
 	isOrg, err := c.isOrganization(owner)
 	if err != nil {
@@ -93,6 +292,13 @@ func (c *Client) ListRepositoriesWithCustomProperties(owner string, customProper
 			log.Printf("GitHub API: Owner '%s' detected as organization, using org endpoint", owner)
 		}
 		repos, err := c.listRepositoriesAsOrgWithCustomProperties(owner, customProperties)
+		// Only an organization listing that fails outright falls back; a later page failing, or an
+		// incomplete listing, would otherwise be hidden by the user endpoint's public repositories
+		var pageErr *ListingPageError
+		var incompleteErr *IncompleteListingError
+		if errors.As(err, &incompleteErr) || (errors.As(err, &pageErr) && pageErr.Page > 1) {
+			return nil, err
+		}
 		if err != nil {
 			if c.verbose {
 				log.Printf("GitHub API: Organization endpoint failed, falling back to user endpoint - %v", err)
@@ -154,318 +360,1013 @@ func (c *Client) listRepositoriesAsOrg(org string) ([]Repository, error) {
 
 // listRepositoriesAsOrgWithCustomProperties lists repositories for an organization with custom properties
 func (c *Client) listRepositoriesAsOrgWithCustomProperties(org string, customProperties []string) ([]Repository, error) {
-	var allRepos []Repository
+	repos, err := c.listAllRepositories(org, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization repositories: %w", err)
+	}
+	return c.toRepositories(org, repos, customProperties), nil
+}
 
-	opts := &github.RepositoryListByOrgOptions{
-		Type: "all", // Include public, private, and forked repositories
-		ListOptions: github.ListOptions{
-			PerPage: 100,
-		},
+// listRepositoriesAsUser lists repositories for a user
+func (c *Client) listRepositoriesAsUser(user string) ([]Repository, error) {
+	return c.listRepositoriesAsUserWithCustomProperties(user, nil)
+}
+
+// listRepositoriesAsUserWithCustomProperties lists repositories for a user with custom properties
+func (c *Client) listRepositoriesAsUserWithCustomProperties(user string, customProperties []string) ([]Repository, error) {
+	repos, err := c.listAllRepositories(user, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user repositories: %w", err)
 	}
+	return c.toRepositories(user, repos, customProperties), nil
+}
 
-	pageCount := 0
-	for {
-		pageCount++
-		if c.verbose {
-			log.Printf("GitHub API: GET /orgs/%s/repos (page=%d, per_page=%d, type=%s)", org, opts.Page, opts.PerPage, opts.Type)
+// toRepositories converts listed repositories, skipping those without a default branch, and fetches
+// the requested custom properties of each
+func (c *Client) toRepositories(owner string, repos []*github.Repository, customProperties []string) []Repository {
+	var allRepos []Repository
+	for _, repo := range repos {
+		if repo.GetDefaultBranch() == "" {
+			continue // Skip repos without default branch
 		}
 
-		repos, resp, err := c.client.Repositories.ListByOrg(c.ctx, org, opts)
-		if err != nil {
-			if c.verbose {
-				log.Printf("GitHub API: Error listing organization repositories on page %d - %v", pageCount, err)
-			}
-			// If this is the first page, return the error as the operation completely failed
-			if pageCount == 1 {
-				return nil, fmt.Errorf("failed to list organization repositories: %w", err)
-			}
-			// If this is a subsequent page, log a warning but return what we have so far
-			if c.verbose {
-				log.Printf("GitHub API: Pagination failed on page %d, returning %d repositories from previous pages", pageCount, len(allRepos))
-			}
-			break
+		repository := Repository{
+			Owner:         owner,
+			Name:          repo.GetName(),
+			DefaultBranch: repo.GetDefaultBranch(),
+			FullName:      repo.GetFullName(),
+			Topics:        repo.Topics,
+			Language:      repo.GetLanguage(),
+			PushedAt:      repo.GetPushedAt().Time,
 		}
 
-		if c.verbose {
-			log.Printf("GitHub API: Response status %d, received %d repositories on page %d", resp.StatusCode, len(repos), pageCount)
+		// Fetch custom properties if requested
+		if len(customProperties) > 0 {
+			props, err := c.GetRepositoryCustomProperties(owner, repo.GetName(), customProperties)
+			if err != nil {
+				if c.verbose {
+					log.Printf("Warning: Failed to fetch custom properties for %s: %v", repo.GetFullName(), err)
+				}
+				// Continue with empty properties rather than failing
+			}
+			repository.CustomProperties = props
 		}
 
-		for _, repo := range repos {
-			if repo.GetDefaultBranch() == "" {
-				continue // Skip repos without default branch
-			}
+		allRepos = append(allRepos, repository)
+	}
+	return allRepos
+}
 
-			repository := Repository{
-				Owner:         org,
-				Name:          repo.GetName(),
-				DefaultBranch: repo.GetDefaultBranch(),
-				FullName:      repo.GetFullName(),
-			}
+// DefaultWorkflowDirs are the workflow directories scanned when none are configured
+var DefaultWorkflowDirs = []string{".github/workflows"}
 
-			// Fetch custom properties if requested
-			if len(customProperties) > 0 {
-				props, err := c.GetRepositoryCustomProperties(org, repo.GetName(), customProperties)
-				if err != nil {
-					if c.verbose {
-						log.Printf("Warning: Failed to fetch custom properties for %s: %v", repo.GetFullName(), err)
-					}
-					// Continue with empty properties rather than failing
-				}
-				repository.CustomProperties = props
-			}
+// OrgTemplateRepository is the repository holding an organization's shared community files and workflow templates
+const OrgTemplateRepository = ".github"
 
-			allRepos = append(allRepos, repository)
-		}
+// WorkflowTemplatesDir holds the workflow templates offered to every new repository, in the OrgTemplateRepository
+const WorkflowTemplatesDir = "workflow-templates"
 
-		if resp.NextPage == 0 {
-			break
-		}
-		opts.Page = resp.NextPage
+// WorkflowDirsFor returns the workflow directories to scan in a repository: the given directories, plus the
+// workflow templates directory in the owner's .github repository
+func WorkflowDirsFor(repo Repository, dirs []string) []string {
+	if repo.Name != OrgTemplateRepository {
+		return dirs
 	}
-
-	if c.verbose {
-		log.Printf("GitHub API: Total organization repositories found: %d (across %d pages)", len(allRepos), pageCount)
+	for _, dir := range dirs {
+		if NormalizeWorkflowDir(dir) == WorkflowTemplatesDir {
+			return dirs
+		}
 	}
+	return append(append([]string{}, dirs...), WorkflowTemplatesDir)
+}
 
-	return allRepos, nil
+// IsWorkflowTemplate reports whether a file is one of the organization's workflow templates
+func IsWorkflowTemplate(repo Repository, filePath string) bool {
+	return repo.Name == OrgTemplateRepository && strings.HasPrefix(filePath, WorkflowTemplatesDir+"/")
 }
 
-// listRepositoriesAsUser lists repositories for a user
-func (c *Client) listRepositoriesAsUser(user string) ([]Repository, error) {
-	return c.listRepositoriesAsUserWithCustomProperties(user, nil)
+// GetWorkflowFiles retrieves all workflow files from a repository's .github/workflows directory
+func (c *Client) GetWorkflowFiles(repo Repository) ([]WorkflowFile, error) {
+	return c.GetWorkflowFilesInDirs(repo, DefaultWorkflowDirs)
 }
 
-// listRepositoriesAsUserWithCustomProperties lists repositories for a user with custom properties
-func (c *Client) listRepositoriesAsUserWithCustomProperties(user string, customProperties []string) ([]Repository, error) {
-	var allRepos []Repository
+// GetWorkflowFilesInDirs retrieves workflow files from every directory matching the given patterns
+// Patterns are slash-separated directory paths relative to the repository root. Plain paths
+// (e.g., ".gitea/workflows") are walked recursively; patterns containing glob characters
+// (e.g., "services/*/.github/workflows" or "**/workflows") are matched against the repository tree.
+func (c *Client) GetWorkflowFilesInDirs(repo Repository, patterns []string) ([]WorkflowFile, error) {
+	if c.verbose {
+		log.Printf("GitHub API: Getting workflow files for repository '%s' from %v", repo.FullName, patterns)
+	}
 
-	opts := &github.RepositoryListByUserOptions{
-		Type: "all", // Include public, private, and forked repositories
-		ListOptions: github.ListOptions{
-			PerPage: 100,
-		},
+	// Every file is read at the same commit, so a push during the scan cannot mix two versions of the branch
+	commitSHA, empty := c.branchHead(repo)
+	if empty {
+		return nil, &NoWorkflowsError{Repository: repo.FullName, Reason: ContentEmpty}
+	}
+	ref := repo.DefaultBranch
+	if commitSHA != "" {
+		ref = commitSHA
 	}
 
-	pageCount := 0
-	for {
-		pageCount++
-		if c.verbose {
-			log.Printf("GitHub API: GET /users/%s/repos (page=%d, per_page=%d, type=%s)", user, opts.Page, opts.PerPage, opts.Type)
+	var entries []workflowEntry
+	var globPatterns []string
+	var submodule error
+	seen := make(map[string]bool)
+
+	for _, pattern := range patterns {
+		pattern = NormalizeWorkflowDir(pattern)
+		if pattern == "" {
+			continue
+		}
+		if IsGlobPattern(pattern) {
+			globPatterns = append(globPatterns, pattern)
+			continue
 		}
 
-		repos, resp, err := c.client.Repositories.ListByUser(c.ctx, user, opts)
+		dirEntries, err := c.listWorkflowDir(repo, pattern, ref)
+		if NoWorkflowsReason(err) == ContentSubmodule {
+			submodule = err
+			continue
+		}
 		if err != nil {
-			if c.verbose {
-				log.Printf("GitHub API: Error listing user repositories on page %d - %v", pageCount, err)
+			return nil, err
+		}
+		for _, entry := range dirEntries {
+			if !seen[entry.path] {
+				seen[entry.path] = true
+				entries = append(entries, entry)
 			}
-			// If this is the first page, return the error as the operation completely failed
-			if pageCount == 1 {
-				return nil, fmt.Errorf("failed to list user repositories: %w", err)
+		}
+	}
+
+	// Glob patterns need the full repository tree, fetched once for all patterns
+	if len(globPatterns) > 0 {
+		treeEntries, err := c.listTreeFiles(repo, ref)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range treeEntries {
+			if seen[entry.path] || !isWorkflowFile(entry.path) {
+				continue
 			}
-			// If this is a subsequent page, log a warning but return what we have so far
-			if c.verbose {
-				log.Printf("GitHub API: Pagination failed on page %d, returning %d repositories from previous pages", pageCount, len(allRepos))
+			for _, pattern := range globPatterns {
+				if MatchesWorkflowDir(pattern, entry.path) {
+					seen[entry.path] = true
+					entries = append(entries, entry)
+					break
+				}
 			}
-			break
 		}
+	}
 
-		if c.verbose {
-			log.Printf("GitHub API: Response status %d, received %d repositories on page %d", resp.StatusCode, len(repos), pageCount)
+	// A .github directory vendored as a submodule lists as missing, since the API does not serve its files
+	if len(entries) == 0 {
+		if submodule != nil {
+			return nil, submodule
 		}
+		if path := c.findSubmodule(repo, patterns, ref); path != "" {
+			return nil, &NoWorkflowsError{Repository: repo.FullName, Reason: ContentSubmodule, Path: path}
+		}
+	}
 
-		for _, repo := range repos {
-			if repo.GetDefaultBranch() == "" {
-				continue // Skip repos without default branch
-			}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].path < entries[j].path
+	})
 
-			repository := Repository{
-				Owner:         user,
-				Name:          repo.GetName(),
-				DefaultBranch: repo.GetDefaultBranch(),
-				FullName:      repo.GetFullName(),
+	var workflowFiles []WorkflowFile
+	for _, entry := range entries {
+		// Files outside the scan's workflow filter are neither downloaded nor analyzed
+		if !c.fileFilter.Allows(entry.path) {
+			if c.verbose {
+				log.Printf("Skipping workflow file %s: excluded by workflow filter", entry.path)
 			}
-
-			// Fetch custom properties if requested
-			if len(customProperties) > 0 {
-				props, err := c.GetRepositoryCustomProperties(user, repo.GetName(), customProperties)
-				if err != nil {
-					if c.verbose {
-						log.Printf("Warning: Failed to fetch custom properties for %s: %v", repo.GetFullName(), err)
-					}
-					// Continue with empty properties rather than failing
-				}
-				repository.CustomProperties = props
+			if c.filtered != nil {
+				c.filtered(repo, entry.path)
 			}
+			continue
+		}
 
-			allRepos = append(allRepos, repository)
+		// Skip oversized files without downloading them
+		if c.maxFileSize > 0 && entry.size > c.maxFileSize {
+			if c.verbose {
+				log.Printf("Skipping workflow file %s: %d bytes exceeds limit of %d", entry.path, entry.size, c.maxFileSize)
+			}
+			workflowFiles = append(workflowFiles, WorkflowFile{
+				Repository: repo,
+				Path:       entry.path,
+				Size:       entry.size,
+				SHA:        entry.sha,
+				CommitSHA:  commitSHA,
+				TooLarge:   true,
+			})
+			continue
 		}
 
-		if resp.NextPage == 0 {
-			break
+		content, err := c.getFileContent(repo, entry.path, ref)
+		if err != nil {
+			return nil, err
 		}
-		opts.Page = resp.NextPage
+
+		workflowFiles = append(workflowFiles, WorkflowFile{
+			Repository: repo,
+			Path:       entry.path,
+			Content:    content,
+			Size:       entry.size,
+			SHA:        entry.sha,
+			CommitSHA:  commitSHA,
+		})
 	}
 
 	if c.verbose {
-		log.Printf("GitHub API: Total user repositories found: %d (across %d pages)", len(allRepos), pageCount)
+		log.Printf("GitHub API: Total workflow files retrieved: %d", len(workflowFiles))
 	}
 
-	return allRepos, nil
+	return workflowFiles, nil
 }
 
-// GetWorkflowFiles retrieves all workflow files from a repository's .github/workflows directory
-func (c *Client) GetWorkflowFiles(repo Repository) ([]WorkflowFile, error) {
+// branchHead returns the commit SHA the default branch points at, or an empty SHA when it cannot be
+// resolved. GitHub answers 409 Conflict for repositories without any commits, which are reported as empty.
+func (c *Client) branchHead(repo Repository) (string, bool) {
 	if c.verbose {
-		log.Printf("GitHub API: Getting workflow files for repository '%s'", repo.FullName)
+		log.Printf("GitHub API: GET /repos/%s/git/ref/heads/%s", repo.FullName, repo.DefaultBranch)
 	}
 
-	var workflowFiles []WorkflowFile
+	ref, resp, err := c.client.Git.GetRef(c.ctx, repo.Owner, repo.Name, "heads/"+repo.DefaultBranch)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusConflict {
+			return "", true
+		}
+		if c.verbose {
+			log.Printf("GitHub API: Unable to resolve %s of %s, reading files from the branch: %v", repo.DefaultBranch, repo.FullName, err)
+		}
+		return "", false
+	}
+	return ref.GetObject().GetSHA(), false
+}
+
+// findSubmodule returns the top-level directory of a workflow directory pattern that is a git submodule,
+// or an empty string when none is
+func (c *Client) findSubmodule(repo Repository, patterns []string, ref string) string {
+	checked := make(map[string]bool)
+	for _, pattern := range patterns {
+		pattern = NormalizeWorkflowDir(pattern)
+		if pattern == "" || IsGlobPattern(pattern) {
+			continue
+		}
+		top := strings.SplitN(pattern, "/", 2)[0]
+		if checked[top] {
+			continue
+		}
+		checked[top] = true
+
+		if c.verbose {
+			log.Printf("GitHub API: GET /repos/%s/contents/%s", repo.FullName, top)
+		}
+		content, _, _, err := c.client.Repositories.GetContents(c.ctx, repo.Owner, repo.Name, top, &github.RepositoryContentGetOptions{Ref: ref})
+		if err == nil && content.GetType() == "submodule" {
+			return top
+		}
+	}
+	return ""
+}
 
-	// Get contents of .github/workflows directory
+// listWorkflowDir recursively lists workflow files under a directory at a ref
+func (c *Client) listWorkflowDir(repo Repository, dir, ref string) ([]workflowEntry, error) {
 	if c.verbose {
-		log.Printf("GitHub API: GET /repos/%s/contents/.github/workflows", repo.FullName)
+		log.Printf("GitHub API: GET /repos/%s/contents/%s", repo.FullName, dir)
 	}
 
-	_, dirContent, resp, err := c.client.Repositories.GetContents(
+	fileContent, dirContent, resp, err := c.client.Repositories.GetContents(
 		c.ctx,
 		repo.Owner,
 		repo.Name,
-		".github/workflows",
-		&github.RepositoryContentGetOptions{Ref: repo.DefaultBranch},
+		dir,
+		&github.RepositoryContentGetOptions{Ref: ref},
 	)
 
 	if err != nil {
 		// If the directory doesn't exist, that's okay - no workflows
 		if resp != nil && resp.StatusCode == 404 {
 			if c.verbose {
-				log.Printf("GitHub API: No .github/workflows directory found (404) - repository has no workflows")
+				log.Printf("GitHub API: No %s directory found (404)", dir)
 			}
-			return workflowFiles, nil
+			return nil, nil
 		}
 		if c.verbose {
-			log.Printf("GitHub API: Error getting workflow directory - %v", err)
+			log.Printf("GitHub API: Error getting workflow directory %s - %v", dir, err)
 		}
-		return nil, fmt.Errorf("failed to get workflow directory: %w", err)
+		return nil, fmt.Errorf("failed to get workflow directory %s: %w", dir, err)
+	}
+
+	if fileContent.GetType() == "submodule" {
+		return nil, &NoWorkflowsError{Repository: repo.FullName, Reason: ContentSubmodule, Path: dir}
 	}
 
 	if c.verbose {
-		log.Printf("GitHub API: Response status %d, found %d items in workflows directory", resp.StatusCode, len(dirContent))
+		log.Printf("GitHub API: Response status %d, found %d items in %s", resp.StatusCode, len(dirContent), dir)
 	}
 
-	// Process each file in the workflows directory
+	var entries []workflowEntry
 	for _, item := range dirContent {
-		if item.GetType() != "file" {
-			continue
-		}
-
-		filename := item.GetName()
-		// Only process YAML/YML files
-		if !isWorkflowFile(filename) {
-			if c.verbose {
-				log.Printf("Skipping non-workflow file: %s", filename)
+		switch item.GetType() {
+		case "dir":
+			// Nested directories may hold reusable workflows
+			nested, err := c.listWorkflowDir(repo, item.GetPath(), ref)
+			if err != nil {
+				return nil, err
 			}
-			continue
+			entries = append(entries, nested...)
+		case "file":
+			if !isWorkflowFile(item.GetName()) {
+				if c.verbose {
+					log.Printf("Skipping non-workflow file: %s", item.GetPath())
+				}
+				continue
+			}
+			entries = append(entries, workflowEntry{path: item.GetPath(), size: item.GetSize(), sha: item.GetSHA()})
 		}
+	}
 
-		if c.verbose {
-			log.Printf("GitHub API: GET /repos/%s/contents/%s", repo.FullName, item.GetPath())
-		}
+	return entries, nil
+}
 
-		// Get the file content
-		fileContent, _, _, err := c.client.Repositories.GetContents(
-			c.ctx,
-			repo.Owner,
-			repo.Name,
-			item.GetPath(),
-			&github.RepositoryContentGetOptions{Ref: repo.DefaultBranch},
-		)
+// listTreeFiles lists all files in the repository at a ref
+func (c *Client) listTreeFiles(repo Repository, ref string) ([]workflowEntry, error) {
+	if c.verbose {
+		log.Printf("GitHub API: GET /repos/%s/git/trees/%s?recursive=1", repo.FullName, ref)
+	}
 
-		if err != nil {
-			if c.verbose {
-				log.Printf("GitHub API: Error getting workflow file %s - %v", filename, err)
-			}
-			return nil, fmt.Errorf("failed to get workflow file %s: %w", filename, err)
+	tree, resp, err := c.client.Git.GetTree(c.ctx, repo.Owner, repo.Name, ref, true)
+	if err != nil {
+		// Empty repositories have no tree
+		if resp != nil && (resp.StatusCode == 404 || resp.StatusCode == 409) {
+			return nil, nil
 		}
+		return nil, fmt.Errorf("failed to get repository tree: %w", err)
+	}
 
-		content, err := fileContent.GetContent()
-		if err != nil {
-			if c.verbose {
-				log.Printf("Error decoding workflow file %s - %v", filename, err)
-			}
-			return nil, fmt.Errorf("failed to decode workflow file %s: %w", filename, err)
+	if tree.GetTruncated() {
+		log.Printf("Warning: Repository tree for %s was truncated; some workflow files may be missed", repo.FullName)
+	}
+
+	var entries []workflowEntry
+	for _, entry := range tree.Entries {
+		if entry.GetType() == "blob" {
+			entries = append(entries, workflowEntry{path: entry.GetPath(), size: entry.GetSize(), sha: entry.GetSHA()})
 		}
+	}
+
+	return entries, nil
+}
+
+// getFileContent retrieves and decodes a single file at a ref
+func (c *Client) getFileContent(repo Repository, filePath, ref string) (string, error) {
+	if c.verbose {
+		log.Printf("GitHub API: GET /repos/%s/contents/%s", repo.FullName, filePath)
+	}
+
+	fileContent, _, _, err := c.client.Repositories.GetContents(
+		c.ctx,
+		repo.Owner,
+		repo.Name,
+		filePath,
+		&github.RepositoryContentGetOptions{Ref: ref},
+	)
 
+	if err != nil {
 		if c.verbose {
-			log.Printf("Successfully retrieved workflow file: %s (%d bytes)", item.GetPath(), len(content))
+			log.Printf("GitHub API: Error getting workflow file %s - %v", filePath, err)
 		}
+		return "", fmt.Errorf("failed to get workflow file %s: %w", filePath, err)
+	}
 
-		workflowFiles = append(workflowFiles, WorkflowFile{
-			Repository: repo,
-			Path:       item.GetPath(),
-			Content:    content,
-		})
+	content, err := fileContent.GetContent()
+	if err != nil {
+		if c.verbose {
+			log.Printf("Error decoding workflow file %s - %v", filePath, err)
+		}
+		return "", fmt.Errorf("failed to decode workflow file %s: %w", filePath, err)
 	}
 
 	if c.verbose {
-		log.Printf("GitHub API: Total workflow files retrieved: %d", len(workflowFiles))
+		log.Printf("Successfully retrieved workflow file: %s (%d bytes)", filePath, len(content))
 	}
 
-	return workflowFiles, nil
+	return content, nil
 }
 
-// isWorkflowFile checks if a filename is a workflow file (yml or yaml)
-func isWorkflowFile(filename string) bool {
-	if len(filename) < 5 {
-		return false
+// GetFileContent returns the content of a file at a ref
+func (c *Client) GetFileContent(owner, repo, filePath, ref string) (string, error) {
+	return c.getFileContent(Repository{Owner: owner, Name: repo, FullName: owner + "/" + repo}, filePath, ref)
+}
+
+// GetRequiredStatusChecks returns the status checks a branch requires before merging, from branch
+// protection and from active rulesets, sorted and without duplicates. Unprotected branches require none.
+func (c *Client) GetRequiredStatusChecks(owner, repo, branch string) ([]string, error) {
+	if c.verbose {
+		log.Printf("GitHub API: GET /repos/%s/%s/branches/%s/protection/required_status_checks", owner, repo, branch)
 	}
 
-	ext := filename[len(filename)-4:]
-	return ext == ".yml" || ext == "yaml"
-}
+	seen := make(map[string]bool)
+	var checks []string
+	add := func(check string) {
+		if check != "" && !seen[check] {
+			seen[check] = true
+			checks = append(checks, check)
+		}
+	}
 
-// CreatePullRequest creates a pull request with the given changes
-func (c *Client) CreatePullRequest(repo Repository, title, body, headBranch string) error {
-	baseBranch := repo.DefaultBranch
+	required, resp, err := c.client.Repositories.GetRequiredStatusChecks(c.ctx, owner, repo, branch)
+	switch {
+	case err == nil:
+		if required.Contexts != nil {
+			for _, check := range *required.Contexts {
+				add(check)
+			}
+		}
+		if required.Checks != nil {
+			for _, check := range *required.Checks {
+				add(check.Context)
+			}
+		}
+	case resp != nil && resp.StatusCode == http.StatusNotFound:
+		// Branch is not protected, or protection requires no status checks
+	default:
+		return nil, fmt.Errorf("failed to get required status checks: %w", classifyTokenError(err))
+	}
 
-	newPR := &github.NewPullRequest{
-		Title: &title,
-		Head:  &headBranch,
-		Base:  &baseBranch,
-		Body:  &body,
+	if c.verbose {
+		log.Printf("GitHub API: GET /repos/%s/%s/rules/branches/%s", owner, repo, branch)
 	}
 
-	_, _, err := c.client.PullRequests.Create(c.ctx, repo.Owner, repo.Name, newPR)
+	rules, _, err := c.client.Repositories.GetRulesForBranch(c.ctx, owner, repo, branch)
 	if err != nil {
-		return fmt.Errorf("failed to create pull request: %w", err)
+		return nil, fmt.Errorf("failed to get branch rules: %w", classifyTokenError(err))
+	}
+	for _, rule := range rules {
+		if rule.Type != "required_status_checks" || rule.Parameters == nil {
+			continue
+		}
+		var params struct {
+			RequiredStatusChecks []struct {
+				Context string `json:"context"`
+			} `json:"required_status_checks"`
+		}
+		if err := json.Unmarshal(*rule.Parameters, &params); err != nil {
+			return nil, fmt.Errorf("failed to read required status checks rule: %w", err)
+		}
+		for _, check := range params.RequiredStatusChecks {
+			add(check.Context)
+		}
 	}
 
-	return nil
+	sort.Strings(checks)
+	return checks, nil
 }
 
-// ResolveRef resolves a git reference (tag, branch, or SHA) to a commit SHA
-func (c *Client) ResolveRef(owner, repo, ref string) (string, error) {
-	// Try to get the reference directly
-	gitRef, _, err := c.client.Git.GetRef(c.ctx, owner, repo, "refs/tags/"+ref)
-	if err == nil && gitRef.Object != nil {
-		return gitRef.Object.GetSHA(), nil
-	}
+// CodeOwnersPaths are where GitHub looks for a CODEOWNERS file, in order; the first one found is used
+var CodeOwnersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
 
-	// Try as a branch reference
-	gitRef, _, err = c.client.Git.GetRef(c.ctx, owner, repo, "refs/heads/"+ref)
-	if err == nil && gitRef.Object != nil {
-		return gitRef.Object.GetSHA(), nil
-	}
+// GetCodeOwners returns the content of a repository's CODEOWNERS file at a ref, or empty content when it has none
+func (c *Client) GetCodeOwners(owner, repo, ref string) (string, error) {
+	for _, filePath := range CodeOwnersPaths {
+		if c.verbose {
+			log.Printf("GitHub API: GET /repos/%s/%s/contents/%s?ref=%s", owner, repo, filePath, ref)
+		}
 
-	// Try to get commit directly (if ref is already a SHA)
-	commit, _, err := c.client.Git.GetCommit(c.ctx, owner, repo, ref)
+		fileContent, _, resp, err := c.client.Repositories.GetContents(
+			c.ctx,
+			owner,
+			repo,
+			filePath,
+			&github.RepositoryContentGetOptions{Ref: ref},
+		)
+		if err != nil {
+			if resp != nil && resp.StatusCode == 404 {
+				continue
+			}
+			return "", fmt.Errorf("failed to get %s: %w", filePath, classifyTokenError(err))
+		}
+		if fileContent == nil {
+			continue
+		}
+
+		content, err := fileContent.GetContent()
+		if err != nil {
+			return "", fmt.Errorf("failed to decode %s: %w", filePath, err)
+		}
+		return content, nil
+	}
+	return "", nil
+}
+
+// GetFileSHA returns the blob SHA of a file at a ref, or an empty SHA when the file does not exist
+func (c *Client) GetFileSHA(owner, repo, filePath, ref string) (string, error) {
+	if c.verbose {
+		log.Printf("GitHub API: GET /repos/%s/%s/contents/%s?ref=%s", owner, repo, filePath, ref)
+	}
+
+	fileContent, _, resp, err := c.client.Repositories.GetContents(
+		c.ctx,
+		owner,
+		repo,
+		filePath,
+		&github.RepositoryContentGetOptions{Ref: ref},
+	)
+	if err != nil {
+		if resp != nil && resp.StatusCode == 404 {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get file %s: %w", filePath, err)
+	}
+	if fileContent == nil {
+		return "", fmt.Errorf("%s is a directory, not a file", filePath)
+	}
+
+	return fileContent.GetSHA(), nil
+}
+
+// PutFile creates or updates a file on a branch with a commit of its own
+// It reports whether a commit was made; files whose content is unchanged are left alone.
+func (c *Client) PutFile(owner, repo, branch, filePath string, content []byte, message string) (bool, error) {
+	if c.verbose {
+		log.Printf("GitHub API: PUT /repos/%s/%s/contents/%s (branch %s)", owner, repo, filePath, branch)
+	}
+
+	opts := &github.RepositoryContentFileOptions{
+		Message: &message,
+		Content: content,
+		Branch:  &branch,
+	}
+
+	existing, _, resp, err := c.client.Repositories.GetContents(c.ctx, owner, repo, filePath, &github.RepositoryContentGetOptions{Ref: branch})
+	switch {
+	case err != nil && resp != nil && resp.StatusCode == 404:
+		_, _, err = c.client.Repositories.CreateFile(c.ctx, owner, repo, filePath, opts)
+	case err != nil:
+		return false, fmt.Errorf("failed to get file %s: %w", filePath, classifyTokenError(err))
+	case existing == nil:
+		return false, fmt.Errorf("%s is a directory, not a file", filePath)
+	default:
+		if current, decodeErr := existing.GetContent(); decodeErr == nil && current == string(content) {
+			return false, nil
+		}
+		opts.SHA = existing.SHA
+		_, _, err = c.client.Repositories.UpdateFile(c.ctx, owner, repo, filePath, opts)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to write file %s: %w", filePath, classifyTokenError(err))
+	}
+
+	return true, nil
+}
+
+// NormalizeWorkflowDir converts a user-supplied directory to the slash-separated form used by the GitHub API
+// Windows-style separators and leading "./" are accepted so paths copied from a local checkout work as-is.
+func NormalizeWorkflowDir(dir string) string {
+	dir = strings.ReplaceAll(strings.TrimSpace(dir), `\`, "/")
+	for strings.HasPrefix(dir, "./") {
+		dir = dir[2:]
+	}
+	return strings.Trim(dir, "/")
+}
+
+// IsGlobPattern reports whether a workflow directory pattern contains glob characters
+func IsGlobPattern(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+// MatchesWorkflowDir reports whether a file lives (at any depth) under a directory matching pattern
+// Each pattern segment is matched with path.Match; a "**" segment matches zero or more directories.
+func MatchesWorkflowDir(pattern, filePath string) bool {
+	patternSegs := strings.Split(strings.Trim(pattern, "/"), "/")
+	dirSegs := strings.Split(path.Dir(filePath), "/")
+
+	// Try every ancestor directory of the file so nested workflows are included
+	for i := len(dirSegs); i > 0; i-- {
+		if matchSegments(patternSegs, dirSegs[:i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments matches path segments against pattern segments, supporting "**"
+func matchSegments(patternSegs, pathSegs []string) bool {
+	if len(patternSegs) == 0 {
+		return len(pathSegs) == 0
+	}
+
+	if patternSegs[0] == "**" {
+		for i := 0; i <= len(pathSegs); i++ {
+			if matchSegments(patternSegs[1:], pathSegs[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(pathSegs) == 0 {
+		return false
+	}
+	if ok, err := path.Match(patternSegs[0], pathSegs[0]); err != nil || !ok {
+		return false
+	}
+	return matchSegments(patternSegs[1:], pathSegs[1:])
+}
+
+// isWorkflowFile checks if a filename is a workflow file (yml or yaml)
+func isWorkflowFile(filename string) bool {
+	if len(filename) < 5 {
+		return false
+	}
+
+	ext := filename[len(filename)-4:]
+	return ext == ".yml" || ext == "yaml"
+}
+
+// CreatePullRequest creates a pull request with the given changes against baseBranch, or the default branch when empty
+func (c *Client) CreatePullRequest(repo Repository, title, body, headBranch, baseBranch string) error {
+	if baseBranch == "" {
+		baseBranch = repo.DefaultBranch
+	}
+
+	newPR := &github.NewPullRequest{
+		Title: &title,
+		Head:  &headBranch,
+		Base:  &baseBranch,
+		Body:  &body,
+	}
+
+	_, _, err := c.client.PullRequests.Create(c.ctx, repo.Owner, repo.Name, newPR)
+	if err != nil {
+		return fmt.Errorf("failed to create pull request: %w", classifyTokenError(err))
+	}
+
+	return nil
+}
+
+// RequestReviewers asks users and teams to review a pull request
+// Teams are given as slugs of teams in the repository owner's organization.
+func (c *Client) RequestReviewers(repo Repository, number int, users, teams []string) error {
+	if c.verbose {
+		log.Printf("GitHub API: Requesting reviews from %d users and %d teams on %s#%d", len(users), len(teams), repo.FullName, number)
+	}
+
+	_, _, err := c.client.PullRequests.RequestReviewers(c.ctx, repo.Owner, repo.Name, number, github.ReviewersRequest{
+		Reviewers:     users,
+		TeamReviewers: teams,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to request reviewers: %w", classifyTokenError(err))
+	}
+
+	return nil
+}
+
+// CreateIssue opens an issue in a repository and returns its URL
+func (c *Client) CreateIssue(owner, repo, title, body string) (string, error) {
+	if c.verbose {
+		log.Printf("GitHub API: Creating issue '%s' in %s/%s", title, owner, repo)
+	}
+
+	issue, _, err := c.client.Issues.Create(c.ctx, owner, repo, &github.IssueRequest{
+		Title: &title,
+		Body:  &body,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create issue: %w", classifyTokenError(err))
+	}
+
+	return issue.GetHTMLURL(), nil
+}
+
+// CreateIssueComment comments on an issue or pull request
+func (c *Client) CreateIssueComment(owner, repo string, number int, body string) error {
+	if c.verbose {
+		log.Printf("GitHub API: Commenting on #%d in %s/%s", number, owner, repo)
+	}
+
+	if _, _, err := c.client.Issues.CreateComment(c.ctx, owner, repo, number, &github.IssueComment{Body: &body}); err != nil {
+		return fmt.Errorf("failed to comment on #%d: %w", number, classifyTokenError(err))
+	}
+	return nil
+}
+
+// SecurityAdvisory is a repository security advisory about an action
+type SecurityAdvisory struct {
+	Summary         string
+	Description     string
+	Severity        string // low, medium, high, or critical
+	Package         string // Affected action, "owner/name"
+	PatchedVersions string // Version consumers should move to, if any
+}
+
+// securityAdvisoryResponse holds the fields read from the repository security advisory endpoints
+type securityAdvisoryResponse struct {
+	Summary string `json:"summary"`
+	State   string `json:"state"`
+	HTMLURL string `json:"html_url"`
+}
+
+// CreateSecurityAdvisory drafts a repository security advisory, visible only to the repository's
+// administrators and security managers until it is published, and returns its URL
+func (c *Client) CreateSecurityAdvisory(owner, repo string, advisory SecurityAdvisory) (string, error) {
+	if c.verbose {
+		log.Printf("GitHub API: Drafting security advisory '%s' in %s/%s", advisory.Summary, owner, repo)
+	}
+
+	vulnerability := map[string]interface{}{
+		"package": map[string]string{"ecosystem": "actions", "name": advisory.Package},
+	}
+	if advisory.PatchedVersions != "" {
+		vulnerability["patched_versions"] = advisory.PatchedVersions
+	}
+	body := map[string]interface{}{
+		"summary":         advisory.Summary,
+		"description":     advisory.Description,
+		"severity":        advisory.Severity,
+		"vulnerabilities": []interface{}{vulnerability},
+	}
+
+	req, err := c.client.NewRequest("POST", fmt.Sprintf("repos/%s/%s/security-advisories", owner, repo), body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create security advisory request: %w", err)
+	}
+	var created securityAdvisoryResponse
+	if _, err := c.client.Do(c.ctx, req, &created); err != nil {
+		return "", fmt.Errorf("failed to draft security advisory: %w", classifyTokenError(err))
+	}
+	return created.HTMLURL, nil
+}
+
+// FindSecurityAdvisory returns the URL of a draft or triaged security advisory with the given summary,
+// or "" when there is none
+func (c *Client) FindSecurityAdvisory(owner, repo, summary string) (string, error) {
+	if c.verbose {
+		log.Printf("GitHub API: Looking for security advisory '%s' in %s/%s", summary, owner, repo)
+	}
+
+	for page := 1; page != 0; {
+		req, err := c.client.NewRequest("GET", fmt.Sprintf("repos/%s/%s/security-advisories?per_page=100&page=%d", owner, repo, page), nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to create security advisory request: %w", err)
+		}
+		var advisories []securityAdvisoryResponse
+		resp, err := c.client.Do(c.ctx, req, &advisories)
+		if err != nil {
+			return "", fmt.Errorf("failed to list security advisories: %w", classifyTokenError(err))
+		}
+
+		for _, advisory := range advisories {
+			if (advisory.State == "draft" || advisory.State == "triage") && advisory.Summary == summary {
+				return advisory.HTMLURL, nil
+			}
+		}
+		page = resp.NextPage
+	}
+
+	return "", nil
+}
+
+// FindOpenIssue returns the URL of an open issue with the given title, or "" when there is none
+func (c *Client) FindOpenIssue(owner, repo, title string) (string, error) {
+	if c.verbose {
+		log.Printf("GitHub API: Looking for open issue '%s' in %s/%s", title, owner, repo)
+	}
+
+	opts := &github.IssueListByRepoOptions{
+		State:       "open",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	for {
+		issues, resp, err := c.client.Issues.ListByRepo(c.ctx, owner, repo, opts)
+		if err != nil {
+			return "", fmt.Errorf("failed to list issues: %w", classifyTokenError(err))
+		}
+
+		for _, issue := range issues {
+			// The issues endpoint also lists pull requests
+			if !issue.IsPullRequest() && issue.GetTitle() == title {
+				return issue.GetHTMLURL(), nil
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return "", nil
+}
+
+// ListBranches returns the names of branches in a repository that start with prefix
+func (c *Client) ListBranches(owner, repo, prefix string) ([]string, error) {
+	if c.verbose {
+		log.Printf("GitHub API: Listing branches matching '%s' in %s/%s", prefix, owner, repo)
+	}
+
+	opts := &github.ReferenceListOptions{
+		Ref:         "heads/" + prefix,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var branches []string
+	for {
+		refs, resp, err := c.client.Git.ListMatchingRefs(c.ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list branches: %w", classifyTokenError(err))
+		}
+
+		for _, ref := range refs {
+			branches = append(branches, strings.TrimPrefix(ref.GetRef(), "refs/heads/"))
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return branches, nil
+}
+
+// ListBranchPullRequests returns every pull request, open or closed, opened from a branch
+func (c *Client) ListBranchPullRequests(owner, repo, branch string) ([]PullRequestInfo, error) {
+	if c.verbose {
+		log.Printf("GitHub API: Listing pull requests from branch '%s' in %s/%s", branch, owner, repo)
+	}
+
+	opts := &github.PullRequestListOptions{
+		State:       "all",
+		Head:        owner + ":" + branch,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var pulls []PullRequestInfo
+	for {
+		prs, resp, err := c.client.PullRequests.List(c.ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pull requests: %w", classifyTokenError(err))
+		}
+
+		for _, pr := range prs {
+			pulls = append(pulls, pullRequestInfo(pr))
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return pulls, nil
+}
+
+// ListOpenPullRequests returns the open pull requests of a repository
+func (c *Client) ListOpenPullRequests(owner, repo string) ([]PullRequestInfo, error) {
+	if c.verbose {
+		log.Printf("GitHub API: Listing open pull requests in %s/%s", owner, repo)
+	}
+
+	opts := &github.PullRequestListOptions{
+		State:       "open",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var pulls []PullRequestInfo
+	for {
+		prs, resp, err := c.client.PullRequests.List(c.ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pull requests: %w", classifyTokenError(err))
+		}
+
+		for _, pr := range prs {
+			pulls = append(pulls, pullRequestInfo(pr))
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return pulls, nil
+}
+
+// GetPullRequest returns a pull request of a repository by number
+func (c *Client) GetPullRequest(owner, repo string, number int) (*PullRequestInfo, error) {
+	if c.verbose {
+		log.Printf("GitHub API: Getting pull request #%d in %s/%s", number, owner, repo)
+	}
+
+	pr, _, err := c.client.PullRequests.Get(c.ctx, owner, repo, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request #%d: %w", number, classifyTokenError(err))
+	}
+	info := pullRequestInfo(pr)
+	return &info, nil
+}
+
+// pullRequestInfo summarizes a pull request from the list endpoint
+func pullRequestInfo(pr *github.PullRequest) PullRequestInfo {
+	// The list endpoint omits "merged"; a merge time is set only for merged pull requests
+	return PullRequestInfo{
+		Number:     pr.GetNumber(),
+		State:      pr.GetState(),
+		Merged:     pr.MergedAt != nil,
+		URL:        pr.GetHTMLURL(),
+		Title:      pr.GetTitle(),
+		Author:     pr.GetUser().GetLogin(),
+		HeadBranch: pr.GetHead().GetRef(),
+		HeadSHA:    pr.GetHead().GetSHA(),
+		CreatedAt:  pr.GetCreatedAt().Time,
+		MergedAt:   pr.GetMergedAt().Time,
+	}
+}
+
+// ListPullRequestFiles returns the files a pull request changes, with their diffs
+func (c *Client) ListPullRequestFiles(owner, repo string, number int) ([]PullRequestFile, error) {
+	if c.verbose {
+		log.Printf("GitHub API: Listing files of pull request #%d in %s/%s", number, owner, repo)
+	}
+
+	opts := &github.ListOptions{PerPage: 100}
+	var files []PullRequestFile
+	for {
+		page, resp, err := c.client.PullRequests.ListFiles(c.ctx, owner, repo, number, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files of pull request #%d: %w", number, classifyTokenError(err))
+		}
+
+		for _, file := range page {
+			files = append(files, PullRequestFile{Path: file.GetFilename(), Patch: file.GetPatch()})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return files, nil
+}
+
+// DeleteBranch deletes a branch from a repository
+func (c *Client) DeleteBranch(owner, repo, branch string) error {
+	if c.verbose {
+		log.Printf("GitHub API: Deleting branch '%s' in %s/%s", branch, owner, repo)
+	}
+
+	if _, err := c.client.Git.DeleteRef(c.ctx, owner, repo, "heads/"+branch); err != nil {
+		return fmt.Errorf("failed to delete branch %s: %w", branch, classifyTokenError(err))
+	}
+
+	return nil
+}
+
+// ResolveRef resolves a git reference (tag, branch, or SHA) to a commit SHA
+// When GitHub answers every lookup with not found, the error wraps ErrRefNotFound: a workflow
+// using the reference fails at runtime. Other failures, such as rate limits, do not.
+func (c *Client) ResolveRef(owner, repo, ref string) (string, error) {
+	// Try to get the reference directly
+	gitRef, tagResp, err := c.client.Git.GetRef(c.ctx, owner, repo, "refs/tags/"+ref)
+	if err == nil && gitRef.Object != nil {
+		return gitRef.Object.GetSHA(), nil
+	}
+
+	// Try as a branch reference
+	gitRef, branchResp, err := c.client.Git.GetRef(c.ctx, owner, repo, "refs/heads/"+ref)
+	if err == nil && gitRef.Object != nil {
+		return gitRef.Object.GetSHA(), nil
+	}
+
+	// Try to get commit directly (if ref is already a SHA)
+	commit, commitResp, err := c.client.Git.GetCommit(c.ctx, owner, repo, ref)
 	if err == nil {
 		return commit.GetSHA(), nil
 	}
 
+	// A ref that is not a commit SHA is rejected as unprocessable rather than not found
+	if isStatus(tagResp, http.StatusNotFound) && isStatus(branchResp, http.StatusNotFound) &&
+		(isStatus(commitResp, http.StatusNotFound) || isStatus(commitResp, http.StatusUnprocessableEntity)) {
+		return "", fmt.Errorf("could not resolve reference %s in %s/%s: %w", ref, owner, repo, ErrRefNotFound)
+	}
+
 	return "", fmt.Errorf("could not resolve reference %s in %s/%s", ref, owner, repo)
 }
 
-// GetTagsForRepo gets all tags for a repository and returns them with their commit SHAs
+// isStatus reports whether a response has the given status code
+func isStatus(resp *github.Response, status int) bool {
+	return resp != nil && resp.Response != nil && resp.StatusCode == status
+}
+
+// GetTagsForRepo gets the tags of a repository and returns them with their commit SHAs
+// With a page limit set, only the first pages are listed. GitHub lists the highest tag names first,
+// so for actions with thousands of tags the recent releases are kept and old ones left out.
 func (c *Client) GetTagsForRepo(owner, repo string) (map[string]string, error) {
 	tags := make(map[string]string)
 
@@ -473,7 +1374,7 @@ func (c *Client) GetTagsForRepo(owner, repo string) (map[string]string, error) {
 		PerPage: 100,
 	}
 
-	for {
+	for pages := 1; ; pages++ {
 		repoTags, resp, err := c.client.Repositories.ListTags(c.ctx, owner, repo, opts)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list tags: %w", err)
@@ -485,6 +1386,48 @@ func (c *Client) GetTagsForRepo(owner, repo string) (map[string]string, error) {
 			}
 		}
 
+		if resp.NextPage == 0 {
+			break
+		}
+		if c.maxTagPages > 0 && pages >= c.maxTagPages {
+			if c.verbose {
+				log.Printf("GitHub API: Stopped listing tags of %s/%s after %d pages (%d tags)", owner, repo, pages, len(tags))
+			}
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return tags, nil
+}
+
+// GetMatchingTags returns the tags of a repository whose names start with prefix, with the SHAs
+// they point at
+// It lists matching refs rather than every tag, so finding the aliases of v4 in an action with
+// thousands of tags takes one request. Like ResolveRef, annotated tags map to their tag object.
+func (c *Client) GetMatchingTags(owner, repo, prefix string) (map[string]string, error) {
+	if c.verbose {
+		log.Printf("GitHub API: Listing tags matching '%s' in %s/%s", prefix, owner, repo)
+	}
+
+	opts := &github.ReferenceListOptions{
+		Ref:         "tags/" + prefix,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	tags := make(map[string]string)
+	for {
+		refs, resp, err := c.client.Git.ListMatchingRefs(c.ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list matching tags: %w", classifyTokenError(err))
+		}
+
+		for _, ref := range refs {
+			if ref.Object != nil && ref.Object.SHA != nil {
+				tags[strings.TrimPrefix(ref.GetRef(), "refs/tags/")] = ref.Object.GetSHA()
+			}
+		}
+
 		if resp.NextPage == 0 {
 			break
 		}
@@ -494,6 +1437,384 @@ func (c *Client) GetTagsForRepo(owner, repo string) (map[string]string, error) {
 	return tags, nil
 }
 
+// GetTagRulesets returns the active tag rulesets applying to a repository
+// The list endpoint omits conditions and rules, so each tag ruleset is fetched individually.
+func (c *Client) GetTagRulesets(owner, repo string) ([]TagRuleset, error) {
+	if c.verbose {
+		log.Printf("GitHub API: Listing rulesets for %s/%s", owner, repo)
+	}
+
+	rulesets, _, err := c.client.Repositories.GetAllRulesets(c.ctx, owner, repo, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rulesets: %w", classifyTokenError(err))
+	}
+
+	var tagRulesets []TagRuleset
+	for _, summary := range rulesets {
+		if summary.GetTarget() != "tag" || summary.Enforcement != "active" {
+			continue
+		}
+
+		ruleset, _, err := c.client.Repositories.GetRuleset(c.ctx, owner, repo, summary.GetID(), true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ruleset %q: %w", summary.Name, classifyTokenError(err))
+		}
+
+		tagRuleset := TagRuleset{Name: ruleset.Name}
+		if refName := ruleset.GetConditions().GetRefName(); refName != nil {
+			tagRuleset.Include = refName.Include
+			tagRuleset.Exclude = refName.Exclude
+		}
+		for _, rule := range ruleset.Rules {
+			tagRuleset.Rules = append(tagRuleset.Rules, rule.Type)
+		}
+		tagRulesets = append(tagRulesets, tagRuleset)
+	}
+
+	return tagRulesets, nil
+}
+
+// GetRepositoryStatus returns whether a repository is archived along with its description and README
+func (c *Client) GetRepositoryStatus(owner, repo string) (*RepositoryStatus, error) {
+	if c.verbose {
+		log.Printf("GitHub API: Getting repository status for %s/%s", owner, repo)
+	}
+
+	repository, _, err := c.client.Repositories.Get(c.ctx, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository: %w", classifyTokenError(err))
+	}
+	status := &RepositoryStatus{
+		Archived:    repository.GetArchived(),
+		Description: repository.GetDescription(),
+	}
+
+	readme, resp, err := c.client.Repositories.GetReadme(c.ctx, owner, repo, nil)
+	if err != nil {
+		if resp != nil && resp.StatusCode == 404 {
+			return status, nil
+		}
+		return nil, fmt.Errorf("failed to get README: %w", classifyTokenError(err))
+	}
+	content, err := readme.GetContent()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode README: %w", err)
+	}
+	status.Readme = content
+
+	return status, nil
+}
+
+// GetRepositoryActivity returns the open issue and pull request counts and the latest release of a repository
+func (c *Client) GetRepositoryActivity(owner, repo string) (*RepositoryActivity, error) {
+	if c.verbose {
+		log.Printf("GitHub API: Getting repository activity for %s/%s", owner, repo)
+	}
+
+	repository, _, err := c.client.Repositories.Get(c.ctx, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository: %w", classifyTokenError(err))
+	}
+	pulls, err := c.ListOpenPullRequests(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	// GitHub counts open pull requests as open issues
+	activity := &RepositoryActivity{
+		Archived:         repository.GetArchived(),
+		OpenIssues:       max(repository.GetOpenIssuesCount()-len(pulls), 0),
+		OpenPullRequests: len(pulls),
+	}
+
+	release, resp, err := c.client.Repositories.GetLatestRelease(c.ctx, owner, repo)
+	if err != nil {
+		if resp != nil && resp.StatusCode == 404 {
+			return activity, nil
+		}
+		return nil, fmt.Errorf("failed to get latest release: %w", classifyTokenError(err))
+	}
+	activity.LatestRelease = release.GetTagName()
+	activity.LatestReleaseDate = release.GetPublishedAt().Time
+
+	return activity, nil
+}
+
+// GetLatestRelease returns the tag of the latest release of a repository, or "" if it has no releases
+func (c *Client) GetLatestRelease(owner, repo string) (string, error) {
+	if c.verbose {
+		log.Printf("GitHub API: Getting latest release for %s/%s", owner, repo)
+	}
+
+	release, resp, err := c.client.Repositories.GetLatestRelease(c.ctx, owner, repo)
+	if err != nil {
+		if resp != nil && resp.StatusCode == 404 {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get latest release: %w", classifyTokenError(err))
+	}
+	return release.GetTagName(), nil
+}
+
+// GetReleaseDate returns when a version of an action was published
+// Tags with a GitHub release use the release publish date; other tags, branches, and SHAs use the commit date.
+func (c *Client) GetReleaseDate(owner, repo, ref string) (time.Time, error) {
+	if c.verbose {
+		log.Printf("GitHub API: Getting release date for %s/%s@%s", owner, repo, ref)
+	}
+
+	if !isFullSHA(ref) {
+		release, _, err := c.client.Repositories.GetReleaseByTag(c.ctx, owner, repo, ref)
+		if err == nil && !release.GetPublishedAt().IsZero() {
+			return release.GetPublishedAt().Time, nil
+		}
+		if c.verbose && err != nil {
+			log.Printf("No release found for %s/%s@%s, falling back to commit date: %v", owner, repo, ref, err)
+		}
+	}
+
+	commit, _, err := c.client.Repositories.GetCommit(c.ctx, owner, repo, ref, nil)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get commit for %s/%s@%s: %w", owner, repo, ref, err)
+	}
+
+	date := commit.GetCommit().GetCommitter().GetDate()
+	if date.IsZero() {
+		return time.Time{}, fmt.Errorf("no commit date available for %s/%s@%s", owner, repo, ref)
+	}
+
+	return date.Time, nil
+}
+
+// GetLastWorkflowRun returns when a workflow file last ran, and false if it never has
+// Only files in .github/workflows are registered as workflows by GitHub Actions.
+func (c *Client) GetLastWorkflowRun(owner, repo, filePath string) (time.Time, bool, error) {
+	if path.Dir(filePath) != ".github/workflows" {
+		return time.Time{}, false, fmt.Errorf("workflow runs are only tracked for files in .github/workflows: %s", filePath)
+	}
+
+	if c.verbose {
+		log.Printf("GitHub API: Getting last run of workflow %s in %s/%s", filePath, owner, repo)
+	}
+
+	runs, _, err := c.client.Actions.ListWorkflowRunsByFileName(c.ctx, owner, repo, path.Base(filePath), &github.ListWorkflowRunsOptions{
+		ListOptions: github.ListOptions{PerPage: 1},
+	})
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to list workflow runs for %s: %w", filePath, err)
+	}
+
+	// Runs are returned newest first
+	if len(runs.WorkflowRuns) == 0 {
+		return time.Time{}, false, nil
+	}
+	return runs.WorkflowRuns[0].GetCreatedAt().Time, true, nil
+}
+
+// ListWorkflowRunTimes returns the start times of a workflow file's runs created on or after since
+func (c *Client) ListWorkflowRunTimes(owner, repo, filePath string, since time.Time) ([]time.Time, error) {
+	runs, err := c.ListWorkflowRuns(owner, repo, filePath, since)
+	if err != nil {
+		return nil, err
+	}
+
+	times := make([]time.Time, 0, len(runs))
+	for _, run := range runs {
+		times = append(times, run.CreatedAt)
+	}
+	return times, nil
+}
+
+// ListWorkflowRuns returns a workflow file's runs created on or after since, newest first
+func (c *Client) ListWorkflowRuns(owner, repo, filePath string, since time.Time) ([]WorkflowRun, error) {
+	if path.Dir(filePath) != ".github/workflows" {
+		return nil, fmt.Errorf("workflow runs are only tracked for files in .github/workflows: %s", filePath)
+	}
+
+	if c.verbose {
+		log.Printf("GitHub API: Listing runs of workflow %s in %s/%s since %s", filePath, owner, repo, since.Format("2006-01-02"))
+	}
+
+	opts := &github.ListWorkflowRunsOptions{
+		Created:     ">=" + since.Format("2006-01-02"),
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var runs []WorkflowRun
+	for {
+		page, resp, err := c.client.Actions.ListWorkflowRunsByFileName(c.ctx, owner, repo, path.Base(filePath), opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list workflow runs for %s: %w", filePath, err)
+		}
+
+		for _, run := range page.WorkflowRuns {
+			runs = append(runs, WorkflowRun{ID: run.GetID(), CreatedAt: run.GetCreatedAt().Time})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return runs, nil
+}
+
+// DispatchWorkflow triggers a workflow_dispatch run of a workflow file on ref, without inputs
+func (c *Client) DispatchWorkflow(owner, repo, filePath, ref string) error {
+	if path.Dir(filePath) != ".github/workflows" {
+		return fmt.Errorf("only workflows in .github/workflows can be dispatched: %s", filePath)
+	}
+
+	if c.verbose {
+		log.Printf("GitHub API: Dispatching workflow %s in %s/%s on %s", filePath, owner, repo, ref)
+	}
+
+	_, err := c.client.Actions.CreateWorkflowDispatchEventByFileName(c.ctx, owner, repo, path.Base(filePath), github.CreateWorkflowDispatchEventRequest{Ref: ref})
+	if err != nil {
+		return fmt.Errorf("failed to dispatch %s: %w", filePath, classifyTokenError(err))
+	}
+	return nil
+}
+
+// ListDispatchRuns returns the workflow_dispatch runs on a branch, newest first
+func (c *Client) ListDispatchRuns(owner, repo, branch string) ([]DispatchRun, error) {
+	if c.verbose {
+		log.Printf("GitHub API: Listing workflow_dispatch runs on %s in %s/%s", branch, owner, repo)
+	}
+
+	runs, _, err := c.client.Actions.ListRepositoryWorkflowRuns(c.ctx, owner, repo, &github.ListWorkflowRunsOptions{
+		Branch:      branch,
+		Event:       "workflow_dispatch",
+		ListOptions: github.ListOptions{PerPage: 100},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflow runs on %s: %w", branch, classifyTokenError(err))
+	}
+
+	dispatched := make([]DispatchRun, 0, len(runs.WorkflowRuns))
+	for _, run := range runs.WorkflowRuns {
+		// Paths of some runs carry the ref they ran at, e.g. ".github/workflows/ci.yml@refs/heads/main"
+		filePath, _, _ := strings.Cut(run.GetPath(), "@")
+		dispatched = append(dispatched, DispatchRun{
+			ID:         run.GetID(),
+			FilePath:   filePath,
+			Status:     run.GetStatus(),
+			Conclusion: run.GetConclusion(),
+			URL:        run.GetHTMLURL(),
+			CreatedAt:  run.GetCreatedAt().Time,
+		})
+	}
+	return dispatched, nil
+}
+
+// GetWorkflowRunBillableMS returns the billable milliseconds of a workflow run per runner environment
+// (e.g. "UBUNTU", "WINDOWS", "MACOS"). Runs of public repositories and self-hosted runners are not billed.
+func (c *Client) GetWorkflowRunBillableMS(owner, repo string, runID int64) (map[string]int64, error) {
+	if c.verbose {
+		log.Printf("GitHub API: Getting timing of workflow run %d in %s/%s", runID, owner, repo)
+	}
+
+	usage, _, err := c.client.Actions.GetWorkflowRunUsageByID(c.ctx, owner, repo, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timing of workflow run %d: %w", runID, err)
+	}
+
+	billable := make(map[string]int64)
+	if usage.Billable != nil {
+		for environment, bill := range *usage.Billable {
+			if bill != nil {
+				billable[environment] = bill.GetTotalMS()
+			}
+		}
+	}
+	return billable, nil
+}
+
+// GetSecretInventory lists the names, never the values, of the repository and organization secrets and
+// variables a repository's workflows can read. Listing secrets needs the Secrets read permission.
+func (c *Client) GetSecretInventory(owner, repo string) (*SecretInventory, error) {
+	if c.verbose {
+		log.Printf("GitHub API: Listing secret and variable names for %s/%s", owner, repo)
+	}
+
+	inventory := &SecretInventory{}
+	var err error
+	if inventory.RepoSecrets, err = c.listSecretNames(func(opts *github.ListOptions) (*github.Secrets, *github.Response, error) {
+		return c.client.Actions.ListRepoSecrets(c.ctx, owner, repo, opts)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list repository secrets: %w", classifyTokenError(err))
+	}
+	if inventory.OrgSecrets, err = c.listSecretNames(func(opts *github.ListOptions) (*github.Secrets, *github.Response, error) {
+		return c.client.Actions.ListRepoOrgSecrets(c.ctx, owner, repo, opts)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list organization secrets: %w", classifyTokenError(err))
+	}
+	if inventory.RepoVariables, err = c.listVariableNames(func(opts *github.ListOptions) (*github.ActionsVariables, *github.Response, error) {
+		return c.client.Actions.ListRepoVariables(c.ctx, owner, repo, opts)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list repository variables: %w", classifyTokenError(err))
+	}
+	if inventory.OrgVariables, err = c.listVariableNames(func(opts *github.ListOptions) (*github.ActionsVariables, *github.Response, error) {
+		return c.client.Actions.ListRepoOrgVariables(c.ctx, owner, repo, opts)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list organization variables: %w", classifyTokenError(err))
+	}
+
+	return inventory, nil
+}
+
+// listSecretNames collects secret names across pages
+func (c *Client) listSecretNames(list func(*github.ListOptions) (*github.Secrets, *github.Response, error)) ([]string, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	var names []string
+	for {
+		secrets, resp, err := list(opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, secret := range secrets.Secrets {
+			names = append(names, secret.Name)
+		}
+		if resp.NextPage == 0 {
+			return names, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// listVariableNames collects variable names across pages
+func (c *Client) listVariableNames(list func(*github.ListOptions) (*github.ActionsVariables, *github.Response, error)) ([]string, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	var names []string
+	for {
+		variables, resp, err := list(opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, variable := range variables.Variables {
+			names = append(names, variable.Name)
+		}
+		if resp.NextPage == 0 {
+			return names, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// isFullSHA reports whether a ref is a full 40-character commit SHA
+func isFullSHA(ref string) bool {
+	if len(ref) != 40 {
+		return false
+	}
+	for _, r := range ref {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
+		}
+	}
+	return true
+}
+
 // GetRepositoryCustomProperties fetches custom properties for a repository using the GitHub Custom Properties API
 func (c *Client) GetRepositoryCustomProperties(owner, repo string, properties []string) (map[string]string, error) {
 	if c.verbose {
@@ -507,6 +1828,10 @@ func (c *Client) GetRepositoryCustomProperties(owner, repo string, properties []
 		return customProperties, nil
 	}
 
+	if c.anonymous {
+		return customProperties, ErrAuthRequired
+	}
+
 	// Use the official GitHub Custom Properties API
 	if c.verbose {
 		log.Printf("GitHub API: GET /repos/%s/%s/properties/values (custom properties API)", owner, repo)
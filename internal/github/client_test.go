@@ -2,12 +2,14 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-github/v65/github"
 )
@@ -280,9 +282,11 @@ func TestPaginationLogic_SimulatedData(t *testing.T) {
 	}
 }
 
-// TestListRepositories_PaginationWithPartialFailure verifies that when pagination fails
-// on subsequent pages, we still return repositories from successful pages
+// TestListRepositories_PaginationWithPartialFailure verifies that when pagination keeps failing
+// on subsequent pages, the listing fails instead of returning the repositories of earlier pages
 func TestListRepositories_PaginationWithPartialFailure(t *testing.T) {
+	defer func(delay time.Duration) { listingRetryDelay = delay }(listingRetryDelay)
+	listingRetryDelay = 0
 	currentPage := 0
 
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -336,28 +340,155 @@ func TestListRepositories_PaginationWithPartialFailure(t *testing.T) {
 		verbose: true,
 	}
 
-	// Test that we get the first page even when subsequent pages fail
+	// A partial listing would silently leave repositories unscanned
 	repos, err := githubClient.ListRepositories("partialfail")
+	var pageErr *ListingPageError
+	if !errors.As(err, &pageErr) || pageErr.Page != 2 {
+		t.Fatalf("Expected a listing error on page 2, got %v with %d repositories", err, len(repos))
+	}
+
+	// Page 1 once, then page 2 on every attempt
+	if currentPage != 1+listingPageAttempts {
+		t.Errorf("Expected %d page requests, got %d", 1+listingPageAttempts, currentPage)
+	}
+}
+
+// TestGetWorkflowFilesInDirs_ReadsAtBranchHead verifies that every file is read at the commit the default
+// branch pointed at, and that blob and commit SHAs are recorded
+func TestGetWorkflowFilesInDirs_ReadsAtBranchHead(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/testorg/app/git/ref/heads/main":
+			w.Write([]byte(`{"ref": "refs/heads/main", "object": {"type": "commit", "sha": "commit123"}}`))
+
+		case "/repos/testorg/app/contents/.github/workflows":
+			if ref := r.URL.Query().Get("ref"); ref != "commit123" {
+				t.Errorf("Expected directory listing at commit123, got ref %q", ref)
+			}
+			w.Write([]byte(`[{"type": "file", "name": "ci.yml", "path": ".github/workflows/ci.yml", "sha": "blob456", "size": 9}]`))
+
+		case "/repos/testorg/app/contents/.github/workflows/ci.yml":
+			if ref := r.URL.Query().Get("ref"); ref != "commit123" {
+				t.Errorf("Expected file content at commit123, got ref %q", ref)
+			}
+			w.Write([]byte(`{"type": "file", "name": "ci.yml", "path": ".github/workflows/ci.yml", "sha": "blob456", "encoding": "base64", "content": "b246IHB1c2g="}`))
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+	githubClient := &Client{client: client, ctx: context.Background()}
+
+	files, err := githubClient.GetWorkflowFiles(Repository{Owner: "testorg", Name: "app", FullName: "testorg/app", DefaultBranch: "main"})
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
 	}
+	if len(files) != 1 {
+		t.Fatalf("Expected 1 workflow file, got %d", len(files))
+	}
+	if files[0].Content != "on: push" || files[0].SHA != "blob456" || files[0].CommitSHA != "commit123" {
+		t.Errorf("Unexpected workflow file: %+v", files[0])
+	}
+}
 
-	// Should have the 100 repositories from the first page
-	expectedTotal := 100
-	if len(repos) != expectedTotal {
-		t.Errorf("Expected %d repositories, got %d", expectedTotal, len(repos))
+// TestGetWorkflowFiles_ClassifiesRepositoriesWithoutWorkflows verifies that empty repositories and
+// repositories whose .github directory is a submodule are reported instead of looking like they have no workflows
+func TestGetWorkflowFiles_ClassifiesRepositoriesWithoutWorkflows(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/testorg/empty/git/ref/heads/main":
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"message": "Git Repository is empty."}`))
+
+		case "/repos/testorg/vendored/git/ref/heads/main", "/repos/testorg/plain/git/ref/heads/main":
+			w.Write([]byte(`{"ref": "refs/heads/main", "object": {"type": "commit", "sha": "commit123"}}`))
+
+		case "/repos/testorg/vendored/contents/.github":
+			w.Write([]byte(`{"type": "submodule", "name": ".github", "path": ".github", "sha": "sub789", "submodule_git_url": "https://github.com/testorg/shared-github.git"}`))
+
+		case "/repos/testorg/plain/contents/.github":
+			w.Write([]byte(`[{"type": "file", "name": "CODEOWNERS", "path": ".github/CODEOWNERS"}]`))
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+	githubClient := &Client{client: client, ctx: context.Background()}
+
+	tests := []struct {
+		name   string
+		reason string
+	}{
+		{"empty", ContentEmpty},
+		{"vendored", ContentSubmodule},
+		{"plain", ""},
 	}
+	for _, tt := range tests {
+		files, err := githubClient.GetWorkflowFiles(Repository{Owner: "testorg", Name: tt.name, FullName: "testorg/" + tt.name, DefaultBranch: "main"})
+		if reason := NoWorkflowsReason(err); reason != tt.reason {
+			t.Errorf("Expected %s to be classified as %q, got %q (%v)", tt.name, tt.reason, reason, err)
+		}
+		if tt.reason == "" && (err != nil || len(files) != 0) {
+			t.Errorf("Expected %s to have no workflow files and no error, got %d files, %v", tt.name, len(files), err)
+		}
+	}
+}
+
+// TestResolveRef_NotFound verifies that missing repositories and refs are told apart from other failures
+func TestResolveRef_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/testorg/action/git/ref/tags/v1":
+			w.Write([]byte(`{"ref": "refs/tags/v1", "object": {"type": "commit", "sha": "commit123"}}`))
+
+		case "/repos/testorg/action/git/commits/v9":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"message": "No commit found for SHA: v9"}`))
 
-	// Verify we attempted both pages
-	if currentPage != 2 {
-		t.Errorf("Expected 2 page requests, got %d", currentPage)
+		case "/repos/testorg/flaky/git/ref/tags/v1", "/repos/testorg/flaky/git/ref/heads/v1", "/repos/testorg/flaky/git/commits/v1":
+			w.WriteHeader(http.StatusInternalServerError)
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+	githubClient := &Client{client: client, ctx: context.Background()}
+
+	if sha, err := githubClient.ResolveRef("testorg", "action", "v1"); err != nil || sha != "commit123" {
+		t.Errorf("Expected v1 to resolve to commit123, got %q, %v", sha, err)
 	}
 
-	// Verify repository names are correct for first page
-	for i, repo := range repos {
-		expectedName := fmt.Sprintf("repo-%d", i+1)
-		if repo.Name != expectedName {
-			t.Errorf("Expected repo %d to be named '%s', got '%s'", i, expectedName, repo.Name)
+	tests := []struct {
+		repo     string
+		ref      string
+		notFound bool
+	}{
+		{"action", "v9", true},
+		{"deleted", "v1", true},
+		{"flaky", "v1", false},
+	}
+	for _, tt := range tests {
+		_, err := githubClient.ResolveRef("testorg", tt.repo, tt.ref)
+		if err == nil {
+			t.Fatalf("Expected %s@%s not to resolve", tt.repo, tt.ref)
+		}
+		if errors.Is(err, ErrRefNotFound) != tt.notFound {
+			t.Errorf("Expected %s@%s not found to be %t, got %v", tt.repo, tt.ref, tt.notFound, err)
 		}
 	}
 }
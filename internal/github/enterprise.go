@@ -0,0 +1,36 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// DefaultHost is the host of repositories scanned without an API URL
+const DefaultHost = "github.com"
+
+// ParseAPIURL checks a GitHub Enterprise Server API URL, such as https://ghe.example.com/api/v3, and
+// returns it with the trailing slash the API client needs
+func ParseAPIURL(apiURL string) (string, error) {
+	parsed, err := url.Parse(apiURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", fmt.Errorf("invalid API URL %q: expected an http or https URL such as https://ghe.example.com/api/v3", apiURL)
+	}
+	if !strings.HasSuffix(parsed.Path, "/") {
+		parsed.Path += "/"
+	}
+	return parsed.String(), nil
+}
+
+// APIHost returns the host repositories reached through an API URL live on: the URL's host name for
+// GitHub Enterprise Server, or DefaultHost when the URL is empty
+func APIHost(apiURL string) string {
+	if apiURL == "" {
+		return DefaultHost
+	}
+	parsed, err := url.Parse(apiURL)
+	if err != nil || parsed.Hostname() == "" {
+		return apiURL
+	}
+	return strings.TrimPrefix(parsed.Hostname(), "api.")
+}
@@ -0,0 +1,28 @@
+package github
+
+import "testing"
+
+func TestParseAPIURL(t *testing.T) {
+	apiURL, err := ParseAPIURL("https://ghe.example.com/api/v3")
+	if err != nil || apiURL != "https://ghe.example.com/api/v3/" {
+		t.Errorf("Expected the URL with a trailing slash, got %q (%v)", apiURL, err)
+	}
+
+	for _, invalid := range []string{"", "ghe.example.com", "ftp://ghe.example.com/api/v3", "https:///api/v3"} {
+		if _, err := ParseAPIURL(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestAPIHost(t *testing.T) {
+	for apiURL, expected := range map[string]string{
+		"":                               "github.com",
+		"https://ghe.example.com/api/v3": "ghe.example.com",
+		"https://api.acme.ghe.com":       "acme.ghe.com",
+	} {
+		if host := APIHost(apiURL); host != expected {
+			t.Errorf("Expected host %q for %q, got %q", expected, apiURL, host)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v65/github"
+)
+
+// ErrAuthRequired is returned by operations that are unavailable to anonymous clients
+var ErrAuthRequired = errors.New("this operation requires a GitHub token")
+
+// ErrRefNotFound is returned when a repository or ref does not exist, or is not visible to the token
+var ErrRefNotFound = errors.New("repository or ref not found")
+
+// IsRateLimited reports whether err is a GitHub rate limit error
+func IsRateLimited(err error) bool {
+	var rateLimitErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	return errors.As(err, &rateLimitErr) || errors.As(err, &abuseErr)
+}
+
+// TokenErrorKind classifies authentication and authorization failures returned by the GitHub API
+type TokenErrorKind string
+
+const (
+	// TokenErrorMissingScope indicates the token lacks a scope required for the operation (e.g., workflow)
+	TokenErrorMissingScope TokenErrorKind = "missing_scope"
+	// TokenErrorSSORequired indicates the token has not been authorized for the organization's SAML SSO
+	TokenErrorSSORequired TokenErrorKind = "sso_required"
+	// TokenErrorExpired indicates the token is expired, revoked, or otherwise invalid
+	TokenErrorExpired TokenErrorKind = "expired"
+)
+
+// TokenError is an actionable error describing why a token was rejected
+type TokenError struct {
+	Kind   TokenErrorKind
+	Scope  string // Missing scope, when known
+	SSOURL string // URL to authorize the token for SSO, when provided by GitHub
+	Err    error  // Underlying API error
+}
+
+// Error returns a message that tells the user how to fix the token problem
+func (e *TokenError) Error() string {
+	switch e.Kind {
+	case TokenErrorMissingScope:
+		scope := e.Scope
+		if scope == "" {
+			scope = "required"
+		}
+		return fmt.Sprintf("token is missing the '%s' scope. Classic tokens need the 'repo' and 'workflow' scopes to update workflow files; fine-grained tokens need 'Contents' and 'Workflows' read/write permissions (%v)", scope, e.Err)
+	case TokenErrorSSORequired:
+		if e.SSOURL != "" {
+			return fmt.Sprintf("token is not authorized for this organization's SAML SSO. Authorize it at %s (%v)", e.SSOURL, e.Err)
+		}
+		return fmt.Sprintf("token is not authorized for this organization's SAML SSO. Authorize it under your token settings via 'Configure SSO' (%v)", e.Err)
+	case TokenErrorExpired:
+		return fmt.Sprintf("token is expired, revoked, or invalid. Generate a new token and pass it via --token or GITHUB_TOKEN (%v)", e.Err)
+	default:
+		return e.Err.Error()
+	}
+}
+
+// Unwrap returns the underlying API error
+func (e *TokenError) Unwrap() error {
+	return e.Err
+}
+
+// classifyTokenError inspects a GitHub API error and returns a TokenError when the failure
+// is caused by the token itself. Other errors are returned unchanged.
+func classifyTokenError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var errResp *github.ErrorResponse
+	if !errors.As(err, &errResp) || errResp.Response == nil {
+		return err
+	}
+
+	resp := errResp.Response
+	message := strings.ToLower(errResp.Message)
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return &TokenError{Kind: TokenErrorExpired, Err: err}
+	case http.StatusForbidden, http.StatusNotFound, http.StatusUnprocessableEntity:
+		// GitHub signals SSO enforcement with the X-GitHub-SSO header
+		if sso := resp.Header.Get("X-GitHub-SSO"); sso != "" && strings.HasPrefix(sso, "required") {
+			return &TokenError{Kind: TokenErrorSSORequired, SSOURL: parseSSOURL(sso), Err: err}
+		}
+
+		// Pushing workflow files without the workflow scope is rejected with a descriptive message
+		if strings.Contains(message, "workflow") && strings.Contains(message, "scope") {
+			return &TokenError{Kind: TokenErrorMissingScope, Scope: "workflow", Err: err}
+		}
+
+		// Compare granted scopes against the scopes the endpoint accepts
+		if missing := missingScope(resp.Header); missing != "" {
+			return &TokenError{Kind: TokenErrorMissingScope, Scope: missing, Err: err}
+		}
+	}
+
+	return err
+}
+
+// parseSSOURL extracts the authorization URL from an X-GitHub-SSO header value
+// (e.g., "required; url=https://github.com/orgs/acme/sso?authorization_request=...")
+func parseSSOURL(header string) string {
+	for _, part := range strings.Split(header, ";") {
+		part = strings.TrimSpace(part)
+		if strings.HasPrefix(part, "url=") {
+			return strings.TrimPrefix(part, "url=")
+		}
+	}
+	return ""
+}
+
+// missingScope returns the first accepted scope that was not granted to the token, if any
+func missingScope(header http.Header) string {
+	accepted := parseScopes(header.Get("X-Accepted-OAuth-Scopes"))
+	if len(accepted) == 0 {
+		return ""
+	}
+
+	granted := make(map[string]bool)
+	for _, scope := range parseScopes(header.Get("X-OAuth-Scopes")) {
+		granted[scope] = true
+	}
+
+	for _, scope := range accepted {
+		if granted[scope] {
+			return "" // Any one accepted scope is sufficient
+		}
+	}
+	return accepted[0]
+}
+
+// parseScopes splits a comma separated scopes header into individual scopes
+func parseScopes(header string) []string {
+	var scopes []string
+	for _, scope := range strings.Split(header, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// Reasons a repository has no workflow files to scan
+const (
+	ContentEmpty     = "empty"     // The repository has no commits
+	ContentSubmodule = "submodule" // .github or a workflow directory is a git submodule, whose files the API does not serve
+)
+
+// NoWorkflowsError reports a repository whose workflow files cannot be read because of how it is laid out
+type NoWorkflowsError struct {
+	Repository string
+	Reason     string // ContentEmpty or ContentSubmodule
+	Path       string // Submodule path, for ContentSubmodule
+}
+
+func (e *NoWorkflowsError) Error() string {
+	if e.Reason == ContentSubmodule {
+		return fmt.Sprintf("%s is a git submodule in %s; its workflow files are not part of the repository", e.Path, e.Repository)
+	}
+	return fmt.Sprintf("repository %s is empty", e.Repository)
+}
+
+// NoWorkflowsReason returns the reason of a NoWorkflowsError, or an empty string for any other error
+func NoWorkflowsReason(err error) string {
+	var noWorkflows *NoWorkflowsError
+	if errors.As(err, &noWorkflows) {
+		return noWorkflows.Reason
+	}
+	return ""
+}
+
+// TokenInfo describes the token used by the client
+type TokenInfo struct {
+	Login       string    // Account the token acts as
+	Scopes      []string  // Scopes granted to a classic token
+	FineGrained bool      // True when GitHub reports no OAuth scopes (fine-grained PAT or app token)
+	ExpiresAt   time.Time // Zero when the token does not expire
+}
+
+// HasScope reports whether the token was granted the given scope
+func (t *TokenInfo) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTokenInfo performs a preflight request and reports the scopes and expiry of the client's token
+func (c *Client) GetTokenInfo() (*TokenInfo, error) {
+	// Installation tokens cannot read /user; the app's identity comes from its JWT, and its permissions
+	// are those the installation grants, not scopes
+	if c.app != nil {
+		login, err := c.app.login(c.ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &TokenInfo{Login: login, FineGrained: true}, nil
+	}
+
+	if c.verbose {
+		log.Printf("GitHub API: GET /user (token preflight)")
+	}
+
+	user, resp, err := c.client.Users.Get(c.ctx, "")
+	if err != nil {
+		return nil, classifyTokenError(err)
+	}
+
+	info := &TokenInfo{Login: user.GetLogin()}
+	if resp == nil || resp.Response == nil {
+		return info, nil
+	}
+
+	scopesHeader, hasScopes := resp.Header["X-Oauth-Scopes"]
+	if hasScopes && len(scopesHeader) > 0 {
+		info.Scopes = parseScopes(scopesHeader[0])
+	} else {
+		info.FineGrained = true
+	}
+
+	if expiry := resp.Header.Get("GitHub-Authentication-Token-Expiration"); expiry != "" {
+		if t, err := time.Parse("2006-01-02 15:04:05 MST", expiry); err == nil {
+			info.ExpiresAt = t
+		} else if t, err := time.Parse("2006-01-02 15:04:05 -0700", expiry); err == nil {
+			info.ExpiresAt = t
+		}
+	}
+
+	if c.verbose {
+		log.Printf("GitHub API: Token scopes: %v (fine-grained: %t, expires: %v)", info.Scopes, info.FineGrained, info.ExpiresAt)
+	}
+
+	return info, nil
+}
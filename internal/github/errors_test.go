@@ -0,0 +1,143 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v65/github"
+)
+
+// newErrorResponse builds a go-github error response with the given status, message and headers
+func newErrorResponse(status int, message string, headers map[string]string) error {
+	resp := &http.Response{StatusCode: status, Header: http.Header{}}
+	for key, value := range headers {
+		resp.Header.Set(key, value)
+	}
+	return &github.ErrorResponse{Response: resp, Message: message}
+}
+
+// TestClassifyTokenError verifies that token failures are mapped to actionable error kinds
+func TestClassifyTokenError(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedKind TokenErrorKind
+		expectedHint string
+	}{
+		{
+			name:         "expired token",
+			err:          newErrorResponse(http.StatusUnauthorized, "Bad credentials", nil),
+			expectedKind: TokenErrorExpired,
+			expectedHint: "expired",
+		},
+		{
+			name: "sso not authorized",
+			err: newErrorResponse(http.StatusForbidden, "Resource protected by organization SAML enforcement", map[string]string{
+				"X-GitHub-SSO": "required; url=https://github.com/orgs/acme/sso?authorization_request=abc",
+			}),
+			expectedKind: TokenErrorSSORequired,
+			expectedHint: "https://github.com/orgs/acme/sso?authorization_request=abc",
+		},
+		{
+			name:         "missing workflow scope",
+			err:          newErrorResponse(http.StatusNotFound, "refusing to allow a Personal Access Token to create or update workflow `.github/workflows/ci.yml` without `workflow` scope", nil),
+			expectedKind: TokenErrorMissingScope,
+			expectedHint: "'workflow' scope",
+		},
+		{
+			name: "missing accepted scope",
+			err: newErrorResponse(http.StatusForbidden, "Resource not accessible by integration", map[string]string{
+				"X-Accepted-OAuth-Scopes": "repo",
+				"X-OAuth-Scopes":          "read:org",
+			}),
+			expectedKind: TokenErrorMissingScope,
+			expectedHint: "'repo' scope",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyTokenError(fmt.Errorf("wrapped: %w", tt.err))
+
+			var tokenErr *TokenError
+			if !errors.As(err, &tokenErr) {
+				t.Fatalf("Expected TokenError, got %T: %v", err, err)
+			}
+			if tokenErr.Kind != tt.expectedKind {
+				t.Errorf("Expected kind %s, got %s", tt.expectedKind, tokenErr.Kind)
+			}
+			if !strings.Contains(tokenErr.Error(), tt.expectedHint) {
+				t.Errorf("Expected message to contain %q, got %q", tt.expectedHint, tokenErr.Error())
+			}
+			if !errors.Is(err, tt.err) {
+				t.Error("Expected TokenError to unwrap to the original API error")
+			}
+		})
+	}
+}
+
+// TestClassifyTokenError_PassesThroughOtherErrors verifies unrelated errors are not reclassified
+func TestClassifyTokenError_PassesThroughOtherErrors(t *testing.T) {
+	serverErr := newErrorResponse(http.StatusInternalServerError, "Internal server error", nil)
+	if err := classifyTokenError(serverErr); err != serverErr {
+		t.Errorf("Expected server error to pass through unchanged, got %v", err)
+	}
+
+	plainErr := errors.New("network unreachable")
+	if err := classifyTokenError(plainErr); err != plainErr {
+		t.Errorf("Expected plain error to pass through unchanged, got %v", err)
+	}
+
+	// Granted scope satisfies the accepted scopes, so this is not a token problem
+	grantedErr := newErrorResponse(http.StatusNotFound, "Not Found", map[string]string{
+		"X-Accepted-OAuth-Scopes": "repo",
+		"X-OAuth-Scopes":          "repo, workflow",
+	})
+	if err := classifyTokenError(grantedErr); err != grantedErr {
+		t.Errorf("Expected error with sufficient scopes to pass through unchanged, got %v", err)
+	}
+}
+
+func TestIsRateLimited(t *testing.T) {
+	if !IsRateLimited(fmt.Errorf("listing: %w", &github.RateLimitError{Message: "API rate limit exceeded"})) {
+		t.Errorf("Expected wrapped rate limit error to be detected")
+	}
+	if !IsRateLimited(&github.AbuseRateLimitError{Message: "secondary rate limit"}) {
+		t.Errorf("Expected secondary rate limit error to be detected")
+	}
+	if IsRateLimited(newErrorResponse(http.StatusNotFound, "Not Found", nil)) {
+		t.Errorf("Expected other API errors not to be treated as rate limits")
+	}
+}
+
+func TestNoWorkflowsReason(t *testing.T) {
+	err := fmt.Errorf("scanning: %w", &NoWorkflowsError{Repository: "my-org/legacy", Reason: ContentSubmodule, Path: ".github"})
+	if reason := NoWorkflowsReason(err); reason != ContentSubmodule {
+		t.Errorf("Expected %s, got %q", ContentSubmodule, reason)
+	}
+	if !strings.Contains(err.Error(), ".github is a git submodule") {
+		t.Errorf("Expected the error to name the submodule, got %q", err.Error())
+	}
+	if reason := NoWorkflowsReason(newErrorResponse(http.StatusNotFound, "Not Found", nil)); reason != "" {
+		t.Errorf("Expected other errors to have no reason, got %q", reason)
+	}
+}
+
+func TestAnonymousClient_CustomPropertiesRequireAuth(t *testing.T) {
+	client := NewClientWithConfig("", &Config{})
+	if !client.IsAnonymous() {
+		t.Fatalf("Expected client without a token to be anonymous")
+	}
+
+	_, err := client.GetRepositoryCustomProperties("my-org", "api", []string{"Team"})
+	if !errors.Is(err, ErrAuthRequired) {
+		t.Errorf("Expected ErrAuthRequired, got %v", err)
+	}
+
+	if NewClientWithConfig("token", &Config{}).IsAnonymous() {
+		t.Errorf("Expected client with a token not to be anonymous")
+	}
+}
@@ -0,0 +1,173 @@
+package github
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotRecorded is returned when replaying a request that has no recorded response
+var ErrNotRecorded = errors.New("no recorded response")
+
+// fixture is a recorded API response, kept as one JSON file per request in a fixture directory
+type fixture struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+// unrecordedHeaders are response headers left out of fixtures
+var unrecordedHeaders = []string{"Set-Cookie"}
+
+// recordTransport sends requests and saves each response to a fixture directory
+type recordTransport struct {
+	base http.RoundTripper
+	dir  string
+}
+
+// NewRecordTransport wraps a transport so every API response is also saved to a fixture directory
+// for later replay. Only responses are saved; request headers, including the token, never are.
+func NewRecordTransport(base http.RoundTripper, dir string) (http.RoundTripper, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &recordTransport{base: base, dir: dir}, nil
+}
+
+// RoundTrip sends the request and records its response before returning it
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key, err := fixtureKey(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response to record: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	header := resp.Header.Clone()
+	for _, name := range unrecordedHeaders {
+		header.Del(name)
+	}
+	recorded := fixture{Method: req.Method, URL: req.URL.String(), Status: resp.StatusCode, Header: header, Body: string(body)}
+	if err := writeFixture(filepath.Join(t.dir, key+".json"), recorded); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// writeFixture saves a fixture atomically, so concurrent requests for the same URL never leave a partial file
+func writeFixture(path string, recorded fixture) error {
+	data, err := json.MarshalIndent(recorded, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fixture: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".fixture-*")
+	if err != nil {
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	return nil
+}
+
+// replayTransport answers requests from a fixture directory without network access
+type replayTransport struct {
+	dir string
+}
+
+// NewReplayTransport returns a transport that answers requests with the responses recorded in a
+// fixture directory. Requests without a recorded response fail with ErrNotRecorded.
+func NewReplayTransport(dir string) (http.RoundTripper, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read fixture directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("fixture path %s is not a directory", dir)
+	}
+	return &replayTransport{dir: dir}, nil
+}
+
+// RoundTrip returns the recorded response to the request
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key, err := fixtureKey(req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(t.dir, key+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w for %s %s in %s; record the scan again", ErrNotRecorded, req.Method, req.URL, t.dir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read fixture: %w", err)
+	}
+
+	var recorded fixture
+	if err := json.Unmarshal(data, &recorded); err != nil {
+		return nil, fmt.Errorf("unable to parse fixture for %s %s: %w", req.Method, req.URL, err)
+	}
+	header := recorded.Header
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		StatusCode:    recorded.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+// fixtureKey identifies a request by method, URL, and body, so the same request maps to the same
+// fixture in every run. The request body is read and restored.
+func fixtureKey(req *http.Request) (string, error) {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s\n", req.Method, req.URL.String())
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return "", fmt.Errorf("failed to read request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		hash.Write(body)
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:32], nil
+}
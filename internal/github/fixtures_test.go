@@ -0,0 +1,93 @@
+package github
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFixtures_RecordAndReplay(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, `{"path":"`+r.URL.Path+`"}`)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	recorder, err := NewRecordTransport(http.DefaultTransport, dir)
+	if err != nil {
+		t.Fatalf("Failed to create record transport: %v", err)
+	}
+	get := func(transport http.RoundTripper, path string) (*http.Response, string, error) {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		req.Header.Set("Authorization", "Bearer ghp_secret")
+		resp, err := (&http.Client{Transport: transport}).Do(req)
+		if err != nil {
+			return nil, "", err
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body), nil
+	}
+
+	if _, body, err := get(recorder, "/repos/my-org/api"); err != nil || body != `{"path":"/repos/my-org/api"}` {
+		t.Fatalf("Expected the live response while recording, got %q, %v", body, err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("Expected 1 fixture, got %d", len(files))
+	}
+	data, _ := os.ReadFile(files[0])
+	if strings.Contains(string(data), "ghp_secret") || strings.Contains(string(data), "session=secret") {
+		t.Errorf("Expected the token and cookies left out of the fixture, got %s", data)
+	}
+
+	replayer, err := NewReplayTransport(dir)
+	if err != nil {
+		t.Fatalf("Failed to create replay transport: %v", err)
+	}
+	resp, body, err := get(replayer, "/repos/my-org/api")
+	if err != nil || resp.StatusCode != http.StatusOK || body != `{"path":"/repos/my-org/api"}` {
+		t.Errorf("Expected the recorded response on replay, got %q, %v", body, err)
+	}
+	if resp != nil && resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Expected recorded headers on replay, got %v", resp.Header)
+	}
+	if requests != 1 {
+		t.Errorf("Expected replay to make no requests, got %d in total", requests)
+	}
+
+	if _, _, err := get(replayer, "/repos/my-org/web"); !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("Expected ErrNotRecorded for an unrecorded request, got %v", err)
+	}
+}
+
+func TestFixtureKey_IncludesBody(t *testing.T) {
+	first, _ := http.NewRequest(http.MethodPost, "https://api.github.com/graphql", strings.NewReader(`{"query":"a"}`))
+	second, _ := http.NewRequest(http.MethodPost, "https://api.github.com/graphql", strings.NewReader(`{"query":"b"}`))
+
+	firstKey, _ := fixtureKey(first)
+	secondKey, _ := fixtureKey(second)
+	if firstKey == secondKey {
+		t.Error("Expected requests with different bodies to have different fixtures")
+	}
+	if body, _ := io.ReadAll(first.Body); string(body) != `{"query":"a"}` {
+		t.Errorf("Expected the request body restored, got %q", body)
+	}
+}
+
+func TestNewReplayTransport_MissingDirectory(t *testing.T) {
+	if _, err := NewReplayTransport(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("Expected an error for a missing fixture directory")
+	}
+}
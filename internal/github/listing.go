@@ -0,0 +1,253 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v65/github"
+)
+
+// listingPageAttempts is how many times a page of the repository listing is requested before the listing fails
+const listingPageAttempts = 3
+
+// listingRetryDelay is the wait before requesting a failed page again; it doubles with each attempt
+var listingRetryDelay = time.Second
+
+// ListingPageError reports a page of an owner's repository listing that failed, on every attempt when retried
+type ListingPageError struct {
+	Owner string
+	Page  int
+	Err   error
+}
+
+func (e *ListingPageError) Error() string {
+	return fmt.Sprintf("listing repositories of %s failed on page %d: %v", e.Owner, e.Page, e.Err)
+}
+
+func (e *ListingPageError) Unwrap() error {
+	return e.Err
+}
+
+// IncompleteListingError reports a repository listing that found fewer repositories than GitHub reports
+// the owner has, even after listing again
+type IncompleteListingError struct {
+	Owner    string
+	Listed   int // Repositories owned by Owner that were listed
+	Expected int // Public repositories, plus private ones when the token can see their count
+}
+
+func (e *IncompleteListingError) Error() string {
+	return fmt.Sprintf("listed %d of the %d repositories GitHub reports for %s; the listing is incomplete. "+
+		"Tokens limited to some repositories cannot list the rest: use --token-repos to scan the repositories the token can access, "+
+		"or --skip-listing-check to scan those listed", e.Listed, e.Expected, e.Owner)
+}
+
+// listRepositoryPages lists every page of an owner's repositories, requesting failed pages again
+func (c *Client) listRepositoryPages(owner string, isOrg bool) ([]*github.Repository, error) {
+	return c.listPages(owner, func(page int) ([]*github.Repository, *github.Response, error) {
+		return c.listRepositoryPage(owner, isOrg, page)
+	})
+}
+
+// listPages requests every page of a repository listing, requesting failed pages again
+func (c *Client) listPages(owner string, listPage func(page int) ([]*github.Repository, *github.Response, error)) ([]*github.Repository, error) {
+	var all []*github.Repository
+	page := 0
+	for pageCount := 1; ; pageCount++ {
+		repos, resp, err := listPage(page)
+		// Server errors and dropped connections are retried; client errors such as 403 or 404 would recur
+		for attempt := 2; err != nil && retryableListing(resp) && attempt <= listingPageAttempts; attempt++ {
+			delay := listingRetryDelay << (attempt - 2)
+			if c.verbose {
+				log.Printf("GitHub API: Error listing repositories of %s on page %d, retrying in %s - %v", owner, pageCount, delay, err)
+			}
+			time.Sleep(delay)
+			repos, resp, err = listPage(page)
+		}
+		if err != nil {
+			return nil, &ListingPageError{Owner: owner, Page: pageCount, Err: err}
+		}
+
+		if c.verbose {
+			log.Printf("GitHub API: Response status %d, received %d repositories on page %d", resp.StatusCode, len(repos), pageCount)
+		}
+		all = append(all, repos...)
+
+		if resp.NextPage == 0 {
+			if c.verbose {
+				log.Printf("GitHub API: Total repositories listed for %s: %d (across %d pages)", owner, len(all), pageCount)
+			}
+			return all, nil
+		}
+		page = resp.NextPage
+	}
+}
+
+// retryableListing reports whether a failed page request may succeed when requested again
+func retryableListing(resp *github.Response) bool {
+	return resp == nil || resp.Response == nil || resp.StatusCode >= 500
+}
+
+// listRepositoryPage requests one page of 100 of an owner's repositories, of every type
+func (c *Client) listRepositoryPage(owner string, isOrg bool, page int) ([]*github.Repository, *github.Response, error) {
+	listOptions := github.ListOptions{Page: page, PerPage: 100}
+	if isOrg {
+		if c.verbose {
+			log.Printf("GitHub API: GET /orgs/%s/repos (page=%d, per_page=%d, type=all)", owner, page, listOptions.PerPage)
+		}
+		return c.client.Repositories.ListByOrg(c.ctx, owner, &github.RepositoryListByOrgOptions{Type: "all", ListOptions: listOptions})
+	}
+	if c.verbose {
+		log.Printf("GitHub API: GET /users/%s/repos (page=%d, per_page=%d, type=all)", owner, page, listOptions.PerPage)
+	}
+	return c.client.Repositories.ListByUser(c.ctx, owner, &github.RepositoryListByUserOptions{Type: "all", ListOptions: listOptions})
+}
+
+// listAllRepositories lists an owner's repositories and checks the count against the totals GitHub
+// reports for the owner. Repositories created or deleted while paging shift later pages, so a short
+// listing is listed once more and the two are combined; one still short fails.
+func (c *Client) listAllRepositories(owner string, isOrg bool) ([]*github.Repository, error) {
+	repos, err := c.listRepositoryPages(owner, isOrg)
+	if err != nil {
+		return nil, err
+	}
+	repos = uniqueRepositories(repos)
+	if c.skipListingCheck {
+		return repos, nil
+	}
+
+	expected, ok := c.repositoryTotal(owner, isOrg)
+	if !ok {
+		return repos, nil
+	}
+	listed := ownedRepositories(owner, repos)
+	if listed < expected {
+		if c.verbose {
+			log.Printf("GitHub API: Listed %d of the %d repositories reported for %s, listing again", listed, expected, owner)
+		}
+		again, err := c.listRepositoryPages(owner, isOrg)
+		if err != nil {
+			return nil, err
+		}
+		repos = uniqueRepositories(append(repos, again...))
+		listed = ownedRepositories(owner, repos)
+	}
+	if listed < expected {
+		return nil, &IncompleteListingError{Owner: owner, Listed: listed, Expected: expected}
+	}
+	if c.verbose {
+		log.Printf("GitHub API: Listing of %s verified: %d repositories, %d reported", owner, listed, expected)
+	}
+	return repos, nil
+}
+
+// repositoryTotal returns the number of repositories GitHub reports an owner has, and false when it
+// cannot be read. Organizations report private repositories to members who can see them; users are
+// listed through their public repositories only, so only those are counted.
+func (c *Client) repositoryTotal(owner string, isOrg bool) (int, bool) {
+	if isOrg {
+		org, _, err := c.client.Organizations.Get(c.ctx, owner)
+		if err != nil || org.PublicRepos == nil {
+			if c.verbose {
+				log.Printf("GitHub API: Repository totals of %s unavailable, listing not verified - %v", owner, err)
+			}
+			return 0, false
+		}
+		return org.GetPublicRepos() + int(org.GetTotalPrivateRepos()), true
+	}
+
+	user, _, err := c.client.Users.Get(c.ctx, owner)
+	if err != nil || user.PublicRepos == nil {
+		if c.verbose {
+			log.Printf("GitHub API: Repository totals of %s unavailable, listing not verified - %v", owner, err)
+		}
+		return 0, false
+	}
+	return user.GetPublicRepos(), true
+}
+
+// ownedRepositories counts the repositories owned by owner, leaving out those of other owners a user
+// listing includes
+func ownedRepositories(owner string, repos []*github.Repository) int {
+	return len(repositoriesOwnedBy(owner, repos))
+}
+
+// repositoriesOwnedBy keeps the repositories owned by owner
+func repositoriesOwnedBy(owner string, repos []*github.Repository) []*github.Repository {
+	var owned []*github.Repository
+	for _, repo := range repos {
+		if strings.HasPrefix(strings.ToLower(repo.GetFullName()), strings.ToLower(owner)+"/") {
+			owned = append(owned, repo)
+		}
+	}
+	return owned
+}
+
+// uniqueRepositories drops repeated repositories, which a page shifted by a new repository lists twice
+func uniqueRepositories(repos []*github.Repository) []*github.Repository {
+	seen := make(map[string]bool, len(repos))
+	unique := make([]*github.Repository, 0, len(repos))
+	for _, repo := range repos {
+		key := strings.ToLower(repo.GetFullName())
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, repo)
+	}
+	return unique
+}
+
+// listAccessibleRepositories lists the repositories of owner the token can access, rather than those the
+// owner has. Personal access tokens, including fine-grained ones granted selected repositories, list the
+// authenticated user's repositories; GitHub App installation tokens, which have no user, list the
+// repositories of their installation. The listing is not checked against the owner's totals, since such
+// tokens see only part of them.
+func (c *Client) listAccessibleRepositories(owner string) ([]*github.Repository, error) {
+	repos, err := c.listPages(owner, func(page int) ([]*github.Repository, *github.Response, error) {
+		if c.verbose {
+			log.Printf("GitHub API: GET /user/repos (page=%d, per_page=100)", page)
+		}
+		return c.client.Repositories.ListByAuthenticatedUser(c.ctx, &github.RepositoryListByAuthenticatedUserOptions{
+			Affiliation: "owner,collaborator,organization_member",
+			ListOptions: github.ListOptions{Page: page, PerPage: 100},
+		})
+	})
+
+	var pageErr *ListingPageError
+	if errors.As(err, &pageErr) && pageErr.Page == 1 && installationToken(pageErr.Err) {
+		if c.verbose {
+			log.Printf("GitHub API: Token has no user, listing the repositories of its installation")
+		}
+		repos, err = c.listPages(owner, func(page int) ([]*github.Repository, *github.Response, error) {
+			if c.verbose {
+				log.Printf("GitHub API: GET /installation/repositories (page=%d, per_page=100)", page)
+			}
+			installed, resp, err := c.client.Apps.ListRepos(c.ctx, &github.ListOptions{Page: page, PerPage: 100})
+			if err != nil {
+				return nil, resp, err
+			}
+			return installed.Repositories, resp, nil
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	repos = repositoriesOwnedBy(owner, uniqueRepositories(repos))
+	if c.verbose {
+		log.Printf("GitHub API: Token can access %d repositories of %s", len(repos), owner)
+	}
+	return repos, nil
+}
+
+// installationToken reports whether a failed /user/repos request means the token belongs to a GitHub App
+// installation, which GitHub answers with 403 "Resource not accessible by integration"
+func installationToken(err error) bool {
+	var errResp *github.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusForbidden
+}
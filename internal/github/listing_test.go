@@ -0,0 +1,168 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v65/github"
+)
+
+// listingServer serves an organization reporting public and private repository totals, whose
+// repository listing is answered by list for each request in turn
+func listingServer(t *testing.T, org string, public, private int, list func(request int, page string) (int, []string)) *Client {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/orgs/" + org:
+			fmt.Fprintf(w, `{"login": %q, "type": "Organization", "public_repos": %d, "total_private_repos": %d}`, org, public, private)
+		case "/orgs/" + org + "/repos":
+			requests++
+			status, names := list(requests, r.URL.Query().Get("page"))
+			if status != http.StatusOK {
+				w.WriteHeader(status)
+				w.Write([]byte(`{"message": "failed"}`))
+				return
+			}
+			repos := make([]string, len(names))
+			for i, name := range names {
+				repos[i] = fmt.Sprintf(`{"name": %q, "full_name": "%s/%s", "default_branch": "main"}`, name, org, name)
+			}
+			w.Write([]byte("[" + strings.Join(repos, ",") + "]"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+	return &Client{client: client, ctx: context.Background()}
+}
+
+func TestListRepositories_RetriesFailedPage(t *testing.T) {
+	defer func(delay time.Duration) { listingRetryDelay = delay }(listingRetryDelay)
+	listingRetryDelay = 0
+
+	githubClient := listingServer(t, "flaky", 2, 0, func(request int, page string) (int, []string) {
+		if request == 1 {
+			return http.StatusBadGateway, nil
+		}
+		return http.StatusOK, []string{"api", "web"}
+	})
+
+	repos, err := githubClient.ListRepositories("flaky")
+	if err != nil {
+		t.Fatalf("Expected the failed page to be retried, got %v", err)
+	}
+	if len(repos) != 2 {
+		t.Errorf("Expected 2 repositories, got %d", len(repos))
+	}
+}
+
+func TestListRepositories_ListsAgainWhenShort(t *testing.T) {
+	// A repository deleted while paging shifts another past the page boundary of the first listing
+	githubClient := listingServer(t, "busy", 3, 0, func(request int, page string) (int, []string) {
+		if request == 1 {
+			return http.StatusOK, []string{"api", "web"}
+		}
+		return http.StatusOK, []string{"api", "docs", "web"}
+	})
+
+	repos, err := githubClient.ListRepositories("busy")
+	if err != nil {
+		t.Fatalf("Expected the second listing to complete the first, got %v", err)
+	}
+	if len(repos) != 3 {
+		t.Errorf("Expected 3 unique repositories, got %d", len(repos))
+	}
+}
+
+func TestListRepositories_IncompleteListing(t *testing.T) {
+	list := func(request int, page string) (int, []string) {
+		return http.StatusOK, []string{"api", "web", "docs"}
+	}
+
+	githubClient := listingServer(t, "limited", 3, 2, list)
+	_, err := githubClient.ListRepositories("limited")
+	var incomplete *IncompleteListingError
+	if !errors.As(err, &incomplete) || incomplete.Listed != 3 || incomplete.Expected != 5 {
+		t.Fatalf("Expected 3 of 5 repositories to fail the listing, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "--skip-listing-check") {
+		t.Errorf("Expected the error to name --skip-listing-check, got %v", err)
+	}
+
+	githubClient = listingServer(t, "limited", 3, 2, list)
+	githubClient.skipListingCheck = true
+	repos, err := githubClient.ListRepositories("limited")
+	if err != nil || len(repos) != 3 {
+		t.Errorf("Expected the 3 listed repositories without the check, got %d (%v)", len(repos), err)
+	}
+}
+
+func TestOwnedRepositories(t *testing.T) {
+	repos := []*github.Repository{
+		{FullName: github.String("My-Org/api")},
+		{FullName: github.String("my-org/api")},
+		{FullName: github.String("my-org/web")},
+		{FullName: github.String("other/tool")},
+	}
+
+	unique := uniqueRepositories(repos)
+	if len(unique) != 3 {
+		t.Fatalf("Expected 3 unique repositories, got %d", len(unique))
+	}
+	if owned := ownedRepositories("my-org", unique); owned != 2 {
+		t.Errorf("Expected 2 repositories owned by my-org, got %d", owned)
+	}
+}
+
+// accessibleServer serves /user/repos, answering 403 like GitHub does for installation tokens when
+// installation is set, and /installation/repositories
+func accessibleServer(t *testing.T, installation bool, names []string) *Client {
+	repos := make([]string, len(names))
+	for i, name := range names {
+		repos[i] = fmt.Sprintf(`{"name": %q, "full_name": %q, "default_branch": "main"}`, name[strings.Index(name, "/")+1:], name)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/user/repos" && installation:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "Resource not accessible by integration"}`))
+		case r.URL.Path == "/user/repos":
+			w.Write([]byte("[" + strings.Join(repos, ",") + "]"))
+		case r.URL.Path == "/installation/repositories" && installation:
+			fmt.Fprintf(w, `{"total_count": %d, "repositories": [%s]}`, len(repos), strings.Join(repos, ","))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+	return &Client{client: client, ctx: context.Background(), tokenRepositories: true}
+}
+
+func TestListRepositories_TokenRepositories(t *testing.T) {
+	for _, installation := range []bool{false, true} {
+		githubClient := accessibleServer(t, installation, []string{"my-org/api", "my-org/web", "someone/fork"})
+
+		repos, err := githubClient.ListRepositories("my-org")
+		if err != nil {
+			t.Fatalf("installation %t: expected the accessible repositories, got %v", installation, err)
+		}
+		if len(repos) != 2 || repos[0].FullName != "my-org/api" || repos[1].FullName != "my-org/web" || repos[0].Owner != "my-org" {
+			t.Errorf("installation %t: expected my-org's 2 repositories, got %+v", installation, repos)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package github
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// tokenPattern matches GitHub tokens (personal access, OAuth, installation, and refresh tokens) and JWTs
+var tokenPattern = regexp.MustCompile(`\b(gh[pousr]_|github_pat_)[A-Za-z0-9_]{16,}|\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)
+
+// redacted replaces a token; GitHub token prefixes are kept so the kind of token is still recognizable
+const redacted = "[redacted]"
+
+// RedactTokens masks GitHub tokens and JWTs in s, along with any of the given secrets, such as a token
+// passed with --token in a format the patterns do not know
+func RedactTokens(s string, secrets ...string) string {
+	for _, secret := range secrets {
+		if len(secret) >= 8 {
+			s = strings.ReplaceAll(s, secret, redacted)
+		}
+	}
+	return tokenPattern.ReplaceAllStringFunc(s, func(token string) string {
+		for _, prefix := range []string{"ghp_", "gho_", "ghu_", "ghs_", "ghr_", "github_pat_"} {
+			if strings.HasPrefix(token, prefix) {
+				return prefix + redacted
+			}
+		}
+		return redacted
+	})
+}
+
+// RedactingWriter is a log output that masks tokens before passing writes through. The standard logger
+// writes each entry in one call, so tokens are never split across writes.
+type RedactingWriter struct {
+	out     io.Writer
+	mu      sync.Mutex
+	secrets []string
+}
+
+// NewRedactingWriter creates a writer that redacts tokens and the given secrets from writes to out
+func NewRedactingWriter(out io.Writer, secrets ...string) *RedactingWriter {
+	return &RedactingWriter{out: out, secrets: secrets}
+}
+
+// AddSecret adds a value to redact, e.g. a token only known once flags are parsed
+func (w *RedactingWriter) AddSecret(secret string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.secrets = append(w.secrets, secret)
+}
+
+// Write passes p through with its tokens redacted, reporting p as fully written
+func (w *RedactingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, err := io.WriteString(w.out, RedactTokens(string(p), w.secrets...)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,56 @@
+package github
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RequestStats summarizes the HTTP requests made by a client
+type RequestStats struct {
+	Requests int           // Number of API requests sent
+	Duration time.Duration // Total time spent waiting on API responses
+}
+
+// requestStats accumulates request counts and durations across concurrent callers
+type requestStats struct {
+	mutex    sync.Mutex
+	requests int
+	duration time.Duration
+}
+
+// record adds a completed request to the totals
+func (s *requestStats) record(elapsed time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.requests++
+	s.duration += elapsed
+}
+
+// statsTransport wraps an http.RoundTripper to record request timing
+type statsTransport struct {
+	base  http.RoundTripper
+	stats *requestStats
+}
+
+// RoundTrip sends the request and records how long it took
+func (t *statsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	t.stats.record(time.Since(start))
+	return resp, err
+}
+
+// RequestStats returns the number of API requests made so far and the time spent on them
+func (c *Client) RequestStats() RequestStats {
+	if c.stats == nil {
+		return RequestStats{}
+	}
+
+	c.stats.mutex.Lock()
+	defer c.stats.mutex.Unlock()
+	return RequestStats{
+		Requests: c.stats.requests,
+		Duration: c.stats.duration,
+	}
+}
@@ -0,0 +1,33 @@
+package github
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStatsTransport_RecordsRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	stats := &requestStats{}
+	httpClient := &http.Client{Transport: &statsTransport{base: http.DefaultTransport, stats: stats}}
+	for i := 0; i < 3; i++ {
+		resp, err := httpClient.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	client := &Client{stats: stats}
+	if got := client.RequestStats().Requests; got != 3 {
+		t.Errorf("Expected 3 recorded requests, got %d", got)
+	}
+
+	if got := (&Client{}).RequestStats().Requests; got != 0 {
+		t.Errorf("Expected client without stats to report 0 requests, got %d", got)
+	}
+}
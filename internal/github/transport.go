@@ -0,0 +1,172 @@
+package github
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default timeouts for the underlying HTTP transport
+const (
+	DefaultRequestTimeout = 60 * time.Second
+	DefaultDialTimeout    = 30 * time.Second
+)
+
+// TransportConfig holds network options for reaching the GitHub API from enterprise environments
+type TransportConfig struct {
+	ProxyURL           string        // Explicit proxy URL; when empty HTTP_PROXY/HTTPS_PROXY/NO_PROXY are honored
+	CABundle           string        // PEM file of additional trusted CA certificates
+	InsecureSkipVerify bool          // Disable TLS certificate verification (testing only)
+	DialTimeout        time.Duration // Timeout for establishing connections (0 = default)
+}
+
+// NewTransport builds an HTTP transport with proxy, CA, and TLS settings applied
+func NewTransport(config TransportConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	// Proxy settings: explicit URL wins, otherwise fall back to the environment
+	transport.Proxy = http.ProxyFromEnvironment
+	if config.ProxyURL != "" {
+		proxyURL, err := url.Parse(config.ProxyURL)
+		if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", config.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if config.CABundle != "" {
+		pem, err := os.ReadFile(config.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read CA bundle: %w", err)
+		}
+
+		// Extend the system pool so public endpoints keep working alongside private CAs
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid PEM certificates found in CA bundle %s", config.CABundle)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.InsecureSkipVerify {
+		tlsConfig.InsecureSkipVerify = true
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	dialTimeout := config.DialTimeout
+	if dialTimeout == 0 {
+		dialTimeout = DefaultDialTimeout
+	}
+	transport.DialContext = (&net.Dialer{Timeout: dialTimeout, KeepAlive: 30 * time.Second}).DialContext
+	transport.TLSHandshakeTimeout = dialTimeout
+
+	return transport, nil
+}
+
+// Headers tagging every API request with the tool version and run
+const (
+	VersionHeader = "X-Actions-Maintainer-Version"
+	RunIDHeader   = "X-Actions-Maintainer-Run-Id"
+)
+
+// RequestTags attribute API traffic to this tool, so API gateways and GitHub audit logs can tell it from other automation
+type RequestTags struct {
+	Version         string // Tool version
+	RunID           string // Identifies one run of the tool (empty = DefaultRunID)
+	UserAgentSuffix string // Appended to the User-Agent, e.g. a team or pipeline name
+}
+
+// UserAgent returns the User-Agent sent with every request, e.g. "actions-maintainer/1.2.0 (run 1a2b3c4d) platform-team"
+func (t RequestTags) UserAgent() string {
+	version := t.Version
+	if version == "" {
+		version = "dev"
+	}
+	agent := fmt.Sprintf("actions-maintainer/%s (run %s)", version, t.runID())
+	if t.UserAgentSuffix != "" {
+		agent += " " + t.UserAgentSuffix
+	}
+	return agent
+}
+
+// runID returns the configured run ID, or the run ID shared by the whole process
+func (t RequestTags) runID() string {
+	if t.RunID != "" {
+		return t.RunID
+	}
+	return DefaultRunID()
+}
+
+// ValidateUserAgentSuffix rejects suffixes that cannot be sent in a header
+func ValidateUserAgentSuffix(suffix string) error {
+	if strings.ContainsFunc(suffix, func(r rune) bool { return r < 0x20 || r == 0x7f }) {
+		return fmt.Errorf("user agent suffix must not contain control characters")
+	}
+	return nil
+}
+
+var (
+	defaultRunID     string
+	defaultRunIDOnce sync.Once
+)
+
+// DefaultRunID identifies this run of the tool: the workflow run when running in GitHub Actions
+// (e.g. "1234567890-1" for attempt 1), otherwise a random ID generated once per process
+func DefaultRunID() string {
+	defaultRunIDOnce.Do(func() {
+		if runID := os.Getenv("GITHUB_RUN_ID"); runID != "" {
+			defaultRunID = runID
+			if attempt := os.Getenv("GITHUB_RUN_ATTEMPT"); attempt != "" {
+				defaultRunID += "-" + attempt
+			}
+			return
+		}
+		buf := make([]byte, 8)
+		if _, err := rand.Read(buf); err == nil {
+			defaultRunID = hex.EncodeToString(buf)
+		} else {
+			defaultRunID = fmt.Sprintf("%x", time.Now().UnixNano())
+		}
+	})
+	return defaultRunID
+}
+
+// tagTransport adds the User-Agent and tag headers to every request
+type tagTransport struct {
+	base    http.RoundTripper
+	agent   string
+	version string
+	runID   string
+}
+
+// newTagTransport wraps a transport so its requests carry the given tags
+func newTagTransport(base http.RoundTripper, tags RequestTags) *tagTransport {
+	version := tags.Version
+	if version == "" {
+		version = "dev"
+	}
+	return &tagTransport{base: base, agent: tags.UserAgent(), version: version, runID: tags.runID()}
+}
+
+// RoundTrip sends a copy of the request carrying the tags; the caller's request is left unmodified
+func (t *tagTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	tagged := req.Clone(req.Context())
+	tagged.Header.Set("User-Agent", t.agent)
+	tagged.Header.Set(VersionHeader, t.version)
+	tagged.Header.Set(RunIDHeader, t.runID)
+	return t.base.RoundTrip(tagged)
+}
@@ -0,0 +1,132 @@
+package github
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewTransport_CABundleTrustsPrivateCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// Without the bundle the self-signed certificate is rejected
+	transport, err := NewTransport(TransportConfig{})
+	if err != nil {
+		t.Fatalf("NewTransport() returned error: %v", err)
+	}
+	if _, err := (&http.Client{Transport: transport}).Get(server.URL); err == nil {
+		t.Errorf("Expected request to fail without CA bundle")
+	}
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, certPEM, 0o600); err != nil {
+		t.Fatalf("Failed to write CA bundle: %v", err)
+	}
+
+	transport, err = NewTransport(TransportConfig{CABundle: bundle})
+	if err != nil {
+		t.Fatalf("NewTransport() returned error: %v", err)
+	}
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected request to succeed with CA bundle: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestNewTransport_InvalidOptions(t *testing.T) {
+	invalidBundle := filepath.Join(t.TempDir(), "invalid.pem")
+	if err := os.WriteFile(invalidBundle, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("Failed to write bundle: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		config TransportConfig
+	}{
+		{"malformed proxy", TransportConfig{ProxyURL: "://proxy"}},
+		{"proxy without host", TransportConfig{ProxyURL: "proxy.internal:8080"}},
+		{"missing CA bundle", TransportConfig{CABundle: filepath.Join(t.TempDir(), "missing.pem")}},
+		{"CA bundle without certificates", TransportConfig{CABundle: invalidBundle}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewTransport(tt.config); err == nil {
+				t.Errorf("Expected error for %s", tt.name)
+			}
+		})
+	}
+}
+
+func TestNewTransport_ExplicitProxy(t *testing.T) {
+	transport, err := NewTransport(TransportConfig{ProxyURL: "http://proxy.internal:8080"})
+	if err != nil {
+		t.Fatalf("NewTransport() returned error: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/user", nil)
+	proxyURL, err := transport.Proxy(req)
+	if err != nil || proxyURL == nil || proxyURL.Host != "proxy.internal:8080" {
+		t.Errorf("Expected requests to use explicit proxy, got %v (err: %v)", proxyURL, err)
+	}
+}
+
+func TestTagTransport_TagsRequests(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tags := RequestTags{Version: "1.2.0", RunID: "run-7", UserAgentSuffix: "platform-team"}
+	client := &http.Client{Transport: newTagTransport(http.DefaultTransport, tags)}
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("User-Agent", "other-client")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if got := received.Get("User-Agent"); got != "actions-maintainer/1.2.0 (run run-7) platform-team" {
+		t.Errorf("Expected tagged User-Agent, got %q", got)
+	}
+	if received.Get(VersionHeader) != "1.2.0" || received.Get(RunIDHeader) != "run-7" {
+		t.Errorf("Expected version and run ID headers, got %v", received)
+	}
+	if req.Header.Get("User-Agent") != "other-client" {
+		t.Errorf("Expected the caller's request to be left unmodified, got %q", req.Header.Get("User-Agent"))
+	}
+}
+
+func TestRequestTags_DefaultRunID(t *testing.T) {
+	agent := RequestTags{}.UserAgent()
+	if agent != "actions-maintainer/dev (run "+DefaultRunID()+")" {
+		t.Errorf("Expected the process run ID in the User-Agent, got %q", agent)
+	}
+	if DefaultRunID() == "" || DefaultRunID() != DefaultRunID() {
+		t.Errorf("Expected a stable run ID, got %q", DefaultRunID())
+	}
+}
+
+func TestValidateUserAgentSuffix(t *testing.T) {
+	if err := ValidateUserAgentSuffix("platform-team (nightly)"); err != nil {
+		t.Errorf("Expected suffix to be valid, got %v", err)
+	}
+	if err := ValidateUserAgentSuffix("team\r\nX-Injected: 1"); err == nil {
+		t.Errorf("Expected suffix with a line break to be rejected")
+	}
+}
@@ -0,0 +1,87 @@
+package github
+
+import "testing"
+
+func TestMatchesWorkflowDir(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		filePath string
+		expected bool
+	}{
+		{".github/workflows", ".github/workflows/ci.yml", true},
+		{".github/workflows", ".github/workflows/reusable/build.yml", true},
+		{".github/workflows", ".gitea/workflows/ci.yml", false},
+		{"services/*/.github/workflows", "services/api/.github/workflows/ci.yml", true},
+		{"services/*/.github/workflows", "services/api/nested/.github/workflows/ci.yml", false},
+		{"**/workflows", "deploy/ci/workflows/release.yaml", true},
+		{"**/workflows", ".gitea/workflows/ci.yml", true},
+		{"**/workflows", "workflows/ci.yml", true},
+		{"**/workflows", "docs/ci.yml", false},
+		{".git*/workflows", ".gitea/workflows/ci.yml", true},
+	}
+
+	for _, tt := range tests {
+		if got := MatchesWorkflowDir(tt.pattern, tt.filePath); got != tt.expected {
+			t.Errorf("MatchesWorkflowDir(%q, %q) = %v, expected %v", tt.pattern, tt.filePath, got, tt.expected)
+		}
+	}
+}
+
+func TestIsGlobPattern(t *testing.T) {
+	if IsGlobPattern(".github/workflows") {
+		t.Errorf("Expected plain directory not to be a glob pattern")
+	}
+	if !IsGlobPattern("services/*/.github/workflows") {
+		t.Errorf("Expected wildcard directory to be a glob pattern")
+	}
+}
+
+func TestNormalizeWorkflowDir(t *testing.T) {
+	tests := map[string]string{
+		".github/workflows":       ".github/workflows",
+		"/.github/workflows/":     ".github/workflows",
+		`.github\workflows`:       ".github/workflows",
+		`.\services\api\.github\`: "services/api/.github",
+		"./ci/workflows":          "ci/workflows",
+		"  ":                      "",
+	}
+
+	for input, expected := range tests {
+		if got := NormalizeWorkflowDir(input); got != expected {
+			t.Errorf("NormalizeWorkflowDir(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
+func TestWorkflowDirsFor_AddsTemplatesInOrgRepository(t *testing.T) {
+	dirs := []string{".github/workflows"}
+
+	orgRepo := Repository{Owner: "my-org", Name: ".github", FullName: "my-org/.github"}
+	got := WorkflowDirsFor(orgRepo, dirs)
+	if len(got) != 2 || got[0] != ".github/workflows" || got[1] != WorkflowTemplatesDir {
+		t.Errorf("Expected workflow templates to be scanned in the .github repository, got %v", got)
+	}
+	if len(dirs) != 1 {
+		t.Errorf("Expected the configured directories to be left unchanged, got %v", dirs)
+	}
+	if got := WorkflowDirsFor(orgRepo, []string{"workflow-templates/"}); len(got) != 1 {
+		t.Errorf("Expected an explicitly configured templates directory not to be repeated, got %v", got)
+	}
+
+	if got := WorkflowDirsFor(Repository{Owner: "my-org", Name: "api", FullName: "my-org/api"}, dirs); len(got) != 1 {
+		t.Errorf("Expected other repositories to scan only the configured directories, got %v", got)
+	}
+}
+
+func TestIsWorkflowTemplate(t *testing.T) {
+	orgRepo := Repository{Name: ".github"}
+	if !IsWorkflowTemplate(orgRepo, "workflow-templates/ci.yml") {
+		t.Errorf("Expected workflow-templates/ci.yml in the .github repository to be a template")
+	}
+	if IsWorkflowTemplate(orgRepo, ".github/workflows/ci.yml") {
+		t.Errorf("Expected the .github repository's own workflows not to be templates")
+	}
+	if IsWorkflowTemplate(Repository{Name: "api"}, "workflow-templates/ci.yml") {
+		t.Errorf("Expected templates only in the .github repository")
+	}
+}
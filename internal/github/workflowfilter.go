@@ -0,0 +1,77 @@
+package github
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// WorkflowFilter narrows a scan to workflow files matching any include glob and no exclude glob
+// Globs without a slash match the file name (e.g., "deploy-*.yml"); globs with one match the path
+// from the repository root, where "**" matches any number of directories.
+type WorkflowFilter struct {
+	Include []string // Empty includes every file
+	Exclude []string
+}
+
+// ParseWorkflowFilter builds a filter from comma-separated include and exclude globs, returning nil when both are empty
+func ParseWorkflowFilter(include, exclude string) (*WorkflowFilter, error) {
+	filter := &WorkflowFilter{
+		Include: splitGlobs(include),
+		Exclude: splitGlobs(exclude),
+	}
+	if len(filter.Include) == 0 && len(filter.Exclude) == 0 {
+		return nil, nil
+	}
+
+	for _, pattern := range append(append([]string{}, filter.Include...), filter.Exclude...) {
+		for _, segment := range strings.Split(pattern, "/") {
+			if _, err := path.Match(segment, ""); err != nil {
+				return nil, fmt.Errorf("invalid workflow glob %q: %w", pattern, err)
+			}
+		}
+	}
+	return filter, nil
+}
+
+// splitGlobs splits a comma-separated list of globs, normalizing path separators
+func splitGlobs(list string) []string {
+	var globs []string
+	for _, part := range strings.Split(list, ",") {
+		if glob := NormalizeWorkflowDir(part); glob != "" {
+			globs = append(globs, glob)
+		}
+	}
+	return globs
+}
+
+// Allows reports whether a workflow file passes the filter; a nil filter allows every file
+func (f *WorkflowFilter) Allows(filePath string) bool {
+	if f == nil {
+		return true
+	}
+
+	for _, pattern := range f.Exclude {
+		if matchesWorkflowGlob(pattern, filePath) {
+			return false
+		}
+	}
+	if len(f.Include) == 0 {
+		return true
+	}
+	for _, pattern := range f.Include {
+		if matchesWorkflowGlob(pattern, filePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesWorkflowGlob matches a file name glob against the base name, or a path glob against the whole path
+func matchesWorkflowGlob(pattern, filePath string) bool {
+	if !strings.Contains(pattern, "/") {
+		ok, err := path.Match(pattern, path.Base(filePath))
+		return err == nil && ok
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(filePath, "/"))
+}
@@ -0,0 +1,58 @@
+package github
+
+import "testing"
+
+func TestParseWorkflowFilter_Empty(t *testing.T) {
+	filter, err := ParseWorkflowFilter(" ", ",")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if filter != nil {
+		t.Errorf("Expected nil filter for empty globs, got %+v", filter)
+	}
+	if !filter.Allows(".github/workflows/ci.yml") {
+		t.Errorf("Expected nil filter to allow every file")
+	}
+}
+
+func TestParseWorkflowFilter_InvalidGlob(t *testing.T) {
+	if _, err := ParseWorkflowFilter("release[.yml", ""); err == nil {
+		t.Errorf("Expected error for malformed glob")
+	}
+}
+
+func TestWorkflowFilter_Allows(t *testing.T) {
+	filter, err := ParseWorkflowFilter("release*.yml, .github/workflows/deploy/*", "*-experimental.yml,**/legacy/*")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := map[string]bool{
+		".github/workflows/release.yml":                 true,
+		"services/api/.github/workflows/release-v2.yml": true,
+		".github/workflows/deploy/prod.yml":             true,
+		".github/workflows/ci.yml":                      false,
+		".github/workflows/release-experimental.yml":    false,
+		".github/workflows/legacy/release.yml":          false,
+		".github/workflows/deploy/nested/prod.yml":      false,
+	}
+
+	for filePath, expected := range tests {
+		if got := filter.Allows(filePath); got != expected {
+			t.Errorf("Allows(%q) = %v, expected %v", filePath, got, expected)
+		}
+	}
+}
+
+func TestWorkflowFilter_ExcludeOnly(t *testing.T) {
+	filter, err := ParseWorkflowFilter("", "nightly.yml")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !filter.Allows(".github/workflows/ci.yml") {
+		t.Errorf("Expected files not excluded to be allowed")
+	}
+	if filter.Allows(".github/workflows/nightly.yml") {
+		t.Errorf("Expected excluded file to be skipped")
+	}
+}
@@ -0,0 +1,230 @@
+package golden
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/actions"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// Issue types raised by golden set checks
+const (
+	IssueTypeMissingWorkflow    = "missing-required-workflow" // A workflow required for the repository's class is absent
+	IssueTypeUnapprovedWorkflow = "unapproved-workflow"       // A workflow is neither required nor allowed for the repository's class
+)
+
+// Class is the approved workflow set of a class of repositories
+// Patterns are matched against the workflow file name, or against the whole path when they contain
+// a "/", and may use path.Match wildcards such as "deploy-*.yml".
+type Class struct {
+	Name          string                  `json:"name"`
+	Conditions    *actions.RuleConditions `json:"conditions,omitempty"`     // Repositories of the class when not chosen by ClassProperty; nil matches none
+	Required      []string                `json:"required,omitempty"`       // Workflows every repository of the class must have
+	Allowed       []string                `json:"allowed,omitempty"`        // Workflows repositories of the class may also have
+	AllowUnlisted bool                    `json:"allow_unlisted,omitempty"` // Only check required workflows, allowing any other
+}
+
+// GoldenSet assigns repositories to classes and holds each class's approved workflows
+type GoldenSet struct {
+	ClassProperty string  `json:"class_property,omitempty"` // Custom property naming a repository's class, taking precedence over conditions
+	Default       string  `json:"default,omitempty"`        // Class of repositories matching no other class
+	Classes       []Class `json:"classes"`
+}
+
+// Load reads a golden set file
+func Load(filename string) (*GoldenSet, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read golden set file: %w", err)
+	}
+
+	var set GoldenSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse golden set file: %w", err)
+	}
+	if err := set.Validate(); err != nil {
+		return nil, err
+	}
+	return &set, nil
+}
+
+// Validate checks that classes are named uniquely and their conditions and patterns are valid
+func (s *GoldenSet) Validate() error {
+	if len(s.Classes) == 0 {
+		return fmt.Errorf("golden set has no classes")
+	}
+
+	names := make(map[string]bool)
+	for _, class := range s.Classes {
+		if class.Name == "" {
+			return fmt.Errorf("golden set class without a name")
+		}
+		if names[strings.ToLower(class.Name)] {
+			return fmt.Errorf("golden set class %q is defined twice", class.Name)
+		}
+		names[strings.ToLower(class.Name)] = true
+
+		if err := class.Conditions.Validate(); err != nil {
+			return fmt.Errorf("golden set class %q: %w", class.Name, err)
+		}
+		for _, pattern := range append(append([]string{}, class.Required...), class.Allowed...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("golden set class %q: invalid workflow pattern %q: %w", class.Name, pattern, err)
+			}
+		}
+	}
+	if s.Default != "" && s.class(s.Default) == nil {
+		return fmt.Errorf("golden set default class %q is not defined", s.Default)
+	}
+	return nil
+}
+
+// class returns the class with a name, compared case-insensitively, or nil
+func (s *GoldenSet) class(name string) *Class {
+	for i := range s.Classes {
+		if strings.EqualFold(s.Classes[i].Name, name) {
+			return &s.Classes[i]
+		}
+	}
+	return nil
+}
+
+// Properties returns the custom properties needed to assign repositories to classes
+func (s *GoldenSet) Properties() []string {
+	seen := make(map[string]bool)
+	var properties []string
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			properties = append(properties, name)
+		}
+	}
+
+	add(s.ClassProperty)
+	for _, class := range s.Classes {
+		if class.Conditions != nil {
+			for name := range class.Conditions.CustomProperties {
+				add(name)
+			}
+		}
+	}
+	sort.Strings(properties)
+	return properties
+}
+
+// ClassOf returns the class of a repository: the class named by its ClassProperty value, otherwise
+// the first class whose conditions it matches, otherwise the default class. It returns nil for
+// repositories of no class.
+func (s *GoldenSet) ClassOf(repo output.RepositoryResult) *Class {
+	if s.ClassProperty != "" {
+		if class := s.class(repo.CustomProperties[s.ClassProperty]); class != nil {
+			return class
+		}
+	}
+
+	context := actions.RepositoryContext{
+		Name:             repo.Name,
+		FullName:         repo.FullName,
+		DefaultBranch:    repo.DefaultBranch,
+		CustomProperties: repo.CustomProperties,
+		Topics:           repo.Topics,
+	}
+	for i := range s.Classes {
+		if s.Classes[i].Conditions != nil && s.Classes[i].Conditions.Matches(context) {
+			return &s.Classes[i]
+		}
+	}
+	return s.class(s.Default)
+}
+
+// Check compares the workflows of a scanned repository with the golden set of its class, returning
+// an issue for each missing required workflow and each workflow the class does not approve.
+// Organization workflow templates are not workflows of the repository and are ignored.
+func (s *GoldenSet) Check(repo output.RepositoryResult) []output.ActionIssue {
+	class := s.ClassOf(repo)
+	if class == nil {
+		return nil
+	}
+
+	var paths []string
+	for _, wf := range repo.WorkflowFiles {
+		if !wf.Template {
+			paths = append(paths, wf.Path)
+		}
+	}
+
+	var issues []output.ActionIssue
+	for _, pattern := range class.Required {
+		found := false
+		for _, workflowPath := range paths {
+			if matches(pattern, workflowPath) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			issues = append(issues, output.ActionIssue{
+				Repository:  pattern,
+				IssueType:   IssueTypeMissingWorkflow,
+				Severity:    "medium",
+				Description: fmt.Sprintf("Workflow %s is required for %s repositories but is missing", pattern, class.Name),
+				Context:     "class:" + class.Name,
+			})
+		}
+	}
+
+	if class.AllowUnlisted {
+		return issues
+	}
+	for _, workflowPath := range paths {
+		if !class.approves(workflowPath) {
+			issues = append(issues, output.ActionIssue{
+				Repository:  path.Base(workflowPath),
+				IssueType:   IssueTypeUnapprovedWorkflow,
+				Severity:    "low",
+				Description: fmt.Sprintf("Workflow %s is not in the approved workflow set of %s repositories", path.Base(workflowPath), class.Name),
+				Context:     "class:" + class.Name,
+				FilePath:    workflowPath,
+			})
+		}
+	}
+	return issues
+}
+
+// approves reports whether a workflow is required or allowed for the class
+func (c *Class) approves(workflowPath string) bool {
+	for _, patterns := range [][]string{c.Required, c.Allowed} {
+		for _, pattern := range patterns {
+			if matches(pattern, workflowPath) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// extensionPattern matches the extension of workflow files, which may be .yml or .yaml interchangeably
+var extensionPattern = regexp.MustCompile(`\.ya?ml$`)
+
+// matches reports whether a workflow path matches a pattern, by file name or, for patterns with a
+// "/", by whole path; ".yml" and ".yaml" match each other
+func matches(pattern, workflowPath string) bool {
+	target := path.Base(workflowPath)
+	if strings.Contains(pattern, "/") {
+		target = workflowPath
+	}
+	if ok, _ := path.Match(pattern, target); ok {
+		return true
+	}
+	if !extensionPattern.MatchString(pattern) || !extensionPattern.MatchString(target) {
+		return false
+	}
+	ok, _ := path.Match(extensionPattern.ReplaceAllString(pattern, ".yml"), extensionPattern.ReplaceAllString(target, ".yml"))
+	return ok
+}
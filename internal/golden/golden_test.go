@@ -0,0 +1,138 @@
+package golden
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/actions"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+func repository(name string, properties map[string]string, paths ...string) output.RepositoryResult {
+	repo := output.RepositoryResult{Name: name, FullName: "my-org/" + name, CustomProperties: properties}
+	for _, p := range paths {
+		repo.WorkflowFiles = append(repo.WorkflowFiles, output.WorkflowFileResult{Path: p})
+	}
+	return repo
+}
+
+func testSet() *GoldenSet {
+	return &GoldenSet{
+		ClassProperty: "workflow-class",
+		Classes: []Class{
+			{
+				Name:     "service",
+				Required: []string{"ci.yml", "codeql.yml", "release.yml"},
+				Allowed:  []string{"deploy-*.yml"},
+			},
+			{
+				Name:          "library",
+				Conditions:    &actions.RuleConditions{RepositoryPattern: "^lib-"},
+				Required:      []string{"ci.yml"},
+				AllowUnlisted: true,
+			},
+		},
+	}
+}
+
+func issueSummary(issues []output.ActionIssue) []string {
+	var summary []string
+	for _, issue := range issues {
+		summary = append(summary, issue.IssueType+" "+issue.Repository+" "+issue.Context)
+	}
+	return summary
+}
+
+func TestCheck(t *testing.T) {
+	set := testSet()
+
+	repo := repository("api", map[string]string{"workflow-class": "Service"},
+		".github/workflows/ci.yaml", ".github/workflows/release.yml", ".github/workflows/deploy-prod.yml", ".github/workflows/nightly.yml")
+	expected := []string{
+		"missing-required-workflow codeql.yml class:service",
+		"unapproved-workflow nightly.yml class:service",
+	}
+	issues := set.Check(repo)
+	if got := issueSummary(issues); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if issues[1].FilePath != ".github/workflows/nightly.yml" || issues[1].Severity != "low" || issues[0].Severity != "medium" {
+		t.Errorf("Unexpected issues %+v", issues)
+	}
+
+	library := repository("lib-utils", nil, ".github/workflows/docs.yml")
+	if got := issueSummary(set.Check(library)); !reflect.DeepEqual(got, []string{"missing-required-workflow ci.yml class:library"}) {
+		t.Errorf("Expected only the missing workflow of an allow_unlisted class, got %v", got)
+	}
+
+	if issues := set.Check(repository("website", nil, ".github/workflows/anything.yml")); len(issues) != 0 {
+		t.Errorf("Expected no issues for a repository of no class, got %v", issueSummary(issues))
+	}
+}
+
+func TestCheckIgnoresTemplates(t *testing.T) {
+	set := &GoldenSet{Default: "all", Classes: []Class{{Name: "all", Required: []string{"ci.yml"}}}}
+	repo := repository(".github", nil, ".github/workflows/ci.yml")
+	repo.WorkflowFiles = append(repo.WorkflowFiles, output.WorkflowFileResult{Path: "workflow-templates/go.yml", Template: true})
+
+	if issues := set.Check(repo); len(issues) != 0 {
+		t.Errorf("Expected workflow templates to be ignored, got %v", issueSummary(issues))
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		expected      bool
+	}{
+		{"ci.yml", ".github/workflows/ci.yml", true},
+		{"ci.yml", ".github/workflows/ci.yaml", true},
+		{"deploy-*.yml", ".github/workflows/deploy-prod.yaml", true},
+		{".github/workflows/ci.yml", ".github/workflows/ci.yml", true},
+		{".github/workflows/ci.yml", "ci/workflows/ci.yml", false},
+		{"ci.yml", ".github/workflows/ci-extra.yml", false},
+	}
+	for _, tt := range tests {
+		if got := matches(tt.pattern, tt.path); got != tt.expected {
+			t.Errorf("matches(%q, %q) = %v, expected %v", tt.pattern, tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return file
+	}
+
+	set, err := Load(write("golden.json", `{
+		"class_property": "workflow-class",
+		"classes": [{"name": "service", "conditions": {"custom_properties": {"tier": "1"}}, "required": ["ci.yml"]}]
+	}`))
+	if err != nil {
+		t.Fatalf("Expected the golden set to load, got %v", err)
+	}
+	if got := set.Properties(); !reflect.DeepEqual(got, []string{"tier", "workflow-class"}) {
+		t.Errorf("Expected the class property and condition properties, got %v", got)
+	}
+
+	invalid := map[string]string{
+		"empty.json":     `{"classes": []}`,
+		"duplicate.json": `{"classes": [{"name": "a"}, {"name": "a"}]}`,
+		"pattern.json":   `{"classes": [{"name": "a", "required": ["[ci.yml"]}]}`,
+		"regex.json":     `{"classes": [{"name": "a", "conditions": {"repository_pattern": "("}}]}`,
+		"default.json":   `{"default": "b", "classes": [{"name": "a"}]}`,
+	}
+	for name, content := range invalid {
+		if _, err := Load(write(name, content)); err == nil || !strings.Contains(err.Error(), "golden set") {
+			t.Errorf("Expected %s to be rejected, got %v", name, err)
+		}
+	}
+}
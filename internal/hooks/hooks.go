@@ -0,0 +1,206 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/pr"
+)
+
+// DefaultTimeout bounds a single hook invocation
+const DefaultTimeout = 30 * time.Second
+
+// Event types sent to hooks
+const (
+	EventIssue = "issue" // One issue found by scan
+	EventPlan  = "plan"  // One repository's planned updates, before create-pr opens a PR
+)
+
+// Event is the JSON document a hook receives on stdin (command) or as the request body (webhook)
+type Event struct {
+	Type           string              `json:"event"`
+	Repository     string              `json:"repository"`            // Scanned repository full name
+	BaseBranch     string              `json:"base_branch,omitempty"` // Branch a planned pull request targets, when not the default
+	Issue          *output.ActionIssue `json:"issue,omitempty"`
+	Updates        []PlanUpdate        `json:"updates,omitempty"`
+	RequiredChecks []string            `json:"required_checks,omitempty"` // Required checks of the target branch the planned updates may rename
+
+	ScriptCouplings []pr.ScriptCoupling `json:"script_couplings,omitempty"` // Run step scripts hardcoding versions or workflow files the planned updates change
+}
+
+// PlanUpdate is a single planned action update in a plan event
+type PlanUpdate struct {
+	FilePath         string             `json:"file_path"`
+	Action           string             `json:"action"`
+	CurrentVersion   string             `json:"current_version"`
+	TargetVersion    string             `json:"target_version"`
+	TargetRepository string             `json:"target_repository,omitempty"`
+	Issue            output.ActionIssue `json:"issue"`
+}
+
+// Config holds configuration options for hooks
+type Config struct {
+	Verbose    bool
+	Command    string        // Shell command run once per event with the event JSON on stdin
+	WebhookURL string        // URL the event JSON is POSTed to
+	Timeout    time.Duration // Zero uses DefaultTimeout
+	HTTPClient *http.Client  // Nil uses the default client
+}
+
+// Runner delivers events to the configured command and webhook
+type Runner struct {
+	command    string
+	webhookURL string
+	timeout    time.Duration
+	httpClient *http.Client
+	verbose    bool
+}
+
+// NewRunner creates a hook runner, or returns nil when no hook is configured
+func NewRunner(config *Config) *Runner {
+	if config == nil || (config.Command == "" && config.WebhookURL == "") {
+		return nil
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Runner{
+		command:    config.Command,
+		webhookURL: config.WebhookURL,
+		timeout:    timeout,
+		httpClient: httpClient,
+		verbose:    config.Verbose,
+	}
+}
+
+// IssueEvent builds the event for an issue found in a repository
+func IssueEvent(repoFullName string, issue output.ActionIssue) Event {
+	return Event{Type: EventIssue, Repository: repoFullName, Issue: &issue}
+}
+
+// PlanEvent builds the event for a repository's planned updates
+func PlanEvent(plan pr.UpdatePlan) Event {
+	event := Event{Type: EventPlan, Repository: plan.Repository.FullName, BaseBranch: plan.BaseBranch}
+	for _, update := range plan.Updates {
+		event.Updates = append(event.Updates, PlanUpdate{
+			FilePath:         update.FilePath,
+			Action:           update.ActionRepo,
+			CurrentVersion:   update.CurrentVersion,
+			TargetVersion:    update.TargetVersion,
+			TargetRepository: update.TargetRepo,
+			Issue:            update.Issue,
+		})
+	}
+	for _, check := range plan.RequiredChecks {
+		event.RequiredChecks = append(event.RequiredChecks, check.Check)
+	}
+	event.ScriptCouplings = plan.ScriptCouplings
+	return event
+}
+
+// Run delivers an event to every configured hook
+// An error means a hook failed or rejected the event: the command exited non-zero
+// or the webhook answered with a non-2xx status.
+func (r *Runner) Run(event Event) error {
+	if r == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode hook event: %w", err)
+	}
+
+	if r.command != "" {
+		if err := r.runCommand(event, payload); err != nil {
+			return err
+		}
+	}
+	if r.webhookURL != "" {
+		if err := r.postWebhook(payload); err != nil {
+			return err
+		}
+	}
+
+	if r.verbose {
+		log.Printf("Hook accepted %s event for %s", event.Type, event.Repository)
+	}
+	return nil
+}
+
+// runCommand runs the hook command through the platform shell with the event on stdin
+func (r *Runner) runCommand(event Event, payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", r.command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", r.command)
+	}
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Env = append(os.Environ(),
+		"ACTIONS_MAINTAINER_EVENT="+event.Type,
+		"ACTIONS_MAINTAINER_REPOSITORY="+event.Repository,
+	)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.Output()
+	if r.verbose && len(stdout) > 0 {
+		log.Printf("Hook command output for %s: %s", event.Repository, strings.TrimSpace(string(stdout)))
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("hook command timed out after %s", r.timeout)
+	}
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return fmt.Errorf("hook command failed: %w: %s", err, message)
+		}
+		return fmt.Errorf("hook command failed: %w", err)
+	}
+	return nil
+}
+
+// postWebhook POSTs the event to the webhook URL
+func (r *Runner) postWebhook(payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("invalid hook URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("hook webhook failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("hook webhook returned %s", resp.Status)
+	}
+	return nil
+}
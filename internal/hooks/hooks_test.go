@@ -0,0 +1,98 @@
+package hooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/pr"
+)
+
+func TestNewRunner_Unconfigured(t *testing.T) {
+	runner := NewRunner(&Config{})
+	if runner != nil {
+		t.Fatalf("Expected nil runner when no hook is configured")
+	}
+	if err := runner.Run(Event{Type: EventIssue}); err != nil {
+		t.Errorf("Expected nil runner to accept events, got %v", err)
+	}
+}
+
+func TestRun_Command(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell command")
+	}
+
+	outputFile := filepath.Join(t.TempDir(), "event.json")
+	runner := NewRunner(&Config{Command: `cat > "` + outputFile + `"; test "$ACTIONS_MAINTAINER_EVENT" = issue`})
+
+	issue := output.ActionIssue{Repository: "actions/checkout", CurrentVersion: "v3", IssueType: "outdated"}
+	if err := runner.Run(IssueEvent("my-org/api", issue)); err != nil {
+		t.Fatalf("Run() returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Expected hook to receive the event: %v", err)
+	}
+	var event Event
+	if err := json.Unmarshal(data, &event); err != nil {
+		t.Fatalf("Expected JSON on stdin: %v", err)
+	}
+	if event.Type != EventIssue || event.Repository != "my-org/api" || event.Issue == nil || event.Issue.Repository != "actions/checkout" {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+}
+
+func TestRun_CommandRejects(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell command")
+	}
+
+	runner := NewRunner(&Config{Command: "echo not approved >&2; exit 3"})
+	err := runner.Run(Event{Type: EventPlan, Repository: "my-org/api"})
+	if err == nil || !strings.Contains(err.Error(), "not approved") {
+		t.Errorf("Expected rejection with command stderr, got %v", err)
+	}
+}
+
+func TestRun_Webhook(t *testing.T) {
+	var received Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected JSON content type, got %q", r.Header.Get("Content-Type"))
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		if received.Repository == "my-org/blocked" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	runner := NewRunner(&Config{WebhookURL: server.URL})
+	plan := pr.UpdatePlan{
+		Repository: github.Repository{FullName: "my-org/api"},
+		Updates: []pr.ActionUpdate{
+			{FilePath: ".github/workflows/ci.yml", ActionRepo: "actions/checkout", CurrentVersion: "v3", TargetVersion: "v4"},
+		},
+	}
+
+	if err := runner.Run(PlanEvent(plan)); err != nil {
+		t.Fatalf("Run() returned error: %v", err)
+	}
+	if received.Type != EventPlan || len(received.Updates) != 1 || received.Updates[0].TargetVersion != "v4" {
+		t.Errorf("Unexpected webhook event: %+v", received)
+	}
+
+	plan.Repository.FullName = "my-org/blocked"
+	if err := runner.Run(PlanEvent(plan)); err == nil {
+		t.Errorf("Expected non-2xx webhook response to reject the plan")
+	}
+}
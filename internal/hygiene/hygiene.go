@@ -0,0 +1,364 @@
+package hygiene
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// Hygiene checks, also used as issue types
+const (
+	CheckMissingTimeout   = "missing-timeout"    // Jobs without timeout-minutes run for up to 6 hours
+	CheckContinueOnError  = "continue-on-error"  // Jobs with continue-on-error: true never fail the run
+	CheckFailFastDisabled = "fail-fast-disabled" // Every job sets strategy.fail-fast: false
+
+	CheckMissingConcurrency      = "missing-concurrency"        // Deploy workflows without a concurrency group can deploy twice at once
+	CheckMissingCancelInProgress = "missing-cancel-in-progress" // Pull request workflows keep running superseded commits
+	CheckCacheKey                = "cache-key"                  // actions/cache keys that never refresh, never hit, or mix operating systems
+	CheckJobGraph                = "job-graph"                  // needs naming missing jobs, cycles, unreachable jobs, or outputs of missing jobs
+)
+
+// AllChecks lists every hygiene check
+var AllChecks = []string{
+	CheckMissingTimeout, CheckContinueOnError, CheckFailFastDisabled,
+	CheckMissingConcurrency, CheckMissingCancelInProgress, CheckCacheKey, CheckJobGraph,
+}
+
+// Standard concurrency groups added by create-pr. Deploys of a ref queue behind each other instead of
+// being cancelled halfway, while pull request runs of a newer commit cancel the older ones.
+const (
+	DeployConcurrencyGroup      = "${{ github.workflow }}-${{ github.ref }}"
+	PullRequestConcurrencyGroup = "${{ github.workflow }}-${{ github.event.pull_request.number || github.ref }}"
+)
+
+// Config holds configuration options for hygiene analysis
+type Config struct {
+	Verbose bool
+	Checks  []string // Enabled checks; empty enables none
+}
+
+// Analyzer flags workflow settings that hide failures or waste runner time
+type Analyzer struct {
+	checks  map[string]bool
+	verbose bool
+}
+
+// NewAnalyzer creates an analyzer running the given checks
+func NewAnalyzer(checks []string) *Analyzer {
+	return NewAnalyzerWithConfig(&Config{Verbose: false, Checks: checks})
+}
+
+// NewAnalyzerWithConfig creates an analyzer with configuration
+func NewAnalyzerWithConfig(config *Config) *Analyzer {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	checks := make(map[string]bool, len(config.Checks))
+	for _, check := range config.Checks {
+		checks[check] = true
+	}
+
+	return &Analyzer{
+		checks:  checks,
+		verbose: config.Verbose,
+	}
+}
+
+// ParseChecks parses a comma-separated list of checks, where "all" enables every check
+func ParseChecks(value string) ([]string, error) {
+	var checks []string
+	for _, check := range strings.Split(value, ",") {
+		check = strings.TrimSpace(check)
+		switch {
+		case check == "":
+			continue
+		case check == "all":
+			return AllChecks, nil
+		case !isCheck(check):
+			return nil, fmt.Errorf("unknown hygiene check %q: use %s, or all", check, strings.Join(AllChecks, ", "))
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// isCheck reports whether name is a known hygiene check
+func isCheck(name string) bool {
+	for _, check := range AllChecks {
+		if check == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Enabled reports whether any check is enabled
+func (a *Analyzer) Enabled() bool {
+	return len(a.checks) > 0
+}
+
+// Analyze returns low-severity issues for the jobs of a single workflow file
+// The issue Repository field holds the workflow key at fault (e.g., "timeout-minutes").
+func (a *Analyzer) Analyze(filePath string, jobs []workflow.JobSettings) []output.ActionIssue {
+	var issues []output.ActionIssue
+
+	for _, job := range jobs {
+		// Reusable workflow calls cannot set timeout-minutes; the called workflow's jobs must
+		if a.checks[CheckMissingTimeout] && !job.HasTimeout && !job.Reusable {
+			issues = append(issues, output.ActionIssue{
+				Repository:  "timeout-minutes",
+				IssueType:   CheckMissingTimeout,
+				Severity:    "low",
+				Description: fmt.Sprintf("Job '%s' has no timeout-minutes, so a hung run holds a runner for the 6 hour default", job.Job),
+				Context:     fmt.Sprintf("job:%s", job.Job),
+				FilePath:    filePath,
+			})
+		}
+
+		if a.checks[CheckContinueOnError] && job.ContinueOnError {
+			issues = append(issues, output.ActionIssue{
+				Repository:  "continue-on-error",
+				IssueType:   CheckContinueOnError,
+				Severity:    "low",
+				Description: fmt.Sprintf("Job '%s' sets continue-on-error: true, so its failures never fail the workflow", job.Job),
+				Context:     fmt.Sprintf("job:%s", job.Job),
+				FilePath:    filePath,
+			})
+		}
+	}
+
+	if a.checks[CheckFailFastDisabled] && len(jobs) > 0 && allFailFastDisabled(jobs) {
+		issues = append(issues, output.ActionIssue{
+			Repository:  "strategy.fail-fast",
+			IssueType:   CheckFailFastDisabled,
+			Severity:    "low",
+			Description: fmt.Sprintf("All %d jobs set strategy.fail-fast: false, so every matrix combination runs to completion after a failure", len(jobs)),
+			Context:     "jobs:" + jobNames(jobs),
+			FilePath:    filePath,
+		})
+	}
+
+	if a.verbose && len(issues) > 0 {
+		log.Printf("Found %d hygiene issues in %s", len(issues), filePath)
+	}
+
+	return issues
+}
+
+// AnalyzeConcurrency returns low-severity issues for the concurrency policy of a single workflow file
+// Each issue carries the standard concurrency block create-pr adds to fix it.
+func (a *Analyzer) AnalyzeConcurrency(settings *workflow.ConcurrencySettings) []output.ActionIssue {
+	var issues []output.ActionIssue
+
+	if ungrouped := settings.DeploysUngrouped(); a.checks[CheckMissingConcurrency] && len(ungrouped) > 0 {
+		issues = append(issues, output.ActionIssue{
+			Repository:  "concurrency",
+			IssueType:   CheckMissingConcurrency,
+			Severity:    "low",
+			Description: fmt.Sprintf("Deploy jobs %s have no concurrency group, so two runs can deploy at the same time", strings.Join(ungrouped, ", ")),
+			Context:     "jobs:" + strings.Join(ungrouped, ","),
+			FilePath:    settings.FilePath,
+			Concurrency: &output.ConcurrencyRemediation{Group: DeployConcurrencyGroup, CancelInProgress: false},
+		})
+	}
+
+	// Deploy workflows are left alone: cancelling a deploy halfway can leave an environment broken
+	if a.checks[CheckMissingCancelInProgress] && settings.PullRequest && !settings.Deploys() && settings.CancelInProgress == "" {
+		group := settings.Group
+		if group == "" {
+			group = PullRequestConcurrencyGroup
+		}
+		issues = append(issues, output.ActionIssue{
+			Repository:  "concurrency.cancel-in-progress",
+			IssueType:   CheckMissingCancelInProgress,
+			Severity:    "low",
+			Description: "Pull request runs do not set cancel-in-progress, so pushing a new commit leaves runs of the superseded one using runner minutes",
+			Context:     "workflow",
+			FilePath:    settings.FilePath,
+			Concurrency: &output.ConcurrencyRemediation{Group: group, CancelInProgress: true},
+		})
+	}
+
+	if a.verbose && len(issues) > 0 {
+		log.Printf("Found %d concurrency issues in %s", len(issues), settings.FilePath)
+	}
+
+	return issues
+}
+
+// cacheTool is a dependency cache recognized from the path it caches, with the files its key should hash
+type cacheTool struct {
+	pathMarkers []string
+	name        string
+	hashFiles   string
+}
+
+// cacheTools are the caches suggested keys are derived for, matched on the path input in order
+var cacheTools = []cacheTool{
+	{[]string{"node_modules", ".npm"}, "npm", "'**/package-lock.json'"},
+	{[]string{"yarn"}, "yarn", "'**/yarn.lock'"},
+	{[]string{"pnpm"}, "pnpm", "'**/pnpm-lock.yaml'"},
+	{[]string{"go-build", "go/pkg/mod"}, "go", "'**/go.sum'"},
+	{[]string{".m2"}, "maven", "'**/pom.xml'"},
+	{[]string{".gradle"}, "gradle", "'**/*.gradle*', '**/gradle-wrapper.properties'"},
+	{[]string{"pip", "pypoetry", ".venv"}, "pip", "'**/requirements*.txt', '**/poetry.lock'"},
+	{[]string{".cargo", "target"}, "cargo", "'**/Cargo.lock'"},
+	{[]string{".nuget"}, "nuget", "'**/packages.lock.json'"},
+	{[]string{"bundle"}, "gems", "'**/Gemfile.lock'"},
+	{[]string{"composer"}, "composer", "'**/composer.lock'"},
+}
+
+// perRunExpressions change on every run, so a key using them is never hit again
+var perRunExpressions = []string{"github.sha", "github.run_id", "github.run_number", "github.run_attempt"}
+
+// AnalyzeCacheSteps returns low-severity advisories for the actions/cache keys of a single workflow file:
+// static keys never refresh, keys not hashing dependency files never follow dependency changes or miss on
+// every run, and keys without the operating system restore caches built on another runner. Each advisory
+// carries a suggested key template derived from the cached path.
+func (a *Analyzer) AnalyzeCacheSteps(steps []workflow.CacheStep) []output.ActionIssue {
+	if !a.checks[CheckCacheKey] {
+		return nil
+	}
+
+	var issues []output.ActionIssue
+	for _, step := range steps {
+		var problems []string
+		key := strings.ToLower(step.Key)
+		switch {
+		case step.Key == "":
+			continue // Missing keys fail the step, so the run already reports them
+		case !strings.Contains(key, "${{"):
+			problems = append(problems, "is static, so the cache is saved once and never refreshed")
+		case containsAny(key, perRunExpressions):
+			problems = append(problems, "changes on every run, so it is never hit again and every run saves a new cache")
+		case !strings.Contains(key, "hashfiles("):
+			problems = append(problems, "does not hash dependency files with hashFiles(), so it does not change when dependencies do")
+		}
+		if step.RunnerVaries() && !keyNamesRunner(key, strings.ToLower(step.RunsOn)) {
+			problems = append(problems, fmt.Sprintf("does not include the operating system, though the job runs on %s, so caches built on one OS are restored on another", step.RunsOn))
+		}
+		if len(problems) == 0 {
+			continue
+		}
+
+		_, version, _ := strings.Cut(step.Uses, "@")
+		suggested := suggestCacheKey(step.Path)
+		issues = append(issues, output.ActionIssue{
+			Repository:        "actions/cache",
+			CurrentVersion:    version,
+			IssueType:         CheckCacheKey,
+			Severity:          "low",
+			Description:       fmt.Sprintf("Cache key '%s' %s; consider key: %s", step.Key, strings.Join(problems, " and "), suggested),
+			Context:           step.Context,
+			FilePath:          step.FilePath,
+			SuggestedCacheKey: suggested,
+		})
+	}
+
+	if a.verbose && len(issues) > 0 {
+		log.Printf("Found %d cache key issues", len(issues))
+	}
+
+	return issues
+}
+
+// jobGraphSeverities rate job graph problems: GitHub rejects workflows with undefined needs or cycles,
+// while unreachable jobs are skipped and outputs of missing jobs are empty without failing the run
+var jobGraphSeverities = map[string]string{
+	workflow.JobGraphUndefinedNeed:   "high",
+	workflow.JobGraphCycle:           "high",
+	workflow.JobGraphUnreachable:     "medium",
+	workflow.JobGraphUndefinedOutput: "medium",
+}
+
+// AnalyzeJobGraph returns issues for the job dependency graph of a single workflow file. Unlike the
+// other hygiene checks they are not low severity, since a broken graph fails or skips work, most
+// often after a job of a reusable workflow was renamed.
+func (a *Analyzer) AnalyzeJobGraph(filePath string, problems []workflow.JobGraphProblem) []output.ActionIssue {
+	if !a.checks[CheckJobGraph] {
+		return nil
+	}
+
+	var issues []output.ActionIssue
+	for _, problem := range problems {
+		repository, context := "needs", "job:"+strings.Join(problem.Jobs, "")
+		switch problem.Kind {
+		case workflow.JobGraphCycle:
+			context = "jobs:" + strings.Join(problem.Jobs, ",")
+		case workflow.JobGraphUnreachable:
+			repository = "if"
+		case workflow.JobGraphUndefinedOutput:
+			repository, context = "on.workflow_call.outputs", "output:"+strings.Join(problem.Jobs, "")
+		}
+		issues = append(issues, output.ActionIssue{
+			Repository:  repository,
+			IssueType:   CheckJobGraph,
+			Severity:    jobGraphSeverities[problem.Kind],
+			Description: fmt.Sprintf("Line %d: %s", problem.Line, problem.Message),
+			Context:     context,
+			FilePath:    filePath,
+		})
+	}
+
+	if a.verbose && len(issues) > 0 {
+		log.Printf("Found %d job graph issues in %s", len(issues), filePath)
+	}
+
+	return issues
+}
+
+// keyNamesRunner reports whether a lowercased key distinguishes runners: it uses runner.os or the
+// expression runs-on is chosen by, such as matrix.os
+func keyNamesRunner(key, runsOn string) bool {
+	if strings.Contains(key, "runner.os") {
+		return true
+	}
+	inner := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(runsOn, "${{"), "}}"))
+	return inner != "" && strings.Contains(key, inner)
+}
+
+// suggestCacheKey returns a key template for a cached path: the runner OS, the tool, and a hash of its lock files
+func suggestCacheKey(path string) string {
+	path = strings.ToLower(path)
+	for _, tool := range cacheTools {
+		if containsAny(path, tool.pathMarkers) {
+			return fmt.Sprintf("${{ runner.os }}-%s-${{ hashFiles(%s) }}", tool.name, tool.hashFiles)
+		}
+	}
+	return "${{ runner.os }}-cache-${{ hashFiles('<lock file>') }}"
+}
+
+// containsAny reports whether s contains any of the substrings
+func containsAny(s string, substrings []string) bool {
+	for _, substring := range substrings {
+		if strings.Contains(s, substring) {
+			return true
+		}
+	}
+	return false
+}
+
+// allFailFastDisabled reports whether every job disables fail-fast
+func allFailFastDisabled(jobs []workflow.JobSettings) bool {
+	for _, job := range jobs {
+		if !job.FailFastDisabled {
+			return false
+		}
+	}
+	return true
+}
+
+// jobNames returns the sorted job IDs joined with commas
+func jobNames(jobs []workflow.JobSettings) string {
+	names := make([]string, len(jobs))
+	for i, job := range jobs {
+		names[i] = job.Job
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
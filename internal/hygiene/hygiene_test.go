@@ -0,0 +1,168 @@
+package hygiene
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+const ciPath = ".github/workflows/ci.yml"
+
+func TestParseChecks(t *testing.T) {
+	checks, err := ParseChecks("missing-timeout, continue-on-error")
+	if err != nil || !reflect.DeepEqual(checks, []string{CheckMissingTimeout, CheckContinueOnError}) {
+		t.Errorf("Unexpected checks %v, error %v", checks, err)
+	}
+	if checks, _ := ParseChecks("all"); !reflect.DeepEqual(checks, AllChecks) {
+		t.Errorf("Expected all checks, got %v", checks)
+	}
+	if _, err := ParseChecks("missing-timeout,typo"); err == nil {
+		t.Errorf("Expected an error for an unknown check")
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	jobs := []workflow.JobSettings{
+		{FilePath: ciPath, Job: "lint", ContinueOnError: true, FailFastDisabled: true},
+		{FilePath: ciPath, Job: "release", Reusable: true, FailFastDisabled: true},
+		{FilePath: ciPath, Job: "test", HasTimeout: true, FailFastDisabled: true},
+	}
+
+	issues := NewAnalyzer(AllChecks).Analyze(ciPath, jobs)
+
+	var types []string
+	for _, issue := range issues {
+		types = append(types, issue.IssueType+" "+issue.Context)
+		if issue.Severity != "low" || issue.FilePath != ciPath {
+			t.Errorf("Unexpected issue %+v", issue)
+		}
+	}
+	// The reusable workflow call cannot set timeout-minutes
+	expected := []string{
+		"missing-timeout job:lint",
+		"continue-on-error job:lint",
+		"fail-fast-disabled jobs:lint,release,test",
+	}
+	if !reflect.DeepEqual(types, expected) {
+		t.Errorf("Expected %v, got %v", expected, types)
+	}
+}
+
+func TestAnalyze_ToggledChecks(t *testing.T) {
+	jobs := []workflow.JobSettings{
+		{FilePath: ciPath, Job: "lint", ContinueOnError: true, FailFastDisabled: true},
+		{FilePath: ciPath, Job: "test"},
+	}
+
+	if issues := NewAnalyzer(nil).Analyze(ciPath, jobs); len(issues) != 0 {
+		t.Errorf("Expected no issues with every check disabled, got %+v", issues)
+	}
+
+	issues := NewAnalyzer([]string{CheckFailFastDisabled, CheckContinueOnError}).Analyze(ciPath, jobs)
+	// fail-fast is only flagged when every job disables it
+	if len(issues) != 1 || issues[0].IssueType != CheckContinueOnError {
+		t.Errorf("Expected only the continue-on-error issue, got %+v", issues)
+	}
+}
+
+func TestAnalyzeConcurrency(t *testing.T) {
+	analyzer := NewAnalyzer([]string{CheckMissingConcurrency, CheckMissingCancelInProgress})
+
+	deploy := &workflow.ConcurrencySettings{FilePath: ".github/workflows/deploy.yml", DeployJobs: []string{"production", "staging"}, GroupedJobs: []string{"staging"}}
+	issues := analyzer.AnalyzeConcurrency(deploy)
+	if len(issues) != 1 || issues[0].IssueType != CheckMissingConcurrency || issues[0].Context != "jobs:production" {
+		t.Fatalf("Expected a missing concurrency issue for the production job, got %+v", issues)
+	}
+	if remediation := issues[0].Concurrency; remediation == nil || remediation.Group != DeployConcurrencyGroup || remediation.CancelInProgress {
+		t.Errorf("Expected deploys to queue in the standard group, got %+v", remediation)
+	}
+
+	ci := &workflow.ConcurrencySettings{FilePath: ciPath, Group: "ci-${{ github.ref }}", PullRequest: true}
+	issues = analyzer.AnalyzeConcurrency(ci)
+	if len(issues) != 1 || issues[0].IssueType != CheckMissingCancelInProgress {
+		t.Fatalf("Expected a missing cancel-in-progress issue, got %+v", issues)
+	}
+	if remediation := issues[0].Concurrency; remediation == nil || remediation.Group != "ci-${{ github.ref }}" || !remediation.CancelInProgress {
+		t.Errorf("Expected the existing group to be kept with cancel-in-progress, got %+v", remediation)
+	}
+
+	ci.CancelInProgress = "false"
+	if issues := analyzer.AnalyzeConcurrency(ci); len(issues) != 0 {
+		t.Errorf("Expected an explicit cancel-in-progress to be respected, got %+v", issues)
+	}
+
+	if issues := NewAnalyzer([]string{CheckMissingTimeout}).AnalyzeConcurrency(deploy); len(issues) != 0 {
+		t.Errorf("Expected disabled checks to raise nothing, got %+v", issues)
+	}
+}
+
+func TestAnalyzeCacheSteps(t *testing.T) {
+	steps := []workflow.CacheStep{
+		{FilePath: ciPath, Job: "build", Context: "job:build/step:step-1", Uses: "actions/cache@v4", Key: "deps", Path: "node_modules", RunsOn: "ubuntu-latest"},
+		{FilePath: ciPath, Job: "build", Context: "job:build/step:step-2", Uses: "actions/cache@v4", Key: "build-${{ github.sha }}", Path: "target", RunsOn: "ubuntu-latest"},
+		{FilePath: ciPath, Job: "test", Context: "job:test/step:step-1", Uses: "actions/cache@v3", Key: "go-${{ hashFiles('**/go.sum') }}", Path: "~/go/pkg/mod", RunsOn: "${{ matrix.os }}"},
+		{FilePath: ciPath, Job: "test", Context: "job:test/step:step-2", Uses: "actions/cache@v4", Key: "${{ matrix.os }}-go-${{ hashFiles('**/go.sum') }}", Path: "~/go/pkg/mod", RunsOn: "${{ matrix.os }}"},
+		{FilePath: ciPath, Job: "lint", Context: "job:lint/step:step-1", Uses: "actions/cache@v4", Key: "${{ runner.os }}-pip-${{ hashFiles('**/requirements.txt') }}", Path: "~/.cache/pip", RunsOn: "ubuntu-latest"},
+	}
+
+	issues := NewAnalyzer([]string{CheckCacheKey}).AnalyzeCacheSteps(steps)
+	if len(issues) != 3 {
+		t.Fatalf("Expected 3 cache key issues, got %+v", issues)
+	}
+
+	expected := []struct{ context, problem, suggested string }{
+		{"job:build/step:step-1", "is static", "${{ runner.os }}-npm-${{ hashFiles('**/package-lock.json') }}"},
+		{"job:build/step:step-2", "changes on every run", "${{ runner.os }}-cargo-${{ hashFiles('**/Cargo.lock') }}"},
+		{"job:test/step:step-1", "does not include the operating system", "${{ runner.os }}-go-${{ hashFiles('**/go.sum') }}"},
+	}
+	for i, issue := range issues {
+		if issue.Context != expected[i].context || issue.SuggestedCacheKey != expected[i].suggested ||
+			!strings.Contains(issue.Description, expected[i].problem) {
+			t.Errorf("Unexpected issue %d: %+v", i, issue)
+		}
+		if issue.IssueType != CheckCacheKey || issue.Repository != "actions/cache" || issue.Severity != "low" {
+			t.Errorf("Unexpected issue fields: %+v", issue)
+		}
+	}
+	if issues[2].CurrentVersion != "v3" {
+		t.Errorf("Expected the cache action version, got %q", issues[2].CurrentVersion)
+	}
+
+	if issues := NewAnalyzer([]string{CheckMissingTimeout}).AnalyzeCacheSteps(steps); len(issues) != 0 {
+		t.Errorf("Expected no issues when the check is disabled, got %+v", issues)
+	}
+}
+
+func TestAnalyzeJobGraph(t *testing.T) {
+	problems := []workflow.JobGraphProblem{
+		{Kind: workflow.JobGraphUndefinedNeed, Jobs: []string{"deploy"}, Line: 12, Message: `job "deploy" needs undefined job "build"`},
+		{Kind: workflow.JobGraphCycle, Jobs: []string{"a", "b"}, Line: 3, Message: "jobs a -> b -> a need each other in a cycle"},
+		{Kind: workflow.JobGraphUnreachable, Jobs: []string{"test"}, Line: 20, Message: `job "test" never runs: it needs job "lint", which is disabled with if: false`},
+		{Kind: workflow.JobGraphUndefinedOutput, Jobs: []string{"version"}, Line: 6, Message: `workflow_call output "version" reads jobs.build, which is not a job of the workflow`},
+	}
+
+	issues := NewAnalyzer([]string{CheckJobGraph}).AnalyzeJobGraph(ciPath, problems)
+	expected := []struct{ repository, context, severity string }{
+		{"needs", "job:deploy", "high"},
+		{"needs", "jobs:a,b", "high"},
+		{"if", "job:test", "medium"},
+		{"on.workflow_call.outputs", "output:version", "medium"},
+	}
+	if len(issues) != len(expected) {
+		t.Fatalf("Expected %d job graph issues, got %+v", len(expected), issues)
+	}
+	for i, issue := range issues {
+		if issue.Repository != expected[i].repository || issue.Context != expected[i].context || issue.Severity != expected[i].severity {
+			t.Errorf("Unexpected issue %d: %+v", i, issue)
+		}
+		if issue.IssueType != CheckJobGraph || issue.FilePath != ciPath || !strings.HasPrefix(issue.Description, "Line ") {
+			t.Errorf("Unexpected issue fields: %+v", issue)
+		}
+	}
+
+	if issues := NewAnalyzer([]string{CheckCacheKey}).AnalyzeJobGraph(ciPath, problems); len(issues) != 0 {
+		t.Errorf("Expected no issues when the check is disabled, got %+v", issues)
+	}
+}
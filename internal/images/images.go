@@ -0,0 +1,417 @@
+package images
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// Issue types raised by registry checks
+const (
+	IssueTypeMissingImageTag = "missing-image-tag"  // The tag or digest no longer exists, so jobs fail to start
+	IssueTypeStaleImage      = "stale-image"        // The image was built longer ago than the maximum age
+	IssueTypeArchitecture    = "image-architecture" // The image is not published for the architecture of the job's runner
+)
+
+// DefaultMaxAgeDays is the image age flagged as stale when no maximum is configured
+const DefaultMaxAgeDays = 365
+
+// DefaultTimeout bounds a registry request when no HTTP client is supplied
+const DefaultTimeout = 30 * time.Second
+
+// manifestMediaTypes are the manifest and index formats requested from registries
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// challengeParamPattern matches the key="value" parameters of a WWW-Authenticate challenge
+var challengeParamPattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// Config holds configuration options for registry checks
+type Config struct {
+	Verbose    bool
+	MaxAgeDays int          // Age in days flagged as stale; zero uses DefaultMaxAgeDays
+	HTTPClient *http.Client // Nil uses a client with DefaultTimeout
+}
+
+// Checker looks up container images in their registries
+// Lookups are anonymous, so only public images can be checked. Results are cached per image
+// reference, as the same image is typically used across many repositories.
+type Checker struct {
+	httpClient *http.Client
+	maxAgeDays int
+	verbose    bool
+	now        func() time.Time
+	results    map[string]lookup
+	tokens     map[string]string // bearer tokens by registry and repository
+	warned     map[string]bool   // registries a lookup failure was already reported for
+}
+
+// lookup is the registry state of an image reference
+type lookup struct {
+	created   *time.Time
+	platforms []string // "os/architecture" of each published image; empty when the registry does not say
+	missing   bool
+	err       error
+}
+
+// manifest is an image manifest or a multi-platform index
+type manifest struct {
+	Manifests []struct {
+		Digest   string `json:"digest"`
+		Platform struct {
+			OS           string `json:"os"`
+			Architecture string `json:"architecture"`
+			Variant      string `json:"variant"`
+		} `json:"platform"`
+	} `json:"manifests"`
+	Config struct {
+		Digest string `json:"digest"`
+	} `json:"config"`
+}
+
+// NewChecker creates a registry checker flagging images older than maxAgeDays
+func NewChecker(maxAgeDays int) *Checker {
+	return NewCheckerWithConfig(&Config{Verbose: false, MaxAgeDays: maxAgeDays})
+}
+
+// NewCheckerWithConfig creates a registry checker with configuration
+func NewCheckerWithConfig(config *Config) *Checker {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	maxAgeDays := config.MaxAgeDays
+	if maxAgeDays <= 0 {
+		maxAgeDays = DefaultMaxAgeDays
+	}
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: DefaultTimeout}
+	}
+
+	return &Checker{
+		httpClient: httpClient,
+		maxAgeDays: maxAgeDays,
+		verbose:    config.Verbose,
+		now:        time.Now,
+		results:    make(map[string]lookup),
+		tokens:     make(map[string]string),
+		warned:     make(map[string]bool),
+	}
+}
+
+// Check looks up each image, setting Created and Missing in place, and returns issues for
+// missing tags, stale images, and images not published for the architecture of their job's runner. Images that cannot be looked up, such as private ones, are left
+// unchecked; a warning is logged for the first failure per registry.
+func (c *Checker) Check(images []workflow.ContainerImage) []output.ActionIssue {
+	var issues []output.ActionIssue
+	for i := range images {
+		image := &images[i]
+		result := c.lookup(*image)
+		if result.err != nil {
+			if !c.warned[image.Registry] {
+				log.Printf("Warning: Unable to check images in %s, leaving them unchecked: %v", image.Registry, result.err)
+				c.warned[image.Registry] = true
+			} else if c.verbose {
+				log.Printf("Unable to check image %s: %v", image.Image, result.err)
+			}
+			continue
+		}
+
+		image.Missing = result.missing
+		image.Created = result.created
+		if issue, ok := architectureIssue(*image, result.platforms); ok {
+			issues = append(issues, issue)
+		}
+		switch {
+		case image.Missing:
+			issues = append(issues, output.ActionIssue{
+				Repository:     image.Name(),
+				CurrentVersion: reference(*image),
+				IssueType:      IssueTypeMissingImageTag,
+				Severity:       "high",
+				Description:    fmt.Sprintf("Image %s was not found in %s; jobs using it fail to start", image.Image, image.Registry),
+				Context:        imageContext(*image),
+				FilePath:       image.FilePath,
+			})
+		case image.Created != nil:
+			age := int(c.now().Sub(*image.Created).Hours() / 24)
+			if age <= c.maxAgeDays {
+				continue
+			}
+			issues = append(issues, output.ActionIssue{
+				Repository:     image.Name(),
+				CurrentVersion: reference(*image),
+				IssueType:      IssueTypeStaleImage,
+				Severity:       "low",
+				Description:    fmt.Sprintf("Image %s was built %d days ago (%s); consider a newer tag", image.Image, age, image.Created.Format("2006-01-02")),
+				Context:        imageContext(*image),
+				FilePath:       image.FilePath,
+			})
+		}
+	}
+	return issues
+}
+
+// architectureIssue returns an issue when an image is published for platforms, none of them the
+// Linux architecture of the image's runner. Jobs cannot start such a container, and docker:// steps
+// fail with "exec format error", as runners do not emulate other architectures.
+func architectureIssue(image workflow.ContainerImage, platforms []string) (output.ActionIssue, bool) {
+	if image.Runner == "" || len(platforms) == 0 || supports(platforms, "linux/"+image.Runner) {
+		return output.ActionIssue{}, false
+	}
+	return output.ActionIssue{
+		Repository:     image.Name(),
+		CurrentVersion: reference(image),
+		IssueType:      IssueTypeArchitecture,
+		Severity:       "high",
+		Description: fmt.Sprintf("Image %s is published for %s but the job runs on linux/%s runners; use a multi-platform tag or a runner of a published architecture",
+			image.Image, strings.Join(platforms, ", "), image.Runner),
+		Context:  imageContext(image),
+		FilePath: image.FilePath,
+	}, true
+}
+
+// supports reports whether platforms include a platform, ignoring variants such as the v8 of linux/arm64/v8
+func supports(platforms []string, platform string) bool {
+	for _, published := range platforms {
+		if published == platform || strings.HasPrefix(published, platform+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// reference returns the digest of an image, or its tag when it is not pinned to a digest
+func reference(image workflow.ContainerImage) string {
+	if image.Digest != "" {
+		return image.Digest
+	}
+	return image.Tag
+}
+
+// imageContext describes where an image is used, e.g. "job:test service:postgres"
+func imageContext(image workflow.ContainerImage) string {
+	switch image.Kind {
+	case workflow.ImageKindService:
+		return fmt.Sprintf("job:%s service:%s", image.Job, image.Service)
+	case workflow.ImageKindStep:
+		return fmt.Sprintf("job:%s step:%s", image.Job, image.Step)
+	}
+	return fmt.Sprintf("job:%s container", image.Job)
+}
+
+// lookup returns the cached registry state of an image, fetching it on first use
+func (c *Checker) lookup(image workflow.ContainerImage) lookup {
+	key := image.Registry + "/" + image.Repository + "@" + reference(image)
+	if result, ok := c.results[key]; ok {
+		return result
+	}
+
+	result := lookup{}
+	created, platforms, found, err := c.inspect(image.Registry, image.Repository, reference(image))
+	switch {
+	case err != nil:
+		result.err = err
+	case !found:
+		result.missing = true
+	default:
+		result.created = created
+		result.platforms = platforms
+	}
+	c.results[key] = result
+	return result
+}
+
+// inspect returns when an image was built and the platforms it is published for, or found false
+// when the registry has no such reference. Multi-platform images are dated by their linux/amd64
+// image, the platform of GitHub-hosted runners.
+func (c *Checker) inspect(registry, repository, ref string) (*time.Time, []string, bool, error) {
+	m, found, err := c.manifest(registry, repository, ref)
+	if err != nil || !found {
+		return nil, nil, found, err
+	}
+
+	var platforms []string
+	if len(m.Manifests) > 0 {
+		digest := m.Manifests[0].Digest
+		for _, entry := range m.Manifests {
+			// Attestation manifests are listed with an unknown platform
+			if entry.Platform.OS != "" && entry.Platform.OS != "unknown" {
+				platforms = append(platforms, entry.Platform.OS+"/"+entry.Platform.Architecture+variant(entry.Platform.Variant))
+			}
+		}
+		for _, entry := range m.Manifests {
+			if entry.Platform.OS == "linux" && entry.Platform.Architecture == "amd64" {
+				digest = entry.Digest
+				break
+			}
+		}
+		m, found, err = c.manifest(registry, repository, digest)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if !found {
+			return nil, nil, false, fmt.Errorf("platform manifest %s not found", digest)
+		}
+	}
+	if m.Config.Digest == "" {
+		return nil, platforms, true, nil
+	}
+
+	var config struct {
+		Created      *time.Time `json:"created"`
+		OS           string     `json:"os"`
+		Architecture string     `json:"architecture"`
+		Variant      string     `json:"variant"`
+	}
+	if _, err := c.getJSON(registry, repository, "blobs/"+m.Config.Digest, nil, &config); err != nil {
+		return nil, nil, false, err
+	}
+	if len(platforms) == 0 && config.OS != "" && config.Architecture != "" {
+		platforms = []string{config.OS + "/" + config.Architecture + variant(config.Variant)}
+	}
+	return config.Created, platforms, true, nil
+}
+
+// variant returns the "/variant" suffix of a platform, or "" when it has none
+func variant(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "/" + value
+}
+
+// manifest fetches the manifest or index of a tag or digest
+func (c *Checker) manifest(registry, repository, ref string) (*manifest, bool, error) {
+	m := &manifest{}
+	found, err := c.getJSON(registry, repository, "manifests/"+ref, manifestMediaTypes, m)
+	if err != nil || !found {
+		return nil, found, err
+	}
+	return m, true, nil
+}
+
+// getJSON decodes a registry API response, returning found false for 404 responses
+func (c *Checker) getJSON(registry, repository, path string, accept []string, target interface{}) (bool, error) {
+	resp, err := c.get(registry, repository, fmt.Sprintf("https://%s/v2/%s/%s", apiHost(registry), repository, path), accept)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("registry returned %s for %s", resp.Status, path)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return false, fmt.Errorf("failed to decode registry response: %w", err)
+	}
+	return true, nil
+}
+
+// get sends a registry request, answering a bearer challenge with an anonymous pull token once
+func (c *Checker) get(registry, repository, requestURL string, accept []string) (*http.Response, error) {
+	scope := registry + "/" + repository
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create registry request: %w", err)
+		}
+		if len(accept) > 0 {
+			req.Header.Set("Accept", strings.Join(accept, ", "))
+		}
+		if token := c.tokens[scope]; token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("registry request failed: %w", err)
+		}
+		if resp.StatusCode != http.StatusUnauthorized || attempt > 0 {
+			return resp, nil
+		}
+
+		challenge := resp.Header.Get("WWW-Authenticate")
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		token, err := c.token(challenge, repository)
+		if err != nil {
+			return nil, err
+		}
+		c.tokens[scope] = token
+	}
+}
+
+// token requests an anonymous pull token for a WWW-Authenticate bearer challenge
+func (c *Checker) token(challenge, repository string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("registry requires %s authentication", scheme)
+	}
+
+	values := make(map[string]string)
+	for _, match := range challengeParamPattern.FindAllStringSubmatch(params, -1) {
+		values[match[1]] = match[2]
+	}
+	if values["realm"] == "" {
+		return "", fmt.Errorf("registry authentication challenge has no realm")
+	}
+	if values["scope"] == "" {
+		values["scope"] = "repository:" + repository + ":pull"
+	}
+
+	query := url.Values{}
+	query.Set("scope", values["scope"])
+	if values["service"] != "" {
+		query.Set("service", values["service"])
+	}
+	resp, err := c.httpClient.Get(values["realm"] + "?" + query.Encode())
+	if err != nil {
+		return "", fmt.Errorf("registry token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry token request returned %s", resp.Status)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode registry token: %w", err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	if body.AccessToken != "" {
+		return body.AccessToken, nil
+	}
+	return "", fmt.Errorf("registry token response has no token")
+}
+
+// apiHost returns the host serving a registry's API; Docker Hub images are named docker.io but served elsewhere
+func apiHost(registry string) string {
+	if registry == workflow.DefaultImageRegistry {
+		return "registry-1.docker.io"
+	}
+	return registry
+}
@@ -0,0 +1,168 @@
+package images
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// newTestRegistry serves library/postgres:16 as a multi-platform index, library/redis:7 as a single
+// manifest, and requires an anonymous bearer token for every API request
+func newTestRegistry(t *testing.T, requests *int) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if r.URL.Path == "/token" {
+			if r.URL.Query().Get("scope") == "" {
+				t.Errorf("Expected token request to carry a scope")
+			}
+			fmt.Fprint(w, `{"token":"pull-token"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer pull-token" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test-registry"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v2/library/postgres/manifests/16":
+			fmt.Fprint(w, `{"manifests":[
+				{"digest":"sha256:arm","platform":{"os":"linux","architecture":"arm64","variant":"v8"}},
+				{"digest":"sha256:amd","platform":{"os":"linux","architecture":"amd64"}}]}`)
+		case "/v2/library/postgres/manifests/sha256:amd":
+			fmt.Fprint(w, `{"config":{"digest":"sha256:postgres-config"}}`)
+		case "/v2/library/postgres/blobs/sha256:postgres-config":
+			fmt.Fprint(w, `{"created":"2026-09-01T00:00:00Z"}`)
+		case "/v2/library/redis/manifests/7":
+			fmt.Fprint(w, `{"config":{"digest":"sha256:redis-config"}}`)
+		case "/v2/library/redis/blobs/sha256:redis-config":
+			fmt.Fprint(w, `{"created":"2024-01-15T00:00:00Z","os":"linux","architecture":"amd64"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server
+}
+
+func testImage(registry, job, repository, tag string) workflow.ContainerImage {
+	name := strings.TrimPrefix(repository, "library/")
+	return workflow.ContainerImage{
+		FilePath:   ".github/workflows/ci.yml",
+		Job:        job,
+		Kind:       workflow.ImageKindService,
+		Service:    name,
+		Image:      registry + "/" + name + ":" + tag,
+		Registry:   registry,
+		Repository: repository,
+		Tag:        tag,
+	}
+}
+
+func TestCheck(t *testing.T) {
+	requests := 0
+	server := newTestRegistry(t, &requests)
+	defer server.Close()
+	registry := server.Listener.Addr().String()
+
+	checker := NewCheckerWithConfig(&Config{MaxAgeDays: 365, HTTPClient: server.Client()})
+	checker.now = func() time.Time { return time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC) }
+
+	images := []workflow.ContainerImage{
+		testImage(registry, "test", "library/postgres", "16"),
+		testImage(registry, "test", "library/redis", "7"),
+		testImage(registry, "test", "library/mysql", "5.5"),
+	}
+	issues := checker.Check(images)
+
+	if images[0].Created == nil || !images[0].Created.Equal(time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected postgres to be dated by its linux/amd64 image, got %v", images[0].Created)
+	}
+	if images[1].Created == nil || images[1].Missing {
+		t.Errorf("Expected redis to be found and dated, got %+v", images[1])
+	}
+	if !images[2].Missing {
+		t.Errorf("Expected mysql:5.5 to be missing")
+	}
+
+	if len(issues) != 2 {
+		t.Fatalf("Expected 2 issues, got %d: %+v", len(issues), issues)
+	}
+	if issues[0].IssueType != IssueTypeStaleImage || issues[0].CurrentVersion != "7" || issues[0].Context != "job:test service:redis" {
+		t.Errorf("Expected a stale-image issue for redis:7, got %+v", issues[0])
+	}
+	if !strings.Contains(issues[0].Description, "990 days ago (2024-01-15)") {
+		t.Errorf("Expected the image age in the description, got %q", issues[0].Description)
+	}
+	if issues[1].IssueType != IssueTypeMissingImageTag || issues[1].Severity != "high" || issues[1].Repository != registry+"/mysql" {
+		t.Errorf("Expected a missing-image-tag issue for mysql:5.5, got %+v", issues[1])
+	}
+
+	// The same images in another repository are answered from the cache
+	before := requests
+	checker.Check([]workflow.ContainerImage{testImage(registry, "other", "library/redis", "7")})
+	if requests != before {
+		t.Errorf("Expected cached lookups, got %d more requests", requests-before)
+	}
+}
+
+func TestCheck_Architecture(t *testing.T) {
+	requests := 0
+	server := newTestRegistry(t, &requests)
+	defer server.Close()
+	registry := server.Listener.Addr().String()
+
+	checker := NewCheckerWithConfig(&Config{MaxAgeDays: 3650, HTTPClient: server.Client()})
+	checker.now = func() time.Time { return time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC) }
+
+	postgres := testImage(registry, "test", "library/postgres", "16")
+	postgres.Runner = workflow.ArchitectureARM64
+	redis := testImage(registry, "test", "library/redis", "7")
+	redis.Runner = workflow.ArchitectureARM64
+	unknown := testImage(registry, "other", "library/redis", "7")
+
+	issues := checker.Check([]workflow.ContainerImage{postgres, redis, unknown})
+	if len(issues) != 1 {
+		t.Fatalf("Expected 1 issue, got %d: %+v", len(issues), issues)
+	}
+	issue := issues[0]
+	if issue.IssueType != IssueTypeArchitecture || issue.Severity != "high" || issue.Context != "job:test service:redis" {
+		t.Errorf("Expected an image-architecture issue for redis on arm64, got %+v", issue)
+	}
+	if !strings.Contains(issue.Description, "published for linux/amd64 but the job runs on linux/arm64") {
+		t.Errorf("Expected the published platforms in the description, got %q", issue.Description)
+	}
+}
+
+func TestCheck_UnreachableRegistry(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="private"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	registry := server.Listener.Addr().String()
+
+	checker := NewCheckerWithConfig(&Config{HTTPClient: server.Client()})
+	images := []workflow.ContainerImage{testImage(registry, "build", "my-org/builder", "v2")}
+	if issues := checker.Check(images); len(issues) != 0 {
+		t.Errorf("Expected private images to be left unchecked, got %+v", issues)
+	}
+	if images[0].Created != nil || images[0].Missing {
+		t.Errorf("Expected no registry state for an unchecked image, got %+v", images[0])
+	}
+}
+
+func TestApiHost(t *testing.T) {
+	if host := apiHost("docker.io"); host != "registry-1.docker.io" {
+		t.Errorf("Expected Docker Hub API host, got %s", host)
+	}
+	if host := apiHost("ghcr.io"); host != "ghcr.io" {
+		t.Errorf("Expected ghcr.io, got %s", host)
+	}
+}
@@ -0,0 +1,385 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// GroupByRepository groups tickets per scanned repository
+const GroupByRepository = "repository"
+
+// customPropertyPrefix groups tickets by a repository custom property (e.g., "custom_property:Team")
+const customPropertyPrefix = "custom_property:"
+
+// Config describes how scan results map to Jira tickets
+type Config struct {
+	BaseURL   string            `json:"base_url"`             // e.g. https://example.atlassian.net
+	Project   string            `json:"project"`              // Default project key
+	IssueType string            `json:"issue_type,omitempty"` // Default issue type (default: Task)
+	GroupBy   string            `json:"group_by,omitempty"`   // "repository" (default) or "custom_property:<name>"
+	Labels    []string          `json:"labels,omitempty"`     // Labels added to created tickets
+	Groups    map[string]Target `json:"groups,omitempty"`     // Per-group project/issue type overrides
+}
+
+// Target is the project and issue type a group's ticket is created in
+type Target struct {
+	Project   string `json:"project,omitempty"`
+	IssueType string `json:"issue_type,omitempty"`
+}
+
+// LoadConfig loads and validates a Jira config file
+func LoadConfig(filename string) (*Config, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read Jira config: %w", err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("unable to parse Jira config as JSON: %w", err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// Validate checks required fields and fills defaults
+func (c *Config) Validate() error {
+	if c.BaseURL == "" {
+		return fmt.Errorf("jira config: base_url is required")
+	}
+	if c.Project == "" {
+		return fmt.Errorf("jira config: project is required")
+	}
+	if c.IssueType == "" {
+		c.IssueType = "Task"
+	}
+	if c.GroupBy == "" {
+		c.GroupBy = GroupByRepository
+	}
+	if c.GroupBy != GroupByRepository && (!strings.HasPrefix(c.GroupBy, customPropertyPrefix) || c.GroupBy == customPropertyPrefix) {
+		return fmt.Errorf("jira config: group_by must be %q or %q<name>", GroupByRepository, customPropertyPrefix)
+	}
+	c.BaseURL = strings.TrimRight(c.BaseURL, "/")
+	return nil
+}
+
+// target returns the project and issue type for a group
+func (c *Config) target(group string) Target {
+	target := Target{Project: c.Project, IssueType: c.IssueType}
+	if override, ok := c.Groups[group]; ok {
+		if override.Project != "" {
+			target.Project = override.Project
+		}
+		if override.IssueType != "" {
+			target.IssueType = override.IssueType
+		}
+	}
+	return target
+}
+
+// State remembers which Jira ticket tracks each group, so reruns update instead of duplicating
+type State struct {
+	Tickets map[string]string `json:"tickets"` // Group key -> Jira issue key
+}
+
+// LoadState loads the ticket state file; a missing file is an empty state
+func LoadState(filename string) (*State, error) {
+	state := &State{Tickets: make(map[string]string)}
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read Jira state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("unable to parse Jira state as JSON: %w", err)
+	}
+	if state.Tickets == nil {
+		state.Tickets = make(map[string]string)
+	}
+	return state, nil
+}
+
+// Save writes the ticket state file
+func (s *State) Save(filename string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode Jira state: %w", err)
+	}
+	if err := os.WriteFile(filename, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write Jira state: %w", err)
+	}
+	return nil
+}
+
+// Client is a minimal Jira REST API v2 client
+type Client struct {
+	baseURL    string
+	email      string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a Jira client; with an email it uses basic auth (Jira Cloud API
+// tokens), otherwise the token is sent as a bearer token (Data Center PATs)
+func NewClient(baseURL, email, token string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		email:      email,
+		token:      token,
+		httpClient: httpClient,
+	}
+}
+
+// CreateIssue creates a ticket and returns its key
+func (c *Client) CreateIssue(target Target, summary, description string, labels []string) (string, error) {
+	fields := map[string]interface{}{
+		"project":     map[string]string{"key": target.Project},
+		"issuetype":   map[string]string{"name": target.IssueType},
+		"summary":     summary,
+		"description": description,
+	}
+	if len(labels) > 0 {
+		fields["labels"] = labels
+	}
+
+	var created struct {
+		Key string `json:"key"`
+	}
+	if err := c.do(http.MethodPost, "/rest/api/2/issue", map[string]interface{}{"fields": fields}, &created); err != nil {
+		return "", fmt.Errorf("failed to create Jira issue: %w", err)
+	}
+	return created.Key, nil
+}
+
+// UpdateIssue replaces a ticket's summary and description
+func (c *Client) UpdateIssue(key, summary, description string) error {
+	body := map[string]interface{}{"fields": map[string]string{"summary": summary, "description": description}}
+	if err := c.do(http.MethodPut, "/rest/api/2/issue/"+key, body, nil); err != nil {
+		return fmt.Errorf("failed to update Jira issue %s: %w", key, err)
+	}
+	return nil
+}
+
+// AddComment adds a comment to a ticket
+func (c *Client) AddComment(key, comment string) error {
+	if err := c.do(http.MethodPost, "/rest/api/2/issue/"+key+"/comment", map[string]string{"body": comment}, nil); err != nil {
+		return fmt.Errorf("failed to comment on Jira issue %s: %w", key, err)
+	}
+	return nil
+}
+
+// do sends a JSON request and decodes the JSON response into result (when non-nil)
+func (c *Client) do(method, path string, body, result interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.email != "" {
+		req.SetBasicAuth(c.email, c.token)
+	} else if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("jira returned %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	if result != nil && len(data) > 0 {
+		return json.Unmarshal(data, result)
+	}
+	return nil
+}
+
+// Group is the set of outstanding issues tracked by one ticket
+type Group struct {
+	Key    string            // Group name (repository full name or custom property value)
+	Issues []RepositoryIssue // Outstanding issues, sorted by repository
+}
+
+// RepositoryIssue is an issue together with the repository it was found in
+type RepositoryIssue struct {
+	Repository string
+	Issue      output.ActionIssue
+}
+
+// GroupIssues groups a scan's outstanding issues by the configured key
+// Repositories without the grouping custom property fall into the "unassigned" group.
+func GroupIssues(result *output.ScanResult, groupBy string) []Group {
+	groups := make(map[string]*Group)
+
+	for _, repo := range result.Repositories {
+		if len(repo.Issues) == 0 {
+			continue
+		}
+
+		key := groupKey(repo, groupBy)
+		group, exists := groups[key]
+		if !exists {
+			group = &Group{Key: key}
+			groups[key] = group
+		}
+		for _, issue := range repo.Issues {
+			group.Issues = append(group.Issues, RepositoryIssue{Repository: repo.FullName, Issue: issue})
+		}
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	sorted := make([]Group, 0, len(keys))
+	for _, key := range keys {
+		sorted = append(sorted, *groups[key])
+	}
+	return sorted
+}
+
+// groupKey returns the group a repository's ticket belongs to
+func groupKey(repo output.RepositoryResult, groupBy string) string {
+	if groupBy == GroupByRepository {
+		return repo.FullName
+	}
+	if value := repo.CustomProperties[strings.TrimPrefix(groupBy, customPropertyPrefix)]; value != "" {
+		return value
+	}
+	return "unassigned"
+}
+
+// Summary returns the ticket summary line for a group
+func (g Group) Summary() string {
+	return fmt.Sprintf("GitHub Actions maintenance: %d outstanding issues in %s", len(g.Issues), g.Key)
+}
+
+// Description renders the group's issues as a Jira wiki markup table
+func (g Group) Description(owner string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "actions-maintainer found %d outstanding GitHub Actions issues for *%s* (owner %s).\n\n", len(g.Issues), g.Key, owner)
+	b.WriteString("||Repository||File||Action||Current||Suggested||Type||Severity||\n")
+	for _, ri := range g.Issues {
+		suggested := ri.Issue.SuggestedVersion
+		if suggested == "" {
+			suggested = ri.Issue.MigrationTarget
+		}
+		fmt.Fprintf(&b, "|%s|%s|%s|%s|%s|%s|%s|\n",
+			cell(ri.Repository), cell(ri.Issue.FilePath), cell(ri.Issue.Repository), cell(ri.Issue.CurrentVersion),
+			cell(suggested), cell(ri.Issue.IssueType), cell(ri.Issue.Severity))
+	}
+	return b.String()
+}
+
+// cell escapes a value for a Jira table cell; empty cells need a placeholder to render
+func cell(value string) string {
+	if value == "" {
+		return " "
+	}
+	return strings.ReplaceAll(value, "|", "\\|")
+}
+
+// SyncResult reports what a sync did
+type SyncResult struct {
+	Created  []string // Jira keys of created tickets
+	Updated  []string // Jira keys of updated tickets
+	Resolved []string // Jira keys of tickets whose group has no outstanding issues left
+}
+
+// IssueTracker is the subset of the Jira client used by Sync
+type IssueTracker interface {
+	CreateIssue(target Target, summary, description string, labels []string) (string, error)
+	UpdateIssue(key, summary, description string) error
+	AddComment(key, comment string) error
+}
+
+// Sync creates or updates one ticket per group and comments on tickets whose issues are all resolved
+// The state is updated in place; tickets for resolved groups are forgotten so a later
+// regression opens a fresh ticket.
+func Sync(tracker IssueTracker, config *Config, state *State, result *output.ScanResult, verbose bool) (*SyncResult, error) {
+	sync := &SyncResult{}
+	seen := make(map[string]bool)
+
+	// Only groups covered by this scan can be resolved, so a filtered scan leaves other tickets alone
+	scanned := make(map[string]bool)
+	for _, repo := range result.Repositories {
+		scanned[groupKey(repo, config.GroupBy)] = true
+	}
+
+	for _, group := range GroupIssues(result, config.GroupBy) {
+		seen[group.Key] = true
+		summary, description := group.Summary(), group.Description(result.Owner)
+
+		if key, exists := state.Tickets[group.Key]; exists {
+			if err := tracker.UpdateIssue(key, summary, description); err != nil {
+				return sync, err
+			}
+			sync.Updated = append(sync.Updated, key)
+			if verbose {
+				log.Printf("Updated Jira issue %s for %s", key, group.Key)
+			}
+			continue
+		}
+
+		key, err := tracker.CreateIssue(config.target(group.Key), summary, description, config.Labels)
+		if err != nil {
+			return sync, err
+		}
+		state.Tickets[group.Key] = key
+		sync.Created = append(sync.Created, key)
+		if verbose {
+			log.Printf("Created Jira issue %s for %s", key, group.Key)
+		}
+	}
+
+	groupKeys := make([]string, 0, len(state.Tickets))
+	for group := range state.Tickets {
+		groupKeys = append(groupKeys, group)
+	}
+	sort.Strings(groupKeys)
+
+	for _, group := range groupKeys {
+		if seen[group] || !scanned[group] {
+			continue
+		}
+		key := state.Tickets[group]
+		if err := tracker.AddComment(key, fmt.Sprintf("actions-maintainer found no outstanding issues for %s in the latest scan.", group)); err != nil {
+			return sync, err
+		}
+		delete(state.Tickets, group)
+		sync.Resolved = append(sync.Resolved, key)
+	}
+
+	return sync, nil
+}
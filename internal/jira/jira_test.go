@@ -0,0 +1,178 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// mockTracker records tracker calls
+type mockTracker struct {
+	created  []Target
+	updated  []string
+	comments []string
+}
+
+func (m *mockTracker) CreateIssue(target Target, summary, description string, labels []string) (string, error) {
+	m.created = append(m.created, target)
+	return fmt.Sprintf("%s-%d", target.Project, len(m.created)), nil
+}
+
+func (m *mockTracker) UpdateIssue(key, summary, description string) error {
+	m.updated = append(m.updated, key)
+	return nil
+}
+
+func (m *mockTracker) AddComment(key, comment string) error {
+	m.comments = append(m.comments, key)
+	return nil
+}
+
+func scanResult(apiIssues, webIssues int) *output.ScanResult {
+	repo := func(name, team string, count int) output.RepositoryResult {
+		result := output.RepositoryResult{FullName: name, CustomProperties: map[string]string{"Team": team}}
+		for i := 0; i < count; i++ {
+			result.Issues = append(result.Issues, output.ActionIssue{Repository: "actions/checkout", CurrentVersion: "v3", SuggestedVersion: "v4", IssueType: "outdated"})
+		}
+		return result
+	}
+	return &output.ScanResult{Owner: "my-org", Repositories: []output.RepositoryResult{
+		repo("my-org/api", "payments", apiIssues),
+		repo("my-org/web", "", webIssues),
+	}}
+}
+
+func TestSync_CreatesUpdatesAndResolves(t *testing.T) {
+	config := &Config{BaseURL: "https://jira.example.com", Project: "OPS"}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() returned error: %v", err)
+	}
+	state := &State{Tickets: map[string]string{"my-org/old": "OPS-99"}}
+	tracker := &mockTracker{}
+
+	result, err := Sync(tracker, config, state, scanResult(2, 1), false)
+	if err != nil {
+		t.Fatalf("Sync() returned error: %v", err)
+	}
+	if len(result.Created) != 2 || state.Tickets["my-org/api"] != "OPS-1" || state.Tickets["my-org/web"] != "OPS-2" {
+		t.Errorf("Expected a ticket per repository, got %+v (state %v)", result, state.Tickets)
+	}
+	if len(result.Resolved) != 0 {
+		t.Errorf("Expected tickets for repositories outside the scan to be left alone, got %v", result.Resolved)
+	}
+
+	// A rerun updates the stored tickets and resolves repositories without issues
+	result, err = Sync(tracker, config, state, scanResult(1, 0), false)
+	if err != nil {
+		t.Fatalf("Sync() returned error: %v", err)
+	}
+	if len(result.Created) != 0 || len(result.Updated) != 1 || result.Updated[0] != "OPS-1" {
+		t.Errorf("Expected the existing ticket to be updated, got %+v", result)
+	}
+	if len(result.Resolved) != 1 || result.Resolved[0] != "OPS-2" {
+		t.Errorf("Expected my-org/web ticket to be resolved, got %v", result.Resolved)
+	}
+	if _, exists := state.Tickets["my-org/web"]; exists {
+		t.Errorf("Expected resolved ticket to be removed from state")
+	}
+}
+
+func TestSync_GroupByCustomProperty(t *testing.T) {
+	config := &Config{
+		BaseURL: "https://jira.example.com",
+		Project: "OPS",
+		GroupBy: "custom_property:Team",
+		Groups:  map[string]Target{"payments": {Project: "PAY", IssueType: "Bug"}},
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() returned error: %v", err)
+	}
+	state := &State{Tickets: make(map[string]string)}
+	tracker := &mockTracker{}
+
+	if _, err := Sync(tracker, config, state, scanResult(1, 1), false); err != nil {
+		t.Fatalf("Sync() returned error: %v", err)
+	}
+	if state.Tickets["payments"] != "PAY-1" || state.Tickets["unassigned"] != "OPS-2" {
+		t.Errorf("Unexpected tickets: %v", state.Tickets)
+	}
+	if tracker.created[0].IssueType != "Bug" || tracker.created[1].IssueType != "Task" {
+		t.Errorf("Expected per-group issue type mapping, got %+v", tracker.created)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	for _, config := range []Config{
+		{Project: "OPS"},
+		{BaseURL: "https://jira.example.com"},
+		{BaseURL: "https://jira.example.com", Project: "OPS", GroupBy: "team"},
+	} {
+		if err := config.Validate(); err == nil {
+			t.Errorf("Expected validation error for %+v", config)
+		}
+	}
+}
+
+func TestState_RoundTrip(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "jira-state.json")
+
+	state, err := LoadState(filename)
+	if err != nil || len(state.Tickets) != 0 {
+		t.Fatalf("Expected empty state for missing file, got %v, %v", state, err)
+	}
+	state.Tickets["my-org/api"] = "OPS-1"
+	if err := state.Save(filename); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	loaded, err := LoadState(filename)
+	if err != nil || loaded.Tickets["my-org/api"] != "OPS-1" {
+		t.Errorf("Expected saved ticket to load, got %v, %v", loaded, err)
+	}
+}
+
+func TestClient_CreateIssue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/rest/api/2/issue" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if user, pass, ok := r.BasicAuth(); !ok || user != "me@example.com" || pass != "token" {
+			t.Errorf("Expected basic auth with API token")
+		}
+
+		var body struct {
+			Fields map[string]interface{} `json:"fields"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if project := body.Fields["project"].(map[string]interface{})["key"]; project != "OPS" {
+			t.Errorf("Expected project OPS, got %v", project)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"key": "OPS-7"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "me@example.com", "token", nil)
+	key, err := client.CreateIssue(Target{Project: "OPS", IssueType: "Task"}, "summary", "description", []string{"github-actions"})
+	if err != nil {
+		t.Fatalf("CreateIssue() returned error: %v", err)
+	}
+	if key != "OPS-7" {
+		t.Errorf("Expected OPS-7, got %s", key)
+	}
+}
+
+func TestGroupDescription_EscapesCells(t *testing.T) {
+	group := Group{Key: "my-org/api", Issues: []RepositoryIssue{
+		{Repository: "my-org/api", Issue: output.ActionIssue{Repository: "actions/checkout", Description: "a|b", CurrentVersion: "v3|x"}},
+	}}
+	if description := group.Description("my-org"); !strings.Contains(description, `v3\|x`) {
+		t.Errorf("Expected pipes to be escaped, got %q", description)
+	}
+}
@@ -0,0 +1,129 @@
+package ledger
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// Entry is a pull request opened for an update plan
+type Entry struct {
+	PlanHash  string           `json:"plan_hash"` // Hash of the repository, base branch, and updates of the plan
+	CreatedAt time.Time        `json:"created_at"`
+	PR        output.CreatedPR `json:"pr"`
+}
+
+// Ledger records which update plans already have a pull request, so reruns after a partial failure
+// never open a second one. Entries are appended one JSON line at a time and synced to disk before
+// the next pull request is created; a line cut short by a crash is dropped on the next load.
+type Ledger struct {
+	filename       string
+	entries        map[string]Entry // By repository and plan hash
+	missingNewline bool             // The file does not end with a newline, so the next entry must start one
+}
+
+// Open loads a ledger file; a missing file is an empty ledger
+func Open(filename string) (*Ledger, error) {
+	l := &Ledger{filename: filename, entries: make(map[string]Entry)}
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read ledger: %w", err)
+	}
+
+	l.missingNewline = len(data) > 0 && data[len(data)-1] != '\n'
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			// Only a last line without its newline can be cut short by an interrupted write; drop it
+			if i == len(lines)-1 {
+				if err := os.Truncate(filename, int64(len(data)-len(line))); err != nil {
+					return nil, fmt.Errorf("unable to repair ledger: %w", err)
+				}
+				l.missingNewline = false
+				break
+			}
+			return nil, fmt.Errorf("unable to parse ledger line %d: %w", i+1, err)
+		}
+		l.entries[key(entry.PR.Repository, entry.PlanHash)] = entry
+	}
+	return l, nil
+}
+
+// Len returns the number of pull requests in the ledger
+func (l *Ledger) Len() int {
+	return len(l.entries)
+}
+
+// Entries returns the pull requests in the ledger, oldest first
+func (l *Ledger) Entries() []Entry {
+	entries := make([]Entry, 0, len(l.entries))
+	for _, entry := range l.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].CreatedAt.Equal(entries[j].CreatedAt) {
+			return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+		}
+		return entries[i].PR.URL < entries[j].PR.URL
+	})
+	return entries
+}
+
+// Lookup returns the pull request already opened for a repository's plan, or nil when there is none
+func (l *Ledger) Lookup(repository, planHash string) *Entry {
+	entry, ok := l.entries[key(repository, planHash)]
+	if !ok {
+		return nil
+	}
+	return &entry
+}
+
+// Record appends a pull request opened for a plan and syncs it to disk
+func (l *Ledger) Record(planHash string, createdPR output.CreatedPR, now time.Time) error {
+	entry := Entry{PlanHash: planHash, CreatedAt: now.UTC(), PR: createdPR}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode ledger entry: %w", err)
+	}
+
+	file, err := os.OpenFile(l.filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open ledger: %w", err)
+	}
+	if l.missingNewline {
+		data = append([]byte("\n"), data...)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write ledger: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to sync ledger: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close ledger: %w", err)
+	}
+
+	l.missingNewline = false
+	l.entries[key(createdPR.Repository, planHash)] = entry
+	return nil
+}
+
+// key identifies a plan of a repository; repository names are case-insensitive on GitHub
+func key(repository, planHash string) string {
+	return strings.ToLower(repository) + "\x00" + planHash
+}
@@ -0,0 +1,82 @@
+package ledger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+func TestRecordLookup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+
+	l, err := Open(path)
+	if err != nil || l.Len() != 0 {
+		t.Fatalf("Expected an empty ledger for a missing file, got %v, %v", l, err)
+	}
+
+	created := output.CreatedPR{Repository: "my-org/api", Number: 7, URL: "https://github.com/my-org/api/pull/7"}
+	if err := l.Record("abc123", created, time.Now()); err != nil {
+		t.Fatalf("Expected no error recording, got %v", err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Expected no error reopening, got %v", err)
+	}
+	if entry := reopened.Lookup("my-org/API", "abc123"); entry == nil || entry.PR.Number != 7 {
+		t.Errorf("Expected the recorded pull request, got %+v", entry)
+	}
+	if entry := reopened.Lookup("my-org/api", "def456"); entry != nil {
+		t.Errorf("Expected no pull request for another plan, got %+v", entry)
+	}
+}
+
+func TestEntries(t *testing.T) {
+	l, err := Open(filepath.Join(t.TempDir(), "ledger.jsonl"))
+	if err != nil {
+		t.Fatalf("Expected no error opening, got %v", err)
+	}
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l.Record("def456", output.CreatedPR{Repository: "my-org/web", Number: 9}, start.Add(time.Hour))
+	l.Record("abc123", output.CreatedPR{Repository: "my-org/api", Number: 7}, start)
+
+	entries := l.Entries()
+	if len(entries) != 2 || entries[0].PR.Number != 7 || entries[1].PR.Number != 9 {
+		t.Errorf("Expected both pull requests oldest first, got %+v", entries)
+	}
+}
+
+func TestOpen_TruncatedLastLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	data := `{"plan_hash":"abc123","pr":{"repository":"my-org/api","number":7}}` + "\n" + `{"plan_hash":"def4`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := Open(path)
+	if err != nil {
+		t.Fatalf("Expected a line cut short by a crash to be ignored, got %v", err)
+	}
+	if l.Len() != 1 {
+		t.Errorf("Expected 1 entry, got %d", l.Len())
+	}
+
+	// The next entry starts on its own line
+	if err := l.Record("ghi789", output.CreatedPR{Repository: "my-org/web", Number: 8}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if reopened, err := Open(path); err != nil || reopened.Lookup("my-org/web", "ghi789") == nil {
+		t.Fatalf("Expected the entry after a cut short line to load, got %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("{not json\n"+data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path); err == nil {
+		t.Error("Expected an error for an invalid line before the last")
+	}
+}
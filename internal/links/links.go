@@ -0,0 +1,112 @@
+package links
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Target is the repository a link is built for
+type Target struct {
+	Owner      string
+	Name       string
+	FullName   string            // "owner/name"
+	Host       string            // GitHub host, set for GitHub Enterprise Server and multi-host runs
+	Properties map[string]string // Custom properties, such as ProductId
+}
+
+// NewTarget returns the target for a repository's "owner/name" full name and custom properties
+func NewTarget(fullName, host string, properties map[string]string) Target {
+	target := Target{Name: fullName, FullName: fullName, Host: host, Properties: properties}
+	if owner, name, ok := strings.Cut(fullName, "/"); ok {
+		target.Owner, target.Name = owner, name
+	}
+	if target.Properties == nil {
+		target.Properties = map[string]string{}
+	}
+	return target
+}
+
+// urlFuncs are helper functions available to URL templates
+var urlFuncs = template.FuncMap{
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"replace":    strings.ReplaceAll,
+	"pathescape": url.PathEscape,
+}
+
+// Mapping builds links into internal systems, such as developer portal entities or dashboards,
+// from named Go templates over a repository's name and custom properties
+type Mapping struct {
+	templates map[string]*template.Template
+}
+
+// Load reads a JSON object mapping link names to URL templates, e.g.
+// {"backstage": "https://backstage.example.com/catalog/default/component/{{.Name}}"}
+func Load(filename string) (*Mapping, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read link mappings file: %w", err)
+	}
+
+	var sources map[string]string
+	if err := json.Unmarshal(data, &sources); err != nil {
+		return nil, fmt.Errorf("unable to parse link mappings file as a JSON object of URL templates: %w", err)
+	}
+	return Parse(sources)
+}
+
+// Parse parses URL templates keyed by link name
+func Parse(sources map[string]string) (*Mapping, error) {
+	m := &Mapping{templates: make(map[string]*template.Template)}
+	for name, source := range sources {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("link mapping with an empty name")
+		}
+		tmpl, err := template.New(name).Funcs(urlFuncs).Option("missingkey=error").Parse(source)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL template for link %s: %w", name, err)
+		}
+		m.templates[name] = tmpl
+	}
+	return m, nil
+}
+
+// Names returns the link names in sorted order
+func (m *Mapping) Names() []string {
+	if m == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(m.templates))
+	for name := range m.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// URL builds the named link for a repository. A repository without a value the template reads, such
+// as a custom property it does not set, has no link and gets an empty URL, so templates can wrap links
+// in {{with}}. Unknown link names are an error, as they are mistakes in the template using them.
+func (m *Mapping) URL(name string, target Target) (string, error) {
+	if m == nil {
+		return "", fmt.Errorf("no link mappings are loaded; pass --links to use link %q", name)
+	}
+	tmpl, ok := m.templates[name]
+	if !ok {
+		return "", fmt.Errorf("unknown link %q (defined links: %s)", name, strings.Join(m.Names(), ", "))
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, target); err != nil {
+		return "", nil
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
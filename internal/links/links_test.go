@@ -0,0 +1,63 @@
+package links
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMappingURL(t *testing.T) {
+	mapping, err := Parse(map[string]string{
+		"backstage": "https://backstage.example.com/catalog/{{.Owner}}/component/{{lower .Name}}",
+		"grafana":   "https://grafana.example.com/d/{{pathescape .Properties.ProductId}}",
+	})
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+
+	target := NewTarget("my-org/API", "", map[string]string{"ProductId": "pay ments"})
+	tests := []struct {
+		name     string
+		target   Target
+		expected string
+	}{
+		{"backstage", target, "https://backstage.example.com/catalog/my-org/component/api"},
+		{"grafana", target, "https://grafana.example.com/d/pay%20ments"},
+		{"grafana", NewTarget("my-org/web", "", nil), ""}, // No ProductId, so no link
+	}
+	for _, tt := range tests {
+		link, err := mapping.URL(tt.name, tt.target)
+		if err != nil || link != tt.expected {
+			t.Errorf("%s for %s: expected %q, got %q (%v)", tt.name, tt.target.FullName, tt.expected, link, err)
+		}
+	}
+
+	if _, err := mapping.URL("jira", target); err == nil || !strings.Contains(err.Error(), "backstage, grafana") {
+		t.Errorf("Expected an unknown link to list the defined links, got %v", err)
+	}
+	var none *Mapping
+	if _, err := none.URL("backstage", target); err == nil || !strings.Contains(err.Error(), "--links") {
+		t.Errorf("Expected a missing mapping to name --links, got %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "links.json")
+	if err := os.WriteFile(valid, []byte(`{"backstage": "https://backstage.example.com/{{.FullName}}"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mapping, err := Load(valid)
+	if err != nil || len(mapping.Names()) != 1 {
+		t.Fatalf("Expected one link, got %v (%v)", mapping.Names(), err)
+	}
+
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"backstage": "https://backstage.example.com/{{.FullName"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(invalid); err == nil {
+		t.Error("Expected an error for invalid template syntax")
+	}
+}
@@ -0,0 +1,242 @@
+package lockfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/actions"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// FileName is the name of a repository's lockfile within its lock directory
+const FileName = "actions.lock"
+
+// IssueTypeDrift is raised for actions whose use differs from the repository's lockfile
+const IssueTypeDrift = "lock-drift"
+
+// lockVersion is the format version written to lockfiles
+const lockVersion = 1
+
+// Resolver resolves action refs to commits and lists the tags of action repositories
+type Resolver interface {
+	ResolveRefWithCache(owner, repo, ref string) (string, error)
+	GetTagsWithCache(owner, repo string) (map[string]string, error)
+}
+
+// Lock records the commit every action of a repository resolved to at scan time
+type Lock struct {
+	Version     int       `json:"version"`
+	Repository  string    `json:"repository"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Actions     []Entry   `json:"actions"`
+}
+
+// Entry is an action at a ref, e.g. actions/checkout@v4, and what it resolved to
+type Entry struct {
+	Action string `json:"action"`        // uses: without the ref; reusable workflows include their path
+	Ref    string `json:"ref"`           // Ref as written in workflows
+	SHA    string `json:"sha"`           // Commit the ref resolved to
+	Tag    string `json:"tag,omitempty"` // Most specific version tag at the commit, e.g. v4.2.2 for v4
+}
+
+// key identifies an entry within a lock
+func (e Entry) key() string {
+	return strings.ToLower(e.Action) + "@" + e.Ref
+}
+
+// Build resolves the action references of a repository into a lock, one entry per action and ref
+// Local and docker actions are not locked. References that cannot be resolved are left out and
+// returned as errors, so the lock holds what could be verified.
+func Build(repository string, references []workflow.ActionReference, resolver Resolver, now time.Time) (*Lock, []error) {
+	lock := &Lock{Version: lockVersion, Repository: repository, GeneratedAt: now.UTC(), Actions: []Entry{}}
+	seen := make(map[string]bool)
+	var errs []error
+	for _, reference := range references {
+		entry, ok := entryOf(reference)
+		if !ok || seen[entry.key()] {
+			continue
+		}
+		seen[entry.key()] = true
+
+		owner, name, _ := strings.Cut(reference.Repository, "/")
+		name, _, _ = strings.Cut(name, "/")
+		sha, err := resolver.ResolveRefWithCache(owner, name, entry.Ref)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to resolve %s@%s: %w", entry.Action, entry.Ref, err))
+			continue
+		}
+		entry.SHA = sha
+		if tags, err := resolver.GetTagsWithCache(owner, name); err == nil {
+			entry.Tag = tagAt(tags, sha)
+		}
+		lock.Actions = append(lock.Actions, entry)
+	}
+
+	sort.Slice(lock.Actions, func(i, j int) bool { return lock.Actions[i].key() < lock.Actions[j].key() })
+	return lock, errs
+}
+
+// entryOf returns the unresolved entry of an action reference, and false for unlockable references
+func entryOf(reference workflow.ActionReference) (Entry, bool) {
+	if reference.Version == "" || strings.HasPrefix(reference.Repository, ".") || strings.HasPrefix(reference.Repository, "docker://") ||
+		strings.Count(reference.Repository, "/") < 1 {
+		return Entry{}, false
+	}
+	action := reference.Repository
+	if reference.IsReusable && reference.WorkflowPath != "" {
+		action += "/" + reference.WorkflowPath
+	}
+	return Entry{Action: action, Ref: reference.Version}, true
+}
+
+// tagAt returns the most specific version tag pointing at a commit, or "" if none does
+func tagAt(tags map[string]string, sha string) string {
+	var candidates []string
+	for tag, commit := range tags {
+		if commit == sha {
+			candidates = append(candidates, tag)
+		}
+	}
+	return actions.HighestVersion(candidates)
+}
+
+// Path returns where the lockfile of a repository ("owner/name") is kept within a lock directory
+func Path(dir, repository string) string {
+	return filepath.Join(dir, filepath.FromSlash(repository), FileName)
+}
+
+// Load reads the lockfile of a repository, returning nil without an error when it has none yet
+func Load(dir, repository string) (*Lock, error) {
+	data, err := os.ReadFile(Path(dir, repository))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read lockfile: %w", err)
+	}
+
+	lock := &Lock{}
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("unable to parse lockfile: %w", err)
+	}
+	if lock.Version > lockVersion {
+		return nil, fmt.Errorf("lockfile version %d is newer than supported version %d", lock.Version, lockVersion)
+	}
+	return lock, nil
+}
+
+// Save writes the lockfile of a repository under a lock directory
+func Save(dir string, lock *Lock) error {
+	path := Path(dir, lock.Repository)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create lock directory: %w", err)
+	}
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode lockfile: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write lockfile: %w", err)
+	}
+	return nil
+}
+
+// Verify compares the current lock of a repository, built from its action references, with its
+// lockfile. It returns an issue for each ref resolving to another commit than locked, each action
+// or ref not in the lockfile, and each locked entry no longer used.
+//
+// A moved exact tag or branch is high severity, as the code run changed under the same name; moved
+// major tags are medium, as they move on every release, but should be locked again after review.
+func Verify(locked, current *Lock, references []workflow.ActionReference) []output.ActionIssue {
+	lockedEntries := make(map[string]Entry, len(locked.Actions))
+	for _, entry := range locked.Actions {
+		lockedEntries[entry.key()] = entry
+	}
+	currentEntries := make(map[string]Entry, len(current.Actions))
+	for _, entry := range current.Actions {
+		currentEntries[entry.key()] = entry
+	}
+
+	var issues []output.ActionIssue
+	reported := make(map[string]bool)
+	for _, reference := range references {
+		entry, ok := entryOf(reference)
+		if !ok || reported[entry.key()] {
+			continue
+		}
+		now, resolved := currentEntries[entry.key()]
+		if !resolved {
+			continue // Unresolvable refs cannot be compared
+		}
+		reported[entry.key()] = true
+
+		issue := output.ActionIssue{
+			Repository:     reference.Repository,
+			WorkflowPath:   reference.WorkflowPath,
+			CurrentVersion: reference.Version,
+			IssueType:      IssueTypeDrift,
+			Context:        reference.Context,
+			FilePath:       reference.FilePath,
+			PinComment:     reference.PinComment,
+		}
+		was, isLocked := lockedEntries[entry.key()]
+		switch {
+		case !isLocked:
+			issue.Severity = "low"
+			issue.Description = fmt.Sprintf("%s@%s is not in the lockfile; it resolves to %s", entry.Action, entry.Ref, describeCommit(now))
+		case was.SHA != now.SHA:
+			issue.Severity = "high"
+			if output.PinStyle(entry.Ref) == output.PinStyleMajorTag {
+				issue.Severity = "medium"
+			}
+			issue.Description = fmt.Sprintf("%s@%s was locked at %s but now resolves to %s", entry.Action, entry.Ref, describeCommit(was), describeCommit(now))
+		default:
+			continue
+		}
+		issues = append(issues, issue)
+	}
+
+	for _, entry := range locked.Actions {
+		if stillUsed(entry, references) {
+			continue
+		}
+		issues = append(issues, output.ActionIssue{
+			Repository:     entry.Action,
+			CurrentVersion: entry.Ref,
+			IssueType:      IssueTypeDrift,
+			Severity:       "low",
+			Description:    fmt.Sprintf("%s@%s is locked but no longer used; lock the repository again", entry.Action, entry.Ref),
+			Context:        "lock",
+		})
+	}
+	return issues
+}
+
+// stillUsed reports whether any action reference uses a locked entry, resolved or not
+func stillUsed(entry Entry, references []workflow.ActionReference) bool {
+	for _, reference := range references {
+		if current, ok := entryOf(reference); ok && current.key() == entry.key() {
+			return true
+		}
+	}
+	return false
+}
+
+// describeCommit names the commit of an entry by its tag when it has one
+func describeCommit(entry Entry) string {
+	sha := entry.SHA
+	if len(sha) > 12 {
+		sha = sha[:12]
+	}
+	if entry.Tag != "" && entry.Tag != entry.Ref {
+		return fmt.Sprintf("%s (%s)", sha, entry.Tag)
+	}
+	return sha
+}
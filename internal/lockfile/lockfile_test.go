@@ -0,0 +1,133 @@
+package lockfile
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// fakeResolver resolves refs and lists tags from canned maps keyed by "owner/repo"
+type fakeResolver struct {
+	refs map[string]map[string]string
+	tags map[string]map[string]string
+}
+
+func (f *fakeResolver) ResolveRefWithCache(owner, repo, ref string) (string, error) {
+	sha, ok := f.refs[owner+"/"+repo][ref]
+	if !ok {
+		return "", fmt.Errorf("ref %s not found in %s/%s", ref, owner, repo)
+	}
+	return sha, nil
+}
+
+func (f *fakeResolver) GetTagsWithCache(owner, repo string) (map[string]string, error) {
+	return f.tags[owner+"/"+repo], nil
+}
+
+func references() []workflow.ActionReference {
+	return []workflow.ActionReference{
+		{Repository: "actions/checkout", Version: "v4", Context: "job:build/step:checkout", FilePath: ".github/workflows/ci.yml"},
+		{Repository: "actions/checkout", Version: "v4", Context: "job:test/step:checkout", FilePath: ".github/workflows/ci.yml"},
+		{Repository: "my-org/deploy", Version: "v1.2.0", Context: "job:deploy/step:deploy", FilePath: ".github/workflows/cd.yml"},
+		{Repository: "my-org/shared", Version: "main", WorkflowPath: ".github/workflows/lint.yml", IsReusable: true, Context: "job:lint", FilePath: ".github/workflows/ci.yml"},
+		{Repository: "./.github/actions/setup", Context: "job:build/step:setup", FilePath: ".github/workflows/ci.yml"},
+		{Repository: "my-org/gone", Version: "v1", Context: "job:build/step:gone", FilePath: ".github/workflows/ci.yml"},
+	}
+}
+
+func resolver() *fakeResolver {
+	return &fakeResolver{
+		refs: map[string]map[string]string{
+			"actions/checkout": {"v4": "sha-checkout-422"},
+			"my-org/deploy":    {"v1.2.0": "sha-deploy-120"},
+			"my-org/shared":    {"main": "sha-shared-main"},
+		},
+		tags: map[string]map[string]string{
+			"actions/checkout": {"v4": "sha-checkout-422", "v4.2.2": "sha-checkout-422", "v4.2.1": "sha-checkout-421"},
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	lock, errs := Build("my-org/api", references(), resolver(), time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "my-org/gone@v1") {
+		t.Errorf("Expected an error for the unresolvable ref, got %v", errs)
+	}
+	if len(lock.Actions) != 3 {
+		t.Fatalf("Expected 3 entries, got %d: %+v", len(lock.Actions), lock.Actions)
+	}
+	checkout := lock.Actions[0]
+	if checkout.Action != "actions/checkout" || checkout.Ref != "v4" || checkout.SHA != "sha-checkout-422" || checkout.Tag != "v4.2.2" {
+		t.Errorf("Expected actions/checkout@v4 locked at v4.2.2, got %+v", checkout)
+	}
+	if lock.Actions[2].Action != "my-org/shared/.github/workflows/lint.yml" {
+		t.Errorf("Expected the reusable workflow locked with its path, got %s", lock.Actions[2].Action)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	missing, err := Load(dir, "my-org/api")
+	if err != nil || missing != nil {
+		t.Fatalf("Expected no lock and no error for a missing lockfile, got %v, %v", missing, err)
+	}
+
+	lock, _ := Build("my-org/api", references(), resolver(), time.Now())
+	if err := Save(dir, lock); err != nil {
+		t.Fatalf("Failed to save lock: %v", err)
+	}
+	loaded, err := Load(dir, "my-org/api")
+	if err != nil {
+		t.Fatalf("Failed to load lock: %v", err)
+	}
+	if loaded.Repository != "my-org/api" || len(loaded.Actions) != len(lock.Actions) {
+		t.Errorf("Expected the saved lock back, got %+v", loaded)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	locked := &Lock{Version: 1, Repository: "my-org/api", Actions: []Entry{
+		{Action: "actions/checkout", Ref: "v4", SHA: "sha-checkout-421", Tag: "v4.2.1"},
+		{Action: "my-org/deploy", Ref: "v1.2.0", SHA: "sha-deploy-old"},
+		{Action: "my-org/gone", Ref: "v1", SHA: "sha-gone"},
+		{Action: "my-org/removed", Ref: "v2", SHA: "sha-removed"},
+	}}
+	current, _ := Build("my-org/api", references(), resolver(), time.Now())
+
+	issues := Verify(locked, current, references())
+	bySubject := make(map[string]output.ActionIssue)
+	for _, issue := range issues {
+		if issue.IssueType != IssueTypeDrift {
+			t.Errorf("Expected %s issues, got %s", IssueTypeDrift, issue.IssueType)
+		}
+		bySubject[issue.Repository+"@"+issue.CurrentVersion] = issue
+	}
+	if len(issues) != 4 {
+		t.Fatalf("Expected 4 issues, got %d: %+v", len(issues), issues)
+	}
+
+	// A moved major tag moves on every release; a moved exact tag should never move
+	if issue := bySubject["actions/checkout@v4"]; issue.Severity != "medium" || issue.Context != "job:build/step:checkout" ||
+		!strings.Contains(issue.Description, "v4.2.1") || !strings.Contains(issue.Description, "v4.2.2") {
+		t.Errorf("Expected a medium issue for the moved major tag, got %+v", issue)
+	}
+	if issue := bySubject["my-org/deploy@v1.2.0"]; issue.Severity != "high" {
+		t.Errorf("Expected a high issue for the moved exact tag, got %+v", issue)
+	}
+	if issue := bySubject["my-org/shared@main"]; issue.Severity != "low" || !strings.Contains(issue.Description, "not in the lockfile") {
+		t.Errorf("Expected a low issue for the unlocked workflow, got %+v", issue)
+	}
+	// Unresolvable refs still in use are neither drifted nor removed
+	if _, found := bySubject["my-org/gone@v1"]; found {
+		t.Error("Expected no issue for a locked ref that could not be resolved")
+	}
+	if issue := bySubject["my-org/removed@v2"]; issue.Severity != "low" || issue.Context != "lock" {
+		t.Errorf("Expected a low issue for the locked action no longer used, got %+v", issue)
+	}
+}
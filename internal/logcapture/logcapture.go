@@ -0,0 +1,96 @@
+package logcapture
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Recorder is a log output that passes writes through and, while recording, keeps
+// each line so it can be attached to the result of the unit of work that produced it
+//
+// Writes are serialized, so a Recorder can be shared by the standard logger and any
+// goroutines logging through it.
+type Recorder struct {
+	out       io.Writer
+	mutex     sync.Mutex
+	recording bool
+	lines     []string
+	partial   bytes.Buffer
+}
+
+// NewRecorder creates a recorder that passes writes through to out (which may be nil)
+func NewRecorder(out io.Writer) *Recorder {
+	return &Recorder{out: out}
+}
+
+// Write passes p through and records its complete lines while recording
+func (r *Recorder) Write(p []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.recording {
+		r.partial.Write(p)
+		for {
+			line, err := r.partial.ReadString('\n')
+			if err != nil {
+				// Keep the incomplete line for the next write
+				r.partial.Reset()
+				r.partial.WriteString(line)
+				break
+			}
+			r.lines = append(r.lines, strings.TrimSuffix(line, "\n"))
+		}
+	}
+
+	if r.out == nil {
+		return len(p), nil
+	}
+	return r.out.Write(p)
+}
+
+// Notef records a line without passing it through, for messages already printed elsewhere
+func (r *Recorder) Notef(format string, args ...interface{}) {
+	if r == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.recording {
+		r.lines = append(r.lines, fmt.Sprintf(format, args...))
+	}
+}
+
+// Start begins recording, discarding anything recorded before
+func (r *Recorder) Start() {
+	if r == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.recording = true
+	r.lines = nil
+	r.partial.Reset()
+}
+
+// Stop ends recording and returns the recorded lines
+func (r *Recorder) Stop() []string {
+	if r == nil {
+		return nil
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.partial.Len() > 0 {
+		r.lines = append(r.lines, r.partial.String())
+		r.partial.Reset()
+	}
+	lines := r.lines
+	r.recording = false
+	r.lines = nil
+	return lines
+}
@@ -0,0 +1,72 @@
+package logcapture
+
+import (
+	"bytes"
+	"log"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestRecorder_RecordsOnlyWhileStarted(t *testing.T) {
+	var out bytes.Buffer
+	recorder := NewRecorder(&out)
+	logger := log.New(recorder, "", 0)
+
+	logger.Printf("before")
+	recorder.Start()
+	logger.Printf("first")
+	recorder.Notef("note %d", 1)
+	logger.Printf("second")
+	lines := recorder.Stop()
+	logger.Printf("after")
+
+	expected := []string{"first", "note 1", "second"}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("Expected %v, got %v", expected, lines)
+	}
+	if out.String() != "before\nfirst\nsecond\nafter\n" {
+		t.Errorf("Expected writes to pass through, got %q", out.String())
+	}
+}
+
+func TestRecorder_PartialWrites(t *testing.T) {
+	recorder := NewRecorder(nil)
+	recorder.Start()
+	recorder.Write([]byte("hello "))
+	recorder.Write([]byte("world\nnext"))
+
+	expected := []string{"hello world", "next"}
+	if lines := recorder.Stop(); !reflect.DeepEqual(lines, expected) {
+		t.Errorf("Expected %v, got %v", expected, lines)
+	}
+}
+
+func TestRecorder_ConcurrentWrites(t *testing.T) {
+	recorder := NewRecorder(nil)
+	logger := log.New(recorder, "", 0)
+	recorder.Start()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			logger.Printf("line %d", i)
+		}(i)
+	}
+	wg.Wait()
+
+	if lines := recorder.Stop(); len(lines) != 20 {
+		t.Errorf("Expected 20 recorded lines, got %d", len(lines))
+	}
+}
+
+func TestRecorder_Nil(t *testing.T) {
+	var recorder *Recorder
+	recorder.Start()
+	recorder.Notef("ignored")
+	if lines := recorder.Stop(); lines != nil {
+		t.Errorf("Expected nil recorder to record nothing, got %v", lines)
+	}
+}
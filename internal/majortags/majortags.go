@@ -0,0 +1,196 @@
+package majortags
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/actions"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// TagClient lists the tags of a repository, mapped to the commits they point at
+type TagClient interface {
+	GetTagsWithCache(owner, repo string) (map[string]string, error)
+}
+
+// Config holds configuration options for major tag checks
+type Config struct {
+	Verbose bool
+}
+
+// Checker finds internal actions whose floating major tags lag their newest release
+type Checker struct {
+	client  TagClient
+	verbose bool
+}
+
+// NewChecker creates a checker listing tags with the given client
+func NewChecker(client TagClient) *Checker {
+	return NewCheckerWithConfig(client, &Config{Verbose: false})
+}
+
+// NewCheckerWithConfig creates a checker with configuration
+func NewCheckerWithConfig(client TagClient, config *Config) *Checker {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	return &Checker{
+		client:  client,
+		verbose: config.Verbose,
+	}
+}
+
+// Check returns a finding for each major tag of an internal action that does not point at the newest
+// release of its major version, such as v2 left on v2.3.0 after v2.4.1 was tagged
+//
+// Actions are internal when their owner also owns a scanned repository. Releases are the version tags
+// more specific than the major tag, e.g. v2.4 or v2.4.1. Findings are medium severity when scanned
+// repositories pin the major tag, as they are silently missing the newer release, and low otherwise.
+func (c *Checker) Check(repositories []output.RepositoryResult) []output.MajorTagFinding {
+	owners := make(map[string]bool)
+	for _, repo := range repositories {
+		owners[strings.ToLower(ownerOf(repo.FullName))] = true
+	}
+
+	// Consumers pinned to each version of each internal action repository
+	usage := make(map[string]map[string]map[string]bool)
+	for _, repo := range repositories {
+		for _, action := range repo.Actions {
+			actionRepo := repositoryOf(action.Repository)
+			if actionRepo == "" || !owners[strings.ToLower(ownerOf(actionRepo))] {
+				continue
+			}
+			if usage[actionRepo] == nil {
+				usage[actionRepo] = make(map[string]map[string]bool)
+			}
+			if strings.EqualFold(actionRepo, repo.FullName) {
+				continue // A repository using its own action is not a consumer
+			}
+			if usage[actionRepo][action.Version] == nil {
+				usage[actionRepo][action.Version] = make(map[string]bool)
+			}
+			usage[actionRepo][action.Version][repo.FullName] = true
+		}
+	}
+
+	var findings []output.MajorTagFinding
+	for actionRepo, versions := range usage {
+		owner, name, _ := strings.Cut(actionRepo, "/")
+		tags, err := c.client.GetTagsWithCache(owner, name)
+		if err != nil {
+			log.Printf("Warning: Failed to list the tags of internal action %s: %v", actionRepo, err)
+			continue
+		}
+
+		for _, lagging := range LaggingMajorTags(tags) {
+			finding := lagging
+			finding.Repository = actionRepo
+			finding.Consumers = sortedKeys(versions[finding.MajorTag])
+			finding.Severity = "low"
+			if len(finding.Consumers) > 0 {
+				finding.Severity = "medium"
+			}
+			finding.Description = describe(finding)
+			findings = append(findings, finding)
+		}
+		if c.verbose {
+			log.Printf("Checked the major tags of internal action %s", actionRepo)
+		}
+	}
+
+	// Findings affecting the most consumers first
+	sort.Slice(findings, func(i, j int) bool {
+		if len(findings[i].Consumers) != len(findings[j].Consumers) {
+			return len(findings[i].Consumers) > len(findings[j].Consumers)
+		}
+		if findings[i].Repository != findings[j].Repository {
+			return findings[i].Repository < findings[j].Repository
+		}
+		return findings[i].MajorTag < findings[j].MajorTag
+	})
+
+	return findings
+}
+
+// LaggingMajorTags returns the major tags among tags, mapped to their commits, that do not point at the
+// commit of the newest release of their major version, sorted by tag. Only MajorTag, PointsAt, and
+// LatestRelease are set.
+func LaggingMajorTags(tags map[string]string) []output.MajorTagFinding {
+	releases := make(map[string][]string) // major tag -> release tags
+	for tag := range tags {
+		if major := actions.MajorTag(tag); major != "" && major != tag {
+			releases[major] = append(releases[major], tag)
+		}
+	}
+
+	var lagging []output.MajorTagFinding
+	for major, versions := range releases {
+		commit, tagged := tags[major]
+		if !tagged {
+			continue // Actions without major tags are pinned by release
+		}
+		newest := actions.HighestVersion(versions)
+		if commit == tags[newest] {
+			continue
+		}
+
+		// The newest release the major tag was last moved to, if any
+		var pointedAt []string
+		for _, version := range versions {
+			if tags[version] == commit {
+				pointedAt = append(pointedAt, version)
+			}
+		}
+		lagging = append(lagging, output.MajorTagFinding{
+			MajorTag:      major,
+			PointsAt:      actions.HighestVersion(pointedAt),
+			LatestRelease: newest,
+		})
+	}
+
+	sort.Slice(lagging, func(i, j int) bool { return lagging[i].MajorTag < lagging[j].MajorTag })
+	return lagging
+}
+
+// describe explains a finding and how to move the major tag
+func describe(finding output.MajorTagFinding) string {
+	pointsAt := "a commit that is not a release"
+	if finding.PointsAt != "" {
+		pointsAt = finding.PointsAt
+	}
+	impact := "no scanned repository pins it yet"
+	if len(finding.Consumers) > 0 {
+		impact = fmt.Sprintf("%d repositories pinned to %s are missing its changes", len(finding.Consumers), finding.MajorTag)
+	}
+	return fmt.Sprintf("Major tag %s of internal action %s points at %s, not the newest release %s; %s. Move it with `git tag -f %s %s && git push -f origin %s`",
+		finding.MajorTag, finding.Repository, pointsAt, finding.LatestRelease, impact, finding.MajorTag, finding.LatestRelease, finding.MajorTag)
+}
+
+// repositoryOf returns the "owner/name" repository of an action reference, which may name a path
+// within it, or "" for local and docker references
+func repositoryOf(reference string) string {
+	parts := strings.Split(reference, "/")
+	if len(parts) < 2 || strings.HasPrefix(reference, ".") || strings.Contains(parts[0], ":") {
+		return ""
+	}
+	return parts[0] + "/" + parts[1]
+}
+
+// ownerOf returns the owner part of an "owner/name" reference
+func ownerOf(repository string) string {
+	owner, _, _ := strings.Cut(repository, "/")
+	return owner
+}
+
+// sortedKeys returns the keys of a set in sorted order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
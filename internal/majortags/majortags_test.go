@@ -0,0 +1,109 @@
+package majortags
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// fakeClient returns canned tags per action repository
+type fakeClient struct {
+	tags  map[string]map[string]string
+	calls []string
+}
+
+func (f *fakeClient) GetTagsWithCache(owner, repo string) (map[string]string, error) {
+	name := owner + "/" + repo
+	f.calls = append(f.calls, name)
+	tags, ok := f.tags[name]
+	if !ok {
+		return nil, fmt.Errorf("no tags for %s", name)
+	}
+	return tags, nil
+}
+
+func consumer(fullName string, uses ...string) output.RepositoryResult {
+	repo := output.RepositoryResult{FullName: fullName}
+	for _, use := range uses {
+		var action workflow.ActionReference
+		fmt.Sscanf(use, "%s %s", &action.Repository, &action.Version)
+		repo.Actions = append(repo.Actions, action)
+	}
+	return repo
+}
+
+func TestLaggingMajorTags(t *testing.T) {
+	tags := map[string]string{
+		"v1": "c1", "v1.0.0": "c0", "v1.1.0": "c1", // v1 moved to its newest release
+		"v2": "c20", "v2.0.0": "c20", "v2.1": "c21", "v2.1.3": "c213", // v2 left on v2.0.0
+		"v3": "c3x", "v3.0.0": "c30", // v3 points at no release
+		"v4.0.0": "c40", // no major tag
+		"main":   "c213",
+	}
+
+	lagging := LaggingMajorTags(tags)
+	if len(lagging) != 2 {
+		t.Fatalf("Expected 2 lagging major tags, got %d: %+v", len(lagging), lagging)
+	}
+	if lagging[0].MajorTag != "v2" || lagging[0].PointsAt != "v2.0.0" || lagging[0].LatestRelease != "v2.1.3" {
+		t.Errorf("Expected v2 at v2.0.0 behind v2.1.3, got %+v", lagging[0])
+	}
+	if lagging[1].MajorTag != "v3" || lagging[1].PointsAt != "" || lagging[1].LatestRelease != "v3.0.0" {
+		t.Errorf("Expected v3 at no release behind v3.0.0, got %+v", lagging[1])
+	}
+}
+
+func TestCheck_ReportsLaggingMajorTags(t *testing.T) {
+	client := &fakeClient{tags: map[string]map[string]string{
+		"my-org/deploy": {"v1": "a", "v1.2.0": "a", "v1.3.0": "b"},
+		"my-org/lint":   {"v2": "c", "v2.0.1": "c"},
+	}}
+	repos := []output.RepositoryResult{
+		consumer("my-org/api", "my-org/deploy v1", "my-org/lint v2", "actions/checkout v4"),
+		consumer("my-org/web", "my-org/deploy/sub v1", "my-org/deploy v1.3.0"),
+		consumer("my-org/deploy", "my-org/deploy v1"),
+	}
+
+	findings := NewChecker(client).Check(repos)
+	if len(findings) != 1 {
+		t.Fatalf("Expected 1 finding, got %d: %+v", len(findings), findings)
+	}
+
+	finding := findings[0]
+	if finding.Repository != "my-org/deploy" || finding.MajorTag != "v1" || finding.LatestRelease != "v1.3.0" {
+		t.Errorf("Expected my-org/deploy v1 behind v1.3.0, got %+v", finding)
+	}
+	// Actions in a subdirectory share the repository's tags; the action's own repository is not a consumer
+	if len(finding.Consumers) != 2 || finding.Consumers[0] != "my-org/api" || finding.Consumers[1] != "my-org/web" {
+		t.Errorf("Expected consumers my-org/api and my-org/web, got %v", finding.Consumers)
+	}
+	if finding.Severity != "medium" {
+		t.Errorf("Expected medium severity for a pinned major tag, got %s", finding.Severity)
+	}
+	if !strings.Contains(finding.Description, "git tag -f v1 v1.3.0") {
+		t.Errorf("Expected the description to show how to move the tag, got %s", finding.Description)
+	}
+	if len(client.calls) != 2 {
+		t.Errorf("Expected tags listed only for the internal actions, got %v", client.calls)
+	}
+}
+
+func TestCheck_UnpinnedAndUnreadable(t *testing.T) {
+	client := &fakeClient{tags: map[string]map[string]string{
+		"my-org/deploy": {"v1": "a", "v1.0.0": "a", "v1.1.0": "b"},
+	}}
+	repos := []output.RepositoryResult{
+		consumer("my-org/api", "my-org/deploy v1.1.0", "my-org/missing v1"),
+	}
+
+	findings := NewChecker(client).Check(repos)
+	if len(findings) != 1 {
+		t.Fatalf("Expected 1 finding, got %d", len(findings))
+	}
+	if findings[0].Severity != "low" || len(findings[0].Consumers) != 0 {
+		t.Errorf("Expected a low severity finding without consumers, got %+v", findings[0])
+	}
+}
@@ -0,0 +1,217 @@
+package output
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxSummaryIssues caps the issue table of the job summary; GitHub limits summaries to 1 MiB
+const maxSummaryIssues = 100
+
+// annotationLevel maps issue severity to a GitHub workflow command
+// Issues already present in the baseline are notices so only new problems fail review.
+func annotationLevel(issue ActionIssue) string {
+	if issue.Existing {
+		return "notice"
+	}
+	switch issue.Severity {
+	case "critical", "high":
+		return "error"
+	case "medium":
+		return "warning"
+	default:
+		return "notice"
+	}
+}
+
+// WriteGitHubAnnotations writes a workflow command annotation for each issue in the given repository,
+// returning how many were written. Line numbers come from the workflow files checked out in workspace;
+// an issue whose uses line cannot be found is annotated on the file alone.
+func WriteGitHubAnnotations(w io.Writer, result *ScanResult, repository, workspace string) (int, error) {
+	count := 0
+	for _, repo := range result.Repositories {
+		if !strings.EqualFold(repo.FullName, repository) {
+			continue
+		}
+
+		lines := make(map[string][]string)
+		for _, issue := range repo.Issues {
+			content, loaded := lines[issue.FilePath]
+			if !loaded {
+				content = readLines(filepath.Join(workspace, filepath.FromSlash(issue.FilePath)))
+				lines[issue.FilePath] = content
+			}
+
+			properties := "file=" + escapeProperty(issue.FilePath)
+			if line := findUsesLine(content, issue); line > 0 {
+				properties += fmt.Sprintf(",line=%d", line)
+			}
+			properties += ",title=" + escapeProperty(annotationTitle(issue))
+
+			if _, err := fmt.Fprintf(w, "::%s %s::%s\n", annotationLevel(issue), properties, escapeData(annotationMessage(issue))); err != nil {
+				return count, fmt.Errorf("failed to write annotation: %w", err)
+			}
+			count++
+		}
+	}
+	return count, nil
+}
+
+// annotationTitle names the issue type and action reference
+func annotationTitle(issue ActionIssue) string {
+	action := issue.Repository
+	if issue.WorkflowPath != "" {
+		action += "/" + issue.WorkflowPath
+	}
+	if issue.CurrentVersion != "" {
+		action += "@" + issue.CurrentVersion
+	}
+	return fmt.Sprintf("%s: %s", issue.IssueType, action)
+}
+
+// annotationMessage describes the issue and the suggested fix
+func annotationMessage(issue ActionIssue) string {
+	message := issue.Description
+	switch {
+	case issue.MigrationTarget != "":
+		message += fmt.Sprintf(" (migrate to %s)", issue.MigrationTarget)
+	case issue.Remediation != nil && issue.Remediation.Replacement != "":
+		message += fmt.Sprintf(" (replace with %s)", issue.Remediation.Replacement)
+	case issue.Remediation != nil:
+		message += " (remove the step)"
+	case issue.Concurrency != nil:
+		message += fmt.Sprintf(" (set concurrency group %s with cancel-in-progress: %t)", issue.Concurrency.Group, issue.Concurrency.CancelInProgress)
+	case issue.SuggestedVersion != "":
+		message += fmt.Sprintf(" (suggested version: %s)", issue.SuggestedVersion)
+	}
+	return message
+}
+
+// readLines returns the lines of a file, or nil if it cannot be read
+func readLines(path string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
+
+// findUsesLine returns the 1-based line of the uses entry an issue refers to, or 0 if it is not found
+func findUsesLine(lines []string, issue ActionIssue) int {
+	line, _ := findUsesPosition(lines, issue)
+	return line
+}
+
+// findUsesPosition returns the 1-based line and column of the uses entry an issue refers to, or 0, 0
+// if it is not found; the column is that of the uses key
+func findUsesPosition(lines []string, issue ActionIssue) (int, int) {
+	if issue.Repository == "" {
+		return 0, 0
+	}
+	reference := issue.Repository
+	if issue.WorkflowPath != "" {
+		reference += "/" + issue.WorkflowPath
+	}
+	reference = strings.ToLower(reference + "@" + issue.CurrentVersion)
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "-"))
+		value, found := strings.CutPrefix(trimmed, "uses:")
+		if !found {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		if strings.HasPrefix(strings.ToLower(value), reference) {
+			return i + 1, strings.Index(line, "uses:") + 1
+		}
+	}
+	return 0, 0
+}
+
+// escapeData escapes an annotation message for a workflow command
+func escapeData(value string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(value)
+}
+
+// escapeProperty escapes an annotation property value for a workflow command
+func escapeProperty(value string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(value)
+}
+
+// WriteGitHubJobSummary writes a Markdown job summary of the scan. Issues are listed for the given
+// repository, or for every repository when it is empty, most severe first.
+func WriteGitHubJobSummary(w io.Writer, result *ScanResult, repository string) error {
+	var b strings.Builder
+
+	totalIssues := 0
+	for _, count := range result.Summary.IssuesBySeverity {
+		totalIssues += count
+	}
+
+	b.WriteString("## Actions Maintainer\n\n")
+	fmt.Fprintf(&b, "Scanned %d repositories using %d actions and found **%d issues**",
+		result.Summary.TotalRepositories, result.Summary.TotalActions, totalIssues)
+	if result.Summary.ExistingIssues > 0 {
+		fmt.Fprintf(&b, " (%d already in the baseline)", result.Summary.ExistingIssues)
+	}
+	b.WriteString(".\n\n")
+
+	if len(result.Summary.IssuesBySeverity) > 0 {
+		b.WriteString("| Severity | Issues |\n")
+		b.WriteString("|----------|--------|\n")
+		for _, severity := range []string{"critical", "high", "medium", "low"} {
+			if count := result.Summary.IssuesBySeverity[severity]; count > 0 {
+				fmt.Fprintf(&b, "| %s | %d |\n", severity, count)
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	var issues []ActionIssue
+	for _, repo := range result.Repositories {
+		if repository == "" || strings.EqualFold(repo.FullName, repository) {
+			issues = append(issues, repo.Issues...)
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		return isHigherSeverity(issues[i].Severity, issues[j].Severity)
+	})
+
+	if len(issues) > 0 {
+		b.WriteString("| Severity | File | Action | Current | Suggested | Type |\n")
+		b.WriteString("|----------|------|--------|---------|-----------|------|\n")
+		for i, issue := range issues {
+			if i == maxSummaryIssues {
+				fmt.Fprintf(&b, "\n_%d more issues are in the scan results._\n", len(issues)-maxSummaryIssues)
+				break
+			}
+			suggested := issue.SuggestedVersion
+			if issue.MigrationTarget != "" {
+				suggested = issue.MigrationTarget
+			}
+			if issue.Existing {
+				suggested += " (existing)"
+			}
+			fmt.Fprintf(&b, "| %s | `%s` | `%s` | %s | %s | %s |\n",
+				issue.Severity, issue.FilePath, issue.Repository, issue.CurrentVersion, suggested, issue.IssueType)
+		}
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write job summary: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package output
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteGitHubAnnotations(t *testing.T) {
+	workspace := t.TempDir()
+	workflowDir := filepath.Join(workspace, ".github", "workflows")
+	if err := os.MkdirAll(workflowDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := "name: CI\non: push\njobs:\n  build:\n    steps:\n      - uses: actions/checkout@v2\n      - uses: \"actions/setup-node@v1\"\n"
+	if err := os.WriteFile(filepath.Join(workflowDir, "ci.yml"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := &ScanResult{Repositories: []RepositoryResult{
+		{FullName: "my-org/api", Issues: []ActionIssue{
+			{Repository: "actions/checkout", CurrentVersion: "v2", SuggestedVersion: "v4", IssueType: "outdated", Severity: "medium", Description: "Outdated: 50% behind", FilePath: ".github/workflows/ci.yml"},
+			{Repository: "actions/setup-node", CurrentVersion: "v1", IssueType: "deprecated", Severity: "high", Description: "Deprecated", FilePath: ".github/workflows/ci.yml", Existing: true},
+			{Repository: "actions/cache", CurrentVersion: "v1", IssueType: "outdated", Severity: "critical", Description: "Missing", FilePath: ".github/workflows/gone.yml"},
+		}},
+		{FullName: "my-org/web", Issues: []ActionIssue{
+			{Repository: "actions/checkout", CurrentVersion: "v2", IssueType: "outdated", Severity: "low", FilePath: ".github/workflows/ci.yml"},
+		}},
+	}}
+
+	var buf bytes.Buffer
+	count, err := WriteGitHubAnnotations(&buf, result, "My-Org/API", workspace)
+	if err != nil {
+		t.Fatalf("WriteGitHubAnnotations() returned error: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 annotations for my-org/api, got %d", count)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	expected := []string{
+		"::warning file=.github/workflows/ci.yml,line=6,title=outdated%3A actions/checkout@v2::Outdated: 50%25 behind (suggested version: v4)",
+		"::notice file=.github/workflows/ci.yml,line=7,title=deprecated%3A actions/setup-node@v1::Deprecated",
+		"::error file=.github/workflows/gone.yml,title=outdated%3A actions/cache@v1::Missing",
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got:\n%s", len(expected), buf.String())
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("Line %d:\nexpected %s\ngot      %s", i, expected[i], lines[i])
+		}
+	}
+}
+
+func TestWriteGitHubJobSummary(t *testing.T) {
+	result := &ScanResult{
+		Repositories: []RepositoryResult{
+			{FullName: "my-org/api", Issues: []ActionIssue{
+				{Repository: "actions/checkout", CurrentVersion: "v2", SuggestedVersion: "v4", IssueType: "outdated", Severity: "low", FilePath: ".github/workflows/ci.yml"},
+				{Repository: "actions/setup-node", CurrentVersion: "v1", MigrationTarget: "my-org/setup-node@v1", IssueType: "migration", Severity: "high", FilePath: ".github/workflows/ci.yml"},
+			}},
+			{FullName: "my-org/web", Issues: []ActionIssue{
+				{Repository: "actions/cache", CurrentVersion: "v1", IssueType: "outdated", Severity: "critical", FilePath: ".github/workflows/ci.yml"},
+			}},
+		},
+		Summary: Summary{TotalRepositories: 2, TotalActions: 5, IssuesBySeverity: map[string]int{"critical": 1, "high": 1, "low": 1}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteGitHubJobSummary(&buf, result, "my-org/api"); err != nil {
+		t.Fatalf("WriteGitHubJobSummary() returned error: %v", err)
+	}
+	summary := buf.String()
+
+	for _, expected := range []string{
+		"Scanned 2 repositories using 5 actions and found **3 issues**.",
+		"| critical | 1 |",
+		"| high | `.github/workflows/ci.yml` | `actions/setup-node` | v1 | my-org/setup-node@v1 | migration |",
+	} {
+		if !strings.Contains(summary, expected) {
+			t.Errorf("Expected summary to contain %q, got:\n%s", expected, summary)
+		}
+	}
+	if strings.Contains(summary, "actions/cache") {
+		t.Errorf("Expected issues of other repositories to be left out, got:\n%s", summary)
+	}
+	if strings.Index(summary, "actions/setup-node") > strings.Index(summary, "actions/checkout") {
+		t.Errorf("Expected issues ordered by severity, got:\n%s", summary)
+	}
+}
@@ -0,0 +1,67 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// generateRepositoryResults builds scan results for the given number of repositories
+func generateRepositoryResults(repos, actionsPerRepo int) []RepositoryResult {
+	results := make([]RepositoryResult, repos)
+	for r := range results {
+		fullName := fmt.Sprintf("my-org/repo-%d", repos-r)
+		var refs []workflow.ActionReference
+		var issues []ActionIssue
+		for a := 0; a < actionsPerRepo; a++ {
+			ref := workflow.ActionReference{
+				Repository: fmt.Sprintf("actions/action-%d", a%15),
+				Version:    fmt.Sprintf("v%d", a%4+1),
+				FilePath:   fmt.Sprintf(".github/workflows/wf-%d.yml", a%5),
+				Context:    fmt.Sprintf("job:build/step:step-%d", a),
+			}
+			refs = append(refs, ref)
+			if a%3 == 0 {
+				issues = append(issues, ActionIssue{
+					Repository:     ref.Repository,
+					CurrentVersion: ref.Version,
+					IssueType:      "outdated",
+					Severity:       "medium",
+					FilePath:       ref.FilePath,
+					Context:        ref.Context,
+				})
+			}
+		}
+		results[r] = RepositoryResult{
+			Name:          fmt.Sprintf("repo-%d", repos-r),
+			FullName:      fullName,
+			WorkflowFiles: []WorkflowFileResult{{Path: ".github/workflows/ci.yml", ActionCount: len(refs), Actions: refs}},
+			Actions:       refs,
+			Issues:        issues,
+		}
+	}
+	return results
+}
+
+func BenchmarkBuildScanResult(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		repos := generateRepositoryResults(500, 40)
+		b.StartTimer()
+		BuildScanResult("my-org", repos)
+	}
+}
+
+func BenchmarkFormatNotebook(b *testing.B) {
+	result := BuildScanResult("my-org", generateRepositoryResults(500, 40))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := FormatNotebook(result, io.Discard); err != nil {
+			b.Fatalf("FormatNotebook() returned error: %v", err)
+		}
+	}
+}
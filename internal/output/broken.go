@@ -0,0 +1,78 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BrokenReference is a repository or ref used by scanned workflows that does not exist, summarized
+// from broken-reference issues. Every workflow using it fails when the job reaches the step.
+type BrokenReference struct {
+	Action       string   `json:"action"`                  // Action or reusable workflow repository
+	WorkflowPath string   `json:"workflow_path,omitempty"` // Reusable workflow or nested action path
+	Version      string   `json:"version"`
+	Repositories []string `json:"repositories"` // Scanned repositories using the reference, sorted
+	Occurrences  int      `json:"occurrences"`  // Broken-reference issues for the reference
+}
+
+// Reference returns the reference as written in a uses line, e.g. "my-org/deploy@v9"
+func (r BrokenReference) Reference() string {
+	target := r.Action
+	if r.WorkflowPath != "" {
+		target += "/" + r.WorkflowPath
+	}
+	return target + "@" + r.Version
+}
+
+// tallyBrokenReferences adds the broken-reference issues of a repository to the tallies
+func tallyBrokenReferences(tallies map[string]*BrokenReference, repo RepositoryResult) {
+	for _, issue := range repo.Issues {
+		if issue.IssueType != "broken-reference" {
+			continue
+		}
+		reference := BrokenReference{Action: issue.Repository, WorkflowPath: issue.WorkflowPath, Version: issue.CurrentVersion}
+		tally, ok := tallies[reference.Reference()]
+		if !ok {
+			tally = &reference
+			tallies[reference.Reference()] = tally
+		}
+		if !containsRepository(tally.Repositories, repo.FullName) {
+			tally.Repositories = append(tally.Repositories, repo.FullName)
+		}
+		tally.Occurrences++
+	}
+}
+
+// containsRepository reports whether a list of repositories contains one
+func containsRepository(repositories []string, repository string) bool {
+	for _, r := range repositories {
+		if r == repository {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedBrokenReferences returns the tallied references, those used by most repositories first
+func sortedBrokenReferences(tallies map[string]*BrokenReference) []BrokenReference {
+	var references []BrokenReference
+	for _, tally := range tallies {
+		reference := *tally
+		sort.Strings(reference.Repositories)
+		references = append(references, reference)
+	}
+	sort.Slice(references, func(i, j int) bool {
+		if len(references[i].Repositories) != len(references[j].Repositories) {
+			return len(references[i].Repositories) > len(references[j].Repositories)
+		}
+		return references[i].Reference() < references[j].Reference()
+	})
+	return references
+}
+
+// brokenReferenceLine is the Markdown list item of a broken reference in reports
+func brokenReferenceLine(reference BrokenReference) string {
+	return fmt.Sprintf("- ❌ **%s** does not exist: %d uses in %s\n",
+		reference.Reference(), reference.Occurrences, strings.Join(reference.Repositories, ", "))
+}
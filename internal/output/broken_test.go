@@ -0,0 +1,44 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSummaryBrokenReferences(t *testing.T) {
+	repositories := []RepositoryResult{
+		{FullName: "my-org/api", Issues: []ActionIssue{
+			{Repository: "my-org/deploy", CurrentVersion: "v9", IssueType: "broken-reference"},
+			{Repository: "my-org/deploy", CurrentVersion: "v9", IssueType: "broken-reference"},
+			{Repository: "my-org/workflows", WorkflowPath: ".github/workflows/build.yml", CurrentVersion: "v1", IssueType: "broken-reference"},
+		}},
+		{FullName: "my-org/web", Issues: []ActionIssue{
+			{Repository: "my-org/deploy", CurrentVersion: "v9", IssueType: "broken-reference"},
+			{Repository: "actions/checkout", CurrentVersion: "v3", IssueType: "outdated"},
+		}},
+	}
+
+	references := calculateSummary(repositories).BrokenReferences
+	if len(references) != 2 {
+		t.Fatalf("Expected 2 broken references, got %+v", references)
+	}
+	deploy := references[0]
+	if deploy.Reference() != "my-org/deploy@v9" || deploy.Occurrences != 3 || strings.Join(deploy.Repositories, ",") != "my-org/api,my-org/web" {
+		t.Errorf("Expected my-org/deploy@v9 first with 3 uses in 2 repositories, got %+v", deploy)
+	}
+	if reference := references[1].Reference(); reference != "my-org/workflows/.github/workflows/build.yml@v1" {
+		t.Errorf("Expected the reusable workflow path in the reference, got %q", reference)
+	}
+
+	result := &ScanResult{Owner: "my-org", Repositories: repositories, Summary: calculateSummary(repositories)}
+	var table strings.Builder
+	if err := FormatTable(result, &table); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(table.String(), "BROKEN REFERENCE") || !strings.Contains(table.String(), "my-org/deploy@v9") {
+		t.Errorf("Expected broken references in the table, got:\n%s", table.String())
+	}
+	if line := brokenReferenceLine(deploy); !strings.Contains(line, "**my-org/deploy@v9** does not exist: 3 uses in my-org/api, my-org/web") {
+		t.Errorf("Unexpected report line %q", line)
+	}
+}
@@ -0,0 +1,42 @@
+package output
+
+import (
+	"os"
+)
+
+// ANSI color codes for terminal output. Every code is two digits, so colored text always gains
+// the same number of bytes and tabwriter columns of colored cells stay aligned.
+const (
+	colorDefault = "39"
+	colorGray    = "90"
+	colorRed     = "91"
+	colorYellow  = "93"
+	colorCyan    = "96"
+	colorMagenta = "95"
+)
+
+// severityColors maps issue severity to a terminal color
+var severityColors = map[string]string{
+	"critical": colorMagenta,
+	"high":     colorRed,
+	"medium":   colorYellow,
+	"low":      colorCyan,
+}
+
+// paint wraps text in an ANSI color code
+func paint(code, text string) string {
+	return "\x1b[" + code + "m" + text + "\x1b[0m"
+}
+
+// UseColor reports whether output to a file should be colored: it must be a terminal, and
+// neither noColor, the NO_COLOR environment variable, nor TERM=dumb may disable color
+func UseColor(file *os.File, noColor bool) bool {
+	if noColor || os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
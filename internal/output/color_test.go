@@ -0,0 +1,39 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUseColor(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	defer file.Close()
+
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "xterm-256color")
+	if UseColor(file, false) {
+		t.Errorf("Expected no color for a regular file")
+	}
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Skipf("No null device: %v", err)
+	}
+	defer devNull.Close()
+
+	// The null device is a character device, standing in for a terminal
+	if !UseColor(devNull, false) {
+		t.Errorf("Expected color for a character device")
+	}
+	if UseColor(devNull, true) {
+		t.Errorf("Expected --no-color to disable color")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if UseColor(devNull, false) {
+		t.Errorf("Expected NO_COLOR to disable color")
+	}
+}
@@ -0,0 +1,104 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Deadline is an announced removal of action versions still in use, summarized from brownout issues
+type Deadline struct {
+	Action       string    `json:"action"`
+	Versions     []string  `json:"versions"` // Pinned versions that stop working, sorted
+	Date         time.Time `json:"date"`
+	Repositories int       `json:"repositories"` // Scanned repositories using the versions
+	Occurrences  int       `json:"occurrences"`  // Brownout issues for the action and date
+	Announcement string    `json:"announcement,omitempty"`
+}
+
+// DaysFrom returns the days from a time until the deadline, negative once it has passed
+func (d Deadline) DaysFrom(t time.Time) int {
+	return int(d.Date.Sub(t.UTC().Truncate(24*time.Hour)).Hours() / 24)
+}
+
+// deadlineTally accumulates a deadline and the repositories and versions it affects
+type deadlineTally struct {
+	deadline     Deadline
+	versions     map[string]bool
+	repositories map[string]bool
+}
+
+// tallyDeadlines adds the brownout issues of a repository to the deadline tallies
+func tallyDeadlines(tallies map[string]*deadlineTally, repo RepositoryResult) {
+	for _, issue := range repo.Issues {
+		if issue.IssueType != "brownout" || issue.Deadline == nil {
+			continue
+		}
+		key := issue.Repository + "@" + issue.Deadline.Format(time.DateOnly)
+		tally, ok := tallies[key]
+		if !ok {
+			tally = &deadlineTally{
+				deadline:     Deadline{Action: issue.Repository, Date: *issue.Deadline, Announcement: issue.Announcement},
+				versions:     make(map[string]bool),
+				repositories: make(map[string]bool),
+			}
+			tallies[key] = tally
+		}
+		version := issue.CurrentVersion
+		if issue.PinComment != "" {
+			version = issue.PinComment
+		}
+		tally.versions[version] = true
+		tally.repositories[repo.FullName] = true
+		tally.deadline.Occurrences++
+	}
+}
+
+// sortedDeadlines returns the tallied deadlines, soonest first
+func sortedDeadlines(tallies map[string]*deadlineTally) []Deadline {
+	var deadlines []Deadline
+	for _, tally := range tallies {
+		deadline := tally.deadline
+		for version := range tally.versions {
+			deadline.Versions = append(deadline.Versions, version)
+		}
+		sort.Strings(deadline.Versions)
+		deadline.Repositories = len(tally.repositories)
+		deadlines = append(deadlines, deadline)
+	}
+	sort.Slice(deadlines, func(i, j int) bool {
+		if !deadlines[i].Date.Equal(deadlines[j].Date) {
+			return deadlines[i].Date.Before(deadlines[j].Date)
+		}
+		return deadlines[i].Action < deadlines[j].Action
+	})
+	return deadlines
+}
+
+// When describes a deadline relative to a time, as "in 12 days" or "3 days ago"
+func (d Deadline) When(t time.Time) string {
+	switch days := d.DaysFrom(t); {
+	case days > 0:
+		return fmt.Sprintf("in %d days", days)
+	case days == 0:
+		return "today"
+	default:
+		return fmt.Sprintf("%d days ago", -days)
+	}
+}
+
+// deadlineLine is the Markdown list item of a deadline in reports, relative to the scan time
+func deadlineLine(deadline Deadline, scanTime time.Time) string {
+	verb := "stops"
+	if deadline.DaysFrom(scanTime) < 0 {
+		verb = "stopped"
+	}
+	line := fmt.Sprintf("- ⏰ **%s %s** %s working on **%s** (%s): %d uses in %d repositories",
+		deadline.Action, strings.Join(deadline.Versions, ", "), verb, deadline.Date.Format(time.DateOnly), deadline.When(scanTime),
+		deadline.Occurrences, deadline.Repositories)
+	if deadline.Announcement != "" {
+		line += fmt.Sprintf(" ([announcement](%s))", deadline.Announcement)
+	}
+	return line + "\n"
+}
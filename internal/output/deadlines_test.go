@@ -0,0 +1,43 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSummaryDeadlines(t *testing.T) {
+	removal := time.Date(2025, 1, 30, 0, 0, 0, 0, time.UTC)
+	cacheRemoval := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	repositories := []RepositoryResult{
+		{FullName: "my-org/api", Issues: []ActionIssue{
+			{Repository: "actions/upload-artifact", CurrentVersion: "v3", IssueType: "brownout", Deadline: &removal},
+			{Repository: "actions/upload-artifact", CurrentVersion: "v2", IssueType: "brownout", Deadline: &removal},
+			{Repository: "actions/cache", CurrentVersion: "v2", IssueType: "brownout", Deadline: &cacheRemoval},
+		}},
+		{FullName: "my-org/web", Issues: []ActionIssue{
+			{Repository: "actions/upload-artifact", CurrentVersion: "v3", IssueType: "brownout", Deadline: &removal},
+			{Repository: "actions/checkout", CurrentVersion: "v3", IssueType: "outdated"},
+		}},
+	}
+
+	deadlines := calculateSummary(repositories).Deadlines
+	if len(deadlines) != 2 {
+		t.Fatalf("Expected 2 deadlines, got %+v", deadlines)
+	}
+	artifact := deadlines[0]
+	if artifact.Action != "actions/upload-artifact" || artifact.Repositories != 2 || artifact.Occurrences != 3 {
+		t.Errorf("Expected the artifact deadline first with 3 uses in 2 repositories, got %+v", artifact)
+	}
+	if strings.Join(artifact.Versions, ",") != "v2,v3" {
+		t.Errorf("Expected versions v2 and v3, got %v", artifact.Versions)
+	}
+
+	scanTime := time.Date(2025, 1, 20, 15, 0, 0, 0, time.UTC)
+	if when := artifact.When(scanTime); when != "in 10 days" {
+		t.Errorf("Expected \"in 10 days\", got %q", when)
+	}
+	if line := deadlineLine(artifact, scanTime.AddDate(0, 1, 0)); !strings.Contains(line, "stopped working on **2025-01-30** (21 days ago)") {
+		t.Errorf("Expected a passed deadline, got %q", line)
+	}
+}
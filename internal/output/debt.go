@@ -0,0 +1,131 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"time"
+)
+
+// DefaultDebtWeights weigh issues by severity in the debt score
+var DefaultDebtWeights = map[string]float64{
+	"critical": 10,
+	"high":     5,
+	"medium":   2,
+	"low":      1,
+}
+
+// DebtSummary is the organization's actions debt: issues weighted by severity, per scanned repository,
+// as one number to track from scan to scan
+type DebtSummary struct {
+	Score          float64  `json:"score"`                 // Weighted issues per scanned repository, rounded to two decimals
+	WeightedIssues float64  `json:"weighted_issues"`       // Sum of the weights of every active issue
+	Repositories   int      `json:"repositories"`          // Scanned repositories the weighted issues are spread over
+	Target         *float64 `json:"target,omitempty"`      // Score aimed for by the current target
+	TargetName     string   `json:"target_name,omitempty"` // Name of the current target, e.g. "2026-Q4"
+	OnTarget       bool     `json:"on_target,omitempty"`   // The score is at or below the target
+}
+
+// DebtTarget is a debt score to reach by a date
+type DebtTarget struct {
+	Name  string  `json:"name,omitempty"` // e.g. "2026-Q4"
+	Until string  `json:"until"`          // Last day the target applies, as YYYY-MM-DD
+	Score float64 `json:"score"`
+}
+
+// DebtPolicy sets the severity weights of the debt score and the targets it is measured against
+type DebtPolicy struct {
+	Weights map[string]float64 `json:"weights,omitempty"` // Severities left out keep their default weight
+	Targets []DebtTarget       `json:"targets,omitempty"`
+}
+
+// LoadDebtPolicy reads a JSON debt policy file
+func LoadDebtPolicy(filename string) (*DebtPolicy, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read debt targets file: %w", err)
+	}
+
+	var policy DebtPolicy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse debt targets file: %w", err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Validate checks that weights name known severities and are not negative, and that targets have
+// dates and scores
+func (p *DebtPolicy) Validate() error {
+	for severity, weight := range p.Weights {
+		if !IsValidSeverity(severity) {
+			return fmt.Errorf("debt weight for unknown severity %q", severity)
+		}
+		if weight < 0 {
+			return fmt.Errorf("debt weight for %s must not be negative", severity)
+		}
+	}
+	for _, target := range p.Targets {
+		if _, err := time.Parse("2006-01-02", target.Until); err != nil {
+			return fmt.Errorf("debt target %q: until must be a YYYY-MM-DD date", target.Name)
+		}
+		if target.Score < 0 {
+			return fmt.Errorf("debt target %q: score must not be negative", target.Name)
+		}
+	}
+	return nil
+}
+
+// weight returns the weight of a severity, falling back to its default weight
+func (p *DebtPolicy) weight(severity string) float64 {
+	if p != nil {
+		if weight, ok := p.Weights[severity]; ok {
+			return weight
+		}
+	}
+	return DefaultDebtWeights[severity]
+}
+
+// CurrentTarget returns the target applying at a time: the one ending soonest on or after it, or nil
+// once every target has passed
+func (p *DebtPolicy) CurrentTarget(now time.Time) *DebtTarget {
+	if p == nil {
+		return nil
+	}
+	targets := append([]DebtTarget(nil), p.Targets...)
+	sort.SliceStable(targets, func(i, j int) bool { return targets[i].Until < targets[j].Until })
+	today := now.Format("2006-01-02")
+	for i := range targets {
+		if targets[i].Until >= today {
+			return &targets[i]
+		}
+	}
+	return nil
+}
+
+// CalculateDebt scores a summary's active issues with a policy's weights, and measures the score
+// against the policy's target at a time; a nil policy uses the default weights without a target
+func CalculateDebt(summary Summary, policy *DebtPolicy, now time.Time) *DebtSummary {
+	debt := &DebtSummary{Repositories: summary.TotalRepositories}
+	for _, severity := range severities {
+		debt.WeightedIssues += policy.weight(severity) * float64(summary.IssuesBySeverity[severity])
+	}
+	if debt.Repositories > 0 {
+		debt.Score = math.Round(debt.WeightedIssues/float64(debt.Repositories)*100) / 100
+	}
+	if target := policy.CurrentTarget(now); target != nil {
+		debt.Target = &target.Score
+		debt.TargetName = target.Name
+		debt.OnTarget = debt.Score <= target.Score
+	}
+	return debt
+}
+
+// AboveTarget reports whether a debt score misses its target; scores without a target never do
+func (d *DebtSummary) AboveTarget() bool {
+	return d != nil && d.Target != nil && !d.OnTarget
+}
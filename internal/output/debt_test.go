@@ -0,0 +1,75 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalculateDebt(t *testing.T) {
+	summary := Summary{
+		TotalRepositories: 3,
+		IssuesBySeverity:  map[string]int{"critical": 1, "high": 2, "medium": 1, "low": 1},
+	}
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	debt := CalculateDebt(summary, nil, now)
+	if debt.WeightedIssues != 23 || debt.Score != 7.67 || debt.Target != nil {
+		t.Errorf("Expected 23 weighted issues scoring 7.67 without a target, got %+v", debt)
+	}
+
+	policy := &DebtPolicy{
+		Weights: map[string]float64{"critical": 20},
+		Targets: []DebtTarget{
+			{Name: "2026-Q4", Until: "2026-12-31", Score: 8},
+			{Name: "2026-Q3", Until: "2026-09-30", Score: 12},
+			{Name: "2026-10", Until: "2026-10-16", Score: 10},
+		},
+	}
+	debt = CalculateDebt(summary, policy, now)
+	if debt.Score != 11 || debt.TargetName != "2026-10" || *debt.Target != 10 || !debt.AboveTarget() {
+		t.Errorf("Expected a score of 11 above the 2026-10 target of 10, got %+v", debt)
+	}
+
+	debt = CalculateDebt(summary, policy, now.AddDate(0, 0, 1))
+	if debt.TargetName != "2026-Q4" || *debt.Target != 8 {
+		t.Errorf("Expected the 2026-Q4 target once the 2026-10 target has passed, got %+v", debt)
+	}
+	if debt = CalculateDebt(summary, policy, now.AddDate(1, 0, 0)); debt.Target != nil || debt.AboveTarget() {
+		t.Errorf("Expected no target once every target has passed, got %+v", debt)
+	}
+
+	if debt := CalculateDebt(Summary{}, policy, now); debt.Score != 0 {
+		t.Errorf("Expected a score of 0 without repositories, got %+v", debt)
+	}
+}
+
+func TestLoadDebtPolicy(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "debt.json")
+	if err := os.WriteFile(file, []byte(`{"weights": {"low": 0}, "targets": [{"name": "2026-Q4", "until": "2026-12-31", "score": 4.5}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := LoadDebtPolicy(file)
+	if err != nil {
+		t.Fatalf("Failed to load debt policy: %v", err)
+	}
+	if policy.weight("low") != 0 || policy.weight("high") != 5 || len(policy.Targets) != 1 {
+		t.Errorf("Expected the low weight overridden and others defaulted, got %+v", policy)
+	}
+
+	for invalid, message := range map[string]string{
+		`{"weights": {"urgent": 1}}`:                          "unknown severity",
+		`{"weights": {"high": -1}}`:                           "must not be negative",
+		`{"targets": [{"until": "2026-Q4", "score": 1}]}`:     "YYYY-MM-DD",
+		`{"targets": [{"until": "2026-12-31", "score": -1}]}`: "must not be negative",
+	} {
+		if err := os.WriteFile(file, []byte(invalid), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadDebtPolicy(file); err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("Expected an error containing %q for %s, got %v", message, invalid, err)
+		}
+	}
+}
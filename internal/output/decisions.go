@@ -0,0 +1,106 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// Decision outcomes
+const (
+	DecisionMatched    = "matched"    // A rule applies to the action
+	DecisionUnmatched  = "unmatched"  // No rule applies, so only rule-independent checks ran
+	DecisionRaised     = "raised"     // A check raised an issue
+	DecisionNotRaised  = "not-raised" // A check ran without raising an issue
+	DecisionReported   = "reported"   // The issue is in the scan results
+	DecisionSuppressed = "suppressed" // The issue was suppressed or excluded by the severity policy
+	DecisionSkipped    = "skipped"    // The workflow file was not analyzed
+)
+
+// Decision stages besides the checks, which are recorded under their own names
+const (
+	DecisionStageRule           = "rule"            // Rule selection
+	DecisionStageReport         = "report"          // Suppressions and the severity policy
+	DecisionStageWorkflowFilter = "workflow-filter" // --workflow-filter and --exclude-workflow
+)
+
+// Decision records why scan did or did not report issues for one action reference, or why a workflow
+// file was not analyzed at all (scan --explain-all)
+type Decision struct {
+	Action   string         `json:"action,omitempty"` // "owner/repo[/path]@version", or "./path" for local reusable workflows; empty for skipped workflow files
+	FilePath string         `json:"file_path"`
+	Context  string         `json:"context,omitempty"`
+	Rule     string         `json:"rule,omitempty"` // Repository, and workflow path, of the rule that applies
+	Steps    []DecisionStep `json:"steps"`
+
+	repository string
+	version    string
+}
+
+// DecisionStep is one stage of a decision
+type DecisionStep struct {
+	Stage   string `json:"stage"`   // DecisionStageRule, a check name, DecisionStageReport, or DecisionStageWorkflowFilter
+	Outcome string `json:"outcome"` // One of the Decision outcomes
+	Reason  string `json:"reason"`
+}
+
+// NewDecision starts the decision of an action reference
+func NewDecision(action workflow.ActionReference) Decision {
+	uses := action.Repository
+	if action.WorkflowPath != "" {
+		uses += "/" + action.WorkflowPath
+	}
+	uses += "@" + action.Version
+	if action.Local {
+		uses = "./" + action.WorkflowPath
+	}
+	return Decision{
+		Action:     uses,
+		FilePath:   action.FilePath,
+		Context:    action.Context,
+		repository: action.Repository,
+		version:    action.Version,
+	}
+}
+
+// AddStep appends a stage to the decision
+func (d *Decision) AddStep(stage, outcome, reason string) {
+	d.Steps = append(d.Steps, DecisionStep{Stage: stage, Outcome: outcome, Reason: reason})
+}
+
+// RecordReported completes the decisions of a repository with the fate of each issue raised for their
+// action references: reported, or suppressed along with the reason. Issues without an action reference,
+// such as missing required actions, have no decision to complete.
+func RecordReported(decisions []Decision, issues []ActionIssue, suppressed []SuppressedIssue) []Decision {
+	for i := range decisions {
+		decision := &decisions[i]
+		for _, issue := range issues {
+			if decision.concerns(issue) {
+				decision.AddStep(DecisionStageReport, DecisionReported, fmt.Sprintf("%s (%s): %s", issue.IssueType, issue.Severity, issue.Description))
+			}
+		}
+		for _, issue := range suppressed {
+			if decision.concerns(issue.ActionIssue) {
+				reason := issue.Reason
+				if reason == "" {
+					reason = "matched a suppressions file entry"
+				}
+				decision.AddStep(DecisionStageReport, DecisionSuppressed, fmt.Sprintf("%s: %s", issue.IssueType, reason))
+			}
+		}
+	}
+	return decisions
+}
+
+// SkippedFileDecision records a workflow file the scan did not analyze
+func SkippedFileDecision(path, stage, reason string) Decision {
+	decision := Decision{FilePath: path}
+	decision.AddStep(stage, DecisionSkipped, reason)
+	return decision
+}
+
+// concerns reports whether an issue was raised for the decision's action reference
+func (d *Decision) concerns(issue ActionIssue) bool {
+	return d.repository != "" && issue.Repository == d.repository && issue.CurrentVersion == d.version &&
+		issue.FilePath == d.FilePath && issue.Context == d.Context
+}
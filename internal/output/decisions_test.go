@@ -0,0 +1,41 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+func TestRecordReported(t *testing.T) {
+	decisions := []Decision{
+		NewDecision(workflow.ActionReference{Repository: "actions/checkout", Version: "v2", FilePath: "ci.yml", Context: "job:build"}),
+		NewDecision(workflow.ActionReference{Repository: "actions/checkout", Version: "v2", FilePath: "release.yml"}),
+		NewDecision(workflow.ActionReference{Repository: "my-org/shared", WorkflowPath: ".github/workflows/build.yml", Version: "v1", FilePath: "ci.yml"}),
+	}
+	issues := []ActionIssue{
+		{Repository: "actions/checkout", CurrentVersion: "v2", IssueType: "outdated", Severity: "low", Description: "old", FilePath: "ci.yml", Context: "job:build"},
+		{IssueType: "missing-required-action", FilePath: "ci.yml"},
+	}
+	suppressed := []SuppressedIssue{
+		{ActionIssue: ActionIssue{Repository: "actions/checkout", CurrentVersion: "v2", IssueType: "outdated", FilePath: "release.yml"}, Reason: "frozen release branch"},
+	}
+
+	decisions = RecordReported(decisions, issues, suppressed)
+	if decisions[2].Action != "my-org/shared/.github/workflows/build.yml@v1" {
+		t.Errorf("Expected the workflow path in the action, got %q", decisions[2].Action)
+	}
+	if len(decisions[0].Steps) != 1 || decisions[0].Steps[0].Outcome != DecisionReported || decisions[0].Steps[0].Reason != "outdated (low): old" {
+		t.Errorf("Expected the outdated issue to be reported, got %+v", decisions[0].Steps)
+	}
+	if len(decisions[1].Steps) != 1 || decisions[1].Steps[0].Outcome != DecisionSuppressed || decisions[1].Steps[0].Reason != "outdated: frozen release branch" {
+		t.Errorf("Expected the suppression to be recorded, got %+v", decisions[1].Steps)
+	}
+	if len(decisions[2].Steps) != 0 {
+		t.Errorf("Expected no issues for the shared workflow, got %+v", decisions[2].Steps)
+	}
+
+	skipped := SkippedFileDecision(".github/workflows/legacy.yml", DecisionStageWorkflowFilter, "excluded")
+	if skipped.Action != "" || skipped.Steps[0].Outcome != DecisionSkipped {
+		t.Errorf("Unexpected skipped file decision %+v", skipped)
+	}
+}
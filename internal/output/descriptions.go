@@ -0,0 +1,128 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/links"
+)
+
+// DefaultDescriptionKey names the description template of issue types without one of their own
+const DefaultDescriptionKey = "*"
+
+// DescriptionTemplates rewrite issue descriptions, so organizations can add internal links,
+// translations, or team guidance to the text shown in reports and pull requests
+type DescriptionTemplates struct {
+	templates map[string]*template.Template // By issue type, rule id, or DefaultDescriptionKey
+}
+
+// DescriptionTemplateData is the data available to description templates: every field of the issue,
+// such as .SuggestedVersion and .RuleID, and the following
+type DescriptionTemplateData struct {
+	ActionIssue
+	Default           string // Built-in description, for wrapping or rewording
+	ScannedRepository string // "owner/name" of the repository the issue was found in
+}
+
+// LoadDescriptionTemplates loads description templates from a JSON object mapping issue types, rule
+// ids, or "*" to Go templates
+func LoadDescriptionTemplates(filename string) (*DescriptionTemplates, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read description templates file: %w", err)
+	}
+
+	var sources map[string]string
+	if err := json.Unmarshal(data, &sources); err != nil {
+		return nil, fmt.Errorf("unable to parse description templates file as a JSON object of templates: %w", err)
+	}
+	return ParseDescriptionTemplates(sources)
+}
+
+// ParseDescriptionTemplates parses description templates keyed by issue type, rule id, or "*"
+func ParseDescriptionTemplates(sources map[string]string) (*DescriptionTemplates, error) {
+	templates := &DescriptionTemplates{templates: make(map[string]*template.Template)}
+	for key, source := range sources {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, fmt.Errorf("description template with an empty issue type")
+		}
+		tmpl, err := template.New(key).Funcs(reportTemplateFuncs).Option("missingkey=zero").Parse(source)
+		if err != nil {
+			return nil, fmt.Errorf("invalid description template for %s: %w", key, err)
+		}
+		templates.templates[key] = tmpl
+	}
+	return templates, nil
+}
+
+// SetLinks makes the link mappings available to the description templates' link function
+func (t *DescriptionTemplates) SetLinks(mapping *links.Mapping) {
+	if t == nil {
+		return
+	}
+	for _, tmpl := range t.templates {
+		tmpl.Funcs(template.FuncMap{"link": linkFunc(mapping)})
+	}
+}
+
+// template returns the template of an issue: its issue type's, then its rule id's, then the default
+func (t *DescriptionTemplates) template(issue ActionIssue) *template.Template {
+	if tmpl, ok := t.templates[issue.IssueType]; ok {
+		return tmpl
+	}
+	if tmpl, ok := t.templates[RuleID(issue.IssueType)]; ok {
+		return tmpl
+	}
+	return t.templates[DefaultDescriptionKey]
+}
+
+// Apply rewrites the descriptions of every issue, suppressed or not, that has a template
+// Rule ids must already be annotated, so templates can use them. Issues whose template fails to
+// render, or renders empty text, keep their built-in description; the failures are returned.
+func (t *DescriptionTemplates) Apply(repositories []RepositoryResult) error {
+	if t == nil || len(t.templates) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for r := range repositories {
+		repo := &repositories[r]
+		for i := range repo.Issues {
+			if err := t.apply(&repo.Issues[i], repo.FullName); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		for i := range repo.SuppressedIssues {
+			if err := t.apply(&repo.SuppressedIssues[i].ActionIssue, repo.FullName); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// apply rewrites the description of one issue
+func (t *DescriptionTemplates) apply(issue *ActionIssue, scannedRepository string) error {
+	tmpl := t.template(*issue)
+	if tmpl == nil {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	data := DescriptionTemplateData{ActionIssue: *issue, Default: issue.Description, ScannedRepository: scannedRepository}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("%s: %s issue of %s: %w", scannedRepository, issue.IssueType, issue.Repository, err)
+	}
+	description := strings.TrimSpace(buf.String())
+	if description == "" {
+		return fmt.Errorf("%s: %s issue of %s: the description template rendered empty text", scannedRepository, issue.IssueType, issue.Repository)
+	}
+	issue.Description = description
+	return nil
+}
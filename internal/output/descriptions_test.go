@@ -0,0 +1,95 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDescriptionTemplatesApply(t *testing.T) {
+	templates, err := ParseDescriptionTemplates(map[string]string{
+		"outdated": `{{.Default}} See {{.DocsURL}} or ask #platform about {{.ScannedRepository}}.`,
+		"AM006":    `Banned: {{upper .Repository}}`,
+		"*":        `{{.Default}} (team guidance)`,
+	})
+	if err != nil {
+		t.Fatalf("Expected templates to parse, got %v", err)
+	}
+
+	repositories := []RepositoryResult{{
+		FullName: "acme/app",
+		Issues: []ActionIssue{
+			{Repository: "actions/checkout", IssueType: "outdated", Description: "Update to v4."},
+			{Repository: "actions/cache", IssueType: "deprecated-node", Description: "Node 16 is deprecated."},
+		},
+		SuppressedIssues: []SuppressedIssue{{ActionIssue: ActionIssue{Repository: "evil/action", IssueType: "banned-action", Description: "Banned."}}},
+	}}
+	AnnotateRules(repositories, "https://wiki.acme.example/rules")
+	if err := templates.Apply(repositories); err != nil {
+		t.Fatalf("Expected templates to render, got %v", err)
+	}
+
+	issues := repositories[0].Issues
+	if expected := "Update to v4. See https://wiki.acme.example/rules#outdated or ask #platform about acme/app."; issues[0].Description != expected {
+		t.Errorf("Expected %q, got %q", expected, issues[0].Description)
+	}
+	if expected := "Node 16 is deprecated. (team guidance)"; issues[1].Description != expected {
+		t.Errorf("Expected the default template, got %q", issues[1].Description)
+	}
+	if got := repositories[0].SuppressedIssues[0].Description; got != "Banned: EVIL/ACTION" {
+		t.Errorf("Expected the rule id template on suppressed issues, got %q", got)
+	}
+}
+
+func TestDescriptionTemplatesKeepDefaultOnFailure(t *testing.T) {
+	templates, err := ParseDescriptionTemplates(map[string]string{
+		"outdated": `{{.Missing.Field}}`,
+		"pinned":   `   `,
+	})
+	if err != nil {
+		t.Fatalf("Expected templates to parse, got %v", err)
+	}
+
+	repositories := []RepositoryResult{{
+		FullName: "acme/app",
+		Issues: []ActionIssue{
+			{Repository: "actions/checkout", IssueType: "outdated", Description: "Update to v4."},
+			{Repository: "actions/cache", IssueType: "pinned", Description: "Pinned."},
+			{Repository: "actions/setup-go", IssueType: "deprecated-node", Description: "Untouched."},
+		},
+	}}
+	err = templates.Apply(repositories)
+	if err == nil || !strings.Contains(err.Error(), "acme/app: outdated issue of actions/checkout") || !strings.Contains(err.Error(), "rendered empty text") {
+		t.Errorf("Expected both failures to be reported, got %v", err)
+	}
+	for i, expected := range []string{"Update to v4.", "Pinned.", "Untouched."} {
+		if got := repositories[0].Issues[i].Description; got != expected {
+			t.Errorf("Expected issue %d to keep %q, got %q", i, expected, got)
+		}
+	}
+}
+
+func TestLoadDescriptionTemplates(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "descriptions.json")
+	if err := os.WriteFile(valid, []byte(`{"outdated": "Mettez à jour vers {{.SuggestedVersion}}."}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	templates, err := LoadDescriptionTemplates(valid)
+	if err != nil {
+		t.Fatalf("Expected the file to load, got %v", err)
+	}
+	repositories := []RepositoryResult{{Issues: []ActionIssue{{IssueType: "outdated", SuggestedVersion: "v4"}}}}
+	if err := templates.Apply(repositories); err != nil || repositories[0].Issues[0].Description != "Mettez à jour vers v4." {
+		t.Errorf("Expected a translated description, got %q (%v)", repositories[0].Issues[0].Description, err)
+	}
+
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"outdated": "{{.Default"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDescriptionTemplates(invalid); err == nil || !strings.Contains(err.Error(), "outdated") {
+		t.Errorf("Expected a parse error naming the issue type, got %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package output
+
+import "sort"
+
+// DeployWorkflow is a workflow file with jobs deploying to environments, and the issues found in it
+type DeployWorkflow struct {
+	Repository      string
+	FilePath        string
+	Environments    []string // Environment names as written, sorted
+	Jobs            int      // Jobs deploying to an environment
+	Issues          int      // Issues found in the workflow file
+	HighestSeverity string   // Severity of the most severe issue; empty when there are none
+}
+
+// DeploymentInventory lists the workflow files of a scan that deploy to environments, riskiest first:
+// those with the most severe issues, then those with the most issues. Outdated actions in deploy
+// workflows run with access to environment secrets, so they are the ones to update first.
+func DeploymentInventory(result *ScanResult) []DeployWorkflow {
+	var workflows []DeployWorkflow
+	for _, repo := range result.Repositories {
+		byFile := make(map[string]*DeployWorkflow)
+		environments := make(map[string]map[string]bool)
+		var order []string
+		for _, usage := range repo.Environments {
+			deploy, ok := byFile[usage.FilePath]
+			if !ok {
+				deploy = &DeployWorkflow{Repository: repo.FullName, FilePath: usage.FilePath}
+				byFile[usage.FilePath] = deploy
+				environments[usage.FilePath] = make(map[string]bool)
+				order = append(order, usage.FilePath)
+			}
+			deploy.Jobs++
+			environments[usage.FilePath][usage.Environment] = true
+		}
+
+		for _, issue := range repo.Issues {
+			deploy, ok := byFile[issue.FilePath]
+			if !ok {
+				continue
+			}
+			deploy.Issues++
+			if deploy.HighestSeverity == "" || isHigherSeverity(issue.Severity, deploy.HighestSeverity) {
+				deploy.HighestSeverity = issue.Severity
+			}
+		}
+
+		for _, filePath := range order {
+			deploy := byFile[filePath]
+			deploy.Environments = sortedKeys(environments[filePath])
+			workflows = append(workflows, *deploy)
+		}
+	}
+
+	sort.SliceStable(workflows, func(i, j int) bool {
+		if workflows[i].HighestSeverity != workflows[j].HighestSeverity {
+			return isHigherSeverity(workflows[i].HighestSeverity, workflows[j].HighestSeverity)
+		}
+		if workflows[i].Issues != workflows[j].Issues {
+			return workflows[i].Issues > workflows[j].Issues
+		}
+		if workflows[i].Repository != workflows[j].Repository {
+			return workflows[i].Repository < workflows[j].Repository
+		}
+		return workflows[i].FilePath < workflows[j].FilePath
+	})
+	return workflows
+}
@@ -0,0 +1,83 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+func environmentsResult() *ScanResult {
+	return &ScanResult{
+		Owner: "my-org",
+		Repositories: []RepositoryResult{
+			{
+				FullName: "my-org/api",
+				Environments: []workflow.EnvironmentUsage{
+					{FilePath: ".github/workflows/deploy.yml", Job: "staging", Environment: "staging"},
+					{FilePath: ".github/workflows/deploy.yml", Job: "production", Environment: "production", URL: "https://api.example.com"},
+				},
+				Issues: []ActionIssue{
+					{Repository: "actions/checkout", IssueType: "outdated", Severity: "medium", FilePath: ".github/workflows/deploy.yml"},
+					{Repository: "actions/setup-node", IssueType: "outdated", Severity: "low", FilePath: ".github/workflows/ci.yml"},
+				},
+			},
+			{
+				FullName: "my-org/web",
+				Environments: []workflow.EnvironmentUsage{
+					{FilePath: ".github/workflows/release.yml", Job: "publish", Environment: "production"},
+				},
+				Issues: []ActionIssue{
+					{Repository: "old/deploy-action", IssueType: "deprecated", Severity: "high", FilePath: ".github/workflows/release.yml"},
+				},
+			},
+			{
+				FullName: "my-org/docs",
+				Environments: []workflow.EnvironmentUsage{
+					{FilePath: ".github/workflows/pages.yml", Job: "deploy", Environment: "github-pages"},
+				},
+			},
+		},
+	}
+}
+
+func TestDeploymentInventory(t *testing.T) {
+	deployments := DeploymentInventory(environmentsResult())
+	if len(deployments) != 3 {
+		t.Fatalf("Expected 3 deploy workflows, got %d: %+v", len(deployments), deployments)
+	}
+
+	web := deployments[0]
+	if web.Repository != "my-org/web" || web.HighestSeverity != "high" || web.Issues != 1 {
+		t.Errorf("Expected the workflow with a high severity issue first, got %+v", web)
+	}
+
+	api := deployments[1]
+	if api.Repository != "my-org/api" || api.Jobs != 2 || api.Issues != 1 || api.HighestSeverity != "medium" {
+		t.Errorf("Expected issues outside the deploy workflow to be left out, got %+v", api)
+	}
+	if strings.Join(api.Environments, ",") != "production,staging" {
+		t.Errorf("Expected sorted environments, got %v", api.Environments)
+	}
+
+	docs := deployments[2]
+	if docs.Repository != "my-org/docs" || docs.Issues != 0 || docs.HighestSeverity != "" {
+		t.Errorf("Expected the workflow without issues last, got %+v", docs)
+	}
+}
+
+func TestCreateEnvironmentsCell(t *testing.T) {
+	source := strings.Join(createEnvironmentsCell(DeploymentInventory(environmentsResult())).Source, "")
+
+	for _, want := range []string{
+		"## 🚀 Deployment Environments",
+		"**3** workflows deploy to **3** environments; **2** of them have issues.",
+		"| my-org/web | `.github/workflows/release.yml` | production | 1 | 1 | high |",
+		"| my-org/api | `.github/workflows/deploy.yml` | production, staging | 2 | 1 | medium |",
+		"| my-org/docs | `.github/workflows/pages.yml` | github-pages | 1 | 0 | - |",
+	} {
+		if !strings.Contains(source, want) {
+			t.Errorf("Expected cell to contain %q, got:\n%s", want, source)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Gate outcomes of an exit summary
+const (
+	GatePassed   = "passed"
+	GateFailed   = "failed"
+	GateDisabled = "disabled" // No --fail-on threshold was given
+)
+
+// severities lists issue severities, most severe first
+var severities = []string{"critical", "high", "medium", "low"}
+
+// ExitSummary is the machine-readable outcome of a scan, for CI pipelines that branch on results
+// without parsing the full scan result
+type ExitSummary struct {
+	Repositories     int            `json:"repositories"`
+	TotalIssues      int            `json:"total_issues"`
+	NewIssues        int            `json:"new_issues"` // Issues not in the baseline scan
+	HighestSeverity  string         `json:"highest_severity,omitempty"`
+	IssuesBySeverity map[string]int `json:"issues_by_severity"`
+	IssuesByType     map[string]int `json:"issues_by_type"`
+	Gate             string         `json:"gate"`                       // GatePassed, GateFailed, or GateDisabled
+	FailOn           string         `json:"fail_on,omitempty"`          // --fail-on threshold
+	GatingIssues     int            `json:"gating_issues"`              // New issues at or above the threshold
+	BudgetExhausted  string         `json:"budget_exhausted,omitempty"` // Why the scan stopped early, if it did
+	Debt             *DebtSummary   `json:"debt,omitempty"`             // Actions debt score and its target
+	ExitCode         int            `json:"exit_code"`
+}
+
+// BuildExitSummary summarizes a finalized scan result, the --fail-on gate, and the exit code of the scan
+func BuildExitSummary(result *ScanResult, failOn, budgetExhausted string, exitCode int) ExitSummary {
+	summary := ExitSummary{
+		Repositories:     result.Summary.TotalRepositories,
+		IssuesBySeverity: make(map[string]int),
+		IssuesByType:     make(map[string]int),
+		Gate:             GateDisabled,
+		FailOn:           failOn,
+		BudgetExhausted:  budgetExhausted,
+		ExitCode:         exitCode,
+		Debt:             result.Summary.Debt,
+	}
+	for severity, count := range result.Summary.IssuesBySeverity {
+		summary.IssuesBySeverity[severity] = count
+		summary.TotalIssues += count
+	}
+	for issueType, count := range result.Summary.IssuesByType {
+		summary.IssuesByType[issueType] = count
+	}
+	summary.NewIssues = summary.TotalIssues - result.Summary.ExistingIssues
+
+	for _, severity := range severities {
+		if summary.IssuesBySeverity[severity] > 0 {
+			summary.HighestSeverity = severity
+			break
+		}
+	}
+
+	if failOn != "" {
+		summary.GatingIssues = len(GatingIssues(result, failOn))
+		summary.Gate = GatePassed
+		if summary.GatingIssues > 0 {
+			summary.Gate = GateFailed
+		}
+	}
+	return summary
+}
+
+// WriteExitSummary writes the summary as indented JSON
+func WriteExitSummary(w io.Writer, summary ExitSummary) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(summary); err != nil {
+		return fmt.Errorf("failed to write exit summary: %w", err)
+	}
+	return nil
+}
+
+// WriteGitHubOutputs writes the summary as step outputs in the GITHUB_OUTPUT file format, one
+// name=value line each: repositories, total-issues, new-issues, highest-severity, gate,
+// gating-issues, exit-code, a count per severity (critical, high, medium, low),
+// issues-by-type as compact JSON, and debt-score when the scan was scored
+func WriteGitHubOutputs(w io.Writer, summary ExitSummary) error {
+	byType, err := json.Marshal(summary.IssuesByType)
+	if err != nil {
+		return fmt.Errorf("failed to encode issues by type: %w", err)
+	}
+
+	outputs := [][2]string{
+		{"repositories", strconv.Itoa(summary.Repositories)},
+		{"total-issues", strconv.Itoa(summary.TotalIssues)},
+		{"new-issues", strconv.Itoa(summary.NewIssues)},
+		{"highest-severity", summary.HighestSeverity},
+		{"gate", summary.Gate},
+		{"gating-issues", strconv.Itoa(summary.GatingIssues)},
+		{"exit-code", strconv.Itoa(summary.ExitCode)},
+	}
+	for _, severity := range severities {
+		outputs = append(outputs, [2]string{severity, strconv.Itoa(summary.IssuesBySeverity[severity])})
+	}
+	outputs = append(outputs, [2]string{"issues-by-type", string(byType)})
+	if summary.Debt != nil {
+		outputs = append(outputs, [2]string{"debt-score", strconv.FormatFloat(summary.Debt.Score, 'f', -1, 64)})
+	}
+
+	var b strings.Builder
+	for _, output := range outputs {
+		fmt.Fprintf(&b, "%s=%s\n", output[0], output[1])
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write GitHub outputs: %w", err)
+	}
+	return nil
+}
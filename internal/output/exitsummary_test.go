@@ -0,0 +1,85 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func exitSummaryResult() *ScanResult {
+	return BuildScanResult("my-org", []RepositoryResult{{
+		Name:     "app",
+		FullName: "my-org/app",
+		Issues: []ActionIssue{
+			{Repository: "actions/checkout", IssueType: "outdated", Severity: "medium"},
+			{Repository: "actions/cache", IssueType: "outdated", Severity: "low", Existing: true},
+			{Repository: "evil/action", IssueType: "banned-action", Severity: "high"},
+		},
+	}})
+}
+
+func TestBuildExitSummary(t *testing.T) {
+	summary := BuildExitSummary(exitSummaryResult(), "high", "", 2)
+
+	if summary.Repositories != 1 || summary.TotalIssues != 3 || summary.NewIssues != 2 {
+		t.Errorf("Expected 1 repository with 3 issues, 2 new, got %+v", summary)
+	}
+	if summary.HighestSeverity != "high" || summary.IssuesBySeverity["medium"] != 1 || summary.IssuesByType["outdated"] != 2 {
+		t.Errorf("Unexpected counts %+v", summary)
+	}
+	if summary.Gate != GateFailed || summary.GatingIssues != 1 || summary.ExitCode != 2 {
+		t.Errorf("Expected the gate to fail on the new high issue, got %+v", summary)
+	}
+
+	if passed := BuildExitSummary(exitSummaryResult(), "critical", "", 0); passed.Gate != GatePassed || passed.GatingIssues != 0 {
+		t.Errorf("Expected the critical gate to pass, got %+v", passed)
+	}
+	if disabled := BuildExitSummary(exitSummaryResult(), "", "max duration 30m0s reached", 3); disabled.Gate != GateDisabled || disabled.BudgetExhausted == "" {
+		t.Errorf("Expected no gate and the budget reason, got %+v", disabled)
+	}
+}
+
+func TestWriteExitSummary(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteExitSummary(&buf, BuildExitSummary(exitSummaryResult(), "high", "", 2)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Expected JSON, got %v: %s", err, buf.String())
+	}
+	if decoded["gate"] != "failed" || decoded["exit_code"] != float64(2) || decoded["fail_on"] != "high" {
+		t.Errorf("Unexpected summary %s", buf.String())
+	}
+}
+
+func TestWriteGitHubOutputs(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteGitHubOutputs(&buf, BuildExitSummary(exitSummaryResult(), "", "", 0)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	outputs := make(map[string]string)
+	for _, line := range lines {
+		name, value, _ := strings.Cut(line, "=")
+		outputs[name] = value
+	}
+	expected := map[string]string{
+		"total-issues":     "3",
+		"new-issues":       "2",
+		"highest-severity": "high",
+		"gate":             "disabled",
+		"exit-code":        "0",
+		"critical":         "0",
+		"low":              "1",
+		"issues-by-type":   `{"banned-action":1,"outdated":2}`,
+	}
+	for name, value := range expected {
+		if outputs[name] != value {
+			t.Errorf("Expected %s=%s, got %q", name, value, outputs[name])
+		}
+	}
+}
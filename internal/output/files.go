@@ -0,0 +1,73 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/compress"
+)
+
+// CreateOutputFile creates a report file, expanding a leading "~" and creating missing parent directories
+// Both "/" and the platform separator are accepted, so Windows paths such as reports\scan.json work.
+func CreateOutputFile(path string) (*os.File, error) {
+	path, err := expandHome(path)
+	if err != nil {
+		return nil, err
+	}
+	path = filepath.Clean(filepath.FromSlash(path))
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("unable to create output directory: %w", err)
+		}
+	}
+
+	return os.Create(path)
+}
+
+// IsNotebookFile reports whether an output path selects the Jupyter notebook format
+func IsNotebookFile(path string) bool {
+	return hasExtension(path, ".ipynb")
+}
+
+// hasExtension reports whether a path ends in the extension, ignoring case and a compression
+// extension, so "scan.sarif.gz" is a SARIF file
+func hasExtension(path, extension string) bool {
+	path, _ = compress.TrimExtension(path)
+	return strings.EqualFold(filepath.Ext(path), extension)
+}
+
+// ParseOutputPaths splits a comma-separated --output value into paths, dropping duplicates
+// An empty value yields a single empty path, meaning JSON to stdout.
+func ParseOutputPaths(value string) []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, path := range strings.Split(value, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		paths = append(paths, path)
+	}
+	if len(paths) == 0 {
+		return []string{""}
+	}
+	return paths
+}
+
+// expandHome replaces a leading "~" with the user's home directory
+// Shells such as PowerShell and cmd.exe pass "~" through unexpanded.
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, `~\`) {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to expand %q: %w", path, err)
+	}
+	return filepath.Join(home, path[1:]), nil
+}
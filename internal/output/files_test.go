@@ -0,0 +1,93 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCreateOutputFile_CreatesParentDirectories(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reports", "2024", "scan.json")
+
+	file, err := CreateOutputFile(path)
+	if err != nil {
+		t.Fatalf("CreateOutputFile() returned error: %v", err)
+	}
+	file.Close()
+
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected output file to exist: %v", err)
+	}
+}
+
+func TestCreateOutputFile_ExpandsHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	file, err := CreateOutputFile("~/reports/scan.json")
+	if err != nil {
+		t.Fatalf("CreateOutputFile() returned error: %v", err)
+	}
+	file.Close()
+
+	if _, err := os.Stat(filepath.Join(home, "reports", "scan.json")); err != nil {
+		t.Errorf("Expected output file under home directory: %v", err)
+	}
+}
+
+func TestIsNotebookFile(t *testing.T) {
+	tests := map[string]bool{
+		"report.ipynb":              true,
+		`C:\Reports\Audit.IPYNB`:    true,
+		"results.json":              false,
+		"notebook.ipynb.json":       false,
+		"reports.ipynb/results.txt": false,
+	}
+
+	for path, expected := range tests {
+		if got := IsNotebookFile(path); got != expected {
+			t.Errorf("IsNotebookFile(%q) = %v, expected %v", path, got, expected)
+		}
+	}
+}
+
+func TestParseOutputPaths(t *testing.T) {
+	tests := map[string][]string{
+		"":                                     {""},
+		"scan.json":                            {"scan.json"},
+		"scan.json, report.ipynb,report.sarif": {"scan.json", "report.ipynb", "report.sarif"},
+		"scan.json,,scan.json":                 {"scan.json"},
+	}
+
+	for value, expected := range tests {
+		got := ParseOutputPaths(value)
+		if len(got) != len(expected) {
+			t.Errorf("ParseOutputPaths(%q) = %q, expected %q", value, got, expected)
+			continue
+		}
+		for i := range expected {
+			if got[i] != expected[i] {
+				t.Errorf("ParseOutputPaths(%q) = %q, expected %q", value, got, expected)
+				break
+			}
+		}
+	}
+}
+
+func TestIsSARIFFile(t *testing.T) {
+	tests := map[string]bool{
+		"report.sarif":      true,
+		"REPORT.SARIF":      true,
+		"report.sarif.json": false,
+		"report.json":       false,
+		"report.sarif.gz":   true,
+		"report.json.zst":   false,
+	}
+
+	for path, expected := range tests {
+		if got := IsSARIFFile(path); got != expected {
+			t.Errorf("IsSARIFFile(%q) = %v, expected %v", path, got, expected)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package output
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// Pinning styles of an action reference
+const (
+	PinStyleSHA      = "sha"       // Full or abbreviated commit SHA
+	PinStyleMajorTag = "major-tag" // Floating major tag such as "v4"
+	PinStyleExactTag = "exact-tag" // Minor or patch tag such as "v4.1" or "v4.1.2"
+	PinStyleBranch   = "branch"    // Branch or any other named ref
+)
+
+// pinStyles lists the pinning styles in display order
+var pinStyles = []string{PinStyleSHA, PinStyleExactTag, PinStyleMajorTag, PinStyleBranch}
+
+// maxSeverityHistory caps how many previous scans are carried forward in the severity history
+const maxSeverityHistory = 12
+
+// PinningSummary counts action references by pinning style
+type PinningSummary struct {
+	Total  int            `json:"total"`
+	Styles map[string]int `json:"styles"`
+}
+
+// Percent returns the share of references using a pinning style, from 0 to 100
+func (p *PinningSummary) Percent(style string) float64 {
+	if p == nil || p.Total == 0 {
+		return 0
+	}
+	return float64(p.Styles[style]) / float64(p.Total) * 100
+}
+
+// FreshnessSummary describes how far outdated references lag behind the suggested version
+type FreshnessSummary struct {
+	Measured                  int     `json:"measured"` // Outdated or deprecated issues with a comparable major version
+	MedianMajorVersionsBehind float64 `json:"median_major_versions_behind"`
+	MaxMajorVersionsBehind    int     `json:"max_major_versions_behind"`
+}
+
+// SeveritySnapshot records the issue counts by severity of one scan
+type SeveritySnapshot struct {
+	ScanTime         time.Time          `json:"scan_time"`
+	IssuesBySeverity map[string]int     `json:"issues_by_severity"`
+	DebtScore        float64            `json:"debt_score,omitempty"`    // Actions debt score of the scan
+	MergeLatency     []TeamMergeLatency `json:"merge_latency,omitempty"` // Time-to-merge per team as of the scan
+}
+
+// TeamMergeLatency measures how quickly a team merges the pull requests actions-maintainer opens
+type TeamMergeLatency struct {
+	Team               string  `json:"team"`
+	PullRequests       int     `json:"pull_requests"`
+	Merged             int     `json:"merged"`
+	Open               int     `json:"open"`
+	Closed             int     `json:"closed,omitempty"`      // Closed without merging
+	MedianHoursToMerge float64 `json:"median_hours_to_merge"` // From opening to merging, over merged pull requests
+}
+
+// PinStyle classifies a version reference by how it is pinned
+func PinStyle(version string) string {
+	if len(version) >= 7 && len(version) <= 40 && !strings.HasPrefix(version, "v") && isHex(version) {
+		return PinStyleSHA
+	}
+
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	for _, part := range parts {
+		if part == "" || strings.Trim(part, "0123456789") != "" {
+			return PinStyleBranch
+		}
+	}
+	if len(parts) == 1 {
+		return PinStyleMajorTag
+	}
+	return PinStyleExactTag
+}
+
+// isHex reports whether a string contains only hexadecimal characters
+func isHex(s string) bool {
+	for _, char := range s {
+		if !((char >= '0' && char <= '9') || (char >= 'a' && char <= 'f') || (char >= 'A' && char <= 'F')) {
+			return false
+		}
+	}
+	return true
+}
+
+// majorVersion returns the major version of a tag such as "v4.1.2", or false if it has none
+func majorVersion(version string) (int, bool) {
+	major := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 2)[0]
+	value, err := strconv.Atoi(major)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// MajorVersionsBehind returns how many major versions an issue's current version lags its suggestion
+// SHA pins are compared using their version comments. It returns false when either version has no major number.
+func MajorVersionsBehind(issue ActionIssue) (int, bool) {
+	current, suggested := issue.CurrentVersion, issue.SuggestedVersion
+	if PinStyle(current) == PinStyleSHA {
+		current, suggested = issue.PinComment, issue.SuggestedPinComment
+	}
+
+	currentMajor, ok := majorVersion(current)
+	if !ok {
+		return 0, false
+	}
+	suggestedMajor, ok := majorVersion(suggested)
+	if !ok || suggestedMajor < currentMajor {
+		return 0, false
+	}
+	return suggestedMajor - currentMajor, true
+}
+
+// countPinning adds the pinning styles of versioned action references to a pinning summary
+func countPinning(pinning *PinningSummary, actions []workflow.ActionReference) {
+	for _, action := range actions {
+		if action.Version == "" {
+			continue // Local actions have no version
+		}
+		pinning.Total++
+		pinning.Styles[PinStyle(action.Version)]++
+	}
+}
+
+// calculateFreshness measures major versions behind across outdated and deprecated issues
+func calculateFreshness(issues []ActionIssue) *FreshnessSummary {
+	var behind []int
+	for _, issue := range issues {
+		if issue.IssueType != "outdated" && issue.IssueType != "deprecated" {
+			continue
+		}
+		if versions, ok := MajorVersionsBehind(issue); ok {
+			behind = append(behind, versions)
+		}
+	}
+	if len(behind) == 0 {
+		return nil
+	}
+
+	sort.Ints(behind)
+	freshness := &FreshnessSummary{
+		Measured:               len(behind),
+		MaxMajorVersionsBehind: behind[len(behind)-1],
+	}
+	middle := len(behind) / 2
+	if len(behind)%2 == 0 {
+		freshness.MedianMajorVersionsBehind = float64(behind[middle-1]+behind[middle]) / 2
+	} else {
+		freshness.MedianMajorVersionsBehind = float64(behind[middle])
+	}
+	return freshness
+}
+
+// SeverityHistoryFrom carries a previous scan's severity history forward, adding that scan's own counts
+// The oldest entries are dropped beyond maxSeverityHistory.
+func SeverityHistoryFrom(previous *ScanResult) []SeveritySnapshot {
+	if previous == nil {
+		return nil
+	}
+
+	history := append([]SeveritySnapshot(nil), previous.Summary.SeverityHistory...)
+	snapshot := SeveritySnapshot{
+		ScanTime:         previous.ScanTime,
+		IssuesBySeverity: previous.Summary.IssuesBySeverity,
+		MergeLatency:     previous.Summary.MergeLatency,
+	}
+	if previous.Summary.Debt != nil {
+		snapshot.DebtScore = previous.Summary.Debt.Score
+	}
+	history = append(history, snapshot)
+	if len(history) > maxSeverityHistory {
+		history = history[len(history)-maxSeverityHistory:]
+	}
+	return history
+}
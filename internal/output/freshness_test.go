@@ -0,0 +1,185 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+func TestPinStyle(t *testing.T) {
+	tests := map[string]string{
+		"8e5e7e5ab8b370d6c329ec480221332ada57f0ab": PinStyleSHA,
+		"8e5e7e5":  PinStyleSHA,
+		"v4":       PinStyleMajorTag,
+		"4":        PinStyleMajorTag,
+		"v4.1":     PinStyleExactTag,
+		"v4.1.2":   PinStyleExactTag,
+		"main":     PinStyleBranch,
+		"release":  PinStyleBranch,
+		"v4-beta":  PinStyleBranch,
+		"deadbeef": PinStyleSHA,
+	}
+
+	for version, expected := range tests {
+		if style := PinStyle(version); style != expected {
+			t.Errorf("PinStyle(%q) = %q, expected %q", version, style, expected)
+		}
+	}
+}
+
+func TestMajorVersionsBehind(t *testing.T) {
+	tests := []struct {
+		issue    ActionIssue
+		expected int
+		ok       bool
+	}{
+		{ActionIssue{CurrentVersion: "v2", SuggestedVersion: "v4"}, 2, true},
+		{ActionIssue{CurrentVersion: "v4.0.1", SuggestedVersion: "v4.2.0"}, 0, true},
+		{ActionIssue{CurrentVersion: "8e5e7e5ab8b370d6c329ec480221332ada57f0ab", SuggestedVersion: "11bd71901bbe5b1630ceea73d27597364c9af683", PinComment: "v3.1.0", SuggestedPinComment: "v4.2.2"}, 1, true},
+		{ActionIssue{CurrentVersion: "8e5e7e5ab8b370d6c329ec480221332ada57f0ab", SuggestedVersion: "11bd71901bbe5b1630ceea73d27597364c9af683"}, 0, false},
+		{ActionIssue{CurrentVersion: "main", SuggestedVersion: "v4"}, 0, false},
+		{ActionIssue{CurrentVersion: "v5", SuggestedVersion: "v4"}, 0, false},
+	}
+
+	for _, test := range tests {
+		behind, ok := MajorVersionsBehind(test.issue)
+		if behind != test.expected || ok != test.ok {
+			t.Errorf("MajorVersionsBehind(%s -> %s) = (%d, %v), expected (%d, %v)", test.issue.CurrentVersion, test.issue.SuggestedVersion, behind, ok, test.expected, test.ok)
+		}
+	}
+}
+
+func TestCalculateSummary_PinningAndFreshness(t *testing.T) {
+	repositories := []RepositoryResult{
+		{
+			Name:     "api",
+			FullName: "my-org/api",
+			Actions: []workflow.ActionReference{
+				{Repository: "actions/checkout", Version: "8e5e7e5ab8b370d6c329ec480221332ada57f0ab"},
+				{Repository: "actions/cache", Version: "v3"},
+				{Repository: "actions/setup-go", Version: "v5.0.1"},
+				{Repository: "my-org/deploy", Version: "main"},
+				{Repository: "./local-action"},
+			},
+			Issues: []ActionIssue{
+				{Repository: "actions/cache", CurrentVersion: "v1", SuggestedVersion: "v4", IssueType: "outdated", Severity: "high"},
+				{Repository: "actions/cache", CurrentVersion: "v3", SuggestedVersion: "v4", IssueType: "outdated", Severity: "medium"},
+				{Repository: "actions/setup-go", CurrentVersion: "v3", SuggestedVersion: "v5", IssueType: "deprecated", Severity: "high"},
+				{Repository: "actions/setup-go", CurrentVersion: "v4", SuggestedVersion: "v5", IssueType: "outdated", Severity: "low"},
+				{Repository: "workflow", IssueType: "risky-trigger", Severity: "high"},
+			},
+		},
+	}
+
+	summary := calculateSummary(repositories)
+
+	if summary.Pinning == nil || summary.Pinning.Total != 4 {
+		t.Fatalf("Expected 4 versioned references, got %+v", summary.Pinning)
+	}
+	for style, expected := range map[string]float64{PinStyleSHA: 25, PinStyleMajorTag: 25, PinStyleExactTag: 25, PinStyleBranch: 25} {
+		if percent := summary.Pinning.Percent(style); percent != expected {
+			t.Errorf("Expected %s to be %.0f%%, got %.1f%%", style, expected, percent)
+		}
+	}
+
+	// Versions behind: 3, 1, 2, 1 -> median 1.5
+	if summary.Freshness == nil {
+		t.Fatal("Expected freshness statistics")
+	}
+	if summary.Freshness.Measured != 4 || summary.Freshness.MedianMajorVersionsBehind != 1.5 || summary.Freshness.MaxMajorVersionsBehind != 3 {
+		t.Errorf("Unexpected freshness: %+v", summary.Freshness)
+	}
+
+	if empty := calculateSummary(nil); empty.Pinning != nil || empty.Freshness != nil {
+		t.Errorf("Expected no pinning or freshness without actions, got %+v %+v", empty.Pinning, empty.Freshness)
+	}
+}
+
+func TestSeverityHistoryFrom(t *testing.T) {
+	if history := SeverityHistoryFrom(nil); history != nil {
+		t.Errorf("Expected no history without a previous scan, got %v", history)
+	}
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	previous := &ScanResult{ScanTime: start, Summary: Summary{IssuesBySeverity: map[string]int{"high": 3}}}
+	for i := 0; i < maxSeverityHistory+3; i++ {
+		history := SeverityHistoryFrom(previous)
+		previous = &ScanResult{
+			ScanTime: start.AddDate(0, 0, i+1),
+			Summary:  Summary{IssuesBySeverity: map[string]int{"high": i}, SeverityHistory: history},
+		}
+	}
+
+	history := SeverityHistoryFrom(previous)
+	if len(history) != maxSeverityHistory {
+		t.Fatalf("Expected history capped at %d, got %d", maxSeverityHistory, len(history))
+	}
+	if last := history[len(history)-1]; !last.ScanTime.Equal(previous.ScanTime) {
+		t.Errorf("Expected the previous scan last, got %v", last.ScanTime)
+	}
+}
+
+func TestCreateHeaderCell_PinningAndFreshness(t *testing.T) {
+	result := &ScanResult{
+		ScanTime: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		Summary: Summary{
+			IssuesBySeverity: map[string]int{"high": 2},
+			Pinning:          &PinningSummary{Total: 4, Styles: map[string]int{PinStyleSHA: 3, PinStyleMajorTag: 1}},
+			Freshness:        &FreshnessSummary{Measured: 2, MedianMajorVersionsBehind: 1.5, MaxMajorVersionsBehind: 2},
+			SeverityHistory: []SeveritySnapshot{
+				{ScanTime: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), IssuesBySeverity: map[string]int{"high": 5, "low": 1}},
+			},
+		},
+	}
+
+	header := strings.Join(createHeaderCell(result).Source, "")
+	for _, expected := range []string{
+		"**75%** of references pinned to a commit SHA, **0%** to an exact tag, **25%** to a major tag, **0%** to a branch",
+		"median of **1.5** major versions behind (max **2**, across 2 references)",
+	} {
+		if !strings.Contains(header, expected) {
+			t.Errorf("Expected header to contain %q, got:\n%s", expected, header)
+		}
+	}
+
+	summary := strings.Join(createSummaryCell(result).Source, "")
+	for _, expected := range []string{"### Severity Trend", "| 2026-02-01 | 0 | 5 | 0 | 1 |", "| 2026-03-01 (this scan) | 0 | 2 | 0 | 0 |"} {
+		if !strings.Contains(summary, expected) {
+			t.Errorf("Expected summary to contain %q, got:\n%s", expected, summary)
+		}
+	}
+}
+
+func TestCreateSummaryCell_MergeLatency(t *testing.T) {
+	result := &ScanResult{
+		ScanTime: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		Summary: Summary{
+			IssuesBySeverity: map[string]int{"high": 2},
+			MergeLatency: []TeamMergeLatency{
+				{Team: "payments", PullRequests: 4, Merged: 3, Open: 1, MedianHoursToMerge: 30.5},
+				{Team: "platform", PullRequests: 2, Open: 2},
+			},
+		},
+	}
+	previous := &ScanResult{
+		ScanTime: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		Summary:  Summary{MergeLatency: []TeamMergeLatency{{Team: "payments", PullRequests: 2, Merged: 2, MedianHoursToMerge: 12}}},
+	}
+	result.Summary.SeverityHistory = SeverityHistoryFrom(previous)
+	if len(result.Summary.SeverityHistory[0].MergeLatency) != 1 {
+		t.Fatalf("Expected the previous scan's merge latency in the history, got %+v", result.Summary.SeverityHistory)
+	}
+
+	summary := strings.Join(createSummaryCell(result).Source, "")
+	for _, expected := range []string{
+		"### Merge Latency by Team",
+		"| payments | 4 | 3 | 1 | 30.5 h | 12.0 h |",
+		"| platform | 2 | 0 | 2 | n/a | n/a |",
+	} {
+		if !strings.Contains(summary, expected) {
+			t.Errorf("Expected summary to contain %q, got:\n%s", expected, summary)
+		}
+	}
+}
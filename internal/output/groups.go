@@ -0,0 +1,85 @@
+package output
+
+import "sort"
+
+// IssueGroup aggregates identical issues in one repository that differ only by the file they were found in
+type IssueGroup struct {
+	Repository       string   `json:"repository"`
+	WorkflowPath     string   `json:"workflow_path,omitempty"`
+	CurrentVersion   string   `json:"current_version"`
+	SuggestedVersion string   `json:"suggested_version,omitempty"`
+	MigrationTarget  string   `json:"migration_target,omitempty"`
+	IssueType        string   `json:"issue_type"`
+	Severity         string   `json:"severity"`
+	Description      string   `json:"description"`
+	Files            []string `json:"files"` // Affected workflow files, sorted
+	Count            int      `json:"count"` // Number of detailed issues in the group
+	Existing         bool     `json:"existing,omitempty"`
+}
+
+// issueGroupKey identifies issues that are the same finding in different places
+type issueGroupKey struct {
+	repository, workflowPath, currentVersion, suggestedVersion, migrationTarget, issueType string
+}
+
+// GroupIssues merges issues with the same action, version, and type, keeping the order of first occurrence
+// Workflow-level findings without a version are also keyed by description so distinct findings stay separate.
+func GroupIssues(issues []ActionIssue) []IssueGroup {
+	var groups []IssueGroup
+	positions := make(map[issueGroupKey]int)
+	files := make(map[issueGroupKey]map[string]bool)
+
+	for _, issue := range issues {
+		key := issueGroupKey{
+			repository:       issue.Repository,
+			workflowPath:     issue.WorkflowPath,
+			currentVersion:   issue.CurrentVersion,
+			suggestedVersion: issue.SuggestedVersion,
+			migrationTarget:  issue.MigrationTarget,
+			issueType:        issue.IssueType,
+		}
+		if issue.CurrentVersion == "" && issue.SuggestedVersion == "" {
+			key.repository += "|" + issue.Description
+		}
+
+		position, exists := positions[key]
+		if !exists {
+			position = len(groups)
+			positions[key] = position
+			files[key] = make(map[string]bool)
+			groups = append(groups, IssueGroup{
+				Repository:       issue.Repository,
+				WorkflowPath:     issue.WorkflowPath,
+				CurrentVersion:   issue.CurrentVersion,
+				SuggestedVersion: issue.SuggestedVersion,
+				MigrationTarget:  issue.MigrationTarget,
+				IssueType:        issue.IssueType,
+				Severity:         issue.Severity,
+				Description:      issue.Description,
+				Existing:         true,
+			})
+		}
+
+		group := &groups[position]
+		group.Count++
+		// A group is only existing when every issue in it was already in the baseline
+		group.Existing = group.Existing && issue.Existing
+		if !files[key][issue.FilePath] {
+			files[key][issue.FilePath] = true
+			group.Files = append(group.Files, issue.FilePath)
+		}
+	}
+
+	for i := range groups {
+		sort.Strings(groups[i].Files)
+	}
+
+	return groups
+}
+
+// GroupRepositoryIssues fills IssueGroups for every repository, leaving the detailed issues in place for PR planning
+func (r *ScanResult) GroupRepositoryIssues() {
+	for i := range r.Repositories {
+		r.Repositories[i].IssueGroups = GroupIssues(r.Repositories[i].Issues)
+	}
+}
@@ -0,0 +1,81 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGroupIssues(t *testing.T) {
+	checkout := func(filePath string, existing bool) ActionIssue {
+		return ActionIssue{
+			Repository:       "actions/checkout",
+			CurrentVersion:   "v3",
+			SuggestedVersion: "v4",
+			IssueType:        "outdated",
+			Severity:         "medium",
+			FilePath:         filePath,
+			Existing:         existing,
+		}
+	}
+
+	issues := []ActionIssue{
+		checkout(".github/workflows/test.yml", true),
+		checkout(".github/workflows/build.yml", false),
+		checkout(".github/workflows/build.yml", true), // Second job in the same file
+		{Repository: "actions/checkout", CurrentVersion: "v3", SuggestedVersion: "v4", IssueType: "deprecated", FilePath: ".github/workflows/build.yml"},
+		{Repository: "actions/checkout", CurrentVersion: "v2", SuggestedVersion: "v4", IssueType: "outdated", FilePath: ".github/workflows/build.yml"},
+		{Repository: "workflow", IssueType: "risky-trigger", Description: "pull_request_target", FilePath: ".github/workflows/a.yml"},
+		{Repository: "workflow", IssueType: "risky-trigger", Description: "workflow_run", FilePath: ".github/workflows/b.yml"},
+	}
+
+	groups := GroupIssues(issues)
+	if len(groups) != 5 {
+		t.Fatalf("Expected 5 groups, got %d: %+v", len(groups), groups)
+	}
+
+	first := groups[0]
+	if first.Count != 3 || strings.Join(first.Files, ",") != ".github/workflows/build.yml,.github/workflows/test.yml" {
+		t.Errorf("Unexpected first group: %+v", first)
+	}
+	if first.Existing {
+		t.Error("Expected a group with a new issue not to be existing")
+	}
+	if groups[1].IssueType != "deprecated" || groups[2].CurrentVersion != "v2" {
+		t.Errorf("Expected groups in first-occurrence order, got %+v", groups)
+	}
+	if groups[3].Description != "pull_request_target" || groups[4].Description != "workflow_run" {
+		t.Errorf("Expected distinct workflow findings to stay separate, got %+v", groups[3:])
+	}
+
+	if groups := GroupIssues(nil); len(groups) != 0 {
+		t.Errorf("Expected no groups, got %d", len(groups))
+	}
+}
+
+func TestGroupRepositoryIssues(t *testing.T) {
+	var issues []ActionIssue
+	for _, file := range []string{"a.yml", "b.yml", "c.yml", "d.yml", "e.yml", "f.yml", "g.yml"} {
+		issues = append(issues, ActionIssue{Repository: "actions/cache", CurrentVersion: "v3", SuggestedVersion: "v4", IssueType: "outdated", FilePath: file})
+	}
+
+	result := &ScanResult{
+		Repositories: []RepositoryResult{{Name: "api", FullName: "my-org/api", Issues: issues}},
+	}
+	result.GroupRepositoryIssues()
+
+	repo := result.Repositories[0]
+	if len(repo.Issues) != 7 {
+		t.Errorf("Expected detailed issues to be kept, got %d", len(repo.Issues))
+	}
+	if len(repo.IssueGroups) != 1 || repo.IssueGroups[0].Count != 7 {
+		t.Fatalf("Expected one group of 7 issues, got %+v", repo.IssueGroups)
+	}
+
+	cell := strings.Join(createRepositoryDetailsCell(result).Source, "")
+	if !strings.Contains(cell, "- **actions/cache**: v3 → v4 (outdated) — 7 files: `a.yml`, `b.yml`, `c.yml`, `d.yml`, `e.yml` and 2 more") {
+		t.Errorf("Expected a grouped issue line, got:\n%s", cell)
+	}
+	if strings.Contains(cell, "**File:**") {
+		t.Errorf("Expected grouped output instead of per-file listings, got:\n%s", cell)
+	}
+}
@@ -0,0 +1,69 @@
+package output
+
+import (
+	"sort"
+	"time"
+)
+
+// ImageUsage is a container image reference and where it is used across repositories
+type ImageUsage struct {
+	Image        string     // Reference as written, e.g. "postgres:16"
+	Kinds        []string   // workflow.ImageKindContainer, workflow.ImageKindService, and/or workflow.ImageKindStep
+	Repositories []string   // Repositories using the image, sorted
+	Uses         int        // Jobs and services using the image
+	Created      *time.Time // Image creation time, when checked against the registry
+	Missing      bool       // The registry has no such tag or digest
+}
+
+// ImageInventory groups the container images of a scan by reference, most widely used first
+func ImageInventory(result *ScanResult) []ImageUsage {
+	type imageSets struct {
+		usage        *ImageUsage
+		kinds        map[string]bool
+		repositories map[string]bool
+	}
+
+	byImage := make(map[string]*imageSets)
+	var order []string
+	for _, repo := range result.Repositories {
+		for _, image := range repo.ContainerImages {
+			sets, ok := byImage[image.Image]
+			if !ok {
+				sets = &imageSets{
+					usage:        &ImageUsage{Image: image.Image},
+					kinds:        make(map[string]bool),
+					repositories: make(map[string]bool),
+				}
+				byImage[image.Image] = sets
+				order = append(order, image.Image)
+			}
+			sets.kinds[image.Kind] = true
+			sets.repositories[repo.FullName] = true
+			sets.usage.Uses++
+			if image.Created != nil {
+				sets.usage.Created = image.Created
+			}
+			sets.usage.Missing = sets.usage.Missing || image.Missing
+		}
+	}
+
+	images := make([]ImageUsage, 0, len(order))
+	for _, name := range order {
+		sets := byImage[name]
+		usage := sets.usage
+		usage.Kinds = sortedKeys(sets.kinds)
+		usage.Repositories = sortedKeys(sets.repositories)
+		images = append(images, *usage)
+	}
+
+	sort.SliceStable(images, func(i, j int) bool {
+		if len(images[i].Repositories) != len(images[j].Repositories) {
+			return len(images[i].Repositories) > len(images[j].Repositories)
+		}
+		if images[i].Uses != images[j].Uses {
+			return images[i].Uses > images[j].Uses
+		}
+		return images[i].Image < images[j].Image
+	})
+	return images
+}
@@ -0,0 +1,79 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+func imagesResult() *ScanResult {
+	built := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	return &ScanResult{
+		Owner: "my-org",
+		Repositories: []RepositoryResult{
+			{
+				FullName: "my-org/api",
+				ContainerImages: []workflow.ContainerImage{
+					{FilePath: ".github/workflows/ci.yml", Job: "test", Kind: workflow.ImageKindService, Service: "postgres", Image: "postgres:16", Registry: "docker.io", Repository: "library/postgres", Tag: "16", Created: &built},
+					{FilePath: ".github/workflows/ci.yml", Job: "build", Kind: workflow.ImageKindContainer, Image: "ghcr.io/my-org/builder:v2", Registry: "ghcr.io", Repository: "my-org/builder", Tag: "v2"},
+				},
+			},
+			{
+				FullName: "my-org/web",
+				ContainerImages: []workflow.ContainerImage{
+					{FilePath: ".github/workflows/ci.yml", Job: "e2e", Kind: workflow.ImageKindContainer, Image: "postgres:16", Registry: "docker.io", Repository: "library/postgres", Tag: "16", Created: &built},
+					{FilePath: ".github/workflows/ci.yml", Job: "e2e", Kind: workflow.ImageKindService, Service: "mysql", Image: "mysql:5.5", Registry: "docker.io", Repository: "library/mysql", Tag: "5.5", Missing: true},
+				},
+			},
+		},
+	}
+}
+
+func TestImageInventory(t *testing.T) {
+	images := ImageInventory(imagesResult())
+	if len(images) != 3 {
+		t.Fatalf("Expected 3 images, got %d", len(images))
+	}
+
+	postgres := images[0]
+	if postgres.Image != "postgres:16" || postgres.Uses != 2 || len(postgres.Repositories) != 2 {
+		t.Errorf("Expected postgres:16 used twice in 2 repositories first, got %+v", postgres)
+	}
+	if strings.Join(postgres.Kinds, ",") != "container,service" || postgres.Created == nil {
+		t.Errorf("Expected both kinds and the build date, got %+v", postgres)
+	}
+	if images[1].Image != "ghcr.io/my-org/builder:v2" || images[2].Image != "mysql:5.5" || !images[2].Missing {
+		t.Errorf("Unexpected order or state: %+v", images[1:])
+	}
+}
+
+func TestCreateContainerImagesCell(t *testing.T) {
+	source := strings.Join(createContainerImagesCell(ImageInventory(imagesResult())).Source, "")
+
+	for _, want := range []string{
+		"## 🐳 Container Images",
+		"**3** images are used by job containers, service containers, and docker:// steps. **2** were checked against their registries; **1** no longer exist.",
+		"| `postgres:16` | container, service | 2 | 2026-09-01 | ✅ Found |",
+		"| `ghcr.io/my-org/builder:v2` | container | 1 | - | - |",
+		"| `mysql:5.5` | service | 1 | - | ❌ Missing |",
+	} {
+		if !strings.Contains(source, want) {
+			t.Errorf("Expected section to contain %q, got:\n%s", want, source)
+		}
+	}
+}
+
+func TestRedact_ContainerImages(t *testing.T) {
+	result := imagesResult()
+	result.Redact([]byte("test-key"))
+
+	internal := result.Repositories[0].ContainerImages[1]
+	if strings.Contains(internal.Image, "my-org") || strings.Contains(internal.Repository, "my-org") || internal.FilePath == ".github/workflows/ci.yml" {
+		t.Errorf("Expected internal image and path to be redacted, got %+v", internal)
+	}
+	if public := result.Repositories[0].ContainerImages[0]; public.Image != "postgres:16" {
+		t.Errorf("Expected public image to be kept, got %+v", public)
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
+	"sort"
 	"time"
 
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/patcher"
 	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
 )
 
@@ -18,17 +21,111 @@ type ScanResult struct {
 	Repositories []RepositoryResult `json:"repositories"`
 	Summary      Summary            `json:"summary"`
 	CreatedPRs   []CreatedPR        `json:"created_prs,omitempty"`
+
+	// Org-level analysis: job step sequences repeated across repositories (scan --detect-duplicates)
+	ReusableWorkflowCandidates []DuplicateStepCluster `json:"reusable_workflow_candidates,omitempty"`
+
+	// Org-level analysis: widely used internal actions with movable tags (scan --check-tag-protection)
+	TagProtectionFindings []TagProtectionFinding `json:"tag_protection_findings,omitempty"`
+
+	// Producer-side analysis: internal actions whose major tag lags their newest release (scan --check-major-tags)
+	MajorTagFindings []MajorTagFinding `json:"major_tag_findings,omitempty"`
+
+	// Org-level analysis: who consumes each internal action and how far behind they are (scan --internal-actions)
+	InternalActions []InternalAction `json:"internal_actions,omitempty"`
+}
+
+// InternalAction is a maintainer-facing view of an action hosted by a scanned owner: the repositories
+// consuming it, the versions they use, and the state of the action repository
+type InternalAction struct {
+	Repository        string              `json:"repository"`
+	Consumers         []string            `json:"consumers"`                     // Scanned repositories using the action, sorted
+	Uses              int                 `json:"uses"`                          // References to the action across consumers
+	Versions          map[string]int      `json:"versions"`                      // Consumers by version; SHA pins are read by their pin comment
+	VersionConsumers  map[string][]string `json:"version_consumers,omitempty"`   // Consumers of each version, sorted
+	StaleConsumers    []string            `json:"stale_consumers,omitempty"`     // Consumers on an older major version than the latest release, sorted
+	OpenIssues        int                 `json:"open_issues"`                   // Open issues on the action repository, not counting pull requests
+	OpenPullRequests  int                 `json:"open_pull_requests"`            // Open pull requests on the action repository
+	LatestRelease     string              `json:"latest_release,omitempty"`      // Tag of the latest release
+	LatestReleaseDate *time.Time          `json:"latest_release_date,omitempty"` // When the latest release was published
+	Archived          bool                `json:"archived,omitempty"`
+}
+
+// TagProtectionFinding is a governance finding for an internal action used by many repositories
+// whose consumed release tags are not protected from being moved or deleted
+type TagProtectionFinding struct {
+	Repository      string   `json:"repository"`       // Internal action repository
+	Consumers       []string `json:"consumers"`        // Scanned repositories using the action, sorted
+	UnprotectedTags []string `json:"unprotected_tags"` // Consumed tags an active ruleset does not protect
+	Severity        string   `json:"severity"`
+	Description     string   `json:"description"`
+}
+
+// MajorTagFinding is a producer-side finding for an internal action whose floating major tag, such as
+// v2, was not moved to its newest release, so consumers pinned to the major tag miss its fixes
+type MajorTagFinding struct {
+	Repository    string   `json:"repository"`          // Internal action repository
+	MajorTag      string   `json:"major_tag"`           // e.g. v2
+	PointsAt      string   `json:"points_at,omitempty"` // Release the major tag points at; empty when it points at no release
+	LatestRelease string   `json:"latest_release"`      // Newest release of the major version, e.g. v2.4.1
+	Consumers     []string `json:"consumers,omitempty"` // Scanned repositories pinned to the major tag, sorted
+	Severity      string   `json:"severity"`
+	Description   string   `json:"description"`
+}
+
+// DuplicateStepCluster is a job step sequence repeated across repositories,
+// recommended for extraction into a shared reusable workflow
+type DuplicateStepCluster struct {
+	Fingerprint  string           `json:"fingerprint"`  // Short hash of the normalized steps
+	Steps        []string         `json:"steps"`        // Normalized steps shared by every occurrence
+	Repositories []string         `json:"repositories"` // Affected repositories, sorted
+	Occurrences  []StepOccurrence `json:"occurrences"`  // Every job containing the sequence
+}
+
+// StepOccurrence identifies a job containing a duplicated step sequence
+type StepOccurrence struct {
+	Repository string `json:"repository"`
+	FilePath   string `json:"file_path"`
+	Job        string `json:"job"`
 }
 
 // RepositoryResult represents the scan result for a single repository
 type RepositoryResult struct {
-	Name             string                     `json:"name"`
-	FullName         string                     `json:"full_name"`
-	DefaultBranch    string                     `json:"default_branch"`
-	WorkflowFiles    []WorkflowFileResult       `json:"workflow_files"`
-	Actions          []workflow.ActionReference `json:"actions"`
-	Issues           []ActionIssue              `json:"issues,omitempty"`
-	CustomProperties map[string]string          `json:"custom_properties,omitempty"`
+	Name             string                      `json:"name"`
+	FullName         string                      `json:"full_name"`
+	Host             string                      `json:"host,omitempty"` // GitHub host of the repository, set for GitHub Enterprise Server and multi-host runs
+	DefaultBranch    string                      `json:"default_branch"`
+	WorkflowFiles    []WorkflowFileResult        `json:"workflow_files"`
+	Actions          []workflow.ActionReference  `json:"actions"`
+	Issues           []ActionIssue               `json:"issues,omitempty"`
+	IssueGroups      []IssueGroup                `json:"issue_groups,omitempty"` // Identical issues merged across files (report --group-issues)
+	SuppressedIssues []SuppressedIssue           `json:"suppressed_issues,omitempty"`
+	CustomProperties map[string]string           `json:"custom_properties,omitempty"`
+	Topics           []string                    `json:"topics,omitempty"`
+	Language         string                      `json:"language,omitempty"`         // Primary language detected by GitHub
+	Triggers         []workflow.TriggerInfo      `json:"triggers,omitempty"`         // Trigger inventory per workflow file
+	SecretFlows      []workflow.SecretFlow       `json:"secret_flows,omitempty"`     // Secrets and variables passed to actions (scan --map-secrets)
+	ContainerImages  []workflow.ContainerImage   `json:"container_images,omitempty"` // Job container and service images
+	ToolSetups       []workflow.ToolSetup        `json:"tool_setups,omitempty"`      // Language toolchains set up by jobs
+	Environments     []workflow.EnvironmentUsage `json:"environments,omitempty"`     // Jobs deploying to environments
+	Logs             []string                    `json:"logs,omitempty"`             // Log lines recorded while scanning (scan --capture-logs)
+	Decisions        []Decision                  `json:"decisions,omitempty"`        // Why each action reference was or was not reported (scan --explain-all)
+	Status           string                      `json:"status,omitempty"`           // Set when the repository's workflow files were not scanned
+	Error            string                      `json:"error,omitempty"`            // Why the repository failed (status "failed")
+}
+
+// Repository statuses recorded when a repository's workflow files were not scanned
+const (
+	RepositoryStatusEmpty       = "empty"        // The repository has no commits
+	RepositoryStatusSubmodule   = "submodule"    // .github or a workflow directory is a git submodule
+	RepositoryStatusNoWorkflows = "no-workflows" // The workflow directories hold no workflow files
+	RepositoryStatusOverBudget  = "over-budget"  // The scan budget (--max-duration, --max-api-calls) ran out first
+	RepositoryStatusFailed      = "failed"       // The workflow files could not be fetched
+)
+
+// Scanned reports whether the repository's workflows were scanned
+func (r RepositoryResult) Scanned() bool {
+	return r.Status == ""
 }
 
 // WorkflowFileResult represents a workflow file scan result
@@ -36,14 +133,56 @@ type WorkflowFileResult struct {
 	Path        string                     `json:"path"`
 	ActionCount int                        `json:"action_count"`
 	Actions     []workflow.ActionReference `json:"actions"`
+	Status      string                     `json:"status,omitempty"`      // Set when the file was not analyzed (e.g., "skipped-too-large")
+	Error       string                     `json:"error,omitempty"`       // Why the file failed to parse (status "parse-failed")
+	Size        int                        `json:"size,omitempty"`        // File size in bytes, when known
+	BlobSHA     string                     `json:"blob_sha,omitempty"`    // Git blob SHA of the scanned content
+	CommitSHA   string                     `json:"commit_sha,omitempty"`  // Default branch commit the file was scanned at
+	Template    bool                       `json:"template,omitempty"`    // Organization workflow template in the .github repository
+	Usage       *WorkflowUsage             `json:"usage,omitempty"`       // Run history (scan --workflow-usage)
+	Minutes     *MinutesEstimate           `json:"minutes,omitempty"`     // Estimated Actions minutes (scan --estimate-minutes)
+	TokenUsage  *workflow.TokenUsage       `json:"token_usage,omitempty"` // Permissions and tokens the workflow uses
+}
+
+// WorkflowUsage summarizes how often a workflow file ran within the usage window
+type WorkflowUsage struct {
+	WindowDays  int            `json:"window_days"`
+	TotalRuns   int            `json:"total_runs"`
+	RunsByMonth map[string]int `json:"runs_by_month,omitempty"` // Keyed by "YYYY-MM"
+	LastRun     *time.Time     `json:"last_run,omitempty"`      // Most recent run, even outside the window
+	NeverRun    bool           `json:"never_run,omitempty"`     // The workflow has no runs at all
 }
 
+// Stale reports whether the workflow did not run within the usage window
+func (u *WorkflowUsage) Stale() bool {
+	return u != nil && u.TotalRuns == 0
+}
+
+// MinutesEstimate is the estimated monthly GitHub Actions usage of a workflow file, extrapolated from
+// the billable time of its recent runs
+type MinutesEstimate struct {
+	WindowDays      int            `json:"window_days"`
+	Runs            int            `json:"runs"`                // Runs within the window
+	SampledRuns     int            `json:"sampled_runs"`        // Runs whose billable time was fetched
+	MonthlyMinutes  int            `json:"monthly_minutes"`     // Estimated billable minutes per 30 days
+	ByRunner        map[string]int `json:"by_runner,omitempty"` // Monthly minutes per runner environment, e.g. "UBUNTU"
+	WeightedMinutes int            `json:"weighted_minutes"`    // Monthly minutes with runner multipliers applied (Windows 2x, macOS 10x)
+}
+
+// Workflow file statuses recorded when a file is skipped instead of analyzed
+const (
+	WorkflowStatusSkippedTooLarge   = "skipped-too-large"   // File exceeds the configured size limit
+	WorkflowStatusSkippedTooComplex = "skipped-too-complex" // YAML anchors/aliases expand beyond the node limit
+	WorkflowStatusParseFailed       = "parse-failed"        // File is not a valid workflow
+)
+
 // ActionIssue represents an issue with an action (outdated version, deprecated, etc.)
 type ActionIssue struct {
 	Repository         string   `json:"repository"`
+	WorkflowPath       string   `json:"workflow_path,omitempty"` // Path within the repository for reusable workflows and nested actions (migrations only)
 	CurrentVersion     string   `json:"current_version"`
 	SuggestedVersion   string   `json:"suggested_version,omitempty"`
-	IssueType          string   `json:"issue_type"` // "outdated", "deprecated", "migration"
+	IssueType          string   `json:"issue_type"` // "outdated", "deprecated", "migration", "comment-drift", "tag-moved", "risky-trigger"
 	Severity           string   `json:"severity"`   // "low", "medium", "high", "critical"
 	Description        string   `json:"description"`
 	Context            string   `json:"context"` // where the issue was found
@@ -51,8 +190,126 @@ type ActionIssue struct {
 	SchemaChanges      []string `json:"schema_changes,omitempty"`      // Description of schema changes that will be applied
 	HasTransformations bool     `json:"has_transformations,omitempty"` // Whether this upgrade includes schema transformations
 
+	// Patch preview: the concrete transformation of the step's with: block (scan --patch-preview)
+	PatchPreview *patcher.Patch `json:"patch_preview,omitempty"`
+
 	// Migration support: for actions that have moved to a new repository
 	MigrationTarget string `json:"migration_target,omitempty"` // Target repository for migration (e.g., "new-org/action@v1")
+
+	// Pin comment support: for SHA pins annotated with a trailing version comment
+	PinComment          string `json:"pin_comment,omitempty"`           // Current trailing comment (e.g., "v4.1.1")
+	SuggestedPinComment string `json:"suggested_pin_comment,omitempty"` // Version to record in the comment after updating
+
+	// Pin age support: release metadata for outdated issues (scan --pin-age)
+	CurrentVersionDate   *time.Time `json:"current_version_date,omitempty"`   // When the pinned version was released
+	SuggestedVersionDate *time.Time `json:"suggested_version_date,omitempty"` // When the suggested version was released
+	PinAgeDays           int        `json:"pin_age_days,omitempty"`           // Days since the pinned version was released
+	DaysBehind           int        `json:"days_behind,omitempty"`            // Days between the pinned and suggested releases
+
+	// Brownouts: the date an announced removal stops the pinned version working, and the announcement
+	Deadline     *time.Time `json:"deadline,omitempty"`
+	Announcement string     `json:"announcement,omitempty"`
+
+	// Baseline support: issues already present in a previous scan (scan --baseline)
+	Existing bool `json:"existing,omitempty"`
+
+	// Conditional rules: the conditions of the repository-specific rule that raised the issue
+	RuleConditions string `json:"rule_conditions,omitempty"`
+
+	// Action owners: users and "org/team" teams of the rule, requested as reviewers by create-pr
+	Owners []string `json:"owners,omitempty"`
+
+	// Commit message: the template or preset of the rule, used by create-pr for the commit of its updates
+	CommitMessage string `json:"commit_message,omitempty"`
+
+	// Report-only rules: the issue is reported but create-pr never plans an update for it
+	ReportOnly bool `json:"report_only,omitempty"`
+
+	// Required actions: where create-pr inserts a missing required action (rules with "insert": true)
+	Insertion *RequiredInsertion `json:"insertion,omitempty"`
+
+	// Banned actions: how create-pr removes or replaces the step (rules with a ban remediation)
+	Remediation *BanRemediation `json:"remediation,omitempty"`
+
+	// Concurrency hygiene: the concurrency block create-pr adds to the workflow (scan --hygiene-checks)
+	Concurrency *ConcurrencyRemediation `json:"concurrency,omitempty"`
+
+	// Cache key hygiene: key template suggested for an actions/cache step (scan --hygiene-checks cache-key)
+	SuggestedCacheKey string `json:"suggested_cache_key,omitempty"`
+
+	// Coordinated edits: files outside .github/workflows that create-pr changes alongside the update (rules with "files")
+	FileEdits []FileEdit `json:"file_edits,omitempty"`
+
+	// Prioritization: 0-100 score weighing severity, popularity, deploy workflows, triggers, and version distance
+	PriorityScore int `json:"priority_score,omitempty"`
+
+	// Reusable workflow reach: other scanned repositories calling the reusable workflow containing the finding
+	Consumers int `json:"consumers,omitempty"`
+
+	// Rule documentation: the stable id of the issue type and where its meaning and remediation are documented
+	RuleID   string `json:"rule_id,omitempty"`  // e.g. "AM001" for outdated
+	RuleDocs string `json:"docs_url,omitempty"` // Use DocsURL(), which falls back to the default documentation
+
+	// Check-specific details without a field of their own, e.g. {"cve": "CVE-2025-30066"}
+	Metadata IssueMetadata `json:"metadata,omitempty"`
+}
+
+// Well-known issue metadata keys
+const (
+	MetadataCVE              = "cve"               // Advisory id, e.g. "CVE-2025-30066" or "GHSA-mrrh-fwg8-r2c3"
+	MetadataEOLDate          = "eol_date"          // End-of-life date of a runtime or runner image, as YYYY-MM-DD
+	MetadataRunnerLabel      = "runner_label"      // Runner label the issue concerns, e.g. "ubuntu-20.04"
+	MetadataOriginalSeverity = "original_severity" // Severity before a severity policy lowered it
+)
+
+// IssueMetadata holds check-specific details of an issue, keyed by name
+// Reading a missing key, including from a nil map, returns "". Templates read entries as
+// {{.Metadata.cve}} or {{index .Metadata "eol_date"}}.
+type IssueMetadata map[string]string
+
+// SetMetadata records a detail of the issue, creating the metadata map on first use
+func (i *ActionIssue) SetMetadata(key, value string) {
+	if i.Metadata == nil {
+		i.Metadata = make(IssueMetadata)
+	}
+	i.Metadata[key] = value
+}
+
+// FileEdit is a regular expression edit to a repository file, made in the same pull request as an action update
+type FileEdit struct {
+	Path    string `json:"path"`             // File to edit, e.g. ".github/dependabot.yml" or "README.md"
+	Find    string `json:"find"`             // Regular expression matching the text to replace
+	Replace string `json:"replace"`          // Replacement; $1 expands groups, {current} and {target} the action versions
+	Reason  string `json:"reason,omitempty"` // Why the file changes, shown in the pull request
+}
+
+// BanRemediation describes how to remove or replace a banned action
+type BanRemediation struct {
+	Action      string            `json:"action"`                 // "remove" deletes the step, "replace" swaps the action
+	Replacement string            `json:"replacement,omitempty"`  // Replacement call, e.g. "my-org/checkout@v4"
+	WithMapping map[string]string `json:"with_mapping,omitempty"` // Inputs renamed for the replacement; an empty name drops the input
+	With        map[string]string `json:"with,omitempty"`         // Inputs set on the replacement
+}
+
+// ConcurrencyRemediation describes the workflow-level concurrency block added to a workflow
+// An existing block keeps its group and gains cancel-in-progress.
+type ConcurrencyRemediation struct {
+	Group            string `json:"group"`              // Group added when the workflow sets none
+	CancelInProgress bool   `json:"cancel_in_progress"` // Cancel superseded runs, or queue them behind the running one
+}
+
+// RequiredInsertion describes how to insert a missing required action into a workflow
+type RequiredInsertion struct {
+	Scope string            `json:"scope"`          // "workflow" inserts a first job, "job" inserts a first step
+	Job   string            `json:"job,omitempty"`  // Job receiving the step, for job scope
+	With  map[string]string `json:"with,omitempty"` // Inputs of the inserted call
+}
+
+// SuppressedIssue represents an issue silenced by a suppressions file entry
+type SuppressedIssue struct {
+	ActionIssue
+	Reason string `json:"suppression_reason,omitempty"` // Why the risk was accepted
+	Until  string `json:"suppressed_until,omitempty"`   // Expiry date of the suppression, if any
 }
 
 // Summary provides aggregate statistics about the scan
@@ -67,7 +324,78 @@ type Summary struct {
 	UniqueReusableWorkflows map[string]ActionUsageStat `json:"unique_reusable_workflows"` // Only reusable workflows
 	IssuesByType            map[string]int             `json:"issues_by_type"`
 	IssuesBySeverity        map[string]int             `json:"issues_by_severity"`
+	TotalSuppressedIssues   int                        `json:"total_suppressed_issues,omitempty"`
+	ExistingIssues          int                        `json:"existing_issues,omitempty"`        // Issues already present in the baseline scan
+	SkippedWorkflowFiles    int                        `json:"skipped_workflow_files,omitempty"` // Files recorded but not analyzed
+	UnscannedRepositories   map[string]int             `json:"unscanned_repositories,omitempty"` // Repositories whose workflow files were not scanned, by status
 	TopIssues               []ActionIssue              `json:"top_issues"`
+	Pinning                 *PinningSummary            `json:"pinning,omitempty"`           // References by pinning style
+	Tokens                  *TokenSummary              `json:"tokens,omitempty"`            // Workflows by permissions and token usage
+	Debt                    *DebtSummary               `json:"debt,omitempty"`              // Issues weighted by severity per repository, against the current target
+	Freshness               *FreshnessSummary          `json:"freshness,omitempty"`         // How far outdated references lag behind
+	Deadlines               []Deadline                 `json:"deadlines,omitempty"`         // Announced removals of versions in use, soonest first
+	BrokenReferences        []BrokenReference          `json:"broken_references,omitempty"` // Repositories or refs in use that do not exist
+	SeverityHistory         []SeveritySnapshot         `json:"severity_history,omitempty"`  // Severity counts of previous scans (scan --baseline)
+	MergeLatency            []TeamMergeLatency         `json:"merge_latency,omitempty"`     // Time-to-merge of actions-maintainer pull requests per team (scan --team-property)
+	Owners                  []OwnerSummary             `json:"owners,omitempty"`            // Per-owner breakdown of merged reports (report --merge)
+	Timing                  *TimingBreakdown           `json:"timing,omitempty"`            // Where scan time was spent (scan command only)
+}
+
+// TimingBreakdown records where time was spent during a scan
+// Analyze excludes version resolution, which is reported separately as Resolve. Durations are
+// written to JSON as seconds, rounded to the millisecond.
+type TimingBreakdown struct {
+	API            time.Duration // Fetching repositories, workflow files, and properties
+	Parse          time.Duration // Parsing workflow YAML
+	Analyze        time.Duration // Evaluating rules against actions
+	Resolve        time.Duration // Resolving versions and refs (includes resolver API calls)
+	APIRequests    int           // Total GitHub API requests sent
+	ResolverCalls  int           // Total version resolver invocations
+	APIRequestTime time.Duration // Total time waiting on GitHub API responses
+}
+
+// timingJSON is the JSON form of a TimingBreakdown
+type timingJSON struct {
+	APISeconds        float64 `json:"api_seconds"`
+	ParseSeconds      float64 `json:"parse_seconds"`
+	AnalyzeSeconds    float64 `json:"analyze_seconds"`
+	ResolveSeconds    float64 `json:"resolve_seconds"`
+	APIRequests       int     `json:"api_requests"`
+	ResolverCalls     int     `json:"resolver_calls"`
+	APIRequestSeconds float64 `json:"api_request_seconds"`
+}
+
+// MarshalJSON writes the durations as seconds
+func (t TimingBreakdown) MarshalJSON() ([]byte, error) {
+	seconds := func(d time.Duration) float64 { return math.Round(d.Seconds()*1000) / 1000 }
+	return json.Marshal(timingJSON{
+		APISeconds:        seconds(t.API),
+		ParseSeconds:      seconds(t.Parse),
+		AnalyzeSeconds:    seconds(t.Analyze),
+		ResolveSeconds:    seconds(t.Resolve),
+		APIRequests:       t.APIRequests,
+		ResolverCalls:     t.ResolverCalls,
+		APIRequestSeconds: seconds(t.APIRequestTime),
+	})
+}
+
+// UnmarshalJSON reads durations written as seconds
+func (t *TimingBreakdown) UnmarshalJSON(data []byte) error {
+	var decoded timingJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	duration := func(seconds float64) time.Duration { return time.Duration(seconds * float64(time.Second)) }
+	*t = TimingBreakdown{
+		API:            duration(decoded.APISeconds),
+		Parse:          duration(decoded.ParseSeconds),
+		Analyze:        duration(decoded.AnalyzeSeconds),
+		Resolve:        duration(decoded.ResolveSeconds),
+		APIRequests:    decoded.APIRequests,
+		ResolverCalls:  decoded.ResolverCalls,
+		APIRequestTime: duration(decoded.APIRequestSeconds),
+	}
+	return nil
 }
 
 // ActionUsageStat represents usage statistics for a specific action
@@ -82,10 +410,15 @@ type ActionUsageStat struct {
 // CreatedPR represents a pull request that was created during the scan
 type CreatedPR struct {
 	Repository  string `json:"repository"`
+	BaseBranch  string `json:"base_branch,omitempty"` // Set when the PR targets a branch other than the default
 	URL         string `json:"url"`
+	Branch      string `json:"branch,omitempty"` // Head branch the pull request was opened from
 	Title       string `json:"title"`
 	Number      int    `json:"number"`
 	UpdateCount int    `json:"update_count"`
+
+	Reviewers  []string `json:"reviewers,omitempty"`  // Action owners requested as reviewers
+	Attachment string   `json:"attachment,omitempty"` // File on the branch listing every update when the body was truncated
 }
 
 // FormatJSON outputs the scan results as JSON
@@ -112,9 +445,13 @@ func FormatJSON(result *ScanResult, writer io.Writer, pretty bool) error {
 }
 
 // BuildScanResult constructs a complete scan result from repository data
+// Repositories, workflow files, actions, and issues are sorted so that results are
+// identical regardless of the order in which repositories were scanned.
 func BuildScanResult(owner string, repositories []RepositoryResult) *ScanResult {
 	scanTime := time.Now()
 
+	SortRepositoryResults(repositories)
+
 	// Calculate summary statistics
 	summary := calculateSummary(repositories)
 
@@ -140,120 +477,262 @@ func AddCreatedPR(result *ScanResult, pr CreatedPR) {
 	result.CreatedPRs = append(result.CreatedPRs, pr)
 }
 
-// calculateSummary generates summary statistics from repository results
-func calculateSummary(repositories []RepositoryResult) Summary {
-	summary := Summary{
-		UniqueActions:           make(map[string]ActionUsageStat),
-		UniqueRegularActions:    make(map[string]ActionUsageStat),
-		UniqueReusableWorkflows: make(map[string]ActionUsageStat),
-		IssuesByType:            make(map[string]int),
-		IssuesBySeverity:        make(map[string]int),
-	}
+// SortRepositoryResults sorts repositories by full name and their workflow files, actions,
+// and issues into a deterministic order. Sorting happens in place.
+func SortRepositoryResults(repositories []RepositoryResult) {
+	sort.SliceStable(repositories, func(i, j int) bool {
+		return repositories[i].FullName < repositories[j].FullName
+	})
 
-	totalWorkflowFiles := 0
-	totalActions := 0
-	totalRegularActions := 0
-	totalReusableWorkflows := 0
-	var allIssues []ActionIssue
+	for i := range repositories {
+		repo := &repositories[i]
 
-	// Process each repository
-	for _, repo := range repositories {
-		summary.TotalRepositories++
-		totalWorkflowFiles += len(repo.WorkflowFiles)
+		sort.SliceStable(repo.WorkflowFiles, func(a, b int) bool {
+			return repo.WorkflowFiles[a].Path < repo.WorkflowFiles[b].Path
+		})
+		for j := range repo.WorkflowFiles {
+			sortActionReferences(repo.WorkflowFiles[j].Actions)
+		}
 
-		// Process actions in this repository
-		for _, action := range repo.Actions {
-			totalActions++
+		sortActionReferences(repo.Actions)
+		SortIssues(repo.Issues)
 
-			if action.IsReusable {
-				totalReusableWorkflows++
-			} else {
-				totalRegularActions++
+		sort.SliceStable(repo.SuppressedIssues, func(a, b int) bool {
+			x, y := repo.SuppressedIssues[a], repo.SuppressedIssues[b]
+			if x.FilePath != y.FilePath {
+				return x.FilePath < y.FilePath
 			}
+			return x.Repository < y.Repository
+		})
+	}
+}
 
-			// Update combined unique actions statistics
-			stat, exists := summary.UniqueActions[action.Repository]
-			if !exists {
-				stat = ActionUsageStat{
-					Repository:         action.Repository,
-					Versions:           make(map[string]int),
-					Repositories:       make([]string, 0),
-					IsReusableWorkflow: action.IsReusable,
-				}
-			}
+// sortActionReferences orders action references by file, context, repository, and version
+func sortActionReferences(actions []workflow.ActionReference) {
+	sort.SliceStable(actions, func(i, j int) bool {
+		a, b := actions[i], actions[j]
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		if a.Context != b.Context {
+			return a.Context < b.Context
+		}
+		if a.Repository != b.Repository {
+			return a.Repository < b.Repository
+		}
+		return a.Version < b.Version
+	})
+}
 
-			stat.UsageCount++
-			stat.Versions[action.Version]++
+// SortIssues orders issues by priority score and severity (highest first), then file, action, and issue type
+func SortIssues(issues []ActionIssue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.PriorityScore != b.PriorityScore {
+			return a.PriorityScore > b.PriorityScore
+		}
+		if a.Severity != b.Severity {
+			return isHigherSeverity(a.Severity, b.Severity)
+		}
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		if a.Repository != b.Repository {
+			return a.Repository < b.Repository
+		}
+		if a.IssueType != b.IssueType {
+			return a.IssueType < b.IssueType
+		}
+		return a.Context < b.Context
+	})
+}
 
-			// Add repository to list if not already present
-			found := false
-			for _, repoName := range stat.Repositories {
-				if repoName == repo.FullName {
-					found = true
-					break
-				}
-			}
-			if !found {
-				stat.Repositories = append(stat.Repositories, repo.FullName)
-			}
+// summaryBuilder accumulates summary statistics one repository at a time,
+// so summaries can be calculated without holding every repository in memory
+type summaryBuilder struct {
+	summary   Summary
+	allIssues []ActionIssue
+	pinning   *PinningSummary
+	tokens    *TokenSummary
+	deadlines map[string]*deadlineTally
+	broken    map[string]*BrokenReference
+}
 
-			summary.UniqueActions[action.Repository] = stat
+// newSummaryBuilder creates an empty summary builder
+func newSummaryBuilder() *summaryBuilder {
+	return &summaryBuilder{
+		summary: Summary{
+			UniqueActions:           make(map[string]ActionUsageStat),
+			UniqueRegularActions:    make(map[string]ActionUsageStat),
+			UniqueReusableWorkflows: make(map[string]ActionUsageStat),
+			IssuesByType:            make(map[string]int),
+			IssuesBySeverity:        make(map[string]int),
+		},
+		pinning:   &PinningSummary{Styles: make(map[string]int)},
+		tokens:    &TokenSummary{},
+		deadlines: make(map[string]*deadlineTally),
+		broken:    make(map[string]*BrokenReference),
+	}
+}
 
-			// Update type-specific statistics
-			var typeSpecificMap map[string]ActionUsageStat
-			if action.IsReusable {
-				typeSpecificMap = summary.UniqueReusableWorkflows
-			} else {
-				typeSpecificMap = summary.UniqueRegularActions
-			}
+// add accumulates the statistics of one repository
+func (b *summaryBuilder) add(repo RepositoryResult) {
+	// Repositories without workflows would only dilute the statistics
+	if !repo.Scanned() {
+		if b.summary.UnscannedRepositories == nil {
+			b.summary.UnscannedRepositories = make(map[string]int)
+		}
+		b.summary.UnscannedRepositories[repo.Status]++
+		return
+	}
 
-			typeStat, exists := typeSpecificMap[action.Repository]
-			if !exists {
-				typeStat = ActionUsageStat{
-					Repository:         action.Repository,
-					Versions:           make(map[string]int),
-					Repositories:       make([]string, 0),
-					IsReusableWorkflow: action.IsReusable,
-				}
+	b.summary.TotalRepositories++
+	b.summary.TotalWorkflowFiles += len(repo.WorkflowFiles)
+	for _, wf := range repo.WorkflowFiles {
+		if wf.Status != "" {
+			b.summary.SkippedWorkflowFiles++
+		}
+	}
+
+	// Process actions in this repository
+	for _, action := range repo.Actions {
+		b.summary.TotalActions++
+
+		if action.IsReusable {
+			b.summary.TotalReusableWorkflows++
+		} else {
+			b.summary.TotalRegularActions++
+		}
+
+		// Local reusable workflows are not shared, so they are left out of the usage of each action
+		if action.Local {
+			continue
+		}
+
+		// Update combined unique actions statistics
+		stat, exists := b.summary.UniqueActions[action.Repository]
+		if !exists {
+			stat = ActionUsageStat{
+				Repository:         action.Repository,
+				Versions:           make(map[string]int),
+				Repositories:       make([]string, 0),
+				IsReusableWorkflow: action.IsReusable,
 			}
+		}
 
-			typeStat.UsageCount++
-			typeStat.Versions[action.Version]++
+		stat.UsageCount++
+		stat.Versions[action.Version]++
 
-			// Add repository to list if not already present
-			found = false
-			for _, repoName := range typeStat.Repositories {
-				if repoName == repo.FullName {
-					found = true
-					break
-				}
+		// Add repository to list if not already present
+		found := false
+		for _, repoName := range stat.Repositories {
+			if repoName == repo.FullName {
+				found = true
+				break
 			}
-			if !found {
-				typeStat.Repositories = append(typeStat.Repositories, repo.FullName)
+		}
+		if !found {
+			stat.Repositories = append(stat.Repositories, repo.FullName)
+		}
+
+		b.summary.UniqueActions[action.Repository] = stat
+
+		// Update type-specific statistics
+		var typeSpecificMap map[string]ActionUsageStat
+		if action.IsReusable {
+			typeSpecificMap = b.summary.UniqueReusableWorkflows
+		} else {
+			typeSpecificMap = b.summary.UniqueRegularActions
+		}
+
+		typeStat, exists := typeSpecificMap[action.Repository]
+		if !exists {
+			typeStat = ActionUsageStat{
+				Repository:         action.Repository,
+				Versions:           make(map[string]int),
+				Repositories:       make([]string, 0),
+				IsReusableWorkflow: action.IsReusable,
 			}
+		}
+
+		typeStat.UsageCount++
+		typeStat.Versions[action.Version]++
 
-			typeSpecificMap[action.Repository] = typeStat
+		// Add repository to list if not already present
+		found = false
+		for _, repoName := range typeStat.Repositories {
+			if repoName == repo.FullName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			typeStat.Repositories = append(typeStat.Repositories, repo.FullName)
 		}
 
-		// Process issues
-		for _, issue := range repo.Issues {
-			allIssues = append(allIssues, issue)
-			summary.IssuesByType[issue.IssueType]++
-			summary.IssuesBySeverity[issue.Severity]++
+		typeSpecificMap[action.Repository] = typeStat
+	}
+
+	// Process issues
+	for _, issue := range repo.Issues {
+		b.allIssues = append(b.allIssues, issue)
+		b.summary.IssuesByType[issue.IssueType]++
+		b.summary.IssuesBySeverity[issue.Severity]++
+		if issue.Existing {
+			b.summary.ExistingIssues++
 		}
 	}
 
-	summary.TotalWorkflowFiles = totalWorkflowFiles
-	summary.TotalActions = totalActions
-	summary.TotalRegularActions = totalRegularActions
-	summary.TotalReusableWorkflows = totalReusableWorkflows
+	// Suppressed issues are tracked separately and excluded from issue statistics
+	b.summary.TotalSuppressedIssues += len(repo.SuppressedIssues)
+
+	countPinning(b.pinning, repo.Actions)
+	countTokens(b.tokens, repo.WorkflowFiles)
+	tallyDeadlines(b.deadlines, repo)
+	tallyBrokenReferences(b.broken, repo)
+}
+
+// build returns the accumulated summary
+func (b *summaryBuilder) build() Summary {
+	summary := b.summary
+
+	// Repository lists are built in scan order; sort them so output is stable.
+	// Map keys are already emitted in sorted order by encoding/json.
+	for _, statsMap := range []map[string]ActionUsageStat{summary.UniqueActions, summary.UniqueRegularActions, summary.UniqueReusableWorkflows} {
+		for name, stat := range statsMap {
+			sort.Strings(stat.Repositories)
+			statsMap[name] = stat
+		}
+	}
 
 	// Select top issues (limit to 10)
-	summary.TopIssues = selectTopIssues(allIssues, 10)
+	summary.TopIssues = selectTopIssues(b.allIssues, 10)
+
+	if b.pinning.Total > 0 {
+		summary.Pinning = b.pinning
+	}
+	if b.tokens.Workflows > 0 {
+		summary.Tokens = b.tokens
+	}
+	if summary.TotalRepositories > 0 {
+		summary.Debt = CalculateDebt(summary, nil, time.Now())
+	}
+	summary.Freshness = calculateFreshness(b.allIssues)
+	summary.Deadlines = sortedDeadlines(b.deadlines)
+	summary.BrokenReferences = sortedBrokenReferences(b.broken)
 
 	return summary
 }
 
+// calculateSummary generates summary statistics from repository results
+func calculateSummary(repositories []RepositoryResult) Summary {
+	builder := newSummaryBuilder()
+	for _, repo := range repositories {
+		builder.add(repo)
+	}
+	return builder.build()
+}
+
 // WorkflowIssueGroup represents consolidated issues for a single workflow file
 type WorkflowIssueGroup struct {
 	FilePath    string
@@ -308,16 +787,17 @@ func selectTopIssues(issues []ActionIssue, limit int) []ActionIssue {
 		sortedGroups = append(sortedGroups, groupWithCount{group: group, count: group.IssueCount})
 	}
 
-	// Sort by issue count (descending), then by severity as tiebreaker
-	for i := 0; i < len(sortedGroups)-1; i++ {
-		for j := i + 1; j < len(sortedGroups); j++ {
-			if sortedGroups[i].count < sortedGroups[j].count ||
-				(sortedGroups[i].count == sortedGroups[j].count &&
-					!isHigherSeverity(sortedGroups[i].group.Severity, sortedGroups[j].group.Severity)) {
-				sortedGroups[i], sortedGroups[j] = sortedGroups[j], sortedGroups[i]
-			}
+	// Sort by issue count (descending), then by severity, then by file path for deterministic ties
+	sort.Slice(sortedGroups, func(i, j int) bool {
+		a, b := sortedGroups[i], sortedGroups[j]
+		if a.count != b.count {
+			return a.count > b.count
 		}
-	}
+		if a.group.Severity != b.group.Severity {
+			return isHigherSeverity(a.group.Severity, b.group.Severity)
+		}
+		return a.group.FilePath < b.group.FilePath
+	})
 
 	// Convert back to ActionIssue format, limiting to the specified count
 	topIssues := make([]ActionIssue, 0, limit)
@@ -343,6 +823,30 @@ func selectTopIssues(issues []ActionIssue, limit int) []ActionIssue {
 	return topIssues
 }
 
+// IsValidSeverity reports whether severity is one of "low", "medium", "high", or "critical"
+func IsValidSeverity(severity string) bool {
+	switch severity {
+	case "low", "medium", "high", "critical":
+		return true
+	}
+	return false
+}
+
+// GatingIssues returns new issues at or above the minimum severity
+// Issues marked as existing by a baseline never gate.
+func GatingIssues(result *ScanResult, minSeverity string) []ActionIssue {
+	var gating []ActionIssue
+	for _, repo := range result.Repositories {
+		for _, issue := range repo.Issues {
+			if issue.Existing || isHigherSeverity(minSeverity, issue.Severity) {
+				continue
+			}
+			gating = append(gating, issue)
+		}
+	}
+	return gating
+}
+
 // isHigherSeverity returns true if severity1 is higher than severity2
 func isHigherSeverity(severity1, severity2 string) bool {
 	severityOrder := map[string]int{
@@ -1,8 +1,11 @@
 package output
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
 )
@@ -430,3 +433,172 @@ func TestCalculateSummary_ActionsAndWorkflowsBreakdown(t *testing.T) {
 		t.Logf("%s: %d uses, %d versions, %d repos", name, stat.UsageCount, len(stat.Versions), len(stat.Repositories))
 	}
 }
+
+// TestBuildScanResult_DeterministicOrdering verifies that results are identical regardless of scan order
+func TestBuildScanResult_DeterministicOrdering(t *testing.T) {
+	makeRepos := func(reverse bool) []RepositoryResult {
+		repos := []RepositoryResult{
+			{
+				Name:     "repo-b",
+				FullName: "owner/repo-b",
+				WorkflowFiles: []WorkflowFileResult{
+					{Path: ".github/workflows/z.yml"},
+					{Path: ".github/workflows/a.yml"},
+				},
+				Actions: []workflow.ActionReference{
+					{Repository: "actions/setup-go", Version: "v4", FilePath: ".github/workflows/z.yml", Context: "job:build/step:go"},
+					{Repository: "actions/checkout", Version: "v3", FilePath: ".github/workflows/a.yml", Context: "job:build/step:checkout"},
+				},
+				Issues: []ActionIssue{
+					{Repository: "actions/setup-go", IssueType: "outdated", Severity: "low", FilePath: ".github/workflows/z.yml"},
+					{Repository: "actions/checkout", IssueType: "deprecated", Severity: "high", FilePath: ".github/workflows/a.yml"},
+				},
+			},
+			{
+				Name:     "repo-a",
+				FullName: "owner/repo-a",
+				Actions: []workflow.ActionReference{
+					{Repository: "actions/checkout", Version: "v4", FilePath: ".github/workflows/ci.yml", Context: "job:test/step:checkout"},
+				},
+			},
+		}
+		if reverse {
+			repos[0], repos[1] = repos[1], repos[0]
+			first := &repos[1]
+			first.WorkflowFiles[0], first.WorkflowFiles[1] = first.WorkflowFiles[1], first.WorkflowFiles[0]
+			first.Actions[0], first.Actions[1] = first.Actions[1], first.Actions[0]
+			first.Issues[0], first.Issues[1] = first.Issues[1], first.Issues[0]
+		}
+		return repos
+	}
+
+	first := BuildScanResult("owner", makeRepos(false))
+	second := BuildScanResult("owner", makeRepos(true))
+
+	if first.Repositories[0].FullName != "owner/repo-a" {
+		t.Errorf("Expected repositories sorted by full name, got %s first", first.Repositories[0].FullName)
+	}
+
+	repoB := first.Repositories[1]
+	if repoB.WorkflowFiles[0].Path != ".github/workflows/a.yml" {
+		t.Errorf("Expected workflow files sorted by path, got %s first", repoB.WorkflowFiles[0].Path)
+	}
+	if repoB.Actions[0].Repository != "actions/checkout" {
+		t.Errorf("Expected actions sorted by file path, got %s first", repoB.Actions[0].Repository)
+	}
+	if repoB.Issues[0].Severity != "high" {
+		t.Errorf("Expected issues sorted by severity, got %s first", repoB.Issues[0].Severity)
+	}
+
+	checkoutRepos := first.Summary.UniqueActions["actions/checkout"].Repositories
+	if len(checkoutRepos) != 2 || checkoutRepos[0] != "owner/repo-a" {
+		t.Errorf("Expected summary repository list sorted, got %v", checkoutRepos)
+	}
+
+	// Normalize timing and compare the serialized output of both runs
+	second.ScanTime = first.ScanTime
+	var firstJSON, secondJSON strings.Builder
+	if err := FormatJSON(first, &firstJSON, true); err != nil {
+		t.Fatalf("Failed to format first result: %v", err)
+	}
+	if err := FormatJSON(second, &secondJSON, true); err != nil {
+		t.Fatalf("Failed to format second result: %v", err)
+	}
+	if firstJSON.String() != secondJSON.String() {
+		t.Error("Expected identical JSON output regardless of input ordering")
+	}
+}
+
+func TestCalculateSummary_CountsSkippedWorkflowFiles(t *testing.T) {
+	repositories := []RepositoryResult{
+		{
+			Name:     "api",
+			FullName: "my-org/api",
+			WorkflowFiles: []WorkflowFileResult{
+				{Path: ".github/workflows/ci.yml", ActionCount: 1},
+				{Path: ".github/workflows/generated.yml", Status: WorkflowStatusSkippedTooLarge, Size: 5 * 1024 * 1024},
+				{Path: ".github/workflows/anchors.yml", Status: WorkflowStatusSkippedTooComplex},
+			},
+		},
+	}
+
+	summary := calculateSummary(repositories)
+	if summary.TotalWorkflowFiles != 3 {
+		t.Errorf("Expected 3 workflow files, got %d", summary.TotalWorkflowFiles)
+	}
+	if summary.SkippedWorkflowFiles != 2 {
+		t.Errorf("Expected 2 skipped workflow files, got %d", summary.SkippedWorkflowFiles)
+	}
+}
+
+func TestCalculateSummary_ExcludesUnscannedRepositories(t *testing.T) {
+	repositories := []RepositoryResult{
+		{
+			Name:          "api",
+			FullName:      "my-org/api",
+			WorkflowFiles: []WorkflowFileResult{{Path: ".github/workflows/ci.yml", ActionCount: 1}},
+			Actions:       []workflow.ActionReference{{Repository: "actions/checkout", Version: "v3"}},
+			Issues:        []ActionIssue{{Repository: "actions/checkout", IssueType: "outdated", Severity: "medium"}},
+		},
+		{Name: "new", FullName: "my-org/new", Status: RepositoryStatusEmpty},
+		{Name: "docs", FullName: "my-org/docs", Status: RepositoryStatusNoWorkflows},
+		{Name: "legacy", FullName: "my-org/legacy", Status: RepositoryStatusSubmodule},
+		{Name: "site", FullName: "my-org/site", Status: RepositoryStatusNoWorkflows},
+	}
+
+	summary := calculateSummary(repositories)
+	if summary.TotalRepositories != 1 {
+		t.Errorf("Expected only the scanned repository to be counted, got %d", summary.TotalRepositories)
+	}
+	expected := map[string]int{RepositoryStatusEmpty: 1, RepositoryStatusSubmodule: 1, RepositoryStatusNoWorkflows: 2}
+	for status, count := range expected {
+		if summary.UnscannedRepositories[status] != count {
+			t.Errorf("Expected %d %s repositories, got %d", count, status, summary.UnscannedRepositories[status])
+		}
+	}
+	if summary.IssuesBySeverity["medium"] != 1 || summary.TotalActions != 1 {
+		t.Errorf("Expected issue statistics from the scanned repository only, got %+v", summary)
+	}
+}
+
+func TestGatingIssues_ExcludesExistingAndLowerSeverity(t *testing.T) {
+	result := BuildScanResult("my-org", []RepositoryResult{
+		{FullName: "my-org/api", Issues: []ActionIssue{
+			{Repository: "actions/checkout", IssueType: "outdated", Severity: "high"},
+			{Repository: "actions/cache", IssueType: "deprecated", Severity: "critical", Existing: true},
+			{Repository: "actions/setup-go", IssueType: "outdated", Severity: "medium"},
+		}},
+	})
+
+	gating := GatingIssues(result, "high")
+	if len(gating) != 1 || gating[0].Repository != "actions/checkout" {
+		t.Errorf("Expected only the new high severity issue to gate, got %+v", gating)
+	}
+	if got := len(GatingIssues(result, "low")); got != 2 {
+		t.Errorf("Expected 2 new issues at or above low severity, got %d", got)
+	}
+	if result.Summary.ExistingIssues != 1 {
+		t.Errorf("Expected 1 existing issue in summary, got %d", result.Summary.ExistingIssues)
+	}
+}
+
+func TestTimingBreakdown_JSONSeconds(t *testing.T) {
+	timing := TimingBreakdown{API: 1500 * time.Millisecond, Parse: 1234567 * time.Nanosecond, APIRequests: 12, APIRequestTime: 2 * time.Second}
+	data, err := json.Marshal(timing)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	for _, expected := range []string{`"api_seconds":1.5`, `"parse_seconds":0.001`, `"api_requests":12`, `"api_request_seconds":2`} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Expected %s in %s", expected, data)
+		}
+	}
+
+	var decoded TimingBreakdown
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if decoded.API != timing.API || decoded.APIRequestTime != timing.APIRequestTime || decoded.APIRequests != 12 {
+		t.Errorf("Expected the durations to read back, got %+v", decoded)
+	}
+}
@@ -0,0 +1,102 @@
+package output
+
+import (
+	"sort"
+	"strings"
+)
+
+// OwnerSummary is the share of a merged report belonging to one owner
+type OwnerSummary struct {
+	Owner            string         `json:"owner"`
+	Repositories     int            `json:"repositories"`
+	Issues           int            `json:"issues"`
+	IssuesBySeverity map[string]int `json:"issues_by_severity"`
+}
+
+// MergeScanResults combines scan results of different owners or scan shards into one result
+// Repositories scanned more than once, on the same host, keep the result of the latest scan. The summary is recalculated
+// over every repository, with a per-owner breakdown in Owners, and the scan spans the earliest start
+// to the latest end. Severity history is dropped, as each input was compared with its own baseline.
+func MergeScanResults(results []*ScanResult) *ScanResult {
+	// Later scans replace earlier results of the same repository
+	ordered := make([]*ScanResult, len(results))
+	copy(ordered, results)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].ScanTime.Before(ordered[j].ScanTime)
+	})
+
+	merged := &ScanResult{CreatedPRs: []CreatedPR{}}
+	byName := make(map[string]int)
+	var owners []string
+	seenOwners := make(map[string]bool)
+	for _, result := range ordered {
+		for _, owner := range strings.Split(result.Owner, ",") {
+			if owner != "" && !seenOwners[strings.ToLower(owner)] {
+				seenOwners[strings.ToLower(owner)] = true
+				owners = append(owners, owner)
+			}
+		}
+		if merged.ScanTime.IsZero() || result.ScanTime.Before(merged.ScanTime) {
+			merged.ScanTime = result.ScanTime
+		}
+		if result.ScanEndTime.After(merged.ScanEndTime) {
+			merged.ScanEndTime = result.ScanEndTime
+		}
+
+		for _, repo := range result.Repositories {
+			key := repo.Host + " " + repo.FullName
+			if i, ok := byName[key]; ok {
+				merged.Repositories[i] = repo
+				continue
+			}
+			byName[key] = len(merged.Repositories)
+			merged.Repositories = append(merged.Repositories, repo)
+		}
+		merged.CreatedPRs = append(merged.CreatedPRs, result.CreatedPRs...)
+		merged.ReusableWorkflowCandidates = append(merged.ReusableWorkflowCandidates, result.ReusableWorkflowCandidates...)
+		merged.TagProtectionFindings = append(merged.TagProtectionFindings, result.TagProtectionFindings...)
+		merged.MajorTagFindings = append(merged.MajorTagFindings, result.MajorTagFindings...)
+		merged.InternalActions = append(merged.InternalActions, result.InternalActions...)
+	}
+	if !merged.ScanEndTime.IsZero() {
+		merged.Duration = merged.ScanEndTime.Sub(merged.ScanTime)
+	}
+	merged.Owner = strings.Join(owners, ",")
+
+	SortRepositoryResults(merged.Repositories)
+	merged.Summary = calculateSummary(merged.Repositories)
+	merged.Summary.Owners = ownerSummaries(merged.Repositories)
+	return merged
+}
+
+// ownerSummaries breaks repositories and their issues down by owner, most issues first
+func ownerSummaries(repositories []RepositoryResult) []OwnerSummary {
+	byOwner := make(map[string]*OwnerSummary)
+	var order []string
+	for _, repo := range repositories {
+		owner := repositoryOwner(repo.FullName)
+		summary, ok := byOwner[owner]
+		if !ok {
+			summary = &OwnerSummary{Owner: owner, IssuesBySeverity: make(map[string]int)}
+			byOwner[owner] = summary
+			order = append(order, owner)
+		}
+		summary.Repositories++
+		for _, issue := range repo.Issues {
+			summary.Issues++
+			summary.IssuesBySeverity[issue.Severity]++
+		}
+	}
+
+	summaries := make([]OwnerSummary, 0, len(order))
+	for _, owner := range order {
+		summaries = append(summaries, *byOwner[owner])
+	}
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if summaries[i].Issues != summaries[j].Issues {
+			return summaries[i].Issues > summaries[j].Issues
+		}
+		return summaries[i].Owner < summaries[j].Owner
+	})
+	return summaries
+}
@@ -0,0 +1,85 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMergeScanResults(t *testing.T) {
+	first := BuildScanResult("org-a", []RepositoryResult{
+		{Name: "api", FullName: "org-a/api", Issues: []ActionIssue{{Repository: "actions/checkout", IssueType: "outdated", Severity: "medium"}}},
+		{Name: "web", FullName: "org-a/web", Issues: []ActionIssue{{Repository: "actions/cache", IssueType: "outdated", Severity: "high"}}},
+	})
+	first.ScanTime = time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	first.ScanEndTime = first.ScanTime.Add(time.Hour)
+	first.CreatedPRs = []CreatedPR{{Repository: "org-a/api", Number: 3}}
+
+	second := BuildScanResult("org-b", []RepositoryResult{
+		{Name: "tools", FullName: "org-b/tools", Issues: []ActionIssue{
+			{Repository: "actions/cache", IssueType: "outdated", Severity: "high"},
+			{Repository: "actions/setup-go", IssueType: "deprecated", Severity: "critical"},
+		}},
+	})
+	second.ScanTime = time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	second.ScanEndTime = second.ScanTime.Add(30 * time.Minute)
+
+	// A later rescan of org-a/web, which has since been fixed
+	rescan := BuildScanResult("org-a", []RepositoryResult{{Name: "web", FullName: "org-a/web"}})
+	rescan.ScanTime = time.Date(2026, 10, 2, 9, 0, 0, 0, time.UTC)
+	rescan.ScanEndTime = rescan.ScanTime.Add(time.Minute)
+
+	merged := MergeScanResults([]*ScanResult{rescan, first, second})
+
+	if merged.Owner != "org-b,org-a" {
+		t.Errorf("Expected owners in scan order, got %q", merged.Owner)
+	}
+	if !merged.ScanTime.Equal(second.ScanTime) || !merged.ScanEndTime.Equal(rescan.ScanEndTime) {
+		t.Errorf("Expected the scan to span %v to %v, got %v to %v", second.ScanTime, rescan.ScanEndTime, merged.ScanTime, merged.ScanEndTime)
+	}
+	if len(merged.Repositories) != 3 || merged.Repositories[0].FullName != "org-a/api" || merged.Repositories[2].FullName != "org-b/tools" {
+		t.Fatalf("Expected 3 sorted repositories, got %+v", merged.Repositories)
+	}
+	if len(merged.Repositories[1].Issues) != 0 {
+		t.Errorf("Expected the latest scan of org-a/web to be kept, got %+v", merged.Repositories[1].Issues)
+	}
+	if merged.Summary.TotalRepositories != 3 || merged.Summary.IssuesBySeverity["high"] != 1 {
+		t.Errorf("Expected the summary recalculated over merged repositories, got %+v", merged.Summary)
+	}
+	if len(merged.CreatedPRs) != 1 {
+		t.Errorf("Expected created PRs to be combined, got %+v", merged.CreatedPRs)
+	}
+
+	owners := merged.Summary.Owners
+	if len(owners) != 2 {
+		t.Fatalf("Expected 2 owners, got %+v", owners)
+	}
+	if owners[0].Owner != "org-b" || owners[0].Repositories != 1 || owners[0].Issues != 2 || owners[0].IssuesBySeverity["critical"] != 1 {
+		t.Errorf("Expected org-b with the most issues first, got %+v", owners[0])
+	}
+	if owners[1].Owner != "org-a" || owners[1].Repositories != 2 || owners[1].Issues != 1 {
+		t.Errorf("Expected org-a second, got %+v", owners[1])
+	}
+
+	var table strings.Builder
+	if err := FormatTable(merged, &table); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(table.String(), "OWNER") || !strings.Contains(table.String(), "org-b") {
+		t.Errorf("Expected an owner table, got:\n%s", table.String())
+	}
+}
+
+func TestMergeScanResults_Hosts(t *testing.T) {
+	cloud := BuildScanResult("acme", []RepositoryResult{{Name: "api", FullName: "acme/api", Host: "github.com"}})
+	enterprise := BuildScanResult("acme", []RepositoryResult{{Name: "api", FullName: "acme/api", Host: "ghe.example.com"}})
+	enterprise.ScanTime = cloud.ScanTime.Add(time.Minute)
+
+	merged := MergeScanResults([]*ScanResult{cloud, enterprise})
+	if len(merged.Repositories) != 2 {
+		t.Fatalf("Expected repositories of the same name on different hosts kept apart, got %+v", merged.Repositories)
+	}
+	if merged.Owner != "acme" || merged.Summary.TotalRepositories != 2 {
+		t.Errorf("Expected one owner with 2 repositories, got %q and %+v", merged.Owner, merged.Summary)
+	}
+}
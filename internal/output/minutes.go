@@ -0,0 +1,74 @@
+package output
+
+import (
+	"sort"
+)
+
+// MinutesConsumer is a workflow file with an Actions minutes estimate
+type MinutesConsumer struct {
+	Repository string
+	Workflow   string
+	Estimate   *MinutesEstimate
+}
+
+// RepositoryMinutes is the estimated monthly Actions usage of a repository's workflows
+type RepositoryMinutes struct {
+	Repository      string
+	Workflows       int
+	MonthlyMinutes  int
+	WeightedMinutes int
+}
+
+// TopMinutesConsumers returns workflow files with minutes estimates, highest weighted minutes first
+func TopMinutesConsumers(result *ScanResult) []MinutesConsumer {
+	var consumers []MinutesConsumer
+	for _, repo := range result.Repositories {
+		for _, file := range repo.WorkflowFiles {
+			if file.Minutes != nil {
+				consumers = append(consumers, MinutesConsumer{Repository: repo.FullName, Workflow: file.Path, Estimate: file.Minutes})
+			}
+		}
+	}
+
+	sort.SliceStable(consumers, func(i, j int) bool {
+		a, b := consumers[i].Estimate, consumers[j].Estimate
+		if a.WeightedMinutes != b.WeightedMinutes {
+			return a.WeightedMinutes > b.WeightedMinutes
+		}
+		if a.MonthlyMinutes != b.MonthlyMinutes {
+			return a.MonthlyMinutes > b.MonthlyMinutes
+		}
+		if consumers[i].Repository != consumers[j].Repository {
+			return consumers[i].Repository < consumers[j].Repository
+		}
+		return consumers[i].Workflow < consumers[j].Workflow
+	})
+	return consumers
+}
+
+// MinutesByRepository totals the minutes estimates of each repository, highest weighted minutes first
+func MinutesByRepository(result *ScanResult) []RepositoryMinutes {
+	var totals []RepositoryMinutes
+	for _, repo := range result.Repositories {
+		total := RepositoryMinutes{Repository: repo.FullName}
+		for _, file := range repo.WorkflowFiles {
+			if file.Minutes == nil {
+				continue
+			}
+			total.Workflows++
+			total.MonthlyMinutes += file.Minutes.MonthlyMinutes
+			total.WeightedMinutes += file.Minutes.WeightedMinutes
+		}
+		if total.Workflows > 0 {
+			totals = append(totals, total)
+		}
+	}
+
+	sort.SliceStable(totals, func(i, j int) bool {
+		if totals[i].WeightedMinutes != totals[j].WeightedMinutes {
+			return totals[i].WeightedMinutes > totals[j].WeightedMinutes
+		}
+		return totals[i].Repository < totals[j].Repository
+	})
+	return totals
+}
@@ -0,0 +1,98 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func minutesResult() *ScanResult {
+	return &ScanResult{
+		Owner: "my-org",
+		Repositories: []RepositoryResult{
+			{
+				FullName: "my-org/api",
+				WorkflowFiles: []WorkflowFileResult{
+					{Path: ".github/workflows/ci.yml", Minutes: &MinutesEstimate{Runs: 40, MonthlyMinutes: 300, WeightedMinutes: 300, ByRunner: map[string]int{"UBUNTU": 300}}},
+					{Path: ".github/workflows/release.yml", Minutes: &MinutesEstimate{Runs: 2, MonthlyMinutes: 50, WeightedMinutes: 500, ByRunner: map[string]int{"MACOS": 50}}},
+					{Path: ".github/workflows/docs.yml"},
+				},
+			},
+			{
+				FullName: "my-org/web",
+				WorkflowFiles: []WorkflowFileResult{
+					{Path: ".github/workflows/ci.yml", Minutes: &MinutesEstimate{Runs: 10, MonthlyMinutes: 100, WeightedMinutes: 200, ByRunner: map[string]int{"WINDOWS": 100}}},
+				},
+			},
+			{FullName: "my-org/empty"},
+		},
+	}
+}
+
+func TestTopMinutesConsumers(t *testing.T) {
+	consumers := TopMinutesConsumers(minutesResult())
+	if len(consumers) != 3 {
+		t.Fatalf("Expected 3 consumers, got %d", len(consumers))
+	}
+
+	// Weighted minutes rank the macOS release workflow above the busier Ubuntu CI
+	expected := []string{"my-org/api .github/workflows/release.yml", "my-org/api .github/workflows/ci.yml", "my-org/web .github/workflows/ci.yml"}
+	for i, consumer := range consumers {
+		if got := consumer.Repository + " " + consumer.Workflow; got != expected[i] {
+			t.Errorf("Consumer %d: expected %s, got %s", i, expected[i], got)
+		}
+	}
+}
+
+func TestMinutesByRepository(t *testing.T) {
+	totals := MinutesByRepository(minutesResult())
+	if len(totals) != 2 {
+		t.Fatalf("Expected 2 repositories with estimates, got %d", len(totals))
+	}
+	if totals[0].Repository != "my-org/api" || totals[0].Workflows != 2 || totals[0].MonthlyMinutes != 350 || totals[0].WeightedMinutes != 800 {
+		t.Errorf("Unexpected totals for my-org/api: %+v", totals[0])
+	}
+	if totals[1].Repository != "my-org/web" || totals[1].WeightedMinutes != 200 {
+		t.Errorf("Unexpected totals for my-org/web: %+v", totals[1])
+	}
+}
+
+func TestFormatTable_ListsMinutesConsumers(t *testing.T) {
+	var buf bytes.Buffer
+	if err := FormatTable(minutesResult(), &buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	table := buf.String()
+	if !strings.Contains(table, "TOP MINUTES CONSUMERS") {
+		t.Fatalf("Expected a minutes section, got:\n%s", table)
+	}
+	lines := strings.Split(table[strings.Index(table, "TOP MINUTES CONSUMERS"):], "\n")
+	if fields := strings.Fields(lines[1]); len(fields) != 4 || fields[0] != "my-org/api/.github/workflows/release.yml" || fields[3] != "500" {
+		t.Errorf("Expected release.yml first with 500 weighted minutes, got %q", lines[1])
+	}
+
+	buf.Reset()
+	if err := FormatTable(&ScanResult{Owner: "my-org"}, &buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(buf.String(), "MINUTES") {
+		t.Errorf("Expected no minutes section without estimates, got:\n%s", buf.String())
+	}
+}
+
+func TestCreateActionsMinutesCell(t *testing.T) {
+	result := minutesResult()
+	cell := createActionsMinutesCell(result, TopMinutesConsumers(result))
+	content := strings.Join(cell.Source, "")
+
+	for _, expected := range []string{
+		"Estimated **450** billable minutes per month (**1000** weighted",
+		"| my-org/api | 2 | 350 | 800 |",
+		"| my-org/api | `.github/workflows/release.yml` | 2 | 50 | 500 | MACOS 50 |",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("Expected %q in:\n%s", expected, content)
+		}
+	}
+}
@@ -35,7 +35,15 @@ type JupyterNotebook struct {
 
 // FormatNotebook outputs the scan results as a Jupyter notebook
 func FormatNotebook(result *ScanResult, writer io.Writer) error {
-	notebook := createNotebook(result)
+	return FormatNotebookWithTemplates(result, writer, nil)
+}
+
+// FormatNotebookWithTemplates outputs the scan results as a Jupyter notebook, applying section template overrides
+func FormatNotebookWithTemplates(result *ScanResult, writer io.Writer, templates *ReportTemplates) error {
+	notebook, err := createNotebook(result, templates)
+	if err != nil {
+		return err
+	}
 
 	data, err := json.MarshalIndent(notebook, "", "  ")
 	if err != nil {
@@ -50,8 +58,14 @@ func FormatNotebook(result *ScanResult, writer io.Writer) error {
 	return nil
 }
 
+// notebookSection pairs a report section name with its built-in cell
+type notebookSection struct {
+	name string
+	cell NotebookCell
+}
+
 // createNotebook constructs a Jupyter notebook from scan results
-func createNotebook(result *ScanResult) *JupyterNotebook {
+func createNotebook(result *ScanResult, templates *ReportTemplates) (*JupyterNotebook, error) {
 	notebook := &JupyterNotebook{
 		NBFormat:      4,
 		NBFormatMinor: 4,
@@ -63,24 +77,113 @@ func createNotebook(result *ScanResult) *JupyterNotebook {
 	notebook.Metadata.KernelSpec.Name = "python3"
 	notebook.Metadata.LanguageInfo.Name = "python"
 
-	// Build cells
-	cells := []NotebookCell{
-		createHeaderCell(result),
-		createSummaryCell(result),
-		createIssuesOverviewCell(result),
-		createRepositoryDetailsCell(result),
+	// Build sections
+	sections := []notebookSection{
+		{SectionHeader, createHeaderCell(result)},
+		{SectionSummary, createSummaryCell(result)},
+		{SectionIssuesOverview, createIssuesOverviewCell(result)},
+	}
+
+	// Add the priority ranking if issues were scored
+	if priorities := TopPriorityIssues(result, DefaultPriorityLimit); len(priorities) > 0 {
+		sections = append(sections, notebookSection{SectionPriorities, createPrioritiesCell(priorities)})
+	}
+	sections = append(sections, notebookSection{SectionRepositoryDetails, createRepositoryDetailsCell(result)})
+
+	// Add suppressed issues section if any issues were snoozed
+	if result.Summary.TotalSuppressedIssues > 0 {
+		sections = append(sections, notebookSection{SectionSuppressedIssues, createSuppressedIssuesCell(result)})
+	}
+
+	// Add reusable workflow recommendations if duplicate detection found candidates
+	if len(result.ReusableWorkflowCandidates) > 0 {
+		sections = append(sections, notebookSection{SectionReusableWorkflows, createReusableWorkflowsCell(result)})
+	}
+
+	// Add tag protection findings if internal actions have unprotected release tags
+	if len(result.TagProtectionFindings) > 0 {
+		sections = append(sections, notebookSection{SectionTagProtection, createTagProtectionCell(result)})
+	}
+
+	// Add lagging major tags if major tags were checked
+	if len(result.MajorTagFindings) > 0 {
+		sections = append(sections, notebookSection{SectionMajorTags, createMajorTagsCell(result)})
+	}
+
+	// Add the internal action consumer report if internal actions were reported
+	if len(result.InternalActions) > 0 {
+		sections = append(sections, notebookSection{SectionInternalActions, createInternalActionsCell(result)})
+	}
+
+	// Add the run history heatmap if workflow usage was collected
+	if hasWorkflowUsage(result) {
+		sections = append(sections, notebookSection{SectionWorkflowUsage, createWorkflowUsageCell(result)})
+	}
+
+	// Add the top Actions minutes consumers if minutes were estimated
+	if consumers := TopMinutesConsumers(result); len(consumers) > 0 {
+		sections = append(sections, notebookSection{SectionActionsMinutes, createActionsMinutesCell(result, consumers)})
+	}
+
+	// Add the secret data flow if secrets and variables reach third-party actions
+	if exposures := ThirdPartySecretExposure(result); len(exposures) > 0 {
+		sections = append(sections, notebookSection{SectionSecretFlows, createSecretFlowsCell(exposures)})
+	}
+
+	// Add the container image inventory if jobs use container or service images
+	if images := ImageInventory(result); len(images) > 0 {
+		sections = append(sections, notebookSection{SectionContainerImages, createContainerImagesCell(images)})
+	}
+
+	// Add the deployment inventory if jobs deploy to environments
+	if deployments := DeploymentInventory(result); len(deployments) > 0 {
+		sections = append(sections, notebookSection{SectionEnvironments, createEnvironmentsCell(deployments)})
+	}
+
+	// Add the toolchain setup report if languages of several repositories are known
+	if languages := SetupConsistency(result); len(languages) > 0 {
+		sections = append(sections, notebookSection{SectionSetupConsistency, createSetupConsistencyCell(languages)})
 	}
 
 	// Add PR links section if PRs were created
 	if len(result.CreatedPRs) > 0 {
-		cells = append(cells, createPRLinksCell(result))
+		sections = append(sections, notebookSection{SectionPRLinks, createPRLinksCell(result)})
 	}
 
 	// Add detailed statistics
-	cells = append(cells, createDetailedStatsCell(result))
+	sections = append(sections, notebookSection{SectionDetailedStats, createDetailedStatsCell(result)})
+
+	var cells []NotebookCell
+	for _, section := range sections {
+		cell, include, err := templates.render(section.name, result, section.cell)
+		if err != nil {
+			return nil, err
+		}
+		if include {
+			cells = append(cells, cell)
+		}
+	}
+
+	// Add the pandas analysis cells over the embedded issues if any were found
+	analysis, err := createAnalysisCells(result)
+	if err != nil {
+		return nil, err
+	}
+	cells = append(cells, analysis...)
+
+	// Footer has no built-in content and only appears when templated
+	if templates.has(SectionFooter) {
+		cell, include, err := templates.render(SectionFooter, result, NotebookCell{CellType: "markdown"})
+		if err != nil {
+			return nil, err
+		}
+		if include {
+			cells = append(cells, cell)
+		}
+	}
 
 	notebook.Cells = cells
-	return notebook
+	return notebook, nil
 }
 
 // createHeaderCell creates the main header with scan metadata
@@ -107,6 +210,27 @@ func createHeaderCell(result *ScanResult) NotebookCell {
 		"\n",
 		"## 🎯 Executive Summary\n",
 		"\n",
+	}
+
+	// Announced removals lead the summary, as they break workflows on a fixed date
+	if len(result.Summary.Deadlines) > 0 {
+		source = append(source, "### 🚨 Upcoming Deadlines\n", "\n")
+		for _, deadline := range result.Summary.Deadlines {
+			source = append(source, deadlineLine(deadline, result.ScanTime))
+		}
+		source = append(source, "\n")
+	}
+
+	// Broken references fail every run that reaches them, so they are listed before the statistics
+	if len(result.Summary.BrokenReferences) > 0 {
+		source = append(source, "### ❌ Broken References\n", "\n")
+		for _, reference := range result.Summary.BrokenReferences {
+			source = append(source, brokenReferenceLine(reference))
+		}
+		source = append(source, "\n")
+	}
+
+	source = append(source,
 		fmt.Sprintf("- **%d** repositories scanned\n", result.Summary.TotalRepositories),
 		fmt.Sprintf("- **%d** workflow files analyzed\n", result.Summary.TotalWorkflowFiles),
 		fmt.Sprintf("- **%d** actions found across all workflows\n", result.Summary.TotalActions),
@@ -115,6 +239,21 @@ func createHeaderCell(result *ScanResult) NotebookCell {
 		fmt.Sprintf("- **%d** unique action types identified\n", len(result.Summary.UniqueActions)),
 		fmt.Sprintf("  - **%d** unique regular actions\n", len(result.Summary.UniqueRegularActions)),
 		fmt.Sprintf("  - **%d** unique reusable workflows\n", len(result.Summary.UniqueReusableWorkflows)),
+	)
+
+	if result.Summary.SkippedWorkflowFiles > 0 {
+		source = append(source, fmt.Sprintf("- ⚠️ **%d** workflow files skipped (too large or too complex to parse safely, or invalid)\n", result.Summary.SkippedWorkflowFiles))
+	}
+	unscanned := result.Summary.UnscannedRepositories
+	if total := unscanned[RepositoryStatusEmpty] + unscanned[RepositoryStatusSubmodule] + unscanned[RepositoryStatusNoWorkflows]; total > 0 {
+		source = append(source, fmt.Sprintf("- **%d** repositories without workflows (%d empty, %d with a submodule `.github`, %d with no workflow files) excluded from the statistics\n",
+			total, unscanned[RepositoryStatusEmpty], unscanned[RepositoryStatusSubmodule], unscanned[RepositoryStatusNoWorkflows]))
+	}
+	if overBudget := unscanned[RepositoryStatusOverBudget]; overBudget > 0 {
+		source = append(source, fmt.Sprintf("- ⚠️ **%d** repositories not scanned because the scan budget ran out; results are partial\n", overBudget))
+	}
+	if failed := unscanned[RepositoryStatusFailed]; failed > 0 {
+		source = append(source, fmt.Sprintf("- ⚠️ **%d** repositories not scanned because their workflow files could not be fetched; results are partial\n", failed))
 	}
 
 	// Add issue summary
@@ -128,12 +267,47 @@ func createHeaderCell(result *ScanResult) NotebookCell {
 	} else {
 		source = append(source, "- ✅ **No issues found** - all actions are up to date!\n")
 	}
+	if templateIssues := countWorkflowTemplateIssues(result); templateIssues > 0 {
+		source = append(source, fmt.Sprintf("- ⚠️ **%d** issues in organization workflow templates, which every new repository's workflows are created from\n", templateIssues))
+	}
+
+	// Pinning style and version freshness for leadership reporting
+	if pinning := result.Summary.Pinning; pinning != nil {
+		source = append(source, fmt.Sprintf("- 📌 **%.0f%%** of references pinned to a commit SHA, **%.0f%%** to an exact tag, **%.0f%%** to a major tag, **%.0f%%** to a branch\n",
+			pinning.Percent(PinStyleSHA), pinning.Percent(PinStyleExactTag), pinning.Percent(PinStyleMajorTag), pinning.Percent(PinStyleBranch)))
+	}
+	if freshness := result.Summary.Freshness; freshness != nil {
+		source = append(source, fmt.Sprintf("- ⏳ Outdated references are a median of **%g** major versions behind (max **%d**, across %d references)\n",
+			freshness.MedianMajorVersionsBehind, freshness.MaxMajorVersionsBehind, freshness.Measured))
+	}
+	if tokens := result.Summary.Tokens; tokens != nil {
+		source = append(source, fmt.Sprintf("- 🔐 **%.0f%%** of workflows set explicit token permissions (%d of %d); **%d** use `GITHUB_TOKEN`, **%d** use personal access token secrets\n",
+			tokens.Percent(), tokens.ExplicitPermissions, tokens.Workflows, tokens.GitHubToken, tokens.PATSecrets))
+	}
+	if debt := result.Summary.Debt; debt != nil {
+		source = append(source, debtLine(debt, result.Summary.SeverityHistory))
+	}
 
 	// Add PR summary if any were created
 	if len(result.CreatedPRs) > 0 {
 		source = append(source, fmt.Sprintf("- **%d** pull requests created for automated fixes\n", len(result.CreatedPRs)))
 	}
 
+	// Merged reports break the totals down by owner
+	if len(result.Summary.Owners) > 1 {
+		source = append(source,
+			"\n",
+			"### 🏢 By Owner\n",
+			"\n",
+			"| Owner | Repositories | Issues | Critical | High | Medium | Low |\n",
+			"|-------|--------------|--------|----------|------|--------|-----|\n",
+		)
+		for _, owner := range result.Summary.Owners {
+			source = append(source, fmt.Sprintf("| `%s` | %d | %d | %d | %d | %d | %d |\n", owner.Owner, owner.Repositories, owner.Issues,
+				owner.IssuesBySeverity["critical"], owner.IssuesBySeverity["high"], owner.IssuesBySeverity["medium"], owner.IssuesBySeverity["low"]))
+		}
+	}
+
 	return NotebookCell{
 		CellType: "markdown",
 		Source:   source,
@@ -158,7 +332,15 @@ func createSummaryCell(result *ScanResult) NotebookCell {
 			totalIssues += count
 		}
 
-		for issueType, count := range result.Summary.IssuesByType {
+		// Iterate issue types in sorted order so the report is stable between runs
+		issueTypes := make([]string, 0, len(result.Summary.IssuesByType))
+		for issueType := range result.Summary.IssuesByType {
+			issueTypes = append(issueTypes, issueType)
+		}
+		sort.Strings(issueTypes)
+
+		for _, issueType := range issueTypes {
+			count := result.Summary.IssuesByType[issueType]
 			percentage := float64(count) / float64(totalIssues) * 100
 			source = append(source, fmt.Sprintf("| %s | %d | %.1f%% |\n", issueType, count, percentage))
 		}
@@ -189,12 +371,101 @@ func createSummaryCell(result *ScanResult) NotebookCell {
 		source = append(source, "\n")
 	}
 
+	if result.Summary.ExistingIssues > 0 {
+		source = append(source, fmt.Sprintf("%d of these issues were already present in the baseline scan and are marked _(existing)_.\n", result.Summary.ExistingIssues))
+	}
+
+	// Severity counts of earlier scans, carried forward through --baseline
+	if len(result.Summary.SeverityHistory) > 0 {
+		source = append(source, "\n")
+		source = append(source, createSeverityTrendLines(result)...)
+	}
+
+	// Time-to-merge of actions-maintainer pull requests, measured with --team-property
+	if len(result.Summary.MergeLatency) > 0 {
+		source = append(source, "\n")
+		source = append(source, createMergeLatencyLines(result)...)
+	}
+
 	return NotebookCell{
 		CellType: "markdown",
 		Source:   source,
 	}
 }
 
+// debtLine renders the debt score with its target and the score of the previous scan, if known
+func debtLine(debt *DebtSummary, history []SeveritySnapshot) string {
+	line := fmt.Sprintf("- 📉 Actions debt score **%g** (severity-weighted issues per repository)", debt.Score)
+	if debt.Target != nil {
+		status := "✅ on target"
+		if !debt.OnTarget {
+			status = "❌ above target"
+		}
+		name := ""
+		if debt.TargetName != "" {
+			name = " for " + debt.TargetName
+		}
+		line += fmt.Sprintf("; target **%g**%s, %s", *debt.Target, name, status)
+	}
+	if len(history) > 0 && history[len(history)-1].DebtScore > 0 {
+		line += fmt.Sprintf("; previous scan %g", history[len(history)-1].DebtScore)
+	}
+	return line + "\n"
+}
+
+// createSeverityTrendLines renders issue counts by severity for previous scans followed by this scan
+func createSeverityTrendLines(result *ScanResult) []string {
+	lines := []string{
+		"### Severity Trend\n",
+		"| Scan | Critical | High | Medium | Low |\n",
+		"|------|----------|------|--------|-----|\n",
+	}
+
+	row := func(label string, counts map[string]int) string {
+		return fmt.Sprintf("| %s | %d | %d | %d | %d |\n", label, counts["critical"], counts["high"], counts["medium"], counts["low"])
+	}
+	for _, snapshot := range result.Summary.SeverityHistory {
+		lines = append(lines, row(snapshot.ScanTime.Format("2006-01-02"), snapshot.IssuesBySeverity))
+	}
+	lines = append(lines, row(fmt.Sprintf("%s (this scan)", result.ScanTime.Format("2006-01-02")), result.Summary.IssuesBySeverity))
+
+	return append(lines, "\n")
+}
+
+// createMergeLatencyLines renders each team's time-to-merge, next to the team's median in the previous
+// scan that measured it, so teams that need nudging stand out
+func createMergeLatencyLines(result *ScanResult) []string {
+	lines := []string{
+		"### Merge Latency by Team\n",
+		"| Team | Pull Requests | Merged | Open | Median Time to Merge | Previous Scan |\n",
+		"|------|---------------|--------|------|----------------------|---------------|\n",
+	}
+
+	previous := make(map[string]TeamMergeLatency)
+	for i := len(result.Summary.SeverityHistory) - 1; i >= 0; i-- {
+		if latencies := result.Summary.SeverityHistory[i].MergeLatency; len(latencies) > 0 {
+			for _, latency := range latencies {
+				previous[latency.Team] = latency
+			}
+			break
+		}
+	}
+
+	hours := func(latency TeamMergeLatency, ok bool) string {
+		if !ok || latency.Merged == 0 {
+			return "n/a"
+		}
+		return fmt.Sprintf("%.1f h", latency.MedianHoursToMerge)
+	}
+	for _, latency := range result.Summary.MergeLatency {
+		before, ok := previous[latency.Team]
+		lines = append(lines, fmt.Sprintf("| %s | %d | %d | %d | %s | %s |\n",
+			latency.Team, latency.PullRequests, latency.Merged, latency.Open, hours(latency, true), hours(before, ok)))
+	}
+
+	return append(lines, "\n")
+}
+
 // createIssuesOverviewCell creates an overview of the most critical issues
 func createIssuesOverviewCell(result *ScanResult) NotebookCell {
 	source := []string{
@@ -237,6 +508,25 @@ func createIssuesOverviewCell(result *ScanResult) NotebookCell {
 	}
 }
 
+// countWorkflowTemplateIssues counts the issues found in organization workflow templates
+func countWorkflowTemplateIssues(result *ScanResult) int {
+	count := 0
+	for _, repo := range result.Repositories {
+		templates := make(map[string]bool)
+		for _, wf := range repo.WorkflowFiles {
+			if wf.Template {
+				templates[wf.Path] = true
+			}
+		}
+		for _, issue := range repo.Issues {
+			if templates[issue.FilePath] {
+				count++
+			}
+		}
+	}
+	return count
+}
+
 // createRepositoryDetailsCell creates detailed repository information
 func createRepositoryDetailsCell(result *ScanResult) NotebookCell {
 	source := []string{
@@ -294,6 +584,9 @@ func createRepositoryDetailsCell(result *ScanResult) NotebookCell {
 	}
 
 	for _, repo := range result.Repositories {
+		if !repo.Scanned() {
+			continue
+		}
 		issueCount := len(repo.Issues)
 		issueDisplay := fmt.Sprintf("%d", issueCount)
 		if issueCount > 0 {
@@ -346,19 +639,44 @@ func createRepositoryDetailsCell(result *ScanResult) NotebookCell {
 				source = append(source, "\n")
 			}
 
+			if len(repo.IssueGroups) > 0 {
+				source = append(source, createIssueGroupLines(repo.IssueGroups)...)
+				source = append(source, "\n")
+				continue
+			}
+
 			// Group issues by file
 			fileIssues := make(map[string][]ActionIssue)
+			var filePaths []string
 			for _, issue := range repo.Issues {
+				if _, exists := fileIssues[issue.FilePath]; !exists {
+					filePaths = append(filePaths, issue.FilePath)
+				}
 				fileIssues[issue.FilePath] = append(fileIssues[issue.FilePath], issue)
 			}
+			sort.Strings(filePaths)
 
-			for filePath, issues := range fileIssues {
+			for _, filePath := range filePaths {
+				issues := fileIssues[filePath]
 				source = append(source, fmt.Sprintf("**File:** `%s`\n", filePath))
 				source = append(source, "\n")
 
 				for _, issue := range issues {
-					source = append(source, fmt.Sprintf("- **%s**: %s → %s (%s)\n",
-						issue.Repository, issue.CurrentVersion, issue.SuggestedVersion, issue.IssueType))
+					line := fmt.Sprintf("- **%s**: %s → %s (%s)", issue.Repository, issue.CurrentVersion, issue.SuggestedVersion, issue.IssueType)
+					if issue.CurrentVersion == "" && issue.SuggestedVersion == "" {
+						// Workflow-level findings such as risky triggers have no versions
+						line = fmt.Sprintf("- **%s**: %s (%s)", issue.Repository, issue.Description, issue.IssueType)
+					}
+					if issue.DaysBehind > 0 {
+						line += fmt.Sprintf(" — %d days behind", issue.DaysBehind)
+					}
+					if issue.Consumers > 0 {
+						line += fmt.Sprintf(" — called by %d repositories", issue.Consumers)
+					}
+					if issue.Existing {
+						line += " _(existing)_"
+					}
+					source = append(source, line+"\n")
 				}
 				source = append(source, "\n")
 			}
@@ -371,6 +689,42 @@ func createRepositoryDetailsCell(result *ScanResult) NotebookCell {
 	}
 }
 
+// maxGroupFilesShown caps the files listed per issue group before summarizing the rest
+const maxGroupFilesShown = 5
+
+// createIssueGroupLines renders one line per issue group with the files it affects
+func createIssueGroupLines(groups []IssueGroup) []string {
+	var lines []string
+	for _, group := range groups {
+		line := fmt.Sprintf("- **%s**: %s → %s (%s)", group.Repository, group.CurrentVersion, group.SuggestedVersion, group.IssueType)
+		if group.CurrentVersion == "" && group.SuggestedVersion == "" {
+			line = fmt.Sprintf("- **%s**: %s (%s)", group.Repository, group.Description, group.IssueType)
+		}
+		if group.Existing {
+			line += " _(existing)_"
+		}
+
+		shown := group.Files
+		if len(shown) > maxGroupFilesShown {
+			shown = shown[:maxGroupFilesShown]
+		}
+		var files []string
+		for _, file := range shown {
+			files = append(files, fmt.Sprintf("`%s`", file))
+		}
+		noun := "files"
+		if len(group.Files) == 1 {
+			noun = "file"
+		}
+		line += fmt.Sprintf(" — %d %s: %s", len(group.Files), noun, strings.Join(files, ", "))
+		if hidden := len(group.Files) - len(shown); hidden > 0 {
+			line += fmt.Sprintf(" and %d more", hidden)
+		}
+		lines = append(lines, line+"\n")
+	}
+	return lines
+}
+
 // createPRLinksCell creates a section with links to created PRs
 func createPRLinksCell(result *ScanResult) NotebookCell {
 	source := []string{
@@ -408,6 +762,506 @@ func createPRLinksCell(result *ScanResult) NotebookCell {
 	}
 }
 
+// createSuppressedIssuesCell lists issues hidden by suppressions so accepted risk stays visible
+func createSuppressedIssuesCell(result *ScanResult) NotebookCell {
+	source := []string{
+		"## 🔕 Suppressed Issues\n",
+		"\n",
+		fmt.Sprintf("The following %d issues were suppressed and are excluded from the statistics above:\n", result.Summary.TotalSuppressedIssues),
+		"\n",
+		"| Repository | File | Action | Version | Type | Reason | Until |\n",
+		"|------------|------|--------|---------|------|--------|-------|\n",
+	}
+
+	for _, repo := range result.Repositories {
+		for _, issue := range repo.SuppressedIssues {
+			until := issue.Until
+			if until == "" {
+				until = "version change"
+			}
+			source = append(source, fmt.Sprintf("| `%s` | `%s` | `%s` | `%s` | %s | %s | %s |\n",
+				repo.FullName, issue.FilePath, issue.Repository, issue.CurrentVersion, issue.IssueType, issue.Reason, until))
+		}
+	}
+
+	return NotebookCell{
+		CellType: "markdown",
+		Source:   source,
+	}
+}
+
+// createReusableWorkflowsCell lists step sequences repeated across repositories
+func createReusableWorkflowsCell(result *ScanResult) NotebookCell {
+	source := []string{
+		"## ♻️ Reusable Workflow Candidates\n",
+		"\n",
+		fmt.Sprintf("The following %d step sequences are repeated across repositories. Consider extracting each into a shared reusable workflow.\n", len(result.ReusableWorkflowCandidates)),
+		"\n",
+	}
+
+	for _, cluster := range result.ReusableWorkflowCandidates {
+		source = append(source, fmt.Sprintf("### `%s`: %d steps in %d repositories\n", cluster.Fingerprint, len(cluster.Steps), len(cluster.Repositories)))
+		source = append(source, "\n")
+		source = append(source, "```\n")
+		for i, step := range cluster.Steps {
+			source = append(source, fmt.Sprintf("%d. %s\n", i+1, step))
+		}
+		source = append(source, "```\n")
+		source = append(source, "\n")
+		source = append(source, "**Found in:**\n")
+		for _, occurrence := range cluster.Occurrences {
+			source = append(source, fmt.Sprintf("- `%s` `%s` (job `%s`)\n", occurrence.Repository, occurrence.FilePath, occurrence.Job))
+		}
+		source = append(source, "\n")
+	}
+
+	return NotebookCell{
+		CellType: "markdown",
+		Source:   source,
+	}
+}
+
+// createTagProtectionCell lists widely used internal actions whose release tags can be moved or deleted
+func createTagProtectionCell(result *ScanResult) NotebookCell {
+	source := []string{
+		"## 🏷️ Tag Protection\n",
+		"\n",
+		fmt.Sprintf("The following %d internal actions are used across repositories, but the tags consumers pin to are not protected by a tag ruleset. Anyone with write access could move or delete them.\n", len(result.TagProtectionFindings)),
+		"\n",
+		"| Action | Consumers | Unprotected Tags | Severity |\n",
+		"|--------|-----------|------------------|----------|\n",
+	}
+
+	for _, finding := range result.TagProtectionFindings {
+		source = append(source, fmt.Sprintf("| `%s` | %d | `%s` | %s |\n",
+			finding.Repository, len(finding.Consumers), strings.Join(finding.UnprotectedTags, "`, `"), strings.ToUpper(finding.Severity)))
+	}
+
+	return NotebookCell{
+		CellType: "markdown",
+		Source:   source,
+	}
+}
+
+// createMajorTagsCell lists internal actions whose major tag was not moved to their newest release
+func createMajorTagsCell(result *ScanResult) NotebookCell {
+	source := []string{
+		"## 🔖 Major Tags\n",
+		"\n",
+		fmt.Sprintf("The following %d major tags of internal actions do not point at the newest release of their major version. Consumers pinned to them are missing its fixes until the tag is moved.\n", len(result.MajorTagFindings)),
+		"\n",
+		"| Action | Major Tag | Points At | Newest Release | Pinned Consumers | Severity |\n",
+		"|--------|-----------|-----------|----------------|------------------|----------|\n",
+	}
+
+	for _, finding := range result.MajorTagFindings {
+		pointsAt := "no release"
+		if finding.PointsAt != "" {
+			pointsAt = "`" + finding.PointsAt + "`"
+		}
+		source = append(source, fmt.Sprintf("| `%s` | `%s` | %s | `%s` | %d | %s |\n",
+			finding.Repository, finding.MajorTag, pointsAt, finding.LatestRelease, len(finding.Consumers), strings.ToUpper(finding.Severity)))
+	}
+
+	return NotebookCell{
+		CellType: "markdown",
+		Source:   source,
+	}
+}
+
+// createInternalActionsCell shows the owners of internal actions who depends on them and how far behind
+// their consumers are
+func createInternalActionsCell(result *ScanResult) NotebookCell {
+	source := []string{
+		"## 🧩 Internal Actions\n",
+		"\n",
+		fmt.Sprintf("The following %d actions are hosted by the scanned owners. Consumers behind are those on an older major version than the latest release.\n", len(result.InternalActions)),
+		"\n",
+		"| Action | Consumers | Behind | Versions | Open Issues | Open PRs | Latest Release |\n",
+		"|--------|-----------|--------|----------|-------------|----------|----------------|\n",
+	}
+
+	for _, action := range result.InternalActions {
+		versions := make([]string, 0, len(action.Versions))
+		for version := range action.Versions {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool {
+			if action.Versions[versions[i]] != action.Versions[versions[j]] {
+				return action.Versions[versions[i]] > action.Versions[versions[j]]
+			}
+			return versions[i] < versions[j]
+		})
+		spread := make([]string, 0, len(versions))
+		for _, version := range versions {
+			spread = append(spread, fmt.Sprintf("`%s` (%d)", version, action.Versions[version]))
+		}
+
+		release := "-"
+		if action.LatestRelease != "" {
+			release = fmt.Sprintf("`%s` (%s)", action.LatestRelease, action.LatestReleaseDate.Format("2006-01-02"))
+		}
+		if action.Archived {
+			release += " 📦 archived"
+		}
+		source = append(source, fmt.Sprintf("| `%s` | %d | %d | %s | %d | %d | %s |\n",
+			action.Repository, len(action.Consumers), len(action.StaleConsumers), strings.Join(spread, ", "),
+			action.OpenIssues, action.OpenPullRequests, release))
+	}
+
+	return NotebookCell{
+		CellType: "markdown",
+		Source:   source,
+	}
+}
+
+// hasWorkflowUsage reports whether any workflow file has run history
+func hasWorkflowUsage(result *ScanResult) bool {
+	for _, repo := range result.Repositories {
+		for _, file := range repo.WorkflowFiles {
+			if file.Usage != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// createWorkflowUsageCell creates a heatmap of workflow runs per month
+func createWorkflowUsageCell(result *ScanResult) NotebookCell {
+	source := []string{
+		"## 🗓️ Workflow Usage\n",
+		"\n",
+	}
+
+	// Columns cover every month seen across all workflows
+	monthSet := make(map[string]bool)
+	for _, repo := range result.Repositories {
+		for _, file := range repo.WorkflowFiles {
+			if file.Usage == nil {
+				continue
+			}
+			for month := range file.Usage.RunsByMonth {
+				monthSet[month] = true
+			}
+		}
+	}
+	months := make([]string, 0, len(monthSet))
+	for month := range monthSet {
+		months = append(months, month)
+	}
+	sort.Strings(months)
+
+	source = append(source, "Runs per month for each workflow file. Workflows marked 💤 did not run within the usage window.\n")
+	source = append(source, "\n")
+
+	header := "| Repository | Workflow |"
+	divider := "|------------|----------|"
+	for _, month := range months {
+		header += fmt.Sprintf(" %s |", month)
+		divider += "---------|"
+	}
+	source = append(source, header+" Total |\n")
+	source = append(source, divider+"-------|\n")
+
+	for _, repo := range result.Repositories {
+		for _, file := range repo.WorkflowFiles {
+			if file.Usage == nil {
+				continue
+			}
+			name := fmt.Sprintf("`%s`", file.Path)
+			if file.Usage.Stale() {
+				name += " 💤"
+			}
+			row := fmt.Sprintf("| %s | %s |", repo.FullName, name)
+			for _, month := range months {
+				if count := file.Usage.RunsByMonth[month]; count > 0 {
+					row += fmt.Sprintf(" %d |", count)
+				} else {
+					row += " · |"
+				}
+			}
+			source = append(source, row+fmt.Sprintf(" %d |\n", file.Usage.TotalRuns))
+		}
+	}
+
+	return NotebookCell{
+		CellType: "markdown",
+		Source:   source,
+	}
+}
+
+// maxNotebookMinutesConsumers caps the workflow rows of the Actions minutes section
+const maxNotebookMinutesConsumers = 10
+
+// createActionsMinutesCell creates a summary of estimated monthly Actions minutes per repository and workflow
+func createActionsMinutesCell(result *ScanResult, consumers []MinutesConsumer) NotebookCell {
+	repositories := MinutesByRepository(result)
+	totalMinutes, totalWeighted := 0, 0
+	for _, repo := range repositories {
+		totalMinutes += repo.MonthlyMinutes
+		totalWeighted += repo.WeightedMinutes
+	}
+
+	source := []string{
+		"## ⏱️ Actions Minutes\n",
+		"\n",
+		fmt.Sprintf("Estimated **%d** billable minutes per month (**%d** weighted by runner multipliers: Windows 2x, macOS 10x), ", totalMinutes, totalWeighted),
+		"extrapolated from the billable time of recent runs. Public repositories and self-hosted runners are not billed.\n",
+		"\n",
+		"### By Repository\n",
+		"\n",
+		"| Repository | Workflows | Monthly Minutes | Weighted Minutes |\n",
+		"|------------|-----------|-----------------|------------------|\n",
+	}
+	for _, repo := range repositories {
+		source = append(source, fmt.Sprintf("| %s | %d | %d | %d |\n", repo.Repository, repo.Workflows, repo.MonthlyMinutes, repo.WeightedMinutes))
+	}
+
+	source = append(source,
+		"\n",
+		"### Top Workflows\n",
+		"\n",
+		"| Repository | Workflow | Runs | Monthly Minutes | Weighted Minutes | Runners |\n",
+		"|------------|----------|------|-----------------|------------------|---------|\n",
+	)
+	for i, consumer := range consumers {
+		if i == maxNotebookMinutesConsumers {
+			break
+		}
+		runners := make([]string, 0, len(consumer.Estimate.ByRunner))
+		for runner, minutes := range consumer.Estimate.ByRunner {
+			runners = append(runners, fmt.Sprintf("%s %d", runner, minutes))
+		}
+		sort.Strings(runners)
+		source = append(source, fmt.Sprintf("| %s | `%s` | %d | %d | %d | %s |\n",
+			consumer.Repository, consumer.Workflow, consumer.Estimate.Runs, consumer.Estimate.MonthlyMinutes,
+			consumer.Estimate.WeightedMinutes, strings.Join(runners, ", ")))
+	}
+
+	return NotebookCell{
+		CellType: "markdown",
+		Source:   source,
+	}
+}
+
+// createSecretFlowsCell creates a data-flow report of the secrets and variables third-party actions receive
+func createSecretFlowsCell(exposures []SecretExposure) NotebookCell {
+	repositories := make(map[string]bool)
+	for _, exposure := range exposures {
+		for _, repository := range exposure.Repositories {
+			repositories[repository] = true
+		}
+	}
+
+	source := []string{
+		"## 🔐 Secret Data Flow\n",
+		"\n",
+		fmt.Sprintf("**%d** third-party actions receive secrets or variables from **%d** repositories. ", len(exposures), len(repositories)),
+		"Each of these actions can read the values it is given; pin them to a commit SHA and review what they are trusted with.\n",
+		"\n",
+		"| Action | Secrets | Variables | Repositories |\n",
+		"|--------|---------|-----------|--------------|\n",
+	}
+	for _, exposure := range exposures {
+		source = append(source, fmt.Sprintf("| `%s` | %s | %s | %d |\n", exposure.Action,
+			codeList(exposure.Secrets), codeList(exposure.Variables), len(exposure.Repositories)))
+	}
+
+	source = append(source,
+		"\n",
+		"### Flows\n",
+		"\n",
+		"| Repository | Workflow | Job / Step | Action | Via | Value |\n",
+		"|------------|----------|------------|--------|-----|-------|\n",
+	)
+	for _, exposure := range exposures {
+		for _, flow := range exposure.Flows {
+			location := flow.Job
+			if flow.Step != "" {
+				location += " / " + flow.Step
+			}
+			source = append(source, fmt.Sprintf("| %s | `%s` | %s | `%s` | %s `%s` | %s `%s` |\n",
+				flow.Repository, flow.FilePath, location, flow.Action, flow.Via, flow.Input, flow.Kind, flow.Name))
+		}
+	}
+
+	return NotebookCell{
+		CellType: "markdown",
+		Source:   source,
+	}
+}
+
+// createContainerImagesCell creates an inventory of job container, service, and docker:// step images
+func createContainerImagesCell(images []ImageUsage) NotebookCell {
+	checked, missing := 0, 0
+	for _, image := range images {
+		if image.Created != nil || image.Missing {
+			checked++
+		}
+		if image.Missing {
+			missing++
+		}
+	}
+
+	source := []string{
+		"## 🐳 Container Images\n",
+		"\n",
+		fmt.Sprintf("**%d** images are used by job containers, service containers, and docker:// steps.", len(images)),
+	}
+	if checked > 0 {
+		source = append(source, fmt.Sprintf(" **%d** were checked against their registries; **%d** no longer exist.", checked, missing))
+	}
+	source = append(source,
+		"\n",
+		"\n",
+		"| Image | Used As | Repositories | Built | Status |\n",
+		"|-------|---------|--------------|-------|--------|\n",
+	)
+	for _, image := range images {
+		built, status := "-", "-"
+		if image.Created != nil {
+			built = image.Created.Format("2006-01-02")
+			status = "✅ Found"
+		}
+		if image.Missing {
+			status = "❌ Missing"
+		}
+		source = append(source, fmt.Sprintf("| `%s` | %s | %d | %s | %s |\n",
+			image.Image, strings.Join(image.Kinds, ", "), len(image.Repositories), built, status))
+	}
+
+	return NotebookCell{
+		CellType: "markdown",
+		Source:   source,
+	}
+}
+
+// createPrioritiesCell creates a ranking of the highest-priority issues across repositories
+func createPrioritiesCell(issues []PriorityIssue) NotebookCell {
+	source := []string{
+		"## 🎯 Highest Priority Issues\n",
+		"\n",
+		"Priority scores (0-100) weigh severity, how many repositories use the action, whether the workflow deploys, ",
+		"its triggers, and how far the pinned version lags. `create-pr` opens pull requests in this order.\n",
+		"\n",
+		"| Score | Repository | Action | Version | Severity | Workflow |\n",
+		"|-------|------------|--------|---------|----------|----------|\n",
+	}
+	for _, issue := range issues {
+		version := issue.CurrentVersion
+		if issue.SuggestedVersion != "" {
+			version += " → " + issue.SuggestedVersion
+		}
+		source = append(source, fmt.Sprintf("| %d | %s | `%s` | %s | %s | `%s` |\n",
+			issue.PriorityScore, issue.ScannedRepository, issue.Repository, version, issue.Severity, issue.FilePath))
+	}
+
+	return NotebookCell{
+		CellType: "markdown",
+		Source:   source,
+	}
+}
+
+// createEnvironmentsCell creates an inventory of workflows deploying to environments, riskiest first
+func createEnvironmentsCell(deployments []DeployWorkflow) NotebookCell {
+	environments := make(map[string]bool)
+	withIssues := 0
+	for _, deploy := range deployments {
+		for _, environment := range deploy.Environments {
+			environments[environment] = true
+		}
+		if deploy.Issues > 0 {
+			withIssues++
+		}
+	}
+
+	source := []string{
+		"## 🚀 Deployment Environments\n",
+		"\n",
+		fmt.Sprintf("**%d** workflows deploy to **%d** environments; **%d** of them have issues. ", len(deployments), len(environments), withIssues),
+		"Deploy jobs run with access to environment secrets, so update the actions in these workflows first.\n",
+		"\n",
+		"| Repository | Workflow | Environments | Deploy Jobs | Issues | Highest Severity |\n",
+		"|------------|----------|--------------|-------------|--------|------------------|\n",
+	}
+	for _, deploy := range deployments {
+		severity := "-"
+		if deploy.HighestSeverity != "" {
+			severity = deploy.HighestSeverity
+		}
+		source = append(source, fmt.Sprintf("| %s | `%s` | %s | %d | %d | %s |\n",
+			deploy.Repository, deploy.FilePath, strings.Join(deploy.Environments, ", "), deploy.Jobs, deploy.Issues, severity))
+	}
+
+	return NotebookCell{
+		CellType: "markdown",
+		Source:   source,
+	}
+}
+
+// createSetupConsistencyCell creates a per-language report of how repositories set up their toolchain
+func createSetupConsistencyCell(languages []LanguageSetup) NotebookCell {
+	inconsistent := 0
+	for _, language := range languages {
+		if !language.Consistent() {
+			inconsistent++
+		}
+	}
+
+	source := []string{
+		"## 🧰 Toolchain Setup Consistency\n",
+		"\n",
+		fmt.Sprintf("**%d** of **%d** languages set up their toolchain in more than one way. ", inconsistent, len(languages)),
+		"Converging on the standard pattern of each language keeps versions and caching consistent across repositories.\n",
+		"\n",
+		"| Language | Repositories | Patterns | Standard | Divergent |\n",
+		"|----------|--------------|----------|----------|-----------|\n",
+	}
+	for _, language := range languages {
+		patterns := make([]string, len(language.Patterns))
+		for i, pattern := range language.Patterns {
+			patterns[i] = fmt.Sprintf("%s (%d)", pattern.Pattern, len(pattern.Repositories))
+		}
+		source = append(source, fmt.Sprintf("| %s | %d | %s | %s | %d |\n", language.Language, language.Repositories,
+			strings.Join(patterns, ", "), language.Standard, len(language.Divergent)))
+	}
+
+	if inconsistent > 0 {
+		source = append(source,
+			"\n",
+			"### Repositories to Standardize\n",
+			"\n",
+			"| Language | Repository | Pattern | Standard |\n",
+			"|----------|------------|---------|----------|\n",
+		)
+		for _, language := range languages {
+			for _, pattern := range language.Patterns[1:] {
+				for _, repository := range pattern.Repositories {
+					source = append(source, fmt.Sprintf("| %s | %s | %s | %s |\n", language.Language, repository, pattern.Pattern, language.Standard))
+				}
+			}
+		}
+	}
+
+	return NotebookCell{
+		CellType: "markdown",
+		Source:   source,
+	}
+}
+
+// codeList formats names as inline code, or "-" when there are none
+func codeList(names []string) string {
+	if len(names) == 0 {
+		return "-"
+	}
+	formatted := make([]string, len(names))
+	for i, name := range names {
+		formatted[i] = "`" + name + "`"
+	}
+	return strings.Join(formatted, ", ")
+}
+
 // createDetailedStatsCell creates detailed statistics about action usage
 func createDetailedStatsCell(result *ScanResult) NotebookCell {
 	source := []string{
@@ -457,7 +1311,10 @@ func createDetailedStatsCell(result *ScanResult) NotebookCell {
 		}
 
 		sort.Slice(actionStats, func(i, j int) bool {
-			return actionStats[i].Stats.UsageCount > actionStats[j].Stats.UsageCount
+			if actionStats[i].Stats.UsageCount != actionStats[j].Stats.UsageCount {
+				return actionStats[i].Stats.UsageCount > actionStats[j].Stats.UsageCount
+			}
+			return actionStats[i].Name < actionStats[j].Name
 		})
 
 		// Show top 10 most used
@@ -496,7 +1353,10 @@ func createDetailedStatsCell(result *ScanResult) NotebookCell {
 	}
 
 	sort.Slice(allActionStats, func(i, j int) bool {
-		return allActionStats[i].Stats.UsageCount > allActionStats[j].Stats.UsageCount
+		if allActionStats[i].Stats.UsageCount != allActionStats[j].Stats.UsageCount {
+			return allActionStats[i].Stats.UsageCount > allActionStats[j].Stats.UsageCount
+		}
+		return allActionStats[i].Name < allActionStats[j].Name
 	})
 
 	versionLimit := len(allActionStats)
@@ -526,7 +1386,10 @@ func createDetailedStatsCell(result *ScanResult) NotebookCell {
 		}
 
 		sort.Slice(versions, func(i, j int) bool {
-			return versions[i].Count > versions[j].Count
+			if versions[i].Count != versions[j].Count {
+				return versions[i].Count > versions[j].Count
+			}
+			return versions[i].Version < versions[j].Version
 		})
 
 		for _, vc := range versions {
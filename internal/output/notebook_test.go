@@ -232,3 +232,102 @@ func TestCreateIssuesOverviewCell_EmptyIssues(t *testing.T) {
 		t.Error("Expected no issues message not found")
 	}
 }
+
+func TestCreateReusableWorkflowsCell(t *testing.T) {
+	scanResult := &ScanResult{
+		ReusableWorkflowCandidates: []DuplicateStepCluster{
+			{
+				Fingerprint:  "3f2a9c1b7d4e",
+				Steps:        []string{"uses: actions/checkout", "uses: actions/setup-go", "run: go test ./..."},
+				Repositories: []string{"my-org/api", "my-org/cli", "my-org/web"},
+				Occurrences: []StepOccurrence{
+					{Repository: "my-org/api", FilePath: ".github/workflows/ci.yml", Job: "test"},
+					{Repository: "my-org/cli", FilePath: ".github/workflows/ci.yml", Job: "test"},
+					{Repository: "my-org/web", FilePath: ".github/workflows/build.yml", Job: "unit"},
+				},
+			},
+		},
+	}
+
+	notebook, err := createNotebook(scanResult, nil)
+	if err != nil {
+		t.Fatalf("createNotebook() returned error: %v", err)
+	}
+
+	var source string
+	for _, cell := range notebook.Cells {
+		if joined := strings.Join(cell.Source, ""); strings.Contains(joined, "Reusable Workflow Candidates") {
+			source = joined
+		}
+	}
+
+	if source == "" {
+		t.Fatalf("Expected reusable workflow candidates section in notebook")
+	}
+	for _, expected := range []string{
+		"`3f2a9c1b7d4e`: 3 steps in 3 repositories",
+		"2. uses: actions/setup-go",
+		"- `my-org/web` `.github/workflows/build.yml` (job `unit`)",
+	} {
+		if !strings.Contains(source, expected) {
+			t.Errorf("Expected section to contain %q, got:\n%s", expected, source)
+		}
+	}
+}
+
+func TestCreateTagProtectionCell(t *testing.T) {
+	scanResult := &ScanResult{
+		TagProtectionFindings: []TagProtectionFinding{
+			{
+				Repository:      "my-org/deploy",
+				Consumers:       []string{"my-org/api", "my-org/cli", "my-org/web"},
+				UnprotectedTags: []string{"v1", "v1.2.0"},
+				Severity:        "high",
+			},
+		},
+	}
+
+	notebook, err := createNotebook(scanResult, nil)
+	if err != nil {
+		t.Fatalf("createNotebook() returned error: %v", err)
+	}
+
+	var source string
+	for _, cell := range notebook.Cells {
+		if joined := strings.Join(cell.Source, ""); strings.Contains(joined, "## 🏷️ Tag Protection") {
+			source = joined
+		}
+	}
+
+	expected := "| `my-org/deploy` | 3 | `v1`, `v1.2.0` | HIGH |"
+	if !strings.Contains(source, expected) {
+		t.Errorf("Expected section to contain %q, got:\n%s", expected, source)
+	}
+}
+
+func TestCreateHeaderCell_CountsWorkflowTemplateIssues(t *testing.T) {
+	result := &ScanResult{
+		Owner: "my-org",
+		Repositories: []RepositoryResult{
+			{
+				Name:     ".github",
+				FullName: "my-org/.github",
+				WorkflowFiles: []WorkflowFileResult{
+					{Path: ".github/workflows/lint.yml"},
+					{Path: "workflow-templates/ci.yml", Template: true},
+				},
+				Issues: []ActionIssue{
+					{Repository: "actions/checkout", FilePath: "workflow-templates/ci.yml", IssueType: "outdated", Severity: "medium"},
+					{Repository: "actions/setup-go", FilePath: "workflow-templates/ci.yml", IssueType: "outdated", Severity: "medium"},
+					{Repository: "actions/checkout", FilePath: ".github/workflows/lint.yml", IssueType: "outdated", Severity: "medium"},
+				},
+			},
+		},
+		Summary: Summary{IssuesByType: map[string]int{"outdated": 3}},
+	}
+
+	content := strings.Join(createHeaderCell(result).Source, "")
+	if !strings.Contains(content, "**2** issues in organization workflow templates") {
+		t.Errorf("Expected the header to count issues in workflow templates, got:\n%s", content)
+	}
+}
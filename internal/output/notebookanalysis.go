@@ -0,0 +1,184 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// notebookData is the scan data embedded in notebooks for the analysis cells, one row per issue
+// and per repository so pandas can load each as a table
+type notebookData struct {
+	Owner        string                  `json:"owner"`
+	Issues       []notebookIssueRow      `json:"issues"`
+	Repositories []notebookRepositoryRow `json:"repositories"`
+}
+
+type notebookIssueRow struct {
+	Repository       string `json:"repository"`
+	FilePath         string `json:"file_path"`
+	Action           string `json:"action"`
+	WorkflowPath     string `json:"workflow_path"`
+	CurrentVersion   string `json:"current_version"`
+	SuggestedVersion string `json:"suggested_version"`
+	IssueType        string `json:"issue_type"`
+	RuleID           string `json:"rule_id"`
+	Severity         string `json:"severity"`
+	Description      string `json:"description"`
+	PriorityScore    int    `json:"priority_score"`
+	Existing         bool   `json:"existing"`
+	Context          string `json:"context"`
+}
+
+type notebookRepositoryRow struct {
+	Repository string            `json:"repository"`
+	Language   string            `json:"language"`
+	Topics     []string          `json:"topics"`
+	Properties map[string]string `json:"properties"` // Custom properties, loaded as "properties.<name>" columns
+}
+
+// MarshalJSON writes code cells with the execution count and outputs nbformat requires of them
+func (c NotebookCell) MarshalJSON() ([]byte, error) {
+	type cell NotebookCell
+	if c.CellType != "code" {
+		return json.Marshal(cell(c))
+	}
+	return json.Marshal(struct {
+		cell
+		ExecutionCount *int          `json:"execution_count"`
+		Outputs        []interface{} `json:"outputs"`
+	}{cell: cell(c), Outputs: []interface{}{}})
+}
+
+// codeCell returns a Python code cell from its lines
+func codeCell(lines ...string) NotebookCell {
+	source := make([]string, len(lines))
+	for i, line := range lines {
+		source[i] = line
+		if i < len(lines)-1 {
+			source[i] += "\n"
+		}
+	}
+	return NotebookCell{CellType: "code", Source: source}
+}
+
+// createAnalysisCells embeds the issues of a scan and adds Python cells loading them into pandas,
+// so analysts can filter, pivot, and chart the results without running another scan
+func createAnalysisCells(result *ScanResult) ([]NotebookCell, error) {
+	data := notebookData{Owner: result.Owner, Issues: []notebookIssueRow{}, Repositories: []notebookRepositoryRow{}}
+	propertyKeys := make(map[string]bool)
+	for _, repo := range result.Repositories {
+		topics := repo.Topics
+		if topics == nil {
+			topics = []string{}
+		}
+		properties := repo.CustomProperties
+		if properties == nil {
+			properties = map[string]string{}
+		}
+		for key := range properties {
+			propertyKeys[key] = true
+		}
+		data.Repositories = append(data.Repositories, notebookRepositoryRow{
+			Repository: repo.FullName,
+			Language:   repo.Language,
+			Topics:     topics,
+			Properties: properties,
+		})
+
+		for _, issue := range repo.Issues {
+			ruleID := issue.RuleID
+			if ruleID == "" {
+				ruleID = RuleID(issue.IssueType)
+			}
+			data.Issues = append(data.Issues, notebookIssueRow{
+				Repository:       repo.FullName,
+				FilePath:         issue.FilePath,
+				Action:           issue.Repository,
+				WorkflowPath:     issue.WorkflowPath,
+				CurrentVersion:   issue.CurrentVersion,
+				SuggestedVersion: issue.SuggestedVersion,
+				IssueType:        issue.IssueType,
+				RuleID:           ruleID,
+				Severity:         issue.Severity,
+				Description:      issue.Description,
+				PriorityScore:    issue.PriorityScore,
+				Existing:         issue.Existing,
+				Context:          issue.Context,
+			})
+		}
+	}
+	if len(data.Issues) == 0 {
+		return nil, nil
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode notebook data: %w", err)
+	}
+	// A JSON string literal is also a valid Python string literal
+	literal, err := json.Marshal(string(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode notebook data: %w", err)
+	}
+
+	groupBy := "repository"
+	if key := teamPropertyKey(propertyKeys); key != "" {
+		groupBy = "properties." + key
+	}
+
+	return []NotebookCell{
+		{CellType: "markdown", Source: []string{
+			"## 🔬 Interactive Analysis\n",
+			"\n",
+			"The cells below load this scan's issues into pandas. Run them in order to filter, pivot, and chart the results; edit them freely. ",
+			"Each issue row carries its repository's `language` and custom properties as `properties.<name>` columns. Charts need matplotlib.\n",
+		}},
+		codeCell(
+			"# Scan data embedded by actions-maintainer",
+			"import json",
+			"",
+			"SCAN_DATA = json.loads("+string(literal)+")",
+		),
+		codeCell(
+			"import pandas as pd",
+			"",
+			`repositories = pd.json_normalize(SCAN_DATA["repositories"])`,
+			`issues = pd.DataFrame(SCAN_DATA["issues"]).merge(repositories, on="repository", how="left")`,
+			"SEVERITIES = [\"critical\", \"high\", \"medium\", \"low\"]",
+			"issues.head()",
+		),
+		codeCell(
+			"# Filter issues, e.g. new critical and high issues",
+			`filtered = issues[issues["severity"].isin(["critical", "high"]) & ~issues["existing"]]`,
+			`filtered[["repository", "file_path", "action", "current_version", "suggested_version", "issue_type", "severity"]]`,
+		),
+		codeCell(
+			"# Issues per severity, by team or any other column",
+			fmt.Sprintf("GROUP_BY = %q", groupBy),
+			`by_group = issues.pivot_table(index=GROUP_BY, columns="severity", values="action", aggfunc="count", fill_value=0)`,
+			"by_group = by_group.reindex(columns=[s for s in SEVERITIES if s in by_group.columns])",
+			"by_group",
+		),
+		codeCell(
+			"by_group.plot.barh(stacked=True, title=f\"Issues by {GROUP_BY}\");",
+			`issues["issue_type"].value_counts().plot.pie(title="Issues by type", ylabel="");`,
+		),
+	}, nil
+}
+
+// teamPropertyKey returns the custom property naming a repository's team, if any, for grouping
+func teamPropertyKey(keys map[string]bool) string {
+	var names []string
+	for key := range keys {
+		if strings.Contains(strings.ToLower(key), "team") {
+			names = append(names, key)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	return names[0]
+}
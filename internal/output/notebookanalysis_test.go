@@ -0,0 +1,88 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFormatNotebook_AnalysisCells(t *testing.T) {
+	result := BuildScanResult("acme", []RepositoryResult{{
+		Name:             "api",
+		FullName:         "acme/api",
+		CustomProperties: map[string]string{"TeamName": "payments", "Tier": "1"},
+		Issues: []ActionIssue{
+			{Repository: "actions/checkout", CurrentVersion: "v2", SuggestedVersion: "v4", IssueType: "outdated", Severity: "medium",
+				Description: `Uses "v2" \ ''' quotes`, FilePath: ".github/workflows/ci.yml"},
+		},
+	}})
+
+	var buf bytes.Buffer
+	if err := FormatNotebook(result, &buf); err != nil {
+		t.Fatalf("FormatNotebook() returned error: %v", err)
+	}
+
+	var notebook struct {
+		Cells []map[string]json.RawMessage `json:"cells"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &notebook); err != nil {
+		t.Fatalf("Failed to parse notebook: %v", err)
+	}
+
+	var code [][]string
+	for _, cell := range notebook.Cells {
+		if string(cell["cell_type"]) != `"code"` {
+			if _, ok := cell["outputs"]; ok {
+				t.Errorf("Expected markdown cells without outputs")
+			}
+			continue
+		}
+		if string(cell["execution_count"]) != "null" || string(cell["outputs"]) != "[]" {
+			t.Errorf("Expected code cells with a null execution count and no outputs, got %s and %s", cell["execution_count"], cell["outputs"])
+		}
+		var source []string
+		if err := json.Unmarshal(cell["source"], &source); err != nil {
+			t.Fatalf("Failed to parse cell source: %v", err)
+		}
+		code = append(code, source)
+	}
+	if len(code) != 5 {
+		t.Fatalf("Expected 5 analysis code cells, got %d", len(code))
+	}
+
+	// The data cell holds the scan data as a string literal that Python and JSON read alike
+	data := code[0][len(code[0])-1]
+	literal := strings.TrimSuffix(strings.TrimPrefix(data, "SCAN_DATA = json.loads("), ")")
+	var encoded string
+	if err := json.Unmarshal([]byte(literal), &encoded); err != nil {
+		t.Fatalf("Expected a string literal in %q: %v", data, err)
+	}
+	var embedded notebookData
+	if err := json.Unmarshal([]byte(encoded), &embedded); err != nil {
+		t.Fatalf("Failed to parse embedded data: %v", err)
+	}
+	if len(embedded.Issues) != 1 || embedded.Issues[0].Action != "actions/checkout" || embedded.Issues[0].RuleID != "AM001" {
+		t.Errorf("Expected the checkout issue to be embedded, got %+v", embedded.Issues)
+	}
+	if embedded.Issues[0].Description != `Uses "v2" \ ''' quotes` {
+		t.Errorf("Expected quotes and backslashes to survive embedding, got %q", embedded.Issues[0].Description)
+	}
+	if embedded.Repositories[0].Properties["TeamName"] != "payments" {
+		t.Errorf("Expected custom properties to be embedded, got %+v", embedded.Repositories[0])
+	}
+
+	if pivot := strings.Join(code[3], ""); !strings.Contains(pivot, `GROUP_BY = "properties.TeamName"`) {
+		t.Errorf("Expected issues grouped by the team property, got %q", pivot)
+	}
+}
+
+func TestCreateAnalysisCells_NoIssues(t *testing.T) {
+	cells, err := createAnalysisCells(BuildScanResult("acme", []RepositoryResult{{Name: "api", FullName: "acme/api"}}))
+	if err != nil {
+		t.Fatalf("createAnalysisCells() returned error: %v", err)
+	}
+	if len(cells) != 0 {
+		t.Errorf("Expected no analysis cells without issues, got %d", len(cells))
+	}
+}
@@ -0,0 +1,381 @@
+package output
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// IsParquetFile reports whether an output path selects the Parquet inventory format
+func IsParquetFile(path string) bool {
+	return hasExtension(path, ".parquet")
+}
+
+// InventoryRow is one row of the flattened action inventory: an action reference with one of its
+// issues. References without issues have one row with empty issue columns, and issues without a
+// reference, such as hygiene findings, have one row of their own.
+type InventoryRow struct {
+	Owner            string
+	Repository       string // Full name of the scanned repository
+	Workflow         string // Workflow file path
+	Context          string // Job and step of the reference
+	Action           string // Action repository, e.g. "actions/checkout"
+	WorkflowPath     string // Path within the action repository, for reusable workflows and nested actions
+	Version          string // Ref as written
+	SHA              string // Commit SHA when the ref is pinned to one
+	PinComment       string
+	Reusable         bool
+	IssueType        string
+	RuleID           string
+	Severity         string
+	SuggestedVersion string
+	Description      string
+	PriorityScore    int64
+	Existing         bool
+}
+
+// InventoryRows flattens the action references and issues of a scan
+func InventoryRows(result *ScanResult) []InventoryRow {
+	var rows []InventoryRow
+	for _, repo := range result.Repositories {
+		// Issues keyed by the reference raising them
+		issues := make(map[string][]ActionIssue)
+		var keys []string
+		for _, issue := range repo.Issues {
+			key := inventoryKey(issue.FilePath, issue.Context, issue.Repository, issue.WorkflowPath, issue.CurrentVersion)
+			if _, ok := issues[key]; !ok {
+				keys = append(keys, key)
+			}
+			issues[key] = append(issues[key], issue)
+		}
+
+		matched := make(map[string]bool)
+		for _, file := range repo.WorkflowFiles {
+			for _, action := range file.Actions {
+				row := InventoryRow{
+					Owner:        result.Owner,
+					Repository:   repo.FullName,
+					Workflow:     file.Path,
+					Context:      action.Context,
+					Action:       action.Repository,
+					WorkflowPath: action.WorkflowPath,
+					Version:      action.Version,
+					PinComment:   action.PinComment,
+					Reusable:     action.IsReusable,
+				}
+				if PinStyle(action.Version) == PinStyleSHA {
+					row.SHA = action.Version
+				}
+
+				key := inventoryKey(file.Path, action.Context, action.Repository, action.WorkflowPath, action.Version)
+				if len(issues[key]) == 0 {
+					rows = append(rows, row)
+					continue
+				}
+				matched[key] = true
+				for _, issue := range issues[key] {
+					rows = append(rows, row.withIssue(issue))
+				}
+			}
+		}
+
+		for _, key := range keys {
+			if matched[key] {
+				continue
+			}
+			for _, issue := range issues[key] {
+				row := InventoryRow{
+					Owner:        result.Owner,
+					Repository:   repo.FullName,
+					Workflow:     issue.FilePath,
+					Context:      issue.Context,
+					Action:       issue.Repository,
+					WorkflowPath: issue.WorkflowPath,
+					Version:      issue.CurrentVersion,
+					PinComment:   issue.PinComment,
+				}
+				rows = append(rows, row.withIssue(issue))
+			}
+		}
+	}
+	return rows
+}
+
+// withIssue returns the row with the columns of an issue set
+func (r InventoryRow) withIssue(issue ActionIssue) InventoryRow {
+	r.IssueType = issue.IssueType
+	r.RuleID = issue.RuleID
+	if r.RuleID == "" {
+		r.RuleID = RuleID(issue.IssueType)
+	}
+	r.Severity = issue.Severity
+	r.SuggestedVersion = issue.SuggestedVersion
+	r.Description = issue.Description
+	r.PriorityScore = int64(issue.PriorityScore)
+	r.Existing = issue.Existing
+	return r
+}
+
+// inventoryKey identifies an action reference within a repository
+func inventoryKey(filePath, context, repository, workflowPath, version string) string {
+	return strings.Join([]string{filePath, context, repository, workflowPath, version}, "\x00")
+}
+
+// inventoryColumns lists the Parquet columns of the inventory, in file order
+var inventoryColumns = []struct {
+	name  string
+	kind  int32
+	value func(InventoryRow) interface{}
+}{
+	{"owner", parquetByteArray, func(r InventoryRow) interface{} { return r.Owner }},
+	{"repository", parquetByteArray, func(r InventoryRow) interface{} { return r.Repository }},
+	{"workflow", parquetByteArray, func(r InventoryRow) interface{} { return r.Workflow }},
+	{"context", parquetByteArray, func(r InventoryRow) interface{} { return r.Context }},
+	{"action", parquetByteArray, func(r InventoryRow) interface{} { return r.Action }},
+	{"workflow_path", parquetByteArray, func(r InventoryRow) interface{} { return r.WorkflowPath }},
+	{"version", parquetByteArray, func(r InventoryRow) interface{} { return r.Version }},
+	{"sha", parquetByteArray, func(r InventoryRow) interface{} { return r.SHA }},
+	{"pin_comment", parquetByteArray, func(r InventoryRow) interface{} { return r.PinComment }},
+	{"reusable", parquetBoolean, func(r InventoryRow) interface{} { return r.Reusable }},
+	{"issue_type", parquetByteArray, func(r InventoryRow) interface{} { return r.IssueType }},
+	{"rule_id", parquetByteArray, func(r InventoryRow) interface{} { return r.RuleID }},
+	{"severity", parquetByteArray, func(r InventoryRow) interface{} { return r.Severity }},
+	{"suggested_version", parquetByteArray, func(r InventoryRow) interface{} { return r.SuggestedVersion }},
+	{"description", parquetByteArray, func(r InventoryRow) interface{} { return r.Description }},
+	{"priority_score", parquetInt64, func(r InventoryRow) interface{} { return r.PriorityScore }},
+	{"existing", parquetBoolean, func(r InventoryRow) interface{} { return r.Existing }},
+}
+
+// Parquet physical types, encodings, and thrift compact protocol field types used by the writer
+const (
+	parquetBoolean   = 0
+	parquetInt64     = 2
+	parquetByteArray = 6
+
+	parquetPlain = 0
+	parquetRLE   = 3
+
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// parquetMagic starts and ends every Parquet file
+const parquetMagic = "PAR1"
+
+// FormatParquet writes the flattened action inventory of a scan as a Parquet file, for DuckDB, Spark,
+// or pandas. Every column is required, with empty strings for missing values. The file holds one
+// uncompressed row group with a single PLAIN-encoded data page per column.
+func FormatParquet(result *ScanResult, writer io.Writer) error {
+	rows := InventoryRows(result)
+
+	var file bytes.Buffer
+	file.WriteString(parquetMagic)
+
+	// Column chunks, each a page header followed by the page's values; an empty inventory has none
+	type chunk struct {
+		offset int64
+		size   int64
+	}
+	var chunks []chunk
+	if len(rows) > 0 {
+		chunks = make([]chunk, len(inventoryColumns))
+	}
+	for i := range chunks {
+		column := inventoryColumns[i]
+		var page bytes.Buffer
+		var bits byte
+		for n, row := range rows {
+			switch value := column.value(row).(type) {
+			case string:
+				binary.Write(&page, binary.LittleEndian, uint32(len(value)))
+				page.WriteString(value)
+			case int64:
+				binary.Write(&page, binary.LittleEndian, value)
+			case bool:
+				// Booleans are bit-packed, least significant bit first
+				if value {
+					bits |= 1 << (n % 8)
+				}
+				if n%8 == 7 || n == len(rows)-1 {
+					page.WriteByte(bits)
+					bits = 0
+				}
+			}
+		}
+
+		header := newCompactWriter()
+		header.i32(1, 0) // DATA_PAGE
+		header.i32(2, int32(page.Len()))
+		header.i32(3, int32(page.Len()))
+		header.structBegin(5) // DataPageHeader
+		header.i32(1, int32(len(rows)))
+		header.i32(2, parquetPlain)
+		header.i32(3, parquetRLE)
+		header.i32(4, parquetRLE)
+		header.structEnd()
+		header.structEnd()
+
+		chunks[i] = chunk{offset: int64(file.Len()), size: int64(header.buf.Len() + page.Len())}
+		file.Write(header.buf.Bytes())
+		file.Write(page.Bytes())
+	}
+
+	// File metadata: the schema, and the row group locating each column chunk
+	meta := newCompactWriter()
+	meta.i32(1, 1) // version
+	meta.listBegin(2, thriftStruct, len(inventoryColumns)+1)
+	meta.elementBegin()
+	meta.binary(4, "schema")
+	meta.i32(5, int32(len(inventoryColumns)))
+	meta.structEnd()
+	for _, column := range inventoryColumns {
+		meta.elementBegin()
+		meta.i32(1, column.kind)
+		meta.i32(3, 0) // REQUIRED
+		meta.binary(4, column.name)
+		if column.kind == parquetByteArray {
+			meta.i32(6, 0)       // ConvertedType UTF8
+			meta.structBegin(10) // LogicalType
+			meta.structBegin(1)  // STRING
+			meta.structEnd()
+			meta.structEnd()
+		}
+		meta.structEnd()
+	}
+	meta.i64(3, int64(len(rows)))
+
+	if len(rows) == 0 {
+		meta.listBegin(4, thriftStruct, 0)
+	} else {
+		var totalSize int64
+		for _, c := range chunks {
+			totalSize += c.size
+		}
+		meta.listBegin(4, thriftStruct, 1)
+		meta.elementBegin()
+		meta.listBegin(1, thriftStruct, len(inventoryColumns))
+		for i, column := range inventoryColumns {
+			meta.elementBegin()
+			meta.i64(2, chunks[i].offset)
+			meta.structBegin(3) // ColumnMetaData
+			meta.i32(1, column.kind)
+			meta.listBegin(2, thriftI32, 2)
+			meta.listI32(parquetPlain)
+			meta.listI32(parquetRLE)
+			meta.listBegin(3, thriftBinary, 1)
+			meta.listBinary(column.name)
+			meta.i32(4, 0) // UNCOMPRESSED
+			meta.i64(5, int64(len(rows)))
+			meta.i64(6, chunks[i].size)
+			meta.i64(7, chunks[i].size)
+			meta.i64(9, chunks[i].offset)
+			meta.structEnd()
+			meta.structEnd()
+		}
+		meta.i64(2, totalSize)
+		meta.i64(3, int64(len(rows)))
+		meta.structEnd()
+	}
+	meta.binary(6, "actions-maintainer")
+	meta.structEnd()
+
+	file.Write(meta.buf.Bytes())
+	binary.Write(&file, binary.LittleEndian, uint32(meta.buf.Len()))
+	file.WriteString(parquetMagic)
+
+	if _, err := writer.Write(file.Bytes()); err != nil {
+		return fmt.Errorf("failed to write Parquet output: %w", err)
+	}
+	return nil
+}
+
+// compactWriter encodes thrift structs with the compact protocol, as used by Parquet metadata
+type compactWriter struct {
+	buf    bytes.Buffer
+	lastID []int16 // Last field id written in each open struct
+}
+
+func newCompactWriter() *compactWriter {
+	return &compactWriter{lastID: []int16{0}}
+}
+
+// field writes a field header, encoding the id as a delta from the previous field when it fits
+func (w *compactWriter) field(id int16, kind byte) {
+	last := &w.lastID[len(w.lastID)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | kind)
+	} else {
+		w.buf.WriteByte(kind)
+		w.varint(zigzag(int64(id)))
+	}
+	*last = id
+}
+
+func (w *compactWriter) varint(v uint64) {
+	for v >= 0x80 {
+		w.buf.WriteByte(byte(v) | 0x80)
+		v >>= 7
+	}
+	w.buf.WriteByte(byte(v))
+}
+
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+func (w *compactWriter) i32(id int16, v int32) {
+	w.field(id, thriftI32)
+	w.varint(zigzag(int64(v)))
+}
+
+func (w *compactWriter) i64(id int16, v int64) {
+	w.field(id, thriftI64)
+	w.varint(zigzag(v))
+}
+
+func (w *compactWriter) binary(id int16, v string) {
+	w.field(id, thriftBinary)
+	w.listBinary(v)
+}
+
+// structBegin opens a struct field; close it with structEnd
+func (w *compactWriter) structBegin(id int16) {
+	w.field(id, thriftStruct)
+	w.lastID = append(w.lastID, 0)
+}
+
+// elementBegin opens a struct element of a list; close it with structEnd
+func (w *compactWriter) elementBegin() {
+	w.lastID = append(w.lastID, 0)
+}
+
+// structEnd closes the innermost struct, or the top-level struct
+func (w *compactWriter) structEnd() {
+	w.buf.WriteByte(0)
+	w.lastID = w.lastID[:len(w.lastID)-1]
+}
+
+// listBegin writes the header of a list field with size elements of a type
+func (w *compactWriter) listBegin(id int16, elementKind byte, size int) {
+	w.field(id, thriftList)
+	if size < 15 {
+		w.buf.WriteByte(byte(size)<<4 | elementKind)
+		return
+	}
+	w.buf.WriteByte(0xf0 | elementKind)
+	w.varint(uint64(size))
+}
+
+func (w *compactWriter) listI32(v int32) {
+	w.varint(zigzag(int64(v)))
+}
+
+func (w *compactWriter) listBinary(v string) {
+	w.varint(uint64(len(v)))
+	w.buf.WriteString(v)
+}
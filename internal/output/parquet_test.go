@@ -0,0 +1,81 @@
+package output
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+func inventoryResult() *ScanResult {
+	return &ScanResult{Owner: "acme", Repositories: []RepositoryResult{{
+		FullName: "acme/api",
+		WorkflowFiles: []WorkflowFileResult{{Path: ".github/workflows/ci.yml", Actions: []workflow.ActionReference{
+			{Repository: "actions/checkout", Version: "v2", Context: "job:build/step:checkout"},
+			{Repository: "actions/cache", Version: "0123456789abcdef0123456789abcdef01234567", PinComment: "v4", Context: "job:build/step:cache"},
+		}}},
+		Issues: []ActionIssue{
+			{Repository: "actions/checkout", CurrentVersion: "v2", SuggestedVersion: "v4", IssueType: "outdated", Severity: "medium",
+				Context: "job:build/step:checkout", FilePath: ".github/workflows/ci.yml", PriorityScore: 42},
+			{Repository: "timeout-minutes", IssueType: "missing-timeout", Severity: "low", Context: "job:build",
+				FilePath: ".github/workflows/ci.yml", Existing: true},
+		},
+	}}}
+}
+
+func TestInventoryRows(t *testing.T) {
+	rows := InventoryRows(inventoryResult())
+	if len(rows) != 3 {
+		t.Fatalf("Expected 3 rows, got %+v", rows)
+	}
+
+	if rows[0].Action != "actions/checkout" || rows[0].IssueType != "outdated" || rows[0].RuleID != "AM001" || rows[0].PriorityScore != 42 {
+		t.Errorf("Expected the checkout reference joined with its issue, got %+v", rows[0])
+	}
+	if rows[1].IssueType != "" || rows[1].SHA != "0123456789abcdef0123456789abcdef01234567" || rows[1].PinComment != "v4" {
+		t.Errorf("Expected the SHA-pinned cache reference without issues, got %+v", rows[1])
+	}
+	if rows[2].Action != "timeout-minutes" || rows[2].IssueType != "missing-timeout" || !rows[2].Existing || rows[2].Owner != "acme" {
+		t.Errorf("Expected the hygiene issue as a row of its own, got %+v", rows[2])
+	}
+}
+
+func TestFormatParquet(t *testing.T) {
+	for name, result := range map[string]*ScanResult{"inventory": inventoryResult(), "empty": {Owner: "acme"}} {
+		var buf bytes.Buffer
+		if err := FormatParquet(result, &buf); err != nil {
+			t.Fatalf("%s: FormatParquet() returned error: %v", name, err)
+		}
+
+		data := buf.Bytes()
+		if !bytes.HasPrefix(data, []byte(parquetMagic)) || !bytes.HasSuffix(data, []byte(parquetMagic)) {
+			t.Fatalf("%s: Expected PAR1 magic at both ends", name)
+		}
+		footerLength := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+		if footerLength <= 0 || footerLength > len(data)-12 {
+			t.Fatalf("%s: Expected a footer within the file, got length %d of %d bytes", name, footerLength, len(data))
+		}
+		footer := data[len(data)-8-footerLength : len(data)-8]
+		for _, column := range inventoryColumns {
+			if !bytes.Contains(footer, []byte(column.name)) {
+				t.Errorf("%s: Expected column %s in the schema", name, column.name)
+			}
+		}
+	}
+}
+
+func TestCompactWriter(t *testing.T) {
+	w := newCompactWriter()
+	w.i32(1, -1)       // Short form: delta 1, type i32, zigzag(-1) = 1
+	w.binary(20, "ab") // Long form: delta over 15
+	w.listBegin(21, thriftI32, 2)
+	w.listI32(3)
+	w.listI32(300)
+	w.structEnd()
+
+	expected := []byte{0x15, 0x01, 0x08, 0x28, 0x02, 'a', 'b', 0x19, 0x25, 0x06, 0xd8, 0x04, 0x00}
+	if !bytes.Equal(w.buf.Bytes(), expected) {
+		t.Errorf("Expected % x, got % x", expected, w.buf.Bytes())
+	}
+}
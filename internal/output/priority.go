@@ -0,0 +1,32 @@
+package output
+
+import "sort"
+
+// DefaultPriorityLimit is how many issues the priority report lists
+const DefaultPriorityLimit = 20
+
+// PriorityIssue is a scored issue with the repository it was found in
+type PriorityIssue struct {
+	ScannedRepository string
+	ActionIssue
+}
+
+// TopPriorityIssues returns up to limit scored issues across repositories, highest priority score first
+func TopPriorityIssues(result *ScanResult, limit int) []PriorityIssue {
+	var issues []PriorityIssue
+	for _, repo := range result.Repositories {
+		for _, issue := range repo.Issues {
+			if issue.PriorityScore > 0 {
+				issues = append(issues, PriorityIssue{ScannedRepository: repo.FullName, ActionIssue: issue})
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].PriorityScore > issues[j].PriorityScore
+	})
+	if limit > 0 && len(issues) > limit {
+		issues = issues[:limit]
+	}
+	return issues
+}
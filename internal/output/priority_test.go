@@ -0,0 +1,72 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func priorityResult() *ScanResult {
+	return &ScanResult{
+		Repositories: []RepositoryResult{
+			{
+				FullName: "my-org/api",
+				Issues: []ActionIssue{
+					{Repository: "actions/checkout", CurrentVersion: "v2", SuggestedVersion: "v4", Severity: "medium", FilePath: ".github/workflows/deploy.yml", PriorityScore: 68},
+					{Repository: "actions/cache", CurrentVersion: "v3", Severity: "low", FilePath: ".github/workflows/ci.yml"},
+				},
+			},
+			{
+				FullName: "my-org/web",
+				Issues: []ActionIssue{
+					{Repository: "old/action", CurrentVersion: "v1", SuggestedVersion: "v2", Severity: "high", FilePath: ".github/workflows/release.yml", PriorityScore: 75},
+				},
+			},
+		},
+	}
+}
+
+func TestTopPriorityIssues(t *testing.T) {
+	issues := TopPriorityIssues(priorityResult(), 0)
+	if len(issues) != 2 {
+		t.Fatalf("Expected unscored issues to be left out, got %+v", issues)
+	}
+	if issues[0].ScannedRepository != "my-org/web" || issues[1].ScannedRepository != "my-org/api" {
+		t.Errorf("Expected highest score first, got %+v", issues)
+	}
+
+	if limited := TopPriorityIssues(priorityResult(), 1); len(limited) != 1 || limited[0].PriorityScore != 75 {
+		t.Errorf("Expected the limit to keep the top issue, got %+v", limited)
+	}
+}
+
+func TestSortIssues_PriorityScoreFirst(t *testing.T) {
+	issues := []ActionIssue{
+		{Repository: "a", Severity: "critical", PriorityScore: 40},
+		{Repository: "b", Severity: "low", PriorityScore: 90},
+		{Repository: "c", Severity: "high"},
+		{Repository: "d", Severity: "critical"},
+	}
+	SortIssues(issues)
+
+	var order []string
+	for _, issue := range issues {
+		order = append(order, issue.Repository)
+	}
+	if strings.Join(order, ",") != "b,a,d,c" {
+		t.Errorf("Expected score then severity order, got %v", order)
+	}
+}
+
+func TestCreatePrioritiesCell(t *testing.T) {
+	source := strings.Join(createPrioritiesCell(TopPriorityIssues(priorityResult(), DefaultPriorityLimit)).Source, "")
+
+	for _, want := range []string{
+		"## 🎯 Highest Priority Issues",
+		"| 75 | my-org/web | `old/action` | v1 → v2 | high | `.github/workflows/release.yml` |",
+		"| 68 | my-org/api | `actions/checkout` | v2 → v4 | medium | `.github/workflows/deploy.yml` |",
+	} {
+		if !strings.Contains(source, want) {
+			t.Errorf("Expected cell to contain %q, got:\n%s", want, source)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// problemSeverity maps an issue to the severity of a problem line, following its annotation level
+func problemSeverity(issue ActionIssue) string {
+	if level := annotationLevel(issue); level != "notice" {
+		return level
+	}
+	return "info"
+}
+
+// FormatProblems writes one compiler-style line per issue, "file:line:col: severity: message [rule id]",
+// for editors and CI logs to match with a problem matcher (see examples/problem-matchers).
+// Files are relative to workspace: the repository checkout when the scan covers one repository, or
+// a directory of checkouts at <owner>/<name> otherwise. Lines and columns point at the uses entry
+// when the file is checked out there, and at the start of the file when it is not.
+func FormatProblems(result *ScanResult, w io.Writer, workspace string) error {
+	scanned := 0
+	for _, repo := range result.Repositories {
+		if repo.Scanned() {
+			scanned++
+		}
+	}
+
+	for _, repo := range result.Repositories {
+		lines := make(map[string][]string)
+		for _, issue := range repo.Issues {
+			file := issue.FilePath
+			if scanned > 1 {
+				file = path.Join(repo.FullName, file)
+			}
+			content, loaded := lines[file]
+			if !loaded {
+				content = readLines(filepath.Join(workspace, filepath.FromSlash(file)))
+				lines[file] = content
+			}
+
+			line, column := findUsesPosition(content, issue)
+			if line == 0 {
+				line, column = 1, 1
+			}
+			ruleID := issue.RuleID
+			if ruleID == "" {
+				ruleID = RuleID(issue.IssueType)
+			}
+			message := strings.Join(strings.Fields(annotationMessage(issue)), " ")
+			if _, err := fmt.Fprintf(w, "%s:%d:%d: %s: %s [%s]\n", file, line, column, problemSeverity(issue), message, ruleID); err != nil {
+				return fmt.Errorf("failed to write problem: %w", err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package output
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// problemPattern is the regexp of the problem matchers in examples/problem-matchers
+var problemPattern = regexp.MustCompile(`^(.+?):(\d+):(\d+): (error|warning|info): (.*) \[([^\]]+)\]$`)
+
+func TestFormatProblems(t *testing.T) {
+	workspace := t.TempDir()
+	workflowPath := filepath.Join(workspace, ".github", "workflows", "ci.yml")
+	if err := os.MkdirAll(filepath.Dir(workflowPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := "on: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - uses: actions/checkout@v3\n"
+	if err := os.WriteFile(workflowPath, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := &ScanResult{Repositories: []RepositoryResult{
+		{FullName: "my-org/api", Issues: []ActionIssue{
+			{Repository: "actions/checkout", CurrentVersion: "v3", SuggestedVersion: "v4", IssueType: "outdated", Severity: "medium",
+				Description: "Action actions/checkout is using version v3, latest is v4", FilePath: ".github/workflows/ci.yml"},
+			{Repository: "my-org/deploy", CurrentVersion: "v9", IssueType: "broken-reference", Severity: "critical",
+				Description: "my-org/deploy@v9 does not exist", FilePath: ".github/workflows/release.yml"},
+		}},
+	}}
+
+	var buf bytes.Buffer
+	if err := FormatProblems(result, &buf, workspace); err != nil {
+		t.Fatalf("FormatProblems() returned error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 problems, got:\n%s", buf.String())
+	}
+	expected := ".github/workflows/ci.yml:6:9: warning: Action actions/checkout is using version v3, latest is v4 (suggested version: v4) [AM001]"
+	if lines[0] != expected {
+		t.Errorf("Expected %q, got %q", expected, lines[0])
+	}
+	if lines[1] != ".github/workflows/release.yml:1:1: error: my-org/deploy@v9 does not exist [AM020]" {
+		t.Errorf("Expected the missing file at 1:1, got %q", lines[1])
+	}
+	for _, line := range lines {
+		if !problemPattern.MatchString(line) {
+			t.Errorf("Expected the problem matcher to match %q", line)
+		}
+	}
+}
+
+func TestFormatProblems_SeveralRepositories(t *testing.T) {
+	issue := ActionIssue{Repository: "actions/checkout", CurrentVersion: "v3", IssueType: "outdated", Severity: "low", Existing: true,
+		Description: "outdated", FilePath: ".github/workflows/ci.yml"}
+	result := &ScanResult{Repositories: []RepositoryResult{
+		{FullName: "my-org/api", Issues: []ActionIssue{issue}},
+		{FullName: "my-org/web"},
+	}}
+
+	var buf bytes.Buffer
+	if err := FormatProblems(result, &buf, t.TempDir()); err != nil {
+		t.Fatalf("FormatProblems() returned error: %v", err)
+	}
+	if got := strings.TrimSpace(buf.String()); got != "my-org/api/.github/workflows/ci.yml:1:1: info: outdated [AM001]" {
+		t.Errorf("Expected the path under the repository's checkout, got %q", got)
+	}
+}
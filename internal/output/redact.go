@@ -0,0 +1,487 @@
+package output
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// redactedValue replaces custom property values in redacted reports
+const redactedValue = "[redacted]"
+
+// redactSafeMetadata lists the issue metadata kept by redaction. Other values, such as self-hosted
+// runner labels and the details of custom checks, can name internal hosts, paths, and teams.
+var redactSafeMetadata = map[string]bool{MetadataCVE: true, MetadataEOLDate: true, MetadataOriginalSeverity: true}
+
+// redactor replaces internal names with stable placeholders
+// Repositories owned by a scanned owner are internal; public actions such as actions/checkout are kept.
+type redactor struct {
+	key      []byte            // HMAC key; placeholders can only be reproduced by someone holding it
+	owners   map[string]bool   // lowercased owners of the scanned repositories
+	names    map[string]string // original repository or path -> placeholder
+	replacer *strings.Replacer // rewrites known names inside free text such as descriptions
+}
+
+// RedactKeyEnv names the environment variable holding the key for redacted names
+const RedactKeyEnv = "ACTIONS_MAINTAINER_REDACT_KEY"
+
+// NewRedactKey returns a random key for a single redaction run
+func NewRedactKey() ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("unable to generate redaction key: %w", err)
+	}
+	return key, nil
+}
+
+// hash returns a short keyed hash of a value
+// An HMAC rather than a plain hash, so names cannot be confirmed by hashing guesses without the key.
+func (red *redactor) hash(value string) string {
+	mac := hmac.New(sha256.New, red.key)
+	mac.Write([]byte(strings.ToLower(value)))
+	return hex.EncodeToString(mac.Sum(nil))[:12]
+}
+
+// Redact replaces repository names with keyed hashes, file paths with placeholders, and custom
+// property values with "[redacted]", so aggregate statistics can be shared outside the organization.
+// Topics, logs, PR URLs, and rule conditions are removed, and secret and variable names are hashed.
+// Public action names and versions are kept. The same key gives the same placeholders on every run.
+func (r *ScanResult) Redact(key []byte) {
+	red := newRedactor(r, key)
+
+	// Merged reports list their owners separated by commas
+	owners := strings.Split(r.Owner, ",")
+	for i := range owners {
+		owners[i] = red.owner(owners[i])
+	}
+	r.Owner = strings.Join(owners, ",")
+	for i := range r.Summary.Owners {
+		r.Summary.Owners[i].Owner = red.owner(r.Summary.Owners[i].Owner)
+	}
+	for i := range r.Repositories {
+		red.repository(&r.Repositories[i])
+	}
+	for i := range r.Summary.TopIssues {
+		red.issue(&r.Summary.TopIssues[i])
+	}
+	r.Summary.UniqueActions = red.stats(r.Summary.UniqueActions)
+	r.Summary.UniqueRegularActions = red.stats(r.Summary.UniqueRegularActions)
+	r.Summary.UniqueReusableWorkflows = red.stats(r.Summary.UniqueReusableWorkflows)
+	red.teams(r.Summary.MergeLatency)
+	for i := range r.Summary.SeverityHistory {
+		red.teams(r.Summary.SeverityHistory[i].MergeLatency)
+	}
+	for i := range r.Summary.Deadlines {
+		r.Summary.Deadlines[i].Action = red.repositoryName(r.Summary.Deadlines[i].Action)
+		r.Summary.Deadlines[i].Announcement = red.replacer.Replace(r.Summary.Deadlines[i].Announcement)
+	}
+	for i := range r.Summary.BrokenReferences {
+		reference := &r.Summary.BrokenReferences[i]
+		if red.internal(reference.Action) {
+			reference.WorkflowPath = red.path(reference.WorkflowPath)
+		}
+		reference.Action = red.repositoryName(reference.Action)
+		for j := range reference.Repositories {
+			reference.Repositories[j] = red.repositoryName(reference.Repositories[j])
+		}
+		sort.Strings(reference.Repositories)
+	}
+
+	for i := range r.ReusableWorkflowCandidates {
+		cluster := &r.ReusableWorkflowCandidates[i]
+		for j := range cluster.Steps {
+			cluster.Steps[j] = red.replacer.Replace(cluster.Steps[j])
+		}
+		for j := range cluster.Repositories {
+			cluster.Repositories[j] = red.repositoryName(cluster.Repositories[j])
+		}
+		sort.Strings(cluster.Repositories)
+		for j := range cluster.Occurrences {
+			cluster.Occurrences[j].Repository = red.repositoryName(cluster.Occurrences[j].Repository)
+			cluster.Occurrences[j].FilePath = red.path(cluster.Occurrences[j].FilePath)
+		}
+	}
+
+	for i := range r.TagProtectionFindings {
+		finding := &r.TagProtectionFindings[i]
+		finding.Repository = red.repositoryName(finding.Repository)
+		finding.Description = red.replacer.Replace(finding.Description)
+		for j := range finding.Consumers {
+			finding.Consumers[j] = red.repositoryName(finding.Consumers[j])
+		}
+		sort.Strings(finding.Consumers)
+	}
+
+	for i := range r.MajorTagFindings {
+		finding := &r.MajorTagFindings[i]
+		finding.Repository = red.repositoryName(finding.Repository)
+		finding.Description = red.replacer.Replace(finding.Description)
+		for j := range finding.Consumers {
+			finding.Consumers[j] = red.repositoryName(finding.Consumers[j])
+		}
+		sort.Strings(finding.Consumers)
+	}
+
+	for i := range r.InternalActions {
+		action := &r.InternalActions[i]
+		action.Repository = red.repositoryName(action.Repository)
+		for j := range action.Consumers {
+			action.Consumers[j] = red.repositoryName(action.Consumers[j])
+		}
+		sort.Strings(action.Consumers)
+		for j := range action.StaleConsumers {
+			action.StaleConsumers[j] = red.repositoryName(action.StaleConsumers[j])
+		}
+		sort.Strings(action.StaleConsumers)
+		for _, consumers := range action.VersionConsumers {
+			for j := range consumers {
+				consumers[j] = red.repositoryName(consumers[j])
+			}
+			sort.Strings(consumers)
+		}
+	}
+
+	for i := range r.CreatedPRs {
+		r.CreatedPRs[i].Repository = red.repositoryName(r.CreatedPRs[i].Repository)
+		r.CreatedPRs[i].Title = red.replacer.Replace(r.CreatedPRs[i].Title)
+		r.CreatedPRs[i].URL = ""
+	}
+}
+
+// newRedactor collects the internal repository names and file paths of a scan result
+func newRedactor(r *ScanResult, key []byte) *redactor {
+	red := &redactor{
+		key:    key,
+		owners: make(map[string]bool),
+		names:  make(map[string]string),
+	}
+	for _, owner := range strings.Split(r.Owner, ",") {
+		if owner != "" {
+			red.owners[strings.ToLower(owner)] = true
+		}
+	}
+	for _, repo := range r.Repositories {
+		if owner, _, found := strings.Cut(repo.FullName, "/"); found {
+			red.owners[strings.ToLower(owner)] = true
+		}
+	}
+
+	addRepository := func(name string) {
+		if name != "" && red.internal(name) {
+			red.names[name] = red.repositoryName(name)
+		}
+	}
+	addPath := func(path string) {
+		if path != "" {
+			red.names[path] = red.path(path)
+		}
+	}
+	addReference := func(repository, workflowPath, filePath string) {
+		addRepository(repository)
+		if red.internal(repository) {
+			addPath(workflowPath)
+		}
+		addPath(filePath)
+	}
+
+	// Migration targets look like "new-org/action/path@v2"
+	addMigrationTarget := func(target string) {
+		ref, _, _ := strings.Cut(target, "@")
+		parts := strings.SplitN(ref, "/", 3)
+		if len(parts) < 2 {
+			return
+		}
+		addRepository(parts[0] + "/" + parts[1])
+		if len(parts) == 3 && red.internal(ref) {
+			addPath(parts[2])
+		}
+	}
+
+	for _, repo := range r.Repositories {
+		addRepository(repo.FullName)
+		for _, file := range repo.WorkflowFiles {
+			addPath(file.Path)
+		}
+		for _, action := range repo.Actions {
+			addReference(action.Repository, action.WorkflowPath, action.FilePath)
+		}
+		for _, issue := range repo.Issues {
+			addReference(issue.Repository, issue.WorkflowPath, issue.FilePath)
+			addMigrationTarget(issue.MigrationTarget)
+		}
+		for _, suppressed := range repo.SuppressedIssues {
+			addReference(suppressed.Repository, suppressed.WorkflowPath, suppressed.FilePath)
+		}
+		for _, trigger := range repo.Triggers {
+			addPath(trigger.FilePath)
+		}
+		for _, flow := range repo.SecretFlows {
+			addRepository(flow.Action)
+			addPath(flow.FilePath)
+		}
+		for _, image := range repo.ContainerImages {
+			addPath(image.FilePath)
+		}
+		for _, setup := range repo.ToolSetups {
+			addPath(setup.FilePath)
+		}
+		for _, environment := range repo.Environments {
+			addPath(environment.FilePath)
+		}
+		for _, decision := range repo.Decisions {
+			// Actions and rules are written like migration targets, "owner/repo/path@version"
+			addMigrationTarget(decision.Action)
+			addMigrationTarget(decision.Rule)
+			addPath(decision.FilePath)
+		}
+	}
+
+	// Replace longer names first so "my-org/api-gateway" is not rewritten as "my-org/api" plus a suffix
+	originals := make([]string, 0, len(red.names))
+	for original := range red.names {
+		originals = append(originals, original)
+	}
+	sort.Slice(originals, func(i, j int) bool {
+		if len(originals[i]) != len(originals[j]) {
+			return len(originals[i]) > len(originals[j])
+		}
+		return originals[i] < originals[j]
+	})
+	pairs := make([]string, 0, len(originals)*2)
+	for _, original := range originals {
+		pairs = append(pairs, original, red.names[original])
+	}
+	red.replacer = strings.NewReplacer(pairs...)
+
+	return red
+}
+
+// internal reports whether a repository reference belongs to a scanned owner
+func (red *redactor) internal(repository string) bool {
+	owner, _, found := strings.Cut(repository, "/")
+	return found && red.owners[strings.ToLower(owner)]
+}
+
+// owner returns the placeholder for an owner
+func (red *redactor) owner(owner string) string {
+	if owner == "" {
+		return ""
+	}
+	return "org-" + red.hash(owner)
+}
+
+// repositoryName returns the placeholder for an internal "owner/name" reference, keeping any path after it
+func (red *redactor) repositoryName(repository string) string {
+	if !red.internal(repository) {
+		return repository
+	}
+	parts := strings.SplitN(repository, "/", 3)
+	redacted := red.owner(parts[0]) + "/repo-" + red.hash(parts[0]+"/"+parts[1])
+	if len(parts) == 3 {
+		redacted += "/" + red.path(parts[2])
+	}
+	return redacted
+}
+
+// host returns the placeholder for a GitHub Enterprise Server host, which names the organization as
+// clearly as its owners do. github.com is public and kept.
+func (red *redactor) host(host string) string {
+	if host == "" || strings.EqualFold(host, "github.com") {
+		return host
+	}
+	return "host-" + red.hash(host)
+}
+
+// path returns the placeholder for a file path
+func (red *redactor) path(path string) string {
+	if path == "" {
+		return ""
+	}
+	return "file-" + red.hash(path)
+}
+
+// repository redacts a repository result in place
+func (red *redactor) repository(repo *RepositoryResult) {
+	repo.FullName = red.repositoryName(repo.FullName)
+	repo.Host = red.host(repo.Host)
+	if _, name, found := strings.Cut(repo.FullName, "/"); found {
+		repo.Name = name
+	} else {
+		repo.Name = "repo-" + red.hash(repo.Name)
+	}
+
+	for name := range repo.CustomProperties {
+		repo.CustomProperties[name] = redactedValue
+	}
+	repo.Topics = nil
+	repo.Logs = nil
+	repo.Error = ""
+
+	for i := range repo.WorkflowFiles {
+		file := &repo.WorkflowFiles[i]
+		file.Path = red.path(file.Path)
+		file.Error = ""
+		if file.TokenUsage != nil {
+			usage := *file.TokenUsage
+			usage.PATSecrets = nil
+			for _, name := range file.TokenUsage.PATSecrets {
+				usage.PATSecrets = append(usage.PATSecrets, "name-"+red.hash(name))
+			}
+			file.TokenUsage = &usage
+		}
+		for j := range file.Actions {
+			red.reference(&file.Actions[j])
+		}
+	}
+	for i := range repo.Actions {
+		red.reference(&repo.Actions[i])
+	}
+	for i := range repo.Issues {
+		red.issue(&repo.Issues[i])
+	}
+	for i := range repo.SuppressedIssues {
+		red.issue(&repo.SuppressedIssues[i].ActionIssue)
+		repo.SuppressedIssues[i].Reason = red.replacer.Replace(repo.SuppressedIssues[i].Reason)
+	}
+	for i := range repo.IssueGroups {
+		group := &repo.IssueGroups[i]
+		if red.internal(group.Repository) {
+			group.WorkflowPath = red.path(group.WorkflowPath)
+		}
+		group.Repository = red.repositoryName(group.Repository)
+		group.MigrationTarget = red.replacer.Replace(group.MigrationTarget)
+		group.Description = red.replacer.Replace(group.Description)
+		for j := range group.Files {
+			group.Files[j] = red.path(group.Files[j])
+		}
+		sort.Strings(group.Files)
+	}
+	for i := range repo.Triggers {
+		repo.Triggers[i].FilePath = red.path(repo.Triggers[i].FilePath)
+	}
+	for i := range repo.SecretFlows {
+		flow := &repo.SecretFlows[i]
+		flow.FilePath = red.path(flow.FilePath)
+		flow.Action = red.repositoryName(flow.Action)
+		flow.Job = ""
+		flow.Step = ""
+		if flow.Name != workflow.AllNames {
+			flow.Name = "name-" + red.hash(flow.Name)
+		}
+		flow.Input = red.replacer.Replace(flow.Input)
+	}
+	for i := range repo.ContainerImages {
+		image := &repo.ContainerImages[i]
+		image.FilePath = red.path(image.FilePath)
+		// Images published under a scanned owner, such as ghcr.io/my-org/app, are internal
+		if red.internal(image.Repository) {
+			image.Image = "image-" + red.hash(image.Image)
+			image.Repository = red.repositoryName(image.Repository)
+		}
+	}
+	for i := range repo.ToolSetups {
+		repo.ToolSetups[i].FilePath = red.path(repo.ToolSetups[i].FilePath)
+		repo.ToolSetups[i].Detail = red.replacer.Replace(repo.ToolSetups[i].Detail)
+	}
+	for i := range repo.Environments {
+		environment := &repo.Environments[i]
+		environment.FilePath = red.path(environment.FilePath)
+		environment.Job = ""
+		environment.Environment = red.replacer.Replace(environment.Environment)
+		// Deployment URLs name internal hosts
+		environment.URL = ""
+	}
+	for i := range repo.Decisions {
+		red.decision(&repo.Decisions[i])
+	}
+}
+
+// decision redacts a scan --explain-all decision in place
+func (red *redactor) decision(decision *Decision) {
+	if local, found := strings.CutPrefix(decision.Action, "./"); found {
+		decision.Action = "./" + red.path(local)
+	} else if decision.Action != "" {
+		reference, version, _ := strings.Cut(decision.Action, "@")
+		decision.Action = red.repositoryName(reference) + "@" + version
+	}
+	decision.FilePath = red.path(decision.FilePath)
+	decision.Context = red.replacer.Replace(decision.Context)
+	decision.Rule = red.repositoryName(decision.Rule)
+	for i := range decision.Steps {
+		decision.Steps[i].Reason = red.replacer.Replace(decision.Steps[i].Reason)
+	}
+	decision.repository = red.repositoryName(decision.repository)
+}
+
+// reference redacts an action reference in place
+func (red *redactor) reference(action *workflow.ActionReference) {
+	if red.internal(action.Repository) {
+		action.WorkflowPath = red.path(action.WorkflowPath)
+	}
+	action.Repository = red.repositoryName(action.Repository)
+	action.Context = red.replacer.Replace(action.Context)
+	action.FilePath = red.path(action.FilePath)
+	action.RepoFullName = red.repositoryName(action.RepoFullName)
+}
+
+// issue redacts an issue in place
+func (red *redactor) issue(issue *ActionIssue) {
+	if red.internal(issue.Repository) {
+		issue.WorkflowPath = red.path(issue.WorkflowPath)
+	}
+	issue.Repository = red.repositoryName(issue.Repository)
+	issue.MigrationTarget = red.replacer.Replace(issue.MigrationTarget)
+	issue.Description = red.replacer.Replace(issue.Description)
+	issue.Announcement = red.replacer.Replace(issue.Announcement)
+	issue.Context = red.replacer.Replace(issue.Context)
+	issue.FilePath = red.path(issue.FilePath)
+	issue.RuleConditions = ""
+	issue.Owners = nil
+	issue.Insertion = nil
+	issue.Remediation = nil
+	issue.PatchPreview = nil
+	// Existing groups are copied from the workflow, which can name the workflow or repository
+	issue.Concurrency = nil
+	// Suggested keys hash the paths of dependency files
+	issue.SuggestedCacheKey = ""
+	for key := range issue.Metadata {
+		if !redactSafeMetadata[key] {
+			issue.Metadata[key] = redactedValue
+		}
+	}
+	// Find patterns are regular expressions, where escaped names such as `my\-org` would slip past the replacer
+	issue.FileEdits = nil
+}
+
+// teams hashes the team names of merge latencies in place, keeping them sorted by name
+func (red *redactor) teams(latencies []TeamMergeLatency) {
+	for i := range latencies {
+		latencies[i].Team = "team-" + red.hash(latencies[i].Team)
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i].Team < latencies[j].Team })
+}
+
+// stats re-keys action usage statistics by redacted action repository
+func (red *redactor) stats(stats map[string]ActionUsageStat) map[string]ActionUsageStat {
+	if stats == nil {
+		return nil
+	}
+	redacted := make(map[string]ActionUsageStat, len(stats))
+	for key, stat := range stats {
+		stat.Repository = red.repositoryName(stat.Repository)
+		repositories := make([]string, len(stat.Repositories))
+		for i, repository := range stat.Repositories {
+			repositories[i] = red.repositoryName(repository)
+		}
+		sort.Strings(repositories)
+		stat.Repositories = repositories
+		redacted[red.repositoryName(key)] = stat
+	}
+	return redacted
+}
@@ -0,0 +1,173 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+func redactTestResult() *ScanResult {
+	deadline := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	issues := []ActionIssue{
+		{Repository: "actions/checkout", CurrentVersion: "v3", SuggestedVersion: "v4", IssueType: "outdated", Severity: "medium", Description: "Action actions/checkout is using version v3, latest is v4", FilePath: ".github/workflows/ci.yml"},
+		{Repository: "my-org/deploy-action", CurrentVersion: "v1", SuggestedVersion: "v2", IssueType: "outdated", Severity: "high", Description: "Action my-org/deploy-action is using version v1, latest is v2", FilePath: ".github/workflows/release.yml", RuleConditions: "ProductId=payments",
+			FileEdits: []FileEdit{{Path: "docs/deploy.md", Find: `my\-org/deploy\-action@v1`, Replace: "my-org/deploy-action@{target}", Reason: "Docs pin my-org/deploy-action"}}},
+		{Repository: "concurrency.cancel-in-progress", IssueType: "missing-cancel-in-progress", Severity: "low", FilePath: ".github/workflows/ci.yml",
+			Concurrency: &ConcurrencyRemediation{Group: "payments-api-ci-${{ github.ref }}", CancelInProgress: true}},
+		{Repository: "actions/cache", CurrentVersion: "v4", IssueType: "cache-key", Severity: "low", FilePath: ".github/workflows/ci.yml",
+			SuggestedCacheKey: "${{ runner.os }}-npm-${{ hashFiles('services/payments/package-lock.json') }}"},
+		{Repository: "runs-on", IssueType: "deprecated-runner", Severity: "medium", FilePath: ".github/workflows/ci.yml",
+			Metadata: IssueMetadata{MetadataRunnerLabel: "payments-gpu", MetadataEOLDate: "2026-04-01", "owner_team": "team-payments"}},
+		{Repository: "my-org/legacy-deploy", CurrentVersion: "v1", IssueType: "brownout", Severity: "high", FilePath: ".github/workflows/release.yml",
+			Deadline: &deadline, Announcement: "my-org/legacy-deploy v1 stops working"},
+	}
+	return &ScanResult{
+		Owner: "my-org",
+		Repositories: []RepositoryResult{
+			{
+				Name:             "payments-api",
+				FullName:         "my-org/payments-api",
+				Host:             "github.payments.example.com",
+				WorkflowFiles:    []WorkflowFileResult{{Path: ".github/workflows/ci.yml"}, {Path: ".github/workflows/release.yml"}},
+				Actions:          []workflow.ActionReference{{Repository: "actions/checkout", Version: "v3", FilePath: ".github/workflows/ci.yml", RepoFullName: "my-org/payments-api"}, {Repository: "my-org/deploy-action", Version: "v1", FilePath: ".github/workflows/release.yml", RepoFullName: "my-org/payments-api"}},
+				Issues:           issues,
+				CustomProperties: map[string]string{"ProductId": "payments"},
+				Topics:           []string{"team-payments"},
+				Logs:             []string{"scanning my-org/payments-api"},
+			},
+		},
+		Summary:    calculateSummary(nil),
+		CreatedPRs: []CreatedPR{{Repository: "my-org/payments-api", URL: "https://github.com/my-org/payments-api/pull/1", Title: "Update actions in my-org/payments-api"}},
+	}
+}
+
+func TestRedact(t *testing.T) {
+	result := redactTestResult()
+	result.Summary = calculateSummary(result.Repositories)
+	result.Summary.MergeLatency = []TeamMergeLatency{{Team: "payments-platform", PullRequests: 2, Merged: 1}}
+	result.Summary.SeverityHistory = []SeveritySnapshot{{MergeLatency: []TeamMergeLatency{{Team: "payments-platform", PullRequests: 1}}}}
+	result.Redact([]byte("test-key"))
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	for _, secret := range []string{"my-org", `my\\-org`, "payments", "deploy-action", ".github/workflows", "docs/deploy.md", "https://"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Expected redacted report not to contain %q:\n%s", secret, data)
+		}
+	}
+
+	repo := result.Repositories[0]
+	if !strings.HasPrefix(result.Owner, "org-") || !strings.HasPrefix(repo.Name, "repo-") || repo.FullName != result.Owner+"/"+repo.Name {
+		t.Errorf("Unexpected redacted names: owner=%q name=%q full_name=%q", result.Owner, repo.Name, repo.FullName)
+	}
+	if metadata := repo.Issues[4].Metadata; metadata[MetadataEOLDate] != "2026-04-01" || metadata[MetadataRunnerLabel] != redactedValue {
+		t.Errorf("Expected well-known metadata to be kept and the rest redacted, got %v", metadata)
+	}
+	if len(result.Summary.Deadlines) != 1 || result.Summary.Deadlines[0].Action != repo.Issues[5].Repository {
+		t.Errorf("Expected the deadline of the internal action to use its placeholder, got %+v", result.Summary.Deadlines)
+	}
+	if team := result.Summary.MergeLatency[0].Team; !strings.HasPrefix(team, "team-") || result.Summary.SeverityHistory[0].MergeLatency[0].Team != team {
+		t.Errorf("Expected team names to be hashed consistently, got %q", team)
+	}
+	if !strings.HasPrefix(repo.Host, "host-") {
+		t.Errorf("Expected the Enterprise Server host to be hashed, got %q", repo.Host)
+	}
+	if repo.CustomProperties["ProductId"] != redactedValue {
+		t.Errorf("Expected custom property value to be redacted, got %q", repo.CustomProperties["ProductId"])
+	}
+
+	// Public actions are kept so usage statistics stay meaningful
+	if repo.Issues[0].Repository != "actions/checkout" || repo.Issues[0].CurrentVersion != "v3" {
+		t.Errorf("Expected public action to be kept, got %+v", repo.Issues[0])
+	}
+	if _, ok := result.Summary.UniqueActions["actions/checkout"]; !ok {
+		t.Errorf("Expected actions/checkout in redacted usage statistics")
+	}
+
+	// The internal action is replaced consistently in the issue, its description, and the statistics
+	internal := repo.Issues[1].Repository
+	if !strings.HasPrefix(internal, result.Owner+"/repo-") || !strings.Contains(repo.Issues[1].Description, internal) {
+		t.Errorf("Expected internal action to be hashed consistently, got %q in %q", internal, repo.Issues[1].Description)
+	}
+	if _, ok := result.Summary.UniqueActions[internal]; !ok {
+		t.Errorf("Expected %s in redacted usage statistics", internal)
+	}
+	if repo.Issues[0].FilePath != repo.WorkflowFiles[0].Path || repo.Issues[0].FilePath == repo.WorkflowFiles[1].Path {
+		t.Errorf("Expected file paths to map to stable, distinct placeholders")
+	}
+}
+
+func TestRedact_StableHashes(t *testing.T) {
+	first, second := redactTestResult(), redactTestResult()
+	first.Redact([]byte("test-key"))
+	second.Redact([]byte("test-key"))
+
+	if first.Repositories[0].FullName != second.Repositories[0].FullName {
+		t.Errorf("Expected stable hashes, got %q and %q", first.Repositories[0].FullName, second.Repositories[0].FullName)
+	}
+}
+
+func TestRedact_KeyedHashes(t *testing.T) {
+	first, second := redactTestResult(), redactTestResult()
+	first.Redact([]byte("test-key"))
+	second.Redact([]byte("other-key"))
+
+	if first.Owner == second.Owner || first.Repositories[0].FullName == second.Repositories[0].FullName {
+		t.Errorf("Expected different keys to give different placeholders, got %q and %q", first.Repositories[0].FullName, second.Repositories[0].FullName)
+	}
+
+	// Hashing a guessed name without the key must not reproduce the placeholder
+	guess := sha256.Sum256([]byte("my-org"))
+	if first.Owner == "org-"+hex.EncodeToString(guess[:])[:12] {
+		t.Errorf("Expected the owner placeholder to depend on the key, got %q", first.Owner)
+	}
+
+	key, err := NewRedactKey()
+	if err != nil || len(key) != 32 {
+		t.Fatalf("Expected a 32 byte random key, got %d bytes (%v)", len(key), err)
+	}
+	if other, _ := NewRedactKey(); string(other) == string(key) {
+		t.Errorf("Expected a new key on every call")
+	}
+}
+
+func TestRedact_Decisions(t *testing.T) {
+	result := redactTestResult()
+	repo := &result.Repositories[0]
+	deploy := NewDecision(workflow.ActionReference{Repository: "my-org/deploy-action", Version: "v1", FilePath: ".github/workflows/release.yml", Context: "job:deploy"})
+	deploy.Rule = "my-org/deploy-action"
+	deploy.AddStep(DecisionStageRule, DecisionMatched, "rule my-org/deploy-action applies")
+	shared := NewDecision(workflow.ActionReference{Repository: "my-org/shared", WorkflowPath: ".github/workflows/build.yml", Version: "v2", FilePath: ".github/workflows/ci.yml"})
+	shared.AddStep(DecisionStageRule, DecisionUnmatched, "the conditions of rule my-org/shared/.github/workflows/build.yml do not match my-org/payments-api")
+	local := NewDecision(workflow.ActionReference{Repository: "my-org/payments-api", WorkflowPath: ".github/workflows/reusable.yml", Local: true, FilePath: ".github/workflows/ci.yml"})
+	skipped := SkippedFileDecision(".github/workflows/experimental.yml", DecisionStageWorkflowFilter, "excluded by --exclude-workflow")
+	repo.Decisions = RecordReported([]Decision{deploy, shared, local, skipped}, repo.Issues, nil)
+	result.Redact([]byte("test-key"))
+
+	for _, decision := range result.Repositories[0].Decisions {
+		data, err := json.Marshal(decision)
+		if err != nil {
+			t.Fatalf("Marshal returned error: %v", err)
+		}
+		for _, secret := range []string{"my-org", "payments", "deploy-action", "shared", ".github/workflows"} {
+			if strings.Contains(string(data), secret) {
+				t.Errorf("Expected redacted decision not to contain %q: %s", secret, data)
+			}
+		}
+	}
+
+	redacted := result.Repositories[0]
+	if redacted.Decisions[0].Action != redacted.Issues[1].Repository+"@v1" || redacted.Decisions[0].FilePath != redacted.Issues[1].FilePath {
+		t.Errorf("Expected the decision to use the placeholders of its issue, got %+v", redacted.Decisions[0])
+	}
+	if !strings.HasPrefix(redacted.Decisions[2].Action, "./file-") {
+		t.Errorf("Expected the local workflow path to be hashed, got %q", redacted.Decisions[2].Action)
+	}
+}
@@ -0,0 +1,116 @@
+package output
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultDocsBaseURL is the page documenting every rule, with one section per issue type
+const DefaultDocsBaseURL = "https://github.com/Jake-Mok-Nelson/actions-maintainer/blob/main/docs/rules.md"
+
+// ruleIDs are the stable rule ids of the built-in issue types
+// Ids are never reused or renumbered, so new issue types take the next free number.
+var ruleIDs = map[string]string{
+	"outdated":                   "AM001",
+	"deprecated":                 "AM002",
+	"migration":                  "AM003",
+	"comment-drift":              "AM004",
+	"tag-moved":                  "AM005",
+	"banned-action":              "AM006",
+	"missing-required-action":    "AM007",
+	"risky-trigger":              "AM008",
+	"missing-timeout":            "AM009",
+	"continue-on-error":          "AM010",
+	"fail-fast-disabled":         "AM011",
+	"missing-concurrency":        "AM012",
+	"missing-cancel-in-progress": "AM013",
+	"cache-key":                  "AM014",
+	"missing-image-tag":          "AM015",
+	"stale-image":                "AM016",
+	"stale-workflow":             "AM017",
+	"brownout":                   "AM018",
+	"image-architecture":         "AM019",
+	"broken-reference":           "AM020",
+	"missing-required-workflow":  "AM021",
+	"unapproved-workflow":        "AM022",
+	"deprecated-release-action":  "AM023",
+	"release-pat":                "AM024",
+	"missing-provenance":         "AM025",
+	"lock-drift":                 "AM026",
+	"broken-call":                "AM027",
+	"risky-dispatch-default":     "AM028",
+	"job-graph":                  "AM029",
+}
+
+// RuleID returns the stable rule id of an issue type; issue types of registered checks without
+// a built-in id are their own rule id
+func RuleID(issueType string) string {
+	if id, ok := ruleIDs[issueType]; ok {
+		return id
+	}
+	return issueType
+}
+
+// RuleDocsURL returns the documentation of an issue type: the section named after the issue type
+// on the page at baseURL, or on DefaultDocsBaseURL when baseURL is empty
+func RuleDocsURL(baseURL, issueType string) string {
+	if baseURL == "" {
+		baseURL = DefaultDocsBaseURL
+	}
+	baseURL, _, _ = strings.Cut(baseURL, "#")
+	return baseURL + "#" + issueType
+}
+
+// DocsURL returns the documentation of the issue, recorded at scan time or from DefaultDocsBaseURL
+// for results written before rule documentation was recorded
+func (i ActionIssue) DocsURL() string {
+	if i.RuleDocs != "" {
+		return i.RuleDocs
+	}
+	return RuleDocsURL("", i.IssueType)
+}
+
+// AnnotateRules records the rule id and documentation URL of every issue, linking to the page at
+// docsBaseURL, or to DefaultDocsBaseURL when empty
+func AnnotateRules(repositories []RepositoryResult, docsBaseURL string) {
+	for r := range repositories {
+		for i := range repositories[r].Issues {
+			issue := &repositories[r].Issues[i]
+			issue.RuleID = RuleID(issue.IssueType)
+			issue.RuleDocs = RuleDocsURL(docsBaseURL, issue.IssueType)
+		}
+		for i := range repositories[r].SuppressedIssues {
+			issue := &repositories[r].SuppressedIssues[i].ActionIssue
+			issue.RuleID = RuleID(issue.IssueType)
+			issue.RuleDocs = RuleDocsURL(docsBaseURL, issue.IssueType)
+		}
+	}
+}
+
+// IssueRule is an issue type with its rule id and documentation
+type IssueRule struct {
+	IssueType string
+	ID        string
+	DocsURL   string
+}
+
+// IssueRules returns the rules of a set of issues, one per issue type, sorted by rule id
+func IssueRules(issues []ActionIssue) []IssueRule {
+	seen := make(map[string]bool)
+	var rules []IssueRule
+	for _, issue := range issues {
+		if seen[issue.IssueType] {
+			continue
+		}
+		seen[issue.IssueType] = true
+		id := issue.RuleID
+		if id == "" {
+			id = RuleID(issue.IssueType)
+		}
+		rules = append(rules, IssueRule{IssueType: issue.IssueType, ID: id, DocsURL: issue.DocsURL()})
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].ID < rules[j].ID
+	})
+	return rules
+}
@@ -0,0 +1,51 @@
+package output
+
+import "testing"
+
+func TestRuleDocsURL(t *testing.T) {
+	tests := []struct {
+		baseURL  string
+		expected string
+	}{
+		{"", DefaultDocsBaseURL + "#outdated"},
+		{"https://wiki.acme.example/actions-rules", "https://wiki.acme.example/actions-rules#outdated"},
+		{"https://wiki.acme.example/actions-rules#top", "https://wiki.acme.example/actions-rules#outdated"},
+	}
+	for _, tt := range tests {
+		if got := RuleDocsURL(tt.baseURL, "outdated"); got != tt.expected {
+			t.Errorf("RuleDocsURL(%q) = %q, expected %q", tt.baseURL, got, tt.expected)
+		}
+	}
+}
+
+func TestAnnotateRules(t *testing.T) {
+	repositories := []RepositoryResult{{
+		Issues:           []ActionIssue{{IssueType: "outdated"}, {IssueType: "custom-check"}},
+		SuppressedIssues: []SuppressedIssue{{ActionIssue: ActionIssue{IssueType: "banned-action"}}},
+	}}
+	AnnotateRules(repositories, "https://wiki.acme.example/rules")
+
+	issues := repositories[0].Issues
+	if issues[0].RuleID != "AM001" || issues[0].DocsURL() != "https://wiki.acme.example/rules#outdated" {
+		t.Errorf("Expected AM001 linking to the custom page, got %q %q", issues[0].RuleID, issues[0].DocsURL())
+	}
+	if issues[1].RuleID != "custom-check" {
+		t.Errorf("Expected an unknown issue type to be its own rule id, got %q", issues[1].RuleID)
+	}
+	if suppressed := repositories[0].SuppressedIssues[0]; suppressed.RuleID != "AM006" {
+		t.Errorf("Expected suppressed issues to be annotated, got %q", suppressed.RuleID)
+	}
+}
+
+func TestIssueRules(t *testing.T) {
+	rules := IssueRules([]ActionIssue{{IssueType: "deprecated"}, {IssueType: "outdated"}, {IssueType: "deprecated"}})
+	if len(rules) != 2 {
+		t.Fatalf("Expected one rule per issue type, got %+v", rules)
+	}
+	if rules[0].ID != "AM001" || rules[1].ID != "AM002" {
+		t.Errorf("Expected rules sorted by id, got %+v", rules)
+	}
+	if rules[1].DocsURL != DefaultDocsBaseURL+"#deprecated" {
+		t.Errorf("Expected issues without docs to link to the default page, got %q", rules[1].DocsURL)
+	}
+}
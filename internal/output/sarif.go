@@ -0,0 +1,155 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// sarifSchema is the SARIF 2.1.0 schema referenced by SARIF output
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// sarifLog is the root of a SARIF 2.1.0 log
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string            `json:"id"`
+	ShortDescription sarifMessage      `json:"shortDescription"`
+	HelpURI          string            `json:"helpUri,omitempty"`
+	Properties       map[string]string `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations"`
+	Properties map[string]string `json:"properties"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// IsSARIFFile reports whether an output path selects the SARIF format
+func IsSARIFFile(path string) bool {
+	return hasExtension(path, ".sarif")
+}
+
+// FormatSARIF writes the issues of a scan as a SARIF 2.1.0 log, one rule per issue type
+// Issue levels follow GitHub annotations: critical and high are errors, medium warnings,
+// and low or baseline issues notes. Locations are relative to each repository's root.
+// Rules keep the issue type as their id, so existing code scanning alerts stay matched, and
+// carry the stable rule id as a property and the rule documentation as their help URI.
+func FormatSARIF(result *ScanResult, writer io.Writer) error {
+	ruleDocs := make(map[string]string)
+	results := []sarifResult{}
+
+	for _, repo := range result.Repositories {
+		for _, issue := range repo.Issues {
+			if _, ok := ruleDocs[issue.IssueType]; !ok {
+				ruleDocs[issue.IssueType] = issue.DocsURL()
+			}
+
+			level := annotationLevel(issue)
+			if level == "notice" {
+				level = "note"
+			}
+
+			properties := map[string]string{
+				"repository": repo.FullName,
+				"severity":   issue.Severity,
+				"action":     issue.Repository,
+			}
+			if issue.CurrentVersion != "" {
+				properties["current_version"] = issue.CurrentVersion
+			}
+			if issue.SuggestedVersion != "" {
+				properties["suggested_version"] = issue.SuggestedVersion
+			}
+			// Metadata never overrides the properties above
+			for key, value := range issue.Metadata {
+				if _, ok := properties[key]; !ok {
+					properties[key] = value
+				}
+			}
+
+			results = append(results, sarifResult{
+				RuleID:  issue.IssueType,
+				Level:   level,
+				Message: sarifMessage{Text: annotationMessage(issue)},
+				Locations: []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: issue.FilePath},
+					},
+				}},
+				Properties: properties,
+			})
+		}
+	}
+
+	rules := make([]sarifRule, 0, len(ruleDocs))
+	for id, docs := range ruleDocs {
+		rules = append(rules, sarifRule{
+			ID:               id,
+			ShortDescription: sarifMessage{Text: fmt.Sprintf("%s action issue", id)},
+			HelpURI:          docs,
+			Properties:       map[string]string{"rule_id": RuleID(id)},
+		})
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].ID < rules[j].ID
+	})
+
+	document := sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "actions-maintainer",
+				InformationURI: "https://github.com/Jake-Mok-Nelson/actions-maintainer",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return fmt.Errorf("failed to encode SARIF: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestFormatSARIF(t *testing.T) {
+	result := &ScanResult{
+		Repositories: []RepositoryResult{{
+			FullName: "my-org/api",
+			Issues: []ActionIssue{
+				{Repository: "actions/checkout", CurrentVersion: "v2", SuggestedVersion: "v4", IssueType: "outdated", Severity: "medium", Description: "Outdated", FilePath: ".github/workflows/ci.yml"},
+				{Repository: "actions/setup-node", CurrentVersion: "v1", IssueType: "deprecated", Severity: "high", Description: "Deprecated", FilePath: ".github/workflows/ci.yml"},
+				{Repository: "actions/cache", CurrentVersion: "v3", IssueType: "outdated", Severity: "high", Description: "Known", FilePath: ".github/workflows/build.yml", Existing: true},
+			},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := FormatSARIF(result, &buf); err != nil {
+		t.Fatalf("FormatSARIF() returned error: %v", err)
+	}
+
+	var document sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &document); err != nil {
+		t.Fatalf("Failed to parse SARIF output: %v", err)
+	}
+	if document.Version != "2.1.0" || len(document.Runs) != 1 {
+		t.Fatalf("Expected a single SARIF 2.1.0 run, got version %q with %d runs", document.Version, len(document.Runs))
+	}
+
+	run := document.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 || run.Tool.Driver.Rules[0].ID != "deprecated" || run.Tool.Driver.Rules[1].ID != "outdated" {
+		t.Errorf("Expected sorted rules deprecated and outdated, got %+v", run.Tool.Driver.Rules)
+	}
+	if rule := run.Tool.Driver.Rules[1]; rule.HelpURI != DefaultDocsBaseURL+"#outdated" || rule.Properties["rule_id"] != "AM001" {
+		t.Errorf("Expected outdated rule to link its documentation with rule id AM001, got %+v", rule)
+	}
+	if len(run.Results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(run.Results))
+	}
+
+	expectedLevels := []string{"warning", "error", "note"}
+	for i, level := range expectedLevels {
+		if run.Results[i].Level != level {
+			t.Errorf("Expected result %d level %q, got %q", i, level, run.Results[i].Level)
+		}
+	}
+	if uri := run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI; uri != ".github/workflows/ci.yml" {
+		t.Errorf("Expected location .github/workflows/ci.yml, got %q", uri)
+	}
+	if run.Results[0].Message.Text != "Outdated (suggested version: v4)" {
+		t.Errorf("Unexpected message %q", run.Results[0].Message.Text)
+	}
+	if run.Results[0].Properties["repository"] != "my-org/api" {
+		t.Errorf("Expected repository property my-org/api, got %q", run.Results[0].Properties["repository"])
+	}
+}
+
+func TestFormatSARIF_NoIssues(t *testing.T) {
+	var buf bytes.Buffer
+	if err := FormatSARIF(&ScanResult{}, &buf); err != nil {
+		t.Fatalf("FormatSARIF() returned error: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"results": []`)) {
+		t.Errorf("Expected an empty results array, got %s", buf.String())
+	}
+}
+
+func TestFormatSARIF_Metadata(t *testing.T) {
+	issue := ActionIssue{Repository: "tj-actions/changed-files", CurrentVersion: "v45", IssueType: "vulnerable", Severity: "critical", Description: "Compromised", FilePath: ".github/workflows/ci.yml"}
+	issue.SetMetadata(MetadataCVE, "CVE-2025-30066")
+	issue.SetMetadata("severity", "overridden")
+	result := &ScanResult{Repositories: []RepositoryResult{{FullName: "my-org/api", Issues: []ActionIssue{issue}}}}
+
+	var buf bytes.Buffer
+	if err := FormatSARIF(result, &buf); err != nil {
+		t.Fatalf("FormatSARIF() returned error: %v", err)
+	}
+	var document sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &document); err != nil {
+		t.Fatalf("Failed to parse SARIF output: %v", err)
+	}
+
+	properties := document.Runs[0].Results[0].Properties
+	if properties[MetadataCVE] != "CVE-2025-30066" {
+		t.Errorf("Expected cve property CVE-2025-30066, got %q", properties[MetadataCVE])
+	}
+	if properties["severity"] != "critical" {
+		t.Errorf("Expected metadata not to override severity, got %q", properties["severity"])
+	}
+}
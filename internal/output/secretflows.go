@@ -0,0 +1,100 @@
+package output
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// SecretExposure is the secrets and variables one third-party action receives across repositories
+type SecretExposure struct {
+	Action       string
+	Secrets      []string // Secret names, with their scope when resolved, e.g. "NPM_TOKEN (organization)"
+	Variables    []string // Variable names, with their scope when resolved
+	Repositories []string
+	Flows        []RepositorySecretFlow
+}
+
+// RepositorySecretFlow is a secret flow with the repository it was found in
+type RepositorySecretFlow struct {
+	Repository string
+	workflow.SecretFlow
+}
+
+// ThirdPartySecretExposure groups the secret flows into third-party actions by action, most secrets first
+// Actions owned by the scanned repository's owner or by GitHub are left out.
+func ThirdPartySecretExposure(result *ScanResult) []SecretExposure {
+	type exposureSets struct {
+		exposure     *SecretExposure
+		secrets      map[string]bool
+		variables    map[string]bool
+		repositories map[string]bool
+	}
+
+	byAction := make(map[string]*exposureSets)
+	for _, repo := range result.Repositories {
+		owner, _, _ := strings.Cut(repo.FullName, "/")
+		for _, flow := range repo.SecretFlows {
+			if !flow.ThirdParty(owner) {
+				continue
+			}
+
+			sets, ok := byAction[flow.Action]
+			if !ok {
+				sets = &exposureSets{
+					exposure:     &SecretExposure{Action: flow.Action},
+					secrets:      make(map[string]bool),
+					variables:    make(map[string]bool),
+					repositories: make(map[string]bool),
+				}
+				byAction[flow.Action] = sets
+			}
+
+			name := flow.Name
+			if flow.Scope != "" {
+				name += " (" + flow.Scope + ")"
+			}
+			if flow.Kind == workflow.FlowKindVariable {
+				sets.variables[name] = true
+			} else {
+				sets.secrets[name] = true
+			}
+			sets.repositories[repo.FullName] = true
+			sets.exposure.Flows = append(sets.exposure.Flows, RepositorySecretFlow{Repository: repo.FullName, SecretFlow: flow})
+		}
+	}
+
+	exposures := make([]SecretExposure, 0, len(byAction))
+	for _, sets := range byAction {
+		exposure := sets.exposure
+		exposure.Secrets = sortedKeys(sets.secrets)
+		exposure.Variables = sortedKeys(sets.variables)
+		exposure.Repositories = sortedKeys(sets.repositories)
+		exposures = append(exposures, *exposure)
+	}
+
+	sort.Slice(exposures, func(i, j int) bool {
+		if len(exposures[i].Secrets) != len(exposures[j].Secrets) {
+			return len(exposures[i].Secrets) > len(exposures[j].Secrets)
+		}
+		if len(exposures[i].Repositories) != len(exposures[j].Repositories) {
+			return len(exposures[i].Repositories) > len(exposures[j].Repositories)
+		}
+		return exposures[i].Action < exposures[j].Action
+	})
+	return exposures
+}
+
+// sortedKeys returns the keys of a set in sorted order
+func sortedKeys(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,91 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+func secretFlowsResult() *ScanResult {
+	return &ScanResult{
+		Owner: "my-org",
+		Repositories: []RepositoryResult{
+			{
+				FullName: "my-org/api",
+				SecretFlows: []workflow.SecretFlow{
+					{FilePath: ".github/workflows/ci.yml", Job: "build", Step: "#1", Action: "actions/checkout", Via: workflow.FlowViaWith, Input: "token", Kind: workflow.FlowKindSecret, Name: "GITHUB_TOKEN"},
+					{FilePath: ".github/workflows/ci.yml", Job: "build", Step: "Publish", Action: "acme/publish", Via: workflow.FlowViaWith, Input: "token", Kind: workflow.FlowKindSecret, Name: "NPM_TOKEN", Scope: "organization"},
+					{FilePath: ".github/workflows/ci.yml", Job: "build", Step: "Publish", Action: "acme/publish", Via: workflow.FlowViaEnv, Input: "REGION", Kind: workflow.FlowKindVariable, Name: "REGION"},
+					{FilePath: ".github/workflows/ci.yml", Job: "build", Step: "Deploy", Action: "my-org/deploy-action", Via: workflow.FlowViaWith, Input: "key", Kind: workflow.FlowKindSecret, Name: "DEPLOY_KEY"},
+				},
+			},
+			{
+				FullName: "my-org/web",
+				SecretFlows: []workflow.SecretFlow{
+					{FilePath: ".github/workflows/ci.yml", Job: "notify", Step: "#2", Action: "acme/slack", Via: workflow.FlowViaEnv, Input: "WEBHOOK", Kind: workflow.FlowKindSecret, Name: "SLACK_WEBHOOK"},
+					{FilePath: ".github/workflows/ci.yml", Job: "publish", Step: "#1", Action: "acme/publish", Via: workflow.FlowViaWith, Input: "token", Kind: workflow.FlowKindSecret, Name: "NPM_TOKEN", Scope: "organization"},
+				},
+			},
+		},
+	}
+}
+
+func TestThirdPartySecretExposure(t *testing.T) {
+	exposures := ThirdPartySecretExposure(secretFlowsResult())
+
+	// GitHub and internal actions are left out
+	if len(exposures) != 2 {
+		t.Fatalf("Expected 2 third-party actions, got %d: %+v", len(exposures), exposures)
+	}
+
+	publish := exposures[0]
+	if publish.Action != "acme/publish" {
+		t.Fatalf("Expected acme/publish first, got %s", publish.Action)
+	}
+	if strings.Join(publish.Secrets, ",") != "NPM_TOKEN (organization)" {
+		t.Errorf("Expected the shared organization secret once, got %v", publish.Secrets)
+	}
+	if strings.Join(publish.Variables, ",") != "REGION" {
+		t.Errorf("Expected variable REGION, got %v", publish.Variables)
+	}
+	if strings.Join(publish.Repositories, ",") != "my-org/api,my-org/web" || len(publish.Flows) != 3 {
+		t.Errorf("Expected 3 flows from 2 repositories, got %d from %v", len(publish.Flows), publish.Repositories)
+	}
+	if exposures[1].Action != "acme/slack" {
+		t.Errorf("Expected acme/slack second, got %s", exposures[1].Action)
+	}
+}
+
+func TestCreateSecretFlowsCell(t *testing.T) {
+	cell := createSecretFlowsCell(ThirdPartySecretExposure(secretFlowsResult()))
+	source := strings.Join(cell.Source, "")
+
+	for _, want := range []string{
+		"## 🔐 Secret Data Flow",
+		"**2** third-party actions receive secrets or variables from **2** repositories",
+		"| `acme/publish` | `NPM_TOKEN (organization)` | `REGION` | 2 |",
+		"| `acme/slack` | `SLACK_WEBHOOK` | - | 1 |",
+		"| my-org/api | `.github/workflows/ci.yml` | build / Publish | `acme/publish` | with `token` | secret `NPM_TOKEN` |",
+	} {
+		if !strings.Contains(source, want) {
+			t.Errorf("Expected section to contain %q, got:\n%s", want, source)
+		}
+	}
+	if strings.Contains(source, "my-org/deploy-action") {
+		t.Error("Expected internal actions to be left out")
+	}
+}
+
+func TestRedact_SecretFlows(t *testing.T) {
+	result := secretFlowsResult()
+	result.Redact([]byte("test-key"))
+
+	flow := result.Repositories[0].SecretFlows[3]
+	if strings.Contains(flow.Action, "my-org") || flow.Name == "DEPLOY_KEY" || flow.Job != "" || flow.Step != "" {
+		t.Errorf("Expected internal action, secret name, job, and step to be redacted, got %+v", flow)
+	}
+	if public := result.Repositories[0].SecretFlows[1]; public.Action != "acme/publish" || public.Name == "NPM_TOKEN" {
+		t.Errorf("Expected public action kept and secret name hashed, got %+v", public)
+	}
+}
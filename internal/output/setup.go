@@ -0,0 +1,106 @@
+package output
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// SetupPatternNone is the pattern of repositories whose workflows never set up their language's
+// toolchain, relying on the version preinstalled on the runner
+const SetupPatternNone = "no setup step"
+
+// SetupPattern is a way of setting up a toolchain and the repositories using it
+type SetupPattern struct {
+	Pattern      string   // e.g. "actions/setup-go", "manual install", or several joined with " + "
+	Repositories []string // sorted
+}
+
+// LanguageSetup is the toolchain setup report of the repositories of one language
+type LanguageSetup struct {
+	Language     string
+	Tool         string
+	Repositories int
+	Patterns     []SetupPattern // most repositories first
+	Standard     string         // The most common pattern
+	Divergent    []string       // Repositories using another pattern, sorted
+}
+
+// Consistent reports whether every repository of the language sets up its toolchain the same way
+func (l LanguageSetup) Consistent() bool {
+	return len(l.Patterns) <= 1
+}
+
+// SetupConsistency groups repositories with workflows by their primary language and reports how each
+// sets up the language's toolchain, most common languages first. Languages without a tracked toolchain
+// are skipped.
+func SetupConsistency(result *ScanResult) []LanguageSetup {
+	byLanguage := make(map[string]map[string][]string) // language -> pattern -> repositories
+	for _, repo := range result.Repositories {
+		// Repositories without workflows have nothing to standardize
+		tool := workflow.LanguageTool(repo.Language)
+		if tool == "" || len(repo.WorkflowFiles) == 0 {
+			continue
+		}
+
+		methods := make(map[string]bool)
+		for _, setup := range repo.ToolSetups {
+			if setup.Tool == tool {
+				methods[setupPattern(setup)] = true
+			}
+		}
+		pattern := SetupPatternNone
+		if len(methods) > 0 {
+			pattern = strings.Join(sortedKeys(methods), " + ")
+		}
+
+		if byLanguage[repo.Language] == nil {
+			byLanguage[repo.Language] = make(map[string][]string)
+		}
+		byLanguage[repo.Language][pattern] = append(byLanguage[repo.Language][pattern], repo.FullName)
+	}
+
+	languages := make([]LanguageSetup, 0, len(byLanguage))
+	for language, patterns := range byLanguage {
+		setup := LanguageSetup{Language: language, Tool: workflow.LanguageTool(language)}
+		for pattern, repositories := range patterns {
+			sort.Strings(repositories)
+			setup.Patterns = append(setup.Patterns, SetupPattern{Pattern: pattern, Repositories: repositories})
+			setup.Repositories += len(repositories)
+		}
+		sort.Slice(setup.Patterns, func(i, j int) bool {
+			if len(setup.Patterns[i].Repositories) != len(setup.Patterns[j].Repositories) {
+				return len(setup.Patterns[i].Repositories) > len(setup.Patterns[j].Repositories)
+			}
+			return setup.Patterns[i].Pattern < setup.Patterns[j].Pattern
+		})
+
+		setup.Standard = setup.Patterns[0].Pattern
+		for _, pattern := range setup.Patterns[1:] {
+			setup.Divergent = append(setup.Divergent, pattern.Repositories...)
+		}
+		sort.Strings(setup.Divergent)
+		languages = append(languages, setup)
+	}
+
+	sort.Slice(languages, func(i, j int) bool {
+		if languages[i].Repositories != languages[j].Repositories {
+			return languages[i].Repositories > languages[j].Repositories
+		}
+		return languages[i].Language < languages[j].Language
+	})
+	return languages
+}
+
+// setupPattern names how a toolchain is set up; setup actions are named, other methods are grouped
+func setupPattern(setup workflow.ToolSetup) string {
+	switch setup.Method {
+	case workflow.SetupMethodAction:
+		return strings.ToLower(setup.Detail)
+	case workflow.SetupMethodContainer:
+		return "container image"
+	default:
+		return "manual install"
+	}
+}
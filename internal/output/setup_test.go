@@ -0,0 +1,70 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+func setupResult() *ScanResult {
+	files := []WorkflowFileResult{{Path: ".github/workflows/ci.yml"}}
+	goAction := workflow.ToolSetup{FilePath: ".github/workflows/ci.yml", Job: "build", Tool: "go", Method: workflow.SetupMethodAction, Detail: "actions/setup-go"}
+	goManual := workflow.ToolSetup{FilePath: ".github/workflows/ci.yml", Job: "legacy", Tool: "go", Method: workflow.SetupMethodManual, Detail: "go.dev/dl/"}
+	nodeAction := workflow.ToolSetup{FilePath: ".github/workflows/ci.yml", Job: "lint", Tool: "node", Method: workflow.SetupMethodAction, Detail: "actions/setup-node"}
+
+	return &ScanResult{
+		Owner: "my-org",
+		Repositories: []RepositoryResult{
+			{FullName: "my-org/api", Language: "Go", WorkflowFiles: files, ToolSetups: []workflow.ToolSetup{goAction, nodeAction}},
+			{FullName: "my-org/worker", Language: "Go", WorkflowFiles: files, ToolSetups: []workflow.ToolSetup{goAction}},
+			{FullName: "my-org/legacy", Language: "Go", WorkflowFiles: files, ToolSetups: []workflow.ToolSetup{goManual}},
+			{FullName: "my-org/cli", Language: "Go", WorkflowFiles: files},
+			{FullName: "my-org/web", Language: "TypeScript", WorkflowFiles: files, ToolSetups: []workflow.ToolSetup{nodeAction}},
+			{FullName: "my-org/infra", Language: "HCL", WorkflowFiles: files},
+			{FullName: "my-org/empty", Language: "Go"},
+		},
+	}
+}
+
+func TestSetupConsistency(t *testing.T) {
+	languages := SetupConsistency(setupResult())
+	if len(languages) != 2 {
+		t.Fatalf("Expected Go and TypeScript, got %+v", languages)
+	}
+
+	golang := languages[0]
+	if golang.Language != "Go" || golang.Repositories != 4 || golang.Consistent() {
+		t.Errorf("Expected 4 inconsistent Go repositories first, got %+v", golang)
+	}
+	if golang.Standard != "actions/setup-go" {
+		t.Errorf("Expected actions/setup-go as the Go standard, got %s", golang.Standard)
+	}
+	// The node setup of a Go repository does not count toward its Go pattern
+	if strings.Join(golang.Patterns[0].Repositories, ",") != "my-org/api,my-org/worker" {
+		t.Errorf("Expected api and worker on the standard pattern, got %v", golang.Patterns[0].Repositories)
+	}
+	if strings.Join(golang.Divergent, ",") != "my-org/cli,my-org/legacy" {
+		t.Errorf("Expected cli and legacy to diverge, got %v", golang.Divergent)
+	}
+
+	if typescript := languages[1]; typescript.Tool != "node" || !typescript.Consistent() {
+		t.Errorf("Expected a consistent TypeScript report, got %+v", typescript)
+	}
+}
+
+func TestCreateSetupConsistencyCell(t *testing.T) {
+	source := strings.Join(createSetupConsistencyCell(SetupConsistency(setupResult())).Source, "")
+
+	for _, want := range []string{
+		"## 🧰 Toolchain Setup Consistency",
+		"**1** of **2** languages set up their toolchain in more than one way.",
+		"| Go | 4 | actions/setup-go (2), manual install (1), no setup step (1) | actions/setup-go | 2 |",
+		"| TypeScript | 1 | actions/setup-node (1) | actions/setup-node | 0 |",
+		"| Go | my-org/legacy | manual install | actions/setup-go |",
+	} {
+		if !strings.Contains(source, want) {
+			t.Errorf("Expected section to contain %q, got:\n%s", want, source)
+		}
+	}
+}
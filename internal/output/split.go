@@ -0,0 +1,190 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/compress"
+)
+
+// Split modes for scan --split-output-by
+const (
+	SplitByRepoCount = "repo-count" // Chunks of at most N repositories
+	SplitByOwner     = "owner"      // One chunk per repository owner
+)
+
+// SplitSpec describes how scan results are split into chunk files
+type SplitSpec struct {
+	Mode      string
+	RepoCount int // Repositories per chunk (SplitByRepoCount only)
+}
+
+// ScanIndex lists the chunk files of a split scan alongside the summary of the whole scan
+type ScanIndex struct {
+	Owner       string        `json:"owner"`
+	ScanTime    time.Time     `json:"scan_time"`
+	ScanEndTime time.Time     `json:"scan_end_time"`
+	Duration    time.Duration `json:"duration"`
+	SplitBy     string        `json:"split_by"`
+	Summary     Summary       `json:"summary"`
+	Chunks      []ScanChunk   `json:"chunks"`
+
+	// Org-level analysis spans chunks, so it is recorded once in the index
+	ReusableWorkflowCandidates []DuplicateStepCluster `json:"reusable_workflow_candidates,omitempty"`
+	TagProtectionFindings      []TagProtectionFinding `json:"tag_protection_findings,omitempty"`
+	MajorTagFindings           []MajorTagFinding      `json:"major_tag_findings,omitempty"`
+	InternalActions            []InternalAction       `json:"internal_actions,omitempty"`
+}
+
+// ScanChunk describes one chunk file of a split scan
+type ScanChunk struct {
+	File         string   `json:"file"` // Path relative to the index file
+	Owner        string   `json:"owner"`
+	Repositories []string `json:"repositories"` // Full names of the repositories in the chunk
+	Issues       int      `json:"issues"`
+}
+
+// ParseSplitSpec parses "repo-count=N" or "owner"
+func ParseSplitSpec(value string) (SplitSpec, error) {
+	if value == SplitByOwner {
+		return SplitSpec{Mode: SplitByOwner}, nil
+	}
+
+	if count, found := strings.CutPrefix(value, SplitByRepoCount+"="); found {
+		n, err := strconv.Atoi(count)
+		if err != nil || n < 1 {
+			return SplitSpec{}, fmt.Errorf("invalid repository count %q: must be a positive integer", count)
+		}
+		return SplitSpec{Mode: SplitByRepoCount, RepoCount: n}, nil
+	}
+
+	return SplitSpec{}, fmt.Errorf("invalid split %q: use %s=N or %s", value, SplitByRepoCount, SplitByOwner)
+}
+
+// String returns the spec in the form accepted by ParseSplitSpec
+func (s SplitSpec) String() string {
+	if s.Mode == SplitByRepoCount {
+		return fmt.Sprintf("%s=%d", SplitByRepoCount, s.RepoCount)
+	}
+	return s.Mode
+}
+
+// SplitScanResult splits a scan result into chunks, each a complete scan result with its own summary,
+// and builds the index describing them. Chunk file names are derived from the index path.
+func SplitScanResult(result *ScanResult, spec SplitSpec, indexPath string) (*ScanIndex, []*ScanResult) {
+	index := &ScanIndex{
+		Owner:                      result.Owner,
+		ScanTime:                   result.ScanTime,
+		ScanEndTime:                result.ScanEndTime,
+		Duration:                   result.Duration,
+		SplitBy:                    spec.String(),
+		Summary:                    result.Summary,
+		Chunks:                     []ScanChunk{},
+		ReusableWorkflowCandidates: result.ReusableWorkflowCandidates,
+		TagProtectionFindings:      result.TagProtectionFindings,
+		MajorTagFindings:           result.MajorTagFindings,
+		InternalActions:            result.InternalActions,
+	}
+
+	var chunks []*ScanResult
+	for i, group := range groupRepositories(result.Repositories, spec) {
+		owner := result.Owner
+		suffix := fmt.Sprintf("%03d", i+1)
+		if spec.Mode == SplitByOwner {
+			owner = repositoryOwner(group[0].FullName)
+			suffix = owner
+		}
+
+		chunk := &ScanResult{
+			Owner:        owner,
+			ScanTime:     result.ScanTime,
+			ScanEndTime:  result.ScanEndTime,
+			Duration:     result.Duration,
+			Repositories: group,
+			Summary:      calculateSummary(group),
+			CreatedPRs:   createdPRsFor(result.CreatedPRs, group),
+		}
+		chunks = append(chunks, chunk)
+
+		entry := ScanChunk{
+			File:  filepath.Base(ChunkFileName(indexPath, suffix)),
+			Owner: owner,
+		}
+		for _, repo := range group {
+			entry.Repositories = append(entry.Repositories, repo.FullName)
+			entry.Issues += len(repo.Issues)
+		}
+		index.Chunks = append(index.Chunks, entry)
+	}
+
+	return index, chunks
+}
+
+// ChunkFileName derives a chunk file name from the index path, e.g. "scan.json" -> "scan-001.json"
+// and "scan.json.gz" -> "scan-001.json.gz"
+func ChunkFileName(indexPath, suffix string) string {
+	indexPath, compression := compress.TrimExtension(indexPath)
+	ext := filepath.Ext(indexPath)
+	return strings.TrimSuffix(indexPath, ext) + "-" + suffix + ext + compression
+}
+
+// IsScanIndex reports whether JSON data is a split scan index rather than scan results
+func IsScanIndex(data []byte) bool {
+	if !bytes.Contains(data, []byte(`"chunks"`)) {
+		return false
+	}
+	var probe struct {
+		Chunks []ScanChunk `json:"chunks"`
+	}
+	return json.Unmarshal(data, &probe) == nil && probe.Chunks != nil
+}
+
+// groupRepositories partitions sorted repositories according to the split spec
+func groupRepositories(repositories []RepositoryResult, spec SplitSpec) [][]RepositoryResult {
+	var groups [][]RepositoryResult
+	switch spec.Mode {
+	case SplitByOwner:
+		// Repositories are sorted by full name, so each owner's repositories are adjacent
+		for _, repo := range repositories {
+			last := len(groups) - 1
+			if last >= 0 && repositoryOwner(groups[last][0].FullName) == repositoryOwner(repo.FullName) {
+				groups[last] = append(groups[last], repo)
+			} else {
+				groups = append(groups, []RepositoryResult{repo})
+			}
+		}
+	default:
+		for start := 0; start < len(repositories); start += spec.RepoCount {
+			end := min(start+spec.RepoCount, len(repositories))
+			groups = append(groups, repositories[start:end])
+		}
+	}
+	return groups
+}
+
+// repositoryOwner returns the owner part of a repository full name
+func repositoryOwner(fullName string) string {
+	owner, _, _ := strings.Cut(fullName, "/")
+	return owner
+}
+
+// createdPRsFor returns the created PRs belonging to the given repositories
+func createdPRsFor(prs []CreatedPR, repositories []RepositoryResult) []CreatedPR {
+	names := make(map[string]bool, len(repositories))
+	for _, repo := range repositories {
+		names[repo.FullName] = true
+	}
+
+	filtered := []CreatedPR{}
+	for _, pr := range prs {
+		if names[pr.Repository] {
+			filtered = append(filtered, pr)
+		}
+	}
+	return filtered
+}
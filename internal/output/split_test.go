@@ -0,0 +1,118 @@
+package output
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseSplitSpec(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected SplitSpec
+		hasError bool
+	}{
+		{"repo-count=500", SplitSpec{Mode: SplitByRepoCount, RepoCount: 500}, false},
+		{"owner", SplitSpec{Mode: SplitByOwner}, false},
+		{"repo-count=0", SplitSpec{}, true},
+		{"repo-count=many", SplitSpec{}, true},
+		{"team", SplitSpec{}, true},
+	}
+
+	for _, test := range tests {
+		spec, err := ParseSplitSpec(test.value)
+		if (err != nil) != test.hasError {
+			t.Errorf("ParseSplitSpec(%q) error = %v, expected error: %v", test.value, err, test.hasError)
+			continue
+		}
+		if spec != test.expected {
+			t.Errorf("ParseSplitSpec(%q) = %+v, expected %+v", test.value, spec, test.expected)
+		}
+		if !test.hasError && spec.String() != test.value {
+			t.Errorf("Expected %+v to format as %q, got %q", spec, test.value, spec.String())
+		}
+	}
+}
+
+func splitTestResult() *ScanResult {
+	repositories := []RepositoryResult{
+		{Name: "api", FullName: "my-org/api", Issues: []ActionIssue{{Repository: "actions/checkout", IssueType: "outdated", Severity: "medium"}}},
+		{Name: "web", FullName: "my-org/web"},
+		{Name: "tools", FullName: "other-org/tools", Issues: []ActionIssue{{Repository: "actions/cache", IssueType: "outdated", Severity: "high"}, {Repository: "actions/setup-go", IssueType: "deprecated", Severity: "high"}}},
+	}
+	result := BuildScanResult("my-org", repositories)
+	result.CreatedPRs = []CreatedPR{{Repository: "other-org/tools", Number: 7}}
+	result.ReusableWorkflowCandidates = []DuplicateStepCluster{{Fingerprint: "abc"}}
+	return result
+}
+
+func TestSplitScanResult_RepoCount(t *testing.T) {
+	result := splitTestResult()
+	index, chunks := SplitScanResult(result, SplitSpec{Mode: SplitByRepoCount, RepoCount: 2}, "out/scan.json")
+
+	if len(chunks) != 2 || len(index.Chunks) != 2 {
+		t.Fatalf("Expected 2 chunks, got %d (index lists %d)", len(chunks), len(index.Chunks))
+	}
+	if index.Chunks[0].File != "scan-001.json" || index.Chunks[1].File != "scan-002.json" {
+		t.Errorf("Unexpected chunk files: %q, %q", index.Chunks[0].File, index.Chunks[1].File)
+	}
+	if !reflect.DeepEqual(index.Chunks[0].Repositories, []string{"my-org/api", "my-org/web"}) || index.Chunks[0].Issues != 1 {
+		t.Errorf("Unexpected first chunk: %+v", index.Chunks[0])
+	}
+
+	// Each chunk is a complete scan result summarizing only its own repositories
+	if chunks[0].Summary.TotalRepositories != 2 || chunks[1].Summary.TotalRepositories != 1 {
+		t.Errorf("Expected chunk summaries of 2 and 1 repositories, got %d and %d", chunks[0].Summary.TotalRepositories, chunks[1].Summary.TotalRepositories)
+	}
+	if len(chunks[0].CreatedPRs) != 0 || len(chunks[1].CreatedPRs) != 1 {
+		t.Errorf("Expected created PRs to follow their repository")
+	}
+
+	// The index keeps the summary of the whole scan and org-level analysis
+	if index.Summary.TotalRepositories != 3 || index.SplitBy != "repo-count=2" || len(index.ReusableWorkflowCandidates) != 1 {
+		t.Errorf("Unexpected index: %+v", index)
+	}
+}
+
+func TestSplitScanResult_Owner(t *testing.T) {
+	index, chunks := SplitScanResult(splitTestResult(), SplitSpec{Mode: SplitByOwner}, "scan.json")
+
+	if len(chunks) != 2 {
+		t.Fatalf("Expected one chunk per owner, got %d", len(chunks))
+	}
+	if index.Chunks[0].File != "scan-my-org.json" || index.Chunks[1].File != "scan-other-org.json" {
+		t.Errorf("Unexpected chunk files: %q, %q", index.Chunks[0].File, index.Chunks[1].File)
+	}
+	if chunks[1].Owner != "other-org" || index.Chunks[1].Issues != 2 {
+		t.Errorf("Unexpected other-org chunk: owner=%q issues=%d", chunks[1].Owner, index.Chunks[1].Issues)
+	}
+}
+
+func TestChunkFileName_Compressed(t *testing.T) {
+	if name := ChunkFileName("out/scan.json.gz", "001"); name != "out/scan-001.json.gz" {
+		t.Errorf("Expected out/scan-001.json.gz, got %q", name)
+	}
+}
+
+func TestIsScanIndex(t *testing.T) {
+	index, chunks := SplitScanResult(BuildScanResult("my-org", nil), SplitSpec{Mode: SplitByOwner}, "scan.json")
+	if len(chunks) != 0 {
+		t.Errorf("Expected no chunks for an empty scan, got %d", len(chunks))
+	}
+
+	data, err := json.Marshal(index)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	if !IsScanIndex(data) {
+		t.Errorf("Expected an index to be detected, even with no chunks")
+	}
+
+	data, err = json.Marshal(splitTestResult())
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	if IsScanIndex(data) {
+		t.Errorf("Expected scan results not to be detected as an index")
+	}
+}
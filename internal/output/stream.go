@@ -0,0 +1,216 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// DecodeScanResult reads scan results one repository at a time, calling fn for each repository
+// as it is decoded. The returned result has every field except Repositories, which are left to fn.
+//
+// Input is either a scan result object or NDJSON with one repository result per line. NDJSON has
+// no scan-level fields, so the owner is taken from the first repository and the summary is calculated.
+func DecodeScanResult(r io.Reader, fn func(*RepositoryResult) error) (*ScanResult, error) {
+	decoder := json.NewDecoder(bufio.NewReader(r))
+
+	if err := expectDelim(decoder, '{'); err != nil {
+		return nil, err
+	}
+
+	// Scan-level fields are collected raw and decoded together once the object ends
+	fields := make(map[string]json.RawMessage)
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read scan results: %w", err)
+		}
+		key, _ := token.(string)
+
+		switch key {
+		case "repositories":
+			if err := decodeRepositories(decoder, fn); err != nil {
+				return nil, err
+			}
+		case "chunks":
+			return nil, fmt.Errorf("input is a split scan index; run the command on each chunk file listed in its chunks")
+		default:
+			var value json.RawMessage
+			if err := decoder.Decode(&value); err != nil {
+				return nil, fmt.Errorf("failed to read scan results field %q: %w", key, err)
+			}
+			fields[key] = value
+		}
+	}
+	if err := expectDelim(decoder, '}'); err != nil {
+		return nil, err
+	}
+
+	// A repository result as the first value means NDJSON
+	if _, isRepository := fields["full_name"]; isRepository {
+		return decodeRepositoryLines(decoder, fields, fn)
+	}
+
+	var result ScanResult
+	if err := unmarshalFields(fields, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse scan results: %w", err)
+	}
+	return &result, nil
+}
+
+// decodeRepositories streams the elements of the repositories array to fn
+func decodeRepositories(decoder *json.Decoder, fn func(*RepositoryResult) error) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("failed to read repositories: %w", err)
+	}
+	if token == nil {
+		return nil // "repositories": null
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("failed to read repositories: expected an array")
+	}
+
+	for decoder.More() {
+		var repo RepositoryResult
+		if err := decoder.Decode(&repo); err != nil {
+			return fmt.Errorf("failed to read repository: %w", err)
+		}
+		if err := fn(&repo); err != nil {
+			return err
+		}
+	}
+	return expectDelim(decoder, ']')
+}
+
+// decodeRepositoryLines streams NDJSON repository results, starting with the already decoded first line
+func decodeRepositoryLines(decoder *json.Decoder, first map[string]json.RawMessage, fn func(*RepositoryResult) error) (*ScanResult, error) {
+	result := &ScanResult{CreatedPRs: []CreatedPR{}}
+	summary := newSummaryBuilder()
+
+	handle := func(repo *RepositoryResult) error {
+		if result.Owner == "" {
+			result.Owner = repositoryOwner(repo.FullName)
+		}
+		summary.add(*repo)
+		return fn(repo)
+	}
+
+	var repo RepositoryResult
+	if err := unmarshalFields(first, &repo); err != nil {
+		return nil, fmt.Errorf("failed to read repository: %w", err)
+	}
+	if err := handle(&repo); err != nil {
+		return nil, err
+	}
+
+	for {
+		var repo RepositoryResult
+		if err := decoder.Decode(&repo); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read repository: %w", err)
+		}
+		if err := handle(&repo); err != nil {
+			return nil, err
+		}
+	}
+
+	result.Summary = summary.build()
+	return result, nil
+}
+
+// expectDelim reads the next token and checks it is the given delimiter
+func expectDelim(decoder *json.Decoder, expected json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("failed to read scan results: %w", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != expected {
+		return fmt.Errorf("failed to read scan results: expected %q, got %v", expected, token)
+	}
+	return nil
+}
+
+// unmarshalFields decodes raw object fields into a struct
+func unmarshalFields(fields map[string]json.RawMessage, target any) error {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+// JSONStreamWriter writes scan results as JSON one repository at a time
+// Repositories are written first, followed by the other scan-level fields when the writer is closed.
+type JSONStreamWriter struct {
+	writer io.Writer
+	count  int
+	err    error
+}
+
+// NewJSONStreamWriter starts a JSON scan result on w
+func NewJSONStreamWriter(w io.Writer) *JSONStreamWriter {
+	stream := &JSONStreamWriter{writer: w}
+	_, stream.err = io.WriteString(w, "{\n  \"repositories\": [")
+	return stream
+}
+
+// WriteRepository appends a repository to the repositories array
+func (s *JSONStreamWriter) WriteRepository(repo *RepositoryResult) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	data, err := json.MarshalIndent(repo, "    ", "  ")
+	if err != nil {
+		s.err = fmt.Errorf("failed to marshal JSON: %w", err)
+		return s.err
+	}
+
+	separator := "\n    "
+	if s.count > 0 {
+		separator = ",\n    "
+	}
+	s.count++
+	if _, err := io.WriteString(s.writer, separator); err != nil {
+		s.err = fmt.Errorf("failed to write JSON: %w", err)
+		return s.err
+	}
+	if _, err := s.writer.Write(data); err != nil {
+		s.err = fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return s.err
+}
+
+// Close ends the repositories array and writes the remaining fields of result, whose repositories are ignored
+func (s *JSONStreamWriter) Close(result *ScanResult) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	rest := *result
+	rest.Repositories = nil
+	data, err := json.MarshalIndent(&rest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	// Drop the opening brace and the empty repositories field, which were already written
+	data = bytes.TrimPrefix(data, []byte("{\n"))
+	data = bytes.Replace(data, []byte("  \"repositories\": null,\n"), nil, 1)
+
+	closing := "\n  ],\n"
+	if s.count == 0 {
+		closing = "],\n"
+	}
+	if _, err := io.WriteString(s.writer, closing); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	if _, err := s.writer.Write(data); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+func streamTestResult() *ScanResult {
+	repositories := []RepositoryResult{
+		{
+			Name:     "api",
+			FullName: "my-org/api",
+			Actions:  []workflow.ActionReference{{Repository: "actions/checkout", Version: "v3", FilePath: ".github/workflows/ci.yml"}},
+			Issues:   []ActionIssue{{Repository: "actions/checkout", CurrentVersion: "v3", SuggestedVersion: "v4", IssueType: "outdated", Severity: "medium"}},
+		},
+		{
+			Name:     "web",
+			FullName: "my-org/web",
+			Actions:  []workflow.ActionReference{{Repository: "actions/setup-go", Version: "v5", FilePath: ".github/workflows/ci.yml"}},
+		},
+	}
+	return BuildScanResult("my-org", repositories)
+}
+
+func TestDecodeScanResult(t *testing.T) {
+	result := streamTestResult()
+	var data bytes.Buffer
+	if err := FormatJSON(result, &data, true); err != nil {
+		t.Fatalf("FormatJSON returned error: %v", err)
+	}
+
+	var names []string
+	decoded, err := DecodeScanResult(&data, func(repo *RepositoryResult) error {
+		names = append(names, repo.FullName)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("DecodeScanResult returned error: %v", err)
+	}
+
+	if !reflect.DeepEqual(names, []string{"my-org/api", "my-org/web"}) {
+		t.Errorf("Expected repositories in order, got %v", names)
+	}
+	if decoded.Repositories != nil {
+		t.Errorf("Expected repositories to be left to the callback")
+	}
+	if decoded.Owner != "my-org" || decoded.Summary.TotalRepositories != 2 || !decoded.ScanTime.Equal(result.ScanTime) {
+		t.Errorf("Expected scan-level fields to be decoded, got owner=%q summary=%+v", decoded.Owner, decoded.Summary)
+	}
+}
+
+func TestDecodeScanResult_NDJSON(t *testing.T) {
+	var lines bytes.Buffer
+	for _, repo := range streamTestResult().Repositories {
+		line, err := json.Marshal(repo)
+		if err != nil {
+			t.Fatalf("Marshal returned error: %v", err)
+		}
+		lines.Write(append(line, '\n'))
+	}
+
+	count := 0
+	decoded, err := DecodeScanResult(&lines, func(repo *RepositoryResult) error {
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("DecodeScanResult returned error: %v", err)
+	}
+
+	if count != 2 {
+		t.Errorf("Expected 2 repositories, got %d", count)
+	}
+	if decoded.Owner != "my-org" {
+		t.Errorf("Expected the owner of the first repository, got %q", decoded.Owner)
+	}
+	if decoded.Summary.TotalRepositories != 2 || decoded.Summary.TotalActions != 2 || decoded.Summary.IssuesBySeverity["medium"] != 1 {
+		t.Errorf("Expected the summary to be calculated, got %+v", decoded.Summary)
+	}
+}
+
+func TestDecodeScanResult_Errors(t *testing.T) {
+	tests := map[string]string{
+		"split index":   `{"owner": "my-org", "chunks": []}`,
+		"not an object": `[1, 2]`,
+		"truncated":     `{"owner": "my-org", "repositories": [{"name": "api"`,
+	}
+	for name, input := range tests {
+		if _, err := DecodeScanResult(strings.NewReader(input), func(*RepositoryResult) error { return nil }); err == nil {
+			t.Errorf("Expected error for %s", name)
+		}
+	}
+
+	// Callback errors stop decoding
+	stop := fmt.Errorf("stop")
+	var data bytes.Buffer
+	if err := FormatJSON(streamTestResult(), &data, false); err != nil {
+		t.Fatalf("FormatJSON returned error: %v", err)
+	}
+	if _, err := DecodeScanResult(&data, func(*RepositoryResult) error { return stop }); err != stop {
+		t.Errorf("Expected the callback error, got %v", err)
+	}
+}
+
+func TestJSONStreamWriter(t *testing.T) {
+	for _, result := range []*ScanResult{streamTestResult(), BuildScanResult("my-org", nil)} {
+		var streamed bytes.Buffer
+		stream := NewJSONStreamWriter(&streamed)
+		for i := range result.Repositories {
+			if err := stream.WriteRepository(&result.Repositories[i]); err != nil {
+				t.Fatalf("WriteRepository returned error: %v", err)
+			}
+		}
+		if err := stream.Close(result); err != nil {
+			t.Fatalf("Close returned error: %v", err)
+		}
+
+		// The streamed output decodes to the same result as FormatJSON
+		var formatted bytes.Buffer
+		if err := FormatJSON(result, &formatted, true); err != nil {
+			t.Fatalf("FormatJSON returned error: %v", err)
+		}
+		var expected, actual map[string]any
+		if err := json.Unmarshal(formatted.Bytes(), &expected); err != nil {
+			t.Fatalf("Unmarshal returned error: %v", err)
+		}
+		if err := json.Unmarshal(streamed.Bytes(), &actual); err != nil {
+			t.Fatalf("Streamed output is not valid JSON: %v\n%s", err, streamed.String())
+		}
+		if len(result.Repositories) == 0 {
+			expected["repositories"] = []any{}
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("Expected streamed JSON to match FormatJSON, got:\n%s", streamed.String())
+		}
+	}
+}
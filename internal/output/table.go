@@ -0,0 +1,241 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Terminal formats selected with --format
+const (
+	TableFormat    = "table"    // Human-readable summary (default for terminal output)
+	JSONFormat     = "json"     // Full JSON results
+	ProblemsFormat = "problems" // One "file:line:col: severity: message" line per issue
+)
+
+// maxTableRepositories, maxTableActions, and maxTableWorkflows cap the rows of the terminal summary
+const (
+	maxTableRepositories = 20
+	maxTableActions      = 10
+	maxTableWorkflows    = 10
+)
+
+// tableSeverities are the severity columns of the terminal summary, most severe first
+var tableSeverities = []string{"critical", "high", "medium", "low"}
+
+// ParseFormat validates a --format value, defaulting to the table format
+func ParseFormat(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", TableFormat:
+		return TableFormat, nil
+	case JSONFormat:
+		return JSONFormat, nil
+	case ProblemsFormat:
+		return ProblemsFormat, nil
+	default:
+		return "", fmt.Errorf("unknown format %q: use %s, %s, or %s", value, TableFormat, JSONFormat, ProblemsFormat)
+	}
+}
+
+// actionIssueCount tallies the issues of one action across repositories
+type actionIssueCount struct {
+	action       string
+	issues       int
+	repositories map[string]bool
+}
+
+// FormatTable writes a human-readable summary of a scan: issues per repository, issues by severity,
+// the actions with the most issues, and the workflows using the most Actions minutes when estimated.
+// Repositories without issues are counted but not listed.
+func FormatTable(result *ScanResult, writer io.Writer) error {
+	return FormatTableWithColor(result, writer, false)
+}
+
+// FormatTableWithColor writes the summary of FormatTable, coloring issue counts by severity when color is set
+func FormatTableWithColor(result *ScanResult, writer io.Writer, color bool) error {
+	// severityCell colors a severity column cell; zero counts are gray so nonzero counts stand out
+	severityCell := func(severity string, count int) string {
+		text := fmt.Sprintf("%d", count)
+		if !color {
+			return text
+		}
+		if count == 0 {
+			return paint(colorGray, text)
+		}
+		return paint(severityColors[severity], text)
+	}
+	headerCell := func(text string) string {
+		if !color {
+			return text
+		}
+		return paint(colorDefault, text)
+	}
+
+	type repositoryRow struct {
+		name       string
+		bySeverity map[string]int
+		total      int
+	}
+
+	var rows []repositoryRow
+	totals := make(map[string]int)
+	actions := make(map[string]*actionIssueCount)
+	totalIssues := 0
+
+	for _, repo := range result.Repositories {
+		row := repositoryRow{name: repo.FullName, bySeverity: make(map[string]int)}
+		for _, issue := range repo.Issues {
+			row.bySeverity[issue.Severity]++
+			row.total++
+			totals[issue.Severity]++
+
+			count, ok := actions[issue.Repository]
+			if !ok {
+				count = &actionIssueCount{action: issue.Repository, repositories: make(map[string]bool)}
+				actions[issue.Repository] = count
+			}
+			count.issues++
+			count.repositories[repo.FullName] = true
+		}
+		totalIssues += row.total
+		if row.total > 0 {
+			rows = append(rows, row)
+		}
+	}
+
+	// Most severe repositories first: by critical, then high, and so on, then by total
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, severity := range tableSeverities {
+			if rows[i].bySeverity[severity] != rows[j].bySeverity[severity] {
+				return rows[i].bySeverity[severity] > rows[j].bySeverity[severity]
+			}
+		}
+		if rows[i].total != rows[j].total {
+			return rows[i].total > rows[j].total
+		}
+		return rows[i].name < rows[j].name
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Scanned %d repositories for %s: %d issues in %d repositories\n",
+		len(result.Repositories), result.Owner, totalIssues, len(rows))
+
+	// Announced removals come first, as they break workflows on a fixed date
+	if len(result.Summary.Deadlines) > 0 {
+		b.WriteString("\n")
+		table := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "DEADLINE\tACTION\tVERSIONS\tREPOSITORIES")
+		for _, deadline := range result.Summary.Deadlines {
+			fmt.Fprintf(table, "%s (%s)\t%s\t%s\t%d\n", deadline.Date.Format(time.DateOnly), deadline.When(result.ScanTime),
+				deadline.Action, strings.Join(deadline.Versions, ", "), deadline.Repositories)
+		}
+		table.Flush()
+	}
+
+	// Broken references fail every run that reaches them
+	if len(result.Summary.BrokenReferences) > 0 {
+		b.WriteString("\n")
+		table := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "BROKEN REFERENCE\tUSES\tREPOSITORIES")
+		for _, reference := range result.Summary.BrokenReferences {
+			fmt.Fprintf(table, "%s\t%d\t%s\n", reference.Reference(), reference.Occurrences, strings.Join(reference.Repositories, ", "))
+		}
+		table.Flush()
+	}
+
+	if len(rows) > 0 {
+		b.WriteString("\n")
+		table := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		header := []string{"REPOSITORY"}
+		for _, severity := range tableSeverities {
+			header = append(header, headerCell(strings.ToUpper(severity)))
+		}
+		fmt.Fprintln(table, strings.Join(append(header, "TOTAL"), "\t"))
+		for i, row := range rows {
+			if i == maxTableRepositories {
+				break
+			}
+			cells := []string{row.name}
+			for _, severity := range tableSeverities {
+				cells = append(cells, severityCell(severity, row.bySeverity[severity]))
+			}
+			fmt.Fprintf(table, "%s\t%d\n", strings.Join(cells, "\t"), row.total)
+		}
+		table.Flush()
+		if len(rows) > maxTableRepositories {
+			fmt.Fprintf(&b, "... and %d more repositories with issues\n", len(rows)-maxTableRepositories)
+		}
+	}
+
+	b.WriteString("\nIssues by severity:")
+	for _, severity := range tableSeverities {
+		fmt.Fprintf(&b, " %s %s", severity, severityCell(severity, totals[severity]))
+	}
+	b.WriteString("\n")
+
+	// Merged reports break the totals down by owner
+	if len(result.Summary.Owners) > 1 {
+		b.WriteString("\n")
+		table := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		header := []string{"OWNER", "REPOSITORIES"}
+		for _, severity := range tableSeverities {
+			header = append(header, headerCell(strings.ToUpper(severity)))
+		}
+		fmt.Fprintln(table, strings.Join(append(header, "TOTAL"), "\t"))
+		for _, owner := range result.Summary.Owners {
+			cells := []string{owner.Owner, fmt.Sprint(owner.Repositories)}
+			for _, severity := range tableSeverities {
+				cells = append(cells, severityCell(severity, owner.IssuesBySeverity[severity]))
+			}
+			fmt.Fprintf(table, "%s\t%d\n", strings.Join(cells, "\t"), owner.Issues)
+		}
+		table.Flush()
+	}
+
+	if len(actions) > 0 {
+		ranked := make([]*actionIssueCount, 0, len(actions))
+		for _, count := range actions {
+			ranked = append(ranked, count)
+		}
+		sort.Slice(ranked, func(i, j int) bool {
+			if ranked[i].issues != ranked[j].issues {
+				return ranked[i].issues > ranked[j].issues
+			}
+			return ranked[i].action < ranked[j].action
+		})
+		if len(ranked) > maxTableActions {
+			ranked = ranked[:maxTableActions]
+		}
+
+		b.WriteString("\n")
+		table := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "TOP ACTIONS\tISSUES\tREPOSITORIES")
+		for _, count := range ranked {
+			fmt.Fprintf(table, "%s\t%d\t%d\n", count.action, count.issues, len(count.repositories))
+		}
+		table.Flush()
+	}
+
+	if consumers := TopMinutesConsumers(result); len(consumers) > 0 {
+		if len(consumers) > maxTableWorkflows {
+			consumers = consumers[:maxTableWorkflows]
+		}
+
+		b.WriteString("\n")
+		table := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "TOP MINUTES CONSUMERS\tRUNS\tMINUTES/MONTH\tWEIGHTED")
+		for _, consumer := range consumers {
+			fmt.Fprintf(table, "%s/%s\t%d\t%d\t%d\n", consumer.Repository, consumer.Workflow,
+				consumer.Estimate.Runs, consumer.Estimate.MonthlyMinutes, consumer.Estimate.WeightedMinutes)
+		}
+		table.Flush()
+	}
+
+	if _, err := io.WriteString(writer, b.String()); err != nil {
+		return fmt.Errorf("failed to write table: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestFormatTable(t *testing.T) {
+	result := &ScanResult{
+		Owner: "my-org",
+		Repositories: []RepositoryResult{
+			{FullName: "my-org/web", Issues: []ActionIssue{
+				{Repository: "actions/checkout", Severity: "medium"},
+				{Repository: "actions/checkout", Severity: "medium"},
+				{Repository: "actions/setup-node", Severity: "low"},
+			}},
+			{FullName: "my-org/api", Issues: []ActionIssue{
+				{Repository: "actions/checkout", Severity: "high"},
+			}},
+			{FullName: "my-org/docs"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := FormatTable(result, &buf); err != nil {
+		t.Fatalf("FormatTable() returned error: %v", err)
+	}
+	out := buf.String()
+
+	if !strings.Contains(out, "Scanned 3 repositories for my-org: 4 issues in 2 repositories") {
+		t.Errorf("Expected scan totals, got:\n%s", out)
+	}
+	if strings.Contains(out, "my-org/docs") {
+		t.Errorf("Expected repositories without issues to be omitted, got:\n%s", out)
+	}
+	// The repository with a high issue sorts before the one with more medium issues
+	if strings.Index(out, "my-org/api") > strings.Index(out, "my-org/web") {
+		t.Errorf("Expected my-org/api before my-org/web, got:\n%s", out)
+	}
+	if !strings.Contains(out, "Issues by severity: critical 0 high 1 medium 2 low 1") {
+		t.Errorf("Expected severity totals, got:\n%s", out)
+	}
+	if !strings.Contains(out, "actions/checkout    3       2") {
+		t.Errorf("Expected actions/checkout with 3 issues in 2 repositories, got:\n%s", out)
+	}
+}
+
+func TestFormatTable_CapsRepositories(t *testing.T) {
+	result := &ScanResult{Owner: "my-org"}
+	for i := 0; i < maxTableRepositories+5; i++ {
+		result.Repositories = append(result.Repositories, RepositoryResult{
+			FullName: fmt.Sprintf("my-org/repo-%02d", i),
+			Issues:   []ActionIssue{{Repository: "actions/checkout", Severity: "low"}},
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := FormatTable(result, &buf); err != nil {
+		t.Fatalf("FormatTable() returned error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "... and 5 more repositories with issues") {
+		t.Errorf("Expected the repository list to be capped, got:\n%s", buf.String())
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := map[string]string{"": TableFormat, "table": TableFormat, "JSON": JSONFormat, "problems": ProblemsFormat}
+	for value, expected := range tests {
+		if got, err := ParseFormat(value); err != nil || got != expected {
+			t.Errorf("ParseFormat(%q) = %q, %v; expected %q", value, got, err, expected)
+		}
+	}
+	if _, err := ParseFormat("yaml"); err == nil {
+		t.Errorf("Expected an error for an unknown format")
+	}
+}
+
+func TestFormatTableWithColor_KeepsColumnsAligned(t *testing.T) {
+	result := &ScanResult{
+		Owner: "my-org",
+		Repositories: []RepositoryResult{
+			{FullName: "my-org/web", Issues: []ActionIssue{{Repository: "actions/checkout", Severity: "critical"}}},
+			{FullName: "my-org/api", Issues: []ActionIssue{{Repository: "actions/checkout", Severity: "low"}}},
+		},
+	}
+
+	var plain, colored bytes.Buffer
+	if err := FormatTable(result, &plain); err != nil {
+		t.Fatalf("FormatTable() returned error: %v", err)
+	}
+	if err := FormatTableWithColor(result, &colored, true); err != nil {
+		t.Fatalf("FormatTableWithColor() returned error: %v", err)
+	}
+
+	if !strings.Contains(colored.String(), paint(colorMagenta, "1")) {
+		t.Errorf("Expected critical counts in magenta, got %q", colored.String())
+	}
+	stripped := regexp.MustCompile("\x1b\\[[0-9]+m").ReplaceAllString(colored.String(), "")
+	if stripped != plain.String() {
+		t.Errorf("Expected colored table to match the plain table without colors\nplain:\n%s\ncolored:\n%s", plain.String(), stripped)
+	}
+}
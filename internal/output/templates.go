@@ -0,0 +1,217 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/links"
+)
+
+// Report sections that can be overridden with templates, in the order they appear
+const (
+	SectionHeader            = "header"
+	SectionSummary           = "summary"
+	SectionIssuesOverview    = "issues-overview"
+	SectionPriorities        = "priorities"
+	SectionRepositoryDetails = "repository-details"
+	SectionSuppressedIssues  = "suppressed-issues"
+	SectionReusableWorkflows = "reusable-workflows"
+	SectionTagProtection     = "tag-protection"
+	SectionMajorTags         = "major-tags"
+	SectionInternalActions   = "internal-actions"
+	SectionWorkflowUsage     = "workflow-usage"
+	SectionActionsMinutes    = "actions-minutes"
+	SectionSecretFlows       = "secret-flows"
+	SectionContainerImages   = "container-images"
+	SectionEnvironments      = "environments"
+	SectionSetupConsistency  = "setup-consistency"
+	SectionPRLinks           = "pr-links"
+	SectionDetailedStats     = "detailed-stats"
+	SectionFooter            = "footer" // Only rendered when a template is provided
+)
+
+// ReportSections lists all overridable report sections
+var ReportSections = []string{
+	SectionHeader,
+	SectionSummary,
+	SectionIssuesOverview,
+	SectionPriorities,
+	SectionRepositoryDetails,
+	SectionSuppressedIssues,
+	SectionReusableWorkflows,
+	SectionTagProtection,
+	SectionMajorTags,
+	SectionInternalActions,
+	SectionWorkflowUsage,
+	SectionActionsMinutes,
+	SectionSecretFlows,
+	SectionContainerImages,
+	SectionEnvironments,
+	SectionSetupConsistency,
+	SectionPRLinks,
+	SectionDetailedStats,
+	SectionFooter,
+}
+
+// reportTemplateExt is the file extension for report section templates
+const reportTemplateExt = ".md.tmpl"
+
+// reportTemplateFuncs are helper functions available to report section templates
+// `link "backstage" .` builds a link from the --links mappings; see SetLinks.
+var reportTemplateFuncs = template.FuncMap{
+	"replace": strings.ReplaceAll,
+	"lower":   strings.ToLower,
+	"upper":   strings.ToUpper,
+	"join":    strings.Join,
+	"link":    linkFunc(nil),
+}
+
+// linkFunc returns the link template function of a mapping. It builds the named link for a
+// repository given as a RepositoryResult or an "owner/name" string.
+func linkFunc(mapping *links.Mapping) func(string, any) (string, error) {
+	return func(name string, repository any) (string, error) {
+		var target links.Target
+		switch repo := repository.(type) {
+		case RepositoryResult:
+			target = links.NewTarget(repo.FullName, repo.Host, repo.CustomProperties)
+		case *RepositoryResult:
+			target = links.NewTarget(repo.FullName, repo.Host, repo.CustomProperties)
+		case string:
+			target = links.NewTarget(repo, "", nil)
+		default:
+			return "", fmt.Errorf("link %q needs a repository or an \"owner/name\" string, got %T", name, repository)
+		}
+		return mapping.URL(name, target)
+	}
+}
+
+// ReportTemplates holds per-section Go template overrides for reports
+type ReportTemplates struct {
+	templates map[string]*template.Template
+}
+
+// ReportTemplateData represents the data available to report section templates
+type ReportTemplateData struct {
+	Result  *ScanResult
+	Summary Summary
+	Default string // Markdown produced by the built-in section, for wrapping or rewording
+}
+
+// LoadReportTemplates loads section templates named "<section>.md.tmpl" from a directory
+func LoadReportTemplates(dir string) (*ReportTemplates, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read report template directory: %w", err)
+	}
+
+	known := make(map[string]bool)
+	for _, section := range ReportSections {
+		known[section] = true
+	}
+
+	templates := &ReportTemplates{templates: make(map[string]*template.Template)}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), reportTemplateExt) {
+			continue
+		}
+
+		section := strings.TrimSuffix(entry.Name(), reportTemplateExt)
+		if !known[section] {
+			return nil, fmt.Errorf("unknown report section template %q (valid sections: %s)", entry.Name(), strings.Join(ReportSections, ", "))
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read report template %s: %w", entry.Name(), err)
+		}
+
+		tmpl, err := template.New(section).Funcs(reportTemplateFuncs).Parse(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse report template %s: %w", entry.Name(), err)
+		}
+		templates.templates[section] = tmpl
+	}
+
+	return templates, nil
+}
+
+// SetLinks makes the link mappings available to the section templates' link function
+func (t *ReportTemplates) SetLinks(mapping *links.Mapping) {
+	if t == nil {
+		return
+	}
+	for _, tmpl := range t.templates {
+		tmpl.Funcs(template.FuncMap{"link": linkFunc(mapping)})
+	}
+}
+
+// Sections returns the names of the overridden sections in sorted order
+func (t *ReportTemplates) Sections() []string {
+	if t == nil {
+		return nil
+	}
+
+	sections := make([]string, 0, len(t.templates))
+	for section := range t.templates {
+		sections = append(sections, section)
+	}
+	sort.Strings(sections)
+	return sections
+}
+
+// has reports whether a section has a template override
+func (t *ReportTemplates) has(section string) bool {
+	if t == nil {
+		return false
+	}
+	_, exists := t.templates[section]
+	return exists
+}
+
+// render applies a section template to the built-in cell, returning false if the section should be omitted
+func (t *ReportTemplates) render(section string, result *ScanResult, cell NotebookCell) (NotebookCell, bool, error) {
+	if !t.has(section) {
+		return cell, true, nil
+	}
+
+	data := ReportTemplateData{
+		Result:  result,
+		Summary: result.Summary,
+		Default: strings.Join(cell.Source, ""),
+	}
+
+	var buf bytes.Buffer
+	if err := t.templates[section].Execute(&buf, data); err != nil {
+		return cell, false, fmt.Errorf("failed to render %s report template: %w", section, err)
+	}
+
+	// An empty template hides the section entirely
+	if strings.TrimSpace(buf.String()) == "" {
+		return cell, false, nil
+	}
+
+	return NotebookCell{
+		CellType: "markdown",
+		Source:   splitSourceLines(buf.String()),
+	}, true, nil
+}
+
+// splitSourceLines splits text into notebook source lines, keeping line endings
+func splitSourceLines(text string) []string {
+	var lines []string
+	for text != "" {
+		idx := strings.Index(text, "\n")
+		if idx < 0 {
+			lines = append(lines, text)
+			break
+		}
+		lines = append(lines, text[:idx+1])
+		text = text[idx+1:]
+	}
+	return lines
+}
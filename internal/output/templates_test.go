@@ -0,0 +1,143 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/links"
+)
+
+func writeReportTemplate(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+}
+
+func TestFormatNotebookWithTemplates_OverridesSections(t *testing.T) {
+	dir := t.TempDir()
+	writeReportTemplate(t, dir, "header.md.tmpl", "# Acme Actions Audit for {{.Result.Owner}}\n\nSee the [runbook](https://wiki.acme.example/actions).\n\n"+
+		`{{replace .Default "repositories scanned" "services audited"}}`)
+	writeReportTemplate(t, dir, "detailed-stats.md.tmpl", "   \n")
+	writeReportTemplate(t, dir, "footer.md.tmpl", "Questions? Contact #platform-team ({{.Summary.TotalRepositories}} repositories scanned)\n")
+
+	templates, err := LoadReportTemplates(dir)
+	if err != nil {
+		t.Fatalf("LoadReportTemplates() returned error: %v", err)
+	}
+
+	result := BuildScanResult("acme", []RepositoryResult{{Name: "api", FullName: "acme/api"}})
+
+	var buf bytes.Buffer
+	if err := FormatNotebookWithTemplates(result, &buf, templates); err != nil {
+		t.Fatalf("FormatNotebookWithTemplates() returned error: %v", err)
+	}
+
+	var notebook JupyterNotebook
+	if err := json.Unmarshal(buf.Bytes(), &notebook); err != nil {
+		t.Fatalf("Failed to parse notebook: %v", err)
+	}
+
+	var sources []string
+	for _, cell := range notebook.Cells {
+		sources = append(sources, strings.Join(cell.Source, ""))
+	}
+
+	if !strings.HasPrefix(sources[0], "# Acme Actions Audit for acme") {
+		t.Errorf("Expected custom header, got %q", sources[0])
+	}
+	if !strings.Contains(sources[0], "services audited") || strings.Contains(sources[0], "repositories scanned") {
+		t.Errorf("Expected header terminology to be replaced, got %q", sources[0])
+	}
+	for _, source := range sources {
+		if strings.Contains(source, "Detailed Action Statistics") {
+			t.Errorf("Expected empty template to hide detailed statistics section")
+		}
+	}
+	if last := sources[len(sources)-1]; !strings.Contains(last, "1 repositories scanned") {
+		t.Errorf("Expected custom footer as last cell, got %q", last)
+	}
+}
+
+func TestFormatNotebookWithTemplates_Links(t *testing.T) {
+	dir := t.TempDir()
+	writeReportTemplate(t, dir, "footer.md.tmpl", `{{range .Result.Repositories}}{{.FullName}}: {{link "backstage" .}}{{with link "grafana" .}} {{.}}{{end}}
+{{end}}`)
+
+	templates, err := LoadReportTemplates(dir)
+	if err != nil {
+		t.Fatalf("LoadReportTemplates() returned error: %v", err)
+	}
+	mapping, err := links.Parse(map[string]string{
+		"backstage": "https://backstage.example.com/catalog/default/component/{{.Name}}",
+		"grafana":   "https://grafana.example.com/d/{{.Properties.ProductId}}",
+	})
+	if err != nil {
+		t.Fatalf("links.Parse() returned error: %v", err)
+	}
+	templates.SetLinks(mapping)
+
+	result := BuildScanResult("acme", []RepositoryResult{
+		{Name: "api", FullName: "acme/api", CustomProperties: map[string]string{"ProductId": "payments"}},
+		{Name: "web", FullName: "acme/web"},
+	})
+	var buf bytes.Buffer
+	if err := FormatNotebookWithTemplates(result, &buf, templates); err != nil {
+		t.Fatalf("FormatNotebookWithTemplates() returned error: %v", err)
+	}
+
+	for _, expected := range []string{
+		"acme/api: https://backstage.example.com/catalog/default/component/api https://grafana.example.com/d/payments",
+		"acme/web: https://backstage.example.com/catalog/default/component/web\\n",
+	} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("Expected %q in the footer, got %s", expected, buf.String())
+		}
+	}
+}
+
+func TestLoadReportTemplates_RejectsUnknownSection(t *testing.T) {
+	dir := t.TempDir()
+	writeReportTemplate(t, dir, "sidebar.md.tmpl", "hello")
+
+	if _, err := LoadReportTemplates(dir); err == nil {
+		t.Errorf("Expected error for unknown section template")
+	}
+}
+
+func TestLoadReportTemplates_InvalidTemplate(t *testing.T) {
+	dir := t.TempDir()
+	writeReportTemplate(t, dir, "header.md.tmpl", "{{.Result.Owner")
+
+	if _, err := LoadReportTemplates(dir); err == nil {
+		t.Errorf("Expected error for invalid template syntax")
+	}
+}
+
+func TestFormatNotebookWithTemplates_IssueMetadata(t *testing.T) {
+	dir := t.TempDir()
+	writeReportTemplate(t, dir, "footer.md.tmpl",
+		`{{range .Result.Repositories}}{{range .Issues}}{{.Repository}}: {{.Metadata.cve}} until {{index .Metadata "eol_date"}}{{end}}{{end}}`)
+
+	templates, err := LoadReportTemplates(dir)
+	if err != nil {
+		t.Fatalf("LoadReportTemplates() returned error: %v", err)
+	}
+
+	issue := ActionIssue{Repository: "actions/setup-node", IssueType: "outdated", Severity: "medium"}
+	issue.SetMetadata(MetadataCVE, "CVE-2024-0001")
+	issue.SetMetadata(MetadataEOLDate, "2025-04-30")
+	result := BuildScanResult("acme", []RepositoryResult{{Name: "api", FullName: "acme/api", Issues: []ActionIssue{issue}}})
+
+	var buf bytes.Buffer
+	if err := FormatNotebookWithTemplates(result, &buf, templates); err != nil {
+		t.Fatalf("FormatNotebookWithTemplates() returned error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "actions/setup-node: CVE-2024-0001 until 2025-04-30") {
+		t.Errorf("Expected issue metadata in the footer, got %s", buf.String())
+	}
+}
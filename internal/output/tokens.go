@@ -0,0 +1,38 @@
+package output
+
+// TokenSummary counts workflows by how they scope and authenticate with tokens, a hardening measure
+// for security reporting
+type TokenSummary struct {
+	Workflows           int `json:"workflows"`            // Workflow files whose token usage was read
+	ExplicitPermissions int `json:"explicit_permissions"` // Workflows setting permissions: for the workflow or every job
+	GitHubToken         int `json:"github_token"`         // Workflows reading secrets.GITHUB_TOKEN or github.token
+	PATSecrets          int `json:"pat_secrets"`          // Workflows using personal access token secrets
+}
+
+// Percent returns the share of workflows with explicit permissions, from 0 to 100
+func (t *TokenSummary) Percent() float64 {
+	if t == nil || t.Workflows == 0 {
+		return 0
+	}
+	return float64(t.ExplicitPermissions) / float64(t.Workflows) * 100
+}
+
+// countTokens adds the token usage of a repository's workflow files to a token summary
+func countTokens(tokens *TokenSummary, files []WorkflowFileResult) {
+	for _, file := range files {
+		usage := file.TokenUsage
+		if usage == nil {
+			continue
+		}
+		tokens.Workflows++
+		if usage.ExplicitPermissions {
+			tokens.ExplicitPermissions++
+		}
+		if usage.GitHubToken {
+			tokens.GitHubToken++
+		}
+		if len(usage.PATSecrets) > 0 {
+			tokens.PATSecrets++
+		}
+	}
+}
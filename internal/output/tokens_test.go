@@ -0,0 +1,37 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+func TestTokenSummary(t *testing.T) {
+	repositories := []RepositoryResult{{
+		FullName: "my-org/api",
+		WorkflowFiles: []WorkflowFileResult{
+			{Path: ".github/workflows/ci.yml", TokenUsage: &workflow.TokenUsage{ExplicitPermissions: true, GitHubToken: true}},
+			{Path: ".github/workflows/release.yml", TokenUsage: &workflow.TokenUsage{PATSecrets: []string{"RELEASE_PAT"}}},
+			{Path: ".github/workflows/huge.yml", Status: WorkflowStatusSkippedTooLarge},
+		},
+	}}
+
+	summary := calculateSummary(repositories)
+	expected := TokenSummary{Workflows: 2, ExplicitPermissions: 1, GitHubToken: 1, PATSecrets: 1}
+	if summary.Tokens == nil || *summary.Tokens != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, summary.Tokens)
+	}
+	if percent := summary.Tokens.Percent(); percent != 50 {
+		t.Errorf("Expected 50%% explicit permissions, got %g", percent)
+	}
+
+	cell := createHeaderCell(&ScanResult{Owner: "my-org", Summary: summary})
+	if source := strings.Join(cell.Source, ""); !strings.Contains(source, "**50%** of workflows set explicit token permissions (1 of 2)") {
+		t.Errorf("Expected the token summary in the executive summary, got:\n%s", source)
+	}
+
+	if calculateSummary(nil).Tokens != nil {
+		t.Error("Expected no token summary without workflow files")
+	}
+}
@@ -0,0 +1,43 @@
+package output
+
+import "strings"
+
+// CountWorkflowConsumers records, on every issue in a reusable workflow of a scanned repository, how
+// many other scanned repositories call that workflow, so a finding in a shared workflow shows how far
+// it reaches. Each finding is reported once, by the workflow's home repository: callers only analyze
+// their own workflow files.
+func CountWorkflowConsumers(repositories []RepositoryResult) {
+	consumers := workflowConsumers(repositories)
+	for r := range repositories {
+		for i := range repositories[r].Issues {
+			issue := &repositories[r].Issues[i]
+			if count := len(consumers[workflowKey(repositories[r].FullName, issue.FilePath)]); count > 0 {
+				issue.Consumers = count
+			}
+		}
+	}
+}
+
+// workflowConsumers returns the repositories calling each reusable workflow, keyed by workflowKey
+// Calls from the workflow's own repository are not counted.
+func workflowConsumers(repositories []RepositoryResult) map[string]map[string]bool {
+	consumers := make(map[string]map[string]bool)
+	for _, repo := range repositories {
+		for _, action := range repo.Actions {
+			if !strings.Contains(action.WorkflowPath, ".github/workflows/") || strings.EqualFold(action.Repository, repo.FullName) {
+				continue
+			}
+			key := workflowKey(action.Repository, action.WorkflowPath)
+			if consumers[key] == nil {
+				consumers[key] = make(map[string]bool)
+			}
+			consumers[key][strings.ToLower(repo.FullName)] = true
+		}
+	}
+	return consumers
+}
+
+// workflowKey identifies a workflow file across repositories
+func workflowKey(repository, path string) string {
+	return strings.ToLower(repository) + "/" + path
+}
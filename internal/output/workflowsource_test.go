@@ -0,0 +1,33 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+func TestCountWorkflowConsumers(t *testing.T) {
+	sharedCall := workflow.ActionReference{Repository: "my-org/shared", WorkflowPath: ".github/workflows/build.yml", Version: "v1", IsReusable: true}
+	repositories := []RepositoryResult{
+		{FullName: "my-org/shared", Actions: []workflow.ActionReference{sharedCall}, Issues: []ActionIssue{
+			{Repository: "actions/setup-go", CurrentVersion: "v3", IssueType: "outdated", FilePath: ".github/workflows/build.yml"},
+			{Repository: "actions/cache", CurrentVersion: "v2", IssueType: "outdated", FilePath: ".github/workflows/release.yml"},
+		}},
+		{FullName: "my-org/api", Actions: []workflow.ActionReference{sharedCall}, Issues: []ActionIssue{
+			{Repository: "actions/cache", CurrentVersion: "v2", IssueType: "outdated", FilePath: ".github/workflows/build.yml"},
+		}},
+		{FullName: "my-org/web", Actions: []workflow.ActionReference{sharedCall}},
+	}
+	CountWorkflowConsumers(repositories)
+
+	shared := repositories[0].Issues
+	if shared[0].Consumers != 2 {
+		t.Errorf("Expected the finding in the shared workflow to count api and web but not its own call, got %d", shared[0].Consumers)
+	}
+	if shared[1].Consumers != 0 {
+		t.Errorf("Expected no consumers for a workflow nobody calls, got %d", shared[1].Consumers)
+	}
+	if api := repositories[1].Issues; api[0].Consumers != 0 {
+		t.Errorf("Expected a caller's workflow of the same path not to count the shared workflow's consumers, got %d", api[0].Consumers)
+	}
+}
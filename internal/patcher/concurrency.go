@@ -0,0 +1,94 @@
+package patcher
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConcurrencyBlock is a workflow-level concurrency setting to add to a workflow
+type ConcurrencyBlock struct {
+	Group            string // Group added when the workflow sets none
+	CancelInProgress bool
+}
+
+// SetConcurrency adds a workflow-level concurrency block before jobs:, or adds cancel-in-progress to an
+// existing block, and returns the updated content with a description of the change. An existing group and
+// cancel-in-progress are kept as written, so the content is returned unchanged when both are set.
+// Like InsertAction, edits are made in the text; a flow-style concurrency mapping returns an error.
+func (wp *WorkflowPatcher) SetConcurrency(content string, block ConcurrencyBlock) (string, string, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &root); err != nil {
+		return content, "", fmt.Errorf("failed to parse workflow YAML: %w", err)
+	}
+	if len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return content, "", fmt.Errorf("workflow is empty")
+	}
+	top := root.Content[0]
+	if top.Style&yaml.FlowStyle != 0 {
+		return content, "", fmt.Errorf("flow-style workflows cannot be edited")
+	}
+
+	lines := strings.Split(content, "\n")
+	cancel := "cancel-in-progress: " + strconv.FormatBool(block.CancelInProgress)
+	step := nestedIndent(top)
+
+	key, concurrency := mappingEntry(top, "concurrency")
+	if concurrency == nil {
+		jobsKey, _ := mappingEntry(top, "jobs")
+		if jobsKey == nil {
+			return content, "", fmt.Errorf("workflow has no jobs")
+		}
+		indent := strings.Repeat(" ", jobsKey.Column-1)
+		inserted := []string{
+			indent + "concurrency:",
+			indent + step + "group: " + scalar(block.Group),
+			indent + step + cancel,
+		}
+		at := leadingCommentStart(lines, jobsKey.Line-1)
+		if at > 0 && strings.TrimSpace(lines[at-1]) == "" {
+			inserted = append(inserted, "")
+		}
+		return spliceLines(lines, at, inserted), fmt.Sprintf("Added concurrency group %s with %s", block.Group, cancel), nil
+	}
+
+	switch {
+	case concurrency.Kind == yaml.ScalarNode:
+		// concurrency: <group> becomes a mapping keeping the group
+		if concurrency.Line != key.Line {
+			return content, "", fmt.Errorf("concurrency group is not on the concurrency: line")
+		}
+		indent := strings.Repeat(" ", key.Column-1)
+		edit := lineEdit{start: key.Line - 1, end: key.Line, lines: []string{
+			indent + "concurrency:",
+			indent + step + "group: " + scalar(concurrency.Value),
+			indent + step + cancel,
+		}}
+		return applyEdits(lines, []lineEdit{edit}), "Set concurrency " + cancel, nil
+	case concurrency.Kind != yaml.MappingNode || concurrency.Style&yaml.FlowStyle != 0:
+		return content, "", fmt.Errorf("concurrency: is not a block mapping")
+	}
+
+	if existing := nodeValue(concurrency, "cancel-in-progress"); existing != nil {
+		return content, "", nil
+	}
+	if len(concurrency.Content) == 0 {
+		return content, "", fmt.Errorf("concurrency: is empty")
+	}
+	indent := strings.Repeat(" ", concurrency.Content[0].Column-1)
+	last := concurrency.Content[len(concurrency.Content)-2]
+	end := blockEnd(lines, last.Line-1, last.Column-1)
+	edit := lineEdit{start: end, end: end, lines: []string{indent + cancel}}
+	return applyEdits(lines, []lineEdit{edit}), "Set concurrency " + cancel, nil
+}
+
+// nestedIndent returns the indentation step of a workflow's nested mappings, from its jobs, or two spaces
+func nestedIndent(top *yaml.Node) string {
+	jobsKey, jobs := mappingEntry(top, "jobs")
+	if jobsKey != nil && jobs.Kind == yaml.MappingNode && len(jobs.Content) > 0 && jobs.Content[0].Column > jobsKey.Column {
+		return strings.Repeat(" ", jobs.Content[0].Column-jobsKey.Column)
+	}
+	return "  "
+}
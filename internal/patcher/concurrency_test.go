@@ -0,0 +1,94 @@
+package patcher
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetConcurrency_AddsBlock(t *testing.T) {
+	content := `name: Deploy
+on: push
+
+# Production deploy
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    environment: production
+    steps:
+      - run: ./deploy.sh
+`
+	updated, change, err := NewWorkflowPatcher().SetConcurrency(content, ConcurrencyBlock{
+		Group: "${{ github.workflow }}-${{ github.ref }}",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `name: Deploy
+on: push
+
+concurrency:
+  group: ${{ github.workflow }}-${{ github.ref }}
+  cancel-in-progress: false
+
+# Production deploy
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    environment: production
+    steps:
+      - run: ./deploy.sh
+`
+	if updated != expected {
+		t.Errorf("Unexpected content:\n%s", updated)
+	}
+	if !strings.Contains(change, "Added concurrency group") {
+		t.Errorf("Unexpected change %q", change)
+	}
+}
+
+func TestSetConcurrency_ExistingGroup(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "scalar group",
+			content:  "on: pull_request\nconcurrency: ci-${{ github.ref }}\njobs:\n    test:\n        runs-on: ubuntu-latest\n",
+			expected: "on: pull_request\nconcurrency:\n    group: ci-${{ github.ref }}\n    cancel-in-progress: true\njobs:\n    test:\n        runs-on: ubuntu-latest\n",
+		},
+		{
+			name:     "mapping without cancel-in-progress",
+			content:  "on: pull_request\nconcurrency:\n  group: ci\n  # comment\njobs:\n  test:\n    runs-on: ubuntu-latest\n",
+			expected: "on: pull_request\nconcurrency:\n  group: ci\n  cancel-in-progress: true\n  # comment\njobs:\n  test:\n    runs-on: ubuntu-latest\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated, _, err := NewWorkflowPatcher().SetConcurrency(tt.content, ConcurrencyBlock{Group: "unused", CancelInProgress: true})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if updated != tt.expected {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tt.expected, updated)
+			}
+		})
+	}
+}
+
+func TestSetConcurrency_AlreadySet(t *testing.T) {
+	content := "on: pull_request\nconcurrency:\n  group: ci\n  cancel-in-progress: false\njobs:\n  test:\n    runs-on: ubuntu-latest\n"
+	updated, change, err := NewWorkflowPatcher().SetConcurrency(content, ConcurrencyBlock{Group: "ci", CancelInProgress: true})
+	if err != nil || updated != content || change != "" {
+		t.Errorf("Expected an explicit setting to be kept, got %q, %q, %v", updated, change, err)
+	}
+}
+
+func TestSetConcurrency_FlowStyle(t *testing.T) {
+	content := "on: pull_request\nconcurrency: {group: ci}\njobs:\n  test:\n    runs-on: ubuntu-latest\n"
+	if _, _, err := NewWorkflowPatcher().SetConcurrency(content, ConcurrencyBlock{Group: "ci", CancelInProgress: true}); err == nil {
+		t.Errorf("Expected an error for a flow-style concurrency mapping")
+	}
+}
@@ -0,0 +1,206 @@
+package patcher
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// InputType is the kind of value an action input expects
+// Action metadata has no input types, so they are inferred from each input's default: true or false
+// makes a boolean input, and a number makes a number input. Every other input takes a string.
+type InputType string
+
+const (
+	InputTypeString  InputType = "string"
+	InputTypeBoolean InputType = "boolean"
+	InputTypeNumber  InputType = "number"
+)
+
+// WarningMarker starts the description of a change that is a warning rather than an edit
+const WarningMarker = "Warning: "
+
+// InputTypeSource returns the inputs of an action at a version, keyed by input name
+// Path is the action's path within the repository, empty for actions at the repository root.
+type InputTypeSource interface {
+	InputTypes(repository, path, version string) (map[string]InputType, error)
+}
+
+// actionMetadata is the part of an action.yml file input validation reads
+type actionMetadata struct {
+	Inputs map[string]struct {
+		Default interface{} `yaml:"default"`
+		Type    string      `yaml:"type"` // Not part of the metadata syntax, but set by some actions
+	} `yaml:"inputs"`
+}
+
+// ParseActionInputs returns the inputs declared in action metadata (action.yml) with their types
+func ParseActionInputs(content string) (map[string]InputType, error) {
+	var metadata actionMetadata
+	if err := yaml.Unmarshal([]byte(content), &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse action metadata: %w", err)
+	}
+
+	inputs := make(map[string]InputType, len(metadata.Inputs))
+	for name, input := range metadata.Inputs {
+		switch InputType(strings.ToLower(input.Type)) {
+		case InputTypeBoolean, InputTypeNumber, InputTypeString:
+			inputs[name] = InputType(strings.ToLower(input.Type))
+			continue
+		}
+		inputs[name] = valueType(input.Default)
+	}
+	return inputs, nil
+}
+
+// valueType returns the input type a default value implies; strings holding a boolean or number count
+// as one, since action metadata often quotes defaults
+func valueType(value interface{}) InputType {
+	switch v := value.(type) {
+	case bool:
+		return InputTypeBoolean
+	case int, int64, uint64, float64:
+		return InputTypeNumber
+	case string:
+		switch {
+		case v == "true" || v == "false":
+			return InputTypeBoolean
+		case isNumber(v):
+			return InputTypeNumber
+		}
+	}
+	return InputTypeString
+}
+
+// isNumber reports whether a string is a decimal number
+func isNumber(value string) bool {
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil && strings.TrimSpace(value) == value && value != ""
+}
+
+// CheckInputTypes compares the values a patch adds or modifies with the inputs of the target action,
+// returning a warning for each input the action does not declare and each value of the wrong type.
+// Values holding ${{ }} expressions are only known when the workflow runs and are not checked.
+func CheckInputTypes(patch *Patch, inputs map[string]InputType) []string {
+	if patch == nil || inputs == nil {
+		return nil
+	}
+	action := patch.Repository
+	if patch.ToRepository != "" {
+		action = patch.ToRepository
+	}
+
+	var warnings []string
+	check := func(field string, value interface{}) {
+		expected, ok := inputs[field]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("Input '%s' is not declared by %s@%s", field, action, patch.ToVersion))
+			return
+		}
+		if warning := typeMismatch(field, value, expected); warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+	for _, addition := range patch.Additions {
+		check(addition.Field, addition.Value)
+	}
+	for _, rename := range patch.Renames {
+		if _, ok := inputs[rename.NewField]; !ok {
+			warnings = append(warnings, fmt.Sprintf("Input '%s' is not declared by %s@%s", rename.NewField, action, patch.ToVersion))
+		}
+	}
+	for _, modification := range patch.Modifications {
+		check(modification.Field, modification.NewValue)
+	}
+	return warnings
+}
+
+// typeMismatch describes a value that does not suit an input's type, or returns ""
+func typeMismatch(field string, value interface{}, expected InputType) string {
+	if s, ok := value.(string); ok && strings.Contains(s, "${{") {
+		return ""
+	}
+	switch expected {
+	case InputTypeBoolean:
+		switch v := value.(type) {
+		case bool:
+			return ""
+		case string:
+			if v == "true" || v == "false" {
+				return fmt.Sprintf("Input '%s' is a boolean, but the patch sets the string %q; use %s without quotes", field, v, v)
+			}
+		}
+		return fmt.Sprintf("Input '%s' is a boolean, but the patch sets %v; use true or false", field, value)
+	case InputTypeNumber:
+		switch v := value.(type) {
+		case int, int64, uint64, float64:
+			return ""
+		case string:
+			if isNumber(v) {
+				return fmt.Sprintf("Input '%s' is a number, but the patch sets the string %q; use %s without quotes", field, v, v)
+			}
+		}
+		return fmt.Sprintf("Input '%s' is a number, but the patch sets %v", field, value)
+	}
+	return ""
+}
+
+// DirectoryInputTypes reads action metadata from a local directory laid out as
+// <dir>/<owner>/<repo>[/<path>]/action.yml, the same for every version, for offline patch tests
+type DirectoryInputTypes struct {
+	Dir string
+}
+
+// InputTypes reads the action.yml or action.yaml of an action; actions without metadata in the
+// directory return nil inputs, which skips their checks
+func (d DirectoryInputTypes) InputTypes(repository, path, version string) (map[string]InputType, error) {
+	dir := filepath.Join(d.Dir, filepath.FromSlash(repository), filepath.FromSlash(path))
+	for _, name := range []string{"action.yml", "action.yaml"} {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read action metadata: %w", err)
+		}
+		return ParseActionInputs(string(content))
+	}
+	return nil, nil
+}
+
+// SetInputTypes checks patched inputs against the inputs each target action declares
+// Mismatches are added to the patch's warnings and, in PatchWorkflowContent, to the changes as
+// descriptions starting with WarningMarker.
+func (wp *WorkflowPatcher) SetInputTypes(source InputTypeSource) {
+	wp.inputTypes = source
+}
+
+// checkInputs adds input type warnings to a patch and returns them
+func (wp *WorkflowPatcher) checkInputs(patch *Patch, path string) []string {
+	if wp.inputTypes == nil || patch == nil || !patch.Applied {
+		return nil
+	}
+	repository := patch.Repository
+	if patch.ToRepository != "" {
+		repository = patch.ToRepository
+	}
+	inputs, err := wp.inputTypes.InputTypes(repository, path, patch.ToVersion)
+	if err != nil {
+		warning := fmt.Sprintf("Unable to check inputs of %s@%s: %v", repository, patch.ToVersion, err)
+		patch.Warnings = append(patch.Warnings, warning)
+		return []string{warning}
+	}
+	warnings := CheckInputTypes(patch, inputs)
+	patch.Warnings = append(patch.Warnings, warnings...)
+	return warnings
+}
+
+// IsWarning reports whether a change description from PatchWorkflowContent is a warning
+func IsWarning(change string) bool {
+	return strings.Contains(change, ": "+WarningMarker)
+}
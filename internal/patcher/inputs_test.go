@@ -0,0 +1,135 @@
+package patcher
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const uploadArtifactMetadata = `name: Upload a Build Artifact
+inputs:
+  name:
+    description: Artifact name
+    default: artifact
+  overwrite:
+    description: Replace an existing artifact
+    default: 'false'
+  retention-days:
+    description: Days to keep the artifact
+  compression-level:
+    default: '6'
+  include-hidden-files:
+    default: false
+`
+
+func TestParseActionInputs(t *testing.T) {
+	inputs, err := ParseActionInputs(uploadArtifactMetadata)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]InputType{
+		"name":                 InputTypeString,
+		"overwrite":            InputTypeBoolean,
+		"retention-days":       InputTypeString, // No default to infer a type from
+		"compression-level":    InputTypeNumber,
+		"include-hidden-files": InputTypeBoolean,
+	}
+	for name, inputType := range expected {
+		if inputs[name] != inputType {
+			t.Errorf("Input %s: expected %s, got %s", name, inputType, inputs[name])
+		}
+	}
+}
+
+func TestCheckInputTypes(t *testing.T) {
+	inputs, _ := ParseActionInputs(uploadArtifactMetadata)
+	patch := &Patch{
+		Repository: "actions/upload-artifact",
+		ToVersion:  "v4",
+		Additions: []FieldAddition{
+			{Field: "overwrite", Value: "false"},
+			{Field: "include-hidden-files", Value: true},
+			{Field: "compression-level", Value: "${{ inputs.level }}"},
+			{Field: "if-no-files-found", Value: "error"},
+		},
+		Modifications: []FieldModification{{Field: "compression-level", NewValue: "fast"}},
+	}
+
+	warnings := CheckInputTypes(patch, inputs)
+	if len(warnings) != 3 {
+		t.Fatalf("Expected 3 warnings, got %d: %v", len(warnings), warnings)
+	}
+	if !strings.Contains(warnings[0], `boolean, but the patch sets the string "false"`) {
+		t.Errorf("Expected the quoted boolean reported, got %q", warnings[0])
+	}
+	if !strings.Contains(warnings[1], "'if-no-files-found' is not declared by actions/upload-artifact@v4") {
+		t.Errorf("Expected the undeclared input reported, got %q", warnings[1])
+	}
+	if !strings.Contains(warnings[2], "'compression-level' is a number") {
+		t.Errorf("Expected the non-numeric value reported, got %q", warnings[2])
+	}
+}
+
+func TestPatchWorkflowContent_PreservesScalarTypes(t *testing.T) {
+	wp := NewWorkflowPatcher()
+	wp.AddPatchRule(ActionPatchRule{
+		Repository: "actions/upload-artifact",
+		VersionPatches: []VersionPatch{{
+			FromVersion: "v3",
+			ToVersion:   "v4",
+			Patches: []FieldPatch{
+				{Operation: OperationAdd, Field: "overwrite", Value: "false", Reason: "v4 artifacts are immutable"},
+				{Operation: OperationAdd, Field: "include-hidden-files", Value: false, Reason: "v4 skips hidden files"},
+				{Operation: OperationModify, Field: "name", Value: "dist-v4", Reason: "Distinct artifact name"},
+			},
+		}},
+	})
+	dir := t.TempDir()
+	actionDir := filepath.Join(dir, "actions", "upload-artifact")
+	if err := os.MkdirAll(actionDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(actionDir, "action.yml"), []byte(uploadArtifactMetadata), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	wp.SetInputTypes(DirectoryInputTypes{Dir: dir})
+
+	content := `jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/upload-artifact@v3
+        with:
+          path: dist/
+          name: 'dist' # shared with the deploy job
+          retention-days: 5
+`
+	updated, changes, err := wp.PatchWorkflowContent(content, []ActionVersionUpdate{{ActionRepo: "actions/upload-artifact", FromVersion: "v3", ToVersion: "v4"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Untouched inputs keep their order, the modified string its quoting and comment, and each added
+	// value its type
+	expected := `        with:
+          path: dist/
+          name: 'dist-v4' # shared with the deploy job
+          retention-days: 5
+          overwrite: "false"
+          include-hidden-files: false
+`
+	if !strings.HasSuffix(updated, expected) {
+		t.Errorf("Expected the with block to end with:\n%s\ngot:\n%s", expected, updated)
+	}
+
+	var warnings []string
+	for _, change := range changes {
+		if IsWarning(change) {
+			warnings = append(warnings, change)
+		}
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "Job 'build', Step 1: Warning: Input 'overwrite' is a boolean") {
+		t.Errorf("Expected one warning for the quoted boolean, got %v", warnings)
+	}
+}
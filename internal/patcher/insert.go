@@ -0,0 +1,182 @@
+package patcher
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ActionInsertion is a required action to insert into a workflow
+type ActionInsertion struct {
+	Uses string            // Action or reusable workflow to call, e.g. "org/security-scan@v2"
+	Job  string            // Job receiving the action as its first step; empty inserts a new first job
+	With map[string]string // Inputs of the call
+}
+
+// jobIDPattern matches characters not allowed in job ids
+var jobIDPattern = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// InsertAction inserts a required action into workflow content, as a new first job or as the first step
+// of a job, and returns the updated content with a description of the change.
+// The insertion is made in the text rather than by re-marshaling, so comments and formatting are kept;
+// flow-style jobs and steps cannot be edited this way and return an error.
+func (wp *WorkflowPatcher) InsertAction(content string, insertion ActionInsertion) (string, string, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &root); err != nil {
+		return content, "", fmt.Errorf("failed to parse workflow YAML: %w", err)
+	}
+	if len(root.Content) == 0 {
+		return content, "", fmt.Errorf("workflow is empty")
+	}
+	jobs := nodeValue(root.Content[0], "jobs")
+	if jobs == nil || jobs.Kind != yaml.MappingNode || len(jobs.Content) == 0 {
+		return content, "", fmt.Errorf("workflow has no jobs")
+	}
+	if jobs.Style&yaml.FlowStyle != 0 {
+		return content, "", fmt.Errorf("flow-style jobs cannot be edited")
+	}
+
+	lines := strings.Split(content, "\n")
+	if insertion.Job == "" {
+		return insertJob(lines, jobs, insertion)
+	}
+	return insertStep(lines, jobs, insertion)
+}
+
+// insertJob inserts a job calling the action before the first job
+func insertJob(lines []string, jobs *yaml.Node, insertion ActionInsertion) (string, string, error) {
+	first := jobs.Content[0]
+	indent := strings.Repeat(" ", first.Column-1)
+	step := "  "
+	if body := jobs.Content[1]; body.Kind == yaml.MappingNode && len(body.Content) > 0 && body.Content[0].Column > first.Column {
+		step = strings.Repeat(" ", body.Content[0].Column-first.Column)
+	}
+
+	existing := make(map[string]bool)
+	for i := 0; i < len(jobs.Content); i += 2 {
+		existing[jobs.Content[i].Value] = true
+	}
+	id := jobID(insertion.Uses, existing)
+
+	block := []string{indent + id + ":"}
+	if strings.Contains(insertion.Uses, ".github/workflows/") {
+		block = append(block, indent+step+"uses: "+scalar(insertion.Uses))
+		block = append(block, withLines(indent+step, step, insertion.With)...)
+	} else {
+		block = append(block,
+			indent+step+"runs-on: ubuntu-latest",
+			indent+step+"steps:",
+			indent+step+step+"- uses: "+scalar(insertion.Uses))
+		block = append(block, withLines(indent+step+step+"  ", step, insertion.With)...)
+	}
+
+	at := leadingCommentStart(lines, first.Line-1)
+	return spliceLines(lines, at, block), fmt.Sprintf("Inserted job '%s' calling %s before the first job", id, insertion.Uses), nil
+}
+
+// insertStep inserts a step using the action before the first step of a job
+func insertStep(lines []string, jobs *yaml.Node, insertion ActionInsertion) (string, string, error) {
+	job := nodeValue(jobs, insertion.Job)
+	if job == nil || job.Kind != yaml.MappingNode {
+		return "", "", fmt.Errorf("job %s not found", insertion.Job)
+	}
+	steps := nodeValue(job, "steps")
+	if steps == nil || steps.Kind != yaml.SequenceNode || len(steps.Content) == 0 {
+		return "", "", fmt.Errorf("job %s has no steps", insertion.Job)
+	}
+	if steps.Style&yaml.FlowStyle != 0 {
+		return "", "", fmt.Errorf("flow-style steps of job %s cannot be edited", insertion.Job)
+	}
+
+	first := steps.Content[0]
+	line := lines[first.Line-1]
+	if first.Column-1 > len(line) || strings.TrimSpace(line[:first.Column-1]) != "-" {
+		return "", "", fmt.Errorf("first step of job %s is not on its list item line", insertion.Job)
+	}
+	dash := line[:len(line)-len(strings.TrimLeft(line, " "))]
+	content := strings.Repeat(" ", first.Column-1)
+
+	block := []string{dash + "- uses: " + scalar(insertion.Uses)}
+	block = append(block, withLines(content, "  ", insertion.With)...)
+
+	at := leadingCommentStart(lines, first.Line-1)
+	return spliceLines(lines, at, block), fmt.Sprintf("Job '%s': Inserted step using %s before the first step", insertion.Job, insertion.Uses), nil
+}
+
+// withLines renders a with: block of inputs in key order, or nothing when there are no inputs
+func withLines(indent, step string, with map[string]string) []string {
+	if len(with) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(with))
+	for key := range with {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lines := []string{indent + "with:"}
+	for _, key := range keys {
+		lines = append(lines, indent+step+scalar(key)+": "+scalar(with[key]))
+	}
+	return lines
+}
+
+// scalar renders a string as a YAML scalar, quoting it only when needed
+func scalar(value string) string {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%q", value)
+	}
+	return strings.TrimSuffix(string(data), "\n")
+}
+
+// jobID derives a job id from the action name that does not collide with existing jobs
+func jobID(uses string, existing map[string]bool) string {
+	name, _, _ := strings.Cut(uses, "@")
+	name = name[strings.LastIndex(name, "/")+1:]
+	name = strings.TrimSuffix(strings.TrimSuffix(name, ".yml"), ".yaml")
+	base := strings.Trim(jobIDPattern.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if base == "" || !(base[0] == '_' || base[0] >= 'a' && base[0] <= 'z') {
+		base = "required-" + base
+	}
+
+	id := base
+	for n := 2; existing[id]; n++ {
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+	return id
+}
+
+// leadingCommentStart returns the first line of the comment block directly above a line, so an insertion
+// does not separate a job or step from its comments
+func leadingCommentStart(lines []string, index int) int {
+	for index > 0 && strings.HasPrefix(strings.TrimSpace(lines[index-1]), "#") {
+		index--
+	}
+	return index
+}
+
+// spliceLines inserts a block of lines before a line index and joins the result
+func spliceLines(lines []string, at int, block []string) string {
+	updated := make([]string, 0, len(lines)+len(block))
+	updated = append(updated, lines[:at]...)
+	updated = append(updated, block...)
+	updated = append(updated, lines[at:]...)
+	return strings.Join(updated, "\n")
+}
+
+// nodeValue returns the value of a key in a mapping node, or nil
+func nodeValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
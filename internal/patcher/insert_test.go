@@ -0,0 +1,134 @@
+package patcher
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInsertAction_FirstJob(t *testing.T) {
+	content := `name: CI
+on: push
+jobs:
+    # Unit tests
+    test:
+        runs-on: ubuntu-latest
+        steps:
+            - uses: actions/checkout@v4
+    scan:
+        runs-on: ubuntu-latest
+        steps:
+            - run: echo scan
+`
+	updated, change, err := NewWorkflowPatcher().InsertAction(content, ActionInsertion{
+		Uses: "my-org/security-scan@v2",
+		With: map[string]string{"level": "high", "fail": "true"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The job id avoids the existing scan job, and the comment stays with the test job
+	expected := `name: CI
+on: push
+jobs:
+    security-scan:
+        runs-on: ubuntu-latest
+        steps:
+            - uses: my-org/security-scan@v2
+              with:
+                  fail: "true"
+                  level: high
+    # Unit tests
+    test:
+        runs-on: ubuntu-latest
+`
+	if !strings.HasPrefix(updated, expected) {
+		t.Errorf("Expected content to start with:\n%s\ngot:\n%s", expected, updated)
+	}
+	if !strings.Contains(change, "security-scan") {
+		t.Errorf("Expected change to name the inserted job, got %q", change)
+	}
+}
+
+func TestInsertAction_ReusableWorkflowJob(t *testing.T) {
+	content := `on: push
+jobs:
+  scan:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo scan
+`
+	updated, _, err := NewWorkflowPatcher().InsertAction(content, ActionInsertion{
+		Uses: "my-org/security/.github/workflows/scan.yml@v2",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `on: push
+jobs:
+  scan-2:
+    uses: my-org/security/.github/workflows/scan.yml@v2
+  scan:
+`
+	if !strings.HasPrefix(updated, expected) {
+		t.Errorf("Expected content to start with:\n%s\ngot:\n%s", expected, updated)
+	}
+}
+
+func TestInsertAction_FirstStep(t *testing.T) {
+	content := `on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+    - name: Checkout
+      uses: actions/checkout@v4 # pinned
+    - run: make
+`
+	updated, _, err := NewWorkflowPatcher().InsertAction(content, ActionInsertion{
+		Uses: "step-security/harden-runner@v2",
+		Job:  "build",
+		With: map[string]string{"egress-policy": "audit"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+    - uses: step-security/harden-runner@v2
+      with:
+        egress-policy: audit
+    - name: Checkout
+      uses: actions/checkout@v4 # pinned
+    - run: make
+`
+	if updated != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, updated)
+	}
+}
+
+func TestInsertAction_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		insertion ActionInsertion
+	}{
+		{"no jobs", "on: push\n", ActionInsertion{Uses: "a/b@v1"}},
+		{"flow jobs", "on: push\njobs: {test: {runs-on: ubuntu-latest}}\n", ActionInsertion{Uses: "a/b@v1"}},
+		{"unknown job", "on: push\njobs:\n  test:\n    steps:\n      - run: x\n", ActionInsertion{Uses: "a/b@v1", Job: "build"}},
+		{"flow steps", "on: push\njobs:\n  test:\n    steps: [{run: x}]\n", ActionInsertion{Uses: "a/b@v1", Job: "test"}},
+		{"reusable job", "on: push\njobs:\n  test:\n    uses: a/b/.github/workflows/c.yml@v1\n", ActionInsertion{Uses: "a/b@v1", Job: "test"}},
+	}
+
+	for _, tt := range tests {
+		updated, _, err := NewWorkflowPatcher().InsertAction(tt.content, tt.insertion)
+		if err == nil {
+			t.Errorf("%s: expected an error, got content:\n%s", tt.name, updated)
+		}
+	}
+}
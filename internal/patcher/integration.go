@@ -1,6 +1,7 @@
 package patcher
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
 	"gopkg.in/yaml.v3"
@@ -10,7 +11,8 @@ import (
 
 // WorkflowPatcher provides high-level workflow patching capabilities
 type WorkflowPatcher struct {
-	patcher *Patcher
+	patcher    *Patcher
+	inputTypes InputTypeSource // Checks patched inputs when set
 }
 
 // NewWorkflowPatcher creates a new workflow patcher
@@ -35,20 +37,18 @@ func parseActionRef(uses string) *workflow.ActionReference {
 
 	// Regular expression to parse action references
 	// Supports: owner/repo@version, owner/repo/path@version
-	re := regexp.MustCompile(`^([^/@]+/[^/@]+)(?:/[^@]*)?@(.+)$`)
+	re := regexp.MustCompile(`^([^/@]+/[^/@]+)(?:/([^@]*))?@(.+)$`)
 	matches := re.FindStringSubmatch(uses)
 
-	if len(matches) != 3 {
+	if len(matches) != 4 {
 		return nil // Invalid format
 	}
 
-	repository := matches[1]
-	version := matches[2]
-
 	return &workflow.ActionReference{
-		Repository: repository,
-		Version:    version,
-		IsReusable: false,
+		Repository:   matches[1],
+		WorkflowPath: matches[2],
+		Version:      matches[3],
+		IsReusable:   false,
 	}
 }
 
@@ -81,6 +81,12 @@ func (wp *WorkflowPatcher) PatchStep(step *workflow.Step, fromVersion, toVersion
 
 // PatchStepWithLocation applies patches to a workflow step with potential location change
 func (wp *WorkflowPatcher) PatchStepWithLocation(step *workflow.Step, fromVersion, toVersion, toRepository string) (*Patch, error) {
+	return wp.PatchStepWithPath(step, fromVersion, toVersion, toRepository, "")
+}
+
+// PatchStepWithPath applies patches to a workflow step with a potential repository and path change
+// An empty toPath keeps the step's current path within the repository.
+func (wp *WorkflowPatcher) PatchStepWithPath(step *workflow.Step, fromVersion, toVersion, toRepository, toPath string) (*Patch, error) {
 	if step.Uses == "" {
 		return nil, fmt.Errorf("step does not use an action")
 	}
@@ -102,17 +108,16 @@ func (wp *WorkflowPatcher) PatchStepWithLocation(step *workflow.Step, fromVersio
 		step.With = patch.UpdatedWith
 	}
 
-	// If repository changed, update the uses field regardless of whether patches were applied
-	if toRepository != actionRef.Repository {
-		// Preserve any path after repository name (e.g., "owner/repo/path@version")
-		oldUses := step.Uses
-		re := regexp.MustCompile(`^([^/@]+/[^/@]+)(.*)@(.+)$`)
-		matches := re.FindStringSubmatch(oldUses)
-		if len(matches) == 4 {
-			path := matches[2] // Could be empty or "/subpath"
-			step.Uses = toRepository + path + "@" + toVersion
+	// If repository or path changed, update the uses field regardless of whether patches were applied
+	if toRepository != actionRef.Repository || (toPath != "" && toPath != actionRef.WorkflowPath) {
+		// Preserve any path after repository name (e.g., "owner/repo/path@version") unless it is renamed
+		path := actionRef.WorkflowPath
+		if toPath != "" {
+			path = toPath
+		}
+		if path != "" {
+			step.Uses = toRepository + "/" + path + "@" + toVersion
 		} else {
-			// Fallback to simple replacement
 			step.Uses = toRepository + "@" + toVersion
 		}
 	}
@@ -121,73 +126,113 @@ func (wp *WorkflowPatcher) PatchStepWithLocation(step *workflow.Step, fromVersio
 }
 
 // PatchWorkflowContent applies patches to workflow YAML content during version updates
-// This function can be used by the PR creator to apply schema changes when updating workflow files
+// This function can be used by the PR creator to apply schema changes when updating workflow files.
+// The workflow is edited as a YAML node tree, so keys the parser does not model (if:, id:, needs:, ...)
+// and comments are kept; the file is re-indented when any patch applies.
 func (wp *WorkflowPatcher) PatchWorkflowContent(content string, updates []ActionVersionUpdate) (string, []string, error) {
 	// Parse the workflow
-	var workflow workflow.Workflow
-	if err := yaml.Unmarshal([]byte(content), &workflow); err != nil {
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &root); err != nil {
 		return content, nil, fmt.Errorf("failed to parse workflow YAML: %w", err)
 	}
+	if len(root.Content) == 0 {
+		return content, nil, nil
+	}
+	jobs := nodeValue(root.Content[0], "jobs")
+	if jobs == nil || jobs.Kind != yaml.MappingNode {
+		return content, nil, nil
+	}
 
 	var allChanges []string
 	patchingApplied := false
 
 	// Process each job
-	for jobName, job := range workflow.Jobs {
-		// Process job steps
-		for stepIdx, step := range job.Steps {
-			if step.Uses == "" {
+	for jobIdx := 0; jobIdx+1 < len(jobs.Content); jobIdx += 2 {
+		jobName := jobs.Content[jobIdx].Value
+		steps := nodeValue(jobs.Content[jobIdx+1], "steps")
+		if steps == nil || steps.Kind != yaml.SequenceNode {
+			continue
+		}
+
+		// Process job steps; step operations may insert or delete steps around the current one
+		for stepIdx := 0; stepIdx < len(steps.Content); stepIdx++ {
+			stepNode := steps.Content[stepIdx]
+			var step workflow.Step
+			if err := stepNode.Decode(&step); err != nil || step.Uses == "" {
 				continue
 			}
 
 			// Check if this step needs patching
 			for _, update := range updates {
-				if wp.stepMatchesUpdate(&step, update) {
-					var patch *Patch
-					var err error
-
-					// Determine target repository
-					targetRepo := update.ActionRepo
-					if update.ToActionRepo != "" {
-						targetRepo = update.ToActionRepo
-					}
+				if !wp.stepMatchesUpdate(&step, update) {
+					continue
+				}
 
-					// Apply patching with potential location change
-					if targetRepo != update.ActionRepo {
-						patch, err = wp.PatchStepWithLocation(&step, update.FromVersion, update.ToVersion, targetRepo)
-					} else {
-						patch, err = wp.PatchStep(&step, update.FromVersion, update.ToVersion)
-					}
+				var patch *Patch
+				var err error
 
-					if err != nil {
+				// Determine target repository
+				targetRepo := update.ActionRepo
+				if update.ToActionRepo != "" {
+					targetRepo = update.ToActionRepo
+				}
+
+				// Apply patching with potential location change
+				if targetRepo != update.ActionRepo || (update.ToPath != "" && update.ToPath != update.FromPath) {
+					patch, err = wp.PatchStepWithPath(&step, update.FromVersion, update.ToVersion, targetRepo, update.ToPath)
+				} else {
+					patch, err = wp.PatchStep(&step, update.FromVersion, update.ToVersion)
+				}
+
+				if err != nil {
+					return content, allChanges, fmt.Errorf("failed to patch step in job %s, step %d: %w", jobName, stepIdx, err)
+				}
+
+				if patch.Applied {
+					// Update the step in the workflow
+					if err := updateStepNode(stepNode, step, patch); err != nil {
 						return content, allChanges, fmt.Errorf("failed to patch step in job %s, step %d: %w", jobName, stepIdx, err)
 					}
+					patchingApplied = true
 
-					if patch.Applied {
-						// Update the step in the workflow
-						workflow.Jobs[jobName].Steps[stepIdx] = step
-						patchingApplied = true
+					// Add changes to the list based on the new patch structure
+					for _, addition := range patch.Additions {
+						changeDescription := fmt.Sprintf("Job '%s', Step %d: Added '%s' = '%v' (%s)", jobName, stepIdx+1, addition.Field, addition.Value, addition.Reason)
+						allChanges = append(allChanges, changeDescription)
+					}
+					for _, removal := range patch.Removals {
+						changeDescription := fmt.Sprintf("Job '%s', Step %d: Removed '%s' (%s)", jobName, stepIdx+1, removal.Field, removal.Reason)
+						allChanges = append(allChanges, changeDescription)
+					}
+					for _, rename := range patch.Renames {
+						changeDescription := fmt.Sprintf("Job '%s', Step %d: Renamed '%s' to '%s' (%s)", jobName, stepIdx+1, rename.OldField, rename.NewField, rename.Reason)
+						allChanges = append(allChanges, changeDescription)
+					}
+					for _, modification := range patch.Modifications {
+						changeDescription := fmt.Sprintf("Job '%s', Step %d: Modified '%s' from '%v' to '%v' (%s)", jobName, stepIdx+1, modification.Field, modification.OldValue, modification.NewValue, modification.Reason)
+						allChanges = append(allChanges, changeDescription)
+					}
+					path := ""
+					if actionRef := parseActionRef(step.Uses); actionRef != nil {
+						path = actionRef.WorkflowPath
+					}
+					for _, warning := range wp.checkInputs(patch, path) {
+						allChanges = append(allChanges, fmt.Sprintf("Job '%s', Step %d: %s%s", jobName, stepIdx+1, WarningMarker, warning))
+					}
 
-						// Add changes to the list based on the new patch structure
-						for _, addition := range patch.Additions {
-							changeDescription := fmt.Sprintf("Job '%s', Step %d: Added '%s' = '%v' (%s)", jobName, stepIdx+1, addition.Field, addition.Value, addition.Reason)
-							allChanges = append(allChanges, changeDescription)
+					// Step-level changes, which may move the step within the job
+					for _, change := range patch.StepChanges {
+						newIdx, description, err := applyStepChange(steps, stepIdx, change)
+						if err != nil {
+							return content, allChanges, fmt.Errorf("failed to patch step in job %s, step %d: %w", jobName, stepIdx+1, err)
 						}
-						for _, removal := range patch.Removals {
-							changeDescription := fmt.Sprintf("Job '%s', Step %d: Removed '%s' (%s)", jobName, stepIdx+1, removal.Field, removal.Reason)
-							allChanges = append(allChanges, changeDescription)
-						}
-						for _, rename := range patch.Renames {
-							changeDescription := fmt.Sprintf("Job '%s', Step %d: Renamed '%s' to '%s' (%s)", jobName, stepIdx+1, rename.OldField, rename.NewField, rename.Reason)
-							allChanges = append(allChanges, changeDescription)
-						}
-						for _, modification := range patch.Modifications {
-							changeDescription := fmt.Sprintf("Job '%s', Step %d: Modified '%s' from '%v' to '%v' (%s)", jobName, stepIdx+1, modification.Field, modification.OldValue, modification.NewValue, modification.Reason)
-							allChanges = append(allChanges, changeDescription)
+						if description != "" {
+							allChanges = append(allChanges, fmt.Sprintf("Job '%s', Step %d: %s (%s)", jobName, stepIdx+1, description, change.Reason))
 						}
+						stepIdx = newIdx
 					}
-					break
 				}
+				break
 			}
 		}
 	}
@@ -198,12 +243,17 @@ func (wp *WorkflowPatcher) PatchWorkflowContent(content string, updates []Action
 	}
 
 	// Marshal the updated workflow back to YAML
-	updatedData, err := yaml.Marshal(&workflow)
-	if err != nil {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&root); err != nil {
+		return content, allChanges, fmt.Errorf("failed to marshal updated workflow: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
 		return content, allChanges, fmt.Errorf("failed to marshal updated workflow: %w", err)
 	}
 
-	return string(updatedData), allChanges, nil
+	return buf.String(), allChanges, nil
 }
 
 // ActionVersionUpdate represents an action version update that needs transformation
@@ -212,6 +262,8 @@ type ActionVersionUpdate struct {
 	FromVersion  string
 	ToVersion    string
 	ToActionRepo string // Target repository (if different from ActionRepo)
+	FromPath     string // Path within ActionRepo (e.g., "init" in "github/codeql-action/init"); empty matches any path
+	ToPath       string // Target path within the repository (if renamed)
 	FilePath     string // workflow file path for context
 }
 
@@ -227,7 +279,10 @@ func (wp *WorkflowPatcher) stepMatchesUpdate(step *workflow.Step, update ActionV
 		return false
 	}
 
-	// Check if repository and current version match
+	// Check if repository, path (when given), and current version match
+	if update.FromPath != "" && actionRef.WorkflowPath != update.FromPath {
+		return false
+	}
 	return actionRef.Repository == update.ActionRepo && actionRef.Version == update.FromVersion
 }
 
@@ -251,18 +306,39 @@ func (wp *WorkflowPatcher) GetPatchInfo(repository, fromVersion, toVersion strin
 		return nil, false
 	}
 
-	// Find the version patch
-	for _, patch := range rule.VersionPatches {
-		if patch.FromVersion == fromVersion && patch.ToVersion == toVersion {
-			return &patch, true
+	// Find the version patch, which may name versions with wildcards or ranges
+	patch := findVersionPatch(rule.VersionPatches, fromVersion, toVersion, func(VersionPatch) bool { return true })
+	return patch, patch != nil
+}
+
+// UpgradeTarget returns the version patch that upgrades a version of an action to an exact version, for
+// tools that pick upgrades from the patch rules rather than from scan results
+func (wp *WorkflowPatcher) UpgradeTarget(repository, fromVersion string) (*VersionPatch, bool) {
+	rule, exists := wp.patcher.GetPatchRules()[repository]
+	if !exists {
+		return nil, false
+	}
+	for _, vp := range rule.VersionPatches {
+		if vp.FromVersion == fromVersion && !isVersionPattern(vp.ToVersion) {
+			return &vp, true
+		}
+	}
+	for _, vp := range rule.VersionPatches {
+		if versionMatches(vp.FromVersion, fromVersion) && !isVersionPattern(vp.ToVersion) {
+			return &vp, true
 		}
 	}
-
 	return nil, false
 }
 
 // PreviewChanges shows what changes would be made without actually applying them
 func (wp *WorkflowPatcher) PreviewChanges(repository, fromVersion, toVersion string, withBlock interface{}) (*Patch, error) {
+	return wp.PreviewChangesWithLocation(repository, fromVersion, toVersion, repository, withBlock)
+}
+
+// PreviewChangesWithLocation shows what changes an upgrade with a potential repository change would make
+// without actually applying them
+func (wp *WorkflowPatcher) PreviewChangesWithLocation(fromRepository, fromVersion, toVersion, toRepository string, withBlock interface{}) (*Patch, error) {
 	// Create a copy of the with block for preview
 	var withCopy interface{}
 	if withBlock != nil {
@@ -278,7 +354,17 @@ func (wp *WorkflowPatcher) PreviewChanges(repository, fromVersion, toVersion str
 	}
 
 	// Build patch for the copy
-	return wp.patcher.BuildPatch(repository, fromVersion, toVersion, withCopy)
+	patch, err := wp.patcher.BuildPatchWithLocation(fromRepository, fromVersion, toVersion, toRepository, withCopy)
+	if err != nil {
+		return patch, err
+	}
+	wp.checkInputs(patch, "")
+	return patch, nil
+}
+
+// AddPatchRule adds a custom patch rule to the underlying patcher
+func (wp *WorkflowPatcher) AddPatchRule(rule ActionPatchRule) {
+	wp.patcher.AddPatchRule(rule)
 }
 
 // HasPatch checks if a patch is available for the given repository and version transition
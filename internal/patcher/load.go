@@ -0,0 +1,61 @@
+package patcher
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadPatchRules reads patch rules from a YAML or JSON file holding a list of action patch rules
+func LoadPatchRules(path string) ([]ActionPatchRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read patch rules file: %w", err)
+	}
+	return ParsePatchRules(data)
+}
+
+// ParsePatchRules parses and validates a list of action patch rules; JSON is parsed as YAML
+func ParsePatchRules(data []byte) ([]ActionPatchRule, error) {
+	var rules []ActionPatchRule
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("unable to parse patch rules: %w", err)
+	}
+
+	for i, rule := range rules {
+		if rule.Repository == "" {
+			return nil, fmt.Errorf("patch rule %d: repository is required", i+1)
+		}
+		for j, vp := range rule.VersionPatches {
+			if vp.FromVersion == "" || vp.ToVersion == "" {
+				return nil, fmt.Errorf("patch rule %d (%s), version patch %d: from_version and to_version are required", i+1, rule.Repository, j+1)
+			}
+			for k, fieldPatch := range vp.Patches {
+				if err := validateFieldPatch(fieldPatch); err != nil {
+					return nil, fmt.Errorf("patch rule %d (%s), version patch %d, patch %d: %w", i+1, rule.Repository, j+1, k+1, err)
+				}
+			}
+		}
+	}
+	return rules, nil
+}
+
+// validateFieldPatch checks that a field patch names a known operation with the fields it needs
+func validateFieldPatch(fieldPatch FieldPatch) error {
+	switch fieldPatch.Operation {
+	case OperationAdd, OperationRemove, OperationModify:
+		if fieldPatch.Field == "" {
+			return fmt.Errorf("field must be specified for %s operation", fieldPatch.Operation)
+		}
+	case OperationRename:
+		if fieldPatch.Field == "" || fieldPatch.NewField == "" {
+			return fmt.Errorf("field and new_field must be specified for %s operation", fieldPatch.Operation)
+		}
+	case OperationSetEnv, OperationRemoveEnv, OperationAddCondition, OperationSetName, OperationInsertStep, OperationDeleteStep:
+		return NewPatcher().addStepChange(fieldPatch, &Patch{})
+	default:
+		return fmt.Errorf("unknown operation: %s", fieldPatch.Operation)
+	}
+	return nil
+}
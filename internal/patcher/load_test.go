@@ -0,0 +1,56 @@
+package patcher
+
+import "testing"
+
+func TestParsePatchRules(t *testing.T) {
+	data := []byte(`
+- repository: actions/setup-node
+  version_patches:
+    - from_version: "v2.*"
+      to_version: v4
+      description: Built-in caching
+      patches:
+        - operation: add
+          field: cache
+          value: npm
+          reason: Cache dependencies
+        - operation: delete-step
+          field: actions/cache
+          position: after
+`)
+	rules, err := ParsePatchRules(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rules) != 1 || len(rules[0].VersionPatches) != 1 || len(rules[0].VersionPatches[0].Patches) != 2 {
+		t.Fatalf("Expected one rule with two patches, got %+v", rules)
+	}
+	if patch := rules[0].VersionPatches[0].Patches[1]; patch.Operation != OperationDeleteStep || patch.Position != PositionAfter {
+		t.Errorf("Expected a delete-step patch after the step, got %+v", patch)
+	}
+
+	// JSON is read as YAML
+	if _, err := ParsePatchRules([]byte(`[{"repository": "actions/checkout", "version_patches": [{"from_version": "v1", "to_version": "v4", "patches": [{"operation": "remove", "field": "token"}]}]}]`)); err != nil {
+		t.Errorf("Expected JSON rules to parse, got %v", err)
+	}
+}
+
+func TestParsePatchRules_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"not a list", "repository: actions/checkout"},
+		{"missing repository", "- version_patches: []"},
+		{"missing versions", "- repository: a/b\n  version_patches:\n    - from_version: v1\n"},
+		{"unknown operation", "- repository: a/b\n  version_patches:\n    - {from_version: v1, to_version: v2, patches: [{operation: drop, field: x}]}\n"},
+		{"rename without new field", "- repository: a/b\n  version_patches:\n    - {from_version: v1, to_version: v2, patches: [{operation: rename, field: x}]}\n"},
+		{"invalid position", "- repository: a/b\n  version_patches:\n    - {from_version: v1, to_version: v2, patches: [{operation: delete-step, field: a/c, position: below}]}\n"},
+	}
+
+	for _, tt := range tests {
+		if _, err := ParsePatchRules([]byte(tt.data)); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
@@ -17,6 +17,27 @@ const (
 	OperationRename Operation = "rename"
 	// OperationModify changes the value or structure of an existing field
 	OperationModify Operation = "modify"
+
+	// Step-level operations change the step around the with block; they are applied by PatchWorkflowContent
+
+	// OperationSetEnv sets a key of the step's env block to Value
+	OperationSetEnv Operation = "set-env"
+	// OperationRemoveEnv removes a key from the step's env block
+	OperationRemoveEnv Operation = "remove-env"
+	// OperationAddCondition adds an if: condition to the step, combined with && with any existing condition
+	OperationAddCondition Operation = "add-if"
+	// OperationSetName changes the step's name to Value
+	OperationSetName Operation = "set-name"
+	// OperationInsertStep inserts Value, a step mapping, next to the step
+	OperationInsertStep Operation = "insert-step"
+	// OperationDeleteStep deletes the adjacent step using the action repository in Field
+	OperationDeleteStep Operation = "delete-step"
+)
+
+// Positions of steps inserted or deleted next to the patched step
+const (
+	PositionBefore = "before"
+	PositionAfter  = "after"
 )
 
 // FieldPatch represents a single field transformation (internal for rules)
@@ -25,6 +46,7 @@ type FieldPatch struct {
 	Field     string      `yaml:"field"`
 	NewField  string      `yaml:"new_field,omitempty"` // For rename operations
 	Value     interface{} `yaml:"value,omitempty"`     // For add/modify operations
+	Position  string      `yaml:"position,omitempty"`  // For insert-step (default after) and delete-step (default either side)
 	Reason    string      `yaml:"reason"`              // Why this change is needed
 }
 
@@ -72,6 +94,15 @@ type FieldModification struct {
 	Reason   string      `json:"reason"`
 }
 
+// StepChange represents a step-level change around the with block
+type StepChange struct {
+	Operation Operation   `json:"operation"`
+	Field     string      `json:"field,omitempty"`
+	Value     interface{} `json:"value,omitempty"`
+	Position  string      `json:"position,omitempty"`
+	Reason    string      `json:"reason"`
+}
+
 // Patch represents all changes needed for an action upgrade
 type Patch struct {
 	Repository     string `json:"repository"`
@@ -86,6 +117,7 @@ type Patch struct {
 	Removals      []FieldRemoval      `json:"removals,omitempty"`
 	Renames       []FieldRename       `json:"renames,omitempty"`
 	Modifications []FieldModification `json:"modifications,omitempty"`
+	StepChanges   []StepChange        `json:"step_changes,omitempty"` // Applied to the workflow by PatchWorkflowContent
 
 	// Results after applying the patch
 	Applied      bool        `json:"applied"`
@@ -150,26 +182,10 @@ func (p *Patcher) BuildPatchWithLocation(fromRepository, fromVersion, toVersion,
 		return patch, nil // No patch rules defined for either repository
 	}
 
-	// Find the appropriate version patch that matches our migration
-	var versionPatch *VersionPatch
-	for _, vp := range rule.VersionPatches {
-		// Check for exact match with location change
-		if vp.FromVersion == fromVersion && vp.ToVersion == toVersion {
-			// If both repositories are specified in the patch, ensure they match
-			if vp.FromRepository != "" && vp.ToRepository != "" {
-				if vp.FromRepository == fromRepository && vp.ToRepository == toRepository {
-					versionPatch = &vp
-					break
-				}
-			} else {
-				// If no repository migration specified, match any same-repo transition
-				if fromRepository == toRepository {
-					versionPatch = &vp
-					break
-				}
-			}
-		}
-	}
+	// Find the appropriate version patch that matches our migration; exact versions win over wildcards and ranges
+	versionPatch := findVersionPatch(rule.VersionPatches, fromVersion, toVersion, func(vp VersionPatch) bool {
+		return locationMatches(vp, fromRepository, toRepository)
+	})
 
 	if versionPatch == nil {
 		return patch, nil // No specific patch for this version/location transition
@@ -189,7 +205,7 @@ func (p *Patcher) BuildPatchWithLocation(fromRepository, fromVersion, toVersion,
 		return patch, fmt.Errorf("failed to apply patches: %w", err)
 	}
 
-	patch.Applied = len(patch.Additions) > 0 || len(patch.Removals) > 0 || len(patch.Renames) > 0 || len(patch.Modifications) > 0 || (fromRepository != toRepository)
+	patch.Applied = len(patch.Additions) > 0 || len(patch.Removals) > 0 || len(patch.Renames) > 0 || len(patch.Modifications) > 0 || len(patch.StepChanges) > 0 || (fromRepository != toRepository)
 	patch.UpdatedWith = updatedWith
 
 	return patch, nil
@@ -219,11 +235,44 @@ func (p *Patcher) applyFieldPatch(withMap map[string]interface{}, fieldPatch Fie
 		return p.applyRenamePatch(withMap, fieldPatch, patch)
 	case OperationModify:
 		return p.applyModifyPatch(withMap, fieldPatch, patch)
+	case OperationSetEnv, OperationRemoveEnv, OperationAddCondition, OperationSetName, OperationInsertStep, OperationDeleteStep:
+		return p.addStepChange(fieldPatch, patch)
 	default:
 		return fmt.Errorf("unknown operation: %s", fieldPatch.Operation)
 	}
 }
 
+// addStepChange validates a step-level operation and records it for PatchWorkflowContent to apply
+func (p *Patcher) addStepChange(fieldPatch FieldPatch, patch *Patch) error {
+	switch fieldPatch.Operation {
+	case OperationSetEnv, OperationRemoveEnv, OperationDeleteStep:
+		if fieldPatch.Field == "" {
+			return fmt.Errorf("field must be specified for %s operation", fieldPatch.Operation)
+		}
+	case OperationAddCondition, OperationSetName:
+		if value, ok := fieldPatch.Value.(string); !ok || value == "" {
+			return fmt.Errorf("value must be a non-empty string for %s operation", fieldPatch.Operation)
+		}
+	case OperationInsertStep:
+		step, err := p.toMap(fieldPatch.Value)
+		if err != nil || len(step) == 0 {
+			return fmt.Errorf("value must be a step mapping for %s operation", fieldPatch.Operation)
+		}
+	}
+	if fieldPatch.Position != "" && fieldPatch.Position != PositionBefore && fieldPatch.Position != PositionAfter {
+		return fmt.Errorf("invalid position %q: must be %q or %q", fieldPatch.Position, PositionBefore, PositionAfter)
+	}
+
+	patch.StepChanges = append(patch.StepChanges, StepChange{
+		Operation: fieldPatch.Operation,
+		Field:     fieldPatch.Field,
+		Value:     fieldPatch.Value,
+		Position:  fieldPatch.Position,
+		Reason:    fieldPatch.Reason,
+	})
+	return nil
+}
+
 // applyAddPatch adds a new field to the with block
 func (p *Patcher) applyAddPatch(withMap map[string]interface{}, fieldPatch FieldPatch, patch *Patch) error {
 	if _, exists := withMap[fieldPatch.Field]; exists {
@@ -313,7 +362,12 @@ func (p *Patcher) toMap(input interface{}) (map[string]interface{}, error) {
 
 	switch v := input.(type) {
 	case map[string]interface{}:
-		return v, nil
+		// Copied, so the original with block recorded in the patch is left as it was
+		result := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			result[key] = value
+		}
+		return result, nil
 	case map[interface{}]interface{}:
 		// Convert map[interface{}]interface{} to map[string]interface{}
 		result := make(map[string]interface{})
@@ -367,20 +421,7 @@ func (p *Patcher) HasPatchWithLocation(fromRepository, fromVersion, toVersion, t
 		}
 	}
 
-	for _, patch := range rule.VersionPatches {
-		if patch.FromVersion == fromVersion && patch.ToVersion == toVersion {
-			// If both repositories are specified in the patch, ensure they match
-			if patch.FromRepository != "" && patch.ToRepository != "" {
-				if patch.FromRepository == fromRepository && patch.ToRepository == toRepository {
-					return true
-				}
-			} else {
-				// If no repository migration specified, match same-repo transitions
-				if fromRepository == toRepository {
-					return true
-				}
-			}
-		}
-	}
-	return false
+	return findVersionPatch(rule.VersionPatches, fromVersion, toVersion, func(vp VersionPatch) bool {
+		return locationMatches(vp, fromRepository, toRepository)
+	}) != nil
 }
@@ -205,6 +205,11 @@ func TestCheckoutV1ToV4Transformation(t *testing.T) {
 		t.Errorf("Expected fetch-depth to be 1, got %v", fetchDepth)
 	}
 
+	// The original with block is recorded unchanged
+	if _, hasToken := patch.OriginalWith.(map[string]interface{})["token"]; !hasToken {
+		t.Error("Expected the original with block to keep the token field")
+	}
+
 	// Check that the patch structure is populated correctly
 	expectedRemovals := 1  // token removal
 	expectedAdditions := 1 // fetch-depth addition
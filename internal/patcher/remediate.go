@@ -0,0 +1,271 @@
+package patcher
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ActionRemediation removes or replaces every call of an action at a version
+type ActionRemediation struct {
+	Uses        string            // Call to remediate as written, e.g. "acme/upload@v1"; the repository is matched case-insensitively
+	Remove      bool              // Delete the steps using the action
+	Replacement string            // Call replacing it, e.g. "my-org/upload@v2", when not removing
+	WithMapping map[string]string // Inputs renamed for the replacement; an empty name drops the input
+	With        map[string]string // Inputs set on the replacement
+}
+
+// lineEdit replaces lines [start, end) with new lines
+type lineEdit struct {
+	start, end int
+	lines      []string
+}
+
+// RemediateAction removes or replaces the steps calling an action and returns the updated content with
+// a description of each change. Jobs calling the action as a reusable workflow are replaced but never
+// removed, as other jobs may need them. Like InsertAction, edits are made in the text so comments and
+// formatting are kept; calls in flow-style YAML return an error.
+func (wp *WorkflowPatcher) RemediateAction(content string, remediation ActionRemediation) (string, []string, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &root); err != nil {
+		return content, nil, fmt.Errorf("failed to parse workflow YAML: %w", err)
+	}
+	if len(root.Content) == 0 {
+		return content, nil, nil
+	}
+	jobs := nodeValue(root.Content[0], "jobs")
+	if jobs == nil || jobs.Kind != yaml.MappingNode {
+		return content, nil, nil
+	}
+
+	lines := strings.Split(content, "\n")
+	var edits []lineEdit
+	var changes []string
+	for i := 0; i+1 < len(jobs.Content); i += 2 {
+		jobName, job := jobs.Content[i].Value, jobs.Content[i+1]
+		if job.Kind != yaml.MappingNode {
+			continue
+		}
+
+		if uses := nodeValue(job, "uses"); uses != nil && callMatches(uses.Value, remediation.Uses) {
+			if remediation.Remove {
+				continue
+			}
+			if job.Style&yaml.FlowStyle != 0 {
+				return content, nil, fmt.Errorf("flow-style job %s cannot be edited", jobName)
+			}
+			callEdits, callChanges, err := replaceCall(lines, job, remediation)
+			if err != nil {
+				return content, nil, fmt.Errorf("job %s: %w", jobName, err)
+			}
+			edits = append(edits, callEdits...)
+			for _, change := range callChanges {
+				changes = append(changes, fmt.Sprintf("Job '%s': %s", jobName, change))
+			}
+		}
+
+		steps := nodeValue(job, "steps")
+		if steps == nil || steps.Kind != yaml.SequenceNode {
+			continue
+		}
+		removed := 0
+		for stepIdx, step := range steps.Content {
+			uses := nodeValue(step, "uses")
+			if uses == nil || !callMatches(uses.Value, remediation.Uses) {
+				continue
+			}
+			if steps.Style&yaml.FlowStyle != 0 || step.Style&yaml.FlowStyle != 0 {
+				return content, nil, fmt.Errorf("flow-style steps of job %s cannot be edited", jobName)
+			}
+
+			if remediation.Remove {
+				line := step.Line - 1
+				if !strings.HasPrefix(strings.TrimSpace(lines[line]), "-") {
+					return content, nil, fmt.Errorf("step %d of job %s is not on its list item line", stepIdx+1, jobName)
+				}
+				dashIndent := indentation(lines[line])
+				edits = append(edits, lineEdit{start: leadingCommentStart(lines, line), end: blockEnd(lines, line, dashIndent)})
+				changes = append(changes, fmt.Sprintf("Job '%s', Step %d: Removed %s", jobName, stepIdx+1, remediation.Uses))
+				removed++
+				continue
+			}
+
+			callEdits, callChanges, err := replaceCall(lines, step, remediation)
+			if err != nil {
+				return content, nil, fmt.Errorf("job %s, step %d: %w", jobName, stepIdx+1, err)
+			}
+			edits = append(edits, callEdits...)
+			for _, change := range callChanges {
+				changes = append(changes, fmt.Sprintf("Job '%s', Step %d: %s", jobName, stepIdx+1, change))
+			}
+		}
+		if removed > 0 && removed == len(steps.Content) {
+			return content, nil, fmt.Errorf("removing %s would leave job %s without steps", remediation.Uses, jobName)
+		}
+	}
+
+	if len(edits) == 0 {
+		return content, nil, nil
+	}
+	return applyEdits(lines, edits), changes, nil
+}
+
+// replaceCall swaps the uses: of a step or job to the replacement and maps its with: inputs
+func replaceCall(lines []string, call *yaml.Node, remediation ActionRemediation) ([]lineEdit, []string, error) {
+	usesKey, usesValue := mappingEntry(call, "uses")
+	line := lines[usesValue.Line-1]
+	edits := []lineEdit{{
+		start: usesValue.Line - 1,
+		end:   usesValue.Line,
+		lines: []string{line[:usesValue.Column-1] + scalar(remediation.Replacement)},
+	}}
+	changes := []string{fmt.Sprintf("Replaced %s with %s", remediation.Uses, remediation.Replacement)}
+
+	withKey, with := mappingEntry(call, "with")
+	if with == nil {
+		if len(remediation.With) == 0 {
+			return edits, changes, nil
+		}
+		indent := strings.Repeat(" ", usesKey.Column-1)
+		edits = append(edits, lineEdit{start: usesValue.Line, end: usesValue.Line, lines: withLines(indent, "  ", remediation.With)})
+		for _, key := range sortedKeys(remediation.With) {
+			changes = append(changes, fmt.Sprintf("Set input '%s'", key))
+		}
+		return edits, changes, nil
+	}
+	if len(remediation.WithMapping) == 0 && len(remediation.With) == 0 {
+		return edits, changes, nil
+	}
+	if with.Kind != yaml.MappingNode || with.Style&yaml.FlowStyle != 0 {
+		return nil, nil, fmt.Errorf("with: is not a block mapping")
+	}
+
+	present := make(map[string]bool)
+	kept := 0
+	for i := 0; i+1 < len(with.Content); i += 2 {
+		key, value := with.Content[i], with.Content[i+1]
+		keyLine := key.Line - 1
+		name := key.Value
+		if newName, mapped := remediation.WithMapping[key.Value]; mapped {
+			if newName == "" {
+				edits = append(edits, lineEdit{start: keyLine, end: blockEnd(lines, keyLine, key.Column-1)})
+				changes = append(changes, fmt.Sprintf("Dropped input '%s'", key.Value))
+				continue
+			}
+			name = newName
+		}
+		kept++
+		present[name] = true
+
+		text := lines[keyLine]
+		if !strings.HasPrefix(text[key.Column-1:], key.Value) {
+			return nil, nil, fmt.Errorf("input %s is quoted and cannot be edited", key.Value)
+		}
+		if newValue, set := remediation.With[name]; set {
+			if value.Kind != yaml.ScalarNode || value.Line != key.Line || value.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+				return nil, nil, fmt.Errorf("input %s is not a single-line value", key.Value)
+			}
+			text = text[:key.Column-1] + scalar(name) + ": " + scalar(newValue)
+			changes = append(changes, fmt.Sprintf("Set input '%s'", name))
+		} else if name != key.Value {
+			text = text[:key.Column-1] + scalar(name) + text[key.Column-1+len(key.Value):]
+		}
+		if name != key.Value {
+			changes = append(changes, fmt.Sprintf("Renamed input '%s' to '%s'", key.Value, name))
+		}
+		if text != lines[keyLine] {
+			edits = append(edits, lineEdit{start: keyLine, end: keyLine + 1, lines: []string{text}})
+		}
+	}
+
+	var added []string
+	for _, key := range sortedKeys(remediation.With) {
+		if !present[key] {
+			added = append(added, key)
+		}
+	}
+	withEnd := blockEnd(lines, withKey.Line-1, withKey.Column-1)
+	if kept == 0 && len(added) == 0 {
+		// Every input was dropped, so drop the empty with: block as well
+		return []lineEdit{edits[0], {start: withKey.Line - 1, end: withEnd}}, changes, nil
+	}
+	if len(added) > 0 {
+		indent := strings.Repeat(" ", with.Content[0].Column-1)
+		var addedLines []string
+		for _, key := range added {
+			addedLines = append(addedLines, indent+scalar(key)+": "+scalar(remediation.With[key]))
+			changes = append(changes, fmt.Sprintf("Set input '%s'", key))
+		}
+		edits = append(edits, lineEdit{start: withEnd, end: withEnd, lines: addedLines})
+	}
+	return edits, changes, nil
+}
+
+// callMatches reports whether a uses: value is the call being remediated, ignoring repository case
+func callMatches(uses, call string) bool {
+	usesName, usesVersion, _ := strings.Cut(uses, "@")
+	callName, callVersion, _ := strings.Cut(call, "@")
+	return strings.EqualFold(usesName, callName) && usesVersion == callVersion
+}
+
+// mappingEntry returns the key and value nodes of a key in a mapping node, or nils
+func mappingEntry(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// indentation returns the number of leading spaces of a line
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// blockEnd returns the index after the last line of the block starting at a line, whose following lines
+// are indented deeper than indent; trailing blank lines are not part of the block
+func blockEnd(lines []string, start, indent int) int {
+	end := start + 1
+	for i := start + 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "" {
+			continue
+		}
+		if indentation(lines[i]) <= indent {
+			break
+		}
+		end = i + 1
+	}
+	return end
+}
+
+// applyEdits applies non-overlapping line edits from the bottom up, so earlier line numbers stay valid
+// At the same line, replacements are applied before insertions so inserted lines land above them.
+func applyEdits(lines []string, edits []lineEdit) string {
+	sort.SliceStable(edits, func(i, j int) bool {
+		if edits[i].start != edits[j].start {
+			return edits[i].start > edits[j].start
+		}
+		return edits[i].end > edits[j].end
+	})
+	for _, edit := range edits {
+		updated := make([]string, 0, len(lines)-(edit.end-edit.start)+len(edit.lines))
+		updated = append(updated, lines[:edit.start]...)
+		updated = append(updated, edit.lines...)
+		updated = append(updated, lines[edit.end:]...)
+		lines = updated
+	}
+	return strings.Join(lines, "\n")
+}
+
+// sortedKeys returns the keys of a string map in order
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,153 @@
+package patcher
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRemediateAction_RemoveSteps(t *testing.T) {
+	content := `on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      # Cache dependencies
+      - name: Cache
+        uses: Sketchy/Cache@main
+        with:
+          path: ~/.cache
+
+      - run: make
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make test
+      - uses: sketchy/cache@main
+`
+	updated, changes, err := NewWorkflowPatcher().RemediateAction(content, ActionRemediation{Uses: "sketchy/cache@main", Remove: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+
+      - run: make
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make test
+`
+	if updated != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, updated)
+	}
+	if len(changes) != 2 || !strings.Contains(changes[0], "Job 'build', Step 2") {
+		t.Errorf("Expected 2 changes, got %v", changes)
+	}
+}
+
+func TestRemediateAction_ReplaceStep(t *testing.T) {
+	content := `on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: acme/upload@v1 # v1.2.0
+        with:
+          path: dist/ # build output
+          token: ${{ secrets.ACME_TOKEN }}
+          retries: 3
+      - uses: acme/upload@v1
+`
+	updated, changes, err := NewWorkflowPatcher().RemediateAction(content, ActionRemediation{
+		Uses:        "acme/upload@v1",
+		Replacement: "my-org/upload@v2",
+		WithMapping: map[string]string{"path": "files", "token": ""},
+		With:        map[string]string{"retries": "5", "visibility": "private"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: my-org/upload@v2
+        with:
+          files: dist/ # build output
+          retries: "5"
+          visibility: private
+      - uses: my-org/upload@v2
+        with:
+          retries: "5"
+          visibility: private
+`
+	if updated != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, updated)
+	}
+	if len(changes) == 0 || !strings.Contains(strings.Join(changes, "\n"), "Renamed input 'path' to 'files'") {
+		t.Errorf("Expected the input rename to be recorded, got %v", changes)
+	}
+}
+
+func TestRemediateAction_ReplaceDropsEmptyWith(t *testing.T) {
+	content := `on: push
+jobs:
+  scan:
+    uses: acme/workflows/.github/workflows/scan.yml@v1
+    with:
+      level: high
+    secrets: inherit
+`
+	updated, _, err := NewWorkflowPatcher().RemediateAction(content, ActionRemediation{
+		Uses:        "acme/workflows/.github/workflows/scan.yml@v1",
+		Replacement: "my-org/security/.github/workflows/scan.yml@v2",
+		WithMapping: map[string]string{"level": ""},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `on: push
+jobs:
+  scan:
+    uses: my-org/security/.github/workflows/scan.yml@v2
+    secrets: inherit
+`
+	if updated != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, updated)
+	}
+}
+
+func TestRemediateAction_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		remediation ActionRemediation
+	}{
+		{"only step", "on: push\njobs:\n  test:\n    steps:\n      - uses: a/b@v1\n", ActionRemediation{Uses: "a/b@v1", Remove: true}},
+		{"flow steps", "on: push\njobs:\n  test:\n    steps: [{uses: a/b@v1}, {run: x}]\n", ActionRemediation{Uses: "a/b@v1", Remove: true}},
+		{"flow with", "on: push\njobs:\n  test:\n    steps:\n      - uses: a/b@v1\n        with: {x: 1}\n", ActionRemediation{Uses: "a/b@v1", Replacement: "c/d@v2", WithMapping: map[string]string{"x": "y"}}},
+	}
+
+	for _, tt := range tests {
+		updated, _, err := NewWorkflowPatcher().RemediateAction(tt.content, tt.remediation)
+		if err == nil {
+			t.Errorf("%s: expected an error, got content:\n%s", tt.name, updated)
+		}
+	}
+
+	// Workflows without the action are left untouched
+	content := "on: push\njobs:\n  test:\n    steps:\n      - uses: a/b@v2\n"
+	updated, changes, err := NewWorkflowPatcher().RemediateAction(content, ActionRemediation{Uses: "a/b@v1", Remove: true})
+	if err != nil || updated != content || len(changes) != 0 {
+		t.Errorf("Expected no changes, got %v, %v:\n%s", changes, err, updated)
+	}
+}
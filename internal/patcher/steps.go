@@ -0,0 +1,212 @@
+package patcher
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+	"gopkg.in/yaml.v3"
+)
+
+// updateStepNode writes a patched step's uses: and with: back to its node, leaving other keys untouched
+func updateStepNode(node *yaml.Node, step workflow.Step, patch *Patch) error {
+	if uses := nodeValue(node, "uses"); uses != nil && uses.Value != step.Uses {
+		uses.Value = step.Uses
+	}
+	if len(patch.Additions) == 0 && len(patch.Removals) == 0 && len(patch.Renames) == 0 && len(patch.Modifications) == 0 {
+		return nil
+	}
+
+	with := nodeValue(node, "with")
+	if with == nil || with.Kind != yaml.MappingNode {
+		withMap, _ := patch.UpdatedWith.(map[string]interface{})
+		if len(withMap) == 0 {
+			removeMappingKey(node, "with")
+			return nil
+		}
+		var encoded yaml.Node
+		if err := encoded.Encode(withMap); err != nil {
+			return fmt.Errorf("failed to encode with block: %w", err)
+		}
+		setMappingValue(node, "with", &encoded)
+		return nil
+	}
+
+	// Edit the existing with block in place, so untouched inputs keep their order, quoting, and comments
+	for _, removal := range patch.Removals {
+		removeMappingKey(with, removal.Field)
+	}
+	for _, rename := range patch.Renames {
+		if key, _ := mappingEntry(with, rename.OldField); key != nil {
+			key.Value = rename.NewField
+		}
+	}
+	for _, modification := range patch.Modifications {
+		_, previous := mappingEntry(with, modification.Field)
+		value, err := scalarValueNode(modification.NewValue, previous)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", modification.Field, err)
+		}
+		setMappingValue(with, modification.Field, value)
+	}
+	for _, addition := range patch.Additions {
+		value, err := scalarValueNode(addition.Value, nil)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", addition.Field, err)
+		}
+		setMappingValue(with, addition.Field, value)
+	}
+	if len(with.Content) == 0 {
+		removeMappingKey(node, "with")
+	}
+	return nil
+}
+
+// scalarValueNode encodes a patched value, keeping its type: the string "false" stays quoted and the
+// boolean false does not. A string replacing a quoted or block string keeps that style and its comment.
+func scalarValueNode(value interface{}, previous *yaml.Node) (*yaml.Node, error) {
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return nil, err
+	}
+	if previous == nil || previous.Kind != yaml.ScalarNode || node.Kind != yaml.ScalarNode {
+		return &node, nil
+	}
+	if previous.Style != 0 && previous.ShortTag() == "!!str" && node.ShortTag() == "!!str" {
+		node.Style = previous.Style
+	}
+	node.LineComment = previous.LineComment
+	return &node, nil
+}
+
+// applyStepChange applies a step-level change to the step at index in a steps sequence. It returns the
+// index of the patched step afterwards, which moves when steps are inserted or deleted before it, and a
+// description of the change or "" when there was nothing to change.
+func applyStepChange(steps *yaml.Node, index int, change StepChange) (int, string, error) {
+	step := steps.Content[index]
+	switch change.Operation {
+	case OperationSetEnv:
+		env := nodeValue(step, "env")
+		if env == nil {
+			env = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			setMappingValue(step, "env", env)
+		} else if env.Kind != yaml.MappingNode {
+			return index, "", fmt.Errorf("env is not a mapping")
+		}
+		var value yaml.Node
+		if err := value.Encode(change.Value); err != nil {
+			return index, "", fmt.Errorf("failed to encode env %s: %w", change.Field, err)
+		}
+		setMappingValue(env, change.Field, &value)
+		return index, fmt.Sprintf("Set env '%s' = '%v'", change.Field, change.Value), nil
+
+	case OperationRemoveEnv:
+		env := nodeValue(step, "env")
+		if env == nil || !removeMappingKey(env, change.Field) {
+			return index, "", nil
+		}
+		if len(env.Content) == 0 {
+			removeMappingKey(step, "env")
+		}
+		return index, fmt.Sprintf("Removed env '%s'", change.Field), nil
+
+	case OperationSetName:
+		name := fmt.Sprint(change.Value)
+		setMappingValue(step, "name", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name})
+		return index, fmt.Sprintf("Set name to '%s'", name), nil
+
+	case OperationAddCondition:
+		condition := stripExpression(fmt.Sprint(change.Value))
+		if existing := nodeValue(step, "if"); existing != nil && strings.TrimSpace(existing.Value) != "" {
+			current := stripExpression(existing.Value)
+			if current == condition {
+				return index, "", nil
+			}
+			condition = "(" + current + ") && (" + condition + ")"
+		}
+		setMappingValue(step, "if", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: condition})
+		return index, fmt.Sprintf("Set condition '%s'", condition), nil
+
+	case OperationInsertStep:
+		var inserted yaml.Node
+		if err := inserted.Encode(change.Value); err != nil {
+			return index, "", fmt.Errorf("failed to encode inserted step: %w", err)
+		}
+		at := index + 1
+		if change.Position == PositionBefore {
+			at = index
+		}
+		steps.Content = append(steps.Content[:at], append([]*yaml.Node{&inserted}, steps.Content[at:]...)...)
+		if at == index {
+			index++
+		}
+		return index, fmt.Sprintf("Inserted step %s", stepLabel(&inserted)), nil
+
+	case OperationDeleteStep:
+		var candidates []int
+		if change.Position != PositionAfter {
+			candidates = append(candidates, index-1)
+		}
+		if change.Position != PositionBefore {
+			candidates = append(candidates, index+1)
+		}
+		for _, at := range candidates {
+			if at < 0 || at >= len(steps.Content) {
+				continue
+			}
+			uses := nodeValue(steps.Content[at], "uses")
+			if uses == nil || !strings.EqualFold(strings.SplitN(uses.Value, "@", 2)[0], change.Field) {
+				continue
+			}
+			label := stepLabel(steps.Content[at])
+			steps.Content = append(steps.Content[:at], steps.Content[at+1:]...)
+			if at < index {
+				index--
+			}
+			return index, fmt.Sprintf("Deleted adjacent step %s", label), nil
+		}
+		return index, "", nil
+	}
+	return index, "", fmt.Errorf("unknown step operation: %s", change.Operation)
+}
+
+// setMappingValue sets a key of a mapping node, appending it when absent
+func setMappingValue(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+// removeMappingKey deletes a key from a mapping node and reports whether it was present
+func removeMappingKey(node *yaml.Node, key string) bool {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// stripExpression removes the ${{ }} wrapper from an if: expression
+func stripExpression(expression string) string {
+	expression = strings.TrimSpace(expression)
+	if strings.HasPrefix(expression, "${{") && strings.HasSuffix(expression, "}}") {
+		expression = strings.TrimSpace(expression[3 : len(expression)-2])
+	}
+	return expression
+}
+
+// stepLabel describes a step node by its uses:, name: or run: for change descriptions
+func stepLabel(step *yaml.Node) string {
+	for _, key := range []string{"uses", "name", "run"} {
+		if value := nodeValue(step, key); value != nil && value.Value != "" {
+			return fmt.Sprintf("'%s'", strings.SplitN(value.Value, "\n", 2)[0])
+		}
+	}
+	return "(unnamed)"
+}
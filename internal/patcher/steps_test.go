@@ -0,0 +1,168 @@
+package patcher
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// createWorkflowPatcherWithCacheRules creates a WorkflowPatcher that moves actions/cache into setup-node
+func createWorkflowPatcherWithCacheRules() *WorkflowPatcher {
+	wp := NewWorkflowPatcher()
+	wp.patcher.AddPatchRule(ActionPatchRule{
+		Repository: "actions/setup-node",
+		VersionPatches: []VersionPatch{
+			{
+				FromVersion: "v2",
+				ToVersion:   "v4",
+				Description: "Use built-in dependency caching",
+				Patches: []FieldPatch{
+					{Operation: OperationAdd, Field: "cache", Value: "npm", Reason: "setup-node caches npm dependencies itself"},
+					{Operation: OperationDeleteStep, Field: "actions/cache", Position: PositionAfter, Reason: "Replaced by setup-node caching"},
+					{Operation: OperationRemoveEnv, Field: "NODE_CACHE", Reason: "No longer read"},
+					{Operation: OperationSetEnv, Field: "CI", Value: "true", Reason: "Non-interactive installs"},
+					{Operation: OperationSetName, Value: "Set up Node with caching", Reason: "Describe the cached setup"},
+					{Operation: OperationAddCondition, Value: "${{ !env.ACT }}", Reason: "Skip under act"},
+					{Operation: OperationInsertStep, Value: map[string]interface{}{"run": "npm ci"}, Reason: "Install from the cache"},
+				},
+			},
+		},
+	})
+	return wp
+}
+
+func TestPatchWorkflowContent_StepOperations(t *testing.T) {
+	content := `name: CI
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      # Node toolchain
+      - id: node
+        if: github.event_name == 'push'
+        uses: actions/setup-node@v2
+        with:
+          node-version: 20
+        env:
+          NODE_CACHE: "1"
+      - uses: actions/cache@v3
+        with:
+          path: ~/.npm
+      - run: npm test
+`
+	updates := []ActionVersionUpdate{{ActionRepo: "actions/setup-node", FromVersion: "v2", ToVersion: "v4"}}
+	updated, changes, err := createWorkflowPatcherWithCacheRules().PatchWorkflowContent(content, updates)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `name: CI
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      # Node toolchain
+      - id: node
+        if: (github.event_name == 'push') && (!env.ACT)
+        uses: actions/setup-node@v2
+        with:
+          node-version: 20
+          cache: npm
+        env:
+          CI: "true"
+        name: Set up Node with caching
+      - run: npm ci
+      - run: npm test
+`
+	if updated != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, updated)
+	}
+
+	for _, want := range []string{
+		"Job 'build', Step 2: Added 'cache' = 'npm'",
+		"Job 'build', Step 2: Deleted adjacent step 'actions/cache@v3'",
+		"Job 'build', Step 2: Removed env 'NODE_CACHE'",
+		"Job 'build', Step 2: Inserted step 'npm ci'",
+	} {
+		found := false
+		for _, change := range changes {
+			if strings.HasPrefix(change, want) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected a change starting with %q, got %v", want, changes)
+		}
+	}
+}
+
+func TestPatchWorkflowContent_NoMatchKeepsContent(t *testing.T) {
+	content := "on: push\njobs:\n  build:\n    steps:\n      - uses: actions/setup-node@v4 # current\n"
+	updates := []ActionVersionUpdate{{ActionRepo: "actions/setup-node", FromVersion: "v2", ToVersion: "v4"}}
+
+	updated, changes, err := createWorkflowPatcherWithCacheRules().PatchWorkflowContent(content, updates)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if updated != content || len(changes) != 0 {
+		t.Errorf("Expected content to be unchanged, got changes %v:\n%s", changes, updated)
+	}
+}
+
+func TestApplyStepChange_InsertBeforeAndDelete(t *testing.T) {
+	content := "steps:\n  - uses: actions/cache@v3\n  - uses: actions/setup-node@v2\n"
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &root); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	steps := nodeValue(root.Content[0], "steps")
+
+	index, description, err := applyStepChange(steps, 1, StepChange{Operation: OperationDeleteStep, Field: "Actions/Cache"})
+	if err != nil || index != 0 || !strings.Contains(description, "actions/cache@v3") {
+		t.Fatalf("Expected the previous step to be deleted, got index %d, %q, %v", index, description, err)
+	}
+
+	index, _, err = applyStepChange(steps, index, StepChange{Operation: OperationInsertStep, Position: PositionBefore, Value: map[string]interface{}{"run": "echo setup"}})
+	if err != nil || index != 1 || len(steps.Content) != 2 {
+		t.Fatalf("Expected a step inserted before, got index %d with %d steps, %v", index, len(steps.Content), err)
+	}
+	if label := stepLabel(steps.Content[0]); label != "'echo setup'" {
+		t.Errorf("Expected the inserted step first, got %s", label)
+	}
+}
+
+func TestAddStepChangeValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		fieldPatch  FieldPatch
+		expectError bool
+	}{
+		{"set-env", FieldPatch{Operation: OperationSetEnv, Field: "CI", Value: "true"}, false},
+		{"set-env without field", FieldPatch{Operation: OperationSetEnv, Value: "true"}, true},
+		{"remove-env without field", FieldPatch{Operation: OperationRemoveEnv}, true},
+		{"add-if", FieldPatch{Operation: OperationAddCondition, Value: "success()"}, false},
+		{"add-if without value", FieldPatch{Operation: OperationAddCondition}, true},
+		{"set-name with number", FieldPatch{Operation: OperationSetName, Value: 3}, true},
+		{"insert-step", FieldPatch{Operation: OperationInsertStep, Value: map[string]interface{}{"run": "make"}}, false},
+		{"insert-step with string", FieldPatch{Operation: OperationInsertStep, Value: "make"}, true},
+		{"delete-step", FieldPatch{Operation: OperationDeleteStep, Field: "actions/cache", Position: PositionAfter}, false},
+		{"delete-step without field", FieldPatch{Operation: OperationDeleteStep}, true},
+		{"invalid position", FieldPatch{Operation: OperationDeleteStep, Field: "actions/cache", Position: "below"}, true},
+	}
+
+	for _, tt := range tests {
+		patch := &Patch{}
+		err := NewPatcher().addStepChange(tt.fieldPatch, patch)
+		if (err != nil) != tt.expectError {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.expectError, err)
+		}
+		if err == nil && len(patch.StepChanges) != 1 {
+			t.Errorf("%s: expected the step change to be recorded, got %+v", tt.name, patch.StepChanges)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package patcher
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// versionMatches reports whether a version satisfies a version pattern of a patch rule. Patterns are an
+// exact version, a wildcard such as "v3.*", "3.x", or "*", or space- or comma-separated comparisons that
+// must all hold, such as ">=3 <4" or ">=v3.2, <v4". Wildcards and comparisons only match numeric
+// versions, so SHAs and branches need an exact pattern.
+func versionMatches(pattern, version string) bool {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == version {
+		return true
+	}
+	if pattern == "*" {
+		return true
+	}
+	if pattern == "" {
+		return false
+	}
+
+	parsed, ok := parseNumericVersion(version)
+	if !ok {
+		return false
+	}
+	if strings.ContainsAny(pattern[:1], "<>=!") {
+		return rangeMatches(pattern, parsed)
+	}
+	return wildcardMatches(pattern, parsed)
+}
+
+// isVersionPattern reports whether a version of a patch rule is a wildcard or range rather than a version
+func isVersionPattern(version string) bool {
+	version = strings.TrimSpace(version)
+	if version == "*" || strings.ContainsAny(version, "<>=! ,") {
+		return true
+	}
+	for _, segment := range strings.Split(strings.TrimPrefix(version, "v"), ".") {
+		if segment == "*" || segment == "x" || segment == "X" {
+			return true
+		}
+	}
+	return false
+}
+
+// wildcardMatches matches a version against a pattern such as "v3.*", where a wildcard segment matches
+// the rest of the version
+func wildcardMatches(pattern string, version []int) bool {
+	segments := strings.Split(strings.TrimPrefix(pattern, "v"), ".")
+	for i, segment := range segments {
+		if segment == "*" || segment == "x" || segment == "X" {
+			return true
+		}
+		number, err := strconv.Atoi(segment)
+		if err != nil || i >= len(version) || version[i] != number {
+			return false
+		}
+	}
+	return len(segments) == len(version)
+}
+
+// comparisonPattern matches one comparison of a version range, e.g. ">=3" or "< v4.1"
+var comparisonPattern = regexp.MustCompile(`^\s*(<=|>=|!=|==|<|>|=)\s*([^\s,<>=!]+)\s*,?`)
+
+// rangeMatches matches a version against comparisons such as ">=3 <4"; missing segments count as 0
+func rangeMatches(pattern string, version []int) bool {
+	for pattern != "" {
+		matches := comparisonPattern.FindStringSubmatch(pattern)
+		if matches == nil {
+			return false
+		}
+		pattern = strings.TrimSpace(pattern[len(matches[0]):])
+
+		bound, ok := parseNumericVersion(matches[2])
+		if !ok {
+			return false
+		}
+		order := compareNumericVersions(version, bound)
+		var holds bool
+		switch matches[1] {
+		case ">=":
+			holds = order >= 0
+		case ">":
+			holds = order > 0
+		case "<=":
+			holds = order <= 0
+		case "<":
+			holds = order < 0
+		case "=", "==":
+			holds = order == 0
+		case "!=":
+			holds = order != 0
+		}
+		if !holds {
+			return false
+		}
+	}
+	return true
+}
+
+// parseNumericVersion parses "v3.5.2" or "3.5" into its numeric segments, ignoring any pre-release or
+// build suffix; it reports false for SHAs, branches, and other non-numeric versions
+func parseNumericVersion(version string) ([]int, bool) {
+	version = strings.TrimPrefix(version, "v")
+	if end := strings.IndexAny(version, "-+"); end >= 0 {
+		version = version[:end]
+	}
+	if version == "" {
+		return nil, false
+	}
+
+	var segments []int
+	for _, segment := range strings.Split(version, ".") {
+		number, err := strconv.Atoi(segment)
+		if err != nil || number < 0 {
+			return nil, false
+		}
+		segments = append(segments, number)
+	}
+	return segments, true
+}
+
+// compareNumericVersions compares two parsed versions, returning -1, 0, or 1
+func compareNumericVersions(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// findVersionPatch returns the version patch applying to a version transition, preferring patches with
+// exact versions over wildcards and ranges; accept narrows the candidates further
+func findVersionPatch(patches []VersionPatch, fromVersion, toVersion string, accept func(VersionPatch) bool) *VersionPatch {
+	for i := range patches {
+		if patches[i].FromVersion == fromVersion && patches[i].ToVersion == toVersion && accept(patches[i]) {
+			return &patches[i]
+		}
+	}
+	for i := range patches {
+		vp := patches[i]
+		if !isVersionPattern(vp.FromVersion) && !isVersionPattern(vp.ToVersion) {
+			continue
+		}
+		if versionMatches(vp.FromVersion, fromVersion) && versionMatches(vp.ToVersion, toVersion) && accept(vp) {
+			return &patches[i]
+		}
+	}
+	return nil
+}
+
+// locationMatches reports whether a version patch applies to a repository transition: patches naming both
+// repositories need them to match, and other patches only apply within one repository
+func locationMatches(vp VersionPatch, fromRepository, toRepository string) bool {
+	if vp.FromRepository != "" && vp.ToRepository != "" {
+		return vp.FromRepository == fromRepository && vp.ToRepository == toRepository
+	}
+	return fromRepository == toRepository
+}
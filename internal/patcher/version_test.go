@@ -0,0 +1,92 @@
+package patcher
+
+import "testing"
+
+func TestVersionMatches(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		version  string
+		expected bool
+	}{
+		{"v3", "v3", true},
+		{"v3", "v3.5.2", false},
+		{"main", "main", true},
+		{"v3.*", "v3", true},
+		{"v3.*", "v3.5.2", true},
+		{"v3.*", "v4.0.0", false},
+		{"3.x", "v3.1", true},
+		{"v3.5.*", "v3.4.9", false},
+		{"*", "a1b2c3d", true},
+		{">=3 <4", "v3.5.2", true},
+		{">=3 <4", "v4", false},
+		{">=3 <4", "v2.9", false},
+		{">=v3.2, <v4", "v3.2.0", true},
+		{">= 3.2 < 4", "v3.1", false},
+		{">2", "v2.0.1", true},
+		{"!=v3.1", "v3.1.0", false},
+		{">=3 <4", "main", false},
+		{">=3 <4", "a1b2c3d4e5f6", false},
+		{">=3 oops", "v3", false},
+		{"", "v3", false},
+	}
+
+	for _, tt := range tests {
+		if got := versionMatches(tt.pattern, tt.version); got != tt.expected {
+			t.Errorf("versionMatches(%q, %q): expected %v, got %v", tt.pattern, tt.version, tt.expected, got)
+		}
+	}
+}
+
+func TestBuildPatch_VersionPatterns(t *testing.T) {
+	patcher := NewPatcher()
+	patcher.AddPatchRule(ActionPatchRule{
+		Repository: "actions/checkout",
+		VersionPatches: []VersionPatch{
+			{
+				FromVersion: ">=3 <4",
+				ToVersion:   "v4.*",
+				Description: "v3 to v4",
+				Patches:     []FieldPatch{{Operation: OperationAdd, Field: "show-progress", Value: true, Reason: "New in v4"}},
+			},
+			{
+				FromVersion: "v3.6.0",
+				ToVersion:   "v4",
+				Description: "Exact v3.6.0 to v4",
+				Patches:     []FieldPatch{{Operation: OperationRemove, Field: "ssh-strict", Reason: "Exact rule"}},
+			},
+		},
+	})
+
+	patch, err := patcher.BuildPatch("actions/checkout", "v3.5.2", "v4.1.1", map[string]interface{}{"ref": "main"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !patch.Applied || patch.Description != "v3 to v4" || len(patch.Additions) != 1 {
+		t.Errorf("Expected the range rule to apply to v3.5.2 -> v4.1.1, got %+v", patch)
+	}
+	if patch.FromVersion != "v3.5.2" || patch.ToVersion != "v4.1.1" {
+		t.Errorf("Expected the patch to record the actual versions, got %s -> %s", patch.FromVersion, patch.ToVersion)
+	}
+
+	// An exact rule wins over a matching range
+	patch, err = patcher.BuildPatch("actions/checkout", "v3.6.0", "v4", map[string]interface{}{"ssh-strict": true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if patch.Description != "Exact v3.6.0 to v4" {
+		t.Errorf("Expected the exact rule to win, got %q", patch.Description)
+	}
+
+	if !patcher.HasPatch("actions/checkout", "v3.1", "v4.0.0") {
+		t.Error("Expected HasPatch to match the range rule")
+	}
+	if patcher.HasPatch("actions/checkout", "v2", "v4") {
+		t.Error("Expected no patch for v2")
+	}
+
+	wp := NewWorkflowPatcher()
+	wp.AddPatchRule(patcher.rules["actions/checkout"])
+	if info, ok := wp.GetPatchInfo("actions/checkout", "v3.1", "v4"); !ok || info.Description != "v3 to v4" {
+		t.Errorf("Expected patch info from the range rule, got %+v", info)
+	}
+}
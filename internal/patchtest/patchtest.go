@@ -0,0 +1,243 @@
+package patchtest
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/patcher"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/pr"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// ExpectedSuffix marks the expected output of a sample workflow: ci.yml is checked against ci.expected.yml
+const ExpectedSuffix = ".expected"
+
+// Config holds configuration options for the test runner
+type Config struct {
+	Verbose bool
+	Update  bool // Write the actual output as the expected output instead of comparing
+
+	// InputTypes checks patched inputs against action metadata; warnings are listed with the changes
+	InputTypes patcher.InputTypeSource
+}
+
+// Case is a sample workflow and the file holding its expected output
+type Case struct {
+	Name         string // Sample file name relative to the cases directory
+	InputPath    string
+	ExpectedPath string
+}
+
+// Result describes the outcome of one case
+type Result struct {
+	Case    Case
+	Passed  bool
+	Updated bool     // The expected output was (re)written from the actual output
+	Changes []string // Changes made by the patcher and version updates
+	Diff    string   // Differences between the expected and actual output when the case failed
+	Err     error    // Set when the case could not be run
+}
+
+// Runner applies patch rules to sample workflows and compares the results with expected outputs
+type Runner struct {
+	patcher *patcher.WorkflowPatcher
+	verbose bool
+	update  bool
+}
+
+// NewRunner creates a new runner for a set of patch rules
+func NewRunner(rules []patcher.ActionPatchRule) *Runner {
+	return NewRunnerWithConfig(rules, &Config{Verbose: false})
+}
+
+// NewRunnerWithConfig creates a new runner for a set of patch rules with configuration
+func NewRunnerWithConfig(rules []patcher.ActionPatchRule, config *Config) *Runner {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	wp := patcher.NewWorkflowPatcher()
+	for _, rule := range rules {
+		wp.AddPatchRule(rule)
+	}
+	if config.InputTypes != nil {
+		wp.SetInputTypes(config.InputTypes)
+	}
+
+	return &Runner{
+		patcher: wp,
+		verbose: config.Verbose,
+		update:  config.Update,
+	}
+}
+
+// DiscoverCases finds the sample workflows in a directory and its subdirectories, sorted by name
+// Every .yml or .yaml file is a sample unless it is an expected output itself.
+func DiscoverCases(dir string) ([]Case, error) {
+	var cases []Case
+	err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := filepath.Ext(path)
+		if entry.IsDir() || (ext != ".yml" && ext != ".yaml") {
+			return nil
+		}
+		base := strings.TrimSuffix(path, ext)
+		if strings.HasSuffix(base, ExpectedSuffix) {
+			return nil
+		}
+
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		cases = append(cases, Case{Name: name, InputPath: path, ExpectedPath: base + ExpectedSuffix + ext})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find test cases in %s: %w", dir, err)
+	}
+
+	sort.Slice(cases, func(i, j int) bool { return cases[i].Name < cases[j].Name })
+	return cases, nil
+}
+
+// Run runs every case and returns one result per case, in order
+func (r *Runner) Run(cases []Case) []Result {
+	results := make([]Result, 0, len(cases))
+	for _, c := range cases {
+		results = append(results, r.RunCase(c))
+	}
+	return results
+}
+
+// RunCase upgrades every action in a sample workflow that a patch rule covers, as create-pr would, and
+// compares the output with the expected file. An action is upgraded to the exact to_version of the first
+// version patch matching its version.
+func (r *Runner) RunCase(c Case) Result {
+	result := Result{Case: c}
+
+	input, err := os.ReadFile(c.InputPath)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to read sample: %w", err)
+		return result
+	}
+
+	updates, err := r.plannedUpdates(string(input), c.Name)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	if r.verbose {
+		log.Printf("Patch test %s: applying %d updates", c.Name, len(updates))
+	}
+
+	actual, changes, err := pr.PatchWorkflowContent(r.patcher, string(input), updates)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to patch sample: %w", err)
+		return result
+	}
+	result.Changes = changes
+
+	if r.update {
+		if err := os.WriteFile(c.ExpectedPath, []byte(actual), 0644); err != nil {
+			result.Err = fmt.Errorf("failed to write expected output: %w", err)
+			return result
+		}
+		result.Passed = true
+		result.Updated = true
+		return result
+	}
+
+	expected, err := os.ReadFile(c.ExpectedPath)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to read expected output: %w", err)
+		return result
+	}
+
+	result.Passed = string(expected) == actual
+	if !result.Passed {
+		result.Diff = Diff(string(expected), actual)
+	}
+	return result
+}
+
+// plannedUpdates picks an upgrade for each action in the sample that a version patch applies to
+func (r *Runner) plannedUpdates(content, name string) ([]pr.ActionUpdate, error) {
+	refs, err := workflow.ParseWorkflow(content, name, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sample: %w", err)
+	}
+
+	var updates []pr.ActionUpdate
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		key := ref.Repository + "/" + ref.WorkflowPath + "@" + ref.Version
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		vp, ok := r.patcher.UpgradeTarget(ref.Repository, ref.Version)
+		if !ok {
+			continue
+		}
+		update := pr.ActionUpdate{
+			FilePath:       name,
+			ActionRepo:     ref.Repository,
+			WorkflowPath:   ref.WorkflowPath,
+			CurrentVersion: ref.Version,
+			TargetVersion:  vp.ToVersion,
+		}
+		if vp.ToRepository != "" && vp.ToRepository != ref.Repository {
+			update.TargetRepo = vp.ToRepository
+		}
+		updates = append(updates, update)
+	}
+	return updates, nil
+}
+
+// Diff returns a line diff turning expected into actual: removed lines start with "- ", added lines with
+// "+ ", and unchanged lines with two spaces
+func Diff(expected, actual string) string {
+	a := strings.Split(expected, "\n")
+	b := strings.Split(actual, "\n")
+
+	// Longest common subsequence lengths of the suffixes
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			diff.WriteString("  " + a[i] + "\n")
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			diff.WriteString("- " + a[i] + "\n")
+			i++
+		default:
+			diff.WriteString("+ " + b[j] + "\n")
+			j++
+		}
+	}
+	return diff.String()
+}
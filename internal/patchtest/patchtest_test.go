@@ -0,0 +1,130 @@
+package patchtest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/patcher"
+)
+
+var checkoutRules = []patcher.ActionPatchRule{{
+	Repository: "actions/checkout",
+	VersionPatches: []patcher.VersionPatch{{
+		FromVersion: ">=1 <4",
+		ToVersion:   "v4",
+		Patches:     []patcher.FieldPatch{{Operation: patcher.OperationRemove, Field: "token", Reason: "Uses GITHUB_TOKEN"}},
+	}},
+}}
+
+const sample = `on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v2
+        with:
+          token: ${{ secrets.PAT }}
+          fetch-depth: 0
+`
+
+const expected = `on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+        with:
+          fetch-depth: 0
+`
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func TestDiscoverCases(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "ci.yml"), sample)
+	writeFile(t, filepath.Join(dir, "ci.expected.yml"), expected)
+	writeFile(t, filepath.Join(dir, "nested", "release.yaml"), sample)
+	writeFile(t, filepath.Join(dir, "README.md"), "samples")
+
+	cases, err := DiscoverCases(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(cases) != 2 {
+		t.Fatalf("Expected 2 cases, got %+v", cases)
+	}
+	if cases[0].Name != "ci.yml" || cases[0].ExpectedPath != filepath.Join(dir, "ci.expected.yml") {
+		t.Errorf("Expected ci.yml checked against ci.expected.yml, got %+v", cases[0])
+	}
+	if cases[1].Name != filepath.Join("nested", "release.yaml") || cases[1].ExpectedPath != filepath.Join(dir, "nested", "release.expected.yaml") {
+		t.Errorf("Expected the nested sample with a .yaml expected output, got %+v", cases[1])
+	}
+}
+
+func TestRunCase(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "pass.yml"), sample)
+	writeFile(t, filepath.Join(dir, "pass.expected.yml"), expected)
+	writeFile(t, filepath.Join(dir, "fail.yml"), sample)
+	writeFile(t, filepath.Join(dir, "fail.expected.yml"), strings.Replace(expected, "fetch-depth: 0", "fetch-depth: 1", 1))
+	writeFile(t, filepath.Join(dir, "missing.yml"), sample)
+
+	cases, err := DiscoverCases(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	results := NewRunner(checkoutRules).Run(cases)
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+
+	fail, missing, pass := results[0], results[1], results[2]
+	if !pass.Passed || pass.Err != nil || len(pass.Changes) == 0 {
+		t.Errorf("Expected pass.yml to pass with changes, got %+v", pass)
+	}
+	if fail.Passed || !strings.Contains(fail.Diff, "-           fetch-depth: 1") || !strings.Contains(fail.Diff, "+           fetch-depth: 0") {
+		t.Errorf("Expected fail.yml to fail with a diff, got %+v", fail)
+	}
+	if missing.Err == nil || missing.Passed {
+		t.Errorf("Expected missing.yml to report its missing expected output, got %+v", missing)
+	}
+}
+
+func TestRunCase_Update(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "ci.yml"), sample)
+
+	cases, err := DiscoverCases(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result := NewRunnerWithConfig(checkoutRules, &Config{Update: true}).RunCase(cases[0])
+	if result.Err != nil || !result.Updated {
+		t.Fatalf("Expected the expected output to be written, got %+v", result)
+	}
+
+	written, err := os.ReadFile(filepath.Join(dir, "ci.expected.yml"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(written) != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, written)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	diff := Diff("a\nb\nc", "a\nx\nc")
+	if diff != "  a\n- b\n+ x\n  c\n" {
+		t.Errorf("Unexpected diff:\n%s", diff)
+	}
+}
@@ -0,0 +1,384 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// Stage names in execution order
+const (
+	StageScan     = "scan"
+	StageReport   = "report"
+	StageCreatePR = "create-pr"
+)
+
+// Stages lists all pipeline stages in execution order
+var Stages = []string{StageScan, StageReport, StageCreatePR}
+
+// Config describes a scan → report → create-pr pipeline loaded from a JSON file
+//
+// Tokens are never read from the file; they come from --token or the environment
+// variable named by TokenEnv (default GITHUB_TOKEN).
+type Config struct {
+	Owner    string       `json:"owner"`               // GitHub user or organization to scan
+	Filter   string       `json:"filter,omitempty"`    // Optional repository name regex applied to every stage
+	Verbose  bool         `json:"verbose,omitempty"`   // Enable verbose logging in every stage
+	TokenEnv string       `json:"token_env,omitempty"` // Environment variable holding the GitHub token
+	APIURL   string       `json:"api_url,omitempty"`   // GitHub Enterprise Server API URL (empty = github.com)
+	Hosts    []HostConfig `json:"hosts,omitempty"`     // Several GitHub hosts to scan in one run, instead of owner and api_url
+	Links    string       `json:"links,omitempty"`     // URL mappings file for the link function of report and pull request templates
+
+	Network    NetworkConfig    `json:"network"`
+	Encryption EncryptionConfig `json:"encryption"`
+	Scan       ScanConfig       `json:"scan"`
+	Report     ReportConfig     `json:"report"`
+	CreatePR   CreatePRConfig   `json:"create_pr"`
+}
+
+// HostConfig is a GitHub host scanned in a multi-host run, such as github.com and a GitHub Enterprise
+// Server, with the owners to scan on it and its own token
+type HostConfig struct {
+	Name     string   `json:"name,omitempty"`      // Recorded as each repository's host; defaults to the API URL's host name or github.com
+	APIURL   string   `json:"api_url,omitempty"`   // GitHub Enterprise Server API URL (empty = github.com)
+	TokenEnv string   `json:"token_env,omitempty"` // Environment variable holding the host's token (default: the pipeline's token)
+	Owners   []string `json:"owners"`              // Users or organizations to scan on the host
+}
+
+// HostName returns the name recorded for the host's repositories
+func (h HostConfig) HostName() string {
+	if h.Name != "" {
+		return h.Name
+	}
+	return github.APIHost(h.APIURL)
+}
+
+// NetworkConfig holds proxy and TLS settings shared by the scan and create-pr stages
+type NetworkConfig struct {
+	Proxy              string `json:"proxy,omitempty"`
+	CABundle           string `json:"ca_bundle,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+	Timeout            string `json:"timeout,omitempty"`           // Duration such as "30s" or "2m"
+	UserAgentSuffix    string `json:"user_agent_suffix,omitempty"` // Appended to the User-Agent of every GitHub API request
+}
+
+// EncryptionConfig encrypts the scan results and report with age and decrypts them for later stages
+type EncryptionConfig struct {
+	Recipient string `json:"recipient,omitempty"` // Comma-separated age recipients or recipients files
+	Identity  string `json:"identity,omitempty"`  // age identity file for reading the results back
+}
+
+// ScanConfig configures the scan stage (enabled unless set to false)
+type ScanConfig struct {
+	Enabled                 *bool        `json:"enabled,omitempty"`
+	Output                  string       `json:"output,omitempty"` // JSON results file; read as input when the scan stage is disabled
+	RulesFile               string       `json:"rules_file,omitempty"`
+	SuppressionsFile        string       `json:"suppressions_file,omitempty"`
+	WorkflowDirs            []string     `json:"workflow_dirs,omitempty"`
+	WorkflowFilter          []string     `json:"workflow_filter,omitempty"`   // Globs of workflow files to scan
+	ExcludeWorkflows        []string     `json:"exclude_workflows,omitempty"` // Globs of workflow files to skip
+	CustomProperty          string       `json:"custom_property,omitempty"`
+	SkipResolution          bool         `json:"skip_resolution,omitempty"`
+	MaxTagPages             int          `json:"max_tag_pages,omitempty"`           // Pages of 100 tags listed per action; 0 lists every page
+	ComprehensiveCache      []string     `json:"comprehensive_cache,omitempty"`     // Action repositories whose every tag is listed, e.g. ["my-org/*"]
+	ResolveLatest           bool         `json:"resolve_latest,omitempty"`          // Look up rules' latest versions from their actions
+	SkipWorkflowTemplates   bool         `json:"skip_workflow_templates,omitempty"` // Leave the organization's workflow templates out
+	PinAge                  bool         `json:"pin_age,omitempty"`
+	PatchPreview            bool         `json:"patch_preview,omitempty"` // Embed concrete patches in transformed issues
+	DetectDuplicates        bool         `json:"detect_duplicates,omitempty"`
+	CheckDeprecationNotices bool         `json:"check_deprecation_notices,omitempty"` // Look for deprecation notices in action repositories
+	MaxWorkflowSize         int          `json:"max_workflow_size,omitempty"`
+	Baseline                string       `json:"baseline,omitempty"`              // Previous scan results; matching issues are marked existing
+	TeamProperty            string       `json:"team_property,omitempty"`         // Custom property naming each repository's team, for time-to-merge per team
+	FailOn                  string       `json:"fail_on,omitempty"`               // Minimum severity of new issues that fails the run
+	DebtTargets             string       `json:"debt_targets,omitempty"`          // Severity weights and dated targets of the actions debt score
+	FailOnDebt              bool         `json:"fail_on_debt,omitempty"`          // Fail the run when the debt score is above the current target
+	SummaryFile             string       `json:"summary_file,omitempty"`          // JSON summary of issue counts, gate outcome, and exit code
+	MaxDuration             string       `json:"max_duration,omitempty"`          // Stop scanning new repositories after this long, e.g. "30m"
+	MaxAPICalls             int          `json:"max_api_calls,omitempty"`         // Stop scanning new repositories after this many API calls
+	MaxFailures             string       `json:"max_failures,omitempty"`          // Abort once more repositories failed to fetch or parse, e.g. "5" or "2%"
+	OrderBy                 string       `json:"order_by,omitempty"`              // Scan order: "pushed", "issues", or "property:<name>[=<values>]"
+	MaxRepos                int          `json:"max_repos,omitempty"`             // Scan only this many repositories, for trial runs
+	Sample                  bool         `json:"sample,omitempty"`                // Scan a random sample of max_repos repositories
+	ReposSnapshot           string       `json:"repos_snapshot,omitempty"`        // Repository list reused between scans
+	ReposSnapshotTTL        string       `json:"repos_snapshot_ttl,omitempty"`    // Age after which the snapshot is refreshed, e.g. "6h"
+	SkipListingCheck        bool         `json:"skip_listing_check,omitempty"`    // Scan a repository listing shorter than the owner's reported totals
+	TokenRepos              bool         `json:"token_repos,omitempty"`           // Scan only the repositories the token can access
+	PriorityWeights         string       `json:"priority_weights,omitempty"`      // Weights file for issue priority scores
+	DocsBaseURL             string       `json:"docs_base_url,omitempty"`         // Page documenting each rule
+	DescriptionTemplates    string       `json:"description_templates,omitempty"` // Templates rewriting issue descriptions
+	WorkflowGoldenSet       string       `json:"workflow_golden_set,omitempty"`   // Approved workflows of each class of repositories
+	SeverityPolicy          string       `json:"severity_policy,omitempty"`       // Severity downgrades and excluded checks for non-production repositories
+	WriteLocks              string       `json:"write_locks,omitempty"`           // Directory to write per-repository lockfiles to
+	VerifyLocks             string       `json:"verify_locks,omitempty"`          // Directory of lockfiles to verify actions against
+	CaptureLogs             bool         `json:"capture_logs,omitempty"`
+	ExplainAll              bool         `json:"explain_all,omitempty"`
+	RegistryURL             string       `json:"registry_url,omitempty"`            // Token comes from ACTIONS_REGISTRY_TOKEN
+	RegistryMapping         string       `json:"registry_mapping,omitempty"`        // Field mapping file for the registry
+	HookCommand             string       `json:"hook_command,omitempty"`            // Run per issue with the issue JSON on stdin
+	HookURL                 string       `json:"hook_url,omitempty"`                // Receives each issue as JSON
+	WorkflowUsageDays       int          `json:"workflow_usage_days,omitempty"`     // Run history window for stale-workflow detection
+	EstimateMinutesDays     int          `json:"estimate_minutes_days,omitempty"`   // Run history window for Actions minutes estimates
+	CheckImagesDays         int          `json:"check_images_days,omitempty"`       // Maximum image age for registry checks of container images
+	MapSecrets              bool         `json:"map_secrets,omitempty"`             // Map secrets and variables passed to actions
+	CheckTagProtection      int          `json:"check_tag_protection,omitempty"`    // Minimum consumers of an internal action whose tags are checked
+	CheckMajorTags          bool         `json:"check_major_tags,omitempty"`        // Check internal actions' major tags point at their newest release
+	CheckCalls              bool         `json:"check_calls,omitempty"`             // Check reusable workflow calls pass the secrets the workflows require
+	CheckDispatchDefaults   bool         `json:"check_dispatch_defaults,omitempty"` // Check workflow_dispatch input defaults against the built-in policy
+	DispatchPolicy          string       `json:"dispatch_policy,omitempty"`         // Policy file of risky workflow_dispatch defaults
+	InternalActions         int          `json:"internal_actions,omitempty"`        // Minimum consumers of an internal action reported to its owners
+	GitHubAnnotations       bool         `json:"github_annotations,omitempty"`      // Annotate issues and write a job summary in GitHub Actions
+	Checks                  ChecksConfig `json:"checks,omitempty"`                  // Workflow hygiene checks, all disabled by default
+	ActionChecks            []string     `json:"action_checks,omitempty"`           // Action checks to run, e.g. ["outdated", "deprecated"]; empty runs all
+	DisableDefaultRules     []string     `json:"disable_default_rules,omitempty"`   // Action repositories whose built-in rules are turned off, e.g. ["actions/cache"]
+	ReleaseChecks           []string     `json:"release_checks,omitempty"`          // Release pipeline checks to run, e.g. ["release-pat"]; empty runs none
+}
+
+// ChecksConfig toggles the workflow hygiene checks of the scan stage
+type ChecksConfig struct {
+	MissingTimeout          bool `json:"missing_timeout,omitempty"`            // Jobs without timeout-minutes
+	ContinueOnError         bool `json:"continue_on_error,omitempty"`          // Jobs with continue-on-error: true
+	FailFastDisabled        bool `json:"fail_fast_disabled,omitempty"`         // Every job sets strategy.fail-fast: false
+	MissingConcurrency      bool `json:"missing_concurrency,omitempty"`        // Deploy workflows without a concurrency group
+	MissingCancelInProgress bool `json:"missing_cancel_in_progress,omitempty"` // Pull request workflows without cancel-in-progress
+	CacheKey                bool `json:"cache_key,omitempty"`                  // actions/cache key anti-patterns
+	JobGraph                bool `json:"job_graph,omitempty"`                  // Broken needs graphs and outputs of missing jobs
+}
+
+// Enabled returns the names of the enabled checks, as accepted by scan --hygiene-checks
+func (c ChecksConfig) Enabled() []string {
+	var checks []string
+	if c.MissingTimeout {
+		checks = append(checks, "missing-timeout")
+	}
+	if c.ContinueOnError {
+		checks = append(checks, "continue-on-error")
+	}
+	if c.FailFastDisabled {
+		checks = append(checks, "fail-fast-disabled")
+	}
+	if c.MissingConcurrency {
+		checks = append(checks, "missing-concurrency")
+	}
+	if c.MissingCancelInProgress {
+		checks = append(checks, "missing-cancel-in-progress")
+	}
+	if c.CacheKey {
+		checks = append(checks, "cache-key")
+	}
+	if c.JobGraph {
+		checks = append(checks, "job-graph")
+	}
+	return checks
+}
+
+// ReportConfig configures the report stage (enabled unless set to false)
+type ReportConfig struct {
+	Enabled       *bool  `json:"enabled,omitempty"`
+	Output        string `json:"output,omitempty"` // Comma-separated .json, .ipynb, or .sarif report files (default: summary table on stdout)
+	TemplateDir   string `json:"template_dir,omitempty"`
+	GroupIssues   bool   `json:"group_issues,omitempty"`   // Merge identical issues across files in the report
+	Redact        bool   `json:"redact,omitempty"`         // Hash repository names and strip paths and property values
+	EmitApprovals string `json:"emit_approvals,omitempty"` // Approvals file listing the planned updates for review
+}
+
+// CreatePRConfig configures the create-pr stage (disabled unless set to true)
+type CreatePRConfig struct {
+	Enabled            *bool  `json:"enabled,omitempty"`
+	Template           string `json:"template,omitempty"`
+	IncludeExisting    bool   `json:"include_existing,omitempty"`     // Also update issues present in the scan baseline
+	HookCommand        string `json:"hook_command,omitempty"`         // Run per repository plan; non-zero exit skips it
+	HookURL            string `json:"hook_url,omitempty"`             // Receives each plan; non-2xx skips it
+	SkipStaleWorkflows bool   `json:"skip_stale_workflows,omitempty"` // Leave workflows without recent runs alone
+	AllowStale         bool   `json:"allow_stale,omitempty"`          // Update workflows changed since the scan
+	BaseBranches       string `json:"base_branches,omitempty"`        // Rules file choosing base branches per repository
+	Canary             string `json:"canary,omitempty"`               // Open pull requests for N or N% of repositories first
+	CanaryProperty     string `json:"canary_property,omitempty"`      // "name=value" custom property preferred for the cohort
+	CanaryState        string `json:"canary_state,omitempty"`         // File recording the canary cohort
+	Promote            bool   `json:"promote,omitempty"`              // Open the remaining pull requests once the cohort merged
+	Wave               int    `json:"wave,omitempty"`                 // Only open pull requests for this wave of the wave plan
+	WavePlan           string `json:"wave_plan,omitempty"`            // Wave plan written by the waves command
+	AuditLog           string `json:"audit_log,omitempty"`            // File or URL recording every branch, file, and pull request written
+	Approvals          string `json:"approvals,omitempty"`            // Only make the updates approved in this file
+	NoCodeOwners       bool   `json:"no_codeowners,omitempty"`        // Do not request reviews from CODEOWNERS
+	MaxReviewers       int    `json:"max_reviewers,omitempty"`        // Most reviewers to request per pull request
+	CoexistMode        string `json:"coexist_mode,omitempty"`         // skip, supersede, or ignore open Dependabot and Renovate pull requests
+	Ledger             string `json:"ledger,omitempty"`               // File recording the pull requests created per plan, so reruns skip them
+	Changelog          string `json:"changelog,omitempty"`            // Markdown file listing every pull request and change of the run
+	CommitMessage      string `json:"commit_message,omitempty"`       // Commit message preset or template for the updates
+	CommitLayout       string `json:"commit_layout,omitempty"`        // single, per-action, or per-file commits on each branch
+	ReviewMode         bool   `json:"review_mode,omitempty"`          // Suggest the updates in a pull request review instead of pushing them
+	AppID              string `json:"app_id,omitempty"`               // Authenticate as this GitHub App with per-repository installation tokens
+	AppPrivateKey      string `json:"app_private_key,omitempty"`      // PEM private key file of the GitHub App
+	DispatchCheck      bool   `json:"dispatch_check,omitempty"`       // Run workflows patched with schema transformations on the pull request branches
+	DispatchTimeout    string `json:"dispatch_timeout,omitempty"`     // Longest to wait for the dispatched runs, e.g. "30m"
+}
+
+// LoadFile loads a pipeline configuration from a JSON file
+func LoadFile(filename string) (*Config, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open pipeline config: %w", err)
+	}
+	defer file.Close()
+
+	return Load(file)
+}
+
+// Save writes the pipeline configuration to a JSON file
+func (c *Config) Save(filename string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode pipeline config: %w", err)
+	}
+
+	if err := os.WriteFile(filename, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("unable to write pipeline config: %w", err)
+	}
+
+	return nil
+}
+
+// Load parses and validates a pipeline configuration from JSON
+func Load(reader io.Reader) (*Config, error) {
+	var config Config
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("unable to parse pipeline config as JSON: %w", err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// Enabled reports whether a stage will run
+// Scan and report run unless disabled; create-pr must be enabled explicitly since it writes to GitHub.
+func (c *Config) Enabled(stage string) bool {
+	switch stage {
+	case StageScan:
+		return c.Scan.Enabled == nil || *c.Scan.Enabled
+	case StageReport:
+		return c.Report.Enabled == nil || *c.Report.Enabled
+	case StageCreatePR:
+		return c.CreatePR.Enabled != nil && *c.CreatePR.Enabled
+	default:
+		return false
+	}
+}
+
+// SetStages enables exactly the listed stages, overriding the configuration file
+func (c *Config) SetStages(stages []string) error {
+	selected := make(map[string]bool)
+	for _, stage := range stages {
+		stage = strings.TrimSpace(stage)
+		if stage == "" {
+			continue
+		}
+		if !isStage(stage) {
+			return fmt.Errorf("unknown stage %q (valid stages: %s)", stage, strings.Join(Stages, ", "))
+		}
+		selected[stage] = true
+	}
+	if len(selected) == 0 {
+		return fmt.Errorf("at least one stage must be selected")
+	}
+
+	scan, report, createPR := selected[StageScan], selected[StageReport], selected[StageCreatePR]
+	c.Scan.Enabled = &scan
+	c.Report.Enabled = &report
+	c.CreatePR.Enabled = &createPR
+
+	return c.Validate()
+}
+
+// Validate checks that the enabled stages have the inputs they need
+func (c *Config) Validate() error {
+	if !c.Enabled(StageScan) && !c.Enabled(StageReport) && !c.Enabled(StageCreatePR) {
+		return fmt.Errorf("pipeline config enables no stages")
+	}
+
+	if len(c.Hosts) > 0 {
+		if err := c.validateHosts(); err != nil {
+			return err
+		}
+	} else if c.Enabled(StageScan) && c.Owner == "" {
+		return fmt.Errorf("pipeline config: owner is required when the scan stage is enabled")
+	}
+	if c.APIURL != "" {
+		if _, err := github.ParseAPIURL(c.APIURL); err != nil {
+			return fmt.Errorf("pipeline config: api_url: %w", err)
+		}
+	}
+
+	// Later stages read the scan results file when the scan itself is skipped
+	if !c.Enabled(StageScan) && c.Scan.Output == "" {
+		return fmt.Errorf("pipeline config: scan.output must name an existing results file when the scan stage is disabled")
+	}
+
+	if output.IsNotebookFile(c.Scan.Output) || output.IsSARIFFile(c.Scan.Output) || strings.Contains(c.Scan.Output, ",") {
+		return fmt.Errorf("pipeline config: scan.output must be a single JSON file; use report.output for notebooks, SARIF, or several files")
+	}
+
+	if c.Scan.MaxWorkflowSize < 0 {
+		return fmt.Errorf("pipeline config: scan.max_workflow_size must be positive")
+	}
+
+	// Encrypted results can only be read back by later stages with an identity
+	if c.Encryption.Recipient != "" && c.Encryption.Identity == "" && (c.Enabled(StageReport) || c.Enabled(StageCreatePR)) {
+		return fmt.Errorf("pipeline config: encryption.identity is required to read encrypted results in later stages")
+	}
+
+	if c.Scan.FailOn != "" && !output.IsValidSeverity(c.Scan.FailOn) {
+		return fmt.Errorf("pipeline config: scan.fail_on must be one of low, medium, high, critical")
+	}
+
+	return nil
+}
+
+// validateHosts checks that every host has owners, a valid API URL, and a name no other host uses
+func (c *Config) validateHosts() error {
+	if c.Owner != "" || c.APIURL != "" {
+		return fmt.Errorf("pipeline config: set owners and api_url within hosts, not at the top level")
+	}
+	// Each host's scan results are read back to be merged
+	if c.Encryption.Recipient != "" && c.Encryption.Identity == "" {
+		return fmt.Errorf("pipeline config: encryption.identity is required to merge the encrypted results of several hosts")
+	}
+
+	names := make(map[string]bool)
+	for _, host := range c.Hosts {
+		name := host.HostName()
+		if names[strings.ToLower(name)] {
+			return fmt.Errorf("pipeline config: host %s is listed more than once", name)
+		}
+		names[strings.ToLower(name)] = true
+		if len(host.Owners) == 0 {
+			return fmt.Errorf("pipeline config: host %s has no owners", name)
+		}
+		if host.APIURL != "" {
+			if _, err := github.ParseAPIURL(host.APIURL); err != nil {
+				return fmt.Errorf("pipeline config: host %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// isStage reports whether name is a known stage
+func isStage(name string) bool {
+	for _, stage := range Stages {
+		if stage == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package pipeline
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoad_DefaultStages(t *testing.T) {
+	config, err := Load(strings.NewReader(`{"owner": "my-org", "report": {"output": "report.ipynb"}}`))
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+
+	if !config.Enabled(StageScan) || !config.Enabled(StageReport) {
+		t.Errorf("Expected scan and report to be enabled by default")
+	}
+	if config.Enabled(StageCreatePR) {
+		t.Errorf("Expected create-pr to require explicit enabling")
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown field":          `{"owner": "my-org", "scna": {}}`,
+		"missing owner":          `{"report": {"output": "report.ipynb"}}`,
+		"no stages":              `{"owner": "my-org", "scan": {"enabled": false, "output": "scan.json"}, "report": {"enabled": false}}`,
+		"skipped scan no input":  `{"scan": {"enabled": false}, "create_pr": {"enabled": true}}`,
+		"notebook scan output":   `{"owner": "my-org", "scan": {"output": "scan.ipynb"}}`,
+		"negative workflow size": `{"owner": "my-org", "scan": {"max_workflow_size": -1}}`,
+		"encryption no identity": `{"owner": "my-org", "encryption": {"recipient": "age1abc"}}`,
+		"invalid api url":        `{"owner": "my-org", "api_url": "ghe.example.com"}`,
+		"owner and hosts":        `{"owner": "my-org", "hosts": [{"owners": ["my-org"]}]}`,
+		"host without owners":    `{"hosts": [{"api_url": "https://ghe.example.com/api/v3"}]}`,
+		"duplicate hosts":        `{"hosts": [{"owners": ["a"]}, {"name": "github.com", "owners": ["b"]}]}`,
+	}
+
+	for name, input := range tests {
+		if _, err := Load(strings.NewReader(input)); err == nil {
+			t.Errorf("Expected error for %s", name)
+		}
+	}
+}
+
+func TestLoad_Hosts(t *testing.T) {
+	config, err := Load(strings.NewReader(`{"hosts": [
+		{"owners": ["my-org"]},
+		{"api_url": "https://ghe.example.com/api/v3", "token_env": "GHES_TOKEN", "owners": ["corp", "platform"]}
+	]}`))
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if name := config.Hosts[0].HostName(); name != "github.com" {
+		t.Errorf("Expected github.com for a host without an API URL, got %q", name)
+	}
+	if name := config.Hosts[1].HostName(); name != "ghe.example.com" {
+		t.Errorf("Expected the API URL's host name, got %q", name)
+	}
+}
+
+func TestSetStages(t *testing.T) {
+	config, err := Load(strings.NewReader(`{"owner": "my-org", "scan": {"output": "scan.json"}}`))
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+
+	if err := config.SetStages([]string{"report", " create-pr"}); err != nil {
+		t.Fatalf("SetStages() returned error: %v", err)
+	}
+	if config.Enabled(StageScan) || !config.Enabled(StageReport) || !config.Enabled(StageCreatePR) {
+		t.Errorf("Expected only report and create-pr to be enabled")
+	}
+
+	if err := config.SetStages([]string{"deploy"}); err == nil {
+		t.Errorf("Expected error for unknown stage")
+	}
+	if err := config.SetStages([]string{""}); err == nil {
+		t.Errorf("Expected error when no stages are selected")
+	}
+}
+
+func TestSave_RoundTrip(t *testing.T) {
+	enabled := true
+	config := &Config{Owner: "my-org", TokenEnv: "ORG_TOKEN", CreatePR: CreatePRConfig{Enabled: &enabled}}
+	config.Scan.Output = "scan.json"
+
+	filename := filepath.Join(t.TempDir(), "pipeline.json")
+	if err := config.Save(filename); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	loaded, err := LoadFile(filename)
+	if err != nil {
+		t.Fatalf("LoadFile() returned error: %v", err)
+	}
+	if loaded.Owner != "my-org" || loaded.TokenEnv != "ORG_TOKEN" || loaded.Scan.Output != "scan.json" || !loaded.Enabled(StageCreatePR) {
+		t.Errorf("Unexpected config after round trip: %+v", loaded)
+	}
+}
@@ -0,0 +1,182 @@
+package planfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/pr"
+)
+
+// DefaultFile is where the plan command writes the plan when no output is given
+const DefaultFile = ".actions-maintainer-plan.json"
+
+// Item statuses
+const (
+	StatusPending = "pending" // Not executed yet
+	StatusCreated = "created" // Pull request opened
+	StatusFailed  = "failed"  // Creating the pull request failed; retried on resume
+	StatusSkipped = "skipped" // Workflows changed since planning; rescan and plan again
+)
+
+// Item is one planned pull request with its execution status
+type Item struct {
+	Repository string            `json:"repository"`
+	BaseBranch string            `json:"base_branch"` // Branch the pull request targets
+	Branch     string            `json:"branch"`      // Head branch the pull request is opened from
+	PlanHash   string            `json:"plan_hash"`
+	Status     string            `json:"status"`
+	PR         *output.CreatedPR `json:"pr,omitempty"`
+	Error      string            `json:"error,omitempty"` // Why the item failed or was skipped
+	UpdatedAt  *time.Time        `json:"updated_at,omitempty"`
+	Plan       pr.UpdatePlan     `json:"plan"`
+}
+
+// Execution records a create-pr run over the plan
+type Execution struct {
+	StartedAt time.Time `json:"started_at"`
+	Login     string    `json:"login,omitempty"` // Account of the token that executed the plan
+}
+
+// File is a reviewed set of pull requests, written by the plan command and executed by create-pr --plan.
+// Each item's status is saved as it is executed, so an interrupted rollout resumes where it stopped and
+// the file records what was done.
+type File struct {
+	PlannedAt  time.Time   `json:"planned_at"`
+	Input      string      `json:"input,omitempty"` // Scan results the plan was made from
+	Items      []Item      `json:"items"`
+	Executions []Execution `json:"executions,omitempty"`
+}
+
+// New plans one item per update plan, in order
+func New(plans []pr.UpdatePlan, input string, now time.Time) *File {
+	file := &File{PlannedAt: now, Input: input, Items: []Item{}}
+	for _, plan := range plans {
+		file.Items = append(file.Items, Item{
+			Repository: plan.Repository.FullName,
+			BaseBranch: plan.TargetBranch(),
+			Branch:     plan.BranchName(),
+			PlanHash:   plan.PlanHash(),
+			Status:     StatusPending,
+			Plan:       plan,
+		})
+	}
+	return file
+}
+
+// Load reads a plan file, rejecting items whose updates were edited after planning. Reviewers may
+// remove items, but an edited item would execute updates its hash and branch do not describe.
+func Load(filename string) (*File, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read plan: %w", err)
+	}
+
+	file := &File{}
+	if err := json.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("unable to parse plan as JSON: %w", err)
+	}
+	for i, item := range file.Items {
+		if item.Plan.PlanHash() != item.PlanHash {
+			return nil, fmt.Errorf("plan item %d (%s) was edited after planning; remove it or plan again", i+1, item.Repository)
+		}
+		switch item.Status {
+		case StatusPending, StatusCreated, StatusFailed, StatusSkipped:
+		default:
+			return nil, fmt.Errorf("plan item %d (%s) has unknown status %q", i+1, item.Repository, item.Status)
+		}
+	}
+	return file, nil
+}
+
+// Save writes the plan file through a temporary file, so an interrupted write never loses the statuses saved before
+func (f *File) Save(filename string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode plan: %w", err)
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write plan: %w", err)
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(append(data, '\n')); err != nil {
+		temp.Close()
+		return fmt.Errorf("failed to write plan: %w", err)
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return fmt.Errorf("failed to write plan: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("failed to write plan: %w", err)
+	}
+	if err := os.Rename(temp.Name(), filename); err != nil {
+		return fmt.Errorf("failed to write plan: %w", err)
+	}
+	return nil
+}
+
+// Counts returns the number of items in each status
+func (f *File) Counts() map[string]int {
+	counts := make(map[string]int)
+	for _, item := range f.Items {
+		counts[item.Status]++
+	}
+	return counts
+}
+
+// Started reports whether any item was executed
+func (f *File) Started() bool {
+	for _, item := range f.Items {
+		if item.Status != StatusPending {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the indexes of the items left to execute, pending and failed ones, in plan order.
+// A positive chunk caps how many are returned, so a rollout can be executed a chunk per run.
+func (f *File) Next(chunk int) []int {
+	var next []int
+	for i, item := range f.Items {
+		if chunk > 0 && len(next) == chunk {
+			break
+		}
+		if item.Status == StatusPending || item.Status == StatusFailed {
+			next = append(next, i)
+		}
+	}
+	return next
+}
+
+// Created records the pull request opened for an item
+func (f *File) Created(i int, createdPR output.CreatedPR, now time.Time) {
+	f.Items[i].Status = StatusCreated
+	f.Items[i].PR = &createdPR
+	f.Items[i].Error = ""
+	f.Items[i].UpdatedAt = &now
+}
+
+// Failed records why an item failed, or was skipped when status is StatusSkipped
+func (f *File) Failed(i int, status, reason string, now time.Time) {
+	f.Items[i].Status = status
+	f.Items[i].Error = reason
+	f.Items[i].UpdatedAt = &now
+}
+
+// CreatedPRs returns the pull requests opened for the plan's items, in plan order
+func (f *File) CreatedPRs() []output.CreatedPR {
+	var created []output.CreatedPR
+	for _, item := range f.Items {
+		if item.PR != nil {
+			created = append(created, *item.PR)
+		}
+	}
+	return created
+}
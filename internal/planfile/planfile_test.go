@@ -0,0 +1,92 @@
+package planfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/pr"
+)
+
+func testPlans(names ...string) []pr.UpdatePlan {
+	var plans []pr.UpdatePlan
+	for _, name := range names {
+		plans = append(plans, pr.UpdatePlan{
+			Repository: github.Repository{FullName: name, DefaultBranch: "main"},
+			Updates: []pr.ActionUpdate{{
+				FilePath:       ".github/workflows/ci.yml",
+				ActionRepo:     "actions/checkout",
+				CurrentVersion: "v3",
+				TargetVersion:  "v4",
+			}},
+			Snapshot: map[string]string{".github/workflows/ci.yml": "abc123"},
+		})
+	}
+	return plans
+}
+
+func TestFile_SaveLoadAndResume(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "plan.json")
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	file := New(testPlans("my-org/api", "my-org/web", "my-org/docs"), "scan.json", now)
+	if file.Started() {
+		t.Fatal("Expected a new plan not to be started")
+	}
+	if file.Items[0].Branch != file.Items[0].Plan.BranchName() || file.Items[0].BaseBranch != "main" {
+		t.Errorf("Expected the item to name its branches, got %+v", file.Items[0])
+	}
+	if next := file.Next(2); len(next) != 2 || next[0] != 0 || next[1] != 1 {
+		t.Errorf("Expected the first chunk to be items 0 and 1, got %v", next)
+	}
+
+	file.Created(0, output.CreatedPR{Repository: "my-org/api", URL: "https://github.com/my-org/api/pull/1"}, now)
+	file.Failed(1, StatusFailed, "rate limited", now)
+	if err := file.Save(filename); err != nil {
+		t.Fatalf("Failed to save the plan: %v", err)
+	}
+
+	loaded, err := Load(filename)
+	if err != nil {
+		t.Fatalf("Failed to load the plan: %v", err)
+	}
+	if !loaded.Started() {
+		t.Error("Expected the loaded plan to be started")
+	}
+	if next := loaded.Next(0); len(next) != 2 || next[0] != 1 || next[1] != 2 {
+		t.Errorf("Expected the failed and pending items to be resumed, got %v", next)
+	}
+	counts := loaded.Counts()
+	if counts[StatusCreated] != 1 || counts[StatusFailed] != 1 || counts[StatusPending] != 1 {
+		t.Errorf("Unexpected counts %v", counts)
+	}
+	if created := loaded.CreatedPRs(); len(created) != 1 || created[0].Repository != "my-org/api" {
+		t.Errorf("Expected the created pull request, got %+v", created)
+	}
+	if loaded.Items[1].Error != "rate limited" || loaded.Items[1].Plan.Snapshot[".github/workflows/ci.yml"] != "abc123" {
+		t.Errorf("Expected the item and its plan to round-trip, got %+v", loaded.Items[1])
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(filename))
+	if err != nil || len(entries) != 1 {
+		t.Errorf("Expected no temporary files left behind, got %d entries (%v)", len(entries), err)
+	}
+}
+
+func TestLoad_RejectsEditedItems(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "plan.json")
+	file := New(testPlans("my-org/api"), "", time.Now())
+	file.Items[0].Plan.Updates[0].TargetVersion = "v5"
+	if err := file.Save(filename); err != nil {
+		t.Fatalf("Failed to save the plan: %v", err)
+	}
+
+	_, err := Load(filename)
+	if err == nil || !strings.Contains(err.Error(), "edited") {
+		t.Errorf("Expected the edited item to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,240 @@
+package pr
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// IssueTypeVersionAlignment is the issue type of updates moving a reusable workflow call to the
+// version all consumers of the workflow are aligned to
+const IssueTypeVersionAlignment = "version-alignment"
+
+// AlignmentTarget is the single version every consumer of an internal reusable workflow is aligned to
+type AlignmentTarget struct {
+	Workflow  string         // "owner/repo/<path to workflow>"
+	Version   string         // Version consumers are aligned to
+	Pinned    bool           // Version was set in the versions file rather than being the most used
+	Versions  map[string]int // Consumer repositories per version in use
+	Consumers int            // Consumer repositories of the workflow
+}
+
+// LoadAlignmentVersions reads a JSON object mapping "owner/repo" or "owner/repo/<path to workflow>" to
+// the version consumers of those reusable workflows are aligned to
+func LoadAlignmentVersions(filename string) (map[string]string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read alignment versions: %w", err)
+	}
+
+	var versions map[string]string
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, fmt.Errorf("unable to parse alignment versions %s: %w", filename, err)
+	}
+	for ref, version := range versions {
+		if _, _, err := ParseWorkflowRef(ref); err != nil {
+			return nil, err
+		}
+		if version == "" {
+			return nil, fmt.Errorf("alignment versions: no version given for %s", ref)
+		}
+	}
+	return versions, nil
+}
+
+// AlignmentTargets picks the version each internal reusable workflow's consumers are aligned to: the
+// version versions sets for the workflow or its repository, otherwise the version used by the most
+// consumer repositories, ties going to the newest. Workflows are only aligned to their most used version
+// when consumers use more than one.
+//
+// Workflows are internal when their owner also owns a scanned repository, and a repository calling its
+// own workflows is not a consumer. SHA pins are left alone, since pinning is a deliberate choice.
+func AlignmentTargets(repositories []output.RepositoryResult, versions map[string]string) []AlignmentTarget {
+	owners := make(map[string]bool)
+	for _, repo := range repositories {
+		owners[strings.ToLower(extractOwner(repo.FullName))] = true
+	}
+
+	// workflow -> version -> consumers
+	usage := make(map[string]map[string]map[string]bool)
+	for _, repo := range repositories {
+		for _, action := range repo.Actions {
+			if !alignable(action) || !owners[strings.ToLower(extractOwner(action.Repository))] || strings.EqualFold(action.Repository, repo.FullName) {
+				continue
+			}
+			uses := action.Repository + "/" + action.WorkflowPath
+			if usage[uses] == nil {
+				usage[uses] = make(map[string]map[string]bool)
+			}
+			if usage[uses][action.Version] == nil {
+				usage[uses][action.Version] = make(map[string]bool)
+			}
+			usage[uses][action.Version][repo.FullName] = true
+		}
+	}
+
+	var targets []AlignmentTarget
+	for uses, byVersion := range usage {
+		target := AlignmentTarget{Workflow: uses, Versions: make(map[string]int)}
+		all := make(map[string]bool)
+		for version, consumers := range byVersion {
+			target.Versions[version] = len(consumers)
+			for consumer := range consumers {
+				all[consumer] = true
+			}
+		}
+		target.Consumers = len(all)
+
+		workflowRepo, _, _ := ParseWorkflowRef(uses)
+		if version, ok := versions[uses]; ok {
+			target.Version, target.Pinned = version, true
+		} else if version, ok := versions[workflowRepo]; ok {
+			target.Version, target.Pinned = version, true
+		} else {
+			target.Version = mostUsedVersion(target.Versions)
+		}
+
+		// Every consumer already on the target version
+		if len(target.Versions) == 1 && target.Versions[target.Version] > 0 {
+			continue
+		}
+		targets = append(targets, target)
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Workflow < targets[j].Workflow
+	})
+	return targets
+}
+
+// PlanAlignment creates one plan per consumer repository moving its calls of each target's workflow to
+// the target's version. Calls already on the version, and SHA pins, are skipped.
+func PlanAlignment(repositories []output.RepositoryResult, targets []AlignmentTarget) []UpdatePlan {
+	byWorkflow := make(map[string]AlignmentTarget)
+	for _, target := range targets {
+		byWorkflow[target.Workflow] = target
+	}
+
+	var plans []UpdatePlan
+	for _, repo := range repositories {
+		plan := UpdatePlan{
+			Repository: github.Repository{
+				Owner:         extractOwner(repo.FullName),
+				Name:          repo.Name,
+				FullName:      repo.FullName,
+				DefaultBranch: repo.DefaultBranch,
+			},
+			Updates: []ActionUpdate{},
+		}
+
+		// A workflow calling the same reference twice only needs one update
+		seen := make(map[string]bool)
+		for _, action := range repo.Actions {
+			if !alignable(action) || strings.EqualFold(action.Repository, repo.FullName) {
+				continue
+			}
+			uses := action.Repository + "/" + action.WorkflowPath
+			target, ok := byWorkflow[uses]
+			if !ok || action.Version == target.Version {
+				continue
+			}
+
+			key := action.FilePath + "|" + uses + "@" + action.Version
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			reason := fmt.Sprintf("the version %d of its %d consumers use", target.Versions[target.Version], target.Consumers)
+			if target.Pinned {
+				reason = "the version set for it"
+			}
+			plan.Updates = append(plan.Updates, ActionUpdate{
+				FilePath:       action.FilePath,
+				ActionRepo:     action.Repository,
+				WorkflowPath:   action.WorkflowPath,
+				CurrentVersion: action.Version,
+				TargetVersion:  target.Version,
+				Issue: output.ActionIssue{
+					Repository:       action.Repository,
+					CurrentVersion:   action.Version,
+					SuggestedVersion: target.Version,
+					IssueType:        IssueTypeVersionAlignment,
+					Severity:         "low",
+					Description:      fmt.Sprintf("Consumers of reusable workflow %s are aligned to %s, %s", uses, target.Version, reason),
+					Context:          action.Context,
+					FilePath:         action.FilePath,
+				},
+			})
+		}
+
+		if len(plan.Updates) > 0 {
+			plans = append(plans, plan)
+		}
+	}
+
+	return plans
+}
+
+// alignable reports whether an action reference is a remote reusable workflow call not pinned to a SHA
+func alignable(action workflow.ActionReference) bool {
+	return action.IsReusable && action.Repository != "" && action.WorkflowPath != "" &&
+		output.PinStyle(action.Version) != output.PinStyleSHA
+}
+
+// mostUsedVersion returns the version with the most consumers, ties going to the newest version
+func mostUsedVersion(versions map[string]int) string {
+	best := ""
+	for version, count := range versions {
+		if best == "" || count > versions[best] || (count == versions[best] && newerVersion(version, best)) {
+			best = version
+		}
+	}
+	return best
+}
+
+// newerVersion reports whether version a is newer than b, comparing numeric versions such as v1.2.3
+// segment by segment. Versions that are not numeric, such as branches, are older than numeric ones and
+// otherwise ordered by name so the choice is stable.
+func newerVersion(a, b string) bool {
+	aParts, aNumeric := numericVersion(a)
+	bParts, bNumeric := numericVersion(b)
+	if aNumeric != bNumeric {
+		return aNumeric
+	}
+	if aNumeric {
+		for i := 0; i < len(aParts) || i < len(bParts); i++ {
+			var x, y int
+			if i < len(aParts) {
+				x = aParts[i]
+			}
+			if i < len(bParts) {
+				y = bParts[i]
+			}
+			if x != y {
+				return x > y
+			}
+		}
+	}
+	return a > b
+}
+
+// numericVersion parses "v3.5.2" or "3.5" into its numeric segments
+func numericVersion(version string) ([]int, bool) {
+	var segments []int
+	for _, segment := range strings.Split(strings.TrimPrefix(version, "v"), ".") {
+		number, err := strconv.Atoi(segment)
+		if err != nil || number < 0 {
+			return nil, false
+		}
+		segments = append(segments, number)
+	}
+	return segments, true
+}
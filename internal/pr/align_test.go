@@ -0,0 +1,92 @@
+package pr
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+func TestAlignmentTargets(t *testing.T) {
+	call := func(workflowPath, version string) workflow.ActionReference {
+		return workflow.ActionReference{
+			Repository:   "my-org/shared",
+			WorkflowPath: workflowPath,
+			Version:      version,
+			IsReusable:   true,
+			FilePath:     ".github/workflows/ci.yml",
+		}
+	}
+	build, deploy, lint := ".github/workflows/build.yml", ".github/workflows/deploy.yml", ".github/workflows/lint.yml"
+
+	repositories := []output.RepositoryResult{
+		{Name: "api", FullName: "my-org/api", Actions: []workflow.ActionReference{
+			call(build, "v1.2.0"), call(build, "v1.2.0"), call(deploy, "v1"), call(lint, "v3"),
+		}},
+		{Name: "web", FullName: "my-org/web", Actions: []workflow.ActionReference{
+			call(build, "v1.2.0"), call(deploy, "v2"), call(lint, "v3"),
+		}},
+		{Name: "worker", FullName: "my-org/worker", Actions: []workflow.ActionReference{
+			call(build, "v1.3.0"),
+			call(deploy, "8f4b7f84864484a7bf31766abe9204da3cbe65b3"), // SHA pins are left alone
+			{Repository: "actions/reusable", WorkflowPath: ".github/workflows/ci.yml", Version: "v1", IsReusable: true},
+		}},
+		{Name: "shared", FullName: "my-org/shared", Actions: []workflow.ActionReference{
+			call(build, "main"), // Its own workflow
+		}},
+		{Name: "ops", FullName: "my-org/ops", Actions: []workflow.ActionReference{
+			{Repository: "actions/reusable", WorkflowPath: ".github/workflows/ci.yml", Version: "v2", IsReusable: true},
+		}},
+	}
+
+	targets := AlignmentTargets(repositories, nil)
+	if len(targets) != 2 {
+		t.Fatalf("Expected build and deploy to be aligned (lint is consistent, actions/reusable is external), got %+v", targets)
+	}
+	if targets[0].Workflow != "my-org/shared/"+build || targets[0].Version != "v1.2.0" || targets[0].Consumers != 3 {
+		t.Errorf("Expected build aligned to its most used version, got %+v", targets[0])
+	}
+	if targets[1].Workflow != "my-org/shared/"+deploy || targets[1].Version != "v2" {
+		t.Errorf("Expected the deploy tie to go to the newest version, got %+v", targets[1])
+	}
+
+	plans := PlanAlignment(repositories, targets)
+	if len(plans) != 2 || plans[0].Repository.FullName != "my-org/api" || plans[1].Repository.FullName != "my-org/worker" {
+		t.Fatalf("Expected plans for api and worker, got %+v", plans)
+	}
+	if len(plans[0].Updates) != 1 || plans[0].Updates[0].WorkflowPath != deploy || plans[0].Updates[0].TargetVersion != "v2" {
+		t.Errorf("Expected api's deploy call aligned to v2, got %+v", plans[0].Updates)
+	}
+	update := plans[1].Updates[0]
+	if len(plans[1].Updates) != 1 || update.CurrentVersion != "v1.3.0" || update.TargetVersion != "v1.2.0" || update.Issue.IssueType != IssueTypeVersionAlignment {
+		t.Errorf("Expected worker's build call aligned back to v1.2.0, got %+v", plans[1].Updates)
+	}
+	if !strings.Contains(update.Issue.Description, "the version 2 of its 3 consumers use") {
+		t.Errorf("Expected the description to explain the choice, got %q", update.Issue.Description)
+	}
+
+	// Versions set for a workflow, or for its whole repository, win over the most used version
+	targets = AlignmentTargets(repositories, map[string]string{"my-org/shared/" + build: "v1.3.0", "my-org/shared": "v4"})
+	if len(targets) != 3 || targets[0].Version != "v1.3.0" || !targets[0].Pinned || targets[1].Version != "v4" || targets[2].Workflow != "my-org/shared/"+lint {
+		t.Errorf("Expected the versions set to be the targets, got %+v", targets)
+	}
+}
+
+func TestLoadAlignmentVersions(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "versions.json")
+	os.WriteFile(valid, []byte(`{"my-org/shared/.github/workflows/build.yml": "v2", "my-org/deploy": "v1.4.0"}`), 0644)
+	versions, err := LoadAlignmentVersions(valid)
+	if err != nil || versions["my-org/deploy"] != "v1.4.0" {
+		t.Errorf("Expected the versions to load, got %v (%v)", versions, err)
+	}
+
+	withVersion := filepath.Join(dir, "ref.json")
+	os.WriteFile(withVersion, []byte(`{"my-org/shared@v2": "v2"}`), 0644)
+	if _, err := LoadAlignmentVersions(withVersion); err == nil {
+		t.Error("Expected a versioned workflow reference to be rejected")
+	}
+}
@@ -0,0 +1,120 @@
+package pr
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/actions"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// DefaultBranchPattern stands for a repository's default branch in base branch rules
+const DefaultBranchPattern = "~DEFAULT_BRANCH"
+
+// BaseBranchRule selects the base branches pull requests target in matching repositories
+type BaseBranchRule struct {
+	Branches   []string                `json:"branches"`             // Branch names or globs such as "release/*", or "~DEFAULT_BRANCH"
+	Conditions *actions.RuleConditions `json:"conditions,omitempty"` // Repositories the rule applies to; nil matches every repository
+}
+
+// BranchLister lists the branches of a repository that start with a prefix
+type BranchLister interface {
+	ListBranches(owner, repo, prefix string) ([]string, error)
+}
+
+// LoadBaseBranchRules reads base branch rules from a JSON file
+func LoadBaseBranchRules(filePath string) ([]BaseBranchRule, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read base branch rules: %w", err)
+	}
+
+	var rules []BaseBranchRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse base branch rules: %w", err)
+	}
+
+	for i, rule := range rules {
+		if len(rule.Branches) == 0 {
+			return nil, fmt.Errorf("base branch rule %d has no branches", i+1)
+		}
+		for _, pattern := range rule.Branches {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("base branch rule %d: invalid branch pattern %q: %w", i+1, pattern, err)
+			}
+		}
+		if err := rule.Conditions.Validate(); err != nil {
+			return nil, fmt.Errorf("base branch rule %d: %w", i+1, err)
+		}
+	}
+	return rules, nil
+}
+
+// BaseBranchPatterns returns the branch patterns of the first rule matching a repository
+// Repositories no rule matches target their default branch only.
+func BaseBranchPatterns(rules []BaseBranchRule, repo output.RepositoryResult) []string {
+	context := actions.RepositoryContext{
+		Name:             repo.Name,
+		FullName:         repo.FullName,
+		DefaultBranch:    repo.DefaultBranch,
+		CustomProperties: repo.CustomProperties,
+		Topics:           repo.Topics,
+	}
+	for _, rule := range rules {
+		if rule.Conditions.Matches(context) {
+			return rule.Branches
+		}
+	}
+	return []string{DefaultBranchPattern}
+}
+
+// ExpandBaseBranches copies a repository's plan once per base branch matching the patterns
+// Globs are resolved against the repository's branches; branches are deduplicated in pattern order.
+func ExpandBaseBranches(lister BranchLister, plan UpdatePlan, patterns []string) ([]UpdatePlan, error) {
+	var branches []string
+	seen := make(map[string]bool)
+	add := func(branch string) {
+		if !seen[branch] {
+			seen[branch] = true
+			branches = append(branches, branch)
+		}
+	}
+
+	for _, pattern := range patterns {
+		switch {
+		case pattern == DefaultBranchPattern:
+			add(plan.Repository.DefaultBranch)
+		case !strings.ContainsAny(pattern, "*?["):
+			add(pattern)
+		default:
+			// Only list branches sharing the pattern's literal prefix
+			prefix := pattern[:strings.IndexAny(pattern, "*?[")]
+			candidates, err := lister.ListBranches(plan.Repository.Owner, plan.Repository.Name, prefix)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list branches matching %q: %w", pattern, err)
+			}
+			for _, branch := range candidates {
+				if matched, _ := path.Match(pattern, branch); matched {
+					add(branch)
+				}
+			}
+		}
+	}
+
+	plans := make([]UpdatePlan, 0, len(branches))
+	for _, branch := range branches {
+		branchPlan := plan
+		if branch != plan.Repository.DefaultBranch {
+			branchPlan.BaseBranch = branch
+		}
+		plans = append(plans, branchPlan)
+	}
+	if len(plans) == 0 {
+		log.Printf("Warning: No branches in %s match base branches %s", plan.Repository.FullName, strings.Join(patterns, ", "))
+	}
+	return plans, nil
+}
@@ -0,0 +1,123 @@
+package pr
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/actions"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// fakeBranchLister returns branches starting with the requested prefix
+type fakeBranchLister struct {
+	branches []string
+	err      error
+	prefixes []string
+}
+
+func (f *fakeBranchLister) ListBranches(owner, repo, prefix string) ([]string, error) {
+	f.prefixes = append(f.prefixes, prefix)
+	var matching []string
+	for _, branch := range f.branches {
+		if len(branch) >= len(prefix) && branch[:len(prefix)] == prefix {
+			matching = append(matching, branch)
+		}
+	}
+	return matching, f.err
+}
+
+func TestLoadBaseBranchRules(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.json")
+	os.WriteFile(valid, []byte(`[
+		{"branches": ["~DEFAULT_BRANCH", "release/*"], "conditions": {"topic": "lts"}},
+		{"branches": ["~DEFAULT_BRANCH"]}
+	]`), 0o644)
+
+	rules, err := LoadBaseBranchRules(valid)
+	if err != nil {
+		t.Fatalf("LoadBaseBranchRules() returned error: %v", err)
+	}
+	if len(rules) != 2 || rules[0].Conditions.Topic != "lts" {
+		t.Errorf("Unexpected rules: %+v", rules)
+	}
+
+	for name, content := range map[string]string{
+		"empty.json":   `[{"branches": []}]`,
+		"pattern.json": `[{"branches": ["release/["]}]`,
+		"regex.json":   `[{"branches": ["main"], "conditions": {"repository_pattern": "("}}]`,
+	} {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, []byte(content), 0o644)
+		if _, err := LoadBaseBranchRules(path); err == nil {
+			t.Errorf("Expected an error for %s", name)
+		}
+	}
+}
+
+func TestBaseBranchPatterns(t *testing.T) {
+	rules := []BaseBranchRule{
+		{Branches: []string{DefaultBranchPattern, "release/*"}, Conditions: &actions.RuleConditions{Topic: "lts"}},
+		{Branches: []string{"develop"}},
+	}
+
+	lts := output.RepositoryResult{Name: "api", FullName: "my-org/api", Topics: []string{"lts"}}
+	if got := BaseBranchPatterns(rules, lts); !reflect.DeepEqual(got, []string{DefaultBranchPattern, "release/*"}) {
+		t.Errorf("Expected the first matching rule's branches, got %v", got)
+	}
+	other := output.RepositoryResult{Name: "web", FullName: "my-org/web"}
+	if got := BaseBranchPatterns(rules, other); !reflect.DeepEqual(got, []string{"develop"}) {
+		t.Errorf("Expected the unconditional rule's branches, got %v", got)
+	}
+	if got := BaseBranchPatterns(nil, other); !reflect.DeepEqual(got, []string{DefaultBranchPattern}) {
+		t.Errorf("Expected the default branch without rules, got %v", got)
+	}
+}
+
+func TestExpandBaseBranches(t *testing.T) {
+	plan := UpdatePlan{
+		Repository: github.Repository{Owner: "my-org", Name: "api", FullName: "my-org/api", DefaultBranch: "main"},
+		Updates:    []ActionUpdate{{ActionRepo: "actions/checkout", CurrentVersion: "v2", TargetVersion: "v4"}},
+	}
+	lister := &fakeBranchLister{branches: []string{"release/1.x", "release/2.x", "release/2.x/hotfix", "releases"}}
+
+	plans, err := ExpandBaseBranches(lister, plan, []string{DefaultBranchPattern, "release/*", "main", "support"})
+	if err != nil {
+		t.Fatalf("ExpandBaseBranches() returned error: %v", err)
+	}
+
+	var targets []string
+	for _, p := range plans {
+		targets = append(targets, p.TargetBranch())
+	}
+	// "*" does not cross "/", and the default branch is only planned once
+	expected := []string{"main", "release/1.x", "release/2.x", "support"}
+	if !reflect.DeepEqual(targets, expected) {
+		t.Errorf("Expected %v, got %v", expected, targets)
+	}
+	if plans[0].BaseBranch != "" {
+		t.Errorf("Expected the default branch plan to leave BaseBranch empty, got %q", plans[0].BaseBranch)
+	}
+	if !reflect.DeepEqual(lister.prefixes, []string{"release/"}) {
+		t.Errorf("Expected branches listed by the glob's literal prefix, got %v", lister.prefixes)
+	}
+
+	if _, err := ExpandBaseBranches(&fakeBranchLister{err: fmt.Errorf("not found")}, plan, []string{"release/*"}); err == nil {
+		t.Errorf("Expected a listing error to be returned")
+	}
+}
+
+func TestGeneratePRTitle_BaseBranch(t *testing.T) {
+	creator := &Creator{}
+	plan := UpdatePlan{
+		Updates:    []ActionUpdate{{ActionRepo: "actions/checkout", CurrentVersion: "v2", TargetVersion: "v4"}},
+		BaseBranch: "release/1.x",
+	}
+	if title := creator.generatePRTitle(plan); title != "[release/1.x] Update actions/checkout from v2 to v4" {
+		t.Errorf("Unexpected title: %s", title)
+	}
+}
@@ -0,0 +1,119 @@
+package pr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// ParseWorkflowRef splits "owner/repo" or "owner/repo/.github/workflows/file.yml" into
+// the repository and the workflow path (empty when only the repository is given)
+func ParseWorkflowRef(ref string) (string, string, error) {
+	if strings.Contains(ref, "@") {
+		return "", "", fmt.Errorf("workflow reference %q must not include a version; pass the version separately", ref)
+	}
+
+	parts := strings.SplitN(ref, "/", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("workflow reference %q must be owner/repo or owner/repo/<path to workflow>", ref)
+	}
+
+	repository := parts[0] + "/" + parts[1]
+	if len(parts) == 2 {
+		return repository, "", nil
+	}
+	return repository, parts[2], nil
+}
+
+// PlanBroadcast creates one plan per consumer repository updating calls to a reusable workflow to version
+// An empty workflowPath matches every reusable workflow in workflowRepo. Calls already on version are skipped.
+func PlanBroadcast(repositories []output.RepositoryResult, workflowRepo, workflowPath, version string) []UpdatePlan {
+	var plans []UpdatePlan
+
+	for _, repo := range repositories {
+		plan := UpdatePlan{
+			Repository: github.Repository{
+				Owner:         extractOwner(repo.FullName),
+				Name:          repo.Name,
+				FullName:      repo.FullName,
+				DefaultBranch: repo.DefaultBranch,
+			},
+			Updates: []ActionUpdate{},
+		}
+
+		// A workflow calling the same reference twice only needs one update
+		seen := make(map[string]bool)
+		for _, action := range repo.Actions {
+			if !action.IsReusable || action.Local || action.Repository != workflowRepo || action.Version == version {
+				continue
+			}
+			if workflowPath != "" && action.WorkflowPath != workflowPath {
+				continue
+			}
+
+			uses := action.Repository + "/" + action.WorkflowPath
+			key := action.FilePath + "|" + uses + "@" + action.Version
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			plan.Updates = append(plan.Updates, ActionUpdate{
+				FilePath:       action.FilePath,
+				ActionRepo:     action.Repository,
+				WorkflowPath:   action.WorkflowPath,
+				CurrentVersion: action.Version,
+				TargetVersion:  version,
+				Issue: output.ActionIssue{
+					Repository:       action.Repository,
+					CurrentVersion:   action.Version,
+					SuggestedVersion: version,
+					IssueType:        "outdated",
+					Severity:         "low",
+					Description:      fmt.Sprintf("Reusable workflow %s released %s", uses, version),
+					Context:          action.Context,
+					FilePath:         action.FilePath,
+				},
+			})
+		}
+
+		if len(plan.Updates) > 0 {
+			plans = append(plans, plan)
+		}
+	}
+
+	return plans
+}
+
+// BroadcastIssueTitle returns the title of the tracking issue for a reusable workflow rollout
+func BroadcastIssueTitle(workflowRef, version string, consumers int) string {
+	return fmt.Sprintf("Roll out %s@%s to %d consumers", workflowRef, version, consumers)
+}
+
+// BroadcastIssueBody lists the consumer pull requests of a rollout, and repositories whose pull request failed
+func BroadcastIssueBody(workflowRef, version string, createdPRs []output.CreatedPR, failed []string) string {
+	var body strings.Builder
+
+	body.WriteString(fmt.Sprintf("Tracking the rollout of `%s@%s` to its consumers.\n\n", workflowRef, version))
+
+	body.WriteString("## Consumer Pull Requests\n\n")
+	if len(createdPRs) == 0 {
+		body.WriteString("No pull requests were created.\n")
+	}
+	for _, createdPR := range createdPRs {
+		body.WriteString(fmt.Sprintf("- [ ] %s: [#%d](%s) (%d updates)\n", createdPR.Repository, createdPR.Number, createdPR.URL, createdPR.UpdateCount))
+	}
+
+	if len(failed) > 0 {
+		body.WriteString("\n## Needs Manual Follow-Up\n\n")
+		for _, repo := range failed {
+			body.WriteString(fmt.Sprintf("- [ ] %s\n", repo))
+		}
+	}
+
+	body.WriteString("\n---\n*This issue was created by actions-maintainer.*\n")
+
+	return body.String()
+}
@@ -0,0 +1,126 @@
+package pr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+func TestParseWorkflowRef(t *testing.T) {
+	tests := []struct {
+		ref      string
+		repo     string
+		path     string
+		hasError bool
+	}{
+		{"my-org/shared", "my-org/shared", "", false},
+		{"my-org/shared/.github/workflows/build.yml", "my-org/shared", ".github/workflows/build.yml", false},
+		{"my-org/shared/.github/workflows/build.yml@v2", "", "", true},
+		{"my-org", "", "", true},
+		{"/shared", "", "", true},
+	}
+
+	for _, test := range tests {
+		repo, path, err := ParseWorkflowRef(test.ref)
+		if (err != nil) != test.hasError {
+			t.Errorf("ParseWorkflowRef(%q) error = %v, expected error: %v", test.ref, err, test.hasError)
+			continue
+		}
+		if repo != test.repo || path != test.path {
+			t.Errorf("ParseWorkflowRef(%q) = (%q, %q), expected (%q, %q)", test.ref, repo, path, test.repo, test.path)
+		}
+	}
+}
+
+func TestPlanBroadcast(t *testing.T) {
+	build := func(filePath, version string) workflow.ActionReference {
+		return workflow.ActionReference{
+			Repository:   "my-org/shared",
+			WorkflowPath: ".github/workflows/build.yml",
+			Version:      version,
+			IsReusable:   true,
+			FilePath:     filePath,
+		}
+	}
+
+	repositories := []output.RepositoryResult{
+		{
+			Name:     "api",
+			FullName: "my-org/api",
+			Actions: []workflow.ActionReference{
+				build(".github/workflows/ci.yml", "v1"),
+				build(".github/workflows/ci.yml", "v1"), // Called from two jobs
+				build(".github/workflows/release.yml", "v1.4.0"),
+				{Repository: "my-org/shared", WorkflowPath: ".github/workflows/deploy.yml", Version: "v1", IsReusable: true, FilePath: ".github/workflows/ci.yml"},
+				{Repository: "actions/checkout", Version: "v3", FilePath: ".github/workflows/ci.yml"},
+			},
+		},
+		{
+			Name:     "web",
+			FullName: "my-org/web",
+			Actions:  []workflow.ActionReference{build(".github/workflows/ci.yml", "v2")},
+		},
+	}
+
+	plans := PlanBroadcast(repositories, "my-org/shared", ".github/workflows/build.yml", "v2")
+	if len(plans) != 1 {
+		t.Fatalf("Expected 1 plan (web is already on v2), got %d", len(plans))
+	}
+
+	plan := plans[0]
+	if plan.Repository.FullName != "my-org/api" || plan.Repository.Owner != "my-org" {
+		t.Errorf("Unexpected repository: %+v", plan.Repository)
+	}
+	if len(plan.Updates) != 2 {
+		t.Fatalf("Expected 2 updates, got %d", len(plan.Updates))
+	}
+	for _, update := range plan.Updates {
+		if update.ActionRepo != "my-org/shared" || update.WorkflowPath != ".github/workflows/build.yml" || update.TargetVersion != "v2" {
+			t.Errorf("Unexpected update: %+v", update)
+		}
+	}
+
+	// Without a path every workflow from the repository is updated
+	if plans := PlanBroadcast(repositories, "my-org/shared", "", "v2"); len(plans[0].Updates) != 3 {
+		t.Errorf("Expected 3 updates for all workflows, got %d", len(plans[0].Updates))
+	}
+}
+
+func TestPlanBroadcast_UpdatesWorkflowContent(t *testing.T) {
+	repositories := []output.RepositoryResult{
+		{
+			Name:     "api",
+			FullName: "my-org/api",
+			Actions: []workflow.ActionReference{
+				{Repository: "my-org/shared", WorkflowPath: ".github/workflows/build.yml", Version: "v1", IsReusable: true, FilePath: ".github/workflows/ci.yml"},
+			},
+		},
+	}
+
+	plans := PlanBroadcast(repositories, "my-org/shared", "", "v2")
+	content := "jobs:\n  build:\n    uses: my-org/shared/.github/workflows/build.yml@v1\n"
+	updated := UpdateWorkflowContent(content, plans[0].Updates)
+
+	if !strings.Contains(updated, "uses: my-org/shared/.github/workflows/build.yml@v2") {
+		t.Errorf("Expected the reusable workflow call to be updated, got:\n%s", updated)
+	}
+}
+
+func TestBroadcastIssueBody(t *testing.T) {
+	body := BroadcastIssueBody("my-org/shared/.github/workflows/build.yml", "v2", []output.CreatedPR{
+		{Repository: "my-org/api", Number: 12, URL: "https://github.com/my-org/api/pull/12", UpdateCount: 2},
+	}, []string{"my-org/web"})
+
+	for _, expected := range []string{
+		"`my-org/shared/.github/workflows/build.yml@v2`",
+		"- [ ] my-org/api: [#12](https://github.com/my-org/api/pull/12) (2 updates)",
+		"## Needs Manual Follow-Up",
+		"- [ ] my-org/web",
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected body to contain %q, got:\n%s", expected, body)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package pr
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// ChangelogEntry is a pull request opened by a create-pr run, with the changes it makes
+type ChangelogEntry struct {
+	PR         output.CreatedPR
+	BaseBranch string            // Branch the pull request targets
+	Changes    []ChangelogChange // Workflow changes, in plan order
+	Files      []string          // Files outside .github/workflows edited by rules
+}
+
+// ChangelogChange is one change to a workflow file; an empty Before is an addition and an empty After a removal
+type ChangelogChange struct {
+	File   string
+	Action string
+	Before string
+	After  string
+}
+
+// NewChangelog pairs the pull requests created with the plans they were opened for, by repository and
+// base branch. Plans without a pull request, e.g. because creating it failed, are left out.
+func NewChangelog(plans []UpdatePlan, created []output.CreatedPR) []ChangelogEntry {
+	byKey := make(map[string]UpdatePlan, len(plans))
+	for _, plan := range plans {
+		byKey[plan.Repository.FullName+"\x00"+plan.BaseBranch] = plan
+	}
+
+	var entries []ChangelogEntry
+	for _, createdPR := range created {
+		plan, ok := byKey[createdPR.Repository+"\x00"+createdPR.BaseBranch]
+		if !ok {
+			continue
+		}
+		entry := ChangelogEntry{
+			PR:         createdPR,
+			BaseBranch: plan.TargetBranch(),
+			Files:      plan.FilePaths(),
+		}
+		for _, update := range plan.Updates {
+			entry.Changes = append(entry.Changes, changelogChange(update))
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].PR.Repository != entries[j].PR.Repository {
+			return entries[i].PR.Repository < entries[j].PR.Repository
+		}
+		return entries[i].BaseBranch < entries[j].BaseBranch
+	})
+	return entries
+}
+
+// changelogChange describes an update the way the default PR body does, as the refs before and after
+func changelogChange(update ActionUpdate) ChangelogChange {
+	action := joinRefPath(update.ActionRepo, update.WorkflowPath)
+	change := ChangelogChange{
+		File:   update.FilePath,
+		Action: action,
+		Before: action + "@" + update.CurrentVersion,
+	}
+
+	switch {
+	case update.Issue.IssueType == "missing-required-action":
+		change.Before = ""
+		change.After = action + "@" + update.TargetVersion
+	case update.Issue.Remediation != nil:
+		if update.Issue.Remediation.Action == "replace" {
+			change.After = update.Issue.Remediation.Replacement
+		}
+	case update.Issue.Concurrency != nil:
+		change.Action = "concurrency"
+		change.Before = ""
+		change.After = fmt.Sprintf("group: %s, cancel-in-progress: %t", update.Issue.Concurrency.Group, update.Issue.Concurrency.CancelInProgress)
+	default:
+		// Migrations may move the action to another repository or path
+		targetRepo, targetPath := update.ActionRepo, update.WorkflowPath
+		if update.TargetRepo != "" {
+			targetRepo = update.TargetRepo
+		}
+		if update.TargetPath != "" {
+			targetPath = update.TargetPath
+		}
+		change.After = joinRefPath(targetRepo, targetPath) + "@" + update.TargetVersion
+	}
+	return change
+}
+
+// WriteChangelog writes the pull requests of a run as a CHANGELOG-style markdown document, one section per
+// pull request, suitable for attaching to a change ticket. Sections start with "## ", after a header.
+func WriteChangelog(w io.Writer, entries []ChangelogEntry, generated time.Time) error {
+	var doc strings.Builder
+	doc.WriteString("# Changelog\n\n")
+	doc.WriteString(fmt.Sprintf("Changes made by actions-maintainer create-pr on %s.\n\n", generated.UTC().Format("2006-01-02 15:04 UTC")))
+	if len(entries) == 0 {
+		doc.WriteString("No pull requests were created.\n")
+	}
+
+	for _, entry := range entries {
+		doc.WriteString(fmt.Sprintf("## %s\n\n", entry.PR.Repository))
+		doc.WriteString(fmt.Sprintf("[#%d %s](%s) into `%s`", entry.PR.Number, entry.PR.Title, entry.PR.URL, entry.BaseBranch))
+		if entry.PR.Branch != "" {
+			doc.WriteString(fmt.Sprintf(" from `%s`", entry.PR.Branch))
+		}
+		doc.WriteString("\n\n")
+
+		if len(entry.Changes) > 0 {
+			doc.WriteString("| File | Action | Before | After |\n")
+			doc.WriteString("|---|---|---|---|\n")
+			for _, change := range entry.Changes {
+				before, after := "_none_", "_removed_"
+				if change.Before != "" {
+					before = tableCode(change.Before)
+				}
+				if change.After != "" {
+					after = tableCode(change.After)
+				}
+				doc.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", tableCode(change.File), tableCode(change.Action), before, after))
+			}
+			doc.WriteString("\n")
+		}
+
+		if len(entry.Files) > 0 {
+			files := make([]string, 0, len(entry.Files))
+			for _, file := range entry.Files {
+				files = append(files, "`"+file+"`")
+			}
+			doc.WriteString(fmt.Sprintf("Other files changed: %s\n\n", strings.Join(files, ", ")))
+		}
+	}
+
+	_, err := io.WriteString(w, doc.String())
+	return err
+}
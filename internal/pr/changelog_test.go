@@ -0,0 +1,96 @@
+package pr
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+func TestNewChangelog(t *testing.T) {
+	plans := []UpdatePlan{
+		{
+			Repository: github.Repository{FullName: "my-org/web", DefaultBranch: "main"},
+			Updates: []ActionUpdate{
+				{FilePath: ".github/workflows/ci.yml", ActionRepo: "actions/checkout", CurrentVersion: "v3", TargetVersion: "v4", Issue: output.ActionIssue{IssueType: "outdated"}},
+				{FilePath: ".github/workflows/ci.yml", ActionRepo: "old-org/deploy", CurrentVersion: "v2", TargetVersion: "v1", TargetRepo: "new-org/deploy", Issue: output.ActionIssue{IssueType: "migration"}},
+				{FilePath: ".github/workflows/ci.yml", ActionRepo: "acme/upload", CurrentVersion: "v1", Issue: output.ActionIssue{IssueType: "banned-action", Remediation: &output.BanRemediation{Action: "remove"}}},
+				{FilePath: ".github/workflows/ci.yml", ActionRepo: "my-org/scan", TargetVersion: "v2", Issue: output.ActionIssue{IssueType: "missing-required-action"}},
+			},
+			Files: []FilePlan{{Path: ".github/dependabot.yml"}},
+		},
+		{
+			Repository: github.Repository{FullName: "my-org/api", DefaultBranch: "main"},
+			BaseBranch: "release/1",
+			Updates:    []ActionUpdate{{FilePath: ".github/workflows/ci.yml", ActionRepo: "actions/cache", CurrentVersion: "v2", TargetVersion: "v4"}},
+		},
+		{
+			Repository: github.Repository{FullName: "my-org/failed", DefaultBranch: "main"},
+			Updates:    []ActionUpdate{{FilePath: ".github/workflows/ci.yml", ActionRepo: "actions/cache", CurrentVersion: "v2", TargetVersion: "v4"}},
+		},
+	}
+	created := []output.CreatedPR{
+		{Repository: "my-org/web", URL: "https://github.com/my-org/web/pull/7", Branch: "actions-maintainer/updates", Title: "Update GitHub Actions", Number: 7},
+		{Repository: "my-org/api", BaseBranch: "release/1", URL: "https://github.com/my-org/api/pull/3", Title: "Update GitHub Actions | release/1", Number: 3},
+	}
+
+	entries := NewChangelog(plans, created)
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %+v", entries)
+	}
+	if entries[0].PR.Repository != "my-org/api" || entries[0].BaseBranch != "release/1" {
+		t.Errorf("Expected entries sorted by repository, got %s (%s) first", entries[0].PR.Repository, entries[0].BaseBranch)
+	}
+
+	web := entries[1]
+	expected := []ChangelogChange{
+		{File: ".github/workflows/ci.yml", Action: "actions/checkout", Before: "actions/checkout@v3", After: "actions/checkout@v4"},
+		{File: ".github/workflows/ci.yml", Action: "old-org/deploy", Before: "old-org/deploy@v2", After: "new-org/deploy@v1"},
+		{File: ".github/workflows/ci.yml", Action: "acme/upload", Before: "acme/upload@v1"},
+		{File: ".github/workflows/ci.yml", Action: "my-org/scan", After: "my-org/scan@v2"},
+	}
+	if len(web.Changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got %+v", len(expected), web.Changes)
+	}
+	for i := range expected {
+		if web.Changes[i] != expected[i] {
+			t.Errorf("Expected change %d to be %+v, got %+v", i, expected[i], web.Changes[i])
+		}
+	}
+	if web.BaseBranch != "main" || len(web.Files) != 1 || web.Files[0] != ".github/dependabot.yml" {
+		t.Errorf("Expected main and the dependabot file, got %s and %v", web.BaseBranch, web.Files)
+	}
+
+	var doc strings.Builder
+	if err := WriteChangelog(&doc, entries, time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("WriteChangelog failed: %v", err)
+	}
+	for _, want := range []string{
+		"# Changelog\n\nChanges made by actions-maintainer create-pr on 2026-03-02 09:30 UTC.\n\n## my-org/api\n",
+		"[#3 Update GitHub Actions | release/1](https://github.com/my-org/api/pull/3) into `release/1`\n",
+		"[#7 Update GitHub Actions](https://github.com/my-org/web/pull/7) into `main` from `actions-maintainer/updates`\n",
+		"| `.github/workflows/ci.yml` | `old-org/deploy` | `old-org/deploy@v2` | `new-org/deploy@v1` |\n",
+		"| `.github/workflows/ci.yml` | `acme/upload` | `acme/upload@v1` | _removed_ |\n",
+		"| `.github/workflows/ci.yml` | `my-org/scan` | _none_ | `my-org/scan@v2` |\n",
+		"Other files changed: `.github/dependabot.yml`\n",
+	} {
+		if !strings.Contains(doc.String(), want) {
+			t.Errorf("Expected changelog to contain %q, got:\n%s", want, doc.String())
+		}
+	}
+	if strings.Contains(doc.String(), "my-org/failed") {
+		t.Errorf("Expected plans without a pull request to be left out, got:\n%s", doc.String())
+	}
+}
+
+func TestWriteChangelog_Empty(t *testing.T) {
+	var doc strings.Builder
+	if err := WriteChangelog(&doc, nil, time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("WriteChangelog failed: %v", err)
+	}
+	if !strings.HasSuffix(doc.String(), "No pull requests were created.\n") || strings.Contains(doc.String(), "## ") {
+		t.Errorf("Expected an empty changelog, got:\n%s", doc.String())
+	}
+}
@@ -0,0 +1,139 @@
+package pr
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CodeOwnersClient reads a repository's CODEOWNERS file
+type CodeOwnersClient interface {
+	GetCodeOwners(owner, repo, ref string) (string, error)
+}
+
+// CodeOwnersRule is a line of a CODEOWNERS file: a path pattern and the owners of matching files
+type CodeOwnersRule struct {
+	Pattern string
+	Owners  []string // "@user" and "@org/team" as written; e-mail owners are kept but cannot be requested
+	match   *regexp.Regexp
+}
+
+// Matches reports whether the rule's pattern matches a path relative to the repository root
+func (r CodeOwnersRule) Matches(path string) bool {
+	return r.match.MatchString(strings.TrimPrefix(path, "/"))
+}
+
+// ParseCodeOwners parses the rules of a CODEOWNERS file; lines with invalid patterns are skipped
+func ParseCodeOwners(content string) []CodeOwnersRule {
+	var rules []CodeOwnersRule
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		rule := CodeOwnersRule{Pattern: fields[0]}
+		for _, owner := range fields[1:] {
+			if strings.HasPrefix(owner, "#") {
+				break
+			}
+			rule.Owners = append(rule.Owners, owner)
+		}
+		match, err := codeOwnersPattern(rule.Pattern)
+		if err != nil {
+			continue
+		}
+		rule.match = match
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// codeOwnersPattern compiles a CODEOWNERS pattern, which follows gitignore rules: patterns with a
+// leading or inner slash are relative to the root and others match at any depth, "*" stays within a
+// directory, "**" crosses directories, and a directory pattern owns everything beneath it except
+// that "dir/*" only owns the directory's own files
+func codeOwnersPattern(pattern string) (*regexp.Regexp, error) {
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	directFiles := strings.HasSuffix(pattern, "/*")
+	pattern = strings.Trim(pattern, "/")
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch char := pattern[i]; {
+		case char == '*' && i+1 < len(pattern) && pattern[i+1] == '*':
+			if i+2 < len(pattern) && pattern[i+2] == '/' {
+				// "**/" matches zero or more directories
+				b.WriteString("(?:.*/)?")
+				i += 2
+			} else {
+				b.WriteString(".*")
+				i++
+			}
+		case char == '*':
+			b.WriteString("[^/]*")
+		case char == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(char)))
+		}
+	}
+	if !directFiles {
+		b.WriteString("(?:/.*)?")
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// CodeOwnersOf returns the owners of a path: those of the last matching rule, as GitHub uses
+func CodeOwnersOf(rules []CodeOwnersRule, path string) []string {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if rules[i].Matches(path) {
+			return rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// FindCodeOwners returns the code owners of the files a plan changes, in the order they are first
+// named, from the CODEOWNERS file of the branch the pull request targets. Owners are returned as
+// "user" and "org/team"; e-mail owners cannot be requested as reviewers and are left out.
+func FindCodeOwners(client CodeOwnersClient, plan UpdatePlan) ([]string, error) {
+	content, err := client.GetCodeOwners(plan.Repository.Owner, plan.Repository.Name, plan.TargetBranch())
+	if err != nil {
+		return nil, fmt.Errorf("unable to read CODEOWNERS: %w", err)
+	}
+	rules := ParseCodeOwners(content)
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	paths := make([]string, 0, len(plan.Updates)+len(plan.Files))
+	seenPaths := make(map[string]bool)
+	for _, update := range plan.Updates {
+		if !seenPaths[update.FilePath] {
+			seenPaths[update.FilePath] = true
+			paths = append(paths, update.FilePath)
+		}
+	}
+	paths = append(paths, plan.FilePaths()...)
+
+	var owners []string
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		for _, owner := range CodeOwnersOf(rules, path) {
+			if !ownerPattern.MatchString(owner) {
+				continue
+			}
+			owner = strings.TrimPrefix(owner, "@")
+			if key := strings.ToLower(owner); !seen[key] {
+				seen[key] = true
+				owners = append(owners, owner)
+			}
+		}
+	}
+	return owners, nil
+}
@@ -0,0 +1,84 @@
+package pr
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+)
+
+const testCodeOwners = `# Default owners
+*                       @my-org/everyone
+*.md                    @docs-team-lead
+/.github/workflows/     @my-org/ci   # CI owners
+.github/workflows/deploy.yml @my-org/ci @my-org/release releases@example.com
+docs/*                  @writer
+**/generated/**         @bot
+/build/logs             @octocat
+`
+
+func TestCodeOwnersOf(t *testing.T) {
+	rules := ParseCodeOwners(testCodeOwners)
+	if len(rules) != 7 {
+		t.Fatalf("Expected 7 rules, got %d", len(rules))
+	}
+
+	tests := []struct {
+		path     string
+		expected []string
+	}{
+		{"main.go", []string{"@my-org/everyone"}},
+		{"docs/guide/README.md", []string{"@docs-team-lead"}},
+		{".github/workflows/ci.yml", []string{"@my-org/ci"}},
+		{".github/workflows/deploy.yml", []string{"@my-org/ci", "@my-org/release", "releases@example.com"}},
+		{"docs/index.html", []string{"@writer"}},
+		{"docs/api/index.html", []string{"@my-org/everyone"}}, // docs/* does not own subdirectories
+		{"src/generated/api/types.go", []string{"@bot"}},
+		{"build/logs/today.log", []string{"@octocat"}},
+		{"src/build/logs/today.log", []string{"@my-org/everyone"}}, // /build/logs is anchored to the root
+	}
+	for _, tt := range tests {
+		if owners := CodeOwnersOf(rules, tt.path); !reflect.DeepEqual(owners, tt.expected) {
+			t.Errorf("CodeOwnersOf(%q): expected %v, got %v", tt.path, tt.expected, owners)
+		}
+	}
+}
+
+type fakeCodeOwnersClient struct {
+	content string
+	ref     string
+}
+
+func (f *fakeCodeOwnersClient) GetCodeOwners(owner, repo, ref string) (string, error) {
+	f.ref = ref
+	return f.content, nil
+}
+
+func TestFindCodeOwners(t *testing.T) {
+	client := &fakeCodeOwnersClient{content: testCodeOwners}
+	plan := UpdatePlan{
+		Repository: github.Repository{Owner: "my-org", Name: "api", FullName: "my-org/api", DefaultBranch: "main"},
+		Updates: []ActionUpdate{
+			{FilePath: ".github/workflows/deploy.yml"},
+			{FilePath: ".github/workflows/ci.yml"},
+			{FilePath: ".github/workflows/deploy.yml"},
+		},
+	}
+
+	owners, err := FindCodeOwners(client, plan)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// E-mail owners cannot be requested as reviewers
+	if !reflect.DeepEqual(owners, []string{"my-org/ci", "my-org/release"}) {
+		t.Errorf("Expected [my-org/ci my-org/release], got %v", owners)
+	}
+	if client.ref != "main" {
+		t.Errorf("Expected CODEOWNERS read from the target branch main, got %q", client.ref)
+	}
+
+	client.content = ""
+	if owners, err := FindCodeOwners(client, plan); err != nil || owners != nil {
+		t.Errorf("Expected no owners without a CODEOWNERS file, got %v, %v", owners, err)
+	}
+}
@@ -0,0 +1,154 @@
+package pr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+)
+
+// Coexist modes choose what happens to updates of an action that Dependabot or Renovate already has an
+// open pull request for
+const (
+	CoexistSkip      = "skip"      // Leave the action to the bot's pull request, avoiding conflicting edits
+	CoexistSupersede = "supersede" // Update the action anyway, cross-linking and commenting on the bot's pull request
+	CoexistIgnore    = "ignore"    // Do not look for bot pull requests
+)
+
+// ParseCoexistMode validates a --coexist-mode value, defaulting to skip
+func ParseCoexistMode(value string) (string, error) {
+	switch value {
+	case "":
+		return CoexistSkip, nil
+	case CoexistSkip, CoexistSupersede, CoexistIgnore:
+		return value, nil
+	}
+	return "", fmt.Errorf("invalid coexist mode %q: use %s, %s, or %s", value, CoexistSkip, CoexistSupersede, CoexistIgnore)
+}
+
+// dependencyBots are the logins and head branch prefixes of dependency update bots
+var dependencyBots = []struct {
+	login  string
+	branch string
+}{
+	{login: "dependabot[bot]", branch: "dependabot/"},
+	{login: "renovate[bot]", branch: "renovate/"},
+}
+
+// OpenPullRequestLister lists the open pull requests of a repository
+type OpenPullRequestLister interface {
+	ListOpenPullRequests(owner, repo string) ([]github.PullRequestInfo, error)
+}
+
+// BotPullRequest is an open Dependabot or Renovate pull request updating an action a plan updates
+type BotPullRequest struct {
+	ActionRepo  string                 `json:"action_repo"`
+	PullRequest github.PullRequestInfo `json:"pull_request"`
+}
+
+// isDependencyBot reports whether a pull request was opened by Dependabot or Renovate
+func isDependencyBot(pull github.PullRequestInfo) bool {
+	for _, bot := range dependencyBots {
+		if strings.EqualFold(pull.Author, bot.login) || strings.HasPrefix(pull.HeadBranch, bot.branch) {
+			return true
+		}
+	}
+	return false
+}
+
+// mentionsAction reports whether a pull request title or head branch names an action, as in
+// "Bump actions/checkout from 3 to 4", "Update actions/checkout action to v4", or
+// "dependabot/github_actions/actions/checkout-4"
+func mentionsAction(text, action string) bool {
+	text, action = strings.ToLower(text), strings.ToLower(action)
+	for start := 0; ; {
+		i := strings.Index(text[start:], action)
+		if i < 0 {
+			return false
+		}
+		i += start
+		end := i + len(action)
+		before := i == 0 || !isNameChar(text[i-1])
+		after := end == len(text) || text[end] == ' ' ||
+			(text[end] == '-' && end+1 < len(text) && (text[end+1] >= '0' && text[end+1] <= '9' || text[end+1] == 'v'))
+		if before && after {
+			return true
+		}
+		start = i + 1
+	}
+}
+
+// isNameChar reports whether a byte can be part of an owner or repository name
+func isNameChar(char byte) bool {
+	return char >= 'a' && char <= 'z' || char >= '0' && char <= '9' || char == '-' || char == '_' || char == '.'
+}
+
+// FindBotPullRequests returns the open Dependabot and Renovate pull requests of the plan's repository
+// that update an action the plan updates
+func FindBotPullRequests(client OpenPullRequestLister, plan UpdatePlan) ([]BotPullRequest, error) {
+	pulls, err := client.ListOpenPullRequests(plan.Repository.Owner, plan.Repository.Name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list open pull requests: %w", err)
+	}
+
+	var found []BotPullRequest
+	seen := make(map[string]bool)
+	for _, pull := range pulls {
+		if !isDependencyBot(pull) {
+			continue
+		}
+		for _, update := range plan.Updates {
+			key := fmt.Sprintf("%s#%d", update.ActionRepo, pull.Number)
+			if seen[key] || !(mentionsAction(pull.Title, update.ActionRepo) || mentionsAction(pull.HeadBranch, update.ActionRepo)) {
+				continue
+			}
+			seen[key] = true
+			found = append(found, BotPullRequest{ActionRepo: update.ActionRepo, PullRequest: pull})
+		}
+	}
+	return found, nil
+}
+
+// Coexist applies a coexist mode to a plan given the bot pull requests updating its actions
+// Skip drops the updates of those actions; supersede keeps them and lists the bot pull requests in
+// Supersedes. The plan is returned unchanged when there are no bot pull requests.
+func Coexist(plan UpdatePlan, pulls []BotPullRequest, mode string) UpdatePlan {
+	if len(pulls) == 0 || mode == CoexistIgnore {
+		return plan
+	}
+	if mode == CoexistSupersede {
+		plan.Supersedes = pulls
+		return plan
+	}
+
+	skipped := make(map[string]bool)
+	for _, pull := range pulls {
+		skipped[pull.ActionRepo] = true
+	}
+	var updates []ActionUpdate
+	files := make(map[string]bool)
+	for _, update := range plan.Updates {
+		if skipped[update.ActionRepo] {
+			continue
+		}
+		updates = append(updates, update)
+		files[update.FilePath] = true
+	}
+	plan.Updates = updates
+	plan.Files = PlanFileEdits(updates)
+
+	// Files left untouched need no drift check
+	snapshot := make(map[string]string)
+	for path, sha := range plan.Snapshot {
+		if files[path] {
+			snapshot[path] = sha
+		}
+	}
+	plan.Snapshot = snapshot
+	return plan
+}
+
+// SupersedeComment is the comment left on a bot pull request superseded by a plan's pull request
+func SupersedeComment(pull BotPullRequest, url string) string {
+	return fmt.Sprintf("Superseded by %s, which updates %s together with the repository's other action updates. This pull request can be closed once it merges.", url, pull.ActionRepo)
+}
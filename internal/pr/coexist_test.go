@@ -0,0 +1,117 @@
+package pr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+)
+
+func TestMentionsAction(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected bool
+	}{
+		{"Bump actions/checkout from 3 to 4", true},
+		{"chore(deps): update actions/checkout action to v4", true},
+		{"dependabot/github_actions/actions/checkout-4", true},
+		{"renovate/actions-checkout-4.x", false},
+		{"Bump actions/checkout-extra from 1 to 2", false},
+		{"Bump my-actions/checkout from 1 to 2", false},
+		{"Bump actions/setup-node from 3 to 4", false},
+	}
+	for _, tt := range tests {
+		if got := mentionsAction(tt.text, "actions/checkout"); got != tt.expected {
+			t.Errorf("mentionsAction(%q): expected %v, got %v", tt.text, tt.expected, got)
+		}
+	}
+}
+
+type fakeOpenPullRequestLister struct {
+	pulls []github.PullRequestInfo
+}
+
+func (f *fakeOpenPullRequestLister) ListOpenPullRequests(owner, repo string) ([]github.PullRequestInfo, error) {
+	return f.pulls, nil
+}
+
+func coexistPlan() UpdatePlan {
+	return UpdatePlan{
+		Repository: github.Repository{Owner: "my-org", Name: "api", FullName: "my-org/api"},
+		Updates: []ActionUpdate{
+			{ActionRepo: "actions/checkout", CurrentVersion: "v3", TargetVersion: "v4", FilePath: ".github/workflows/ci.yml"},
+			{ActionRepo: "actions/setup-node", CurrentVersion: "v3", TargetVersion: "v4", FilePath: ".github/workflows/build.yml"},
+		},
+		Snapshot: map[string]string{".github/workflows/ci.yml": "aaa", ".github/workflows/build.yml": "bbb"},
+	}
+}
+
+func TestFindBotPullRequests(t *testing.T) {
+	client := &fakeOpenPullRequestLister{pulls: []github.PullRequestInfo{
+		{Number: 7, Title: "Bump actions/checkout from 3 to 4", Author: "dependabot[bot]", HeadBranch: "dependabot/github_actions/actions/checkout-4"},
+		{Number: 8, Title: "Update actions/checkout to v4", Author: "octocat", HeadBranch: "octocat/checkout"},
+		{Number: 9, Title: "Update actions/cache action to v4", Author: "renovate[bot]", HeadBranch: "renovate/actions-cache-4.x"},
+	}}
+
+	pulls, err := FindBotPullRequests(client, coexistPlan())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// A person's pull request and a bot pull request for another action are left alone
+	if len(pulls) != 1 || pulls[0].ActionRepo != "actions/checkout" || pulls[0].PullRequest.Number != 7 {
+		t.Errorf("Expected only Dependabot's checkout pull request, got %+v", pulls)
+	}
+}
+
+func TestCoexist_Skip(t *testing.T) {
+	pulls := []BotPullRequest{{ActionRepo: "actions/checkout", PullRequest: github.PullRequestInfo{Number: 7}}}
+	plan := Coexist(coexistPlan(), pulls, CoexistSkip)
+
+	if len(plan.Updates) != 1 || plan.Updates[0].ActionRepo != "actions/setup-node" {
+		t.Errorf("Expected only the setup-node update, got %+v", plan.Updates)
+	}
+	if _, ok := plan.Snapshot[".github/workflows/ci.yml"]; ok || len(plan.Snapshot) != 1 {
+		t.Errorf("Expected the untouched ci.yml dropped from the snapshot, got %v", plan.Snapshot)
+	}
+	if len(plan.Supersedes) != 0 {
+		t.Errorf("Expected nothing superseded when skipping, got %+v", plan.Supersedes)
+	}
+}
+
+func TestCoexist_Supersede(t *testing.T) {
+	pulls := []BotPullRequest{{
+		ActionRepo:  "actions/checkout",
+		PullRequest: github.PullRequestInfo{Number: 7, Title: "Bump actions/checkout from 3 to 4", Author: "dependabot[bot]", URL: "https://github.com/my-org/api/pull/7"},
+	}}
+	plan := Coexist(coexistPlan(), pulls, CoexistSupersede)
+
+	if len(plan.Updates) != 2 {
+		t.Errorf("Expected both updates kept, got %+v", plan.Updates)
+	}
+	if len(plan.Supersedes) != 1 {
+		t.Fatalf("Expected 1 superseded pull request, got %+v", plan.Supersedes)
+	}
+
+	creator := NewCreator(&github.Client{})
+	body := creator.generatePRBody(plan)
+	if !strings.Contains(body, "### 🔁 Supersedes") || !strings.Contains(body, "- #7 (actions/checkout) by dependabot[bot]") {
+		t.Errorf("Expected the superseded pull request listed in the body, got:\n%s", body)
+	}
+
+	comment := SupersedeComment(plan.Supersedes[0], "https://github.com/my-org/api/pull/42")
+	if !strings.Contains(comment, "https://github.com/my-org/api/pull/42") {
+		t.Errorf("Expected the comment to link the new pull request, got %q", comment)
+	}
+}
+
+func TestParseCoexistMode(t *testing.T) {
+	if mode, err := ParseCoexistMode(""); err != nil || mode != CoexistSkip {
+		t.Errorf("Expected skip by default, got %q, %v", mode, err)
+	}
+	if mode, err := ParseCoexistMode("supersede"); err != nil || mode != CoexistSupersede {
+		t.Errorf("Expected supersede, got %q, %v", mode, err)
+	}
+	if _, err := ParseCoexistMode("close"); err == nil {
+		t.Error("Expected an error for an unknown mode")
+	}
+}
@@ -0,0 +1,134 @@
+package pr
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// CommitMessagePresets are commit message templates named by --commit-message or a rule's commit_message
+var CommitMessagePresets = map[string]string{
+	// The pull request title, which is also the default
+	"title": `{{.Title}}`,
+	// Conventional Commits for dependency bumps, e.g. "chore(deps): bump actions/checkout to v4"
+	"conventional": `chore(deps): {{if eq .Count 1}}bump {{.Action}} to {{.To}}{{else}}bump {{.Count}} actions{{end}}`,
+	// Conventional Commits for CI changes, e.g. "ci: bump actions/checkout to v4"
+	"conventional-ci": `ci: {{if eq .Count 1}}bump {{.Action}} to {{.To}}{{else}}bump {{.Count}} actions{{end}}`,
+}
+
+// CommitMessageData is the data available to commit message templates
+type CommitMessageData struct {
+	Title      string // Title of the pull request
+	Repository string // "owner/name"
+	BaseBranch string // Branch the pull request targets
+	Count      int    // Number of distinct actions updated
+	Action     string // Updated action, or a comma-separated list when several actions are updated
+	From       string // Version updated from, when every update starts from the same version
+	To         string // Version updated to, when every update moves to the same version
+	Updates    []ActionUpdate
+}
+
+// ParseCommitMessage parses a commit message template, or the template of a preset named by value
+func ParseCommitMessage(value string) (*template.Template, error) {
+	if preset, ok := CommitMessagePresets[value]; ok {
+		value = preset
+	}
+	tmpl, err := template.New("commit-message").Funcs(TemplateFuncs).Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid commit message template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// SetCommitMessage sets the commit message template used when no rule of a plan sets its own
+func (c *Creator) SetCommitMessage(tmpl *template.Template) {
+	c.commitMessage = tmpl
+}
+
+// generateCommitMessage returns the message of the commit pushed for a plan
+// A template set by the rules of every update in the plan wins over the creator's, and without
+// either the pull request title is used. Templates that fail to render fall back the same way.
+func (c *Creator) generateCommitMessage(plan UpdatePlan, title string) string {
+	data := commitMessageData(plan, title)
+
+	if ruleTemplate := planCommitMessage(plan); ruleTemplate != "" {
+		tmpl, err := ParseCommitMessage(ruleTemplate)
+		if err == nil {
+			var message string
+			if message, err = renderCommitMessage(tmpl, data); err == nil {
+				return message
+			}
+		}
+		fmt.Printf("Warning: %s: ignoring the commit message of its rule: %v\n", plan.Repository.FullName, err)
+	}
+
+	if c.commitMessage != nil {
+		message, err := renderCommitMessage(c.commitMessage, data)
+		if err == nil {
+			return message
+		}
+		fmt.Printf("Warning: %s: ignoring the commit message template: %v\n", plan.Repository.FullName, err)
+	}
+	return title
+}
+
+// planCommitMessage returns the commit message template shared by the rules of every update, or ""
+// when any update's rule sets none or the rules disagree
+func planCommitMessage(plan UpdatePlan) string {
+	message := ""
+	for i, update := range plan.Updates {
+		if update.Issue.CommitMessage == "" || (i > 0 && update.Issue.CommitMessage != message) {
+			return ""
+		}
+		message = update.Issue.CommitMessage
+	}
+	return message
+}
+
+// commitMessageData summarizes a plan's updates for commit message templates
+func commitMessageData(plan UpdatePlan, title string) CommitMessageData {
+	data := CommitMessageData{
+		Title:      title,
+		Repository: plan.Repository.FullName,
+		BaseBranch: plan.TargetBranch(),
+		Updates:    plan.Updates,
+	}
+
+	actions := make(map[string]bool)
+	from := make(map[string]bool)
+	to := make(map[string]bool)
+	for _, update := range plan.Updates {
+		actions[update.ActionRepo] = true
+		from[update.CurrentVersion] = true
+		to[update.TargetVersion] = true
+	}
+	names := make([]string, 0, len(actions))
+	for name := range actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	data.Count = len(names)
+	data.Action = strings.Join(names, ", ")
+	if len(from) == 1 {
+		data.From = plan.Updates[0].CurrentVersion
+	}
+	if len(to) == 1 {
+		data.To = plan.Updates[0].TargetVersion
+	}
+	return data
+}
+
+// renderCommitMessage executes a template, rejecting messages without a subject line
+func renderCommitMessage(tmpl *template.Template, data CommitMessageData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	message := strings.TrimSpace(buf.String())
+	if message == "" {
+		return "", fmt.Errorf("the template rendered an empty message")
+	}
+	return message, nil
+}
@@ -0,0 +1,73 @@
+package pr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+func commitMessagePlan(updates ...ActionUpdate) UpdatePlan {
+	return UpdatePlan{
+		Repository: github.Repository{Owner: "my-org", Name: "api", FullName: "my-org/api", DefaultBranch: "main"},
+		Updates:    updates,
+	}
+}
+
+func TestGenerateCommitMessage_Presets(t *testing.T) {
+	checkout := ActionUpdate{FilePath: ".github/workflows/ci.yml", ActionRepo: "actions/checkout", CurrentVersion: "v3", TargetVersion: "v4"}
+	release := ActionUpdate{FilePath: ".github/workflows/release.yml", ActionRepo: "actions/checkout", CurrentVersion: "v3", TargetVersion: "v4"}
+	setupGo := ActionUpdate{FilePath: ".github/workflows/ci.yml", ActionRepo: "actions/setup-go", CurrentVersion: "v4", TargetVersion: "v5"}
+
+	tests := []struct {
+		preset   string
+		plan     UpdatePlan
+		expected string
+	}{
+		{"conventional", commitMessagePlan(checkout, release), "chore(deps): bump actions/checkout to v4"},
+		{"conventional", commitMessagePlan(checkout, setupGo), "chore(deps): bump 2 actions"},
+		{"conventional-ci", commitMessagePlan(checkout), "ci: bump actions/checkout to v4"},
+		{"title", commitMessagePlan(checkout), "PR title"},
+		{"{{.Repository}}: {{.Action}} {{.From}} -> {{.To}}", commitMessagePlan(checkout), "my-org/api: actions/checkout v3 -> v4"},
+	}
+	for _, test := range tests {
+		tmpl, err := ParseCommitMessage(test.preset)
+		if err != nil {
+			t.Fatalf("Expected %q to parse, got %v", test.preset, err)
+		}
+		creator := NewCreator(nil)
+		creator.SetCommitMessage(tmpl)
+		if message := creator.generateCommitMessage(test.plan, "PR title"); message != test.expected {
+			t.Errorf("Expected %q for %q, got %q", test.expected, test.preset, message)
+		}
+	}
+}
+
+func TestGenerateCommitMessage_RuleOverrides(t *testing.T) {
+	ruled := ActionUpdate{ActionRepo: "my-org/deploy", CurrentVersion: "v1", TargetVersion: "v2", Issue: output.ActionIssue{CommitMessage: "feat(deploy): move to {{.To}}"}}
+	unruled := ActionUpdate{ActionRepo: "actions/checkout", CurrentVersion: "v3", TargetVersion: "v4"}
+
+	tmpl, _ := ParseCommitMessage("conventional")
+	creator := NewCreator(nil)
+	creator.SetCommitMessage(tmpl)
+
+	if message := creator.generateCommitMessage(commitMessagePlan(ruled), "title"); message != "feat(deploy): move to v2" {
+		t.Errorf("Expected the rule's template, got %q", message)
+	}
+	if message := creator.generateCommitMessage(commitMessagePlan(ruled, unruled), "title"); message != "chore(deps): bump 2 actions" {
+		t.Errorf("Expected the global template when not every rule sets one, got %q", message)
+	}
+
+	broken := ruled
+	broken.Issue.CommitMessage = "{{.Missing"
+	if message := NewCreator(nil).generateCommitMessage(commitMessagePlan(broken), "title"); message != "title" {
+		t.Errorf("Expected the title for an invalid rule template, got %q", message)
+	}
+}
+
+func TestParseCommitMessage_Invalid(t *testing.T) {
+	if _, err := ParseCommitMessage("{{.Title"); err == nil || !strings.Contains(err.Error(), "invalid commit message template") {
+		t.Errorf("Expected an invalid template error, got %v", err)
+	}
+}
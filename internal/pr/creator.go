@@ -2,16 +2,28 @@ package pr
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"errors"
 	"fmt"
+	"os"
 	"regexp"
+	"sort"
 	"strings"
 	"text/template"
+	"time"
 
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/audit"
 	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/ledger"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/links"
 	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
 	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/patcher"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
 )
 
+// BranchPrefix starts the name of every branch created for a pull request
+const BranchPrefix = "actions-maintainer/"
+
 // parseMigrationTarget parses a migration target string (e.g., "new-org/action@v2")
 // Returns the repository and version parts, or empty strings if not a migration target
 func parseMigrationTarget(migrationTarget string) (string, string) {
@@ -31,11 +43,27 @@ func parseMigrationTarget(migrationTarget string) (string, string) {
 	return matches[1], matches[2]
 }
 
+// parseMigrationTargetPath returns the path inside the repository of a migration target
+// (e.g., ".github/workflows/new.yml" from "org/repo/.github/workflows/new.yml@v2"), or "" if none
+func parseMigrationTargetPath(migrationTarget string) string {
+	re := regexp.MustCompile(`^[^/@]+/[^/@]+/([^@]+)@.+$`)
+	matches := re.FindStringSubmatch(migrationTarget)
+	if len(matches) != 2 {
+		return ""
+	}
+	return matches[1]
+}
+
 // Creator handles creating pull requests for action updates
 type Creator struct {
 	githubClient *github.Client
 	patcher      *patcher.WorkflowPatcher
 	template     *template.Template
+	auditLog     *audit.Logger
+	ledger       *ledger.Ledger
+
+	commitMessage *template.Template // Commit message template; nil uses the pull request title
+	commitLayout  string             // How updates are split into commits; "" is CommitLayoutSingle
 }
 
 // UpdatePlan represents a plan to update actions in a repository
@@ -43,23 +71,103 @@ type Creator struct {
 // action updates for that repository. This ensures that all patches for
 // a repository are applied together in a single pull request.
 type UpdatePlan struct {
-	Repository github.Repository
-	Updates    []ActionUpdate // ALL updates for this repository
+	Repository      github.Repository `json:"repository"`
+	Updates         []ActionUpdate    `json:"updates"`                    // ALL updates for this repository
+	BaseBranch      string            `json:"base_branch,omitempty"`      // Branch the pull request targets; empty for the default branch
+	Files           []FilePlan        `json:"files,omitempty"`            // Coordinated edits to files outside .github/workflows made in the same pull request
+	Snapshot        map[string]string `json:"snapshot,omitempty"`         // Blob SHAs of the updated workflow files when they were scanned, by path
+	ScannedCommit   string            `json:"scanned_commit,omitempty"`   // Default branch commit the workflows were scanned at; new branches start from it
+	RequiredChecks  []AffectedCheck   `json:"required_checks,omitempty"`  // Required status checks of the target branch the updates may rename
+	ScriptCouplings []ScriptCoupling  `json:"script_couplings,omitempty"` // Run step scripts hardcoding versions or files the updates change
+	CodeOwners      []string          `json:"code_owners,omitempty"`      // Code owners of the changed files ("user" or "org/team"), requested after the action owners
+	MaxReviewers    int               `json:"max_reviewers,omitempty"`    // Most reviewers to request; 0 for no limit
+	Supersedes      []BotPullRequest  `json:"supersedes,omitempty"`       // Open Dependabot or Renovate pull requests for the same actions (--coexist-mode supersede)
+}
+
+// TargetBranch returns the branch the pull request targets
+func (p UpdatePlan) TargetBranch() string {
+	if p.BaseBranch != "" {
+		return p.BaseBranch
+	}
+	return p.Repository.DefaultBranch
+}
+
+// PriorityScore returns the highest priority score of the plan's updates
+func (p UpdatePlan) PriorityScore() int {
+	highest := 0
+	for _, update := range p.Updates {
+		if update.Issue.PriorityScore > highest {
+			highest = update.Issue.PriorityScore
+		}
+	}
+	return highest
+}
+
+// SortByPriority orders plans by their highest priority score, keeping the order of equal plans
+func SortByPriority(plans []UpdatePlan) {
+	sort.SliceStable(plans, func(i, j int) bool {
+		return plans[i].PriorityScore() > plans[j].PriorityScore()
+	})
+}
+
+// PlanHash returns a hash of the plan's repository, base branch, and update set. Identical plans get the
+// same hash on every run, whatever the order of their updates, while plans with different updates never collide.
+func (p UpdatePlan) PlanHash() string {
+	lines := make([]string, 0, len(p.Updates)+len(p.Files))
+	for _, update := range p.Updates {
+		lines = append(lines, strings.Join([]string{
+			update.FilePath,
+			update.ActionRepo,
+			update.WorkflowPath,
+			update.CurrentVersion,
+			update.TargetRepo,
+			update.TargetPath,
+			update.TargetVersion,
+		}, "|"))
+	}
+	for _, file := range p.Files {
+		for _, edit := range file.Edits {
+			lines = append(lines, strings.Join([]string{file.Path, edit.Find, edit.Replace}, "|"))
+		}
+	}
+	sort.Strings(lines)
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n%s\n", p.Repository.FullName, p.BaseBranch)
+	for _, line := range lines {
+		fmt.Fprintf(hash, "%s\n", line)
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// BranchName returns the head branch for the plan's pull request, named after a short plan hash
+func (p UpdatePlan) BranchName() string {
+	name := fmt.Sprintf("%supdate-actions-%s", BranchPrefix, p.PlanHash()[:12])
+	if p.BaseBranch != "" {
+		// Each base branch needs its own head branch
+		name += "-" + strings.ReplaceAll(p.BaseBranch, "/", "-")
+	}
+	return name
 }
 
 // ActionUpdate represents a single action update
 type ActionUpdate struct {
-	FilePath       string
-	ActionRepo     string
-	CurrentVersion string
-	TargetVersion  string
-	TargetRepo     string // Target repository for migrations (empty if same repo)
-	Issue          output.ActionIssue
+	FilePath       string             `json:"file_path"`
+	ActionRepo     string             `json:"action_repo"`
+	WorkflowPath   string             `json:"workflow_path,omitempty"` // Path within ActionRepo for reusable workflows and nested actions (e.g., ".github/workflows/ci.yml")
+	CurrentVersion string             `json:"current_version"`
+	TargetVersion  string             `json:"target_version"`
+	TargetRepo     string             `json:"target_repo,omitempty"`    // Target repository for migrations (empty if same repo)
+	TargetPath     string             `json:"target_path,omitempty"`    // Target path for path migrations (empty to keep WorkflowPath)
+	TargetComment  string             `json:"target_comment,omitempty"` // Version to record in a trailing pin comment (empty to derive from TargetVersion)
+	Issue          output.ActionIssue `json:"issue"`
 }
 
 // TemplateData represents the data available to PR body templates
 type TemplateData struct {
 	Repository        github.Repository
+	BaseBranch        string   // Branch the pull request targets
+	Reviewers         []string // Owners of the updated actions requested as reviewers ("user" or "org/team")
 	Updates           []ActionUpdate
 	UpdateCount       int
 	DeprecatedUpdates []ActionUpdate
@@ -67,6 +175,11 @@ type TemplateData struct {
 	MigrationUpdates  []ActionUpdate
 	SecurityUpdates   []ActionUpdate
 	OtherUpdates      []ActionUpdate
+	Files             []FilePlan         // Edits to files outside .github/workflows
+	RequiredChecks    []AffectedCheck    // Required status checks the updates may rename, blocking the merge
+	ScriptCouplings   []ScriptCoupling   // Run step scripts hardcoding versions or workflow files the updates change
+	Rules             []output.IssueRule // Rule ids and documentation of the issue types the updates fix
+	Supersedes        []BotPullRequest   // Dependabot or Renovate pull requests this one supersedes
 }
 
 // NewCreator creates a new PR creator
@@ -87,6 +200,56 @@ func NewCreatorWithTemplate(githubClient *github.Client, tmpl *template.Template
 	}
 }
 
+// SetAuditLog records the branches, files, and pull requests the creator writes in an audit log
+func (c *Creator) SetAuditLog(logger *audit.Logger) {
+	c.auditLog = logger
+}
+
+// SetLinks makes the link mappings available to the PR body template's link function
+func (c *Creator) SetLinks(mapping *links.Mapping) {
+	if c.template != nil {
+		c.template.Funcs(template.FuncMap{"link": linkFunc(mapping)})
+	}
+}
+
+// linkFunc returns the link template function of a mapping. It builds the named link for a
+// repository given as a Repository or an "owner/name" string.
+func linkFunc(mapping *links.Mapping) func(string, any) (string, error) {
+	return func(name string, repository any) (string, error) {
+		var target links.Target
+		switch repo := repository.(type) {
+		case github.Repository:
+			target = links.NewTarget(repo.FullName, "", repo.CustomProperties)
+		case *github.Repository:
+			target = links.NewTarget(repo.FullName, "", repo.CustomProperties)
+		case string:
+			target = links.NewTarget(repo, "", nil)
+		default:
+			return "", fmt.Errorf("link %q needs a repository or an \"owner/name\" string, got %T", name, repository)
+		}
+		return mapping.URL(name, target)
+	}
+}
+
+// SetLedger skips plans the ledger already has a pull request for, and records each pull request created
+func (c *Creator) SetLedger(l *ledger.Ledger) {
+	c.ledger = l
+}
+
+// UnrecordedError reports a pull request that was created but could not be recorded in the ledger
+type UnrecordedError struct {
+	PR  output.CreatedPR
+	Err error
+}
+
+func (e *UnrecordedError) Error() string {
+	return fmt.Sprintf("pull request %s was created but not recorded: %v", e.PR.URL, e.Err)
+}
+
+func (e *UnrecordedError) Unwrap() error {
+	return e.Err
+}
+
 // CreateUpdatePRs creates pull requests for action updates
 // This function creates exactly one PR per UpdatePlan, and since PlanUpdates
 // ensures one plan per repository, this guarantees one PR per repository.
@@ -99,64 +262,237 @@ func (c *Creator) CreateUpdatePRs(plans []UpdatePlan) ([]output.CreatedPR, error
 			continue
 		}
 
-		// Create a single PR that contains ALL updates for this repository
-		createdPR, err := c.createPRForPlan(plan)
+		createdPR, err := c.CreatePR(plan)
+		var unrecorded *UnrecordedError
+		if errors.As(err, &unrecorded) {
+			// Stop rather than risk duplicates on the next run
+			return append(createdPRs, createdPR), err
+		}
 		if err != nil {
 			fmt.Printf("Failed to create PR for %s: %v\n", plan.Repository.FullName, err)
 			continue
 		}
-
 		createdPRs = append(createdPRs, createdPR)
-		fmt.Printf("Created PR for %s with %d action updates\n", plan.Repository.FullName, len(plan.Updates))
 	}
 
 	return createdPRs, nil
 }
 
+// CreatePR creates the pull request for a single plan, returning the ledger's pull request when the plan
+// already has one. An *UnrecordedError is returned with the pull request when the ledger cannot record it.
+func (c *Creator) CreatePR(plan UpdatePlan) (output.CreatedPR, error) {
+	// A rerun after a partial failure must not open a second pull request for the same plan
+	var planHash string
+	if c.ledger != nil {
+		planHash = plan.PlanHash()
+		if entry := c.ledger.Lookup(plan.Repository.FullName, planHash); entry != nil {
+			fmt.Printf("Skipping %s (%s): pull request already created at %s according to the ledger\n", plan.Repository.FullName, plan.TargetBranch(), entry.PR.URL)
+			return entry.PR, nil
+		}
+	}
+
+	// Create a single PR that contains ALL updates for this repository
+	createdPR, err := c.createPRForPlan(plan)
+	if err != nil {
+		return output.CreatedPR{}, err
+	}
+	if c.ledger != nil {
+		if err := c.ledger.Record(planHash, createdPR, time.Now()); err != nil {
+			return createdPR, &UnrecordedError{PR: createdPR, Err: err}
+		}
+	}
+
+	fmt.Printf("Created PR for %s (%s) with %d action updates\n", plan.Repository.FullName, plan.TargetBranch(), len(plan.Updates))
+	return createdPR, nil
+}
+
+// branchCommit is the commit pushed to a pull request's head branch
+type branchCommit struct {
+	Branch    string
+	Commits   []layoutCommit // Commits pushed to the branch, in order
+	Parent    string         // Commit the branch starts from; empty for the tip of the base branch
+	Workflows []string       // Workflow files patched with the plan's updates
+	Files     []string       // Other files edited by the plan's rules
+	Generated []BranchFile   // Files written whole, such as the full update list of a truncated body
+}
+
+// planCommit lays out the commit for a plan's branch and the pull request body describing it
+// Bodies too long for GitHub are truncated, and the full update list is committed with the workflows.
+func (c *Creator) planCommit(plan UpdatePlan) (branchCommit, string) {
+	// Name the branch after the plan's contents, so re-runs reuse it and different plans never share one
+	commit := branchCommit{
+		Branch: plan.BranchName(),
+		Files:  plan.FilePaths(),
+	}
+	if plan.BaseBranch == "" {
+		commit.Parent = plan.ScannedCommit
+	}
+	seen := make(map[string]bool)
+	for _, update := range plan.Updates {
+		if !seen[update.FilePath] {
+			seen[update.FilePath] = true
+			commit.Workflows = append(commit.Workflows, update.FilePath)
+		}
+	}
+
+	body, overflow := c.fitBody(plan, commit.Branch, c.generatePRBody(plan))
+	if overflow != nil {
+		commit.Generated = append(commit.Generated, *overflow)
+	}
+	return commit, body
+}
+
 // createPRForPlan creates a pull request for a single update plan
 func (c *Creator) createPRForPlan(plan UpdatePlan) (output.CreatedPR, error) {
-	// Create a descriptive branch name
-	branchName := fmt.Sprintf("actions-maintainer/update-actions-%d", len(plan.Updates))
-
-	// Generate PR title and body
+	// Generate PR title, body, and the commit it describes
 	title := c.generatePRTitle(plan)
-	body := c.generatePRBody(plan)
+	commit, body := c.planCommit(plan)
+	commit.Commits = c.layoutCommits(plan, title, commit)
+	reviewers := PlanReviewers(plan)
 
 	// For now, we'll simulate the PR creation since we'd need to:
 	// 1. Create a new branch
 	// 2. Update the workflow files
 	// 3. Commit the changes
 	// 4. Create the PR
+	// 5. Request reviews from the owners of the updated actions
 
 	// This is a simplified implementation that would need additional
 	// GitHub API calls to actually create and push changes
 	fmt.Printf("Would create PR for %s:\n", plan.Repository.FullName)
-	fmt.Printf("Branch: %s\n", branchName)
+	fmt.Printf("Branch: %s\n", commit.Branch)
+	fmt.Printf("Base: %s\n", plan.TargetBranch())
+	if commit.Parent != "" {
+		fmt.Printf("Parent: %s\n", commit.Parent)
+	}
 	fmt.Printf("Title: %s\n", title)
+	if len(commit.Commits) == 1 {
+		fmt.Printf("Commit message: %s\n", commit.Commits[0].Message)
+	} else {
+		fmt.Printf("Commits:\n")
+		for _, layoutCommit := range commit.Commits {
+			fmt.Printf("  %s (%s)\n", layoutCommit.Message, strings.Join(layoutCommit.Paths, ", "))
+		}
+	}
+	fmt.Printf("Workflows: %s\n", strings.Join(commit.Workflows, ", "))
+	if len(commit.Files) > 0 {
+		fmt.Printf("Other files: %s\n", strings.Join(commit.Files, ", "))
+	}
+	attachment := ""
+	for _, file := range commit.Generated {
+		fmt.Printf("Generated file: %s (%d bytes)\n", file.Path, len(file.Content))
+		attachment = file.Path
+	}
+	if !reviewers.Empty() {
+		fmt.Printf("Reviewers: %s\n", strings.Join(reviewers.Names(plan.Repository.Owner), ", "))
+	}
 	fmt.Printf("Body: %s\n", body)
 
 	// Return simulated PR info
 	prNumber := 42 // Simulated PR number
 	prURL := fmt.Sprintf("https://github.com/%s/pull/%d", plan.Repository.FullName, prNumber)
+	for _, pull := range plan.Supersedes {
+		fmt.Printf("Comment on #%d: %s\n", pull.PullRequest.Number, SupersedeComment(pull, prURL))
+	}
+	c.auditCommit(plan, commit, prNumber, prURL)
 
 	return output.CreatedPR{
 		Repository:  plan.Repository.FullName,
+		BaseBranch:  plan.BaseBranch,
 		URL:         prURL,
+		Branch:      commit.Branch,
 		Title:       title,
 		Number:      prNumber,
 		UpdateCount: len(plan.Updates),
+		Reviewers:   reviewers.Names(plan.Repository.Owner),
+		Attachment:  attachment,
 	}, nil
 }
 
+// openPullRequest returns the open pull request from a plan's branch, or nil when there is none
+func (c *Creator) openPullRequest(plan UpdatePlan, branch string) *github.PullRequestInfo {
+	if c.githubClient == nil {
+		return nil
+	}
+	pulls, err := c.githubClient.ListBranchPullRequests(plan.Repository.Owner, plan.Repository.Name, branch)
+	if err != nil {
+		return nil
+	}
+	for _, pull := range pulls {
+		if pull.State == "open" {
+			return &pull
+		}
+	}
+	return nil
+}
+
+// auditCommit records the branch, files, and pull request written for a plan in the audit log
+// Re-runs push to the branch of a pull request that is still open, which is recorded as an update.
+func (c *Creator) auditCommit(plan UpdatePlan, commit branchCommit, number int, url string) {
+	if c.auditLog == nil {
+		return
+	}
+
+	existing := c.openPullRequest(plan, commit.Branch)
+	updated := existing != nil
+	if updated {
+		number, url = existing.Number, existing.URL
+	}
+
+	record := func(event audit.Event) {
+		event.Repository = plan.Repository.FullName
+		event.Branch = commit.Branch
+		if err := c.auditLog.Record(event); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: audit log: %v\n", err)
+		}
+	}
+
+	if !updated {
+		record(audit.Event{Action: audit.ActionBranchCreated, BaseBranch: plan.TargetBranch()})
+	}
+	for _, path := range commit.Workflows {
+		record(audit.Event{Action: audit.ActionFileModified, Path: path})
+	}
+	for _, path := range commit.Files {
+		record(audit.Event{Action: audit.ActionFileModified, Path: path})
+	}
+	for _, file := range commit.Generated {
+		record(audit.Event{Action: audit.ActionFileModified, Path: file.Path})
+	}
+
+	action := audit.ActionPROpened
+	if updated {
+		action = audit.ActionPRUpdated
+	}
+	record(audit.Event{Action: action, URL: url, PRNumber: number, BaseBranch: plan.TargetBranch()})
+}
+
 // generatePRTitle creates a descriptive title for the PR
 func (c *Creator) generatePRTitle(plan UpdatePlan) string {
+	title := fmt.Sprintf("Update %d GitHub Actions to latest versions", len(plan.Updates))
 	if len(plan.Updates) == 1 {
 		update := plan.Updates[0]
-		return fmt.Sprintf("Update %s from %s to %s",
+		title = fmt.Sprintf("Update %s from %s to %s",
 			update.ActionRepo, update.CurrentVersion, update.TargetVersion)
+		if update.Issue.Insertion != nil {
+			title = fmt.Sprintf("Add required action %s@%s", update.ActionRepo, update.TargetVersion)
+		}
+		if remediation := update.Issue.Remediation; remediation != nil {
+			title = fmt.Sprintf("Remove banned action %s", update.ActionRepo)
+			if remediation.Action == "replace" {
+				title = fmt.Sprintf("Replace banned action %s with %s", update.ActionRepo, remediation.Replacement)
+			}
+		}
+		if update.Issue.Concurrency != nil {
+			title = fmt.Sprintf("Set concurrency policy of %s", update.FilePath)
+		}
 	}
 
-	return fmt.Sprintf("Update %d GitHub Actions to latest versions", len(plan.Updates))
+	// Distinguish pull requests for maintenance branches from the default branch's
+	if plan.BaseBranch != "" {
+		title = fmt.Sprintf("[%s] %s", plan.BaseBranch, title)
+	}
+	return title
 }
 
 // generatePRBody creates a detailed body for the PR
@@ -197,6 +533,8 @@ func (c *Creator) generatePRBodyFromTemplate(plan UpdatePlan) string {
 	// Prepare template data
 	data := TemplateData{
 		Repository:        plan.Repository,
+		BaseBranch:        plan.TargetBranch(),
+		Reviewers:         PlanReviewers(plan).Names(plan.Repository.Owner),
 		Updates:           plan.Updates,
 		UpdateCount:       len(plan.Updates),
 		DeprecatedUpdates: deprecatedUpdates,
@@ -204,6 +542,11 @@ func (c *Creator) generatePRBodyFromTemplate(plan UpdatePlan) string {
 		MigrationUpdates:  migrationUpdates,
 		SecurityUpdates:   securityUpdates,
 		OtherUpdates:      otherUpdates,
+		Files:             plan.Files,
+		RequiredChecks:    plan.RequiredChecks,
+		ScriptCouplings:   plan.ScriptCouplings,
+		Rules:             planRules(plan),
+		Supersedes:        plan.Supersedes,
 	}
 
 	// Execute template
@@ -218,15 +561,46 @@ func (c *Creator) generatePRBodyFromTemplate(plan UpdatePlan) string {
 
 // generateDefaultPRBody creates a detailed body for the PR using the default template
 func (c *Creator) generateDefaultPRBody(plan UpdatePlan) string {
+	return c.renderDefaultPRBody(plan, DefaultSectionLimit)
+}
+
+// renderDefaultPRBody renders the default body, listing at most sectionLimit updates per section
+// before collapsing the rest into a details block; a sectionLimit <= 0 lists every update
+func (c *Creator) renderDefaultPRBody(plan UpdatePlan, sectionLimit int) string {
 	var body strings.Builder
 
 	body.WriteString("## GitHub Actions Updates\n\n")
 	body.WriteString("This PR updates GitHub Actions to their latest recommended versions.\n\n")
 
+	// Required checks renamed by the updates block the merge, so they lead the body
+	if len(plan.RequiredChecks) > 0 {
+		body.WriteString("### ⚠️ Required Status Checks\n\n")
+		body.WriteString("The updated reusable workflows report these required status checks of `" + plan.TargetBranch() +
+			"`. If the called jobs were renamed, the checks will never report and this PR cannot merge until branch protection is updated.\n\n")
+		for _, check := range plan.RequiredChecks {
+			body.WriteString(fmt.Sprintf("- `%s`: job `%s` in `%s` calls `%s`\n", check.Check, check.Job, check.FilePath, check.Action))
+		}
+		body.WriteString("\n")
+	}
+
+	// Scripts assuming the old versions keep running them, or break, after the merge
+	if len(plan.ScriptCouplings) > 0 {
+		body.WriteString("### ⚠️ Scripts Coupled to Updated Versions\n\n")
+		body.WriteString("These run steps hardcode versions or workflow files this PR changes. Check whether they need updating too.\n\n")
+		for _, coupling := range plan.ScriptCouplings {
+			body.WriteString(fmt.Sprintf("- `%s` (%s): %s\n  `%s`\n", coupling.FilePath, coupling.Context, coupling.Message, strings.ReplaceAll(coupling.Line, "`", "'")))
+		}
+		body.WriteString("\n")
+	}
+
 	// Group updates by issue type
 	deprecatedUpdates := []ActionUpdate{}
 	outdatedUpdates := []ActionUpdate{}
 	migrationUpdates := []ActionUpdate{}
+	requiredUpdates := []ActionUpdate{}
+	bannedUpdates := []ActionUpdate{}
+	concurrencyUpdates := []ActionUpdate{}
+	alignmentUpdates := []ActionUpdate{}
 
 	for _, update := range plan.Updates {
 		switch update.Issue.IssueType {
@@ -236,47 +610,128 @@ func (c *Creator) generateDefaultPRBody(plan UpdatePlan) string {
 			outdatedUpdates = append(outdatedUpdates, update)
 		case "migration":
 			migrationUpdates = append(migrationUpdates, update)
+		case "missing-required-action":
+			requiredUpdates = append(requiredUpdates, update)
+		case "banned-action":
+			bannedUpdates = append(bannedUpdates, update)
+		case "missing-concurrency", "missing-cancel-in-progress":
+			concurrencyUpdates = append(concurrencyUpdates, update)
+		case IssueTypeVersionAlignment:
+			alignmentUpdates = append(alignmentUpdates, update)
 		}
 	}
 
-	// Migration updates section
-	if len(migrationUpdates) > 0 {
-		body.WriteString("### 🚀 Action Migrations\n\n")
-		for _, update := range migrationUpdates {
-			if update.TargetRepo != "" {
-				body.WriteString(fmt.Sprintf("- **%s**: `%s@%s` → `%s@%s`\n",
-					update.ActionRepo, update.ActionRepo, update.CurrentVersion, update.TargetRepo, update.TargetVersion))
-			} else {
-				body.WriteString(fmt.Sprintf("- **%s**: %s → %s\n",
-					update.ActionRepo, update.CurrentVersion, update.TargetVersion))
-			}
-			body.WriteString(fmt.Sprintf("  - **File**: `%s`\n", update.FilePath))
-			if update.Issue.Description != "" {
-				body.WriteString(fmt.Sprintf("  - **Reason**: %s\n", update.Issue.Description))
-			}
-			body.WriteString("\n")
+	// Banned actions section
+	writeUpdateSection(&body, "### 🚫 Banned Actions", bannedUpdates, sectionLimit, func(body *strings.Builder, update ActionUpdate) {
+		action := joinRefPath(update.ActionRepo, update.WorkflowPath) + "@" + update.CurrentVersion
+		if update.Issue.Remediation.Action == "replace" {
+			body.WriteString(fmt.Sprintf("- **%s**: replaced with `%s`\n", action, update.Issue.Remediation.Replacement))
+		} else {
+			body.WriteString(fmt.Sprintf("- **%s**: removed\n", action))
 		}
-	}
+		body.WriteString(fmt.Sprintf("  - **File**: `%s`\n", update.FilePath))
+		if update.Issue.Description != "" {
+			body.WriteString(fmt.Sprintf("  - **Reason**: %s\n", update.Issue.Description))
+		}
+		body.WriteString("\n")
+	})
+
+	// Required actions section
+	writeUpdateSection(&body, "### 🛡️ Required Actions", requiredUpdates, sectionLimit, func(body *strings.Builder, update ActionUpdate) {
+		body.WriteString(fmt.Sprintf("- **%s**: added at %s\n",
+			joinRefPath(update.ActionRepo, update.WorkflowPath), update.TargetVersion))
+		body.WriteString(fmt.Sprintf("  - **File**: `%s` (%s)\n", update.FilePath, update.Issue.Context))
+		if update.Issue.Description != "" {
+			body.WriteString(fmt.Sprintf("  - **Reason**: %s\n", update.Issue.Description))
+		}
+		body.WriteString("\n")
+	})
 
-	// Deprecated updates section
-	if len(deprecatedUpdates) > 0 {
-		body.WriteString("### ⚠️ Deprecated Version Updates\n\n")
-		for _, update := range deprecatedUpdates {
+	// Migration updates section
+	writeUpdateSection(&body, "### 🚀 Action Migrations", migrationUpdates, sectionLimit, func(body *strings.Builder, update ActionUpdate) {
+		if update.TargetRepo != "" {
+			body.WriteString(fmt.Sprintf("- **%s**: `%s@%s` → `%s@%s`\n",
+				update.ActionRepo, update.ActionRepo, update.CurrentVersion, update.TargetRepo, update.TargetVersion))
+		} else {
 			body.WriteString(fmt.Sprintf("- **%s**: %s → %s\n",
 				update.ActionRepo, update.CurrentVersion, update.TargetVersion))
-			body.WriteString(fmt.Sprintf("  - **File**: `%s`\n\n", update.FilePath))
 		}
-	}
+		body.WriteString(fmt.Sprintf("  - **File**: `%s`\n", update.FilePath))
+		if update.Issue.Description != "" {
+			body.WriteString(fmt.Sprintf("  - **Reason**: %s\n", update.Issue.Description))
+		}
+		body.WriteString("\n")
+	})
+
+	// Deprecated updates section
+	writeUpdateSection(&body, "### ⚠️ Deprecated Version Updates", deprecatedUpdates, sectionLimit, writeVersionUpdate)
 
 	// Outdated updates section
-	if len(outdatedUpdates) > 0 {
-		body.WriteString("### 📊 Version Updates\n\n")
-		for _, update := range outdatedUpdates {
-			body.WriteString(fmt.Sprintf("- **%s**: %s → %s\n",
-				update.ActionRepo, update.CurrentVersion, update.TargetVersion))
-			body.WriteString(fmt.Sprintf("  - **File**: `%s`\n\n", update.FilePath))
+	writeUpdateSection(&body, "### 📊 Version Updates", outdatedUpdates, sectionLimit, writeVersionUpdate)
+
+	// Version alignment section
+	writeUpdateSection(&body, "### 🧭 Version Alignment", alignmentUpdates, sectionLimit, func(body *strings.Builder, update ActionUpdate) {
+		body.WriteString(fmt.Sprintf("- **%s**: %s → %s\n",
+			joinRefPath(update.ActionRepo, update.WorkflowPath), update.CurrentVersion, update.TargetVersion))
+		body.WriteString(fmt.Sprintf("  - **File**: `%s`\n", update.FilePath))
+		if update.Issue.Description != "" {
+			body.WriteString(fmt.Sprintf("  - **Reason**: %s\n", update.Issue.Description))
+		}
+		body.WriteString("\n")
+	})
+
+	// with: keys changed by schema transformations, so reviewers need not read the YAML diff
+	var transformedUpdates []ActionUpdate
+	for _, update := range plan.Updates {
+		if len(update.InputChanges()) > 0 {
+			transformedUpdates = append(transformedUpdates, update)
 		}
 	}
+	writeUpdateSection(&body, "### 🔧 Input Changes", transformedUpdates, sectionLimit, writeInputChanges)
+
+	// Concurrency policy section
+	writeUpdateSection(&body, "### 🚦 Concurrency", concurrencyUpdates, sectionLimit, func(body *strings.Builder, update ActionUpdate) {
+		body.WriteString(fmt.Sprintf("- **%s**: group `%s`, cancel-in-progress: %t\n",
+			update.FilePath, update.Issue.Concurrency.Group, update.Issue.Concurrency.CancelInProgress))
+		if update.Issue.Description != "" {
+			body.WriteString(fmt.Sprintf("  - **Reason**: %s\n", update.Issue.Description))
+		}
+		body.WriteString("\n")
+	})
+
+	// Bot pull requests for the same actions, cross-linked so they can be closed
+	if len(plan.Supersedes) > 0 {
+		body.WriteString("### 🔁 Supersedes\n\n")
+		for _, pull := range plan.Supersedes {
+			body.WriteString(fmt.Sprintf("- #%d (%s) by %s\n", pull.PullRequest.Number, pull.ActionRepo, pull.PullRequest.Author))
+		}
+		body.WriteString("\n")
+	}
+
+	// Coordinated edits to other files
+	if len(plan.Files) > 0 {
+		body.WriteString("### 📄 Related File Changes\n\n")
+		for _, file := range plan.Files {
+			body.WriteString(fmt.Sprintf("- `%s`\n", file.Path))
+			for _, edit := range file.Edits {
+				if edit.Reason != "" {
+					body.WriteString(fmt.Sprintf("  - **%s**: %s\n", edit.Action, edit.Reason))
+				} else {
+					body.WriteString(fmt.Sprintf("  - **%s**\n", edit.Action))
+				}
+			}
+			body.WriteString("\n")
+		}
+	}
+
+	// Documentation of what each rule means and how to remediate it
+	if rules := planRules(plan); len(rules) > 0 {
+		body.WriteString("### 📖 Rules\n\n")
+		for _, rule := range rules {
+			body.WriteString(fmt.Sprintf("- [`%s` %s](%s)\n", rule.ID, rule.IssueType, rule.DocsURL))
+		}
+		body.WriteString("\n")
+	}
 
 	body.WriteString("### Benefits of staying up to date\n\n")
 	body.WriteString("- ✅ Improved performance\n")
@@ -292,6 +747,44 @@ func (c *Creator) generateDefaultPRBody(plan UpdatePlan) string {
 	return body.String()
 }
 
+// planRules returns the rules of the issues a plan fixes
+func planRules(plan UpdatePlan) []output.IssueRule {
+	issues := make([]output.ActionIssue, 0, len(plan.Updates))
+	for _, update := range plan.Updates {
+		if update.Issue.IssueType != "" {
+			issues = append(issues, update.Issue)
+		}
+	}
+	return output.IssueRules(issues)
+}
+
+// writeUpdateSection writes a heading and its updates, collapsing those past the limit into a details block
+func writeUpdateSection(body *strings.Builder, heading string, updates []ActionUpdate, limit int, write func(*strings.Builder, ActionUpdate)) {
+	if len(updates) == 0 {
+		return
+	}
+
+	body.WriteString(heading + "\n\n")
+	for _, update := range limitUpdates(limit, updates) {
+		write(body, update)
+	}
+
+	if rest := overflowUpdates(limit, updates); len(rest) > 0 {
+		body.WriteString(fmt.Sprintf("<details>\n<summary>%d more</summary>\n\n", len(rest)))
+		for _, update := range rest {
+			write(body, update)
+		}
+		body.WriteString("</details>\n\n")
+	}
+}
+
+// writeVersionUpdate writes a version bump of an action in the default body
+func writeVersionUpdate(body *strings.Builder, update ActionUpdate) {
+	body.WriteString(fmt.Sprintf("- **%s**: %s → %s\n",
+		update.ActionRepo, update.CurrentVersion, update.TargetVersion))
+	body.WriteString(fmt.Sprintf("  - **File**: `%s`\n\n", update.FilePath))
+}
+
 // PlanUpdates creates update plans from scan results
 // This function ensures that all patches for a repository are batched into a single UpdatePlan.
 // This is critical to ensure that when PRs are created, all related patches are applied together
@@ -306,10 +799,11 @@ func PlanUpdates(repositories []output.RepositoryResult) []UpdatePlan {
 
 		plan := UpdatePlan{
 			Repository: github.Repository{
-				Owner:         extractOwner(repo.FullName),
-				Name:          repo.Name,
-				FullName:      repo.FullName,
-				DefaultBranch: repo.DefaultBranch,
+				Owner:            extractOwner(repo.FullName),
+				Name:             repo.Name,
+				FullName:         repo.FullName,
+				DefaultBranch:    repo.DefaultBranch,
+				CustomProperties: repo.CustomProperties,
 			},
 			Updates: []ActionUpdate{},
 		}
@@ -317,7 +811,11 @@ func PlanUpdates(repositories []output.RepositoryResult) []UpdatePlan {
 		// Collect ALL issues for this repository into a single plan
 		// This ensures patches are never split across multiple PRs for the same repository
 		for _, issue := range repo.Issues {
-			var targetVersion, targetRepo string
+			if issue.ReportOnly {
+				continue // Rules with report_only leave the fix to a person
+			}
+
+			var targetVersion, targetRepo, targetPath string
 
 			// Handle migration cases
 			if issue.IssueType == "migration" && issue.MigrationTarget != "" {
@@ -326,9 +824,12 @@ func PlanUpdates(repositories []output.RepositoryResult) []UpdatePlan {
 				if targetRepo == "" || targetVersion == "" {
 					continue // Skip malformed migration targets
 				}
+				if issue.WorkflowPath != "" {
+					targetPath = parseMigrationTargetPath(issue.MigrationTarget)
+				}
 			} else {
-				// Handle regular version updates
-				if issue.SuggestedVersion == "" {
+				// Handle regular version updates; banned actions and concurrency fixes need no target version
+				if issue.SuggestedVersion == "" && issue.Remediation == nil && issue.Concurrency == nil {
 					continue // Skip issues without suggested fixes
 				}
 				targetVersion = issue.SuggestedVersion
@@ -338,9 +839,12 @@ func PlanUpdates(repositories []output.RepositoryResult) []UpdatePlan {
 			update := ActionUpdate{
 				FilePath:       issue.FilePath,
 				ActionRepo:     issue.Repository,
+				WorkflowPath:   issue.WorkflowPath,
 				CurrentVersion: issue.CurrentVersion,
 				TargetVersion:  targetVersion,
 				TargetRepo:     targetRepo,
+				TargetPath:     targetPath,
+				TargetComment:  issue.SuggestedPinComment,
 				Issue:          issue,
 			}
 
@@ -348,6 +852,9 @@ func PlanUpdates(repositories []output.RepositoryResult) []UpdatePlan {
 		}
 
 		if len(plan.Updates) > 0 {
+			// Files such as dependabot.yml or README badges change in the same pull request
+			plan.Files = PlanFileEdits(plan.Updates)
+			plan.Snapshot, plan.ScannedCommit = snapshotOf(repo, plan.Updates)
 			plans = append(plans, plan)
 		}
 	}
@@ -376,47 +883,203 @@ func UpdateWorkflowContent(content string, updates []ActionUpdate) string {
 	updatedContent := content
 
 	for _, update := range updates {
+		// Inserted and remediated actions are edited as whole steps rather than references
+		if update.Issue.Insertion != nil || update.Issue.Remediation != nil || update.Issue.Concurrency != nil {
+			continue
+		}
+
 		// Create pattern to match the action reference
-		oldRef := fmt.Sprintf("%s@%s", update.ActionRepo, update.CurrentVersion)
+		oldRef := fmt.Sprintf("%s@%s", joinRefPath(update.ActionRepo, update.WorkflowPath), update.CurrentVersion)
 
-		var newRef string
+		// Migrations may change the repository, the path within it, or both
+		targetRepo := update.ActionRepo
 		if update.TargetRepo != "" {
-			// Migration case: change repository and version
-			newRef = fmt.Sprintf("%s@%s", update.TargetRepo, update.TargetVersion)
-		} else {
-			// Regular version update: same repository, new version
-			newRef = fmt.Sprintf("%s@%s", update.ActionRepo, update.TargetVersion)
+			targetRepo = update.TargetRepo
 		}
-
-		// Use regex to safely replace action references
-		pattern := regexp.MustCompile(regexp.QuoteMeta(oldRef))
-		updatedContent = pattern.ReplaceAllString(updatedContent, newRef)
+		targetPath := update.WorkflowPath
+		if update.TargetPath != "" {
+			targetPath = update.TargetPath
+		}
+		newRef := fmt.Sprintf("%s@%s", joinRefPath(targetRepo, targetPath), update.TargetVersion)
+
+		// Use regex to safely replace action references, keeping any trailing pin comment in sync
+		pattern := regexp.MustCompile(regexp.QuoteMeta(oldRef) + `(["']?)([ \t]+#[^\n]*)?`)
+		updatedContent = pattern.ReplaceAllStringFunc(updatedContent, func(match string) string {
+			groups := pattern.FindStringSubmatch(match)
+			return newRef + groups[1] + syncPinComment(groups[2], update)
+		})
 	}
 
 	return updatedContent
 }
 
+// joinRefPath appends a path within a repository to the repository name, if any
+func joinRefPath(repository, path string) string {
+	if path == "" {
+		return repository
+	}
+	return repository + "/" + path
+}
+
+// syncPinComment rewrites the version in a trailing "# vX" comment to match the updated ref
+// Comments that name no version are preserved as-is. When the new ref is a SHA and the target
+// tag is unknown, the stale version comment is dropped rather than left misleading.
+func syncPinComment(comment string, update ActionUpdate) string {
+	commentVersion := ""
+	if comment != "" {
+		commentVersion = workflow.PinCommentVersion(comment)
+	}
+
+	replacement := update.TargetComment
+	if replacement == "" && !isCommitSHA(update.TargetVersion) {
+		replacement = update.TargetVersion
+	}
+
+	switch {
+	case comment == "" && update.TargetComment != "":
+		// Annotate new SHA pins with the tag they correspond to
+		return " # " + update.TargetComment
+	case commentVersion == "":
+		return comment
+	case replacement == "":
+		return ""
+	default:
+		return strings.Replace(comment, commentVersion, replacement, 1)
+	}
+}
+
+// isCommitSHA reports whether a ref looks like a full or abbreviated commit SHA
+func isCommitSHA(ref string) bool {
+	if len(ref) < 7 || len(ref) > 40 {
+		return false
+	}
+	for _, char := range ref {
+		if !((char >= '0' && char <= '9') || (char >= 'a' && char <= 'f') || (char >= 'A' && char <= 'F')) {
+			return false
+		}
+	}
+	return true
+}
+
 // UpdateWorkflowContentWithTransformations updates workflow content with both version changes and schema patches
 func (c *Creator) UpdateWorkflowContentWithTransformations(content string, updates []ActionUpdate) (string, []string, error) {
+	return PatchWorkflowContent(c.patcher, content, updates)
+}
+
+// PatchWorkflowContent applies schema patches and version updates to workflow content using the given patcher
+// The result is validated; if the rewrite introduced problems, the original content is returned with an
+// error wrapping workflow.ErrInvalidWorkflow, so a broken file is never committed.
+func PatchWorkflowContent(wp *patcher.WorkflowPatcher, content string, updates []ActionUpdate) (string, []string, error) {
+	return PatchWorkflowContentWithValidation(wp, content, updates, nil)
+}
+
+// PatchWorkflowContentWithValidation patches workflow content as PatchWorkflowContent does, validating
+// the result with the given checks
+func PatchWorkflowContentWithValidation(wp *patcher.WorkflowPatcher, content string, updates []ActionUpdate, validation *workflow.ValidationConfig) (string, []string, error) {
+	// Patch with LF line endings and restore CRLF afterwards so Windows checkouts keep their line endings
+	original := content
+	crlf := strings.Contains(content, "\r\n")
+	if crlf {
+		content = strings.ReplaceAll(content, "\r\n", "\n")
+	}
+	unpatched := content
+
+	// Insert missing required actions before updating existing references
+	var stepChanges []string
+	for _, update := range updates {
+		insertion := update.Issue.Insertion
+		if insertion == nil {
+			continue
+		}
+		inserted, change, err := wp.InsertAction(content, patcher.ActionInsertion{
+			Uses: joinRefPath(update.ActionRepo, update.WorkflowPath) + "@" + update.TargetVersion,
+			Job:  insertion.Job,
+			With: insertion.With,
+		})
+		if err != nil {
+			return original, nil, fmt.Errorf("failed to insert %s: %w", update.ActionRepo, err)
+		}
+		content = inserted
+		stepChanges = append(stepChanges, change)
+	}
+
+	// Remove or replace banned actions; every call of an action at a version is remediated at once
+	remediated := make(map[string]bool)
+	for _, update := range updates {
+		remediation := update.Issue.Remediation
+		uses := joinRefPath(update.ActionRepo, update.WorkflowPath) + "@" + update.CurrentVersion
+		if remediation == nil || remediated[uses] {
+			continue
+		}
+		remediated[uses] = true
+		updated, changes, err := wp.RemediateAction(content, patcher.ActionRemediation{
+			Uses:        uses,
+			Remove:      remediation.Action == "remove",
+			Replacement: remediation.Replacement,
+			WithMapping: remediation.WithMapping,
+			With:        remediation.With,
+		})
+		if err != nil {
+			return original, nil, fmt.Errorf("failed to remediate %s: %w", uses, err)
+		}
+		content = updated
+		stepChanges = append(stepChanges, changes...)
+	}
+
+	// Add concurrency blocks flagged by the hygiene checks
+	for _, update := range updates {
+		concurrency := update.Issue.Concurrency
+		if concurrency == nil {
+			continue
+		}
+		updated, change, err := wp.SetConcurrency(content, patcher.ConcurrencyBlock{
+			Group:            concurrency.Group,
+			CancelInProgress: concurrency.CancelInProgress,
+		})
+		if err != nil {
+			return original, nil, fmt.Errorf("failed to set concurrency: %w", err)
+		}
+		content = updated
+		if change != "" {
+			stepChanges = append(stepChanges, change)
+		}
+	}
+
 	// Convert ActionUpdate to patcher.ActionVersionUpdate
-	patcherUpdates := make([]patcher.ActionVersionUpdate, len(updates))
-	for i, update := range updates {
-		patcherUpdates[i] = patcher.ActionVersionUpdate{
-			ActionRepo:  update.ActionRepo,
-			FromVersion: update.CurrentVersion,
-			ToVersion:   update.TargetVersion,
-			FilePath:    update.FilePath,
+	patcherUpdates := make([]patcher.ActionVersionUpdate, 0, len(updates))
+	for _, update := range updates {
+		if update.Issue.Insertion != nil || update.Issue.Remediation != nil || update.Issue.Concurrency != nil {
+			continue
 		}
+		patcherUpdates = append(patcherUpdates, patcher.ActionVersionUpdate{
+			ActionRepo:   update.ActionRepo,
+			FromVersion:  update.CurrentVersion,
+			ToVersion:    update.TargetVersion,
+			ToActionRepo: update.TargetRepo,
+			FromPath:     update.WorkflowPath,
+			ToPath:       update.TargetPath,
+			FilePath:     update.FilePath,
+		})
 	}
 
 	// Apply patches
-	updatedContent, changes, err := c.patcher.PatchWorkflowContent(content, patcherUpdates)
+	updatedContent, changes, err := wp.PatchWorkflowContent(content, patcherUpdates)
 	if err != nil {
-		return content, nil, fmt.Errorf("failed to apply patches: %w", err)
+		return original, nil, fmt.Errorf("failed to apply patches: %w", err)
 	}
 
 	// Update version references
 	finalContent := UpdateWorkflowContent(updatedContent, updates)
+	changes = append(stepChanges, changes...)
+
+	// Problems the file already had are not the rewrite's fault
+	if problems := workflow.NewProblemsWithConfig(unpatched, finalContent, validation); len(problems) > 0 {
+		return original, nil, fmt.Errorf("%w after update: %s", workflow.ErrInvalidWorkflow, strings.Join(problems, "; "))
+	}
+
+	if crlf {
+		finalContent = strings.ReplaceAll(finalContent, "\n", "\r\n")
+	}
 
 	return finalContent, changes, nil
 }
@@ -434,7 +1097,10 @@ func validateBatchingInvariant(repositories []output.RepositoryResult, plans []U
 	for _, repo := range repositories {
 		hasFixableIssues := false
 		for _, issue := range repo.Issues {
-			if issue.SuggestedVersion != "" {
+			if issue.ReportOnly {
+				continue
+			}
+			if issue.SuggestedVersion != "" || issue.Remediation != nil || issue.Concurrency != nil {
 				totalFixableIssues++
 				hasFixableIssues = true
 			}
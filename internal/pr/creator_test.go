@@ -1,13 +1,21 @@
 package pr
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 	"text/template"
 
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/audit"
 	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/ledger"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/links"
 	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/patcher"
 )
 
 // TestPlanUpdates_BatchesAllRepositoryPatches tests that all patches for a repository are batched into a single plan
@@ -180,6 +188,40 @@ func TestPlanUpdates_SkipsIssuesWithoutSuggestedVersions(t *testing.T) {
 	}
 }
 
+// TestPlanUpdates_SkipsReportOnlyIssues tests that issues of report-only rules are never planned
+func TestPlanUpdates_SkipsReportOnlyIssues(t *testing.T) {
+	repositories := []output.RepositoryResult{
+		{
+			Name:          "test-repo",
+			FullName:      "testowner/test-repo",
+			DefaultBranch: "main",
+			Issues: []output.ActionIssue{
+				{Repository: "actions/checkout", CurrentVersion: "v3", SuggestedVersion: "v4", FilePath: ".github/workflows/ci.yml", IssueType: "outdated"},
+				{Repository: "old-org/deploy", CurrentVersion: "v2", FilePath: ".github/workflows/ci.yml", IssueType: "migration", MigrationTarget: "new-org/deploy@v1", ReportOnly: true},
+			},
+		},
+		{
+			Name:          "other-repo",
+			FullName:      "testowner/other-repo",
+			DefaultBranch: "main",
+			Issues: []output.ActionIssue{
+				{Repository: "old-org/deploy", CurrentVersion: "v2", FilePath: ".github/workflows/ci.yml", IssueType: "migration", MigrationTarget: "new-org/deploy@v1", ReportOnly: true},
+			},
+		},
+	}
+
+	plans := PlanUpdates(repositories)
+	if len(plans) != 1 {
+		t.Fatalf("Expected 1 plan, got %d", len(plans))
+	}
+	if len(plans[0].Updates) != 1 || plans[0].Updates[0].ActionRepo != "actions/checkout" {
+		t.Errorf("Expected only the checkout update, got %+v", plans[0].Updates)
+	}
+	if err := validateBatchingInvariant(repositories, plans); err != nil {
+		t.Errorf("Expected batching invariant to hold, got %v", err)
+	}
+}
+
 // TestPlanUpdates_HandlesDuplicateActionsAcrossFiles tests that the same action in multiple files is handled correctly
 func TestPlanUpdates_HandlesDuplicateActionsAcrossFiles(t *testing.T) {
 	repositories := []output.RepositoryResult{
@@ -768,6 +810,10 @@ func TestGeneratePRBodyWithoutTemplate(t *testing.T) {
 		t.Error("Action update details not found")
 	}
 
+	if !strings.Contains(body, "- [`AM001` outdated]("+output.DefaultDocsBaseURL+"#outdated)") {
+		t.Error("Rule documentation link not found")
+	}
+
 	if !strings.Contains(body, "This PR was automatically generated") {
 		t.Error("Footer not found")
 	}
@@ -1049,3 +1095,607 @@ func TestGenerateDefaultPRBody_WithMigrations(t *testing.T) {
 
 	t.Logf("Generated PR body:\n%s", body)
 }
+
+func TestUpdateWorkflowContent_SyncsPinComments(t *testing.T) {
+	content := `jobs:
+  test:
+    steps:
+      - uses: actions/checkout@b4ffde65f46336ab88eb53be808477a3936bae11 # v4.1.1
+      - uses: actions/setup-node@v3 # v3
+      - uses: actions/cache@v3 # keep in sync with runner image
+      - uses: actions/upload-artifact@a8a3f3ad30e3422c9c7b888a15615d19a852ae32 # v3.1.3
+`
+
+	updates := []ActionUpdate{
+		{
+			ActionRepo:     "actions/checkout",
+			CurrentVersion: "b4ffde65f46336ab88eb53be808477a3936bae11",
+			TargetVersion:  "11bd71901bbe5b1630ceea73d27597364c9af683",
+			TargetComment:  "v4.2.2",
+		},
+		{
+			ActionRepo:     "actions/setup-node",
+			CurrentVersion: "v3",
+			TargetVersion:  "v4",
+		},
+		{
+			ActionRepo:     "actions/cache",
+			CurrentVersion: "v3",
+			TargetVersion:  "v4",
+		},
+		{
+			// SHA target without a known tag: stale version comment is dropped
+			ActionRepo:     "actions/upload-artifact",
+			CurrentVersion: "a8a3f3ad30e3422c9c7b888a15615d19a852ae32",
+			TargetVersion:  "65c4c4a1ddee5b72f698fdd19549f0f0fb45cf08",
+		},
+	}
+
+	updatedContent := UpdateWorkflowContent(content, updates)
+
+	expected := []string{
+		"actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2\n",
+		"actions/setup-node@v4 # v4\n",
+		"actions/cache@v4 # keep in sync with runner image\n",
+		"actions/upload-artifact@65c4c4a1ddee5b72f698fdd19549f0f0fb45cf08\n",
+	}
+	for _, line := range expected {
+		if !strings.Contains(updatedContent, line) {
+			t.Errorf("Expected updated content to contain %q, got:\n%s", line, updatedContent)
+		}
+	}
+}
+
+// TestPlanUpdates_WithPathMigrations tests that reusable workflow path renames update the full uses reference
+func TestPlanUpdates_WithPathMigrations(t *testing.T) {
+	repositories := []output.RepositoryResult{
+		{
+			Name:     "api",
+			FullName: "my-org/api",
+			Issues: []output.ActionIssue{
+				{
+					Repository:      "org/workflows",
+					WorkflowPath:    ".github/workflows/old.yml",
+					CurrentVersion:  "v2",
+					MigrationTarget: "org/workflows/.github/workflows/new.yml@v3",
+					IssueType:       "migration",
+					FilePath:        ".github/workflows/ci.yml",
+				},
+				{
+					Repository:      "org/shared",
+					WorkflowPath:    ".github/workflows/deploy.yml",
+					CurrentVersion:  "v1",
+					MigrationTarget: "new-org/shared/.github/workflows/deploy.yml@v1",
+					IssueType:       "migration",
+					FilePath:        ".github/workflows/ci.yml",
+				},
+			},
+		},
+	}
+
+	plans := PlanUpdates(repositories)
+	if len(plans) != 1 || len(plans[0].Updates) != 2 {
+		t.Fatalf("Expected 1 plan with 2 updates, got %+v", plans)
+	}
+
+	rename := plans[0].Updates[0]
+	if rename.WorkflowPath != ".github/workflows/old.yml" || rename.TargetPath != ".github/workflows/new.yml" || rename.TargetRepo != "org/workflows" {
+		t.Errorf("Unexpected rename update: %+v", rename)
+	}
+
+	content := `jobs:
+  build:
+    uses: org/workflows/.github/workflows/old.yml@v2
+  deploy:
+    uses: org/shared/.github/workflows/deploy.yml@v1
+`
+	updated := UpdateWorkflowContent(content, plans[0].Updates)
+
+	if !strings.Contains(updated, "uses: org/workflows/.github/workflows/new.yml@v3") {
+		t.Errorf("Expected the workflow path to be renamed, got:\n%s", updated)
+	}
+	if !strings.Contains(updated, "uses: new-org/shared/.github/workflows/deploy.yml@v1") {
+		t.Errorf("Expected the workflow repository to be migrated with its path, got:\n%s", updated)
+	}
+}
+
+// TestPatchWorkflowContent_InsertsRequiredActions tests that required actions are inserted alongside version updates
+func TestPatchWorkflowContent_InsertsRequiredActions(t *testing.T) {
+	content := "on: push\r\njobs:\r\n  build:\r\n    runs-on: ubuntu-latest\r\n    steps:\r\n      - uses: actions/checkout@v3\r\n"
+
+	repo := output.RepositoryResult{
+		Name:     "api",
+		FullName: "my-org/api",
+		Issues: []output.ActionIssue{
+			{
+				Repository:       "step-security/harden-runner",
+				SuggestedVersion: "v2",
+				IssueType:        "missing-required-action",
+				Context:          "job:build",
+				FilePath:         ".github/workflows/ci.yml",
+				Insertion:        &output.RequiredInsertion{Scope: "job", Job: "build"},
+			},
+			{
+				Repository:       "actions/checkout",
+				CurrentVersion:   "v3",
+				SuggestedVersion: "v4",
+				IssueType:        "outdated",
+				FilePath:         ".github/workflows/ci.yml",
+			},
+		},
+	}
+	plans := PlanUpdates([]output.RepositoryResult{repo})
+	if len(plans) != 1 || len(plans[0].Updates) != 2 {
+		t.Fatalf("Expected one plan with 2 updates, got %+v", plans)
+	}
+
+	updated, changes, err := PatchWorkflowContent(patcher.NewWorkflowPatcher(), content, plans[0].Updates)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "on: push\r\njobs:\r\n  build:\r\n    runs-on: ubuntu-latest\r\n    steps:\r\n      - uses: step-security/harden-runner@v2\r\n      - uses: actions/checkout@v4\r\n"
+	if updated != expected {
+		t.Errorf("Expected:\n%q\ngot:\n%q", expected, updated)
+	}
+	if len(changes) != 1 || !strings.Contains(changes[0], "harden-runner") {
+		t.Errorf("Expected the insertion to be recorded as a change, got %v", changes)
+	}
+
+	body := NewCreator(nil).generateDefaultPRBody(plans[0])
+	if !strings.Contains(body, "Required Actions") || !strings.Contains(body, "job:build") {
+		t.Errorf("Expected the PR body to list the required action, got:\n%s", body)
+	}
+}
+
+// TestPatchWorkflowContent_RemediatesBannedActions tests that banned actions are removed or replaced as whole steps
+func TestPatchWorkflowContent_RemediatesBannedActions(t *testing.T) {
+	content := `on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v3
+      - uses: sketchy/cache@main
+      - uses: acme/upload@v1
+        with:
+          path: dist/
+      - uses: acme/upload@v1
+        with:
+          path: docs/
+`
+	issue := func(repository, version string, remediation *output.BanRemediation) output.ActionIssue {
+		return output.ActionIssue{
+			Repository:     repository,
+			CurrentVersion: version,
+			IssueType:      "banned-action",
+			FilePath:       ".github/workflows/ci.yml",
+			Remediation:    remediation,
+		}
+	}
+	replace := &output.BanRemediation{Action: "replace", Replacement: "my-org/upload@v2", WithMapping: map[string]string{"path": "files"}}
+	repo := output.RepositoryResult{
+		Name:     "api",
+		FullName: "my-org/api",
+		Issues: []output.ActionIssue{
+			issue("sketchy/cache", "main", &output.BanRemediation{Action: "remove"}),
+			issue("acme/upload", "v1", replace),
+			issue("acme/upload", "v1", replace),
+			issue("old/notify", "v3", nil),
+		},
+	}
+	plans := PlanUpdates([]output.RepositoryResult{repo})
+	if len(plans) != 1 || len(plans[0].Updates) != 3 {
+		t.Fatalf("Expected one plan with 3 updates, got %+v", plans)
+	}
+	if err := validateBatchingInvariant([]output.RepositoryResult{repo}, plans); err != nil {
+		t.Errorf("Expected removals to count as fixable issues: %v", err)
+	}
+
+	updated, changes, err := PatchWorkflowContent(patcher.NewWorkflowPatcher(), content, plans[0].Updates)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v3
+      - uses: my-org/upload@v2
+        with:
+          files: dist/
+      - uses: my-org/upload@v2
+        with:
+          files: docs/
+`
+	if updated != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, updated)
+	}
+	if len(changes) != 5 {
+		t.Errorf("Expected a removal and two replacements with renamed inputs, got %v", changes)
+	}
+
+	body := NewCreator(nil).generateDefaultPRBody(plans[0])
+	if !strings.Contains(body, "Banned Actions") || !strings.Contains(body, "sketchy/cache@main**: removed") {
+		t.Errorf("Expected the PR body to list the banned actions, got:\n%s", body)
+	}
+}
+
+// TestUpdatePlanBranchName tests that branch names are derived from the plan's contents
+func TestUpdatePlanBranchName(t *testing.T) {
+	checkout := ActionUpdate{FilePath: ".github/workflows/ci.yml", ActionRepo: "actions/checkout", CurrentVersion: "v3", TargetVersion: "v4"}
+	setupGo := ActionUpdate{FilePath: ".github/workflows/ci.yml", ActionRepo: "actions/setup-go", CurrentVersion: "v4", TargetVersion: "v5"}
+	repository := github.Repository{FullName: "my-org/api", DefaultBranch: "main"}
+
+	plan := UpdatePlan{Repository: repository, Updates: []ActionUpdate{checkout, setupGo}}
+	name := plan.BranchName()
+	if !regexp.MustCompile(`^actions-maintainer/update-actions-[0-9a-f]{12}$`).MatchString(name) {
+		t.Fatalf("Expected a hashed branch name, got %s", name)
+	}
+
+	reordered := UpdatePlan{Repository: repository, Updates: []ActionUpdate{setupGo, checkout}}
+	if reordered.BranchName() != name {
+		t.Errorf("Expected the same branch for the same updates in another order, got %s and %s", name, reordered.BranchName())
+	}
+
+	newer := setupGo
+	newer.TargetVersion = "v6"
+	if changed := (UpdatePlan{Repository: repository, Updates: []ActionUpdate{checkout, newer}}); changed.BranchName() == name {
+		t.Errorf("Expected a different branch for a different target version, got %s", name)
+	}
+	if other := (UpdatePlan{Repository: github.Repository{FullName: "my-org/web"}, Updates: plan.Updates}); other.BranchName() == name {
+		t.Errorf("Expected a different branch for another repository, got %s", name)
+	}
+
+	release := UpdatePlan{Repository: repository, Updates: plan.Updates, BaseBranch: "release/1.x"}
+	if !strings.HasSuffix(release.BranchName(), "-release-1.x") || strings.TrimSuffix(release.BranchName(), "-release-1.x") == name {
+		t.Errorf("Expected a distinct branch ending in the base branch, got %s", release.BranchName())
+	}
+}
+
+// largePlan returns a plan with n outdated updates, each in its own workflow
+func largePlan(n int) UpdatePlan {
+	plan := UpdatePlan{Repository: github.Repository{FullName: "my-org/monorepo", Owner: "my-org", DefaultBranch: "main"}}
+	for i := 0; i < n; i++ {
+		plan.Updates = append(plan.Updates, ActionUpdate{
+			FilePath:       fmt.Sprintf(".github/workflows/service-%04d.yml", i),
+			ActionRepo:     "actions/checkout",
+			CurrentVersion: "v3",
+			TargetVersion:  "v4",
+			Issue:          output.ActionIssue{IssueType: "outdated"},
+		})
+	}
+	return plan
+}
+
+// TestGenerateDefaultPRBody_CollapsesLongSections tests that updates past the section limit are collapsed
+func TestGenerateDefaultPRBody_CollapsesLongSections(t *testing.T) {
+	plan := largePlan(DefaultSectionLimit + 3)
+	body := NewCreator(nil).generateDefaultPRBody(plan)
+
+	if !strings.Contains(body, "<details>\n<summary>3 more</summary>") {
+		t.Fatalf("Expected the updates past the limit in a details block, got:\n%s", body)
+	}
+	before, after, _ := strings.Cut(body, "<summary>3 more</summary>")
+	if strings.Count(before, "service-") != DefaultSectionLimit || strings.Count(after, "service-") != 3 {
+		t.Errorf("Expected %d updates before the details block and 3 inside it, got %d and %d",
+			DefaultSectionLimit, strings.Count(before, "service-"), strings.Count(after, "service-"))
+	}
+}
+
+// TestPlanCommit_TruncatesLongBodies tests that bodies over GitHub's limit are cut and the full list committed
+func TestPlanCommit_TruncatesLongBodies(t *testing.T) {
+	plan := largePlan(2000)
+	creator := NewCreator(nil)
+	commit, body := creator.planCommit(plan)
+
+	if len(body) > MaxBodyLength {
+		t.Fatalf("Expected a body of at most %d characters, got %d", MaxBodyLength, len(body))
+	}
+	if !strings.Contains(body, "This description was truncated") {
+		t.Errorf("Expected a truncation notice in the body")
+	}
+	link := fmt.Sprintf("https://github.com/my-org/monorepo/blob/%s/%s", commit.Branch, OverflowFilePath)
+	if !strings.Contains(body, link) {
+		t.Errorf("Expected the body to link to %s", link)
+	}
+
+	// The cut falls on a line boundary, so no entry is left half written
+	head, _, _ := strings.Cut(body, "\n</details>")
+	full := creator.generatePRBody(plan)
+	if !strings.HasPrefix(full, head+"\n") {
+		t.Errorf("Expected the body to be cut after a complete line, got ...%s", head[len(head)-40:])
+	}
+
+	// The section's details block was cut open and must be closed before the notice
+	if strings.Count(body, "<details>") != strings.Count(body, "</details>") {
+		t.Errorf("Expected every details block to be closed, got %d open and %d closed",
+			strings.Count(body, "<details>"), strings.Count(body, "</details>"))
+	}
+
+	if len(commit.Generated) != 1 || commit.Generated[0].Path != OverflowFilePath {
+		t.Fatalf("Expected the full update list to be committed to %s, got %+v", OverflowFilePath, commit.Generated)
+	}
+	if content := commit.Generated[0].Content; !strings.Contains(content, "service-0000.yml") || !strings.Contains(content, "service-1999.yml") || strings.Contains(content, "<details>") {
+		t.Errorf("Expected the committed list to hold every update uncollapsed")
+	}
+	if len(commit.Workflows) != 2000 {
+		t.Errorf("Expected 2000 workflows in the commit, got %d", len(commit.Workflows))
+	}
+}
+
+// TestPlanCommit_KeepsShortBodies tests that bodies within the limit are left alone
+func TestPlanCommit_KeepsShortBodies(t *testing.T) {
+	plan := largePlan(3)
+	creator := NewCreator(nil)
+	commit, body := creator.planCommit(plan)
+
+	if body != creator.generatePRBody(plan) {
+		t.Errorf("Expected a short body to be unchanged")
+	}
+	if len(commit.Generated) != 0 {
+		t.Errorf("Expected no generated files for a short body, got %+v", commit.Generated)
+	}
+}
+
+// TestFitBody_ClosesDetailsCutOpen tests that details blocks opened before the cut are closed
+func TestFitBody_ClosesDetailsCutOpen(t *testing.T) {
+	plan := largePlan(1)
+	body := "<details>\n<summary>outer</summary>\n\n<details>\n" + strings.Repeat("- line\n", MaxBodyLength/7) + "</details>\n</details>\n"
+
+	fitted, overflow := NewCreator(nil).fitBody(plan, "actions-maintainer/update-actions-abc", body)
+	if overflow == nil || len(fitted) > MaxBodyLength {
+		t.Fatalf("Expected the body to be truncated to %d characters, got %d", MaxBodyLength, len(fitted))
+	}
+	head, _, _ := strings.Cut(fitted, "\n\n<details>\n<summary>⚠️")
+	if !strings.HasSuffix(head, "- line\n</details>\n</details>") {
+		t.Errorf("Expected both open details blocks closed after the last whole line, got ...%s", head[len(head)-40:])
+	}
+}
+
+// TestTemplateFuncs_LimitSections tests that templates can cap sections with limit and overflow
+func TestTemplateFuncs_LimitSections(t *testing.T) {
+	tmpl := template.Must(template.New("pr-body").Funcs(TemplateFuncs).Parse(
+		`{{range limit 2 .OutdatedUpdates}}{{.FilePath}} {{end}}|{{with overflow 2 .OutdatedUpdates}}{{len .}} more{{end}}|{{len (limit 0 .OutdatedUpdates)}}`))
+	body := NewCreatorWithTemplate(nil, tmpl).generatePRBody(largePlan(5))
+
+	expected := ".github/workflows/service-0000.yml .github/workflows/service-0001.yml |3 more|5"
+	if body != expected {
+		t.Errorf("Expected %q, got %q", expected, body)
+	}
+	if rest := overflowUpdates(10, largePlan(5).Updates); rest != nil {
+		t.Errorf("Expected no overflow under the limit, got %d updates", len(rest))
+	}
+}
+
+func TestGeneratePRBodyWithTemplate_IssueMetadata(t *testing.T) {
+	tmpl := template.Must(template.New("pr-body").Funcs(TemplateFuncs).Parse(
+		`{{range .Updates}}{{.ActionRepo}} fixes {{.Issue.Metadata.cve}}{{with .Issue.Metadata.runner_label}} on {{.}}{{end}}{{end}}`))
+
+	issue := output.ActionIssue{IssueType: "outdated", Severity: "high"}
+	issue.SetMetadata(output.MetadataCVE, "CVE-2025-30066")
+	plan := UpdatePlan{
+		Repository: github.Repository{FullName: "my-org/api", DefaultBranch: "main"},
+		Updates:    []ActionUpdate{{ActionRepo: "tj-actions/changed-files", CurrentVersion: "v45", TargetVersion: "v46", Issue: issue}},
+	}
+
+	body := NewCreatorWithTemplate(nil, tmpl).generatePRBody(plan)
+	if body != "tj-actions/changed-files fixes CVE-2025-30066" {
+		t.Errorf("Expected issue metadata in the body, got %q", body)
+	}
+}
+
+func TestGeneratePRBodyWithTemplate_Links(t *testing.T) {
+	tmpl := template.Must(template.New("pr-body").Funcs(TemplateFuncs).Parse(
+		`[Backstage]({{link "backstage" .Repository}}){{with link "grafana" .Repository}} [Grafana]({{.}}){{end}}`))
+	mapping, err := links.Parse(map[string]string{
+		"backstage": "https://backstage.example.com/catalog/default/component/{{.Name}}",
+		"grafana":   "https://grafana.example.com/d/{{.Properties.ProductId}}",
+	})
+	if err != nil {
+		t.Fatalf("links.Parse() returned error: %v", err)
+	}
+	creator := NewCreatorWithTemplate(nil, tmpl)
+	creator.SetLinks(mapping)
+
+	plan := UpdatePlan{
+		Repository: github.Repository{FullName: "my-org/api", Name: "api", DefaultBranch: "main", CustomProperties: map[string]string{"ProductId": "payments"}},
+		Updates:    []ActionUpdate{{ActionRepo: "actions/checkout", CurrentVersion: "v3", TargetVersion: "v4"}},
+	}
+	expected := "[Backstage](https://backstage.example.com/catalog/default/component/api) [Grafana](https://grafana.example.com/d/payments)"
+	if body := creator.generatePRBody(plan); body != expected {
+		t.Errorf("Expected %q, got %q", expected, body)
+	}
+
+	plan.Repository.CustomProperties = nil
+	if body := creator.generatePRBody(plan); body != "[Backstage](https://backstage.example.com/catalog/default/component/api)" {
+		t.Errorf("Expected no Grafana link without a ProductId, got %q", body)
+	}
+}
+
+func TestSortByPriority(t *testing.T) {
+	plan := func(name string, scores ...int) UpdatePlan {
+		p := UpdatePlan{Repository: github.Repository{FullName: name}}
+		for _, score := range scores {
+			p.Updates = append(p.Updates, ActionUpdate{Issue: output.ActionIssue{PriorityScore: score}})
+		}
+		return p
+	}
+	plans := []UpdatePlan{plan("my-org/a", 10, 20), plan("my-org/b"), plan("my-org/c", 5, 80), plan("my-org/d", 20)}
+	SortByPriority(plans)
+
+	var order []string
+	for _, p := range plans {
+		order = append(order, p.Repository.FullName)
+	}
+	if strings.Join(order, ",") != "my-org/c,my-org/a,my-org/d,my-org/b" {
+		t.Errorf("Expected plans by highest score, keeping ties in order, got %v", order)
+	}
+}
+
+// TestCreateUpdatePRs_AuditsWrites tests that the branch, files, and pull request of each plan are audited
+func TestCreateUpdatePRs_AuditsWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger, err := audit.Open(&audit.Config{Target: path, Actor: "octocat", Command: "create-pr"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	creator := NewCreator(nil)
+	creator.SetAuditLog(logger)
+
+	plans := []UpdatePlan{{
+		Repository: github.Repository{Owner: "my-org", Name: "api", FullName: "my-org/api", DefaultBranch: "main"},
+		Updates: []ActionUpdate{
+			{FilePath: ".github/workflows/ci.yml", ActionRepo: "actions/checkout", CurrentVersion: "v3", TargetVersion: "v4"},
+			{FilePath: ".github/workflows/release.yml", ActionRepo: "actions/checkout", CurrentVersion: "v3", TargetVersion: "v4"},
+		},
+	}}
+	createdPRs, err := creator.CreateUpdatePRs(plans)
+	if err != nil || len(createdPRs) != 1 {
+		t.Fatalf("Expected 1 PR, got %d (%v)", len(createdPRs), err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actions []string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var event audit.Event
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("Expected a JSON line, got %q", line)
+		}
+		if event.Repository != "my-org/api" || event.Branch != createdPRs[0].Branch || event.Actor != "octocat" {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+		actions = append(actions, event.Action+" "+event.Path)
+	}
+
+	expected := []string{
+		audit.ActionBranchCreated + " ",
+		audit.ActionFileModified + " .github/workflows/ci.yml",
+		audit.ActionFileModified + " .github/workflows/release.yml",
+		audit.ActionPROpened + " ",
+	}
+	if strings.Join(actions, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected events %v, got %v", expected, actions)
+	}
+}
+
+func TestCreateUpdatePRs_SkipsPlansInLedger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	plans := []UpdatePlan{{
+		Repository: github.Repository{Owner: "my-org", Name: "api", FullName: "my-org/api", DefaultBranch: "main"},
+		Updates: []ActionUpdate{
+			{FilePath: ".github/workflows/ci.yml", ActionRepo: "actions/checkout", CurrentVersion: "v3", TargetVersion: "v4"},
+		},
+	}}
+
+	first, err := ledger.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	creator := NewCreator(nil)
+	creator.SetLedger(first)
+	createdPRs, err := creator.CreateUpdatePRs(plans)
+	if err != nil || len(createdPRs) != 1 {
+		t.Fatalf("Expected 1 PR, got %d (%v)", len(createdPRs), err)
+	}
+
+	// A rerun reads the ledger back and returns the recorded pull request instead of opening another
+	second, err := ledger.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := second.Lookup("My-Org/API", plans[0].PlanHash())
+	if entry == nil || entry.PR.Branch != createdPRs[0].Branch {
+		t.Fatalf("Expected the pull request in the ledger, got %+v", entry)
+	}
+
+	path2 := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger, err := audit.Open(&audit.Config{Target: path2, Command: "create-pr"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	creator = NewCreator(nil)
+	creator.SetLedger(second)
+	creator.SetAuditLog(logger)
+	rerun, err := creator.CreateUpdatePRs(plans)
+	if err != nil || len(rerun) != 1 || rerun[0].URL != createdPRs[0].URL {
+		t.Fatalf("Expected the recorded PR, got %+v (%v)", rerun, err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path2); len(data) != 0 {
+		t.Errorf("Expected no writes for a plan in the ledger, got %s", data)
+	}
+
+	// A changed plan is a different pull request
+	plans[0].Updates[0].TargetVersion = "v5"
+	if second.Lookup("my-org/api", plans[0].PlanHash()) != nil {
+		t.Error("Expected a plan with different updates not to be in the ledger")
+	}
+}
+
+func TestPatchWorkflowContent_SetsConcurrency(t *testing.T) {
+	content := `on: pull_request
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v3
+`
+	repo := output.RepositoryResult{
+		Name:     "api",
+		FullName: "my-org/api",
+		Issues: []output.ActionIssue{
+			{
+				Repository:  "concurrency.cancel-in-progress",
+				IssueType:   "missing-cancel-in-progress",
+				FilePath:    ".github/workflows/ci.yml",
+				Concurrency: &output.ConcurrencyRemediation{Group: "ci-${{ github.ref }}", CancelInProgress: true},
+			},
+			{Repository: "actions/checkout", CurrentVersion: "v3", SuggestedVersion: "v4", IssueType: "outdated", FilePath: ".github/workflows/ci.yml"},
+		},
+	}
+	plans := PlanUpdates([]output.RepositoryResult{repo})
+	if len(plans) != 1 || len(plans[0].Updates) != 2 {
+		t.Fatalf("Expected one plan with 2 updates, got %+v", plans)
+	}
+	if err := validateBatchingInvariant([]output.RepositoryResult{repo}, plans); err != nil {
+		t.Errorf("Expected concurrency fixes to count as fixable issues: %v", err)
+	}
+
+	updated, changes, err := PatchWorkflowContent(patcher.NewWorkflowPatcher(), content, plans[0].Updates)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `on: pull_request
+concurrency:
+  group: ci-${{ github.ref }}
+  cancel-in-progress: true
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+`
+	if updated != expected {
+		t.Errorf("Unexpected content:\n%s", updated)
+	}
+	if len(changes) == 0 || !strings.Contains(changes[0], "concurrency") {
+		t.Errorf("Expected the concurrency change to be described, got %v", changes)
+	}
+
+	creator := NewCreator(nil)
+	if body := creator.generateDefaultPRBody(plans[0]); !strings.Contains(body, "### 🚦 Concurrency") {
+		t.Errorf("Expected a concurrency section in the body, got:\n%s", body)
+	}
+}
@@ -0,0 +1,237 @@
+package pr
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// DispatchClient triggers workflow_dispatch runs on pull request branches, reads their results, and
+// reports them on the pull requests
+type DispatchClient interface {
+	GetFileContent(owner, repo, filePath, ref string) (string, error)
+	DispatchWorkflow(owner, repo, filePath, ref string) error
+	ListDispatchRuns(owner, repo, branch string) ([]github.DispatchRun, error)
+	CreateIssueComment(owner, repo string, number int, body string) error
+}
+
+// Dispatch check statuses
+const (
+	DispatchSkipped   = "skipped"   // The workflow cannot be dispatched; Reason says why
+	DispatchPending   = "pending"   // Dispatched; the run has not completed
+	DispatchCompleted = "completed" // The run completed with Conclusion
+	DispatchTimedOut  = "timed_out" // The run did not complete before the wait ended
+)
+
+// dispatchPollInterval is how often pending runs are checked while waiting
+var dispatchPollInterval = 30 * time.Second
+
+// dispatchClockSkew allows for clock differences between this machine and GitHub when matching runs
+// to the dispatches that started them
+const dispatchClockSkew = time.Minute
+
+// DispatchCheck is a workflow_dispatch run of a workflow the pull request patches with schema
+// transformations, run on the pull request branch to check the upgrade actually works
+type DispatchCheck struct {
+	FilePath   string `json:"file_path"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion,omitempty"` // success, failure, cancelled, ... once completed
+	RunURL     string `json:"run_url,omitempty"`
+	Reason     string `json:"reason,omitempty"` // Why the workflow was skipped
+}
+
+// DispatchChecks are the checks of one pull request
+type DispatchChecks struct {
+	PR           output.CreatedPR
+	Checks       []DispatchCheck
+	DispatchedAt time.Time
+}
+
+// Pending reports whether any check is waiting for its run to complete
+func (d *DispatchChecks) Pending() bool {
+	for _, check := range d.Checks {
+		if check.Status == DispatchPending {
+			return true
+		}
+	}
+	return false
+}
+
+// TransformedWorkflows returns the workflow files of the plan with updates that include schema
+// transformations, the upgrades most likely to break a workflow, in sorted order
+func TransformedWorkflows(plan UpdatePlan) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, update := range plan.Updates {
+		if update.Issue.HasTransformations && !seen[update.FilePath] {
+			seen[update.FilePath] = true
+			paths = append(paths, update.FilePath)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// StartDispatchChecks triggers a workflow_dispatch run on the pull request branch of each workflow the
+// plan patches with schema transformations. Workflows without a workflow_dispatch trigger, or with
+// required inputs that have no default, are skipped, as are those GitHub refuses to dispatch.
+func StartDispatchChecks(client DispatchClient, plan UpdatePlan, createdPR output.CreatedPR, now time.Time) *DispatchChecks {
+	checks := &DispatchChecks{PR: createdPR, DispatchedAt: now}
+	owner, name := plan.Repository.Owner, plan.Repository.Name
+	for _, filePath := range TransformedWorkflows(plan) {
+		check := DispatchCheck{FilePath: filePath, Status: DispatchSkipped}
+		if reason := dispatchBlocker(client, owner, name, filePath, createdPR.Branch); reason != "" {
+			check.Reason = reason
+		} else if err := client.DispatchWorkflow(owner, name, filePath, createdPR.Branch); err != nil {
+			check.Reason = err.Error()
+		} else {
+			check.Status = DispatchPending
+		}
+		checks.Checks = append(checks.Checks, check)
+	}
+	return checks
+}
+
+// dispatchBlocker returns why a workflow cannot be dispatched without inputs on a branch, or "" when it can
+func dispatchBlocker(client DispatchClient, owner, repo, filePath, branch string) string {
+	if path.Dir(filePath) != ".github/workflows" {
+		return "only workflows in .github/workflows can be dispatched"
+	}
+	content, err := client.GetFileContent(owner, repo, filePath, branch)
+	if err != nil {
+		return fmt.Sprintf("unable to read the patched workflow: %v", err)
+	}
+	triggers, err := workflow.ParseTriggers(content, filePath, nil)
+	if err != nil {
+		return fmt.Sprintf("unable to parse the patched workflow: %v", err)
+	}
+	dispatchable := false
+	for _, event := range triggers.Events {
+		if event == "workflow_dispatch" {
+			dispatchable = true
+		}
+	}
+	if !dispatchable {
+		return "the workflow has no workflow_dispatch trigger"
+	}
+
+	inputs, err := workflow.ParseDispatchInputs(content, filePath, nil)
+	if err != nil {
+		return fmt.Sprintf("unable to parse the patched workflow: %v", err)
+	}
+	var required []string
+	for _, input := range inputs {
+		if input.Required && !input.HasDefault {
+			required = append(required, input.Name)
+		}
+	}
+	if len(required) > 0 {
+		return fmt.Sprintf("the workflow requires inputs without defaults: %s", strings.Join(required, ", "))
+	}
+	return ""
+}
+
+// UpdateDispatchChecks matches the pending checks of a pull request to the newest workflow_dispatch run
+// of their workflow on its branch started since the dispatch, recording the run and, once it
+// completes, its conclusion
+func UpdateDispatchChecks(client DispatchClient, checks *DispatchChecks) error {
+	if !checks.Pending() {
+		return nil
+	}
+	owner, name, _ := strings.Cut(checks.PR.Repository, "/")
+	runs, err := client.ListDispatchRuns(owner, name, checks.PR.Branch)
+	if err != nil {
+		return err
+	}
+
+	for i, check := range checks.Checks {
+		if check.Status != DispatchPending {
+			continue
+		}
+		// Runs are listed newest first
+		for _, run := range runs {
+			if run.FilePath != check.FilePath || run.CreatedAt.Before(checks.DispatchedAt.Add(-dispatchClockSkew)) {
+				continue
+			}
+			checks.Checks[i].RunURL = run.URL
+			if run.Status == "completed" {
+				checks.Checks[i].Status = DispatchCompleted
+				checks.Checks[i].Conclusion = run.Conclusion
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// WaitDispatchChecks checks the runs of pending checks until all complete or timeout passes; runs still
+// pending then are DispatchTimedOut. Failures to read runs are returned once the wait ends, keyed by
+// pull request URL.
+func WaitDispatchChecks(client DispatchClient, all []*DispatchChecks, timeout time.Duration) map[string]error {
+	deadline := time.Now().Add(timeout)
+	failures := make(map[string]error)
+	for {
+		pending := false
+		for _, checks := range all {
+			if err := UpdateDispatchChecks(client, checks); err != nil {
+				failures[checks.PR.URL] = err
+			} else {
+				delete(failures, checks.PR.URL)
+			}
+			pending = pending || checks.Pending()
+		}
+		if !pending || !time.Now().Add(dispatchPollInterval).Before(deadline) {
+			break
+		}
+		time.Sleep(dispatchPollInterval)
+	}
+
+	for _, checks := range all {
+		for i := range checks.Checks {
+			if checks.Checks[i].Status == DispatchPending {
+				checks.Checks[i].Status = DispatchTimedOut
+			}
+		}
+	}
+	return failures
+}
+
+// dispatchIcons mark run conclusions in pull request comments
+var dispatchIcons = map[string]string{"success": "✅", "failure": "❌", "cancelled": "⚪", "timed_out": "⏱️"}
+
+// DispatchCheckComment returns the pull request comment reporting the checks
+func DispatchCheckComment(checks []DispatchCheck) string {
+	var b strings.Builder
+	b.WriteString("### Upgrade Check Runs\n\n")
+	b.WriteString("These workflows are patched with schema transformations, so they were run on this branch with `workflow_dispatch` to check the upgrade works.\n\n")
+	b.WriteString("| Workflow | Result |\n")
+	b.WriteString("|----------|--------|\n")
+	for _, check := range checks {
+		var result string
+		switch check.Status {
+		case DispatchCompleted:
+			icon := dispatchIcons[check.Conclusion]
+			if icon == "" {
+				icon = "⚠️"
+			}
+			result = fmt.Sprintf("%s %s", icon, check.Conclusion)
+		case DispatchTimedOut:
+			result = "⏳ still running"
+		case DispatchSkipped:
+			result = "not run: " + check.Reason
+		default:
+			result = check.Status
+		}
+		if check.RunURL != "" {
+			result = fmt.Sprintf("[%s](%s)", result, check.RunURL)
+		}
+		fmt.Fprintf(&b, "| `%s` | %s |\n", check.FilePath, result)
+	}
+	return b.String()
+}
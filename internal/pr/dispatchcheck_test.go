@@ -0,0 +1,105 @@
+package pr
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// fakeDispatchClient serves workflow files by path, records dispatches, and lists canned runs
+type fakeDispatchClient struct {
+	files      map[string]string
+	runs       []github.DispatchRun
+	dispatched []string
+	comments   []string
+}
+
+func (f *fakeDispatchClient) GetFileContent(owner, repo, filePath, ref string) (string, error) {
+	content, ok := f.files[filePath]
+	if !ok {
+		return "", fmt.Errorf("%s not found", filePath)
+	}
+	return content, nil
+}
+
+func (f *fakeDispatchClient) DispatchWorkflow(owner, repo, filePath, ref string) error {
+	f.dispatched = append(f.dispatched, filePath+"@"+ref)
+	return nil
+}
+
+func (f *fakeDispatchClient) ListDispatchRuns(owner, repo, branch string) ([]github.DispatchRun, error) {
+	return f.runs, nil
+}
+
+func (f *fakeDispatchClient) CreateIssueComment(owner, repo string, number int, body string) error {
+	f.comments = append(f.comments, body)
+	return nil
+}
+
+func TestDispatchChecks(t *testing.T) {
+	defer func(interval time.Duration) { dispatchPollInterval = interval }(dispatchPollInterval)
+	dispatchPollInterval = 0
+
+	client := &fakeDispatchClient{files: map[string]string{
+		".github/workflows/ci.yml":     "on:\n  push:\n  workflow_dispatch:\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - run: make\n",
+		".github/workflows/deploy.yml": "on:\n  workflow_dispatch:\n    inputs:\n      environment:\n        required: true\njobs:\n  deploy:\n    runs-on: ubuntu-latest\n    steps:\n      - run: make deploy\n",
+		".github/workflows/lint.yml":   "on: pull_request\njobs:\n  lint:\n    runs-on: ubuntu-latest\n    steps:\n      - run: make lint\n",
+	}}
+	transformed := output.ActionIssue{HasTransformations: true}
+	plan := UpdatePlan{
+		Repository: github.Repository{Owner: "my-org", Name: "api", FullName: "my-org/api", DefaultBranch: "main"},
+		Updates: []ActionUpdate{
+			{FilePath: ".github/workflows/lint.yml", ActionRepo: "actions/setup-node", CurrentVersion: "v3", TargetVersion: "v4", Issue: transformed},
+			{FilePath: ".github/workflows/ci.yml", ActionRepo: "actions/upload-artifact", CurrentVersion: "v3", TargetVersion: "v4", Issue: transformed},
+			{FilePath: ".github/workflows/deploy.yml", ActionRepo: "actions/cache", CurrentVersion: "v3", TargetVersion: "v4", Issue: transformed},
+			{FilePath: ".github/workflows/release.yml", ActionRepo: "actions/checkout", CurrentVersion: "v3", TargetVersion: "v4"},
+		},
+	}
+	if paths := TransformedWorkflows(plan); strings.Join(paths, ",") != ".github/workflows/ci.yml,.github/workflows/deploy.yml,.github/workflows/lint.yml" {
+		t.Fatalf("Expected the workflows with transformations, got %v", paths)
+	}
+
+	dispatchedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	createdPR := output.CreatedPR{Repository: "my-org/api", Number: 7, Branch: plan.BranchName(), URL: "https://github.com/my-org/api/pull/7"}
+	checks := StartDispatchChecks(client, plan, createdPR, dispatchedAt)
+	if len(client.dispatched) != 1 || client.dispatched[0] != ".github/workflows/ci.yml@"+plan.BranchName() {
+		t.Fatalf("Expected only ci.yml dispatched on the pull request branch, got %v", client.dispatched)
+	}
+	if checks.Checks[1].Status != DispatchSkipped || !strings.Contains(checks.Checks[1].Reason, "environment") {
+		t.Errorf("Expected deploy.yml skipped for its required input, got %+v", checks.Checks[1])
+	}
+	if checks.Checks[2].Status != DispatchSkipped || !strings.Contains(checks.Checks[2].Reason, "workflow_dispatch") {
+		t.Errorf("Expected lint.yml skipped for its missing trigger, got %+v", checks.Checks[2])
+	}
+
+	// A run from before the dispatch is not the check's run
+	client.runs = []github.DispatchRun{
+		{FilePath: ".github/workflows/ci.yml", Status: "completed", Conclusion: "success", URL: "https://github.com/my-org/api/actions/runs/1", CreatedAt: dispatchedAt.Add(-time.Hour)},
+	}
+	if failures := WaitDispatchChecks(client, []*DispatchChecks{checks}, 0); len(failures) != 0 {
+		t.Fatalf("Unexpected failures %v", failures)
+	}
+	if checks.Checks[0].Status != DispatchTimedOut || checks.Checks[0].RunURL != "" {
+		t.Errorf("Expected the check to time out without a run, got %+v", checks.Checks[0])
+	}
+
+	checks.Checks[0].Status = DispatchPending
+	client.runs = append([]github.DispatchRun{
+		{FilePath: ".github/workflows/ci.yml", Status: "completed", Conclusion: "failure", URL: "https://github.com/my-org/api/actions/runs/2", CreatedAt: dispatchedAt.Add(10 * time.Second)},
+	}, client.runs...)
+	WaitDispatchChecks(client, []*DispatchChecks{checks}, time.Minute)
+	if checks.Checks[0].Status != DispatchCompleted || checks.Checks[0].Conclusion != "failure" || !strings.HasSuffix(checks.Checks[0].RunURL, "/runs/2") {
+		t.Errorf("Expected the dispatched run's failure, got %+v", checks.Checks[0])
+	}
+
+	comment := DispatchCheckComment(checks.Checks)
+	for _, expected := range []string{"[❌ failure](https://github.com/my-org/api/actions/runs/2)", "not run: the workflow requires inputs without defaults: environment"} {
+		if !strings.Contains(comment, expected) {
+			t.Errorf("Expected the comment to contain %q, got:\n%s", expected, comment)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package pr
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// FileSHAClient looks up the blob SHA of a file at a ref
+type FileSHAClient interface {
+	GetFileSHA(owner, repo, filePath, ref string) (string, error)
+}
+
+// StaleFile is a workflow file that changed on the default branch since it was scanned
+type StaleFile struct {
+	Path       string
+	ScannedSHA string
+	CurrentSHA string // Empty when the file was deleted
+}
+
+// snapshotOf records the scanned blob SHAs of the workflow files a plan updates, and the commit they were
+// scanned at. Scan results written before SHAs were recorded have none, and their files are not checked.
+func snapshotOf(repo output.RepositoryResult, updates []ActionUpdate) (map[string]string, string) {
+	updated := make(map[string]bool)
+	for _, update := range updates {
+		updated[update.FilePath] = true
+	}
+
+	snapshot := make(map[string]string)
+	commit := ""
+	for _, file := range repo.WorkflowFiles {
+		if !updated[file.Path] {
+			continue
+		}
+		if file.BlobSHA != "" {
+			snapshot[file.Path] = file.BlobSHA
+		}
+		if commit == "" {
+			commit = file.CommitSHA
+		}
+	}
+	if len(snapshot) == 0 {
+		return nil, commit
+	}
+	return snapshot, commit
+}
+
+// CheckDrift compares the plan's workflow files on the default branch with the scan snapshot, returning
+// the files changed since the scan. Patches computed from stale content could undo or conflict with
+// those changes, so such plans should be skipped until the repository is rescanned.
+func CheckDrift(client FileSHAClient, plan UpdatePlan) ([]StaleFile, error) {
+	paths := make([]string, 0, len(plan.Snapshot))
+	for filePath := range plan.Snapshot {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+
+	var stale []StaleFile
+	for _, filePath := range paths {
+		current, err := client.GetFileSHA(plan.Repository.Owner, plan.Repository.Name, filePath, plan.Repository.DefaultBranch)
+		if err != nil {
+			return nil, fmt.Errorf("unable to check %s for changes since the scan: %w", filePath, err)
+		}
+		if current != plan.Snapshot[filePath] {
+			stale = append(stale, StaleFile{Path: filePath, ScannedSHA: plan.Snapshot[filePath], CurrentSHA: current})
+		}
+	}
+	return stale, nil
+}
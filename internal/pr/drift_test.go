@@ -0,0 +1,116 @@
+package pr
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// fakeFileSHAClient returns blob SHAs of files on the default branch
+type fakeFileSHAClient struct {
+	shas map[string]string
+	err  error
+	refs []string
+}
+
+func (f *fakeFileSHAClient) GetFileSHA(owner, repo, filePath, ref string) (string, error) {
+	f.refs = append(f.refs, ref)
+	return f.shas[filePath], f.err
+}
+
+func driftPlan() UpdatePlan {
+	repositories := []output.RepositoryResult{{
+		Name:          "api",
+		FullName:      "my-org/api",
+		DefaultBranch: "main",
+		WorkflowFiles: []output.WorkflowFileResult{
+			{Path: ".github/workflows/ci.yml", BlobSHA: "aaa", CommitSHA: "head1"},
+			{Path: ".github/workflows/deploy.yml", BlobSHA: "bbb", CommitSHA: "head1"},
+			{Path: ".github/workflows/lint.yml", BlobSHA: "ccc", CommitSHA: "head1"},
+		},
+		Issues: []output.ActionIssue{
+			{Repository: "actions/checkout", CurrentVersion: "v3", SuggestedVersion: "v4", IssueType: "outdated", FilePath: ".github/workflows/ci.yml"},
+			{Repository: "actions/checkout", CurrentVersion: "v3", SuggestedVersion: "v4", IssueType: "outdated", FilePath: ".github/workflows/deploy.yml"},
+		},
+	}}
+	return PlanUpdates(repositories)[0]
+}
+
+func TestPlanUpdates_RecordsSnapshot(t *testing.T) {
+	expected := map[string]string{
+		".github/workflows/ci.yml":     "aaa",
+		".github/workflows/deploy.yml": "bbb",
+	}
+	plan := driftPlan()
+	if !reflect.DeepEqual(plan.Snapshot, expected) {
+		t.Errorf("Expected the snapshot of updated files only, got %v", plan.Snapshot)
+	}
+	if plan.ScannedCommit != "head1" {
+		t.Errorf("Expected the scanned commit to be recorded, got %q", plan.ScannedCommit)
+	}
+}
+
+func TestPlanCommit_StartsFromScannedCommit(t *testing.T) {
+	creator := NewCreator(nil)
+	plan := driftPlan()
+	if commit, _ := creator.planCommit(plan); commit.Parent != "head1" {
+		t.Errorf("Expected the default branch commit to start from the scanned commit, got %q", commit.Parent)
+	}
+
+	// Other base branches were never scanned, so their commits start from the branch tip
+	plan.BaseBranch = "release/1.x"
+	if commit, _ := creator.planCommit(plan); commit.Parent != "" {
+		t.Errorf("Expected no parent for another base branch, got %q", commit.Parent)
+	}
+}
+
+func TestCheckDrift(t *testing.T) {
+	client := &fakeFileSHAClient{shas: map[string]string{
+		".github/workflows/ci.yml":     "aaa",
+		".github/workflows/deploy.yml": "changed",
+	}}
+
+	stale, err := CheckDrift(client, driftPlan())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []StaleFile{{Path: ".github/workflows/deploy.yml", ScannedSHA: "bbb", CurrentSHA: "changed"}}
+	if !reflect.DeepEqual(stale, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, stale)
+	}
+	if !reflect.DeepEqual(client.refs, []string{"main", "main"}) {
+		t.Errorf("Expected files to be checked on the default branch, got %v", client.refs)
+	}
+}
+
+func TestCheckDrift_DeletedFile(t *testing.T) {
+	client := &fakeFileSHAClient{shas: map[string]string{".github/workflows/ci.yml": "aaa"}}
+
+	stale, err := CheckDrift(client, driftPlan())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(stale) != 1 || stale[0].Path != ".github/workflows/deploy.yml" || stale[0].CurrentSHA != "" {
+		t.Errorf("Expected the deleted file to be stale, got %+v", stale)
+	}
+}
+
+func TestCheckDrift_WithoutSnapshot(t *testing.T) {
+	client := &fakeFileSHAClient{err: fmt.Errorf("should not be called")}
+	plan := UpdatePlan{Repository: github.Repository{FullName: "my-org/api"}}
+
+	stale, err := CheckDrift(client, plan)
+	if err != nil || len(stale) != 0 || len(client.refs) != 0 {
+		t.Errorf("Expected plans without a snapshot to pass unchecked, got %+v, %v", stale, err)
+	}
+}
+
+func TestCheckDrift_LookupError(t *testing.T) {
+	client := &fakeFileSHAClient{err: fmt.Errorf("rate limited")}
+	if _, err := CheckDrift(client, driftPlan()); err == nil {
+		t.Errorf("Expected lookup errors to be returned")
+	}
+}
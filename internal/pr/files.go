@@ -0,0 +1,120 @@
+package pr
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// FilePlan collects the coordinated edits to one file outside .github/workflows, such as
+// .github/dependabot.yml or a README badge, made in the same pull request as the workflow updates
+type FilePlan struct {
+	Path  string       `json:"path"`
+	Edits []FileChange `json:"edits"`
+}
+
+// FileChange is a rules-driven edit with its version placeholders resolved
+type FileChange struct {
+	Find    string `json:"find"`    // Regular expression matching the text to replace
+	Replace string `json:"replace"` // Replacement; $1 expands groups
+	Reason  string `json:"reason,omitempty"`
+	Action  string `json:"action,omitempty"` // Action whose update needs the edit, e.g. "actions/cache"
+}
+
+// ValidateFileEdit checks that a rule's file edit targets a repository file outside .github/workflows
+// with a valid regular expression; workflow files are changed by the workflow patchers instead
+func ValidateFileEdit(edit output.FileEdit) error {
+	if edit.Path == "" {
+		return fmt.Errorf("path is required")
+	}
+	cleaned := path.Clean(edit.Path)
+	if path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return fmt.Errorf("path %s must be relative to the repository root", edit.Path)
+	}
+	if strings.HasPrefix(cleaned, ".github/workflows/") {
+		return fmt.Errorf("path %s is a workflow file; use patch rules to change workflows", edit.Path)
+	}
+	if edit.Find == "" {
+		return fmt.Errorf("find is required for %s", edit.Path)
+	}
+	if _, err := regexp.Compile(edit.Find); err != nil {
+		return fmt.Errorf("invalid find pattern for %s: %w", edit.Path, err)
+	}
+	return nil
+}
+
+// PlanFileEdits gathers the file edits of a plan's updates into one plan per file, sorted by path
+// {current} and {target} in a replacement become the update's versions. Edits that resolve to the same
+// change, such as a rule matching several workflows, are made once.
+func PlanFileEdits(updates []ActionUpdate) []FilePlan {
+	byPath := make(map[string]*FilePlan)
+	seen := make(map[string]bool)
+	for _, update := range updates {
+		for _, edit := range update.Issue.FileEdits {
+			filePath := path.Clean(edit.Path)
+			replace := strings.NewReplacer("{current}", update.CurrentVersion, "{target}", update.TargetVersion).Replace(edit.Replace)
+			key := filePath + "\x00" + edit.Find + "\x00" + replace
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			plan, ok := byPath[filePath]
+			if !ok {
+				plan = &FilePlan{Path: filePath}
+				byPath[filePath] = plan
+			}
+			plan.Edits = append(plan.Edits, FileChange{
+				Find:    edit.Find,
+				Replace: replace,
+				Reason:  edit.Reason,
+				Action:  joinRefPath(update.ActionRepo, update.WorkflowPath),
+			})
+		}
+	}
+
+	plans := make([]FilePlan, 0, len(byPath))
+	for _, plan := range byPath {
+		plans = append(plans, *plan)
+	}
+	sort.Slice(plans, func(i, j int) bool { return plans[i].Path < plans[j].Path })
+	return plans
+}
+
+// ApplyFilePlan applies a file's edits in order and returns the updated content with a description of
+// each edit that changed it. Edits whose pattern no longer matches are skipped, so a README without the
+// badge is left alone.
+func ApplyFilePlan(content string, plan FilePlan) (string, []string, error) {
+	var changes []string
+	for _, edit := range plan.Edits {
+		pattern, err := regexp.Compile(edit.Find)
+		if err != nil {
+			return content, nil, fmt.Errorf("invalid find pattern for %s: %w", plan.Path, err)
+		}
+		updated := pattern.ReplaceAllString(content, edit.Replace)
+		if updated == content {
+			continue
+		}
+		content = updated
+
+		change := fmt.Sprintf("Updated %s for %s", plan.Path, edit.Action)
+		if edit.Reason != "" {
+			change += ": " + edit.Reason
+		}
+		changes = append(changes, change)
+	}
+	return content, changes, nil
+}
+
+// FilePaths returns the paths of the files outside .github/workflows the plan edits
+func (p UpdatePlan) FilePaths() []string {
+	paths := make([]string, 0, len(p.Files))
+	for _, file := range p.Files {
+		paths = append(paths, file.Path)
+	}
+	return paths
+}
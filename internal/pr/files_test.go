@@ -0,0 +1,85 @@
+package pr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+func TestPlanUpdates_FileEdits(t *testing.T) {
+	edits := []output.FileEdit{
+		{Path: "README.md", Find: `setup-node@v\d+`, Replace: "setup-node@{target}", Reason: "Keep the badge current"},
+		{Path: "./.github/dependabot.yml", Find: `(# pinned: actions/setup-node )\S+`, Replace: "${1}{target}"},
+	}
+	repositories := []output.RepositoryResult{{
+		Name:     "api",
+		FullName: "my-org/api",
+		Issues: []output.ActionIssue{
+			{Repository: "actions/setup-node", CurrentVersion: "v2", SuggestedVersion: "v4", IssueType: "outdated", FilePath: ".github/workflows/ci.yml", FileEdits: edits},
+			{Repository: "actions/setup-node", CurrentVersion: "v2", SuggestedVersion: "v4", IssueType: "outdated", FilePath: ".github/workflows/release.yml", FileEdits: edits},
+			{Repository: "actions/checkout", CurrentVersion: "v3", SuggestedVersion: "v4", IssueType: "outdated", FilePath: ".github/workflows/ci.yml"},
+		},
+	}}
+
+	plans := PlanUpdates(repositories)
+	if len(plans) != 1 {
+		t.Fatalf("Expected 1 plan, got %d", len(plans))
+	}
+	files := plans[0].Files
+	if strings.Join(plans[0].FilePaths(), ",") != ".github/dependabot.yml,README.md" {
+		t.Fatalf("Expected dependabot.yml and README.md once each, got %v", plans[0].FilePaths())
+	}
+	if len(files[0].Edits) != 1 || files[0].Edits[0].Replace != "${1}v4" || files[0].Edits[0].Action != "actions/setup-node" {
+		t.Errorf("Expected one resolved dependabot edit for actions/setup-node, got %+v", files[0].Edits)
+	}
+
+	body := NewCreator(nil).generatePRBody(plans[0])
+	if !strings.Contains(body, "### 📄 Related File Changes") || !strings.Contains(body, "- **actions/setup-node**: Keep the badge current") {
+		t.Errorf("Expected the body to list related file changes, got:\n%s", body)
+	}
+}
+
+func TestApplyFilePlan(t *testing.T) {
+	content := "# API\n\n![Node](https://img.shields.io/badge/setup--node-v2-blue)\nUses actions/setup-node@v2.\n"
+	plan := FilePlan{Path: "README.md", Edits: []FileChange{
+		{Find: `setup--node-v\d+`, Replace: "setup--node-v4", Reason: "Update the badge", Action: "actions/setup-node"},
+		{Find: `setup-node@v2`, Replace: "setup-node@v4", Action: "actions/setup-node"},
+		{Find: `actions/cache@v2`, Replace: "actions/cache@v4", Action: "actions/cache"},
+	}}
+
+	updated, changes, err := ApplyFilePlan(content, plan)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "# API\n\n![Node](https://img.shields.io/badge/setup--node-v4-blue)\nUses actions/setup-node@v4.\n"
+	if updated != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, updated)
+	}
+	if len(changes) != 2 || changes[0] != "Updated README.md for actions/setup-node: Update the badge" {
+		t.Errorf("Expected 2 changes skipping the unmatched edit, got %v", changes)
+	}
+}
+
+func TestValidateFileEdit(t *testing.T) {
+	tests := []struct {
+		name        string
+		edit        output.FileEdit
+		expectError bool
+	}{
+		{"dependabot", output.FileEdit{Path: ".github/dependabot.yml", Find: "v2"}, false},
+		{"readme", output.FileEdit{Path: "docs/../README.md", Find: `badge/v\d+`}, false},
+		{"missing path", output.FileEdit{Find: "v2"}, true},
+		{"absolute path", output.FileEdit{Path: "/etc/passwd", Find: "root"}, true},
+		{"outside repository", output.FileEdit{Path: "../other/README.md", Find: "v2"}, true},
+		{"workflow file", output.FileEdit{Path: ".github/workflows/ci.yml", Find: "v2"}, true},
+		{"missing find", output.FileEdit{Path: "README.md"}, true},
+		{"invalid find", output.FileEdit{Path: "README.md", Find: "("}, true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateFileEdit(tt.edit); (err != nil) != tt.expectError {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.expectError, err)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package pr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/patcher"
+)
+
+// InputChange is a with: key a schema transformation changes in a step, with its value before and after
+type InputChange struct {
+	Input  string // Key of the input; renames read "old → new"
+	Before string // Value before the transformation, empty when the key was unset
+	After  string // Value after the transformation, empty when the key is removed
+	Reason string // Why the transformation changes the key
+}
+
+// InputChanges returns the with: keys the update's patch preview changes, or nil when the issue has no
+// applied preview (scan --patch-preview)
+func (u ActionUpdate) InputChanges() []InputChange {
+	return inputChanges(u.Issue.PatchPreview)
+}
+
+// inputChanges lists a patch's renames, modifications, removals, and additions, taking the values of
+// renamed and removed keys from the original with: block
+func inputChanges(patch *patcher.Patch) []InputChange {
+	if patch == nil || !patch.Applied {
+		return nil
+	}
+	original, _ := patch.OriginalWith.(map[string]interface{})
+	updated, _ := patch.UpdatedWith.(map[string]interface{})
+
+	var changes []InputChange
+	for _, rename := range patch.Renames {
+		changes = append(changes, InputChange{
+			Input:  rename.OldField + " → " + rename.NewField,
+			Before: inputValue(original, rename.OldField),
+			After:  inputValue(updated, rename.NewField),
+			Reason: rename.Reason,
+		})
+	}
+	for _, modification := range patch.Modifications {
+		changes = append(changes, InputChange{
+			Input:  modification.Field,
+			Before: formatInputValue(modification.OldValue),
+			After:  formatInputValue(modification.NewValue),
+			Reason: modification.Reason,
+		})
+	}
+	for _, removal := range patch.Removals {
+		changes = append(changes, InputChange{
+			Input:  removal.Field,
+			Before: inputValue(original, removal.Field),
+			Reason: removal.Reason,
+		})
+	}
+	for _, addition := range patch.Additions {
+		changes = append(changes, InputChange{
+			Input:  addition.Field,
+			Before: inputValue(original, addition.Field),
+			After:  formatInputValue(addition.Value),
+			Reason: addition.Reason,
+		})
+	}
+	return changes
+}
+
+// inputValue formats the value of a key in a with: block, or returns "" when the key is unset
+func inputValue(with map[string]interface{}, key string) string {
+	value, ok := with[key]
+	if !ok {
+		return ""
+	}
+	return formatInputValue(value)
+}
+
+// formatInputValue formats a with: value as the workflow file would show it
+func formatInputValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// writeInputChanges writes the with: changes of an update as a before/after table in the default body
+func writeInputChanges(body *strings.Builder, update ActionUpdate) {
+	target := update.TargetVersion
+	if update.TargetRepo != "" {
+		target = update.TargetRepo + "@" + update.TargetVersion
+	}
+	body.WriteString(fmt.Sprintf("**%s** %s → %s in `%s`", update.ActionRepo, update.CurrentVersion, target, update.FilePath))
+	if update.Issue.Context != "" {
+		body.WriteString(fmt.Sprintf(" (%s)", update.Issue.Context))
+	}
+	body.WriteString("\n\n")
+
+	body.WriteString("| Input | Before | After | Reason |\n")
+	body.WriteString("|---|---|---|---|\n")
+	for _, change := range update.InputChanges() {
+		before, after := "_unset_", "_removed_"
+		if change.Before != "" {
+			before = tableCode(change.Before)
+		}
+		if change.After != "" {
+			after = tableCode(change.After)
+		}
+		body.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s |\n", change.Input, before, after, tableText(change.Reason)))
+	}
+	body.WriteString("\n")
+}
+
+// tableCode formats a value as inline code within a markdown table cell
+func tableCode(value string) string {
+	return "`" + tableText(strings.ReplaceAll(value, "`", "'")) + "`"
+}
+
+// tableText escapes the characters that would break a markdown table cell
+func tableText(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.ReplaceAll(text, "\n", " ")
+}
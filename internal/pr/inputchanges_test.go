@@ -0,0 +1,93 @@
+package pr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/patcher"
+)
+
+func TestInputChanges(t *testing.T) {
+	patch := &patcher.Patch{
+		Applied:       true,
+		Renames:       []patcher.FieldRename{{OldField: "node_version", NewField: "node-version", Reason: "renamed"}},
+		Modifications: []patcher.FieldModification{{Field: "cache", OldValue: false, NewValue: "npm", Reason: "cache is a package manager"}},
+		Removals:      []patcher.FieldRemoval{{Field: "token", Reason: "no longer needed"}},
+		Additions:     []patcher.FieldAddition{{Field: "fetch-depth", Value: 1, Reason: "shallow | fast"}},
+		OriginalWith:  map[string]interface{}{"node_version": "18", "cache": false, "token": "${{ secrets.PAT }}"},
+		UpdatedWith:   map[string]interface{}{"node-version": "18", "cache": "npm", "fetch-depth": 1},
+	}
+	update := ActionUpdate{Issue: output.ActionIssue{PatchPreview: patch}}
+
+	changes := update.InputChanges()
+	expected := []InputChange{
+		{Input: "node_version → node-version", Before: "18", After: "18", Reason: "renamed"},
+		{Input: "cache", Before: "false", After: "npm", Reason: "cache is a package manager"},
+		{Input: "token", Before: "${{ secrets.PAT }}", Reason: "no longer needed"},
+		{Input: "fetch-depth", After: "1", Reason: "shallow | fast"},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got %+v", len(expected), changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("Expected change %d to be %+v, got %+v", i, expected[i], changes[i])
+		}
+	}
+
+	patch.Applied = false
+	if changes := update.InputChanges(); changes != nil {
+		t.Errorf("Expected no changes for an unapplied patch, got %+v", changes)
+	}
+}
+
+func TestGenerateDefaultPRBody_InputChanges(t *testing.T) {
+	wp := patcher.NewWorkflowPatcher()
+	wp.AddPatchRule(patcher.ActionPatchRule{
+		Repository: "actions/checkout",
+		VersionPatches: []patcher.VersionPatch{{
+			FromVersion: "v1",
+			ToVersion:   "v4",
+			Patches:     []patcher.FieldPatch{{Operation: patcher.OperationRemove, Field: "token", Reason: "v4 uses GITHUB_TOKEN"}},
+		}},
+	})
+	preview, err := wp.PreviewChanges("actions/checkout", "v1", "v4", map[string]interface{}{"token": "${{ secrets.PAT }}"})
+	if err != nil {
+		t.Fatalf("Failed to preview patch: %v", err)
+	}
+	plan := UpdatePlan{
+		Repository: github.Repository{Name: "test-repo", FullName: "testowner/test-repo"},
+		Updates: []ActionUpdate{
+			{
+				ActionRepo:     "actions/checkout",
+				CurrentVersion: "v1",
+				TargetVersion:  "v4",
+				FilePath:       ".github/workflows/ci.yml",
+				Issue:          output.ActionIssue{IssueType: "outdated", Context: "job:build/step:checkout", PatchPreview: preview},
+			},
+			{
+				ActionRepo:     "actions/cache",
+				CurrentVersion: "v3",
+				TargetVersion:  "v4",
+				FilePath:       ".github/workflows/ci.yml",
+				Issue:          output.ActionIssue{IssueType: "outdated"},
+			},
+		},
+	}
+
+	body := NewCreator(nil).generateDefaultPRBody(plan)
+	if !strings.Contains(body, "### 🔧 Input Changes") {
+		t.Fatalf("Expected an input changes section, got:\n%s", body)
+	}
+	if !strings.Contains(body, "**actions/checkout** v1 → v4 in `.github/workflows/ci.yml` (job:build/step:checkout)") {
+		t.Errorf("Expected the transformed step named, got:\n%s", body)
+	}
+	if !strings.Contains(body, "| `token` | `${{ secrets.PAT }}` | _removed_ |") {
+		t.Errorf("Expected the removed token in the table, got:\n%s", body)
+	}
+	if strings.Contains(body, "**actions/cache** v3 → v4 in") {
+		t.Errorf("Expected no table for an update without transformations, got:\n%s", body)
+	}
+}
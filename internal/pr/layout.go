@@ -0,0 +1,158 @@
+package pr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Commit layouts of a pull request branch
+const (
+	CommitLayoutSingle    = "single"     // One commit with every update (default)
+	CommitLayoutPerAction = "per-action" // One commit per updated action, with the file edits its update needs
+	CommitLayoutPerFile   = "per-file"   // One commit per changed workflow file, then one per other file
+)
+
+// ParseCommitLayout validates a commit layout name; "" is the single commit layout
+func ParseCommitLayout(value string) (string, error) {
+	switch value {
+	case "":
+		return CommitLayoutSingle, nil
+	case CommitLayoutSingle, CommitLayoutPerAction, CommitLayoutPerFile:
+		return value, nil
+	}
+	return "", fmt.Errorf("unknown commit layout %q (expected %s, %s, or %s)", value, CommitLayoutSingle, CommitLayoutPerAction, CommitLayoutPerFile)
+}
+
+// SetCommitLayout sets how a plan's updates are split into commits on its branch
+func (c *Creator) SetCommitLayout(layout string) {
+	c.commitLayout = layout
+}
+
+// layoutCommit is one commit of a pull request branch
+type layoutCommit struct {
+	Message string
+	Paths   []string // Files the commit changes
+}
+
+// commitGroup is a subset of a plan's changes committed together, before its message is known
+type commitGroup struct {
+	title   string // Default message when no template applies
+	updates []ActionUpdate
+	paths   []string
+}
+
+// layoutCommits splits a plan's changes into the commits of its branch, in order
+// Every group gets its commit message from the templates as a plan of its own updates would. Files
+// written whole, such as the full update list of a truncated body, go in the last commit.
+func (c *Creator) layoutCommits(plan UpdatePlan, title string, commit branchCommit) []layoutCommit {
+	var groups []commitGroup
+	switch c.commitLayout {
+	case CommitLayoutPerAction:
+		groups = groupByAction(plan)
+	case CommitLayoutPerFile:
+		groups = groupByFile(plan)
+	}
+	if len(groups) <= 1 {
+		groups = []commitGroup{{title: title, updates: plan.Updates, paths: append(append([]string{}, commit.Workflows...), commit.Files...)}}
+	}
+	for _, file := range commit.Generated {
+		last := &groups[len(groups)-1]
+		last.paths = append(last.paths, file.Path)
+	}
+
+	commits := make([]layoutCommit, 0, len(groups))
+	for _, group := range groups {
+		groupPlan := plan
+		groupPlan.Updates = group.updates
+		message := group.title
+		if len(group.updates) > 0 {
+			message = c.generateCommitMessage(groupPlan, group.title)
+		}
+		commits = append(commits, layoutCommit{Message: message, Paths: group.paths})
+	}
+	return commits
+}
+
+// groupByAction groups updates by action, in the order actions first appear, with the edits of
+// other files each update needs; edits not tied to an updated action get a commit of their own
+func groupByAction(plan UpdatePlan) []commitGroup {
+	var groups []commitGroup
+	index := make(map[string]int)
+	for _, update := range plan.Updates {
+		i, ok := index[update.ActionRepo]
+		if !ok {
+			i = len(groups)
+			index[update.ActionRepo] = i
+			groups = append(groups, commitGroup{})
+		}
+		groups[i].updates = append(groups[i].updates, update)
+		groups[i].paths = appendPath(groups[i].paths, update.FilePath)
+	}
+	for i := range groups {
+		groups[i].title = actionCommitTitle(groups[i].updates)
+	}
+
+	var untied []string
+	for _, file := range plan.Files {
+		for _, edit := range file.Edits {
+			if i, ok := index[edit.Action]; ok {
+				groups[i].paths = appendPath(groups[i].paths, file.Path)
+			} else {
+				untied = appendPath(untied, file.Path)
+			}
+		}
+	}
+	if len(untied) > 0 {
+		groups = append(groups, commitGroup{title: "Update " + strings.Join(untied, ", "), paths: untied})
+	}
+	return groups
+}
+
+// groupByFile groups updates by workflow file, in the order files first appear, then gives each
+// other file a commit of its own
+func groupByFile(plan UpdatePlan) []commitGroup {
+	var groups []commitGroup
+	index := make(map[string]int)
+	for _, update := range plan.Updates {
+		i, ok := index[update.FilePath]
+		if !ok {
+			i = len(groups)
+			index[update.FilePath] = i
+			groups = append(groups, commitGroup{paths: []string{update.FilePath}})
+		}
+		groups[i].updates = append(groups[i].updates, update)
+	}
+	for i := range groups {
+		groups[i].title = fmt.Sprintf("Update %d GitHub Actions in %s", len(groups[i].updates), groups[i].paths[0])
+		if len(groups[i].updates) == 1 {
+			groups[i].title = actionCommitTitle(groups[i].updates)
+		}
+	}
+
+	for _, path := range plan.FilePaths() {
+		groups = append(groups, commitGroup{title: "Update " + path, paths: []string{path}})
+	}
+	return groups
+}
+
+// actionCommitTitle describes the updates of one action, e.g. "Update actions/checkout from v3 to v4"
+func actionCommitTitle(updates []ActionUpdate) string {
+	data := commitMessageData(UpdatePlan{Updates: updates}, "")
+	switch {
+	case data.From != "" && data.To != "":
+		return fmt.Sprintf("Update %s from %s to %s", data.Action, data.From, data.To)
+	case data.To != "":
+		return fmt.Sprintf("Update %s to %s", data.Action, data.To)
+	}
+	return fmt.Sprintf("Update %s", data.Action)
+}
+
+// appendPath appends a path unless it is already listed
+func appendPath(paths []string, path string) []string {
+	for _, existing := range paths {
+		if existing == path {
+			return paths
+		}
+	}
+	return append(paths, path)
+}
@@ -0,0 +1,87 @@
+package pr
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+)
+
+func layoutPlan() UpdatePlan {
+	return UpdatePlan{
+		Repository: github.Repository{Owner: "my-org", Name: "api", FullName: "my-org/api", DefaultBranch: "main"},
+		Updates: []ActionUpdate{
+			{FilePath: ".github/workflows/ci.yml", ActionRepo: "actions/checkout", CurrentVersion: "v3", TargetVersion: "v4"},
+			{FilePath: ".github/workflows/ci.yml", ActionRepo: "actions/cache", CurrentVersion: "v3", TargetVersion: "v4"},
+			{FilePath: ".github/workflows/release.yml", ActionRepo: "actions/checkout", CurrentVersion: "v3", TargetVersion: "v4"},
+		},
+		Files: []FilePlan{
+			{Path: "Makefile", Edits: []FileChange{{Find: "cache-v3", Replace: "cache-v4", Action: "actions/cache"}}},
+			{Path: "renovate.json", Edits: []FileChange{{Find: "a", Replace: "b"}}},
+		},
+	}
+}
+
+func commitLayoutFor(t *testing.T, layout string) []layoutCommit {
+	t.Helper()
+	creator := NewCreator(nil)
+	creator.SetCommitLayout(layout)
+	plan := layoutPlan()
+	commit, _ := creator.planCommit(plan)
+	return creator.layoutCommits(plan, "PR title", commit)
+}
+
+func TestLayoutCommits_Single(t *testing.T) {
+	commits := commitLayoutFor(t, CommitLayoutSingle)
+	expected := []layoutCommit{{
+		Message: "PR title",
+		Paths:   []string{".github/workflows/ci.yml", ".github/workflows/release.yml", "Makefile", "renovate.json"},
+	}}
+	if !reflect.DeepEqual(commits, expected) {
+		t.Errorf("Expected one commit with every file, got %+v", commits)
+	}
+}
+
+func TestLayoutCommits_PerAction(t *testing.T) {
+	commits := commitLayoutFor(t, CommitLayoutPerAction)
+	expected := []layoutCommit{
+		{Message: "Update actions/checkout from v3 to v4", Paths: []string{".github/workflows/ci.yml", ".github/workflows/release.yml"}},
+		{Message: "Update actions/cache from v3 to v4", Paths: []string{".github/workflows/ci.yml", "Makefile"}},
+		{Message: "Update renovate.json", Paths: []string{"renovate.json"}},
+	}
+	if !reflect.DeepEqual(commits, expected) {
+		t.Errorf("Expected one commit per action with its file edits, got %+v", commits)
+	}
+}
+
+func TestLayoutCommits_PerFileWithTemplate(t *testing.T) {
+	creator := NewCreator(nil)
+	creator.SetCommitLayout(CommitLayoutPerFile)
+	tmpl, err := ParseCommitMessage("conventional")
+	if err != nil {
+		t.Fatal(err)
+	}
+	creator.SetCommitMessage(tmpl)
+	plan := layoutPlan()
+	commit, _ := creator.planCommit(plan)
+
+	commits := creator.layoutCommits(plan, "PR title", commit)
+	expected := []layoutCommit{
+		{Message: "chore(deps): bump 2 actions", Paths: []string{".github/workflows/ci.yml"}},
+		{Message: "chore(deps): bump actions/checkout to v4", Paths: []string{".github/workflows/release.yml"}},
+		{Message: "Update Makefile", Paths: []string{"Makefile"}},
+		{Message: "Update renovate.json", Paths: []string{"renovate.json"}},
+	}
+	if !reflect.DeepEqual(commits, expected) {
+		t.Errorf("Expected one templated commit per workflow file, then one per other file, got %+v", commits)
+	}
+}
+
+func TestParseCommitLayout(t *testing.T) {
+	if layout, err := ParseCommitLayout(""); err != nil || layout != CommitLayoutSingle {
+		t.Errorf("Expected the single layout by default, got %q, %v", layout, err)
+	}
+	if _, err := ParseCommitLayout("per-job"); err == nil {
+		t.Error("Expected an error for an unknown layout")
+	}
+}
@@ -0,0 +1,87 @@
+package pr
+
+import (
+	"fmt"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// ParseMigrateTarget splits "owner/repo[/path]@version" into the repository, path, and version
+func ParseMigrateTarget(ref string) (string, string, string, error) {
+	repository, version := parseMigrationTarget(ref)
+	if repository == "" || version == "" {
+		return "", "", "", fmt.Errorf("migration target %q must be owner/repo@version or owner/repo/<path>@version", ref)
+	}
+	return repository, parseMigrationTargetPath(ref), version, nil
+}
+
+// PlanMigration creates one plan per repository replacing every usage of fromRepo with toRepo@toVersion
+// An empty fromPath matches every path in fromRepo, and an empty toPath keeps each usage's current path.
+func PlanMigration(repositories []output.RepositoryResult, fromRepo, fromPath, toRepo, toPath, toVersion string) []UpdatePlan {
+	var plans []UpdatePlan
+
+	for _, repo := range repositories {
+		plan := UpdatePlan{
+			Repository: github.Repository{
+				Owner:         extractOwner(repo.FullName),
+				Name:          repo.Name,
+				FullName:      repo.FullName,
+				DefaultBranch: repo.DefaultBranch,
+			},
+			Updates: []ActionUpdate{},
+		}
+
+		// A workflow using the same reference twice only needs one update
+		seen := make(map[string]bool)
+		for _, action := range repo.Actions {
+			if action.Local || action.Repository != fromRepo {
+				continue
+			}
+			if fromPath != "" && action.WorkflowPath != fromPath {
+				continue
+			}
+
+			from := joinRefPath(action.Repository, action.WorkflowPath)
+			key := action.FilePath + "|" + from + "@" + action.Version
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			targetPath := toPath
+			if targetPath == "" {
+				targetPath = action.WorkflowPath
+			}
+			target := joinRefPath(toRepo, targetPath) + "@" + toVersion
+
+			plan.Updates = append(plan.Updates, ActionUpdate{
+				FilePath:       action.FilePath,
+				ActionRepo:     action.Repository,
+				WorkflowPath:   action.WorkflowPath,
+				CurrentVersion: action.Version,
+				TargetVersion:  toVersion,
+				TargetRepo:     toRepo,
+				TargetPath:     targetPath,
+				Issue: output.ActionIssue{
+					Repository:       action.Repository,
+					WorkflowPath:     action.WorkflowPath,
+					CurrentVersion:   action.Version,
+					IssueType:        "migration",
+					Severity:         "medium",
+					Description:      fmt.Sprintf("%s@%s is being replaced by %s", from, action.Version, target),
+					Context:          action.Context,
+					FilePath:         action.FilePath,
+					MigrationTarget:  target,
+					SuggestedVersion: toVersion,
+				},
+			})
+		}
+
+		if len(plan.Updates) > 0 {
+			plans = append(plans, plan)
+		}
+	}
+
+	return plans
+}
@@ -0,0 +1,99 @@
+package pr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+func TestParseMigrateTarget(t *testing.T) {
+	tests := []struct {
+		ref      string
+		repo     string
+		path     string
+		version  string
+		hasError bool
+	}{
+		{"new-org/action@v2", "new-org/action", "", "v2", false},
+		{"new-org/shared/.github/workflows/build.yml@v3", "new-org/shared", ".github/workflows/build.yml", "v3", false},
+		{"new-org/action", "", "", "", true},
+		{"new-org@v2", "", "", "", true},
+	}
+
+	for _, test := range tests {
+		repo, path, version, err := ParseMigrateTarget(test.ref)
+		if (err != nil) != test.hasError {
+			t.Errorf("ParseMigrateTarget(%q) error = %v, expected error: %v", test.ref, err, test.hasError)
+			continue
+		}
+		if repo != test.repo || path != test.path || version != test.version {
+			t.Errorf("ParseMigrateTarget(%q) = (%q, %q, %q), expected (%q, %q, %q)", test.ref, repo, path, version, test.repo, test.path, test.version)
+		}
+	}
+}
+
+func TestPlanMigration(t *testing.T) {
+	repositories := []output.RepositoryResult{
+		{
+			Name:     "api",
+			FullName: "my-org/api",
+			Actions: []workflow.ActionReference{
+				{Repository: "old-org/action", Version: "v1", FilePath: ".github/workflows/ci.yml"},
+				{Repository: "old-org/action", Version: "v1", FilePath: ".github/workflows/ci.yml"}, // Used by two jobs
+				{Repository: "old-org/action", WorkflowPath: "sub", Version: "v1.2", FilePath: ".github/workflows/release.yml"},
+				{Repository: "actions/checkout", Version: "v4", FilePath: ".github/workflows/ci.yml"},
+			},
+		},
+		{
+			Name:     "web",
+			FullName: "my-org/web",
+			Actions:  []workflow.ActionReference{{Repository: "actions/checkout", Version: "v4", FilePath: ".github/workflows/ci.yml"}},
+		},
+	}
+
+	plans := PlanMigration(repositories, "old-org/action", "", "new-org/action", "", "v2")
+	if len(plans) != 1 {
+		t.Fatalf("Expected 1 plan (web does not use the action), got %d", len(plans))
+	}
+	if plans[0].Repository.FullName != "my-org/api" || plans[0].Repository.Owner != "my-org" {
+		t.Errorf("Unexpected repository: %+v", plans[0].Repository)
+	}
+	if len(plans[0].Updates) != 2 {
+		t.Fatalf("Expected 2 updates, got %d", len(plans[0].Updates))
+	}
+
+	update := plans[0].Updates[1]
+	if update.TargetRepo != "new-org/action" || update.TargetPath != "sub" || update.TargetVersion != "v2" {
+		t.Errorf("Expected the path to be kept, got %+v", update)
+	}
+	if update.Issue.IssueType != "migration" || update.Issue.MigrationTarget != "new-org/action/sub@v2" {
+		t.Errorf("Unexpected issue: %+v", update.Issue)
+	}
+
+	// A source path restricts the migration to that path
+	if plans := PlanMigration(repositories, "old-org/action", "sub", "new-org/action", "", "v2"); len(plans[0].Updates) != 1 {
+		t.Errorf("Expected 1 update for the sub path, got %d", len(plans[0].Updates))
+	}
+}
+
+func TestPlanMigration_UpdatesWorkflowContent(t *testing.T) {
+	repositories := []output.RepositoryResult{
+		{
+			Name:     "api",
+			FullName: "my-org/api",
+			Actions: []workflow.ActionReference{
+				{Repository: "old-org/action", Version: "v1", FilePath: ".github/workflows/ci.yml"},
+			},
+		},
+	}
+
+	plans := PlanMigration(repositories, "old-org/action", "", "new-org/action", "", "v2")
+	content := "steps:\n  - uses: old-org/action@v1\n"
+	updated := UpdateWorkflowContent(content, plans[0].Updates)
+
+	if !strings.Contains(updated, "uses: new-org/action@v2") {
+		t.Errorf("Expected the action to be replaced, got:\n%s", updated)
+	}
+}
@@ -0,0 +1,86 @@
+package pr
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// MaxBodyLength is the longest pull request body GitHub accepts
+const MaxBodyLength = 65536
+
+// DefaultSectionLimit is how many updates each section of the default body lists before
+// collapsing the rest into a details block
+const DefaultSectionLimit = 25
+
+// OverflowFilePath is where the full list of updates is committed when a body is too long for GitHub
+const OverflowFilePath = ".github/actions-maintainer-updates.md"
+
+// TemplateFuncs are helper functions available to PR body templates
+// `limit N list` keeps the first N updates of a section and `overflow N list` returns the rest,
+// so templates can cap each section, e.g. {{range limit 20 .OutdatedUpdates}}. N <= 0 means no limit.
+// `link "backstage" .Repository` builds a link from the mappings set with Creator.SetLinks.
+var TemplateFuncs = template.FuncMap{
+	"limit":    limitUpdates,
+	"overflow": overflowUpdates,
+	"link":     linkFunc(nil),
+}
+
+// limitUpdates returns the first n updates, or all of them when n is not positive
+func limitUpdates(n int, updates []ActionUpdate) []ActionUpdate {
+	if n <= 0 || len(updates) <= n {
+		return updates
+	}
+	return updates[:n]
+}
+
+// overflowUpdates returns the updates past the first n, or none when n is not positive
+func overflowUpdates(n int, updates []ActionUpdate) []ActionUpdate {
+	if n <= 0 || len(updates) <= n {
+		return nil
+	}
+	return updates[n:]
+}
+
+// BranchFile is a generated file committed to the pull request branch alongside the updated workflows,
+// such as the full update list of a truncated body
+type BranchFile struct {
+	Path    string
+	Content string
+}
+
+// fitBody truncates a body longer than MaxBodyLength at a line boundary, closing any details block the
+// cut leaves open, and ends it with a collapsed note linking to the full list committed to the branch.
+// The returned file holds the full list to commit, and is nil when the body already fits.
+func (c *Creator) fitBody(plan UpdatePlan, branchName, body string) (string, *BranchFile) {
+	if len(body) <= MaxBodyLength {
+		return body, nil
+	}
+
+	overflow := &BranchFile{
+		Path:    OverflowFilePath,
+		Content: fmt.Sprintf("# GitHub Actions Updates for %s\n\n%s\n", plan.Repository.FullName, c.renderDefaultPRBody(plan, 0)),
+	}
+	link := fmt.Sprintf("https://github.com/%s/blob/%s/%s", plan.Repository.FullName, branchName, overflow.Path)
+	notice := fmt.Sprintf("\n\n<details>\n<summary>⚠️ This description was truncated</summary>\n\n"+
+		"The %d updates in this PR are too many to list in full. See [`%s`](%s) on this branch for the complete list.\n</details>\n",
+		len(plan.Updates), overflow.Path, link)
+
+	// Cut at a line boundary, moving back further while the closing tags for cut-open details blocks don't fit
+	head := body[:MaxBodyLength-len(notice)]
+	for {
+		cut := strings.LastIndex(head, "\n")
+		if cut < 0 {
+			cut = 0
+		}
+		head = head[:cut]
+
+		closing := ""
+		for open := strings.Count(head, "<details>") - strings.Count(head, "</details>"); open > 0; open-- {
+			closing += "\n</details>"
+		}
+		if len(head)+len(closing)+len(notice) <= MaxBodyLength {
+			return head + closing + notice, overflow
+		}
+	}
+}
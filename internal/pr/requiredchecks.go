@@ -0,0 +1,111 @@
+package pr
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// RequiredChecksClient looks up the status checks a branch requires and the workflow files that report them
+type RequiredChecksClient interface {
+	GetRequiredStatusChecks(owner, repo, branch string) ([]string, error)
+	GetFileContent(owner, repo, filePath, ref string) (string, error)
+}
+
+// AffectedCheck is a required status check reported by a job whose check names the plan may change
+type AffectedCheck struct {
+	Check    string `json:"check"`     // Required status check name
+	FilePath string `json:"file_path"` // Workflow file containing the job
+	Job      string `json:"job"`       // Id of the job reporting the check
+	Action   string `json:"action"`    // Reusable workflow the plan updates, e.g. "my-org/shared/.github/workflows/build.yml"
+}
+
+// templateExpression matches ${{ ... }} expressions in job names, which are only known when the job runs
+var templateExpression = regexp.MustCompile(`\$\{\{.*?\}\}`)
+
+// FindAffectedChecks returns the required status checks of the plan's target branch that may stop
+// reporting once the pull request merges. Jobs calling a reusable workflow report checks named
+// "<caller> / <called job>", so updating or migrating the called workflow can rename those checks,
+// leaving branch protection waiting for a check that never arrives and deadlocking every merge.
+func FindAffectedChecks(client RequiredChecksClient, plan UpdatePlan) ([]AffectedCheck, error) {
+	// Reusable workflow calls the plan changes, by file and calling job
+	calls := make(map[string]map[string]string)
+	for _, update := range plan.Updates {
+		job, ok := strings.CutPrefix(update.Issue.Context, "job:")
+		if !ok || strings.Contains(job, "/step:") || !strings.Contains(update.WorkflowPath, ".github/workflows/") {
+			continue
+		}
+		if calls[update.FilePath] == nil {
+			calls[update.FilePath] = make(map[string]string)
+		}
+		calls[update.FilePath][job] = joinRefPath(update.ActionRepo, update.WorkflowPath)
+	}
+	if len(calls) == 0 {
+		return nil, nil
+	}
+
+	required, err := client.GetRequiredStatusChecks(plan.Repository.Owner, plan.Repository.Name, plan.TargetBranch())
+	if err != nil {
+		return nil, fmt.Errorf("unable to check required status checks: %w", err)
+	}
+	if len(required) == 0 {
+		return nil, nil
+	}
+
+	ref := plan.TargetBranch()
+	if plan.BaseBranch == "" && plan.ScannedCommit != "" {
+		ref = plan.ScannedCommit
+	}
+
+	paths := make([]string, 0, len(calls))
+	for filePath := range calls {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+
+	var affected []AffectedCheck
+	for _, filePath := range paths {
+		content, err := client.GetFileContent(plan.Repository.Owner, plan.Repository.Name, filePath, ref)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s: %w", filePath, err)
+		}
+		outline, err := workflow.ParseWorkflowOutline(content, filePath, nil)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s: %w", filePath, err)
+		}
+
+		for _, job := range outline.Jobs {
+			action, ok := calls[filePath][job.Name]
+			if !ok {
+				continue
+			}
+			pattern := checkNamePattern(job.CheckName())
+			for _, check := range required {
+				if pattern.MatchString(check) {
+					affected = append(affected, AffectedCheck{Check: check, FilePath: filePath, Job: job.Name, Action: action})
+				}
+			}
+		}
+	}
+	return affected, nil
+}
+
+// checkNamePattern matches the checks reported by the jobs of a reusable workflow called by a job
+// named name: "<name> / <called job>", with an optional matrix suffix "<name> (<values>) / <called job>".
+// Expressions in the name match anything.
+func checkNamePattern(name string) *regexp.Regexp {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	last := 0
+	for _, match := range templateExpression.FindAllStringIndex(name, -1) {
+		pattern.WriteString(regexp.QuoteMeta(name[last:match[0]]))
+		pattern.WriteString(".*")
+		last = match[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(name[last:]))
+	pattern.WriteString(`( \(.*\))? / `)
+	return regexp.MustCompile(pattern.String())
+}
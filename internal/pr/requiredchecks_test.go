@@ -0,0 +1,150 @@
+package pr
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// fakeRequiredChecksClient returns the required checks of a branch and workflow contents
+type fakeRequiredChecksClient struct {
+	checks   []string
+	files    map[string]string
+	err      error
+	branches []string
+	refs     []string
+}
+
+func (f *fakeRequiredChecksClient) GetRequiredStatusChecks(owner, repo, branch string) ([]string, error) {
+	f.branches = append(f.branches, branch)
+	return f.checks, f.err
+}
+
+func (f *fakeRequiredChecksClient) GetFileContent(owner, repo, filePath, ref string) (string, error) {
+	f.refs = append(f.refs, ref)
+	content, ok := f.files[filePath]
+	if !ok {
+		return "", fmt.Errorf("file not found: %s", filePath)
+	}
+	return content, nil
+}
+
+const requiredChecksWorkflow = `
+on: pull_request
+jobs:
+  build:
+    name: Build ${{ matrix.os }}
+    strategy:
+      matrix:
+        os: [ubuntu-latest, windows-latest]
+    uses: my-org/shared/.github/workflows/build.yml@v1
+  deploy:
+    uses: my-org/shared/.github/workflows/deploy.yml@v1
+  lint:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v3
+`
+
+func requiredChecksPlan() UpdatePlan {
+	return UpdatePlan{
+		Repository:    github.Repository{Owner: "my-org", Name: "api", FullName: "my-org/api", DefaultBranch: "main"},
+		ScannedCommit: "head1",
+		Updates: []ActionUpdate{
+			{
+				FilePath: ".github/workflows/ci.yml", ActionRepo: "my-org/shared", WorkflowPath: ".github/workflows/build.yml",
+				CurrentVersion: "v1", TargetVersion: "v2", Issue: output.ActionIssue{Context: "job:build"},
+			},
+			{
+				FilePath: ".github/workflows/ci.yml", ActionRepo: "actions/checkout",
+				CurrentVersion: "v3", TargetVersion: "v4", Issue: output.ActionIssue{Context: "job:lint/step:0"},
+			},
+		},
+	}
+}
+
+func TestFindAffectedChecks(t *testing.T) {
+	client := &fakeRequiredChecksClient{
+		checks: []string{"Build (ubuntu-latest) / compile", "Build windows-latest / compile", "deploy / release", "lint"},
+		files:  map[string]string{".github/workflows/ci.yml": requiredChecksWorkflow},
+	}
+
+	affected, err := FindAffectedChecks(client, requiredChecksPlan())
+	if err != nil {
+		t.Fatalf("FindAffectedChecks() returned error: %v", err)
+	}
+
+	expected := []AffectedCheck{
+		{Check: "Build (ubuntu-latest) / compile", FilePath: ".github/workflows/ci.yml", Job: "build", Action: "my-org/shared/.github/workflows/build.yml"},
+		{Check: "Build windows-latest / compile", FilePath: ".github/workflows/ci.yml", Job: "build", Action: "my-org/shared/.github/workflows/build.yml"},
+	}
+	if !reflect.DeepEqual(affected, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, affected)
+	}
+	if !reflect.DeepEqual(client.branches, []string{"main"}) || !reflect.DeepEqual(client.refs, []string{"head1"}) {
+		t.Errorf("Expected checks of main and the workflow at the scanned commit, got %v and %v", client.branches, client.refs)
+	}
+}
+
+func TestFindAffectedChecks_OnlyStepUpdates(t *testing.T) {
+	client := &fakeRequiredChecksClient{err: fmt.Errorf("should not be called")}
+	plan := requiredChecksPlan()
+	plan.Updates = plan.Updates[1:]
+
+	affected, err := FindAffectedChecks(client, plan)
+	if err != nil || len(affected) != 0 || len(client.branches) != 0 {
+		t.Errorf("Expected no lookups for step updates, got %+v, %v after %v", affected, err, client.branches)
+	}
+}
+
+func TestFindAffectedChecks_BaseBranch(t *testing.T) {
+	client := &fakeRequiredChecksClient{
+		checks: []string{"deploy / release"},
+		files:  map[string]string{".github/workflows/ci.yml": requiredChecksWorkflow},
+	}
+	plan := requiredChecksPlan()
+	plan.BaseBranch = "release/1.x"
+	plan.Updates[0].Issue.Context = "job:deploy"
+	plan.Updates[0].WorkflowPath = ".github/workflows/deploy.yml"
+
+	affected, err := FindAffectedChecks(client, plan)
+	if err != nil {
+		t.Fatalf("FindAffectedChecks() returned error: %v", err)
+	}
+	if len(affected) != 1 || affected[0].Check != "deploy / release" || affected[0].Job != "deploy" {
+		t.Errorf("Expected the deploy check to be affected, got %+v", affected)
+	}
+	if !reflect.DeepEqual(client.branches, []string{"release/1.x"}) || !reflect.DeepEqual(client.refs, []string{"release/1.x"}) {
+		t.Errorf("Expected the base branch to be checked and read, got %v and %v", client.branches, client.refs)
+	}
+}
+
+func TestFindAffectedChecks_LookupError(t *testing.T) {
+	client := &fakeRequiredChecksClient{err: fmt.Errorf("forbidden")}
+	if _, err := FindAffectedChecks(client, requiredChecksPlan()); err == nil {
+		t.Errorf("Expected a lookup error to be returned")
+	}
+}
+
+func TestGenerateDefaultPRBody_RequiredChecks(t *testing.T) {
+	creator := NewCreator(nil)
+	plan := requiredChecksPlan()
+	plan.RequiredChecks = []AffectedCheck{
+		{Check: "Build (ubuntu-latest) / compile", FilePath: ".github/workflows/ci.yml", Job: "build", Action: "my-org/shared/.github/workflows/build.yml"},
+	}
+
+	body := creator.generateDefaultPRBody(plan)
+	if !strings.Contains(body, "### ⚠️ Required Status Checks") ||
+		!strings.Contains(body, "- `Build (ubuntu-latest) / compile`: job `build` in `.github/workflows/ci.yml`") {
+		t.Errorf("Expected the body to warn about the required check, got:\n%s", body)
+	}
+
+	plan.RequiredChecks = nil
+	if body := creator.generateDefaultPRBody(plan); strings.Contains(body, "Required Status Checks") {
+		t.Errorf("Expected no warning without affected checks")
+	}
+}
@@ -0,0 +1,313 @@
+package pr
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/audit"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/patcher"
+)
+
+// ReviewMarker is the comment a review pull request appends to each line with a suggestion. GitHub
+// only accepts review comments on lines a pull request changes, so the no-op pull request changes
+// nothing but these comments; applying a suggestion replaces the line, marker included.
+const ReviewMarker = "# actions-maintainer: update suggested in review"
+
+// ErrNotLineEdit is returned for updates that change more than the lines they update, such as schema
+// patches adding inputs or inserted steps, which a suggestion on a single line cannot express
+var ErrNotLineEdit = errors.New("update adds or removes lines")
+
+// ReviewClient reads the pull requests and files a review is computed from
+type ReviewClient interface {
+	GetFileContent(owner, repo, filePath, ref string) (string, error)
+	GetPullRequest(owner, repo string, number int) (*github.PullRequestInfo, error)
+	ListPullRequestFiles(owner, repo string, number int) ([]github.PullRequestFile, error)
+}
+
+// Suggestion replaces one line of a workflow file in a review comment
+type Suggestion struct {
+	Path        string
+	Line        int // 1-based line number in the reviewed version of the file
+	Original    string
+	Replacement string
+}
+
+// Body returns the review comment of a suggestion, which GitHub renders with a one-click "Commit suggestion"
+func (s Suggestion) Body() string {
+	return "```suggestion\n" + s.Replacement + "\n```"
+}
+
+// Review is a pull request review suggesting a plan's updates line by line
+type Review struct {
+	Plan        UpdatePlan
+	PullRequest *github.PullRequestInfo // Existing pull request reviewed; nil when a no-op pull request is opened for the review
+	Ref         string                  // Commit or branch the suggestions were computed at
+	Suggestions []Suggestion
+	Skipped     []ActionUpdate    // Updates that cannot be suggested on lines of the pull request
+	Annotated   map[string]string // Workflow content of the no-op pull request with ReviewMarker on each suggested line, by path
+}
+
+// Suggestions patches a workflow file with its updates and returns a suggestion for each changed line
+// Updates that add or remove lines return ErrNotLineEdit.
+func Suggestions(wp *patcher.WorkflowPatcher, filePath, content string, updates []ActionUpdate) ([]Suggestion, error) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	patched, _, err := PatchWorkflowContent(wp, content, updates)
+	if err != nil {
+		return nil, err
+	}
+
+	before := strings.Split(content, "\n")
+	after := strings.Split(patched, "\n")
+	if len(before) != len(after) {
+		return nil, ErrNotLineEdit
+	}
+
+	var suggestions []Suggestion
+	for i := range before {
+		if before[i] != after[i] {
+			suggestions = append(suggestions, Suggestion{Path: filePath, Line: i + 1, Original: before[i], Replacement: after[i]})
+		}
+	}
+	return suggestions, nil
+}
+
+// AnnotateForReview appends ReviewMarker to each suggested line of a workflow file
+func AnnotateForReview(content string, suggestions []Suggestion) string {
+	crlf := strings.Contains(content, "\r\n")
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for _, suggestion := range suggestions {
+		if suggestion.Line < 1 || suggestion.Line > len(lines) {
+			continue
+		}
+		lines[suggestion.Line-1] += " " + ReviewMarker
+	}
+	annotated := strings.Join(lines, "\n")
+	if crlf {
+		annotated = strings.ReplaceAll(annotated, "\n", "\r\n")
+	}
+	return annotated
+}
+
+// hunkHeader matches the header of a unified diff hunk, capturing the start of its new side
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// CommentableLines returns the lines of the new version of a file that a review comment can be placed
+// on: those added or shown as context in the pull request's diff of the file
+func CommentableLines(patch string) map[int]bool {
+	lines := make(map[int]bool)
+	line := 0
+	for _, diffLine := range strings.Split(patch, "\n") {
+		if match := hunkHeader.FindStringSubmatch(diffLine); match != nil {
+			line, _ = strconv.Atoi(match[1])
+			continue
+		}
+		if line == 0 || diffLine == "" {
+			continue
+		}
+		switch diffLine[0] {
+		case '+', ' ':
+			lines[line] = true
+			line++
+		}
+	}
+	return lines
+}
+
+// PlanReview computes the suggestions of a plan's updates. With a pull request number the review goes
+// on that open pull request, at its head commit, and only lines its diff shows can take suggestions;
+// otherwise the suggestions are computed at the scanned commit for a new no-op pull request.
+func PlanReview(client ReviewClient, wp *patcher.WorkflowPatcher, plan UpdatePlan, number int) (*Review, error) {
+	review := &Review{Plan: plan, Ref: plan.ScannedCommit}
+	if review.Ref == "" || plan.BaseBranch != "" {
+		review.Ref = plan.TargetBranch()
+	}
+
+	var commentable map[string]map[int]bool
+	if number > 0 {
+		pull, err := client.GetPullRequest(plan.Repository.Owner, plan.Repository.Name, number)
+		if err != nil {
+			return nil, err
+		}
+		if pull.State != "open" {
+			return nil, fmt.Errorf("pull request #%d is %s", number, pull.State)
+		}
+		files, err := client.ListPullRequestFiles(plan.Repository.Owner, plan.Repository.Name, number)
+		if err != nil {
+			return nil, err
+		}
+		commentable = make(map[string]map[int]bool, len(files))
+		for _, file := range files {
+			commentable[file.Path] = CommentableLines(file.Patch)
+		}
+		review.PullRequest = pull
+		review.Ref = pull.HeadSHA
+	} else {
+		review.Annotated = make(map[string]string)
+	}
+
+	byFile := make(map[string][]ActionUpdate)
+	var paths []string
+	for _, update := range plan.Updates {
+		if _, ok := byFile[update.FilePath]; !ok {
+			paths = append(paths, update.FilePath)
+		}
+		byFile[update.FilePath] = append(byFile[update.FilePath], update)
+	}
+	sort.Strings(paths)
+
+	for _, filePath := range paths {
+		updates := byFile[filePath]
+		content, err := client.GetFileContent(plan.Repository.Owner, plan.Repository.Name, filePath, review.Ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+		}
+		suggestions, err := Suggestions(wp, filePath, content, updates)
+		if err != nil {
+			review.Skipped = append(review.Skipped, updates...)
+			continue
+		}
+
+		if commentable != nil {
+			var placed []Suggestion
+			for _, suggestion := range suggestions {
+				if commentable[filePath][suggestion.Line] {
+					placed = append(placed, suggestion)
+				}
+			}
+			// Part of an update outside the diff cannot be suggested, so the file's updates are left whole
+			if len(placed) < len(suggestions) {
+				review.Skipped = append(review.Skipped, updates...)
+				continue
+			}
+		} else if len(suggestions) > 0 {
+			review.Annotated[filePath] = AnnotateForReview(content, suggestions)
+		}
+		review.Suggestions = append(review.Suggestions, suggestions...)
+	}
+	return review, nil
+}
+
+// ReviewBody returns the summary comment of a review
+func ReviewBody(review *Review) string {
+	var body strings.Builder
+	body.WriteString("## GitHub Actions Updates\n\n")
+	body.WriteString(fmt.Sprintf("This review suggests %d line changes updating GitHub Actions to their latest recommended versions. ", len(review.Suggestions)))
+	body.WriteString("Use **Commit suggestion** on each, or **Add suggestion to batch** to apply them in one commit.\n\n")
+
+	if len(review.Skipped) > 0 {
+		body.WriteString("### Not Suggested\n\n")
+		body.WriteString("These updates change more than their own lines, or lines this pull request does not touch, and need a full update pull request:\n\n")
+		for _, update := range review.Skipped {
+			body.WriteString(fmt.Sprintf("- **%s**: %s → %s in `%s`\n", joinRefPath(update.ActionRepo, update.WorkflowPath), update.CurrentVersion, update.TargetVersion, update.FilePath))
+		}
+		body.WriteString("\n")
+	}
+
+	if rules := planRules(review.Plan); len(rules) > 0 {
+		body.WriteString("### 📖 Rules\n\n")
+		for _, rule := range rules {
+			body.WriteString(fmt.Sprintf("- [`%s` %s](%s)\n", rule.ID, rule.IssueType, rule.DocsURL))
+		}
+		body.WriteString("\n")
+	}
+
+	body.WriteString("---\n")
+	body.WriteString("*This review was automatically generated by [actions-maintainer](https://github.com/Jake-Mok-Nelson/actions-maintainer)*")
+	return body.String()
+}
+
+// reviewBranchName returns the head branch of a plan's no-op review pull request
+func reviewBranchName(plan UpdatePlan) string {
+	return strings.Replace(plan.BranchName(), "update-actions-", "review-actions-", 1)
+}
+
+// CreateReviews posts each plan's updates as suggestions in a pull request review instead of pushing
+// them, for repositories where direct pushes are unwelcome. With reviewPR the review goes on that
+// existing pull request; otherwise a no-op pull request marks each line with a suggestion.
+func (c *Creator) CreateReviews(plans []UpdatePlan, reviewPR int) ([]output.CreatedPR, error) {
+	var reviewed []output.CreatedPR
+	for _, plan := range plans {
+		if len(plan.Updates) == 0 {
+			continue
+		}
+		review, err := PlanReview(c.githubClient, c.patcher, plan, reviewPR)
+		if err != nil {
+			fmt.Printf("Failed to review %s: %v\n", plan.Repository.FullName, err)
+			continue
+		}
+		if len(review.Suggestions) == 0 {
+			fmt.Printf("Skipping %s: none of its %d updates can be suggested line by line\n", plan.Repository.FullName, len(plan.Updates))
+			continue
+		}
+
+		reviewed = append(reviewed, c.postReview(review))
+		fmt.Printf("Reviewed %s with %d suggestions (%d updates not suggested)\n", plan.Repository.FullName, len(review.Suggestions), len(review.Skipped))
+	}
+	return reviewed, nil
+}
+
+// postReview opens the no-op pull request of a review when it has none, and posts the review on it
+func (c *Creator) postReview(review *Review) output.CreatedPR {
+	plan := review.Plan
+	createdPR := output.CreatedPR{
+		Repository:  plan.Repository.FullName,
+		BaseBranch:  plan.BaseBranch,
+		UpdateCount: len(review.Suggestions),
+	}
+
+	// As with update pull requests, the branch push and API calls are simulated
+	if review.PullRequest != nil {
+		createdPR.Number = review.PullRequest.Number
+		createdPR.URL = review.PullRequest.URL
+		createdPR.Title = review.PullRequest.Title
+		createdPR.Branch = review.PullRequest.HeadBranch
+	} else {
+		createdPR.Number = 42 // Simulated PR number
+		createdPR.URL = fmt.Sprintf("https://github.com/%s/pull/%d", plan.Repository.FullName, createdPR.Number)
+		createdPR.Title = "Review suggested GitHub Actions updates"
+		if plan.BaseBranch != "" {
+			createdPR.Title = fmt.Sprintf("[%s] %s", plan.BaseBranch, createdPR.Title)
+		}
+		createdPR.Branch = reviewBranchName(plan)
+
+		paths := make([]string, 0, len(review.Annotated))
+		for filePath := range review.Annotated {
+			paths = append(paths, filePath)
+		}
+		sort.Strings(paths)
+		fmt.Printf("Would create review PR for %s:\n", plan.Repository.FullName)
+		fmt.Printf("Branch: %s\n", createdPR.Branch)
+		fmt.Printf("Base: %s\n", plan.TargetBranch())
+		fmt.Printf("Title: %s\n", createdPR.Title)
+		fmt.Printf("Workflows marked for review: %s\n", strings.Join(paths, ", "))
+		c.auditCommit(plan, branchCommit{Branch: createdPR.Branch, Workflows: paths}, createdPR.Number, createdPR.URL)
+	}
+
+	fmt.Printf("Would post review on %s#%d with %d suggestions:\n", plan.Repository.FullName, createdPR.Number, len(review.Suggestions))
+	for _, suggestion := range review.Suggestions {
+		fmt.Printf("  %s:%d: %s\n", suggestion.Path, suggestion.Line, strings.TrimSpace(suggestion.Replacement))
+	}
+	fmt.Printf("Body: %s\n", ReviewBody(review))
+
+	if c.auditLog != nil {
+		event := audit.Event{
+			Action:     audit.ActionReviewPosted,
+			Repository: plan.Repository.FullName,
+			Branch:     createdPR.Branch,
+			BaseBranch: plan.TargetBranch(),
+			URL:        createdPR.URL,
+			PRNumber:   createdPR.Number,
+		}
+		if err := c.auditLog.Record(event); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: audit log: %v\n", err)
+		}
+	}
+	return createdPR
+}
@@ -0,0 +1,165 @@
+package pr
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/patcher"
+)
+
+const reviewWorkflow = `name: CI
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v3
+      - uses: actions/setup-node@v3 # node
+        with:
+          node-version: 20
+`
+
+// fakeReviewClient serves workflow content at refs and a pull request with its diffs
+type fakeReviewClient struct {
+	content map[string]string // Keyed by "path@ref"
+	pull    *github.PullRequestInfo
+	files   []github.PullRequestFile
+}
+
+func (f *fakeReviewClient) GetFileContent(owner, repo, filePath, ref string) (string, error) {
+	content, ok := f.content[filePath+"@"+ref]
+	if !ok {
+		return "", fmt.Errorf("%s not found at %s", filePath, ref)
+	}
+	return content, nil
+}
+
+func (f *fakeReviewClient) GetPullRequest(owner, repo string, number int) (*github.PullRequestInfo, error) {
+	if f.pull == nil || f.pull.Number != number {
+		return nil, fmt.Errorf("pull request #%d not found", number)
+	}
+	return f.pull, nil
+}
+
+func (f *fakeReviewClient) ListPullRequestFiles(owner, repo string, number int) ([]github.PullRequestFile, error) {
+	return f.files, nil
+}
+
+func reviewPlan() UpdatePlan {
+	repositories := []output.RepositoryResult{{
+		Name:          "api",
+		FullName:      "my-org/api",
+		DefaultBranch: "main",
+		WorkflowFiles: []output.WorkflowFileResult{{Path: ".github/workflows/ci.yml", CommitSHA: "head1"}},
+		Issues: []output.ActionIssue{
+			{Repository: "actions/checkout", CurrentVersion: "v3", SuggestedVersion: "v4", IssueType: "outdated", FilePath: ".github/workflows/ci.yml"},
+			{Repository: "actions/setup-node", CurrentVersion: "v3", SuggestedVersion: "v4", IssueType: "outdated", FilePath: ".github/workflows/ci.yml"},
+		},
+	}}
+	return PlanUpdates(repositories)[0]
+}
+
+func TestSuggestions(t *testing.T) {
+	suggestions, err := Suggestions(patcher.NewWorkflowPatcher(), ".github/workflows/ci.yml", reviewWorkflow, reviewPlan().Updates)
+	if err != nil {
+		t.Fatalf("Failed to compute suggestions: %v", err)
+	}
+	if len(suggestions) != 2 {
+		t.Fatalf("Expected 2 suggestions, got %d: %+v", len(suggestions), suggestions)
+	}
+	if suggestions[0].Line != 7 || suggestions[0].Replacement != "      - uses: actions/checkout@v4" {
+		t.Errorf("Expected line 7 updated to actions/checkout@v4, got %+v", suggestions[0])
+	}
+	if suggestions[1].Line != 8 || !strings.Contains(suggestions[1].Replacement, "actions/setup-node@v4 # node") {
+		t.Errorf("Expected line 8 updated with its comment kept, got %+v", suggestions[1])
+	}
+	if body := suggestions[0].Body(); body != "```suggestion\n      - uses: actions/checkout@v4\n```" {
+		t.Errorf("Unexpected suggestion body %q", body)
+	}
+}
+
+func TestSuggestions_NotLineEdit(t *testing.T) {
+	updates := []ActionUpdate{{
+		FilePath:      ".github/workflows/ci.yml",
+		ActionRepo:    "actions/cache",
+		TargetVersion: "v4",
+		Issue: output.ActionIssue{
+			IssueType: "missing-required-action",
+			Insertion: &output.RequiredInsertion{Job: "build"},
+		},
+	}}
+	if _, err := Suggestions(patcher.NewWorkflowPatcher(), ".github/workflows/ci.yml", reviewWorkflow, updates); err != ErrNotLineEdit {
+		t.Errorf("Expected ErrNotLineEdit for an inserted step, got %v", err)
+	}
+}
+
+func TestAnnotateForReview(t *testing.T) {
+	annotated := AnnotateForReview("a\r\nb\r\nc\r\n", []Suggestion{{Line: 2}})
+	if annotated != "a\r\nb "+ReviewMarker+"\r\nc\r\n" {
+		t.Errorf("Expected line 2 marked with CRLF line endings kept, got %q", annotated)
+	}
+}
+
+func TestCommentableLines(t *testing.T) {
+	patch := "@@ -5,4 +5,5 @@ jobs:\n     runs-on: ubuntu-latest\n     steps:\n-      - uses: actions/checkout@v2\n+      - uses: actions/checkout@v3\n+      - run: make\n       - uses: actions/setup-node@v3\n@@ -20,2 +21,2 @@\n-old\n+new\n"
+	lines := CommentableLines(patch)
+	for _, line := range []int{5, 6, 7, 8, 9, 21} {
+		if !lines[line] {
+			t.Errorf("Expected line %d to be commentable", line)
+		}
+	}
+	if lines[10] || lines[4] || len(lines) != 6 {
+		t.Errorf("Expected only the lines of the diff's new side, got %v", lines)
+	}
+}
+
+func TestPlanReview_NoOpPullRequest(t *testing.T) {
+	client := &fakeReviewClient{content: map[string]string{".github/workflows/ci.yml@head1": reviewWorkflow}}
+
+	review, err := PlanReview(client, patcher.NewWorkflowPatcher(), reviewPlan(), 0)
+	if err != nil {
+		t.Fatalf("Failed to plan review: %v", err)
+	}
+	if review.PullRequest != nil || review.Ref != "head1" || len(review.Suggestions) != 2 {
+		t.Fatalf("Expected 2 suggestions at the scanned commit for a new pull request, got %+v", review)
+	}
+	annotated := review.Annotated[".github/workflows/ci.yml"]
+	if strings.Count(annotated, ReviewMarker) != 2 || !strings.Contains(annotated, "actions/checkout@v3 "+ReviewMarker) {
+		t.Errorf("Expected both suggested lines marked, got:\n%s", annotated)
+	}
+	if body := ReviewBody(review); !strings.Contains(body, "suggests 2 line changes") || strings.Contains(body, "Not Suggested") {
+		t.Errorf("Unexpected review body:\n%s", body)
+	}
+}
+
+func TestPlanReview_ExistingPullRequest(t *testing.T) {
+	client := &fakeReviewClient{
+		content: map[string]string{".github/workflows/ci.yml@pr-head": reviewWorkflow},
+		pull:    &github.PullRequestInfo{Number: 7, State: "open", HeadSHA: "pr-head"},
+		// The pull request touches the checkout line but not the setup-node line
+		files: []github.PullRequestFile{{Path: ".github/workflows/ci.yml", Patch: "@@ -6,2 +6,2 @@\n     steps:\n-      - uses: actions/checkout@v2\n+      - uses: actions/checkout@v3\n"}},
+	}
+
+	review, err := PlanReview(client, patcher.NewWorkflowPatcher(), reviewPlan(), 7)
+	if err != nil {
+		t.Fatalf("Failed to plan review: %v", err)
+	}
+	if review.Ref != "pr-head" || review.Annotated != nil {
+		t.Errorf("Expected the review at the pull request head without a new pull request, got %+v", review)
+	}
+	// Both updates of the file are left whole when one of them falls outside the diff
+	if len(review.Suggestions) != 0 || len(review.Skipped) != 2 {
+		t.Errorf("Expected the file's updates skipped, got %d suggestions and %d skipped", len(review.Suggestions), len(review.Skipped))
+	}
+	if body := ReviewBody(review); !strings.Contains(body, "### Not Suggested") || !strings.Contains(body, "actions/setup-node") {
+		t.Errorf("Expected the skipped updates listed, got:\n%s", body)
+	}
+
+	client.pull.State = "closed"
+	if _, err := PlanReview(client, patcher.NewWorkflowPatcher(), reviewPlan(), 7); err == nil {
+		t.Error("Expected an error reviewing a closed pull request")
+	}
+}
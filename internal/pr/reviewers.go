@@ -0,0 +1,128 @@
+package pr
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ownerPattern matches a GitHub user ("octocat") or organization team ("my-org/platform"), optionally prefixed with "@"
+var ownerPattern = regexp.MustCompile(`^@?([A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?)(?:/([A-Za-z0-9_.-]+))?$`)
+
+// Reviewers are the users and teams asked to review a pull request
+type Reviewers struct {
+	Users []string // GitHub logins
+	Teams []string // Team slugs in the repository owner's organization
+}
+
+// Empty reports whether there is nobody to request a review from
+func (r Reviewers) Empty() bool {
+	return len(r.Users) == 0 && len(r.Teams) == 0
+}
+
+// Names lists the reviewers as "user" and "org/team", for display
+func (r Reviewers) Names(org string) []string {
+	names := append([]string(nil), r.Users...)
+	for _, team := range r.Teams {
+		names = append(names, org+"/"+team)
+	}
+	return names
+}
+
+// ValidateOwner checks that an owner names a GitHub user or an "org/team" team
+func ValidateOwner(owner string) error {
+	if !ownerPattern.MatchString(owner) {
+		return fmt.Errorf("invalid owner %q: use a GitHub user such as octocat or a team such as my-org/platform", owner)
+	}
+	return nil
+}
+
+// PlanReviewers returns the owners of the actions a plan updates, deduplicated and sorted, followed by
+// the code owners of the changed files in CODEOWNERS order, up to the plan's maximum number of reviewers
+// Teams must belong to the organization owning the repository, since GitHub only accepts those as
+// reviewers; others are skipped with a warning.
+func PlanReviewers(plan UpdatePlan) Reviewers {
+	users := make(map[string]bool)
+	teams := make(map[string]bool)
+
+	for _, update := range plan.Updates {
+		for _, owner := range update.Issue.Owners {
+			matches := ownerPattern.FindStringSubmatch(owner)
+			switch {
+			case matches == nil:
+				log.Printf("Warning: Ignoring invalid owner %q of %s", owner, update.ActionRepo)
+			case matches[2] == "":
+				users[strings.ToLower(matches[1])] = true
+			case !strings.EqualFold(matches[1], plan.Repository.Owner):
+				log.Printf("Warning: Not requesting review from team %s on %s; teams must belong to the repository's organization", strings.TrimPrefix(owner, "@"), plan.Repository.FullName)
+			default:
+				teams[strings.ToLower(matches[2])] = true
+			}
+		}
+	}
+
+	// Action owners come first, so a limit drops code owners before them
+	reviewers := Reviewers{Users: sortedKeys(users), Teams: sortedKeys(teams)}
+	remaining := -1
+	if plan.MaxReviewers > 0 {
+		reviewers = reviewers.limit(plan.MaxReviewers)
+		remaining = plan.MaxReviewers - len(reviewers.Users) - len(reviewers.Teams)
+	}
+
+	for _, owner := range plan.CodeOwners {
+		if remaining == 0 {
+			break
+		}
+		matches := ownerPattern.FindStringSubmatch(owner)
+		switch {
+		case matches == nil:
+			continue
+		case matches[2] == "":
+			user := strings.ToLower(matches[1])
+			if users[user] {
+				continue
+			}
+			users[user] = true
+			reviewers.Users = append(reviewers.Users, user)
+		case !strings.EqualFold(matches[1], plan.Repository.Owner):
+			log.Printf("Warning: Not requesting review from code owner %s on %s; teams must belong to the repository's organization", owner, plan.Repository.FullName)
+			continue
+		default:
+			team := strings.ToLower(matches[2])
+			if teams[team] {
+				continue
+			}
+			teams[team] = true
+			reviewers.Teams = append(reviewers.Teams, team)
+		}
+		remaining--
+	}
+
+	return reviewers
+}
+
+// limit keeps at most n reviewers, users before teams
+func (r Reviewers) limit(n int) Reviewers {
+	if len(r.Users)+len(r.Teams) <= n {
+		return r
+	}
+	if len(r.Users) >= n {
+		return Reviewers{Users: r.Users[:n]}
+	}
+	return Reviewers{Users: r.Users, Teams: r.Teams[:n-len(r.Users)]}
+}
+
+// sortedKeys returns the keys of a set in order
+func sortedKeys(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,84 @@
+package pr
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+func TestPlanReviewers(t *testing.T) {
+	plan := UpdatePlan{
+		Repository: github.Repository{Owner: "my-org", Name: "api", FullName: "my-org/api"},
+		Updates: []ActionUpdate{
+			{ActionRepo: "my-org/deploy", Issue: output.ActionIssue{Owners: []string{"@my-org/platform", "Octocat"}}},
+			{ActionRepo: "my-org/lint", Issue: output.ActionIssue{Owners: []string{"my-org/Platform", "hubot"}}},
+			{ActionRepo: "other-org/tool", Issue: output.ActionIssue{Owners: []string{"other-org/maintainers"}}},
+			{ActionRepo: "actions/checkout"},
+		},
+	}
+
+	reviewers := PlanReviewers(plan)
+	if !reflect.DeepEqual(reviewers.Users, []string{"hubot", "octocat"}) {
+		t.Errorf("Expected users [hubot octocat], got %v", reviewers.Users)
+	}
+	// Teams outside the repository's organization cannot review
+	if !reflect.DeepEqual(reviewers.Teams, []string{"platform"}) {
+		t.Errorf("Expected teams [platform], got %v", reviewers.Teams)
+	}
+	if names := reviewers.Names("my-org"); !reflect.DeepEqual(names, []string{"hubot", "octocat", "my-org/platform"}) {
+		t.Errorf("Expected names [hubot octocat my-org/platform], got %v", names)
+	}
+}
+
+func TestPlanReviewers_NoOwners(t *testing.T) {
+	plan := UpdatePlan{
+		Repository: github.Repository{Owner: "my-org", FullName: "my-org/api"},
+		Updates:    []ActionUpdate{{ActionRepo: "actions/checkout"}},
+	}
+
+	reviewers := PlanReviewers(plan)
+	if !reviewers.Empty() {
+		t.Errorf("Expected no reviewers, got %+v", reviewers)
+	}
+	if names := reviewers.Names("my-org"); names != nil {
+		t.Errorf("Expected nil names, got %v", names)
+	}
+}
+
+func TestValidateOwner(t *testing.T) {
+	for _, owner := range []string{"octocat", "@octocat", "my-org/platform", "@my-org/release_eng"} {
+		if err := ValidateOwner(owner); err != nil {
+			t.Errorf("ValidateOwner(%q) returned error: %v", owner, err)
+		}
+	}
+	for _, owner := range []string{"", "-octocat", "a/b/c", "my org"} {
+		if err := ValidateOwner(owner); err == nil {
+			t.Errorf("ValidateOwner(%q) expected an error", owner)
+		}
+	}
+}
+
+func TestPlanReviewers_CodeOwners(t *testing.T) {
+	plan := UpdatePlan{
+		Repository: github.Repository{Owner: "my-org", FullName: "my-org/api"},
+		Updates:    []ActionUpdate{{ActionRepo: "my-org/deploy", Issue: output.ActionIssue{Owners: []string{"octocat"}}}},
+		CodeOwners: []string{"my-org/ci", "Octocat", "other-org/ops", "hubot", "my-org/security"},
+	}
+
+	reviewers := PlanReviewers(plan)
+	if names := reviewers.Names("my-org"); !reflect.DeepEqual(names, []string{"octocat", "hubot", "my-org/ci", "my-org/security"}) {
+		t.Errorf("Expected action owners then code owners, got %v", names)
+	}
+
+	// Action owners are kept before code owners when limiting
+	plan.MaxReviewers = 2
+	if names := PlanReviewers(plan).Names("my-org"); !reflect.DeepEqual(names, []string{"octocat", "my-org/ci"}) {
+		t.Errorf("Expected [octocat my-org/ci], got %v", names)
+	}
+	plan.MaxReviewers = 1
+	if names := PlanReviewers(plan).Names("my-org"); !reflect.DeepEqual(names, []string{"octocat"}) {
+		t.Errorf("Expected [octocat], got %v", names)
+	}
+}
@@ -0,0 +1,183 @@
+package pr
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// FileContentClient reads files of a repository at a ref
+type FileContentClient interface {
+	GetFileContent(owner, repo, filePath, ref string) (string, error)
+}
+
+// Kinds of script couplings
+const (
+	CouplingActionTag    = "action-tag"    // The script names an action version the plan updates
+	CouplingWorkflowFile = "workflow-file" // The script names a reusable workflow file the plan moves
+	CouplingAPIVersion   = "api-version"   // The script pins a GitHub API version in a job whose actions the plan updates
+)
+
+// ScriptCoupling is a run step whose script hardcodes something the plan's updates change, so it may
+// break or silently keep using the old version once the pull request merges
+type ScriptCoupling struct {
+	Kind      string `json:"kind"`
+	FilePath  string `json:"file_path"`
+	Context   string `json:"context"`   // Step running the script, "job:<job>/step:<name>"
+	Reference string `json:"reference"` // What the script hardcodes, e.g. "actions/checkout@v3"
+	Line      string `json:"line"`      // Script line with the reference
+	Message   string `json:"message"`
+}
+
+// apiVersionHeader matches GitHub API version headers and preview media types pinned in a script
+var apiVersionHeader = regexp.MustCompile(`(?i)x-github-api-version:\s*[0-9-]+|application/vnd\.github\.[a-z0-9-]+-preview`)
+
+// apiCall matches scripts calling the GitHub REST API
+var apiCall = regexp.MustCompile(`(?i)api\.github\.com|\bgh\s+api\b|/api/v3/`)
+
+// maxCouplingLine is the longest script line kept in a coupling
+const maxCouplingLine = 120
+
+// FindScriptCouplings returns the run steps of the plan's workflow files that hardcode action tags the
+// plan updates, names of reusable workflow files it moves, or GitHub API version headers next to actions
+// it updates. The check is a heuristic for reviewers: scripts calling gh or the REST API with these
+// assumptions keep working on the old versions after the merge, or stop working.
+func FindScriptCouplings(client FileContentClient, plan UpdatePlan) ([]ScriptCoupling, error) {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, update := range plan.Updates {
+		if !seen[update.FilePath] {
+			seen[update.FilePath] = true
+			paths = append(paths, update.FilePath)
+		}
+	}
+	sort.Strings(paths)
+
+	ref := plan.TargetBranch()
+	if plan.BaseBranch == "" && plan.ScannedCommit != "" {
+		ref = plan.ScannedCommit
+	}
+
+	var couplings []ScriptCoupling
+	for _, filePath := range paths {
+		content, err := client.GetFileContent(plan.Repository.Owner, plan.Repository.Name, filePath, ref)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s: %w", filePath, err)
+		}
+		steps, err := workflow.ParseRunSteps(content, filePath, nil)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s: %w", filePath, err)
+		}
+		couplings = append(couplings, scriptCouplings(plan, steps)...)
+	}
+	return couplings, nil
+}
+
+// scriptCouplings checks the run steps of one workflow file against the plan's updates
+func scriptCouplings(plan UpdatePlan, steps []workflow.RunStep) []ScriptCoupling {
+	var couplings []ScriptCoupling
+	seen := make(map[string]bool)
+	add := func(coupling ScriptCoupling) {
+		key := coupling.Context + "\x00" + coupling.Kind + "\x00" + coupling.Reference
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		couplings = append(couplings, coupling)
+	}
+
+	for _, step := range steps {
+		callsAPI := apiCall.MatchString(step.Run)
+		for _, line := range strings.Split(step.Run, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			coupling := ScriptCoupling{FilePath: step.FilePath, Context: step.Context, Line: truncateLine(line)}
+
+			for _, update := range plan.Updates {
+				if update.FilePath != step.FilePath {
+					continue
+				}
+				if update.CurrentVersion != "" && update.CurrentVersion != update.TargetVersion &&
+					strings.Contains(strings.ToLower(line), strings.ToLower(update.ActionRepo)) && containsVersion(line, update.CurrentVersion) {
+					coupling.Kind = CouplingActionTag
+					coupling.Reference = update.ActionRepo + "@" + update.CurrentVersion
+					coupling.Message = fmt.Sprintf("script hardcodes %s, which this pull request updates to %s", coupling.Reference, update.TargetVersion)
+					add(coupling)
+				}
+				if moved := movedWorkflow(update); moved != "" && strings.Contains(line, path.Base(update.WorkflowPath)) {
+					coupling.Kind = CouplingWorkflowFile
+					coupling.Reference = joinRefPath(update.ActionRepo, update.WorkflowPath)
+					coupling.Message = fmt.Sprintf("script names workflow file %s, which this pull request moves to %s", path.Base(update.WorkflowPath), moved)
+					add(coupling)
+				}
+			}
+
+			if header := apiVersionHeader.FindString(line); header != "" && callsAPI {
+				if updated := jobUpdates(plan, step); len(updated) > 0 {
+					coupling.Kind = CouplingAPIVersion
+					coupling.Reference = header
+					coupling.Message = fmt.Sprintf("script pins the GitHub API with %q in a job whose actions this pull request updates (%s); check the updated actions still agree with it",
+						header, strings.Join(updated, ", "))
+					add(coupling)
+				}
+			}
+		}
+	}
+	return couplings
+}
+
+// containsVersion reports whether a line mentions a version as a whole token, so "v1" does not match "v10"
+func containsVersion(line, version string) bool {
+	pattern := regexp.MustCompile(`(^|[^\w.-])` + regexp.QuoteMeta(version) + `($|[^\w.-])`)
+	return pattern.MatchString(line)
+}
+
+// movedWorkflow returns where an update moves a reusable workflow, or "" when it keeps its repository and path
+func movedWorkflow(update ActionUpdate) string {
+	if update.WorkflowPath == "" || !strings.Contains(update.WorkflowPath, ".github/workflows/") {
+		return ""
+	}
+	targetRepo, targetPath := update.ActionRepo, update.WorkflowPath
+	if update.TargetRepo != "" {
+		targetRepo = update.TargetRepo
+	}
+	if update.TargetPath != "" {
+		targetPath = update.TargetPath
+	}
+	if targetRepo == update.ActionRepo && targetPath == update.WorkflowPath {
+		return ""
+	}
+	return joinRefPath(targetRepo, targetPath)
+}
+
+// jobUpdates returns the actions the plan updates in the job of a step
+func jobUpdates(plan UpdatePlan, step workflow.RunStep) []string {
+	job := "job:" + step.Job
+	var actions []string
+	seen := make(map[string]bool)
+	for _, update := range plan.Updates {
+		if update.FilePath != step.FilePath || (update.Issue.Context != job && !strings.HasPrefix(update.Issue.Context, job+"/")) {
+			continue
+		}
+		if !seen[update.ActionRepo] {
+			seen[update.ActionRepo] = true
+			actions = append(actions, update.ActionRepo)
+		}
+	}
+	return actions
+}
+
+// truncateLine shortens a script line for display
+func truncateLine(line string) string {
+	runes := []rune(line)
+	if len(runes) <= maxCouplingLine {
+		return line
+	}
+	return string(runes[:maxCouplingLine]) + "…"
+}
@@ -0,0 +1,94 @@
+package pr
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+const scriptCouplingWorkflow = `
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v3
+      - name: Check release
+        run: |
+          # actions/checkout@v3 is only mentioned in this comment
+          gh api repos/actions/checkout/releases/tags/v3 --jq .name
+          echo "actions/checkout@v30 is a different version"
+      - name: Call API
+        run: |
+          curl -H "X-GitHub-Api-Version: 2022-11-28" https://api.github.com/repos/my-org/api
+  dispatch:
+    runs-on: ubuntu-latest
+    steps:
+      - run: gh workflow run build.yml --repo my-org/shared
+      - run: |
+          curl -H "X-GitHub-Api-Version: 2022-11-28" https://api.github.com/meta
+`
+
+func TestFindScriptCouplings(t *testing.T) {
+	client := &fakeRequiredChecksClient{files: map[string]string{".github/workflows/ci.yml": scriptCouplingWorkflow}}
+	plan := UpdatePlan{
+		Repository:    github.Repository{Owner: "my-org", Name: "api", FullName: "my-org/api", DefaultBranch: "main"},
+		ScannedCommit: "head1",
+		Updates: []ActionUpdate{
+			{
+				FilePath: ".github/workflows/ci.yml", ActionRepo: "actions/checkout",
+				CurrentVersion: "v3", TargetVersion: "v4", Issue: output.ActionIssue{Context: "job:build/step:step-1"},
+			},
+			{
+				FilePath: ".github/workflows/ci.yml", ActionRepo: "my-org/shared", WorkflowPath: ".github/workflows/build.yml",
+				CurrentVersion: "v1", TargetVersion: "v1", TargetRepo: "my-org/platform", Issue: output.ActionIssue{Context: "job:release"},
+			},
+		},
+	}
+
+	couplings, err := FindScriptCouplings(client, plan)
+	if err != nil {
+		t.Fatalf("FindScriptCouplings() returned error: %v", err)
+	}
+
+	var got [][3]string
+	for _, coupling := range couplings {
+		got = append(got, [3]string{coupling.Kind, coupling.Context, coupling.Reference})
+	}
+	expected := [][3]string{
+		{CouplingActionTag, "job:build/step:Check release", "actions/checkout@v3"},
+		{CouplingAPIVersion, "job:build/step:Call API", "X-GitHub-Api-Version: 2022-11-28"},
+		{CouplingWorkflowFile, "job:dispatch/step:step-1", "my-org/shared/.github/workflows/build.yml"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if !reflect.DeepEqual(client.refs, []string{"head1"}) {
+		t.Errorf("Expected the workflow to be read at the scanned commit, got %v", client.refs)
+	}
+	if couplings[2].Message != "script names workflow file build.yml, which this pull request moves to my-org/platform/.github/workflows/build.yml" {
+		t.Errorf("Unexpected message %q", couplings[2].Message)
+	}
+}
+
+func TestGeneratePRBody_ScriptCouplings(t *testing.T) {
+	plan := UpdatePlan{
+		Repository: github.Repository{FullName: "my-org/api", DefaultBranch: "main"},
+		Updates:    []ActionUpdate{{FilePath: ".github/workflows/ci.yml", ActionRepo: "actions/checkout", CurrentVersion: "v3", TargetVersion: "v4"}},
+		ScriptCouplings: []ScriptCoupling{{
+			Kind: CouplingActionTag, FilePath: ".github/workflows/ci.yml", Context: "job:build/step:Check release",
+			Reference: "actions/checkout@v3", Line: "gh api repos/actions/checkout/releases/tags/v3",
+			Message: "script hardcodes actions/checkout@v3, which this pull request updates to v4",
+		}},
+	}
+
+	body := NewCreator(nil).generatePRBody(plan)
+	for _, expected := range []string{"### ⚠️ Scripts Coupled to Updated Versions", "script hardcodes actions/checkout@v3", "`gh api repos/actions/checkout/releases/tags/v3`"} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected %q in the body, got:\n%s", expected, body)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package priority
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// Weights set how much each factor contributes to an issue's priority score
+// Each factor is rated from 0 to 1 and the score is their weighted average, scaled to 0-100,
+// so only the ratio between weights matters. A zero weight ignores a factor.
+type Weights struct {
+	Severity        float64 `json:"severity"`
+	Popularity      float64 `json:"popularity"`       // Share of scanned repositories using the action
+	Deploy          float64 `json:"deploy"`           // The workflow deploys to an environment
+	Trigger         float64 `json:"trigger"`          // How exposed the workflow's triggers are
+	VersionDistance float64 `json:"version_distance"` // How far the pinned version lags the suggestion
+}
+
+// DefaultWeights favor severity, then deploy workflows
+var DefaultWeights = Weights{
+	Severity:        40,
+	Popularity:      15,
+	Deploy:          20,
+	Trigger:         10,
+	VersionDistance: 15,
+}
+
+// severityRatings rate issue severities
+var severityRatings = map[string]float64{
+	"critical": 1,
+	"high":     0.75,
+	"medium":   0.5,
+	"low":      0.25,
+}
+
+// privilegedEvents run with a write token and secrets on input from outside the repository
+var privilegedEvents = map[string]bool{
+	"pull_request_target":         true,
+	"workflow_run":                true,
+	"issue_comment":               true,
+	"issues":                      true,
+	"pull_request_review_comment": true,
+	"discussion":                  true,
+	"discussion_comment":          true,
+}
+
+// manualEvents only run when a person or another workflow starts them
+var manualEvents = map[string]bool{
+	"workflow_dispatch":   true,
+	"repository_dispatch": true,
+	"workflow_call":       true,
+}
+
+// maxMajorsBehind is the version distance rated as the furthest behind
+const maxMajorsBehind = 4
+
+// LoadWeights loads weights from a JSON file; factors the file leaves out keep their default weight
+func LoadWeights(filename string) (Weights, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return Weights{}, fmt.Errorf("unable to read priority weights: %w", err)
+	}
+
+	weights := DefaultWeights
+	if err := json.Unmarshal(data, &weights); err != nil {
+		return Weights{}, fmt.Errorf("unable to parse priority weights: %w", err)
+	}
+	if err := weights.Validate(); err != nil {
+		return Weights{}, err
+	}
+	return weights, nil
+}
+
+// Validate checks that no weight is negative and at least one is positive
+func (w Weights) Validate() error {
+	for name, weight := range map[string]float64{
+		"severity":         w.Severity,
+		"popularity":       w.Popularity,
+		"deploy":           w.Deploy,
+		"trigger":          w.Trigger,
+		"version_distance": w.VersionDistance,
+	} {
+		if weight < 0 {
+			return fmt.Errorf("invalid priority weights: %s must not be negative", name)
+		}
+	}
+	if w.total() == 0 {
+		return fmt.Errorf("invalid priority weights: at least one weight must be positive")
+	}
+	return nil
+}
+
+// total returns the sum of the weights
+func (w Weights) total() float64 {
+	return w.Severity + w.Popularity + w.Deploy + w.Trigger + w.VersionDistance
+}
+
+// Score sets the priority score of every issue in place
+// Popularity is measured across the given repositories, so all of them are scored together.
+func Score(repositories []output.RepositoryResult, weights Weights) {
+	users := make(map[string]int)
+	for _, repo := range repositories {
+		seen := make(map[string]bool)
+		for _, action := range repo.Actions {
+			if !action.Local && !seen[action.Repository] {
+				seen[action.Repository] = true
+				users[action.Repository]++
+			}
+		}
+	}
+
+	for i := range repositories {
+		repo := &repositories[i]
+		deploys := make(map[string]bool)
+		for _, environment := range repo.Environments {
+			deploys[environment.FilePath] = true
+		}
+		triggers := make(map[string][]string)
+		for _, trigger := range repo.Triggers {
+			triggers[trigger.FilePath] = trigger.Events
+		}
+
+		for j := range repo.Issues {
+			issue := &repo.Issues[j]
+			issue.PriorityScore = ratings{
+				severity:        severityRatings[issue.Severity],
+				popularity:      float64(users[issue.Repository]) / float64(len(repositories)),
+				deploy:          rateBool(deploys[issue.FilePath]),
+				trigger:         rateTriggers(triggers[issue.FilePath]),
+				versionDistance: rateVersionDistance(*issue),
+			}.score(weights)
+		}
+	}
+}
+
+// ratings are an issue's factors, each rated from 0 to 1
+type ratings struct {
+	severity, popularity, deploy, trigger, versionDistance float64
+}
+
+// score combines the ratings into a 0-100 score
+func (r ratings) score(weights Weights) int {
+	total := weights.total()
+	if total == 0 {
+		return 0
+	}
+	sum := r.severity*weights.Severity +
+		r.popularity*weights.Popularity +
+		r.deploy*weights.Deploy +
+		r.trigger*weights.Trigger +
+		r.versionDistance*weights.VersionDistance
+	return int(math.Round(100 * sum / total))
+}
+
+// rateBool rates a yes/no factor
+func rateBool(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// rateTriggers rates a workflow's events: privileged events highest, then automatic ones, then manual ones
+func rateTriggers(events []string) float64 {
+	rating := 0.0
+	for _, event := range events {
+		switch {
+		case privilegedEvents[event]:
+			return 1
+		case manualEvents[event]:
+			rating = math.Max(rating, 0.25)
+		default:
+			rating = math.Max(rating, 0.5)
+		}
+	}
+	return rating
+}
+
+// rateVersionDistance rates how many major versions an issue's pin lags its suggestion,
+// falling back to the days between releases when versions have no major number
+func rateVersionDistance(issue output.ActionIssue) float64 {
+	if issue.SuggestedVersion == "" {
+		return 0
+	}
+	if behind, ok := output.MajorVersionsBehind(issue); ok {
+		if behind == 0 {
+			return 1.0 / (2 * maxMajorsBehind) // A minor or patch update
+		}
+		return math.Min(float64(behind)/maxMajorsBehind, 1)
+	}
+	if issue.DaysBehind > 0 {
+		return math.Min(float64(issue.DaysBehind)/365, 1)
+	}
+	return 0
+}
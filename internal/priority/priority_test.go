@@ -0,0 +1,116 @@
+package priority
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+func scoredRepositories() []output.RepositoryResult {
+	return []output.RepositoryResult{
+		{
+			FullName: "my-org/api",
+			Actions: []workflow.ActionReference{
+				{Repository: "actions/checkout", Version: "v2"},
+				{Repository: "old/deploy-action", Version: "v1"},
+			},
+			Triggers: []workflow.TriggerInfo{
+				{FilePath: ".github/workflows/deploy.yml", Events: []string{"push"}},
+				{FilePath: ".github/workflows/manual.yml", Events: []string{"workflow_dispatch"}},
+			},
+			Environments: []workflow.EnvironmentUsage{
+				{FilePath: ".github/workflows/deploy.yml", Job: "deploy", Environment: "production"},
+			},
+			Issues: []output.ActionIssue{
+				{Repository: "actions/checkout", CurrentVersion: "v2", SuggestedVersion: "v4", IssueType: "outdated", Severity: "medium", FilePath: ".github/workflows/deploy.yml"},
+				{Repository: "old/deploy-action", CurrentVersion: "v1", SuggestedVersion: "v1.2", IssueType: "outdated", Severity: "medium", FilePath: ".github/workflows/manual.yml"},
+			},
+		},
+		{
+			FullName: "my-org/web",
+			Actions: []workflow.ActionReference{
+				{Repository: "actions/checkout", Version: "v4"},
+			},
+		},
+	}
+}
+
+func TestScore(t *testing.T) {
+	repositories := scoredRepositories()
+	Score(repositories, DefaultWeights)
+
+	deploy, manual := repositories[0].Issues[0], repositories[0].Issues[1]
+	// severity 0.5*40 + popularity 1*15 + deploy 1*20 + trigger 0.5*10 + distance 0.5*15 = 67.5 of 100
+	if deploy.PriorityScore != 68 {
+		t.Errorf("Expected deploy workflow issue to score 68, got %d", deploy.PriorityScore)
+	}
+	// severity 0.5*40 + popularity 0.5*15 + trigger 0.25*10 + distance 0.125*15 = 31.875 of 100
+	if manual.PriorityScore != 32 {
+		t.Errorf("Expected manual workflow issue to score 32, got %d", manual.PriorityScore)
+	}
+}
+
+func TestScore_Weights(t *testing.T) {
+	repositories := scoredRepositories()
+	Score(repositories, Weights{Deploy: 1})
+
+	if repositories[0].Issues[0].PriorityScore != 100 || repositories[0].Issues[1].PriorityScore != 0 {
+		t.Errorf("Expected only the deploy factor to count, got %+v", repositories[0].Issues)
+	}
+}
+
+func TestRateTriggers(t *testing.T) {
+	tests := []struct {
+		events   []string
+		expected float64
+	}{
+		{nil, 0},
+		{[]string{"workflow_dispatch"}, 0.25},
+		{[]string{"push", "workflow_dispatch"}, 0.5},
+		{[]string{"workflow_dispatch", "schedule"}, 0.5},
+		{[]string{"push", "pull_request_target"}, 1},
+	}
+
+	for _, tt := range tests {
+		if got := rateTriggers(tt.events); got != tt.expected {
+			t.Errorf("rateTriggers(%v) = %v, expected %v", tt.events, got, tt.expected)
+		}
+	}
+}
+
+func TestLoadWeights(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "weights.json")
+	if err := os.WriteFile(filename, []byte(`{"deploy": 50, "popularity": 0}`), 0o644); err != nil {
+		t.Fatalf("Failed to write weights: %v", err)
+	}
+
+	weights, err := LoadWeights(filename)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := DefaultWeights
+	expected.Deploy = 50
+	expected.Popularity = 0
+	if weights != expected {
+		t.Errorf("Expected %+v, got %+v", expected, weights)
+	}
+}
+
+func TestLoadWeights_Invalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"negative": `{"severity": -1}`,
+		"all zero": `{"severity": 0, "popularity": 0, "deploy": 0, "trigger": 0, "version_distance": 0}`,
+		"not json": `severity: 1`,
+	} {
+		filename := filepath.Join(t.TempDir(), "weights.json")
+		if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write weights: %v", err)
+		}
+		if _, err := LoadWeights(filename); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
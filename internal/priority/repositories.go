@@ -0,0 +1,125 @@
+package priority
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+)
+
+// Repository orders for scan --order-by, which scans the most important repositories first when a
+// budget limits how many are scanned
+const (
+	OrderPushed   = "pushed"    // Most recently pushed first
+	OrderIssues   = "issues"    // Most open issues in the baseline scan first
+	OrderProperty = "property:" // By a custom property, "property:<name>" or "property:<name>=<first>,<second>,..."
+)
+
+// RepositoryOrder is a parsed --order-by value
+type RepositoryOrder struct {
+	By       string   // OrderPushed, OrderIssues, or OrderProperty
+	Property string   // Custom property name when ordering by property
+	Values   []string // Property values in priority order; empty orders values ascending, numbers numerically
+}
+
+// ParseRepositoryOrder parses an --order-by value; an empty value keeps the listed order and returns nil
+func ParseRepositoryOrder(value string) (*RepositoryOrder, error) {
+	switch {
+	case value == "":
+		return nil, nil
+	case value == OrderPushed, value == OrderIssues:
+		return &RepositoryOrder{By: value}, nil
+	case strings.HasPrefix(value, OrderProperty):
+		name, values, hasValues := strings.Cut(strings.TrimPrefix(value, OrderProperty), "=")
+		order := &RepositoryOrder{By: OrderProperty, Property: strings.TrimSpace(name)}
+		if order.Property == "" {
+			return nil, fmt.Errorf("invalid order %q: name the custom property, as in property:priority", value)
+		}
+		if hasValues {
+			for _, v := range strings.Split(values, ",") {
+				if v = strings.TrimSpace(v); v != "" {
+					order.Values = append(order.Values, v)
+				}
+			}
+			if len(order.Values) == 0 {
+				return nil, fmt.Errorf("invalid order %q: list the property values in priority order", value)
+			}
+		}
+		return order, nil
+	}
+	return nil, fmt.Errorf("invalid order %q: use %s, %s, or %s<name>[=<values>]", value, OrderPushed, OrderIssues, OrderProperty)
+}
+
+// OrderRepositories sorts repositories in place by the order. Ties, and repositories without the
+// property being ordered on, keep their listed order, with the latter last. issueCounts returns a
+// repository's open issues from a previous scan and is only used when ordering by issues.
+func OrderRepositories(repositories []github.Repository, order *RepositoryOrder, issueCounts func(fullName string) int) {
+	if order == nil {
+		return
+	}
+
+	switch order.By {
+	case OrderPushed:
+		sort.SliceStable(repositories, func(i, j int) bool {
+			return repositories[i].PushedAt.After(repositories[j].PushedAt)
+		})
+	case OrderIssues:
+		sort.SliceStable(repositories, func(i, j int) bool {
+			return issueCounts(repositories[i].FullName) > issueCounts(repositories[j].FullName)
+		})
+	case OrderProperty:
+		sort.SliceStable(repositories, func(i, j int) bool {
+			return order.propertyLess(repositories[i].CustomProperties[order.Property], repositories[j].CustomProperties[order.Property])
+		})
+	}
+}
+
+// propertyLess reports whether a repository with property value a comes before one with value b
+func (o *RepositoryOrder) propertyLess(a, b string) bool {
+	if len(o.Values) > 0 {
+		return o.rank(a) < o.rank(b)
+	}
+	if a == "" || b == "" {
+		return a != "" && b == ""
+	}
+	numberA, errA := strconv.ParseFloat(a, 64)
+	numberB, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		return numberA < numberB
+	}
+	return a < b
+}
+
+// rank returns the position of a property value in the listed values, past the end when unlisted
+func (o *RepositoryOrder) rank(value string) int {
+	for i, v := range o.Values {
+		if strings.EqualFold(v, value) {
+			return i
+		}
+	}
+	return len(o.Values)
+}
+
+// LimitRepositories returns at most max repositories for a trial scan: the first max, or with sample a
+// random sample of max in their listed order, reproducible with the same seed. max <= 0 keeps them all.
+func LimitRepositories(repositories []github.Repository, max int, sample bool, seed uint64) []github.Repository {
+	if max <= 0 || len(repositories) <= max {
+		return repositories
+	}
+	if !sample {
+		return repositories[:max]
+	}
+
+	random := rand.New(rand.NewPCG(seed, seed))
+	picked := random.Perm(len(repositories))[:max]
+	sort.Ints(picked)
+
+	sampled := make([]github.Repository, max)
+	for i, index := range picked {
+		sampled[i] = repositories[index]
+	}
+	return sampled
+}
@@ -0,0 +1,91 @@
+package priority
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+)
+
+func repositoryNames(repositories []github.Repository) []string {
+	names := make([]string, len(repositories))
+	for i, repo := range repositories {
+		names[i] = repo.Name
+	}
+	return names
+}
+
+func orderedRepositories() []github.Repository {
+	day := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	return []github.Repository{
+		{Name: "docs", FullName: "my-org/docs", PushedAt: day, CustomProperties: map[string]string{"tier": "low", "priority": "10"}},
+		{Name: "api", FullName: "my-org/api", PushedAt: day.Add(48 * time.Hour), CustomProperties: map[string]string{"tier": "critical", "priority": "1"}},
+		{Name: "legacy", FullName: "my-org/legacy"},
+		{Name: "web", FullName: "my-org/web", PushedAt: day.Add(24 * time.Hour), CustomProperties: map[string]string{"tier": "high", "priority": "2"}},
+	}
+}
+
+func TestOrderRepositories(t *testing.T) {
+	issueCounts := map[string]int{"my-org/web": 12, "my-org/legacy": 3}
+	tests := []struct {
+		order    string
+		expected []string
+	}{
+		{"", []string{"docs", "api", "legacy", "web"}},
+		{"pushed", []string{"api", "web", "docs", "legacy"}},
+		{"issues", []string{"web", "legacy", "docs", "api"}},
+		{"property:priority", []string{"api", "web", "docs", "legacy"}}, // Numbers compare numerically
+		{"property:tier=critical,high", []string{"api", "web", "docs", "legacy"}},
+	}
+	for _, tt := range tests {
+		order, err := ParseRepositoryOrder(tt.order)
+		if err != nil {
+			t.Fatalf("ParseRepositoryOrder(%q): expected no error, got %v", tt.order, err)
+		}
+		repositories := orderedRepositories()
+		OrderRepositories(repositories, order, func(fullName string) int { return issueCounts[fullName] })
+		if names := repositoryNames(repositories); !reflect.DeepEqual(names, tt.expected) {
+			t.Errorf("Order %q: expected %v, got %v", tt.order, tt.expected, names)
+		}
+	}
+}
+
+func TestParseRepositoryOrder_Invalid(t *testing.T) {
+	for _, value := range []string{"stars", "property:", "property:tier="} {
+		if _, err := ParseRepositoryOrder(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestLimitRepositories(t *testing.T) {
+	repositories := orderedRepositories()
+
+	if got := repositoryNames(LimitRepositories(repositories, 0, false, 0)); len(got) != 4 {
+		t.Errorf("Expected no limit to keep every repository, got %v", got)
+	}
+	if got := repositoryNames(LimitRepositories(repositories, 10, true, 1)); len(got) != 4 {
+		t.Errorf("Expected a limit above the count to keep every repository, got %v", got)
+	}
+	if got, expected := repositoryNames(LimitRepositories(repositories, 2, false, 0)), []string{"docs", "api"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected the first repositories %v, got %v", expected, got)
+	}
+
+	sampled := repositoryNames(LimitRepositories(repositories, 3, true, 42))
+	if len(sampled) != 3 {
+		t.Fatalf("Expected a sample of 3, got %v", sampled)
+	}
+	if again := repositoryNames(LimitRepositories(repositories, 3, true, 42)); !reflect.DeepEqual(sampled, again) {
+		t.Errorf("Expected the same seed to pick the same sample, got %v and %v", sampled, again)
+	}
+	position := map[string]int{"docs": 0, "api": 1, "legacy": 2, "web": 3}
+	for i := 1; i < len(sampled); i++ {
+		if position[sampled[i-1]] >= position[sampled[i]] {
+			t.Errorf("Expected the sample to keep the listed order, got %v", sampled)
+		}
+	}
+	if names := repositoryNames(repositories); !reflect.DeepEqual(names, []string{"docs", "api", "legacy", "web"}) {
+		t.Errorf("Expected sampling to leave the repositories unchanged, got %v", names)
+	}
+}
@@ -0,0 +1,322 @@
+package registry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/actions"
+)
+
+// DefaultTimeout bounds a registry request when no HTTP client is supplied
+const DefaultTimeout = 30 * time.Second
+
+// Mapping describes where rule fields live in a registry's JSON entries
+//
+// EntriesPath is a dot-separated path to the array of entries (empty when the
+// document itself is the array). Each field names the entry key holding that value.
+type Mapping struct {
+	EntriesPath    string `json:"entries_path,omitempty"`
+	Repository     string `json:"repository,omitempty"`
+	LatestVersion  string `json:"latest_version,omitempty"`
+	MinimumVersion string `json:"minimum_version,omitempty"`
+	BannedVersions string `json:"banned_versions,omitempty"`
+	Recommendation string `json:"recommendation,omitempty"`
+}
+
+// DefaultMapping matches registries whose entries use the rules file field names
+var DefaultMapping = Mapping{
+	Repository:     "repository",
+	LatestVersion:  "latest_version",
+	MinimumVersion: "minimum_version",
+	BannedVersions: "banned_versions",
+	Recommendation: "recommendation",
+}
+
+// Config holds configuration options for registry sync
+type Config struct {
+	Verbose    bool
+	URL        string
+	Token      string       // Optional bearer token
+	Mapping    *Mapping     // Nil uses DefaultMapping
+	CacheDir   string       // Where responses and ETags are cached; empty disables caching
+	HTTPClient *http.Client // Nil uses a client with DefaultTimeout
+}
+
+// Client fetches approved action versions from an internal registry
+type Client struct {
+	url        string
+	token      string
+	mapping    Mapping
+	cacheDir   string
+	httpClient *http.Client
+	verbose    bool
+}
+
+// cachedResponse is a registry response stored for conditional requests
+type cachedResponse struct {
+	URL       string          `json:"url"`
+	ETag      string          `json:"etag"`
+	FetchedAt time.Time       `json:"fetched_at"`
+	Body      json.RawMessage `json:"body"`
+}
+
+// NewClient creates a registry client
+func NewClient(config *Config) *Client {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	mapping := DefaultMapping
+	if config.Mapping != nil {
+		mapping = config.Mapping.withDefaults()
+	}
+
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: DefaultTimeout}
+	}
+
+	return &Client{
+		url:        config.URL,
+		token:      config.Token,
+		mapping:    mapping,
+		cacheDir:   config.CacheDir,
+		httpClient: httpClient,
+		verbose:    config.Verbose,
+	}
+}
+
+// DefaultCacheDir returns the per-user directory for cached registry responses
+func DefaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "actions-maintainer", "registry")
+}
+
+// LoadMappingFile loads a field mapping from a JSON file
+func LoadMappingFile(filename string) (*Mapping, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read registry mapping file: %w", err)
+	}
+
+	var mapping Mapping
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		return nil, fmt.Errorf("unable to parse registry mapping file as JSON: %w", err)
+	}
+	return &mapping, nil
+}
+
+// withDefaults fills unset fields from DefaultMapping
+func (m Mapping) withDefaults() Mapping {
+	if m.Repository == "" {
+		m.Repository = DefaultMapping.Repository
+	}
+	if m.LatestVersion == "" {
+		m.LatestVersion = DefaultMapping.LatestVersion
+	}
+	if m.MinimumVersion == "" {
+		m.MinimumVersion = DefaultMapping.MinimumVersion
+	}
+	if m.BannedVersions == "" {
+		m.BannedVersions = DefaultMapping.BannedVersions
+	}
+	if m.Recommendation == "" {
+		m.Recommendation = DefaultMapping.Recommendation
+	}
+	return m
+}
+
+// FetchRules downloads the registry and converts its entries to rules
+// A cached copy is reused when the registry answers 304 Not Modified, and as a
+// fallback when the registry cannot be reached.
+func (c *Client) FetchRules() ([]actions.Rule, error) {
+	body, err := c.fetch()
+	if err != nil {
+		return nil, err
+	}
+	return ParseRules(body, c.mapping)
+}
+
+// fetch returns the registry document, using the ETag cache when possible
+func (c *Client) fetch() ([]byte, error) {
+	cached := c.readCache()
+
+	req, err := http.NewRequest(http.MethodGet, c.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry URL: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if cached != nil && cached.ETag != "" {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if cached != nil {
+			log.Printf("Warning: registry unreachable, using copy cached at %s: %v", cached.FetchedAt.Format(time.RFC3339), err)
+			return cached.Body, nil
+		}
+		return nil, fmt.Errorf("failed to fetch registry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		if c.verbose {
+			log.Printf("Registry not modified since %s (ETag %s), using cached copy", cached.FetchedAt.Format(time.RFC3339), cached.ETag)
+		}
+		return cached.Body, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("registry returned %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry response: %w", err)
+	}
+	if !json.Valid(body) {
+		return nil, fmt.Errorf("registry response is not valid JSON")
+	}
+
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		c.writeCache(&cachedResponse{URL: c.url, ETag: etag, FetchedAt: time.Now(), Body: body})
+	}
+
+	return body, nil
+}
+
+// cachePath returns the cache file for the registry URL, or "" when caching is disabled
+func (c *Client) cachePath() string {
+	if c.cacheDir == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(c.url))
+	return filepath.Join(c.cacheDir, hex.EncodeToString(sum[:8])+".json")
+}
+
+// readCache returns the cached response for the registry URL, if any
+func (c *Client) readCache() *cachedResponse {
+	path := c.cachePath()
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var cached cachedResponse
+	if err := json.Unmarshal(data, &cached); err != nil || cached.URL != c.url {
+		return nil
+	}
+	return &cached
+}
+
+// writeCache stores a response; failures only disable caching for this run
+func (c *Client) writeCache(cached *cachedResponse) {
+	path := c.cachePath()
+	if path == "" {
+		return
+	}
+
+	data, err := json.Marshal(cached)
+	if err == nil {
+		if err = os.MkdirAll(filepath.Dir(path), 0o755); err == nil {
+			err = os.WriteFile(path, data, 0o644)
+		}
+	}
+	if err != nil && c.verbose {
+		log.Printf("Failed to cache registry response: %v", err)
+	}
+}
+
+// ParseRules converts a registry document into rules using the given mapping
+// Banned versions become deprecated versions. Entries without a repository or
+// latest version are rejected, matching rules file validation.
+func ParseRules(data []byte, mapping Mapping) ([]actions.Rule, error) {
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("unable to parse registry as JSON: %w", err)
+	}
+
+	entries := document
+	if mapping.EntriesPath != "" {
+		for _, key := range strings.Split(mapping.EntriesPath, ".") {
+			object, ok := entries.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("registry entries path %q not found", mapping.EntriesPath)
+			}
+			entries = object[key]
+		}
+	}
+
+	list, ok := entries.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("registry entries must be a JSON array")
+	}
+
+	rules := make([]actions.Rule, 0, len(list))
+	for i, item := range list {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("registry entry %d: expected an object", i+1)
+		}
+
+		rule := actions.Rule{
+			Repository:         stringField(entry, mapping.Repository),
+			LatestVersion:      stringField(entry, mapping.LatestVersion),
+			MinimumVersion:     stringField(entry, mapping.MinimumVersion),
+			DeprecatedVersions: stringListField(entry, mapping.BannedVersions),
+			Recommendation:     stringField(entry, mapping.Recommendation),
+		}
+		if rule.Repository == "" {
+			return nil, fmt.Errorf("registry entry %d: %s field is required", i+1, mapping.Repository)
+		}
+		if rule.LatestVersion == "" {
+			return nil, fmt.Errorf("registry entry %d: %s field is required for repository %s", i+1, mapping.LatestVersion, rule.Repository)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// stringField returns a string entry value, or ""
+func stringField(entry map[string]interface{}, key string) string {
+	value, _ := entry[key].(string)
+	return value
+}
+
+// stringListField returns a list entry value, accepting a single string as a one-item list
+func stringListField(entry map[string]interface{}, key string) []string {
+	switch value := entry[key].(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		var values []string
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
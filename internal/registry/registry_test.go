@@ -0,0 +1,108 @@
+package registry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const registryDocument = `{"data": {"actions": [
+  {"name": "actions/checkout", "latest": "v4", "minimum": "v3", "banned": ["v1"], "notes": "Use v4"},
+  {"name": "actions/setup-node", "latest": "v4", "banned": "v2"}
+]}}`
+
+func TestParseRules_CustomMapping(t *testing.T) {
+	rules, err := ParseRules([]byte(registryDocument), Mapping{
+		EntriesPath: "data.actions",
+		Repository:  "name", LatestVersion: "latest", MinimumVersion: "minimum",
+		BannedVersions: "banned", Recommendation: "notes",
+	})
+	if err != nil {
+		t.Fatalf("ParseRules() returned error: %v", err)
+	}
+
+	if len(rules) != 2 {
+		t.Fatalf("Expected 2 rules, got %d", len(rules))
+	}
+	if rules[0].Repository != "actions/checkout" || rules[0].LatestVersion != "v4" || rules[0].MinimumVersion != "v3" || rules[0].Recommendation != "Use v4" {
+		t.Errorf("Unexpected rule: %+v", rules[0])
+	}
+	if len(rules[0].DeprecatedVersions) != 1 || rules[0].DeprecatedVersions[0] != "v1" {
+		t.Errorf("Expected banned versions to become deprecated versions, got %v", rules[0].DeprecatedVersions)
+	}
+	if len(rules[1].DeprecatedVersions) != 1 || rules[1].DeprecatedVersions[0] != "v2" {
+		t.Errorf("Expected a single banned version string to be accepted, got %v", rules[1].DeprecatedVersions)
+	}
+}
+
+func TestParseRules_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+	}{
+		{"not an array", `{"repository": "actions/checkout"}`},
+		{"missing repository", `[{"latest_version": "v4"}]`},
+		{"missing latest version", `[{"repository": "actions/checkout"}]`},
+		{"invalid JSON", `not json`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseRules([]byte(tt.document), DefaultMapping); err == nil {
+				t.Errorf("Expected error for %s", tt.name)
+			}
+		})
+	}
+}
+
+func TestFetchRules_ETagCaching(t *testing.T) {
+	requests, notModified := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("Expected bearer token, got %q", r.Header.Get("Authorization"))
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`[{"repository": "actions/checkout", "latest_version": "v4"}]`))
+	}))
+
+	client := NewClient(&Config{URL: server.URL, Token: "secret", CacheDir: t.TempDir()})
+	for i := 0; i < 2; i++ {
+		rules, err := client.FetchRules()
+		if err != nil {
+			t.Fatalf("FetchRules() returned error: %v", err)
+		}
+		if len(rules) != 1 || rules[0].Repository != "actions/checkout" {
+			t.Errorf("Unexpected rules: %+v", rules)
+		}
+	}
+	if requests != 2 || notModified != 1 {
+		t.Errorf("Expected the second request to be conditional (requests=%d, not modified=%d)", requests, notModified)
+	}
+
+	// The cached copy is used when the registry is unreachable
+	server.Close()
+	rules, err := client.FetchRules()
+	if err != nil {
+		t.Fatalf("Expected cached fallback, got error: %v", err)
+	}
+	if len(rules) != 1 {
+		t.Errorf("Expected cached rules, got %+v", rules)
+	}
+}
+
+func TestFetchRules_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	if _, err := NewClient(&Config{URL: server.URL}).FetchRules(); err == nil {
+		t.Errorf("Expected error for forbidden response")
+	}
+}
@@ -0,0 +1,191 @@
+package release
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// Release pipeline checks, also used as issue types
+const (
+	CheckDeprecatedAction  = "deprecated-release-action" // Archived release actions that no longer receive fixes
+	CheckReleasePAT        = "release-pat"               // gh release authenticated with a personal access token
+	CheckMissingProvenance = "missing-provenance"        // Workflows publishing releases without build provenance
+)
+
+// AllChecks lists every release pipeline check
+var AllChecks = []string{CheckDeprecatedAction, CheckReleasePAT, CheckMissingProvenance}
+
+// ProvenanceAction is named by missing-provenance issues as the action to add
+const ProvenanceAction = "actions/attest-build-provenance"
+
+// deprecatedActions are archived release actions, with what replaces them
+var deprecatedActions = map[string]string{
+	"actions/create-release":       "softprops/action-gh-release or `gh release create`",
+	"actions/upload-release-asset": "softprops/action-gh-release or `gh release upload`",
+}
+
+// publishingActions create releases or upload their assets
+var publishingActions = map[string]bool{
+	"actions/create-release":       true,
+	"actions/upload-release-asset": true,
+	"softprops/action-gh-release":  true,
+	"ncipollo/release-action":      true,
+	"goreleaser/goreleaser-action": true,
+}
+
+// provenanceActions generate build provenance attestations; the SLSA generator is called as reusable
+// workflows under its repository
+var provenanceActions = []string{
+	"actions/attest-build-provenance",
+	"actions/attest",
+	"slsa-framework/slsa-github-generator",
+}
+
+// Config holds configuration options for release pipeline analysis
+type Config struct {
+	Verbose bool
+	Checks  []string // Enabled checks; empty enables none
+}
+
+// Analyzer flags release pipelines using archived actions, personal access tokens, or no provenance
+type Analyzer struct {
+	checks  map[string]bool
+	verbose bool
+}
+
+// NewAnalyzer creates an analyzer running the given checks
+func NewAnalyzer(checks []string) *Analyzer {
+	return NewAnalyzerWithConfig(&Config{Verbose: false, Checks: checks})
+}
+
+// NewAnalyzerWithConfig creates an analyzer with configuration
+func NewAnalyzerWithConfig(config *Config) *Analyzer {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	checks := make(map[string]bool, len(config.Checks))
+	for _, check := range config.Checks {
+		checks[check] = true
+	}
+
+	return &Analyzer{
+		checks:  checks,
+		verbose: config.Verbose,
+	}
+}
+
+// ParseChecks parses a comma-separated list of checks, where "all" enables every check
+func ParseChecks(value string) ([]string, error) {
+	var checks []string
+	for _, check := range strings.Split(value, ",") {
+		check = strings.TrimSpace(check)
+		switch {
+		case check == "":
+			continue
+		case check == "all":
+			return AllChecks, nil
+		case !isCheck(check):
+			return nil, fmt.Errorf("unknown release check %q: use %s, or all", check, strings.Join(AllChecks, ", "))
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// isCheck reports whether name is a known release check
+func isCheck(name string) bool {
+	for _, check := range AllChecks {
+		if check == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Enabled reports whether any check is enabled
+func (a *Analyzer) Enabled() bool {
+	return len(a.checks) > 0
+}
+
+// Analyze returns issues for the release steps of a single workflow file
+// Provenance is only looked for in the same workflow, where attestations must be generated to
+// describe the build that produced the released artifacts.
+func (a *Analyzer) Analyze(filePath string, steps []workflow.ReleaseStep) []output.ActionIssue {
+	var issues []output.ActionIssue
+	publishing, attested := false, false
+
+	for _, step := range steps {
+		action, version, _ := strings.Cut(step.Uses, "@")
+		action = strings.ToLower(action)
+
+		if replacement, ok := deprecatedActions[action]; ok && a.checks[CheckDeprecatedAction] {
+			issues = append(issues, output.ActionIssue{
+				Repository:     action,
+				CurrentVersion: version,
+				IssueType:      CheckDeprecatedAction,
+				Severity:       "medium",
+				Description:    fmt.Sprintf("%s is archived and no longer maintained; use %s instead", action, replacement),
+				Context:        step.Context,
+				FilePath:       filePath,
+			})
+		}
+
+		if step.Run != "" && len(step.TokenSecrets) > 0 && a.checks[CheckReleasePAT] {
+			issues = append(issues, output.ActionIssue{
+				Repository:  "gh release",
+				IssueType:   CheckReleasePAT,
+				Severity:    "high",
+				Description: fmt.Sprintf("gh release runs with secrets.%s, a long-lived token, instead of the job's GITHUB_TOKEN with contents: write permission", strings.Join(step.TokenSecrets, ", secrets.")),
+				Context:     step.Context,
+				FilePath:    filePath,
+			})
+		}
+
+		if publishingActions[action] || publishesRelease(step.Run) {
+			publishing = true
+		}
+		if isProvenanceAction(action) {
+			attested = true
+		}
+	}
+
+	if publishing && !attested && a.checks[CheckMissingProvenance] {
+		issues = append(issues, output.ActionIssue{
+			Repository:  ProvenanceAction,
+			IssueType:   CheckMissingProvenance,
+			Severity:    "medium",
+			Description: "Workflow publishes releases without generating build provenance, so consumers cannot verify how the artifacts were built; add actions/attest-build-provenance or the SLSA generator",
+			Context:     "workflow",
+			FilePath:    filePath,
+		})
+	}
+
+	return issues
+}
+
+// publishesRelease reports whether a run: script creates a release or uploads release assets with the GitHub CLI
+func publishesRelease(run string) bool {
+	for _, line := range strings.Split(run, "\n") {
+		fields := strings.Fields(line)
+		for i := 0; i+2 < len(fields); i++ {
+			if fields[i] == "gh" && fields[i+1] == "release" && (fields[i+2] == "create" || fields[i+2] == "upload") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isProvenanceAction reports whether an action, or a reusable workflow under it, generates provenance
+func isProvenanceAction(action string) bool {
+	for _, provenance := range provenanceActions {
+		if action == provenance || strings.HasPrefix(action, provenance+"/") {
+			return true
+		}
+	}
+	return false
+}
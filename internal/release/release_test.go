@@ -0,0 +1,96 @@
+package release
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+const releasePath = ".github/workflows/release.yml"
+
+func TestParseChecks(t *testing.T) {
+	checks, err := ParseChecks("release-pat, missing-provenance")
+	if err != nil || !reflect.DeepEqual(checks, []string{CheckReleasePAT, CheckMissingProvenance}) {
+		t.Errorf("Unexpected checks %v, error %v", checks, err)
+	}
+	if checks, _ := ParseChecks("all"); !reflect.DeepEqual(checks, AllChecks) {
+		t.Errorf("Expected all checks, got %v", checks)
+	}
+	if _, err := ParseChecks("release-pat,typo"); err == nil {
+		t.Errorf("Expected an error for an unknown check")
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	steps := []workflow.ReleaseStep{
+		{Job: "release", Context: "job:release/step:Create", Uses: "actions/create-release@v1"},
+		{Job: "release", Context: "job:release/step:Upload", Run: "gh release upload v1 dist.zip", TokenSecrets: []string{"RELEASE_PAT"}},
+		{Job: "release", Context: "job:release/step:View", Run: "gh release view v1"},
+	}
+
+	issues := NewAnalyzer(AllChecks).Analyze(releasePath, steps)
+
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.IssueType+" "+issue.Repository+" "+issue.Context+" "+issue.Severity)
+		if issue.FilePath != releasePath {
+			t.Errorf("Expected issues in %s, got %+v", releasePath, issue)
+		}
+	}
+	expected := []string{
+		"deprecated-release-action actions/create-release job:release/step:Create medium",
+		"release-pat gh release job:release/step:Upload high",
+		"missing-provenance actions/attest-build-provenance workflow medium",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if issues[0].CurrentVersion != "v1" {
+		t.Errorf("Expected the deprecated action's version, got %q", issues[0].CurrentVersion)
+	}
+}
+
+func TestAnalyzeProvenance(t *testing.T) {
+	analyzer := NewAnalyzer([]string{CheckMissingProvenance})
+	tests := []struct {
+		name     string
+		steps    []workflow.ReleaseStep
+		expected int
+	}{
+		{"attested", []workflow.ReleaseStep{
+			{Uses: "softprops/action-gh-release@v2"},
+			{Uses: "actions/attest-build-provenance@v1"},
+		}, 0},
+		{"slsa generator", []workflow.ReleaseStep{
+			{Run: "gh release create v1"},
+			{Uses: "slsa-framework/slsa-github-generator/.github/workflows/generator_generic_slsa3.yml@v2.0.0"},
+		}, 0},
+		{"not publishing", []workflow.ReleaseStep{
+			{Uses: "actions/checkout@v4"},
+			{Run: "gh release download v1"},
+		}, 0},
+		{"unattested", []workflow.ReleaseStep{
+			{Uses: "goreleaser/goreleaser-action@v6"},
+		}, 1},
+	}
+	for _, tt := range tests {
+		if issues := analyzer.Analyze(releasePath, tt.steps); len(issues) != tt.expected {
+			t.Errorf("%s: expected %d issues, got %+v", tt.name, tt.expected, issues)
+		}
+	}
+}
+
+func TestAnalyzeDisabledChecks(t *testing.T) {
+	steps := []workflow.ReleaseStep{
+		{Uses: "actions/create-release@v1"},
+		{Run: "gh release create v1", TokenSecrets: []string{"PAT"}},
+	}
+	analyzer := NewAnalyzer(nil)
+	if analyzer.Enabled() {
+		t.Errorf("Expected an analyzer without checks to be disabled")
+	}
+	if issues := analyzer.Analyze(releasePath, steps); len(issues) != 0 {
+		t.Errorf("Expected no issues with every check disabled, got %+v", issues)
+	}
+}
@@ -0,0 +1,175 @@
+package scanopts
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/actions"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/budget"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/hygiene"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/priority"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/release"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/snapshot"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// Flags reads the command line flags of a scan
+type Flags interface {
+	Get(name string) (string, bool)
+	Is(name string) bool
+}
+
+// Options are the validated limits, checks, and behaviour switches of a scan. Flags that are only
+// paths or free text are read by the command itself.
+type Options struct {
+	SkipResolution bool
+	ResolveLatest  bool
+	ExplainAll     bool
+	FailOn         string // Minimum severity that fails the scan, or "" to never fail
+
+	MaxWorkflowSize  int           // Workflow files larger than this many bytes are skipped
+	MaxTagPages      int           // 0 lists every page of tags
+	MaxDuration      time.Duration // 0 is unlimited
+	MaxAPICalls      int           // 0 is unlimited
+	FailureBudget    *budget.FailureBudget
+	ReposSnapshotTTL time.Duration
+
+	RepositoryOrder *priority.RepositoryOrder
+	MaxRepos        int // 0 scans every repository
+	Sample          bool
+	SampleSeed      uint64
+
+	WorkflowUsageDays       int // 0 skips workflow run usage
+	EstimateMinutesDays     int // 0 skips the minutes estimate
+	ImageMaxAgeDays         int // 0 skips container image checks
+	TagProtectionConsumers  int // 0 skips tag protection checks
+	InternalActionConsumers int // 0 skips internal action discovery
+
+	HygieneChecks        []string
+	ReleaseChecks        []string
+	ActionChecks         []string
+	DisabledDefaultRules []string
+}
+
+// Parse reads and validates the options of a scan, so that a mistyped flag fails before any API call
+func Parse(flags Flags) (*Options, error) {
+	get := func(name string) string {
+		value, _ := flags.Get(name)
+		return value
+	}
+
+	opts := &Options{
+		SkipResolution:   flags.Is("skip-resolution"),
+		ResolveLatest:    flags.Is("resolve-latest"),
+		ExplainAll:       flags.Is("explain-all"),
+		FailOn:           get("fail-on"),
+		MaxWorkflowSize:  workflow.DefaultMaxFileSize,
+		ReposSnapshotTTL: snapshot.DefaultTTL,
+		Sample:           flags.Is("sample"),
+		SampleSeed:       rand.Uint64(),
+	}
+	if opts.ResolveLatest && opts.SkipResolution {
+		return nil, fmt.Errorf("--resolve-latest looks up action tags, which --skip-resolution disables")
+	}
+	if (get("write-locks") != "" || get("verify-locks") != "") && opts.SkipResolution {
+		return nil, fmt.Errorf("lockfiles record resolved commits, which --skip-resolution disables")
+	}
+	if opts.FailOn != "" && !output.IsValidSeverity(opts.FailOn) {
+		return nil, fmt.Errorf("--fail-on must be one of low, medium, high, critical")
+	}
+
+	var err error
+	for _, limit := range []struct {
+		name  string
+		unit  string
+		value *int
+	}{
+		{"max-workflow-size", "number of bytes", &opts.MaxWorkflowSize},
+		{"max-tag-pages", "number of pages", &opts.MaxTagPages},
+		{"max-api-calls", "number", &opts.MaxAPICalls},
+		{"max-repos", "number of repositories", &opts.MaxRepos},
+		{"workflow-usage", "number of days", &opts.WorkflowUsageDays},
+		{"estimate-minutes", "number of days", &opts.EstimateMinutesDays},
+		{"check-images", "number of days", &opts.ImageMaxAgeDays},
+		{"check-tag-protection", "number of repositories", &opts.TagProtectionConsumers},
+		{"internal-actions", "number of repositories", &opts.InternalActionConsumers},
+	} {
+		if err := parsePositive(get(limit.name), limit.value); err != nil {
+			return nil, fmt.Errorf("--%s must be a positive %s", limit.name, limit.unit)
+		}
+	}
+
+	if value := get("max-duration"); value != "" {
+		opts.MaxDuration, err = time.ParseDuration(value)
+		if err != nil || opts.MaxDuration <= 0 {
+			return nil, fmt.Errorf("--max-duration must be a positive duration such as 30m")
+		}
+	}
+	if value := get("repos-snapshot-ttl"); value != "" {
+		opts.ReposSnapshotTTL, err = time.ParseDuration(value)
+		if err != nil || opts.ReposSnapshotTTL <= 0 {
+			return nil, fmt.Errorf("--repos-snapshot-ttl must be a positive duration such as 6h")
+		}
+	}
+	if value := get("max-failures"); value != "" {
+		if opts.FailureBudget, err = budget.ParseFailureBudget(value); err != nil {
+			return nil, fmt.Errorf("--max-failures: %w", err)
+		}
+	}
+
+	if opts.RepositoryOrder, err = priority.ParseRepositoryOrder(get("order-by")); err != nil {
+		return nil, fmt.Errorf("--order-by: %w", err)
+	}
+	baselineFile, teamProperty, ledgerFile := get("baseline"), get("team-property"), get("ledger")
+	if opts.RepositoryOrder != nil && opts.RepositoryOrder.By == priority.OrderIssues && baselineFile == "" {
+		return nil, fmt.Errorf("--order-by issues requires --baseline for the previous issue counts")
+	}
+	if teamProperty != "" && baselineFile == "" && ledgerFile == "" {
+		return nil, fmt.Errorf("--team-property requires --baseline or --ledger for the pull requests to measure")
+	}
+	if ledgerFile != "" && teamProperty == "" {
+		return nil, fmt.Errorf("--ledger is only used with --team-property")
+	}
+
+	sampleSeed := get("sample-seed")
+	if (opts.Sample || sampleSeed != "") && opts.MaxRepos == 0 {
+		return nil, fmt.Errorf("--sample and --sample-seed require --max-repos")
+	}
+	if sampleSeed != "" {
+		if opts.SampleSeed, err = strconv.ParseUint(sampleSeed, 10, 64); err != nil {
+			return nil, fmt.Errorf("--sample-seed must be a non-negative integer")
+		}
+		opts.Sample = true
+	}
+
+	if opts.HygieneChecks, err = hygiene.ParseChecks(get("hygiene-checks")); err != nil {
+		return nil, fmt.Errorf("--hygiene-checks: %w", err)
+	}
+	if opts.ReleaseChecks, err = release.ParseChecks(get("release-checks")); err != nil {
+		return nil, fmt.Errorf("--release-checks: %w", err)
+	}
+	if opts.ActionChecks, err = actions.ParseChecks(get("action-checks")); err != nil {
+		return nil, fmt.Errorf("--action-checks: %w", err)
+	}
+	if opts.DisabledDefaultRules, err = actions.ParseDisabledDefaultRules(get("disable-default-rules")); err != nil {
+		return nil, fmt.Errorf("--disable-default-rules: %w", err)
+	}
+
+	return opts, nil
+}
+
+// parsePositive sets value to a positive integer flag; an unset flag leaves the default
+func parsePositive(flag string, value *int) error {
+	if flag == "" {
+		return nil
+	}
+	parsed, err := strconv.Atoi(flag)
+	if err != nil || parsed <= 0 {
+		return fmt.Errorf("not a positive number: %q", flag)
+	}
+	*value = parsed
+	return nil
+}
@@ -0,0 +1,95 @@
+package scanopts
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/priority"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/snapshot"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// flags is a command line of variable flags ("name" -> "value") and switches ("name" -> "")
+type flags map[string]string
+
+func (f flags) Get(name string) (string, bool) {
+	value, ok := f[name]
+	return value, ok
+}
+
+func (f flags) Is(name string) bool {
+	_, ok := f[name]
+	return ok
+}
+
+func TestParse_Defaults(t *testing.T) {
+	opts, err := Parse(flags{})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if opts.MaxWorkflowSize != workflow.DefaultMaxFileSize || opts.ReposSnapshotTTL != snapshot.DefaultTTL {
+		t.Errorf("Expected the default size limit and snapshot TTL, got %+v", opts)
+	}
+	if opts.MaxRepos != 0 || opts.Sample || opts.RepositoryOrder != nil || opts.FailureBudget != nil || opts.ExplainAll {
+		t.Errorf("Expected no limits, ordering, or explanations by default, got %+v", opts)
+	}
+}
+
+func TestParse(t *testing.T) {
+	opts, err := Parse(flags{
+		"explain-all":           "",
+		"fail-on":               "high",
+		"max-workflow-size":     "2048",
+		"max-duration":          "30m",
+		"max-failures":          "2%",
+		"order-by":              "issues",
+		"baseline":              "previous.json",
+		"max-repos":             "10",
+		"sample-seed":           "42",
+		"workflow-usage":        "30",
+		"disable-default-rules": "actions/cache",
+	})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !opts.ExplainAll || opts.FailOn != "high" || opts.MaxWorkflowSize != 2048 || opts.MaxDuration != 30*time.Minute {
+		t.Errorf("Expected the flags to be read, got %+v", opts)
+	}
+	if opts.FailureBudget == nil || opts.FailureBudget.String() != "2%" {
+		t.Errorf("Expected a 2%% failure budget, got %v", opts.FailureBudget)
+	}
+	if opts.RepositoryOrder == nil || opts.RepositoryOrder.By != priority.OrderIssues {
+		t.Errorf("Expected ordering by issues, got %+v", opts.RepositoryOrder)
+	}
+	if opts.MaxRepos != 10 || !opts.Sample || opts.SampleSeed != 42 {
+		t.Errorf("Expected a seeded sample of 10 repositories, got %d, %v, %d", opts.MaxRepos, opts.Sample, opts.SampleSeed)
+	}
+	if opts.WorkflowUsageDays != 30 || len(opts.DisabledDefaultRules) != 1 {
+		t.Errorf("Expected 30 days of usage and one disabled rule, got %d, %v", opts.WorkflowUsageDays, opts.DisabledDefaultRules)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		flags    flags
+		expected string
+	}{
+		{flags{"skip-resolution": "", "resolve-latest": ""}, "--resolve-latest"},
+		{flags{"skip-resolution": "", "write-locks": "locks"}, "lockfiles"},
+		{flags{"fail-on": "urgent"}, "--fail-on"},
+		{flags{"max-tag-pages": "0"}, "--max-tag-pages must be a positive number of pages"},
+		{flags{"check-images": "soon"}, "--check-images must be a positive number of days"},
+		{flags{"max-duration": "-1m"}, "--max-duration"},
+		{flags{"max-failures": "200%"}, "--max-failures"},
+		{flags{"order-by": "issues"}, "--baseline"},
+		{flags{"ledger": "prs.jsonl"}, "--ledger is only used with --team-property"},
+		{flags{"sample": ""}, "--max-repos"},
+		{flags{"hygiene-checks": "unknown"}, "--hygiene-checks"},
+	}
+	for _, tt := range tests {
+		if _, err := Parse(tt.flags); err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("Expected %v to fail with %q, got %v", tt.flags, tt.expected, err)
+		}
+	}
+}
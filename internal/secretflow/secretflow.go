@@ -0,0 +1,114 @@
+package secretflow
+
+import (
+	"log"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// Scopes a secret or variable is resolved to
+const (
+	ScopeRepository   = "repository"   // Defined on the repository; takes precedence over organization values
+	ScopeOrganization = "organization" // An organization secret or variable shared with the repository
+	ScopeAutomatic    = "automatic"    // GITHUB_TOKEN, created for every run
+	ScopeNotFound     = "not-found"    // Neither a repository nor an organization value: an environment secret, or undefined
+)
+
+// InventoryClient lists the secret and variable names available to a repository
+type InventoryClient interface {
+	GetSecretInventory(owner, repo string) (*github.SecretInventory, error)
+}
+
+// Config holds configuration options for secret scope resolution
+type Config struct {
+	Verbose bool
+}
+
+// Resolver sets the scope of secret flows from each repository's secret inventory
+type Resolver struct {
+	client  InventoryClient
+	verbose bool
+	warned  bool
+}
+
+// NewResolver creates a scope resolver
+func NewResolver(client InventoryClient) *Resolver {
+	return NewResolverWithConfig(client, &Config{Verbose: false})
+}
+
+// NewResolverWithConfig creates a scope resolver with configuration
+func NewResolverWithConfig(client InventoryClient, config *Config) *Resolver {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	return &Resolver{
+		client:  client,
+		verbose: config.Verbose,
+	}
+}
+
+// Resolve sets the scope of each flow in place
+// Scopes stay empty when the inventory cannot be listed, typically because the token lacks the
+// Secrets read permission; a warning is logged for the first such repository only.
+func (r *Resolver) Resolve(repoFullName string, flows []workflow.SecretFlow) {
+	if len(flows) == 0 {
+		return
+	}
+	owner, repo, found := strings.Cut(repoFullName, "/")
+	if !found {
+		return
+	}
+
+	inventory, err := r.client.GetSecretInventory(owner, repo)
+	if err != nil {
+		if !r.warned {
+			log.Printf("Warning: Unable to list secrets of %s, secret scopes are left unresolved: %v", repoFullName, err)
+			r.warned = true
+		} else if r.verbose {
+			log.Printf("Unable to list secrets of %s: %v", repoFullName, err)
+		}
+		return
+	}
+
+	repoSecrets, orgSecrets := nameSet(inventory.RepoSecrets), nameSet(inventory.OrgSecrets)
+	repoVariables, orgVariables := nameSet(inventory.RepoVariables), nameSet(inventory.OrgVariables)
+
+	for i := range flows {
+		flow := &flows[i]
+		name := strings.ToUpper(flow.Name)
+		switch {
+		case flow.Name == workflow.AllNames:
+			continue
+		case flow.Kind == workflow.FlowKindSecret && name == "GITHUB_TOKEN":
+			flow.Scope = ScopeAutomatic
+		case flow.Kind == workflow.FlowKindSecret:
+			flow.Scope = scope(repoSecrets[name], orgSecrets[name])
+		case flow.Kind == workflow.FlowKindVariable:
+			flow.Scope = scope(repoVariables[name], orgVariables[name])
+		}
+	}
+}
+
+// scope picks the scope a name resolves to, with repository values shadowing organization values
+func scope(inRepository, inOrganization bool) string {
+	switch {
+	case inRepository:
+		return ScopeRepository
+	case inOrganization:
+		return ScopeOrganization
+	default:
+		return ScopeNotFound
+	}
+}
+
+// nameSet indexes names case-insensitively, as GitHub stores them in upper case
+func nameSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[strings.ToUpper(name)] = true
+	}
+	return set
+}
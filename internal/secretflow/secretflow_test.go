@@ -0,0 +1,71 @@
+package secretflow
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+type mockInventoryClient struct {
+	inventory *github.SecretInventory
+	err       error
+	calls     int
+}
+
+func (m *mockInventoryClient) GetSecretInventory(owner, repo string) (*github.SecretInventory, error) {
+	m.calls++
+	return m.inventory, m.err
+}
+
+func TestResolve(t *testing.T) {
+	client := &mockInventoryClient{inventory: &github.SecretInventory{
+		RepoSecrets:   []string{"DEPLOY_KEY", "NPM_TOKEN"},
+		OrgSecrets:    []string{"NPM_TOKEN", "SLACK_WEBHOOK"},
+		OrgVariables:  []string{"REGION"},
+		RepoVariables: []string{"DEPLOY_URL"},
+	}}
+
+	flows := []workflow.SecretFlow{
+		{Kind: workflow.FlowKindSecret, Name: "npm_token"},
+		{Kind: workflow.FlowKindSecret, Name: "SLACK_WEBHOOK"},
+		{Kind: workflow.FlowKindSecret, Name: "GITHUB_TOKEN"},
+		{Kind: workflow.FlowKindSecret, Name: "PROD_PASSWORD"},
+		{Kind: workflow.FlowKindSecret, Name: workflow.AllNames},
+		{Kind: workflow.FlowKindVariable, Name: "REGION"},
+		{Kind: workflow.FlowKindVariable, Name: "DEPLOY_URL"},
+		{Kind: workflow.FlowKindVariable, Name: "DEPLOY_KEY"},
+	}
+	NewResolver(client).Resolve("my-org/api", flows)
+
+	// Repository values shadow organization values, and secrets and variables are separate namespaces
+	expected := []string{ScopeRepository, ScopeOrganization, ScopeAutomatic, ScopeNotFound, "", ScopeOrganization, ScopeRepository, ScopeNotFound}
+	for i, flow := range flows {
+		if flow.Scope != expected[i] {
+			t.Errorf("%s %s: expected scope %q, got %q", flow.Kind, flow.Name, expected[i], flow.Scope)
+		}
+	}
+}
+
+func TestResolve_InventoryError(t *testing.T) {
+	client := &mockInventoryClient{err: errors.New("403 Resource not accessible by integration")}
+	resolver := NewResolverWithConfig(client, nil)
+
+	flows := []workflow.SecretFlow{{Kind: workflow.FlowKindSecret, Name: "NPM_TOKEN"}}
+	resolver.Resolve("my-org/api", flows)
+	resolver.Resolve("my-org/web", flows)
+
+	if flows[0].Scope != "" {
+		t.Errorf("Expected scope to stay unresolved, got %q", flows[0].Scope)
+	}
+	if client.calls != 2 {
+		t.Errorf("Expected an inventory request per repository, got %d", client.calls)
+	}
+
+	// Repositories without flows need no inventory
+	resolver.Resolve("my-org/docs", nil)
+	if client.calls != 2 {
+		t.Errorf("Expected no inventory request without flows, got %d requests", client.calls)
+	}
+}
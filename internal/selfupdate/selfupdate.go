@@ -0,0 +1,242 @@
+package selfupdate
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/actions"
+)
+
+// Repository is the GitHub repository the tool is released from
+const Repository = "Jake-Mok-Nelson/actions-maintainer"
+
+// ChecksumsAsset is the release asset listing the SHA-256 checksum of every binary, as sha256sum writes it
+const ChecksumsAsset = "checksums.txt"
+
+// DefaultAPIURL is the GitHub API the releases are read from
+const DefaultAPIURL = "https://api.github.com"
+
+// maxBinarySize bounds release downloads
+const maxBinarySize = 200 << 20
+
+// Release is a published release of the tool
+type Release struct {
+	Tag    string  `json:"tag_name"`
+	URL    string  `json:"html_url"`
+	Assets []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Asset returns the release asset with a name, or nil
+func (r *Release) Asset(name string) *Asset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// Updater looks up and downloads releases of the tool
+type Updater struct {
+	Client *http.Client // HTTP client with the network settings (nil = http.DefaultClient)
+	APIURL string       // GitHub API base URL (empty = DefaultAPIURL)
+	Token  string       // Optional token, raising the API rate limit
+}
+
+// Latest returns the latest release of the tool
+func (u *Updater) Latest() (*Release, error) {
+	apiURL := u.APIURL
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	data, err := u.get(strings.TrimSuffix(apiURL, "/")+"/repos/"+Repository+"/releases/latest", 1<<20)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up the latest release: %w", err)
+	}
+
+	var release Release
+	if err := json.Unmarshal(data, &release); err != nil {
+		return nil, fmt.Errorf("failed to parse the latest release: %w", err)
+	}
+	if release.Tag == "" {
+		return nil, fmt.Errorf("latest release has no tag")
+	}
+	return &release, nil
+}
+
+// Download downloads the release binary with a name and checks it against the release's checksums
+func (u *Updater) Download(release *Release, name string) ([]byte, error) {
+	asset := release.Asset(name)
+	if asset == nil {
+		return nil, fmt.Errorf("release %s has no binary %s for this platform", release.Tag, name)
+	}
+	checksumsAsset := release.Asset(ChecksumsAsset)
+	if checksumsAsset == nil {
+		return nil, fmt.Errorf("release %s has no %s to verify the download", release.Tag, ChecksumsAsset)
+	}
+
+	checksums, err := u.get(checksumsAsset.URL, 1<<20)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", ChecksumsAsset, err)
+	}
+	content, err := u.get(asset.URL, maxBinarySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	if err := Verify(content, ParseChecksums(checksums), name); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// get fetches a URL, reading at most limit bytes
+func (u *Updater) get(url string, limit int64) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if u.Token != "" && strings.HasPrefix(url, u.apiPrefix()) {
+		req.Header.Set("Authorization", "Bearer "+u.Token)
+	}
+	client := u.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("GET %s: response larger than %d bytes", url, limit)
+	}
+	return data, nil
+}
+
+// apiPrefix returns the API base URL the token is sent to; downloads from other hosts go without it
+func (u *Updater) apiPrefix() string {
+	if u.APIURL == "" {
+		return DefaultAPIURL
+	}
+	return u.APIURL
+}
+
+// AssetName returns the name of the release binary for a platform, e.g. actions-maintainer-linux-amd64
+func AssetName(goos, goarch string) string {
+	name := fmt.Sprintf("actions-maintainer-%s-%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// CurrentAssetName returns the name of the release binary for the running platform
+func CurrentAssetName() string {
+	return AssetName(runtime.GOOS, runtime.GOARCH)
+}
+
+// IsRelease reports whether a version is a release version such as 0.5.0, rather than a development build
+func IsRelease(version string) bool {
+	return actions.HighestVersion([]string{strings.TrimPrefix(version, "v")}) != ""
+}
+
+// Outdated reports whether the latest release is newer than the current version; development builds
+// are never outdated
+func Outdated(current, latest string) bool {
+	current, latest = strings.TrimPrefix(current, "v"), strings.TrimPrefix(latest, "v")
+	if current == latest || !IsRelease(current) {
+		return false
+	}
+	return actions.HighestVersion([]string{current, latest}) == latest
+}
+
+// ParseChecksums reads a checksums file of "<sha256>  <name>" lines
+func ParseChecksums(data []byte) map[string]string {
+	checksums := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 {
+			// sha256sum marks files read in binary mode with a leading '*'
+			checksums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+		}
+	}
+	return checksums
+}
+
+// Verify checks that content has the checksum listed for a name
+func Verify(content []byte, checksums map[string]string, name string) error {
+	expected, ok := checksums[name]
+	if !ok {
+		return fmt.Errorf("%s lists no checksum for %s", ChecksumsAsset, name)
+	}
+	sum := sha256.Sum256(content)
+	if actual := hex.EncodeToString(sum[:]); actual != expected {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", name, expected, actual)
+	}
+	return nil
+}
+
+// Replace replaces the executable at a path with new content, keeping its permissions
+// The new binary is written next to the old one and renamed over it, so an interrupted update leaves
+// the old binary in place. Windows cannot replace a running executable, so it is moved aside first
+// and left as <path>.old.
+func Replace(executable string, content []byte) error {
+	info, err := os.Stat(executable)
+	if err != nil {
+		return fmt.Errorf("failed to read the current binary: %w", err)
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(executable), "."+filepath.Base(executable)+".new-*")
+	if err != nil {
+		return fmt.Errorf("failed to write the new binary: %w", err)
+	}
+	tempName := temp.Name()
+	defer os.Remove(tempName)
+	if _, err := temp.Write(content); err != nil {
+		temp.Close()
+		return fmt.Errorf("failed to write the new binary: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("failed to write the new binary: %w", err)
+	}
+	if err := os.Chmod(tempName, info.Mode().Perm()|0o111); err != nil {
+		return fmt.Errorf("failed to make the new binary executable: %w", err)
+	}
+
+	if runtime.GOOS == "windows" {
+		old := executable + ".old"
+		os.Remove(old)
+		if err := os.Rename(executable, old); err != nil {
+			return fmt.Errorf("failed to move the current binary aside: %w", err)
+		}
+	}
+	if err := os.Rename(tempName, executable); err != nil {
+		return fmt.Errorf("failed to replace the current binary: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package selfupdate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOutdated(t *testing.T) {
+	tests := []struct {
+		current, latest string
+		expected        bool
+	}{
+		{"0.4.1", "v0.5.0", true},
+		{"v0.5.0", "v0.5.0", false},
+		{"0.5.0", "v0.5.0", false},
+		{"v0.6.0", "v0.5.0", false},
+		{"dev", "v0.5.0", false},
+	}
+	for _, tt := range tests {
+		if outdated := Outdated(tt.current, tt.latest); outdated != tt.expected {
+			t.Errorf("Outdated(%q, %q) = %t, want %t", tt.current, tt.latest, outdated, tt.expected)
+		}
+	}
+}
+
+func TestAssetName(t *testing.T) {
+	if name := AssetName("windows", "amd64"); name != "actions-maintainer-windows-amd64.exe" {
+		t.Errorf("Expected the .exe binary for Windows, got %q", name)
+	}
+	if name := AssetName("darwin", "arm64"); name != "actions-maintainer-darwin-arm64" {
+		t.Errorf("Expected the darwin arm64 binary, got %q", name)
+	}
+}
+
+func TestLatestAndDownload(t *testing.T) {
+	binary := []byte("new binary")
+	sum := sha256.Sum256(binary)
+	checksums := hex.EncodeToString(sum[:]) + "  actions-maintainer-linux-amd64\n"
+
+	var authorized []string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			authorized = append(authorized, r.URL.Path)
+		}
+		switch r.URL.Path {
+		case "/repos/" + Repository + "/releases/latest":
+			fmt.Fprintf(w, `{"tag_name": "v0.5.0", "assets": [
+				{"name": "actions-maintainer-linux-amd64", "browser_download_url": "%[1]s/download/binary"},
+				{"name": "actions-maintainer-darwin-arm64", "browser_download_url": "%[1]s/download/tampered"},
+				{"name": "checksums.txt", "browser_download_url": "%[1]s/download/checksums.txt"}
+			]}`, server.URL)
+		case "/download/binary":
+			w.Write(binary)
+		case "/download/tampered":
+			w.Write([]byte("tampered"))
+		case "/download/checksums.txt":
+			fmt.Fprint(w, checksums+strings.Repeat("0", 64)+"  actions-maintainer-darwin-arm64\n")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	updater := &Updater{Client: server.Client(), APIURL: server.URL, Token: "secret"}
+	release, err := updater.Latest()
+	if err != nil {
+		t.Fatalf("Latest() returned error: %v", err)
+	}
+	if release.Tag != "v0.5.0" || len(release.Assets) != 3 {
+		t.Fatalf("Expected release v0.5.0 with 3 assets, got %+v", release)
+	}
+
+	content, err := updater.Download(release, "actions-maintainer-linux-amd64")
+	if err != nil || string(content) != "new binary" {
+		t.Errorf("Expected the verified binary, got %q (%v)", content, err)
+	}
+	if _, err := updater.Download(release, "actions-maintainer-darwin-arm64"); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("Expected a checksum mismatch for a tampered binary, got %v", err)
+	}
+	if _, err := updater.Download(release, "actions-maintainer-windows-amd64.exe"); err == nil {
+		t.Errorf("Expected an error for a platform without a binary")
+	}
+	// The server stands in for both the API and the download host here
+	if len(authorized) == 0 {
+		t.Errorf("Expected the token sent to the API")
+	}
+}
+
+func TestReplace(t *testing.T) {
+	executable := filepath.Join(t.TempDir(), "actions-maintainer")
+	if err := os.WriteFile(executable, []byte("old binary"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Replace(executable, []byte("new binary")); err != nil {
+		t.Fatalf("Replace() returned error: %v", err)
+	}
+	content, err := os.ReadFile(executable)
+	if err != nil || string(content) != "new binary" {
+		t.Errorf("Expected the new binary in place, got %q (%v)", content, err)
+	}
+	if info, err := os.Stat(executable); err != nil || info.Mode().Perm() != 0o751 {
+		t.Errorf("Expected the permissions kept and executable, got %v (%v)", info.Mode(), err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(executable)); len(entries) != 1 {
+		t.Errorf("Expected no temporary files left behind, got %d entries", len(entries))
+	}
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// DefaultRefreshTop is how many of the most used actions the background refresh keeps warm
+const DefaultRefreshTop = 50
+
+// DefaultRefreshInterval is the pause between background tag refreshes. With the defaults a pass over
+// the top actions finishes within the resolver's one hour cache lifetime.
+const DefaultRefreshInterval = time.Minute
+
+// DefaultMaxTrackedActions bounds how many actions the server counts users of. /analyze is open to any
+// client, so without a bound every distinct posted action would grow the counts for good.
+const DefaultMaxTrackedActions = 1000
+
+// TagRefresher replaces the cached tags of an action repository with freshly fetched ones
+type TagRefresher interface {
+	RefreshTags(owner, repo string) error
+}
+
+// recordUsage counts a repository or analyzed workflow as one user of each action it references
+func (s *Server) recordUsage(references []workflow.ActionReference) {
+	seen := make(map[string]bool)
+	for _, reference := range references {
+		if !reference.Local {
+			seen[strings.ToLower(reference.Repository)] = true
+		}
+	}
+
+	s.usageMu.Lock()
+	defer s.usageMu.Unlock()
+	for repository := range seen {
+		if _, tracked := s.usage[repository]; !tracked && len(s.usage) >= s.maxTracked {
+			s.evictLeastUsed()
+		}
+		s.usage[repository]++
+	}
+}
+
+// evictLeastUsed stops counting the action with the fewest users, making room for a new one. Actions
+// posted once by a client therefore replace each other rather than the widely used actions. The caller
+// holds usageMu.
+func (s *Server) evictLeastUsed() {
+	least := ""
+	for repository, users := range s.usage {
+		if least == "" || users < s.usage[least] || (users == s.usage[least] && repository > least) {
+			least = repository
+		}
+	}
+	delete(s.usage, least)
+}
+
+// seedUsage counts the users of each action in a scan, so the refresh starts with the actions the
+// organization uses most instead of waiting for requests
+func (s *Server) seedUsage(result *output.ScanResult) {
+	for _, repo := range result.Repositories {
+		s.recordUsage(repo.Actions)
+	}
+}
+
+// TopActions returns the n actions with the most users, scanned repositories and analyzed workflows
+// together, most used first
+func (s *Server) TopActions(n int) []string {
+	s.usageMu.Lock()
+	defer s.usageMu.Unlock()
+
+	actions := make([]string, 0, len(s.usage))
+	for repository := range s.usage {
+		actions = append(actions, repository)
+	}
+	sort.Slice(actions, func(i, j int) bool {
+		if s.usage[actions[i]] != s.usage[actions[j]] {
+			return s.usage[actions[i]] > s.usage[actions[j]]
+		}
+		return actions[i] < actions[j]
+	})
+	if len(actions) > n {
+		actions = actions[:n]
+	}
+	return actions
+}
+
+// RunTagRefresh refreshes the cached tags of the most used actions one repository at a time, pausing
+// between refreshes so the requests stay within rate limits, until ctx is done. Each pass re-reads the
+// most used actions, picking up actions that became popular through /analyze. Servers without a
+// refresher return at once.
+func (s *Server) RunTagRefresh(ctx context.Context) {
+	if s.refresher == nil {
+		return
+	}
+
+	for {
+		actions := s.TopActions(s.refreshTop)
+		if len(actions) == 0 {
+			// Nothing analyzed yet
+			if !s.waitRefresh(ctx) {
+				return
+			}
+			continue
+		}
+
+		for _, action := range actions {
+			owner, name, _ := strings.Cut(action, "/")
+			started := time.Now()
+			if err := s.refresher.RefreshTags(owner, name); err != nil {
+				log.Printf("Warning: Failed to refresh the tags of %s: %v", action, err)
+			} else if s.verbose {
+				log.Printf("Server: refreshed the tags of %s in %s", action, time.Since(started).Round(time.Millisecond))
+			}
+			if !s.waitRefresh(ctx) {
+				return
+			}
+		}
+	}
+}
+
+// waitRefresh pauses for the refresh interval, reporting false when ctx is done first
+func (s *Server) waitRefresh(ctx context.Context) bool {
+	timer := time.NewTimer(s.refreshInterval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
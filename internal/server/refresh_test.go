@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/actions"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// recordingRefresher records refreshed repositories, cancelling once it has seen enough
+type recordingRefresher struct {
+	mu        sync.Mutex
+	refreshed []string
+	limit     int
+	cancel    context.CancelFunc
+}
+
+func (r *recordingRefresher) RefreshTags(owner, repo string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.refreshed = append(r.refreshed, owner+"/"+repo)
+	if len(r.refreshed) == r.limit {
+		r.cancel()
+	}
+	return nil
+}
+
+func TestRunTagRefresh(t *testing.T) {
+	uses := func(repositories ...string) []workflow.ActionReference {
+		var references []workflow.ActionReference
+		for _, repository := range repositories {
+			references = append(references, workflow.ActionReference{Repository: repository, Version: "v1"})
+		}
+		return references
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	refresher := &recordingRefresher{limit: 4, cancel: cancel}
+	analyzer := New(&Config{
+		Rules: actions.NewManagerWithResolverConfigAndRules(nil, &actions.Config{}, nil),
+		Results: &output.ScanResult{Repositories: []output.RepositoryResult{
+			{FullName: "my-org/api", Actions: uses("actions/checkout", "actions/checkout", "actions/setup-go")},
+			{FullName: "my-org/web", Actions: uses("actions/checkout", "actions/setup-node")},
+		}},
+		Refresher:       refresher,
+		RefreshTop:      2,
+		RefreshInterval: time.Millisecond,
+	})
+
+	// Analyzed workflows count towards the most used actions
+	for i := 0; i < 2; i++ {
+		workflowYAML := "on: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - uses: actions/setup-node@v4\n"
+		recorder := httptest.NewRecorder()
+		analyzer.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/analyze", strings.NewReader(workflowYAML)))
+	}
+	if top := analyzer.TopActions(2); strings.Join(top, ",") != "actions/setup-node,actions/checkout" {
+		t.Fatalf("Expected setup-node and checkout to be the most used, got %v", top)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		analyzer.RunTagRefresh(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the refresh to stop when its context is done")
+	}
+
+	if strings.Join(refresher.refreshed, ",") != "actions/setup-node,actions/checkout,actions/setup-node,actions/checkout" {
+		t.Errorf("Expected passes over the top actions only, got %v", refresher.refreshed)
+	}
+}
+
+func TestRecordUsage_MaxTracked(t *testing.T) {
+	analyzer := New(&Config{
+		Rules:      actions.NewManagerWithResolverConfigAndRules(nil, &actions.Config{}, nil),
+		MaxTracked: 3,
+	})
+	reference := func(repository string) []workflow.ActionReference {
+		return []workflow.ActionReference{{Repository: repository, Version: "v1"}}
+	}
+
+	for i := 0; i < 3; i++ {
+		analyzer.recordUsage(reference("actions/checkout"))
+		analyzer.recordUsage(reference("actions/setup-go"))
+	}
+	// Clients posting a new action on every request only replace each other
+	for _, repository := range []string{"evil/one", "evil/two", "evil/three", "evil/four"} {
+		analyzer.recordUsage(reference(repository))
+	}
+
+	if len(analyzer.usage) != 3 {
+		t.Errorf("Expected at most 3 tracked actions, got %v", analyzer.usage)
+	}
+	if top := analyzer.TopActions(2); strings.Join(top, ",") != "actions/checkout,actions/setup-go" {
+		t.Errorf("Expected the widely used actions to be kept, got %v", top)
+	}
+}
@@ -0,0 +1,305 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/actions"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/badge"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/hooks"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/pr"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// DefaultMaxBodySize bounds request bodies; scan results of large organizations need more
+const DefaultMaxBodySize = 32 << 20
+
+// defaultWorkflowPath names a workflow posted to /analyze without a path
+const defaultWorkflowPath = ".github/workflows/workflow.yml"
+
+// Config holds configuration options for the server
+type Config struct {
+	Verbose     bool
+	Rules       *actions.Manager   // Analyzes posted workflows; required
+	DocsBaseURL string             // Rule documentation linked from issues; empty uses output.DefaultDocsBaseURL
+	MaxBodySize int64              // Zero uses DefaultMaxBodySize
+	Results     *output.ScanResult // Scan whose repositories get badges and seed the most used actions; nil serves no badges
+
+	// Background tag refresh: RunTagRefresh keeps the tags of the most used actions cached
+	Refresher       TagRefresher  // nil disables the refresh
+	RefreshTop      int           // Actions kept warm; zero uses DefaultRefreshTop
+	RefreshInterval time.Duration // Pause between refreshes; zero uses DefaultRefreshInterval
+	MaxTracked      int           // Actions whose users are counted; zero uses DefaultMaxTrackedActions
+}
+
+// Server exposes the analysis engine over HTTP, so other services can analyze workflows and plan
+// updates without embedding it
+type Server struct {
+	rules       *actions.Manager
+	docsBaseURL string
+	maxBodySize int64
+	verbose     bool
+	badges      map[string]badge.Grade // Grades of the scanned repositories, by "owner/name"
+
+	refresher       TagRefresher
+	refreshTop      int
+	refreshInterval time.Duration
+	usageMu         sync.Mutex
+	usage           map[string]int // Scanned repositories and analyzed workflows using each action, by lowercase "owner/name"
+	maxTracked      int            // Bound on the actions in usage
+}
+
+// New creates a server analyzing workflows with the configured rules
+func New(config *Config) *Server {
+	maxBodySize := config.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultMaxBodySize
+	}
+	badges := make(map[string]badge.Grade)
+	if config.Results != nil {
+		for _, grade := range badge.ScoreAll(config.Results) {
+			badges[grade.Repository] = grade
+		}
+	}
+	refreshTop := config.RefreshTop
+	if refreshTop <= 0 {
+		refreshTop = DefaultRefreshTop
+	}
+	refreshInterval := config.RefreshInterval
+	if refreshInterval <= 0 {
+		refreshInterval = DefaultRefreshInterval
+	}
+	maxTracked := config.MaxTracked
+	if maxTracked <= 0 {
+		maxTracked = DefaultMaxTrackedActions
+	}
+
+	server := &Server{
+		rules:           config.Rules,
+		docsBaseURL:     config.DocsBaseURL,
+		maxBodySize:     maxBodySize,
+		verbose:         config.Verbose,
+		badges:          badges,
+		refresher:       config.Refresher,
+		refreshTop:      refreshTop,
+		refreshInterval: refreshInterval,
+		usage:           make(map[string]int),
+		maxTracked:      maxTracked,
+	}
+	if config.Results != nil {
+		server.seedUsage(config.Results)
+	}
+	return server
+}
+
+// AnalyzeResponse is the answer to POST /analyze
+type AnalyzeResponse struct {
+	Repository string                     `json:"repository,omitempty"`
+	FilePath   string                     `json:"file_path"`
+	Actions    []workflow.ActionReference `json:"actions"`
+	Issues     []output.ActionIssue       `json:"issues"`
+}
+
+// PlanResponse is the answer to POST /plan
+type PlanResponse struct {
+	Plans []Plan `json:"plans"`
+}
+
+// Plan is the pull request create-pr would open for one repository
+type Plan struct {
+	Repository string             `json:"repository"`
+	BaseBranch string             `json:"base_branch"`
+	Branch     string             `json:"branch"`
+	PlanHash   string             `json:"plan_hash"`
+	Updates    []hooks.PlanUpdate `json:"updates"`
+	Files      []string           `json:"files,omitempty"` // Files outside .github/workflows edited with the updates
+}
+
+// errorResponse is the body of every failed request
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler returns the server's routes:
+//
+//	POST /analyze  workflow YAML in, the workflow's actions and issues out
+//	POST /plan     scan JSON in, the update plans create-pr would open out
+//	GET  /healthz  liveness probe
+//	GET  /badges/{owner}/{name}.svg   actions hygiene badge of a repository in the configured scan
+//	GET  /badges/{owner}/{name}.json  the same badge as shields.io endpoint JSON
+//
+// /analyze takes the workflow's repository ("owner/name") and path as the repository and path
+// query parameters, so rule conditions and file paths apply as in a scan.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/analyze", s.handleAnalyze)
+	mux.HandleFunc("/plan", s.handlePlan)
+	mux.HandleFunc("/badges/", s.handleBadge)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok\n"))
+	})
+	return mux
+}
+
+// handleAnalyze analyzes one workflow file
+func (s *Server) handleAnalyze(w http.ResponseWriter, r *http.Request) {
+	body, ok := s.readBody(w, r)
+	if !ok {
+		return
+	}
+
+	repository := r.URL.Query().Get("repository")
+	filePath := r.URL.Query().Get("path")
+	if filePath == "" {
+		filePath = defaultWorkflowPath
+	}
+
+	references, err := workflow.ParseWorkflow(string(body), filePath, repository)
+	if err != nil {
+		s.writeError(w, http.StatusUnprocessableEntity, fmt.Errorf("invalid workflow: %w", err))
+		return
+	}
+	s.recordUsage(references)
+
+	_, name, _ := strings.Cut(repository, "/")
+	repo := output.RepositoryResult{FullName: repository, Name: name, Actions: references}
+	repo.Issues = s.rules.AnalyzeRepository(repo)
+	if s.rules.HasRequirements() {
+		if outline, err := workflow.ParseWorkflowOutline(string(body), filePath, nil); err == nil {
+			repo.Issues = append(repo.Issues, s.rules.CheckRequiredActions(repo, []workflow.WorkflowOutline{*outline})...)
+		}
+	}
+	repositories := []output.RepositoryResult{repo}
+	output.AnnotateRules(repositories, s.docsBaseURL)
+
+	response := AnalyzeResponse{Repository: repository, FilePath: filePath, Actions: references, Issues: repositories[0].Issues}
+	if response.Actions == nil {
+		response.Actions = []workflow.ActionReference{}
+	}
+	if response.Issues == nil {
+		response.Issues = []output.ActionIssue{}
+	}
+	if s.verbose {
+		log.Printf("Server: analyzed %s: %d actions, %d issues", filePath, len(response.Actions), len(response.Issues))
+	}
+	s.writeJSON(w, response)
+}
+
+// handlePlan plans the updates of a scan result
+func (s *Server) handlePlan(w http.ResponseWriter, r *http.Request) {
+	body, ok := s.readBody(w, r)
+	if !ok {
+		return
+	}
+
+	var scanResult output.ScanResult
+	if err := json.Unmarshal(body, &scanResult); err != nil {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid scan result: %w", err))
+		return
+	}
+
+	response := PlanResponse{Plans: []Plan{}}
+	for _, plan := range pr.PlanUpdates(scanResult.Repositories) {
+		event := hooks.PlanEvent(plan)
+		response.Plans = append(response.Plans, Plan{
+			Repository: plan.Repository.FullName,
+			BaseBranch: plan.TargetBranch(),
+			Branch:     plan.BranchName(),
+			PlanHash:   plan.PlanHash(),
+			Updates:    event.Updates,
+			Files:      plan.FilePaths(),
+		})
+	}
+	if s.verbose {
+		log.Printf("Server: planned %d pull requests for %d repositories", len(response.Plans), len(scanResult.Repositories))
+	}
+	s.writeJSON(w, response)
+}
+
+// handleBadge serves the badge of a scanned repository
+func (s *Server) handleBadge(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", http.MethodGet)
+		s.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s %s is not supported; use GET", r.Method, r.URL.Path))
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/badges/")
+	var format string
+	switch {
+	case strings.HasSuffix(name, ".svg"):
+		name, format = strings.TrimSuffix(name, ".svg"), badge.FormatSVG
+	case strings.HasSuffix(name, ".json"):
+		name, format = strings.TrimSuffix(name, ".json"), badge.FormatEndpoint
+	default:
+		s.writeError(w, http.StatusNotFound, fmt.Errorf("%s is not a badge; use /badges/{owner}/{name}.svg or .json", r.URL.Path))
+		return
+	}
+
+	grade, ok := s.badges[name]
+	if !ok {
+		s.writeError(w, http.StatusNotFound, fmt.Errorf("no scanned repository %s", name))
+		return
+	}
+
+	// Badges change with every scan, so README images must not be cached for long
+	w.Header().Set("Cache-Control", "max-age=300")
+	if format == badge.FormatSVG {
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Write(badge.SVG(grade))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(badge.Endpoint(grade))
+}
+
+// readBody reads the body of a POST request, answering other methods and oversized bodies with an error
+func (s *Server) readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		s.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s %s is not supported; use POST", r.Method, r.URL.Path))
+		return nil, false
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.maxBodySize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			s.writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("request body exceeds %d bytes", s.maxBodySize))
+		} else {
+			s.writeError(w, http.StatusBadRequest, fmt.Errorf("failed to read request body: %w", err))
+		}
+		return nil, false
+	}
+	if len(strings.TrimSpace(string(body))) == 0 {
+		s.writeError(w, http.StatusBadRequest, errors.New("request body is empty"))
+		return nil, false
+	}
+	return body, true
+}
+
+// writeJSON answers with a JSON document
+func (s *Server) writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil && s.verbose {
+		log.Printf("Server: failed to write response: %v", err)
+	}
+}
+
+// writeError answers with a JSON error document
+func (s *Server) writeError(w http.ResponseWriter, status int, err error) {
+	if s.verbose {
+		log.Printf("Server: %d: %v", status, err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{Error: err.Error()})
+}
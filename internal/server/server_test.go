@@ -0,0 +1,145 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/actions"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+func testServer() *Server {
+	rules := []actions.Rule{{Repository: "actions/checkout", LatestVersion: "v4"}}
+	return New(&Config{Rules: actions.NewManagerWithResolverConfigAndRules(nil, &actions.Config{}, rules)})
+}
+
+func TestAnalyze(t *testing.T) {
+	workflowYAML := `on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v3
+      - uses: actions/setup-go@v5
+`
+	request := httptest.NewRequest(http.MethodPost, "/analyze?repository=my-org/api&path=.github/workflows/ci.yml", strings.NewReader(workflowYAML))
+	recorder := httptest.NewRecorder()
+	testServer().Handler().ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var response AnalyzeResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Expected a JSON response, got %v", err)
+	}
+	if len(response.Actions) != 2 {
+		t.Errorf("Expected 2 actions, got %d", len(response.Actions))
+	}
+	if len(response.Issues) != 1 {
+		t.Fatalf("Expected 1 issue, got %+v", response.Issues)
+	}
+	issue := response.Issues[0]
+	if issue.IssueType != "outdated" || issue.SuggestedVersion != "v4" || issue.FilePath != ".github/workflows/ci.yml" {
+		t.Errorf("Expected checkout to be outdated in ci.yml, got %+v", issue)
+	}
+	if issue.RuleID != "AM001" {
+		t.Errorf("Expected rule id AM001, got %q", issue.RuleID)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	scan := output.ScanResult{Repositories: []output.RepositoryResult{
+		{Name: "api", FullName: "my-org/api", DefaultBranch: "main", Issues: []output.ActionIssue{
+			{Repository: "actions/checkout", CurrentVersion: "v3", SuggestedVersion: "v4", IssueType: "outdated", FilePath: ".github/workflows/ci.yml"},
+		}},
+		{Name: "web", FullName: "my-org/web", DefaultBranch: "main"},
+	}}
+	body, _ := json.Marshal(scan)
+
+	recorder := httptest.NewRecorder()
+	testServer().Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/plan", strings.NewReader(string(body))))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var response PlanResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Expected a JSON response, got %v", err)
+	}
+	if len(response.Plans) != 1 {
+		t.Fatalf("Expected 1 plan, got %+v", response.Plans)
+	}
+	plan := response.Plans[0]
+	if plan.Repository != "my-org/api" || plan.BaseBranch != "main" || plan.Branch == "" || plan.PlanHash == "" {
+		t.Errorf("Unexpected plan %+v", plan)
+	}
+	if len(plan.Updates) != 1 || plan.Updates[0].TargetVersion != "v4" {
+		t.Errorf("Expected the checkout update to v4, got %+v", plan.Updates)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	server := New(&Config{Rules: actions.NewManager(), MaxBodySize: 64})
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{http.MethodGet, "/analyze", "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/analyze", "  ", http.StatusBadRequest},
+		{http.MethodPost, "/analyze", "jobs: [", http.StatusUnprocessableEntity},
+		{http.MethodPost, "/plan", "not json", http.StatusBadRequest},
+		{http.MethodPost, "/plan", strings.Repeat("x", 65), http.StatusRequestEntityTooLarge},
+		{http.MethodGet, "/healthz", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		server.Handler().ServeHTTP(recorder, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+		if recorder.Code != tt.status {
+			t.Errorf("Expected %s %s to answer %d, got %d: %s", tt.method, tt.path, tt.status, recorder.Code, recorder.Body.String())
+		}
+		if tt.status != http.StatusOK && !strings.Contains(recorder.Body.String(), `"error"`) {
+			t.Errorf("Expected a JSON error from %s %s, got %s", tt.method, tt.path, recorder.Body.String())
+		}
+	}
+}
+
+func TestBadges(t *testing.T) {
+	analyzer := New(&Config{
+		Rules: actions.NewManagerWithResolverConfigAndRules(nil, &actions.Config{}, nil),
+		Results: &output.ScanResult{Repositories: []output.RepositoryResult{
+			{FullName: "my-org/api", Issues: []output.ActionIssue{{Repository: "actions/checkout", Severity: "high"}}},
+		}},
+	})
+
+	tests := []struct {
+		path        string
+		status      int
+		contentType string
+		body        string
+	}{
+		{"/badges/my-org/api.svg", http.StatusOK, "image/svg+xml", "A (90)"},
+		{"/badges/my-org/api.json", http.StatusOK, "application/json", `"message": "A (90)"`},
+		{"/badges/my-org/web.svg", http.StatusNotFound, "application/json", "no scanned repository my-org/web"},
+		{"/badges/my-org/api.png", http.StatusNotFound, "application/json", "is not a badge"},
+	}
+
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		analyzer.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if recorder.Code != tt.status {
+			t.Errorf("%s: Expected %d, got %d", tt.path, tt.status, recorder.Code)
+		}
+		if contentType := recorder.Header().Get("Content-Type"); contentType != tt.contentType {
+			t.Errorf("%s: Expected content type %s, got %s", tt.path, tt.contentType, contentType)
+		}
+		if !strings.Contains(recorder.Body.String(), tt.body) {
+			t.Errorf("%s: Expected body to contain %q, got %s", tt.path, tt.body, recorder.Body.String())
+		}
+	}
+}
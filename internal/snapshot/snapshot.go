@@ -0,0 +1,66 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+)
+
+// DefaultTTL is how long a repository list snapshot is reused when no TTL is configured
+const DefaultTTL = 24 * time.Hour
+
+// Snapshot is a saved repository list of an owner, so repeated scans skip listing repositories
+// The list is saved before --filter is applied, so one snapshot serves scans with different filters.
+type Snapshot struct {
+	Owner        string              `json:"owner"`
+	CreatedAt    time.Time           `json:"created_at"`
+	Repositories []github.Repository `json:"repositories"`
+}
+
+// Age returns how long ago the snapshot was taken
+func (s *Snapshot) Age(now time.Time) time.Duration {
+	return now.Sub(s.CreatedAt)
+}
+
+// Fresh reports whether the snapshot lists the owner's repositories and is younger than the TTL
+func (s *Snapshot) Fresh(owner string, ttl time.Duration, now time.Time) bool {
+	return strings.EqualFold(s.Owner, owner) && s.Age(now) < ttl
+}
+
+// Load reads a snapshot file, returning nil without an error when the file does not exist yet
+func Load(filename string) (*Snapshot, error) {
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read repository snapshot: %w", err)
+	}
+
+	snapshot := &Snapshot{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("unable to parse repository snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// Save writes a snapshot of an owner's repositories taken now
+func Save(filename, owner string, repositories []github.Repository) error {
+	snapshot := Snapshot{Owner: owner, CreatedAt: time.Now().UTC(), Repositories: repositories}
+	if snapshot.Repositories == nil {
+		snapshot.Repositories = []github.Repository{}
+	}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode repository snapshot: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write repository snapshot: %w", err)
+	}
+	return nil
+}
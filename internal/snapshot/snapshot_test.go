@@ -0,0 +1,56 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+)
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repos.json")
+
+	missing, err := Load(path)
+	if err != nil || missing != nil {
+		t.Fatalf("Expected no snapshot and no error for a missing file, got %+v, %v", missing, err)
+	}
+
+	pushed := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	repositories := []github.Repository{
+		{Owner: "my-org", Name: "api", FullName: "my-org/api", DefaultBranch: "main", Topics: []string{"go"}, Language: "Go", PushedAt: pushed},
+	}
+	if err := Save(path, "my-org", repositories); err != nil {
+		t.Fatalf("Expected no error saving, got %v", err)
+	}
+
+	snapshot, err := Load(path)
+	if err != nil {
+		t.Fatalf("Expected no error loading, got %v", err)
+	}
+	if len(snapshot.Repositories) != 1 || snapshot.Repositories[0].Language != "Go" || !snapshot.Repositories[0].PushedAt.Equal(pushed) {
+		t.Errorf("Expected the repository metadata to round-trip, got %+v", snapshot.Repositories)
+	}
+
+	now := snapshot.CreatedAt.Add(time.Hour)
+	if !snapshot.Fresh("My-Org", 2*time.Hour, now) {
+		t.Error("Expected a one hour old snapshot to be fresh with a two hour TTL")
+	}
+	if snapshot.Fresh("my-org", 30*time.Minute, now) {
+		t.Error("Expected a one hour old snapshot to be stale with a 30 minute TTL")
+	}
+	if snapshot.Fresh("other-org", 2*time.Hour, now) {
+		t.Error("Expected a snapshot of another owner not to be used")
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repos.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Expected an error for an invalid snapshot")
+	}
+}
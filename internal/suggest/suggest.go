@@ -0,0 +1,225 @@
+package suggest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/actions"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// DefaultMinRepositories is the number of repositories an action must be used in to get a suggested rule
+const DefaultMinRepositories = 3
+
+// Latest version sources of a suggestion
+const (
+	SourceTags  = "tags"  // The highest release tag of the action repository
+	SourceUsage = "usage" // The highest version in use, when tags cannot be listed
+)
+
+// RuleMatcher finds the rule covering an action of a scanned repository
+type RuleMatcher interface {
+	RuleFor(repo output.RepositoryResult, action workflow.ActionReference) *actions.Rule
+}
+
+// TagLister lists the tags of an action repository, mapping each tag to its commit
+type TagLister interface {
+	GetTagsWithCache(owner, repo string) (map[string]string, error)
+}
+
+// Options restrict which uncovered actions get a suggested rule
+type Options struct {
+	MinRepositories int      // Zero uses DefaultMinRepositories
+	Owners          []string // Only actions published by these owners; empty suggests rules for every action
+}
+
+// Suggestion is a draft rule for an action used widely without rule coverage
+type Suggestion struct {
+	Rule         actions.Rule
+	Repositories int            // Scanned repositories using the action without a rule
+	Uses         int            // References to the action without a rule
+	Versions     map[string]int // Uses by version, SHA pins counted under their pin comment's version when it has one
+	Source       string         // SourceTags or SourceUsage
+}
+
+// Uncovered finds the actions and reusable workflows no rule covers, used in at least the minimum
+// number of repositories, most widely used first
+func Uncovered(result *output.ScanResult, rules RuleMatcher, options Options) []Suggestion {
+	minRepositories := options.MinRepositories
+	if minRepositories <= 0 {
+		minRepositories = DefaultMinRepositories
+	}
+	owners := make(map[string]bool)
+	for _, owner := range options.Owners {
+		owners[strings.ToLower(owner)] = true
+	}
+
+	type usage struct {
+		suggestion   *Suggestion
+		repositories map[string]bool
+	}
+	byAction := make(map[string]*usage)
+	var order []string
+	for _, repo := range result.Repositories {
+		for _, action := range repo.Actions {
+			// Local reusable workflows have no versions to write rules for
+			if action.Local {
+				continue
+			}
+			owner, _, _ := strings.Cut(action.Repository, "/")
+			if len(owners) > 0 && !owners[strings.ToLower(owner)] {
+				continue
+			}
+			if rules != nil && rules.RuleFor(repo, action) != nil {
+				continue
+			}
+
+			key := strings.ToLower(action.Repository)
+			if action.IsReusable {
+				key += "/" + action.WorkflowPath
+			}
+			found, ok := byAction[key]
+			if !ok {
+				rule := actions.Rule{Repository: action.Repository}
+				if action.IsReusable {
+					rule.WorkflowPath = action.WorkflowPath
+				}
+				found = &usage{
+					suggestion:   &Suggestion{Rule: rule, Versions: make(map[string]int)},
+					repositories: make(map[string]bool),
+				}
+				byAction[key] = found
+				order = append(order, key)
+			}
+			found.repositories[repo.FullName] = true
+			found.suggestion.Uses++
+			found.suggestion.Versions[usedVersion(action)]++
+		}
+	}
+
+	var suggestions []Suggestion
+	for _, key := range order {
+		found := byAction[key]
+		if len(found.repositories) < minRepositories {
+			continue
+		}
+		found.suggestion.Repositories = len(found.repositories)
+		suggestions = append(suggestions, *found.suggestion)
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Repositories != suggestions[j].Repositories {
+			return suggestions[i].Repositories > suggestions[j].Repositories
+		}
+		if suggestions[i].Uses != suggestions[j].Uses {
+			return suggestions[i].Uses > suggestions[j].Uses
+		}
+		return suggestions[i].Name() < suggestions[j].Name()
+	})
+	return suggestions
+}
+
+// Name returns the action, with the path of a reusable workflow
+func (s Suggestion) Name() string {
+	if s.Rule.WorkflowPath != "" {
+		return s.Rule.Repository + "/" + s.Rule.WorkflowPath
+	}
+	return s.Rule.Repository
+}
+
+// usedVersion returns the version of a reference, reading SHA pins by their pin comment
+func usedVersion(action workflow.ActionReference) string {
+	if version := workflow.PinCommentVersion(action.PinComment); version != "" && isCommitSHA(action.Version) {
+		return version
+	}
+	return action.Version
+}
+
+// SetLatestVersions fills in the latest version of each suggestion: the major tag of the action's
+// highest release, such as v4 for v4.2.1, or the release tag itself when there is no major tag.
+// Actions whose tags cannot be listed, or that have no release tags, fall back to the highest
+// version in use. A nil lister uses the versions in use for every action.
+func SetLatestVersions(suggestions []Suggestion, tags TagLister) {
+	for i := range suggestions {
+		suggestion := &suggestions[i]
+		if tags != nil {
+			owner, name, _ := strings.Cut(suggestion.Rule.Repository, "/")
+			if list, err := tags.GetTagsWithCache(owner, name); err == nil {
+				names := make([]string, 0, len(list))
+				for tag := range list {
+					names = append(names, tag)
+				}
+				if latest := LatestVersion(names); latest != "" {
+					suggestion.Rule.LatestVersion = latest
+					suggestion.Source = SourceTags
+					continue
+				}
+			}
+		}
+
+		versions := make([]string, 0, len(suggestion.Versions))
+		for version := range suggestion.Versions {
+			versions = append(versions, version)
+		}
+		suggestion.Rule.LatestVersion = LatestVersion(versions)
+		suggestion.Source = SourceUsage
+	}
+}
+
+// LatestVersion returns the major tag of the highest version among tags when it is listed, such as
+// v4 for v4.2.1, or the highest version itself. Branches, SHAs, and pre-releases are ignored, and ""
+// is returned when no tag is a version.
+func LatestVersion(tags []string) string {
+	latest := actions.HighestVersion(tags)
+	if latest == "" {
+		return ""
+	}
+	major := actions.MajorTag(latest)
+	for _, tag := range tags {
+		if tag == major {
+			return major
+		}
+	}
+	return latest
+}
+
+// isCommitSHA reports whether a version is a full commit SHA
+func isCommitSHA(version string) bool {
+	return len(version) == 40 && strings.Trim(strings.ToLower(version), "0123456789abcdef") == ""
+}
+
+// Rules returns the draft rules of the suggestions that have a latest version
+func Rules(suggestions []Suggestion) []actions.Rule {
+	rules := []actions.Rule{}
+	for _, suggestion := range suggestions {
+		if suggestion.Rule.LatestVersion != "" {
+			rules = append(rules, suggestion.Rule)
+		}
+	}
+	return rules
+}
+
+// Describe summarizes a suggestion for review, e.g. "my-org/deploy: 12 repositories, 30 uses (v1 ×20, v2 ×10), latest v2 from tags"
+func Describe(suggestion Suggestion) string {
+	versions := make([]string, 0, len(suggestion.Versions))
+	for version := range suggestion.Versions {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		if suggestion.Versions[versions[i]] != suggestion.Versions[versions[j]] {
+			return suggestion.Versions[versions[i]] > suggestion.Versions[versions[j]]
+		}
+		return versions[i] < versions[j]
+	})
+	counts := make([]string, 0, len(versions))
+	for _, version := range versions {
+		counts = append(counts, fmt.Sprintf("%s ×%d", version, suggestion.Versions[version]))
+	}
+
+	latest := "no version found, left out"
+	if suggestion.Rule.LatestVersion != "" {
+		latest = fmt.Sprintf("latest %s from %s", suggestion.Rule.LatestVersion, suggestion.Source)
+	}
+	return fmt.Sprintf("%s: %d repositories, %d uses (%s), %s", suggestion.Name(), suggestion.Repositories, suggestion.Uses, strings.Join(counts, ", "), latest)
+}
@@ -0,0 +1,107 @@
+package suggest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/actions"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+type testTags map[string][]string
+
+func (t testTags) GetTagsWithCache(owner, repo string) (map[string]string, error) {
+	names, ok := t[owner+"/"+repo]
+	if !ok {
+		return nil, fmt.Errorf("not found")
+	}
+	tags := make(map[string]string)
+	for _, name := range names {
+		tags[name] = "sha-" + name
+	}
+	return tags, nil
+}
+
+func suggestTestResult() *output.ScanResult {
+	result := &output.ScanResult{Owner: "my-org"}
+	for i := 1; i <= 4; i++ {
+		repo := output.RepositoryResult{Name: fmt.Sprintf("app%d", i), FullName: fmt.Sprintf("my-org/app%d", i)}
+		repo.Actions = append(repo.Actions,
+			workflow.ActionReference{Repository: "actions/checkout", Version: "v4"},
+			workflow.ActionReference{Repository: "my-org/deploy", Version: "v1"},
+		)
+		if i <= 2 {
+			repo.Actions = append(repo.Actions, workflow.ActionReference{Repository: "my-org/notify", Version: "v2"})
+		}
+		if i == 4 {
+			repo.Actions = append(repo.Actions, workflow.ActionReference{
+				Repository: "my-org/deploy", Version: "0123456789abcdef0123456789abcdef01234567", PinComment: "v2.1.0",
+			})
+		}
+		result.Repositories = append(result.Repositories, repo)
+	}
+	return result
+}
+
+func TestUncovered(t *testing.T) {
+	rules := actions.NewManagerWithResolverConfigAndRules(nil, &actions.Config{}, []actions.Rule{
+		{Repository: "actions/checkout", LatestVersion: "v4"},
+	})
+
+	suggestions := Uncovered(suggestTestResult(), rules, Options{})
+	if len(suggestions) != 1 {
+		t.Fatalf("Expected only my-org/deploy to be uncovered and widely used, got %+v", suggestions)
+	}
+	deploy := suggestions[0]
+	if deploy.Name() != "my-org/deploy" || deploy.Repositories != 4 || deploy.Uses != 5 {
+		t.Errorf("Expected my-org/deploy in 4 repositories with 5 uses, got %+v", deploy)
+	}
+	if deploy.Versions["v1"] != 4 || deploy.Versions["v2.1.0"] != 1 {
+		t.Errorf("Expected SHA pins counted under their pin comment, got %v", deploy.Versions)
+	}
+
+	suggestions = Uncovered(suggestTestResult(), nil, Options{MinRepositories: 2, Owners: []string{"my-org"}})
+	if len(suggestions) != 2 || suggestions[1].Name() != "my-org/notify" {
+		t.Errorf("Expected the internal deploy and notify actions, got %+v", suggestions)
+	}
+}
+
+func TestSetLatestVersions(t *testing.T) {
+	suggestions := Uncovered(suggestTestResult(), nil, Options{Owners: []string{"my-org"}})
+	SetLatestVersions(suggestions, testTags{"my-org/deploy": {"v1", "v1.4.0", "v2", "v2.3.1", "v3.0.0-beta.1", "main"}})
+	if suggestions[0].Rule.LatestVersion != "v2" || suggestions[0].Source != SourceTags {
+		t.Errorf("Expected the v2 major tag from tags, got %+v", suggestions[0])
+	}
+
+	SetLatestVersions(suggestions, nil)
+	if suggestions[0].Rule.LatestVersion != "v2.1.0" || suggestions[0].Source != SourceUsage {
+		t.Errorf("Expected the highest version in use without tags, got %+v", suggestions[0])
+	}
+
+	rules := Rules(suggestions)
+	if len(rules) != 1 || rules[0].Repository != "my-org/deploy" {
+		t.Errorf("Expected a draft rule for my-org/deploy, got %+v", rules)
+	}
+	if description := Describe(suggestions[0]); !strings.Contains(description, "4 repositories, 5 uses (v1 ×4, v2.1.0 ×1), latest v2.1.0 from usage") {
+		t.Errorf("Unexpected description %q", description)
+	}
+}
+
+func TestLatestVersion(t *testing.T) {
+	tests := []struct {
+		tags     []string
+		expected string
+	}{
+		{[]string{"v1", "v1.2.0", "v2.0.1", "v2"}, "v2"},
+		{[]string{"v1.9.0", "v1.10.0"}, "v1.10.0"},
+		{[]string{"1.0.0", "2.1.0", "2"}, "2"},
+		{[]string{"main", "latest"}, ""},
+	}
+	for _, test := range tests {
+		if got := LatestVersion(test.tags); got != test.expected {
+			t.Errorf("LatestVersion(%v): expected %q, got %q", test.tags, test.expected, got)
+		}
+	}
+}
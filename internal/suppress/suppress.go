@@ -0,0 +1,150 @@
+package suppress
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// dateLayout is the accepted format for suppression expiry dates
+const dateLayout = "2006-01-02"
+
+// Suppression silences a specific issue until a date or until the action version changes
+//
+// Matching: Repository and Action are required. FilePath and IssueType narrow the match
+// when set. A suppression with Version only applies while the action is still pinned to
+// that version, so it lifts automatically once the version changes.
+type Suppression struct {
+	Repository string `json:"repository"`           // Scanned repository full name (e.g., "my-org/api")
+	FilePath   string `json:"file_path,omitempty"`  // Optional workflow file path
+	Action     string `json:"action"`               // Action repository (e.g., "actions/checkout")
+	IssueType  string `json:"issue_type,omitempty"` // Optional issue type ("outdated", "deprecated", "migration")
+	Until      string `json:"until,omitempty"`      // Optional expiry date (YYYY-MM-DD)
+	Version    string `json:"version,omitempty"`    // Optional: suppress only while pinned to this version
+	Reason     string `json:"reason,omitempty"`     // Why the risk was accepted
+
+	until time.Time
+}
+
+// Set holds loaded suppressions
+type Set struct {
+	suppressions []Suppression
+}
+
+// LoadFile loads suppressions from a JSON file
+func LoadFile(filename string) (*Set, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open suppressions file: %w", err)
+	}
+	defer file.Close()
+
+	return Load(file)
+}
+
+// Load parses and validates suppressions from JSON
+func Load(reader io.Reader) (*Set, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read suppressions: %w", err)
+	}
+
+	var suppressions []Suppression
+	if err := json.Unmarshal(data, &suppressions); err != nil {
+		return nil, fmt.Errorf("unable to parse suppressions as JSON: %w", err)
+	}
+
+	for i := range suppressions {
+		s := &suppressions[i]
+		if s.Repository == "" {
+			return nil, fmt.Errorf("suppression %d: repository field is required", i+1)
+		}
+		if s.Action == "" {
+			return nil, fmt.Errorf("suppression %d: action field is required", i+1)
+		}
+		if s.Until != "" {
+			until, err := time.Parse(dateLayout, s.Until)
+			if err != nil {
+				return nil, fmt.Errorf("suppression %d: until must be a date in YYYY-MM-DD format: %w", i+1, err)
+			}
+			// Suppression remains active for the whole of the expiry day
+			s.until = until.Add(24 * time.Hour)
+		}
+	}
+
+	return &Set{suppressions: suppressions}, nil
+}
+
+// Len returns the number of loaded suppressions
+func (s *Set) Len() int {
+	return len(s.suppressions)
+}
+
+// Apply splits issues for a repository into active and suppressed issues as of the given time
+func (s *Set) Apply(repoFullName string, issues []output.ActionIssue, now time.Time) ([]output.ActionIssue, []output.SuppressedIssue) {
+	if s == nil || len(s.suppressions) == 0 {
+		return issues, nil
+	}
+
+	var active []output.ActionIssue
+	var suppressed []output.SuppressedIssue
+
+	for _, issue := range issues {
+		if match := s.find(repoFullName, issue, now); match != nil {
+			suppressed = append(suppressed, output.SuppressedIssue{
+				ActionIssue: issue,
+				Reason:      match.Reason,
+				Until:       match.Until,
+			})
+			continue
+		}
+		active = append(active, issue)
+	}
+
+	return active, suppressed
+}
+
+// Expired returns suppressions whose expiry date has passed
+func (s *Set) Expired(now time.Time) []Suppression {
+	if s == nil {
+		return nil
+	}
+
+	var expired []Suppression
+	for _, suppression := range s.suppressions {
+		if !suppression.until.IsZero() && !now.Before(suppression.until) {
+			expired = append(expired, suppression)
+		}
+	}
+	return expired
+}
+
+// find returns the first active suppression matching the issue, if any
+func (s *Set) find(repoFullName string, issue output.ActionIssue, now time.Time) *Suppression {
+	for i := range s.suppressions {
+		suppression := &s.suppressions[i]
+
+		if suppression.Repository != repoFullName || suppression.Action != issue.Repository {
+			continue
+		}
+		if suppression.FilePath != "" && suppression.FilePath != issue.FilePath {
+			continue
+		}
+		if suppression.IssueType != "" && suppression.IssueType != issue.IssueType {
+			continue
+		}
+		if suppression.Version != "" && suppression.Version != issue.CurrentVersion {
+			continue // Version changed since the risk was accepted
+		}
+		if !suppression.until.IsZero() && !now.Before(suppression.until) {
+			continue // Suppression has expired
+		}
+
+		return suppression
+	}
+	return nil
+}
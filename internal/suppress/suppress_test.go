@@ -0,0 +1,120 @@
+package suppress
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+func mustLoad(t *testing.T, data string) *Set {
+	t.Helper()
+	set, err := Load(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	return set
+}
+
+func TestApply_MatchesRepositoryFileActionAndType(t *testing.T) {
+	set := mustLoad(t, `[
+		{
+			"repository": "my-org/api",
+			"file_path": ".github/workflows/ci.yml",
+			"action": "actions/checkout",
+			"issue_type": "outdated",
+			"reason": "accepted"
+		}
+	]`)
+
+	issues := []output.ActionIssue{
+		{Repository: "actions/checkout", CurrentVersion: "v3", IssueType: "outdated", FilePath: ".github/workflows/ci.yml"},
+		{Repository: "actions/checkout", CurrentVersion: "v3", IssueType: "outdated", FilePath: ".github/workflows/release.yml"},
+		{Repository: "actions/checkout", CurrentVersion: "v1", IssueType: "deprecated", FilePath: ".github/workflows/ci.yml"},
+		{Repository: "actions/setup-node", CurrentVersion: "v3", IssueType: "outdated", FilePath: ".github/workflows/ci.yml"},
+	}
+
+	active, suppressed := set.Apply("my-org/api", issues, time.Now())
+	if len(suppressed) != 1 {
+		t.Fatalf("Expected 1 suppressed issue, got %d", len(suppressed))
+	}
+	if len(active) != 3 {
+		t.Errorf("Expected 3 active issues, got %d", len(active))
+	}
+	if suppressed[0].Reason != "accepted" {
+		t.Errorf("Expected reason 'accepted', got '%s'", suppressed[0].Reason)
+	}
+
+	// A different scanned repository should not be affected
+	active, suppressed = set.Apply("my-org/web", issues, time.Now())
+	if len(suppressed) != 0 || len(active) != len(issues) {
+		t.Errorf("Expected no suppression for other repository, got %d suppressed", len(suppressed))
+	}
+}
+
+func TestApply_Expiry(t *testing.T) {
+	set := mustLoad(t, `[{"repository": "my-org/api", "action": "actions/checkout", "until": "2025-06-30"}]`)
+	issues := []output.ActionIssue{{Repository: "actions/checkout", CurrentVersion: "v3", IssueType: "outdated"}}
+
+	// Still active on the expiry day itself
+	onDay := time.Date(2025, 6, 30, 23, 0, 0, 0, time.UTC)
+	if _, suppressed := set.Apply("my-org/api", issues, onDay); len(suppressed) != 1 {
+		t.Errorf("Expected issue to be suppressed on expiry day, got %d suppressed", len(suppressed))
+	}
+	if expired := set.Expired(onDay); len(expired) != 0 {
+		t.Errorf("Expected no expired suppressions on expiry day, got %d", len(expired))
+	}
+
+	after := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	if _, suppressed := set.Apply("my-org/api", issues, after); len(suppressed) != 0 {
+		t.Errorf("Expected suppression to lapse after expiry, got %d suppressed", len(suppressed))
+	}
+	if expired := set.Expired(after); len(expired) != 1 {
+		t.Errorf("Expected 1 expired suppression, got %d", len(expired))
+	}
+}
+
+func TestApply_VersionChangeLiftsSuppression(t *testing.T) {
+	set := mustLoad(t, `[{"repository": "my-org/api", "action": "actions/checkout", "version": "v3"}]`)
+
+	pinned := []output.ActionIssue{{Repository: "actions/checkout", CurrentVersion: "v3", IssueType: "outdated"}}
+	if _, suppressed := set.Apply("my-org/api", pinned, time.Now()); len(suppressed) != 1 {
+		t.Errorf("Expected issue to be suppressed while pinned to v3, got %d suppressed", len(suppressed))
+	}
+
+	changed := []output.ActionIssue{{Repository: "actions/checkout", CurrentVersion: "v2", IssueType: "deprecated"}}
+	if _, suppressed := set.Apply("my-org/api", changed, time.Now()); len(suppressed) != 0 {
+		t.Errorf("Expected suppression to lift after version change, got %d suppressed", len(suppressed))
+	}
+}
+
+func TestApply_NilSet(t *testing.T) {
+	var set *Set
+	issues := []output.ActionIssue{{Repository: "actions/checkout", CurrentVersion: "v3"}}
+
+	active, suppressed := set.Apply("my-org/api", issues, time.Now())
+	if len(active) != 1 || suppressed != nil {
+		t.Errorf("Expected nil set to pass issues through unchanged")
+	}
+}
+
+func TestLoad_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"missing repository", `[{"action": "actions/checkout"}]`},
+		{"missing action", `[{"repository": "my-org/api"}]`},
+		{"invalid date", `[{"repository": "my-org/api", "action": "actions/checkout", "until": "30/06/2025"}]`},
+		{"invalid json", `{not json`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(strings.NewReader(tt.data)); err == nil {
+				t.Errorf("Expected error for %s", tt.name)
+			}
+		})
+	}
+}
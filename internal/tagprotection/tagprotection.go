@@ -0,0 +1,214 @@
+package tagprotection
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// DefaultMinConsumers is how many scanned repositories must use an internal action before its tags are checked
+const DefaultMinConsumers = 3
+
+// Ruleset rule types that protect a tag
+const (
+	ruleUpdate   = "update"
+	ruleDeletion = "deletion"
+)
+
+// RulesetClient lists the active tag rulesets applying to a repository
+type RulesetClient interface {
+	GetTagRulesets(owner, repo string) ([]github.TagRuleset, error)
+}
+
+// Config holds configuration options for tag protection checks
+type Config struct {
+	Verbose      bool
+	MinConsumers int // Scanned repositories that must use an action; zero uses DefaultMinConsumers
+}
+
+// Checker finds widely used internal actions whose release tags can be moved or deleted
+type Checker struct {
+	client       RulesetClient
+	minConsumers int
+	verbose      bool
+}
+
+// NewChecker creates a checker for actions used by at least minConsumers scanned repositories
+func NewChecker(client RulesetClient, minConsumers int) *Checker {
+	return NewCheckerWithConfig(client, &Config{Verbose: false, MinConsumers: minConsumers})
+}
+
+// NewCheckerWithConfig creates a checker with configuration
+func NewCheckerWithConfig(client RulesetClient, config *Config) *Checker {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	minConsumers := config.MinConsumers
+	if minConsumers <= 0 {
+		minConsumers = DefaultMinConsumers
+	}
+
+	return &Checker{
+		client:       client,
+		minConsumers: minConsumers,
+		verbose:      config.Verbose,
+	}
+}
+
+// internalAction collects how an internal action is consumed
+type internalAction struct {
+	consumers map[string]bool
+	tags      map[string]bool
+}
+
+// Check returns a finding for each internal action used by enough repositories with unprotected consumed tags
+//
+// Actions are internal when their owner also owns a scanned repository. Exact tags such as v1.2.3 must be
+// protected from updates and deletion; floating major tags such as v1 are moved on every release by design,
+// so only deletion protection is required. SHA pins are immutable and branches are not tags, so neither is checked.
+func (c *Checker) Check(repositories []output.RepositoryResult) []output.TagProtectionFinding {
+	owners := make(map[string]bool)
+	for _, repo := range repositories {
+		owners[strings.ToLower(ownerOf(repo.FullName))] = true
+	}
+
+	usage := make(map[string]*internalAction)
+	for _, repo := range repositories {
+		for _, action := range repo.Actions {
+			if !owners[strings.ToLower(ownerOf(action.Repository))] || strings.EqualFold(action.Repository, repo.FullName) {
+				continue
+			}
+			entry, exists := usage[action.Repository]
+			if !exists {
+				entry = &internalAction{consumers: make(map[string]bool), tags: make(map[string]bool)}
+				usage[action.Repository] = entry
+			}
+			entry.consumers[repo.FullName] = true
+			if style := output.PinStyle(action.Version); style == output.PinStyleExactTag || style == output.PinStyleMajorTag {
+				entry.tags[action.Version] = true
+			}
+		}
+	}
+
+	var findings []output.TagProtectionFinding
+	for actionRepo, entry := range usage {
+		if len(entry.consumers) < c.minConsumers || len(entry.tags) == 0 {
+			continue
+		}
+
+		owner, name, _ := strings.Cut(actionRepo, "/")
+		rulesets, err := c.client.GetTagRulesets(owner, name)
+		if err != nil {
+			log.Printf("Warning: Failed to check tag protection for %s: %v", actionRepo, err)
+			continue
+		}
+
+		var unprotected []string
+		exactUnprotected := false
+		for tag := range entry.tags {
+			if !isProtected(tag, rulesets) {
+				unprotected = append(unprotected, tag)
+				exactUnprotected = exactUnprotected || output.PinStyle(tag) == output.PinStyleExactTag
+			}
+		}
+		if len(unprotected) == 0 {
+			if c.verbose {
+				log.Printf("Tags of %s consumed by %d repositories are protected", actionRepo, len(entry.consumers))
+			}
+			continue
+		}
+		sort.Strings(unprotected)
+
+		severity := "medium"
+		if exactUnprotected {
+			severity = "high"
+		}
+		findings = append(findings, output.TagProtectionFinding{
+			Repository:      actionRepo,
+			Consumers:       sortedKeys(entry.consumers),
+			UnprotectedTags: unprotected,
+			Severity:        severity,
+			Description: fmt.Sprintf("Internal action %s is used by %d repositories but tags %s can be moved or deleted; add a tag ruleset restricting updates and deletions",
+				actionRepo, len(entry.consumers), strings.Join(unprotected, ", ")),
+		})
+	}
+
+	// Most widely used actions first
+	sort.Slice(findings, func(i, j int) bool {
+		if len(findings[i].Consumers) != len(findings[j].Consumers) {
+			return len(findings[i].Consumers) > len(findings[j].Consumers)
+		}
+		return findings[i].Repository < findings[j].Repository
+	})
+
+	return findings
+}
+
+// isProtected reports whether the rulesets covering a tag block the changes that matter for its pin style
+func isProtected(tag string, rulesets []github.TagRuleset) bool {
+	ref := "refs/tags/" + tag
+	blocked := make(map[string]bool)
+	for _, ruleset := range rulesets {
+		if !matchesAny(ruleset.Include, ref) || matchesAny(ruleset.Exclude, ref) {
+			continue
+		}
+		for _, rule := range ruleset.Rules {
+			blocked[rule] = true
+		}
+	}
+
+	if !blocked[ruleDeletion] {
+		return false
+	}
+	// Floating major tags are expected to move
+	return blocked[ruleUpdate] || output.PinStyle(tag) == output.PinStyleMajorTag
+}
+
+// matchesAny reports whether a ref matches any ruleset ref name pattern
+// "~ALL" matches every ref; "*" does not cross "/" and "**" does.
+func matchesAny(patterns []string, ref string) bool {
+	for _, pattern := range patterns {
+		if pattern == "~ALL" {
+			return true
+		}
+		if !strings.HasPrefix(pattern, "refs/") {
+			pattern = "refs/tags/" + pattern
+		}
+		if refPatternToRegexp(pattern).MatchString(ref) {
+			return true
+		}
+	}
+	return false
+}
+
+// refPatternToRegexp converts a ruleset ref name pattern to an anchored regex
+func refPatternToRegexp(pattern string) *regexp.Regexp {
+	quoted := regexp.QuoteMeta(pattern)
+	quoted = strings.ReplaceAll(quoted, `\*\*`, `.*`)
+	quoted = strings.ReplaceAll(quoted, `\*`, `[^/]*`)
+	quoted = strings.ReplaceAll(quoted, `\?`, `[^/]`)
+	// QuoteMeta output always compiles
+	return regexp.MustCompile("^" + quoted + "$")
+}
+
+// ownerOf returns the owner part of an "owner/name" reference
+func ownerOf(repository string) string {
+	owner, _, _ := strings.Cut(repository, "/")
+	return owner
+}
+
+// sortedKeys returns the keys of a set in sorted order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,131 @@
+package tagprotection
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// fakeClient returns canned rulesets per action repository
+type fakeClient struct {
+	rulesets map[string][]github.TagRuleset
+	errors   map[string]error
+	calls    []string
+}
+
+func (f *fakeClient) GetTagRulesets(owner, repo string) ([]github.TagRuleset, error) {
+	name := owner + "/" + repo
+	f.calls = append(f.calls, name)
+	return f.rulesets[name], f.errors[name]
+}
+
+func consumer(fullName string, uses ...string) output.RepositoryResult {
+	repo := output.RepositoryResult{FullName: fullName}
+	for _, use := range uses {
+		var action workflow.ActionReference
+		fmt.Sscanf(use, "%s %s", &action.Repository, &action.Version)
+		repo.Actions = append(repo.Actions, action)
+	}
+	return repo
+}
+
+func TestCheck_ReportsUnprotectedTags(t *testing.T) {
+	client := &fakeClient{rulesets: map[string][]github.TagRuleset{
+		"my-org/deploy": {{Name: "majors", Include: []string{"refs/tags/v*"}, Exclude: []string{"refs/tags/v*.*"}, Rules: []string{"deletion"}}},
+	}}
+	repos := []output.RepositoryResult{
+		consumer("my-org/api", "my-org/deploy v1", "actions/checkout v4"),
+		consumer("my-org/web", "my-org/deploy v1.2.0"),
+		consumer("my-org/cli", "my-org/deploy 8f4b7f84864484a7bf31766abe9204da3cbe65b3"),
+		consumer("my-org/deploy", "my-org/deploy v1"),
+	}
+
+	findings := NewChecker(client, 3).Check(repos)
+	if len(findings) != 1 {
+		t.Fatalf("Expected 1 finding, got %d", len(findings))
+	}
+
+	finding := findings[0]
+	if finding.Repository != "my-org/deploy" {
+		t.Errorf("Expected my-org/deploy, got %s", finding.Repository)
+	}
+	// The action's own repository is not a consumer
+	if len(finding.Consumers) != 3 || finding.Consumers[0] != "my-org/api" {
+		t.Errorf("Expected 3 sorted consumers, got %v", finding.Consumers)
+	}
+	// The major tag only needs deletion protection; the SHA pin is immutable
+	if len(finding.UnprotectedTags) != 1 || finding.UnprotectedTags[0] != "v1.2.0" {
+		t.Errorf("Expected only v1.2.0 unprotected, got %v", finding.UnprotectedTags)
+	}
+	if finding.Severity != "high" {
+		t.Errorf("Expected high severity for an unprotected exact tag, got %s", finding.Severity)
+	}
+	if len(client.calls) != 1 {
+		t.Errorf("Expected rulesets fetched only for the internal action, got %v", client.calls)
+	}
+}
+
+func TestCheck_ProtectedAndBelowThreshold(t *testing.T) {
+	client := &fakeClient{rulesets: map[string][]github.TagRuleset{
+		"my-org/deploy": {
+			{Name: "no-delete", Include: []string{"~ALL"}, Rules: []string{"deletion"}},
+			{Name: "immutable", Include: []string{"v*.*.*"}, Rules: []string{"update"}},
+		},
+	}}
+	repos := []output.RepositoryResult{
+		consumer("my-org/api", "my-org/deploy v1.2.0", "my-org/lint v2"),
+		consumer("my-org/web", "my-org/deploy v1"),
+		consumer("my-org/cli", "my-org/lint v2"),
+	}
+
+	findings := NewChecker(client, 2).Check(repos)
+	if len(findings) != 1 || findings[0].Repository != "my-org/lint" {
+		t.Errorf("Expected only the unprotected my-org/lint, got %+v", findings)
+	}
+
+	// Both actions are used by 2 repositories, below the threshold of 3
+	if findings := NewChecker(client, 3).Check(repos); len(findings) != 0 {
+		t.Errorf("Expected no findings below the consumer threshold, got %+v", findings)
+	}
+}
+
+func TestCheck_FetchErrorSkipsAction(t *testing.T) {
+	client := &fakeClient{errors: map[string]error{"my-org/deploy": fmt.Errorf("forbidden")}}
+	repos := []output.RepositoryResult{
+		consumer("my-org/api", "my-org/deploy v1", "my-org/lint v2"),
+		consumer("my-org/web", "my-org/deploy v1", "my-org/lint v2"),
+	}
+
+	findings := NewChecker(client, 2).Check(repos)
+	if len(findings) != 1 || findings[0].Repository != "my-org/lint" {
+		t.Fatalf("Expected only my-org/lint, got %+v", findings)
+	}
+	if findings[0].Severity != "medium" {
+		t.Errorf("Expected medium severity for an unprotected major tag, got %s", findings[0].Severity)
+	}
+}
+
+func TestMatchesAny(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		ref      string
+		expected bool
+	}{
+		{[]string{"~ALL"}, "refs/tags/v1", true},
+		{[]string{"refs/tags/v*"}, "refs/tags/v1.2.3", true},
+		{[]string{"refs/tags/v*"}, "refs/tags/release/v1", false},
+		{[]string{"refs/tags/**"}, "refs/tags/release/v1", true},
+		{[]string{"v1.?"}, "refs/tags/v1.2", true},
+		{[]string{"refs/heads/main"}, "refs/tags/main", false},
+		{nil, "refs/tags/v1", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchesAny(tt.patterns, tt.ref); got != tt.expected {
+			t.Errorf("matchesAny(%v, %q) = %v, expected %v", tt.patterns, tt.ref, got, tt.expected)
+		}
+	}
+}
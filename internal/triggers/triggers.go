@@ -0,0 +1,183 @@
+package triggers
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// IssueTypeRiskyTrigger is the issue type for risky workflow trigger configurations
+const IssueTypeRiskyTrigger = "risky-trigger"
+
+// DefaultStaleAfter is how long a scheduled workflow may go without running before it is flagged
+const DefaultStaleAfter = 365 * 24 * time.Hour
+
+// RunHistoryClient looks up when a workflow last ran
+type RunHistoryClient interface {
+	GetLastWorkflowRun(owner, repo, filePath string) (time.Time, bool, error)
+}
+
+// Config holds configuration options for trigger analysis
+type Config struct {
+	Verbose    bool
+	StaleAfter time.Duration // Zero uses DefaultStaleAfter
+}
+
+// Analyzer flags risky trigger patterns across the workflows of a repository
+type Analyzer struct {
+	client     RunHistoryClient
+	staleAfter time.Duration
+	verbose    bool
+	now        func() time.Time
+}
+
+// NewAnalyzer creates a trigger analyzer; client may be nil to skip run history checks
+func NewAnalyzer(client RunHistoryClient) *Analyzer {
+	return NewAnalyzerWithConfig(client, &Config{Verbose: false})
+}
+
+// NewAnalyzerWithConfig creates a trigger analyzer with configuration
+func NewAnalyzerWithConfig(client RunHistoryClient, config *Config) *Analyzer {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	staleAfter := config.StaleAfter
+	if staleAfter <= 0 {
+		staleAfter = DefaultStaleAfter
+	}
+
+	return &Analyzer{
+		client:     client,
+		staleAfter: staleAfter,
+		verbose:    config.Verbose,
+		now:        time.Now,
+	}
+}
+
+// Analyze returns issues for risky triggers in a repository's workflows
+// The issue Repository field holds the trigger event (e.g., "pull_request_target").
+func (a *Analyzer) Analyze(repoFullName string, infos []workflow.TriggerInfo) []output.ActionIssue {
+	var issues []output.ActionIssue
+
+	// workflow_run sources are referenced by workflow name
+	byName := make(map[string]*workflow.TriggerInfo)
+	for i := range infos {
+		byName[infos[i].DisplayName()] = &infos[i]
+	}
+
+	for i := range infos {
+		info := &infos[i]
+
+		for _, job := range info.PRHeadCheckoutJobs {
+			issues = append(issues, output.ActionIssue{
+				Repository:  "pull_request_target",
+				IssueType:   IssueTypeRiskyTrigger,
+				Severity:    "critical",
+				Description: fmt.Sprintf("Job '%s' checks out the pull request head in a pull_request_target workflow, running untrusted code with a write token and secrets", job),
+				Context:     fmt.Sprintf("job:%s", job),
+				FilePath:    info.FilePath,
+			})
+		}
+
+		if chain := workflowRunChain(info, byName); len(chain) > 2 {
+			issues = append(issues, output.ActionIssue{
+				Repository:  "workflow_run",
+				IssueType:   IssueTypeRiskyTrigger,
+				Severity:    "medium",
+				Description: fmt.Sprintf("Chained workflow_run triggers (%s) propagate privileges and are hard to audit", strings.Join(chain, " → ")),
+				Context:     "trigger:workflow_run",
+				FilePath:    info.FilePath,
+			})
+		}
+
+		if issue := a.checkStaleSchedule(repoFullName, info); issue != nil {
+			issues = append(issues, *issue)
+		}
+	}
+
+	return issues
+}
+
+// workflowRunChain returns the upstream chain of workflow_run triggers ending at info
+// A chain of two entries is a single workflow_run hop; longer chains are flagged.
+func workflowRunChain(info *workflow.TriggerInfo, byName map[string]*workflow.TriggerInfo) []string {
+	chain := []string{info.DisplayName()}
+	seen := map[string]bool{info.DisplayName(): true}
+
+	current := info
+	for len(current.WorkflowRunSources) > 0 {
+		// Follow the first source that is itself defined in this repository
+		var next *workflow.TriggerInfo
+		source := current.WorkflowRunSources[0]
+		for _, name := range current.WorkflowRunSources {
+			if upstream, ok := byName[name]; ok {
+				source, next = name, upstream
+				break
+			}
+		}
+
+		chain = append([]string{source}, chain...)
+		if next == nil || seen[source] {
+			break
+		}
+		seen[source] = true
+		current = next
+	}
+
+	return chain
+}
+
+// checkStaleSchedule flags schedule-only workflows that have not run within the stale window
+func (a *Analyzer) checkStaleSchedule(repoFullName string, info *workflow.TriggerInfo) *output.ActionIssue {
+	if a.client == nil || !isScheduleOnly(info) {
+		return nil
+	}
+
+	parts := strings.SplitN(repoFullName, "/", 2)
+	if len(parts) != 2 {
+		return nil
+	}
+
+	lastRun, hasRun, err := a.client.GetLastWorkflowRun(parts[0], parts[1], info.FilePath)
+	if err != nil {
+		if a.verbose {
+			log.Printf("Unable to check run history of %s in %s: %v", info.FilePath, repoFullName, err)
+		}
+		return nil
+	}
+
+	description := "Scheduled workflow has never run; consider removing it"
+	if hasRun {
+		if a.now().Sub(lastRun) < a.staleAfter {
+			return nil
+		}
+		description = fmt.Sprintf("Scheduled workflow has not run since %s; consider removing it", lastRun.Format("2006-01-02"))
+	}
+
+	return &output.ActionIssue{
+		Repository:  "schedule",
+		IssueType:   IssueTypeRiskyTrigger,
+		Severity:    "low",
+		Description: description,
+		Context:     "trigger:schedule",
+		FilePath:    info.FilePath,
+	}
+}
+
+// isScheduleOnly reports whether a workflow only runs on a schedule (optionally also manually)
+func isScheduleOnly(info *workflow.TriggerInfo) bool {
+	if !info.HasEvent("schedule") {
+		return false
+	}
+	for _, event := range info.Events {
+		if event != "schedule" && event != "workflow_dispatch" {
+			return false
+		}
+	}
+	return true
+}
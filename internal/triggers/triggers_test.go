@@ -0,0 +1,82 @@
+package triggers
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+// mockRunHistory returns fixed last-run times keyed by workflow path
+type mockRunHistory struct {
+	lastRuns map[string]time.Time
+}
+
+func (m *mockRunHistory) GetLastWorkflowRun(owner, repo, filePath string) (time.Time, bool, error) {
+	if filePath == ".github/workflows/broken.yml" {
+		return time.Time{}, false, fmt.Errorf("not found")
+	}
+	lastRun, ok := m.lastRuns[filePath]
+	return lastRun, ok, nil
+}
+
+func TestAnalyze_PullRequestTargetCheckout(t *testing.T) {
+	analyzer := NewAnalyzer(nil)
+	issues := analyzer.Analyze("my-org/api", []workflow.TriggerInfo{
+		{FilePath: ".github/workflows/pr.yml", Events: []string{"pull_request_target"}, PRHeadCheckoutJobs: []string{"build"}},
+		{FilePath: ".github/workflows/label.yml", Events: []string{"pull_request_target"}},
+	})
+
+	if len(issues) != 1 {
+		t.Fatalf("Expected 1 issue, got %d", len(issues))
+	}
+	if issues[0].Severity != "critical" || issues[0].IssueType != IssueTypeRiskyTrigger || issues[0].Context != "job:build" {
+		t.Errorf("Unexpected issue: %+v", issues[0])
+	}
+}
+
+func TestAnalyze_WorkflowRunChains(t *testing.T) {
+	analyzer := NewAnalyzer(nil)
+	issues := analyzer.Analyze("my-org/api", []workflow.TriggerInfo{
+		{FilePath: ".github/workflows/ci.yml", Name: "CI", Events: []string{"push"}},
+		{FilePath: ".github/workflows/package.yml", Name: "Package", Events: []string{"workflow_run"}, WorkflowRunSources: []string{"CI"}},
+		{FilePath: ".github/workflows/deploy.yml", Name: "Deploy", Events: []string{"workflow_run"}, WorkflowRunSources: []string{"Package"}},
+	})
+
+	if len(issues) != 1 {
+		t.Fatalf("Expected only the end of the chain to be flagged, got %d issues", len(issues))
+	}
+	if issues[0].FilePath != ".github/workflows/deploy.yml" || !strings.Contains(issues[0].Description, "CI → Package → Deploy") {
+		t.Errorf("Unexpected issue: %+v", issues[0])
+	}
+}
+
+func TestAnalyze_StaleSchedules(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	analyzer := NewAnalyzer(&mockRunHistory{lastRuns: map[string]time.Time{
+		".github/workflows/stale.yml":  now.AddDate(-2, 0, 0),
+		".github/workflows/recent.yml": now.AddDate(0, -1, 0),
+		".github/workflows/mixed.yml":  now.AddDate(-2, 0, 0),
+	}})
+	analyzer.now = func() time.Time { return now }
+
+	issues := analyzer.Analyze("my-org/api", []workflow.TriggerInfo{
+		{FilePath: ".github/workflows/stale.yml", Events: []string{"schedule", "workflow_dispatch"}},
+		{FilePath: ".github/workflows/recent.yml", Events: []string{"schedule"}},
+		{FilePath: ".github/workflows/never.yml", Events: []string{"schedule"}},
+		{FilePath: ".github/workflows/mixed.yml", Events: []string{"push", "schedule"}},
+		{FilePath: ".github/workflows/broken.yml", Events: []string{"schedule"}},
+	})
+
+	if len(issues) != 2 {
+		t.Fatalf("Expected 2 stale schedule issues, got %d: %+v", len(issues), issues)
+	}
+	if issues[0].FilePath != ".github/workflows/stale.yml" || !strings.Contains(issues[0].Description, "2022-06-01") {
+		t.Errorf("Unexpected stale issue: %+v", issues[0])
+	}
+	if issues[1].FilePath != ".github/workflows/never.yml" || !strings.Contains(issues[1].Description, "never run") {
+		t.Errorf("Unexpected never-run issue: %+v", issues[1])
+	}
+}
@@ -0,0 +1,173 @@
+package usage
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// IssueTypeStaleWorkflow is the issue type for workflows that did not run within the usage window
+const IssueTypeStaleWorkflow = "stale-workflow"
+
+// DefaultWindowDays is how much run history is inspected when no window is configured
+const DefaultWindowDays = 90
+
+// RunHistoryClient lists the runs of a workflow file
+type RunHistoryClient interface {
+	ListWorkflowRunTimes(owner, repo, filePath string, since time.Time) ([]time.Time, error)
+	GetLastWorkflowRun(owner, repo, filePath string) (time.Time, bool, error)
+}
+
+// Config holds configuration options for workflow usage analysis
+type Config struct {
+	Verbose    bool
+	WindowDays int // Days of run history to inspect; zero uses DefaultWindowDays
+}
+
+// Analyzer records run history per workflow file and flags workflows that stopped running
+type Analyzer struct {
+	client     RunHistoryClient
+	windowDays int
+	verbose    bool
+	now        func() time.Time
+}
+
+// NewAnalyzer creates a usage analyzer inspecting windowDays of run history
+func NewAnalyzer(client RunHistoryClient, windowDays int) *Analyzer {
+	return NewAnalyzerWithConfig(client, &Config{Verbose: false, WindowDays: windowDays})
+}
+
+// NewAnalyzerWithConfig creates a usage analyzer with configuration
+func NewAnalyzerWithConfig(client RunHistoryClient, config *Config) *Analyzer {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	windowDays := config.WindowDays
+	if windowDays <= 0 {
+		windowDays = DefaultWindowDays
+	}
+
+	return &Analyzer{
+		client:     client,
+		windowDays: windowDays,
+		verbose:    config.Verbose,
+		now:        time.Now,
+	}
+}
+
+// Analyze sets the usage of each analyzed workflow file in place and returns stale-workflow issues
+// Only files in .github/workflows have run history; other files and skipped files are left alone.
+func (a *Analyzer) Analyze(repoFullName string, files []output.WorkflowFileResult) []output.ActionIssue {
+	parts := strings.SplitN(repoFullName, "/", 2)
+	if len(parts) != 2 {
+		return nil
+	}
+	owner, repo := parts[0], parts[1]
+
+	now := a.now()
+	since := now.AddDate(0, 0, -a.windowDays)
+
+	var issues []output.ActionIssue
+	for i := range files {
+		file := &files[i]
+		if file.Status != "" || path.Dir(file.Path) != ".github/workflows" {
+			continue
+		}
+
+		runs, err := a.client.ListWorkflowRunTimes(owner, repo, file.Path, since)
+		if err != nil {
+			if a.verbose {
+				log.Printf("Unable to list runs of %s in %s: %v", file.Path, repoFullName, err)
+			}
+			continue
+		}
+
+		usage := &output.WorkflowUsage{
+			WindowDays:  a.windowDays,
+			RunsByMonth: make(map[string]int),
+		}
+		for _, run := range runs {
+			if run.Before(since) {
+				continue
+			}
+			usage.TotalRuns++
+			usage.RunsByMonth[run.Format("2006-01")]++
+			if usage.LastRun == nil || run.After(*usage.LastRun) {
+				last := run
+				usage.LastRun = &last
+			}
+		}
+
+		if usage.TotalRuns == 0 {
+			// Look further back to tell abandoned workflows apart from ones that never ran
+			lastRun, hasRun, err := a.client.GetLastWorkflowRun(owner, repo, file.Path)
+			if err != nil {
+				if a.verbose {
+					log.Printf("Unable to check last run of %s in %s: %v", file.Path, repoFullName, err)
+				}
+			} else if hasRun {
+				usage.LastRun = &lastRun
+			} else {
+				usage.NeverRun = true
+			}
+			issues = append(issues, staleWorkflowIssue(file.Path, usage))
+		}
+
+		file.Usage = usage
+	}
+
+	return issues
+}
+
+// staleWorkflowIssue describes a workflow with no runs in the usage window
+func staleWorkflowIssue(filePath string, usage *output.WorkflowUsage) output.ActionIssue {
+	description := fmt.Sprintf("Workflow has not run in the past %d days; consider removing it", usage.WindowDays)
+	switch {
+	case usage.NeverRun:
+		description = "Workflow has never run; consider removing it"
+	case usage.LastRun != nil:
+		description = fmt.Sprintf("Workflow has not run since %s (window: %d days); consider removing it", usage.LastRun.Format("2006-01-02"), usage.WindowDays)
+	}
+
+	return output.ActionIssue{
+		Repository:  "workflow",
+		IssueType:   IssueTypeStaleWorkflow,
+		Severity:    "low",
+		Description: description,
+		Context:     fmt.Sprintf("window:%dd", usage.WindowDays),
+		FilePath:    filePath,
+	}
+}
+
+// ExcludeStaleWorkflows removes issues in workflow files that did not run within the usage window
+// It returns the number of issues removed; files without usage data are kept.
+func ExcludeStaleWorkflows(repos []output.RepositoryResult) int {
+	removed := 0
+	for i := range repos {
+		stale := make(map[string]bool)
+		for _, file := range repos[i].WorkflowFiles {
+			if file.Usage.Stale() {
+				stale[file.Path] = true
+			}
+		}
+		if len(stale) == 0 {
+			continue
+		}
+
+		kept := repos[i].Issues[:0]
+		for _, issue := range repos[i].Issues {
+			if stale[issue.FilePath] {
+				removed++
+				continue
+			}
+			kept = append(kept, issue)
+		}
+		repos[i].Issues = kept
+	}
+	return removed
+}
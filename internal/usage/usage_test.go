@@ -0,0 +1,123 @@
+package usage
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// mockRunHistory returns fixed run times keyed by workflow path
+type mockRunHistory struct {
+	runs map[string][]time.Time
+}
+
+func (m *mockRunHistory) ListWorkflowRunTimes(owner, repo, filePath string, since time.Time) ([]time.Time, error) {
+	if filePath == ".github/workflows/broken.yml" {
+		return nil, fmt.Errorf("not found")
+	}
+	var runs []time.Time
+	for _, run := range m.runs[filePath] {
+		if !run.Before(since) {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+func (m *mockRunHistory) GetLastWorkflowRun(owner, repo, filePath string) (time.Time, bool, error) {
+	var last time.Time
+	for _, run := range m.runs[filePath] {
+		if run.After(last) {
+			last = run
+		}
+	}
+	return last, !last.IsZero(), nil
+}
+
+func TestAnalyze_RunsByMonthAndStaleWorkflows(t *testing.T) {
+	now := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	analyzer := NewAnalyzer(&mockRunHistory{runs: map[string][]time.Time{
+		".github/workflows/ci.yml": {
+			time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC),
+		},
+		".github/workflows/old.yml": {time.Date(2023, 1, 5, 0, 0, 0, 0, time.UTC)},
+	}}, 90)
+	analyzer.now = func() time.Time { return now }
+
+	files := []output.WorkflowFileResult{
+		{Path: ".github/workflows/ci.yml"},
+		{Path: ".github/workflows/old.yml"},
+		{Path: ".github/workflows/unused.yml"},
+		{Path: ".github/workflows/broken.yml"},
+		{Path: ".github/workflows/huge.yml", Status: output.WorkflowStatusSkippedTooLarge},
+		{Path: "ci/templates/build.yml"},
+	}
+	issues := analyzer.Analyze("my-org/api", files)
+
+	ci := files[0].Usage
+	if ci == nil || ci.TotalRuns != 3 || ci.RunsByMonth["2024-04"] != 1 || ci.RunsByMonth["2024-06"] != 2 || ci.Stale() {
+		t.Errorf("Unexpected usage for ci.yml: %+v", ci)
+	}
+
+	if old := files[1].Usage; old == nil || !old.Stale() || old.NeverRun || old.LastRun == nil {
+		t.Errorf("Expected old.yml to be stale with a last run, got %+v", old)
+	}
+	if unused := files[2].Usage; unused == nil || !unused.NeverRun {
+		t.Errorf("Expected unused.yml to be marked never run, got %+v", unused)
+	}
+	for _, file := range files[3:] {
+		if file.Usage != nil {
+			t.Errorf("Expected no usage for %s, got %+v", file.Path, file.Usage)
+		}
+	}
+
+	if len(issues) != 2 {
+		t.Fatalf("Expected 2 stale-workflow issues, got %d", len(issues))
+	}
+	if issues[0].FilePath != ".github/workflows/old.yml" || !strings.Contains(issues[0].Description, "2023-01-05") {
+		t.Errorf("Unexpected issue: %+v", issues[0])
+	}
+	if issues[1].FilePath != ".github/workflows/unused.yml" || !strings.Contains(issues[1].Description, "never run") {
+		t.Errorf("Unexpected issue: %+v", issues[1])
+	}
+	for _, issue := range issues {
+		if issue.IssueType != IssueTypeStaleWorkflow || issue.Severity != "low" {
+			t.Errorf("Unexpected issue type or severity: %+v", issue)
+		}
+	}
+}
+
+func TestExcludeStaleWorkflows(t *testing.T) {
+	repos := []output.RepositoryResult{
+		{
+			FullName: "my-org/api",
+			WorkflowFiles: []output.WorkflowFileResult{
+				{Path: ".github/workflows/ci.yml", Usage: &output.WorkflowUsage{TotalRuns: 4}},
+				{Path: ".github/workflows/old.yml", Usage: &output.WorkflowUsage{NeverRun: true}},
+				{Path: ".github/workflows/other.yml"},
+			},
+			Issues: []output.ActionIssue{
+				{Repository: "actions/checkout", FilePath: ".github/workflows/ci.yml"},
+				{Repository: "actions/checkout", FilePath: ".github/workflows/old.yml"},
+				{Repository: "actions/setup-go", FilePath: ".github/workflows/other.yml"},
+			},
+		},
+	}
+
+	if removed := ExcludeStaleWorkflows(repos); removed != 1 {
+		t.Errorf("Expected 1 issue removed, got %d", removed)
+	}
+	if len(repos[0].Issues) != 2 {
+		t.Fatalf("Expected 2 remaining issues, got %d", len(repos[0].Issues))
+	}
+	for _, issue := range repos[0].Issues {
+		if issue.FilePath == ".github/workflows/old.yml" {
+			t.Errorf("Expected issues in old.yml to be removed")
+		}
+	}
+}
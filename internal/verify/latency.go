@@ -0,0 +1,81 @@
+package verify
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// UnassignedTeam groups the pull requests of repositories without a value for the team property
+const UnassignedTeam = "unassigned"
+
+// Teams maps each repository, by lower-case full name, to the value of its team custom property
+func Teams(repositories []output.RepositoryResult, property string) map[string]string {
+	teams := make(map[string]string, len(repositories))
+	for _, repo := range repositories {
+		if team := strings.TrimSpace(repo.CustomProperties[property]); team != "" {
+			teams[strings.ToLower(repo.FullName)] = team
+		}
+	}
+	return teams
+}
+
+// MergeLatency groups pull requests by the team of their repository and measures the median time from
+// opening to merging of each team's merged pull requests, so teams that leave updates unmerged stand out.
+// Teams are sorted by name, keeping rows comparable from scan to scan.
+func MergeLatency(pulls map[string][]PullRequest, teams map[string]string) []output.TeamMergeLatency {
+	byTeam := make(map[string]*output.TeamMergeLatency)
+	hours := make(map[string][]float64)
+	for repository, repoPulls := range pulls {
+		team := teams[strings.ToLower(repository)]
+		if team == "" {
+			team = UnassignedTeam
+		}
+		latency := byTeam[team]
+		if latency == nil {
+			latency = &output.TeamMergeLatency{Team: team}
+			byTeam[team] = latency
+		}
+
+		for _, pull := range repoPulls {
+			latency.PullRequests++
+			switch pull.State {
+			case StateMerged:
+				latency.Merged++
+				if pull.CreatedAt != nil && pull.MergedAt != nil {
+					hours[team] = append(hours[team], pull.MergedAt.Sub(*pull.CreatedAt).Hours())
+				}
+			case StateOpen:
+				latency.Open++
+			case StateClosed:
+				latency.Closed++
+			}
+		}
+	}
+
+	latencies := make([]output.TeamMergeLatency, 0, len(byTeam))
+	for team, latency := range byTeam {
+		latency.MedianHoursToMerge = median(hours[team])
+		latencies = append(latencies, *latency)
+	}
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i].Team < latencies[j].Team
+	})
+	return latencies
+}
+
+// median returns the median of values rounded to a tenth, or 0 when there are none
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Float64s(values)
+	middle := len(values) / 2
+	value := values[middle]
+	if len(values)%2 == 0 {
+		value = (values[middle-1] + values[middle]) / 2
+	}
+	return math.Round(value*10) / 10
+}
@@ -0,0 +1,357 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// Pull request states in a closure report
+const (
+	StateMerged  = "merged"
+	StateOpen    = "open"
+	StateClosed  = "closed"  // Closed without merging
+	StateUnknown = "unknown" // The pull request could not be read
+)
+
+// PullRequestClient reads pull requests by number
+type PullRequestClient interface {
+	GetPullRequest(owner, repo string, number int) (*github.PullRequestInfo, error)
+}
+
+// DispatchRunClient lists the workflow_dispatch runs on a branch
+type DispatchRunClient interface {
+	ListDispatchRuns(owner, repo, branch string) ([]github.DispatchRun, error)
+}
+
+// PullRequest is a pull request opened for a repository, with its state at verification time
+type PullRequest struct {
+	URL         string       `json:"url"`
+	Number      int          `json:"number"`
+	Title       string       `json:"title,omitempty"`
+	Branch      string       `json:"branch,omitempty"`
+	State       string       `json:"state"`
+	CreatedAt   *time.Time   `json:"created_at,omitempty"`
+	MergedAt    *time.Time   `json:"merged_at,omitempty"`
+	UpgradeRuns []UpgradeRun `json:"upgrade_runs,omitempty"`
+}
+
+// UpgradeRun is the newest workflow_dispatch run of a workflow on a pull request's branch, such as the
+// run create-pr --dispatch-check starts for a workflow patched with schema transformations
+type UpgradeRun struct {
+	Workflow   string `json:"workflow"`
+	Status     string `json:"status"`               // queued, in_progress, or completed
+	Conclusion string `json:"conclusion,omitempty"` // success, failure, cancelled, ... once completed
+	URL        string `json:"url"`
+}
+
+// RepositoryClosure is the outcome of a repository's pull requests: the issues of the original scan
+// that are gone, those still found, and new issues found by the rescan
+type RepositoryClosure struct {
+	Repository   string               `json:"repository"`
+	PullRequests []PullRequest        `json:"pull_requests"`
+	Rescanned    bool                 `json:"rescanned"` // False when the repository could not be scanned again
+	Fixed        []output.ActionIssue `json:"fixed,omitempty"`
+	Remaining    []output.ActionIssue `json:"remaining,omitempty"`
+	Regressed    []output.ActionIssue `json:"regressed,omitempty"`
+}
+
+// Summary counts the repositories and issues of a closure report
+type Summary struct {
+	Repositories int `json:"repositories"`
+	Rescanned    int `json:"rescanned"`
+	Fixed        int `json:"fixed,omitempty"`
+	Remaining    int `json:"remaining,omitempty"`
+	Regressed    int `json:"regressed,omitempty"`
+}
+
+// Report is a closure report comparing the repositories with pull requests before and after rollout
+type Report struct {
+	Owner        string              `json:"owner"`
+	ScanTime     time.Time           `json:"scan_time"`   // When the original scan ran
+	VerifiedAt   time.Time           `json:"verified_at"` // When the repositories were scanned again
+	Summary      Summary             `json:"summary"`
+	Repositories []RepositoryClosure `json:"repositories"`
+}
+
+// Closed reports whether every original issue is fixed and no new issue was found
+func (r *Report) Closed() bool {
+	return r.Summary.Remaining == 0 && r.Summary.Regressed == 0 && r.Summary.Rescanned == r.Summary.Repositories
+}
+
+// PullRequests groups created pull requests by repository, dropping duplicates of the same pull
+// request, and looks up the state of each. Pull requests that cannot be read are StateUnknown.
+func PullRequests(client PullRequestClient, created []output.CreatedPR, verbose bool) map[string][]PullRequest {
+	seen := make(map[string]bool)
+	pulls := make(map[string][]PullRequest)
+	for _, pr := range created {
+		key := fmt.Sprintf("%s#%d", strings.ToLower(pr.Repository), pr.Number)
+		if pr.Number == 0 || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		pull := PullRequest{URL: pr.URL, Number: pr.Number, Title: pr.Title, Branch: pr.Branch, State: StateUnknown}
+		owner, name, _ := strings.Cut(pr.Repository, "/")
+		info, err := client.GetPullRequest(owner, name, pr.Number)
+		if err != nil {
+			log.Printf("Warning: Failed to read pull request %s: %v", key, err)
+		} else {
+			pull.State = stateOf(info)
+			if !info.CreatedAt.IsZero() {
+				pull.CreatedAt = &info.CreatedAt
+			}
+			if !info.MergedAt.IsZero() {
+				pull.MergedAt = &info.MergedAt
+			}
+			if verbose {
+				log.Printf("Pull request %s is %s", key, pull.State)
+			}
+		}
+		pulls[pr.Repository] = append(pulls[pr.Repository], pull)
+	}
+	return pulls
+}
+
+// AddUpgradeRuns records the newest workflow_dispatch run of each workflow on the pull requests' branches,
+// showing whether the upgrades worked when run. Runs that cannot be listed are left out.
+func AddUpgradeRuns(client DispatchRunClient, pulls map[string][]PullRequest) {
+	for repository, repoPulls := range pulls {
+		owner, name, _ := strings.Cut(repository, "/")
+		for i, pull := range repoPulls {
+			if pull.Branch == "" {
+				continue
+			}
+			runs, err := client.ListDispatchRuns(owner, name, pull.Branch)
+			if err != nil {
+				log.Printf("Warning: Failed to list the workflow runs of %s#%d: %v", repository, pull.Number, err)
+				continue
+			}
+			// Runs are listed newest first
+			seen := make(map[string]bool)
+			for _, run := range runs {
+				if seen[run.FilePath] {
+					continue
+				}
+				seen[run.FilePath] = true
+				repoPulls[i].UpgradeRuns = append(repoPulls[i].UpgradeRuns, UpgradeRun{
+					Workflow:   run.FilePath,
+					Status:     run.Status,
+					Conclusion: run.Conclusion,
+					URL:        run.URL,
+				})
+			}
+			sort.Slice(repoPulls[i].UpgradeRuns, func(a, b int) bool {
+				return repoPulls[i].UpgradeRuns[a].Workflow < repoPulls[i].UpgradeRuns[b].Workflow
+			})
+		}
+	}
+}
+
+// stateOf returns the closure report state of a pull request
+func stateOf(info *github.PullRequestInfo) string {
+	switch {
+	case info.Merged:
+		return StateMerged
+	case info.State == "open":
+		return StateOpen
+	default:
+		return StateClosed
+	}
+}
+
+// Merged reports whether any of the pull requests was merged
+func Merged(pulls []PullRequest) bool {
+	for _, pull := range pulls {
+		if pull.State == StateMerged {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildReport compares the repositories with pull requests in the original scan with the rescan
+// Issues match on workflow file, action, issue type, and context, but not version: an outdated
+// action updated to a version that is still outdated remains open. Repositories missing from the
+// rescan are reported with every original issue remaining.
+func BuildReport(before, after *output.ScanResult, pulls map[string][]PullRequest, verifiedAt time.Time) *Report {
+	original := repositoriesByName(before)
+	rescanned := repositoriesByName(after)
+
+	report := &Report{Owner: before.Owner, ScanTime: before.ScanTime, VerifiedAt: verifiedAt.UTC(), Repositories: []RepositoryClosure{}}
+	for repository, repoPulls := range pulls {
+		closure := RepositoryClosure{Repository: repository, PullRequests: repoPulls}
+		var originalIssues []output.ActionIssue
+		if repo, ok := original[strings.ToLower(repository)]; ok {
+			originalIssues = repo.Issues
+		}
+
+		if repo, ok := rescanned[strings.ToLower(repository)]; ok {
+			closure.Rescanned = true
+			closure.Fixed, closure.Remaining, closure.Regressed = Compare(originalIssues, repo.Issues)
+		} else {
+			closure.Remaining = append(closure.Remaining, originalIssues...)
+		}
+
+		report.Summary.Repositories++
+		if closure.Rescanned {
+			report.Summary.Rescanned++
+		}
+		report.Summary.Fixed += len(closure.Fixed)
+		report.Summary.Remaining += len(closure.Remaining)
+		report.Summary.Regressed += len(closure.Regressed)
+		report.Repositories = append(report.Repositories, closure)
+	}
+
+	sort.Slice(report.Repositories, func(i, j int) bool {
+		return report.Repositories[i].Repository < report.Repositories[j].Repository
+	})
+	return report
+}
+
+// repositoriesByName indexes the repositories of a scan by lowercase full name
+func repositoriesByName(result *output.ScanResult) map[string]output.RepositoryResult {
+	repositories := make(map[string]output.RepositoryResult)
+	if result == nil {
+		return repositories
+	}
+	for _, repo := range result.Repositories {
+		repositories[strings.ToLower(repo.FullName)] = repo
+	}
+	return repositories
+}
+
+// Compare splits the issues of a repository before and after its pull requests into those fixed,
+// those remaining, and those only found after
+func Compare(before, after []output.ActionIssue) (fixed, remaining, regressed []output.ActionIssue) {
+	afterKeys := make(map[string]bool, len(after))
+	for _, issue := range after {
+		afterKeys[issueKey(issue)] = true
+	}
+	beforeKeys := make(map[string]bool, len(before))
+	for _, issue := range before {
+		beforeKeys[issueKey(issue)] = true
+		if afterKeys[issueKey(issue)] {
+			remaining = append(remaining, issue)
+		} else {
+			fixed = append(fixed, issue)
+		}
+	}
+	for _, issue := range after {
+		if !beforeKeys[issueKey(issue)] {
+			regressed = append(regressed, issue)
+		}
+	}
+	return fixed, remaining, regressed
+}
+
+// issueKey identifies an issue across its fix, ignoring the version it changes
+func issueKey(issue output.ActionIssue) string {
+	return strings.Join([]string{issue.FilePath, strings.ToLower(issue.Repository), issue.WorkflowPath, issue.IssueType, issue.Context}, "\x00")
+}
+
+// WriteJSON writes the report as indented JSON
+func WriteJSON(w io.Writer, report *Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("failed to write closure report: %w", err)
+	}
+	return nil
+}
+
+// WriteMarkdown writes the report as a Markdown document for sign-off
+func WriteMarkdown(w io.Writer, report *Report) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Closure Report: %s\n\n", report.Owner)
+	fmt.Fprintf(&b, "Original scan: %s  \nVerified: %s\n\n", report.ScanTime.UTC().Format(time.RFC3339), report.VerifiedAt.Format(time.RFC3339))
+
+	status := "✅ Closed: every original issue is fixed and no new issue was found"
+	if !report.Closed() {
+		status = "❌ Open: issues remain, were introduced, or repositories could not be rescanned"
+	}
+	fmt.Fprintf(&b, "**%s**\n\n", status)
+
+	b.WriteString("| Repository | Pull Requests | Fixed | Remaining | Regressed |\n")
+	b.WriteString("|------------|---------------|-------|-----------|-----------|\n")
+	for _, closure := range report.Repositories {
+		pulls := make([]string, 0, len(closure.PullRequests))
+		for _, pull := range closure.PullRequests {
+			pulls = append(pulls, fmt.Sprintf("[#%d](%s) %s", pull.Number, pull.URL, pull.State))
+		}
+		repository := closure.Repository
+		if !closure.Rescanned {
+			repository += " (not rescanned)"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %d | %d | %d |\n", repository, strings.Join(pulls, ", "), len(closure.Fixed), len(closure.Remaining), len(closure.Regressed))
+	}
+	fmt.Fprintf(&b, "| **Total** | | **%d** | **%d** | **%d** |\n", report.Summary.Fixed, report.Summary.Remaining, report.Summary.Regressed)
+
+	writeUpgradeRuns(&b, report.Repositories)
+
+	for _, closure := range report.Repositories {
+		if len(closure.Remaining) == 0 && len(closure.Regressed) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n", closure.Repository)
+		writeIssues(&b, "Remaining", closure.Remaining)
+		writeIssues(&b, "Regressed", closure.Regressed)
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write closure report: %w", err)
+	}
+	return nil
+}
+
+// writeUpgradeRuns lists the workflow_dispatch runs on the pull requests' branches, if there are any
+func writeUpgradeRuns(b *strings.Builder, closures []RepositoryClosure) {
+	var rows []string
+	for _, closure := range closures {
+		for _, pull := range closure.PullRequests {
+			for _, run := range pull.UpgradeRuns {
+				result := run.Status
+				if run.Conclusion != "" {
+					result = run.Conclusion
+				}
+				rows = append(rows, fmt.Sprintf("| %s | [#%d](%s) | `%s` | [%s](%s) |\n", closure.Repository, pull.Number, pull.URL, run.Workflow, result, run.URL))
+			}
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+
+	b.WriteString("\n## Upgrade Runs\n\n")
+	b.WriteString("| Repository | Pull Request | Workflow | Result |\n")
+	b.WriteString("|------------|--------------|----------|--------|\n")
+	for _, row := range rows {
+		b.WriteString(row)
+	}
+}
+
+// writeIssues lists issues under a heading, if there are any
+func writeIssues(b *strings.Builder, heading string, issues []output.ActionIssue) {
+	if len(issues) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n### %s\n\n", heading)
+	for _, issue := range issues {
+		subject := issue.Repository
+		if issue.CurrentVersion != "" {
+			subject += "@" + issue.CurrentVersion
+		}
+		location := ""
+		if issue.FilePath != "" {
+			location = " in " + issue.FilePath
+		}
+		fmt.Fprintf(b, "- **%s** `%s`%s (%s): %s\n", issue.IssueType, subject, location, strings.ToUpper(issue.Severity), issue.Description)
+	}
+}
@@ -0,0 +1,215 @@
+package verify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// fakeClient returns canned pull requests keyed by "owner/repo#number"
+type fakeClient map[string]*github.PullRequestInfo
+
+func (f fakeClient) GetPullRequest(owner, repo string, number int) (*github.PullRequestInfo, error) {
+	key := fmt.Sprintf("%s/%s#%d", owner, repo, number)
+	info, ok := f[key]
+	if !ok {
+		return nil, fmt.Errorf("pull request %s not found", key)
+	}
+	return info, nil
+}
+
+func issue(action, version, issueType, filePath string) output.ActionIssue {
+	return output.ActionIssue{Repository: action, CurrentVersion: version, IssueType: issueType, Severity: "medium", FilePath: filePath, Context: "job:build/step:" + action}
+}
+
+func TestPullRequests(t *testing.T) {
+	client := fakeClient{
+		"my-org/api#1": {Number: 1, State: "closed", Merged: true},
+		"my-org/api#2": {Number: 2, State: "open"},
+		"my-org/web#3": {Number: 3, State: "closed"},
+	}
+	created := []output.CreatedPR{
+		{Repository: "my-org/api", Number: 1},
+		{Repository: "my-org/api", Number: 1}, // Recorded in both the scan and the ledger
+		{Repository: "my-org/api", Number: 2},
+		{Repository: "my-org/web", Number: 3},
+		{Repository: "my-org/cli", Number: 4},
+	}
+
+	pulls := PullRequests(client, created, false)
+	if len(pulls["my-org/api"]) != 2 || pulls["my-org/api"][0].State != StateMerged || pulls["my-org/api"][1].State != StateOpen {
+		t.Errorf("Expected a merged and an open pull request for my-org/api, got %+v", pulls["my-org/api"])
+	}
+	if pulls["my-org/web"][0].State != StateClosed {
+		t.Errorf("Expected a closed pull request for my-org/web, got %+v", pulls["my-org/web"])
+	}
+	if pulls["my-org/cli"][0].State != StateUnknown {
+		t.Errorf("Expected an unknown state for an unreadable pull request, got %+v", pulls["my-org/cli"])
+	}
+	if !Merged(pulls["my-org/api"]) || Merged(pulls["my-org/web"]) {
+		t.Error("Expected only my-org/api to have a merged pull request")
+	}
+}
+
+// fakeRunClient returns canned workflow_dispatch runs keyed by "owner/repo@branch"
+type fakeRunClient map[string][]github.DispatchRun
+
+func (f fakeRunClient) ListDispatchRuns(owner, repo, branch string) ([]github.DispatchRun, error) {
+	return f[owner+"/"+repo+"@"+branch], nil
+}
+
+func TestAddUpgradeRuns(t *testing.T) {
+	client := fakeRunClient{"my-org/api@actions-maintainer/update": {
+		{FilePath: ".github/workflows/ci.yml", Status: "completed", Conclusion: "failure", URL: "https://github.com/my-org/api/actions/runs/2"},
+		{FilePath: ".github/workflows/ci.yml", Status: "completed", Conclusion: "success", URL: "https://github.com/my-org/api/actions/runs/1"},
+		{FilePath: ".github/workflows/build.yml", Status: "in_progress", URL: "https://github.com/my-org/api/actions/runs/3"},
+	}}
+	pulls := map[string][]PullRequest{"my-org/api": {
+		{Number: 1, URL: "https://github.com/my-org/api/pull/1", Branch: "actions-maintainer/update", State: StateOpen},
+		{Number: 2, URL: "https://github.com/my-org/api/pull/2", State: StateOpen},
+	}}
+
+	AddUpgradeRuns(client, pulls)
+	runs := pulls["my-org/api"][0].UpgradeRuns
+	if len(runs) != 2 || runs[0].Workflow != ".github/workflows/build.yml" || runs[1].Conclusion != "failure" {
+		t.Fatalf("Expected the newest run of each workflow, got %+v", runs)
+	}
+	if len(pulls["my-org/api"][1].UpgradeRuns) != 0 {
+		t.Errorf("Expected no runs for a pull request without a branch, got %+v", pulls["my-org/api"][1].UpgradeRuns)
+	}
+
+	var buf bytes.Buffer
+	report := &Report{Owner: "my-org", Repositories: []RepositoryClosure{{Repository: "my-org/api", PullRequests: pulls["my-org/api"], Rescanned: true}}}
+	if err := WriteMarkdown(&buf, report); err != nil {
+		t.Fatalf("WriteMarkdown() returned error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "## Upgrade Runs") || !strings.Contains(buf.String(), "`.github/workflows/ci.yml` | [failure](https://github.com/my-org/api/actions/runs/2)") {
+		t.Errorf("Expected the upgrade runs in the report, got:\n%s", buf.String())
+	}
+}
+
+func TestMergeLatency(t *testing.T) {
+	opened := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	client := fakeClient{
+		"my-org/api#1": {Number: 1, State: "closed", Merged: true, CreatedAt: opened, MergedAt: opened.Add(10 * time.Hour)},
+		"my-org/api#2": {Number: 2, State: "closed", Merged: true, CreatedAt: opened, MergedAt: opened.Add(30 * time.Hour)},
+		"my-org/web#3": {Number: 3, State: "closed", Merged: true, CreatedAt: opened, MergedAt: opened.Add(2 * time.Hour)},
+		"my-org/web#4": {Number: 4, State: "open", CreatedAt: opened},
+		"my-org/cli#5": {Number: 5, State: "closed", CreatedAt: opened},
+	}
+	created := []output.CreatedPR{
+		{Repository: "my-org/api", Number: 1},
+		{Repository: "my-org/api", Number: 2},
+		{Repository: "my-org/web", Number: 3},
+		{Repository: "my-org/web", Number: 4},
+		{Repository: "my-org/cli", Number: 5},
+	}
+	repositories := []output.RepositoryResult{
+		{FullName: "My-Org/API", CustomProperties: map[string]string{"team": "payments"}},
+		{FullName: "my-org/web", CustomProperties: map[string]string{"team": "payments"}},
+		{FullName: "my-org/cli"},
+	}
+
+	latencies := MergeLatency(PullRequests(client, created, false), Teams(repositories, "team"))
+	expected := []output.TeamMergeLatency{
+		{Team: "payments", PullRequests: 4, Merged: 3, Open: 1, MedianHoursToMerge: 10},
+		{Team: UnassignedTeam, PullRequests: 1, Closed: 1},
+	}
+	if len(latencies) != len(expected) {
+		t.Fatalf("Expected %d teams, got %+v", len(expected), latencies)
+	}
+	for i := range expected {
+		if latencies[i] != expected[i] {
+			t.Errorf("Expected team %d to be %+v, got %+v", i, expected[i], latencies[i])
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	before := []output.ActionIssue{
+		issue("actions/checkout", "v3", "outdated", ".github/workflows/ci.yml"),
+		issue("actions/setup-node", "v2", "outdated", ".github/workflows/ci.yml"),
+	}
+	after := []output.ActionIssue{
+		// Updated to a version that is still outdated
+		issue("actions/setup-node", "v3", "outdated", ".github/workflows/ci.yml"),
+		issue("actions/cache", "v3", "deprecated", ".github/workflows/ci.yml"),
+	}
+
+	fixed, remaining, regressed := Compare(before, after)
+	if len(fixed) != 1 || fixed[0].Repository != "actions/checkout" {
+		t.Errorf("Expected actions/checkout fixed, got %+v", fixed)
+	}
+	if len(remaining) != 1 || remaining[0].Repository != "actions/setup-node" {
+		t.Errorf("Expected actions/setup-node remaining, got %+v", remaining)
+	}
+	if len(regressed) != 1 || regressed[0].Repository != "actions/cache" {
+		t.Errorf("Expected actions/cache regressed, got %+v", regressed)
+	}
+}
+
+func TestBuildReport(t *testing.T) {
+	before := &output.ScanResult{Owner: "my-org", Repositories: []output.RepositoryResult{
+		{FullName: "my-org/api", Issues: []output.ActionIssue{issue("actions/checkout", "v3", "outdated", ".github/workflows/ci.yml")}},
+		{FullName: "my-org/web", Issues: []output.ActionIssue{issue("actions/cache", "v2", "deprecated", ".github/workflows/ci.yml")}},
+	}}
+	after := &output.ScanResult{Owner: "my-org", Repositories: []output.RepositoryResult{
+		{FullName: "my-org/api"},
+	}}
+	pulls := map[string][]PullRequest{
+		"my-org/web": {{Number: 2, URL: "https://github.com/my-org/web/pull/2", State: StateOpen}},
+		"my-org/api": {{Number: 1, URL: "https://github.com/my-org/api/pull/1", State: StateMerged}},
+	}
+
+	report := BuildReport(before, after, pulls, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	if report.Summary.Repositories != 2 || report.Summary.Rescanned != 1 || report.Summary.Fixed != 1 || report.Summary.Remaining != 1 {
+		t.Errorf("Unexpected summary: %+v", report.Summary)
+	}
+	if report.Repositories[0].Repository != "my-org/api" || !report.Repositories[0].Rescanned {
+		t.Errorf("Expected my-org/api first and rescanned, got %+v", report.Repositories[0])
+	}
+	// A repository missing from the rescan keeps its issues open
+	if web := report.Repositories[1]; web.Rescanned || len(web.Remaining) != 1 {
+		t.Errorf("Expected my-org/web not rescanned with its issue remaining, got %+v", web)
+	}
+	if report.Closed() {
+		t.Error("Expected the report to be open with an issue remaining")
+	}
+
+	var markdown bytes.Buffer
+	if err := WriteMarkdown(&markdown, report); err != nil {
+		t.Fatalf("Failed to write Markdown: %v", err)
+	}
+	for _, want := range []string{"# Closure Report: my-org", "❌ Open", "[#1](https://github.com/my-org/api/pull/1) merged", "my-org/web (not rescanned)", "### Remaining"} {
+		if !strings.Contains(markdown.String(), want) {
+			t.Errorf("Expected Markdown to contain %q, got:\n%s", want, markdown.String())
+		}
+	}
+
+	var encoded bytes.Buffer
+	if err := WriteJSON(&encoded, report); err != nil {
+		t.Fatalf("Failed to write JSON: %v", err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(encoded.Bytes(), &decoded); err != nil || decoded.Summary != report.Summary {
+		t.Errorf("Expected the JSON report to round trip, got %+v, %v", decoded.Summary, err)
+	}
+}
+
+func TestReportClosed(t *testing.T) {
+	before := &output.ScanResult{Owner: "my-org", Repositories: []output.RepositoryResult{
+		{FullName: "my-org/api", Issues: []output.ActionIssue{issue("actions/checkout", "v3", "outdated", ".github/workflows/ci.yml")}},
+	}}
+	after := &output.ScanResult{Owner: "my-org", Repositories: []output.RepositoryResult{{FullName: "My-Org/API"}}}
+	pulls := map[string][]PullRequest{"my-org/api": {{Number: 1, State: StateMerged}}}
+
+	if report := BuildReport(before, after, pulls, time.Now()); !report.Closed() {
+		t.Errorf("Expected the report to be closed, got %+v", report.Summary)
+	}
+}
@@ -0,0 +1,194 @@
+package waves
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+)
+
+// DefaultPlanFile is where the wave plan is written when no output is given
+const DefaultPlanFile = ".actions-maintainer-waves.json"
+
+// Rollout waves, in the order pull requests are opened
+const (
+	WaveLowRisk  = 1 // Only minor and patch bumps, in repositories that do not deploy
+	WaveStandard = 2 // Everything else
+	WaveHighRisk = 3 // Breaking changes in repositories that deploy to environments
+	waveCount    = WaveHighRisk
+)
+
+// waveDescriptions explain what each wave holds
+var waveDescriptions = map[int]string{
+	WaveLowRisk:  "Low risk: only minor and patch bumps, no deploy workflows",
+	WaveStandard: "Standard: major bumps or migrations without deploy workflows, or minor bumps in repositories that deploy",
+	WaveHighRisk: "High risk: breaking changes in repositories that deploy to environments",
+}
+
+// Config holds configuration options for wave planning
+type Config struct {
+	Property string // Custom property whose value (1-3) assigns a repository's wave, overriding its risk
+}
+
+// Repository is a repository assigned to a wave
+type Repository struct {
+	FullName string `json:"full_name"`
+	Issues   int    `json:"issues"`
+	Reason   string `json:"reason"` // Why the repository is in its wave
+}
+
+// Wave is a group of repositories whose pull requests are opened together
+type Wave struct {
+	Number       int          `json:"number"`
+	Description  string       `json:"description"`
+	Repositories []Repository `json:"repositories"`
+}
+
+// Plan is the wave plan artifact consumed by create-pr --wave
+type Plan struct {
+	CreatedAt time.Time `json:"created_at"`
+	Property  string    `json:"property,omitempty"`
+	Waves     []Wave    `json:"waves"`
+}
+
+// Planner assigns repositories to waves one at a time, so large scan results are not held in memory
+type Planner struct {
+	property string
+	waves    [waveCount][]Repository
+}
+
+// NewPlanner creates a wave planner
+func NewPlanner(config *Config) *Planner {
+	if config == nil {
+		config = &Config{}
+	}
+	return &Planner{property: config.Property}
+}
+
+// Add assigns a repository with issues to its wave; repositories without issues need no pull request
+func (p *Planner) Add(repo *output.RepositoryResult) {
+	if len(repo.Issues) == 0 {
+		return
+	}
+
+	wave, reason := p.classify(repo)
+	p.waves[wave-1] = append(p.waves[wave-1], Repository{
+		FullName: repo.FullName,
+		Issues:   len(repo.Issues),
+		Reason:   reason,
+	})
+}
+
+// classify picks a repository's wave from its custom property, or else from the risk of its updates
+func (p *Planner) classify(repo *output.RepositoryResult) (int, string) {
+	if p.property != "" {
+		if value, ok := repo.CustomProperties[p.property]; ok {
+			if wave, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && wave >= 1 && wave <= waveCount {
+				return wave, fmt.Sprintf("custom property %s=%s", p.property, value)
+			}
+		}
+	}
+
+	deploys := len(repo.Environments) > 0
+	breaking := breakingIssue(repo.Issues)
+	switch {
+	case deploys && breaking != "":
+		return WaveHighRisk, breaking + " in a repository that deploys to environments"
+	case breaking != "":
+		return WaveStandard, breaking
+	case deploys:
+		return WaveStandard, "minor and patch bumps in a repository that deploys to environments"
+	default:
+		return WaveLowRisk, "minor and patch bumps only"
+	}
+}
+
+// breakingIssue describes the first issue whose update may break workflows, or returns "" when every
+// update is a minor or patch bump. Updates whose versions cannot be compared are treated as breaking.
+func breakingIssue(issues []output.ActionIssue) string {
+	for _, issue := range issues {
+		switch {
+		case issue.IssueType == "migration" || issue.MigrationTarget != "":
+			return fmt.Sprintf("migration of %s", issue.Repository)
+		case issue.Remediation != nil:
+			return fmt.Sprintf("removal of banned %s", issue.Repository)
+		case issue.HasTransformations:
+			return fmt.Sprintf("input transformations for %s", issue.Repository)
+		}
+		if issue.SuggestedVersion == "" {
+			continue
+		}
+		behind, ok := output.MajorVersionsBehind(issue)
+		switch {
+		case !ok:
+			return fmt.Sprintf("unversioned update of %s to %s", issue.Repository, issue.SuggestedVersion)
+		case behind > 0:
+			return fmt.Sprintf("major version bump of %s to %s", issue.Repository, issue.SuggestedVersion)
+		}
+	}
+	return ""
+}
+
+// Plan returns the wave plan, with the repositories of each wave sorted by name
+// Empty waves are kept so wave numbers mean the same thing in every plan.
+func (p *Planner) Plan(now time.Time) *Plan {
+	plan := &Plan{CreatedAt: now, Property: p.property}
+	for i, repositories := range p.waves {
+		sorted := append([]Repository{}, repositories...)
+		sort.Slice(sorted, func(a, b int) bool {
+			return sorted[a].FullName < sorted[b].FullName
+		})
+		plan.Waves = append(plan.Waves, Wave{
+			Number:       i + 1,
+			Description:  waveDescriptions[i+1],
+			Repositories: sorted,
+		})
+	}
+	return plan
+}
+
+// Repositories returns the names of the repositories in a wave
+func (p *Plan) Repositories(number int) (map[string]bool, error) {
+	for _, wave := range p.Waves {
+		if wave.Number != number {
+			continue
+		}
+		names := make(map[string]bool, len(wave.Repositories))
+		for _, repo := range wave.Repositories {
+			names[repo.FullName] = true
+		}
+		return names, nil
+	}
+	return nil, fmt.Errorf("wave %d is not in the wave plan", number)
+}
+
+// LoadPlan loads a wave plan file
+func LoadPlan(filename string) (*Plan, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read wave plan: %w", err)
+	}
+
+	plan := &Plan{}
+	if err := json.Unmarshal(data, plan); err != nil {
+		return nil, fmt.Errorf("unable to parse wave plan as JSON: %w", err)
+	}
+	return plan, nil
+}
+
+// Save writes the wave plan file
+func (p *Plan) Save(filename string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode wave plan: %w", err)
+	}
+	if err := os.WriteFile(filename, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write wave plan: %w", err)
+	}
+	return nil
+}
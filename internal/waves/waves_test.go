@@ -0,0 +1,125 @@
+package waves
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
+)
+
+func wavesRepositories() []output.RepositoryResult {
+	production := []workflow.EnvironmentUsage{{FilePath: ".github/workflows/deploy.yml", Job: "deploy", Environment: "production"}}
+	return []output.RepositoryResult{
+		{
+			FullName: "my-org/docs",
+			Issues:   []output.ActionIssue{{Repository: "actions/checkout", CurrentVersion: "v4.1.0", SuggestedVersion: "v4.2.2", IssueType: "outdated"}},
+		},
+		{
+			FullName: "my-org/api",
+			Issues:   []output.ActionIssue{{Repository: "actions/setup-node", CurrentVersion: "v3", SuggestedVersion: "v4", IssueType: "outdated"}},
+		},
+		{
+			FullName:     "my-org/web",
+			Environments: production,
+			Issues:       []output.ActionIssue{{Repository: "actions/checkout", CurrentVersion: "v4.1.0", SuggestedVersion: "v4.2.2", IssueType: "outdated"}},
+		},
+		{
+			FullName:     "my-org/payments",
+			Environments: production,
+			Issues:       []output.ActionIssue{{Repository: "old/deploy", CurrentVersion: "v1", IssueType: "migration", MigrationTarget: "new/deploy@v1"}},
+		},
+		{
+			FullName:         "my-org/billing",
+			Environments:     production,
+			CustomProperties: map[string]string{"rollout-wave": "1"},
+			Issues:           []output.ActionIssue{{Repository: "actions/setup-go", CurrentVersion: "v3", SuggestedVersion: "v5", IssueType: "outdated"}},
+		},
+		{
+			FullName: "my-org/clean",
+		},
+	}
+}
+
+func planWaves(t *testing.T, config *Config) *Plan {
+	t.Helper()
+	planner := NewPlanner(config)
+	repositories := wavesRepositories()
+	for i := range repositories {
+		planner.Add(&repositories[i])
+	}
+	return planner.Plan(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func waveNames(wave Wave) string {
+	var names []string
+	for _, repo := range wave.Repositories {
+		names = append(names, repo.FullName)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestPlanner_AssignsWavesByRisk(t *testing.T) {
+	plan := planWaves(t, nil)
+	if len(plan.Waves) != 3 {
+		t.Fatalf("Expected 3 waves, got %d", len(plan.Waves))
+	}
+
+	expected := []string{
+		"my-org/docs",
+		"my-org/api,my-org/web",
+		"my-org/billing,my-org/payments",
+	}
+	for i, want := range expected {
+		if got := waveNames(plan.Waves[i]); got != want {
+			t.Errorf("Wave %d: expected %s, got %s", i+1, want, got)
+		}
+	}
+
+	payments := plan.Waves[2].Repositories[1]
+	if payments.Issues != 1 || !strings.Contains(payments.Reason, "migration of old/deploy") {
+		t.Errorf("Expected the migration to be the reason, got %+v", payments)
+	}
+}
+
+func TestPlanner_CustomPropertyOverridesRisk(t *testing.T) {
+	plan := planWaves(t, &Config{Property: "rollout-wave"})
+
+	if got := waveNames(plan.Waves[0]); got != "my-org/billing,my-org/docs" {
+		t.Errorf("Expected the property to move billing to wave 1, got %s", got)
+	}
+	if reason := plan.Waves[0].Repositories[0].Reason; reason != "custom property rollout-wave=1" {
+		t.Errorf("Unexpected reason %q", reason)
+	}
+}
+
+func TestBreakingIssue_UnversionedUpdates(t *testing.T) {
+	issues := []output.ActionIssue{{Repository: "actions/checkout", CurrentVersion: "main", SuggestedVersion: "v4"}}
+	if breakingIssue(issues) == "" {
+		t.Errorf("Expected updates whose versions cannot be compared to be breaking")
+	}
+}
+
+func TestPlan_SaveAndLoad(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "waves.json")
+	if err := planWaves(t, nil).Save(filename); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+
+	plan, err := LoadPlan(filename)
+	if err != nil {
+		t.Fatalf("Failed to load plan: %v", err)
+	}
+	names, err := plan.Repositories(2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(names) != 2 || !names["my-org/api"] || !names["my-org/web"] {
+		t.Errorf("Unexpected wave 2 repositories: %v", names)
+	}
+	if _, err := plan.Repositories(4); err == nil {
+		t.Errorf("Expected an error for a wave not in the plan")
+	}
+}
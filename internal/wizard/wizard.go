@@ -0,0 +1,198 @@
+package wizard
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/pipeline"
+)
+
+// DefaultConfigFile is where init writes the pipeline config unless told otherwise
+const DefaultConfigFile = "actions-maintainer.json"
+
+// ErrInputEnded is returned when input runs out before every question is answered
+var ErrInputEnded = errors.New("input ended before setup was complete")
+
+// Prompter asks questions on a terminal and reads one answer per line
+type Prompter struct {
+	scanner *bufio.Scanner
+	out     io.Writer
+}
+
+// NewPrompter creates a prompter reading answers from in and writing questions to out
+func NewPrompter(in io.Reader, out io.Writer) *Prompter {
+	return &Prompter{
+		scanner: bufio.NewScanner(in),
+		out:     out,
+	}
+}
+
+// Ask asks a question and returns the trimmed answer, or def when the answer is empty
+func (p *Prompter) Ask(question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+
+	if !p.scanner.Scan() {
+		fmt.Fprintln(p.out)
+		if err := p.scanner.Err(); err != nil {
+			return "", fmt.Errorf("unable to read answer: %w", err)
+		}
+		return "", ErrInputEnded
+	}
+
+	answer := strings.TrimSpace(p.scanner.Text())
+	if answer == "" {
+		return def, nil
+	}
+	return answer, nil
+}
+
+// AskValid asks a question until validate accepts the answer
+func (p *Prompter) AskValid(question, def string, validate func(string) error) (string, error) {
+	for {
+		answer, err := p.Ask(question, def)
+		if err != nil {
+			return "", err
+		}
+		if err := validate(answer); err != nil {
+			fmt.Fprintf(p.out, "  %v\n", err)
+			continue
+		}
+		return answer, nil
+	}
+}
+
+// Confirm asks a yes/no question, returning def when the answer is empty
+func (p *Prompter) Confirm(question string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+
+	answer, err := p.AskValid(fmt.Sprintf("%s (%s)", question, hint), "", func(answer string) error {
+		switch strings.ToLower(answer) {
+		case "", "y", "yes", "n", "no":
+			return nil
+		}
+		return fmt.Errorf("please answer y or n")
+	})
+	if err != nil {
+		return false, err
+	}
+
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return true, nil
+	case "n", "no":
+		return false, nil
+	}
+	return def, nil
+}
+
+// Run asks for the settings of a first pipeline and returns the resulting config
+func Run(p *Prompter) (*pipeline.Config, error) {
+	config := &pipeline.Config{}
+	var err error
+
+	if config.Owner, err = p.AskValid("GitHub owner (user or organization) to scan", "", required); err != nil {
+		return nil, err
+	}
+
+	tokenEnv, err := p.AskValid("Environment variable holding your GitHub token", "GITHUB_TOKEN", required)
+	if err != nil {
+		return nil, err
+	}
+	if tokenEnv != "GITHUB_TOKEN" {
+		config.TokenEnv = tokenEnv
+	}
+
+	if config.Filter, err = p.AskValid("Repository name filter regex (empty for all repositories)", "", validRegex); err != nil {
+		return nil, err
+	}
+
+	if config.Scan.RulesFile, err = p.AskValid("Custom rules file (empty for built-in rules)", "", existingFile); err != nil {
+		return nil, err
+	}
+
+	if config.Scan.SuppressionsFile, err = p.AskValid("Suppressions file (empty for none)", "", existingFile); err != nil {
+		return nil, err
+	}
+
+	if config.Scan.Output, err = p.AskValid("Scan results file", "scan-results.json", jsonFile); err != nil {
+		return nil, err
+	}
+
+	if config.Report.Output, err = p.AskValid("Report file (.ipynb notebook or .json)", "actions-report.ipynb", reportFile); err != nil {
+		return nil, err
+	}
+
+	createPRs, err := p.Confirm("Create pull requests for updates when running the pipeline?", false)
+	if err != nil {
+		return nil, err
+	}
+	if createPRs {
+		config.CreatePR.Enabled = &createPRs
+
+		if config.CreatePR.Template, err = p.AskValid("Pull request body template (empty for the default)", "", existingFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// required rejects empty answers
+func required(answer string) error {
+	if answer == "" {
+		return fmt.Errorf("a value is required")
+	}
+	return nil
+}
+
+// validRegex accepts empty answers and valid regular expressions
+func validRegex(answer string) error {
+	if _, err := regexp.Compile(answer); err != nil {
+		return fmt.Errorf("invalid regex: %v", err)
+	}
+	return nil
+}
+
+// existingFile accepts empty answers and paths to existing files
+func existingFile(answer string) error {
+	if answer == "" {
+		return nil
+	}
+	if info, err := os.Stat(answer); err != nil || info.IsDir() {
+		return fmt.Errorf("file not found: %s", answer)
+	}
+	return nil
+}
+
+// jsonFile rejects notebook paths, which cannot hold scan results
+func jsonFile(answer string) error {
+	if output.IsNotebookFile(answer) {
+		return fmt.Errorf("scan results must be a JSON file")
+	}
+	return nil
+}
+
+// reportFile accepts notebook and JSON report paths
+func reportFile(answer string) error {
+	if !output.IsNotebookFile(answer) && !strings.HasSuffix(strings.ToLower(answer), ".json") {
+		return fmt.Errorf("report file must end in .ipynb or .json")
+	}
+	return nil
+}
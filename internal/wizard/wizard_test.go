@@ -0,0 +1,97 @@
+package wizard
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_Defaults(t *testing.T) {
+	var out bytes.Buffer
+	answers := strings.Join([]string{
+		"my-org", // owner
+		"",       // token env
+		"",       // filter
+		"",       // rules file
+		"",       // suppressions file
+		"",       // scan results
+		"",       // report
+		"",       // create PRs
+	}, "\n") + "\n"
+
+	config, err := Run(NewPrompter(strings.NewReader(answers), &out))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if config.Owner != "my-org" || config.TokenEnv != "" || config.Filter != "" {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+	if config.Scan.Output != "scan-results.json" || config.Report.Output != "actions-report.ipynb" {
+		t.Errorf("Unexpected output files: scan=%s report=%s", config.Scan.Output, config.Report.Output)
+	}
+	if config.CreatePR.Enabled != nil {
+		t.Errorf("Expected create-pr to stay disabled")
+	}
+	if !strings.Contains(out.String(), "GitHub owner") {
+		t.Errorf("Expected questions to be written, got %q", out.String())
+	}
+}
+
+func TestRun_RetriesInvalidAnswers(t *testing.T) {
+	dir := t.TempDir()
+	rulesFile := filepath.Join(dir, "rules.json")
+	if err := os.WriteFile(rulesFile, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	answers := strings.Join([]string{
+		"",           // owner (required, asked again)
+		"my-org",     // owner
+		"ORG_TOKEN",  // token env
+		"([",         // invalid filter
+		"^api-.*",    // filter
+		"missing",    // rules file that does not exist
+		rulesFile,    // rules file
+		"",           // suppressions file
+		"scan.ipynb", // scan results must be JSON
+		"scan.json",  // scan results
+		"report.txt", // unsupported report format
+		"report.json",
+		"maybe", // not a yes/no answer
+		"y",     // create PRs
+		"",      // PR template
+	}, "\n") + "\n"
+
+	config, err := Run(NewPrompter(strings.NewReader(answers), &out))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if config.Owner != "my-org" || config.TokenEnv != "ORG_TOKEN" || config.Filter != "^api-.*" {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+	if config.Scan.RulesFile != rulesFile || config.Scan.Output != "scan.json" || config.Report.Output != "report.json" {
+		t.Errorf("Unexpected files: %+v %+v", config.Scan, config.Report)
+	}
+	if config.CreatePR.Enabled == nil || !*config.CreatePR.Enabled {
+		t.Errorf("Expected create-pr to be enabled")
+	}
+	for _, message := range []string{"a value is required", "invalid regex", "file not found", "must be a JSON file", "must end in .ipynb or .json", "please answer y or n"} {
+		if !strings.Contains(out.String(), message) {
+			t.Errorf("Expected %q in output", message)
+		}
+	}
+}
+
+func TestRun_InputEnded(t *testing.T) {
+	var out bytes.Buffer
+	_, err := Run(NewPrompter(strings.NewReader("my-org\n"), &out))
+	if !errors.Is(err, ErrInputEnded) {
+		t.Errorf("Expected ErrInputEnded, got %v", err)
+	}
+}
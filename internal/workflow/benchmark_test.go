@@ -0,0 +1,52 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// generateWorkflow builds a workflow with the given number of jobs and steps per job
+func generateWorkflow(jobs, stepsPerJob int) string {
+	var b strings.Builder
+	b.WriteString("name: Benchmark\non: [push]\njobs:\n")
+	for j := 0; j < jobs; j++ {
+		fmt.Fprintf(&b, "  job-%d:\n    runs-on: ubuntu-latest\n    steps:\n", j)
+		for s := 0; s < stepsPerJob; s++ {
+			fmt.Fprintf(&b, "      - name: Step %d\n        uses: actions/checkout@b4ffde65f46336ab88eb53be808477a3936bae11 # v4.1.%d\n        with:\n          fetch-depth: %d\n", s, s, s)
+		}
+	}
+	b.WriteString("  reusable:\n    uses: my-org/workflows/.github/workflows/build.yml@v1\n")
+	return b.String()
+}
+
+func BenchmarkParseWorkflow_Small(b *testing.B) {
+	content := generateWorkflow(2, 5)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseWorkflow(content, ".github/workflows/ci.yml", "my-org/api"); err != nil {
+			b.Fatalf("ParseWorkflow() returned error: %v", err)
+		}
+	}
+}
+
+func BenchmarkParseWorkflow_Large(b *testing.B) {
+	content := generateWorkflow(50, 20)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseWorkflow(content, ".github/workflows/ci.yml", "my-org/api"); err != nil {
+			b.Fatalf("ParseWorkflow() returned error: %v", err)
+		}
+	}
+}
+
+func BenchmarkExtractPinComments(b *testing.B) {
+	content := generateWorkflow(50, 20)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		extractPinComments(content)
+	}
+}
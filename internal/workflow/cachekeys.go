@@ -0,0 +1,78 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CacheStep is a step saving or restoring a cache with actions/cache
+type CacheStep struct {
+	FilePath string
+	Job      string
+	Context  string // "job:<job>/step:<name>", as for action references
+	Uses     string // uses: as written, e.g. "actions/cache/restore@v4"
+	Key      string // key input as written
+	Path     string // path input as written
+	RunsOn   string // runs-on of the job when a single label or expression, e.g. "${{ matrix.os }}"
+}
+
+// RunnerVaries reports whether the job's runner is chosen by an expression, typically a matrix of
+// operating systems, so caches saved on one runner may be restored on another
+func (c CacheStep) RunnerVaries() bool {
+	return strings.Contains(c.RunsOn, "${{")
+}
+
+// IsCacheAction reports whether a uses: value calls actions/cache or its restore and save sub-actions
+func IsCacheAction(uses string) bool {
+	name, _, _ := strings.Cut(strings.ToLower(uses), "@")
+	return name == "actions/cache" || name == "actions/cache/restore" || name == "actions/cache/save"
+}
+
+// ParseCacheSteps returns the actions/cache steps of a workflow, sorted by job
+func ParseCacheSteps(content, filePath string, config *Config) ([]CacheStep, error) {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	workflow, err := decodeWorkflow(content, config)
+	if err != nil {
+		return nil, err
+	}
+
+	jobNames := make([]string, 0, len(workflow.Jobs))
+	for jobName := range workflow.Jobs {
+		jobNames = append(jobNames, jobName)
+	}
+	sort.Strings(jobNames)
+
+	var steps []CacheStep
+	for _, jobName := range jobNames {
+		job := workflow.Jobs[jobName]
+		runsOn, _ := job.RunsOn.(string)
+		for stepIdx, step := range job.Steps {
+			if !IsCacheAction(step.Uses) {
+				continue
+			}
+			stepName := step.Name
+			if stepName == "" {
+				stepName = fmt.Sprintf("step-%d", stepIdx+1)
+			}
+
+			key, _ := settingValue(step.With, "key").(string)
+			path, _ := settingValue(step.With, "path").(string)
+
+			steps = append(steps, CacheStep{
+				FilePath: filePath,
+				Job:      jobName,
+				Context:  fmt.Sprintf("job:%s/step:%s", jobName, stepName),
+				Uses:     step.Uses,
+				Key:      strings.TrimSpace(key),
+				Path:     strings.TrimSpace(path),
+				RunsOn:   strings.TrimSpace(runsOn),
+			})
+		}
+	}
+
+	return steps, nil
+}
@@ -0,0 +1,58 @@
+package workflow
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCacheSteps(t *testing.T) {
+	content := `
+on: push
+jobs:
+  test:
+    runs-on: ${{ matrix.os }}
+    steps:
+      - uses: actions/checkout@v4
+      - name: Cache modules
+        uses: actions/cache@v4
+        with:
+          path: ~/go/pkg/mod
+          key: go-${{ hashFiles('**/go.sum') }}
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/cache/restore@v4
+        with:
+          path: node_modules
+          key: static
+`
+	steps, err := ParseCacheSteps(content, "ci.yml", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []CacheStep{
+		{FilePath: "ci.yml", Job: "build", Context: "job:build/step:step-1", Uses: "actions/cache/restore@v4", Key: "static", Path: "node_modules", RunsOn: "ubuntu-latest"},
+		{FilePath: "ci.yml", Job: "test", Context: "job:test/step:Cache modules", Uses: "actions/cache@v4", Key: "go-${{ hashFiles('**/go.sum') }}", Path: "~/go/pkg/mod", RunsOn: "${{ matrix.os }}"},
+	}
+	if !reflect.DeepEqual(steps, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, steps)
+	}
+	if steps[0].RunnerVaries() || !steps[1].RunnerVaries() {
+		t.Errorf("Expected only the matrix job's runner to vary")
+	}
+}
+
+func TestIsCacheAction(t *testing.T) {
+	for uses, expected := range map[string]bool{
+		"actions/cache@v4":         true,
+		"Actions/Cache/save@v4":    true,
+		"actions/cache/restore@v3": true,
+		"actions/cache-other@v1":   false,
+		"actions/checkout@v4":      false,
+	} {
+		if IsCacheAction(uses) != expected {
+			t.Errorf("IsCacheAction(%q) = %v, expected %v", uses, !expected, expected)
+		}
+	}
+}
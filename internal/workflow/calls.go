@@ -0,0 +1,116 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ReusableCall is a job calling a reusable workflow, with the secrets it passes
+type ReusableCall struct {
+	FilePath       string
+	Job            string
+	Context        string   // "job:<job>"
+	Uses           string   // uses: as written
+	Secrets        []string // Secrets passed by name under secrets:, sorted
+	InheritSecrets bool     // "secrets: inherit" passes every secret of the caller
+}
+
+// CallSecret is a secret a reusable workflow declares under on.workflow_call.secrets
+type CallSecret struct {
+	Name     string
+	Required bool
+}
+
+// ParseReusableCalls returns the jobs of a workflow that call a reusable workflow, sorted by job
+func ParseReusableCalls(content, filePath string, config *Config) ([]ReusableCall, error) {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	workflow, err := decodeWorkflow(content, config)
+	if err != nil {
+		return nil, err
+	}
+
+	jobNames := make([]string, 0, len(workflow.Jobs))
+	for jobName := range workflow.Jobs {
+		jobNames = append(jobNames, jobName)
+	}
+	sort.Strings(jobNames)
+
+	var calls []ReusableCall
+	for _, jobName := range jobNames {
+		job := workflow.Jobs[jobName]
+		if job.Uses == "" {
+			continue
+		}
+		call := ReusableCall{
+			FilePath: filePath,
+			Job:      jobName,
+			Context:  fmt.Sprintf("job:%s", jobName),
+			Uses:     job.Uses,
+		}
+		switch secrets := job.Secrets.(type) {
+		case string:
+			call.InheritSecrets = strings.TrimSpace(secrets) == "inherit"
+		case map[string]interface{}:
+			for name := range secrets {
+				call.Secrets = append(call.Secrets, name)
+			}
+			sort.Strings(call.Secrets)
+		}
+		calls = append(calls, call)
+	}
+
+	return calls, nil
+}
+
+// ParseCallSecrets returns the secrets a reusable workflow declares for its callers, sorted by name,
+// and whether the workflow can be called at all, that is, whether it has a workflow_call trigger
+func ParseCallSecrets(content string, config *Config) ([]CallSecret, bool, error) {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	workflow, err := decodeWorkflow(content, config)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var settings interface{}
+	switch on := workflow.On.(type) {
+	case string:
+		if on != "workflow_call" {
+			return nil, false, nil
+		}
+	case []interface{}:
+		callable := false
+		for _, event := range on {
+			if name, ok := event.(string); ok && name == "workflow_call" {
+				callable = true
+			}
+		}
+		if !callable {
+			return nil, false, nil
+		}
+	case map[string]interface{}:
+		value, ok := on["workflow_call"]
+		if !ok {
+			return nil, false, nil
+		}
+		settings = value
+	default:
+		return nil, false, nil
+	}
+
+	declared, _ := settingValue(settings, "secrets").(map[string]interface{})
+	secrets := make([]CallSecret, 0, len(declared))
+	for name, definition := range declared {
+		required, _ := settingValue(definition, "required").(bool)
+		secrets = append(secrets, CallSecret{Name: name, Required: required})
+	}
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].Name < secrets[j].Name })
+
+	return secrets, true, nil
+}
@@ -0,0 +1,79 @@
+package workflow
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseReusableCalls(t *testing.T) {
+	content := `
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make test
+  deploy:
+    uses: my-org/workflows/.github/workflows/deploy.yml@v1
+    secrets:
+      DEPLOY_KEY: ${{ secrets.DEPLOY_KEY }}
+      AWS_ROLE: ${{ secrets.AWS_ROLE }}
+  release:
+    uses: ./.github/workflows/release.yml
+    secrets: inherit
+  lint:
+    uses: my-org/workflows/.github/workflows/lint.yml@v1
+`
+	calls, err := ParseReusableCalls(content, "ci.yml", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []ReusableCall{
+		{FilePath: "ci.yml", Job: "deploy", Context: "job:deploy", Uses: "my-org/workflows/.github/workflows/deploy.yml@v1", Secrets: []string{"AWS_ROLE", "DEPLOY_KEY"}},
+		{FilePath: "ci.yml", Job: "lint", Context: "job:lint", Uses: "my-org/workflows/.github/workflows/lint.yml@v1"},
+		{FilePath: "ci.yml", Job: "release", Context: "job:release", Uses: "./.github/workflows/release.yml", InheritSecrets: true},
+	}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Unexpected calls:\n got: %+v\nwant: %+v", calls, expected)
+	}
+}
+
+func TestParseCallSecrets(t *testing.T) {
+	content := `
+on:
+  workflow_call:
+    inputs:
+      environment:
+        type: string
+    secrets:
+      DEPLOY_KEY:
+        required: true
+      SLACK_WEBHOOK:
+        description: Optional notifications
+  workflow_dispatch:
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - run: ./deploy.sh
+`
+	secrets, callable, err := ParseCallSecrets(content, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []CallSecret{{Name: "DEPLOY_KEY", Required: true}, {Name: "SLACK_WEBHOOK"}}
+	if !callable || !reflect.DeepEqual(secrets, expected) {
+		t.Errorf("Expected a callable workflow with %+v, got %v %+v", expected, callable, secrets)
+	}
+
+	for _, on := range []string{"workflow_call", "[push, workflow_call]"} {
+		secrets, callable, err := ParseCallSecrets("on: "+on+"\njobs: {}\n", nil)
+		if err != nil || !callable || len(secrets) != 0 {
+			t.Errorf("on: %s: expected a callable workflow without secrets, got %v %+v %v", on, callable, secrets, err)
+		}
+	}
+	if _, callable, _ := ParseCallSecrets("on: push\njobs: {}\n", nil); callable {
+		t.Error("Expected a push workflow not to be callable")
+	}
+}
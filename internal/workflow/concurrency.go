@@ -0,0 +1,110 @@
+package workflow
+
+import (
+	"sort"
+	"strings"
+)
+
+// ConcurrencySettings is the concurrency policy of a single workflow file
+// A concurrency group stops two runs of a deploy workflow from deploying at once, and cancel-in-progress
+// stops superseded pull request runs from using runner minutes.
+type ConcurrencySettings struct {
+	FilePath         string
+	Group            string   // Workflow-level concurrency group; empty when not set
+	CancelInProgress string   // Workflow-level cancel-in-progress as written ("true", "false", or an expression); empty when not set
+	PullRequest      bool     // Triggered by pull_request
+	DeployJobs       []string // Jobs deploying to an environment, sorted
+	GroupedJobs      []string // Jobs setting their own concurrency group, sorted
+}
+
+// Deploys reports whether any job deploys to an environment
+func (c *ConcurrencySettings) Deploys() bool {
+	return len(c.DeployJobs) > 0
+}
+
+// DeploysUngrouped returns the deploy jobs that can run concurrently with another run of the workflow:
+// all of them when the workflow sets no group, otherwise none
+func (c *ConcurrencySettings) DeploysUngrouped() []string {
+	if c.Group != "" {
+		return nil
+	}
+	grouped := make(map[string]bool, len(c.GroupedJobs))
+	for _, job := range c.GroupedJobs {
+		grouped[job] = true
+	}
+	var jobs []string
+	for _, job := range c.DeployJobs {
+		if !grouped[job] {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
+// ParseConcurrency parses the workflow- and job-level concurrency: of a workflow
+func ParseConcurrency(content, filePath string, config *Config) (*ConcurrencySettings, error) {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	workflow, err := decodeWorkflow(content, config)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := &ConcurrencySettings{FilePath: filePath, PullRequest: triggeredBy(workflow.On, "pull_request")}
+	settings.Group, settings.CancelInProgress = concurrencyValues(workflow.Concurrency)
+
+	for jobName, job := range workflow.Jobs {
+		if group, _ := concurrencyValues(job.Concurrency); group != "" {
+			settings.GroupedJobs = append(settings.GroupedJobs, jobName)
+		}
+		if job.Environment != nil {
+			settings.DeployJobs = append(settings.DeployJobs, jobName)
+		}
+	}
+	sort.Strings(settings.GroupedJobs)
+	sort.Strings(settings.DeployJobs)
+
+	return settings, nil
+}
+
+// concurrencyValues returns the group and cancel-in-progress of a concurrency: value
+func concurrencyValues(concurrency interface{}) (string, string) {
+	switch value := concurrency.(type) {
+	case string:
+		return strings.TrimSpace(value), ""
+	case map[string]interface{}:
+		group, _ := value["group"].(string)
+		cancel := ""
+		switch setting := value["cancel-in-progress"].(type) {
+		case bool:
+			cancel = "false"
+			if setting {
+				cancel = "true"
+			}
+		case string:
+			cancel = strings.TrimSpace(setting)
+		}
+		return strings.TrimSpace(group), cancel
+	}
+	return "", ""
+}
+
+// triggeredBy reports whether an on: value lists an event
+func triggeredBy(on interface{}, event string) bool {
+	switch value := on.(type) {
+	case string:
+		return value == event
+	case []interface{}:
+		for _, item := range value {
+			if item == event {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		_, ok := value[event]
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package workflow
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseConcurrency(t *testing.T) {
+	content := `
+on:
+  pull_request:
+  push:
+    branches: [main]
+concurrency:
+  group: ci-${{ github.ref }}
+  cancel-in-progress: true
+jobs:
+  test:
+    runs-on: ubuntu-latest
+  deploy:
+    runs-on: ubuntu-latest
+    environment: production
+    concurrency: deploy-production
+`
+	settings, err := ParseConcurrency(content, "ci.yml", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := &ConcurrencySettings{
+		FilePath:         "ci.yml",
+		Group:            "ci-${{ github.ref }}",
+		CancelInProgress: "true",
+		PullRequest:      true,
+		DeployJobs:       []string{"deploy"},
+		GroupedJobs:      []string{"deploy"},
+	}
+	if !reflect.DeepEqual(settings, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, settings)
+	}
+}
+
+func TestConcurrencySettings_DeploysUngrouped(t *testing.T) {
+	settings, err := ParseConcurrency(`
+on: push
+jobs:
+  staging:
+    environment: staging
+    concurrency: staging
+  production:
+    environment:
+      name: production
+`, "deploy.yml", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if settings.PullRequest || settings.CancelInProgress != "" {
+		t.Errorf("Expected a push workflow without cancel-in-progress, got %+v", settings)
+	}
+	if jobs := settings.DeploysUngrouped(); !reflect.DeepEqual(jobs, []string{"production"}) {
+		t.Errorf("Expected only the production job to be ungrouped, got %v", jobs)
+	}
+
+	settings.Group = "deploy"
+	if jobs := settings.DeploysUngrouped(); len(jobs) != 0 {
+		t.Errorf("Expected a workflow group to cover every job, got %v", jobs)
+	}
+}
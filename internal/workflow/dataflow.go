@@ -0,0 +1,255 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Kinds of sensitive values tracked by ParseSecretFlows
+const (
+	FlowKindSecret   = "secret"
+	FlowKindVariable = "variable"
+)
+
+// Ways a sensitive value reaches an action
+const (
+	FlowViaWith    = "with"    // Action or reusable workflow input
+	FlowViaEnv     = "env"     // Environment variable of the step, job, or workflow
+	FlowViaSecrets = "secrets" // Secret of a reusable workflow call
+)
+
+// AllNames stands for every secret, as with "secrets: inherit" or toJSON(secrets)
+const AllNames = "*"
+
+// githubOwners publish the actions GitHub itself maintains
+var githubOwners = map[string]bool{"actions": true, "github": true}
+
+// SecretFlow is a secret or variable passed to an action or reusable workflow
+type SecretFlow struct {
+	FilePath string `json:"file_path"`
+	Job      string `json:"job"`
+	Step     string `json:"step,omitempty"` // Step name, or "#N" for unnamed steps; empty for reusable workflow calls
+	Action   string `json:"action"`         // owner/repo[/path] of the action or reusable workflow, or docker://image
+	Version  string `json:"version,omitempty"`
+	Via      string `json:"via"`             // FlowViaWith, FlowViaEnv, or FlowViaSecrets
+	Input    string `json:"input"`           // Input, environment variable, or secret receiving the value
+	Kind     string `json:"kind"`            // FlowKindSecret or FlowKindVariable
+	Name     string `json:"name"`            // Secret or variable name; GITHUB_TOKEN for github.token, AllNames for all secrets
+	Scope    string `json:"scope,omitempty"` // Where the value is defined, when resolved against the secret inventory
+}
+
+// ThirdParty reports whether the receiving action is published outside the repository owner and GitHub
+func (f SecretFlow) ThirdParty(repoOwner string) bool {
+	if strings.HasPrefix(f.Action, "docker://") {
+		return true
+	}
+	owner, _, _ := strings.Cut(f.Action, "/")
+	return !strings.EqualFold(owner, repoOwner) && !githubOwners[strings.ToLower(owner)]
+}
+
+// sensitiveValue is a secret or variable read by an expression
+type sensitiveValue struct {
+	kind string
+	name string
+}
+
+// ParseSecretFlows parses a workflow and returns each secret and variable passed to a remote action or
+// reusable workflow through with:, env:, or secrets:. Workflow and job env is inherited by every action
+// step of the job, and values copied into env and read back through env.NAME are followed. Local actions
+// are part of the repository and are not reported.
+func ParseSecretFlows(content, filePath string, config *Config) ([]SecretFlow, error) {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	workflow, err := decodeWorkflow(content, config)
+	if err != nil {
+		return nil, err
+	}
+
+	workflowEnv := sensitiveEnv(workflow.Env, nil)
+
+	jobNames := make([]string, 0, len(workflow.Jobs))
+	for jobName := range workflow.Jobs {
+		jobNames = append(jobNames, jobName)
+	}
+	sort.Strings(jobNames)
+
+	var flows []SecretFlow
+	for _, jobName := range jobNames {
+		job := workflow.Jobs[jobName]
+		jobEnv := mergeEnv(workflowEnv, sensitiveEnv(job.Env, workflowEnv))
+
+		if job.Uses != "" {
+			action, version := flowAction(job.Uses)
+			if action == "" {
+				continue
+			}
+			base := SecretFlow{FilePath: filePath, Job: jobName, Action: action, Version: version}
+			flows = append(flows, mappingFlows(base, FlowViaWith, job.With, jobEnv)...)
+			if inherit, ok := job.Secrets.(string); ok && strings.TrimSpace(inherit) == "inherit" {
+				flow := base
+				flow.Via, flow.Input, flow.Kind, flow.Name = FlowViaSecrets, AllNames, FlowKindSecret, AllNames
+				flows = append(flows, flow)
+			} else {
+				flows = append(flows, mappingFlows(base, FlowViaSecrets, job.Secrets, jobEnv)...)
+			}
+			continue
+		}
+
+		for i, step := range job.Steps {
+			if step.Uses == "" {
+				continue
+			}
+			action, version := flowAction(step.Uses)
+			if action == "" {
+				continue
+			}
+			name := step.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			base := SecretFlow{FilePath: filePath, Job: jobName, Step: name, Action: action, Version: version}
+
+			stepEnv := mergeEnv(jobEnv, sensitiveEnv(step.Env, jobEnv))
+			flows = append(flows, mappingFlows(base, FlowViaWith, step.With, stepEnv)...)
+			flows = append(flows, envFlows(base, stepEnv)...)
+		}
+	}
+
+	return flows, nil
+}
+
+// flowAction returns the reference of a remote action or reusable workflow, or "" for local ones
+func flowAction(uses string) (action, version string) {
+	if strings.HasPrefix(uses, "docker://") {
+		return uses, ""
+	}
+	ref := parseActionRef(uses, false)
+	if ref == nil {
+		return "", ""
+	}
+	action = ref.Repository
+	if ref.WorkflowPath != "" {
+		action += "/" + ref.WorkflowPath
+	}
+	return action, ref.Version
+}
+
+// mappingFlows returns a flow for each sensitive value read by the values of a with: or secrets: mapping
+func mappingFlows(base SecretFlow, via string, mapping interface{}, env map[string][]sensitiveValue) []SecretFlow {
+	values, ok := mapping.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var flows []SecretFlow
+	for _, key := range keys {
+		value, ok := values[key].(string)
+		if !ok {
+			continue
+		}
+		for _, sensitive := range sensitiveValues(value, env) {
+			flow := base
+			flow.Via, flow.Input, flow.Kind, flow.Name = via, key, sensitive.kind, sensitive.name
+			flows = append(flows, flow)
+		}
+	}
+	return flows
+}
+
+// envFlows returns a flow for each sensitive value in the environment an action step runs with
+func envFlows(base SecretFlow, env map[string][]sensitiveValue) []SecretFlow {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var flows []SecretFlow
+	for _, name := range names {
+		for _, sensitive := range env[name] {
+			flow := base
+			flow.Via, flow.Input, flow.Kind, flow.Name = FlowViaEnv, name, sensitive.kind, sensitive.name
+			flows = append(flows, flow)
+		}
+	}
+	return flows
+}
+
+// sensitiveEnv returns the sensitive values of each variable of an env: mapping, following env.NAME
+// references into the inherited environment. Variables without sensitive values are kept with none,
+// so they shadow inherited variables of the same name.
+func sensitiveEnv(env interface{}, inherited map[string][]sensitiveValue) map[string][]sensitiveValue {
+	values, ok := env.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	result := make(map[string][]sensitiveValue, len(values))
+	for name, value := range values {
+		text, _ := value.(string)
+		result[name] = sensitiveValues(text, inherited)
+	}
+	return result
+}
+
+// mergeEnv overlays an environment on the inherited one
+func mergeEnv(inherited, overlay map[string][]sensitiveValue) map[string][]sensitiveValue {
+	if len(overlay) == 0 {
+		return inherited
+	}
+	merged := make(map[string][]sensitiveValue, len(inherited)+len(overlay))
+	for name, values := range inherited {
+		merged[name] = values
+	}
+	for name, values := range overlay {
+		merged[name] = values
+	}
+	return merged
+}
+
+// sensitiveValues returns the secrets and variables the expressions of a value read, directly or through env
+func sensitiveValues(value string, env map[string][]sensitiveValue) []sensitiveValue {
+	var values []sensitiveValue
+	seen := make(map[sensitiveValue]bool)
+	add := func(value sensitiveValue) {
+		if !seen[value] {
+			seen[value] = true
+			values = append(values, value)
+		}
+	}
+
+	for _, expression := range ExtractExpressions(value) {
+		references, err := ParseExpression(expression)
+		if err != nil {
+			continue
+		}
+		for _, reference := range references {
+			switch {
+			case reference.Context == "secrets":
+				name := reference.Property
+				if name == "" {
+					name = AllNames
+				}
+				add(sensitiveValue{FlowKindSecret, name})
+			case reference.Context == "vars" && reference.Property != "":
+				add(sensitiveValue{FlowKindVariable, reference.Property})
+			case reference.Context == "github" && strings.EqualFold(reference.Property, "token"):
+				add(sensitiveValue{FlowKindSecret, "GITHUB_TOKEN"})
+			case reference.Context == "env" && reference.Property != "":
+				for _, inherited := range env[reference.Property] {
+					add(inherited)
+				}
+			}
+		}
+	}
+	return values
+}
@@ -0,0 +1,174 @@
+package workflow
+
+import (
+	"fmt"
+	"testing"
+)
+
+// flowKeys formats flows as "job step action via input kind name" for compact comparison
+func flowKeys(flows []SecretFlow) []string {
+	keys := make([]string, len(flows))
+	for i, flow := range flows {
+		keys[i] = fmt.Sprintf("%s %s %s %s %s %s %s", flow.Job, flow.Step, flow.Action, flow.Via, flow.Input, flow.Kind, flow.Name)
+	}
+	return keys
+}
+
+func assertFlows(t *testing.T, flows []SecretFlow, expected []string) {
+	t.Helper()
+	keys := flowKeys(flows)
+	if len(keys) != len(expected) {
+		t.Fatalf("Expected %d flows, got %d: %v", len(expected), len(keys), keys)
+	}
+	for i := range expected {
+		if keys[i] != expected[i] {
+			t.Errorf("Flow %d: expected %q, got %q", i, expected[i], keys[i])
+		}
+	}
+}
+
+func TestParseSecretFlows_WithAndEnv(t *testing.T) {
+	content := `
+on: push
+env:
+  NPM_TOKEN: ${{ secrets.NPM_TOKEN }}
+  REGION: us-east-1
+jobs:
+  publish:
+    runs-on: ubuntu-latest
+    env:
+      DEPLOY_URL: ${{ vars.DEPLOY_URL }}
+    steps:
+      - uses: actions/checkout@v4
+      - name: Publish
+        uses: third-party/publish-action@v2
+        with:
+          token: ${{ secrets.PUBLISH_TOKEN }}
+          registry: https://registry.example.com
+      - name: Notify
+        uses: acme/slack-notify@v1
+        env:
+          NPM_TOKEN: not-a-secret
+          WEBHOOK: ${{ secrets.SLACK_WEBHOOK }}
+      - run: echo "${{ secrets.NOT_AN_ACTION }}"
+      - uses: ./.github/actions/local
+        with:
+          token: ${{ secrets.LOCAL_TOKEN }}
+`
+	flows, err := ParseSecretFlows(content, ".github/workflows/publish.yml", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Inherited env reaches every action step; the Notify step's NPM_TOKEN shadows the secret
+	assertFlows(t, flows, []string{
+		"publish #1 actions/checkout env DEPLOY_URL variable DEPLOY_URL",
+		"publish #1 actions/checkout env NPM_TOKEN secret NPM_TOKEN",
+		"publish Publish third-party/publish-action with token secret PUBLISH_TOKEN",
+		"publish Publish third-party/publish-action env DEPLOY_URL variable DEPLOY_URL",
+		"publish Publish third-party/publish-action env NPM_TOKEN secret NPM_TOKEN",
+		"publish Notify acme/slack-notify env DEPLOY_URL variable DEPLOY_URL",
+		"publish Notify acme/slack-notify env WEBHOOK secret SLACK_WEBHOOK",
+	})
+	if flows[2].Version != "v2" || flows[2].FilePath != ".github/workflows/publish.yml" {
+		t.Errorf("Expected version v2 and file path to be recorded, got %+v", flows[2])
+	}
+}
+
+func TestParseSecretFlows_EnvIndirection(t *testing.T) {
+	content := `
+on: push
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    env:
+      API_KEY: ${{ secrets.API_KEY }}
+    steps:
+      - uses: acme/deploy@v1
+        env:
+          API_KEY: ""
+        with:
+          key: ${{ env.API_KEY }}
+          token: ${{ github.token }}
+          all: ${{ toJSON(secrets) }}
+`
+	flows, err := ParseSecretFlows(content, "deploy.yml", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// env.API_KEY resolves against the step env, which clears the job's secret
+	assertFlows(t, flows, []string{
+		"deploy #1 acme/deploy with all secret *",
+		"deploy #1 acme/deploy with token secret GITHUB_TOKEN",
+	})
+
+	content = `
+on: push
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    env:
+      API_KEY: ${{ secrets.API_KEY }}
+    steps:
+      - uses: acme/deploy@v1
+        with:
+          key: ${{ env.API_KEY }}
+`
+	flows, err = ParseSecretFlows(content, "deploy.yml", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertFlows(t, flows, []string{
+		"deploy #1 acme/deploy with key secret API_KEY",
+		"deploy #1 acme/deploy env API_KEY secret API_KEY",
+	})
+}
+
+func TestParseSecretFlows_ReusableWorkflows(t *testing.T) {
+	content := `
+on: push
+jobs:
+  build:
+    uses: shared-org/workflows/.github/workflows/build.yml@v1
+    with:
+      environment: ${{ vars.ENVIRONMENT }}
+    secrets:
+      registry-password: ${{ secrets.REGISTRY_PASSWORD }}
+  release:
+    uses: shared-org/workflows/.github/workflows/release.yml@main
+    secrets: inherit
+  local:
+    uses: ./.github/workflows/local.yml
+    secrets: inherit
+`
+	flows, err := ParseSecretFlows(content, "ci.yml", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertFlows(t, flows, []string{
+		"build  shared-org/workflows/.github/workflows/build.yml with environment variable ENVIRONMENT",
+		"build  shared-org/workflows/.github/workflows/build.yml secrets registry-password secret REGISTRY_PASSWORD",
+		"release  shared-org/workflows/.github/workflows/release.yml secrets * secret *",
+	})
+}
+
+func TestSecretFlowThirdParty(t *testing.T) {
+	tests := []struct {
+		action   string
+		expected bool
+	}{
+		{"actions/checkout", false},
+		{"github/codeql-action/init", false},
+		{"My-Org/internal-action", false},
+		{"acme/deploy", true},
+		{"docker://alpine:3.20", true},
+	}
+
+	for _, tt := range tests {
+		if got := (SecretFlow{Action: tt.action}).ThirdParty("my-org"); got != tt.expected {
+			t.Errorf("ThirdParty(%q): expected %v, got %v", tt.action, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DispatchInput is an input of a workflow's workflow_dispatch trigger
+type DispatchInput struct {
+	FilePath   string
+	Name       string
+	Type       string   // string, boolean, choice, number, or environment; string when unset
+	Default    string   // Default as written, with booleans and numbers formatted; empty when unset
+	HasDefault bool     // A default is set, even an empty one
+	Options    []string // Choices of a choice input
+	Required   bool
+}
+
+// ParseDispatchInputs returns the inputs of a workflow's workflow_dispatch trigger, sorted by name
+// Workflows without a workflow_dispatch trigger, or with one that has no inputs, return none.
+func ParseDispatchInputs(content, filePath string, config *Config) ([]DispatchInput, error) {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	workflow, err := decodeWorkflow(content, config)
+	if err != nil {
+		return nil, err
+	}
+
+	on, ok := workflow.On.(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	declared, _ := settingValue(on["workflow_dispatch"], "inputs").(map[string]interface{})
+
+	inputs := make([]DispatchInput, 0, len(declared))
+	for name, definition := range declared {
+		input := DispatchInput{FilePath: filePath, Name: name, Type: "string"}
+		if inputType, ok := settingValue(definition, "type").(string); ok && inputType != "" {
+			input.Type = inputType
+		}
+		if value := settingValue(definition, "default"); value != nil {
+			input.Default = fmt.Sprint(value)
+			input.HasDefault = true
+		}
+		input.Options = stringList(settingValue(definition, "options"))
+		input.Required, _ = settingValue(definition, "required").(bool)
+		inputs = append(inputs, input)
+	}
+	sort.Slice(inputs, func(i, j int) bool { return inputs[i].Name < inputs[j].Name })
+
+	return inputs, nil
+}
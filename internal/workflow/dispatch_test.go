@@ -0,0 +1,51 @@
+package workflow
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDispatchInputs(t *testing.T) {
+	content := `
+on:
+  push:
+  workflow_dispatch:
+    inputs:
+      environment:
+        type: environment
+        default: production
+      force:
+        type: boolean
+        default: true
+      region:
+        type: choice
+        options: [us-east-1, eu-west-1]
+        required: true
+      note:
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - run: ./deploy.sh
+`
+	inputs, err := ParseDispatchInputs(content, "deploy.yml", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []DispatchInput{
+		{FilePath: "deploy.yml", Name: "environment", Type: "environment", Default: "production", HasDefault: true},
+		{FilePath: "deploy.yml", Name: "force", Type: "boolean", Default: "true", HasDefault: true},
+		{FilePath: "deploy.yml", Name: "note", Type: "string"},
+		{FilePath: "deploy.yml", Name: "region", Type: "choice", Options: []string{"us-east-1", "eu-west-1"}, Required: true},
+	}
+	if !reflect.DeepEqual(inputs, expected) {
+		t.Errorf("Unexpected inputs:\n got: %+v\nwant: %+v", inputs, expected)
+	}
+
+	for _, content := range []string{"on: workflow_dispatch\njobs: {}\n", "on: [push]\njobs: {}\n"} {
+		if inputs, err := ParseDispatchInputs(content, "ci.yml", nil); err != nil || len(inputs) != 0 {
+			t.Errorf("Expected no inputs for %q, got %+v, %v", content, inputs, err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package workflow
+
+import (
+	"sort"
+	"strings"
+)
+
+// EnvironmentUsage is a job deploying to a GitHub Actions environment
+// Environments can require reviewers, wait timers and branch policies before a job runs, and hold the
+// secrets a deployment uses, so jobs targeting them are the highest-risk parts of a workflow.
+type EnvironmentUsage struct {
+	FilePath    string `json:"file_path"`
+	Job         string `json:"job"`
+	Environment string `json:"environment"`   // Name as written; may be an expression such as ${{ inputs.target }}
+	URL         string `json:"url,omitempty"` // Deployment URL shown on the run, when set
+}
+
+// Dynamic reports whether the environment is chosen by an expression at run time
+func (e EnvironmentUsage) Dynamic() bool {
+	return strings.Contains(e.Environment, "${{")
+}
+
+// ParseEnvironments parses the environment: of each job in a workflow, sorted by job name
+func ParseEnvironments(content, filePath string, config *Config) ([]EnvironmentUsage, error) {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	workflow, err := decodeWorkflow(content, config)
+	if err != nil {
+		return nil, err
+	}
+
+	jobNames := make([]string, 0, len(workflow.Jobs))
+	for jobName := range workflow.Jobs {
+		jobNames = append(jobNames, jobName)
+	}
+	sort.Strings(jobNames)
+
+	var environments []EnvironmentUsage
+	for _, jobName := range jobNames {
+		var name, url string
+		switch environment := workflow.Jobs[jobName].Environment.(type) {
+		case string:
+			name = environment
+		case map[string]interface{}:
+			name, _ = environment["name"].(string)
+			url, _ = environment["url"].(string)
+		}
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		environments = append(environments, EnvironmentUsage{
+			FilePath:    filePath,
+			Job:         jobName,
+			Environment: name,
+			URL:         strings.TrimSpace(url),
+		})
+	}
+
+	return environments, nil
+}
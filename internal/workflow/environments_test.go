@@ -0,0 +1,50 @@
+package workflow
+
+import "testing"
+
+func TestParseEnvironments(t *testing.T) {
+	content := `
+on: push
+jobs:
+  deploy-prod:
+    runs-on: ubuntu-latest
+    environment:
+      name: production
+      url: https://example.com
+    steps:
+      - uses: actions/checkout@v4
+  deploy-staging:
+    runs-on: ubuntu-latest
+    environment: staging
+  deploy-dynamic:
+    runs-on: ubuntu-latest
+    environment: ${{ inputs.target }}
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make
+`
+	environments, err := ParseEnvironments(content, ".github/workflows/deploy.yml", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []EnvironmentUsage{
+		{Job: "deploy-dynamic", Environment: "${{ inputs.target }}"},
+		{Job: "deploy-prod", Environment: "production", URL: "https://example.com"},
+		{Job: "deploy-staging", Environment: "staging"},
+	}
+	if len(environments) != len(expected) {
+		t.Fatalf("Expected %d environments, got %d: %+v", len(expected), len(environments), environments)
+	}
+	for i, want := range expected {
+		want.FilePath = ".github/workflows/deploy.yml"
+		if environments[i] != want {
+			t.Errorf("Environment %d: expected %+v, got %+v", i, want, environments[i])
+		}
+	}
+
+	if !environments[0].Dynamic() || environments[1].Dynamic() {
+		t.Errorf("Expected only the expression environment to be dynamic")
+	}
+}
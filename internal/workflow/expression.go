@@ -0,0 +1,305 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+)
+
+// expressionContexts are the contexts available to workflow expressions
+var expressionContexts = map[string]bool{
+	"github": true, "env": true, "vars": true, "job": true, "jobs": true, "steps": true, "runner": true,
+	"secrets": true, "strategy": true, "matrix": true, "needs": true, "inputs": true,
+}
+
+// expressionFunctions are the functions available to workflow expressions, in lower case
+var expressionFunctions = map[string]bool{
+	"contains": true, "startswith": true, "endswith": true, "format": true, "join": true, "tojson": true,
+	"fromjson": true, "hashfiles": true, "success": true, "always": true, "cancelled": true, "failure": true,
+	"case": true,
+}
+
+// ContextReference is a context property an expression reads, e.g. needs.build for needs.build.outputs.version
+type ContextReference struct {
+	Context  string // Lower-case context name, e.g. "needs"
+	Property string // First property, e.g. "build"; empty when the context is used whole or indexed dynamically
+}
+
+// ParseExpression checks the syntax of a workflow expression, without its ${{ }} delimiters,
+// and returns the context properties it reads
+func ParseExpression(expression string) ([]ContextReference, error) {
+	tokens, err := tokenizeExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &expressionParser{tokens: tokens}
+	if err := p.parseOr(); err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	return p.references, nil
+}
+
+// ExtractExpressions returns the expressions inside ${{ }} delimiters in a value
+func ExtractExpressions(value string) []string {
+	var expressions []string
+	for {
+		start := strings.Index(value, "${{")
+		if start < 0 {
+			return expressions
+		}
+		end := strings.Index(value[start:], "}}")
+		if end < 0 {
+			return expressions
+		}
+		expressions = append(expressions, value[start+3:start+end])
+		value = value[start+end+2:]
+	}
+}
+
+// expressionToken kinds
+const (
+	tokenIdentifier = iota
+	tokenLiteral
+	tokenOperator
+)
+
+type expressionToken struct {
+	kind int
+	text string
+}
+
+// tokenizeExpression splits an expression into identifiers, literals, and operators
+func tokenizeExpression(expression string) ([]expressionToken, error) {
+	var tokens []expressionToken
+	for i := 0; i < len(expression); {
+		char := expression[i]
+		switch {
+		case char == ' ' || char == '\t' || char == '\n' || char == '\r':
+			i++
+		case char == '\'':
+			// Strings are single-quoted, with '' escaping a quote
+			end := i + 1
+			for {
+				next := strings.IndexByte(expression[end:], '\'')
+				if next < 0 {
+					return nil, fmt.Errorf("unterminated string")
+				}
+				end += next + 1
+				if end < len(expression) && expression[end] == '\'' {
+					end++
+					continue
+				}
+				break
+			}
+			tokens = append(tokens, expressionToken{tokenLiteral, expression[i:end]})
+			i = end
+		case isIdentifierStart(char):
+			end := i + 1
+			for end < len(expression) && (isIdentifierStart(expression[end]) || isDigit(expression[end]) || expression[end] == '-') {
+				end++
+			}
+			word := expression[i:end]
+			kind := tokenIdentifier
+			if word == "true" || word == "false" || word == "null" || word == "NaN" || word == "Infinity" {
+				kind = tokenLiteral
+			}
+			tokens = append(tokens, expressionToken{kind, word})
+			i = end
+		case isDigit(char) || (char == '-' && i+1 < len(expression) && isDigit(expression[i+1])):
+			end := i + 1
+			for end < len(expression) && (isDigit(expression[end]) || strings.IndexByte("._xXabcdefABCDEF+-", expression[end]) >= 0) {
+				if (expression[end] == '+' || expression[end] == '-') && expression[end-1] != 'e' && expression[end-1] != 'E' {
+					break
+				}
+				end++
+			}
+			tokens = append(tokens, expressionToken{tokenLiteral, expression[i:end]})
+			i = end
+		default:
+			operator := ""
+			for _, candidate := range []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")", "[", "]", ".", ",", "*"} {
+				if strings.HasPrefix(expression[i:], candidate) {
+					operator = candidate
+					break
+				}
+			}
+			if operator == "" {
+				return nil, fmt.Errorf("unexpected character %q", char)
+			}
+			tokens = append(tokens, expressionToken{tokenOperator, operator})
+			i += len(operator)
+		}
+	}
+	return tokens, nil
+}
+
+func isIdentifierStart(char byte) bool {
+	return char == '_' || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
+
+func isDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
+
+// expressionParser is a recursive descent parser for workflow expressions
+type expressionParser struct {
+	tokens     []expressionToken
+	pos        int
+	references []ContextReference
+}
+
+// peek reports whether the next token is the given operator
+func (p *expressionParser) peek(operator string) bool {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokenOperator && p.tokens[p.pos].text == operator
+}
+
+// accept consumes the next token if it is one of the given operators
+func (p *expressionParser) accept(operators ...string) bool {
+	for _, operator := range operators {
+		if p.peek(operator) {
+			p.pos++
+			return true
+		}
+	}
+	return false
+}
+
+// expect consumes the given operator or fails
+func (p *expressionParser) expect(operator string) error {
+	if p.accept(operator) {
+		return nil
+	}
+	if p.pos >= len(p.tokens) {
+		return fmt.Errorf("expected %q at end of expression", operator)
+	}
+	return fmt.Errorf("expected %q, found %q", operator, p.tokens[p.pos].text)
+}
+
+// parseBinary parses operands joined by any of the operators
+func (p *expressionParser) parseBinary(operand func() error, operators ...string) error {
+	if err := operand(); err != nil {
+		return err
+	}
+	for p.accept(operators...) {
+		if err := operand(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *expressionParser) parseOr() error {
+	return p.parseBinary(p.parseAnd, "||")
+}
+
+func (p *expressionParser) parseAnd() error {
+	return p.parseBinary(p.parseEquality, "&&")
+}
+
+func (p *expressionParser) parseEquality() error {
+	return p.parseBinary(p.parseComparison, "==", "!=")
+}
+
+func (p *expressionParser) parseComparison() error {
+	return p.parseBinary(p.parseUnary, "<=", ">=", "<", ">")
+}
+
+func (p *expressionParser) parseUnary() error {
+	if p.accept("!") {
+		return p.parseUnary()
+	}
+	return p.parsePostfix()
+}
+
+// parsePostfix parses a primary value followed by property accesses and indexes
+func (p *expressionParser) parsePostfix() error {
+	reference, err := p.parsePrimary()
+	if err != nil {
+		return err
+	}
+
+	first := true
+	for {
+		switch {
+		case p.accept("."):
+			if p.accept("*") {
+				first = false
+				continue
+			}
+			if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != tokenIdentifier {
+				return fmt.Errorf("expected a property name after '.'")
+			}
+			if reference != nil && first {
+				reference.Property = p.tokens[p.pos].text
+			}
+			p.pos++
+		case p.accept("["):
+			if p.accept("*") {
+				if err := p.expect("]"); err != nil {
+					return err
+				}
+				first = false
+				continue
+			}
+			// A literal index names the property as a dotted access would
+			if reference != nil && first && p.pos+1 < len(p.tokens) && p.tokens[p.pos].kind == tokenLiteral &&
+				strings.HasPrefix(p.tokens[p.pos].text, "'") && p.tokens[p.pos+1].text == "]" {
+				reference.Property = strings.ReplaceAll(strings.Trim(p.tokens[p.pos].text, "'"), "''", "'")
+			}
+			if err := p.parseOr(); err != nil {
+				return err
+			}
+			if err := p.expect("]"); err != nil {
+				return err
+			}
+		default:
+			if reference != nil {
+				p.references = append(p.references, *reference)
+			}
+			return nil
+		}
+		first = false
+	}
+}
+
+// parsePrimary parses a literal, function call, context, or parenthesized expression,
+// returning the context reference it starts, if any
+func (p *expressionParser) parsePrimary() (*ContextReference, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	token := p.tokens[p.pos]
+	p.pos++
+
+	switch {
+	case token.kind == tokenLiteral:
+		return nil, nil
+	case token.kind == tokenOperator && token.text == "(":
+		if err := p.parseOr(); err != nil {
+			return nil, err
+		}
+		return nil, p.expect(")")
+	case token.kind == tokenIdentifier && p.accept("("):
+		if !expressionFunctions[strings.ToLower(token.text)] {
+			return nil, fmt.Errorf("unknown function %s()", token.text)
+		}
+		if p.accept(")") {
+			return nil, nil
+		}
+		if err := p.parseBinary(p.parseOr, ","); err != nil {
+			return nil, err
+		}
+		return nil, p.expect(")")
+	case token.kind == tokenIdentifier:
+		context := strings.ToLower(token.text)
+		if !expressionContexts[context] {
+			return nil, fmt.Errorf("unknown context %q", token.text)
+		}
+		return &ContextReference{Context: context}, nil
+	default:
+		return nil, fmt.Errorf("unexpected %q", token.text)
+	}
+}
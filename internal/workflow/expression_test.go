@@ -0,0 +1,61 @@
+package workflow
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseExpression(t *testing.T) {
+	tests := []struct {
+		expression string
+		references []ContextReference
+		err        string // Substring of the expected error; empty for none
+	}{
+		{"github.ref == 'refs/heads/main'", []ContextReference{{"github", "ref"}}, ""},
+		{"needs.build.outputs.version != '' && !cancelled()", []ContextReference{{"needs", "build"}}, ""},
+		{"steps['set-up'].outcome == 'success'", []ContextReference{{"steps", "set-up"}}, ""},
+		{"contains(fromJSON('[\"a\",\"b\"]'), matrix.os)", []ContextReference{{"matrix", "os"}}, ""},
+		{"format('{0}-{1}', runner.os, hashFiles('**/go.sum'))", []ContextReference{{"runner", "os"}}, ""},
+		{"github.event.pull_request.labels.*.name", []ContextReference{{"github", "event"}}, ""},
+		{"secrets[inputs.secret-name]", []ContextReference{{"inputs", "secret-name"}, {"secrets", ""}}, ""},
+		{"1.5 >= -2 || true", nil, ""},
+		{"'it''s'", nil, ""},
+		{"github.ref ==", nil, "unexpected end"},
+		{"(github.ref", nil, `expected ")"`},
+		{"'open", nil, "unterminated string"},
+		{"github.ref = 'x'", nil, "unexpected character"},
+		{"toUpper(github.ref)", nil, "unknown function toUpper()"},
+		{"gitub.ref", nil, `unknown context "gitub"`},
+		{"github.ref github.sha", nil, "unexpected"},
+	}
+
+	for _, tt := range tests {
+		references, err := ParseExpression(tt.expression)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%q: expected error containing %q, got %v", tt.expression, tt.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.expression, err)
+			continue
+		}
+		if !reflect.DeepEqual(references, tt.references) {
+			t.Errorf("%q: expected references %v, got %v", tt.expression, tt.references, references)
+		}
+	}
+}
+
+func TestExtractExpressions(t *testing.T) {
+	expressions := ExtractExpressions("echo ${{ github.ref }} and ${{matrix.os}} ${{ unclosed")
+	expected := []string{" github.ref ", "matrix.os"}
+	if !reflect.DeepEqual(expressions, expected) {
+		t.Errorf("Expected %q, got %q", expected, expressions)
+	}
+
+	if expressions := ExtractExpressions("plain text"); len(expressions) != 0 {
+		t.Errorf("Expected no expressions, got %q", expressions)
+	}
+}
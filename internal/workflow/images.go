@@ -0,0 +1,199 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Where a container image is used in a job
+const (
+	ImageKindContainer = "container" // The job's container:
+	ImageKindService   = "service"   // A service container under services:
+	ImageKindStep      = "step"      // A docker:// action step
+)
+
+// Runner architectures, as named by image platforms
+const (
+	ArchitectureAMD64 = "amd64"
+	ArchitectureARM64 = "arm64"
+)
+
+// DefaultImageRegistry is the registry of image names without a registry host
+const DefaultImageRegistry = "docker.io"
+
+// ContainerImage is a job container, service container, or docker:// step image
+type ContainerImage struct {
+	FilePath   string     `json:"file_path"`
+	Job        string     `json:"job"`
+	Kind       string     `json:"kind"`              // ImageKindContainer or ImageKindService
+	Service    string     `json:"service,omitempty"` // Service name for service containers
+	Step       string     `json:"step,omitempty"`    // Step name, or "#N" for unnamed steps, for docker:// steps
+	Image      string     `json:"image"`             // Reference as written, e.g. "postgres:16"
+	Registry   string     `json:"registry"`          // e.g. "docker.io", "ghcr.io"
+	Repository string     `json:"repository"`        // e.g. "library/postgres"
+	Tag        string     `json:"tag,omitempty"`     // "latest" when neither a tag nor a digest is given
+	Digest     string     `json:"digest,omitempty"`
+	Runner     string     `json:"runner,omitempty"`  // Architecture of the job's runner; empty when runs-on does not tell
+	Created    *time.Time `json:"created,omitempty"` // Image creation time, when checked against the registry
+	Missing    bool       `json:"missing,omitempty"` // The registry has no such tag or digest
+}
+
+// Name returns the image without its tag or digest, as written
+func (i ContainerImage) Name() string {
+	name, _, _ := strings.Cut(i.Image, "@")
+	if colon := strings.LastIndex(name, ":"); colon > strings.LastIndex(name, "/") {
+		name = name[:colon]
+	}
+	return name
+}
+
+// ParseContainerImages parses the container:, services:, and docker:// step images of a workflow's jobs
+// Images set by expressions, such as ${{ matrix.image }}, cannot be resolved and are skipped.
+func ParseContainerImages(content, filePath string, config *Config) ([]ContainerImage, error) {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	workflow, err := decodeWorkflow(content, config)
+	if err != nil {
+		return nil, err
+	}
+
+	jobNames := make([]string, 0, len(workflow.Jobs))
+	for jobName := range workflow.Jobs {
+		jobNames = append(jobNames, jobName)
+	}
+	sort.Strings(jobNames)
+
+	var images []ContainerImage
+	for _, jobName := range jobNames {
+		job := workflow.Jobs[jobName]
+		runner := RunnerArchitecture(job.RunsOn)
+		if image, ok := containerImage(job.Container, filePath, jobName); ok {
+			image.Kind = ImageKindContainer
+			image.Runner = runner
+			images = append(images, image)
+		}
+
+		services, _ := job.Services.(map[string]interface{})
+		serviceNames := make([]string, 0, len(services))
+		for name := range services {
+			serviceNames = append(serviceNames, name)
+		}
+		sort.Strings(serviceNames)
+		for _, name := range serviceNames {
+			if image, ok := containerImage(services[name], filePath, jobName); ok {
+				image.Kind = ImageKindService
+				image.Service = name
+				image.Runner = runner
+				images = append(images, image)
+			}
+		}
+
+		for i, step := range job.Steps {
+			if !strings.HasPrefix(step.Uses, "docker://") {
+				continue
+			}
+			if image, ok := containerImage(strings.TrimPrefix(step.Uses, "docker://"), filePath, jobName); ok {
+				image.Kind = ImageKindStep
+				image.Step = step.Name
+				if image.Step == "" {
+					image.Step = fmt.Sprintf("#%d", i+1)
+				}
+				image.Runner = runner
+				images = append(images, image)
+			}
+		}
+	}
+
+	return images, nil
+}
+
+// RunnerArchitecture returns the architecture of the runners a runs-on value selects, or "" when it
+// cannot tell: runs-on set by an expression, or self-hosted and larger runners labelled without an
+// architecture. GitHub-hosted Linux runners are amd64 unless their label ends in -arm.
+func RunnerArchitecture(runsOn interface{}) string {
+	var labels []string
+	switch value := runsOn.(type) {
+	case string:
+		labels = []string{value}
+	case []interface{}:
+		for _, label := range value {
+			if label, ok := label.(string); ok {
+				labels = append(labels, label)
+			}
+		}
+	case map[string]interface{}:
+		// Runner groups select runners by group and optional labels
+		return RunnerArchitecture(value["labels"])
+	}
+
+	architecture := ""
+	for _, label := range labels {
+		label = strings.ToLower(strings.TrimSpace(label))
+		switch {
+		case strings.Contains(label, "${{"):
+			return ""
+		case label == "arm64" || label == "aarch64" || strings.HasSuffix(label, "-arm") || strings.HasSuffix(label, "-arm64"):
+			return ArchitectureARM64
+		case label == "x64" || label == "amd64" || label == "x86_64":
+			architecture = ArchitectureAMD64
+		case label == "ubuntu-latest" || strings.HasPrefix(label, "ubuntu-") && strings.Count(label, "-") == 1:
+			architecture = ArchitectureAMD64
+		}
+	}
+	return architecture
+}
+
+// containerImage reads the image of a container: or service value, which is a name or a mapping with image
+func containerImage(value interface{}, filePath, job string) (ContainerImage, bool) {
+	var reference string
+	switch container := value.(type) {
+	case string:
+		reference = container
+	case map[string]interface{}:
+		reference, _ = container["image"].(string)
+	}
+	reference = strings.TrimSpace(reference)
+	if reference == "" || strings.Contains(reference, "${{") {
+		return ContainerImage{}, false
+	}
+
+	registry, repository, tag, digest := ParseImageReference(reference)
+	return ContainerImage{
+		FilePath:   filePath,
+		Job:        job,
+		Image:      reference,
+		Registry:   registry,
+		Repository: repository,
+		Tag:        tag,
+		Digest:     digest,
+	}, true
+}
+
+// ParseImageReference splits an image reference into registry, repository, tag, and digest
+// Following Docker's rules, the first path component is a registry host only when it contains a "."
+// or ":" or is "localhost"; otherwise the image is on Docker Hub, where single names live under library/.
+func ParseImageReference(reference string) (registry, repository, tag, digest string) {
+	name, digest, _ := strings.Cut(strings.TrimPrefix(reference, "docker://"), "@")
+	if colon := strings.LastIndex(name, ":"); colon > strings.LastIndex(name, "/") {
+		name, tag = name[:colon], name[colon+1:]
+	}
+	if tag == "" && digest == "" {
+		tag = "latest"
+	}
+
+	registry = DefaultImageRegistry
+	if host, rest, found := strings.Cut(name, "/"); found && (strings.ContainsAny(host, ".:") || host == "localhost") {
+		registry, name = host, rest
+	}
+	if registry == DefaultImageRegistry || registry == "index.docker.io" {
+		registry = DefaultImageRegistry
+		if !strings.Contains(name, "/") {
+			name = "library/" + name
+		}
+	}
+	return registry, name, tag, digest
+}
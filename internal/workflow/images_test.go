@@ -0,0 +1,120 @@
+package workflow
+
+import "testing"
+
+func TestParseContainerImages(t *testing.T) {
+	content := `
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    container: node:20-bookworm
+    services:
+      redis:
+        image: redis
+      postgres:
+        image: postgres:16@sha256:abc123
+        env:
+          POSTGRES_PASSWORD: postgres
+  build:
+    runs-on: ubuntu-latest
+    container:
+      image: ghcr.io/my-org/builder:v2
+      credentials:
+        username: ${{ github.actor }}
+  matrix:
+    runs-on: ubuntu-latest
+    container: ${{ matrix.image }}
+  plain:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hi
+  lint:
+    runs-on: ubuntu-24.04-arm
+    steps:
+      - uses: actions/checkout@v4
+      - uses: docker://hadolint/hadolint:v2.12.0
+      - name: Shellcheck
+        uses: docker://koalaman/shellcheck:stable
+`
+	images, err := ParseContainerImages(content, ".github/workflows/ci.yml", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(images) != 6 {
+		t.Fatalf("Expected 6 images, got %d: %+v", len(images), images)
+	}
+
+	expected := []ContainerImage{
+		{Job: "build", Kind: ImageKindContainer, Image: "ghcr.io/my-org/builder:v2", Registry: "ghcr.io", Repository: "my-org/builder", Tag: "v2", Runner: "amd64"},
+		{Job: "lint", Kind: ImageKindStep, Step: "#2", Image: "hadolint/hadolint:v2.12.0", Registry: "docker.io", Repository: "hadolint/hadolint", Tag: "v2.12.0", Runner: "arm64"},
+		{Job: "lint", Kind: ImageKindStep, Step: "Shellcheck", Image: "koalaman/shellcheck:stable", Registry: "docker.io", Repository: "koalaman/shellcheck", Tag: "stable", Runner: "arm64"},
+		{Job: "test", Kind: ImageKindContainer, Image: "node:20-bookworm", Registry: "docker.io", Repository: "library/node", Tag: "20-bookworm", Runner: "amd64"},
+		{Job: "test", Kind: ImageKindService, Service: "postgres", Image: "postgres:16@sha256:abc123", Registry: "docker.io", Repository: "library/postgres", Tag: "16", Digest: "sha256:abc123", Runner: "amd64"},
+		{Job: "test", Kind: ImageKindService, Service: "redis", Image: "redis", Registry: "docker.io", Repository: "library/redis", Tag: "latest", Runner: "amd64"},
+	}
+	for i, want := range expected {
+		want.FilePath = ".github/workflows/ci.yml"
+		if images[i] != want {
+			t.Errorf("Image %d: expected %+v, got %+v", i, want, images[i])
+		}
+	}
+}
+
+func TestRunnerArchitecture(t *testing.T) {
+	tests := []struct {
+		runsOn   interface{}
+		expected string
+	}{
+		{"ubuntu-latest", "amd64"},
+		{"ubuntu-22.04", "amd64"},
+		{"ubuntu-24.04-arm", "arm64"},
+		{[]interface{}{"self-hosted", "linux", "ARM64"}, "arm64"},
+		{[]interface{}{"self-hosted", "linux", "X64"}, "amd64"},
+		{[]interface{}{"self-hosted", "linux"}, ""},
+		{map[string]interface{}{"group": "large", "labels": []interface{}{"linux-arm64"}}, "arm64"},
+		{map[string]interface{}{"group": "large"}, ""},
+		{"${{ matrix.os }}", ""},
+		{"windows-latest", ""},
+	}
+	for _, test := range tests {
+		if got := RunnerArchitecture(test.runsOn); got != test.expected {
+			t.Errorf("RunnerArchitecture(%v): expected %q, got %q", test.runsOn, test.expected, got)
+		}
+	}
+}
+
+func TestParseImageReference(t *testing.T) {
+	tests := []struct {
+		reference, registry, repository, tag, digest string
+	}{
+		{"ubuntu", "docker.io", "library/ubuntu", "latest", ""},
+		{"bitnami/redis:7.2", "docker.io", "bitnami/redis", "7.2", ""},
+		{"docker.io/library/alpine:3.20", "docker.io", "library/alpine", "3.20", ""},
+		{"localhost:5000/app", "localhost:5000", "app", "latest", ""},
+		{"registry.example.com:8443/team/app:1.0", "registry.example.com:8443", "team/app", "1.0", ""},
+		{"mcr.microsoft.com/mssql/server@sha256:def", "mcr.microsoft.com", "mssql/server", "", "sha256:def"},
+	}
+
+	for _, tt := range tests {
+		registry, repository, tag, digest := ParseImageReference(tt.reference)
+		if registry != tt.registry || repository != tt.repository || tag != tt.tag || digest != tt.digest {
+			t.Errorf("ParseImageReference(%q) = %q, %q, %q, %q; expected %q, %q, %q, %q", tt.reference,
+				registry, repository, tag, digest, tt.registry, tt.repository, tt.tag, tt.digest)
+		}
+	}
+}
+
+func TestContainerImageName(t *testing.T) {
+	tests := map[string]string{
+		"postgres:16":                   "postgres",
+		"localhost:5000/app":            "localhost:5000/app",
+		"localhost:5000/app:1@sha256:a": "localhost:5000/app",
+		"ghcr.io/my-org/builder":        "ghcr.io/my-org/builder",
+	}
+	for image, expected := range tests {
+		if got := (ContainerImage{Image: image}).Name(); got != expected {
+			t.Errorf("Name() of %q: expected %q, got %q", image, expected, got)
+		}
+	}
+}
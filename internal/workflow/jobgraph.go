@@ -0,0 +1,212 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kinds of job graph problems
+const (
+	JobGraphUndefinedNeed   = "undefined-need"   // A job needs a job the workflow does not define; GitHub rejects the workflow
+	JobGraphCycle           = "cycle"            // Jobs need each other; GitHub rejects the workflow
+	JobGraphUnreachable     = "unreachable"      // A job needs a job that never runs, so it is always skipped
+	JobGraphUndefinedOutput = "undefined-output" // A workflow_call output reads a job the workflow does not define
+)
+
+// JobGraphProblem is a problem of a workflow's job dependency graph
+type JobGraphProblem struct {
+	Kind    string
+	Jobs    []string // Jobs at fault, in cycle order for cycles; the output name for undefined outputs
+	Line    int
+	Message string
+}
+
+// ParseJobGraph parses the needs: relationships of a workflow's jobs and returns the problems of the
+// graph: needs naming undefined jobs, cycles, jobs that can never run because a job they need is
+// disabled with if: false, and workflow_call outputs reading jobs that do not exist, which callers
+// receive as empty values after a job is renamed.
+func ParseJobGraph(content, filePath string, config *Config) ([]JobGraphProblem, error) {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	_, root, err := decodeWorkflowNode(content, config)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil || len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	document := root.Content[0]
+	jobs := mappingValue(document, "jobs")
+	if jobs == nil || jobs.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	return jobGraphProblems(document, jobs), nil
+}
+
+// jobGraphProblems checks the needs graph of a workflow's jobs mapping and its workflow_call outputs
+func jobGraphProblems(document, jobs *yaml.Node) []JobGraphProblem {
+	var problems []JobGraphProblem
+	jobIDs := make(map[string]bool)
+	for i := 0; i+1 < len(jobs.Content); i += 2 {
+		jobIDs[jobs.Content[i].Value] = true
+	}
+
+	// Only needs naming defined jobs form the graph, so an undefined job is reported once
+	needs := make(map[string][]string)
+	for i := 0; i+1 < len(jobs.Content); i += 2 {
+		name, job := jobs.Content[i].Value, jobs.Content[i+1]
+		node := mappingValue(job, "needs")
+		if node == nil {
+			continue
+		}
+		for _, need := range scalarList(node) {
+			if !jobIDs[need.Value] {
+				problems = append(problems, JobGraphProblem{
+					Kind:    JobGraphUndefinedNeed,
+					Jobs:    []string{name},
+					Line:    need.Line,
+					Message: fmt.Sprintf("job %q needs undefined job %q", name, need.Value),
+				})
+				continue
+			}
+			needs[name] = append(needs[name], need.Value)
+		}
+	}
+
+	for _, cycle := range findCycles(needs) {
+		problems = append(problems, JobGraphProblem{
+			Kind:    JobGraphCycle,
+			Jobs:    cycle[:len(cycle)-1],
+			Line:    jobs.Line,
+			Message: fmt.Sprintf("jobs %s need each other in a cycle", strings.Join(cycle, " -> ")),
+		})
+	}
+
+	problems = append(problems, unreachableJobs(jobs, needs)...)
+	return append(problems, undefinedOutputJobs(document, jobIDs)...)
+}
+
+// unreachableJobs reports jobs skipped on every run because a job they need never runs: it is disabled
+// with if: false, or is itself unreachable. Jobs whose if: calls always(), failure(), or cancelled()
+// run after skipped jobs, so they are reachable.
+func unreachableJobs(jobs *yaml.Node, needs map[string][]string) []JobGraphProblem {
+	conditions := make(map[string]*yaml.Node)
+	lines := make(map[string]int)
+	for i := 0; i+1 < len(jobs.Content); i += 2 {
+		name, job := jobs.Content[i].Value, jobs.Content[i+1]
+		conditions[name] = mappingValue(job, "if")
+		lines[name] = jobs.Content[i].Line
+	}
+
+	// blocker is the needed job that keeps a job from running, or "" when the job can run
+	blockers := make(map[string]string)
+	visiting := make(map[string]bool)
+	var neverRuns func(string) bool
+	neverRuns = func(name string) bool {
+		if disabledCondition(conditions[name]) {
+			return true
+		}
+		if blocker, ok := blockers[name]; ok {
+			return blocker != ""
+		}
+		if visiting[name] || runsAfterSkipped(conditions[name]) {
+			return false // Cycles are reported on their own
+		}
+		visiting[name] = true
+		blockers[name] = ""
+		for _, need := range needs[name] {
+			if neverRuns(need) {
+				blockers[name] = need
+				break
+			}
+		}
+		visiting[name] = false
+		return blockers[name] != ""
+	}
+
+	var problems []JobGraphProblem
+	for i := 0; i+1 < len(jobs.Content); i += 2 {
+		name := jobs.Content[i].Value
+		if disabledCondition(conditions[name]) || !neverRuns(name) {
+			continue
+		}
+		blocker := blockers[name]
+		reason := "never runs"
+		if disabledCondition(conditions[blocker]) {
+			reason = "is disabled with if: false"
+		}
+		problems = append(problems, JobGraphProblem{
+			Kind:    JobGraphUnreachable,
+			Jobs:    []string{name},
+			Line:    lines[name],
+			Message: fmt.Sprintf("job %q never runs: it needs job %q, which %s", name, blocker, reason),
+		})
+	}
+	return problems
+}
+
+// disabledCondition reports whether an if: condition is always false
+func disabledCondition(condition *yaml.Node) bool {
+	if condition == nil || condition.Kind != yaml.ScalarNode {
+		return false
+	}
+	value := strings.TrimSpace(condition.Value)
+	if expressions := ExtractExpressions(value); len(expressions) == 1 && strings.HasPrefix(value, "${{") && strings.HasSuffix(value, "}}") {
+		value = strings.TrimSpace(expressions[0])
+	}
+	return value == "false"
+}
+
+// runsAfterSkipped reports whether an if: condition calls a status function that lets the job run
+// when a job it needs was skipped
+func runsAfterSkipped(condition *yaml.Node) bool {
+	if condition == nil || condition.Kind != yaml.ScalarNode {
+		return false
+	}
+	value := strings.ToLower(strings.ReplaceAll(condition.Value, " ", ""))
+	return strings.Contains(value, "always()") || strings.Contains(value, "failure()") || strings.Contains(value, "cancelled()")
+}
+
+// undefinedOutputJobs reports workflow_call outputs whose value reads jobs.<id> for a job that does not exist
+func undefinedOutputJobs(document *yaml.Node, jobIDs map[string]bool) []JobGraphProblem {
+	var outputs *yaml.Node
+	if on := mappingValue(document, "on"); on != nil {
+		if call := mappingValue(on, "workflow_call"); call != nil {
+			outputs = mappingValue(call, "outputs")
+		}
+	}
+	if outputs == nil || outputs.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var problems []JobGraphProblem
+	for i := 0; i+1 < len(outputs.Content); i += 2 {
+		name := outputs.Content[i].Value
+		value := mappingValue(outputs.Content[i+1], "value")
+		if value == nil || value.Kind != yaml.ScalarNode {
+			continue
+		}
+		for _, expression := range ExtractExpressions(value.Value) {
+			references, err := ParseExpression(expression)
+			if err != nil {
+				continue // Reported by the expression checks
+			}
+			for _, reference := range references {
+				if reference.Context == "jobs" && reference.Property != "" && !jobIDs[reference.Property] {
+					problems = append(problems, JobGraphProblem{
+						Kind:    JobGraphUndefinedOutput,
+						Jobs:    []string{name},
+						Line:    value.Line,
+						Message: fmt.Sprintf("workflow_call output %q reads jobs.%s, which is not a job of the workflow", name, reference.Property),
+					})
+				}
+			}
+		}
+	}
+	return problems
+}
@@ -0,0 +1,77 @@
+package workflow
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseJobGraph(t *testing.T) {
+	content := `on:
+  workflow_call:
+    outputs:
+      artifact:
+        value: ${{ jobs.package.outputs.artifact }}
+      version:
+        value: ${{ jobs.build.outputs.version }}
+jobs:
+  build:
+    runs-on: ubuntu-latest
+  lint:
+    if: false
+    runs-on: ubuntu-latest
+  test:
+    needs: [build, lint]
+    runs-on: ubuntu-latest
+  deploy:
+    needs: test
+    runs-on: ubuntu-latest
+  notify:
+    needs: deploy
+    if: ${{ always() }}
+    runs-on: ubuntu-latest
+  release:
+    needs: [publish]
+    runs-on: ubuntu-latest
+`
+
+	problems, err := ParseJobGraph(content, ".github/workflows/release.yml", nil)
+	if err != nil {
+		t.Fatalf("ParseJobGraph failed: %v", err)
+	}
+
+	type problem struct {
+		Kind string
+		Jobs []string
+	}
+	var got []problem
+	for _, p := range problems {
+		got = append(got, problem{p.Kind, p.Jobs})
+	}
+	expected := []problem{
+		{JobGraphUndefinedNeed, []string{"release"}},
+		{JobGraphUnreachable, []string{"test"}},
+		{JobGraphUnreachable, []string{"deploy"}},
+		{JobGraphUndefinedOutput, []string{"artifact"}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+	if problems[2].Message != `job "deploy" never runs: it needs job "test", which never runs` {
+		t.Errorf("Unexpected message for a job behind an unreachable job: %s", problems[2].Message)
+	}
+	if problems[3].Line != 5 {
+		t.Errorf("Expected the output problem on line 5, got %d", problems[3].Line)
+	}
+}
+
+func TestParseJobGraph_Cycle(t *testing.T) {
+	content := "on: push\njobs:\n  a:\n    needs: c\n    runs-on: ubuntu-latest\n  b:\n    needs: a\n    runs-on: ubuntu-latest\n  c:\n    needs: b\n    runs-on: ubuntu-latest\n"
+
+	problems, err := ParseJobGraph(content, "ci.yml", nil)
+	if err != nil {
+		t.Fatalf("ParseJobGraph failed: %v", err)
+	}
+	if len(problems) != 1 || problems[0].Kind != JobGraphCycle || !reflect.DeepEqual(problems[0].Jobs, []string{"a", "c", "b"}) {
+		t.Errorf("Expected one cycle of a, c, and b, got %+v", problems)
+	}
+}
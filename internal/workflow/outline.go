@@ -0,0 +1,73 @@
+package workflow
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WorkflowOutline is the job layout of a workflow file, in file order
+type WorkflowOutline struct {
+	FilePath string       `json:"file_path"`
+	Jobs     []JobOutline `json:"jobs"`
+}
+
+// JobOutline is a job and the actions it calls, in file order
+type JobOutline struct {
+	Name        string   `json:"name"`
+	DisplayName string   `json:"display_name,omitempty"` // name: of the job, shown in checks instead of its id
+	Uses        string   `json:"uses,omitempty"`         // Reusable workflow called by the job
+	Steps       []string `json:"steps,omitempty"`        // uses: of each step, "" for run steps
+}
+
+// CheckName returns the name the job's check runs are reported under: its display name, or its id
+func (j JobOutline) CheckName() string {
+	if j.DisplayName != "" {
+		return j.DisplayName
+	}
+	return j.Name
+}
+
+// ParseWorkflowOutline parses the jobs of a workflow in the order they are written
+// Map decoding loses job order, so jobs are read from the YAML node tree.
+func ParseWorkflowOutline(content, filePath string, config *Config) (*WorkflowOutline, error) {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	workflow, root, err := decodeWorkflowNode(content, config)
+	if err != nil {
+		return nil, err
+	}
+
+	outline := &WorkflowOutline{FilePath: filePath}
+	if root == nil || len(root.Content) == 0 {
+		return outline, nil
+	}
+	jobs := mappingValue(root.Content[0], "jobs")
+	if jobs == nil || jobs.Kind != yaml.MappingNode {
+		return outline, nil
+	}
+
+	for i := 0; i+1 < len(jobs.Content); i += 2 {
+		name := jobs.Content[i].Value
+		job := workflow.Jobs[name]
+		jobOutline := JobOutline{Name: name, DisplayName: job.Name, Uses: job.Uses}
+		for _, step := range job.Steps {
+			jobOutline.Steps = append(jobOutline.Steps, step.Uses)
+		}
+		outline.Jobs = append(outline.Jobs, jobOutline)
+	}
+
+	return outline, nil
+}
+
+// UsesAction reports whether a uses: value calls an action or reusable workflow, at any version
+// Names are compared case-insensitively, as GitHub does; an empty path matches any path in the repository.
+func UsesAction(uses, repository, path string) bool {
+	ref := parseActionRef(uses, strings.Contains(uses, ".github/workflows/"))
+	if ref == nil || !strings.EqualFold(ref.Repository, repository) {
+		return false
+	}
+	return path == "" || strings.EqualFold(ref.WorkflowPath, path)
+}
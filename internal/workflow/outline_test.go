@@ -0,0 +1,79 @@
+package workflow
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseWorkflowOutline_KeepsJobOrder(t *testing.T) {
+	content := `
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - run: go test ./...
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/setup-go@v5
+  scan:
+    name: Security scan
+    uses: my-org/security/.github/workflows/scan.yml@v2
+`
+	outline, err := ParseWorkflowOutline(content, "ci.yml", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []JobOutline{
+		{Name: "test", Steps: []string{"actions/checkout@v4", ""}},
+		{Name: "build", Steps: []string{"actions/setup-go@v5"}},
+		{Name: "scan", DisplayName: "Security scan", Uses: "my-org/security/.github/workflows/scan.yml@v2"},
+	}
+	if outline.FilePath != "ci.yml" || !reflect.DeepEqual(outline.Jobs, expected) {
+		t.Errorf("Expected jobs %+v in ci.yml, got %+v in %s", expected, outline.Jobs, outline.FilePath)
+	}
+
+	if name := outline.Jobs[0].CheckName(); name != "test" {
+		t.Errorf("Expected an unnamed job to report checks under its id, got %q", name)
+	}
+	if name := outline.Jobs[2].CheckName(); name != "Security scan" {
+		t.Errorf("Expected a named job to report checks under its name, got %q", name)
+	}
+}
+
+func TestParseWorkflowOutline_Empty(t *testing.T) {
+	outline, err := ParseWorkflowOutline("", "empty.yml", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(outline.Jobs) != 0 {
+		t.Errorf("Expected no jobs, got %+v", outline.Jobs)
+	}
+}
+
+func TestUsesAction(t *testing.T) {
+	tests := []struct {
+		uses       string
+		repository string
+		path       string
+		expected   bool
+	}{
+		{"actions/checkout@v4", "actions/checkout", "", true},
+		{"Actions/Checkout@v3", "actions/checkout", "", true},
+		{"github/codeql-action/init@v3", "github/codeql-action", "", true},
+		{"github/codeql-action/init@v3", "github/codeql-action", "analyze", false},
+		{"my-org/security/.github/workflows/scan.yml@v2", "my-org/security", ".github/workflows/scan.yml", true},
+		{"actions/checkout-extra@v1", "actions/checkout", "", false},
+		{"./.github/actions/local", "actions/checkout", "", false},
+		{"", "actions/checkout", "", false},
+	}
+
+	for _, tt := range tests {
+		if got := UsesAction(tt.uses, tt.repository, tt.path); got != tt.expected {
+			t.Errorf("UsesAction(%q, %q, %q): expected %v, got %v", tt.uses, tt.repository, tt.path, tt.expected, got)
+		}
+	}
+}
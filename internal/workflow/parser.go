@@ -1,6 +1,7 @@
 package workflow
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"regexp"
@@ -9,23 +10,51 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// Default limits protecting the parser from oversized or maliciously crafted workflows
+const (
+	DefaultMaxFileSize  = 1024 * 1024 // 1 MiB
+	DefaultMaxNodeCount = 100000      // YAML nodes after alias expansion
+)
+
+// ErrWorkflowTooLarge is returned when a workflow file exceeds the configured size limit
+var ErrWorkflowTooLarge = errors.New("workflow file exceeds size limit")
+
+// ErrWorkflowTooComplex is returned when YAML anchors and aliases expand beyond the node limit
+var ErrWorkflowTooComplex = errors.New("workflow YAML expands beyond node limit")
+
 // Config holds configuration options for the workflow parser
+// Zero limits use the defaults; negative limits disable the check.
 type Config struct {
-	Verbose bool
+	Verbose      bool
+	MaxFileSize  int // Maximum workflow size in bytes
+	MaxNodeCount int // Maximum YAML node count after alias expansion
 }
 
 // Workflow represents a parsed GitHub Actions workflow
 type Workflow struct {
-	Name string         `yaml:"name"`
-	On   interface{}    `yaml:"on"`
-	Jobs map[string]Job `yaml:"jobs"`
+	Name        string         `yaml:"name"`
+	On          interface{}    `yaml:"on"`
+	Env         interface{}    `yaml:"env,omitempty"`
+	Concurrency interface{}    `yaml:"concurrency,omitempty"` // Group name, or a mapping with group and cancel-in-progress
+	Jobs        map[string]Job `yaml:"jobs"`
 }
 
 // Job represents a job in a workflow
 type Job struct {
-	RunsOn interface{} `yaml:"runs-on"`
-	Uses   string      `yaml:"uses,omitempty"`
-	Steps  []Step      `yaml:"steps,omitempty"`
+	Name            string      `yaml:"name,omitempty"` // Display name reported in checks; defaults to the job id
+	RunsOn          interface{} `yaml:"runs-on"`
+	Uses            string      `yaml:"uses,omitempty"`
+	Steps           []Step      `yaml:"steps,omitempty"`
+	TimeoutMinutes  interface{} `yaml:"timeout-minutes,omitempty"`
+	ContinueOnError interface{} `yaml:"continue-on-error,omitempty"`
+	Strategy        interface{} `yaml:"strategy,omitempty"`
+	Env             interface{} `yaml:"env,omitempty"`
+	With            interface{} `yaml:"with,omitempty"`        // Inputs of a reusable workflow call
+	Secrets         interface{} `yaml:"secrets,omitempty"`     // Secrets of a reusable workflow call, or "inherit"
+	Container       interface{} `yaml:"container,omitempty"`   // Image name, or a mapping with image
+	Services        interface{} `yaml:"services,omitempty"`    // Service containers by name
+	Environment     interface{} `yaml:"environment,omitempty"` // Environment name, or a mapping with name and url
+	Concurrency     interface{} `yaml:"concurrency,omitempty"` // Group name, or a mapping with group and cancel-in-progress
 }
 
 // Step represents a step in a job
@@ -33,6 +62,7 @@ type Step struct {
 	Name string      `yaml:"name,omitempty"`
 	Uses string      `yaml:"uses,omitempty"`
 	With interface{} `yaml:"with,omitempty"`
+	Env  interface{} `yaml:"env,omitempty"`
 	Run  string      `yaml:"run,omitempty"`
 }
 
@@ -45,8 +75,18 @@ type ActionReference struct {
 	Context      string // where this action was found (job name, step name)
 	FilePath     string // path to the workflow file
 	RepoFullName string // full name of the repo containing this workflow
+	PinComment   string // trailing comment on the uses line (e.g., "v4.1.1" from "@<sha> # v4.1.1")
+	Local        bool   `json:",omitempty"` // reusable workflow of the same repository, called as "./.github/workflows/ci.yml"; it has no version
+
+	With interface{} `json:"-"` // with: block of the step or job, for patch previews; not written to results
 }
 
+// pinCommentPattern matches a uses line with a trailing comment
+var pinCommentPattern = regexp.MustCompile(`^\s*(?:-\s+)?uses:\s*["']?([^\s"'#]+)["']?\s+#\s*(.*?)\s*$`)
+
+// pinCommentVersionPattern matches a version tag inside a pin comment (e.g., "v4.1.1", "tag=v4", "pin@v2.0")
+var pinCommentVersionPattern = regexp.MustCompile(`(?:v\d+(?:\.\d+)*|\d+\.\d+(?:\.\d+)*)(?:-[0-9A-Za-z.]+)?`)
+
 // ParseWorkflow parses a YAML workflow file and extracts action references
 func ParseWorkflow(content, filePath, repoFullName string) ([]ActionReference, error) {
 	return ParseWorkflowWithResolver(content, filePath, repoFullName, nil)
@@ -74,12 +114,12 @@ func ParseWorkflowWithResolverAndConfig(content, filePath, repoFullName string,
 		log.Printf("Workflow parsing: Starting to parse %s in repository %s", filePath, repoFullName)
 	}
 
-	var workflow Workflow
-	if err := yaml.Unmarshal([]byte(content), &workflow); err != nil {
+	workflow, err := decodeWorkflow(content, config)
+	if err != nil {
 		if config.Verbose {
 			log.Printf("Workflow parsing: Failed to parse YAML in %s - %v", filePath, err)
 		}
-		return nil, fmt.Errorf("failed to parse workflow YAML: %w", err)
+		return nil, err
 	}
 
 	if config.Verbose {
@@ -88,6 +128,9 @@ func ParseWorkflowWithResolverAndConfig(content, filePath, repoFullName string,
 
 	var references []ActionReference
 
+	// Comments are dropped by the YAML decoder, so read them from the raw lines
+	pinComments := extractPinComments(content)
+
 	// Process each job
 	for jobName, job := range workflow.Jobs {
 		if config.Verbose {
@@ -101,9 +144,14 @@ func ParseWorkflowWithResolverAndConfig(content, filePath, repoFullName string,
 			}
 			ref := parseActionRef(job.Uses, true)
 			if ref != nil {
+				if ref.Local {
+					ref.Repository = repoFullName
+				}
 				ref.Context = fmt.Sprintf("job:%s", jobName)
 				ref.FilePath = filePath
 				ref.RepoFullName = repoFullName
+				ref.PinComment = pinComments[job.Uses]
+				ref.With = job.With
 				references = append(references, *ref)
 				if config.Verbose {
 					log.Printf("Workflow parsing: Extracted reusable workflow reference - repository: %s, version: %s", ref.Repository, ref.Version)
@@ -126,6 +174,8 @@ func ParseWorkflowWithResolverAndConfig(content, filePath, repoFullName string,
 					ref.Context = fmt.Sprintf("job:%s/step:%s", jobName, stepName)
 					ref.FilePath = filePath
 					ref.RepoFullName = repoFullName
+					ref.PinComment = pinComments[step.Uses]
+					ref.With = step.With
 					references = append(references, *ref)
 					if config.Verbose {
 						log.Printf("Workflow parsing: Extracted action reference - repository: %s, version: %s, context: %s", ref.Repository, ref.Version, ref.Context)
@@ -142,11 +192,130 @@ func ParseWorkflowWithResolverAndConfig(content, filePath, repoFullName string,
 	return references, nil
 }
 
+// decodeWorkflow decodes workflow YAML after enforcing size and alias expansion limits
+// The document is first decoded into a node tree, which does not expand aliases, so
+// alias bombs are rejected before any expansion takes place.
+func decodeWorkflow(content string, config *Config) (Workflow, error) {
+	workflow, _, err := decodeWorkflowNode(content, config)
+	return workflow, err
+}
+
+// decodeWorkflowNode decodes a workflow as decodeWorkflow does, also returning the YAML document node,
+// which keeps the order of mapping keys such as job names; the node is nil for empty documents
+func decodeWorkflowNode(content string, config *Config) (Workflow, *yaml.Node, error) {
+	var workflow Workflow
+
+	maxFileSize := limitOrDefault(config.MaxFileSize, DefaultMaxFileSize)
+	if maxFileSize > 0 && len(content) > maxFileSize {
+		return workflow, nil, fmt.Errorf("%w: %d bytes (limit %d)", ErrWorkflowTooLarge, len(content), maxFileSize)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &root); err != nil {
+		return workflow, nil, fmt.Errorf("failed to parse workflow YAML: %w", err)
+	}
+	if root.Kind == 0 {
+		return workflow, nil, nil // Empty document
+	}
+
+	maxNodeCount := limitOrDefault(config.MaxNodeCount, DefaultMaxNodeCount)
+	if maxNodeCount > 0 {
+		counter := &nodeCounter{limit: maxNodeCount, expanded: make(map[*yaml.Node]int), visiting: make(map[*yaml.Node]bool)}
+		if count := counter.count(&root); count > maxNodeCount {
+			return workflow, nil, fmt.Errorf("%w: more than %d nodes after alias expansion", ErrWorkflowTooComplex, maxNodeCount)
+		}
+	}
+
+	if err := root.Decode(&workflow); err != nil {
+		return workflow, nil, fmt.Errorf("failed to parse workflow YAML: %w", err)
+	}
+
+	return workflow, &root, nil
+}
+
+// limitOrDefault resolves a configured limit: zero uses the default, negative disables the limit
+func limitOrDefault(limit, defaultLimit int) int {
+	if limit == 0 {
+		return defaultLimit
+	}
+	if limit < 0 {
+		return 0
+	}
+	return limit
+}
+
+// nodeCounter counts YAML nodes as they would appear with aliases expanded
+type nodeCounter struct {
+	limit    int
+	expanded map[*yaml.Node]int  // memoized expanded size per node
+	visiting map[*yaml.Node]bool // guards against self-referencing aliases
+}
+
+// count returns the expanded node count for n, stopping early once the limit is exceeded
+func (c *nodeCounter) count(n *yaml.Node) int {
+	if n == nil {
+		return 0
+	}
+	if size, ok := c.expanded[n]; ok {
+		return size
+	}
+	if c.visiting[n] {
+		return c.limit + 1 // Recursive alias expands without bound
+	}
+	c.visiting[n] = true
+	defer delete(c.visiting, n)
+
+	size := 1
+	if n.Kind == yaml.AliasNode {
+		size += c.count(n.Alias)
+	}
+	for _, child := range n.Content {
+		size += c.count(child)
+		if size > c.limit {
+			break
+		}
+	}
+
+	c.expanded[n] = size
+	return size
+}
+
+// extractPinComments maps each uses value to the trailing comment on its line
+// When the same reference appears more than once, the first comment found wins.
+func extractPinComments(content string) map[string]string {
+	comments := make(map[string]string)
+	for _, line := range strings.Split(content, "\n") {
+		matches := pinCommentPattern.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if len(matches) != 3 || matches[2] == "" {
+			continue
+		}
+		if _, exists := comments[matches[1]]; !exists {
+			comments[matches[1]] = matches[2]
+		}
+	}
+	return comments
+}
+
+// PinCommentVersion extracts the version tag from a pin comment, or "" if it names no version
+func PinCommentVersion(comment string) string {
+	return pinCommentVersionPattern.FindString(comment)
+}
+
 // parseActionRef parses an action reference string (e.g., "actions/checkout@v4")
+// Local reusable workflows ("./.github/workflows/ci.yml") are parsed without a repository, which is the
+// calling repository; local actions are skipped.
 func parseActionRef(uses string, isReusable bool) *ActionReference {
-	// Handle local actions (starting with "./")
+	// Handle local actions and reusable workflows (starting with "./")
 	if strings.HasPrefix(uses, "./") {
-		return nil // Skip local actions
+		path := strings.TrimPrefix(uses, "./")
+		if !isReusable || path == "" || strings.Contains(path, "@") {
+			return nil // Skip local actions
+		}
+		return &ActionReference{
+			WorkflowPath: path,
+			IsReusable:   true,
+			Local:        true,
+		}
 	}
 
 	// Handle Docker actions (starting with "docker://")
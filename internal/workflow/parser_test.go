@@ -0,0 +1,339 @@
+package workflow
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestExtractPinComments(t *testing.T) {
+	content := `jobs:
+  build:
+    uses: my-org/workflows/.github/workflows/build.yml@0123456789abcdef0123456789abcdef01234567 # v1.2.0
+  test:
+    steps:
+      - uses: actions/checkout@b4ffde65f46336ab88eb53be808477a3936bae11 # v4.1.1
+      - name: Setup
+        uses: "actions/setup-node@v4"   #   tag=v4.0.2
+      - uses: actions/cache@v4
+`
+
+	comments := extractPinComments(content)
+
+	expected := map[string]string{
+		"my-org/workflows/.github/workflows/build.yml@0123456789abcdef0123456789abcdef01234567": "v1.2.0",
+		"actions/checkout@b4ffde65f46336ab88eb53be808477a3936bae11":                             "v4.1.1",
+		"actions/setup-node@v4": "tag=v4.0.2",
+	}
+	for uses, comment := range expected {
+		if comments[uses] != comment {
+			t.Errorf("Expected comment %q for %s, got %q", comment, uses, comments[uses])
+		}
+	}
+	if _, exists := comments["actions/cache@v4"]; exists {
+		t.Errorf("Expected no comment for actions/cache@v4")
+	}
+}
+
+func TestPinCommentVersion(t *testing.T) {
+	tests := map[string]string{
+		"v4.1.1":                  "v4.1.1",
+		"tag=v4.0.2":              "v4.0.2",
+		"pin@v2":                  "v2",
+		"1.2.3":                   "1.2.3",
+		"v5.0.0-beta.1":           "v5.0.0-beta.1",
+		"pinned by security team": "",
+		"reviewed 2024":           "",
+	}
+
+	for comment, expected := range tests {
+		if got := PinCommentVersion(comment); got != expected {
+			t.Errorf("PinCommentVersion(%q) = %q, expected %q", comment, got, expected)
+		}
+	}
+}
+
+func TestParseWorkflow_RejectsOversizedFiles(t *testing.T) {
+	content := generateWorkflow(10, 10)
+
+	_, err := ParseWorkflowWithConfig(content, ".github/workflows/ci.yml", "my-org/api", &Config{MaxFileSize: len(content) - 1})
+	if !errors.Is(err, ErrWorkflowTooLarge) {
+		t.Errorf("Expected ErrWorkflowTooLarge, got %v", err)
+	}
+}
+
+func TestNodeCounter_DetectsAliasBomb(t *testing.T) {
+	// Each level holds ten aliases to the previous level, as in a "billion laughs" document
+	level := &yaml.Node{Kind: yaml.ScalarNode, Value: "lol", Anchor: "l0"}
+	for i := 0; i < 9; i++ {
+		next := &yaml.Node{Kind: yaml.SequenceNode}
+		for j := 0; j < 10; j++ {
+			next.Content = append(next.Content, &yaml.Node{Kind: yaml.AliasNode, Alias: level})
+		}
+		level = next
+	}
+
+	counter := &nodeCounter{limit: DefaultMaxNodeCount, expanded: make(map[*yaml.Node]int), visiting: make(map[*yaml.Node]bool)}
+	if count := counter.count(level); count <= DefaultMaxNodeCount {
+		t.Errorf("Expected alias expansion to exceed %d nodes, got %d", DefaultMaxNodeCount, count)
+	}
+
+	small := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+		{Kind: yaml.ScalarNode, Value: "name"},
+		{Kind: yaml.ScalarNode, Value: "CI"},
+	}}
+	counter = &nodeCounter{limit: DefaultMaxNodeCount, expanded: make(map[*yaml.Node]int), visiting: make(map[*yaml.Node]bool)}
+	if count := counter.count(small); count != 3 {
+		t.Errorf("Expected 3 nodes, got %d", count)
+	}
+}
+
+func TestNormalizeStep(t *testing.T) {
+	tests := []struct {
+		step     Step
+		expected string
+	}{
+		{Step{Name: "Checkout", Uses: "actions/checkout@v4"}, "uses: actions/checkout"},
+		{Step{Uses: "actions/checkout@b4ffde65f46336ab88eb53be808477a3936bae11"}, "uses: actions/checkout"},
+		{Step{Uses: "actions/setup-go@v5", With: map[string]interface{}{"go-version": "1.22", "cache": true}}, "uses: actions/setup-go with: cache=true, go-version=1.22"},
+		{Step{Uses: "./.github/actions/build"}, "uses: ./.github/actions/build"},
+		{Step{Name: "Test", Run: "go   test \\\n  ./..."}, "run: go test \\ ./..."},
+		{Step{Name: "Empty"}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeStep(tt.step); got != tt.expected {
+			t.Errorf("NormalizeStep(%+v) = %q, expected %q", tt.step, got, tt.expected)
+		}
+	}
+}
+
+func TestExtractJobSteps(t *testing.T) {
+	content := `name: CI
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - name: Checkout code
+        uses: actions/checkout@v4
+      - run: go test ./...
+  deploy:
+    uses: my-org/workflows/.github/workflows/deploy.yml@v1
+`
+
+	jobs, err := ExtractJobSteps(content, ".github/workflows/ci.yml", "my-org/api", nil)
+	if err != nil {
+		t.Fatalf("ExtractJobSteps() returned error: %v", err)
+	}
+
+	if len(jobs) != 1 {
+		t.Fatalf("Expected 1 job with steps, got %d", len(jobs))
+	}
+	if jobs[0].Job != "test" || len(jobs[0].Steps) != 2 || jobs[0].Steps[0] != "uses: actions/checkout" {
+		t.Errorf("Unexpected job steps: %+v", jobs[0])
+	}
+}
+
+func TestParseTriggers(t *testing.T) {
+	content := `name: PR Build
+on:
+  pull_request_target:
+    types: [opened, synchronize]
+  schedule:
+    - cron: "0 3 * * 1"
+  workflow_run:
+    workflows: ["CI"]
+    types: [completed]
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+        with:
+          ref: ${{ github.event.pull_request.head.sha }}
+      - run: make test
+  label:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - uses: actions/labeler@v5
+`
+
+	info, err := ParseTriggers(content, ".github/workflows/pr.yml", nil)
+	if err != nil {
+		t.Fatalf("ParseTriggers() returned error: %v", err)
+	}
+
+	expectedEvents := []string{"pull_request_target", "schedule", "workflow_run"}
+	if strings.Join(info.Events, ",") != strings.Join(expectedEvents, ",") {
+		t.Errorf("Expected events %v, got %v", expectedEvents, info.Events)
+	}
+	if len(info.Schedules) != 1 || info.Schedules[0] != "0 3 * * 1" {
+		t.Errorf("Expected cron schedule, got %v", info.Schedules)
+	}
+	if len(info.WorkflowRunSources) != 1 || info.WorkflowRunSources[0] != "CI" {
+		t.Errorf("Expected workflow_run source CI, got %v", info.WorkflowRunSources)
+	}
+	if len(info.PRHeadCheckoutJobs) != 1 || info.PRHeadCheckoutJobs[0] != "build" {
+		t.Errorf("Expected build job to be flagged for PR head checkout, got %v", info.PRHeadCheckoutJobs)
+	}
+}
+
+func TestParseTriggers_ShortForms(t *testing.T) {
+	for content, expected := range map[string]string{
+		"on: push\njobs: {}\n":                  "push",
+		"on: [push, pull_request]\njobs: {}\n":  "pull_request,push",
+		"on:\n  workflow_dispatch:\njobs: {}\n": "workflow_dispatch",
+	} {
+		info, err := ParseTriggers(content, "ci.yml", nil)
+		if err != nil {
+			t.Fatalf("ParseTriggers() returned error: %v", err)
+		}
+		if got := strings.Join(info.Events, ","); got != expected {
+			t.Errorf("Expected events %q, got %q", expected, got)
+		}
+	}
+}
+
+func TestParseJobSettings(t *testing.T) {
+	content := `
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    timeout-minutes: 15
+    strategy:
+      fail-fast: false
+      matrix:
+        go: ["1.22", "1.23"]
+    steps:
+      - run: go test ./...
+  lint:
+    runs-on: ubuntu-latest
+    continue-on-error: true
+    steps:
+      - run: make lint
+  experimental:
+    runs-on: ubuntu-latest
+    continue-on-error: ${{ matrix.experimental }}
+    strategy:
+      fail-fast: true
+    steps:
+      - run: make test
+  release:
+    uses: my-org/workflows/.github/workflows/release.yml@v1
+`
+	jobs, err := ParseJobSettings(content, ".github/workflows/ci.yml", nil)
+	if err != nil {
+		t.Fatalf("ParseJobSettings() returned error: %v", err)
+	}
+
+	expected := []JobSettings{
+		{FilePath: ".github/workflows/ci.yml", Job: "experimental"},
+		{FilePath: ".github/workflows/ci.yml", Job: "lint", ContinueOnError: true},
+		{FilePath: ".github/workflows/ci.yml", Job: "release", Reusable: true},
+		{FilePath: ".github/workflows/ci.yml", Job: "test", HasTimeout: true, FailFastDisabled: true},
+	}
+	if len(jobs) != len(expected) {
+		t.Fatalf("Expected %d jobs, got %+v", len(expected), jobs)
+	}
+	for i := range expected {
+		if jobs[i] != expected[i] {
+			t.Errorf("Job %d: expected %+v, got %+v", i, expected[i], jobs[i])
+		}
+	}
+}
+
+func TestParseWorkflow_RecordsWithBlocks(t *testing.T) {
+	content := `
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+        with:
+          fetch-depth: 0
+  deploy:
+    uses: my-org/shared/.github/workflows/deploy.yml@v1
+    with:
+      environment: production
+`
+	refs, err := ParseWorkflow(content, "ci.yml", "my-org/api")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(refs) != 2 {
+		t.Fatalf("Expected 2 references, got %d", len(refs))
+	}
+
+	for _, ref := range refs {
+		with, ok := ref.With.(map[string]interface{})
+		if !ok {
+			t.Fatalf("Expected a with block for %s, got %v", ref.Repository, ref.With)
+		}
+		switch ref.Repository {
+		case "actions/checkout":
+			if with["fetch-depth"] != 0 {
+				t.Errorf("Expected fetch-depth 0, got %v", with)
+			}
+		case "my-org/shared":
+			if with["environment"] != "production" {
+				t.Errorf("Expected environment production, got %v", with)
+			}
+		}
+
+		// The with block is for in-process analysis only
+		data, err := json.Marshal(ref)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Contains(string(data), "With") {
+			t.Errorf("Expected the with block not to be serialized, got %s", data)
+		}
+	}
+}
+
+func TestParseWorkflow_LocalReusableWorkflows(t *testing.T) {
+	content := `
+on: push
+jobs:
+  build:
+    uses: ./.github/workflows/build.yml
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: ./.github/actions/setup
+  deploy:
+    uses: my-org/shared/.github/workflows/deploy.yml@v1
+`
+	refs, err := ParseWorkflow(content, ".github/workflows/ci.yml", "my-org/api")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(refs) != 2 {
+		t.Fatalf("Expected the local and remote reusable workflows, local actions skipped, got %+v", refs)
+	}
+
+	for _, ref := range refs {
+		if ref.Context != "job:build" {
+			if ref.Local {
+				t.Errorf("Expected only the build call to be local, got %+v", ref)
+			}
+			continue
+		}
+		if !ref.Local || !ref.IsReusable || ref.Repository != "my-org/api" || ref.WorkflowPath != ".github/workflows/build.yml" || ref.Version != "" {
+			t.Errorf("Expected a local reference to my-org/api's build workflow, got %+v", ref)
+		}
+	}
+
+	// Only local references record the flag in results
+	data, _ := json.Marshal(refs)
+	if strings.Count(string(data), `"Local":true`) != 1 || strings.Contains(string(data), `"Local":false`) {
+		t.Errorf("Expected Local only on the local reference, got %s", data)
+	}
+}
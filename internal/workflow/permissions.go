@@ -0,0 +1,115 @@
+package workflow
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TokenUsage records how a workflow scopes its GITHUB_TOKEN and which tokens it authenticates with
+type TokenUsage struct {
+	ExplicitPermissions bool     `json:"explicit_permissions"`   // permissions: is set for the workflow, or for every job
+	GitHubToken         bool     `json:"github_token,omitempty"` // Reads secrets.GITHUB_TOKEN or github.token
+	PATSecrets          []string `json:"pat_secrets,omitempty"`  // Other secrets used as GitHub tokens, sorted
+}
+
+// secretReferencePattern matches secrets.NAME and secrets['NAME'] in expressions
+var secretReferencePattern = regexp.MustCompile(`secrets(?:\.([A-Za-z_][A-Za-z0-9_-]*)|\[\s*['"]([^'"]+)['"]\s*\])`)
+
+// githubTokenPattern matches the github.token context
+var githubTokenPattern = regexp.MustCompile(`\bgithub\.token\b`)
+
+// tokenKeys are inputs and environment variables, lowercased, that take a GitHub token
+var tokenKeys = map[string]bool{
+	"token":               true,
+	"github-token":        true,
+	"repo-token":          true,
+	"gh_token":            true,
+	"github_token":        true,
+	"gh_enterprise_token": true,
+}
+
+// ParseTokenUsage reports whether a workflow sets explicit permissions and which tokens it uses
+// A secret other than GITHUB_TOKEN counts as a personal access token when it is passed to a token input
+// or variable, such as with: token or env: GH_TOKEN, or when its name says so, such as RELEASE_PAT or
+// GH_BOT_TOKEN. Secrets such as NPM_TOKEN, passed elsewhere, are not counted.
+func ParseTokenUsage(content, filePath string, config *Config) (*TokenUsage, error) {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	_, root, err := decodeWorkflowNode(content, config)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &TokenUsage{}
+	if root == nil || len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return usage, nil
+	}
+	document := root.Content[0]
+
+	usage.ExplicitPermissions = mappingValue(document, "permissions") != nil
+	if jobs := mappingValue(document, "jobs"); !usage.ExplicitPermissions && jobs != nil && jobs.Kind == yaml.MappingNode && len(jobs.Content) > 0 {
+		usage.ExplicitPermissions = true
+		for i := 1; i < len(jobs.Content); i += 2 {
+			if jobs.Content[i].Kind != yaml.MappingNode || mappingValue(jobs.Content[i], "permissions") == nil {
+				usage.ExplicitPermissions = false
+				break
+			}
+		}
+	}
+
+	pats := make(map[string]bool)
+	collectTokens(document, "", usage, pats)
+	for name := range pats {
+		usage.PATSecrets = append(usage.PATSecrets, name)
+	}
+	sort.Strings(usage.PATSecrets)
+
+	return usage, nil
+}
+
+// collectTokens records the tokens referenced under a node; key is the mapping key holding the node
+func collectTokens(node *yaml.Node, key string, usage *TokenUsage, pats map[string]bool) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			collectTokens(node.Content[i+1], node.Content[i].Value, usage, pats)
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			collectTokens(item, key, usage, pats)
+		}
+	case yaml.ScalarNode:
+		if githubTokenPattern.MatchString(node.Value) {
+			usage.GitHubToken = true
+		}
+		for _, match := range secretReferencePattern.FindAllStringSubmatch(node.Value, -1) {
+			name := match[1]
+			if name == "" {
+				name = match[2]
+			}
+			switch {
+			case strings.EqualFold(name, "GITHUB_TOKEN"):
+				usage.GitHubToken = true
+			case tokenKeys[strings.ToLower(key)] || isPATName(name):
+				pats[name] = true
+			}
+		}
+	}
+}
+
+// isPATName reports whether a secret name marks a personal access token: a PAT part, as in RELEASE_PAT,
+// or a GitHub token name, as in GH_BOT_TOKEN
+func isPATName(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, part := range strings.FieldsFunc(upper, func(r rune) bool { return r == '_' || r == '-' }) {
+		if part == "PAT" {
+			return true
+		}
+	}
+	return (strings.HasPrefix(upper, "GH_") || strings.HasPrefix(upper, "GITHUB_")) && strings.Contains(upper, "TOKEN")
+}
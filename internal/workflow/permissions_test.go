@@ -0,0 +1,65 @@
+package workflow
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTokenUsage(t *testing.T) {
+	content := `
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    permissions:
+      contents: read
+    steps:
+      - uses: actions/checkout@v4
+        with:
+          token: ${{ secrets.CHECKOUT_KEY }}
+      - run: npm publish
+        env:
+          NODE_AUTH_TOKEN: ${{ secrets.NPM_TOKEN }}
+  release:
+    runs-on: ubuntu-latest
+    steps:
+      - run: gh release create "$TAG"
+        env:
+          GH_TOKEN: ${{ github.token }}
+      - run: ./notify.sh "${{ secrets['RELEASE_PAT'] }}"
+`
+	usage, err := ParseTokenUsage(content, "release.yml", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The release job sets no permissions, and NPM_TOKEN is neither passed as a GitHub token nor named like one
+	expected := &TokenUsage{ExplicitPermissions: false, GitHubToken: true, PATSecrets: []string{"CHECKOUT_KEY", "RELEASE_PAT"}}
+	if !reflect.DeepEqual(usage, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, usage)
+	}
+}
+
+func TestParseTokenUsage_ExplicitPermissions(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		explicit bool
+	}{
+		{"workflow level", "on: push\npermissions: read-all\njobs:\n  a:\n    runs-on: ubuntu-latest\n    steps: [{run: make}]\n", true},
+		{"every job", "on: push\njobs:\n  a:\n    runs-on: ubuntu-latest\n    permissions: {}\n    steps: [{run: make}]\n  b:\n    uses: my-org/workflows/.github/workflows/ci.yml@v1\n    permissions:\n      contents: read\n", true},
+		{"no jobs", "on: push\n", false},
+	}
+	for _, tt := range tests {
+		usage, err := ParseTokenUsage(tt.content, "ci.yml", nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if usage.ExplicitPermissions != tt.explicit {
+			t.Errorf("%s: expected explicit permissions %v, got %v", tt.name, tt.explicit, usage.ExplicitPermissions)
+		}
+		if usage.GitHubToken || len(usage.PATSecrets) > 0 {
+			t.Errorf("%s: expected no tokens, got %+v", tt.name, usage)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// tokenVariables are the environment variables the GitHub CLI reads its token from
+var tokenVariables = []string{"GH_TOKEN", "GITHUB_TOKEN", "GH_ENTERPRISE_TOKEN"}
+
+// ReleaseStep is a step or reusable workflow call that may take part in a release pipeline
+type ReleaseStep struct {
+	FilePath     string
+	Job          string
+	Context      string   // "job:<job>/step:<name>" for steps, "job:<job>" for reusable workflow calls
+	Uses         string   // uses: as written; empty for run steps
+	Run          string   // run: script as written; empty for action steps
+	TokenSecrets []string // Secrets other than GITHUB_TOKEN in the step's GitHub CLI token variables
+}
+
+// ParseReleaseSteps returns the action steps and reusable workflow calls of a workflow, and its run
+// steps calling "gh release", sorted by job. Token secrets include env inherited from the job and workflow.
+func ParseReleaseSteps(content, filePath string, config *Config) ([]ReleaseStep, error) {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	workflow, err := decodeWorkflow(content, config)
+	if err != nil {
+		return nil, err
+	}
+
+	jobNames := make([]string, 0, len(workflow.Jobs))
+	for jobName := range workflow.Jobs {
+		jobNames = append(jobNames, jobName)
+	}
+	sort.Strings(jobNames)
+
+	workflowEnv := sensitiveEnv(workflow.Env, nil)
+	var steps []ReleaseStep
+	for _, jobName := range jobNames {
+		job := workflow.Jobs[jobName]
+		if job.Uses != "" {
+			steps = append(steps, ReleaseStep{
+				FilePath: filePath,
+				Job:      jobName,
+				Context:  fmt.Sprintf("job:%s", jobName),
+				Uses:     job.Uses,
+			})
+			continue
+		}
+
+		jobEnv := mergeEnv(workflowEnv, sensitiveEnv(job.Env, workflowEnv))
+		for stepIdx, step := range job.Steps {
+			if step.Uses == "" && !IsReleaseCommand(step.Run) {
+				continue
+			}
+			stepName := step.Name
+			if stepName == "" {
+				stepName = fmt.Sprintf("step-%d", stepIdx+1)
+			}
+
+			releaseStep := ReleaseStep{
+				FilePath: filePath,
+				Job:      jobName,
+				Context:  fmt.Sprintf("job:%s/step:%s", jobName, stepName),
+				Uses:     step.Uses,
+				Run:      step.Run,
+			}
+			if releaseStep.Run != "" {
+				releaseStep.TokenSecrets = tokenSecrets(mergeEnv(jobEnv, sensitiveEnv(step.Env, jobEnv)))
+			}
+			steps = append(steps, releaseStep)
+		}
+	}
+
+	return steps, nil
+}
+
+// IsReleaseCommand reports whether a run: script calls the GitHub CLI's release commands
+func IsReleaseCommand(run string) bool {
+	for _, line := range strings.Split(run, "\n") {
+		if fields := strings.Fields(line); containsSequence(fields, "gh", "release") {
+			return true
+		}
+	}
+	return false
+}
+
+// containsSequence reports whether first is directly followed by second among fields
+func containsSequence(fields []string, first, second string) bool {
+	for i := 0; i+1 < len(fields); i++ {
+		if fields[i] == first && fields[i+1] == second {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenSecrets returns the secrets, other than GITHUB_TOKEN, read by the GitHub CLI token variables of an environment
+func tokenSecrets(env map[string][]sensitiveValue) []string {
+	seen := make(map[string]bool)
+	var secrets []string
+	for _, variable := range tokenVariables {
+		for _, value := range env[variable] {
+			if value.kind != FlowKindSecret || value.name == "GITHUB_TOKEN" || seen[value.name] {
+				continue
+			}
+			seen[value.name] = true
+			secrets = append(secrets, value.name)
+		}
+	}
+	sort.Strings(secrets)
+	return secrets
+}
@@ -0,0 +1,64 @@
+package workflow
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseReleaseSteps(t *testing.T) {
+	content := `
+on:
+  push:
+    tags: ["v*"]
+env:
+  RELEASE_TOKEN: ${{ secrets.RELEASE_PAT }}
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make build
+      - uses: actions/checkout@v4
+  publish:
+    runs-on: ubuntu-latest
+    env:
+      GH_TOKEN: ${{ env.RELEASE_TOKEN }}
+    steps:
+      - name: Release
+        run: gh release create "$TAG" dist/*
+      - name: Default token
+        run: gh release upload "$TAG" extra.zip
+        env:
+          GH_TOKEN: ${{ github.token }}
+  provenance:
+    uses: slsa-framework/slsa-github-generator/.github/workflows/generator_generic_slsa3.yml@v2.0.0
+`
+	steps, err := ParseReleaseSteps(content, "release.yml", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []ReleaseStep{
+		{FilePath: "release.yml", Job: "build", Context: "job:build/step:step-2", Uses: "actions/checkout@v4"},
+		{FilePath: "release.yml", Job: "provenance", Context: "job:provenance", Uses: "slsa-framework/slsa-github-generator/.github/workflows/generator_generic_slsa3.yml@v2.0.0"},
+		{FilePath: "release.yml", Job: "publish", Context: "job:publish/step:Release", Run: `gh release create "$TAG" dist/*`, TokenSecrets: []string{"RELEASE_PAT"}},
+		{FilePath: "release.yml", Job: "publish", Context: "job:publish/step:Default token", Run: `gh release upload "$TAG" extra.zip`},
+	}
+	if !reflect.DeepEqual(steps, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, steps)
+	}
+}
+
+func TestIsReleaseCommand(t *testing.T) {
+	tests := map[string]bool{
+		"gh release create v1.0.0":           true,
+		"set -e\n  gh   release view v1":     true,
+		"gh pr create --fill":                false,
+		"echo 'see the gh-release docs'":     false,
+		"./scripts/release.sh && gh release": true,
+	}
+	for run, expected := range tests {
+		if got := IsReleaseCommand(run); got != expected {
+			t.Errorf("IsReleaseCommand(%q) = %v, expected %v", run, got, expected)
+		}
+	}
+}
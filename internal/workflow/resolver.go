@@ -2,6 +2,7 @@ package workflow
 
 import (
 	"fmt"
+	"path"
 	"strings"
 	"time"
 
@@ -14,6 +15,12 @@ type GitHubClient interface {
 	GetTagsForRepo(owner, repo string) (map[string]string, error)
 }
 
+// MatchingTagsClient is implemented by clients that list only the tags starting with a prefix, which
+// costs far fewer requests than listing every tag of an action with thousands of them
+type MatchingTagsClient interface {
+	GetMatchingTags(owner, repo, prefix string) (map[string]string, error)
+}
+
 // VersionResolver handles resolution of version aliases to commit SHAs
 //
 // Alias Resolution Design:
@@ -29,22 +36,22 @@ type GitHubClient interface {
 //
 // Example: If v1 tag and commit SHA abc123 both point to the same commit,
 // they are considered equivalent even though the strings differ.
+//
+// Tag Listing:
+// Aliases of a version tag are looked up among the tags sharing its major version, such as v4*,
+// when the client supports matching-ref lookups. Every tag is listed only for repositories opted in
+// with SetComprehensiveRepositories, which also keeps their comprehensive cache populated; other
+// repositories get a comprehensive cache only when their full tag list was fetched anyway.
+//
+// Resolvers are safe for concurrent use, including several resolvers sharing one cache: lookups of
+// the same ref or tag list wait for the first to finish, so each is fetched from the API once.
 type VersionResolver struct {
 	client      GitHubClient
 	skipResolve bool
 	cache       cache.Cache
 	cacheTTL    time.Duration
-}
 
-// cacheEntry represents a cached resolution result (deprecated - now using shared cache)
-type cacheEntry struct {
-	sha       string
-	timestamp time.Time
-	tags      map[string]string // maps tag names to SHAs for a repository
-
-	// Enhanced fields for comprehensive action version caching
-	allVersions map[string]string   // complete version->SHA mapping for a repository
-	aliases     map[string][]string // SHA->[]version aliases mapping
+	comprehensiveRepos []string // Repository patterns, such as "actions/*", whose every tag is listed
 }
 
 // ResolvedAction represents an action with resolved version information
@@ -54,18 +61,17 @@ type ResolvedAction struct {
 	Aliases     []string // Other version references that resolve to the same SHA
 }
 
-// NewVersionResolver creates a new version resolver
+// NewVersionResolver creates a new version resolver with a cache of its own
 func NewVersionResolver(client GitHubClient, skipResolve bool) *VersionResolver {
-	return &VersionResolver{
-		client:      client,
-		skipResolve: skipResolve,
-		cache:       nil,       // Will be set when called with a cache
-		cacheTTL:    time.Hour, // Cache for 1 hour
-	}
+	return NewVersionResolverWithCache(client, skipResolve, nil)
 }
 
 // NewVersionResolverWithCache creates a new version resolver with shared cache
+// A nil cache gives the resolver a cache of its own.
 func NewVersionResolverWithCache(client GitHubClient, skipResolve bool, sharedCache cache.Cache) *VersionResolver {
+	if sharedCache == nil {
+		sharedCache = cache.NewMemoryCache()
+	}
 	return &VersionResolver{
 		client:      client,
 		skipResolve: skipResolve,
@@ -74,6 +80,23 @@ func NewVersionResolverWithCache(client GitHubClient, skipResolve bool, sharedCa
 	}
 }
 
+// SetComprehensiveRepositories opts repositories into listing every tag and caching comprehensive
+// version information; patterns are "owner/name" with path.Match wildcards, such as "my-org/*" or "*"
+func (vr *VersionResolver) SetComprehensiveRepositories(patterns []string) {
+	vr.comprehensiveRepos = patterns
+}
+
+// comprehensive reports whether a repository is opted into listing every tag
+func (vr *VersionResolver) comprehensive(owner, repo string) bool {
+	name := strings.ToLower(owner + "/" + repo)
+	for _, pattern := range vr.comprehensiveRepos {
+		if matched, _ := path.Match(strings.ToLower(pattern), name); matched {
+			return true
+		}
+	}
+	return false
+}
+
 // ResolveActionReferences resolves version aliases for a list of action references
 func (vr *VersionResolver) ResolveActionReferences(actions []ActionReference) ([]ResolvedAction, error) {
 	if vr.skipResolve {
@@ -91,6 +114,11 @@ func (vr *VersionResolver) ResolveActionReferences(actions []ActionReference) ([
 
 	var resolved []ResolvedAction
 	for _, action := range actions {
+		// Local reusable workflows have no version to resolve
+		if action.Local {
+			resolved = append(resolved, ResolvedAction{ActionReference: action, Aliases: []string{}})
+			continue
+		}
 		resolvedAction, err := vr.resolveAction(action)
 		if err != nil {
 			// If resolution fails, fall back to unresolved action
@@ -123,9 +151,6 @@ func (vr *VersionResolver) resolveAction(action ActionReference) (ResolvedAction
 		return ResolvedAction{}, fmt.Errorf("failed to resolve %s@%s: %w", action.Repository, action.Version, err)
 	}
 
-	// Proactively populate comprehensive cache if not already present
-	vr.ensureComprehensiveCache(owner, repo)
-
 	// Find aliases (other tags that point to the same commit)
 	aliases, err := vr.findAliases(owner, repo, sha, action.Version)
 	if err != nil {
@@ -133,6 +158,10 @@ func (vr *VersionResolver) resolveAction(action ActionReference) (ResolvedAction
 		aliases = []string{}
 	}
 
+	// Populate the comprehensive cache if not already present: opted-in repositories list every tag,
+	// others only reuse a full tag list that is already cached
+	vr.populateComprehensiveCache(owner, repo, vr.comprehensive(owner, repo))
+
 	return ResolvedAction{
 		ActionReference: action,
 		ResolvedSHA:     sha,
@@ -146,15 +175,16 @@ func (vr *VersionResolver) ResolveRefWithCache(owner, repo, ref string) (string,
 }
 
 // resolveRefWithCache resolves a reference to a commit SHA with caching
+// Concurrent lookups of the same ref wait for the first to finish and read its result from the cache.
 func (vr *VersionResolver) resolveRefWithCache(owner, repo, ref string) (string, error) {
-	// If we have a cache, try to use it
-	if vr.cache != nil {
-		if sha, found, err := vr.cache.GetRef(owner, repo, ref); err == nil && found {
-			return sha, nil
-		} else if err != nil {
-			// Log the error but continue with API resolution
-			fmt.Printf("Warning: Cache error when getting ref %s/%s:%s - %v\n", owner, repo, ref, err)
-		}
+	unlock := vr.cache.Lock(cache.RefKey(owner, repo, ref))
+	defer unlock()
+
+	if sha, found, err := vr.cache.GetRef(owner, repo, ref); err == nil && found {
+		return sha, nil
+	} else if err != nil {
+		// Log the error but continue with API resolution
+		fmt.Printf("Warning: Cache error when getting ref %s/%s:%s - %v\n", owner, repo, ref, err)
 	}
 
 	// Resolve using GitHub API
@@ -163,12 +193,9 @@ func (vr *VersionResolver) resolveRefWithCache(owner, repo, ref string) (string,
 		return "", err
 	}
 
-	// Cache the result if we have a cache
-	if vr.cache != nil {
-		if err := vr.cache.SetRef(owner, repo, ref, sha, vr.cacheTTL); err != nil {
-			// Log the error but don't fail the operation
-			fmt.Printf("Warning: Failed to cache ref resolution %s/%s:%s - %v\n", owner, repo, ref, err)
-		}
+	if err := vr.cache.SetRef(owner, repo, ref, sha, vr.cacheTTL); err != nil {
+		// Log the error but don't fail the operation
+		fmt.Printf("Warning: Failed to cache ref resolution %s/%s:%s - %v\n", owner, repo, ref, err)
 	}
 
 	return sha, nil
@@ -176,8 +203,7 @@ func (vr *VersionResolver) resolveRefWithCache(owner, repo, ref string) (string,
 
 // findAliases finds other version references that resolve to the same commit SHA
 func (vr *VersionResolver) findAliases(owner, repo, targetSHA, currentVersion string) ([]string, error) {
-	// Get all tags for the repository with caching
-	tags, err := vr.getTagsWithCache(owner, repo)
+	tags, err := vr.aliasCandidates(owner, repo, currentVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -198,16 +224,79 @@ func (vr *VersionResolver) findAliases(owner, repo, targetSHA, currentVersion st
 	return aliases, nil
 }
 
+// aliasCandidates returns the tags that may be aliases of a version, with the SHAs they point at
+// Cached tag lists are used first. Otherwise a version tag such as v4.1.1 only needs the tags of its
+// major version, listed by prefix, unless the repository is opted into listing every tag.
+func (vr *VersionResolver) aliasCandidates(owner, repo, version string) (map[string]string, error) {
+	if versions, _, found := vr.GetCachedVersionInfo(owner, repo); found {
+		return versions, nil
+	}
+	if tags, found, err := vr.cache.GetTags(owner, repo); err == nil && found {
+		return tags, nil
+	}
+
+	matcher, ok := vr.client.(MatchingTagsClient)
+	if prefix := majorPrefix(version); ok && prefix != "" && !vr.comprehensive(owner, repo) {
+		return vr.getMatchingTagsWithCache(matcher, owner, repo, prefix)
+	}
+	return vr.getTagsWithCache(owner, repo)
+}
+
+// getMatchingTagsWithCache gets the tags of a repository starting with prefix with caching
+func (vr *VersionResolver) getMatchingTagsWithCache(matcher MatchingTagsClient, owner, repo, prefix string) (map[string]string, error) {
+	unlock := vr.cache.Lock(cache.MatchingTagsKey(owner, repo, prefix))
+	defer unlock()
+
+	if tags, found, err := vr.cache.GetMatchingTags(owner, repo, prefix); err == nil && found {
+		return tags, nil
+	} else if err != nil {
+		// Log the error but continue with API resolution
+		fmt.Printf("Warning: Cache error when getting tags %s/%s:%s* - %v\n", owner, repo, prefix, err)
+	}
+
+	tags, err := matcher.GetMatchingTags(owner, repo, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := vr.cache.SetMatchingTags(owner, repo, prefix, tags, vr.cacheTTL); err != nil {
+		// Log the error but don't fail the operation
+		fmt.Printf("Warning: Failed to cache tags %s/%s:%s* - %v\n", owner, repo, prefix, err)
+	}
+
+	return tags, nil
+}
+
+// majorPrefix returns the major version part of a version tag, such as v4 for v4.1.1, or an empty
+// string for refs that are not version tags, such as branches and SHAs
+func majorPrefix(version string) string {
+	digits := strings.TrimPrefix(strings.TrimPrefix(version, "v"), "V")
+	end := 0
+	for end < len(digits) && digits[end] >= '0' && digits[end] <= '9' {
+		end++
+	}
+	if end == 0 || (end < len(digits) && digits[end] != '.') {
+		return ""
+	}
+	return version[:len(version)-len(digits)+end]
+}
+
+// GetTagsWithCache gets all tags for a repository with caching (public method)
+func (vr *VersionResolver) GetTagsWithCache(owner, repo string) (map[string]string, error) {
+	return vr.getTagsWithCache(owner, repo)
+}
+
 // getTagsWithCache gets all tags for a repository with caching
+// Concurrent lookups of the same repository wait for the first to finish and read its result from the cache.
 func (vr *VersionResolver) getTagsWithCache(owner, repo string) (map[string]string, error) {
-	// If we have a cache, try to use it
-	if vr.cache != nil {
-		if tags, found, err := vr.cache.GetTags(owner, repo); err == nil && found {
-			return tags, nil
-		} else if err != nil {
-			// Log the error but continue with API resolution
-			fmt.Printf("Warning: Cache error when getting tags %s/%s - %v\n", owner, repo, err)
-		}
+	unlock := vr.cache.Lock(cache.TagsKey(owner, repo))
+	defer unlock()
+
+	if tags, found, err := vr.cache.GetTags(owner, repo); err == nil && found {
+		return tags, nil
+	} else if err != nil {
+		// Log the error but continue with API resolution
+		fmt.Printf("Warning: Cache error when getting tags %s/%s - %v\n", owner, repo, err)
 	}
 
 	// Fetch tags using GitHub API
@@ -216,24 +305,44 @@ func (vr *VersionResolver) getTagsWithCache(owner, repo string) (map[string]stri
 		return nil, err
 	}
 
-	// Cache the result if we have a cache
-	if vr.cache != nil {
-		if err := vr.cache.SetTags(owner, repo, tags, vr.cacheTTL); err != nil {
-			// Log the error but don't fail the operation
-			fmt.Printf("Warning: Failed to cache tags %s/%s - %v\n", owner, repo, err)
-		}
+	if err := vr.cache.SetTags(owner, repo, tags, vr.cacheTTL); err != nil {
+		// Log the error but don't fail the operation
+		fmt.Printf("Warning: Failed to cache tags %s/%s - %v\n", owner, repo, err)
 	}
 
 	return tags, nil
 }
 
+// RefreshTags fetches every tag of a repository and replaces its cached tag list, tag refs, and
+// comprehensive version information, so lookups keep hitting the cache instead of waiting for the API
+// once the old entries expire. Resolvers that skip resolution do nothing.
+func (vr *VersionResolver) RefreshTags(owner, repo string) error {
+	if vr.skipResolve {
+		return nil
+	}
+
+	tags, err := vr.client.GetTagsForRepo(owner, repo)
+	if err != nil {
+		return err
+	}
+
+	if err := vr.cache.SetTags(owner, repo, tags, vr.cacheTTL); err != nil {
+		return fmt.Errorf("failed to cache tags %s/%s: %w", owner, repo, err)
+	}
+	aliases := make(map[string][]string)
+	for tag, sha := range tags {
+		if err := vr.cache.SetRef(owner, repo, tag, sha, vr.cacheTTL); err != nil {
+			return fmt.Errorf("failed to cache ref resolution %s/%s:%s: %w", owner, repo, tag, err)
+		}
+		aliases[sha] = append(aliases[sha], tag)
+	}
+	vr.cacheComprehensiveVersionInfo(owner, repo, tags, aliases)
+	return nil
+}
+
 // GetCachedVersionInfo retrieves comprehensive version information from cache
 // Returns version->SHA mappings and SHA->aliases mappings if available in cache
 func (vr *VersionResolver) GetCachedVersionInfo(owner, repo string) (map[string]string, map[string][]string, bool) {
-	if vr.cache == nil {
-		return nil, nil, false
-	}
-
 	versions, aliases, found, err := vr.cache.GetComprehensiveVersionInfo(owner, repo)
 	if err != nil {
 		// Log the error but return not found
@@ -246,10 +355,6 @@ func (vr *VersionResolver) GetCachedVersionInfo(owner, repo string) (map[string]
 
 // cacheComprehensiveVersionInfo stores complete version information for a repository
 func (vr *VersionResolver) cacheComprehensiveVersionInfo(owner, repo string, versions map[string]string, aliases map[string][]string) {
-	if vr.cache == nil {
-		return
-	}
-
 	if err := vr.cache.SetComprehensiveVersionInfo(owner, repo, versions, aliases, vr.cacheTTL); err != nil {
 		// Log the error but don't fail the operation
 		fmt.Printf("Warning: Failed to cache comprehensive version info %s/%s - %v\n", owner, repo, err)
@@ -258,15 +363,31 @@ func (vr *VersionResolver) cacheComprehensiveVersionInfo(owner, repo string, ver
 
 // ensureComprehensiveCache ensures comprehensive version information is cached for a repository
 func (vr *VersionResolver) ensureComprehensiveCache(owner, repo string) {
+	vr.populateComprehensiveCache(owner, repo, true)
+}
+
+// populateComprehensiveCache caches comprehensive version information for a repository if not
+// already present; without fetch it is built only from a tag list that is already cached
+func (vr *VersionResolver) populateComprehensiveCache(owner, repo string, fetch bool) {
+	unlock := vr.cache.Lock(cache.ComprehensiveKey(owner, repo))
+	defer unlock()
+
 	// Check if comprehensive cache already exists and is fresh
 	if _, _, hasCached := vr.GetCachedVersionInfo(owner, repo); hasCached {
 		return // Already cached
 	}
 
-	// Fetch all tags/versions for the repository
-	tags, err := vr.getTagsWithCache(owner, repo)
-	if err != nil {
-		return // Failed to get tags, continue without comprehensive cache
+	var tags map[string]string
+	if fetch {
+		// Fetch all tags/versions for the repository
+		var err error
+		if tags, err = vr.getTagsWithCache(owner, repo); err != nil {
+			return // Failed to get tags, continue without comprehensive cache
+		}
+	} else if cached, found, err := vr.cache.GetTags(owner, repo); err == nil && found {
+		tags = cached
+	} else {
+		return // Tags not fetched yet, and listing them all is not worth the requests
 	}
 
 	// Build comprehensive version mappings and aliases
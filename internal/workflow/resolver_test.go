@@ -2,7 +2,10 @@ package workflow
 
 import (
 	"fmt"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/cache"
 )
@@ -267,3 +270,200 @@ func TestVersionResolver_InvalidRepository(t *testing.T) {
 		t.Errorf("Expected empty ResolvedSHA for invalid repository format, got: %s", action.ResolvedSHA)
 	}
 }
+
+// countingGitHubClient counts API calls and holds each one long enough for concurrent callers to overlap
+type countingGitHubClient struct {
+	*MockGitHubClient
+	mutex     sync.Mutex
+	refCalls  int
+	tagsCalls int
+}
+
+func (c *countingGitHubClient) ResolveRef(owner, repo, ref string) (string, error) {
+	c.mutex.Lock()
+	c.refCalls++
+	c.mutex.Unlock()
+	time.Sleep(10 * time.Millisecond)
+	return c.MockGitHubClient.ResolveRef(owner, repo, ref)
+}
+
+func (c *countingGitHubClient) GetTagsForRepo(owner, repo string) (map[string]string, error) {
+	c.mutex.Lock()
+	c.tagsCalls++
+	c.mutex.Unlock()
+	time.Sleep(10 * time.Millisecond)
+	return c.MockGitHubClient.GetTagsForRepo(owner, repo)
+}
+
+func TestVersionResolver_ConcurrentSharedCache(t *testing.T) {
+	mock := NewMockGitHubClient()
+	mock.AddRefResolution("actions", "checkout", "v4", "abc123")
+	mock.AddRepoTags("actions", "checkout", map[string]string{"v4": "abc123", "v4.1.0": "abc123"})
+	client := &countingGitHubClient{MockGitHubClient: mock}
+
+	// Two resolvers sharing one cache, as concurrent scans of several repositories do
+	shared := cache.NewMemoryCache()
+	resolvers := []*VersionResolver{
+		NewVersionResolverWithCache(client, false, shared),
+		NewVersionResolverWithCache(client, false, shared),
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(resolver *VersionResolver) {
+			defer wg.Done()
+			resolved, err := resolver.ResolveActionReferences([]ActionReference{{Repository: "actions/checkout", Version: "v4"}})
+			if err != nil || len(resolved) != 1 || resolved[0].ResolvedSHA != "abc123" {
+				t.Errorf("Expected actions/checkout@v4 to resolve to abc123, got %+v, %v", resolved, err)
+			}
+		}(resolvers[i%2])
+	}
+	wg.Wait()
+
+	if client.refCalls != 1 {
+		t.Errorf("Expected 1 ref resolution API call, got %d", client.refCalls)
+	}
+	if client.tagsCalls != 1 {
+		t.Errorf("Expected 1 tag listing API call, got %d", client.tagsCalls)
+	}
+}
+
+func TestNewVersionResolver_OwnCache(t *testing.T) {
+	mock := NewMockGitHubClient()
+	mock.AddRefResolution("actions", "checkout", "v4", "abc123")
+	client := &countingGitHubClient{MockGitHubClient: mock}
+	resolver := NewVersionResolver(client, false)
+
+	for i := 0; i < 3; i++ {
+		if sha, err := resolver.ResolveRefWithCache("actions", "checkout", "v4"); err != nil || sha != "abc123" {
+			t.Fatalf("Expected abc123, got %q, %v", sha, err)
+		}
+	}
+	if client.refCalls != 1 {
+		t.Errorf("Expected a resolver without a shared cache to cache refs itself, got %d API calls", client.refCalls)
+	}
+}
+
+// matchingGitHubClient lists tags by prefix and counts full tag listings
+type matchingGitHubClient struct {
+	*MockGitHubClient
+	tagsCalls     int
+	matchingCalls []string
+}
+
+func (c *matchingGitHubClient) GetTagsForRepo(owner, repo string) (map[string]string, error) {
+	c.tagsCalls++
+	return c.MockGitHubClient.GetTagsForRepo(owner, repo)
+}
+
+func (c *matchingGitHubClient) GetMatchingTags(owner, repo, prefix string) (map[string]string, error) {
+	c.matchingCalls = append(c.matchingCalls, prefix)
+	tags, _ := c.MockGitHubClient.GetTagsForRepo(owner, repo)
+	matching := make(map[string]string)
+	for name, sha := range tags {
+		if strings.HasPrefix(name, prefix) {
+			matching[name] = sha
+		}
+	}
+	return matching, nil
+}
+
+func TestVersionResolver_MatchingTagLookups(t *testing.T) {
+	mock := NewMockGitHubClient()
+	mock.AddRefResolution("actions", "checkout", "v4.1.0", "abc123")
+	mock.AddRefResolution("actions", "checkout", "main", "def456")
+	mock.AddRepoTags("actions", "checkout", map[string]string{"v4": "abc123", "v4.1.0": "abc123", "v3": "fed789"})
+	client := &matchingGitHubClient{MockGitHubClient: mock}
+
+	resolver := NewVersionResolverWithCache(client, false, cache.NewMemoryCache())
+	for i := 0; i < 2; i++ {
+		resolved, err := resolver.ResolveActionReferences([]ActionReference{{Repository: "actions/checkout", Version: "v4.1.0"}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(resolved[0].Aliases) != 1 || resolved[0].Aliases[0] != "v4" {
+			t.Errorf("Expected alias v4, got %v", resolved[0].Aliases)
+		}
+	}
+	if client.tagsCalls != 0 || len(client.matchingCalls) != 1 || client.matchingCalls[0] != "v4" {
+		t.Errorf("Expected one cached lookup of v4 tags and no full listing, got %d full listings and lookups %v", client.tagsCalls, client.matchingCalls)
+	}
+	if _, _, found := resolver.GetCachedVersionInfo("actions", "checkout"); found {
+		t.Error("Expected no comprehensive cache without a full tag listing")
+	}
+
+	// Branches have no major version to match, so every tag is listed
+	if _, err := resolver.ResolveActionReferences([]ActionReference{{Repository: "actions/checkout", Version: "main"}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if client.tagsCalls != 1 {
+		t.Errorf("Expected a full tag listing for a branch, got %d", client.tagsCalls)
+	}
+	if _, _, found := resolver.GetCachedVersionInfo("actions", "checkout"); !found {
+		t.Error("Expected the comprehensive cache built from the fetched tag list")
+	}
+}
+
+func TestVersionResolver_ComprehensiveRepositories(t *testing.T) {
+	mock := NewMockGitHubClient()
+	mock.AddRefResolution("my-org", "deploy", "v2", "abc123")
+	mock.AddRepoTags("my-org", "deploy", map[string]string{"v2": "abc123", "v2.0.1": "abc123", "v1": "fed789"})
+	client := &matchingGitHubClient{MockGitHubClient: mock}
+
+	resolver := NewVersionResolverWithCache(client, false, cache.NewMemoryCache())
+	resolver.SetComprehensiveRepositories([]string{"My-Org/*"})
+	if _, err := resolver.ResolveActionReferences([]ActionReference{{Repository: "my-org/deploy", Version: "v2"}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if client.tagsCalls != 1 || len(client.matchingCalls) != 0 {
+		t.Errorf("Expected a full tag listing for an opted-in repository, got %d full listings and lookups %v", client.tagsCalls, client.matchingCalls)
+	}
+	if versions, _, found := resolver.GetCachedVersionInfo("my-org", "deploy"); !found || versions["v1"] != "fed789" {
+		t.Errorf("Expected every version cached, got %v", versions)
+	}
+}
+
+func TestMajorPrefix(t *testing.T) {
+	tests := map[string]string{
+		"v4":       "v4",
+		"v4.1.1":   "v4",
+		"V12.0":    "V12",
+		"2.3.4":    "2",
+		"main":     "",
+		"v4-beta":  "",
+		"a1b2c3d4": "",
+		"":         "",
+	}
+	for version, expected := range tests {
+		if prefix := majorPrefix(version); prefix != expected {
+			t.Errorf("majorPrefix(%q) = %q, expected %q", version, prefix, expected)
+		}
+	}
+}
+
+func TestVersionResolver_RefreshTags(t *testing.T) {
+	client := NewMockGitHubClient()
+	client.AddRepoTags("actions", "checkout", map[string]string{"v4": "sha-new", "v4.2.0": "sha-new", "v3": "sha-old"})
+	resolver := NewVersionResolverWithCache(client, false, cache.NewMemoryCache())
+
+	if err := resolver.RefreshTags("actions", "checkout"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Tag refs resolve from the cache without the API
+	if sha, err := resolver.ResolveRefWithCache("actions", "checkout", "v4"); err != nil || sha != "sha-new" {
+		t.Errorf("Expected v4 to resolve from the refreshed cache, got %q (%v)", sha, err)
+	}
+	versions, aliases, found := resolver.GetCachedVersionInfo("actions", "checkout")
+	if !found || versions["v3"] != "sha-old" || len(aliases["sha-new"]) != 2 {
+		t.Errorf("Expected comprehensive version info, got %v %v (%t)", versions, aliases, found)
+	}
+
+	// A moved tag replaces the cached one
+	client.AddRepoTags("actions", "checkout", map[string]string{"v4": "sha-newer"})
+	resolver.RefreshTags("actions", "checkout")
+	if sha, _ := resolver.ResolveRefWithCache("actions", "checkout", "v4"); sha != "sha-newer" {
+		t.Errorf("Expected the refresh to replace the cached ref, got %q", sha)
+	}
+}
@@ -0,0 +1,52 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+)
+
+// RunStep is a step running a shell script
+type RunStep struct {
+	FilePath string
+	Job      string
+	Context  string // "job:<job>/step:<name>"
+	Run      string // run: script as written
+}
+
+// ParseRunSteps returns the run: steps of a workflow's jobs, sorted by job and in step order
+func ParseRunSteps(content, filePath string, config *Config) ([]RunStep, error) {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	workflow, err := decodeWorkflow(content, config)
+	if err != nil {
+		return nil, err
+	}
+
+	jobNames := make([]string, 0, len(workflow.Jobs))
+	for jobName := range workflow.Jobs {
+		jobNames = append(jobNames, jobName)
+	}
+	sort.Strings(jobNames)
+
+	var steps []RunStep
+	for _, jobName := range jobNames {
+		for stepIdx, step := range workflow.Jobs[jobName].Steps {
+			if step.Run == "" {
+				continue
+			}
+			stepName := step.Name
+			if stepName == "" {
+				stepName = fmt.Sprintf("step-%d", stepIdx+1)
+			}
+			steps = append(steps, RunStep{
+				FilePath: filePath,
+				Job:      jobName,
+				Context:  fmt.Sprintf("job:%s/step:%s", jobName, stepName),
+				Run:      step.Run,
+			})
+		}
+	}
+	return steps, nil
+}
@@ -0,0 +1,43 @@
+package workflow
+
+import "sort"
+
+// JobSettings holds the run-control settings of a single workflow job
+type JobSettings struct {
+	FilePath         string // Workflow file path
+	Job              string // Job ID within the workflow
+	Reusable         bool   // Calls a reusable workflow, which cannot set timeout-minutes
+	HasTimeout       bool   // Sets timeout-minutes
+	ContinueOnError  bool   // Sets continue-on-error: true; expressions are not counted
+	FailFastDisabled bool   // Sets strategy.fail-fast: false
+}
+
+// ParseJobSettings parses a workflow and returns the settings of each job, sorted by job ID
+func ParseJobSettings(content, filePath string, config *Config) ([]JobSettings, error) {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	workflow, err := decodeWorkflow(content, config)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]JobSettings, 0, len(workflow.Jobs))
+	for jobName, job := range workflow.Jobs {
+		failFast, hasFailFast := settingValue(job.Strategy, "fail-fast").(bool)
+		continueOnError, _ := job.ContinueOnError.(bool)
+
+		jobs = append(jobs, JobSettings{
+			FilePath:         filePath,
+			Job:              jobName,
+			Reusable:         job.Uses != "",
+			HasTimeout:       job.TimeoutMinutes != nil,
+			ContinueOnError:  continueOnError,
+			FailFastDisabled: hasFailFast && !failFast,
+		})
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Job < jobs[j].Job })
+
+	return jobs, nil
+}
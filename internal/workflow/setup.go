@@ -0,0 +1,177 @@
+package workflow
+
+import (
+	"sort"
+	"strings"
+)
+
+// How a job provides a language toolchain
+const (
+	SetupMethodAction    = "action"    // A setup action such as actions/setup-go
+	SetupMethodManual    = "manual"    // Installed by a run step, e.g. with nvm or a downloaded archive
+	SetupMethodContainer = "container" // The job container image ships the toolchain, e.g. golang:1.22
+)
+
+// ToolSetup is a language toolchain set up by a job
+type ToolSetup struct {
+	FilePath string `json:"file_path"`
+	Job      string `json:"job"`
+	Tool     string `json:"tool"`   // e.g. "go", "node", "python"
+	Method   string `json:"method"` // SetupMethodAction, SetupMethodManual, or SetupMethodContainer
+	Detail   string `json:"detail"` // The setup action, the matched install command, or the container image
+}
+
+// setupActions maps setup actions to the toolchain they install
+var setupActions = map[string]string{
+	"actions/setup-go":                "go",
+	"actions/setup-node":              "node",
+	"actions/setup-python":            "python",
+	"actions/setup-java":              "java",
+	"actions/setup-dotnet":            "dotnet",
+	"ruby/setup-ruby":                 "ruby",
+	"dtolnay/rust-toolchain":          "rust",
+	"actions-rs/toolchain":            "rust",
+	"shivammathur/setup-php":          "php",
+	"astral-sh/setup-uv":              "python",
+	"conda-incubator/setup-miniconda": "python",
+}
+
+// manualInstallMarkers are run step fragments that install a toolchain by hand, by tool
+var manualInstallMarkers = map[string][]string{
+	"go":     {"go.dev/dl/", "golang.org/dl/", "dl.google.com/go/", "gvm install", "install golang", "install -y golang"},
+	"node":   {"nvm install", "deb.nodesource.com", "nodejs.org/dist/", "install nodejs", "install -y nodejs", "fnm install", "volta install node"},
+	"python": {"pyenv install", "deadsnakes", "install python3", "install -y python3", "python.org/ftp/"},
+	"java":   {"sdk install java", "install openjdk", "install -y openjdk", "adoptium.net"},
+	"dotnet": {"dotnet-install.sh", "dotnet-install.ps1", "install dotnet", "install -y dotnet"},
+	"ruby":   {"rbenv install", "rvm install", "install ruby", "install -y ruby"},
+	"rust":   {"sh.rustup.rs", "rustup toolchain install", "rustup default", "rustup install"},
+	"php":    {"install php", "install -y php"},
+}
+
+// manualInstallTools are the tools of manualInstallMarkers, sorted so matches are reported in a stable order
+var manualInstallTools = func() []string {
+	tools := make([]string, 0, len(manualInstallMarkers))
+	for tool := range manualInstallMarkers {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+	return tools
+}()
+
+// toolImages maps official Docker Hub and Microsoft container images to the toolchain they ship
+var toolImages = map[string]string{
+	"golang":          "go",
+	"node":            "node",
+	"python":          "python",
+	"openjdk":         "java",
+	"eclipse-temurin": "java",
+	"amazoncorretto":  "java",
+	"maven":           "java",
+	"gradle":          "java",
+	"ruby":            "ruby",
+	"rust":            "rust",
+	"php":             "php",
+	"dotnet/sdk":      "dotnet",
+}
+
+// ParseToolSetups parses a workflow and returns the toolchains each job sets up, through setup actions,
+// install commands in run steps, or a toolchain container image
+func ParseToolSetups(content, filePath string, config *Config) ([]ToolSetup, error) {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	workflow, err := decodeWorkflow(content, config)
+	if err != nil {
+		return nil, err
+	}
+
+	jobNames := make([]string, 0, len(workflow.Jobs))
+	for jobName := range workflow.Jobs {
+		jobNames = append(jobNames, jobName)
+	}
+	sort.Strings(jobNames)
+
+	var setups []ToolSetup
+	for _, jobName := range jobNames {
+		job := workflow.Jobs[jobName]
+		seen := make(map[ToolSetup]bool)
+		add := func(tool, method, detail string) {
+			setup := ToolSetup{FilePath: filePath, Job: jobName, Tool: tool, Method: method, Detail: detail}
+			if !seen[setup] {
+				seen[setup] = true
+				setups = append(setups, setup)
+			}
+		}
+
+		if image, ok := containerImage(job.Container, filePath, jobName); ok {
+			if tool := imageTool(image); tool != "" {
+				add(tool, SetupMethodContainer, image.Image)
+			}
+		}
+
+		for _, step := range job.Steps {
+			if step.Uses != "" {
+				action, _, _ := strings.Cut(step.Uses, "@")
+				if tool, ok := setupActions[strings.ToLower(action)]; ok {
+					add(tool, SetupMethodAction, action)
+				}
+				continue
+			}
+			for _, tool := range manualInstallTools {
+				if marker := manualInstallMarker(step.Run, tool); marker != "" {
+					add(tool, SetupMethodManual, marker)
+				}
+			}
+		}
+	}
+
+	return setups, nil
+}
+
+// imageTool returns the toolchain of an official image, or "" for other images
+func imageTool(image ContainerImage) string {
+	switch image.Registry {
+	case DefaultImageRegistry:
+		return toolImages[strings.TrimPrefix(image.Repository, "library/")]
+	case "mcr.microsoft.com":
+		return toolImages[image.Repository]
+	default:
+		return ""
+	}
+}
+
+// manualInstallMarker returns the install command fragment of a tool found in a run script, or ""
+func manualInstallMarker(run, tool string) string {
+	script := strings.ToLower(strings.Join(strings.Fields(run), " "))
+	for _, marker := range manualInstallMarkers[tool] {
+		if strings.Contains(script, marker) {
+			return marker
+		}
+	}
+	return ""
+}
+
+// LanguageTool returns the toolchain of a GitHub repository language, or "" when none is tracked
+func LanguageTool(language string) string {
+	switch strings.ToLower(language) {
+	case "go":
+		return "go"
+	case "javascript", "typescript":
+		return "node"
+	case "python", "jupyter notebook":
+		return "python"
+	case "java", "kotlin", "scala", "groovy":
+		return "java"
+	case "c#", "f#", "visual basic .net":
+		return "dotnet"
+	case "ruby":
+		return "ruby"
+	case "rust":
+		return "rust"
+	case "php":
+		return "php"
+	default:
+		return ""
+	}
+}
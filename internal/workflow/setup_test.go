@@ -0,0 +1,72 @@
+package workflow
+
+import "testing"
+
+func TestParseToolSetups(t *testing.T) {
+	content := `
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - uses: actions/setup-go@v5
+        with:
+          go-version-file: go.mod
+      - uses: actions/setup-go@v5
+  legacy:
+    runs-on: ubuntu-latest
+    steps:
+      - run: |
+          curl -sSL https://go.dev/dl/go1.22.0.linux-amd64.tar.gz | sudo tar -C /usr/local -xz
+      - run: |
+          curl -o- https://raw.githubusercontent.com/nvm-sh/nvm/v0.39.7/install.sh | bash
+          nvm install 20
+  container:
+    runs-on: ubuntu-latest
+    container: golang:1.22
+    steps:
+      - run: go test ./...
+  other:
+    runs-on: ubuntu-latest
+    container: ghcr.io/my-org/golang:1.22
+    steps:
+      - run: make
+`
+	setups, err := ParseToolSetups(content, "ci.yml", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []ToolSetup{
+		{Job: "build", Tool: "go", Method: SetupMethodAction, Detail: "actions/setup-go"},
+		{Job: "container", Tool: "go", Method: SetupMethodContainer, Detail: "golang:1.22"},
+		{Job: "legacy", Tool: "go", Method: SetupMethodManual, Detail: "go.dev/dl/"},
+		{Job: "legacy", Tool: "node", Method: SetupMethodManual, Detail: "nvm install"},
+	}
+	if len(setups) != len(expected) {
+		t.Fatalf("Expected %d setups, got %d: %+v", len(expected), len(setups), setups)
+	}
+	for i, want := range expected {
+		want.FilePath = "ci.yml"
+		if setups[i] != want {
+			t.Errorf("Setup %d: expected %+v, got %+v", i, want, setups[i])
+		}
+	}
+}
+
+func TestLanguageTool(t *testing.T) {
+	tests := map[string]string{
+		"Go":         "go",
+		"TypeScript": "node",
+		"Kotlin":     "java",
+		"C#":         "dotnet",
+		"HCL":        "",
+		"":           "",
+	}
+	for language, expected := range tests {
+		if got := LanguageTool(language); got != expected {
+			t.Errorf("LanguageTool(%q): expected %q, got %q", language, expected, got)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationConfig selects the checks of ValidateWorkflowWithConfig
+type ValidationConfig struct {
+	// Simulate also resolves the job graph and evaluates expressions as a dry run would:
+	// needs must name existing jobs without cycles, no job may be left unreachable behind
+	// a job disabled with if: false, workflow_call outputs must read existing jobs,
+	// expressions must parse and only read known contexts, and needs, steps, and matrix
+	// references must resolve.
+	Simulate bool
+}
+
+// simulateJobs checks the job graph and the expressions of every job
+func (v *validator) simulateJobs(document, jobs *yaml.Node) {
+	for _, problem := range jobGraphProblems(document, jobs) {
+		v.problems = append(v.problems, fmt.Sprintf("line %d: %s", problem.Line, problem.Message))
+	}
+
+	jobIDs := make(map[string]bool)
+	for i := 0; i+1 < len(jobs.Content); i += 2 {
+		jobIDs[jobs.Content[i].Value] = true
+	}
+	for i := 0; i+1 < len(jobs.Content); i += 2 {
+		name, job := jobs.Content[i].Value, jobs.Content[i+1]
+		if job.Kind != yaml.MappingNode {
+			continue
+		}
+
+		// Needs of undefined jobs were reported with the graph
+		var needs []string
+		if node := mappingValue(job, "needs"); node != nil {
+			for _, need := range scalarList(node) {
+				if jobIDs[need.Value] {
+					needs = append(needs, need.Value)
+				}
+			}
+		}
+		v.simulateJob(name, job, needs)
+	}
+}
+
+// simulateJob checks the expressions of a job against the jobs it needs and the steps it defines
+func (v *validator) simulateJob(name string, job *yaml.Node, needs []string) {
+	needed := make(map[string]bool, len(needs))
+	for _, need := range needs {
+		needed[need] = true
+	}
+
+	stepIDs := make(map[string]bool)
+	if steps := mappingValue(job, "steps"); steps != nil && steps.Kind == yaml.SequenceNode {
+		for _, step := range steps.Content {
+			if id := mappingValue(step, "id"); id != nil {
+				stepIDs[id.Value] = true
+			}
+		}
+	}
+	hasMatrix := false
+	if strategy := mappingValue(job, "strategy"); strategy != nil && mappingValue(strategy, "matrix") != nil {
+		hasMatrix = true
+	}
+
+	v.walkExpressions(job, false, func(node *yaml.Node, references []ContextReference) {
+		for _, reference := range references {
+			switch {
+			case reference.Context == "needs" && reference.Property != "" && !needed[reference.Property]:
+				v.addf(node, "job %q reads needs.%s but does not need job %q", name, reference.Property, reference.Property)
+			case reference.Context == "steps" && reference.Property != "" && !stepIDs[reference.Property]:
+				v.addf(node, "job %q reads steps.%s but has no step with that id", name, reference.Property)
+			case reference.Context == "matrix" && !hasMatrix:
+				v.addf(node, "job %q reads matrix without a strategy.matrix", name)
+			}
+		}
+	})
+}
+
+// walkExpressions parses the expressions of every scalar below node, reporting syntax errors and passing
+// the context references of each scalar to visit. if conditions are expressions even without ${{ }}.
+func (v *validator) walkExpressions(node *yaml.Node, isCondition bool, visit func(*yaml.Node, []ContextReference)) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			v.walkExpressions(node.Content[i+1], node.Content[i].Value == "if", visit)
+		}
+	case yaml.SequenceNode:
+		for _, child := range node.Content {
+			v.walkExpressions(child, false, visit)
+		}
+	case yaml.ScalarNode:
+		expressions := ExtractExpressions(node.Value)
+		if isCondition && len(expressions) == 0 && strings.TrimSpace(node.Value) != "" {
+			expressions = []string{node.Value}
+		}
+
+		var references []ContextReference
+		for _, expression := range expressions {
+			found, err := ParseExpression(expression)
+			if err != nil {
+				v.addf(node, "invalid expression %q: %v", strings.TrimSpace(expression), err)
+				continue
+			}
+			references = append(references, found...)
+		}
+		if len(references) > 0 {
+			visit(node, references)
+		}
+	}
+}
+
+// scalarList returns the scalars of a string or list of strings
+func scalarList(node *yaml.Node) []*yaml.Node {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return []*yaml.Node{node}
+	case yaml.SequenceNode:
+		var scalars []*yaml.Node
+		for _, child := range node.Content {
+			if child.Kind == yaml.ScalarNode {
+				scalars = append(scalars, child)
+			}
+		}
+		return scalars
+	}
+	return nil
+}
+
+// findCycles returns each cycle in the needs graph once, starting from its first job in sorted order
+func findCycles(needs map[string][]string) [][]string {
+	names := make([]string, 0, len(needs))
+	for name := range needs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int)
+	var stack []string
+	var cycles [][]string
+
+	var visit func(string)
+	visit = func(name string) {
+		state[name] = visiting
+		stack = append(stack, name)
+		for _, need := range needs[name] {
+			switch state[need] {
+			case visiting:
+				// The cycle runs from the earlier occurrence of need on the stack back to it
+				for i, job := range stack {
+					if job == need {
+						cycles = append(cycles, append(append([]string(nil), stack[i:]...), need))
+						break
+					}
+				}
+			case unvisited:
+				visit(need)
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = done
+	}
+
+	for _, name := range names {
+		if state[name] == unvisited {
+			visit(name)
+		}
+	}
+	return cycles
+}
@@ -0,0 +1,45 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateWorkflowWithConfig_Simulate(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string // Substring of the only expected problem; empty for none
+	}{
+		{"valid graph", "on: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n    outputs:\n      version: ${{ steps.version.outputs.value }}\n    steps:\n      - id: version\n        run: echo value=1 >> $GITHUB_OUTPUT\n  deploy:\n    needs: build\n    if: needs.build.outputs.version != ''\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo ${{ needs.build.outputs.version }}\n", ""},
+		{"valid matrix", "on: push\njobs:\n  test:\n    runs-on: ${{ matrix.os }}\n    strategy:\n      matrix:\n        os: [ubuntu-latest]\n    steps:\n      - run: make\n", ""},
+		{"undefined need", "on: push\njobs:\n  deploy:\n    needs: [build]\n    runs-on: ubuntu-latest\n", `needs undefined job "build"`},
+		{"cycle", "on: push\njobs:\n  a:\n    needs: b\n    runs-on: ubuntu-latest\n  b:\n    needs: a\n    runs-on: ubuntu-latest\n", "jobs a -> b -> a need each other in a cycle"},
+		{"self need", "on: push\njobs:\n  a:\n    needs: a\n    runs-on: ubuntu-latest\n", "jobs a -> a need each other"},
+		{"needs not needed", "on: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n  deploy:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo ${{ needs.build.result }}\n", `reads needs.build but does not need job "build"`},
+		{"unknown step id", "on: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - if: steps.setup.outcome == 'success'\n        run: make\n", "reads steps.setup but has no step with that id"},
+		{"matrix without strategy", "on: push\njobs:\n  build:\n    runs-on: ${{ matrix.os }}\n", "reads matrix without a strategy.matrix"},
+		{"unreachable job", "on: push\njobs:\n  build:\n    if: ${{ false }}\n    runs-on: ubuntu-latest\n  deploy:\n    needs: build\n    runs-on: ubuntu-latest\n", `job "deploy" never runs: it needs job "build", which is disabled with if: false`},
+		{"runs after skipped job", "on: push\njobs:\n  build:\n    if: false\n    runs-on: ubuntu-latest\n  report:\n    needs: build\n    if: always()\n    runs-on: ubuntu-latest\n", ""},
+		{"output of renamed job", "on:\n  workflow_call:\n    outputs:\n      version:\n        value: ${{ jobs.build.outputs.version }}\njobs:\n  compile:\n    runs-on: ubuntu-latest\n", `output "version" reads jobs.build`},
+		{"invalid condition", "on: push\njobs:\n  build:\n    if: github.ref = 'main'\n    runs-on: ubuntu-latest\n", "invalid expression"},
+	}
+
+	for _, tt := range tests {
+		problems := ValidateWorkflowWithConfig(tt.content, &ValidationConfig{Simulate: true})
+		if tt.expected == "" {
+			if len(problems) != 0 {
+				t.Errorf("%s: expected no problems, got %v", tt.name, problems)
+			}
+			continue
+		}
+		if len(problems) != 1 || !strings.Contains(problems[0], tt.expected) {
+			t.Errorf("%s: expected one problem containing %q, got %v", tt.name, tt.expected, problems)
+		}
+
+		// Without simulation the same workflow passes the structural checks
+		if problems := ValidateWorkflow(tt.content); len(problems) != 0 {
+			t.Errorf("%s: expected no problems without simulation, got %v", tt.name, problems)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// JobSteps is the normalized step sequence of a single workflow job
+type JobSteps struct {
+	RepoFullName string   // Repository containing the workflow
+	FilePath     string   // Workflow file path
+	Job          string   // Job ID within the workflow
+	Steps        []string // Normalized steps, in order
+}
+
+// ExtractJobSteps parses a workflow and returns the normalized steps of each job
+// Normalization drops step names, action versions, and whitespace differences so that
+// jobs doing the same work compare equal across repositories. Jobs calling reusable
+// workflows have no steps and are omitted.
+func ExtractJobSteps(content, filePath, repoFullName string, config *Config) ([]JobSteps, error) {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	workflow, err := decodeWorkflow(content, config)
+	if err != nil {
+		return nil, err
+	}
+
+	jobNames := make([]string, 0, len(workflow.Jobs))
+	for jobName := range workflow.Jobs {
+		jobNames = append(jobNames, jobName)
+	}
+	sort.Strings(jobNames)
+
+	var jobs []JobSteps
+	for _, jobName := range jobNames {
+		var steps []string
+		for _, step := range workflow.Jobs[jobName].Steps {
+			if normalized := NormalizeStep(step); normalized != "" {
+				steps = append(steps, normalized)
+			}
+		}
+		if len(steps) == 0 {
+			continue
+		}
+
+		jobs = append(jobs, JobSteps{
+			RepoFullName: repoFullName,
+			FilePath:     filePath,
+			Job:          jobName,
+			Steps:        steps,
+		})
+	}
+
+	return jobs, nil
+}
+
+// NormalizeStep returns a canonical one-line description of a step, or "" for empty steps
+func NormalizeStep(step Step) string {
+	if step.Uses != "" {
+		uses := step.Uses
+		// Local actions and docker images keep their full reference; remote actions drop the version
+		if !strings.HasPrefix(uses, "./") && !strings.HasPrefix(uses, "docker://") {
+			if at := strings.LastIndex(uses, "@"); at > 0 {
+				uses = uses[:at]
+			}
+		}
+
+		normalized := "uses: " + uses
+		if with := normalizeWith(step.With); with != "" {
+			normalized += " with: " + with
+		}
+		return normalized
+	}
+
+	if run := strings.Join(strings.Fields(step.Run), " "); run != "" {
+		return "run: " + run
+	}
+
+	return ""
+}
+
+// normalizeWith renders step inputs as sorted key=value pairs
+func normalizeWith(with interface{}) string {
+	inputs, ok := with.(map[string]interface{})
+	if !ok || len(inputs) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(inputs))
+	for key := range inputs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value := strings.Join(strings.Fields(fmt.Sprint(inputs[key])), " ")
+		pairs = append(pairs, key+"="+value)
+	}
+	return strings.Join(pairs, ", ")
+}
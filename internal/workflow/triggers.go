@@ -0,0 +1,160 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TriggerInfo is the trigger inventory of a single workflow file
+type TriggerInfo struct {
+	FilePath           string   `json:"file_path"`
+	Name               string   `json:"name,omitempty"`
+	Events             []string `json:"events"`                          // Events listed under on:, sorted
+	Schedules          []string `json:"schedules,omitempty"`             // Cron expressions for schedule triggers
+	WorkflowRunSources []string `json:"workflow_run_sources,omitempty"`  // Workflows whose runs trigger this one
+	PRHeadCheckoutJobs []string `json:"pr_head_checkout_jobs,omitempty"` // Jobs checking out the pull request head
+}
+
+// prHeadRefMarkers identify checkout refs that point at untrusted pull request code
+var prHeadRefMarkers = []string{
+	"github.event.pull_request.head.sha",
+	"github.event.pull_request.head.ref",
+	"github.event.pull_request.merge_commit_sha",
+	"github.head_ref",
+	"refs/pull/",
+}
+
+// ParseTriggers parses a workflow's on: section into a trigger inventory
+func ParseTriggers(content, filePath string, config *Config) (*TriggerInfo, error) {
+	if config == nil {
+		config = &Config{Verbose: false}
+	}
+
+	workflow, err := decodeWorkflow(content, config)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &TriggerInfo{
+		FilePath: filePath,
+		Name:     workflow.Name,
+		Events:   []string{},
+	}
+
+	switch on := workflow.On.(type) {
+	case string:
+		info.Events = append(info.Events, on)
+	case []interface{}:
+		for _, event := range on {
+			if name, ok := event.(string); ok {
+				info.Events = append(info.Events, name)
+			}
+		}
+	case map[string]interface{}:
+		for event, settings := range on {
+			info.Events = append(info.Events, event)
+			switch event {
+			case "schedule":
+				info.Schedules = scheduleCrons(settings)
+			case "workflow_run":
+				info.WorkflowRunSources = stringList(settingValue(settings, "workflows"))
+			}
+		}
+	}
+	sort.Strings(info.Events)
+
+	if info.HasEvent("pull_request_target") {
+		info.PRHeadCheckoutJobs = prHeadCheckoutJobs(workflow)
+	}
+
+	return info, nil
+}
+
+// HasEvent reports whether the workflow is triggered by the given event
+func (t *TriggerInfo) HasEvent(event string) bool {
+	for _, e := range t.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// DisplayName returns the name GitHub shows for the workflow (its path when unnamed)
+func (t *TriggerInfo) DisplayName() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return t.FilePath
+}
+
+// prHeadCheckoutJobs returns the sorted jobs that check out the pull request head
+func prHeadCheckoutJobs(workflow Workflow) []string {
+	var jobs []string
+	for jobName, job := range workflow.Jobs {
+		for _, step := range job.Steps {
+			if !strings.HasPrefix(step.Uses, "actions/checkout@") {
+				continue
+			}
+			ref := fmt.Sprint(settingValue(step.With, "ref"))
+			if containsAny(ref, prHeadRefMarkers) {
+				jobs = append(jobs, jobName)
+				break
+			}
+		}
+	}
+	sort.Strings(jobs)
+	return jobs
+}
+
+// scheduleCrons extracts cron expressions from a schedule trigger
+func scheduleCrons(settings interface{}) []string {
+	entries, ok := settings.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var crons []string
+	for _, entry := range entries {
+		if cron, ok := settingValue(entry, "cron").(string); ok {
+			crons = append(crons, cron)
+		}
+	}
+	return crons
+}
+
+// settingValue returns a key from a YAML mapping, or nil
+func settingValue(settings interface{}, key string) interface{} {
+	if mapping, ok := settings.(map[string]interface{}); ok {
+		return mapping[key]
+	}
+	return nil
+}
+
+// stringList converts a YAML scalar or sequence into a list of strings
+func stringList(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// containsAny reports whether s contains any of the substrings
+func containsAny(s string, substrings []string) bool {
+	for _, substring := range substrings {
+		if strings.Contains(s, substring) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,234 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidWorkflow is returned when rewriting a workflow would leave it invalid
+var ErrInvalidWorkflow = errors.New("workflow is invalid")
+
+// ValidateWorkflow checks a workflow or action metadata file for problems that would stop GitHub
+// from running it, returning one message per problem. It checks that the YAML parses, mapping keys
+// are unique, jobs run on a runner or call a reusable workflow, steps either use an action or run
+// a command, uses references are well formed, and ${{ }} expressions are closed.
+func ValidateWorkflow(content string) []string {
+	return ValidateWorkflowWithConfig(content, nil)
+}
+
+// ValidateWorkflowWithConfig checks a workflow as ValidateWorkflow does, adding the dry run checks
+// of the job graph and expressions when config.Simulate is set
+func ValidateWorkflowWithConfig(content string, config *ValidationConfig) []string {
+	if config == nil {
+		config = &ValidationConfig{Simulate: false}
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &root); err != nil {
+		return []string{fmt.Sprintf("invalid YAML: %v", err)}
+	}
+	if root.Kind == 0 || len(root.Content) == 0 {
+		return nil // Empty document
+	}
+
+	v := &validator{}
+	v.checkNode(root.Content[0])
+
+	document := root.Content[0]
+	if document.Kind != yaml.MappingNode {
+		return append(v.problems, "document is not a mapping")
+	}
+
+	if jobs := mappingValue(document, "jobs"); jobs != nil {
+		v.checkWorkflow(document, jobs)
+		if config.Simulate && jobs.Kind == yaml.MappingNode {
+			v.simulateJobs(document, jobs)
+		}
+	} else if runs := mappingValue(document, "runs"); runs != nil {
+		v.checkAction(runs)
+	}
+
+	return v.problems
+}
+
+// NewProblems returns the problems of updated content that the original content did not have,
+// so rewrites are only blamed for what they broke
+func NewProblems(original, updated string) []string {
+	return NewProblemsWithConfig(original, updated, nil)
+}
+
+// NewProblemsWithConfig returns the problems updated content introduced under the given checks
+func NewProblemsWithConfig(original, updated string, config *ValidationConfig) []string {
+	existing := make(map[string]int)
+	for _, problem := range ValidateWorkflowWithConfig(original, config) {
+		existing[stripLine(problem)]++
+	}
+
+	var introduced []string
+	for _, problem := range ValidateWorkflowWithConfig(updated, config) {
+		key := stripLine(problem)
+		if existing[key] > 0 {
+			existing[key]--
+			continue
+		}
+		introduced = append(introduced, problem)
+	}
+	return introduced
+}
+
+// stripLine removes the "line N: " prefix of a problem, since rewrites can shift lines
+func stripLine(problem string) string {
+	if rest, found := strings.CutPrefix(problem, "line "); found {
+		if _, message, ok := strings.Cut(rest, ": "); ok {
+			return message
+		}
+	}
+	return problem
+}
+
+// validator collects the problems found in a document
+type validator struct {
+	problems []string
+}
+
+// addf records a problem found at a node
+func (v *validator) addf(node *yaml.Node, format string, args ...interface{}) {
+	v.problems = append(v.problems, fmt.Sprintf("line %d: ", node.Line)+fmt.Sprintf(format, args...))
+}
+
+// checkNode checks every node for duplicate keys and unclosed expressions
+func (v *validator) checkNode(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		seen := make(map[string]bool)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			if key.Value != "<<" && seen[key.Value] {
+				v.addf(key, "duplicate key %q", key.Value)
+			}
+			seen[key.Value] = true
+			v.checkNode(node.Content[i+1])
+		}
+	case yaml.SequenceNode, yaml.DocumentNode:
+		for _, child := range node.Content {
+			v.checkNode(child)
+		}
+	case yaml.ScalarNode:
+		if strings.Count(node.Value, "${{") > strings.Count(node.Value, "}}") {
+			v.addf(node, "unclosed ${{ }} expression")
+		}
+	}
+}
+
+// checkWorkflow checks the structure of a workflow file
+func (v *validator) checkWorkflow(document, jobs *yaml.Node) {
+	if mappingValue(document, "on") == nil {
+		v.addf(document, "missing \"on\" trigger")
+	}
+	if jobs.Kind != yaml.MappingNode {
+		v.addf(jobs, "jobs is not a mapping")
+		return
+	}
+
+	for i := 0; i+1 < len(jobs.Content); i += 2 {
+		name, job := jobs.Content[i].Value, jobs.Content[i+1]
+		if job.Kind != yaml.MappingNode {
+			v.addf(job, "job %q is not a mapping", name)
+			continue
+		}
+
+		uses := mappingValue(job, "uses")
+		if uses != nil {
+			v.checkUses(uses, fmt.Sprintf("job %q", name), true)
+		} else if mappingValue(job, "runs-on") == nil {
+			v.addf(job, "job %q has neither runs-on nor uses", name)
+		}
+		if steps := mappingValue(job, "steps"); steps != nil {
+			v.checkSteps(steps, fmt.Sprintf("job %q", name))
+		}
+	}
+}
+
+// checkAction checks the runs section of action metadata
+func (v *validator) checkAction(runs *yaml.Node) {
+	if runs.Kind != yaml.MappingNode {
+		v.addf(runs, "runs is not a mapping")
+		return
+	}
+	using := mappingValue(runs, "using")
+	if using == nil {
+		v.addf(runs, "runs has no using")
+		return
+	}
+	if steps := mappingValue(runs, "steps"); steps != nil && using.Value == "composite" {
+		v.checkSteps(steps, "composite action")
+	}
+}
+
+// checkSteps checks that each step either uses an action or runs a command
+func (v *validator) checkSteps(steps *yaml.Node, owner string) {
+	if steps.Kind != yaml.SequenceNode {
+		v.addf(steps, "steps of %s are not a list", owner)
+		return
+	}
+
+	for i, step := range steps.Content {
+		where := fmt.Sprintf("step %d of %s", i+1, owner)
+		if step.Kind != yaml.MappingNode {
+			v.addf(step, "%s is not a mapping", where)
+			continue
+		}
+
+		uses, run := mappingValue(step, "uses"), mappingValue(step, "run")
+		switch {
+		case uses != nil && run != nil:
+			v.addf(step, "%s has both uses and run", where)
+		case uses == nil && run == nil:
+			v.addf(step, "%s has neither uses nor run", where)
+		case uses != nil:
+			v.checkUses(uses, where, false)
+		}
+	}
+}
+
+// checkUses checks that a uses value is a local path, a Docker image, or owner/repo[/path]@ref
+func (v *validator) checkUses(uses *yaml.Node, where string, reusable bool) {
+	value := uses.Value
+	if uses.Kind != yaml.ScalarNode || value == "" {
+		v.addf(uses, "%s has an empty uses", where)
+		return
+	}
+	if strings.HasPrefix(value, "./") || (!reusable && strings.HasPrefix(value, "docker://")) {
+		return
+	}
+
+	reference, ref, found := strings.Cut(value, "@")
+	if !found || ref == "" || strings.ContainsAny(ref, " \t") {
+		v.addf(uses, "%s uses %q without a version after @", where, value)
+		return
+	}
+	parts := strings.Split(reference, "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		v.addf(uses, "%s uses %q, which is not owner/repo@ref", where, value)
+		return
+	}
+	if reusable && len(parts) < 3 {
+		v.addf(uses, "%s uses %q, which names no workflow file", where, value)
+	}
+}
+
+// mappingValue returns the value of a key in a mapping node, or nil if it is absent
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
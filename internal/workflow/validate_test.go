@@ -0,0 +1,56 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateWorkflow(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string // Substring of the only expected problem; empty for none
+	}{
+		{"valid workflow", "on: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - uses: actions/checkout@v4\n      - run: make\n      - uses: ./local\n      - uses: docker://alpine:3\n", ""},
+		{"valid reusable call", "on: push\njobs:\n  call:\n    uses: my-org/workflows/.github/workflows/ci.yml@v1\n", ""},
+		{"valid composite action", "name: x\nruns:\n  using: composite\n  steps:\n    - run: echo ${{ inputs.name }}\n      shell: bash\n", ""},
+		{"invalid YAML", "on: push\njobs:\n  build: [\n", "invalid YAML"},
+		{"duplicate key", "on: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n    runs-on: windows-latest\n", `duplicate key "runs-on"`},
+		{"missing trigger", "jobs:\n  build:\n    runs-on: ubuntu-latest\n", `missing "on" trigger`},
+		{"job without runner", "on: push\njobs:\n  build:\n    steps:\n      - run: make\n", "neither runs-on nor uses"},
+		{"step with uses and run", "on: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - uses: actions/checkout@v4\n        run: make\n", "both uses and run"},
+		{"uses without version", "on: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - uses: actions/checkout@\n", "without a version"},
+		{"uses without owner", "on: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - uses: checkout@v4\n", "not owner/repo@ref"},
+		{"reusable call without file", "on: push\njobs:\n  call:\n    uses: my-org/workflows@v1\n", "names no workflow file"},
+		{"unclosed expression", "on: push\njobs:\n  build:\n    runs-on: ${{ matrix.os\n", "unclosed"},
+		{"composite action without using", "runs:\n  steps: []\n", "runs has no using"},
+	}
+
+	for _, tt := range tests {
+		problems := ValidateWorkflow(tt.content)
+		if tt.expected == "" {
+			if len(problems) != 0 {
+				t.Errorf("%s: expected no problems, got %v", tt.name, problems)
+			}
+			continue
+		}
+		if len(problems) != 1 || !strings.Contains(problems[0], tt.expected) {
+			t.Errorf("%s: expected one problem containing %q, got %v", tt.name, tt.expected, problems)
+		}
+	}
+}
+
+func TestNewProblems(t *testing.T) {
+	original := "on: push\njobs:\n  build:\n    steps:\n      - uses: actions/checkout@v3\n"
+	updated := "# comment shifting lines\non: push\njobs:\n  build:\n    steps:\n      - uses: actions/checkout@\n"
+
+	// The missing runs-on was already there, so only the broken uses is new
+	problems := NewProblems(original, updated)
+	if len(problems) != 1 || !strings.Contains(problems[0], "without a version") {
+		t.Errorf("Expected only the broken uses to be new, got %v", problems)
+	}
+
+	if problems := NewProblems(original, original); len(problems) != 0 {
+		t.Errorf("Expected no new problems for unchanged content, got %v", problems)
+	}
+}
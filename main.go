@@ -1,22 +1,82 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"net/http"
 	"os"
+	"path/filepath"
 	"regexp"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/tucnak/climax"
 
 	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/actions"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/apply"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/approvals"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/audit"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/badge"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/baseline"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/billing"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/budget"
 	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/cache"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/calls"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/canary"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/cleanup"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/completion"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/compress"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/consumers"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/digest"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/dispatch"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/downgrade"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/duplicates"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/encrypt"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/explain"
 	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/github"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/golden"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/hooks"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/hygiene"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/images"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/jira"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/ledger"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/links"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/lockfile"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/logcapture"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/majortags"
 	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/output"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/patcher"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/patchtest"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/pipeline"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/planfile"
 	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/pr"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/priority"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/registry"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/release"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/scanopts"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/secretflow"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/selfupdate"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/server"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/snapshot"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/suggest"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/suppress"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/tagprotection"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/triggers"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/usage"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/verify"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/waves"
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/wizard"
 	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/workflow"
 )
 
@@ -32,11 +92,100 @@ func getVersion() string {
 	return "dev"
 }
 
+// logRedactor masks tokens in log output; commands add the --token they were given
+var logRedactor = github.NewRedactingWriter(os.Stderr)
+
 func main() {
+	logRedactor.AddSecret(os.Getenv("GITHUB_TOKEN"))
+	log.SetOutput(logRedactor)
+
 	cli := climax.New("actions-maintainer")
 	cli.Brief = "GitHub Actions maintenance tool"
 	cli.Version = getVersion()
 
+	// Network flags shared by commands that talk to the GitHub API
+	networkFlags := []climax.Flag{
+		{
+			Name:     "proxy",
+			Short:    "x",
+			Usage:    `--proxy <url>`,
+			Help:     `HTTP(S) proxy URL for GitHub API requests (default: HTTPS_PROXY/HTTP_PROXY/NO_PROXY environment variables)`,
+			Variable: true,
+		},
+		{
+			Name:     "ca-bundle",
+			Short:    "C",
+			Usage:    `--ca-bundle <file>`,
+			Help:     `PEM file of additional CA certificates to trust (e.g., a corporate TLS-inspecting proxy)`,
+			Variable: true,
+		},
+		{
+			Name:     "insecure-skip-verify",
+			Short:    "k",
+			Usage:    `--insecure-skip-verify`,
+			Help:     `Disable TLS certificate verification. Insecure; use only for testing`,
+			Variable: false,
+		},
+		{
+			Name:     "timeout",
+			Usage:    `--timeout <duration>`,
+			Help:     `Timeout for each GitHub API request (e.g., "30s", "2m"; default: 60s)`,
+			Variable: true,
+		},
+		{
+			Name:     "user-agent-suffix",
+			Usage:    `--user-agent-suffix <text>`,
+			Help:     `Text appended to the User-Agent of every GitHub API request (e.g., a team or pipeline name), after the tool version and run ID`,
+			Variable: true,
+		},
+	}
+
+	// Host flag shared by commands that talk to the GitHub API, for GitHub Enterprise Server
+	hostFlags := []climax.Flag{
+		{
+			Name:     "api-url",
+			Usage:    `--api-url <url>`,
+			Help:     `GitHub Enterprise Server API URL, e.g. https://ghe.example.com/api/v3 (default: github.com)`,
+			Variable: true,
+		},
+	}
+
+	// Decryption flag shared by commands that read scan results
+	decryptFlags := []climax.Flag{
+		{
+			Name:     "decrypt-identity",
+			Usage:    `--decrypt-identity <file>`,
+			Help:     `age identity file used to decrypt scan results written with --encrypt-recipient (requires the age CLI)`,
+			Variable: true,
+		},
+	}
+
+	// Audit log flag shared by commands that change repositories
+	auditFlags := []climax.Flag{
+		{
+			Name:     "audit-log",
+			Usage:    `--audit-log <file|url>`,
+			Help:     `Append every branch created, file modified, pull request opened or updated, and branch deleted to this file as JSON lines, or POST each event to this http(s) URL`,
+			Variable: true,
+		},
+	}
+
+	// GitHub App flags of commands that write to repositories
+	appFlags := []climax.Flag{
+		{
+			Name:     "app-id",
+			Usage:    `--app-id <id>`,
+			Help:     `Authenticate as this GitHub App instead of with a token (or set GITHUB_APP_ID env var). Each repository's requests use an installation token minted for only that repository`,
+			Variable: true,
+		},
+		{
+			Name:     "app-private-key",
+			Usage:    `--app-private-key <file>`,
+			Help:     `PEM private key file of the --app-id GitHub App (or set GITHUB_APP_PRIVATE_KEY_FILE env var)`,
+			Variable: true,
+		},
+	}
+
 	// Main scan command
 	scanCmd := climax.Command{
 		Name:  "scan",
@@ -55,21 +204,50 @@ func main() {
 				Name:     "output",
 				Short:    "O",
 				Usage:    `--output <file>`,
-				Help:     `Output file for scan results. Use .json extension for JSON format or .ipynb for Jupyter notebook (default: JSON to stdout)`,
+				Help:     `Output file for scan results, repeatable or comma-separated to write several formats in one run. Format follows the extension: .json for JSON, .ipynb for Jupyter notebook, .sarif for SARIF, .parquet for the flattened action inventory as Parquet (default: stdout, see --format)`,
+				Variable: true,
+			},
+			{
+				Name:     "format",
+				Usage:    `--format <format>`,
+				Help:     `Format of results written to stdout when no --output file is given: table for a summary of repositories, severities, and top actions, json for the full results, or problems for one file:line:col: severity: message line per issue (default: table)`,
+				Variable: true,
+			},
+			{
+				Name: "no-color",
+				Help: `Disable colored severities in terminal output. Color is also off when stdout is not a terminal or NO_COLOR is set`,
+			},
+			{
+				Name:     "encrypt-recipient",
+				Short:    "e",
+				Usage:    `--encrypt-recipient <recipients>`,
+				Help:     `Encrypt the output with age to comma-separated recipients: age public keys, SSH public keys, plugin recipients (e.g., KMS-backed), or recipients files (requires the age CLI)`,
+				Variable: true,
+			},
+			{
+				Name:     "split-output-by",
+				Usage:    `--split-output-by <repo-count=N|owner>`,
+				Help:     `Split JSON output into chunk files of at most N repositories, or one per repository owner. --output is written as an index listing the chunks with the summary of the whole scan`,
 				Variable: true,
 			},
 			{
 				Name:     "token",
 				Short:    "t",
 				Usage:    `--token <token>`,
-				Help:     `GitHub personal access token (or set GITHUB_TOKEN env var)`,
+				Help:     `GitHub personal access token (or set GITHUB_TOKEN env var). Optional: without one, public repositories are scanned anonymously at a low rate limit`,
 				Variable: true,
 			},
 			{
 				Name:     "cache",
 				Short:    "c",
 				Usage:    `--cache <provider>`,
-				Help:     `Cache provider to use (default: memory)`,
+				Help:     `Version cache provider: memory, or file to keep resolved versions for later runs (default: memory)`,
+				Variable: true,
+			},
+			{
+				Name:     "cache-file",
+				Usage:    `--cache-file <file>`,
+				Help:     `File of the file cache provider (default: versions.json in the user cache directory); implies --cache file`,
 				Variable: true,
 			},
 			{
@@ -79,6 +257,18 @@ func main() {
 				Help:     `Skip version alias resolution and use string matching only`,
 				Variable: false,
 			},
+			{
+				Name:     "max-tag-pages",
+				Usage:    `--max-tag-pages <pages>`,
+				Help:     `List at most this many pages of 100 tags per action, keeping the highest tag names, so actions with thousands of tags don't use up the rate limit (default: every page)`,
+				Variable: true,
+			},
+			{
+				Name:     "comprehensive-cache",
+				Usage:    `--comprehensive-cache <repos>`,
+				Help:     `Comma-separated action repositories, such as my-org/* or *, whose every tag is listed to find version aliases and cache all their versions; others look aliases up among the tags of the same major version`,
+				Variable: true,
+			},
 			{
 				Name:     "filter",
 				Short:    "r",
@@ -100,6 +290,24 @@ func main() {
 				Help:     `Path to custom rules file (JSON format). Rules will be merged with defaults. Supports version rules and repository migrations`,
 				Variable: true,
 			},
+			{
+				Name:     "resolve-latest",
+				Usage:    `--resolve-latest`,
+				Help:     `Look up the latest version of each version rule from its action's tags or releases, as its version_source says; latest_version is kept when the lookup fails`,
+				Variable: false,
+			},
+			{
+				Name:     "record",
+				Usage:    `--record <dir>`,
+				Help:     `Save every GitHub API response of the scan to a fixture directory, for replay with --replay`,
+				Variable: true,
+			},
+			{
+				Name:     "replay",
+				Usage:    `--replay <dir>`,
+				Help:     `Answer GitHub API requests from a fixture directory saved with --record, without a token or network access`,
+				Variable: true,
+			},
 			{
 				Name:     "custom-property",
 				Short:    "P",
@@ -107,17 +315,401 @@ func main() {
 				Help:     `Custom repository property to include in the report (e.g., "ProductId"). Can be specified multiple times for multiple properties`,
 				Variable: true,
 			},
+			{
+				Name:     "suppressions-file",
+				Short:    "S",
+				Usage:    `--suppressions-file <file>`,
+				Help:     `Path to suppressions file (JSON format). Matching issues are snoozed until a date or until the action version changes, and listed separately in the report`,
+				Variable: true,
+			},
+			{
+				Name:     "workflow-dirs",
+				Short:    "D",
+				Usage:    `--workflow-dirs <dirs>`,
+				Help:     `Comma-separated workflow directories or globs to scan, searched recursively (e.g., ".github/workflows,.gitea/workflows,services/*/.github/workflows"). Default: .github/workflows`,
+				Variable: true,
+			},
+			{
+				Name:     "skip-workflow-templates",
+				Usage:    `--skip-workflow-templates`,
+				Help:     `Do not scan the organization's workflow templates (workflow-templates/ in the .github repository)`,
+				Variable: false,
+			},
+			{
+				Name:     "workflow-filter",
+				Usage:    `--workflow-filter <globs>`,
+				Help:     `Comma-separated globs of workflow files to scan; files not matching are skipped (e.g., "release*.yml,deploy/*.yml"). Globs without a "/" match the file name, others the full path`,
+				Variable: true,
+			},
+			{
+				Name:     "exclude-workflow",
+				Usage:    `--exclude-workflow <globs>`,
+				Help:     `Comma-separated globs of workflow files to skip, even when they match --workflow-filter (e.g., "*-experimental.yml,.github/workflows/legacy/*")`,
+				Variable: true,
+			},
+			{
+				Name:     "report-template-dir",
+				Short:    "T",
+				Usage:    `--report-template-dir <dir>`,
+				Help:     `Directory of Go templates (<section>.md.tmpl) overriding notebook report sections for custom branding. Sections: header, summary, issues-overview, priorities, repository-details, suppressed-issues, reusable-workflows, tag-protection, internal-actions, workflow-usage, actions-minutes, secret-flows, container-images, environments, setup-consistency, pr-links, detailed-stats, footer`,
+				Variable: true,
+			},
+			{
+				Name:     "links",
+				Usage:    `--links <file>`,
+				Help:     `JSON object of named URL templates over a repository's .Owner, .Name, .FullName, .Host, and custom .Properties, e.g. {"backstage": "https://backstage.example.com/catalog/default/component/{{.Name}}"}. Report and description templates build them with {{link "backstage" .}}`,
+				Variable: true,
+			},
+			{
+				Name:     "profile",
+				Short:    "p",
+				Usage:    `--profile <prefix>`,
+				Help:     `Write pprof CPU and heap profiles to <prefix>.cpu.pprof and <prefix>.heap.pprof for performance analysis`,
+				Variable: true,
+			},
+			{
+				Name:     "max-workflow-size",
+				Short:    "m",
+				Usage:    `--max-workflow-size <bytes>`,
+				Help:     `Skip workflow files larger than this many bytes, recording them as "skipped-too-large" (default: 1048576)`,
+				Variable: true,
+			},
+			{
+				Name:     "pin-age",
+				Short:    "A",
+				Usage:    `--pin-age`,
+				Help:     `Enrich outdated issues with release dates of the current and suggested versions, pin age, and days behind (extra API calls, cached)`,
+				Variable: false,
+			},
+			{
+				Name:     "patch-preview",
+				Usage:    `--patch-preview`,
+				Help:     `Embed the concrete patch of each upgrade with schema transformations in its issue: the inputs added, removed, renamed, and modified, with the before and after with: blocks`,
+				Variable: false,
+			},
+			{
+				Name:     "action-checks",
+				Usage:    `--action-checks <checks>`,
+				Help:     `Comma-separated action checks to run: comment-drift, banned-action, outdated, deprecated, migration, missing-required-action, brownout, broken-reference, or all (default: all)`,
+				Variable: true,
+			},
+			{
+				Name:     "disable-default-rules",
+				Usage:    `--disable-default-rules <repos>`,
+				Help:     `Comma-separated action repositories whose built-in rules are turned off, keeping the others (e.g., "actions/cache"). The built-in rules are the brownout calendar`,
+				Variable: true,
+			},
+			{
+				Name:     "hygiene-checks",
+				Usage:    `--hygiene-checks <checks>`,
+				Help:     `Comma-separated workflow hygiene checks raising low-severity issues: missing-timeout (jobs without timeout-minutes), continue-on-error (jobs with continue-on-error: true), fail-fast-disabled (every job sets strategy.fail-fast: false), missing-concurrency (deploy workflows without a concurrency group), missing-cancel-in-progress (pull request workflows without cancel-in-progress), cache-key (actions/cache keys that are static, miss hashFiles, or omit the OS), job-graph (needs naming missing jobs, cycles, jobs unreachable behind if: false, or workflow_call outputs of missing jobs; medium or high severity), or all`,
+				Variable: true,
+			},
+			{
+				Name:     "release-checks",
+				Usage:    `--release-checks <checks>`,
+				Help:     `Comma-separated release pipeline checks: deprecated-release-action (archived actions/create-release and actions/upload-release-asset), release-pat (gh release run with a personal access token), missing-provenance (workflows publishing releases without actions/attest-build-provenance or the SLSA generator), or all`,
+				Variable: true,
+			},
+			{
+				Name:     "check-deprecation-notices",
+				Usage:    `--check-deprecation-notices`,
+				Help:     `Check each action repository for a deprecation notice (archived, description, or README notice or badge), adding it to issue descriptions and flagging deprecated actions no rule covers (extra API calls per action)`,
+				Variable: false,
+			},
+			{
+				Name:     "detect-duplicates",
+				Short:    "d",
+				Usage:    `--detect-duplicates`,
+				Help:     `Detect job step sequences repeated across 3 or more repositories and recommend extracting them into reusable workflows`,
+				Variable: false,
+			},
+			{
+				Name:     "github-annotations",
+				Usage:    `--github-annotations`,
+				Help:     `When running in GitHub Actions, annotate issues in the current repository (GITHUB_REPOSITORY) on their workflow lines and append a job summary to GITHUB_STEP_SUMMARY`,
+				Variable: false,
+			},
+			{
+				Name:     "summary-file",
+				Usage:    `--summary-file <file>`,
+				Help:     `Write a JSON summary of the outcome (issue counts by severity and type, --fail-on gate, exit code) to this file for CI pipelines. In GitHub Actions it defaults to $RUNNER_TEMP/actions-maintainer-summary.json, and the counts are also set as step outputs through GITHUB_OUTPUT`,
+				Variable: true,
+			},
+			{
+				Name:     "check-tag-protection",
+				Usage:    `--check-tag-protection <repos>`,
+				Help:     `Check that internal actions used by at least <repos> scanned repositories protect their consumed release tags with rulesets, reporting unprotected tags as governance findings (extra API calls per action)`,
+				Variable: true,
+			},
+			{
+				Name:     "check-calls",
+				Usage:    `--check-calls`,
+				Help:     `Check that jobs calling reusable workflows pass the secrets the workflows require and no secrets they do not declare, reporting mismatches as broken-call issues (one file read per called workflow)`,
+				Variable: false,
+			},
+			{
+				Name:     "check-dispatch-defaults",
+				Usage:    `--check-dispatch-defaults`,
+				Help:     `Check the defaults of workflow_dispatch inputs against the built-in policy, reporting inputs that default to production or to a destructive mode as risky-dispatch-default issues`,
+				Variable: false,
+			},
+			{
+				Name:     "dispatch-policy",
+				Usage:    `--dispatch-policy <file>`,
+				Help:     `JSON policy of risky workflow_dispatch input defaults, replacing the built-in policy (implies --check-dispatch-defaults)`,
+				Variable: true,
+			},
+			{
+				Name:     "check-major-tags",
+				Usage:    `--check-major-tags`,
+				Help:     `Check that the major tags of internal actions, such as v2, point at the newest release of their major version, reporting lagging tags as producer-side findings`,
+				Variable: false,
+			},
+			{
+				Name:     "internal-actions",
+				Usage:    `--internal-actions <repos>`,
+				Help:     `Report each action hosted by the scanned owner that at least <repos> scanned repositories use: its consumers, the versions they use, how many are on an older major version than the latest release, and the open issues, open pull requests, and latest release of the action repository (extra API calls per action)`,
+				Variable: true,
+			},
+			{
+				Name:     "baseline",
+				Short:    "b",
+				Usage:    `--baseline <file>`,
+				Help:     `Previous scan JSON results. Issues already present are marked as existing, excluded from --fail-on, and skipped by create-pr`,
+				Variable: true,
+			},
+			{
+				Name:     "team-property",
+				Usage:    `--team-property <property>`,
+				Help:     `Custom property naming each repository's team. Looks up the pull requests recorded in the --baseline scan and the --ledger file and reports the median time-to-merge per team in the summary and its history`,
+				Variable: true,
+			},
+			{
+				Name:     "ledger",
+				Usage:    `--ledger <file>`,
+				Help:     `Ledger file passed to create-pr --ledger, listing the pull requests measured by --team-property`,
+				Variable: true,
+			},
+			{
+				Name:     "priority-weights",
+				Usage:    `--priority-weights <file>`,
+				Help:     `JSON file weighting the factors of each issue's priority score: severity, popularity, deploy, trigger, and version_distance (default 40, 15, 20, 10, 15). Omitted factors keep their default weight`,
+				Variable: true,
+			},
+			{
+				Name:     "docs-base-url",
+				Usage:    `--docs-base-url <url>`,
+				Help:     `Page documenting each rule, with a section per issue type, linked from issues, SARIF rules, and PR bodies (default: the actions-maintainer rules documentation)`,
+				Variable: true,
+			},
+			{
+				Name:     "workflow-golden-set",
+				Usage:    `--workflow-golden-set <file>`,
+				Help:     `JSON file of the approved workflows of each class of repositories, chosen by custom property or conditions. Reports repositories missing required workflows or carrying unapproved ones`,
+				Variable: true,
+			},
+			{
+				Name:     "severity-policy",
+				Usage:    `--severity-policy <file>`,
+				Help:     `JSON policy lowering the severity of issues, or excluding issue types, in repositories chosen by custom property, name pattern, or topic, such as development and test repositories, so reports and --fail-on focus on production`,
+				Variable: true,
+			},
+			{
+				Name:     "write-locks",
+				Usage:    `--write-locks <dir>`,
+				Help:     `Write a lockfile per repository to <dir>/<owner>/<repo>/actions.lock, recording the commit and tag each action ref resolves to`,
+				Variable: true,
+			},
+			{
+				Name:     "verify-locks",
+				Usage:    `--verify-locks <dir>`,
+				Help:     `Compare actions with the lockfiles in <dir>, reporting refs that resolve to other commits than locked and actions missing from or no longer in the lock as lock-drift issues`,
+				Variable: true,
+			},
+			{
+				Name:     "description-templates",
+				Usage:    `--description-templates <file>`,
+				Help:     `JSON file of Go templates, keyed by issue type, rule id, or "*", that rewrite issue descriptions in results, reports, and PRs`,
+				Variable: true,
+			},
+			{
+				Name:     "fail-on",
+				Short:    "f",
+				Usage:    `--fail-on <severity>`,
+				Help:     `Exit with code 2 when new issues at or above this severity (low, medium, high, critical) are found`,
+				Variable: true,
+			},
+			{
+				Name:     "debt-targets",
+				Usage:    `--debt-targets <file>`,
+				Help:     `JSON file of severity weights for the actions debt score, the severity-weighted issues per repository in the summary, and of dated targets it is measured against, e.g. one per quarter`,
+				Variable: true,
+			},
+			{
+				Name:     "fail-on-debt",
+				Usage:    `--fail-on-debt`,
+				Help:     `Exit with code 2 when the actions debt score is above the current target of --debt-targets`,
+				Variable: false,
+			},
+			{
+				Name:     "max-duration",
+				Usage:    `--max-duration <duration>`,
+				Help:     `Stop scanning new repositories once the scan has run this long, e.g. 30m. Remaining repositories are recorded as "over-budget" and the scan exits with code 3`,
+				Variable: true,
+			},
+			{
+				Name:     "max-api-calls",
+				Usage:    `--max-api-calls <n>`,
+				Help:     `Stop scanning new repositories once this many GitHub API calls were made. Remaining repositories are recorded as "over-budget" and the scan exits with code 3`,
+				Variable: true,
+			},
+			{
+				Name:     "max-failures",
+				Usage:    `--max-failures <n|n%>`,
+				Help:     `Abort the scan without writing results once more than this many repositories, or this percentage of them, failed to fetch or parse (e.g., 5 or 2%). Failures within the budget are recorded in the results`,
+				Variable: true,
+			},
+			{
+				Name:     "repos-snapshot",
+				Usage:    `--repos-snapshot <file>`,
+				Help:     `Save the owner's repository list to this file and reuse it on later scans instead of listing repositories again, until it is older than --repos-snapshot-ttl. Speeds up repeated scans of large organizations while tuning rules`,
+				Variable: true,
+			},
+			{
+				Name:     "repos-snapshot-ttl",
+				Usage:    `--repos-snapshot-ttl <duration>`,
+				Help:     `Age after which the --repos-snapshot file is refreshed from GitHub, e.g. 6h (default: 24h)`,
+				Variable: true,
+			},
+			{
+				Name:     "skip-listing-check",
+				Usage:    `--skip-listing-check`,
+				Help:     `Scan the repositories listed even when they are fewer than GitHub reports the owner has, e.g. for tokens limited to some repositories. By default an incomplete listing is retried once, then fails the scan`,
+				Variable: false,
+			},
+			{
+				Name:     "token-repos",
+				Usage:    `--token-repos`,
+				Help:     `Scan exactly the owner's repositories the token can access, listed through /user/repos, or /installation/repositories for GitHub App installation tokens, instead of every repository of the owner. For fine-grained tokens and apps granted selected repositories`,
+				Variable: false,
+			},
+			{
+				Name:     "estimate",
+				Usage:    `--estimate`,
+				Help:     `List the repositories, then print the predicted GitHub API calls and duration of the scan against the token's rate limit and exit without scanning`,
+				Variable: false,
+			},
+			{
+				Name:     "order-by",
+				Usage:    `--order-by <pushed|issues|property:<name>[=<values>]>`,
+				Help:     `Scan the most important repositories first: most recently pushed, most issues in the --baseline scan, or by a custom property, ascending or in the listed value order (default: listed order)`,
+				Variable: true,
+			},
+			{
+				Name:     "max-repos",
+				Usage:    `--max-repos <n>`,
+				Help:     `Scan only the first <n> repositories matching the filters, in --order-by order, for quick trial runs of new rules on large organizations`,
+				Variable: true,
+			},
+			{
+				Name:     "sample",
+				Usage:    `--sample`,
+				Help:     `With --max-repos, scan a random sample of the matching repositories instead of the first ones`,
+				Variable: false,
+			},
+			{
+				Name:     "sample-seed",
+				Usage:    `--sample-seed <n>`,
+				Help:     `Seed of the --sample random sample, to scan the same sample again (default: random, printed with the sample)`,
+				Variable: true,
+			},
+			{
+				Name:     "workflow-usage",
+				Short:    "U",
+				Usage:    `--workflow-usage <days>`,
+				Help:     `Query workflow run history for the past <days> days, recording runs per month for each workflow file and flagging workflows that did not run as "stale-workflow" issues (extra API calls per workflow file)`,
+				Variable: true,
+			},
+			{
+				Name:     "estimate-minutes",
+				Usage:    `--estimate-minutes <days>`,
+				Help:     `Estimate monthly GitHub Actions minutes per workflow file from the billable time of its runs in the past <days> days, and list the top consumers in reports (extra API calls per workflow file and sampled run)`,
+				Variable: true,
+			},
+			{
+				Name:     "check-images",
+				Usage:    `--check-images <days>`,
+				Help:     `Look up job container, service, and docker:// step images in their registries, flagging tags that no longer exist as "missing-image-tag", images built more than <days> days ago as "stale-image", and images not published for the architecture of the job's runner as "image-architecture" (public images only; extra registry requests per image)`,
+				Variable: true,
+			},
+			{
+				Name:     "map-secrets",
+				Usage:    `--map-secrets`,
+				Help:     `Map the secrets and variables each workflow passes to actions through with:, env:, and secrets:, resolving whether they are repository or organization values (needs the Secrets and Variables read permissions), and report those reaching third-party actions`,
+				Variable: false,
+			},
+			{
+				Name:     "capture-logs",
+				Short:    "L",
+				Usage:    `--capture-logs`,
+				Help:     `Record log output and warnings for each repository in its scan result ("logs"), so failures can be debugged from the results file. Combine with --verbose for full detail`,
+				Variable: false,
+			},
+			{
+				Name:     "explain-all",
+				Usage:    `--explain-all`,
+				Help:     `Record for each action reference which rule applied and why each issue was or was not raised, reported, or suppressed, in a "decisions" log in each repository's scan result. Workflow files skipped by --workflow-filter or --exclude-workflow are recorded too`,
+				Variable: false,
+			},
+			{
+				Name:     "hook-command",
+				Short:    "H",
+				Usage:    `--hook-command <command>`,
+				Help:     `Shell command run once per issue with the issue JSON on stdin (e.g., to open tickets). Failures are reported as warnings`,
+				Variable: true,
+			},
+			{
+				Name:     "hook-url",
+				Short:    "W",
+				Usage:    `--hook-url <url>`,
+				Help:     `Webhook the JSON of each issue is POSTed to`,
+				Variable: true,
+			},
+			{
+				Name:     "registry-url",
+				Short:    "u",
+				Usage:    `--registry-url <url>`,
+				Help:     `Internal approved-actions registry (JSON over HTTP) to pull latest, minimum, and banned versions from as rules. Rules file entries take precedence. Responses are cached with ETags`,
+				Variable: true,
+			},
+			{
+				Name:     "registry-mapping",
+				Short:    "M",
+				Usage:    `--registry-mapping <file>`,
+				Help:     `JSON file mapping registry entry fields to rule fields (entries_path, repository, latest_version, minimum_version, banned_versions, recommendation)`,
+				Variable: true,
+			},
+			{
+				Name:     "registry-token",
+				Usage:    `--registry-token <token>`,
+				Help:     `Bearer token for the registry (or set ACTIONS_REGISTRY_TOKEN env var)`,
+				Variable: true,
+			},
 		},
 		Handle: handleScan,
 	}
 
+	scanCmd.Flags = append(scanCmd.Flags, networkFlags...)
+	scanCmd.Flags = append(scanCmd.Flags, hostFlags...)
+	scanCmd.Flags = append(scanCmd.Flags, decryptFlags...)
 	cli.AddCommand(scanCmd)
 
 	// Report command
 	reportCmd := climax.Command{
 		Name:  "report",
 		Brief: "Generate formatted reports from scan JSON results",
-		Usage: `report [--input <file>] [--output <file>] [--format <format>]`,
+		Usage: `report [--input <file> | --merge <file>...] [--output <file>] [--format <format>]`,
 		Help:  `Generates formatted reports from JSON scan results. Input can be a file or stdin. Supports JSON and Jupyter notebook output formats.`,
 		Flags: []climax.Flag{
 			{
@@ -127,40 +719,121 @@ func main() {
 				Help:     `JSON input file from scan command (default: read from stdin)`,
 				Variable: true,
 			},
+			{
+				Name:     "merge",
+				Usage:    `--merge <file>...`,
+				Help:     `Merge the scan result files given as arguments, such as scans of different owners or scan shards, into one report with a per-owner breakdown (e.g. report --merge a.json b.json c.json). Repositories in several files keep the result of the latest scan`,
+				Variable: false,
+			},
 			{
 				Name:     "output",
 				Short:    "o",
 				Usage:    `--output <file>`,
-				Help:     `Output file for formatted report. Use .json extension for JSON format or .ipynb for Jupyter notebook (default: JSON to stdout)`,
+				Help:     `Output file for formatted report, repeatable or comma-separated to write several formats in one run. Format follows the extension: .json for JSON, .ipynb for Jupyter notebook, .sarif for SARIF, .parquet for the flattened action inventory as Parquet (default: stdout, see --format)`,
 				Variable: true,
 			},
-		},
-		Handle: handleReport,
-	}
-
-	cli.AddCommand(reportCmd)
-
-	// Create-PR command
-	createPRCmd := climax.Command{
-		Name:  "create-pr",
-		Brief: "Create pull requests from scan results",
-		Usage: `create-pr [--input <file>] [--template <file>] [--token <token>] [--filter <regex>]`,
-		Help:  `Creates pull requests for action updates from scan results. Input can be a file or stdin. Supports custom Go templates for PR body generation.`,
-		Flags: []climax.Flag{
 			{
-				Name:     "input",
-				Short:    "i",
-				Usage:    `--input <file>`,
-				Help:     `JSON input file from scan command (default: read from stdin)`,
+				Name:     "format",
+				Usage:    `--format <format>`,
+				Help:     `Format of results written to stdout when no --output file is given: table for a summary of repositories, severities, and top actions, json for the full results, or problems for one file:line:col: severity: message line per issue (default: table)`,
 				Variable: true,
 			},
 			{
-				Name:     "template",
-				Short:    "T",
+				Name: "no-color",
+				Help: `Disable colored severities in terminal output. Color is also off when stdout is not a terminal or NO_COLOR is set`,
+			},
+			{
+				Name:     "encrypt-recipient",
+				Short:    "e",
+				Usage:    `--encrypt-recipient <recipients>`,
+				Help:     `Encrypt the output with age to comma-separated recipients: age public keys, SSH public keys, plugin recipients (e.g., KMS-backed), or recipients files (requires the age CLI)`,
+				Variable: true,
+			},
+			{
+				Name:     "report-template-dir",
+				Short:    "T",
+				Usage:    `--report-template-dir <dir>`,
+				Help:     `Directory of Go templates (<section>.md.tmpl) overriding notebook report sections for custom branding. Sections: header, summary, issues-overview, priorities, repository-details, suppressed-issues, reusable-workflows, tag-protection, internal-actions, workflow-usage, actions-minutes, secret-flows, container-images, environments, setup-consistency, pr-links, detailed-stats, footer`,
+				Variable: true,
+			},
+			{
+				Name:     "links",
+				Usage:    `--links <file>`,
+				Help:     `JSON object of named URL templates over a repository's .Owner, .Name, .FullName, .Host, and custom .Properties, e.g. {"backstage": "https://backstage.example.com/catalog/default/component/{{.Name}}"}. Report and description templates build them with {{link "backstage" .}}`,
+				Variable: true,
+			},
+			{
+				Name:     "group-issues",
+				Short:    "g",
+				Usage:    `--group-issues`,
+				Help:     `Merge identical issues (same action, version, and type) in a repository into one entry listing the affected files. JSON reports keep the detailed issues alongside issue_groups`,
+				Variable: false,
+			},
+			{
+				Name:     "redact",
+				Short:    "R",
+				Usage:    `--redact`,
+				Help:     `Replace repository names with keyed hashes, file paths with placeholders, and custom property values with [redacted] so the report can be shared externally. Public action names and versions are kept. Without --redact-key, a random key is used for this run, so placeholders differ between reports`,
+				Variable: false,
+			},
+			{
+				Name:     "redact-key",
+				Usage:    `--redact-key <key>`,
+				Help:     `Secret key for the --redact hashes (or set ACTIONS_MAINTAINER_REDACT_KEY env var). Reports redacted with the same key use the same placeholders, so they can be compared over time`,
+				Variable: true,
+			},
+			{
+				Name:     "emit-approvals",
+				Usage:    `--emit-approvals <file>`,
+				Help:     `Also write the updates create-pr would make to a YAML approvals file, each with approved: false. Set approved: true on the updates to make and pass the file to create-pr --approvals. Approvals already in the file are kept for unchanged updates`,
+				Variable: true,
+			},
+			{
+				Name:     "debt-targets",
+				Usage:    `--debt-targets <file>`,
+				Help:     `JSON file of severity weights for the actions debt score, the severity-weighted issues per repository in the summary, and of dated targets it is measured against, e.g. one per quarter`,
+				Variable: true,
+			},
+			{
+				Name:     "fail-on-debt",
+				Usage:    `--fail-on-debt`,
+				Help:     `Exit with code 2 when the actions debt score is above the current target of --debt-targets`,
+				Variable: false,
+			},
+		},
+		Handle: handleReport,
+	}
+
+	reportCmd.Flags = append(reportCmd.Flags, decryptFlags...)
+	cli.AddCommand(reportCmd)
+
+	// Create-PR command
+	createPRCmd := climax.Command{
+		Name:  "create-pr",
+		Brief: "Create pull requests from scan results",
+		Usage: `create-pr [--input <file> | --plan <file> [--resume] [--chunk <n>]] [--template <file>] [--token <token>] [--filter <regex>] [--canary <N|N%> | --promote] [--wave <n>]`,
+		Help:  `Creates pull requests for action updates from scan results, or executes a plan file written by the plan command. Input can be a file or stdin. Supports custom Go templates for PR body generation.`,
+		Flags: []climax.Flag{
+			{
+				Name:     "input",
+				Short:    "i",
+				Usage:    `--input <file>`,
+				Help:     `JSON input file from scan command (default: read from stdin)`,
+				Variable: true,
+			},
+			{
+				Name:     "template",
+				Short:    "T",
 				Usage:    `--template <file>`,
 				Help:     `Go template file for PR body generation. Template receives TemplateData with Repository, Updates, UpdateCount, and grouped update lists`,
 				Variable: true,
 			},
+			{
+				Name:     "links",
+				Usage:    `--links <file>`,
+				Help:     `JSON object of named URL templates over a repository's .Owner, .Name, .FullName, and custom .Properties, e.g. {"grafana": "https://grafana.example.com/d/{{.Properties.ProductId}}"}. The --template builds them with {{link "grafana" .Repository}}`,
+				Variable: true,
+			},
 			{
 				Name:     "token",
 				Short:    "t",
@@ -175,341 +848,7105 @@ func main() {
 				Help:     `Regular expression to filter repositories by name (e.g., "my-repos-.*")`,
 				Variable: true,
 			},
+			{
+				Name:     "hook-command",
+				Short:    "H",
+				Usage:    `--hook-command <command>`,
+				Help:     `Shell command run once per repository plan with the planned updates as JSON on stdin. A non-zero exit skips that repository, allowing custom approval flows`,
+				Variable: true,
+			},
+			{
+				Name:     "hook-url",
+				Short:    "W",
+				Usage:    `--hook-url <url>`,
+				Help:     `Webhook each repository plan is POSTed to as JSON. A non-2xx response skips that repository`,
+				Variable: true,
+			},
+			{
+				Name:     "include-existing",
+				Short:    "e",
+				Usage:    `--include-existing`,
+				Help:     `Also create pull requests for issues marked as existing by a scan baseline`,
+				Variable: false,
+			},
+			{
+				Name:     "skip-stale-workflows",
+				Short:    "s",
+				Usage:    `--skip-stale-workflows`,
+				Help:     `Skip updates to workflow files that did not run within the scan's --workflow-usage window, including files that never ran`,
+				Variable: false,
+			},
+			{
+				Name:     "base-branches",
+				Short:    "B",
+				Usage:    `--base-branches <file>`,
+				Help:     `JSON rules choosing the base branches pull requests target per repository, e.g. every "release/*" maintenance branch. One pull request is created per matching branch; repositories no rule matches target their default branch`,
+				Variable: true,
+			},
+			{
+				Name:     "canary",
+				Usage:    `--canary <N|N%>`,
+				Help:     `Create pull requests for only N (or N%) of the affected repositories, chosen deterministically, and record the cohort in the --canary-state file. Run again with --promote once the canary pull requests merge`,
+				Variable: true,
+			},
+			{
+				Name:     "canary-property",
+				Usage:    `--canary-property <name>=<value>`,
+				Help:     `Choose repositories whose custom property has this value for the canary cohort first (e.g. rollout-ring=canary)`,
+				Variable: true,
+			},
+			{
+				Name:     "canary-state",
+				Usage:    `--canary-state <file>`,
+				Help:     `File recording the canary cohort between runs (default: .actions-maintainer-canary.json)`,
+				Variable: true,
+			},
+			{
+				Name:     "promote",
+				Usage:    `--promote`,
+				Help:     `Create pull requests for the repositories held back by --canary, once every canary pull request has merged`,
+				Variable: false,
+			},
+			{
+				Name:     "allow-stale",
+				Usage:    `--allow-stale`,
+				Help:     `Create pull requests even for workflow files changed on the default branch since the scan. By default such repositories are skipped until rescanned`,
+				Variable: false,
+			},
+			{
+				Name:     "wave",
+				Usage:    `--wave <n>`,
+				Help:     `Create pull requests only for the repositories in wave n of the --wave-plan file`,
+				Variable: true,
+			},
+			{
+				Name:     "wave-plan",
+				Usage:    `--wave-plan <file>`,
+				Help:     `Wave plan written by the waves command (default: .actions-maintainer-waves.json)`,
+				Variable: true,
+			},
+			{
+				Name:     "approvals",
+				Usage:    `--approvals <file>`,
+				Help:     `Only make the updates approved in an approvals file written by report --emit-approvals`,
+				Variable: true,
+			},
+			{
+				Name:     "no-codeowners",
+				Usage:    `--no-codeowners`,
+				Help:     `Do not request reviews from the CODEOWNERS of the changed workflow files; only the owners named by rules are requested`,
+				Variable: false,
+			},
+			{
+				Name:     "max-reviewers",
+				Usage:    `--max-reviewers <n>`,
+				Help:     `Most reviewers to request per pull request. Owners named by rules come first, then code owners (default: no limit)`,
+				Variable: true,
+			},
+			{
+				Name:     "coexist-mode",
+				Usage:    `--coexist-mode <skip|supersede|ignore>`,
+				Help:     `What to do with actions that already have an open Dependabot or Renovate pull request: skip them, supersede the bot's pull request with a comment, or ignore bot pull requests (default: skip)`,
+				Variable: true,
+			},
+			{
+				Name:     "commit-message",
+				Usage:    `--commit-message <preset|template>`,
+				Help:     `Commit message for the updates: a preset (conventional, e.g. "chore(deps): bump actions/checkout to v4"; conventional-ci; title) or a Go template over .Title, .Repository, .BaseBranch, .Count, .Action, .From, .To, and .Updates. Rules with a commit_message override it (default: the pull request title)`,
+				Variable: true,
+			},
+			{
+				Name:     "commit-layout",
+				Usage:    `--commit-layout <single|per-action|per-file>`,
+				Help:     `How updates are committed on each pull request branch: one commit, one commit per updated action, or one commit per changed file. Each commit gets its own --commit-message (default: single)`,
+				Variable: true,
+			},
+			{
+				Name:     "ledger",
+				Usage:    `--ledger <file>`,
+				Help:     `Record each pull request created in this file, keyed by repository and a hash of its planned updates, and skip plans already recorded. Reruns after a partial failure never open duplicates`,
+				Variable: true,
+			},
+			{
+				Name:     "changelog",
+				Usage:    `--changelog <file>`,
+				Help:     `Write a markdown changelog of the run to this file: every pull request with its link, and the files and versions it changes, e.g. for a change ticket`,
+				Variable: true,
+			},
+			{
+				Name:     "plan",
+				Usage:    `--plan <file>`,
+				Help:     `Create the pull requests of a plan file written by the plan command instead of planning from scan results. Each item's status is saved to the file as it is executed`,
+				Variable: true,
+			},
+			{
+				Name:     "resume",
+				Usage:    `--resume`,
+				Help:     `With --plan, continue a plan that was already executed: pending and failed items are created, items already created are left alone`,
+				Variable: false,
+			},
+			{
+				Name:     "chunk",
+				Usage:    `--chunk <n>`,
+				Help:     `With --plan, create at most n pull requests and stop; run again with --resume for the next chunk (default: the whole plan)`,
+				Variable: true,
+			},
+			{
+				Name:     "estimate",
+				Usage:    `--estimate`,
+				Help:     `Print the predicted GitHub API calls and duration of opening the planned pull requests against the token's rate limit and exit without creating them`,
+				Variable: false,
+			},
+			{
+				Name:     "review-mode",
+				Usage:    `--review-mode`,
+				Help:     `Instead of pushing updates, open a pull request that only marks the lines to update and review it with a one-click suggestion per line, for repositories where direct pushes are unwelcome. Updates that add or remove lines are listed in the review instead`,
+				Variable: false,
+			},
+			{
+				Name:     "review-pr",
+				Usage:    `--review-pr <number>`,
+				Help:     `With --review-mode, post the suggestions on this existing open pull request instead of opening one. Only lines its diff shows can take suggestions; --filter must select a single repository`,
+				Variable: true,
+			},
+			{
+				Name:     "dispatch-check",
+				Usage:    `--dispatch-check`,
+				Help:     `After creating the pull requests, run each workflow patched with schema transformations on its pull request branch with workflow_dispatch, wait for the runs, and comment their conclusions on the pull requests. Workflows without a workflow_dispatch trigger, or with required inputs lacking defaults, are skipped`,
+				Variable: false,
+			},
+			{
+				Name:     "dispatch-timeout",
+				Usage:    `--dispatch-timeout <duration>`,
+				Help:     `Longest to wait for the --dispatch-check runs (default: 30m). Runs still going are reported as running; verify reports their conclusions later`,
+				Variable: true,
+			},
 		},
 		Handle: handleCreatePR,
 	}
 
-	cli.AddCommand(createPRCmd)
-
-	cli.Run()
+	createPRCmd.Flags = append(createPRCmd.Flags, networkFlags...)
+	createPRCmd.Flags = append(createPRCmd.Flags, hostFlags...)
+	createPRCmd.Flags = append(createPRCmd.Flags, decryptFlags...)
+	createPRCmd.Flags = append(createPRCmd.Flags, auditFlags...)
+	createPRCmd.Flags = append(createPRCmd.Flags, appFlags...)
+	cli.AddCommand(createPRCmd)
+
+	// Plan command
+	planCmd := climax.Command{
+		Name:  "plan",
+		Brief: "Write the pull requests create-pr would open to a plan file",
+		Usage: `plan [--input <file>] [--output <file>] [--token <token>] [--filter <regex>] [--wave <n>]`,
+		Help:  `Plans pull requests from scan results like create-pr, including the drift, code owner, and required check lookups, and writes them to a plan file for review instead of creating them. Execute the plan with create-pr --plan; its progress is saved in the file, so an interrupted rollout continues with create-pr --plan --resume.`,
+		Flags: []climax.Flag{
+			{
+				Name:     "input",
+				Short:    "i",
+				Usage:    `--input <file>`,
+				Help:     `JSON input file from scan command (default: read from stdin)`,
+				Variable: true,
+			},
+			{
+				Name:     "output",
+				Short:    "o",
+				Usage:    `--output <file>`,
+				Help:     `Plan file to write (default: .actions-maintainer-plan.json)`,
+				Variable: true,
+			},
+			{
+				Name:     "token",
+				Short:    "t",
+				Usage:    `--token <token>`,
+				Help:     `GitHub personal access token (or set GITHUB_TOKEN env var)`,
+				Variable: true,
+			},
+			{
+				Name:     "filter",
+				Short:    "r",
+				Usage:    `--filter <regex>`,
+				Help:     `Regular expression to filter repositories by name (e.g., "my-repos-.*")`,
+				Variable: true,
+			},
+			{
+				Name:     "hook-command",
+				Short:    "H",
+				Usage:    `--hook-command <command>`,
+				Help:     `Shell command run once per repository plan with the planned updates as JSON on stdin. A non-zero exit leaves that repository out of the plan`,
+				Variable: true,
+			},
+			{
+				Name:     "hook-url",
+				Short:    "W",
+				Usage:    `--hook-url <url>`,
+				Help:     `Webhook each repository plan is POSTed to as JSON. A non-2xx response leaves that repository out of the plan`,
+				Variable: true,
+			},
+			{
+				Name:     "include-existing",
+				Short:    "e",
+				Usage:    `--include-existing`,
+				Help:     `Also plan pull requests for issues marked as existing by a scan baseline`,
+				Variable: false,
+			},
+			{
+				Name:     "skip-stale-workflows",
+				Short:    "s",
+				Usage:    `--skip-stale-workflows`,
+				Help:     `Skip updates to workflow files that did not run within the scan's --workflow-usage window, including files that never ran`,
+				Variable: false,
+			},
+			{
+				Name:     "base-branches",
+				Short:    "B",
+				Usage:    `--base-branches <file>`,
+				Help:     `JSON rules choosing the base branches pull requests target per repository; one pull request is planned per matching branch`,
+				Variable: true,
+			},
+			{
+				Name:     "allow-stale",
+				Usage:    `--allow-stale`,
+				Help:     `Plan pull requests even for workflow files changed on the default branch since the scan`,
+				Variable: false,
+			},
+			{
+				Name:     "wave",
+				Usage:    `--wave <n>`,
+				Help:     `Plan pull requests only for the repositories in wave n of the --wave-plan file`,
+				Variable: true,
+			},
+			{
+				Name:     "wave-plan",
+				Usage:    `--wave-plan <file>`,
+				Help:     `Wave plan written by the waves command (default: .actions-maintainer-waves.json)`,
+				Variable: true,
+			},
+			{
+				Name:     "approvals",
+				Usage:    `--approvals <file>`,
+				Help:     `Only plan the updates approved in an approvals file written by report --emit-approvals`,
+				Variable: true,
+			},
+			{
+				Name:     "no-codeowners",
+				Usage:    `--no-codeowners`,
+				Help:     `Do not request reviews from the CODEOWNERS of the changed workflow files`,
+				Variable: false,
+			},
+			{
+				Name:     "max-reviewers",
+				Usage:    `--max-reviewers <n>`,
+				Help:     `Most reviewers to request per pull request (default: no limit)`,
+				Variable: true,
+			},
+			{
+				Name:     "coexist-mode",
+				Usage:    `--coexist-mode <skip|supersede|ignore>`,
+				Help:     `What to do with actions that already have an open Dependabot or Renovate pull request (default: skip)`,
+				Variable: true,
+			},
+		},
+		Handle: handlePlan,
+	}
+
+	planCmd.Flags = append(planCmd.Flags, networkFlags...)
+	planCmd.Flags = append(planCmd.Flags, hostFlags...)
+	planCmd.Flags = append(planCmd.Flags, decryptFlags...)
+	planCmd.Flags = append(planCmd.Flags, appFlags...)
+	cli.AddCommand(planCmd)
+
+	// Waves command
+	wavesCmd := climax.Command{
+		Name:  "waves",
+		Brief: "Partition repositories with updates into rollout waves",
+		Usage: `waves [--input <file>] [--output <file>] [--wave-property <name>]`,
+		Help:  `Assigns every repository with issues to a rollout wave: 1 for repositories with only minor and patch bumps and no deploy workflows, 3 for repositories deploying to environments with major bumps, migrations, transformations, or removals, and 2 for the rest. The plan is written as JSON for create-pr --wave.`,
+		Flags: []climax.Flag{
+			{
+				Name:     "input",
+				Short:    "i",
+				Usage:    `--input <file>`,
+				Help:     `JSON input file from scan command (default: read from stdin)`,
+				Variable: true,
+			},
+			{
+				Name:     "output",
+				Short:    "o",
+				Usage:    `--output <file>`,
+				Help:     `Wave plan file to write (default: .actions-maintainer-waves.json)`,
+				Variable: true,
+			},
+			{
+				Name:     "wave-property",
+				Short:    "p",
+				Usage:    `--wave-property <name>`,
+				Help:     `Custom property whose value (1, 2, or 3) assigns a repository's wave, overriding its risk (e.g. rollout-wave)`,
+				Variable: true,
+			},
+		},
+		Handle: handleWaves,
+	}
+
+	wavesCmd.Flags = append(wavesCmd.Flags, decryptFlags...)
+	cli.AddCommand(wavesCmd)
+
+	// Apply command
+	applyCmd := climax.Command{
+		Name:  "apply",
+		Brief: "Apply updates from scan results to local checkouts",
+		Usage: `apply [--input <file>] --workdir <path> [--filter <regex>] [--dry-run] [--simulate]`,
+		Help:  `Rewrites workflow files in repositories already cloned under the working directory, using the same transformations as create-pr. Checkouts are located at <workdir>/<owner>/<name> or <workdir>/<name>. No GitHub access is required; committing and pushing is left to your own git automation.`,
+		Flags: []climax.Flag{
+			{
+				Name:     "input",
+				Short:    "i",
+				Usage:    `--input <file>`,
+				Help:     `JSON input file from scan command (default: read from stdin)`,
+				Variable: true,
+			},
+			{
+				Name:     "workdir",
+				Short:    "w",
+				Usage:    `--workdir <path>`,
+				Help:     `Directory containing the cloned repositories`,
+				Variable: true,
+			},
+			{
+				Name:     "filter",
+				Short:    "r",
+				Usage:    `--filter <regex>`,
+				Help:     `Regular expression to filter repositories by name (e.g., "my-repos-.*")`,
+				Variable: true,
+			},
+			{
+				Name:     "dry-run",
+				Short:    "n",
+				Usage:    `--dry-run`,
+				Help:     `Report which files would change without writing them`,
+				Variable: false,
+			},
+			{
+				Name:     "simulate",
+				Usage:    `--simulate`,
+				Help:     `Also dry-run rewritten workflows: check the needs graph for undefined jobs and cycles, and that expressions parse and reference known contexts, needed jobs, step ids, and matrices`,
+				Variable: false,
+			},
+			{
+				Name:     "verbose",
+				Short:    "v",
+				Usage:    `--verbose`,
+				Help:     `Enable verbose logging`,
+				Variable: false,
+			},
+		},
+		Handle: handleApply,
+	}
+
+	applyCmd.Flags = append(applyCmd.Flags, decryptFlags...)
+	cli.AddCommand(applyCmd)
+
+	// Jira command
+	jiraCmd := climax.Command{
+		Name:  "jira",
+		Brief: "Create or update Jira tickets from scan results",
+		Usage: `jira --config <file> [--input <file>] [--state <file>] [--dry-run]`,
+		Help:  `Creates one Jira ticket per repository (or per custom property value) summarizing outstanding action issues, and updates it on later runs. Ticket keys are stored in a state file to avoid duplicates; tickets whose issues are all resolved get a closing comment. Authenticate with JIRA_EMAIL and JIRA_API_TOKEN (Jira Cloud) or JIRA_API_TOKEN alone (Data Center personal access token).`,
+		Flags: []climax.Flag{
+			{
+				Name:     "input",
+				Short:    "i",
+				Usage:    `--input <file>`,
+				Help:     `JSON input file from scan command (default: read from stdin)`,
+				Variable: true,
+			},
+			{
+				Name:     "config",
+				Short:    "c",
+				Usage:    `--config <file>`,
+				Help:     `Jira config file (JSON): base_url, project, issue_type, group_by ("repository" or "custom_property:<name>"), labels, and per-group project/issue type overrides`,
+				Variable: true,
+			},
+			{
+				Name:     "state",
+				Short:    "s",
+				Usage:    `--state <file>`,
+				Help:     `File storing the Jira ticket for each group (default: .actions-maintainer-jira.json)`,
+				Variable: true,
+			},
+			{
+				Name:     "dry-run",
+				Short:    "n",
+				Usage:    `--dry-run`,
+				Help:     `Show which tickets would be created or updated without calling Jira`,
+				Variable: false,
+			},
+			{
+				Name:     "verbose",
+				Short:    "v",
+				Usage:    `--verbose`,
+				Help:     `Enable verbose logging`,
+				Variable: false,
+			},
+		},
+		Handle: handleJira,
+	}
+
+	jiraCmd.Flags = append(jiraCmd.Flags, networkFlags...)
+	jiraCmd.Flags = append(jiraCmd.Flags, decryptFlags...)
+	cli.AddCommand(jiraCmd)
+
+	// Cleanup command
+	cleanupCmd := climax.Command{
+		Name:  "cleanup",
+		Brief: "Delete merged or closed actions-maintainer branches",
+		Usage: `cleanup [--input <file>] [--token <token>] [--filter <regex>] [--dry-run]`,
+		Help:  `Deletes branches created by create-pr (named "actions-maintainer/...") in the repositories of a scan result once their pull requests are merged or closed. Branches with an open pull request, or with no pull request at all, are kept.`,
+		Flags: []climax.Flag{
+			{
+				Name:     "input",
+				Short:    "i",
+				Usage:    `--input <file>`,
+				Help:     `JSON input file from scan command (default: read from stdin)`,
+				Variable: true,
+			},
+			{
+				Name:     "token",
+				Short:    "t",
+				Usage:    `--token <token>`,
+				Help:     `GitHub personal access token (or set GITHUB_TOKEN env var)`,
+				Variable: true,
+			},
+			{
+				Name:     "filter",
+				Short:    "r",
+				Usage:    `--filter <regex>`,
+				Help:     `Regular expression to filter repositories by name (e.g., "my-repos-.*")`,
+				Variable: true,
+			},
+			{
+				Name:     "dry-run",
+				Short:    "n",
+				Usage:    `--dry-run`,
+				Help:     `Show which branches would be deleted without deleting them`,
+				Variable: false,
+			},
+			{
+				Name:     "verbose",
+				Short:    "v",
+				Usage:    `--verbose`,
+				Help:     `Enable verbose logging`,
+				Variable: false,
+			},
+		},
+		Handle: handleCleanup,
+	}
+
+	cleanupCmd.Flags = append(cleanupCmd.Flags, networkFlags...)
+	cleanupCmd.Flags = append(cleanupCmd.Flags, hostFlags...)
+	cleanupCmd.Flags = append(cleanupCmd.Flags, decryptFlags...)
+	cleanupCmd.Flags = append(cleanupCmd.Flags, auditFlags...)
+	cli.AddCommand(cleanupCmd)
+
+	// Verify command
+	verifyCmd := climax.Command{
+		Name:  "verify",
+		Brief: "Confirm pull requests fixed the issues of a scan",
+		Usage: `verify --input <file> [--ledger <file>] [--rules-file <file>] [--output <file>] [--merged-only]`,
+		Help:  `Rescans the repositories that pull requests were opened for, as recorded in the scan's created_prs and the create-pr ledger, and compares their issues with the original scan. Writes a closure report listing each repository's pull requests with their state, the workflow_dispatch runs on their branches (such as create-pr --dispatch-check runs), and its fixed, remaining, and regressed issues. Exits with code 2 unless every original issue is fixed and no new issue was found.`,
+		Flags: []climax.Flag{
+			{
+				Name:     "input",
+				Short:    "i",
+				Usage:    `--input <file>`,
+				Help:     `JSON input file from the scan the pull requests were created from (default: read from stdin)`,
+				Variable: true,
+			},
+			{
+				Name:     "ledger",
+				Usage:    `--ledger <file>`,
+				Help:     `Ledger file passed to create-pr --ledger, listing the pull requests it opened`,
+				Variable: true,
+			},
+			{
+				Name:     "rules-file",
+				Short:    "R",
+				Usage:    `--rules-file <file>`,
+				Help:     `JSON file with the custom rules the scan used, so the rescan finds the same issues`,
+				Variable: true,
+			},
+			{
+				Name:     "output",
+				Short:    "o",
+				Usage:    `--output <file>`,
+				Help:     `Closure report file: JSON for a .json extension, Markdown otherwise (default: Markdown to stdout)`,
+				Variable: true,
+			},
+			{
+				Name:     "merged-only",
+				Usage:    `--merged-only`,
+				Help:     `Only verify repositories with a merged pull request`,
+				Variable: false,
+			},
+			{
+				Name:     "token",
+				Short:    "t",
+				Usage:    `--token <token>`,
+				Help:     `GitHub personal access token (or set GITHUB_TOKEN env var)`,
+				Variable: true,
+			},
+			{
+				Name:     "verbose",
+				Short:    "v",
+				Usage:    `--verbose`,
+				Help:     `Enable verbose logging`,
+				Variable: false,
+			},
+		},
+		Handle: handleVerify,
+	}
+
+	verifyCmd.Flags = append(verifyCmd.Flags, networkFlags...)
+	verifyCmd.Flags = append(verifyCmd.Flags, hostFlags...)
+	verifyCmd.Flags = append(verifyCmd.Flags, decryptFlags...)
+	cli.AddCommand(verifyCmd)
+
+	digestCmd := climax.Command{
+		Name:  "digest",
+		Brief: "Summarize new and fixed issues, stalled pull requests, and deadlines",
+		Usage: `digest --input <file> [--previous <file>] [--ledger <file>] [--format markdown|email|slack|json] [--output <file>]`,
+		Help:  `Builds a digest for scheduled runs from the latest scan, the previous one, and the state of the pull requests actions-maintainer opened: issues new since the previous scan, issues fixed since, pull requests open longer than --stalled-days, deprecation deadlines within --deadline-days, and the severity trend of past scans. Without --previous, issues a scan with --baseline marked as existing are not new, and fixed issues are left out. Pull request status needs a token; without one it is skipped.`,
+		Flags: []climax.Flag{
+			{
+				Name:     "input",
+				Short:    "i",
+				Usage:    `--input <file>`,
+				Help:     `JSON input file from the latest scan (default: read from stdin)`,
+				Variable: true,
+			},
+			{
+				Name:     "previous",
+				Short:    "p",
+				Usage:    `--previous <file>`,
+				Help:     `JSON results of the previous scan, such as last week's, to find new and fixed issues`,
+				Variable: true,
+			},
+			{
+				Name:     "ledger",
+				Usage:    `--ledger <file>`,
+				Help:     `Ledger file passed to create-pr --ledger, listing the pull requests it opened`,
+				Variable: true,
+			},
+			{
+				Name:     "format",
+				Short:    "f",
+				Usage:    `--format <format>`,
+				Help:     `Digest format: markdown, email (a plain text message for sendmail), slack (an incoming webhook payload), or json (default: markdown)`,
+				Variable: true,
+			},
+			{
+				Name:     "output",
+				Short:    "o",
+				Usage:    `--output <file>`,
+				Help:     `Digest file (default: stdout)`,
+				Variable: true,
+			},
+			{
+				Name:     "stalled-days",
+				Usage:    `--stalled-days <days>`,
+				Help:     fmt.Sprintf(`Days a pull request stays open before it is stalled (default: %d)`, digest.DefaultStalledDays),
+				Variable: true,
+			},
+			{
+				Name:     "deadline-days",
+				Usage:    `--deadline-days <days>`,
+				Help:     fmt.Sprintf(`Days ahead to list deprecation deadlines; passed deadlines are always listed (default: %d)`, digest.DefaultDeadlineDays),
+				Variable: true,
+			},
+			{
+				Name:     "email-from",
+				Usage:    `--email-from <address>`,
+				Help:     `From address of an email digest`,
+				Variable: true,
+			},
+			{
+				Name:     "email-to",
+				Usage:    `--email-to <addresses>`,
+				Help:     `Comma-separated recipients of an email digest`,
+				Variable: true,
+			},
+			{
+				Name:     "token",
+				Short:    "t",
+				Usage:    `--token <token>`,
+				Help:     `GitHub personal access token (or set GITHUB_TOKEN env var) to read the state of pull requests`,
+				Variable: true,
+			},
+			{
+				Name:     "verbose",
+				Short:    "v",
+				Usage:    `--verbose`,
+				Help:     `Enable verbose logging`,
+				Variable: false,
+			},
+		},
+		Handle: handleDigest,
+	}
+
+	digestCmd.Flags = append(digestCmd.Flags, networkFlags...)
+	digestCmd.Flags = append(digestCmd.Flags, hostFlags...)
+	digestCmd.Flags = append(digestCmd.Flags, decryptFlags...)
+	cli.AddCommand(digestCmd)
+
+	// Broadcast command
+	broadcastCmd := climax.Command{
+		Name:  "broadcast",
+		Brief: "Roll out a reusable workflow release to its consumers",
+		Usage: `broadcast --workflow <owner/repo[/path]> --version <version> [--input <file>] [--token <token>] [--dry-run]`,
+		Help:  `Finds every repository in a scan result that calls a reusable workflow, creates one pull request per consumer updating only that workflow reference to the new version, and opens a tracking issue in the workflow's repository listing the consumer pull requests.`,
+		Flags: []climax.Flag{
+			{
+				Name:     "input",
+				Short:    "i",
+				Usage:    `--input <file>`,
+				Help:     `JSON input file from scan command (default: read from stdin)`,
+				Variable: true,
+			},
+			{
+				Name:     "workflow",
+				Short:    "w",
+				Usage:    `--workflow <owner/repo[/path]>`,
+				Help:     `Reusable workflow to roll out, e.g. "my-org/shared/.github/workflows/build.yml". Without a path, every reusable workflow in the repository is updated`,
+				Variable: true,
+			},
+			{
+				Name:     "version",
+				Short:    "V",
+				Usage:    `--version <version>`,
+				Help:     `New release of the reusable workflow (tag, branch, or SHA)`,
+				Variable: true,
+			},
+			{
+				Name:     "token",
+				Short:    "t",
+				Usage:    `--token <token>`,
+				Help:     `GitHub personal access token (or set GITHUB_TOKEN env var)`,
+				Variable: true,
+			},
+			{
+				Name:     "filter",
+				Short:    "r",
+				Usage:    `--filter <regex>`,
+				Help:     `Regular expression to filter consumer repositories by name (e.g., "my-repos-.*")`,
+				Variable: true,
+			},
+			{
+				Name:     "template",
+				Short:    "T",
+				Usage:    `--template <file>`,
+				Help:     `Go template file for PR body generation (same data as create-pr)`,
+				Variable: true,
+			},
+			{
+				Name:     "dry-run",
+				Short:    "n",
+				Usage:    `--dry-run`,
+				Help:     `List the consumers and planned updates without creating pull requests or the tracking issue`,
+				Variable: false,
+			},
+			{
+				Name:     "verbose",
+				Short:    "v",
+				Usage:    `--verbose`,
+				Help:     `Enable verbose logging`,
+				Variable: false,
+			},
+		},
+		Handle: handleBroadcast,
+	}
+
+	broadcastCmd.Flags = append(broadcastCmd.Flags, networkFlags...)
+	broadcastCmd.Flags = append(broadcastCmd.Flags, hostFlags...)
+	broadcastCmd.Flags = append(broadcastCmd.Flags, decryptFlags...)
+	broadcastCmd.Flags = append(broadcastCmd.Flags, auditFlags...)
+	cli.AddCommand(broadcastCmd)
+
+	// Align command
+	alignCmd := climax.Command{
+		Name:  "align",
+		Brief: "Align consumers of internal reusable workflows to one version each",
+		Usage: `align [--input <file>] [--versions <file>] [--workflow <owner/repo[/path]>] [--token <token>] [--dry-run]`,
+		Help:  `Campaign mode for reducing version sprawl. For each internal reusable workflow (one whose owner also owns a scanned repository) that consumers call at more than one version, picks the version most consumers use, or the version set in --versions, and creates one pull request per consumer moving its calls to that version, even when its current version is not outdated. SHA pins are left alone.`,
+		Flags: []climax.Flag{
+			{
+				Name:     "input",
+				Short:    "i",
+				Usage:    `--input <file>`,
+				Help:     `JSON input file from scan command (default: read from stdin)`,
+				Variable: true,
+			},
+			{
+				Name:     "versions",
+				Short:    "V",
+				Usage:    `--versions <file>`,
+				Help:     `JSON file mapping "owner/repo" or "owner/repo/<path to workflow>" to the version its consumers are aligned to, instead of the most used version`,
+				Variable: true,
+			},
+			{
+				Name:     "workflow",
+				Short:    "w",
+				Usage:    `--workflow <owner/repo[/path]>`,
+				Help:     `Only align this reusable workflow, or every reusable workflow in the repository when no path is given`,
+				Variable: true,
+			},
+			{
+				Name:     "token",
+				Short:    "t",
+				Usage:    `--token <token>`,
+				Help:     `GitHub personal access token (or set GITHUB_TOKEN env var)`,
+				Variable: true,
+			},
+			{
+				Name:     "filter",
+				Short:    "r",
+				Usage:    `--filter <regex>`,
+				Help:     `Regular expression to filter consumer repositories by name (e.g., "my-repos-.*"). Every scanned repository still counts towards the most used version`,
+				Variable: true,
+			},
+			{
+				Name:     "template",
+				Short:    "T",
+				Usage:    `--template <file>`,
+				Help:     `Go template file for PR body generation (same data as create-pr)`,
+				Variable: true,
+			},
+			{
+				Name:     "dry-run",
+				Short:    "n",
+				Usage:    `--dry-run`,
+				Help:     `List each workflow's version spread, the chosen version, and the planned updates without creating pull requests`,
+				Variable: false,
+			},
+			{
+				Name:     "verbose",
+				Short:    "v",
+				Usage:    `--verbose`,
+				Help:     `Enable verbose logging`,
+				Variable: false,
+			},
+		},
+		Handle: handleAlign,
+	}
+
+	alignCmd.Flags = append(alignCmd.Flags, networkFlags...)
+	alignCmd.Flags = append(alignCmd.Flags, hostFlags...)
+	alignCmd.Flags = append(alignCmd.Flags, decryptFlags...)
+	alignCmd.Flags = append(alignCmd.Flags, auditFlags...)
+	cli.AddCommand(alignCmd)
+
+	// Release checklist command
+	releaseChecklistCmd := climax.Command{
+		Name:  "release-checklist",
+		Brief: "Open convergence checklists for fragmented internal actions",
+		Usage: `release-checklist [--input <file>] [--min-versions <n>] [--token <token>] [--dry-run]`,
+		Help:  `Reads the internal actions of a scan run with --internal-actions and, for each action whose consumers use at least --min-versions versions, opens an issue in the action's repository listing the consumers of each version with a checklist for moving them to the latest release. Actions that already have an open checklist are skipped.`,
+		Flags: []climax.Flag{
+			{
+				Name:     "input",
+				Short:    "i",
+				Usage:    `--input <file>`,
+				Help:     `JSON input file from scan command (default: read from stdin)`,
+				Variable: true,
+			},
+			{
+				Name:     "min-versions",
+				Short:    "m",
+				Usage:    `--min-versions <n>`,
+				Help:     fmt.Sprintf("Versions in use before an action gets a checklist (default: %d)", consumers.DefaultMinVersions),
+				Variable: true,
+			},
+			{
+				Name:     "token",
+				Short:    "t",
+				Usage:    `--token <token>`,
+				Help:     `GitHub personal access token (or set GITHUB_TOKEN env var)`,
+				Variable: true,
+			},
+			{
+				Name:     "dry-run",
+				Short:    "n",
+				Usage:    `--dry-run`,
+				Help:     `Print the checklists without opening issues`,
+				Variable: false,
+			},
+			{
+				Name:     "verbose",
+				Short:    "v",
+				Usage:    `--verbose`,
+				Help:     `Enable verbose logging`,
+				Variable: false,
+			},
+		},
+		Handle: handleReleaseChecklist,
+	}
+
+	releaseChecklistCmd.Flags = append(releaseChecklistCmd.Flags, networkFlags...)
+	releaseChecklistCmd.Flags = append(releaseChecklistCmd.Flags, hostFlags...)
+	releaseChecklistCmd.Flags = append(releaseChecklistCmd.Flags, decryptFlags...)
+	releaseChecklistCmd.Flags = append(releaseChecklistCmd.Flags, auditFlags...)
+	cli.AddCommand(releaseChecklistCmd)
+
+	// Advisories command
+	advisoriesCmd := climax.Command{
+		Name:  "advisories",
+		Brief: "Draft security advisories for deprecated or vulnerable internal actions",
+		Usage: `advisories [--input <file>] [--advisory-repo <owner/repo>] [--token <token>] [--dry-run]`,
+		Help:  `Reads the findings of a scan and, for each internal action (one whose owner also owns a scanned repository) with deprecated or vulnerable versions still in use, drafts a repository security advisory listing the affected consumer repositories, so GitHub's security notifications reach the action's owners. Advisories are drafted in the action's repository, or all in --advisory-repo. A draft with the same summary is not duplicated.`,
+		Flags: []climax.Flag{
+			{
+				Name:     "input",
+				Short:    "i",
+				Usage:    `--input <file>`,
+				Help:     `JSON input file from scan command (default: read from stdin)`,
+				Variable: true,
+			},
+			{
+				Name:     "advisory-repo",
+				Short:    "R",
+				Usage:    `--advisory-repo <owner/repo>`,
+				Help:     `Draft every advisory as a private advisory in this repository, such as the organization's security repository, instead of in each action's repository`,
+				Variable: true,
+			},
+			{
+				Name:     "token",
+				Short:    "t",
+				Usage:    `--token <token>`,
+				Help:     `GitHub personal access token (or set GITHUB_TOKEN env var); needs admin or security manager access to the advisory repositories`,
+				Variable: true,
+			},
+			{
+				Name:     "dry-run",
+				Short:    "n",
+				Usage:    `--dry-run`,
+				Help:     `Print the advisories without drafting them`,
+				Variable: false,
+			},
+			{
+				Name:     "verbose",
+				Short:    "v",
+				Usage:    `--verbose`,
+				Help:     `Enable verbose logging`,
+				Variable: false,
+			},
+		},
+		Handle: handleAdvisories,
+	}
+
+	advisoriesCmd.Flags = append(advisoriesCmd.Flags, networkFlags...)
+	advisoriesCmd.Flags = append(advisoriesCmd.Flags, hostFlags...)
+	advisoriesCmd.Flags = append(advisoriesCmd.Flags, decryptFlags...)
+	advisoriesCmd.Flags = append(advisoriesCmd.Flags, auditFlags...)
+	cli.AddCommand(advisoriesCmd)
+
+	// Migrate command
+	migrateCmd := climax.Command{
+		Name:  "migrate",
+		Brief: "Replace every usage of one action with another",
+		Usage: `migrate --from <owner/repo[/path]> --to <owner/repo[/path]@version> [--input <file>] [--token <token>] [--dry-run]`,
+		Help:  `One-off organization-wide replacement of an action or reusable workflow without editing a rules file. Every repository in a scan result using the --from action gets one pull request rewriting it to --to; with: inputs are transformed by any patch rule defined for the move.`,
+		Flags: []climax.Flag{
+			{
+				Name:     "input",
+				Short:    "i",
+				Usage:    `--input <file>`,
+				Help:     `JSON input file from scan command (default: read from stdin)`,
+				Variable: true,
+			},
+			{
+				Name:     "from",
+				Short:    "f",
+				Usage:    `--from <owner/repo[/path]>`,
+				Help:     `Action to replace, e.g. "old-org/action". Without a path, every path in the repository is replaced`,
+				Variable: true,
+			},
+			{
+				Name:     "to",
+				Short:    "d",
+				Usage:    `--to <owner/repo[/path]@version>`,
+				Help:     `Replacement action and version, e.g. "new-org/action@v2". Without a path, each usage keeps its current path`,
+				Variable: true,
+			},
+			{
+				Name:     "token",
+				Short:    "t",
+				Usage:    `--token <token>`,
+				Help:     `GitHub personal access token (or set GITHUB_TOKEN env var)`,
+				Variable: true,
+			},
+			{
+				Name:     "filter",
+				Short:    "r",
+				Usage:    `--filter <regex>`,
+				Help:     `Regular expression to filter repositories by name (e.g., "my-repos-.*")`,
+				Variable: true,
+			},
+			{
+				Name:     "template",
+				Short:    "T",
+				Usage:    `--template <file>`,
+				Help:     `Go template file for PR body generation (same data as create-pr)`,
+				Variable: true,
+			},
+			{
+				Name:     "dry-run",
+				Short:    "n",
+				Usage:    `--dry-run`,
+				Help:     `List the planned replacements without creating pull requests`,
+				Variable: false,
+			},
+			{
+				Name:     "verbose",
+				Short:    "v",
+				Usage:    `--verbose`,
+				Help:     `Enable verbose logging`,
+				Variable: false,
+			},
+		},
+		Handle: handleMigrate,
+	}
+
+	migrateCmd.Flags = append(migrateCmd.Flags, networkFlags...)
+	migrateCmd.Flags = append(migrateCmd.Flags, hostFlags...)
+	migrateCmd.Flags = append(migrateCmd.Flags, decryptFlags...)
+	migrateCmd.Flags = append(migrateCmd.Flags, auditFlags...)
+	cli.AddCommand(migrateCmd)
+
+	// Init command
+	initCmd := climax.Command{
+		Name:  "init",
+		Brief: "Interactively create a pipeline config file",
+		Usage: `init [--output <file>] [--force]`,
+		Help:  `Asks for the owner to scan, where the GitHub token comes from, a repository filter, rules and suppressions files, output files, and whether to create pull requests, then writes a pipeline config for the run command.`,
+		Flags: []climax.Flag{
+			{
+				Name:     "output",
+				Short:    "o",
+				Usage:    `--output <file>`,
+				Help:     `Config file to write (default: actions-maintainer.json)`,
+				Variable: true,
+			},
+			{
+				Name:     "force",
+				Short:    "f",
+				Usage:    `--force`,
+				Help:     `Overwrite the config file if it already exists`,
+				Variable: false,
+			},
+		},
+		Handle: handleInit,
+	}
+
+	cli.AddCommand(initCmd)
+
+	// Test patches command
+	testPatchesCmd := climax.Command{
+		Name:  "test-patches",
+		Brief: "Test patch rules against sample workflows",
+		Usage: `test-patches --patch-rules <file> --dir <path> [--update] [--action-metadata <dir>]`,
+		Help:  `Applies patch rules to each sample workflow in a directory, as create-pr would, and compares the result with the expected output next to it (ci.yml is checked against ci.expected.yml). Each action a version patch applies to is upgraded to that patch's to_version. Prints a diff for each failing sample and exits with status 1 if any fail.`,
+		Flags: []climax.Flag{
+			{
+				Name:     "patch-rules",
+				Short:    "p",
+				Usage:    `--patch-rules <file>`,
+				Help:     `YAML or JSON file with a list of patch rules`,
+				Variable: true,
+			},
+			{
+				Name:     "dir",
+				Short:    "d",
+				Usage:    `--dir <path>`,
+				Help:     `Directory of sample workflows (.yml or .yaml) and their expected outputs (.expected.yml), searched recursively`,
+				Variable: true,
+			},
+			{
+				Name:     "update",
+				Short:    "u",
+				Usage:    `--update`,
+				Help:     `Write each sample's actual output as its expected output instead of comparing`,
+				Variable: false,
+			},
+			{
+				Name:     "action-metadata",
+				Usage:    `--action-metadata <dir>`,
+				Help:     `Directory of action metadata laid out as <dir>/<owner>/<repo>[/<path>]/action.yml. Values the patches add or modify are checked against the inputs each action declares, warning on undeclared inputs and mismatched types such as the string "false" for a boolean input`,
+				Variable: true,
+			},
+			{
+				Name:     "verbose",
+				Short:    "v",
+				Usage:    `--verbose`,
+				Help:     `Enable verbose logging`,
+				Variable: false,
+			},
+		},
+		Handle: handleTestPatches,
+	}
+
+	cli.AddCommand(testPatchesCmd)
+
+	// Explain command
+	explainCmd := climax.Command{
+		Name:  "explain",
+		Brief: "Explain a single finding of a scan",
+		Usage: `explain --input <file> --repo <name> --file <path> --action <action[@version]>`,
+		Help:  `Prints everything known about the uses of one action in one workflow file: the findings and their rule ids, the rule matching the action, the commit each ref resolves to and the tags aliasing it, the schema transformations the upgrade applies with a before and after preview of the step's with: block, and the diff create-pr would commit. Resolution and the pull request change read from GitHub and are skipped without a token.`,
+		Flags: []climax.Flag{
+			{
+				Name:     "input",
+				Short:    "i",
+				Usage:    `--input <file>`,
+				Help:     `JSON input file from scan command (default: read from stdin)`,
+				Variable: true,
+			},
+			{
+				Name:     "repo",
+				Usage:    `--repo <name>`,
+				Help:     `Repository of the finding, by full name or name`,
+				Variable: true,
+			},
+			{
+				Name:     "file",
+				Short:    "f",
+				Usage:    `--file <path>`,
+				Help:     `Workflow file of the finding (e.g., .github/workflows/ci.yml)`,
+				Variable: true,
+			},
+			{
+				Name:     "action",
+				Short:    "a",
+				Usage:    `--action <action[@version]>`,
+				Help:     `Action as written in uses:, with or without a version (e.g., actions/checkout@v3)`,
+				Variable: true,
+			},
+			{
+				Name:     "rules-file",
+				Short:    "r",
+				Usage:    `--rules-file <file>`,
+				Help:     `JSON file with the custom rules the scan used`,
+				Variable: true,
+			},
+			{
+				Name:     "token",
+				Short:    "t",
+				Usage:    `--token <token>`,
+				Help:     `GitHub personal access token (or set GITHUB_TOKEN env var); without one, refs are not resolved and the pull request change is not shown`,
+				Variable: true,
+			},
+		},
+		Handle: handleExplain,
+	}
+
+	explainCmd.Flags = append(explainCmd.Flags, decryptFlags...)
+	cli.AddCommand(explainCmd)
+
+	// Suggest rules command
+	suggestRulesCmd := climax.Command{
+		Name:  "suggest-rules",
+		Brief: "Draft rules for widely used actions without rules",
+		Usage: `suggest-rules --input <file> [--rules-file <file>] [--output <file>] [--min-repos <n>] [--internal]`,
+		Help:  `Finds the actions and reusable workflows of a scan that no rule covers but many repositories use, looks up the latest release tag of each, and writes a draft rules file for review. Without a token, the highest version in use stands in for the latest release. A summary of each suggestion is printed to stderr.`,
+		Flags: []climax.Flag{
+			{
+				Name:     "input",
+				Short:    "i",
+				Usage:    `--input <file>`,
+				Help:     `JSON input file from scan command (default: read from stdin)`,
+				Variable: true,
+			},
+			{
+				Name:     "rules-file",
+				Short:    "r",
+				Usage:    `--rules-file <file>`,
+				Help:     `JSON file with the rules already in place; actions they cover get no suggestion`,
+				Variable: true,
+			},
+			{
+				Name:     "output",
+				Short:    "o",
+				Usage:    `--output <file>`,
+				Help:     `File to write the draft rules to (default: stdout)`,
+				Variable: true,
+			},
+			{
+				Name:     "min-repos",
+				Usage:    `--min-repos <n>`,
+				Help:     `Minimum number of repositories using an action for it to get a rule (default: 3)`,
+				Variable: true,
+			},
+			{
+				Name:     "internal",
+				Usage:    `--internal`,
+				Help:     `Only suggest rules for actions published by the scanned owners`,
+				Variable: false,
+			},
+			{
+				Name:     "token",
+				Short:    "t",
+				Usage:    `--token <token>`,
+				Help:     `GitHub personal access token (or set GITHUB_TOKEN env var) for listing release tags`,
+				Variable: true,
+			},
+		},
+		Handle: handleSuggestRules,
+	}
+
+	suggestRulesCmd.Flags = append(suggestRulesCmd.Flags, decryptFlags...)
+	cli.AddCommand(suggestRulesCmd)
+
+	// Serve command
+	serveCmd := climax.Command{
+		Name:  "serve",
+		Brief: "Serve the analyzer over an HTTP API",
+		Usage: `serve [--listen <addr>] [--rules-file <file>] [--token <token>] [--input <file>]`,
+		Help:  `Runs an HTTP server so other services, such as IDE plugins and bots, can use the analyzer without embedding it. POST /analyze takes a workflow file as the body, with optional repository and path query parameters, and returns its actions and issues. POST /plan takes scan JSON and returns the update plans create-pr would open. GET /healthz answers ok. With --input, GET /badges/{owner}/{name}.svg and .json serve the actions hygiene badge of each scanned repository. With a token, the cached tags of the most used actions are refreshed in the background at a low request rate. Errors are JSON documents with an error field.`,
+		Flags: []climax.Flag{
+			{
+				Name:     "listen",
+				Short:    "l",
+				Usage:    `--listen <addr>`,
+				Help:     `Address to listen on (default: :8080)`,
+				Variable: true,
+			},
+			{
+				Name:     "input",
+				Short:    "i",
+				Usage:    `--input <file>`,
+				Help:     `JSON file from scan command whose repositories get badges under /badges/`,
+				Variable: true,
+			},
+			{
+				Name:     "rules-file",
+				Short:    "r",
+				Usage:    `--rules-file <file>`,
+				Help:     `JSON file with the custom rules to analyze workflows against`,
+				Variable: true,
+			},
+			{
+				Name:     "token",
+				Short:    "t",
+				Usage:    `--token <token>`,
+				Help:     `GitHub personal access token (or set GITHUB_TOKEN env var) for resolving versions; without one, versions are compared as strings`,
+				Variable: true,
+			},
+			{
+				Name:     "action-checks",
+				Usage:    `--action-checks <checks>`,
+				Help:     `Comma-separated action checks to run, as for scan (default: all)`,
+				Variable: true,
+			},
+			{
+				Name:     "disable-default-rules",
+				Usage:    `--disable-default-rules <repos>`,
+				Help:     `Comma-separated action repositories whose built-in rules are turned off, as for scan`,
+				Variable: true,
+			},
+			{
+				Name:     "docs-base-url",
+				Usage:    `--docs-base-url <url>`,
+				Help:     `Page documenting the rules, linked from issues (default: the project's docs/rules.md)`,
+				Variable: true,
+			},
+			{
+				Name:     "refresh-top",
+				Usage:    `--refresh-top <n>`,
+				Help:     fmt.Sprintf("With a token, refresh the cached tags of the n most used actions in the background, counted from --input and analyzed workflows; 0 disables the refresh (default: %d)", server.DefaultRefreshTop),
+				Variable: true,
+			},
+			{
+				Name:     "refresh-interval",
+				Usage:    `--refresh-interval <duration>`,
+				Help:     fmt.Sprintf("Pause between background tag refreshes, one action at a time (default: %s)", server.DefaultRefreshInterval),
+				Variable: true,
+			},
+			{
+				Name:     "verbose",
+				Short:    "v",
+				Usage:    `--verbose`,
+				Help:     `Log every request`,
+				Variable: false,
+			},
+		},
+		Handle: handleServe,
+	}
+
+	serveCmd.Flags = append(serveCmd.Flags, networkFlags...)
+	serveCmd.Flags = append(serveCmd.Flags, hostFlags...)
+	serveCmd.Flags = append(serveCmd.Flags, decryptFlags...)
+	cli.AddCommand(serveCmd)
+
+	// Badges command
+	badgesCmd := climax.Command{
+		Name:  "badges",
+		Brief: "Generate actions hygiene badges for scanned repositories",
+		Usage: `badges [--input <file>] [--output-dir <dir>] [--format <svg|endpoint|both>] [--publish <owner/repo>]`,
+		Help:  `Grades each scanned repository from A to F by the severity of its issues (100 points, less 25 per critical, 10 per high, 4 per medium, and 1 per low issue) and writes a badge per repository as <owner>/<name>.svg, or as shields.io endpoint JSON in <owner>/<name>.json, so repositories can show their status in their README. With --publish, the badges are committed under badges/ on a branch of a repository, such as its gh-pages branch, instead.`,
+		Flags: []climax.Flag{
+			{
+				Name:     "input",
+				Short:    "i",
+				Usage:    `--input <file>`,
+				Help:     `JSON input file from scan command (default: read from stdin)`,
+				Variable: true,
+			},
+			{
+				Name:     "output-dir",
+				Short:    "o",
+				Usage:    `--output-dir <dir>`,
+				Help:     `Directory to write badges to (default: badges)`,
+				Variable: true,
+			},
+			{
+				Name:     "format",
+				Short:    "f",
+				Usage:    `--format <svg|endpoint|both>`,
+				Help:     `Badge files to generate: svg images, shields.io endpoint JSON, or both (default: svg)`,
+				Variable: true,
+			},
+			{
+				Name:     "publish",
+				Short:    "p",
+				Usage:    `--publish <owner/repo>`,
+				Help:     `Commit the badges to a branch of this repository instead of writing them locally`,
+				Variable: true,
+			},
+			{
+				Name:     "branch",
+				Short:    "b",
+				Usage:    `--branch <branch>`,
+				Help:     `Existing branch to commit published badges to (default: gh-pages)`,
+				Variable: true,
+			},
+			{
+				Name:     "token",
+				Short:    "t",
+				Usage:    `--token <token>`,
+				Help:     `GitHub personal access token (or set GITHUB_TOKEN env var) for --publish`,
+				Variable: true,
+			},
+			{
+				Name:     "dry-run",
+				Short:    "n",
+				Usage:    `--dry-run`,
+				Help:     `Print each repository's grade without writing or publishing badges`,
+				Variable: false,
+			},
+			{
+				Name:     "verbose",
+				Short:    "v",
+				Usage:    `--verbose`,
+				Help:     `Enable verbose logging`,
+				Variable: false,
+			},
+		},
+		Handle: handleBadges,
+	}
+
+	badgesCmd.Flags = append(badgesCmd.Flags, networkFlags...)
+	badgesCmd.Flags = append(badgesCmd.Flags, hostFlags...)
+	badgesCmd.Flags = append(badgesCmd.Flags, decryptFlags...)
+	cli.AddCommand(badgesCmd)
+
+	// Cache command
+	cacheCmd := climax.Command{
+		Name:  "cache",
+		Brief: "Purge entries of the version cache",
+		Usage: `cache purge [--repo <owner/name>] [--older-than <duration>] [--cache-file <file>]`,
+		Help:  `Removes entries from the file cache used by scan --cache file, such as the tag mappings of an action that published a new release mid-campaign, without wiping the rest of the cache. --repo and --older-than narrow the purge; without either, every entry is removed.`,
+		Flags: []climax.Flag{
+			{
+				Name:     "repo",
+				Short:    "r",
+				Usage:    `--repo <owner/name>`,
+				Help:     `Action repository whose tags, refs, and release dates are purged; separate several with commas (default: every repository)`,
+				Variable: true,
+			},
+			{
+				Name:     "older-than",
+				Usage:    `--older-than <duration>`,
+				Help:     `Purge only entries cached at least this long ago, e.g. 24h (default: any age)`,
+				Variable: true,
+			},
+			{
+				Name:     "cache-file",
+				Usage:    `--cache-file <file>`,
+				Help:     `File cache to purge (default: versions.json in the user cache directory)`,
+				Variable: true,
+			},
+			{
+				Name:     "dry-run",
+				Short:    "n",
+				Usage:    `--dry-run`,
+				Help:     `Count the entries that would be purged without removing them`,
+				Variable: false,
+			},
+			{
+				Name:     "verbose",
+				Short:    "v",
+				Usage:    `--verbose`,
+				Help:     `Log every purged entry`,
+				Variable: false,
+			},
+		},
+		Handle: handleCache,
+	}
+
+	cli.AddCommand(cacheCmd)
+
+	// Self-update command
+	selfUpdateCmd := climax.Command{
+		Name:  "self-update",
+		Brief: "Check for and install the latest release",
+		Usage: `self-update [--check] [--force] [--token <token>]`,
+		Help:  `Looks up the latest release of actions-maintainer and, when it is newer than this binary, downloads the binary for this platform, verifies it against the release's checksums.txt, and replaces this binary with it. Rules such as brownout calendars ship with the binary, so an outdated binary misses new deadlines. "version --check" is the same as "self-update --check".`,
+		Flags: []climax.Flag{
+			{
+				Name:     "check",
+				Usage:    `--check`,
+				Help:     `Only report whether a newer release is available, without installing it`,
+				Variable: false,
+			},
+			{
+				Name:     "force",
+				Short:    "f",
+				Usage:    `--force`,
+				Help:     `Install the latest release even when this binary is up to date or a development build`,
+				Variable: false,
+			},
+			{
+				Name:     "token",
+				Short:    "t",
+				Usage:    `--token <token>`,
+				Help:     `GitHub token raising the API rate limit of the release lookup (or set GITHUB_TOKEN env var)`,
+				Variable: true,
+			},
+		},
+		Handle: handleSelfUpdate,
+	}
+
+	selfUpdateCmd.Flags = append(selfUpdateCmd.Flags, networkFlags...)
+	cli.AddCommand(selfUpdateCmd)
+
+	// Completion command
+	completionCmd := climax.Command{
+		Name:  "completion",
+		Brief: "Generate shell completion scripts",
+		Usage: `completion <bash|zsh|fish|powershell>`,
+		Help:  `Prints a completion script covering all commands and flags. Load it with e.g. "source <(actions-maintainer completion bash)" or, in PowerShell, "actions-maintainer completion powershell | Out-String | Invoke-Expression".`,
+		Handle: func(ctx climax.Context) int {
+			return handleCompletion(ctx, cli)
+		},
+	}
+
+	cli.AddCommand(completionCmd)
+
+	// Run command
+	runCmd := climax.Command{
+		Name:  "run",
+		Brief: "Run scan, report, and create-pr as one pipeline",
+		Usage: `run --config <file> [--stages <list>] [--token <token>]`,
+		Help:  `Chains scan → report → create-pr in a single invocation driven by a JSON pipeline config file. Scan and report run unless disabled in the config; create-pr only runs when enabled. Use --stages to override which stages run. The token is taken from --token or GITHUB_TOKEN (or the variable named by token_env), never from the config file.`,
+		Flags: []climax.Flag{
+			{
+				Name:     "config",
+				Short:    "c",
+				Usage:    `--config <file>`,
+				Help:     `Pipeline config file (JSON format)`,
+				Variable: true,
+			},
+			{
+				Name:     "stages",
+				Short:    "s",
+				Usage:    `--stages <list>`,
+				Help:     `Comma-separated stages to run, overriding the config file (scan, report, create-pr)`,
+				Variable: true,
+			},
+			{
+				Name:     "token",
+				Short:    "t",
+				Usage:    `--token <token>`,
+				Help:     `GitHub personal access token (or set GITHUB_TOKEN env var)`,
+				Variable: true,
+			},
+			{
+				Name:     "verbose",
+				Short:    "v",
+				Usage:    `--verbose`,
+				Help:     `Enable verbose logging in every stage`,
+				Variable: false,
+			},
+		},
+		Handle: handleRun,
+	}
+
+	cli.AddCommand(runCmd)
+
+	// Advertise aliases in the command list
+	for i := range cli.Commands {
+		for alias, name := range commandAliases {
+			if cli.Commands[i].Name == name {
+				cli.Commands[i].Brief += fmt.Sprintf(" (alias: %s)", alias)
+			}
+		}
+	}
+
+	os.Args = joinRepeatedOutputs(resolveCommandAlias(resolveVersionCheck(os.Args)))
+	os.Exit(cli.Run())
+}
+
+// exitCodeGateFailed is returned by scan when --fail-on finds new issues
+const exitCodeGateFailed = 2
+
+// exitCodeBudgetExhausted is returned by scan when --max-duration or --max-api-calls stopped it
+// before every repository was scanned
+const exitCodeBudgetExhausted = 3
+
+// commandAliases maps short command aliases to full command names
+var commandAliases = map[string]string{
+	"s":   "scan",
+	"rpt": "report",
+	"pr":  "create-pr",
+	"a":   "apply",
+}
+
+// resolveCommandAlias replaces an aliased command (including after "help") with its full name
+func resolveCommandAlias(args []string) []string {
+	resolved := append([]string(nil), args...)
+
+	index := 1
+	if len(resolved) > 2 && resolved[1] == "help" {
+		index = 2
+	}
+	if index < len(resolved) {
+		if name, ok := commandAliases[resolved[index]]; ok {
+			resolved[index] = name
+		}
+	}
+
+	return resolved
+}
+
+// resolveVersionCheck turns "version --check" into "self-update --check", since climax handles the
+// version command itself
+func resolveVersionCheck(args []string) []string {
+	if len(args) < 3 || args[1] != "version" {
+		return args
+	}
+	for _, arg := range args[2:] {
+		if arg == "--check" || arg == "-check" {
+			resolved := append([]string(nil), args...)
+			resolved[1] = "self-update"
+			return resolved
+		}
+	}
+	return args
+}
+
+// outputShortFlags maps the commands accepting repeated --output flags to the short form of the flag
+var outputShortFlags = map[string]string{
+	"scan":   "O",
+	"report": "o",
+}
+
+// joinRepeatedOutputs merges repeated --output flags into one comma-separated value, since climax
+// keeps only the last. Flags missing a value are left for climax to report.
+func joinRepeatedOutputs(args []string) []string {
+	if len(args) < 2 || outputShortFlags[args[1]] == "" {
+		return args
+	}
+	short := outputShortFlags[args[1]]
+
+	resolved := append([]string(nil), args[:2]...)
+	var outputs []string
+	position := -1
+	for i := 2; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		if !strings.HasPrefix(args[i], "-") || (name != "output" && name != short) {
+			resolved = append(resolved, args[i])
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(args) || strings.HasPrefix(args[i+1], "-") {
+				resolved = append(resolved, args[i])
+				continue
+			}
+			value = args[i+1]
+			i++
+		}
+
+		if position < 0 {
+			position = len(resolved)
+			resolved = append(resolved, "")
+		}
+		outputs = append(outputs, value)
+	}
+
+	if position >= 0 {
+		resolved[position] = "--output=" + strings.Join(outputs, ",")
+	}
+	return resolved
+}
+
+// limitRepositories cuts the repositories of a scan down to --max-repos, reporting how many were kept
+func limitRepositories(repositories []github.Repository, maxRepos int, sample bool, seed uint64) []github.Repository {
+	if maxRepos == 0 || len(repositories) <= maxRepos {
+		return repositories
+	}
+	limited := priority.LimitRepositories(repositories, maxRepos, sample, seed)
+	if sample {
+		fmt.Printf("Sampled repositories: %d/%d (--sample-seed %d scans the same sample again)\n", len(limited), len(repositories), seed)
+	} else {
+		fmt.Printf("Limited repositories: first %d/%d\n", len(limited), len(repositories))
+	}
+	return limited
+}
+
+// lockRepository builds the lock of a scanned repository, returning its drift from the lockfile in
+// verifyDir and writing it to writeDir when they are set. Repositories without a lockfile have no drift.
+func lockRepository(repoResult output.RepositoryResult, resolver lockfile.Resolver, writeDir, verifyDir string, logRecorder *logcapture.Recorder) []output.ActionIssue {
+	warn := func(format string, args ...interface{}) {
+		fmt.Printf("  Warning: "+format+"\n", args...)
+		logRecorder.Notef("Warning: "+format, args...)
+	}
+
+	lock, errs := lockfile.Build(repoResult.FullName, repoResult.Actions, resolver, time.Now())
+	for _, err := range errs {
+		warn("Not locked: %v", err)
+	}
+
+	var issues []output.ActionIssue
+	if verifyDir != "" {
+		locked, err := lockfile.Load(verifyDir, repoResult.FullName)
+		switch {
+		case err != nil:
+			warn("Failed to verify the lockfile of %s: %v", repoResult.FullName, err)
+		case locked == nil:
+			fmt.Printf("  No lockfile to verify at %s\n", lockfile.Path(verifyDir, repoResult.FullName))
+		default:
+			issues = lockfile.Verify(locked, lock, repoResult.Actions)
+		}
+	}
+
+	if writeDir != "" {
+		if err := lockfile.Save(writeDir, lock); err != nil {
+			warn("Failed to write the lockfile of %s: %v", repoResult.FullName, err)
+		}
+	}
+	return issues
+}
+
+func handleScan(ctx climax.Context) int {
+	owner, _ := ctx.Get("owner")
+	if owner == "" {
+		fmt.Fprintf(os.Stderr, "Error: --owner is required\n")
+		return 1
+	}
+
+	token, _ := ctx.Get("token")
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	recordDir, _ := ctx.Get("record")
+	replayDir, _ := ctx.Get("replay")
+	if recordDir != "" && replayDir != "" {
+		fmt.Fprintf(os.Stderr, "Error: --record and --replay cannot be used together\n")
+		return 1
+	}
+	// Replays follow the authenticated code paths of a recording made with a token; the placeholder never leaves the process
+	if replayDir != "" && token == "" {
+		token = "replay"
+	}
+	anonymous := token == ""
+
+	outputFlag, _ := ctx.Get("output")
+	outputFiles := output.ParseOutputPaths(outputFlag)
+	encryptRecipient, _ := ctx.Get("encrypt-recipient")
+	splitOutputBy, _ := ctx.Get("split-output-by")
+
+	terminal, err := terminalOutputOptions(ctx, encryptRecipient)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	// Validate the output split before scanning; the first JSON output holds the index
+	var splitSpec *output.SplitSpec
+	splitIndexFile := ""
+	if splitOutputBy != "" {
+		spec, err := output.ParseSplitSpec(splitOutputBy)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		for _, outputFile := range outputFiles {
+			if outputFile != "" && !output.IsNotebookFile(outputFile) && !output.IsSARIFFile(outputFile) && !output.IsParquetFile(outputFile) {
+				splitIndexFile = outputFile
+				break
+			}
+		}
+		if splitIndexFile == "" {
+			fmt.Fprintf(os.Stderr, "Error: --split-output-by requires a JSON --output file for the index\n")
+			return 1
+		}
+		splitSpec = &spec
+	}
+	filterPattern, _ := ctx.Get("filter")
+	verbose := ctx.Is("verbose")
+	rulesFile, _ := ctx.Get("rules-file")
+	customProperty, _ := ctx.Get("custom-property")
+	suppressionsFile, _ := ctx.Get("suppressions-file")
+	workflowDirsFlag, _ := ctx.Get("workflow-dirs")
+	skipWorkflowTemplates := ctx.Is("skip-workflow-templates")
+	workflowFilterFlag, _ := ctx.Get("workflow-filter")
+	excludeWorkflowFlag, _ := ctx.Get("exclude-workflow")
+	reportTemplateDir, _ := ctx.Get("report-template-dir")
+	profilePrefix, _ := ctx.Get("profile")
+	comprehensiveCacheFlag, _ := ctx.Get("comprehensive-cache")
+	pinAge := ctx.Is("pin-age")
+	estimateOnly := ctx.Is("estimate")
+	patchPreview := ctx.Is("patch-preview")
+	checkDeprecationNotices := ctx.Is("check-deprecation-notices")
+	mapSecrets := ctx.Is("map-secrets")
+	detectDuplicates := ctx.Is("detect-duplicates")
+	checkMajorTags := ctx.Is("check-major-tags")
+	checkCalls := ctx.Is("check-calls")
+	checkDispatchDefaults := ctx.Is("check-dispatch-defaults")
+	dispatchPolicyFile, _ := ctx.Get("dispatch-policy")
+	githubAnnotations := ctx.Is("github-annotations")
+	summaryFile, _ := ctx.Get("summary-file")
+	baselineFile, _ := ctx.Get("baseline")
+	teamProperty, _ := ctx.Get("team-property")
+	ledgerFile, _ := ctx.Get("ledger")
+	orderByFlag, _ := ctx.Get("order-by")
+	reposSnapshotFile, _ := ctx.Get("repos-snapshot")
+	priorityWeightsFile, _ := ctx.Get("priority-weights")
+	docsBaseURL, _ := ctx.Get("docs-base-url")
+	descriptionTemplatesFile, _ := ctx.Get("description-templates")
+	goldenSetFile, _ := ctx.Get("workflow-golden-set")
+	severityPolicyFile, _ := ctx.Get("severity-policy")
+	writeLocksDir, _ := ctx.Get("write-locks")
+	verifyLocksDir, _ := ctx.Get("verify-locks")
+	captureLogs := ctx.Is("capture-logs")
+	registryURL, _ := ctx.Get("registry-url")
+	registryMappingFile, _ := ctx.Get("registry-mapping")
+	registryToken, _ := ctx.Get("registry-token")
+	if registryToken == "" {
+		registryToken = os.Getenv("ACTIONS_REGISTRY_TOKEN")
+	}
+	hookCommand, _ := ctx.Get("hook-command")
+	hookURL, _ := ctx.Get("hook-url")
+
+	debtPolicy, err := loadDebtTargets(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	opts, err := scanopts.Parse(&ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	// The budget covers the whole scan, including listing repositories and loading rules
+	scanBudget := budget.New(opts.MaxDuration, opts.MaxAPICalls)
+
+	if profilePrefix != "" {
+		stopProfiling, err := startProfiling(profilePrefix)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error starting profiler: %v\n", err)
+			return 1
+		}
+		defer stopProfiling()
+	}
+
+	// Track where scan time is spent
+	timing := &output.TimingBreakdown{}
+
+	// Parse custom properties (support multiple values separated by commas)
+	var customProperties []string
+	if customProperty != "" {
+		// Split by comma and trim whitespace
+		parts := strings.Split(customProperty, ",")
+		for _, part := range parts {
+			trimmed := strings.TrimSpace(part)
+			if trimmed != "" {
+				customProperties = append(customProperties, trimmed)
+			}
+		}
+	}
+
+	// Parse workflow directories (comma-separated paths or globs)
+	workflowDirs := github.DefaultWorkflowDirs
+	if workflowDirsFlag != "" {
+		workflowDirs = nil
+		for _, part := range strings.Split(workflowDirsFlag, ",") {
+			trimmed := github.NormalizeWorkflowDir(part)
+			if trimmed != "" {
+				workflowDirs = append(workflowDirs, trimmed)
+			}
+		}
+		if len(workflowDirs) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: --workflow-dirs must list at least one directory\n")
+			return 1
+		}
+	}
+
+	// Parse workflow file include/exclude globs
+	workflowFilter, err := github.ParseWorkflowFilter(workflowFilterFlag, excludeWorkflowFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if verbose {
+		log.Printf("Verbose logging enabled")
+		log.Printf("Scanning repositories for owner: %s", owner)
+		log.Printf("Workflow directories: %v", workflowDirs)
+		if workflowFilter != nil {
+			log.Printf("Workflow file filter: include %v, exclude %v", workflowFilter.Include, workflowFilter.Exclude)
+		}
+	}
+
+	fmt.Printf("Scanning repositories for owner: %s\n", owner)
+
+	// Initialize cache for version resolution
+	cacheProvider, _ := ctx.Get("cache")
+	cacheFile, _ := ctx.Get("cache-file")
+	if cacheProvider == "" {
+		cacheProvider = "memory"
+		if cacheFile != "" {
+			cacheProvider = "file"
+		}
+	}
+
+	// A warm file cache would skip requests while recording and answer with stale versions while replaying
+	if cacheProvider == "file" && (recordDir != "" || replayDir != "") {
+		fmt.Fprintf(os.Stderr, "Error: --record and --replay need the memory cache, so every request is recorded and replayed\n")
+		return 1
+	}
+
+	var cacheInstance cache.Cache
+	switch cacheProvider {
+	case "memory":
+		cacheInstance = cache.NewMemoryCacheWithConfig(&cache.Config{
+			Verbose: verbose,
+		})
+		fmt.Printf("Using in-memory cache for version resolution\n")
+	case "file":
+		if cacheFile == "" {
+			cacheFile = cache.DefaultFilePath()
+		}
+		fileCache, err := cache.NewFileCacheWithConfig(cacheFile, &cache.Config{Verbose: verbose})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		cacheInstance = fileCache
+		fmt.Printf("Using file cache %s for version resolution\n", cacheFile)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: Unsupported cache provider '%s'. Use 'memory' or 'file'.\n", cacheProvider)
+		return 1
+	}
+	defer func() {
+		if err := cacheInstance.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}()
+
+	// Clean expired cache entries
+	cacheInstance.CleanExpired()
+
+	// Initialize components
+	transport, timeout, err := networkOptions(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	switch {
+	case recordDir != "":
+		transport, err = github.NewRecordTransport(transport, recordDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Recording GitHub API responses to %s\n", recordDir)
+	case replayDir != "":
+		transport, err = github.NewReplayTransport(replayDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Replaying GitHub API responses from %s\n", replayDir)
+	}
+
+	// With --explain-all, workflow files the filter skips are recorded with the decisions of each repository
+	filteredFiles := make(map[string][]string)
+	var recordFiltered func(repo github.Repository, path string)
+	if opts.ExplainAll {
+		recordFiltered = func(repo github.Repository, path string) {
+			filteredFiles[repo.FullName] = append(filteredFiles[repo.FullName], path)
+		}
+	}
+
+	githubClient := github.NewClientWithConfig(token, &github.Config{
+		Verbose:     verbose,
+		MaxFileSize: opts.MaxWorkflowSize,
+		Transport:   transport,
+		Timeout:     timeout,
+		FileFilter:  workflowFilter,
+		Filtered:    recordFiltered,
+		Tags:        requestTags(ctx),
+		APIURL:      githubAPIURL(ctx),
+		MaxTagPages: opts.MaxTagPages,
+
+		SkipListingCheck:  ctx.Is("skip-listing-check"),
+		TokenRepositories: ctx.Is("token-repos"),
+	})
+
+	if anonymous {
+		fmt.Fprintf(os.Stderr, "Warning: No GitHub token provided; scanning anonymously. Only public repositories are visible, custom properties are unavailable, and the API allows 60 requests per hour. Use --token or GITHUB_TOKEN for full access.\n")
+		if remaining, limit, reset, err := githubClient.GetRateLimit(); err == nil {
+			fmt.Fprintf(os.Stderr, "Anonymous rate limit: %d/%d requests remaining, resets at %s\n", remaining, limit, reset.Local().Format("15:04:05"))
+		}
+		if len(customProperties) > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: Ignoring --custom-property; custom properties require a token\n")
+			customProperties = nil
+		}
+		if teamProperty != "" {
+			fmt.Fprintf(os.Stderr, "Error: --team-property requires a token to read custom properties and pull requests\n")
+			return 1
+		}
+		if ctx.Is("token-repos") {
+			fmt.Fprintf(os.Stderr, "Error: --token-repos requires a token to list the repositories it can access\n")
+			return 1
+		}
+	}
+
+	// Create version resolver with shared cache
+	versionResolver := workflow.NewVersionResolverWithCache(githubClient, opts.SkipResolution, cacheInstance)
+	if comprehensiveCacheFlag != "" {
+		var patterns []string
+		for _, part := range strings.Split(comprehensiveCacheFlag, ",") {
+			if pattern := strings.TrimSpace(part); pattern != "" {
+				patterns = append(patterns, pattern)
+			}
+		}
+		versionResolver.SetComprehensiveRepositories(patterns)
+	}
+
+	// Load custom rules if provided
+	var customRules []actions.Rule
+	if rulesFile != "" {
+		if verbose {
+			log.Printf("Loading custom rules from file: %s", rulesFile)
+		}
+		var err error
+		customRules, err = loadRulesFromFile(rulesFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading rules file '%s': %v\n", rulesFile, err)
+			return 1
+		}
+		fmt.Printf("Loaded %d custom rules from %s\n", len(customRules), rulesFile)
+	}
+
+	// Pull canonical versions from an internal registry; rules file entries come first so they win
+	if registryURL != "" {
+		var mapping *registry.Mapping
+		if registryMappingFile != "" {
+			mapping, err = registry.LoadMappingFile(registryMappingFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading registry mapping '%s': %v\n", registryMappingFile, err)
+				return 1
+			}
+		}
+
+		registryClient := registry.NewClient(&registry.Config{
+			Verbose:    verbose,
+			URL:        registryURL,
+			Token:      registryToken,
+			Mapping:    mapping,
+			CacheDir:   registry.DefaultCacheDir(),
+			HTTPClient: &http.Client{Transport: transport, Timeout: registryTimeout(timeout)},
+		})
+		registryRules, err := registryClient.FetchRules()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading rules from registry '%s': %v\n", registryURL, err)
+			return 1
+		}
+		customRules = append(customRules, registryRules...)
+		fmt.Printf("Loaded %d rules from registry %s\n", len(registryRules), registryURL)
+	}
+
+	// Replace the pinned latest versions of rules with those their actions publish
+	if opts.ResolveLatest {
+		for _, resolution := range actions.ResolveLatestVersions(customRules, versionResolver, githubClient) {
+			switch {
+			case resolution.Err != nil:
+				fmt.Fprintf(os.Stderr, "Warning: failed to resolve the latest version of %s, keeping %s: %v\n", resolution.Repository, resolution.Previous, resolution.Err)
+			case resolution.Version != resolution.Previous:
+				fmt.Printf("Resolved latest version of %s from %s: %s (was %s)\n", resolution.Repository, resolution.Source, resolution.Version, resolution.Previous)
+			case verbose:
+				log.Printf("Latest version of %s from %s is unchanged: %s", resolution.Repository, resolution.Source, resolution.Version)
+			}
+		}
+	}
+
+	// Load the approved workflow sets if provided
+	var goldenSet *golden.GoldenSet
+	if goldenSetFile != "" {
+		goldenSet, err = golden.Load(goldenSetFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading workflow golden set file '%s': %v\n", goldenSetFile, err)
+			return 1
+		}
+	}
+
+	// Load the severity policy for non-production repositories if provided
+	var severityPolicy *downgrade.Policy
+	if severityPolicyFile != "" {
+		severityPolicy, err = downgrade.Load(severityPolicyFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading severity policy file '%s': %v\n", severityPolicyFile, err)
+			return 1
+		}
+	}
+
+	// Load the workflow_dispatch default policy if provided; the built-in one applies otherwise
+	var dispatchPolicy *dispatch.Policy
+	if dispatchPolicyFile != "" {
+		dispatchPolicy, err = dispatch.LoadPolicy(dispatchPolicyFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading dispatch policy file '%s': %v\n", dispatchPolicyFile, err)
+			return 1
+		}
+	} else if checkDispatchDefaults {
+		dispatchPolicy = &dispatch.DefaultPolicy
+	}
+
+	// Rule conditions, workflow classes, and ordering on custom properties need those properties fetched
+	if !anonymous {
+		neededProperties := actions.ConditionProperties(customRules)
+		if goldenSet != nil {
+			neededProperties = append(neededProperties, goldenSet.Properties()...)
+		}
+		if severityPolicy != nil {
+			neededProperties = append(neededProperties, severityPolicy.Properties()...)
+		}
+		if opts.RepositoryOrder != nil && opts.RepositoryOrder.By == priority.OrderProperty {
+			neededProperties = append(neededProperties, opts.RepositoryOrder.Property)
+		}
+		if teamProperty != "" {
+			neededProperties = append(neededProperties, teamProperty)
+		}
+		for _, name := range neededProperties {
+			requested := false
+			for _, property := range customProperties {
+				if property == name {
+					requested = true
+					break
+				}
+			}
+			if !requested {
+				customProperties = append(customProperties, name)
+			}
+		}
+	}
+
+	// Time version resolution separately from rule evaluation
+	timedResolver := actions.NewTimedResolver(versionResolver)
+
+	actionManager := actions.NewManagerWithResolverConfigAndRules(timedResolver, &actions.Config{
+		Verbose:             verbose,
+		PatchPreview:        patchPreview,
+		Checks:              opts.ActionChecks,
+		SkipResolution:      opts.SkipResolution,
+		WorkflowFilter:      workflowFilter,
+		DisableDefaultRules: opts.DisabledDefaultRules,
+	}, customRules)
+
+	// Per-issue hooks bridge findings into external systems such as ticketing
+	hookRunner := hooks.NewRunner(&hooks.Config{
+		Verbose:    verbose,
+		Command:    hookCommand,
+		WebhookURL: hookURL,
+		HTTPClient: &http.Client{Transport: transport, Timeout: timeout},
+	})
+
+	// Org-level duplicate step detection across all scanned repositories
+	var duplicateDetector *duplicates.Detector
+	if detectDuplicates {
+		duplicateDetector = duplicates.NewDetectorWithConfig(&duplicates.Config{Verbose: verbose})
+	}
+
+	// Workflow hygiene and release pipelines are checked while parsing, when the file content is at hand
+	hygieneAnalyzer := hygiene.NewAnalyzerWithConfig(&hygiene.Config{Verbose: verbose, Checks: opts.HygieneChecks})
+	hygieneIssues := make(map[string][]output.ActionIssue)
+	releaseAnalyzer := release.NewAnalyzerWithConfig(&release.Config{Verbose: verbose, Checks: opts.ReleaseChecks})
+
+	// Required action rules need jobs in file order, which only the outline keeps
+	workflowOutlines := make(map[string][]workflow.WorkflowOutline)
+
+	// Reusable workflow calls are checked against the secrets the called workflows declare
+	var callChecker *calls.Checker
+	reusableCalls := make(map[string][]workflow.ReusableCall)
+	if checkCalls {
+		callChecker = calls.NewCheckerWithConfig(githubClient, &calls.Config{Verbose: verbose, MaxFileSize: opts.MaxWorkflowSize})
+	}
+
+	triggerAnalyzer := triggers.NewAnalyzerWithConfig(githubClient, &triggers.Config{Verbose: verbose})
+
+	// Run history lookups are opt-in since they cost API calls per workflow file
+	var usageAnalyzer *usage.Analyzer
+	if opts.WorkflowUsageDays > 0 {
+		usageAnalyzer = usage.NewAnalyzerWithConfig(githubClient, &usage.Config{Verbose: verbose, WindowDays: opts.WorkflowUsageDays})
+	}
+	var minutesEstimator *billing.Estimator
+	if opts.EstimateMinutesDays > 0 {
+		minutesEstimator = billing.NewEstimatorWithConfig(githubClient, &billing.Config{Verbose: verbose, WindowDays: opts.EstimateMinutesDays})
+	}
+	var imageChecker *images.Checker
+	if opts.ImageMaxAgeDays > 0 {
+		imageChecker = images.NewCheckerWithConfig(&images.Config{Verbose: verbose, MaxAgeDays: opts.ImageMaxAgeDays})
+	}
+	// Secret inventories need an authenticated token; anonymous scans map flows without scopes
+	var secretResolver *secretflow.Resolver
+	if mapSecrets && !anonymous {
+		secretResolver = secretflow.NewResolverWithConfig(githubClient, &secretflow.Config{Verbose: verbose})
+	}
+
+	// Release date lookups for outdated issues share the version cache
+	var ageAnnotator *actions.AgeAnnotator
+	if pinAge {
+		ageAnnotator = actions.NewAgeAnnotator(githubClient, cacheInstance, &actions.Config{Verbose: verbose})
+	}
+
+	// Action repositories can announce their own deprecation
+	var deprecationAnnotator *actions.DeprecationAnnotator
+	if checkDeprecationNotices {
+		deprecationAnnotator = actions.NewDeprecationAnnotator(githubClient, &actions.Config{Verbose: verbose})
+	}
+
+	// Load report template overrides if provided
+	reportTemplates, err := loadReportTemplates(reportTemplateDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading report templates: %v\n", err)
+		return 1
+	}
+	linkMapping, err := loadLinks(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading link mappings: %v\n", err)
+		return 1
+	}
+	reportTemplates.SetLinks(linkMapping)
+
+	// Load issue suppressions if provided
+	var suppressions *suppress.Set
+	if suppressionsFile != "" {
+		if verbose {
+			log.Printf("Loading suppressions from file: %s", suppressionsFile)
+		}
+		var err error
+		suppressions, err = suppress.LoadFile(suppressionsFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading suppressions file '%s': %v\n", suppressionsFile, err)
+			return 1
+		}
+		fmt.Printf("Loaded %d suppressions from %s\n", suppressions.Len(), suppressionsFile)
+
+		for _, expired := range suppressions.Expired(time.Now()) {
+			fmt.Printf("Warning: Suppression for %s in %s expired on %s and no longer applies\n", expired.Action, expired.Repository, expired.Until)
+		}
+	}
+
+	// Load the priority score weights if provided
+	priorityWeights := priority.DefaultWeights
+	if priorityWeightsFile != "" {
+		weights, err := priority.LoadWeights(priorityWeightsFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading priority weights file '%s': %v\n", priorityWeightsFile, err)
+			return 1
+		}
+		priorityWeights = weights
+	}
+
+	// Load the issue description templates if provided
+	var descriptionTemplates *output.DescriptionTemplates
+	if descriptionTemplatesFile != "" {
+		templates, err := output.LoadDescriptionTemplates(descriptionTemplatesFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading description templates file '%s': %v\n", descriptionTemplatesFile, err)
+			return 1
+		}
+		templates.SetLinks(linkMapping)
+		descriptionTemplates = templates
+	}
+
+	// Load the baseline scan if provided; it is read before the output file is created,
+	// so the same file can be used for both
+	var scanBaseline *baseline.Baseline
+	if baselineFile != "" {
+		if verbose {
+			log.Printf("Loading baseline from file: %s", baselineFile)
+		}
+		data, err := os.ReadFile(baselineFile)
+		if err == nil {
+			data, err = prepareScanInput(ctx, data)
+		}
+		if err == nil {
+			scanBaseline, err = baseline.Load(bytes.NewReader(data))
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading baseline file '%s': %v\n", baselineFile, err)
+			return 1
+		}
+		fmt.Printf("Loaded %d baseline issues from %s\n", scanBaseline.Len(), baselineFile)
+	}
+
+	// Pull requests measured by --team-property are recorded in the baseline by run pipelines, and in the ledger by create-pr
+	trackedPRs := append([]output.CreatedPR{}, scanBaseline.CreatedPRs()...)
+	if ledgerFile != "" {
+		prLedger, err := ledger.Open(ledgerFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening ledger '%s': %v\n", ledgerFile, err)
+			return 1
+		}
+		for _, entry := range prLedger.Entries() {
+			trackedPRs = append(trackedPRs, entry.PR)
+		}
+	}
+
+	// Perform scan
+	fmt.Printf("Fetching repositories...\n")
+
+	// First, get basic repository list without custom properties, from the snapshot while it is fresh
+	var repositories []github.Repository
+	var reposSnapshot *snapshot.Snapshot
+	if reposSnapshotFile != "" {
+		reposSnapshot, err = snapshot.Load(reposSnapshotFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v; listing repositories instead\n", err)
+			reposSnapshot = nil
+		}
+	}
+	if reposSnapshot != nil && reposSnapshot.Fresh(owner, opts.ReposSnapshotTTL, time.Now()) {
+		repositories = reposSnapshot.Repositories
+		fmt.Printf("Using repository snapshot %s from %s ago\n", reposSnapshotFile, reposSnapshot.Age(time.Now()).Round(time.Minute))
+	} else {
+		apiStart := time.Now()
+		repositories, err = githubClient.ListRepositories(owner)
+		timing.API += time.Since(apiStart)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing repositories: %v\n", err)
+			return 1
+		}
+		if reposSnapshotFile != "" {
+			if err := snapshot.Save(reposSnapshotFile, owner, repositories); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			} else if verbose {
+				log.Printf("Saved repository snapshot to %s", reposSnapshotFile)
+			}
+		}
+	}
+
+	fmt.Printf("Found %d repositories\n", len(repositories))
+
+	// Apply repository filter if provided
+	if filterPattern != "" {
+		fmt.Printf("Applying filter pattern: %s\n", filterPattern)
+		filterRegex, err := regexp.Compile(filterPattern)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid filter regex pattern '%s': %v\n", filterPattern, err)
+			return 1
+		}
+
+		var filteredRepositories []github.Repository
+		for _, repo := range repositories {
+			if filterRegex.MatchString(repo.Name) {
+				filteredRepositories = append(filteredRepositories, repo)
+			}
+		}
+
+		fmt.Printf("Filtered repositories: %d/%d match pattern\n", len(filteredRepositories), len(repositories))
+		repositories = filteredRepositories
+	}
+
+	// Trial runs scan --max-repos repositories, cut down before custom properties are fetched unless the
+	// first ones are decided by a custom property; ordering again below is stable, so it keeps this order
+	if opts.Sample || opts.RepositoryOrder == nil || opts.RepositoryOrder.By != priority.OrderProperty {
+		priority.OrderRepositories(repositories, opts.RepositoryOrder, scanBaseline.IssueCount)
+		repositories = limitRepositories(repositories, opts.MaxRepos, opts.Sample, opts.SampleSeed)
+	}
+
+	// Predict the rest of the scan from the repository count, with listing already paid for
+	if estimateOnly {
+		usingSnapshot := reposSnapshot != nil && reposSnapshot.Fresh(owner, opts.ReposSnapshotTTL, time.Now())
+		estimatedRepositories := len(repositories)
+		if opts.MaxRepos > 0 && estimatedRepositories > opts.MaxRepos {
+			estimatedRepositories = opts.MaxRepos
+		}
+		estimate := budget.EstimateScan(budget.ScanProfile{
+			Repositories:         estimatedRepositories,
+			ListCalls:            githubClient.RequestStats().Requests,
+			SnapshotUsed:         usingSnapshot,
+			WorkflowDirs:         len(workflowDirs),
+			CustomProperties:     len(customProperties) > 0,
+			SkipResolution:       opts.SkipResolution,
+			PinAge:               pinAge,
+			DeprecationNotices:   checkDeprecationNotices,
+			WorkflowUsage:        opts.WorkflowUsageDays > 0,
+			TagProtectionActions: opts.TagProtectionConsumers > 0,
+			MajorTagActions:      checkMajorTags,
+			ReusableCalls:        checkCalls,
+			InternalActions:      opts.InternalActionConsumers > 0,
+		})
+		remaining, limit, reset, err := githubClient.GetRateLimit()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to read the rate limit: %v\n", err)
+		}
+		estimate.Write(os.Stdout, remaining, limit, reset)
+		return 0
+	}
+
+	// Now fetch custom properties only for filtered repositories
+	if len(customProperties) > 0 {
+		fmt.Printf("Fetching custom properties for %d repositories: %v\n", len(repositories), customProperties)
+		apiStart := time.Now()
+		for i := range repositories {
+			props, err := githubClient.GetRepositoryCustomProperties(repositories[i].Owner, repositories[i].Name, customProperties)
+			if err != nil {
+				if verbose {
+					log.Printf("Warning: Failed to fetch custom properties for %s: %v", repositories[i].FullName, err)
+				}
+				// Continue with empty properties rather than failing
+			}
+			repositories[i].CustomProperties = props
+		}
+		timing.API += time.Since(apiStart)
+	}
+
+	// Scan the most important repositories first, so a budget cuts off the least important
+	if opts.RepositoryOrder != nil {
+		fmt.Printf("Ordering repositories by %s\n", orderByFlag)
+		priority.OrderRepositories(repositories, opts.RepositoryOrder, scanBaseline.IssueCount)
+		repositories = limitRepositories(repositories, opts.MaxRepos, opts.Sample, opts.SampleSeed)
+	}
+
+	// Log output is recorded per repository so it can be attached to each result
+	var logRecorder *logcapture.Recorder
+	if captureLogs {
+		previousOutput := log.Writer()
+		logRecorder = logcapture.NewRecorder(previousOutput)
+		log.SetOutput(logRecorder)
+		defer log.SetOutput(previousOutput)
+	}
+
+	// Workflows are fetched and parsed for every repository before rules are evaluated
+	var scannedRepositories, unscannedRepositories []output.RepositoryResult
+
+	// Repositories whose workflow files could not be fetched or parsed count against --max-failures;
+	// past it the scan stops rather than writing results that silently miss them
+	var failures []string
+	failureBudgetExceeded := func(repository string, err error) bool {
+		failures = append(failures, fmt.Sprintf("%s: %v", repository, err))
+		if !opts.FailureBudget.Exceeded(len(failures), len(repositories)) {
+			return false
+		}
+		fmt.Fprintf(os.Stderr, "Error: %d of %d repositories failed to scan, more than --max-failures %s allows; aborting without writing results\n", len(failures), len(repositories), opts.FailureBudget)
+		for _, failure := range failures {
+			fmt.Fprintf(os.Stderr, "  %s\n", failure)
+		}
+		return true
+	}
+
+	// Scan each repository
+	budgetExhausted := ""
+	for i, repo := range repositories {
+		// Stop cleanly when the budget runs out, recording the repositories left so the results show they are missing
+		if budgetExhausted = scanBudget.Exhausted(githubClient.RequestStats().Requests); budgetExhausted != "" {
+			fmt.Fprintf(os.Stderr, "Warning: Scan budget exhausted (%s); %d/%d repositories not scanned\n", budgetExhausted, len(repositories)-i, len(repositories))
+			for _, remaining := range repositories[i:] {
+				unscannedRepositories = append(unscannedRepositories, output.RepositoryResult{
+					Name:             remaining.Name,
+					FullName:         remaining.FullName,
+					DefaultBranch:    remaining.DefaultBranch,
+					CustomProperties: remaining.CustomProperties,
+					Topics:           remaining.Topics,
+					Language:         remaining.Language,
+					Status:           output.RepositoryStatusOverBudget,
+				})
+			}
+			break
+		}
+
+		fmt.Printf("Scanning repository %d/%d: %s\n", i+1, len(repositories), repo.FullName)
+		logRecorder.Start()
+
+		// Get workflow files
+		apiStart := time.Now()
+		repoWorkflowDirs := workflowDirs
+		if !skipWorkflowTemplates {
+			repoWorkflowDirs = github.WorkflowDirsFor(repo, workflowDirs)
+		}
+		workflowFiles, err := githubClient.GetWorkflowFilesInDirs(repo, repoWorkflowDirs)
+		timing.API += time.Since(apiStart)
+		reason := github.NoWorkflowsReason(err)
+		if err != nil && reason == "" {
+			fmt.Printf("Warning: Failed to get workflow files for %s: %v\n", repo.FullName, err)
+			unscannedRepositories = append(unscannedRepositories, output.RepositoryResult{
+				Name:             repo.Name,
+				FullName:         repo.FullName,
+				DefaultBranch:    repo.DefaultBranch,
+				CustomProperties: repo.CustomProperties,
+				Topics:           repo.Topics,
+				Language:         repo.Language,
+				Logs:             logRecorder.Stop(),
+				Status:           output.RepositoryStatusFailed,
+				Error:            err.Error(),
+			})
+			if failureBudgetExceeded(repo.FullName, err) {
+				return 1
+			}
+			if anonymous && github.IsRateLimited(err) {
+				fmt.Fprintf(os.Stderr, "Warning: Anonymous rate limit exhausted after %d/%d repositories; results are partial. Provide a token to scan the rest.\n", i, len(repositories))
+				break
+			}
+			continue
+		}
+
+		status := ""
+		switch {
+		case reason == github.ContentEmpty:
+			fmt.Printf("  Repository is empty\n")
+			status = output.RepositoryStatusEmpty
+		case reason == github.ContentSubmodule:
+			fmt.Printf("  No workflow files: %v\n", err)
+			status = output.RepositoryStatusSubmodule
+		case len(workflowFiles) == 0:
+			fmt.Printf("  No workflow files found\n")
+			status = output.RepositoryStatusNoWorkflows
+		}
+
+		// Repositories without workflows are recorded with their status but not analyzed
+		if status != "" {
+			unscannedRepositories = append(unscannedRepositories, output.RepositoryResult{
+				Name:             repo.Name,
+				FullName:         repo.FullName,
+				DefaultBranch:    repo.DefaultBranch,
+				CustomProperties: repo.CustomProperties,
+				Topics:           repo.Topics,
+				Language:         repo.Language,
+				Logs:             logRecorder.Stop(),
+				Status:           status,
+			})
+			continue
+		}
+
+		fmt.Printf("  Found %d workflow files\n", len(workflowFiles))
+
+		var repoActions []workflow.ActionReference
+		var workflowFileResults []output.WorkflowFileResult
+		var triggerInfos []workflow.TriggerInfo
+		var secretFlows []workflow.SecretFlow
+		var containerImages []workflow.ContainerImage
+		var toolSetups []workflow.ToolSetup
+		var environments []workflow.EnvironmentUsage
+
+		// Parse each workflow file
+		var parseErr error
+		for _, wf := range workflowFiles {
+			if wf.TooLarge {
+				fmt.Printf("  Warning: Skipped %s: %d bytes exceeds size limit of %d\n", wf.Path, wf.Size, opts.MaxWorkflowSize)
+				logRecorder.Notef("Warning: Skipped %s: %d bytes exceeds size limit of %d", wf.Path, wf.Size, opts.MaxWorkflowSize)
+				workflowFileResults = append(workflowFileResults, output.WorkflowFileResult{
+					Path:      wf.Path,
+					Status:    output.WorkflowStatusSkippedTooLarge,
+					Size:      wf.Size,
+					BlobSHA:   wf.SHA,
+					CommitSHA: wf.CommitSHA,
+					Template:  github.IsWorkflowTemplate(repo, wf.Path),
+				})
+				continue
+			}
+
+			if verbose {
+				log.Printf("Parsing workflow file: %s", wf.Path)
+			}
+			parseStart := time.Now()
+			actions, err := workflow.ParseWorkflowWithConfig(wf.Content, wf.Path, repo.FullName, &workflow.Config{
+				Verbose:     verbose,
+				MaxFileSize: opts.MaxWorkflowSize,
+			})
+			if err == nil {
+				if info, triggerErr := workflow.ParseTriggers(wf.Content, wf.Path, &workflow.Config{
+					Verbose:     verbose,
+					MaxFileSize: opts.MaxWorkflowSize,
+				}); triggerErr == nil {
+					triggerInfos = append(triggerInfos, *info)
+				}
+			}
+			// Token usage is read from every parsed file for the permissions summary
+			var tokenUsage *workflow.TokenUsage
+			if err == nil {
+				tokenUsage, _ = workflow.ParseTokenUsage(wf.Content, wf.Path, &workflow.Config{
+					Verbose:     verbose,
+					MaxFileSize: opts.MaxWorkflowSize,
+				})
+			}
+			if err == nil {
+				if jobImages, imageErr := workflow.ParseContainerImages(wf.Content, wf.Path, &workflow.Config{
+					Verbose:     verbose,
+					MaxFileSize: opts.MaxWorkflowSize,
+				}); imageErr == nil {
+					containerImages = append(containerImages, jobImages...)
+				}
+			}
+			if err == nil {
+				if setups, setupErr := workflow.ParseToolSetups(wf.Content, wf.Path, &workflow.Config{
+					Verbose:     verbose,
+					MaxFileSize: opts.MaxWorkflowSize,
+				}); setupErr == nil {
+					toolSetups = append(toolSetups, setups...)
+				}
+			}
+			if err == nil {
+				if jobEnvironments, environmentErr := workflow.ParseEnvironments(wf.Content, wf.Path, &workflow.Config{
+					Verbose:     verbose,
+					MaxFileSize: opts.MaxWorkflowSize,
+				}); environmentErr == nil {
+					environments = append(environments, jobEnvironments...)
+				}
+			}
+			if err == nil && mapSecrets {
+				if flows, flowErr := workflow.ParseSecretFlows(wf.Content, wf.Path, &workflow.Config{
+					Verbose:     verbose,
+					MaxFileSize: opts.MaxWorkflowSize,
+				}); flowErr == nil {
+					secretFlows = append(secretFlows, flows...)
+				}
+			}
+			if err == nil && actionManager.HasRequirements() {
+				if outline, outlineErr := workflow.ParseWorkflowOutline(wf.Content, wf.Path, &workflow.Config{
+					Verbose:     verbose,
+					MaxFileSize: opts.MaxWorkflowSize,
+				}); outlineErr == nil {
+					workflowOutlines[repo.FullName] = append(workflowOutlines[repo.FullName], *outline)
+				}
+			}
+			if err == nil && callChecker != nil {
+				if jobCalls, callsErr := workflow.ParseReusableCalls(wf.Content, wf.Path, &workflow.Config{
+					Verbose:     verbose,
+					MaxFileSize: opts.MaxWorkflowSize,
+				}); callsErr == nil {
+					reusableCalls[repo.FullName] = append(reusableCalls[repo.FullName], jobCalls...)
+				}
+			}
+			if err == nil && hygieneAnalyzer.Enabled() {
+				if jobs, settingsErr := workflow.ParseJobSettings(wf.Content, wf.Path, &workflow.Config{
+					Verbose:     verbose,
+					MaxFileSize: opts.MaxWorkflowSize,
+				}); settingsErr == nil {
+					hygieneIssues[repo.FullName] = append(hygieneIssues[repo.FullName], hygieneAnalyzer.Analyze(wf.Path, jobs)...)
+				}
+				if concurrency, concurrencyErr := workflow.ParseConcurrency(wf.Content, wf.Path, &workflow.Config{
+					Verbose:     verbose,
+					MaxFileSize: opts.MaxWorkflowSize,
+				}); concurrencyErr == nil {
+					hygieneIssues[repo.FullName] = append(hygieneIssues[repo.FullName], hygieneAnalyzer.AnalyzeConcurrency(concurrency)...)
+				}
+				if cacheSteps, cacheErr := workflow.ParseCacheSteps(wf.Content, wf.Path, &workflow.Config{
+					Verbose:     verbose,
+					MaxFileSize: opts.MaxWorkflowSize,
+				}); cacheErr == nil {
+					hygieneIssues[repo.FullName] = append(hygieneIssues[repo.FullName], hygieneAnalyzer.AnalyzeCacheSteps(cacheSteps)...)
+				}
+				if graphProblems, graphErr := workflow.ParseJobGraph(wf.Content, wf.Path, &workflow.Config{
+					Verbose:     verbose,
+					MaxFileSize: opts.MaxWorkflowSize,
+				}); graphErr == nil {
+					hygieneIssues[repo.FullName] = append(hygieneIssues[repo.FullName], hygieneAnalyzer.AnalyzeJobGraph(wf.Path, graphProblems)...)
+				}
+			}
+			if err == nil && dispatchPolicy != nil {
+				if inputs, dispatchErr := workflow.ParseDispatchInputs(wf.Content, wf.Path, &workflow.Config{
+					Verbose:     verbose,
+					MaxFileSize: opts.MaxWorkflowSize,
+				}); dispatchErr == nil {
+					hygieneIssues[repo.FullName] = append(hygieneIssues[repo.FullName], dispatchPolicy.Check(inputs)...)
+				}
+			}
+			if err == nil && releaseAnalyzer.Enabled() {
+				if steps, releaseErr := workflow.ParseReleaseSteps(wf.Content, wf.Path, &workflow.Config{
+					Verbose:     verbose,
+					MaxFileSize: opts.MaxWorkflowSize,
+				}); releaseErr == nil {
+					hygieneIssues[repo.FullName] = append(hygieneIssues[repo.FullName], releaseAnalyzer.Analyze(wf.Path, steps)...)
+				}
+			}
+			if err == nil && duplicateDetector != nil {
+				jobs, stepsErr := workflow.ExtractJobSteps(wf.Content, wf.Path, repo.FullName, &workflow.Config{
+					Verbose:     verbose,
+					MaxFileSize: opts.MaxWorkflowSize,
+				})
+				if stepsErr == nil {
+					duplicateDetector.AddJobs(jobs)
+				}
+			}
+			timing.Parse += time.Since(parseStart)
+			if err != nil {
+				fmt.Printf("  Warning: Failed to parse %s: %v\n", wf.Path, err)
+				logRecorder.Notef("Warning: Failed to parse %s: %v", wf.Path, err)
+
+				// Record guarded files so they show up in the results instead of silently disappearing
+				// Invalid files count against --max-failures; guarded files are skipped by design
+				status, message := output.WorkflowStatusParseFailed, err.Error()
+				if errors.Is(err, workflow.ErrWorkflowTooLarge) {
+					status, message = output.WorkflowStatusSkippedTooLarge, ""
+				} else if errors.Is(err, workflow.ErrWorkflowTooComplex) {
+					status, message = output.WorkflowStatusSkippedTooComplex, ""
+				} else if parseErr == nil {
+					parseErr = fmt.Errorf("failed to parse %s: %w", wf.Path, err)
+				}
+				workflowFileResults = append(workflowFileResults, output.WorkflowFileResult{
+					Path:      wf.Path,
+					Status:    status,
+					Error:     message,
+					Size:      len(wf.Content),
+					BlobSHA:   wf.SHA,
+					CommitSHA: wf.CommitSHA,
+					Template:  github.IsWorkflowTemplate(repo, wf.Path),
+				})
+				continue
+			}
+
+			fmt.Printf("    %s: %d actions\n", wf.Path, len(actions))
+
+			repoActions = append(repoActions, actions...)
+			workflowFileResults = append(workflowFileResults, output.WorkflowFileResult{
+				Path:        wf.Path,
+				ActionCount: len(actions),
+				Actions:     actions,
+				BlobSHA:     wf.SHA,
+				CommitSHA:   wf.CommitSHA,
+				Template:    github.IsWorkflowTemplate(repo, wf.Path),
+				TokenUsage:  tokenUsage,
+			})
+		}
+
+		scannedRepositories = append(scannedRepositories, output.RepositoryResult{
+			Name:             repo.Name,
+			FullName:         repo.FullName,
+			DefaultBranch:    repo.DefaultBranch,
+			WorkflowFiles:    workflowFileResults,
+			Actions:          repoActions,
+			CustomProperties: repo.CustomProperties,
+			Topics:           repo.Topics,
+			Language:         repo.Language,
+			Triggers:         triggerInfos,
+			SecretFlows:      secretFlows,
+			ContainerImages:  containerImages,
+			ToolSetups:       toolSetups,
+			Environments:     environments,
+			Logs:             logRecorder.Stop(),
+		})
+		if parseErr != nil && failureBudgetExceeded(repo.FullName, parseErr) {
+			return 1
+		}
+	}
+	if len(failures) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %d/%d repositories failed to scan; they are recorded with status %q or workflow files with status %q\n",
+			len(failures), len(repositories), output.RepositoryStatusFailed, output.WorkflowStatusParseFailed)
+	}
+
+	// Rule evaluation runs across repositories in parallel. With --capture-logs each repository is analyzed
+	// with its own logger, so its lines are recorded apart from those of repositories analyzed alongside it.
+	analyzeStart := time.Now()
+	var analyzed [][]output.ActionIssue
+	var analysisLogs [][]string
+	if captureLogs {
+		analyzed, analysisLogs = actionManager.AnalyzeRepositoriesWithLogs(scannedRepositories, log.Writer())
+	} else {
+		analyzed = actionManager.AnalyzeRepositories(scannedRepositories)
+	}
+	timing.Analyze += time.Since(analyzeStart)
+
+	repositoryResults := make([]output.RepositoryResult, 0, len(scannedRepositories))
+	for i, repoResult := range scannedRepositories {
+		fmt.Printf("Analyzing repository %d/%d: %s\n", i+1, len(scannedRepositories), repoResult.FullName)
+		logRecorder.Start()
+
+		analyzeStart := time.Now()
+		issues := analyzed[i]
+		if ageAnnotator != nil {
+			ageAnnotator.Annotate(issues)
+		}
+		if deprecationAnnotator != nil {
+			issues = deprecationAnnotator.Annotate(repoResult.Actions, issues)
+		}
+		issues = append(issues, triggerAnalyzer.Analyze(repoResult.FullName, repoResult.Triggers)...)
+		issues = append(issues, hygieneIssues[repoResult.FullName]...)
+		issues = append(issues, actionManager.CheckRequiredActions(repoResult, workflowOutlines[repoResult.FullName])...)
+		if goldenSet != nil {
+			issues = append(issues, goldenSet.Check(repoResult)...)
+		}
+		timing.Analyze += time.Since(analyzeStart)
+		if callChecker != nil {
+			callsStart := time.Now()
+			issues = append(issues, callChecker.Check(repoResult, reusableCalls[repoResult.FullName])...)
+			timing.API += time.Since(callsStart)
+		}
+		if writeLocksDir != "" || verifyLocksDir != "" {
+			lockStart := time.Now()
+			issues = append(issues, lockRepository(repoResult, versionResolver, writeLocksDir, verifyLocksDir, logRecorder)...)
+			timing.API += time.Since(lockStart)
+		}
+		if usageAnalyzer != nil {
+			usageStart := time.Now()
+			issues = append(issues, usageAnalyzer.Analyze(repoResult.FullName, repoResult.WorkflowFiles)...)
+			timing.API += time.Since(usageStart)
+		}
+		if minutesEstimator != nil {
+			minutesStart := time.Now()
+			minutesEstimator.Estimate(repoResult.FullName, repoResult.WorkflowFiles)
+			timing.API += time.Since(minutesStart)
+		}
+		if imageChecker != nil {
+			imagesStart := time.Now()
+			issues = append(issues, imageChecker.Check(repoResult.ContainerImages)...)
+			timing.API += time.Since(imagesStart)
+		}
+		if secretResolver != nil {
+			resolveStart := time.Now()
+			secretResolver.Resolve(repoResult.FullName, repoResult.SecretFlows)
+			timing.API += time.Since(resolveStart)
+		}
+		issues, excludedIssues := severityPolicy.Apply(repoResult, issues)
+		issues, suppressedIssues := suppressions.Apply(repoResult.FullName, issues, time.Now())
+
+		if len(suppressedIssues) > 0 {
+			fmt.Printf("  Suppressed %d issues\n", len(suppressedIssues))
+		}
+		if len(excludedIssues) > 0 {
+			fmt.Printf("  Excluded %d issues by severity policy\n", len(excludedIssues))
+			suppressedIssues = append(suppressedIssues, excludedIssues...)
+		}
+
+		existingCount := scanBaseline.Mark(repoResult.FullName, issues)
+
+		for _, issue := range issues {
+			if err := hookRunner.Run(hooks.IssueEvent(repoResult.FullName, issue)); err != nil {
+				fmt.Printf("  Warning: Hook failed for %s in %s: %v\n", issue.Repository, issue.FilePath, err)
+				logRecorder.Notef("Warning: Hook failed for %s in %s: %v", issue.Repository, issue.FilePath, err)
+			}
+		}
+
+		if len(issues) > 0 {
+			if existingCount > 0 {
+				fmt.Printf("  Found %d issues (%d new, %d existing)\n", len(issues), len(issues)-existingCount, existingCount)
+			} else {
+				fmt.Printf("  Found %d issues\n", len(issues))
+			}
+			if verbose {
+				for _, issue := range issues {
+					log.Printf("Issue found: %s@%s - %s (severity: %s)", issue.Repository, issue.CurrentVersion, issue.IssueType, issue.Severity)
+				}
+			}
+		}
+
+		repoResult.Issues = issues
+		repoResult.SuppressedIssues = suppressedIssues
+		if opts.ExplainAll {
+			repoResult.Decisions = output.RecordReported(actionManager.ExplainRepository(repoResult), issues, suppressedIssues)
+			for _, path := range filteredFiles[repoResult.FullName] {
+				repoResult.Decisions = append(repoResult.Decisions, output.SkippedFileDecision(path, output.DecisionStageWorkflowFilter, "excluded by --workflow-filter or --exclude-workflow"))
+			}
+		}
+		if captureLogs {
+			repoResult.Logs = append(repoResult.Logs, analysisLogs[i]...)
+		}
+		repoResult.Logs = append(repoResult.Logs, logRecorder.Stop()...)
+		repositoryResults = append(repositoryResults, repoResult)
+	}
+
+	repositoryResults = append(repositoryResults, unscannedRepositories...)
+
+	// Repositories on GitHub Enterprise Server record their host, so results of several hosts can be merged
+	if apiURL := githubAPIURL(ctx); apiURL != "" {
+		for i := range repositoryResults {
+			repositoryResults[i].Host = github.APIHost(apiURL)
+		}
+	}
+
+	// Findings in shared reusable workflows count their callers once every repository is known
+	output.CountWorkflowConsumers(repositoryResults)
+
+	// Priority scores weigh how widely each action is used, so they are set once every repository is analyzed
+	priority.Score(repositoryResults, priorityWeights)
+	output.AnnotateRules(repositoryResults, docsBaseURL)
+	// Descriptions are templated after rule annotation, so templates can link to rule docs
+	if err := descriptionTemplates.Apply(repositoryResults); err != nil {
+		fmt.Printf("Warning: some issue descriptions kept their default text: %v\n", err)
+	}
+
+	// Build final scan result
+	scanResult := output.BuildScanResult(owner, repositoryResults)
+	scanResult.Summary.SeverityHistory = scanBaseline.History()
+	if teamProperty != "" {
+		if len(trackedPRs) == 0 {
+			fmt.Printf("Warning: no pull requests to measure for --team-property; the baseline lists none, so pass the create-pr --ledger file\n")
+		} else {
+			pulls := verify.PullRequests(githubClient, trackedPRs, verbose)
+			scanResult.Summary.MergeLatency = verify.MergeLatency(pulls, verify.Teams(scanResult.Repositories, teamProperty))
+			measured := 0
+			for _, latency := range scanResult.Summary.MergeLatency {
+				measured += latency.PullRequests
+			}
+			fmt.Printf("Measured time-to-merge of %d pull requests across %d teams\n", measured, len(scanResult.Summary.MergeLatency))
+		}
+	}
+	if debtPolicy != nil {
+		scanResult.Summary.Debt = output.CalculateDebt(scanResult.Summary, debtPolicy, scanResult.ScanTime)
+	}
+	if duplicateDetector != nil {
+		scanResult.ReusableWorkflowCandidates = duplicateDetector.Clusters()
+		fmt.Printf("Found %d step sequences repeated across repositories (reusable workflow candidates)\n", len(scanResult.ReusableWorkflowCandidates))
+	}
+	if opts.TagProtectionConsumers > 0 {
+		checker := tagprotection.NewCheckerWithConfig(githubClient, &tagprotection.Config{Verbose: verbose, MinConsumers: opts.TagProtectionConsumers})
+		scanResult.TagProtectionFindings = checker.Check(scanResult.Repositories)
+		fmt.Printf("Found %d widely used internal actions with unprotected release tags\n", len(scanResult.TagProtectionFindings))
+	}
+	if checkMajorTags {
+		// Tag listings share the version cache with resolution
+		checker := majortags.NewCheckerWithConfig(versionResolver, &majortags.Config{Verbose: verbose})
+		scanResult.MajorTagFindings = checker.Check(scanResult.Repositories)
+		fmt.Printf("Found %d major tags of internal actions behind their newest release\n", len(scanResult.MajorTagFindings))
+	}
+	if opts.InternalActionConsumers > 0 {
+		reporter := consumers.NewReporterWithConfig(githubClient, &consumers.Config{Verbose: verbose, MinConsumers: opts.InternalActionConsumers})
+		scanResult.InternalActions = reporter.Report(scanResult.Repositories)
+		fmt.Printf("Reported consumers of %d internal actions\n", len(scanResult.InternalActions))
+	}
+
+	// Analysis time includes resolver calls; report them separately
+	timing.Resolve, timing.ResolverCalls = timedResolver.Elapsed()
+	timing.Analyze -= timing.Resolve
+	if timing.Analyze < 0 {
+		timing.Analyze = 0
+	}
+	requestStats := githubClient.RequestStats()
+	timing.APIRequests = requestStats.Requests
+	timing.APIRequestTime = requestStats.Duration
+	scanResult.Summary.Timing = timing
+
+	fmt.Printf("Timing: api=%s parse=%s analyze=%s resolve=%s (%d API requests, %d resolver calls)\n",
+		timing.API.Round(time.Millisecond), timing.Parse.Round(time.Millisecond), timing.Analyze.Round(time.Millisecond),
+		timing.Resolve.Round(time.Millisecond), timing.APIRequests, timing.ResolverCalls)
+
+	// Finalize scan result with timing
+	output.FinalizeScanResult(scanResult)
+
+	// Every output is written from the same result, in the format of its extension
+	for _, outputFile := range outputFiles {
+		if splitSpec != nil && outputFile == splitIndexFile {
+			if err := writeSplitScanResult(scanResult, *splitSpec, outputFile, encryptRecipient); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing split output: %v\n", err)
+				return 1
+			}
+			continue
+		}
+		if err := writeResultFile(scanResult, outputFile, terminal, encryptRecipient, reportTemplates); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", outputName(outputFile), err)
+			return 1
+		}
+	}
+
+	if githubAnnotations {
+		if err := writeGitHubAnnotations(scanResult); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing GitHub annotations: %v\n", err)
+			return 1
+		}
+	}
+
+	exitCode := 0
+	if opts.FailOn != "" {
+		if gating := output.GatingIssues(scanResult, opts.FailOn); len(gating) > 0 {
+			fmt.Fprintf(os.Stderr, "Found %d new issues at or above %s severity (--fail-on %s)\n", len(gating), opts.FailOn, opts.FailOn)
+			exitCode = exitCodeGateFailed
+		}
+	}
+	if ctx.Is("fail-on-debt") && debtGateFailed(scanResult) {
+		exitCode = exitCodeGateFailed
+	}
+	if exitCode == 0 && budgetExhausted != "" {
+		fmt.Fprintf(os.Stderr, "Scan stopped early: %s\n", budgetExhausted)
+		exitCode = exitCodeBudgetExhausted
+	}
+
+	if err := writeExitSummary(output.BuildExitSummary(scanResult, opts.FailOn, budgetExhausted, exitCode), summaryFile); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing exit summary: %v\n", err)
+		return 1
+	}
+
+	return exitCode
+}
+
+// exitSummaryFileName is the exit summary written to RUNNER_TEMP in GitHub Actions when no --summary-file is given
+const exitSummaryFileName = "actions-maintainer-summary.json"
+
+// writeExitSummary writes the outcome of a scan to summaryFile and, in GitHub Actions, sets it as step
+// outputs through GITHUB_OUTPUT. In GitHub Actions the summary file defaults to
+// $RUNNER_TEMP/actions-maintainer-summary.json; elsewhere it is only written when given.
+func writeExitSummary(summary output.ExitSummary, summaryFile string) error {
+	inActions := os.Getenv("GITHUB_ACTIONS") == "true"
+	if summaryFile == "" && inActions && os.Getenv("RUNNER_TEMP") != "" {
+		summaryFile = filepath.Join(os.Getenv("RUNNER_TEMP"), exitSummaryFileName)
+	}
+
+	if summaryFile != "" {
+		file, err := output.CreateOutputFile(summaryFile)
+		if err != nil {
+			return fmt.Errorf("failed to create summary file: %w", err)
+		}
+		if err := output.WriteExitSummary(file, summary); err != nil {
+			file.Close()
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return fmt.Errorf("failed to write summary file: %w", err)
+		}
+	}
+
+	outputPath := os.Getenv("GITHUB_OUTPUT")
+	if !inActions || outputPath == "" {
+		return nil
+	}
+	file, err := os.OpenFile(outputPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open GitHub outputs: %w", err)
+	}
+	defer file.Close()
+	if err := output.WriteGitHubOutputs(file, summary); err != nil {
+		return err
+	}
+	if summaryFile != "" {
+		if _, err := fmt.Fprintf(file, "summary-file=%s\n", summaryFile); err != nil {
+			return fmt.Errorf("failed to write GitHub outputs: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeResultFile writes a scan result to a file, or to stdout as configured by terminal when the path is empty
+// The file format follows the extension: .ipynb for a notebook, .sarif for SARIF, .parquet for the
+// flattened action inventory, and JSON otherwise. A further .gz or .zst extension compresses the file,
+// e.g. scan.json.gz or scan.sarif.zst.
+func writeResultFile(result *output.ScanResult, outputFile string, terminal terminalOutput, encryptRecipient string, templates *output.ReportTemplates) (err error) {
+	var outputWriter io.Writer = os.Stdout
+	if outputFile != "" {
+		file, err := output.CreateOutputFile(outputFile)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		outputWriter = file
+	}
+
+	// Encrypt the output if recipients are given. age runs as a child process, so it is closed and
+	// waited for on every return, before the file is closed.
+	if encryptRecipient != "" {
+		encryptWriter, startErr := encrypt.NewWriter(outputWriter, encrypt.ParseRecipients(encryptRecipient))
+		if startErr != nil {
+			return fmt.Errorf("failed to start output encryption: %w", startErr)
+		}
+		defer func() {
+			if closeErr := encryptWriter.Close(); closeErr != nil && err == nil {
+				err = fmt.Errorf("failed to encrypt output: %w", closeErr)
+			}
+		}()
+		outputWriter = encryptWriter
+	}
+
+	// Compress before encrypting, as encrypted data does not compress
+	compressWriter, err := compress.NewWriter(outputWriter, compress.FormatForPath(outputFile))
+	if err != nil {
+		return err
+	}
+
+	formatErr := formatResult(result, compressWriter, outputFile, terminal, templates)
+	if err := compressWriter.Close(); err != nil && formatErr == nil {
+		return fmt.Errorf("failed to compress output: %w", err)
+	}
+	return formatErr
+}
+
+// formatResult writes a scan result in the format of the output file, or the terminal format for stdout
+func formatResult(result *output.ScanResult, w io.Writer, outputFile string, terminal terminalOutput, templates *output.ReportTemplates) error {
+	switch {
+	case outputFile == "" && terminal.Format == output.TableFormat:
+		if err := output.FormatTableWithColor(result, w, terminal.Color); err != nil {
+			return fmt.Errorf("failed to format table output: %w", err)
+		}
+	case outputFile == "" && terminal.Format == output.ProblemsFormat:
+		// Paths are relative to the working directory, where editors and CI jobs run the scan
+		if err := output.FormatProblems(result, w, "."); err != nil {
+			return fmt.Errorf("failed to format problems output: %w", err)
+		}
+	case output.IsNotebookFile(outputFile):
+		if err := output.FormatNotebookWithTemplates(result, w, templates); err != nil {
+			return fmt.Errorf("failed to format notebook output: %w", err)
+		}
+	case output.IsSARIFFile(outputFile):
+		if err := output.FormatSARIF(result, w); err != nil {
+			return fmt.Errorf("failed to format SARIF output: %w", err)
+		}
+	case output.IsParquetFile(outputFile):
+		if err := output.FormatParquet(result, w); err != nil {
+			return fmt.Errorf("failed to format Parquet output: %w", err)
+		}
+	default:
+		if err := output.FormatJSON(result, w, true); err != nil {
+			return fmt.Errorf("failed to format JSON output: %w", err)
+		}
+	}
+	return nil
+}
+
+// terminalOutput configures results written to stdout
+type terminalOutput struct {
+	Format string // output.TableFormat, output.JSONFormat, or output.ProblemsFormat
+	Color  bool   // Color severities in the table
+}
+
+// terminalOutputOptions reads --format and --no-color for results written to stdout
+// Encrypted output stays JSON unless a format is given, since it is meant for later commands.
+func terminalOutputOptions(ctx climax.Context, encryptRecipient string) (terminalOutput, error) {
+	formatFlag, _ := ctx.Get("format")
+	format, err := output.ParseFormat(formatFlag)
+	if err != nil {
+		return terminalOutput{}, err
+	}
+	if formatFlag == "" && encryptRecipient != "" {
+		format = output.JSONFormat
+	}
+	return terminalOutput{
+		Format: format,
+		Color:  output.UseColor(os.Stdout, ctx.Is("no-color")),
+	}, nil
+}
+
+// outputName describes an output path in messages
+func outputName(outputFile string) string {
+	if outputFile == "" {
+		return "output"
+	}
+	return outputFile
+}
+
+// writeGitHubAnnotations annotates issues in the current repository and appends a job summary
+// It does nothing outside GitHub Actions, so the same command works locally.
+func writeGitHubAnnotations(result *output.ScanResult) error {
+	if os.Getenv("GITHUB_ACTIONS") != "true" {
+		fmt.Fprintf(os.Stderr, "Warning: Ignoring --github-annotations; not running in GitHub Actions\n")
+		return nil
+	}
+
+	repository := os.Getenv("GITHUB_REPOSITORY")
+	count, err := output.WriteGitHubAnnotations(os.Stdout, result, repository, os.Getenv("GITHUB_WORKSPACE"))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Annotated %d issues in %s\n", count, repository)
+
+	summaryPath := os.Getenv("GITHUB_STEP_SUMMARY")
+	if summaryPath == "" {
+		return nil
+	}
+	file, err := os.OpenFile(summaryPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open job summary: %w", err)
+	}
+	defer file.Close()
+	return output.WriteGitHubJobSummary(file, result, repository)
+}
+
+func handleReport(ctx climax.Context) int {
+	inputFile, _ := ctx.Get("input")
+	outputFlag, _ := ctx.Get("output")
+	outputFiles := output.ParseOutputPaths(outputFlag)
+	encryptRecipient, _ := ctx.Get("encrypt-recipient")
+	reportTemplateDir, _ := ctx.Get("report-template-dir")
+	groupIssues := ctx.Is("group-issues")
+	redact := ctx.Is("redact")
+	approvalsFile, _ := ctx.Get("emit-approvals")
+	merge := ctx.Is("merge")
+
+	debtPolicy, err := loadDebtTargets(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if merge && (len(ctx.Args) == 0 || inputFile != "") {
+		fmt.Fprintf(os.Stderr, "Error: --merge takes the scan result files as arguments and cannot be combined with --input\n")
+		return 1
+	}
+
+	var redactKey []byte
+	if redact {
+		key, err := loadRedactKey(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		redactKey = key
+	}
+
+	terminal, err := terminalOutputOptions(ctx, encryptRecipient)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	reportTemplates, err := loadReportTemplates(reportTemplateDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading report templates: %v\n", err)
+		return 1
+	}
+	linkMapping, err := loadLinks(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading link mappings: %v\n", err)
+		return 1
+	}
+	reportTemplates.SetLinks(linkMapping)
+
+	// Determine output formats based on file extensions
+	notebooksOnly := true
+	for _, outputFile := range outputFiles {
+		if !output.IsNotebookFile(outputFile) {
+			notebooksOnly = false
+		}
+	}
+	streamJSON := len(outputFiles) == 1 && !output.IsNotebookFile(outputFiles[0]) && !output.IsSARIFFile(outputFiles[0]) &&
+		!output.IsParquetFile(outputFiles[0]) && !redact && !merge &&
+		(outputFiles[0] != "" || terminal.Format == output.JSONFormat)
+
+	// Open JSON input for streaming; merged inputs are read in full below
+	inputReader, closeInput := io.Reader(nil), func() error { return nil }
+	if !merge {
+		inputReader, closeInput, err = openScanInput(ctx, inputFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+			return 1
+		}
+	}
+
+	// A single JSON report is written one repository at a time; other formats, several outputs,
+	// and redaction need every repository
+	var jsonStream *output.JSONStreamWriter
+	var encryptWriter *encrypt.Writer
+	var compressWriter io.WriteCloser
+	if streamJSON {
+		var outputWriter io.Writer = os.Stdout
+		if outputFiles[0] != "" {
+			file, err := output.CreateOutputFile(outputFiles[0])
+			if err != nil {
+				closeInput()
+				fmt.Fprintf(os.Stderr, "Error creating output file: %v\n", err)
+				return 1
+			}
+			defer file.Close()
+			outputWriter = file
+		}
+
+		// Encrypt the output if recipients are given
+		if encryptRecipient != "" {
+			encryptWriter, err = encrypt.NewWriter(outputWriter, encrypt.ParseRecipients(encryptRecipient))
+			if err != nil {
+				closeInput()
+				fmt.Fprintf(os.Stderr, "Error starting output encryption: %v\n", err)
+				return 1
+			}
+			outputWriter = encryptWriter
+		}
+		compressWriter, err = compress.NewWriter(outputWriter, compress.FormatForPath(outputFiles[0]))
+		if err != nil {
+			closeInput()
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		jsonStream = output.NewJSONStreamWriter(compressWriter)
+	}
+
+	var repositories, approvalRepositories []output.RepositoryResult
+	processRepository := func(repo *output.RepositoryResult) error {
+		if groupIssues {
+			repo.IssueGroups = output.GroupIssues(repo.Issues)
+		}
+		if approvalsFile != "" && len(repo.Issues) > 0 {
+			// Only the issues are needed to plan updates, so the rest of a streamed repository is not kept
+			approvalRepositories = append(approvalRepositories, output.RepositoryResult{
+				Name:          repo.Name,
+				FullName:      repo.FullName,
+				DefaultBranch: repo.DefaultBranch,
+				Issues:        repo.Issues,
+			})
+		}
+		if jsonStream != nil {
+			return jsonStream.WriteRepository(repo)
+		}
+		if notebooksOnly && reportTemplates == nil {
+			// Built-in notebook sections only count actions, so per-file action lists are dropped to save memory
+			for i := range repo.WorkflowFiles {
+				repo.WorkflowFiles[i].Actions = nil
+			}
+		}
+		repositories = append(repositories, *repo)
+		return nil
+	}
+
+	var scanResult *output.ScanResult
+	if merge {
+		scanResult, err = mergeScanInputs(ctx, ctx.Args)
+		if err == nil {
+			for i := range scanResult.Repositories {
+				processRepository(&scanResult.Repositories[i])
+			}
+		}
+	} else {
+		scanResult, err = output.DecodeScanResult(inputReader, processRepository)
+	}
+	// A decryption failure explains any parse error it caused
+	if closeErr := closeInput(); closeErr != nil {
+		err = closeErr
+	}
+	if err != nil {
+		if encryptWriter != nil {
+			encryptWriter.Close()
+		}
+		fmt.Fprintf(os.Stderr, "Error parsing JSON input: %v\n", err)
+		return 1
+	}
+	scanResult.Repositories = repositories
+
+	// Approvals are written before redaction, since create-pr matches them against the scan results
+	if approvalsFile != "" {
+		if err := emitApprovals(approvalsFile, approvalRepositories); err != nil {
+			if encryptWriter != nil {
+				encryptWriter.Close()
+			}
+			fmt.Fprintf(os.Stderr, "Error writing approvals: %v\n", err)
+			return 1
+		}
+	}
+
+	if redact {
+		scanResult.Redact(redactKey)
+	}
+
+	// Debt is scored again against the targets given to the report, such as this quarter's
+	exitCode := 0
+	if debtPolicy != nil {
+		scanResult.Summary.Debt = output.CalculateDebt(scanResult.Summary, debtPolicy, time.Now())
+	}
+	if ctx.Is("fail-on-debt") && debtGateFailed(scanResult) {
+		exitCode = exitCodeGateFailed
+	}
+
+	if jsonStream != nil {
+		if err := jsonStream.Close(scanResult); err != nil {
+			fmt.Fprintf(os.Stderr, "Error formatting JSON output: %v\n", err)
+			return 1
+		}
+		if err := compressWriter.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error compressing output: %v\n", err)
+			return 1
+		}
+		if encryptWriter != nil {
+			if err := encryptWriter.Close(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error encrypting output: %v\n", err)
+				return 1
+			}
+		}
+		return exitCode
+	}
+
+	for _, outputFile := range outputFiles {
+		if err := writeResultFile(scanResult, outputFile, terminal, encryptRecipient, reportTemplates); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", outputName(outputFile), err)
+			return 1
+		}
+	}
+
+	return exitCode
+}
+
+// loadDebtTargets reads the --debt-targets file, if given, and checks that --fail-on-debt has targets
+// to gate on
+func loadDebtTargets(ctx climax.Context) (*output.DebtPolicy, error) {
+	debtTargetsFile, _ := ctx.Get("debt-targets")
+	if debtTargetsFile == "" {
+		if ctx.Is("fail-on-debt") {
+			return nil, fmt.Errorf("--fail-on-debt requires --debt-targets")
+		}
+		return nil, nil
+	}
+
+	policy, err := output.LoadDebtPolicy(debtTargetsFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading debt targets file '%s': %w", debtTargetsFile, err)
+	}
+	if ctx.Is("fail-on-debt") && len(policy.Targets) == 0 {
+		return nil, fmt.Errorf("--fail-on-debt requires targets in the debt targets file")
+	}
+	return policy, nil
+}
+
+// debtGateFailed reports whether the debt score of a result is above its current target, printing why
+func debtGateFailed(result *output.ScanResult) bool {
+	debt := result.Summary.Debt
+	if debt == nil || debt.Target == nil {
+		fmt.Fprintf(os.Stderr, "Warning: No current debt target; every target in --debt-targets has passed\n")
+		return false
+	}
+	if !debt.AboveTarget() {
+		return false
+	}
+	fmt.Fprintf(os.Stderr, "Actions debt score %g is above the target of %g (--fail-on-debt)\n", debt.Score, *debt.Target)
+	return true
+}
+
+// mergeScanInputs reads each scan result file, decrypting age-encrypted files with --decrypt-identity,
+// and merges them into one result
+func mergeScanInputs(ctx climax.Context, files []string) (*output.ScanResult, error) {
+	results := make([]*output.ScanResult, 0, len(files))
+	for _, file := range files {
+		input, closeInput, err := openScanInput(ctx, file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		var repositories []output.RepositoryResult
+		result, err := output.DecodeScanResult(input, func(repo *output.RepositoryResult) error {
+			repositories = append(repositories, *repo)
+			return nil
+		})
+		if closeErr := closeInput(); closeErr != nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		result.Repositories = repositories
+		results = append(results, result)
+	}
+
+	merged := output.MergeScanResults(results)
+	fmt.Fprintf(os.Stderr, "Merged %d scan results: %d repositories across %d owners\n", len(results), len(merged.Repositories), len(merged.Summary.Owners))
+	return merged, nil
+}
+
+// emitApprovals writes the updates planned for the repositories to an approvals file, keeping the
+// approvals of any file already there
+func emitApprovals(filename string, repositories []output.RepositoryResult) error {
+	file := approvals.Build(repositories)
+	if _, err := os.Stat(filename); err == nil {
+		previous, err := approvals.Load(filename)
+		if err != nil {
+			return err
+		}
+		file.KeepApprovals(previous)
+	}
+	if err := file.Save(filename); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %d planned updates to %s (%d approved)\n", len(file.Updates), filename, file.Approved())
+	return nil
+}
+
+func handleCreatePR(ctx climax.Context) int {
+	if planFile, _ := ctx.Get("plan"); planFile != "" {
+		return executePlan(ctx, planFile)
+	}
+	for _, name := range []string{"resume", "chunk"} {
+		if value, _ := ctx.Get(name); value != "" || ctx.Is(name) {
+			fmt.Fprintf(os.Stderr, "Error: --%s requires --plan\n", name)
+			return 1
+		}
+	}
+	return createPRs(ctx, false)
+}
+
+func handlePlan(ctx climax.Context) int {
+	return createPRs(ctx, true)
+}
+
+// createPRs plans pull requests from scan results and creates them, or with planOnly writes the plans to
+// the plan command's --output file
+func createPRs(ctx climax.Context, planOnly bool) int {
+	inputFile, _ := ctx.Get("input")
+	ledgerFile, _ := ctx.Get("ledger")
+	changelogFile, _ := ctx.Get("changelog")
+	commitMessageFlag, _ := ctx.Get("commit-message")
+	commitLayoutFlag, _ := ctx.Get("commit-layout")
+	templateFile, _ := ctx.Get("template")
+	filterPattern, _ := ctx.Get("filter")
+
+	token, app, err := githubCredentials(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	dispatchTimeout, err := dispatchCheckTimeout(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	var baseBranchRules []pr.BaseBranchRule
+	if baseBranchesFile, _ := ctx.Get("base-branches"); baseBranchesFile != "" {
+		rules, err := pr.LoadBaseBranchRules(baseBranchesFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading base branches: %v\n", err)
+			return 1
+		}
+		baseBranchRules = rules
+	}
+
+	var commitMessage *template.Template
+	if commitMessageFlag != "" {
+		tmpl, err := pr.ParseCommitMessage(commitMessageFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --commit-message: %v\n", err)
+			return 1
+		}
+		commitMessage = tmpl
+	}
+	commitLayout := pr.CommitLayoutSingle
+	if commitLayoutFlag != "" {
+		layout, err := pr.ParseCommitLayout(commitLayoutFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --commit-layout: %v\n", err)
+			return 1
+		}
+		commitLayout = layout
+	}
+
+	// Validate the rollout mode before reading input
+	canaryFlag, _ := ctx.Get("canary")
+	promote := ctx.Is("promote")
+	if canaryFlag != "" && promote {
+		fmt.Fprintf(os.Stderr, "Error: --canary and --promote cannot be combined\n")
+		return 1
+	}
+	var canarySize canary.Size
+	var canaryProperty *canary.Property
+	if canaryFlag != "" {
+		canarySize, err = canary.ParseSize(canaryFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if propertyFlag, _ := ctx.Get("canary-property"); propertyFlag != "" {
+			canaryProperty, err = canary.ParseProperty(propertyFlag)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 1
+			}
+		}
+	}
+	canaryStateFile, _ := ctx.Get("canary-state")
+	if canaryStateFile == "" {
+		canaryStateFile = canary.DefaultStateFile
+	}
+	var canaryState *canary.State
+	if canaryFlag != "" || promote {
+		canaryState, err = canary.LoadState(canaryStateFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if canaryFlag != "" && canaryState.Active() {
+			fmt.Fprintf(os.Stderr, "Error: the canary rollout in %s has not been promoted; run create-pr --promote or remove the file\n", canaryStateFile)
+			return 1
+		}
+		if promote && !canaryState.Active() {
+			fmt.Fprintf(os.Stderr, "Error: no canary rollout to promote in %s\n", canaryStateFile)
+			return 1
+		}
+	}
+
+	// Load the wave plan before reading input
+	var waveRepositories map[string]bool
+	if waveFlag, _ := ctx.Get("wave"); waveFlag != "" {
+		wave, err := strconv.Atoi(waveFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --wave must be a wave number\n")
+			return 1
+		}
+		wavePlanFile, _ := ctx.Get("wave-plan")
+		if wavePlanFile == "" {
+			wavePlanFile = waves.DefaultPlanFile
+		}
+		wavePlan, err := waves.LoadPlan(wavePlanFile)
+		if err == nil {
+			waveRepositories, err = wavePlan.Repositories(wave)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Wave %d: %d repositories in %s\n", wave, len(waveRepositories), wavePlanFile)
+	}
+
+	maxReviewers := 0
+	if maxReviewersFlag, _ := ctx.Get("max-reviewers"); maxReviewersFlag != "" {
+		maxReviewers, err = strconv.Atoi(maxReviewersFlag)
+		if err != nil || maxReviewers < 1 {
+			fmt.Fprintf(os.Stderr, "Error: --max-reviewers must be a positive number\n")
+			return 1
+		}
+	}
+
+	reviewMode := ctx.Is("review-mode")
+	reviewPR := 0
+	if reviewPRFlag, _ := ctx.Get("review-pr"); reviewPRFlag != "" {
+		reviewPR, err = strconv.Atoi(reviewPRFlag)
+		if err != nil || reviewPR < 1 {
+			fmt.Fprintf(os.Stderr, "Error: --review-pr must be a pull request number\n")
+			return 1
+		}
+		if !reviewMode {
+			fmt.Fprintf(os.Stderr, "Error: --review-pr requires --review-mode\n")
+			return 1
+		}
+	}
+	if reviewMode && ledgerFile != "" {
+		fmt.Fprintf(os.Stderr, "Error: --ledger records update pull requests and cannot be combined with --review-mode\n")
+		return 1
+	}
+	if reviewMode && ctx.Is("dispatch-check") {
+		fmt.Fprintf(os.Stderr, "Error: --dispatch-check runs the patched workflows of update pull requests and cannot be combined with --review-mode\n")
+		return 1
+	}
+
+	coexistFlag, _ := ctx.Get("coexist-mode")
+	coexistMode, err := pr.ParseCoexistMode(coexistFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	// Load approvals before reading input
+	var approvalFile *approvals.File
+	if approvalsFlag, _ := ctx.Get("approvals"); approvalsFlag != "" {
+		approvalFile, err = approvals.Load(approvalsFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Approvals: %d/%d updates approved in %s\n", approvalFile.Approved(), len(approvalFile.Updates), approvalsFlag)
+	}
+
+	// Compile the repository filter before reading input
+	var filterRegex *regexp.Regexp
+	if filterPattern != "" {
+		fmt.Printf("Applying filter pattern: %s\n", filterPattern)
+		filterRegex, err = regexp.Compile(filterPattern)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid filter regex pattern '%s': %v\n", filterPattern, err)
+			return 1
+		}
+	}
+
+	inputReader, closeInput, err := openScanInput(ctx, inputFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+
+	// Plan updates one repository at a time so large scan files are not held in memory
+	var updatePlans []pr.UpdatePlan
+	basePatterns := make(map[string][]string)
+	totalRepositories, matchedRepositories, skippedExisting, skippedStale, skippedUnapproved := 0, 0, 0, 0, 0
+	_, err = output.DecodeScanResult(inputReader, func(repo *output.RepositoryResult) error {
+		totalRepositories++
+		if filterRegex != nil && !filterRegex.MatchString(repo.Name) {
+			return nil
+		}
+		if waveRepositories != nil && !waveRepositories[repo.FullName] {
+			return nil
+		}
+		matchedRepositories++
+
+		repositories := []output.RepositoryResult{*repo}
+		// Issues already present in the baseline are left alone unless requested
+		if !ctx.Is("include-existing") {
+			skippedExisting += baseline.ExcludeExisting(repositories)
+		}
+		// Workflows that no longer run are not worth updating
+		if ctx.Is("skip-stale-workflows") {
+			skippedStale += usage.ExcludeStaleWorkflows(repositories)
+		}
+		if approvalFile != nil {
+			skippedUnapproved += approvalFile.ExcludeUnapproved(repositories)
+		}
+		updatePlans = append(updatePlans, pr.PlanUpdates(repositories)...)
+		if baseBranchRules != nil {
+			basePatterns[repo.FullName] = pr.BaseBranchPatterns(baseBranchRules, *repo)
+		}
+		return nil
+	})
+	// A decryption failure explains any parse error it caused
+	if closeErr := closeInput(); closeErr != nil {
+		err = closeErr
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing JSON input: %v\n", err)
+		return 1
+	}
+
+	if filterRegex != nil || waveRepositories != nil {
+		fmt.Printf("Filtered repositories: %d/%d selected\n", matchedRepositories, totalRepositories)
+	}
+
+	// Create GitHub client
+	transport, timeout, err := networkOptions(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	githubClient := github.NewClientWithConfig(token, &github.Config{
+		Transport: transport,
+		Timeout:   timeout,
+		Tags:      requestTags(ctx),
+		APIURL:    githubAPIURL(ctx),
+		App:       app,
+	})
+
+	// Predict the run from the plans before the token preflight and checks make any calls
+	if ctx.Is("estimate") {
+		profile := budget.PRProfile{
+			PullRequests: len(updatePlans),
+			Coexist:      coexistMode != pr.CoexistIgnore,
+			DriftCheck:   !ctx.Is("allow-stale"),
+			CodeOwners:   !ctx.Is("no-codeowners"),
+		}
+		for _, plan := range updatePlans {
+			workflows := make(map[string]bool)
+			for _, update := range plan.Updates {
+				workflows[update.FilePath] = true
+			}
+			profile.Files += len(workflows) + len(plan.FilePaths())
+			profile.Workflows += len(workflows)
+			if !pr.PlanReviewers(plan).Empty() {
+				profile.Reviewers++
+			}
+		}
+		fmt.Printf("Planned %d pull requests changing %d files\n", profile.PullRequests, profile.Files)
+		if baseBranchRules != nil {
+			fmt.Printf("Note: --base-branches may open more than one pull request per repository; the estimate counts one\n")
+		}
+		remaining, limit, reset, err := githubClient.GetRateLimit()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to read the rate limit: %v\n", err)
+		}
+		budget.EstimatePRs(profile).Write(os.Stdout, remaining, limit, reset)
+		return 0
+	}
+
+	// Preflight the token so scope, SSO, and expiry problems surface before any changes are pushed
+	tokenInfo, err := githubClient.GetTokenInfo()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: token preflight failed: %v\n", err)
+		return 1
+	}
+	printTokenPreflight(tokenInfo)
+
+	auditLog, err := openAuditLog(ctx, "create-pr", githubClient, tokenInfo.Login)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer auditLog.Close()
+
+	// Avoid conflicting with Dependabot and Renovate pull requests that already update the same actions
+	if coexistMode != pr.CoexistIgnore {
+		var coexistingPlans []pr.UpdatePlan
+		for _, plan := range updatePlans {
+			pulls, err := pr.FindBotPullRequests(githubClient, plan)
+			if err != nil {
+				fmt.Printf("Warning: %s: %v\n", plan.Repository.FullName, err)
+				coexistingPlans = append(coexistingPlans, plan)
+				continue
+			}
+			for _, pull := range pulls {
+				if coexistMode == pr.CoexistSkip {
+					fmt.Printf("Skipping %s in %s: %s already has open pull request #%d\n", pull.ActionRepo, plan.Repository.FullName, pull.PullRequest.Author, pull.PullRequest.Number)
+				} else {
+					fmt.Printf("Superseding #%d in %s: %s\n", pull.PullRequest.Number, plan.Repository.FullName, pull.PullRequest.Title)
+				}
+			}
+			plan = pr.Coexist(plan, pulls, coexistMode)
+			if len(plan.Updates) == 0 {
+				continue
+			}
+			coexistingPlans = append(coexistingPlans, plan)
+		}
+		updatePlans = coexistingPlans
+	}
+
+	// Patches are computed from the scanned content, so workflows changed since the scan would be overwritten
+	if !ctx.Is("allow-stale") {
+		var freshPlans []pr.UpdatePlan
+		for _, plan := range updatePlans {
+			stale, err := pr.CheckDrift(githubClient, plan)
+			if err != nil {
+				fmt.Printf("Skipping %s: %v\n", plan.Repository.FullName, err)
+				continue
+			}
+			if len(stale) > 0 {
+				paths := make([]string, 0, len(stale))
+				for _, file := range stale {
+					paths = append(paths, file.Path)
+				}
+				fmt.Printf("Skipping %s: %s changed since the scan; rescan the repository to update it\n", plan.Repository.FullName, strings.Join(paths, ", "))
+				continue
+			}
+			freshPlans = append(freshPlans, plan)
+		}
+		updatePlans = freshPlans
+	}
+
+	// Plan one pull request per configured base branch
+	if baseBranchRules != nil {
+		var branchPlans []pr.UpdatePlan
+		for _, plan := range updatePlans {
+			plans, err := pr.ExpandBaseBranches(githubClient, plan, basePatterns[plan.Repository.FullName])
+			if err != nil {
+				fmt.Printf("Skipping %s: %v\n", plan.Repository.FullName, err)
+				continue
+			}
+			branchPlans = append(branchPlans, plans...)
+		}
+		updatePlans = branchPlans
+	}
+
+	// Warn about required status checks the updates may rename, which would block the merge
+	for i, plan := range updatePlans {
+		affected, err := pr.FindAffectedChecks(githubClient, plan)
+		if err != nil {
+			fmt.Printf("Warning: %s: %v\n", plan.Repository.FullName, err)
+			continue
+		}
+		for _, check := range affected {
+			fmt.Printf("Warning: %s: required status check %q on %s is reported by job %s in %s, which calls the updated %s; merging may be blocked if the check is renamed\n",
+				plan.Repository.FullName, check.Check, plan.TargetBranch(), check.Job, check.FilePath, check.Action)
+		}
+		updatePlans[i].RequiredChecks = affected
+	}
+
+	// Warn about run scripts that hardcode the action tags or workflow files the updates change
+	for i, plan := range updatePlans {
+		couplings, err := pr.FindScriptCouplings(githubClient, plan)
+		if err != nil {
+			fmt.Printf("Warning: %s: %v\n", plan.Repository.FullName, err)
+			continue
+		}
+		for _, coupling := range couplings {
+			fmt.Printf("Warning: %s: script-coupling: %s in %s: %s\n", plan.Repository.FullName, coupling.Context, coupling.FilePath, coupling.Message)
+		}
+		updatePlans[i].ScriptCouplings = couplings
+	}
+
+	// Request reviews from the code owners of the changed files, as branch protection may require them
+	for i, plan := range updatePlans {
+		updatePlans[i].MaxReviewers = maxReviewers
+		if ctx.Is("no-codeowners") {
+			continue
+		}
+		owners, err := pr.FindCodeOwners(githubClient, plan)
+		if err != nil {
+			fmt.Printf("Warning: %s: %v\n", plan.Repository.FullName, err)
+			continue
+		}
+		updatePlans[i].CodeOwners = owners
+	}
+
+	// Load custom template if provided
+	var prCreator *pr.Creator
+	if templateFile != "" {
+		tmpl, err := loadTemplateFromFile(templateFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading template file: %v\n", err)
+			return 1
+		}
+		prCreator = pr.NewCreatorWithTemplate(githubClient, tmpl)
+	} else {
+		prCreator = pr.NewCreator(githubClient)
+	}
+	linkMapping, err := loadLinks(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading link mappings: %v\n", err)
+		return 1
+	}
+	prCreator.SetLinks(linkMapping)
+
+	if skippedExisting > 0 {
+		fmt.Printf("Skipping %d existing issues from the scan baseline (use --include-existing to include them)\n", skippedExisting)
+	}
+	if skippedStale > 0 {
+		fmt.Printf("Skipping %d issues in workflows that did not run within the usage window\n", skippedStale)
+	}
+	if skippedUnapproved > 0 {
+		fmt.Printf("Skipping %d issues whose updates are not approved\n", skippedUnapproved)
+	}
+
+	// Plan hooks can veto repositories, e.g. for change approval
+	hookCommand, _ := ctx.Get("hook-command")
+	hookURL, _ := ctx.Get("hook-url")
+	if hookRunner := hooks.NewRunner(&hooks.Config{
+		Command:    hookCommand,
+		WebhookURL: hookURL,
+		HTTPClient: &http.Client{Transport: transport, Timeout: timeout},
+	}); hookRunner != nil {
+		var approvedPlans []pr.UpdatePlan
+		for _, plan := range updatePlans {
+			if err := hookRunner.Run(hooks.PlanEvent(plan)); err != nil {
+				fmt.Printf("Skipping %s: %v\n", plan.Repository.FullName, err)
+				continue
+			}
+			approvedPlans = append(approvedPlans, plan)
+		}
+		updatePlans = approvedPlans
+	}
+
+	// Canary rollouts open a cohort first and promote the remainder once its pull requests merge
+	var canaryRemainder []pr.UpdatePlan
+	switch {
+	case canaryFlag != "":
+		var cohort []pr.UpdatePlan
+		cohort, canaryRemainder = canary.Split(updatePlans, canarySize, canaryProperty)
+		fmt.Printf("Canary: creating pull requests for %d of %d repositories; %d held back until --promote\n",
+			len(canary.RepositoryNames(cohort)), len(canary.RepositoryNames(updatePlans)), len(canary.RepositoryNames(canaryRemainder)))
+		updatePlans = cohort
+	case promote:
+		pending, err := canaryState.Pending(githubClient)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if len(pending) > 0 {
+			fmt.Fprintf(os.Stderr, "Error: not promoting; %d canary pull requests have not merged:\n", len(pending))
+			for _, reason := range pending {
+				fmt.Fprintf(os.Stderr, "  %s\n", reason)
+			}
+			return 1
+		}
+		fmt.Printf("Canary: all %d pull requests merged, promoting to the remaining repositories\n", len(canaryState.Cohort))
+		updatePlans = canaryState.Exclude(updatePlans)
+	}
+
+	// The plan command writes the pull requests for review instead of creating them
+	if planOnly {
+		pr.SortByPriority(updatePlans)
+		return savePlan(ctx, updatePlans, inputFile)
+	}
+
+	if len(updatePlans) == 0 {
+		fmt.Printf("No updates needed - all actions are up to date!\n")
+		if changelogFile != "" {
+			if err := writeChangelog(changelogFile, nil); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 1
+			}
+		}
+		if promote {
+			return saveCanaryPromotion(canaryState, canaryStateFile)
+		}
+		return 0
+	}
+
+	// Riskiest repositories first, so an interrupted run has opened the pull requests that matter most
+	pr.SortByPriority(updatePlans)
+
+	// An existing pull request belongs to one repository and base branch
+	if reviewPR > 0 && len(updatePlans) > 1 {
+		fmt.Fprintf(os.Stderr, "Error: --review-pr reviews one pull request, but %d repositories or base branches have updates; use --filter to select one\n", len(updatePlans))
+		return 1
+	}
+
+	fmt.Printf("Creating pull requests for updates...\n")
+	fmt.Printf("Planning updates for %d repositories\n", len(updatePlans))
+
+	prCreator.SetAuditLog(auditLog)
+	prCreator.SetCommitMessage(commitMessage)
+	prCreator.SetCommitLayout(commitLayout)
+	if ledgerFile != "" {
+		prLedger, err := ledger.Open(ledgerFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if prLedger.Len() > 0 {
+			fmt.Printf("Ledger %s records %d pull requests already created\n", ledgerFile, prLedger.Len())
+		}
+		prCreator.SetLedger(prLedger)
+	}
+	var createdPRs []output.CreatedPR
+	if reviewMode {
+		createdPRs, err = prCreator.CreateReviews(updatePlans, reviewPR)
+	} else {
+		createdPRs, err = prCreator.CreateUpdatePRs(updatePlans)
+	}
+	// The changelog lists the pull requests created before any failure, too
+	if changelogFile != "" {
+		if changelogErr := writeChangelog(changelogFile, pr.NewChangelog(updatePlans, createdPRs)); changelogErr != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", changelogErr)
+			return 1
+		}
+		fmt.Printf("Wrote the changelog of %d pull requests to %s\n", len(createdPRs), changelogFile)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating PRs: %v\n", err)
+		return 1
+	}
+	if err := auditLog.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	// Output created PRs information
+	for _, createdPR := range createdPRs {
+		if createdPR.BaseBranch != "" {
+			fmt.Printf("Created PR for %s (%s): %s\n", createdPR.Repository, createdPR.BaseBranch, createdPR.URL)
+			continue
+		}
+		fmt.Printf("Created PR for %s: %s\n", createdPR.Repository, createdPR.URL)
+	}
+
+	if reviewMode {
+		fmt.Printf("Successfully reviewed %d pull requests\n", len(createdPRs))
+	} else {
+		fmt.Printf("Successfully created %d pull requests\n", len(createdPRs))
+	}
+	if ctx.Is("dispatch-check") {
+		runDispatchChecks(githubClient, updatePlans, createdPRs, dispatchTimeout)
+	}
+
+	switch {
+	case canaryFlag != "":
+		state := canary.NewState(canarySize, createdPRs, canaryRemainder, time.Now())
+		if err := state.Save(canaryStateFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Recorded the canary cohort in %s; run create-pr --promote once its pull requests merge\n", canaryStateFile)
+	case promote:
+		return saveCanaryPromotion(canaryState, canaryStateFile)
+	}
+	return 0
+}
+
+// dispatchCheckTimeout returns the --dispatch-timeout, 30 minutes by default
+func dispatchCheckTimeout(ctx climax.Context) (time.Duration, error) {
+	timeoutFlag, _ := ctx.Get("dispatch-timeout")
+	if timeoutFlag == "" {
+		return 30 * time.Minute, nil
+	}
+	if !ctx.Is("dispatch-check") {
+		return 0, fmt.Errorf("--dispatch-timeout requires --dispatch-check")
+	}
+	timeout, err := time.ParseDuration(timeoutFlag)
+	if err != nil || timeout < 0 {
+		return 0, fmt.Errorf("--dispatch-timeout must be a duration (e.g., 30m, 1h)")
+	}
+	return timeout, nil
+}
+
+// runDispatchChecks runs the workflows the created pull requests patch with schema transformations on
+// their branches, waits for the runs, and comments the conclusions on the pull requests
+func runDispatchChecks(githubClient *github.Client, plans []pr.UpdatePlan, createdPRs []output.CreatedPR, timeout time.Duration) {
+	plansByBranch := make(map[string]pr.UpdatePlan)
+	for _, plan := range plans {
+		plansByBranch[plan.Repository.FullName+"\x00"+plan.BranchName()] = plan
+	}
+
+	var all []*pr.DispatchChecks
+	dispatched := 0
+	for _, createdPR := range createdPRs {
+		plan, ok := plansByBranch[createdPR.Repository+"\x00"+createdPR.Branch]
+		if !ok || len(pr.TransformedWorkflows(plan)) == 0 {
+			continue
+		}
+		checks := pr.StartDispatchChecks(githubClient, plan, createdPR, time.Now())
+		for _, check := range checks.Checks {
+			if check.Status == pr.DispatchSkipped {
+				fmt.Printf("Not dispatching %s in %s: %s\n", check.FilePath, createdPR.Repository, check.Reason)
+				continue
+			}
+			fmt.Printf("Dispatched %s in %s on %s\n", check.FilePath, createdPR.Repository, createdPR.Branch)
+			dispatched++
+		}
+		all = append(all, checks)
+	}
+	if len(all) == 0 {
+		fmt.Printf("No pull request patches workflows with schema transformations; nothing to dispatch\n")
+		return
+	}
+
+	if dispatched > 0 {
+		fmt.Printf("Waiting up to %s for %d dispatched runs\n", timeout, dispatched)
+	}
+	failures := pr.WaitDispatchChecks(githubClient, all, timeout)
+	for _, checks := range all {
+		if err := failures[checks.PR.URL]; err != nil {
+			fmt.Printf("Warning: %s: failed to read the dispatched runs: %v\n", checks.PR.URL, err)
+		}
+		for _, check := range checks.Checks {
+			switch check.Status {
+			case pr.DispatchCompleted:
+				fmt.Printf("Upgrade check %s in %s: %s %s\n", check.FilePath, checks.PR.Repository, check.Conclusion, check.RunURL)
+			case pr.DispatchTimedOut:
+				fmt.Printf("Upgrade check %s in %s: still running after %s %s\n", check.FilePath, checks.PR.Repository, timeout, check.RunURL)
+			}
+		}
+
+		owner, name, _ := strings.Cut(checks.PR.Repository, "/")
+		if err := githubClient.CreateIssueComment(owner, name, checks.PR.Number, pr.DispatchCheckComment(checks.Checks)); err != nil {
+			fmt.Printf("Warning: %s: %v\n", checks.PR.URL, err)
+		}
+	}
+}
+
+// savePlan writes the planned pull requests to the plan command's --output file
+func savePlan(ctx climax.Context, plans []pr.UpdatePlan, inputFile string) int {
+	outputFile, _ := ctx.Get("output")
+	if outputFile == "" {
+		outputFile = planfile.DefaultFile
+	}
+
+	file := planfile.New(plans, inputFile, time.Now())
+	if err := file.Save(outputFile); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	for _, item := range file.Items {
+		fmt.Printf("Planned %s (%s): %d action updates on %s\n", item.Repository, item.BaseBranch, len(item.Plan.Updates), item.Branch)
+	}
+	fmt.Printf("Wrote %d planned pull requests to %s; run create-pr --plan %s to create them\n", len(file.Items), outputFile, outputFile)
+	return 0
+}
+
+// executePlan creates the pull requests of a plan file, saving each item's status as it goes
+func executePlan(ctx climax.Context, planFile string) int {
+	// The plan was reviewed as written, so flags choosing what to update belong to the plan command
+	for _, name := range []string{"input", "filter", "hook-command", "hook-url", "include-existing", "skip-stale-workflows", "base-branches",
+		"canary", "promote", "wave", "approvals", "no-codeowners", "max-reviewers", "coexist-mode", "estimate", "review-mode"} {
+		if value, _ := ctx.Get(name); value != "" || ctx.Is(name) {
+			fmt.Fprintf(os.Stderr, "Error: --%s cannot be combined with --plan; pass it to the plan command instead\n", name)
+			return 1
+		}
+	}
+
+	chunk := 0
+	if chunkFlag, _ := ctx.Get("chunk"); chunkFlag != "" {
+		var err error
+		chunk, err = strconv.Atoi(chunkFlag)
+		if err != nil || chunk < 1 {
+			fmt.Fprintf(os.Stderr, "Error: --chunk must be a positive number\n")
+			return 1
+		}
+	}
+
+	file, err := planfile.Load(planFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if file.Started() && !ctx.Is("resume") {
+		counts := file.Counts()
+		fmt.Fprintf(os.Stderr, "Error: %s was already executed (%d created, %d failed, %d skipped); pass --resume to continue it\n",
+			planFile, counts[planfile.StatusCreated], counts[planfile.StatusFailed], counts[planfile.StatusSkipped])
+		return 1
+	}
+	next := file.Next(chunk)
+	if len(next) == 0 {
+		fmt.Printf("Nothing left to create: all %d items of %s were executed\n", len(file.Items), planFile)
+		return 0
+	}
+
+	token, app, err := githubCredentials(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	dispatchTimeout, err := dispatchCheckTimeout(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	var commitMessage *template.Template
+	if commitMessageFlag, _ := ctx.Get("commit-message"); commitMessageFlag != "" {
+		commitMessage, err = pr.ParseCommitMessage(commitMessageFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --commit-message: %v\n", err)
+			return 1
+		}
+	}
+	commitLayout := pr.CommitLayoutSingle
+	if commitLayoutFlag, _ := ctx.Get("commit-layout"); commitLayoutFlag != "" {
+		commitLayout, err = pr.ParseCommitLayout(commitLayoutFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --commit-layout: %v\n", err)
+			return 1
+		}
+	}
+
+	transport, timeout, err := networkOptions(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	githubClient := github.NewClientWithConfig(token, &github.Config{
+		Transport: transport,
+		Timeout:   timeout,
+		Tags:      requestTags(ctx),
+		APIURL:    githubAPIURL(ctx),
+		App:       app,
+	})
+
+	tokenInfo, err := githubClient.GetTokenInfo()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: token preflight failed: %v\n", err)
+		return 1
+	}
+	printTokenPreflight(tokenInfo)
+
+	auditLog, err := openAuditLog(ctx, "create-pr", githubClient, tokenInfo.Login)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer auditLog.Close()
+
+	var prCreator *pr.Creator
+	if templateFile, _ := ctx.Get("template"); templateFile != "" {
+		tmpl, err := loadTemplateFromFile(templateFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading template file: %v\n", err)
+			return 1
+		}
+		prCreator = pr.NewCreatorWithTemplate(githubClient, tmpl)
+	} else {
+		prCreator = pr.NewCreator(githubClient)
+	}
+	linkMapping, err := loadLinks(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading link mappings: %v\n", err)
+		return 1
+	}
+	prCreator.SetLinks(linkMapping)
+	prCreator.SetAuditLog(auditLog)
+	prCreator.SetCommitMessage(commitMessage)
+	prCreator.SetCommitLayout(commitLayout)
+	if ledgerFile, _ := ctx.Get("ledger"); ledgerFile != "" {
+		prLedger, err := ledger.Open(ledgerFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		prCreator.SetLedger(prLedger)
+	}
+
+	// Pull requests created by this run, for --dispatch-check
+	var runPlans []pr.UpdatePlan
+	var runPRs []output.CreatedPR
+
+	file.Executions = append(file.Executions, planfile.Execution{StartedAt: time.Now(), Login: tokenInfo.Login})
+	fmt.Printf("Creating %d of the %d pull requests in %s\n", len(next), len(file.Items), planFile)
+	for _, i := range next {
+		item := file.Items[i]
+
+		// Workflows changed since planning would be overwritten by the planned patches
+		var stale []pr.StaleFile
+		var err error
+		if !ctx.Is("allow-stale") {
+			stale, err = pr.CheckDrift(githubClient, item.Plan)
+		}
+		var createdPR output.CreatedPR
+		if err == nil && len(stale) == 0 {
+			createdPR, err = prCreator.CreatePR(item.Plan)
+		}
+
+		var unrecorded *pr.UnrecordedError
+		switch {
+		case errors.As(err, &unrecorded):
+			file.Created(i, createdPR, time.Now())
+		case err != nil:
+			fmt.Printf("Failed to create PR for %s: %v\n", item.Repository, err)
+			file.Failed(i, planfile.StatusFailed, github.RedactTokens(err.Error(), token), time.Now())
+		case len(stale) > 0:
+			paths := make([]string, 0, len(stale))
+			for _, staleFile := range stale {
+				paths = append(paths, staleFile.Path)
+			}
+			reason := fmt.Sprintf("%s changed since the scan; rescan the repository and plan again", strings.Join(paths, ", "))
+			fmt.Printf("Skipping %s: %s\n", item.Repository, reason)
+			file.Failed(i, planfile.StatusSkipped, reason, time.Now())
+		default:
+			file.Created(i, createdPR, time.Now())
+			runPlans = append(runPlans, item.Plan)
+			runPRs = append(runPRs, createdPR)
+		}
+
+		if err := file.Save(planFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if unrecorded != nil {
+			// Stop rather than risk duplicates from the ledger on the next run
+			fmt.Fprintf(os.Stderr, "Error creating PRs: %v\n", unrecorded)
+			return 1
+		}
+	}
+
+	// The changelog records every pull request of the plan, including earlier chunks
+	if changelogFile, _ := ctx.Get("changelog"); changelogFile != "" {
+		plans := make([]pr.UpdatePlan, 0, len(file.Items))
+		for _, item := range file.Items {
+			plans = append(plans, item.Plan)
+		}
+		createdPRs := file.CreatedPRs()
+		if err := writeChangelog(changelogFile, pr.NewChangelog(plans, createdPRs)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Wrote the changelog of %d pull requests to %s\n", len(createdPRs), changelogFile)
+	}
+	if err := auditLog.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if ctx.Is("dispatch-check") {
+		runDispatchChecks(githubClient, runPlans, runPRs, dispatchTimeout)
+	}
+
+	counts := file.Counts()
+	fmt.Printf("Plan %s: %d created, %d failed, %d skipped, %d pending\n", planFile,
+		counts[planfile.StatusCreated], counts[planfile.StatusFailed], counts[planfile.StatusSkipped], counts[planfile.StatusPending])
+	if len(file.Next(0)) > 0 {
+		fmt.Printf("Run create-pr --plan %s --resume to create the rest\n", planFile)
+	}
+	return 0
+}
+
+func handleWaves(ctx climax.Context) int {
+	inputFile, _ := ctx.Get("input")
+	outputFile, _ := ctx.Get("output")
+	if outputFile == "" {
+		outputFile = waves.DefaultPlanFile
+	}
+	property, _ := ctx.Get("wave-property")
+
+	inputReader, closeInput, err := openScanInput(ctx, inputFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+
+	planner := waves.NewPlanner(&waves.Config{Property: property})
+	_, err = output.DecodeScanResult(inputReader, func(repo *output.RepositoryResult) error {
+		planner.Add(repo)
+		return nil
+	})
+	if closeErr := closeInput(); closeErr != nil {
+		err = closeErr
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing JSON input: %v\n", err)
+		return 1
+	}
+
+	plan := planner.Plan(time.Now())
+	if err := plan.Save(outputFile); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	for _, wave := range plan.Waves {
+		fmt.Printf("Wave %d: %d repositories - %s\n", wave.Number, len(wave.Repositories), wave.Description)
+	}
+	fmt.Printf("Wrote wave plan to %s; create pull requests one wave at a time with create-pr --wave <n>\n", outputFile)
+	return 0
+}
+
+// writeChangelog writes the markdown changelog of a create-pr run to a file
+func writeChangelog(filename string, entries []pr.ChangelogEntry) error {
+	var doc bytes.Buffer
+	if err := pr.WriteChangelog(&doc, entries, time.Now()); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filename, doc.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write changelog: %w", err)
+	}
+	return nil
+}
+
+// saveCanaryPromotion marks a canary rollout as promoted, so a new rollout can start
+func saveCanaryPromotion(state *canary.State, filename string) int {
+	now := time.Now()
+	state.PromotedAt = &now
+	if err := state.Save(filename); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Marked the canary rollout in %s as promoted\n", filename)
+	return 0
+}
+
+// openAuditLog opens the --audit-log target of a command, recording the token's login as the acting identity
+// The token is only looked up when an audit log is configured and its login is not already known.
+func openAuditLog(ctx climax.Context, command string, githubClient *github.Client, login string) (*audit.Logger, error) {
+	target, _ := ctx.Get("audit-log")
+	if target == "" {
+		return nil, nil
+	}
+
+	if login == "" {
+		info, err := githubClient.GetTokenInfo()
+		if err != nil {
+			return nil, fmt.Errorf("failed to identify the token owner for the audit log: %w", err)
+		}
+		login = info.Login
+	}
+	return audit.Open(&audit.Config{Target: target, Actor: login, Command: command})
+}
+
+// githubCredentials returns the --token, or with --app-id the GitHub App credentials the client mints
+// per-repository installation tokens from
+func githubCredentials(ctx climax.Context) (string, *github.AppCredentials, error) {
+	appID, _ := ctx.Get("app-id")
+	if appID == "" {
+		appID = os.Getenv("GITHUB_APP_ID")
+	}
+	keyFile, _ := ctx.Get("app-private-key")
+	if keyFile == "" {
+		keyFile = os.Getenv("GITHUB_APP_PRIVATE_KEY_FILE")
+	}
+
+	token, _ := ctx.Get("token")
+	if appID != "" || keyFile != "" {
+		if appID == "" || keyFile == "" {
+			return "", nil, fmt.Errorf("--app-id and --app-private-key must be used together")
+		}
+		if token != "" {
+			return "", nil, fmt.Errorf("--token cannot be combined with --app-id")
+		}
+		app, err := github.LoadAppCredentials(appID, keyFile)
+		if err != nil {
+			return "", nil, fmt.Errorf("--app-private-key: %w", err)
+		}
+		return "", app, nil
+	}
+
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	if token == "" {
+		return "", nil, fmt.Errorf("GitHub token is required. Use --token, set GITHUB_TOKEN environment variable, or authenticate as a GitHub App with --app-id and --app-private-key")
+	}
+	logRedactor.AddSecret(token)
+	return token, nil, nil
+}
+
+// printTokenPreflight reports token scopes and expiry, warning about missing permissions for PR creation
+func printTokenPreflight(info *github.TokenInfo) {
+	fmt.Printf("Token preflight:\n")
+	if info.FineGrained {
+		fmt.Printf("  Type: fine-grained or app token (scopes not reported by GitHub)\n")
+		fmt.Printf("  Required permissions: Contents (read/write), Pull requests (read/write), Workflows (read/write)\n")
+	} else {
+		fmt.Printf("  Scopes: %s\n", strings.Join(info.Scopes, ", "))
+		if !info.HasScope("workflow") {
+			fmt.Printf("  Warning: token is missing the 'workflow' scope; pushing workflow file changes will fail\n")
+		}
+		if !info.HasScope("repo") && !info.HasScope("public_repo") {
+			fmt.Printf("  Warning: token is missing the 'repo' scope; branches and pull requests cannot be created\n")
+		}
+	}
+
+	if !info.ExpiresAt.IsZero() {
+		remaining := time.Until(info.ExpiresAt)
+		fmt.Printf("  Expires: %s\n", info.ExpiresAt.Format("2006-01-02 15:04:05 MST"))
+		if remaining < 7*24*time.Hour {
+			fmt.Printf("  Warning: token expires in %s; rotate it soon\n", remaining.Round(time.Hour))
+		}
+	}
+}
+
+// networkOptions builds the HTTP transport and request timeout from the shared network flags
+func networkOptions(ctx climax.Context) (http.RoundTripper, time.Duration, error) {
+	proxyURL, _ := ctx.Get("proxy")
+	caBundle, _ := ctx.Get("ca-bundle")
+	timeoutFlag, _ := ctx.Get("timeout")
+	insecure := ctx.Is("insecure-skip-verify")
+
+	var timeout time.Duration
+	if timeoutFlag != "" {
+		var err error
+		timeout, err = time.ParseDuration(timeoutFlag)
+		if err != nil || timeout <= 0 {
+			return nil, 0, fmt.Errorf("--timeout must be a positive duration (e.g., 30s, 2m)")
+		}
+	}
+
+	if suffix, _ := ctx.Get("user-agent-suffix"); suffix != "" {
+		if err := github.ValidateUserAgentSuffix(suffix); err != nil {
+			return nil, 0, fmt.Errorf("--user-agent-suffix: %w", err)
+		}
+	}
+
+	if apiURL, _ := ctx.Get("api-url"); apiURL != "" {
+		if _, err := github.ParseAPIURL(apiURL); err != nil {
+			return nil, 0, fmt.Errorf("--api-url: %w", err)
+		}
+	}
+
+	if insecure {
+		fmt.Fprintf(os.Stderr, "Warning: TLS certificate verification is disabled (--insecure-skip-verify). Connections are vulnerable to interception.\n")
+	}
+
+	transport, err := github.NewTransport(github.TransportConfig{
+		ProxyURL:           proxyURL,
+		CABundle:           caBundle,
+		InsecureSkipVerify: insecure,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid network configuration: %w", err)
+	}
+
+	return transport, timeout, nil
+}
+
+// githubAPIURL returns the GitHub Enterprise Server API URL of --api-url, checked by networkOptions, or
+// "" for github.com
+func githubAPIURL(ctx climax.Context) string {
+	apiURL, _ := ctx.Get("api-url")
+	return apiURL
+}
+
+// requestTags identifies the tool's GitHub API requests by version, run, and --user-agent-suffix
+func requestTags(ctx climax.Context) github.RequestTags {
+	suffix, _ := ctx.Get("user-agent-suffix")
+	return github.RequestTags{Version: getVersion(), UserAgentSuffix: suffix}
+}
+
+// startProfiling starts a CPU profile and returns a function that stops it and writes a heap profile
+func startProfiling(prefix string) (func(), error) {
+	cpuFile, err := os.Create(prefix + ".cpu.pprof")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CPU profile: %w", err)
+	}
+	if err := pprof.StartCPUProfile(cpuFile); err != nil {
+		cpuFile.Close()
+		return nil, fmt.Errorf("failed to start CPU profile: %w", err)
+	}
+
+	return func() {
+		pprof.StopCPUProfile()
+		cpuFile.Close()
+
+		heapFile, err := os.Create(prefix + ".heap.pprof")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to create heap profile: %v\n", err)
+			return
+		}
+		defer heapFile.Close()
+
+		runtime.GC() // Get up-to-date allocation statistics
+		if err := pprof.WriteHeapProfile(heapFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to write heap profile: %v\n", err)
+			return
+		}
+		fmt.Fprintf(os.Stderr, "Wrote profiles to %s.cpu.pprof and %s.heap.pprof\n", prefix, prefix)
+	}, nil
+}
+
+// loadRedactKey returns the key for --redact hashes from --redact-key or the environment, or a random key for this run
+func loadRedactKey(ctx climax.Context) ([]byte, error) {
+	key, _ := ctx.Get("redact-key")
+	if key == "" {
+		key = os.Getenv(output.RedactKeyEnv)
+	}
+	if key != "" {
+		return []byte(key), nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Warning: no --redact-key or %s set; redacting with a random key, so placeholders will not match other reports\n", output.RedactKeyEnv)
+	return output.NewRedactKey()
+}
+
+// loadReportTemplates loads report section overrides from a directory, returning nil when no directory is set
+func loadReportTemplates(dir string) (*output.ReportTemplates, error) {
+	if dir == "" {
+		return nil, nil
+	}
+
+	templates, err := output.LoadReportTemplates(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(os.Stderr, "Loaded report templates for sections: %s\n", strings.Join(templates.Sections(), ", "))
+	return templates, nil
+}
+
+// writeSplitScanResult writes each chunk of a split scan next to the index file, then the index itself
+func writeSplitScanResult(result *output.ScanResult, spec output.SplitSpec, indexPath, encryptRecipient string) error {
+	index, chunks := output.SplitScanResult(result, spec, indexPath)
+	dir := filepath.Dir(indexPath)
+
+	for i, chunk := range chunks {
+		if err := writeJSONFile(filepath.Join(dir, index.Chunks[i].File), chunk, encryptRecipient); err != nil {
+			return err
+		}
+	}
+	if err := writeJSONFile(indexPath, index, encryptRecipient); err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote %d chunk files and index %s\n", len(chunks), indexPath)
+	return nil
+}
+
+// writeJSONFile writes a value as indented JSON, compressed when the path ends in .gz or .zst and
+// encrypted with age when recipients are given
+func writeJSONFile(path string, value any, encryptRecipient string) error {
+	file, err := output.CreateOutputFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var writer io.Writer = file
+	var encryptWriter *encrypt.Writer
+	if encryptRecipient != "" {
+		if encryptWriter, err = encrypt.NewWriter(file, encrypt.ParseRecipients(encryptRecipient)); err != nil {
+			return err
+		}
+		writer = encryptWriter
+	}
+	compressWriter, err := compress.NewWriter(writer, compress.FormatForPath(path))
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(compressWriter)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(value)
+	if err == nil {
+		err = compressWriter.Close()
+	}
+	if err != nil {
+		if encryptWriter != nil {
+			encryptWriter.Close()
+		}
+		return fmt.Errorf("unable to write %s: %w", path, err)
+	}
+
+	if encryptWriter != nil {
+		if err := encryptWriter.Close(); err != nil {
+			return fmt.Errorf("unable to encrypt %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// openScanInput opens scan results from a file or stdin for streaming, decrypting age-encrypted input
+// with --decrypt-identity and decompressing gzip or zstd compressed input. The returned close
+// function reports decryption failures.
+func openScanInput(ctx climax.Context, inputFile string) (io.Reader, func() error, error) {
+	var input io.Reader = os.Stdin
+	closeInput := func() error { return nil }
+	if inputFile != "" {
+		file, err := os.Open(inputFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to open input file: %w", err)
+		}
+		input = file
+		closeInput = file.Close
+	}
+
+	buffered := bufio.NewReader(input)
+	header, _ := buffered.Peek(64)
+	var plaintext io.Reader = buffered
+	closePlaintext := closeInput
+	if encrypt.IsEncrypted(header) {
+		identity, _ := ctx.Get("decrypt-identity")
+		if identity == "" {
+			closeInput()
+			return nil, nil, fmt.Errorf("input is age-encrypted; pass --decrypt-identity <file>")
+		}
+		decrypted, err := encrypt.NewReader(buffered, identity)
+		if err != nil {
+			closeInput()
+			return nil, nil, err
+		}
+		plaintext = decrypted
+		closePlaintext = func() error {
+			defer closeInput()
+			return decrypted.Close()
+		}
+	}
+
+	// Results are compressed before they are encrypted, so they are decompressed after decryption
+	decompressed, err := compress.NewReader(plaintext)
+	if err != nil {
+		closePlaintext()
+		return nil, nil, err
+	}
+	return decompressed, func() error {
+		defer closePlaintext()
+		return decompressed.Close()
+	}, nil
+}
+
+// prepareScanInput decrypts age-encrypted scan results with --decrypt-identity, decompresses gzip or
+// zstd compressed results, and rejects split scan indexes, which list chunk files rather than
+// containing results; plain JSON is returned unchanged
+func prepareScanInput(ctx climax.Context, data []byte) ([]byte, error) {
+	if encrypt.IsEncrypted(data) {
+		identity, _ := ctx.Get("decrypt-identity")
+		if identity == "" {
+			return nil, fmt.Errorf("input is age-encrypted; pass --decrypt-identity <file>")
+		}
+		var err error
+		if data, err = encrypt.Decrypt(data, identity); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := compress.Decompress(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if output.IsScanIndex(data) {
+		return nil, fmt.Errorf("input is a split scan index; run the command on each chunk file listed in its chunks")
+	}
+	return data, nil
+}
+
+// registryTimeout returns the --timeout value for registry requests, or the registry default
+func registryTimeout(timeout time.Duration) time.Duration {
+	if timeout > 0 {
+		return timeout
+	}
+	return registry.DefaultTimeout
+}
+
+// loadLinks loads the --links URL mappings for templates, returning nil when no file is set
+func loadLinks(ctx climax.Context) (*links.Mapping, error) {
+	filename, _ := ctx.Get("links")
+	if filename == "" {
+		return nil, nil
+	}
+
+	mapping, err := links.Load(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(os.Stderr, "Loaded links: %s\n", strings.Join(mapping.Names(), ", "))
+	return mapping, nil
+}
+
+// loadTemplateFromFile loads a Go template from a file
+func loadTemplateFromFile(filename string) (*template.Template, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template file: %w", err)
+	}
+
+	tmpl, err := template.New("pr-body").Funcs(pr.TemplateFuncs).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// loadRulesFromFile loads custom rules from a JSON file
+func loadRulesFromFile(filename string) ([]actions.Rule, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open rules file: %w", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read rules file: %w", err)
+	}
+
+	var rules []actions.Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("unable to parse rules file as JSON: %w", err)
+	}
+
+	// Validate rules
+	for i, rule := range rules {
+		if rule.Repository == "" {
+			return nil, fmt.Errorf("rule %d: repository field is required", i+1)
+		}
+		if err := actions.ValidateVersionSource(rule.VersionSource); err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i+1, err)
+		}
+		if err := rule.Conditions.Validate(); err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i+1, err)
+		}
+		for _, owner := range rule.Owners {
+			if err := pr.ValidateOwner(owner); err != nil {
+				return nil, fmt.Errorf("rule %d: %w", i+1, err)
+			}
+		}
+		for _, edit := range rule.Files {
+			if err := pr.ValidateFileEdit(edit); err != nil {
+				return nil, fmt.Errorf("rule %d: file edit: %w", i+1, err)
+			}
+		}
+
+		// Brownout rules are calendar entries, so they need no latest version
+		if rule.Brownout != nil {
+			if err := rule.Brownout.Validate(); err != nil {
+				return nil, fmt.Errorf("rule %d: %w", i+1, err)
+			}
+			continue
+		}
+
+		// Required action rules name a version to insert rather than a latest version
+		if rule.Required != nil {
+			if err := rule.Required.Validate(); err != nil {
+				return nil, fmt.Errorf("rule %d: %w", i+1, err)
+			}
+			continue
+		}
+
+		// Ban rules apply at every version, so they need no latest version
+		if rule.Ban != nil {
+			if err := rule.Ban.Validate(); err != nil {
+				return nil, fmt.Errorf("rule %d: %w", i+1, err)
+			}
+			continue
+		}
+
+		// Check if this is a migration rule or a standard version rule
+		isMigrationRule := rule.MigrateToRepository != "" || rule.MigrateToPath != "" || rule.MigrateToVersion != ""
+
+		if isMigrationRule {
+			// Migration rule validation
+			if rule.MigrateToRepository == "" && rule.MigrateToPath == "" {
+				return nil, fmt.Errorf("rule %d: migrate_to_repository or migrate_to_path field is required when migration is specified for repository %s", i+1, rule.Repository)
+			}
+			// Renaming a path within a repository must name the path being renamed
+			if rule.MigrateToRepository == "" && rule.WorkflowPath == "" {
+				return nil, fmt.Errorf("rule %d: workflow_path field is required when migrate_to_path is used without migrate_to_repository for repository %s", i+1, rule.Repository)
+			}
+			if rule.MigrateToVersion == "" {
+				return nil, fmt.Errorf("rule %d: migrate_to_version field is required when migration is specified for repository %s", i+1, rule.Repository)
+			}
+			// For migration rules, latest_version is optional (defaults to current behavior)
+		} else {
+			// Standard version rule validation
+			if rule.LatestVersion == "" {
+				return nil, fmt.Errorf("rule %d: latest_version field is required for repository %s", i+1, rule.Repository)
+			}
+		}
+	}
+
+	return rules, nil
+}
+
+func handleJira(ctx climax.Context) int {
+	inputFile, _ := ctx.Get("input")
+	configFile, _ := ctx.Get("config")
+	stateFile, _ := ctx.Get("state")
+	dryRun := ctx.Is("dry-run")
+	verbose := ctx.Is("verbose")
+
+	if configFile == "" {
+		fmt.Fprintf(os.Stderr, "Error: --config is required\n")
+		return 1
+	}
+	if stateFile == "" {
+		stateFile = ".actions-maintainer-jira.json"
+	}
+
+	config, err := jira.LoadConfig(configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading Jira config: %v\n", err)
+		return 1
+	}
+
+	state, err := jira.LoadState(stateFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading Jira state: %v\n", err)
+		return 1
+	}
+
+	// Read JSON input
+	var inputReader io.Reader
+	if inputFile != "" {
+		file, err := os.Open(inputFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening input file: %v\n", err)
+			return 1
+		}
+		defer file.Close()
+		inputReader = file
+	} else {
+		inputReader = os.Stdin
+	}
+
+	data, err := io.ReadAll(inputReader)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+
+	data, err = prepareScanInput(ctx, data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+
+	var scanResult output.ScanResult
+	if err := json.Unmarshal(data, &scanResult); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing JSON input: %v\n", err)
+		return 1
+	}
+
+	if dryRun {
+		for _, group := range jira.GroupIssues(&scanResult, config.GroupBy) {
+			action := "create"
+			if key, exists := state.Tickets[group.Key]; exists {
+				action = "update " + key
+			}
+			fmt.Printf("Would %s: %s\n", action, group.Summary())
+		}
+		return 0
+	}
+
+	token := os.Getenv("JIRA_API_TOKEN")
+	if token == "" {
+		fmt.Fprintf(os.Stderr, "Error: JIRA_API_TOKEN environment variable is required\n")
+		return 1
+	}
+
+	transport, timeout, err := networkOptions(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	client := jira.NewClient(config.BaseURL, os.Getenv("JIRA_EMAIL"), token, &http.Client{Transport: transport, Timeout: timeout})
+	result, syncErr := jira.Sync(client, config, state, &scanResult, verbose)
+
+	// Save whatever was created before any failure so reruns don't duplicate tickets
+	if err := state.Save(stateFile); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving Jira state: %v\n", err)
+		return 1
+	}
+	if syncErr != nil {
+		fmt.Fprintf(os.Stderr, "Error syncing Jira tickets: %v\n", syncErr)
+		return 1
+	}
+
+	fmt.Printf("Jira tickets: %d created, %d updated, %d resolved\n", len(result.Created), len(result.Updated), len(result.Resolved))
+	return 0
+}
+
+func handleCleanup(ctx climax.Context) int {
+	inputFile, _ := ctx.Get("input")
+	filterPattern, _ := ctx.Get("filter")
+	dryRun := ctx.Is("dry-run")
+	verbose := ctx.Is("verbose")
+
+	token, _ := ctx.Get("token")
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	if token == "" {
+		fmt.Fprintf(os.Stderr, "Error: GitHub token is required. Use --token or set GITHUB_TOKEN environment variable\n")
+		return 1
+	}
+
+	// Read JSON input
+	var inputReader io.Reader
+	if inputFile != "" {
+		file, err := os.Open(inputFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening input file: %v\n", err)
+			return 1
+		}
+		defer file.Close()
+		inputReader = file
+	} else {
+		inputReader = os.Stdin
+	}
+
+	data, err := io.ReadAll(inputReader)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+
+	data, err = prepareScanInput(ctx, data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+
+	var scanResult output.ScanResult
+	if err := json.Unmarshal(data, &scanResult); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing JSON input: %v\n", err)
+		return 1
+	}
+
+	var filterRegex *regexp.Regexp
+	if filterPattern != "" {
+		filterRegex, err = regexp.Compile(filterPattern)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid filter regex pattern '%s': %v\n", filterPattern, err)
+			return 1
+		}
+	}
+
+	transport, timeout, err := networkOptions(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	githubClient := github.NewClientWithConfig(token, &github.Config{
+		Verbose:   verbose,
+		Transport: transport,
+		Timeout:   timeout,
+		Tags:      requestTags(ctx),
+		APIURL:    githubAPIURL(ctx),
+	})
+
+	auditLog, err := openAuditLog(ctx, "cleanup", githubClient, "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer auditLog.Close()
+
+	cleaner := cleanup.NewCleanerWithConfig(githubClient, &cleanup.Config{
+		Verbose: verbose,
+		Prefix:  pr.BranchPrefix,
+		DryRun:  dryRun,
+		Audit:   auditLog,
+	})
+
+	counts := make(map[string]int)
+	for _, repo := range scanResult.Repositories {
+		if filterRegex != nil && !filterRegex.MatchString(repo.Name) {
+			continue
+		}
+
+		parts := strings.SplitN(repo.FullName, "/", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		results, err := cleaner.CleanRepository(parts[0], parts[1])
+		if err != nil {
+			fmt.Printf("Warning: Failed to list branches in %s: %v\n", repo.FullName, err)
+			counts[cleanup.ActionFailed]++
+			continue
+		}
+
+		for _, result := range results {
+			counts[result.Action]++
+			if result.Action != cleanup.ActionKept || verbose {
+				fmt.Printf("%s: %s %s (%s)\n", result.Repository, result.Action, result.Branch, result.Reason)
+			}
+		}
+	}
+
+	if err := auditLog.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if dryRun {
+		fmt.Printf("Branches: %d would be deleted, %d kept, %d failed\n", counts[cleanup.ActionWouldDelete], counts[cleanup.ActionKept], counts[cleanup.ActionFailed])
+	} else {
+		fmt.Printf("Branches: %d deleted, %d kept, %d failed\n", counts[cleanup.ActionDeleted], counts[cleanup.ActionKept], counts[cleanup.ActionFailed])
+	}
+
+	if counts[cleanup.ActionFailed] > 0 {
+		return 1
+	}
+	return 0
+}
+
+func handleVerify(ctx climax.Context) int {
+	inputFile, _ := ctx.Get("input")
+	ledgerFile, _ := ctx.Get("ledger")
+	outputFile, _ := ctx.Get("output")
+	mergedOnly := ctx.Is("merged-only")
+	verbose := ctx.Is("verbose")
+
+	token, _ := ctx.Get("token")
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	if token == "" {
+		fmt.Fprintf(os.Stderr, "Error: GitHub token is required. Use --token or set GITHUB_TOKEN environment variable\n")
+		return 1
+	}
+
+	scanResult, err := readScanResult(ctx, inputFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+
+	// Pull requests are recorded in the scan result by run pipelines, and in the ledger by create-pr
+	created := append([]output.CreatedPR{}, scanResult.CreatedPRs...)
+	if ledgerFile != "" {
+		prLedger, err := ledger.Open(ledgerFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening ledger '%s': %v\n", ledgerFile, err)
+			return 1
+		}
+		for _, entry := range prLedger.Entries() {
+			created = append(created, entry.PR)
+		}
+	}
+	if len(created) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no pull requests to verify; the scan result lists none, so pass the create-pr --ledger file\n")
+		return 1
+	}
+
+	transport, timeout, err := networkOptions(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	githubClient := github.NewClientWithConfig(token, &github.Config{
+		Verbose:   verbose,
+		Transport: transport,
+		Timeout:   timeout,
+		Tags:      requestTags(ctx),
+		APIURL:    githubAPIURL(ctx),
+	})
+
+	pulls := verify.PullRequests(githubClient, created, verbose)
+	if mergedOnly {
+		for repository, repoPulls := range pulls {
+			if !verify.Merged(repoPulls) {
+				delete(pulls, repository)
+			}
+		}
+		if len(pulls) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: none of the %d pull requests is merged\n", len(created))
+			return 1
+		}
+	}
+	verify.AddUpgradeRuns(githubClient, pulls)
+
+	// Rescan each owner's repositories with pull requests through the scan command
+	names := make(map[string][]string)
+	for repository := range pulls {
+		owner, name, _ := strings.Cut(repository, "/")
+		names[owner] = append(names[owner], regexp.QuoteMeta(name))
+	}
+	var rescans []*output.ScanResult
+	for owner, ownerNames := range names {
+		rescanFile, err := os.CreateTemp("", "actions-maintainer-verify-*.json")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating temporary results file: %v\n", err)
+			return 1
+		}
+		rescanFile.Close()
+		defer os.Remove(rescanFile.Name())
+
+		fmt.Printf("==> Rescanning %d repositories of %s\n", len(ownerNames), owner)
+		scanCtx := verifyScanContext(ctx, owner, "^(?:"+strings.Join(ownerNames, "|")+")$", token, rescanFile.Name())
+		if code := handleScan(scanCtx); code != 0 && code != exitCodeGateFailed {
+			fmt.Fprintf(os.Stderr, "Error: rescanning %s failed\n", owner)
+			return 1
+		}
+		rescan, err := readScanResult(scanCtx, rescanFile.Name())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading rescan results: %v\n", err)
+			return 1
+		}
+		rescans = append(rescans, rescan)
+	}
+
+	report := verify.BuildReport(scanResult, output.MergeScanResults(rescans), pulls, time.Now())
+	if outputFile == "" {
+		err = verify.WriteMarkdown(os.Stdout, report)
+	} else {
+		err = writeClosureReport(report, outputFile)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Verified %d repositories: %d issues fixed, %d remaining, %d regressed\n",
+		report.Summary.Repositories, report.Summary.Fixed, report.Summary.Remaining, report.Summary.Regressed)
+	if !report.Closed() {
+		return exitCodeGateFailed
+	}
+	return 0
+}
+
+// verifyScanContext builds the scan flags rescanning the repositories of an owner matching filter
+func verifyScanContext(ctx climax.Context, owner, filter, token, resultsFile string) climax.Context {
+	variable := map[string]string{"owner": owner, "filter": filter, "token": token, "output": resultsFile}
+	nonVariable := make(map[string]bool)
+	for _, name := range []string{"rules-file", "proxy", "ca-bundle", "timeout", "user-agent-suffix"} {
+		if value, ok := ctx.Get(name); ok && value != "" {
+			variable[name] = value
+		}
+	}
+	for _, name := range []string{"insecure-skip-verify", "verbose"} {
+		if ctx.Is(name) {
+			nonVariable[name] = true
+		}
+	}
+	return climax.Context{Variable: variable, NonVariable: nonVariable}
+}
+
+// writeClosureReport writes a closure report in the format of the file's extension
+func writeClosureReport(report *verify.Report, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(filename), ".json") {
+		err = verify.WriteJSON(file, report)
+	} else {
+		err = verify.WriteMarkdown(file, report)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Closure report written to %s\n", filename)
+	return file.Close()
+}
+
+func handleDigest(ctx climax.Context) int {
+	inputFile, _ := ctx.Get("input")
+	previousFile, _ := ctx.Get("previous")
+	ledgerFile, _ := ctx.Get("ledger")
+	formatFlag, _ := ctx.Get("format")
+	outputFile, _ := ctx.Get("output")
+	emailFrom, _ := ctx.Get("email-from")
+	emailTo, _ := ctx.Get("email-to")
+	verbose := ctx.Is("verbose")
+
+	format, err := digest.ParseFormat(formatFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	stalledDays, _ := ctx.Get("stalled-days")
+	deadlineDays, _ := ctx.Get("deadline-days")
+	options, err := digest.ParseOptions(stalledDays, deadlineDays, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	scanResult, err := readScanResult(ctx, inputFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+	var previous *output.ScanResult
+	if previousFile != "" {
+		if previous, err = readScanResult(ctx, previousFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading previous scan: %v\n", err)
+			return 1
+		}
+	}
+
+	// Pull requests are recorded in the scan result by run pipelines, and in the ledger by create-pr
+	created := append([]output.CreatedPR{}, scanResult.CreatedPRs...)
+	if ledgerFile != "" {
+		prLedger, err := ledger.Open(ledgerFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening ledger '%s': %v\n", ledgerFile, err)
+			return 1
+		}
+		for _, entry := range prLedger.Entries() {
+			created = append(created, entry.PR)
+		}
+	}
+
+	token, _ := ctx.Get("token")
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	var pulls map[string][]verify.PullRequest
+	if token == "" {
+		fmt.Fprintf(os.Stderr, "Warning: no GitHub token, so pull request status is left out of the digest. Use --token or set GITHUB_TOKEN\n")
+	} else {
+		transport, timeout, err := networkOptions(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		githubClient := github.NewClientWithConfig(token, &github.Config{
+			Verbose:   verbose,
+			Transport: transport,
+			Timeout:   timeout,
+			Tags:      requestTags(ctx),
+			APIURL:    githubAPIURL(ctx),
+		})
+		pulls = verify.PullRequests(githubClient, created, verbose)
+	}
+
+	headers := digest.EmailHeaders{From: emailFrom}
+	for _, address := range strings.Split(emailTo, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			headers.To = append(headers.To, address)
+		}
+	}
+
+	weekly := digest.Build(scanResult, previous, pulls, options)
+	if outputFile == "" {
+		if err := digest.Write(os.Stdout, weekly, format, headers); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	file, err := os.Create(outputFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating %s: %v\n", outputFile, err)
+		return 1
+	}
+	defer file.Close()
+	if err := digest.Write(file, weekly, format, headers); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := file.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", outputFile, err)
+		return 1
+	}
+	fmt.Printf("Digest written to %s: %d new issues, %d fixed, %d stalled pull requests, %d deadlines\n",
+		outputFile, len(weekly.NewIssues), len(weekly.FixedIssues), len(weekly.StalledPRs), len(weekly.Deadlines))
+	return 0
+}
+
+func handleBroadcast(ctx climax.Context) int {
+	inputFile, _ := ctx.Get("input")
+	workflowRef, _ := ctx.Get("workflow")
+	version, _ := ctx.Get("version")
+	filterPattern, _ := ctx.Get("filter")
+	templateFile, _ := ctx.Get("template")
+	dryRun := ctx.Is("dry-run")
+	verbose := ctx.Is("verbose")
+
+	if workflowRef == "" || version == "" {
+		fmt.Fprintf(os.Stderr, "Error: --workflow and --version are required\n")
+		return 1
+	}
+
+	workflowRepo, workflowPath, err := pr.ParseWorkflowRef(workflowRef)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	token, _ := ctx.Get("token")
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	if token == "" && !dryRun {
+		fmt.Fprintf(os.Stderr, "Error: GitHub token is required. Use --token or set GITHUB_TOKEN environment variable\n")
+		return 1
+	}
+
+	// Read JSON input
+	var inputReader io.Reader
+	if inputFile != "" {
+		file, err := os.Open(inputFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening input file: %v\n", err)
+			return 1
+		}
+		defer file.Close()
+		inputReader = file
+	} else {
+		inputReader = os.Stdin
+	}
+
+	data, err := io.ReadAll(inputReader)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+
+	data, err = prepareScanInput(ctx, data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+
+	var scanResult output.ScanResult
+	if err := json.Unmarshal(data, &scanResult); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing JSON input: %v\n", err)
+		return 1
+	}
+
+	repositories := scanResult.Repositories
+	if filterPattern != "" {
+		filterRegex, err := regexp.Compile(filterPattern)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid filter regex pattern '%s': %v\n", filterPattern, err)
+			return 1
+		}
+
+		repositories = nil
+		for _, repo := range scanResult.Repositories {
+			if filterRegex.MatchString(repo.Name) {
+				repositories = append(repositories, repo)
+			}
+		}
+	}
+
+	plans := pr.PlanBroadcast(repositories, workflowRepo, workflowPath, version)
+	if len(plans) == 0 {
+		fmt.Printf("No consumers of %s need updating to %s\n", workflowRef, version)
+		return 0
+	}
+
+	fmt.Printf("Found %d consumers of %s to update to %s\n", len(plans), workflowRef, version)
+	if dryRun {
+		for _, plan := range plans {
+			for _, update := range plan.Updates {
+				fmt.Printf("  %s: %s %s@%s -> %s\n", plan.Repository.FullName, update.FilePath, update.ActionRepo, update.CurrentVersion, update.TargetVersion)
+			}
+		}
+		return 0
+	}
+
+	transport, timeout, err := networkOptions(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	githubClient := github.NewClientWithConfig(token, &github.Config{
+		Verbose:   verbose,
+		Transport: transport,
+		Timeout:   timeout,
+		Tags:      requestTags(ctx),
+		APIURL:    githubAPIURL(ctx),
+	})
+
+	auditLog, err := openAuditLog(ctx, "broadcast", githubClient, "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer auditLog.Close()
+
+	var prCreator *pr.Creator
+	if templateFile != "" {
+		tmpl, err := loadTemplateFromFile(templateFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading template file: %v\n", err)
+			return 1
+		}
+		prCreator = pr.NewCreatorWithTemplate(githubClient, tmpl)
+	} else {
+		prCreator = pr.NewCreator(githubClient)
+	}
+
+	prCreator.SetAuditLog(auditLog)
+	createdPRs, err := prCreator.CreateUpdatePRs(plans)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating pull requests: %v\n", err)
+		return 1
+	}
+
+	// Consumers without a pull request are listed in the tracking issue for manual follow-up
+	created := make(map[string]bool)
+	for _, createdPR := range createdPRs {
+		created[createdPR.Repository] = true
+	}
+	var failed []string
+	for _, plan := range plans {
+		if !created[plan.Repository.FullName] {
+			failed = append(failed, plan.Repository.FullName)
+		}
+	}
+
+	parts := strings.SplitN(workflowRepo, "/", 2)
+	issueURL, err := githubClient.CreateIssue(parts[0], parts[1],
+		pr.BroadcastIssueTitle(workflowRef, version, len(plans)),
+		pr.BroadcastIssueBody(workflowRef, version, createdPRs, failed))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating tracking issue in %s: %v\n", workflowRepo, err)
+		return 1
+	}
+	if err := auditLog.Record(audit.Event{Action: audit.ActionIssueOpened, Repository: workflowRepo, URL: issueURL}); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: audit log: %v\n", err)
+	}
+	if err := auditLog.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Created %d/%d consumer pull requests. Tracking issue: %s\n", len(createdPRs), len(plans), issueURL)
+	if len(failed) > 0 {
+		return 1
+	}
+	return 0
+}
+
+func handleAlign(ctx climax.Context) int {
+	inputFile, _ := ctx.Get("input")
+	versionsFile, _ := ctx.Get("versions")
+	workflowRef, _ := ctx.Get("workflow")
+	filterPattern, _ := ctx.Get("filter")
+	templateFile, _ := ctx.Get("template")
+	dryRun := ctx.Is("dry-run")
+	verbose := ctx.Is("verbose")
+
+	var workflowRepo, workflowPath string
+	if workflowRef != "" {
+		var err error
+		workflowRepo, workflowPath, err = pr.ParseWorkflowRef(workflowRef)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	var versions map[string]string
+	if versionsFile != "" {
+		var err error
+		versions, err = pr.LoadAlignmentVersions(versionsFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	token, _ := ctx.Get("token")
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	if token == "" && !dryRun {
+		fmt.Fprintf(os.Stderr, "Error: GitHub token is required. Use --token or set GITHUB_TOKEN environment variable\n")
+		return 1
+	}
+
+	scanResult, err := readScanResult(ctx, inputFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	// Every scanned repository counts towards the most used version; the filter only limits the pull requests
+	var targets []pr.AlignmentTarget
+	for _, target := range pr.AlignmentTargets(scanResult.Repositories, versions) {
+		targetRepo, targetPath, _ := pr.ParseWorkflowRef(target.Workflow)
+		if workflowRepo != "" && (targetRepo != workflowRepo || (workflowPath != "" && targetPath != workflowPath)) {
+			continue
+		}
+		targets = append(targets, target)
+	}
+
+	repositories := scanResult.Repositories
+	if filterPattern != "" {
+		filterRegex, err := regexp.Compile(filterPattern)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid filter regex pattern '%s': %v\n", filterPattern, err)
+			return 1
+		}
+
+		repositories = nil
+		for _, repo := range scanResult.Repositories {
+			if filterRegex.MatchString(repo.Name) {
+				repositories = append(repositories, repo)
+			}
+		}
+	}
+
+	plans := pr.PlanAlignment(repositories, targets)
+	if len(plans) == 0 {
+		fmt.Println("No consumers of internal reusable workflows need aligning")
+		return 0
+	}
+
+	fmt.Printf("Aligning %d reusable workflows across %d consumers\n", len(targets), len(plans))
+	for _, target := range targets {
+		spread := make([]string, 0, len(target.Versions))
+		for version, count := range target.Versions {
+			spread = append(spread, fmt.Sprintf("%s: %d", version, count))
+		}
+		sort.Strings(spread)
+		chosen := "most used"
+		if target.Pinned {
+			chosen = "from --versions"
+		}
+		fmt.Printf("  %s -> %s (%s; in use %s)\n", target.Workflow, target.Version, chosen, strings.Join(spread, ", "))
+	}
+	if dryRun {
+		for _, plan := range plans {
+			for _, update := range plan.Updates {
+				fmt.Printf("  %s: %s %s/%s@%s -> %s\n", plan.Repository.FullName, update.FilePath, update.ActionRepo, update.WorkflowPath, update.CurrentVersion, update.TargetVersion)
+			}
+		}
+		return 0
+	}
+
+	transport, timeout, err := networkOptions(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	githubClient := github.NewClientWithConfig(token, &github.Config{
+		Verbose:   verbose,
+		Transport: transport,
+		Timeout:   timeout,
+		Tags:      requestTags(ctx),
+		APIURL:    githubAPIURL(ctx),
+	})
+
+	auditLog, err := openAuditLog(ctx, "align", githubClient, "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer auditLog.Close()
+
+	var prCreator *pr.Creator
+	if templateFile != "" {
+		tmpl, err := loadTemplateFromFile(templateFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading template file: %v\n", err)
+			return 1
+		}
+		prCreator = pr.NewCreatorWithTemplate(githubClient, tmpl)
+	} else {
+		prCreator = pr.NewCreator(githubClient)
+	}
+
+	prCreator.SetAuditLog(auditLog)
+	createdPRs, err := prCreator.CreateUpdatePRs(plans)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating pull requests: %v\n", err)
+		return 1
+	}
+	if err := auditLog.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Created %d/%d consumer pull requests\n", len(createdPRs), len(plans))
+	if len(createdPRs) < len(plans) {
+		return 1
+	}
+	return 0
+}
+
+func handleReleaseChecklist(ctx climax.Context) int {
+	inputFile, _ := ctx.Get("input")
+	minVersionsFlag, _ := ctx.Get("min-versions")
+	dryRun := ctx.Is("dry-run")
+	verbose := ctx.Is("verbose")
+
+	minVersions := consumers.DefaultMinVersions
+	if minVersionsFlag != "" {
+		n, err := strconv.Atoi(minVersionsFlag)
+		if err != nil || n < 2 {
+			fmt.Fprintf(os.Stderr, "Error: --min-versions must be a number of at least 2\n")
+			return 1
+		}
+		minVersions = n
+	}
+
+	token, _ := ctx.Get("token")
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	if token == "" && !dryRun {
+		fmt.Fprintf(os.Stderr, "Error: GitHub token is required. Use --token or set GITHUB_TOKEN environment variable\n")
+		return 1
+	}
+
+	// Read JSON input
+	var inputReader io.Reader
+	if inputFile != "" {
+		file, err := os.Open(inputFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening input file: %v\n", err)
+			return 1
+		}
+		defer file.Close()
+		inputReader = file
+	} else {
+		inputReader = os.Stdin
+	}
+
+	data, err := io.ReadAll(inputReader)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+
+	data, err = prepareScanInput(ctx, data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+
+	var scanResult output.ScanResult
+	if err := json.Unmarshal(data, &scanResult); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing JSON input: %v\n", err)
+		return 1
+	}
+
+	if len(scanResult.InternalActions) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: the scan result has no internal actions; run scan with --internal-actions\n")
+		return 1
+	}
+
+	fragmented := consumers.Fragmented(scanResult.InternalActions, minVersions)
+	if len(fragmented) == 0 {
+		fmt.Printf("No internal action has consumers on %d or more versions\n", minVersions)
+		return 0
+	}
+
+	fmt.Printf("Found %d internal actions with consumers on %d or more versions\n", len(fragmented), minVersions)
+	if dryRun {
+		for _, action := range fragmented {
+			fmt.Printf("\n%s: %s\n\n%s", action.Repository, consumers.ChecklistTitle(action), consumers.ChecklistBody(action))
+		}
+		return 0
+	}
+
+	transport, timeout, err := networkOptions(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	githubClient := github.NewClientWithConfig(token, &github.Config{
+		Verbose:   verbose,
+		Transport: transport,
+		Timeout:   timeout,
+		Tags:      requestTags(ctx),
+		APIURL:    githubAPIURL(ctx),
+	})
+
+	auditLog, err := openAuditLog(ctx, "release-checklist", githubClient, "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer auditLog.Close()
+
+	exitCode := 0
+	for _, action := range fragmented {
+		owner, name, _ := strings.Cut(action.Repository, "/")
+		title := consumers.ChecklistTitle(action)
+
+		// Reruns leave an open checklist alone rather than opening a duplicate
+		existing, err := githubClient.FindOpenIssue(owner, name, title)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking for an open checklist in %s: %v\n", action.Repository, err)
+			exitCode = 1
+			continue
+		}
+		if existing != "" {
+			fmt.Printf("  %s: checklist already open: %s\n", action.Repository, existing)
+			continue
+		}
+
+		issueURL, err := githubClient.CreateIssue(owner, name, title, consumers.ChecklistBody(action))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating checklist issue in %s: %v\n", action.Repository, err)
+			exitCode = 1
+			continue
+		}
+		if err := auditLog.Record(audit.Event{Action: audit.ActionIssueOpened, Repository: action.Repository, URL: issueURL}); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: audit log: %v\n", err)
+		}
+		fmt.Printf("  %s: %s\n", action.Repository, issueURL)
+	}
+
+	if err := auditLog.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return exitCode
+}
+
+func handleAdvisories(ctx climax.Context) int {
+	inputFile, _ := ctx.Get("input")
+	advisoryRepo, _ := ctx.Get("advisory-repo")
+	dryRun := ctx.Is("dry-run")
+	verbose := ctx.Is("verbose")
+
+	if advisoryRepo != "" {
+		if owner, name, ok := strings.Cut(advisoryRepo, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			fmt.Fprintf(os.Stderr, "Error: --advisory-repo must be owner/repo, got %q\n", advisoryRepo)
+			return 1
+		}
+	}
+
+	token, _ := ctx.Get("token")
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	if token == "" && !dryRun {
+		fmt.Fprintf(os.Stderr, "Error: GitHub token is required. Use --token or set GITHUB_TOKEN environment variable\n")
+		return 1
+	}
+
+	scanResult, err := readScanResult(ctx, inputFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	advisories := consumers.FindAdvisories(scanResult.Repositories)
+	if len(advisories) == 0 {
+		fmt.Println("No internal action has deprecated or vulnerable versions in use")
+		return 0
+	}
+
+	fmt.Printf("Found %d internal actions with deprecated or vulnerable versions in use\n", len(advisories))
+	if dryRun {
+		for _, advisory := range advisories {
+			target := advisory.Repository
+			if advisoryRepo != "" {
+				target = advisoryRepo
+			}
+			fmt.Printf("\n%s (%s): %s\n\n%s", target, advisory.Severity, consumers.AdvisorySummary(advisory), consumers.AdvisoryDescription(advisory))
+		}
+		return 0
+	}
+
+	transport, timeout, err := networkOptions(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	githubClient := github.NewClientWithConfig(token, &github.Config{
+		Verbose:   verbose,
+		Transport: transport,
+		Timeout:   timeout,
+		Tags:      requestTags(ctx),
+		APIURL:    githubAPIURL(ctx),
+	})
+
+	auditLog, err := openAuditLog(ctx, "advisories", githubClient, "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer auditLog.Close()
+
+	exitCode := 0
+	for _, advisory := range advisories {
+		target := advisory.Repository
+		if advisoryRepo != "" {
+			target = advisoryRepo
+		}
+		owner, name, _ := strings.Cut(target, "/")
+		summary := consumers.AdvisorySummary(advisory)
+
+		// Reruns leave an existing draft alone rather than drafting a duplicate
+		existing, err := githubClient.FindSecurityAdvisory(owner, name, summary)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking for a draft advisory in %s: %v\n", target, err)
+			exitCode = 1
+			continue
+		}
+		if existing != "" {
+			fmt.Printf("  %s: advisory already drafted: %s\n", advisory.Repository, existing)
+			continue
+		}
+
+		advisoryURL, err := githubClient.CreateSecurityAdvisory(owner, name, github.SecurityAdvisory{
+			Summary:         summary,
+			Description:     consumers.AdvisoryDescription(advisory),
+			Severity:        advisory.Severity,
+			Package:         advisory.Repository,
+			PatchedVersions: advisory.PatchedVersion,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error drafting advisory for %s in %s: %v\n", advisory.Repository, target, err)
+			exitCode = 1
+			continue
+		}
+		if err := auditLog.Record(audit.Event{Action: audit.ActionAdvisoryDrafted, Repository: target, URL: advisoryURL}); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: audit log: %v\n", err)
+		}
+		fmt.Printf("  %s: %s\n", advisory.Repository, advisoryURL)
+	}
+
+	if err := auditLog.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return exitCode
+}
+
+func handleMigrate(ctx climax.Context) int {
+	inputFile, _ := ctx.Get("input")
+	fromRef, _ := ctx.Get("from")
+	toRef, _ := ctx.Get("to")
+	filterPattern, _ := ctx.Get("filter")
+	templateFile, _ := ctx.Get("template")
+	dryRun := ctx.Is("dry-run")
+	verbose := ctx.Is("verbose")
+
+	if fromRef == "" || toRef == "" {
+		fmt.Fprintf(os.Stderr, "Error: --from and --to are required\n")
+		return 1
+	}
+
+	fromRepo, fromPath, err := pr.ParseWorkflowRef(fromRef)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	toRepo, toPath, toVersion, err := pr.ParseMigrateTarget(toRef)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if fromRepo == toRepo && (toPath == "" || toPath == fromPath) {
+		fmt.Fprintf(os.Stderr, "Error: --to must name a different repository or path than --from; use create-pr for version updates\n")
+		return 1
+	}
+
+	token, _ := ctx.Get("token")
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	if token == "" && !dryRun {
+		fmt.Fprintf(os.Stderr, "Error: GitHub token is required. Use --token or set GITHUB_TOKEN environment variable\n")
+		return 1
+	}
+
+	// Read JSON input
+	var inputReader io.Reader
+	if inputFile != "" {
+		file, err := os.Open(inputFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening input file: %v\n", err)
+			return 1
+		}
+		defer file.Close()
+		inputReader = file
+	} else {
+		inputReader = os.Stdin
+	}
+
+	data, err := io.ReadAll(inputReader)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+
+	data, err = prepareScanInput(ctx, data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+
+	var scanResult output.ScanResult
+	if err := json.Unmarshal(data, &scanResult); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing JSON input: %v\n", err)
+		return 1
+	}
+
+	repositories := scanResult.Repositories
+	if filterPattern != "" {
+		filterRegex, err := regexp.Compile(filterPattern)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid filter regex pattern '%s': %v\n", filterPattern, err)
+			return 1
+		}
+
+		repositories = nil
+		for _, repo := range scanResult.Repositories {
+			if filterRegex.MatchString(repo.Name) {
+				repositories = append(repositories, repo)
+			}
+		}
+	}
+
+	plans := pr.PlanMigration(repositories, fromRepo, fromPath, toRepo, toPath, toVersion)
+	if len(plans) == 0 {
+		fmt.Printf("No repositories use %s\n", fromRef)
+		return 0
+	}
+
+	fmt.Printf("Found %d repositories using %s to migrate to %s\n", len(plans), fromRef, toRef)
+	if dryRun {
+		workflowPatcher := patcher.NewWorkflowPatcher()
+		for _, plan := range plans {
+			for _, update := range plan.Updates {
+				fmt.Printf("  %s: %s %s@%s -> %s", plan.Repository.FullName, update.FilePath, update.ActionRepo, update.CurrentVersion, update.Issue.MigrationTarget)
+				if workflowPatcher.HasPatchWithLocation(update.ActionRepo, update.CurrentVersion, update.TargetVersion, update.TargetRepo) {
+					fmt.Printf(" (with: inputs patched)")
+				}
+				fmt.Println()
+			}
+		}
+		return 0
+	}
+
+	transport, timeout, err := networkOptions(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	githubClient := github.NewClientWithConfig(token, &github.Config{
+		Verbose:   verbose,
+		Transport: transport,
+		Timeout:   timeout,
+		Tags:      requestTags(ctx),
+		APIURL:    githubAPIURL(ctx),
+	})
+
+	auditLog, err := openAuditLog(ctx, "migrate", githubClient, "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer auditLog.Close()
+
+	var prCreator *pr.Creator
+	if templateFile != "" {
+		tmpl, err := loadTemplateFromFile(templateFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading template file: %v\n", err)
+			return 1
+		}
+		prCreator = pr.NewCreatorWithTemplate(githubClient, tmpl)
+	} else {
+		prCreator = pr.NewCreator(githubClient)
+	}
+
+	prCreator.SetAuditLog(auditLog)
+	createdPRs, err := prCreator.CreateUpdatePRs(plans)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating pull requests: %v\n", err)
+		return 1
+	}
+
+	if err := auditLog.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Created %d/%d migration pull requests\n", len(createdPRs), len(plans))
+	if len(createdPRs) < len(plans) {
+		return 1
+	}
+	return 0
+}
+
+func handleInit(ctx climax.Context) int {
+	outputFile, _ := ctx.Get("output")
+	if outputFile == "" {
+		outputFile = wizard.DefaultConfigFile
+	}
+
+	if _, err := os.Stat(outputFile); err == nil && !ctx.Is("force") {
+		fmt.Fprintf(os.Stderr, "Error: %s already exists. Use --force to overwrite it\n", outputFile)
+		return 1
+	}
+
+	fmt.Printf("Creating %s. Press Enter to accept the default shown in brackets.\n\n", outputFile)
+
+	config, err := wizard.Run(wizard.NewPrompter(os.Stdin, os.Stdout))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if err := config.Save(outputFile); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving config: %v\n", err)
+		return 1
+	}
+
+	tokenEnv := config.TokenEnv
+	if tokenEnv == "" {
+		tokenEnv = "GITHUB_TOKEN"
+	}
+	fmt.Printf("\nWrote %s. Set %s, then run:\n  actions-maintainer run --config %s\n", outputFile, tokenEnv, outputFile)
+	return 0
+}
+
+func handleCompletion(ctx climax.Context, cli *climax.Application) int {
+	if len(ctx.Args) != 1 {
+		fmt.Fprintf(os.Stderr, "Error: expected one shell argument (%s)\n", strings.Join(completion.Shells, ", "))
+		return 1
+	}
+
+	// Built-in commands handled by climax itself
+	commands := []completion.Command{
+		{Name: "help", Brief: "Show help for a command"},
+		{Name: "version", Brief: "Show version information"},
+	}
+	for _, cmd := range cli.Commands {
+		entry := completion.Command{Name: cmd.Name, Brief: cmd.Brief}
+		for _, flag := range cmd.Flags {
+			entry.Flags = append(entry.Flags, completion.Flag{
+				Name:     flag.Name,
+				Short:    flag.Short,
+				Help:     flag.Help,
+				Variable: flag.Variable,
+			})
+		}
+		commands = append(commands, entry)
+
+		// Aliases complete the same flags as the command they stand for
+		for alias, name := range commandAliases {
+			if name == cmd.Name {
+				aliasEntry := entry
+				aliasEntry.Name = alias
+				commands = append(commands, aliasEntry)
+			}
+		}
+	}
+
+	if err := completion.Generate(os.Stdout, ctx.Args[0], cli.Name, commands); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+func handleRun(ctx climax.Context) int {
+	configFile, _ := ctx.Get("config")
+	stagesFlag, _ := ctx.Get("stages")
+
+	if configFile == "" {
+		fmt.Fprintf(os.Stderr, "Error: --config is required\n")
+		return 1
+	}
+
+	config, err := pipeline.LoadFile(configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading pipeline config: %v\n", err)
+		return 1
+	}
+
+	if stagesFlag != "" {
+		if err := config.SetStages(strings.Split(stagesFlag, ",")); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --stages: %v\n", err)
+			return 1
+		}
+	}
+	if ctx.Is("verbose") {
+		config.Verbose = true
+	}
+
+	token, _ := ctx.Get("token")
+	if token == "" {
+		tokenEnv := config.TokenEnv
+		if tokenEnv == "" {
+			tokenEnv = "GITHUB_TOKEN"
+		}
+		token = os.Getenv(tokenEnv)
+	}
+
+	// Scan results are passed between stages through a JSON file
+	resultsFile := config.Scan.Output
+	if resultsFile == "" {
+		tempFile, err := os.CreateTemp("", "actions-maintainer-scan-*.json")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating temporary results file: %v\n", err)
+			return 1
+		}
+		tempFile.Close()
+		defer os.Remove(tempFile.Name())
+		resultsFile = tempFile.Name()
+	}
+
+	exitCode := 0
+	for _, stage := range pipeline.Stages {
+		if !config.Enabled(stage) {
+			if config.Verbose {
+				log.Printf("Pipeline: skipping disabled stage %s", stage)
+			}
+			continue
+		}
+
+		fmt.Printf("==> %s\n", stage)
+
+		var code int
+		if len(config.Hosts) > 0 && stage != pipeline.StageReport {
+			code = runHostStage(config, stage, token, resultsFile)
+		} else {
+			stageCtx := pipelineStageContext(config, stage, token, resultsFile)
+			switch stage {
+			case pipeline.StageScan:
+				code = handleScan(stageCtx)
+			case pipeline.StageReport:
+				code = handleReport(stageCtx)
+			case pipeline.StageCreatePR:
+				code = handleCreatePR(stageCtx)
+			}
+		}
+		// A failed --fail-on gate or an exhausted budget still produces results, so later stages run and
+		// the scan decides the exit code
+		if (code == exitCodeGateFailed || code == exitCodeBudgetExhausted) && stage == pipeline.StageScan {
+			exitCode = code
+			continue
+		}
+		if code != 0 {
+			fmt.Fprintf(os.Stderr, "Error: pipeline stopped, %s stage failed\n", stage)
+			return code
+		}
+	}
+
+	return exitCode
+}
+
+// runHostStage runs the scan or create-pr stage of a multi-host pipeline once per host, with the host's
+// API URL and token. Each owner on each host is scanned in turn, and the results are merged into
+// resultsFile with every repository recording its host; create-pr is given each host's repositories.
+func runHostStage(config *pipeline.Config, stage, token, resultsFile string) int {
+	tempDir, err := os.MkdirTemp("", "actions-maintainer-hosts-*")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating temporary results directory: %v\n", err)
+		return 1
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Results are read back with the identity the report stage uses
+	readCtx := pipelineStageContext(config, pipeline.StageReport, token, resultsFile)
+
+	var scanned *output.ScanResult
+	if stage == pipeline.StageCreatePR {
+		if scanned, err = readScanResult(readCtx, resultsFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading scan results: %v\n", err)
+			return 1
+		}
+	}
+
+	exitCode := 0
+	var results []*output.ScanResult
+	var changelogs []string
+	for i, host := range config.Hosts {
+		hostConfig := *config
+		hostConfig.Hosts = nil
+		hostConfig.APIURL = host.APIURL
+		// The debt gate applies to the merged results rather than to each owner's
+		hostConfig.Scan.FailOnDebt = false
+		hostToken := token
+		if host.TokenEnv != "" {
+			hostToken = os.Getenv(host.TokenEnv)
+		}
+		name := host.HostName()
+
+		if stage == pipeline.StageCreatePR {
+			var repositories []output.RepositoryResult
+			for _, repo := range scanned.Repositories {
+				if strings.EqualFold(repo.Host, name) {
+					repositories = append(repositories, repo)
+				}
+			}
+			if len(repositories) == 0 {
+				continue
+			}
+			hostFile := filepath.Join(tempDir, fmt.Sprintf("host-%d.json", i))
+			hostResult := output.BuildScanResult(scanned.Owner, repositories)
+			if err := writeResultFile(hostResult, hostFile, terminalOutput{}, config.Encryption.Recipient, nil); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing scan results of %s: %v\n", name, err)
+				return 1
+			}
+			// Each host's changelog is combined into the configured one once every host has run
+			if config.CreatePR.Changelog != "" {
+				hostConfig.CreatePR.Changelog = filepath.Join(tempDir, fmt.Sprintf("host-%d-changelog.md", i))
+				changelogs = append(changelogs, hostConfig.CreatePR.Changelog)
+			}
+			fmt.Printf("--> %s\n", name)
+			if code := handleCreatePR(pipelineStageContext(&hostConfig, stage, hostToken, hostFile)); code != 0 {
+				return code
+			}
+			continue
+		}
+
+		for j, owner := range host.Owners {
+			hostConfig.Owner = owner
+			ownerFile := filepath.Join(tempDir, fmt.Sprintf("host-%d-owner-%d.json", i, j))
+			fmt.Printf("--> %s on %s\n", owner, name)
+			switch code := handleScan(pipelineStageContext(&hostConfig, stage, hostToken, ownerFile)); {
+			case code == exitCodeGateFailed:
+				exitCode = code
+			case code == exitCodeBudgetExhausted:
+				if exitCode == 0 {
+					exitCode = code
+				}
+			case code != 0:
+				return code
+			}
+
+			result, err := readScanResult(readCtx, ownerFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading scan results of %s on %s: %v\n", owner, name, err)
+				return 1
+			}
+			for k := range result.Repositories {
+				result.Repositories[k].Host = name
+			}
+			results = append(results, result)
+		}
+	}
+	if stage == pipeline.StageCreatePR {
+		if config.CreatePR.Changelog != "" {
+			if err := combineChangelogs(config.CreatePR.Changelog, changelogs); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 1
+			}
+		}
+		return 0
+	}
+
+	merged := output.MergeScanResults(results)
+	if config.Scan.DebtTargets != "" {
+		policy, err := output.LoadDebtPolicy(config.Scan.DebtTargets)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: loading debt targets file '%s': %v\n", config.Scan.DebtTargets, err)
+			return 1
+		}
+		merged.Summary.Debt = output.CalculateDebt(merged.Summary, policy, merged.ScanTime)
+		if config.Scan.FailOnDebt && debtGateFailed(merged) {
+			exitCode = exitCodeGateFailed
+		}
+	}
+	if err := writeResultFile(merged, resultsFile, terminalOutput{}, config.Encryption.Recipient, nil); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing merged scan results: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Merged %d repositories from %d hosts into %s\n", len(merged.Repositories), len(config.Hosts), resultsFile)
+	return exitCode
+}
+
+// combineChangelogs writes the pull request sections of each host's create-pr changelog into one
+// changelog, under the header of the first host with any
+func combineChangelogs(filename string, parts []string) error {
+	var header, sections []byte
+	for _, part := range parts {
+		content, err := os.ReadFile(part)
+		if err != nil {
+			return fmt.Errorf("failed to read changelog: %w", err)
+		}
+		// Sections start at the first "## " heading; a changelog without any created no pull requests
+		start := bytes.Index(content, []byte("\n## "))
+		if start < 0 {
+			continue
+		}
+		if header == nil {
+			header = content[:start+1]
+		}
+		sections = append(sections, content[start+1:]...)
+	}
+	if header == nil {
+		return writeChangelog(filename, nil)
+	}
+
+	if err := os.WriteFile(filename, append(header, sections...), 0644); err != nil {
+		return fmt.Errorf("failed to write changelog: %w", err)
+	}
+	return nil
+}
+
+// pipelineStageContext builds the flags a stage handler would receive on the command line
+func pipelineStageContext(config *pipeline.Config, stage, token, resultsFile string) climax.Context {
+	variable := make(map[string]string)
+	nonVariable := make(map[string]bool)
+	set := func(name, value string) {
+		if value != "" {
+			variable[name] = value
+		}
+	}
+
+	if config.Verbose && stage != pipeline.StageReport {
+		nonVariable["verbose"] = true
+	}
+	set("decrypt-identity", config.Encryption.Identity)
+	if stage != pipeline.StageCreatePR {
+		set("encrypt-recipient", config.Encryption.Recipient)
+	}
+	if stage != pipeline.StageReport {
+		// A create-pr stage authenticating as a GitHub App leaves the token to the other stages
+		if stage != pipeline.StageCreatePR || config.CreatePR.AppID == "" {
+			set("token", token)
+		}
+		set("filter", config.Filter)
+		set("proxy", config.Network.Proxy)
+		set("ca-bundle", config.Network.CABundle)
+		set("timeout", config.Network.Timeout)
+		set("user-agent-suffix", config.Network.UserAgentSuffix)
+		set("api-url", config.APIURL)
+		if config.Network.InsecureSkipVerify {
+			nonVariable["insecure-skip-verify"] = true
+		}
+	}
+
+	switch stage {
+	case pipeline.StageScan:
+		set("owner", config.Owner)
+		set("output", resultsFile)
+		set("rules-file", config.Scan.RulesFile)
+		set("suppressions-file", config.Scan.SuppressionsFile)
+		set("workflow-dirs", strings.Join(config.Scan.WorkflowDirs, ","))
+		set("workflow-filter", strings.Join(config.Scan.WorkflowFilter, ","))
+		set("exclude-workflow", strings.Join(config.Scan.ExcludeWorkflows, ","))
+		set("custom-property", config.Scan.CustomProperty)
+		if config.Scan.MaxWorkflowSize > 0 {
+			set("max-workflow-size", strconv.Itoa(config.Scan.MaxWorkflowSize))
+		}
+		if config.Scan.SkipResolution {
+			nonVariable["skip-resolution"] = true
+		}
+		if config.Scan.MaxTagPages > 0 {
+			set("max-tag-pages", strconv.Itoa(config.Scan.MaxTagPages))
+		}
+		set("comprehensive-cache", strings.Join(config.Scan.ComprehensiveCache, ","))
+		if config.Scan.ResolveLatest {
+			nonVariable["resolve-latest"] = true
+		}
+		if config.Scan.SkipWorkflowTemplates {
+			nonVariable["skip-workflow-templates"] = true
+		}
+		if config.Scan.PinAge {
+			nonVariable["pin-age"] = true
+		}
+		if config.Scan.PatchPreview {
+			nonVariable["patch-preview"] = true
+		}
+		set("hygiene-checks", strings.Join(config.Scan.Checks.Enabled(), ","))
+		set("release-checks", strings.Join(config.Scan.ReleaseChecks, ","))
+		set("action-checks", strings.Join(config.Scan.ActionChecks, ","))
+		set("disable-default-rules", strings.Join(config.Scan.DisableDefaultRules, ","))
+		if config.Scan.CheckDeprecationNotices {
+			nonVariable["check-deprecation-notices"] = true
+		}
+		if config.Scan.DetectDuplicates {
+			nonVariable["detect-duplicates"] = true
+		}
+		set("baseline", config.Scan.Baseline)
+		// Time-to-merge is measured over the pull requests of earlier runs, as recorded in the create-pr ledger
+		if config.Scan.TeamProperty != "" {
+			set("team-property", config.Scan.TeamProperty)
+			set("ledger", config.CreatePR.Ledger)
+		}
+		set("fail-on", config.Scan.FailOn)
+		set("debt-targets", config.Scan.DebtTargets)
+		if config.Scan.FailOnDebt {
+			nonVariable["fail-on-debt"] = true
+		}
+		set("summary-file", config.Scan.SummaryFile)
+		set("max-duration", config.Scan.MaxDuration)
+		set("order-by", config.Scan.OrderBy)
+		if config.Scan.MaxRepos > 0 {
+			set("max-repos", strconv.Itoa(config.Scan.MaxRepos))
+		}
+		if config.Scan.Sample {
+			nonVariable["sample"] = true
+		}
+		set("repos-snapshot", config.Scan.ReposSnapshot)
+		set("repos-snapshot-ttl", config.Scan.ReposSnapshotTTL)
+		if config.Scan.SkipListingCheck {
+			nonVariable["skip-listing-check"] = true
+		}
+		if config.Scan.TokenRepos {
+			nonVariable["token-repos"] = true
+		}
+		if config.Scan.MaxAPICalls > 0 {
+			set("max-api-calls", strconv.Itoa(config.Scan.MaxAPICalls))
+		}
+		set("max-failures", config.Scan.MaxFailures)
+		set("priority-weights", config.Scan.PriorityWeights)
+		set("docs-base-url", config.Scan.DocsBaseURL)
+		set("description-templates", config.Scan.DescriptionTemplates)
+		set("workflow-golden-set", config.Scan.WorkflowGoldenSet)
+		set("severity-policy", config.Scan.SeverityPolicy)
+		set("registry-url", config.Scan.RegistryURL)
+		set("registry-mapping", config.Scan.RegistryMapping)
+		set("hook-command", config.Scan.HookCommand)
+		set("hook-url", config.Scan.HookURL)
+		if config.Scan.CaptureLogs {
+			nonVariable["capture-logs"] = true
+		}
+		if config.Scan.ExplainAll {
+			nonVariable["explain-all"] = true
+		}
+		if config.Scan.WorkflowUsageDays > 0 {
+			set("workflow-usage", strconv.Itoa(config.Scan.WorkflowUsageDays))
+		}
+		if config.Scan.EstimateMinutesDays > 0 {
+			set("estimate-minutes", strconv.Itoa(config.Scan.EstimateMinutesDays))
+		}
+		if config.Scan.CheckImagesDays > 0 {
+			set("check-images", strconv.Itoa(config.Scan.CheckImagesDays))
+		}
+		if config.Scan.MapSecrets {
+			nonVariable["map-secrets"] = true
+		}
+		if config.Scan.GitHubAnnotations {
+			nonVariable["github-annotations"] = true
+		}
+		if config.Scan.CheckTagProtection > 0 {
+			set("check-tag-protection", strconv.Itoa(config.Scan.CheckTagProtection))
+		}
+		if config.Scan.CheckMajorTags {
+			nonVariable["check-major-tags"] = true
+		}
+		if config.Scan.CheckCalls {
+			nonVariable["check-calls"] = true
+		}
+		if config.Scan.CheckDispatchDefaults {
+			nonVariable["check-dispatch-defaults"] = true
+		}
+		set("dispatch-policy", config.Scan.DispatchPolicy)
+		set("write-locks", config.Scan.WriteLocks)
+		set("verify-locks", config.Scan.VerifyLocks)
+		if config.Scan.InternalActions > 0 {
+			set("internal-actions", strconv.Itoa(config.Scan.InternalActions))
+		}
+	case pipeline.StageReport:
+		set("input", resultsFile)
+		set("output", config.Report.Output)
+		set("report-template-dir", config.Report.TemplateDir)
+		set("links", config.Links)
+		if config.Report.GroupIssues {
+			nonVariable["group-issues"] = true
+		}
+		if config.Report.Redact {
+			nonVariable["redact"] = true
+		}
+		set("emit-approvals", config.Report.EmitApprovals)
+	case pipeline.StageCreatePR:
+		set("input", resultsFile)
+		set("template", config.CreatePR.Template)
+		set("links", config.Links)
+		if config.CreatePR.IncludeExisting {
+			nonVariable["include-existing"] = true
+		}
+		if config.CreatePR.SkipStaleWorkflows {
+			nonVariable["skip-stale-workflows"] = true
+		}
+		if config.CreatePR.AllowStale {
+			nonVariable["allow-stale"] = true
+		}
+		set("hook-command", config.CreatePR.HookCommand)
+		set("hook-url", config.CreatePR.HookURL)
+		set("base-branches", config.CreatePR.BaseBranches)
+		set("canary", config.CreatePR.Canary)
+		set("canary-property", config.CreatePR.CanaryProperty)
+		set("canary-state", config.CreatePR.CanaryState)
+		if config.CreatePR.Wave > 0 {
+			set("wave", strconv.Itoa(config.CreatePR.Wave))
+		}
+		set("wave-plan", config.CreatePR.WavePlan)
+		set("approvals", config.CreatePR.Approvals)
+		if config.CreatePR.NoCodeOwners {
+			nonVariable["no-codeowners"] = true
+		}
+		if config.CreatePR.MaxReviewers > 0 {
+			set("max-reviewers", strconv.Itoa(config.CreatePR.MaxReviewers))
+		}
+		set("coexist-mode", config.CreatePR.CoexistMode)
+		set("ledger", config.CreatePR.Ledger)
+		set("changelog", config.CreatePR.Changelog)
+		set("commit-message", config.CreatePR.CommitMessage)
+		set("commit-layout", config.CreatePR.CommitLayout)
+		if config.CreatePR.ReviewMode {
+			nonVariable["review-mode"] = true
+		}
+		set("audit-log", config.CreatePR.AuditLog)
+		set("app-id", config.CreatePR.AppID)
+		set("app-private-key", config.CreatePR.AppPrivateKey)
+		if config.CreatePR.DispatchCheck {
+			nonVariable["dispatch-check"] = true
+		}
+		set("dispatch-timeout", config.CreatePR.DispatchTimeout)
+		if config.CreatePR.Promote {
+			nonVariable["promote"] = true
+		}
+	}
+
+	return climax.Context{Variable: variable, NonVariable: nonVariable}
 }
 
-func handleScan(ctx climax.Context) int {
-	owner, _ := ctx.Get("owner")
-	if owner == "" {
-		fmt.Fprintf(os.Stderr, "Error: --owner is required\n")
+func handleTestPatches(ctx climax.Context) int {
+	rulesFile, _ := ctx.Get("patch-rules")
+	dir, _ := ctx.Get("dir")
+	update := ctx.Is("update")
+	verbose := ctx.Is("verbose")
+	metadataDir, _ := ctx.Get("action-metadata")
+
+	if rulesFile == "" || dir == "" {
+		fmt.Fprintf(os.Stderr, "Error: --patch-rules and --dir are required\n")
 		return 1
 	}
 
-	token, _ := ctx.Get("token")
-	if token == "" {
-		token = os.Getenv("GITHUB_TOKEN")
+	rules, err := patcher.LoadPatchRules(rulesFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
 	}
-	if token == "" {
-		fmt.Fprintf(os.Stderr, "Error: GitHub token is required. Use --token or set GITHUB_TOKEN environment variable\n")
+	cases, err := patchtest.DiscoverCases(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if len(cases) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no sample workflows found in %s\n", dir)
 		return 1
 	}
 
-	outputFile, _ := ctx.Get("output")
-	skipResolution := ctx.Is("skip-resolution")
-	filterPattern, _ := ctx.Get("filter")
-	verbose := ctx.Is("verbose")
-	rulesFile, _ := ctx.Get("rules-file")
-	customProperty, _ := ctx.Get("custom-property")
+	config := &patchtest.Config{
+		Verbose: verbose,
+		Update:  update,
+	}
+	if metadataDir != "" {
+		if info, err := os.Stat(metadataDir); err != nil || !info.IsDir() {
+			fmt.Fprintf(os.Stderr, "Error: --action-metadata %s is not a directory\n", metadataDir)
+			return 1
+		}
+		config.InputTypes = patcher.DirectoryInputTypes{Dir: metadataDir}
+	}
+	runner := patchtest.NewRunnerWithConfig(rules, config)
 
-	// Parse custom properties (support multiple values separated by commas)
-	var customProperties []string
-	if customProperty != "" {
-		// Split by comma and trim whitespace
-		parts := strings.Split(customProperty, ",")
-		for _, part := range parts {
-			trimmed := strings.TrimSpace(part)
-			if trimmed != "" {
-				customProperties = append(customProperties, trimmed)
+	failed := 0
+	for _, result := range runner.Run(cases) {
+		switch {
+		case result.Err != nil:
+			failed++
+			fmt.Printf("ERROR %s: %v\n", result.Case.Name, result.Err)
+		case result.Updated:
+			fmt.Printf("UPDATED %s (%d changes)\n", result.Case.Name, len(result.Changes))
+		case result.Passed:
+			fmt.Printf("PASS %s\n", result.Case.Name)
+		default:
+			failed++
+			fmt.Printf("FAIL %s\n%s", result.Case.Name, result.Diff)
+		}
+		for _, change := range result.Changes {
+			if verbose || patcher.IsWarning(change) {
+				fmt.Printf("  %s\n", change)
 			}
 		}
 	}
 
-	if verbose {
-		log.Printf("Verbose logging enabled")
-		log.Printf("Scanning repositories for owner: %s", owner)
+	fmt.Printf("%d/%d samples passed\n", len(cases)-failed, len(cases))
+	if failed > 0 {
+		return 1
 	}
+	return 0
+}
 
-	fmt.Printf("Scanning repositories for owner: %s\n", owner)
-
-	// Initialize cache for version resolution (only memory cache is supported)
-	cacheProvider, _ := ctx.Get("cache")
-	if cacheProvider == "" {
-		cacheProvider = "memory"
-	}
+func handleExplain(ctx climax.Context) int {
+	inputFile, _ := ctx.Get("input")
+	repoName, _ := ctx.Get("repo")
+	filePath, _ := ctx.Get("file")
+	action, _ := ctx.Get("action")
+	rulesFile, _ := ctx.Get("rules-file")
 
-	if cacheProvider != "memory" {
-		fmt.Fprintf(os.Stderr, "Error: Unsupported cache provider '%s'. Only 'memory' is supported.\n", cacheProvider)
+	if repoName == "" || filePath == "" || action == "" {
+		fmt.Fprintf(os.Stderr, "Error: --repo, --file, and --action are required\n")
 		return 1
 	}
 
-	cacheInstance := cache.NewMemoryCacheWithConfig(&cache.Config{
-		Verbose: verbose,
-	})
-	fmt.Printf("Using in-memory cache for version resolution\n")
-	defer cacheInstance.Close()
-
-	// Clean expired cache entries
-	cacheInstance.CleanExpired()
-
-	// Initialize components
-	githubClient := github.NewClientWithConfig(token, &github.Config{
-		Verbose: verbose,
-	})
-
-	// Create version resolver with shared cache
-	versionResolver := workflow.NewVersionResolverWithCache(githubClient, skipResolution, cacheInstance)
-
-	// Load custom rules if provided
 	var customRules []actions.Rule
 	if rulesFile != "" {
-		if verbose {
-			log.Printf("Loading custom rules from file: %s", rulesFile)
-		}
-		var err error
-		customRules, err = loadRulesFromFile(rulesFile)
+		rules, err := loadRulesFromFile(rulesFile)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading rules file '%s': %v\n", rulesFile, err)
+			fmt.Fprintf(os.Stderr, "Error loading rules file: %v\n", err)
 			return 1
 		}
-		fmt.Printf("Loaded %d custom rules from %s\n", len(customRules), rulesFile)
+		customRules = rules
 	}
 
-	actionManager := actions.NewManagerWithResolverConfigAndRules(versionResolver, &actions.Config{
-		Verbose: verbose,
-	}, customRules)
+	// Read JSON input
+	var inputReader io.Reader
+	if inputFile != "" {
+		file, err := os.Open(inputFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening input file: %v\n", err)
+			return 1
+		}
+		defer file.Close()
+		inputReader = file
+	} else {
+		inputReader = os.Stdin
+	}
 
-	// Perform scan
-	fmt.Printf("Fetching repositories...\n")
+	data, err := io.ReadAll(inputReader)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
 
-	// First, get basic repository list without custom properties
-	repositories, err := githubClient.ListRepositories(owner)
+	data, err = prepareScanInput(ctx, data)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error listing repositories: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
 		return 1
 	}
 
-	fmt.Printf("Found %d repositories\n", len(repositories))
+	var scanResult output.ScanResult
+	if err := json.Unmarshal(data, &scanResult); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing JSON input: %v\n", err)
+		return 1
+	}
 
-	// Add helpful information about potential pagination limitations
-	if len(repositories) > 0 && len(repositories)%100 == 0 {
-		fmt.Printf("Note: Repository count is a multiple of 100. If you expected more repositories, check the verbose logs for any pagination errors.\n")
+	explainer := &explain.Explainer{
+		Rules:   actions.NewManagerWithResolverConfigAndRules(nil, &actions.Config{}, customRules),
+		Patcher: patcher.NewWorkflowPatcher(),
 	}
 
-	// Apply repository filter if provided
-	if filterPattern != "" {
-		fmt.Printf("Applying filter pattern: %s\n", filterPattern)
-		filterRegex, err := regexp.Compile(filterPattern)
+	// Resolution and the pull request change need GitHub access
+	token, _ := ctx.Get("token")
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	if token != "" {
+		transport, timeout, err := networkOptions(ctx)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: Invalid filter regex pattern '%s': %v\n", filterPattern, err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			return 1
 		}
-
-		var filteredRepositories []github.Repository
-		for _, repo := range repositories {
-			if filterRegex.MatchString(repo.Name) {
-				filteredRepositories = append(filteredRepositories, repo)
-			}
-		}
-
-		fmt.Printf("Filtered repositories: %d/%d match pattern\n", len(filteredRepositories), len(repositories))
-		repositories = filteredRepositories
+		githubClient := github.NewClientWithConfig(token, &github.Config{
+			Transport: transport,
+			Timeout:   timeout,
+			Tags:      requestTags(ctx),
+			APIURL:    githubAPIURL(ctx),
+		})
+		explainer.Resolver = workflow.NewVersionResolver(githubClient, false)
+		explainer.Content = githubClient
 	}
 
-	// Now fetch custom properties only for filtered repositories
-	if len(customProperties) > 0 {
-		fmt.Printf("Fetching custom properties for %d repositories: %v\n", len(repositories), customProperties)
-		for i := range repositories {
-			props, err := githubClient.GetRepositoryCustomProperties(repositories[i].Owner, repositories[i].Name, customProperties)
-			if err != nil {
-				if verbose {
-					log.Printf("Warning: Failed to fetch custom properties for %s: %v", repositories[i].FullName, err)
-				}
-				// Continue with empty properties rather than failing
-			}
-			repositories[i].CustomProperties = props
-		}
+	explanation, err := explainer.Explain(&scanResult, explain.Query{
+		Repository: repoName,
+		FilePath:   filePath,
+		Action:     action,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := explain.Write(os.Stdout, explanation); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
 	}
+	return 0
+}
 
-	var repositoryResults []output.RepositoryResult
+func handleSuggestRules(ctx climax.Context) int {
+	inputFile, _ := ctx.Get("input")
+	rulesFile, _ := ctx.Get("rules-file")
+	outputFile, _ := ctx.Get("output")
+	minReposFlag, _ := ctx.Get("min-repos")
+	internal := ctx.Is("internal")
 
-	// Scan each repository
-	for i, repo := range repositories {
-		fmt.Printf("Scanning repository %d/%d: %s\n", i+1, len(repositories), repo.FullName)
+	options := suggest.Options{}
+	if minReposFlag != "" {
+		minRepos, err := strconv.Atoi(minReposFlag)
+		if err != nil || minRepos <= 0 {
+			fmt.Fprintf(os.Stderr, "Error: --min-repos must be a positive number\n")
+			return 1
+		}
+		options.MinRepositories = minRepos
+	}
 
-		// Get workflow files
-		workflowFiles, err := githubClient.GetWorkflowFiles(repo)
+	var customRules []actions.Rule
+	if rulesFile != "" {
+		rules, err := loadRulesFromFile(rulesFile)
 		if err != nil {
-			fmt.Printf("Warning: Failed to get workflow files for %s: %v\n", repo.FullName, err)
-			continue
+			fmt.Fprintf(os.Stderr, "Error loading rules file: %v\n", err)
+			return 1
 		}
+		customRules = rules
+	}
 
-		if len(workflowFiles) == 0 {
-			fmt.Printf("  No workflow files found\n")
-			continue
+	// Read JSON input
+	var inputReader io.Reader
+	if inputFile != "" {
+		file, err := os.Open(inputFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening input file: %v\n", err)
+			return 1
 		}
+		defer file.Close()
+		inputReader = file
+	} else {
+		inputReader = os.Stdin
+	}
 
-		fmt.Printf("  Found %d workflow files\n", len(workflowFiles))
+	data, err := io.ReadAll(inputReader)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
 
-		var repoActions []workflow.ActionReference
-		var workflowFileResults []output.WorkflowFileResult
+	data, err = prepareScanInput(ctx, data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
 
-		// Parse each workflow file
-		for _, wf := range workflowFiles {
-			if verbose {
-				log.Printf("Parsing workflow file: %s", wf.Path)
-			}
-			actions, err := workflow.ParseWorkflowWithConfig(wf.Content, wf.Path, repo.FullName, &workflow.Config{
-				Verbose: verbose,
-			})
-			if err != nil {
-				fmt.Printf("  Warning: Failed to parse %s: %v\n", wf.Path, err)
-				continue
-			}
+	var scanResult output.ScanResult
+	if err := json.Unmarshal(data, &scanResult); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing JSON input: %v\n", err)
+		return 1
+	}
 
-			fmt.Printf("    %s: %d actions\n", wf.Path, len(actions))
+	if internal {
+		options.Owners = strings.Split(scanResult.Owner, ",")
+	}
+	rules := actions.NewManagerWithResolverConfigAndRules(nil, &actions.Config{}, customRules)
+	suggestions := suggest.Uncovered(&scanResult, rules, options)
 
-			repoActions = append(repoActions, actions...)
-			workflowFileResults = append(workflowFileResults, output.WorkflowFileResult{
-				Path:        wf.Path,
-				ActionCount: len(actions),
-				Actions:     actions,
-			})
+	// Release tags need GitHub access; without it the versions in use are the best guess
+	var tags suggest.TagLister
+	token, _ := ctx.Get("token")
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	if token != "" {
+		transport, timeout, err := networkOptions(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
 		}
+		githubClient := github.NewClientWithConfig(token, &github.Config{
+			Transport: transport,
+			Timeout:   timeout,
+			Tags:      requestTags(ctx),
+			APIURL:    githubAPIURL(ctx),
+		})
+		tags = workflow.NewVersionResolver(githubClient, false)
+	} else if len(suggestions) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: No token given; using the highest version in use as each action's latest version\n")
+	}
+	suggest.SetLatestVersions(suggestions, tags)
 
-		// Analyze actions for issues
-		if verbose {
-			log.Printf("Starting analysis of %d total actions for repository %s", len(repoActions), repo.FullName)
-		}
-		issues := actionManager.AnalyzeActions(repoActions)
+	fmt.Fprintf(os.Stderr, "Found %d widely used actions without rules\n", len(suggestions))
+	for _, suggestion := range suggestions {
+		fmt.Fprintf(os.Stderr, "  %s\n", suggest.Describe(suggestion))
+	}
 
-		if len(issues) > 0 {
-			fmt.Printf("  Found %d issues\n", len(issues))
-			if verbose {
-				for _, issue := range issues {
-					log.Printf("Issue found: %s@%s - %s (severity: %s)", issue.Repository, issue.CurrentVersion, issue.IssueType, issue.Severity)
-				}
-			}
-		}
+	data, err = json.MarshalIndent(suggest.Rules(suggestions), "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error formatting rules: %v\n", err)
+		return 1
+	}
+	data = append(data, '\n')
+	if outputFile == "" {
+		os.Stdout.Write(data)
+		return 0
+	}
+	if err := os.WriteFile(outputFile, data, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing rules: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Wrote draft rules to %s; review them before adding them to your rules file\n", outputFile)
+	return 0
+}
 
-		repositoryResults = append(repositoryResults, output.RepositoryResult{
-			Name:             repo.Name,
-			FullName:         repo.FullName,
-			DefaultBranch:    repo.DefaultBranch,
-			WorkflowFiles:    workflowFileResults,
-			Actions:          repoActions,
-			Issues:           issues,
-			CustomProperties: repo.CustomProperties,
-		})
+func handleServe(ctx climax.Context) int {
+	listen, _ := ctx.Get("listen")
+	inputFile, _ := ctx.Get("input")
+	rulesFile, _ := ctx.Get("rules-file")
+	actionChecksFlag, _ := ctx.Get("action-checks")
+	disableDefaultRulesFlag, _ := ctx.Get("disable-default-rules")
+	docsBaseURL, _ := ctx.Get("docs-base-url")
+	refreshTopFlag, _ := ctx.Get("refresh-top")
+	refreshIntervalFlag, _ := ctx.Get("refresh-interval")
+	verbose := ctx.Is("verbose")
+
+	if listen == "" {
+		listen = ":8080"
+	}
+
+	refreshTop := server.DefaultRefreshTop
+	if refreshTopFlag != "" {
+		n, err := strconv.Atoi(refreshTopFlag)
+		if err != nil || n < 0 {
+			fmt.Fprintf(os.Stderr, "Error: --refresh-top must be a number of actions, or 0 to disable the refresh\n")
+			return 1
+		}
+		refreshTop = n
+	}
+	refreshInterval := server.DefaultRefreshInterval
+	if refreshIntervalFlag != "" {
+		interval, err := time.ParseDuration(refreshIntervalFlag)
+		if err != nil || interval <= 0 {
+			fmt.Fprintf(os.Stderr, "Error: --refresh-interval must be a positive duration (e.g., 30s, 2m)\n")
+			return 1
+		}
+		refreshInterval = interval
 	}
 
-	// Build final scan result
-	scanResult := output.BuildScanResult(owner, repositoryResults)
-
-	// Finalize scan result with timing
-	output.FinalizeScanResult(scanResult)
+	actionChecks, err := actions.ParseChecks(actionChecksFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --action-checks: %v\n", err)
+		return 1
+	}
+	disabledDefaultRules, err := actions.ParseDisabledDefaultRules(disableDefaultRulesFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --disable-default-rules: %v\n", err)
+		return 1
+	}
 
-	// Set up output writer
-	var outputWriter io.Writer
-	if outputFile != "" {
-		file, err := os.Create(outputFile)
+	var customRules []actions.Rule
+	if rulesFile != "" {
+		customRules, err = loadRulesFromFile(rulesFile)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating output file: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error loading rules file '%s': %v\n", rulesFile, err)
 			return 1
 		}
-		defer file.Close()
-		outputWriter = file
-	} else {
-		outputWriter = os.Stdout
+		fmt.Printf("Loaded %d custom rules from %s\n", len(customRules), rulesFile)
 	}
 
-	// Determine output format based on file extension
-	isNotebook := strings.HasSuffix(strings.ToLower(outputFile), ".ipynb")
-
-	if isNotebook {
-		if err := output.FormatNotebook(scanResult, outputWriter); err != nil {
-			fmt.Fprintf(os.Stderr, "Error formatting notebook output: %v\n", err)
+	// Versions are resolved with a token; without one they are compared as strings
+	token, _ := ctx.Get("token")
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	var scanResult *output.ScanResult
+	if inputFile != "" {
+		scanResult, err = readScanResult(ctx, inputFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			return 1
 		}
-	} else {
-		if err := output.FormatJSON(scanResult, outputWriter, true); err != nil {
-			fmt.Fprintf(os.Stderr, "Error formatting JSON output: %v\n", err)
+	}
+
+	var resolver actions.VersionResolver
+	var refresher server.TagRefresher
+	if token != "" {
+		transport, timeout, err := networkOptions(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			return 1
 		}
+		githubClient := github.NewClientWithConfig(token, &github.Config{
+			Verbose:   verbose,
+			Transport: transport,
+			Timeout:   timeout,
+			Tags:      requestTags(ctx),
+			APIURL:    githubAPIURL(ctx),
+		})
+		cacheInstance := cache.NewMemoryCacheWithConfig(&cache.Config{Verbose: verbose})
+		defer cacheInstance.Close()
+		versionResolver := workflow.NewVersionResolverWithCache(githubClient, false, cacheInstance)
+		resolver = versionResolver
+		if refreshTop > 0 {
+			refresher = versionResolver
+		}
+	}
+
+	analyzer := server.New(&server.Config{
+		Verbose: verbose,
+		Rules: actions.NewManagerWithResolverConfigAndRules(resolver, &actions.Config{
+			Verbose:             verbose,
+			Checks:              actionChecks,
+			SkipResolution:      resolver == nil,
+			DisableDefaultRules: disabledDefaultRules,
+		}, customRules),
+		DocsBaseURL:     docsBaseURL,
+		Results:         scanResult,
+		Refresher:       refresher,
+		RefreshTop:      refreshTop,
+		RefreshInterval: refreshInterval,
+	})
+	if refresher != nil {
+		refreshCtx, stopRefresh := context.WithCancel(context.Background())
+		defer stopRefresh()
+		go analyzer.RunTagRefresh(refreshCtx)
+		fmt.Printf("Refreshing the tags of the %d most used actions in the background, one every %s\n", refreshTop, refreshInterval)
+	}
+	httpServer := &http.Server{
+		Addr:              listen,
+		Handler:           analyzer.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
 	}
 
+	fmt.Printf("Serving the analyzer on %s (POST /analyze, POST /plan, GET /healthz)\n", listen)
+	if err := httpServer.ListenAndServe(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
 	return 0
 }
 
-func handleReport(ctx climax.Context) int {
+func handleBadges(ctx climax.Context) int {
 	inputFile, _ := ctx.Get("input")
-	outputFile, _ := ctx.Get("output")
+	outputDir, _ := ctx.Get("output-dir")
+	formatFlag, _ := ctx.Get("format")
+	publish, _ := ctx.Get("publish")
+	branch, _ := ctx.Get("branch")
+	dryRun := ctx.Is("dry-run")
+	verbose := ctx.Is("verbose")
 
-	// Read JSON input
-	var inputReader io.Reader
-	if inputFile != "" {
-		file, err := os.Open(inputFile)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error opening input file: %v\n", err)
-			return 1
-		}
-		defer file.Close()
-		inputReader = file
-	} else {
-		inputReader = os.Stdin
+	if outputDir == "" {
+		outputDir = "badges"
 	}
-
-	// Parse JSON input
-	data, err := io.ReadAll(inputReader)
+	if branch == "" {
+		branch = "gh-pages"
+	}
+	format, err := badge.ParseFormat(formatFlag)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: --format: %v\n", err)
 		return 1
 	}
 
-	var scanResult output.ScanResult
-	if err := json.Unmarshal(data, &scanResult); err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing JSON input: %v\n", err)
+	scanResult, err := readScanResult(ctx, inputFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return 1
 	}
 
-	// Set up output writer
-	var outputWriter io.Writer
-	if outputFile != "" {
-		file, err := os.Create(outputFile)
+	grades := badge.ScoreAll(scanResult)
+	for _, grade := range grades {
+		fmt.Printf("%s: %s (%d issues)\n", grade.Repository, grade.Message(), grade.Issues)
+	}
+	if dryRun || len(grades) == 0 {
+		return 0
+	}
+
+	if publish == "" {
+		written, err := badge.WriteDir(outputDir, grades, format)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating output file: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			return 1
 		}
-		defer file.Close()
-		outputWriter = file
-	} else {
-		outputWriter = os.Stdout
+		fmt.Printf("Wrote %d badges to %s\n", len(written), outputDir)
+		return 0
+	}
+
+	token, _ := ctx.Get("token")
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
 	}
+	if token == "" {
+		fmt.Fprintf(os.Stderr, "Error: GitHub token is required for --publish. Use --token or set GITHUB_TOKEN environment variable\n")
+		return 1
+	}
+	transport, timeout, err := networkOptions(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	githubClient := github.NewClientWithConfig(token, &github.Config{
+		Verbose:   verbose,
+		Transport: transport,
+		Timeout:   timeout,
+		Tags:      requestTags(ctx),
+		APIURL:    githubAPIURL(ctx),
+	})
 
-	// Determine output format based on file extension
-	isNotebook := strings.HasSuffix(strings.ToLower(outputFile), ".ipynb")
+	changed, err := badge.Publish(githubClient, publish, branch, "badges", grades, format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error publishing badges to %s: %v\n", publish, err)
+		return 1
+	}
+	fmt.Printf("Published badges to %s@%s: %d changed\n", publish, branch, changed)
+	return 0
+}
+
+func handleCache(ctx climax.Context) int {
+	if len(ctx.Args) != 1 || ctx.Args[0] != "purge" {
+		fmt.Fprintf(os.Stderr, "Error: expected the purge subcommand, e.g. cache purge --repo actions/checkout\n")
+		return 1
+	}
+	repoFlag, _ := ctx.Get("repo")
+	olderThanFlag, _ := ctx.Get("older-than")
+	cacheFile, _ := ctx.Get("cache-file")
+	dryRun := ctx.Is("dry-run")
+	verbose := ctx.Is("verbose")
 
-	if isNotebook {
-		if err := output.FormatNotebook(&scanResult, outputWriter); err != nil {
-			fmt.Fprintf(os.Stderr, "Error formatting notebook output: %v\n", err)
+	var filter cache.PurgeFilter
+	for _, part := range strings.Split(repoFlag, ",") {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			filter.Repositories = append(filter.Repositories, trimmed)
+		}
+	}
+	if olderThanFlag != "" {
+		olderThan, err := time.ParseDuration(olderThanFlag)
+		if err != nil || olderThan <= 0 {
+			fmt.Fprintf(os.Stderr, "Error: Invalid --older-than '%s': expected a positive duration such as 24h\n", olderThanFlag)
 			return 1
 		}
-	} else {
-		if err := output.FormatJSON(&scanResult, outputWriter, true); err != nil {
-			fmt.Fprintf(os.Stderr, "Error formatting JSON output: %v\n", err)
+		filter.OlderThan = olderThan
+	}
+	for _, repository := range filter.Repositories {
+		if owner, name, ok := strings.Cut(repository, "/"); !ok || owner == "" || name == "" {
+			fmt.Fprintf(os.Stderr, "Error: Invalid --repo '%s': expected owner/name\n", repository)
 			return 1
 		}
 	}
 
+	if cacheFile == "" {
+		cacheFile = cache.DefaultFilePath()
+	}
+	fileCache, err := cache.NewFileCacheWithConfig(cacheFile, &cache.Config{Verbose: verbose})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	removed, err := fileCache.Purge(filter)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if dryRun {
+		fmt.Printf("%d cache entries would be purged from %s\n", removed, cacheFile)
+		return 0
+	}
+	if err := fileCache.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Purged %d cache entries from %s\n", removed, cacheFile)
 	return 0
 }
 
-func handleCreatePR(ctx climax.Context) int {
-	inputFile, _ := ctx.Get("input")
-	templateFile, _ := ctx.Get("template")
-	filterPattern, _ := ctx.Get("filter")
-
+func handleSelfUpdate(ctx climax.Context) int {
+	check := ctx.Is("check")
+	force := ctx.Is("force")
 	token, _ := ctx.Get("token")
 	if token == "" {
 		token = os.Getenv("GITHUB_TOKEN")
 	}
-	if token == "" {
-		fmt.Fprintf(os.Stderr, "Error: GitHub token is required. Use --token or set GITHUB_TOKEN environment variable\n")
+
+	transport, timeout, err := networkOptions(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	updater := &selfupdate.Updater{Client: &http.Client{Transport: transport, Timeout: timeout}, Token: token}
+
+	current := getVersion()
+	release, err := updater.Latest()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	outdated := selfupdate.Outdated(current, release.Tag)
+
+	switch {
+	case outdated:
+		fmt.Fprintf(os.Stderr, "Warning: actions-maintainer %s is outdated; the latest release is %s (%s). Rules such as brownout calendars may have changed since.\n", current, release.Tag, release.URL)
+	case selfupdate.IsRelease(current):
+		fmt.Printf("actions-maintainer %s is up to date\n", current)
+	default:
+		fmt.Printf("actions-maintainer %s is a development build; the latest release is %s\n", current, release.Tag)
+	}
+	if check || (!outdated && !force) {
+		return 0
+	}
+
+	name := selfupdate.CurrentAssetName()
+	content, err := updater.Download(release, name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	executable, err := os.Executable()
+	if err == nil {
+		executable, err = filepath.EvalSymlinks(executable)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to locate the current binary: %v\n", err)
+		return 1
+	}
+	if err := selfupdate.Replace(executable, content); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Updated %s to %s (checksum verified)\n", executable, release.Tag)
+	return 0
+}
+
+// readScanResult reads a scan result from a file, or stdin when inputFile is empty
+func readScanResult(ctx climax.Context, inputFile string) (*output.ScanResult, error) {
+	input, closeInput, err := openScanInput(ctx, inputFile)
+	if err != nil {
+		return nil, err
+	}
+	defer closeInput()
+
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input: %w", err)
+	}
+	if output.IsScanIndex(data) {
+		return nil, fmt.Errorf("input is a split scan index; run the command on each chunk file listed in its chunks")
+	}
+
+	var scanResult output.ScanResult
+	if err := json.Unmarshal(data, &scanResult); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON input: %w", err)
+	}
+	return &scanResult, nil
+}
+
+func handleApply(ctx climax.Context) int {
+	inputFile, _ := ctx.Get("input")
+	workdir, _ := ctx.Get("workdir")
+	filterPattern, _ := ctx.Get("filter")
+	dryRun := ctx.Is("dry-run")
+	simulate := ctx.Is("simulate")
+	verbose := ctx.Is("verbose")
+
+	if workdir == "" {
+		fmt.Fprintf(os.Stderr, "Error: --workdir is required\n")
+		return 1
+	}
+	if info, err := os.Stat(workdir); err != nil || !info.IsDir() {
+		fmt.Fprintf(os.Stderr, "Error: --workdir '%s' is not a directory\n", workdir)
 		return 1
 	}
 
@@ -527,13 +7964,18 @@ func handleCreatePR(ctx climax.Context) int {
 		inputReader = os.Stdin
 	}
 
-	// Parse JSON input
 	data, err := io.ReadAll(inputReader)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
 		return 1
 	}
 
+	data, err = prepareScanInput(ctx, data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+
 	var scanResult output.ScanResult
 	if err := json.Unmarshal(data, &scanResult); err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing JSON input: %v\n", err)
@@ -560,106 +8002,52 @@ func handleCreatePR(ctx climax.Context) int {
 		scanResult.Repositories = filteredRepositories
 	}
 
-	// Create GitHub client
-	githubClient := github.NewClient(token)
-
-	// Load custom template if provided
-	var prCreator *pr.Creator
-	if templateFile != "" {
-		tmpl, err := loadTemplateFromFile(templateFile)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading template file: %v\n", err)
-			return 1
-		}
-		prCreator = pr.NewCreatorWithTemplate(githubClient, tmpl)
-	} else {
-		prCreator = pr.NewCreator(githubClient)
-	}
-
-	// Plan updates from scan result
 	updatePlans := pr.PlanUpdates(scanResult.Repositories)
-
 	if len(updatePlans) == 0 {
 		fmt.Printf("No updates needed - all actions are up to date!\n")
 		return 0
 	}
 
-	fmt.Printf("Creating pull requests for updates...\n")
-	fmt.Printf("Planning updates for %d repositories\n", len(updatePlans))
-
-	createdPRs, err := prCreator.CreateUpdatePRs(updatePlans)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating PRs: %v\n", err)
-		return 1
-	}
-
-	// Output created PRs information
-	for _, createdPR := range createdPRs {
-		fmt.Printf("Created PR for %s: %s\n", createdPR.Repository, createdPR.URL)
-	}
-
-	fmt.Printf("Successfully created %d pull requests\n", len(createdPRs))
-	return 0
-}
-
-// loadTemplateFromFile loads a Go template from a file
-func loadTemplateFromFile(filename string) (*template.Template, error) {
-	content, err := os.ReadFile(filename)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read template file: %w", err)
-	}
-
-	tmpl, err := template.New("pr-body").Parse(string(content))
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse template: %w", err)
-	}
-
-	return tmpl, nil
-}
-
-// loadRulesFromFile loads custom rules from a JSON file
-func loadRulesFromFile(filename string) ([]actions.Rule, error) {
-	file, err := os.Open(filename)
-	if err != nil {
-		return nil, fmt.Errorf("unable to open rules file: %w", err)
-	}
-	defer file.Close()
-
-	data, err := io.ReadAll(file)
-	if err != nil {
-		return nil, fmt.Errorf("unable to read rules file: %w", err)
+	if dryRun {
+		fmt.Printf("Dry run: no files will be written\n")
 	}
+	fmt.Printf("Applying updates for %d repositories in %s\n", len(updatePlans), workdir)
 
-	var rules []actions.Rule
-	if err := json.Unmarshal(data, &rules); err != nil {
-		return nil, fmt.Errorf("unable to parse rules file as JSON: %w", err)
-	}
+	applier := apply.NewApplierWithConfig(workdir, &apply.Config{
+		Verbose:  verbose,
+		DryRun:   dryRun,
+		Simulate: simulate,
+	})
 
-	// Validate rules
-	for i, rule := range rules {
-		if rule.Repository == "" {
-			return nil, fmt.Errorf("rule %d: repository field is required", i+1)
+	failed := 0
+	filesChanged := 0
+	invalidFiles := 0
+	for _, result := range applier.ApplyPlans(updatePlans) {
+		if result.Err != nil {
+			fmt.Printf("Warning: Skipped %s: %v\n", result.Repository, result.Err)
+			failed++
+			continue
 		}
 
-		// Check if this is a migration rule or a standard version rule
-		isMigrationRule := rule.MigrateToRepository != "" || rule.MigrateToVersion != ""
-
-		if isMigrationRule {
-			// Migration rule validation
-			if rule.MigrateToRepository == "" {
-				return nil, fmt.Errorf("rule %d: migrate_to_repository field is required when migration is specified for repository %s", i+1, rule.Repository)
-			}
-			if rule.MigrateToVersion == "" {
-				return nil, fmt.Errorf("rule %d: migrate_to_version field is required when migration is specified for repository %s", i+1, rule.Repository)
-			}
-			// For migration rules, latest_version is optional (defaults to current behavior)
-		} else {
-			// Standard version rule validation
-			if rule.LatestVersion == "" {
-				return nil, fmt.Errorf("rule %d: latest_version field is required for repository %s", i+1, rule.Repository)
-			}
+		fmt.Printf("%s (%s): %d files updated\n", result.Repository, result.Directory, len(result.FilesChanged))
+		for _, file := range result.FilesChanged {
+			fmt.Printf("  %s\n", file)
+		}
+		for _, change := range result.Changes {
+			fmt.Printf("    %s\n", change)
+		}
+		for _, invalid := range result.InvalidFiles {
+			fmt.Printf("  Warning: Not updated, would be invalid: %s\n", invalid)
 		}
+		filesChanged += len(result.FilesChanged)
+		invalidFiles += len(result.InvalidFiles)
 	}
 
-	return rules, nil
+	fmt.Printf("Updated %d workflow files across %d repositories", filesChanged, len(updatePlans)-failed)
+	if failed > 0 || invalidFiles > 0 {
+		fmt.Printf(" (%d repositories skipped, %d files left unchanged as invalid)\n", failed, invalidFiles)
+		return 1
+	}
+	fmt.Printf("\n")
+	return 0
 }
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Jake-Mok-Nelson/actions-maintainer/internal/pipeline"
+)
+
+func TestResolveCommandAlias(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected []string
+	}{
+		{[]string{"am", "pr", "-i", "scan.json"}, []string{"am", "create-pr", "-i", "scan.json"}},
+		{[]string{"am", "help", "s"}, []string{"am", "help", "scan"}},
+		{[]string{"am", "scan", "-o", "s"}, []string{"am", "scan", "-o", "s"}},
+		{[]string{"am"}, []string{"am"}},
+	}
+
+	for _, tt := range tests {
+		if got := resolveCommandAlias(tt.args); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("resolveCommandAlias(%v) = %v, expected %v", tt.args, got, tt.expected)
+		}
+	}
+}
+
+func TestJoinRepeatedOutputs(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected []string
+	}{
+		{[]string{"am", "scan", "--owner", "org", "--output", "scan.json", "-O", "report.sarif", "--output=report.ipynb"},
+			[]string{"am", "scan", "--owner", "org", "--output=scan.json,report.sarif,report.ipynb"}},
+		{[]string{"am", "report", "-o", "a.json", "-i", "scan.json", "-o", "b.sarif"},
+			[]string{"am", "report", "--output=a.json,b.sarif", "-i", "scan.json"}},
+		{[]string{"am", "scan", "--owner", "org"}, []string{"am", "scan", "--owner", "org"}},
+		{[]string{"am", "scan", "--output"}, []string{"am", "scan", "--output"}},
+		{[]string{"am", "apply", "-o", "x"}, []string{"am", "apply", "-o", "x"}},
+	}
+
+	for _, tt := range tests {
+		if got := joinRepeatedOutputs(tt.args); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("joinRepeatedOutputs(%v) = %v, expected %v", tt.args, got, tt.expected)
+		}
+	}
+}
+
+func TestPipelineStageContext(t *testing.T) {
+	config := &pipeline.Config{
+		Owner:   "my-org",
+		Filter:  "api-.*",
+		Verbose: true,
+		Network: pipeline.NetworkConfig{Proxy: "http://proxy:3128"},
+		Scan:    pipeline.ScanConfig{WorkflowDirs: []string{".github/workflows", ".gitea/workflows"}, MaxWorkflowSize: 2048, Baseline: "previous.json", FailOn: "high", CaptureLogs: true},
+		Report:  pipeline.ReportConfig{Output: "report.ipynb"},
+	}
+
+	scanCtx := pipelineStageContext(config, pipeline.StageScan, "token", "results.json")
+	for name, expected := range map[string]string{
+		"owner":             "my-org",
+		"output":            "results.json",
+		"filter":            "api-.*",
+		"token":             "token",
+		"proxy":             "http://proxy:3128",
+		"workflow-dirs":     ".github/workflows,.gitea/workflows",
+		"max-workflow-size": "2048",
+		"baseline":          "previous.json",
+		"fail-on":           "high",
+	} {
+		if got, _ := scanCtx.Get(name); got != expected {
+			t.Errorf("Expected scan flag %s=%q, got %q", name, expected, got)
+		}
+	}
+	if !scanCtx.Is("verbose") {
+		t.Errorf("Expected scan stage to be verbose")
+	}
+	if !scanCtx.Is("capture-logs") {
+		t.Errorf("Expected scan stage to capture logs")
+	}
+
+	reportCtx := pipelineStageContext(config, pipeline.StageReport, "token", "results.json")
+	if input, _ := reportCtx.Get("input"); input != "results.json" {
+		t.Errorf("Expected report input to be scan results, got %q", input)
+	}
+	if output, _ := reportCtx.Get("output"); output != "report.ipynb" {
+		t.Errorf("Expected report output report.ipynb, got %q", output)
+	}
+	if reportCtx.Is("token") || reportCtx.Is("verbose") {
+		t.Errorf("Expected report stage to receive only report flags")
+	}
+}
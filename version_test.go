@@ -1,6 +1,9 @@
 package main
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestGetVersion(t *testing.T) {
 	tests := []struct {
@@ -37,3 +40,20 @@ func TestGetVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveVersionCheck(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"am", "version", "--check"}, "am self-update --check"},
+		{[]string{"am", "version"}, "am version"},
+		{[]string{"am", "scan", "--check"}, "am scan --check"},
+	}
+	for _, tt := range tests {
+		resolved := resolveVersionCheck(tt.args)
+		if got := strings.Join(resolved, " "); got != tt.expected {
+			t.Errorf("resolveVersionCheck(%v) = %q, want %q", tt.args, got, tt.expected)
+		}
+	}
+}